@@ -0,0 +1,147 @@
+package rl_env_engine
+
+import (
+	"fmt"
+
+	"github.com/jelech/rl_env_engine/core"
+	"github.com/jelech/rl_env_engine/core/wrappers"
+)
+
+// Wrapper wraps a simulation, e.g. to limit its episodes or transform its observations
+type Wrapper func(sim Simulation) (Simulation, error)
+
+// Builder composes engine creation, config validation, seeding and wrappers into one chain:
+//
+//	sim, err := simulations.New().
+//		Scenario("cartpole").
+//		Config(map[string]interface{}{"max_steps": 1000}).
+//		Seed(7).
+//		Wrap(simulations.TimeLimit(500), simulations.NormalizeObs()).
+//		Build()
+//
+// Builder methods never fail; the first problem is reported by Build.
+type Builder struct {
+	engine   *core.SimulationEngine
+	scenario string
+	config   map[string]interface{}
+	wrappers []Wrapper
+}
+
+// New starts building a simulation
+func New() *Builder {
+	return &Builder{config: make(map[string]interface{})}
+}
+
+// Engine creates the simulation with engine instead of an engine holding the built-in
+// scenarios, those added with RegisterScenario and the default presets
+func (b *Builder) Engine(engine *core.SimulationEngine) *Builder {
+	b.engine = engine
+	return b
+}
+
+// Scenario sets the scenario or preset to create
+func (b *Builder) Scenario(name string) *Builder {
+	b.scenario = name
+	return b
+}
+
+// Config merges config into the scenario parameters; later values win
+func (b *Builder) Config(config map[string]interface{}) *Builder {
+	for k, v := range config {
+		b.config[k] = v
+	}
+	return b
+}
+
+// Set sets a single scenario parameter
+func (b *Builder) Set(key string, value interface{}) *Builder {
+	b.config[key] = value
+	return b
+}
+
+// Seed sets the seed parameter of the scenario
+func (b *Builder) Seed(seed int64) *Builder {
+	return b.Set("seed", seed)
+}
+
+// Wrap appends wrappers; Build applies them in order, so the first one wraps the
+// scenario environment and the last one is outermost
+func (b *Builder) Wrap(wrappers ...Wrapper) *Builder {
+	b.wrappers = append(b.wrappers, wrappers...)
+	return b
+}
+
+// Build validates the config, creates the simulation and applies the wrappers.
+// The simulation is closed again when a wrapper fails
+func (b *Builder) Build() (Simulation, error) {
+	if b.scenario == "" {
+		return nil, fmt.Errorf("scenario is required")
+	}
+	engine := b.engine
+	if engine == nil {
+		var err error
+		if engine, err = newBuiltinEngine(); err != nil {
+			return nil, err
+		}
+	}
+
+	config := make(map[string]interface{}, len(b.config))
+	for k, v := range b.config {
+		config[k] = v
+	}
+	sim, err := engine.CreateEnvironment(b.scenario, core.NewBaseConfig(config))
+	if err != nil {
+		return nil, err
+	}
+	for i, wrap := range b.wrappers {
+		wrapped, err := wrap(sim)
+		if err != nil {
+			sim.Close()
+			return nil, fmt.Errorf("wrapper %d: %w", i, err)
+		}
+		sim = wrapped
+	}
+	return sim, nil
+}
+
+// TimeLimit ends episodes after maxSteps steps, reporting them as truncated
+func TimeLimit(maxSteps int) Wrapper {
+	return func(sim Simulation) (Simulation, error) {
+		return wrappers.LimitEpisodeSteps(sim, maxSteps)
+	}
+}
+
+// NormalizeObs normalizes observations with their running mean and variance
+func NormalizeObs() Wrapper {
+	return func(sim Simulation) (Simulation, error) {
+		return wrappers.NormalizeObservation(sim)
+	}
+}
+
+// SelectFeatures keeps only the observation features at indices
+func SelectFeatures(indices ...int) Wrapper {
+	return func(sim Simulation) (Simulation, error) {
+		return wrappers.SelectFeatures(sim, indices...)
+	}
+}
+
+// RescaleAction maps actions in [min, max] onto the Box action space of the simulation
+func RescaleAction(min, max float64) Wrapper {
+	return func(sim Simulation) (Simulation, error) {
+		return wrappers.RescaleAction(sim, min, max)
+	}
+}
+
+// ScaleReward multiplies rewards by scale
+func ScaleReward(scale float64) Wrapper {
+	return func(sim Simulation) (Simulation, error) {
+		return wrappers.ScaleReward(sim, scale)
+	}
+}
+
+// ClipReward clips rewards to [min, max]
+func ClipReward(min, max float64) Wrapper {
+	return func(sim Simulation) (Simulation, error) {
+		return wrappers.ClipReward(sim, min, max)
+	}
+}
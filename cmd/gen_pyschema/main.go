@@ -0,0 +1,226 @@
+// Command gen_pyschema writes the JSON shapes of the HTTP API as Python TypedDicts, so the
+// Python client stays in sync with the Go request and response structs.
+// Run it through go generate in the server package (make python-schema).
+//
+// The generated module doubles as the golden file of the HTTP API: with -check the command
+// compares it with the structs instead of writing it and fails when a shape drifted without
+// regenerating (make check-python-schema). Either way it refuses fields whose JSON key is not
+// an explicit snake_case tag, since the Python client relies on those keys.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jelech/rl_env_engine/core"
+	"github.com/jelech/rl_env_engine/core/curriculum"
+	"github.com/jelech/rl_env_engine/server"
+)
+
+// schemaTypes are the HTTP API bodies, in the order they appear in the generated module
+var schemaTypes = []interface{}{
+	server.CreateEnvRequest{},
+	server.CreateEnvResponse{},
+	server.ResetRequest{},
+	server.ResetResponse{},
+	server.StepRequest{},
+	server.StepResponse{},
+	server.AgentStep{},
+	core.GymSpaces{},
+	server.RecordRequest{},
+	server.CurriculumStageRequest{},
+	curriculum.Progress{},
+	server.InfoResponse{},
+	server.MetricsResponse{},
+	server.StatsResponse{},
+	server.EnvDebugDump{},
+	server.OpenSessionRequest{},
+	server.OpenSessionResponse{},
+	server.CloseSessionRequest{},
+	server.CloseSessionResponse{},
+	server.EnvStatus{},
+	server.AdminEnvsResponse{},
+	server.DrainRequest{},
+	server.DrainResponse{},
+	server.RegisterScenarioRequest{},
+	server.RegisterScenarioResponse{},
+	core.EnvMetadata{},
+	server.ErrorResponse{},
+}
+
+// overrides replaces field types whose JSON encoding differs from the Go type (custom MarshalJSON,
+// []byte as base64)
+var overrides = map[string]string{
+	"EnvMetadata.reward_range":     "List[Optional[float]]",
+	"EnvDebugDump.checkpoint":      "Any",
+	"RegisterScenarioRequest.wasm": "str",
+	"ImageData.pixels":             "str",
+	"GymSpace.low":                 "Union[float, List[Optional[float]], None]",
+	"GymSpace.high":                "Union[float, List[Optional[float]], None]",
+	"GymSpace.start":               "Union[int, List[int]]",
+	"GymSpace.min_length":          "int",
+}
+
+// snakeCase matches the JSON keys allowed in the HTTP API
+var snakeCase = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+
+type generator struct {
+	buf     bytes.Buffer
+	written map[reflect.Type]bool
+	pending map[reflect.Type]bool // structs whose class is still being built, referenced by name in quotes
+	errs    []string              // fields breaking the key naming rules
+}
+
+func main() {
+	out := flag.String("out", "", "output Python file (stdout when empty)")
+	check := flag.Bool("check", false, "compare -out with the generated module instead of writing it")
+	flag.Parse()
+
+	g := &generator{written: make(map[reflect.Type]bool), pending: make(map[reflect.Type]bool)}
+	g.buf.WriteString(`"""JSON request and response shapes of the rl_env_engine HTTP API.
+
+Code generated by cmd/gen_pyschema; DO NOT EDIT.
+"""
+
+from typing import Any, Dict, List, Optional, Union
+
+try:
+    from typing import TypedDict
+except ImportError:  # pragma: no cover
+    from typing_extensions import TypedDict
+`)
+	for _, v := range schemaTypes {
+		g.writeStruct(reflect.TypeOf(v))
+	}
+	if len(g.errs) > 0 {
+		for _, e := range g.errs {
+			fmt.Fprintf(os.Stderr, "gen_pyschema: %s\n", e)
+		}
+		os.Exit(1)
+	}
+
+	if *check {
+		if *out == "" {
+			fmt.Fprintln(os.Stderr, "gen_pyschema: -check requires -out")
+			os.Exit(2)
+		}
+		current, err := os.ReadFile(*out)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "gen_pyschema: %v\n", err)
+			os.Exit(1)
+		}
+		if !bytes.Equal(current, g.buf.Bytes()) {
+			fmt.Fprintf(os.Stderr, "gen_pyschema: %s is out of date, run make python-schema\n", *out)
+			os.Exit(1)
+		}
+		return
+	}
+	if *out == "" {
+		os.Stdout.Write(g.buf.Bytes())
+		return
+	}
+	if err := os.WriteFile(*out, g.buf.Bytes(), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "gen_pyschema: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+type field struct {
+	name, typ string
+}
+
+// writeStruct emits t and, before it, every struct type it references
+func (g *generator) writeStruct(t reflect.Type) {
+	if g.written[t] {
+		return
+	}
+	g.written[t] = true
+	g.pending[t] = true
+	defer delete(g.pending, t)
+
+	var required, optional []field
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if !f.IsExported() || tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" {
+			g.errs = append(g.errs, fmt.Sprintf("%s.%s has no JSON key, tag it with a snake_case name", t.Name(), f.Name))
+			name = f.Name
+		} else if !snakeCase.MatchString(name) {
+			g.errs = append(g.errs, fmt.Sprintf("%s.%s: JSON key %q is not snake_case", t.Name(), f.Name, name))
+		}
+		typ, ok := overrides[t.Name()+"."+name]
+		if !ok {
+			typ = g.pythonType(f.Type)
+		}
+		if strings.Contains(opts, "omitempty") {
+			optional = append(optional, field{name, typ})
+		} else {
+			required = append(required, field{name, typ})
+		}
+	}
+
+	// Python 3.8 has no NotRequired, so optional keys go into a total=False subclass
+	if len(optional) == 0 {
+		g.writeClass(t.Name(), "TypedDict", required)
+		return
+	}
+	if len(required) == 0 {
+		g.writeClass(t.Name(), "TypedDict, total=False", optional)
+		return
+	}
+	base := "_" + t.Name() + "Required"
+	g.writeClass(base, "TypedDict", required)
+	g.writeClass(t.Name(), base+", total=False", optional)
+}
+
+func (g *generator) writeClass(name, bases string, fields []field) {
+	fmt.Fprintf(&g.buf, "\n\nclass %s(%s):\n", name, bases)
+	if len(fields) == 0 {
+		g.buf.WriteString("    pass\n")
+	}
+	for _, f := range fields {
+		fmt.Fprintf(&g.buf, "    %s: %s\n", f.name, f.typ)
+	}
+}
+
+// pythonType maps a Go type to its JSON shape, writing referenced structs first
+func (g *generator) pythonType(t reflect.Type) string {
+	if t == reflect.TypeOf(time.Time{}) {
+		return "str"
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "str"
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "int"
+	case reflect.Float32, reflect.Float64:
+		return "float"
+	case reflect.Slice, reflect.Array:
+		return "List[" + g.pythonType(t.Elem()) + "]"
+	case reflect.Map:
+		return "Dict[str, " + g.pythonType(t.Elem()) + "]"
+	case reflect.Pointer:
+		return "Optional[" + g.pythonType(t.Elem()) + "]"
+	case reflect.Struct:
+		if g.pending[t] {
+			// recursive type: the class is not defined yet, so use a forward reference
+			return fmt.Sprintf("%q", t.Name())
+		}
+		g.writeStruct(t)
+		return t.Name()
+	}
+	return "Any"
+}
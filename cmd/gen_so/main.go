@@ -52,6 +52,16 @@ func Step(id C.int, action *C.double, len C.int) C.int {
 	return C.int(pybridge.Step(int(id), acts))
 }
 
+//export StepInt
+func StepInt(id C.int, action *C.longlong, len C.int) C.int {
+	// Integer actions (e.g. MultiDiscrete), one value per dimension
+	var acts []int64
+	if len > 0 {
+		acts = unsafe.Slice((*int64)(action), int(len))
+	}
+	return C.int(pybridge.StepInt(int(id), acts))
+}
+
 //export GetObservation
 func GetObservation(id C.int, dest *C.double, maxLen C.int) C.int {
 	return C.int(pybridge.GetObservation(int(id), unsafe.Pointer(dest), int(maxLen)))
@@ -62,6 +72,16 @@ func GetReward(id C.int, dest *C.double, maxLen C.int) C.int {
 	return C.int(pybridge.GetReward(int(id), unsafe.Pointer(dest), int(maxLen)))
 }
 
+//export GetObservation32
+func GetObservation32(id C.int, dest *C.float, maxLen C.int) C.int {
+	return C.int(pybridge.GetObservation32(int(id), unsafe.Pointer(dest), int(maxLen)))
+}
+
+//export GetReward32
+func GetReward32(id C.int, dest *C.float, maxLen C.int) C.int {
+	return C.int(pybridge.GetReward32(int(id), unsafe.Pointer(dest), int(maxLen)))
+}
+
 //export GetDone
 func GetDone(id C.int, dest *C.char, maxLen C.int) C.int {
 	return C.int(pybridge.GetDone(int(id), unsafe.Pointer(dest), int(maxLen)))
@@ -72,6 +92,63 @@ func CloseEnv(id C.int) {
 	pybridge.CloseEnv(int(id))
 }
 
+//export GetStepLatency
+func GetStepLatency(dest *C.char, maxLen C.int) C.int {
+	return C.int(pybridge.GetStepLatency(unsafe.Pointer(dest), int(maxLen)))
+}
+
+// EnvPool-style batched asynchronous API
+
+//export CreatePool
+func CreatePool(name *C.char, cfg *C.char, numEnvs C.int, batchSize C.int) C.int {
+	return C.int(pybridge.CreatePool(C.GoString(name), C.GoString(cfg), int(numEnvs), int(batchSize)))
+}
+
+//export PoolObservationSize
+func PoolObservationSize(id C.int) C.int {
+	return C.int(pybridge.PoolObservationSize(int(id)))
+}
+
+//export PoolActionSize
+func PoolActionSize(id C.int) C.int {
+	return C.int(pybridge.PoolActionSize(int(id)))
+}
+
+//export PoolAsyncReset
+func PoolAsyncReset(id C.int) C.int {
+	return C.int(pybridge.PoolAsyncReset(int(id)))
+}
+
+//export PoolSend
+func PoolSend(id C.int, action *C.double, envIDs *C.int, n C.int) C.int {
+	var acts []float64
+	var ids []int32
+	if n > 0 {
+		ids = unsafe.Slice((*int32)(unsafe.Pointer(envIDs)), int(n))
+		if size := pybridge.PoolActionSize(int(id)); size > 0 {
+			acts = unsafe.Slice((*float64)(action), int(n)*size)
+		}
+	}
+	return C.int(pybridge.PoolSend(int(id), acts, ids))
+}
+
+//export PoolRecv
+func PoolRecv(id C.int, obs *C.double, rewards *C.double, terminated *C.char, truncated *C.char, envIDs *C.int, elapsed *C.int) C.int {
+	return C.int(pybridge.PoolRecv(int(id), unsafe.Pointer(obs), unsafe.Pointer(rewards), unsafe.Pointer(terminated),
+		unsafe.Pointer(truncated), unsafe.Pointer(envIDs), unsafe.Pointer(elapsed)))
+}
+
+//export PoolRecv32
+func PoolRecv32(id C.int, obs *C.float, rewards *C.float, terminated *C.char, truncated *C.char, envIDs *C.int, elapsed *C.int) C.int {
+	return C.int(pybridge.PoolRecv32(int(id), unsafe.Pointer(obs), unsafe.Pointer(rewards), unsafe.Pointer(terminated),
+		unsafe.Pointer(truncated), unsafe.Pointer(envIDs), unsafe.Pointer(elapsed)))
+}
+
+//export ClosePool
+func ClosePool(id C.int) {
+	pybridge.ClosePool(int(id))
+}
+
 func main() {}
 `
 
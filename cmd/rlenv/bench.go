@@ -0,0 +1,609 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"os"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"unsafe"
+
+	"github.com/jelech/rl_env_engine/core"
+	pb "github.com/jelech/rl_env_engine/proto"
+	"github.com/jelech/rl_env_engine/pybridge"
+	"github.com/jelech/rl_env_engine/server"
+	"google.golang.org/grpc"
+)
+
+// benchTransports are the layers rlenv bench can drive an environment through, in report order
+var benchTransports = []string{"inproc", "pybridge", "grpc", "http", "http-raw"}
+
+// benchCase is one scenario configuration measured by rlenv bench
+type benchCase struct {
+	Label    string                 `json:"label"`
+	Scenario string                 `json:"scenario"`
+	Config   map[string]interface{} `json:"config,omitempty"`
+}
+
+// defaultBenchCases cover small vector, continuous-control and image-sized observations
+var defaultBenchCases = []benchCase{
+	{Label: "simple", Scenario: "simple"},
+	{Label: "cartpole", Scenario: "cartpole"},
+	{Label: "pendulum", Scenario: "pendulum"},
+	{Label: "lqr-16x4", Scenario: "lqr", Config: map[string]interface{}{"state_dim": 16, "action_dim": 4}},
+	{Label: "snake-pixels", Scenario: "snake", Config: map[string]interface{}{"obs_type": "pixels", "width": 16, "height": 16, "cell_size": 2}},
+}
+
+// benchResult is the measurement of one case over one transport
+type benchResult struct {
+	Case          string  `json:"case"`
+	Transport     string  `json:"transport"`
+	ObsSize       int     `json:"obs_size"` // observation values per step, summed over agents
+	Steps         int     `json:"steps"`
+	StepsPerSec   float64 `json:"steps_per_sec"`
+	MicrosPerStep float64 `json:"us_per_step"`
+	AllocsPerStep float64 `json:"allocs_per_step"` // process-wide, including the in-process server
+	BytesPerStep  float64 `json:"bytes_per_step"`
+	Error         string  `json:"error,omitempty"` // set when the transport cannot drive the scenario
+}
+
+func runBench(args []string) error {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	var scenarioNames listFlag
+	fs.Var(&scenarioNames, "scenario", "scenario or preset to measure (repeatable, alternative to the positional arguments)")
+	overrides := make(setFlag)
+	fs.Var(overrides, "set", "override a config value of every case, e.g. --set dtype=float32 (repeatable)")
+	transports := fs.String("transports", strings.Join(benchTransports, ","), "comma-separated transports to measure: "+strings.Join(benchTransports, ", "))
+	steps := fs.Int("steps", 10000, "timed steps per case and transport")
+	warmup := fs.Int("warmup", 500, "untimed steps before measuring")
+	seed := fs.Int64("seed", 1, "seed of the random actions")
+	jsonOut := fs.Bool("json", false, "print the results as JSON")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: rlenv bench [scenario...] [flags]")
+		fmt.Fprintln(os.Stderr, "Without scenarios a default suite of representative observation sizes is measured.")
+		fs.PrintDefaults()
+	}
+
+	// 场景名可以出现在标志之前或之后
+	var names []string
+	for len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		names, args = append(names, args[0]), args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	names = append(names, fs.Args()...)
+	names = append(names, scenarioNames...)
+	if *steps <= 0 {
+		return fmt.Errorf("--steps must be positive")
+	}
+
+	selected := make(map[string]bool)
+	for _, t := range strings.Split(*transports, ",") {
+		t = strings.TrimSpace(t)
+		if !contains(benchTransports, t) {
+			return fmt.Errorf("unknown transport %q (one of: %s)", t, strings.Join(benchTransports, ", "))
+		}
+		selected[t] = true
+	}
+
+	engine, err := newEngine()
+	if err != nil {
+		return err
+	}
+	cases := defaultBenchCases
+	if len(names) > 0 {
+		cases = nil
+		for _, name := range names {
+			cases = append(cases, benchCase{Label: name, Scenario: name})
+		}
+	}
+
+	// 服务端按请求打印的日志会干扰计时和输出
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	targets := benchTargets{engine: engine}
+	defer targets.Close()
+
+	var results []benchResult
+	for _, c := range cases {
+		values := make(map[string]interface{})
+		for k, v := range c.Config {
+			values[k] = v
+		}
+		for k, v := range overrides {
+			values[k] = v
+		}
+		c.Config = values
+
+		ref, err := engine.CreateEnvironment(c.Scenario, core.NewBaseConfig(values))
+		if err != nil {
+			return fmt.Errorf("%s: %w", c.Label, err)
+		}
+		pool, obsSize, err := sampleActionPool(ref, *seed)
+		ref.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", c.Label, err)
+		}
+
+		for _, transport := range benchTransports {
+			if !selected[transport] {
+				continue
+			}
+			result := benchResult{Case: c.Label, Transport: transport, ObsSize: obsSize}
+			env, err := targets.open(transport, c)
+			if err == nil {
+				err = measure(env, pool, *warmup, *steps, &result)
+				env.Close()
+			}
+			if err != nil {
+				result.Error = err.Error()
+			}
+			results = append(results, result)
+			if !*jsonOut {
+				fmt.Fprintf(os.Stderr, "%s/%s done\n", c.Label, transport)
+			}
+		}
+	}
+
+	if *jsonOut {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(results)
+	}
+	printBenchResults(results)
+	return nil
+}
+
+// sampleActionPool creates random action sets for the agents of env, replayed cyclically while
+// measuring so sampling does not count towards the step cost; it also reports the observation size
+func sampleActionPool(env core.Environment, seed int64) ([][]core.Action, int, error) {
+	observations, err := env.Reset(context.Background())
+	if err != nil {
+		return nil, 0, fmt.Errorf("reset failed: %w", err)
+	}
+	obsSize := 0
+	for _, obs := range observations {
+		obsSize += len(obs.GetData())
+	}
+	rng := rand.New(rand.NewSource(seed))
+	space := env.GetSpaces().ActionSpace
+	pool := make([][]core.Action, 256)
+	for i := range pool {
+		if pool[i], err = core.SampleActions(env, space, len(observations), rng); err != nil {
+			return nil, 0, fmt.Errorf("failed to sample action: %w", err)
+		}
+	}
+	return pool, obsSize, nil
+}
+
+// measure steps env with the action pool, resetting after every episode, and records the
+// throughput and allocations of the timed steps
+func measure(env core.Environment, pool [][]core.Action, warmup, steps int, result *benchResult) error {
+	ctx := context.Background()
+	observations, err := env.Reset(ctx)
+	if err != nil {
+		return fmt.Errorf("reset failed: %w", err)
+	}
+	core.ReleaseObservations(observations)
+
+	var before runtime.MemStats
+	var start time.Time
+	for i := 0; i < warmup+steps; i++ {
+		if i == warmup {
+			runtime.GC()
+			runtime.ReadMemStats(&before)
+			start = time.Now()
+		}
+		observations, _, dones, err := env.Step(ctx, pool[i%len(pool)])
+		if err != nil {
+			return fmt.Errorf("step failed: %w", err)
+		}
+		core.ReleaseObservations(observations)
+		if core.AllDone(dones) {
+			if observations, err = env.Reset(ctx); err != nil {
+				return fmt.Errorf("reset failed: %w", err)
+			}
+			core.ReleaseObservations(observations)
+		}
+	}
+	elapsed := time.Since(start)
+	var after runtime.MemStats
+	runtime.ReadMemStats(&after)
+
+	result.Steps = steps
+	result.StepsPerSec = float64(steps) / elapsed.Seconds()
+	result.MicrosPerStep = float64(elapsed) / float64(time.Microsecond) / float64(steps)
+	result.AllocsPerStep = float64(after.Mallocs-before.Mallocs) / float64(steps)
+	result.BytesPerStep = float64(after.TotalAlloc-before.TotalAlloc) / float64(steps)
+	return nil
+}
+
+func printBenchResults(results []benchResult) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "case\ttransport\tobs\tsteps/s\tµs/step\tallocs/step\tB/step\t")
+	for _, r := range results {
+		if r.Error != "" {
+			fmt.Fprintf(w, "%s\t%s\t%d\tn/a\t\t\t\t  %s\n", r.Case, r.Transport, r.ObsSize, r.Error)
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%.0f\t%.1f\t%.1f\t%.0f\t\n",
+			r.Case, r.Transport, r.ObsSize, r.StepsPerSec, r.MicrosPerStep, r.AllocsPerStep, r.BytesPerStep)
+	}
+	w.Flush()
+}
+
+// benchTargets lazily starts the loopback servers the remote transports connect to
+type benchTargets struct {
+	engine   *core.SimulationEngine
+	grpcAddr string
+	httpURL  string
+	closers  []func()
+}
+
+func (t *benchTargets) open(transport string, c benchCase) (core.Environment, error) {
+	ctx := context.Background()
+	switch transport {
+	case "inproc":
+		return t.engine.CreateEnvironment(c.Scenario, core.NewBaseConfig(c.Config))
+	case "pybridge":
+		return newBridgeEnvironment(t.engine, c)
+	case "grpc":
+		if t.grpcAddr == "" {
+			lis, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				return nil, err
+			}
+			srv := grpc.NewServer(grpc.MaxRecvMsgSize(server.MaxMessageSize), grpc.MaxSendMsgSize(server.MaxMessageSize))
+			pb.RegisterSimulationServiceServer(srv, server.NewGrpcServer())
+			go srv.Serve(lis)
+			t.grpcAddr = lis.Addr().String()
+			t.closers = append(t.closers, srv.Stop)
+		}
+		return newRemoteEnvironment(ctx, t.grpcAddr, c.Scenario, c.Config)
+	case "http", "http-raw":
+		if t.httpURL == "" {
+			lis, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				return nil, err
+			}
+			srv := &http.Server{Handler: server.NewGymAPI().Handler()}
+			go srv.Serve(lis)
+			t.httpURL = "http://" + lis.Addr().String()
+			t.closers = append(t.closers, func() { srv.Close() })
+		}
+		env, err := newHTTPEnvironment(t.httpURL, c)
+		if err != nil || transport == "http" {
+			return env, err
+		}
+		return &rawHTTPEnvironment{httpEnvironment: env}, nil
+	}
+	return nil, fmt.Errorf("unknown transport %q", transport)
+}
+
+func (t *benchTargets) Close() {
+	for _, closeFn := range t.closers {
+		closeFn()
+	}
+}
+
+// bridgeEnvironment drives an environment through the pybridge API the c-shared library
+// exports, copying results into reused caller-owned buffers like the Python side does
+type bridgeEnvironment struct {
+	id      int
+	action  []float64
+	obs     bridgeObservation
+	rewards []float64
+	doneBuf []byte
+	dones   []bool
+}
+
+// bridgeObservation exposes the flat observation copied out of the bridge
+type bridgeObservation struct {
+	data []float64
+}
+
+func (o *bridgeObservation) GetData() []float64                  { return o.data }
+func (o *bridgeObservation) GetMetadata() map[string]interface{} { return nil }
+
+var _ core.Environment = (*bridgeEnvironment)(nil)
+
+func newBridgeEnvironment(engine *core.SimulationEngine, c benchCase) (*bridgeEnvironment, error) {
+	scenario, err := engine.GetScenario(c.Scenario)
+	if err != nil {
+		return nil, err
+	}
+	pybridge.Register(scenario)
+	config, err := json.Marshal(c.Config)
+	if err != nil {
+		return nil, err
+	}
+	id := pybridge.CreateEnv(c.Scenario, string(config))
+	if id < 0 {
+		return nil, fmt.Errorf("pybridge CreateEnv returned %d", id)
+	}
+	return &bridgeEnvironment{id: id, rewards: make([]float64, 64), doneBuf: make([]byte, 64)}, nil
+}
+
+func (e *bridgeEnvironment) Reset(ctx context.Context) ([]core.Observation, error) {
+	n := pybridge.Reset(e.id)
+	if n < 0 {
+		return nil, fmt.Errorf("pybridge Reset returned %d", n)
+	}
+	e.fetchObservation(n)
+	return e.GetObservations(), nil
+}
+
+func (e *bridgeEnvironment) Step(ctx context.Context, actions []core.Action) ([]core.Observation, []float64, []bool, error) {
+	e.action = e.action[:0]
+	for _, action := range actions {
+		values, err := actionValues(action.GetData())
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		e.action = append(e.action, values...)
+	}
+	if code := pybridge.Step(e.id, e.action); code != 0 {
+		return nil, nil, nil, fmt.Errorf("pybridge Step returned %d (the bridge passes actions as float arrays)", code)
+	}
+	e.fetchObservation(len(e.obs.data))
+	rewards := e.rewards[:pybridge.GetReward(e.id, unsafe.Pointer(&e.rewards[0]), len(e.rewards))]
+	n := pybridge.GetDone(e.id, unsafe.Pointer(&e.doneBuf[0]), len(e.doneBuf))
+	e.dones = e.dones[:0]
+	for _, d := range e.doneBuf[:n] {
+		e.dones = append(e.dones, d != 0)
+	}
+	return e.GetObservations(), rewards, e.dones, nil
+}
+
+// fetchObservation copies the latest flat observation of n values out of the bridge
+func (e *bridgeEnvironment) fetchObservation(n int) {
+	if cap(e.obs.data) < n {
+		e.obs.data = make([]float64, n)
+	}
+	e.obs.data = e.obs.data[:n]
+	if n > 0 {
+		e.obs.data = e.obs.data[:pybridge.GetObservation(e.id, unsafe.Pointer(&e.obs.data[0]), n)]
+	}
+}
+
+func (e *bridgeEnvironment) GetObservations() []core.Observation { return []core.Observation{&e.obs} }
+func (e *bridgeEnvironment) GetReward() []float64                { return e.rewards }
+func (e *bridgeEnvironment) GetInfo() map[string]interface{}     { return map[string]interface{}{} }
+func (e *bridgeEnvironment) GetSpaces() core.SpaceDefinition     { return core.SpaceDefinition{} }
+
+func (e *bridgeEnvironment) Close() error {
+	pybridge.CloseEnv(e.id)
+	return nil
+}
+
+// httpEnvironment drives an environment through the Gym-style JSON API
+type httpEnvironment struct {
+	client  *http.Client
+	baseURL string
+	envID   string
+	buf     bytes.Buffer
+
+	observations []core.Observation
+	rewards      []float64
+	info         map[string]interface{}
+}
+
+var _ core.Environment = (*httpEnvironment)(nil)
+
+func newHTTPEnvironment(baseURL string, c benchCase) (*httpEnvironment, error) {
+	env := &httpEnvironment{
+		client:  &http.Client{},
+		baseURL: baseURL,
+		envID:   fmt.Sprintf("bench-%d", time.Now().UnixNano()),
+	}
+	var resp server.CreateEnvResponse
+	if err := env.post("/create", server.CreateEnvRequest{EnvID: env.envID, Scenario: c.Scenario, Config: c.Config}, &resp); err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, fmt.Errorf("%s", resp.Message)
+	}
+	return env, nil
+}
+
+func (e *httpEnvironment) post(path string, request, response interface{}) error {
+	e.buf.Reset()
+	if err := json.NewEncoder(&e.buf).Encode(request); err != nil {
+		return err
+	}
+	resp, err := e.client.Post(e.baseURL+path, "application/json", &e.buf)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var failure struct {
+			Message string `json:"message"`
+		}
+		json.NewDecoder(resp.Body).Decode(&failure)
+		return fmt.Errorf("%s: %s", path, failure.Message)
+	}
+	return json.NewDecoder(resp.Body).Decode(response)
+}
+
+func (e *httpEnvironment) Reset(ctx context.Context) ([]core.Observation, error) {
+	var resp server.ResetResponse
+	if err := e.post("/reset", server.ResetRequest{EnvID: e.envID}, &resp); err != nil {
+		return nil, err
+	}
+	e.observations = httpObservations(resp.Observation)
+	e.info = resp.Info
+	return e.observations, nil
+}
+
+func (e *httpEnvironment) Step(ctx context.Context, actions []core.Action) ([]core.Observation, []float64, []bool, error) {
+	if len(actions) != 1 {
+		return nil, nil, nil, fmt.Errorf("the HTTP API accepts a single scalar action")
+	}
+	values, err := actionValues(actions[0].GetData())
+	if err != nil || len(values) != 1 {
+		return nil, nil, nil, fmt.Errorf("the HTTP API accepts a single scalar action")
+	}
+	var resp server.StepResponse
+	request := server.StepRequest{EnvID: e.envID, Action: map[string]interface{}{"value": values[0]}}
+	if err := e.post("/step", request, &resp); err != nil {
+		return nil, nil, nil, err
+	}
+	e.observations, e.rewards, e.info = httpObservations(resp.Observation), resp.Reward, resp.Info
+	return e.observations, resp.Reward, resp.Done, nil
+}
+
+func httpObservations(data [][]float64) []core.Observation {
+	observations := make([]core.Observation, len(data))
+	for i, d := range data {
+		observations[i] = core.NewBaseObservation(d, nil)
+	}
+	return observations
+}
+
+func (e *httpEnvironment) GetObservations() []core.Observation { return e.observations }
+func (e *httpEnvironment) GetReward() []float64                { return e.rewards }
+func (e *httpEnvironment) GetInfo() map[string]interface{}     { return e.info }
+func (e *httpEnvironment) GetSpaces() core.SpaceDefinition     { return core.SpaceDefinition{} }
+
+func (e *httpEnvironment) Close() error {
+	var resp map[string]interface{}
+	return e.post("/close", map[string]string{"env_id": e.envID}, &resp)
+}
+
+// rawHTTPEnvironment steps through the binary /step_raw endpoint of the Gym-style API
+type rawHTTPEnvironment struct {
+	*httpEnvironment
+	body   []byte
+	resp   []byte
+	obs    []bridgeObservation
+	views  []core.Observation
+	dones  []bool
+	reward []float64
+}
+
+func (e *rawHTTPEnvironment) Step(ctx context.Context, actions []core.Action) ([]core.Observation, []float64, []bool, error) {
+	e.body = binary.LittleEndian.AppendUint16(e.body[:0], uint16(len(e.envID)))
+	e.body = append(e.body, e.envID...)
+	for _, action := range actions {
+		values, err := actionValues(action.GetData())
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		for _, v := range values {
+			e.body = binary.LittleEndian.AppendUint64(e.body, math.Float64bits(v))
+		}
+	}
+	resp, err := e.client.Post(e.baseURL+"/step_raw", server.RawContentType, bytes.NewReader(e.body))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	defer resp.Body.Close()
+	e.buf.Reset()
+	if _, err := e.buf.ReadFrom(resp.Body); err != nil {
+		return nil, nil, nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, nil, fmt.Errorf("/step_raw: %s", strings.TrimSpace(e.buf.String()))
+	}
+	return e.decode(e.buf.Bytes())
+}
+
+// decode parses a float64 /step_raw response into reused buffers
+func (e *rawHTTPEnvironment) decode(data []byte) ([]core.Observation, []float64, []bool, error) {
+	if len(data) < 4 {
+		return nil, nil, nil, fmt.Errorf("/step_raw: truncated response")
+	}
+	n := int(binary.LittleEndian.Uint32(data))
+	data = data[4:]
+	if len(data) < 4*n {
+		return nil, nil, nil, fmt.Errorf("/step_raw: truncated response")
+	}
+	lengths := data[:4*n]
+	data = data[4*n:]
+	next := func() float64 {
+		v := math.Float64frombits(binary.LittleEndian.Uint64(data))
+		data = data[8:]
+		return v
+	}
+	total := 2 * n
+	for i := 0; i < n; i++ {
+		total += int(binary.LittleEndian.Uint32(lengths[4*i:]))
+	}
+	if len(data) != 8*total {
+		return nil, nil, nil, fmt.Errorf("/step_raw: expected %d values, got %d bytes", total, len(data))
+	}
+
+	if len(e.obs) != n {
+		e.obs = make([]bridgeObservation, n)
+		e.views = make([]core.Observation, n)
+		for i := range e.obs {
+			e.views[i] = &e.obs[i]
+		}
+	}
+	for i := range e.obs {
+		e.obs[i].data = e.obs[i].data[:0]
+		for j := binary.LittleEndian.Uint32(lengths[4*i:]); j > 0; j-- {
+			e.obs[i].data = append(e.obs[i].data, next())
+		}
+	}
+	e.reward, e.dones = e.reward[:0], e.dones[:0]
+	for i := 0; i < n; i++ {
+		e.reward = append(e.reward, next())
+	}
+	for i := 0; i < n; i++ {
+		e.dones = append(e.dones, next() != 0)
+	}
+	e.rewards = e.reward
+	return e.views, e.reward, e.dones, nil
+}
+
+// actionValues flattens the values produced by the action sampler into floats
+func actionValues(data interface{}) ([]float64, error) {
+	switch v := data.(type) {
+	case int:
+		return []float64{float64(v)}, nil
+	case float64:
+		return []float64{v}, nil
+	case []float64:
+		return v, nil
+	case []int:
+		values := make([]float64, len(v))
+		for i, x := range v {
+			values[i] = float64(x)
+		}
+		return values, nil
+	case []bool:
+		values := make([]float64, len(v))
+		for i, x := range v {
+			if x {
+				values[i] = 1
+			}
+		}
+		return values, nil
+	}
+	return nil, fmt.Errorf("unsupported action value %T", data)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
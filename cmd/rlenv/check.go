@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+func runCheck(args []string) error {
+	fs := flag.NewFlagSet("check", flag.ExitOnError)
+	var sf scenarioFlags
+	sf.register(fs)
+	steps := fs.Int("steps", 1000, "number of random steps to take")
+	seed := fs.Int64("seed", 1, "seed of the random actions")
+	scenarioArg, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	engine, err := newEngine()
+	if err != nil {
+		return err
+	}
+	scenario, env, err := sf.createEnvironment(engine, scenarioArg)
+	if err != nil {
+		return err
+	}
+
+	report := core.CheckEnvironment(context.Background(), env, core.CheckOptions{Steps: *steps, Seed: *seed})
+	fmt.Printf("%s: %d steps, %d episodes\n", scenario, report.Steps, report.Episodes)
+	for _, w := range report.Warnings {
+		fmt.Printf("  warning: %s\n", w)
+	}
+	for _, e := range report.Errors {
+		fmt.Printf("  error: %s\n", e)
+	}
+	if !report.OK() {
+		return fmt.Errorf("%d problem(s) found", len(report.Errors))
+	}
+	fmt.Println("  ok")
+	return nil
+}
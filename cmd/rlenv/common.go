@@ -0,0 +1,227 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	simulations "github.com/jelech/rl_env_engine"
+	"github.com/jelech/rl_env_engine/core"
+	"github.com/jelech/rl_env_engine/server"
+	"gopkg.in/yaml.v3"
+)
+
+// newEngine returns an engine with every scenario and default preset the gRPC server exposes
+func newEngine() (*core.SimulationEngine, error) {
+	engine := server.NewGrpcServer().Engine()
+	simulations.InstallScenarios(engine)
+	if err := simulations.InstallPresets(engine); err != nil {
+		return nil, err
+	}
+	return engine, nil
+}
+
+// sortedScenarios lists the registered scenario names in alphabetical order
+func sortedScenarios(engine *core.SimulationEngine) []string {
+	names := engine.ListScenarios()
+	sort.Strings(names)
+	return names
+}
+
+// setFlag collects repeated --set key=value overrides; values are parsed as YAML scalars or flow collections
+type setFlag map[string]interface{}
+
+func (s setFlag) String() string {
+	parts := make([]string, 0, len(s))
+	for k, v := range s {
+		parts = append(parts, fmt.Sprintf("%s=%v", k, v))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
+
+func (s setFlag) Set(value string) error {
+	key, raw, ok := strings.Cut(value, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("expected key=value, got %q", value)
+	}
+	var parsed interface{}
+	if err := yaml.Unmarshal([]byte(raw), &parsed); err != nil || parsed == nil {
+		parsed = raw
+	}
+	s[key] = parsed
+	return nil
+}
+
+// listFlag collects the values of a repeatable string flag
+type listFlag []string
+
+func (l *listFlag) String() string { return strings.Join(*l, ",") }
+
+func (l *listFlag) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// scenarioFlags are the flags shared by subcommands that build an environment
+type scenarioFlags struct {
+	scenario   string
+	configPath string
+	overrides  setFlag
+}
+
+func (f *scenarioFlags) register(fs *flag.FlagSet) {
+	f.overrides = make(setFlag)
+	fs.StringVar(&f.scenario, "scenario", "", "scenario or preset to use (alternative to the positional argument)")
+	fs.StringVar(&f.configPath, "config", "", "YAML/JSON simulation file providing the scenario and its config")
+	fs.Var(f.overrides, "set", "override a config value, e.g. --set max_steps=200 (repeatable)")
+}
+
+// parseArgs parses flags, accepting the scenario name either before or after them
+func parseArgs(fs *flag.FlagSet, args []string) (string, error) {
+	var scenario string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		scenario, args = args[0], args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		return "", err
+	}
+	rest := fs.Args()
+	if scenario == "" && len(rest) > 0 {
+		scenario, rest = rest[0], rest[1:]
+	}
+	if len(rest) > 0 {
+		return "", fmt.Errorf("unexpected arguments: %s", strings.Join(rest, " "))
+	}
+	return scenario, nil
+}
+
+// resolve determines the scenario and config from the config file, the positional
+// argument or --scenario and --set overrides (in increasing priority)
+func (f *scenarioFlags) resolve(engine *core.SimulationEngine, scenario string) (string, map[string]interface{}, error) {
+	if f.scenario != "" {
+		if scenario != "" && scenario != f.scenario {
+			return "", nil, fmt.Errorf("scenario given twice: %q and %q", scenario, f.scenario)
+		}
+		scenario = f.scenario
+	}
+	values := make(map[string]interface{})
+	if f.configPath != "" {
+		file, err := simulations.LoadSimulationFile(f.configPath)
+		if err != nil {
+			return "", nil, err
+		}
+		if scenario == "" {
+			scenario = file.Scenario
+		} else if scenario != file.Scenario {
+			return "", nil, fmt.Errorf("scenario %q does not match %q from %s", scenario, file.Scenario, f.configPath)
+		}
+		values = file.Config
+	}
+	if scenario == "" {
+		return "", nil, fmt.Errorf("scenario name required (one of: %s)", strings.Join(sortedScenarios(engine), ", "))
+	}
+	for k, v := range f.overrides {
+		values[k] = v
+	}
+	return scenario, values, nil
+}
+
+// createEnvironment resolves the scenario and config and creates the environment in-process
+func (f *scenarioFlags) createEnvironment(engine *core.SimulationEngine, scenario string) (string, core.Environment, error) {
+	scenario, values, err := f.resolve(engine, scenario)
+	if err != nil {
+		return "", nil, err
+	}
+	env, err := engine.CreateEnvironment(scenario, core.NewBaseConfig(values))
+	if err != nil {
+		return "", nil, err
+	}
+	return scenario, env, nil
+}
+
+// formatBounds prints low/high compactly: uniform bounds become a single value and long ones their range
+func formatBounds(values []float64) string {
+	if len(values) == 0 {
+		return "-"
+	}
+	uniform := true
+	for _, v := range values[1:] {
+		if v != values[0] {
+			uniform = false
+			break
+		}
+	}
+	if uniform {
+		return fmt.Sprintf("%.4g", values[0])
+	}
+	if len(values) > 4 {
+		lo, hi := values[0], values[0]
+		for _, v := range values {
+			lo, hi = math.Min(lo, v), math.Max(hi, v)
+		}
+		return fmt.Sprintf("[%.4g..%.4g]", lo, hi)
+	}
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = fmt.Sprintf("%.4g", v)
+	}
+	return "[" + strings.Join(parts, " ") + "]"
+}
+
+// formatActionSpace renders an action space in a Gym-like notation
+func formatActionSpace(space core.ActionSpace) string {
+	if space.Type == core.SpaceTypeDiscrete {
+		if len(space.DiscreteValues) > 0 {
+			return fmt.Sprintf("Discrete(%d)", len(space.DiscreteValues))
+		}
+		if len(space.Low) > 0 && len(space.High) > 0 {
+			return fmt.Sprintf("Discrete(%d, start=%g)", int(space.High[0]-space.Low[0])+1, space.Low[0])
+		}
+	}
+	if space.Type == core.SpaceTypeMultiDiscrete {
+		if start := formatBounds(space.Low); start != "0" && start != "-" {
+			return fmt.Sprintf("MultiDiscrete(%v, start=%s)", space.Nvec(), start)
+		}
+		return fmt.Sprintf("MultiDiscrete(%v)", space.Nvec())
+	}
+	if space.Type == core.SpaceTypeText {
+		return formatTextSpace(space.MaxLength, space.Charset)
+	}
+	if space.Type == core.SpaceTypeDict {
+		components := make([]string, 0, len(space.Subspaces))
+		for _, name := range space.SubspaceNames() {
+			components = append(components, fmt.Sprintf("%s: %s", name, formatActionSpace(space.Subspaces[name])))
+		}
+		return fmt.Sprintf("Dict(%s)", strings.Join(components, ", "))
+	}
+	if space.Type == core.SpaceTypeHybrid {
+		parameters := make([]string, len(space.Parameters))
+		for i, p := range space.Parameters {
+			parameters[i] = formatActionSpace(p)
+		}
+		return fmt.Sprintf("Hybrid(%s)", strings.Join(parameters, ", "))
+	}
+	return fmt.Sprintf("%v(%s, %s, %v, %s)", space.Type, formatBounds(space.Low), formatBounds(space.High), space.Shape, space.Dtype)
+}
+
+// formatObservationSpace renders an observation space in a Gym-like notation
+func formatObservationSpace(space core.ObservationSpace) string {
+	if space.Type == core.SpaceTypeText {
+		return formatTextSpace(space.MaxLength, space.Charset)
+	}
+	if space.Type == core.SpaceTypeImage {
+		return fmt.Sprintf("Image(%v, %s)", space.Shape, space.Dtype)
+	}
+	return fmt.Sprintf("%v(%s, %s, %v, %s)", space.Type, formatBounds(space.Low), formatBounds(space.High), space.Shape, space.Dtype)
+}
+
+// formatTextSpace renders a Text space; the charset is omitted when it is the default one
+func formatTextSpace(maxLength int, charset string) string {
+	if charset == "" {
+		return fmt.Sprintf("Text(%d)", maxLength)
+	}
+	return fmt.Sprintf("Text(%d, charset=%q)", maxLength, charset)
+}
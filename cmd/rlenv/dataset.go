@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/jelech/rl_env_engine/core"
+	"github.com/jelech/rl_env_engine/core/record"
+)
+
+func runDataset(args []string) error {
+	fs := flag.NewFlagSet("dataset", flag.ExitOnError)
+	var rf rolloutFlags
+	rf.register(fs)
+	steps := fs.Int("steps", 10000, "number of transitions to collect")
+	maxSteps := fs.Int("max-steps", 1000, "time out episodes after this many steps")
+	output := fs.String("out", "", "output .npz file (required)")
+	agent := fs.Int("agent", 0, "agent whose transitions are collected")
+	actionDtype := fs.String("action-dtype", "", "int64 or float32 (default: int64 if every action is integral)")
+	scenarioArg, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		return fmt.Errorf("--out is required")
+	}
+	if *steps <= 0 || *maxSteps <= 0 {
+		return fmt.Errorf("--steps and --max-steps must be positive")
+	}
+
+	ctx := context.Background()
+	scenario, env, act, err := rf.open(ctx, scenarioArg)
+	if err != nil {
+		return err
+	}
+	var trajectory bytes.Buffer
+	recorder := record.New(env, &trajectory)
+	defer recorder.Close()
+
+	collected := 0
+	for collected < *steps {
+		observations, err := recorder.Reset(ctx)
+		if err != nil {
+			return fmt.Errorf("reset failed after %d steps: %w", collected, err)
+		}
+		for episodeSteps := 0; collected < *steps && episodeSteps < *maxSteps; episodeSteps++ {
+			actions, err := act(observations)
+			if err != nil {
+				return fmt.Errorf("policy failed after %d steps: %w", collected, err)
+			}
+			var dones []bool
+			observations, _, dones, err = recorder.Step(ctx, actions)
+			if err != nil {
+				return fmt.Errorf("step failed after %d steps: %w", collected, err)
+			}
+			collected++
+			if core.AllDone(dones) {
+				break
+			}
+		}
+	}
+	if err := recorder.Stop(); err != nil {
+		return err
+	}
+
+	records, err := record.Read(&trajectory)
+	if err != nil {
+		return err
+	}
+	dataset, err := record.NewDataset(records, *agent, *actionDtype)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(file)
+	err = dataset.WriteNPZ(w)
+	if err == nil {
+		err = w.Flush()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(*output)
+		return err
+	}
+
+	terminals, timeouts := 0, 0
+	for i := range dataset.Terminals {
+		if dataset.Terminals[i] {
+			terminals++
+		}
+		if dataset.Timeouts[i] {
+			timeouts++
+		}
+	}
+	fmt.Printf("%s (%s policy, %s): wrote %d transitions to %s\n", scenario, rf.policyName, rf.mode(), dataset.Len(), *output)
+	fmt.Printf("observations %d, actions %d (%s), %d terminals, %d timeouts\n",
+		dataset.ObservationSize, dataset.ActionSize, dataset.ActionDtype, terminals, timeouts)
+	return nil
+}
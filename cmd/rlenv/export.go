@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/jelech/rl_env_engine/core/record"
+)
+
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	format := fs.String("format", "rlds", "output format: rlds (TFRecord of tf.train.Example episodes) or d4rl (.npz of transition arrays)")
+	agent := fs.Int("agent", 0, "agent whose trajectory is exported")
+	actionDtype := fs.String("action-dtype", "", "int64 or float32 (default: int64 if every action is integral)")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: rlenv export [flags] <trajectory.jsonl> <output>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return fmt.Errorf("expected an input trajectory and an output path")
+	}
+	input, output := fs.Arg(0), fs.Arg(1)
+	if *format != "rlds" && *format != "d4rl" {
+		return fmt.Errorf("unknown format %q (expected rlds or d4rl)", *format)
+	}
+
+	records, err := record.ReadFile(input)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", input, err)
+	}
+
+	file, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(file)
+	var info interface{}
+	if *format == "rlds" {
+		info, err = record.ExportRLDS(w, records, record.RLDSOptions{Agent: *agent, ActionDtype: *actionDtype})
+	} else {
+		var dataset *record.Dataset
+		if dataset, err = record.NewDataset(records, *agent, *actionDtype); err == nil {
+			err = dataset.WriteNPZ(w)
+			info = map[string]interface{}{
+				"transitions":      dataset.Len(),
+				"observation_size": dataset.ObservationSize,
+				"action_size":      dataset.ActionSize,
+				"action_dtype":     dataset.ActionDtype,
+			}
+		}
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(output)
+		return err
+	}
+
+	// The feature sizes are printed so readers can build a tf.io.parse_single_example spec
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(info)
+}
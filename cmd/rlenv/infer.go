@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// runInfer rolls out a scenario with random actions and prints the observation
+// bounds it actually produced, followed by a suggested GetSpaces implementation.
+func runInfer(args []string) error {
+	fs := flag.NewFlagSet("infer", flag.ExitOnError)
+	var sf scenarioFlags
+	sf.register(fs)
+	episodes := fs.Int("episodes", 5, "number of random episodes to roll out")
+	maxSteps := fs.Int("max-steps", 1000, "maximum steps per episode")
+	seed := fs.Int64("seed", 1, "seed of the random actions")
+	margin := fs.Float64("margin", 0, "fraction of the observed range added on both sides of each bound")
+	scenarioArg, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	engine, err := newEngine()
+	if err != nil {
+		return err
+	}
+	scenario, env, err := sf.createEnvironment(engine, scenarioArg)
+	if err != nil {
+		return err
+	}
+
+	result, err := core.InferSpaces(context.Background(), env, core.InferOptions{
+		Episodes: *episodes,
+		MaxSteps: *maxSteps,
+		Seed:     *seed,
+		Margin:   *margin,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s: %d steps, %d episodes\n", scenario, result.Steps, result.Episodes)
+	for i := range result.Min {
+		fmt.Printf("  observation[%d]: observed [%v, %v], suggested [%v, %v]\n",
+			i, result.Min[i], result.Max[i], result.Observation.Low[i], result.Observation.High[i])
+	}
+	for _, m := range result.Mismatches {
+		fmt.Printf("  mismatch: %s\n", m)
+	}
+	if len(result.Mismatches) == 0 {
+		fmt.Println("  declared observation space matches the data")
+	}
+
+	src, err := result.GoSource()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\n// Suggested GetSpaces (observation bounds inferred from %d steps):\n%s", result.Steps, src)
+	return nil
+}
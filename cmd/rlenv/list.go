@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// scenarioListing describes one scenario or preset for `rlenv list`
+type scenarioListing struct {
+	Name             string                 `json:"name"`
+	Description      string                 `json:"description"`
+	Preset           *core.Preset           `json:"preset,omitempty"` // set for presets
+	ActionSpace      *core.ActionSpace      `json:"action_space,omitempty"`
+	ObservationSpace *core.ObservationSpace `json:"observation_space,omitempty"`
+	Metadata         *core.EnvMetadata      `json:"metadata,omitempty"`
+	Error            string                 `json:"error,omitempty"` // set when the default config cannot build an environment
+}
+
+func runList(args []string) error {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "print the listing as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	engine, err := newEngine()
+	if err != nil {
+		return err
+	}
+	var listings []scenarioListing
+	for _, name := range append(sortedScenarios(engine), engine.ListPresets()...) {
+		listing := scenarioListing{Name: name}
+		if preset, ok := engine.GetPreset(name); ok {
+			listing.Preset = &preset
+			listing.Description = fmt.Sprintf("preset of %s %s", preset.Scenario, formatConfig(preset.Config))
+		} else {
+			scenario, err := engine.GetScenario(name)
+			if err != nil {
+				return err
+			}
+			listing.Description = scenario.GetDescription()
+		}
+
+		// Spaces depend on the config, so report those of the default configuration
+		env, err := engine.CreateEnvironment(name, core.NewBaseConfig(map[string]interface{}{}))
+		if err != nil {
+			listing.Error = err.Error()
+		} else {
+			spaces := env.GetSpaces()
+			listing.ActionSpace = &spaces.ActionSpace
+			listing.ObservationSpace = &spaces.ObservationSpace
+			metadata := core.GetEnvMetadata(env)
+			listing.Metadata = &metadata
+			env.Close()
+		}
+		listings = append(listings, listing)
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(listings)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "SCENARIO\tACTION SPACE\tOBSERVATION SPACE\tDESCRIPTION")
+	for _, l := range listings {
+		action, observation := "(requires config)", ""
+		if l.ActionSpace != nil {
+			action = formatActionSpace(*l.ActionSpace)
+			observation = formatObservationSpace(*l.ObservationSpace)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", l.Name, action, observation, l.Description)
+	}
+	return w.Flush()
+}
+
+// formatConfig renders a config map as sorted key=value pairs
+func formatConfig(values map[string]interface{}) string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = fmt.Sprintf("%s=%v", k, values[k])
+	}
+	return "(" + strings.Join(parts, ", ") + ")"
+}
@@ -0,0 +1,77 @@
+// Command rlenv serves, inspects and exercises the built-in simulation scenarios.
+//
+//	rlenv serve [--protocol both|http|grpc] [--host H] [--http-port N] [--grpc-port N] [--presets DIR] [--config FILE] [--metrics SPEC] [--runs-db FILE]
+//	rlenv route --workers ADDR,ADDR... [--host H] [--port N] [--socket PATH]
+//	rlenv list  [--json]
+//	rlenv run   <scenario> [--config FILE] [--set key=value]... [--policy P] [--episodes N] [--max-steps N] [--seed S] [--grpc ADDR] [--record FILE] [--video DIR] [--tensorboard DIR] [--metrics SPEC]
+//	rlenv check <scenario> [--config FILE] [--set key=value]... [--steps N] [--seed S]
+//	rlenv infer <scenario> [--config FILE] [--set key=value]... [--episodes N] [--max-steps N] [--seed S] [--margin F]
+//	rlenv shell <scenario> [--config FILE] [--set key=value]... [--seed S] [--render]
+//	rlenv dataset <scenario> --out FILE.npz [--config FILE] [--set key=value]... [--policy P] [--steps N] [--max-steps N] [--seed S] [--grpc ADDR]
+//	rlenv verify <scenario> [--config FILE] [--set key=value]... [--steps N] [--seed S] [--runs N] [--record FILE | --recording FILE]
+//	rlenv bench [scenario...] [--set key=value]... [--transports inproc,pybridge,grpc,http] [--steps N] [--warmup N] [--seed S] [--json]
+//	rlenv export [--format rlds|d4rl] [--agent N] [--action-dtype int64|float32] <trajectory.jsonl> <output>
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// command is a single rlenv subcommand
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
+
+var commands = []command{
+	{"serve", "start the HTTP and/or gRPC servers", runServe},
+	{"route", "route gRPC requests to a pool of worker servers by env_id", runRoute},
+	{"list", "list registered scenarios and their spaces", runList},
+	{"run", "roll out a scenario with a random policy and print episode statistics", runRun},
+	{"check", "drive a scenario with random actions and report interface violations", runCheck},
+	{"infer", "roll out a scenario with random actions and suggest tight observation bounds", runInfer},
+	{"shell", "open an interactive prompt to reset, step and render a scenario", runShell},
+	{"dataset", "roll out a policy and save the transitions as a D4RL-style .npz dataset", runDataset},
+	{"verify", "re-execute a recorded action sequence and assert bitwise-identical results", runVerify},
+	{"bench", "measure steps/sec in-process, through pybridge, gRPC and HTTP", runBench},
+	{"export", "convert a recorded JSONL trajectory into an offline RL dataset", runExport},
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: rlenv <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-7s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Run 'rlenv <command> -h' for the flags of a command.")
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	name := os.Args[1]
+	if name == "-h" || name == "--help" || name == "help" {
+		usage()
+		return
+	}
+	for _, c := range commands {
+		if c.name == name {
+			if err := c.run(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "rlenv %s: %v\n", name, err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "rlenv: unknown command %q\n\n", name)
+	usage()
+	os.Exit(2)
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/jelech/rl_env_engine/core"
+	corepolicy "github.com/jelech/rl_env_engine/core/policy"
+)
+
+// policy chooses the actions for one step from the current observations
+type policy func(observations []core.Observation) ([]core.Action, error)
+
+// newPolicy builds the named policy, one of the baselines of core/policy
+//
+//	random    - uniform samples from the action space (respecting action masks)
+//	zero      - the action closest to zero (the lowest discrete action)
+//	heuristic - the scenario's hand-written controller
+//
+// or an ONNX model
+//
+//	*.onnx    - deterministic actions of an ONNX model (see core/policy for the supported operators)
+func newPolicy(name, scenario string, env core.Environment, rng *rand.Rand) (policy, error) {
+	space := env.GetSpaces().ActionSpace
+	if strings.HasSuffix(name, ".onnx") {
+		model, err := corepolicy.LoadModel(name)
+		if err != nil {
+			return nil, err
+		}
+		p := model.Policy(space)
+		turnBased, _ := env.(core.TurnBasedEnvironment)
+		return func(observations []core.Observation) ([]core.Action, error) {
+			// Only the player to move acts in turn-based environments
+			if turnBased != nil {
+				if player := turnBased.CurrentPlayer(); player >= 0 && player < len(observations) {
+					observations = observations[player : player+1]
+				}
+			}
+			return p.Act(observations)
+		}, nil
+	}
+	for _, baseline := range corepolicy.Baselines {
+		if name == baseline {
+			p, err := corepolicy.Baseline(name, scenario, env, rng)
+			if err != nil {
+				return nil, err
+			}
+			return p.Act, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown policy %q (expected random, zero, heuristic or an .onnx model)", name)
+}
@@ -0,0 +1,291 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jelech/rl_env_engine/core"
+	pb "github.com/jelech/rl_env_engine/proto"
+	"github.com/jelech/rl_env_engine/server"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// remoteEnvironment adapts an environment hosted by a gRPC server to core.Environment,
+// so rollouts can measure end-to-end server throughput
+type remoteEnvironment struct {
+	conn   *grpc.ClientConn
+	client pb.SimulationServiceClient
+	envID  string
+	spaces core.SpaceDefinition
+
+	observations  []core.Observation
+	rewards       []float64
+	info          map[string]interface{}
+	turnBased     bool
+	currentPlayer int
+}
+
+// remoteTurnBasedEnvironment is a remote environment whose observations name the player
+// to move, so samplers and policies submit a single action per step
+type remoteTurnBasedEnvironment struct {
+	*remoteEnvironment
+}
+
+var (
+	_ core.Environment          = (*remoteEnvironment)(nil)
+	_ core.TurnBasedEnvironment = remoteTurnBasedEnvironment{}
+	_ core.ActionMasker         = remoteTurnBasedEnvironment{}
+)
+
+// newRemoteEnvironment creates the environment on the server. Turn-based environments are only
+// recognizable by their observations, so it resets the environment once to find out
+func newRemoteEnvironment(ctx context.Context, addr, scenario string, values map[string]interface{}) (core.Environment, error) {
+	conn, err := grpc.NewClient(addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(
+			grpc.MaxCallRecvMsgSize(server.MaxMessageSize),
+			grpc.MaxCallSendMsgSize(server.MaxMessageSize),
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", addr, err)
+	}
+
+	// typed scalars keep integer metadata and info as ints instead of Struct doubles
+	configValues := map[string]interface{}{server.TypedValuesKey: true}
+	for k, v := range values {
+		configValues[k] = v
+	}
+	config, err := structpb.NewStruct(configValues)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	env := &remoteEnvironment{
+		conn:   conn,
+		client: pb.NewSimulationServiceClient(conn),
+		envID:  fmt.Sprintf("rlenv-%d", time.Now().UnixNano()),
+	}
+	resp, err := env.client.CreateEnvironment(ctx, &pb.CreateEnvironmentRequest{EnvId: env.envID, Scenario: scenario, Config: config})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if !resp.Success {
+		conn.Close()
+		return nil, fmt.Errorf("%s", resp.Message)
+	}
+
+	spaces, err := env.client.GetSpaces(ctx, &pb.GetSpacesRequest{EnvId: env.envID})
+	if err != nil {
+		env.Close()
+		return nil, err
+	}
+	// servers that predate spaces_json only fill the proto fields
+	if spaces.SpacesJson == "" {
+		env.spaces = protoSpaces(spaces)
+	} else if err := json.Unmarshal([]byte(spaces.SpacesJson), &env.spaces); err != nil {
+		env.Close()
+		return nil, fmt.Errorf("invalid spaces_json: %w", err)
+	}
+
+	if _, err := env.Reset(ctx); err != nil {
+		env.Close()
+		return nil, err
+	}
+	if env.turnBased {
+		return remoteTurnBasedEnvironment{env}, nil
+	}
+	return env, nil
+}
+
+// protoSpaces converts the proto space fields of a GetSpaces response
+func protoSpaces(resp *pb.GetSpacesResponse) core.SpaceDefinition {
+	return core.SpaceDefinition{
+		ActionSpace: core.ActionSpace{
+			Type:           core.SpaceType(resp.ActionSpace.GetType()),
+			Low:            resp.ActionSpace.GetLow(),
+			High:           resp.ActionSpace.GetHigh(),
+			Shape:          resp.ActionSpace.GetShape(),
+			Dtype:          resp.ActionSpace.GetDtype(),
+			DiscreteValues: resp.ActionSpace.GetDiscreteValues(),
+			MaxLength:      int(resp.ActionSpace.GetMaxLength()),
+			Charset:        resp.ActionSpace.GetCharset(),
+		},
+		ObservationSpace: core.ObservationSpace{
+			Type:      core.SpaceType(resp.ObservationSpace.GetType()),
+			Low:       resp.ObservationSpace.GetLow(),
+			High:      resp.ObservationSpace.GetHigh(),
+			Shape:     resp.ObservationSpace.GetShape(),
+			Dtype:     resp.ObservationSpace.GetDtype(),
+			MaxLength: int(resp.ObservationSpace.GetMaxLength()),
+			Charset:   resp.ObservationSpace.GetCharset(),
+		},
+	}
+}
+
+func (e *remoteEnvironment) Reset(ctx context.Context) ([]core.Observation, error) {
+	resp, err := e.client.ResetEnvironment(ctx, &pb.ResetEnvironmentRequest{EnvId: e.envID})
+	if err != nil {
+		return nil, err
+	}
+	e.observations = e.convertObservations(resp.Observations)
+	e.rewards = nil
+	e.info = valuesMap(resp.Info, resp.TypedInfo)
+	e.updateCurrentPlayer()
+	return e.observations, nil
+}
+
+func (e *remoteEnvironment) Step(ctx context.Context, actions []core.Action) ([]core.Observation, []float64, []bool, error) {
+	req := &pb.StepEnvironmentRequest{EnvId: e.envID, Actions: make([]*pb.Action, len(actions))}
+	for i, action := range actions {
+		protoAction, err := toProtoAction(action.GetData())
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		req.Actions[i] = protoAction
+	}
+
+	resp, err := e.client.StepEnvironment(ctx, req)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	e.observations = e.convertObservations(resp.Observations)
+	e.rewards = resp.Rewards
+	e.info = valuesMap(resp.Info, resp.TypedInfo)
+	e.updateCurrentPlayer()
+	return e.observations, resp.Rewards, resp.Done, nil
+}
+
+// updateCurrentPlayer finds the player to move from the observation metadata: turn-based
+// scenarios such as tictactoe tag every observation with its agent_name and the current_player
+func (e *remoteEnvironment) updateCurrentPlayer() {
+	e.currentPlayer = -1
+	for i, obs := range e.observations {
+		metadata := obs.GetMetadata()
+		current, ok := metadata["current_player"]
+		if !ok {
+			continue
+		}
+		e.turnBased = true
+		if metadata["agent_name"] == current {
+			e.currentPlayer = i
+			return
+		}
+	}
+}
+
+// CurrentPlayer returns the index of the player to move, or -1 when no observation names it
+func (e remoteTurnBasedEnvironment) CurrentPlayer() int { return e.currentPlayer }
+
+// GetActionMask returns the action_mask metadata of the player to move, nil when it has none
+func (e remoteTurnBasedEnvironment) GetActionMask() []bool {
+	if e.currentPlayer < 0 {
+		return nil
+	}
+	values, _ := e.observations[e.currentPlayer].GetMetadata()["action_mask"].([]interface{})
+	mask := make([]bool, len(values))
+	for i, v := range values {
+		mask[i], _ = v.(bool)
+	}
+	return mask
+}
+
+func (e *remoteEnvironment) GetObservations() []core.Observation { return e.observations }
+func (e *remoteEnvironment) GetReward() []float64                { return e.rewards }
+func (e *remoteEnvironment) GetInfo() map[string]interface{}     { return e.info }
+func (e *remoteEnvironment) GetSpaces() core.SpaceDefinition     { return e.spaces }
+
+func (e *remoteEnvironment) Close() error {
+	defer e.conn.Close()
+	_, err := e.client.CloseEnvironment(context.Background(), &pb.CloseEnvironmentRequest{EnvId: e.envID})
+	return err
+}
+
+func (e *remoteEnvironment) convertObservations(observations []*pb.Observation) []core.Observation {
+	result := make([]core.Observation, len(observations))
+	for i, obs := range observations {
+		if e.spaces.ObservationSpace.Type == core.SpaceTypeText {
+			result[i] = core.NewTextObservation(obs.Text, valuesMap(obs.Metadata, obs.TypedMetadata))
+			continue
+		}
+		if image := obs.Image; image != nil {
+			result[i] = core.NewImageObservation(image.Pixels, int(image.Height), int(image.Width), int(image.Channels), valuesMap(obs.Metadata, obs.TypedMetadata))
+			continue
+		}
+		data := obs.Data
+		if len(obs.DataF32) > 0 {
+			data = make([]float64, len(obs.DataF32))
+			for j, v := range obs.DataF32 {
+				data[j] = float64(v)
+			}
+		}
+		result[i] = core.NewBaseObservation(data, valuesMap(obs.Metadata, obs.TypedMetadata))
+	}
+	return result
+}
+
+// toProtoAction encodes the action values produced by the sampler and policies
+func toProtoAction(data interface{}) (*pb.Action, error) {
+	switch v := data.(type) {
+	case int:
+		return &pb.Action{Data: &pb.Action_IntValue{IntValue: int64(v)}}, nil
+	case int64:
+		return &pb.Action{Data: &pb.Action_IntValue{IntValue: v}}, nil
+	case float64:
+		return &pb.Action{Data: &pb.Action_FloatValue{FloatValue: v}}, nil
+	case []float64:
+		return &pb.Action{Data: &pb.Action_FloatArray{FloatArray: &pb.FloatArray{Values: v}}}, nil
+	case []int:
+		values := make([]int64, len(v))
+		for i, x := range v {
+			values[i] = int64(x)
+		}
+		return &pb.Action{Data: &pb.Action_IntArray{IntArray: &pb.IntArray{Values: values}}}, nil
+	case []bool:
+		return &pb.Action{Data: &pb.Action_BoolArray{BoolArray: &pb.BoolArray{Values: v}}}, nil
+	case bool:
+		return &pb.Action{Data: &pb.Action_BoolValue{BoolValue: v}}, nil
+	case string:
+		return &pb.Action{Data: &pb.Action_StringValue{StringValue: v}}, nil
+	case map[string]core.Action:
+		dict := &pb.ActionDict{Actions: make(map[string]*pb.Action, len(v))}
+		for name, component := range v {
+			protoAction, err := toProtoAction(component.GetData())
+			if err != nil {
+				return nil, fmt.Errorf("component %q: %w", name, err)
+			}
+			dict.Actions[name] = protoAction
+		}
+		return &pb.Action{Data: &pb.Action_Dict{Dict: dict}}, nil
+	case *core.HybridAction:
+		hybrid := &pb.HybridAction{Choice: int64(v.Choice), Parameters: v.Parameters}
+		return &pb.Action{Data: &pb.Action_Hybrid{Hybrid: hybrid}}, nil
+	}
+	return nil, fmt.Errorf("cannot send action of type %T over gRPC", data)
+}
+
+// valuesMap merges a Struct with the typed scalars servers send for environments created with typed_values
+func valuesMap(s *structpb.Struct, typed map[string]*pb.Value) map[string]interface{} {
+	values := s.AsMap()
+	for k, v := range typed {
+		switch kind := v.Kind.(type) {
+		case *pb.Value_DoubleValue:
+			values[k] = kind.DoubleValue
+		case *pb.Value_IntValue:
+			values[k] = int(kind.IntValue)
+		case *pb.Value_BoolValue:
+			values[k] = kind.BoolValue
+		case *pb.Value_StringValue:
+			values[k] = kind.StringValue
+		default:
+			values[k] = nil
+		}
+	}
+	return values
+}
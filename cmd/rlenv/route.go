@@ -0,0 +1,33 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	simulations "github.com/jelech/rl_env_engine"
+	"github.com/jelech/rl_env_engine/server"
+)
+
+func runRoute(args []string) error {
+	fs := flag.NewFlagSet("route", flag.ExitOnError)
+	host := fs.String("host", "0.0.0.0", "host to bind")
+	port := fs.Int("port", 9090, "gRPC port of the router")
+	socket := fs.String("socket", "", "serve on this Unix socket instead of host and port")
+	workers := fs.String("workers", "", "comma separated gRPC addresses (host:port or unix:///path) of the 'rlenv serve --protocol grpc' workers")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %v", fs.Args())
+	}
+	if *workers == "" {
+		return fmt.Errorf("--workers is required")
+	}
+
+	config := simulations.NewRouterConfig(*port, strings.Split(*workers, ",")).WithHost(*host)
+	if *socket != "" {
+		config.WithHost(server.UnixScheme + *socket)
+	}
+	return simulations.StartRouter(config)
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/jelech/rl_env_engine/core"
+	"github.com/jelech/rl_env_engine/core/metrics"
+	corepolicy "github.com/jelech/rl_env_engine/core/policy"
+	"github.com/jelech/rl_env_engine/core/record"
+	"github.com/jelech/rl_env_engine/core/tensorboard"
+	"github.com/jelech/rl_env_engine/core/video"
+)
+
+// rolloutFlags are the flags shared by subcommands that roll out a policy
+type rolloutFlags struct {
+	scenarioFlags
+	policyName string
+	seed       int64
+	grpcAddr   string
+}
+
+func (f *rolloutFlags) register(fs *flag.FlagSet) {
+	f.scenarioFlags.register(fs)
+	fs.StringVar(&f.policyName, "policy", "random", "policy to follow: random, zero, heuristic or the path of an .onnx model")
+	fs.Int64Var(&f.seed, "seed", 0, "seed of the random policy (0 uses the current time)")
+	fs.StringVar(&f.grpcAddr, "grpc", "", "roll out against a running gRPC server (host:port) instead of in-process")
+}
+
+// open creates the environment, in-process or on the gRPC server, and the policy driving it
+func (f *rolloutFlags) open(ctx context.Context, scenarioArg string) (string, core.Environment, policy, error) {
+	engine, err := newEngine()
+	if err != nil {
+		return "", nil, nil, err
+	}
+	var scenario string
+	var env core.Environment
+	if f.grpcAddr != "" {
+		var values map[string]interface{}
+		if scenario, values, err = f.resolve(engine, scenarioArg); err != nil {
+			return "", nil, nil, err
+		}
+		env, err = newRemoteEnvironment(ctx, f.grpcAddr, scenario, values)
+	} else {
+		scenario, env, err = f.createEnvironment(engine, scenarioArg)
+	}
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	if f.seed == 0 {
+		f.seed = time.Now().UnixNano()
+	}
+	// Heuristics are written per scenario, so look through presets
+	policyScenario := scenario
+	if preset, ok := engine.GetPreset(scenario); ok {
+		policyScenario = preset.Scenario
+	}
+	act, err := newPolicy(f.policyName, policyScenario, env, rand.New(rand.NewSource(f.seed)))
+	if err != nil {
+		env.Close()
+		return "", nil, nil, err
+	}
+	return scenario, env, act, nil
+}
+
+// mode describes where the rollout runs
+func (f *rolloutFlags) mode() string {
+	if f.grpcAddr != "" {
+		return "gRPC " + f.grpcAddr
+	}
+	return "in-process"
+}
+
+func runRun(args []string) error {
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	var rf rolloutFlags
+	rf.register(fs)
+	episodes := fs.Int("episodes", 10, "number of episodes to roll out")
+	maxSteps := fs.Int("max-steps", 10000, "truncate episodes after this many steps")
+	quiet := fs.Bool("quiet", false, "only print the summary")
+	recordPath := fs.String("record", "", "record every transition to this JSONL file")
+	videoDir := fs.String("video", "", "save a GIF of every episode into this directory (needs an RGB-rendering scenario)")
+	metricsSpec := fs.String("metrics", "", "publish episode metrics to stdout and/or statsd://host:port[?prefix=p] (comma separated)")
+	tensorboardDir := fs.String("tensorboard", "", "write episode return/length to a TensorBoard event file in this directory")
+	scenarioArg, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if *episodes <= 0 || *maxSteps <= 0 {
+		return fmt.Errorf("--episodes and --max-steps must be positive")
+	}
+
+	ctx := context.Background()
+	scenario, env, act, err := rf.open(ctx, scenarioArg)
+	if err != nil {
+		return err
+	}
+	if *recordPath != "" {
+		recorder, err := record.NewFile(env, *recordPath)
+		if err != nil {
+			env.Close()
+			return err
+		}
+		env = recorder
+	}
+	if *videoDir != "" {
+		recorder, err := video.New(env, video.Options{Dir: *videoDir, EpisodeTrigger: video.EveryEpisodes(1)})
+		if err != nil {
+			env.Close()
+			return err
+		}
+		env = recorder
+	}
+	if *tensorboardDir != "" {
+		writer, err := tensorboard.NewWriter(*tensorboardDir)
+		if err != nil {
+			env.Close()
+			return err
+		}
+		defer writer.Close()
+		env = tensorboard.NewEpisodeLogger(env, writer)
+	}
+	if *metricsSpec != "" {
+		sink, closeSink, err := metrics.Open(*metricsSpec)
+		if err != nil {
+			env.Close()
+			return err
+		}
+		defer closeSink()
+		env = metrics.NewEpisodeReporter(env, sink, map[string]string{"scenario": scenario})
+	}
+	defer env.Close()
+
+	result, err := corepolicy.Run(ctx, env, corepolicy.Func(act), corepolicy.RunOptions{
+		Episodes: *episodes,
+		MaxSteps: *maxSteps,
+		OnEpisode: func(episode corepolicy.EpisodeResult) error {
+			if !*quiet {
+				suffix := ""
+				if episode.Reason == corepolicy.EpisodeMaxSteps {
+					suffix = " (truncated)"
+				}
+				fmt.Printf("episode %3d  return %10.3f  length %6d%s\n", episode.Episode, episode.Return, episode.Length, suffix)
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+	returns := result.Returns()
+	lengths := make([]float64, len(result.Episodes))
+	for i, length := range result.Lengths() {
+		lengths[i] = float64(length)
+	}
+	totalSteps, elapsed := result.Steps, result.Elapsed
+	truncatedEpisodes := result.Count(corepolicy.EpisodeMaxSteps)
+
+	fmt.Printf("%s (%s policy, %s): %d episodes, %d steps in %v, %.0f steps/s, %.1f µs/step\n",
+		scenario, rf.policyName, rf.mode(), len(returns), totalSteps, elapsed.Round(time.Millisecond),
+		float64(totalSteps)/elapsed.Seconds(), float64(elapsed.Microseconds())/float64(totalSteps))
+	if truncatedEpisodes > 0 {
+		fmt.Printf("%d episode(s) truncated at --max-steps %d\n", truncatedEpisodes, *maxSteps)
+	}
+	fmt.Printf("%-7s %10s %10s %10s %10s %10s %10s %10s\n", "", "mean", "std", "min", "p5", "p50", "p95", "max")
+	printStats("return", returns)
+	printStats("length", lengths)
+	return nil
+}
+
+func printStats(name string, values []float64) {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mean, std := meanStd(sorted)
+	fmt.Printf("%-7s %10.3f %10.3f %10.3f %10.3f %10.3f %10.3f %10.3f\n", name, mean, std,
+		sorted[0], percentile(sorted, 5), percentile(sorted, 50), percentile(sorted, 95), sorted[len(sorted)-1])
+}
+
+// meanStd returns the mean and sample standard deviation, the statistic
+// corepolicy.RunResult.StdReturn reports (0 for a single value)
+func meanStd(values []float64) (mean, std float64) {
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	if len(values) < 2 {
+		return mean, 0
+	}
+	for _, v := range values {
+		std += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(std / float64(len(values)-1))
+}
+
+// percentile linearly interpolates the p-th percentile of sorted values
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 1 {
+		return sorted[0]
+	}
+	rank := p / 100 * float64(len(sorted)-1)
+	lo := int(math.Floor(rank))
+	hi := int(math.Ceil(rank))
+	return sorted[lo] + (rank-float64(lo))*(sorted[hi]-sorted[lo])
+}
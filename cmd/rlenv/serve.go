@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	simulations "github.com/jelech/rl_env_engine"
+	"github.com/jelech/rl_env_engine/core/metrics"
+	"github.com/jelech/rl_env_engine/core/runstore"
+	"github.com/jelech/rl_env_engine/core/wasm"
+	"github.com/jelech/rl_env_engine/server"
+)
+
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	protocol := fs.String("protocol", "both", "servers to start: both, http, grpc or shm")
+	host := fs.String("host", "0.0.0.0", "host to bind")
+	httpPort := fs.Int("http-port", 8080, "HTTP server port")
+	grpcPort := fs.Int("grpc-port", 9090, "gRPC server port")
+	httpSocket := fs.String("http-socket", "", "serve HTTP on this Unix socket instead of host and port")
+	grpcSocket := fs.String("grpc-socket", "", "serve gRPC on this Unix socket instead of host and port")
+	shmSocket := fs.String("shm-socket", simulations.DefaultShmServerConfig().SocketPath, "Unix socket of the shared-memory transport (--protocol shm)")
+	shmDir := fs.String("shm-dir", "", "directory of the shared-memory transport's mapped files (default /dev/shm)")
+	presetDir := fs.String("presets", "", "directory of YAML/JSON simulation files served as named presets and reloaded on change")
+	metricsSpec := fs.String("metrics", "", "publish episode metrics to stdout and/or statsd://host:port[?prefix=p] (comma separated)")
+	runsDB := fs.String("runs-db", "", "SQLite database recording runs and episodes, queryable at HTTP /runs")
+	outputDir := fs.String("output-dir", "", "directory that record_path, video_dir, tensorboard_dir and /record paths given by clients are resolved under (empty rejects them)")
+	gymnasium := fs.Bool("gymnasium", false, "report terminated and truncated flags (gymnasium_api) for every environment by default")
+	maxEnvs := fs.Int("max-envs", 0, "maximum active environments per server (0 = unlimited)")
+	maxEnvsPerClient := fs.Int("max-envs-per-client", 0, "maximum active environments per client host (0 = unlimited)")
+	maxEnvsPerSession := fs.Int("max-envs-per-session", 0, "maximum active environments per client session (0 = unlimited)")
+	maxObsSize := fs.Int("max-obs-size", 0, "maximum observation values per agent of a created environment (0 = unlimited)")
+	adminToken := fs.String("admin-token", "", "token required by the admin API (list, force-close, dump and drain environments, upload WASM scenarios, /record); empty leaves the admin API open and disables WASM uploads and /record")
+	allowWasm := fs.Bool("allow-wasm", false, "let admin clients upload WebAssembly modules as new scenarios, run sandboxed with default memory and fuel limits (requires --admin-token)")
+	snapshotDir := fs.String("snapshot-dir", "", "directory where active environments are saved periodically and restored from on startup")
+	snapshotInterval := fs.Duration("snapshot-interval", server.DefaultSnapshotInterval, "how often environments are saved to --snapshot-dir")
+	tlsCert := fs.String("tls-cert", "", "PEM certificate to serve HTTPS and gRPC over TLS (requires --tls-key)")
+	tlsKey := fs.String("tls-key", "", "PEM private key of --tls-cert")
+	scenarios := fs.String("scenarios", "", "comma separated scenarios and presets clients may create (default all)")
+	sessionTTL := fs.Duration("session-ttl", 0, "idle timeout of client sessions opened without one (default 10m)")
+	maxSessionTTL := fs.Duration("max-session-ttl", 0, "maximum idle timeout a client session may request (0 = unlimited)")
+	logLevel := fs.String("log-level", "", "minimum level logged: debug, info, warn or error (default info)")
+	logFormat := fs.String("log-format", "", "format of log lines: text or json (default text)")
+	otlpEndpoint := fs.String("otlp-endpoint", "", "OTLP/gRPC endpoint (host:port) receiving OpenTelemetry spans of requests, resets and steps")
+	otlpInsecure := fs.Bool("otlp-insecure", false, "connect to --otlp-endpoint without TLS")
+	debugPort := fs.Int("debug-port", 0, "serve pprof (/debug/pprof/) and expvar (/debug/vars) on this separate port (0 = disabled)")
+	debugHost := fs.String("debug-host", "localhost", "host of --debug-port; hosts other than loopback ones require --admin-token")
+	configPath := fs.String("config", "", "YAML/JSON server file, or simulation file with a server section, overriding the flags above")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %v", fs.Args())
+	}
+
+	limits := server.Limits{
+		MaxEnvs:            *maxEnvs,
+		MaxEnvsPerClient:   *maxEnvsPerClient,
+		MaxEnvsPerSession:  *maxEnvsPerSession,
+		MaxObservationSize: *maxObsSize,
+	}
+	config := &simulations.ServerConfig{
+		HTTPConfig: simulations.NewHTTPServerConfig(*httpPort).WithHost(*host).WithPresetDir(*presetDir).WithGymnasiumAPI(*gymnasium).WithLimits(limits).WithAdminToken(*adminToken).WithDebugPort(*debugPort).WithDebugHost(*debugHost),
+		GrpcConfig: simulations.NewGrpcServerConfig(*grpcPort).WithHost(*host).WithPresetDir(*presetDir).WithGymnasiumAPI(*gymnasium).WithLimits(limits).WithAdminToken(*adminToken),
+	}
+	config.LogLevel = *logLevel
+	config.LogFormat = *logFormat
+	if *tlsCert != "" || *tlsKey != "" {
+		config.HTTPConfig.WithTLS(*tlsCert, *tlsKey)
+		config.GrpcConfig.WithTLS(*tlsCert, *tlsKey)
+	}
+	if *scenarios != "" {
+		names := strings.Split(*scenarios, ",")
+		config.HTTPConfig.WithScenarios(names...)
+		config.GrpcConfig.WithScenarios(names...)
+	}
+	config.HTTPConfig.WithSessionTTL(*sessionTTL, *maxSessionTTL)
+	config.GrpcConfig.WithSessionTTL(*sessionTTL, *maxSessionTTL)
+	if *allowWasm {
+		config.HTTPConfig.WithWasmScenarios(wasm.DefaultLimits())
+		config.GrpcConfig.WithWasmScenarios(wasm.DefaultLimits())
+	}
+	if *snapshotDir != "" {
+		config.WithSnapshotDir(*snapshotDir, *snapshotInterval)
+	}
+	if *httpSocket != "" {
+		config.HTTPConfig.WithHost(server.UnixScheme + *httpSocket)
+	}
+	if *grpcSocket != "" {
+		config.GrpcConfig.WithHost(server.UnixScheme + *grpcSocket)
+	}
+	shmConfig := simulations.NewShmServerConfig(*shmSocket).WithDir(*shmDir).WithPresetDir(*presetDir).WithOutputDir(*outputDir)
+	if *outputDir != "" {
+		config.HTTPConfig.WithOutputDir(*outputDir)
+		config.GrpcConfig.WithOutputDir(*outputDir)
+	}
+	if *metricsSpec != "" {
+		sink, closeSink, err := metrics.Open(*metricsSpec)
+		if err != nil {
+			return err
+		}
+		defer closeSink()
+		config.HTTPConfig.WithMetricsSink(sink)
+		config.GrpcConfig.WithMetricsSink(sink)
+		shmConfig.WithMetricsSink(sink)
+	}
+	if *runsDB != "" {
+		store, err := runstore.Open(*runsDB)
+		if err != nil {
+			return err
+		}
+		defer store.Close()
+		config.HTTPConfig.WithRunStore(store)
+		config.GrpcConfig.WithRunStore(store)
+		shmConfig.WithRunStore(store)
+	}
+	var tracing *simulations.TracingOptions
+	if *otlpEndpoint != "" {
+		tracing = &simulations.TracingOptions{Endpoint: *otlpEndpoint, Insecure: *otlpInsecure}
+	}
+	if *configPath != "" {
+		file, err := simulations.LoadServerConfigFile(*configPath)
+		if err != nil {
+			return err
+		}
+		file.ApplyTo(config)
+		if file.Tracing != nil {
+			tracing = file.Tracing
+		}
+	}
+	if config.LogLevel != "" || config.LogFormat != "" {
+		if err := simulations.ConfigureLogging(config.LogLevel, config.LogFormat); err != nil {
+			return err
+		}
+	}
+	if tracing != nil {
+		shutdown, err := simulations.SetupTracing(*tracing)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			shutdown(ctx)
+		}()
+	}
+
+	// Ctrl-C and SIGTERM shut the servers down gracefully, closing their environments and
+	// letting the deferred cleanups above (metrics, run store, tracing) run
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	switch *protocol {
+	case "both":
+		log.Printf("Serving HTTP on %s and gRPC on %s", config.HTTPConfig.Address(), config.GrpcConfig.Address())
+		return simulations.StartServersAndWait(ctx, config)
+	case "http":
+		return simulations.StartHTTPServerContext(ctx, config.HTTPConfig)
+	case "grpc":
+		return simulations.StartGrpcServerContext(ctx, config.GrpcConfig)
+	case "shm":
+		return simulations.StartShmServer(shmConfig)
+	}
+	return fmt.Errorf("unknown protocol %q (expected both, http, grpc or shm)", *protocol)
+}
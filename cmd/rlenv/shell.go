@@ -0,0 +1,487 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+const shellHelp = `Commands:
+  reset                 start a new episode
+  step [ACTION]         take one step; omit ACTION (or use "random") to sample one.
+                        Discrete: an integer; Box/MultiDiscrete/MultiBinary: space-separated
+                        numbers; Text: the words, joined by single spaces;
+                        Dict: NAME=VALUE per component, comma-separating array values;
+                        Hybrid: the action index followed by its parameters.
+                        Separate the actions of several agents with '|'
+  run [N]               take up to N random steps (default 10), stopping when the episode ends
+  obs                   print the observation data (values outside the space bounds end in '!')
+  meta                  print the observation metadata
+  info                  print the environment info
+  spaces                print the action and observation spaces
+  mask                  print the legal actions (ActionMasker environments only)
+  render                print the ASCII frame (Renderer environments only)
+  autorender [on|off]   render after every reset and step
+  help                  show this help
+  quit                  leave the shell`
+
+// shell is an interactive session around a single environment
+type shell struct {
+	ctx        context.Context
+	env        core.Environment
+	out        io.Writer
+	rng        *rand.Rand
+	autoRender bool
+
+	observations  []core.Observation
+	episodeReturn float64
+	steps         int
+	done          bool
+}
+
+func runShell(args []string) error {
+	fs := flag.NewFlagSet("shell", flag.ExitOnError)
+	var sf scenarioFlags
+	sf.register(fs)
+	seed := fs.Int64("seed", 0, "seed of the random actions (0 uses the current time)")
+	autoRender := fs.Bool("render", false, "render after every reset and step")
+	scenarioArg, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	engine, err := newEngine()
+	if err != nil {
+		return err
+	}
+	scenario, env, err := sf.createEnvironment(engine, scenarioArg)
+	if err != nil {
+		return err
+	}
+	defer env.Close()
+
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+	sh := &shell{
+		ctx:        context.Background(),
+		env:        env,
+		out:        os.Stdout,
+		rng:        rand.New(rand.NewSource(*seed)),
+		autoRender: *autoRender,
+	}
+
+	spaces := env.GetSpaces()
+	fmt.Fprintf(sh.out, "%s: action %s, observation %s\n", scenario,
+		formatActionSpace(spaces.ActionSpace), formatObservationSpace(spaces.ObservationSpace))
+	fmt.Fprintln(sh.out, `Type "help" for the list of commands.`)
+	if err := sh.reset(); err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for {
+		fmt.Fprintf(sh.out, "%s> ", scenario)
+		if !scanner.Scan() {
+			fmt.Fprintln(sh.out)
+			return scanner.Err()
+		}
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if fields[0] == "quit" || fields[0] == "exit" {
+			return nil
+		}
+		// Errors in a single command are reported without ending the session
+		if err := sh.execute(fields[0], fields[1:]); err != nil {
+			fmt.Fprintf(sh.out, "error: %v\n", err)
+		}
+	}
+}
+
+func (s *shell) execute(name string, args []string) error {
+	switch name {
+	case "help", "?":
+		fmt.Fprintln(s.out, shellHelp)
+	case "reset":
+		return s.reset()
+	case "step":
+		return s.step(args)
+	case "run":
+		return s.run(args)
+	case "obs":
+		s.printObservations()
+	case "meta":
+		for i, obs := range s.observations {
+			s.printMap(fmt.Sprintf("agent %d", i), obs.GetMetadata())
+		}
+	case "info":
+		s.printMap("info", s.env.GetInfo())
+	case "spaces":
+		spaces := s.env.GetSpaces()
+		fmt.Fprintf(s.out, "action:      %s\n", formatActionSpace(spaces.ActionSpace))
+		fmt.Fprintf(s.out, "observation: %s\n", formatObservationSpace(spaces.ObservationSpace))
+	case "mask":
+		masker, ok := s.env.(core.ActionMasker)
+		if !ok {
+			return fmt.Errorf("environment does not provide action masks")
+		}
+		low := 0
+		if space := s.env.GetSpaces().ActionSpace; len(space.Low) > 0 {
+			low = int(space.Low[0])
+		}
+		var legal []string
+		for i, allowed := range masker.GetActionMask() {
+			if allowed {
+				legal = append(legal, strconv.Itoa(low+i))
+			}
+		}
+		fmt.Fprintf(s.out, "legal actions: %s\n", strings.Join(legal, " "))
+	case "render":
+		return s.render()
+	case "autorender":
+		if len(args) > 0 {
+			switch args[0] {
+			case "on":
+				s.autoRender = true
+			case "off":
+				s.autoRender = false
+			default:
+				return fmt.Errorf("expected on or off, got %q", args[0])
+			}
+		}
+		state := "off"
+		if s.autoRender {
+			state = "on"
+		}
+		fmt.Fprintf(s.out, "autorender %s\n", state)
+	default:
+		return fmt.Errorf("unknown command %q (type \"help\")", name)
+	}
+	return nil
+}
+
+func (s *shell) reset() error {
+	observations, err := s.env.Reset(s.ctx)
+	if err != nil {
+		return err
+	}
+	s.observations = observations
+	s.episodeReturn, s.steps, s.done = 0, 0, false
+	fmt.Fprintf(s.out, "reset: %d observation(s)\n", len(observations))
+	s.printObservations()
+	return s.afterChange()
+}
+
+func (s *shell) step(args []string) error {
+	if s.done {
+		return fmt.Errorf("episode is over, use reset")
+	}
+	var actions []core.Action
+	var err error
+	if len(args) == 0 || (len(args) == 1 && args[0] == "random") {
+		actions, err = core.SampleActions(s.env, s.env.GetSpaces().ActionSpace, len(s.observations), s.rng)
+	} else {
+		actions, err = s.parseActions(args)
+	}
+	if err != nil {
+		return err
+	}
+	if err := s.takeStep(actions, true); err != nil {
+		return err
+	}
+	return s.afterChange()
+}
+
+func (s *shell) run(args []string) error {
+	n := 10
+	if len(args) > 0 {
+		var err error
+		if n, err = strconv.Atoi(args[0]); err != nil || n <= 0 {
+			return fmt.Errorf("expected a positive number of steps, got %q", args[0])
+		}
+	}
+	if s.done {
+		return fmt.Errorf("episode is over, use reset")
+	}
+	space := s.env.GetSpaces().ActionSpace
+	for i := 0; i < n && !s.done; i++ {
+		actions, err := core.SampleActions(s.env, space, len(s.observations), s.rng)
+		if err != nil {
+			return err
+		}
+		if err := s.takeStep(actions, false); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(s.out, "step %d  return %.4g  done %v\n", s.steps, s.episodeReturn, s.done)
+	s.printObservations()
+	return s.afterChange()
+}
+
+func (s *shell) takeStep(actions []core.Action, verbose bool) error {
+	observations, rewards, dones, err := s.env.Step(s.ctx, actions)
+	if err != nil {
+		return err
+	}
+	s.observations = observations
+	s.steps++
+	for _, r := range rewards {
+		s.episodeReturn += r
+	}
+	s.done = core.AllDone(dones)
+	if verbose {
+		data := make([]interface{}, len(actions))
+		for i, a := range actions {
+			data[i] = actionValue(a)
+		}
+		fmt.Fprintf(s.out, "step %d  action %v  reward %s  done %v  return %.4g\n", s.steps, data, formatValues(rewards), dones, s.episodeReturn)
+		s.printObservations()
+	}
+	if s.done {
+		fmt.Fprintf(s.out, "episode finished after %d steps with return %.4g\n", s.steps, s.episodeReturn)
+	}
+	return nil
+}
+
+func (s *shell) afterChange() error {
+	if !s.autoRender {
+		return nil
+	}
+	if _, ok := s.env.(core.Renderer); !ok {
+		return nil
+	}
+	return s.render()
+}
+
+func (s *shell) render() error {
+	renderer, ok := s.env.(core.Renderer)
+	if !ok {
+		return fmt.Errorf("environment does not implement rendering")
+	}
+	fmt.Fprint(s.out, renderer.Render())
+	return nil
+}
+
+// parseActions converts the typed action arguments according to the action space
+func (s *shell) parseActions(args []string) ([]core.Action, error) {
+	var groups [][]string
+	current := []string{}
+	for _, arg := range args {
+		for i, part := range strings.Split(arg, "|") {
+			if i > 0 {
+				groups = append(groups, current)
+				current = []string{}
+			}
+			if part != "" {
+				current = append(current, part)
+			}
+		}
+	}
+	groups = append(groups, current)
+
+	space := s.env.GetSpaces().ActionSpace
+	actions := make([]core.Action, len(groups))
+	for i, group := range groups {
+		action, err := s.parseAction(space, group)
+		if err != nil {
+			if len(groups) > 1 {
+				return nil, fmt.Errorf("agent %d: %w", i, err)
+			}
+			return nil, err
+		}
+		actions[i] = action
+	}
+	return actions, nil
+}
+
+func (s *shell) parseAction(space core.ActionSpace, fields []string) (core.Action, error) {
+	if space.Type == core.SpaceTypeDict {
+		return s.parseDictAction(space, fields)
+	}
+	if space.Type == core.SpaceTypeHybrid {
+		return s.parseHybridAction(space, fields)
+	}
+	if space.Type == core.SpaceTypeText {
+		action := core.NewGenericAction(strings.Join(fields, " "))
+		if _, err := space.TextValue(action); err != nil {
+			return nil, err
+		}
+		return action, nil
+	}
+	values := make([]float64, len(fields))
+	for i, f := range fields {
+		v, err := strconv.ParseFloat(f, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", f)
+		}
+		values[i] = v
+	}
+	if creator, ok := s.env.(core.ActionCreator); ok {
+		return creator.CreateAction(values)
+	}
+
+	switch space.Type {
+	case core.SpaceTypeDiscrete:
+		if len(values) != 1 || values[0] != math.Trunc(values[0]) {
+			return nil, fmt.Errorf("discrete action must be a single integer")
+		}
+		if len(space.DiscreteValues) > 0 {
+			if values[0] < 0 || int(values[0]) >= len(space.DiscreteValues) {
+				return nil, fmt.Errorf("discrete action must be an index in [0, %d)", len(space.DiscreteValues))
+			}
+		} else if len(space.Low) > 0 && len(space.High) > 0 && (values[0] < space.Low[0] || values[0] > space.High[0]) {
+			return nil, fmt.Errorf("discrete action must be in [%g, %g]", space.Low[0], space.High[0])
+		}
+		return core.NewGenericAction(int(values[0])), nil
+
+	case core.SpaceTypeBox, core.SpaceTypeMultiDiscrete, core.SpaceTypeMultiBinary:
+		if size := space.Size(); len(values) != size {
+			return nil, fmt.Errorf("%v action needs %d value(s), got %d", space.Type, size, len(values))
+		}
+		if space.Type != core.SpaceTypeBox {
+			for _, v := range values {
+				if v != math.Trunc(v) {
+					return nil, fmt.Errorf("%v action values must be integers", space.Type)
+				}
+			}
+		}
+		for i, v := range values {
+			if (len(space.Low) > 0 && v < space.Low[min(i, len(space.Low)-1)]) ||
+				(len(space.High) > 0 && v > space.High[min(i, len(space.High)-1)]) {
+				fmt.Fprintf(s.out, "warning: value %d (%g) is outside the action space bounds\n", i, v)
+			}
+		}
+		return core.NewActionFromValues(space, values), nil
+	}
+	return nil, fmt.Errorf("unsupported action space type: %v", space.Type)
+}
+
+// parseDictAction parses NAME=VALUE fields into a dict action, parsing each VALUE against the named subspace
+func (s *shell) parseDictAction(space core.ActionSpace, fields []string) (core.Action, error) {
+	components := make(map[string]core.Action, len(fields))
+	for _, field := range fields {
+		name, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return nil, fmt.Errorf("dict action component %q must be NAME=VALUE", field)
+		}
+		sub, ok := space.Subspaces[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown dict action component %q (expected %v)", name, space.SubspaceNames())
+		}
+		component, err := s.parseAction(sub, strings.Split(value, ","))
+		if err != nil {
+			return nil, fmt.Errorf("component %q: %w", name, err)
+		}
+		components[name] = component
+	}
+	for _, name := range space.SubspaceNames() {
+		if _, ok := components[name]; !ok {
+			return nil, fmt.Errorf("dict action is missing component %q", name)
+		}
+	}
+	return core.NewDictAction(components), nil
+}
+
+// parseHybridAction parses the action index followed by the parameters of that action
+func (s *shell) parseHybridAction(space core.ActionSpace, fields []string) (core.Action, error) {
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("hybrid action needs an action index")
+	}
+	choice, err := strconv.Atoi(fields[0])
+	if err != nil || choice < 0 || choice >= len(space.Parameters) {
+		return nil, fmt.Errorf("hybrid action index must be an integer in [0, %d)", len(space.Parameters))
+	}
+	parameters, err := s.parseAction(space.Parameters[choice], fields[1:])
+	if err != nil {
+		return nil, fmt.Errorf("action %d parameters: %w", choice, err)
+	}
+	values, err := core.NewGenericAction(parameters.GetData()).GetFloat64Values()
+	if err != nil {
+		return nil, err
+	}
+	return core.NewHybridAction(choice, values), nil
+}
+
+// actionValue returns the action data for printing, expanding the components of dict actions
+func actionValue(action core.Action) interface{} {
+	components, ok := action.GetData().(map[string]core.Action)
+	if !ok {
+		return action.GetData()
+	}
+	values := make(map[string]interface{}, len(components))
+	for name, component := range components {
+		values[name] = actionValue(component)
+	}
+	return values
+}
+
+// printObservations prints every observation value, flagging those outside the space bounds
+func (s *shell) printObservations() {
+	space := s.env.GetSpaces().ObservationSpace
+	for i, obs := range s.observations {
+		if text, ok := core.ObservationText(obs); ok {
+			fmt.Fprintf(s.out, "  obs[%d] = %q\n", i, text)
+			continue
+		}
+		if _, height, width, channels, ok := core.ObservationImage(obs); ok {
+			fmt.Fprintf(s.out, "  obs[%d] = image %dx%dx%d (use render to view)\n", i, height, width, channels)
+			continue
+		}
+		data := obs.GetData()
+		parts := make([]string, len(data))
+		for j, v := range data {
+			parts[j] = strconv.FormatFloat(v, 'g', 4, 64)
+			if (len(space.Low) > 0 && v < space.Low[min(j, len(space.Low)-1)]) ||
+				(len(space.High) > 0 && v > space.High[min(j, len(space.High)-1)]) {
+				parts[j] += "!"
+			}
+		}
+		fmt.Fprintf(s.out, "  obs[%d] = [%s]\n", i, strings.Join(parts, " "))
+	}
+}
+
+func formatValues(values []float64) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = strconv.FormatFloat(v, 'g', 4, 64)
+	}
+	return "[" + strings.Join(parts, " ") + "]"
+}
+
+// printMap prints a metadata/info map with sorted keys; nested values are shown as JSON
+func (s *shell) printMap(title string, m map[string]interface{}) {
+	fmt.Fprintf(s.out, "%s:\n", title)
+	if len(m) == 0 {
+		fmt.Fprintln(s.out, "  (empty)")
+		return
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		value := fmt.Sprintf("%v", m[k])
+		switch m[k].(type) {
+		case map[string]interface{}, []interface{}:
+			if encoded, err := json.Marshal(m[k]); err == nil {
+				value = string(encoded)
+			}
+		}
+		fmt.Fprintf(s.out, "  %-20s %s\n", k, value)
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/jelech/rl_env_engine/core"
+	"github.com/jelech/rl_env_engine/core/record"
+)
+
+func runVerify(args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	var sf scenarioFlags
+	sf.register(fs)
+	steps := fs.Int("steps", 1000, "number of random steps to record")
+	seed := fs.Int64("seed", 1, "seed of the random actions")
+	runs := fs.Int("runs", 3, "number of times the recorded actions are re-executed")
+	recordPath := fs.String("record", "", "save the recorded run as a JSONL trajectory for later --recording checks")
+	recordingPath := fs.String("recording", "", "re-execute the actions of this JSONL trajectory instead of recording a new run")
+	scenarioArg, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if *runs <= 0 {
+		return fmt.Errorf("--runs must be positive")
+	}
+
+	engine, err := newEngine()
+	if err != nil {
+		return err
+	}
+	scenario, values, err := sf.resolve(engine, scenarioArg)
+	if err != nil {
+		return err
+	}
+	newEnv := func() (core.Environment, error) {
+		return engine.CreateEnvironment(scenario, core.NewBaseConfig(values))
+	}
+	if _, ok := values["seed"]; !ok {
+		fmt.Printf("note: no seed in the config of %s; scenarios without a fixed seed are expected to diverge\n", scenario)
+	}
+
+	ctx := context.Background()
+	var trace *core.Trace
+	if *recordingPath != "" {
+		records, err := record.ReadFile(*recordingPath)
+		if err != nil {
+			return err
+		}
+		if trace, err = record.NewTrace(records); err != nil {
+			return err
+		}
+		fmt.Printf("%s: replaying %d steps from %s\n", scenario, len(trace.Steps), *recordingPath)
+	} else {
+		env, err := newEnv()
+		if err != nil {
+			return err
+		}
+		if *recordPath != "" {
+			recorder, err := record.NewFile(env, *recordPath)
+			if err != nil {
+				env.Close()
+				return err
+			}
+			env = recorder
+		}
+		trace, err = core.RecordTrace(ctx, env, core.DeterminismOptions{Steps: *steps, Seed: *seed})
+		if closeErr := env.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s: recorded %d steps in %d episode(s) with action seed %d\n", scenario, *steps, resets(trace), *seed)
+	}
+
+	for run := 1; run <= *runs; run++ {
+		env, err := newEnv()
+		if err != nil {
+			return err
+		}
+		divergence := core.ReplayTrace(ctx, env, trace)
+		env.Close()
+		if divergence != nil {
+			return fmt.Errorf("nondeterministic: replay %d diverged at %v", run, divergence)
+		}
+	}
+	fmt.Printf("  %d replay(s) bitwise identical\n", *runs)
+	return nil
+}
+
+// resets counts the episodes started in a trace
+func resets(trace *core.Trace) int {
+	n := 0
+	for _, step := range trace.Steps {
+		if step.Reset {
+			n++
+		}
+	}
+	return n
+}
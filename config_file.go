@@ -0,0 +1,432 @@
+package rl_env_engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jelech/rl_env_engine/core/wasm"
+	"github.com/jelech/rl_env_engine/server"
+	"gopkg.in/yaml.v3"
+)
+
+// SimulationFile represents a simulation definition loaded from a YAML/JSON file
+//
+//	scenario: cartpole
+//	config:
+//	  max_steps: ${MAX_STEPS:-500}
+//	server:
+//	  host: 0.0.0.0
+//	  http_port: 8080
+//	  grpc_port: 9090
+//	  preset_dir: ./presets
+//	  limits:
+//	    max_envs: 256
+//	    max_envs_per_client: 32
+type SimulationFile struct {
+	Scenario string                 `json:"scenario" yaml:"scenario"`
+	Config   map[string]interface{} `json:"config" yaml:"config"`
+	Server   *ServerFileConfig      `json:"server,omitempty" yaml:"server,omitempty"`
+}
+
+// ServerFileConfig represents the optional server section of a simulation file
+type ServerFileConfig struct {
+	Host      string `json:"host" yaml:"host"`
+	HTTPPort  int    `json:"http_port" yaml:"http_port"`
+	GrpcPort  int    `json:"grpc_port" yaml:"grpc_port"`
+	PresetDir string `json:"preset_dir,omitempty" yaml:"preset_dir,omitempty"`
+	// HTTPSocket and GrpcSocket, when set, serve the protocol on a Unix socket at that path
+	// instead of host and port
+	HTTPSocket string `json:"http_socket,omitempty" yaml:"http_socket,omitempty"`
+	GrpcSocket string `json:"grpc_socket,omitempty" yaml:"grpc_socket,omitempty"`
+	// Limits, when set, replaces the resource limits of both servers
+	Limits *server.Limits `json:"limits,omitempty" yaml:"limits,omitempty"`
+	// AdminToken, when set, replaces the admin API token of both servers; use ${VAR} to
+	// keep it out of the file
+	AdminToken string `json:"admin_token,omitempty" yaml:"admin_token,omitempty"`
+	// Tenants, when set, replaces the tenants of both servers; use ${VAR} for their API keys
+	Tenants []server.Tenant `json:"tenants,omitempty" yaml:"tenants,omitempty"`
+	// WasmScenarios, when set, lets clients of both servers upload WebAssembly scenarios
+	// sandboxed by these limits; an empty mapping uses the default limits
+	WasmScenarios *wasm.Limits `json:"wasm_scenarios,omitempty" yaml:"wasm_scenarios,omitempty"`
+	// OutputDir, when set, is the directory of both servers that output paths given by
+	// clients (record_path, video_dir, tensorboard_dir and /record) are resolved under
+	OutputDir string `json:"output_dir,omitempty" yaml:"output_dir,omitempty"`
+	// SnapshotDir, when set, is where both servers periodically save their environments
+	// and restore them from on startup, every SnapshotIntervalSeconds (default 30)
+	SnapshotDir             string  `json:"snapshot_dir,omitempty" yaml:"snapshot_dir,omitempty"`
+	SnapshotIntervalSeconds float64 `json:"snapshot_interval_seconds,omitempty" yaml:"snapshot_interval_seconds,omitempty"`
+	// TLS, when set, serves both servers over TLS (HTTPS for the HTTP API)
+	TLS *TLSFileConfig `json:"tls,omitempty" yaml:"tls,omitempty"`
+	// Scenarios, when set, restricts the scenarios and presets clients may create
+	Scenarios []string `json:"scenarios,omitempty" yaml:"scenarios,omitempty"`
+	// SessionTTLSeconds is the idle timeout of sessions opened without one and
+	// MaxSessionTTLSeconds caps the timeout a client may request
+	SessionTTLSeconds    float64 `json:"session_ttl_seconds,omitempty" yaml:"session_ttl_seconds,omitempty"`
+	MaxSessionTTLSeconds float64 `json:"max_session_ttl_seconds,omitempty" yaml:"max_session_ttl_seconds,omitempty"`
+	// LogLevel is the minimum level logged: debug, info, warn or error
+	LogLevel string `json:"log_level,omitempty" yaml:"log_level,omitempty"`
+	// LogFormat is the format of log lines: text or json
+	LogFormat string `json:"log_format,omitempty" yaml:"log_format,omitempty"`
+	// DebugPort, when set, serves pprof and expvar on this separate port of DebugHost
+	// (localhost by default; other hosts require AdminToken)
+	DebugPort int    `json:"debug_port,omitempty" yaml:"debug_port,omitempty"`
+	DebugHost string `json:"debug_host,omitempty" yaml:"debug_host,omitempty"`
+	// Tracing, when set, exports OpenTelemetry spans to an OTLP endpoint (see SetupTracing);
+	// tracing is process-wide, so ApplyTo leaves it to the caller
+	Tracing *TracingOptions `json:"tracing,omitempty" yaml:"tracing,omitempty"`
+}
+
+// TLSFileConfig names the PEM certificate and private key files of a TLS server
+type TLSFileConfig struct {
+	CertFile string `json:"cert_file" yaml:"cert_file"`
+	KeyFile  string `json:"key_file" yaml:"key_file"`
+}
+
+// serverFile accepts both a standalone server file, whose fields are at the top level,
+// and a simulation file with a server section
+type serverFile struct {
+	ServerFileConfig `yaml:",inline"`
+	Server           *ServerFileConfig `json:"server" yaml:"server"`
+}
+
+// envVarPattern matches ${VAR} and ${VAR:-default}; $$ escapes a literal dollar sign
+var envVarPattern = regexp.MustCompile(`\$\$|\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// ExpandEnv interpolates ${VAR} and ${VAR:-default} references using the process environment.
+// Unlike os.ExpandEnv, referencing an unset variable without a default is an error.
+func ExpandEnv(text string) (string, error) {
+	missing := make(map[string]bool)
+	expanded := envVarPattern.ReplaceAllStringFunc(text, func(match string) string {
+		if match == "$$" {
+			return "$"
+		}
+		groups := envVarPattern.FindStringSubmatch(match)
+		if value, ok := os.LookupEnv(groups[1]); ok {
+			return value
+		}
+		if groups[2] != "" {
+			return groups[3]
+		}
+		missing[groups[1]] = true
+		return match
+	})
+
+	if len(missing) > 0 {
+		names := make([]string, 0, len(missing))
+		for name := range missing {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return "", fmt.Errorf("undefined environment variables: %s", strings.Join(names, ", "))
+	}
+	return expanded, nil
+}
+
+// expandFile interpolates environment variables, leaving full-line YAML comments untouched
+func expandFile(text string, skipComments bool) (string, error) {
+	if !skipComments {
+		return ExpandEnv(text)
+	}
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		expanded, err := ExpandEnv(line)
+		if err != nil {
+			return "", fmt.Errorf("line %d: %w", i+1, err)
+		}
+		lines[i] = expanded
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// normalizeJSONNumbers recursively converts integral float64 values to int
+func normalizeJSONNumbers(v interface{}) interface{} {
+	switch val := v.(type) {
+	case float64:
+		if val == math.Trunc(val) && math.Abs(val) < 1<<53 {
+			return int(val)
+		}
+	case []interface{}:
+		for i := range val {
+			val[i] = normalizeJSONNumbers(val[i])
+		}
+	case map[string]interface{}:
+		for k := range val {
+			val[k] = normalizeJSONNumbers(val[k])
+		}
+	}
+	return v
+}
+
+// LoadSimulationFile reads a simulation definition from a YAML or JSON file.
+// Files ending in .json are parsed as JSON, everything else as YAML (a superset of JSON).
+// Environment variables are interpolated before parsing (full-line YAML comments are skipped).
+func LoadSimulationFile(path string) (*SimulationFile, error) {
+	text, isJSON, err := readConfigFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file := &SimulationFile{}
+	if isJSON {
+		err = json.Unmarshal([]byte(text), file)
+		// JSON has no integer type; restore whole numbers to int to match YAML decoding
+		for k, v := range file.Config {
+			file.Config[k] = normalizeJSONNumbers(v)
+		}
+	} else {
+		err = yaml.Unmarshal([]byte(text), file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	if file.Scenario == "" {
+		return nil, fmt.Errorf("config file %s: scenario is required", path)
+	}
+	if file.Config == nil {
+		file.Config = make(map[string]interface{})
+	}
+	return file, nil
+}
+
+// readConfigFile reads a YAML/JSON file and interpolates its environment variables
+func readConfigFile(path string) (string, bool, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	isJSON := strings.EqualFold(filepath.Ext(path), ".json")
+	text, err := expandFile(string(raw), !isJSON)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to interpolate config file %s: %w", path, err)
+	}
+	return text, isJSON, nil
+}
+
+// LoadServerConfigFile reads the server configuration from a YAML or JSON file: either a
+// standalone server file with the ServerFileConfig fields at the top level, or the server
+// section of a simulation file. Environment variables are interpolated as in LoadSimulationFile
+//
+//	host: 0.0.0.0
+//	http_port: 8080
+//	grpc_port: 9090
+//	tls:
+//	  cert_file: /etc/rlenv/server.crt
+//	  key_file: /etc/rlenv/server.key
+//	admin_token: ${RLENV_ADMIN_TOKEN}
+//	scenarios: [cartpole, pendulum]
+//	session_ttl_seconds: 300
+//	log_level: warn
+//	log_format: json
+//	tracing:
+//	  endpoint: otel-collector:4317
+//	  insecure: true
+func LoadServerConfigFile(path string) (*ServerFileConfig, error) {
+	text, isJSON, err := readConfigFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file := &serverFile{}
+	if isJSON {
+		err = json.Unmarshal([]byte(text), file)
+	} else {
+		err = yaml.Unmarshal([]byte(text), file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	config := &file.ServerFileConfig
+	if file.Server != nil {
+		config = file.Server
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// LoadServerConfig returns the default configuration of both servers overridden by the
+// server configuration file at path, ready for StartServersAndWait
+func LoadServerConfig(path string) (*ServerConfig, error) {
+	file, err := LoadServerConfigFile(path)
+	if err != nil {
+		return nil, err
+	}
+	config := DefaultServerConfig()
+	file.ApplyTo(config)
+	return config, nil
+}
+
+// Validate checks the values that would otherwise only fail when the servers start
+func (c *ServerFileConfig) Validate() error {
+	if c.DebugPort < 0 || c.DebugPort > 65535 {
+		return fmt.Errorf("invalid debug_port %d", c.DebugPort)
+	}
+	if c.DebugPort != 0 && (c.DebugPort == c.HTTPPort || c.DebugPort == c.GrpcPort) {
+		return fmt.Errorf("debug_port %d must differ from http_port and grpc_port", c.DebugPort)
+	}
+	if c.DebugHost != "" && !isLoopbackHost(c.DebugHost) && c.AdminToken == "" {
+		return fmt.Errorf("debug_host %s requires admin_token", c.DebugHost)
+	}
+	if c.WasmScenarios != nil && c.AdminToken == "" {
+		return fmt.Errorf("wasm_scenarios requires admin_token")
+	}
+	if c.TLS != nil && (c.TLS.CertFile == "" || c.TLS.KeyFile == "") {
+		return fmt.Errorf("tls requires both cert_file and key_file")
+	}
+	if c.SessionTTLSeconds < 0 || c.MaxSessionTTLSeconds < 0 {
+		return fmt.Errorf("session TTLs must not be negative")
+	}
+	if c.LogLevel != "" {
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(c.LogLevel)); err != nil {
+			return fmt.Errorf("invalid log_level %q (expected debug, info, warn or error)", c.LogLevel)
+		}
+	}
+	if c.LogFormat != "" && c.LogFormat != "text" && c.LogFormat != "json" {
+		return fmt.Errorf("invalid log_format %q (expected text or json)", c.LogFormat)
+	}
+	if c.Tracing != nil && (c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1) {
+		return fmt.Errorf("tracing sample_ratio must be in [0, 1], got %v", c.Tracing.SampleRatio)
+	}
+	return nil
+}
+
+// NewSimulationFromFile creates a simulation from a YAML/JSON file containing the scenario name and its parameters
+func NewSimulationFromFile(path string) (Simulation, error) {
+	file, err := LoadSimulationFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewSimulation(file.Scenario, file.Config)
+}
+
+// ApplyTo overrides host, ports (including the debug port), Unix sockets, TLS, the preset directory, enabled scenarios, resource limits, session TTLs, tenants, admin token, WASM scenarios, snapshots and log level and format of the given server configuration with the values set in the file
+func (c *ServerFileConfig) ApplyTo(config *ServerConfig) {
+	if c == nil || config == nil {
+		return
+	}
+	if c.LogLevel != "" {
+		config.LogLevel = c.LogLevel
+	}
+	if c.LogFormat != "" {
+		config.LogFormat = c.LogFormat
+	}
+	if c.TLS != nil {
+		if config.HTTPConfig != nil {
+			config.HTTPConfig.WithTLS(c.TLS.CertFile, c.TLS.KeyFile)
+		}
+		if config.GrpcConfig != nil {
+			config.GrpcConfig.WithTLS(c.TLS.CertFile, c.TLS.KeyFile)
+		}
+	}
+	if len(c.Scenarios) > 0 {
+		if config.HTTPConfig != nil {
+			config.HTTPConfig.Scenarios = c.Scenarios
+		}
+		if config.GrpcConfig != nil {
+			config.GrpcConfig.Scenarios = c.Scenarios
+		}
+	}
+	if c.SessionTTLSeconds > 0 || c.MaxSessionTTLSeconds > 0 {
+		ttl := time.Duration(c.SessionTTLSeconds * float64(time.Second))
+		maxTTL := time.Duration(c.MaxSessionTTLSeconds * float64(time.Second))
+		if config.HTTPConfig != nil {
+			config.HTTPConfig.WithSessionTTL(ttl, maxTTL)
+		}
+		if config.GrpcConfig != nil {
+			config.GrpcConfig.WithSessionTTL(ttl, maxTTL)
+		}
+	}
+	if c.Host != "" {
+		if config.HTTPConfig != nil {
+			config.HTTPConfig.Host = c.Host
+		}
+		if config.GrpcConfig != nil {
+			config.GrpcConfig.Host = c.Host
+		}
+	}
+	if c.HTTPPort != 0 && config.HTTPConfig != nil {
+		config.HTTPConfig.Port = c.HTTPPort
+	}
+	if c.GrpcPort != 0 && config.GrpcConfig != nil {
+		config.GrpcConfig.Port = c.GrpcPort
+	}
+	if c.DebugPort != 0 && config.HTTPConfig != nil {
+		config.HTTPConfig.DebugPort = c.DebugPort
+	}
+	if c.DebugHost != "" && config.HTTPConfig != nil {
+		config.HTTPConfig.DebugHost = c.DebugHost
+	}
+	if c.HTTPSocket != "" && config.HTTPConfig != nil {
+		config.HTTPConfig.Host = server.UnixScheme + c.HTTPSocket
+	}
+	if c.GrpcSocket != "" && config.GrpcConfig != nil {
+		config.GrpcConfig.Host = server.UnixScheme + c.GrpcSocket
+	}
+	if c.PresetDir != "" {
+		if config.HTTPConfig != nil {
+			config.HTTPConfig.PresetDir = c.PresetDir
+		}
+		if config.GrpcConfig != nil {
+			config.GrpcConfig.PresetDir = c.PresetDir
+		}
+	}
+	if c.Limits != nil {
+		if config.HTTPConfig != nil {
+			config.HTTPConfig.Limits = *c.Limits
+		}
+		if config.GrpcConfig != nil {
+			config.GrpcConfig.Limits = *c.Limits
+		}
+	}
+	if len(c.Tenants) > 0 {
+		if config.HTTPConfig != nil {
+			config.HTTPConfig.Tenants = c.Tenants
+		}
+		if config.GrpcConfig != nil {
+			config.GrpcConfig.Tenants = c.Tenants
+		}
+	}
+	if c.WasmScenarios != nil {
+		if config.HTTPConfig != nil {
+			config.HTTPConfig.WithWasmScenarios(*c.WasmScenarios)
+		}
+		if config.GrpcConfig != nil {
+			config.GrpcConfig.WithWasmScenarios(*c.WasmScenarios)
+		}
+	}
+	if c.OutputDir != "" {
+		if config.HTTPConfig != nil {
+			config.HTTPConfig.OutputDir = c.OutputDir
+		}
+		if config.GrpcConfig != nil {
+			config.GrpcConfig.OutputDir = c.OutputDir
+		}
+	}
+	if c.SnapshotDir != "" {
+		config.WithSnapshotDir(c.SnapshotDir, time.Duration(c.SnapshotIntervalSeconds*float64(time.Second)))
+	}
+	if c.AdminToken != "" {
+		if config.HTTPConfig != nil {
+			config.HTTPConfig.AdminToken = c.AdminToken
+		}
+		if config.GrpcConfig != nil {
+			config.GrpcConfig.AdminToken = c.AdminToken
+		}
+	}
+}
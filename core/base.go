@@ -3,14 +3,19 @@ package core
 import (
 	"context"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/mitchellh/mapstructure"
 )
 
 // BaseObservation 基础观察实现
 type BaseObservation struct {
-	data     []float64
-	metadata map[string]interface{}
+	data      []float64
+	data32    []float32 // float32存储时的数据，此时data只是GetData的转换缓冲区
+	isFloat32 bool
+	metadata  map[string]interface{}
+	pooled    bool // 由AcquireObservation取得，尚未Release
 }
 
 func NewBaseObservation(data []float64, metadata map[string]interface{}) *BaseObservation {
@@ -24,9 +29,23 @@ func NewBaseObservation(data []float64, metadata map[string]interface{}) *BaseOb
 }
 
 func (o *BaseObservation) GetData() []float64 {
+	if o.isFloat32 {
+		o.data = o.data[:0]
+		for _, v := range o.data32 {
+			o.data = append(o.data, float64(v))
+		}
+	}
 	return o.data
 }
 
+// GetData32 返回float32存储的数据，以float64存储时返回nil
+func (o *BaseObservation) GetData32() []float32 {
+	if !o.isFloat32 {
+		return nil
+	}
+	return o.data32
+}
+
 func (o *BaseObservation) GetMetadata() map[string]interface{} {
 	return o.metadata
 }
@@ -46,6 +65,93 @@ func (c *BaseConfig) GetValue(key string) interface{} {
 	return c.values[key]
 }
 
+// Values 返回全部配置值的副本
+func (c *BaseConfig) Values() map[string]interface{} {
+	values := make(map[string]interface{}, len(c.values))
+	for k, v := range c.values {
+		values[k] = v
+	}
+	return values
+}
+
+// GetInt 获取整数配置，接受整数、整数值的浮点数和数字字符串
+func (c *BaseConfig) GetInt(key string) (int, bool, error) {
+	raw, ok := c.lookup(key)
+	if !ok {
+		return 0, false, nil
+	}
+	v, err := ToInt64(raw)
+	if err == nil && int64(int(v)) != v {
+		err = fmt.Errorf("value %d overflows int", v)
+	}
+	if err != nil {
+		return 0, true, c.convertError(key, raw, err)
+	}
+	return int(v), true, nil
+}
+
+// GetFloat64 获取浮点数配置，接受任意数值和数字字符串
+func (c *BaseConfig) GetFloat64(key string) (float64, bool, error) {
+	raw, ok := c.lookup(key)
+	if !ok {
+		return 0, false, nil
+	}
+	v, err := ToFloat64(raw)
+	if err != nil {
+		return 0, true, c.convertError(key, raw, err)
+	}
+	return v, true, nil
+}
+
+// GetBool 获取布尔配置，接受bool、"true"/"false"等字符串和数值0/1
+func (c *BaseConfig) GetBool(key string) (bool, bool, error) {
+	raw, ok := c.lookup(key)
+	if !ok {
+		return false, false, nil
+	}
+	v, err := ToBool(raw)
+	if err != nil {
+		return false, true, c.convertError(key, raw, err)
+	}
+	return v, true, nil
+}
+
+// GetString 获取字符串配置，数值和布尔值会被格式化为字符串
+func (c *BaseConfig) GetString(key string) (string, bool, error) {
+	raw, ok := c.lookup(key)
+	if !ok {
+		return "", false, nil
+	}
+	v, err := ToString(raw)
+	if err != nil {
+		return "", true, c.convertError(key, raw, err)
+	}
+	return v, true, nil
+}
+
+// GetDuration 获取时长配置，接受 "1.5s"、"200ms" 等字符串或以秒为单位的数值
+func (c *BaseConfig) GetDuration(key string) (time.Duration, bool, error) {
+	raw, ok := c.lookup(key)
+	if !ok {
+		return 0, false, nil
+	}
+	v, err := ToDuration(raw)
+	if err != nil {
+		return 0, true, c.convertError(key, raw, err)
+	}
+	return v, true, nil
+}
+
+// lookup 查找配置值，值为nil视为未设置
+func (c *BaseConfig) lookup(key string) (interface{}, bool) {
+	raw, ok := c.values[key]
+	return raw, ok && raw != nil
+}
+
+func (c *BaseConfig) convertError(key string, raw interface{}, cause error) error {
+	return NewSimulationError(ErrConfigInvalid, fmt.Sprintf("%s: %v (got %v)", key, cause, raw), nil)
+}
+
 func (c *BaseConfig) Validate() error {
 	// 基础配置验证，子类可以重写
 	return nil
@@ -80,6 +186,8 @@ type BaseEnvironment struct {
 	strategy    Strategy
 	state       interface{}
 	metadata    map[string]interface{}
+	obsMetadata bool // 观察是否携带元数据，见ObservationMetadataKey
+	dtype       string
 }
 
 func NewBaseEnvironment(name, description string, config Config) *BaseEnvironment {
@@ -88,9 +196,52 @@ func NewBaseEnvironment(name, description string, config Config) *BaseEnvironmen
 		description: description,
 		config:      config,
 		metadata:    make(map[string]interface{}),
+		obsMetadata: observationMetadataEnabled(config),
+		dtype:       observationDtype(config),
 	}
 }
 
+// ObservationMetadataKey 配置中的该键为false时，场景在GetObservations中不再构建观察元数据，
+// GetMetadata返回nil。只读取观察数据的调用方（pybridge、批量步进等）可借此省去每步构建map的开销
+const ObservationMetadataKey = "observation_metadata"
+
+// ObservationMetadataEnabled 返回观察是否应携带元数据，默认为true。
+// 场景在GetObservations中据此跳过元数据的构建
+func (e *BaseEnvironment) ObservationMetadataEnabled() bool {
+	return e.obsMetadata
+}
+
+// Dtype 返回观察数据的存储精度，DtypeFloat64或DtypeFloat32
+func (e *BaseEnvironment) Dtype() string {
+	return e.dtype
+}
+
+// AcquireObservation 按环境的存储精度从对象池取出观察，场景在GetObservations中用它代替core.AcquireObservation
+func (e *BaseEnvironment) AcquireObservation(data []float64, metadata map[string]interface{}) *BaseObservation {
+	if e.dtype == DtypeFloat32 {
+		return AcquireObservation32(data, metadata)
+	}
+	return AcquireObservation(data, metadata)
+}
+
+// observationDtype 读取DtypeKey，无效的取值已在SimulationEngine.CreateEnvironment中报错，此处按float64处理
+func observationDtype(config Config) string {
+	dtype, err := ParseDtype(config)
+	if err != nil {
+		return DtypeFloat64
+	}
+	return dtype
+}
+
+// observationMetadataEnabled 读取ObservationMetadataKey，未设置或无法解析时为true
+func observationMetadataEnabled(config Config) bool {
+	if config == nil {
+		return true
+	}
+	enabled, ok, err := config.GetBool(ObservationMetadataKey)
+	return !ok || err != nil || enabled
+}
+
 func (e *BaseEnvironment) SetDataLoader(loader DataLoader) {
 	e.dataLoader = loader
 }
@@ -99,6 +250,16 @@ func (e *BaseEnvironment) SetStrategy(strategy Strategy) {
 	e.strategy = strategy
 }
 
+// GetDataLoader 获取环境的数据加载器，未设置时返回nil
+func (e *BaseEnvironment) GetDataLoader() DataLoader {
+	return e.dataLoader
+}
+
+// GetStrategy 获取环境的策略，未设置时返回nil
+func (e *BaseEnvironment) GetStrategy() Strategy {
+	return e.strategy
+}
+
 func (e *BaseEnvironment) GetInfo() map[string]interface{} {
 	info := make(map[string]interface{})
 	info["name"] = e.name
@@ -137,21 +298,71 @@ func (e *BaseEnvironment) Close() error {
 
 // SimulationEngine 仿真引擎
 type SimulationEngine struct {
-	scenarios map[string]Scenario
+	// 场景可能在服务运行期间由客户端上传注册，需要加锁
+	scenariosMu sync.RWMutex
+	scenarios   map[string]Scenario
+
+	// 预设可能在服务运行期间被热加载替换，需要加锁
+	presetsMu  sync.RWMutex
+	registered map[string]Preset // 通过RegisterPreset注册
+	presets    map[string]Preset // 通过SetPresets设置（热加载），同名时优先
+
+	hooks *Hooks
 }
 
 func NewSimulationEngine() *SimulationEngine {
 	return &SimulationEngine{
-		scenarios: make(map[string]Scenario),
+		scenarios:  make(map[string]Scenario),
+		registered: make(map[string]Preset),
+		presets:    make(map[string]Preset),
+		hooks:      &Hooks{},
 	}
 }
 
+// Hooks 返回环境生命周期钩子的注册表，服务端创建的环境会触发其中的钩子
+func (s *SimulationEngine) Hooks() *Hooks {
+	return s.hooks
+}
+
+// SetHooks 替换钩子注册表，便于多个引擎（如HTTP与gRPC服务端各自的引擎）共用同一组钩子；之后创建的环境生效
+func (s *SimulationEngine) SetHooks(hooks *Hooks) {
+	s.hooks = hooks
+}
+
 func (s *SimulationEngine) RegisterScenario(scenario Scenario) {
+	s.scenariosMu.Lock()
+	defer s.scenariosMu.Unlock()
 	s.scenarios[scenario.GetName()] = scenario
 }
 
+// ReplaceScenario 在运行期间注册场景：名称未被占用时新增；已被场景占用时仅当replaceable(原场景)为true时替换；
+// 与预设同名时返回错误。已创建的环境继续使用原场景
+func (s *SimulationEngine) ReplaceScenario(scenario Scenario, replaceable func(old Scenario) bool) error {
+	name := scenario.GetName()
+	if _, ok := s.GetPreset(name); ok {
+		return fmt.Errorf("scenario '%s' conflicts with a preset of the same name", name)
+	}
+	s.scenariosMu.Lock()
+	defer s.scenariosMu.Unlock()
+	if old, exists := s.scenarios[name]; exists && !replaceable(old) {
+		return fmt.Errorf("scenario '%s' already exists", name)
+	}
+	s.scenarios[name] = scenario
+	return nil
+}
+
+// hasScenario 判断name是否为已注册的场景
+func (s *SimulationEngine) hasScenario(name string) bool {
+	s.scenariosMu.RLock()
+	defer s.scenariosMu.RUnlock()
+	_, exists := s.scenarios[name]
+	return exists
+}
+
 func (s *SimulationEngine) GetScenario(name string) (Scenario, error) {
+	s.scenariosMu.RLock()
 	scenario, exists := s.scenarios[name]
+	s.scenariosMu.RUnlock()
 	if !exists {
 		return nil, fmt.Errorf("scenario '%s' not found", name)
 	}
@@ -159,6 +370,8 @@ func (s *SimulationEngine) GetScenario(name string) (Scenario, error) {
 }
 
 func (s *SimulationEngine) ListScenarios() []string {
+	s.scenariosMu.RLock()
+	defer s.scenariosMu.RUnlock()
 	var names []string
 	for name := range s.scenarios {
 		names = append(names, name)
@@ -166,15 +379,59 @@ func (s *SimulationEngine) ListScenarios() []string {
 	return names
 }
 
+// createEnvironment 由场景创建环境，场景panic时返回ErrEnvironmentPanic错误
+func createEnvironment(scenario Scenario, config Config) (env Environment, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			env, err = nil, NewSimulationError(ErrEnvironmentPanic, fmt.Sprintf("scenario '%s' panicked creating an environment: %v", scenario.GetName(), r), nil)
+		}
+	}()
+	return scenario.CreateEnvironment(config)
+}
+
+// CreateEnvironment 创建环境；名称既可以是场景名，也可以是预设名（预设配置作为默认值，config中的值优先）。
+// 配置中动力学参数的值可以是{"min": a, "max": b}范围，此时返回每回合重新采样的DynamicsRandomizer
 func (s *SimulationEngine) CreateEnvironment(scenarioName string, config Config) (Environment, error) {
+	if !s.hasScenario(scenarioName) {
+		if preset, ok := s.GetPreset(scenarioName); ok {
+			merged, err := preset.apply(config)
+			if err != nil {
+				return nil, err
+			}
+			scenarioName, config = preset.Scenario, merged
+		}
+	}
+
 	scenario, err := s.GetScenario(scenarioName)
 	if err != nil {
 		return nil, err
 	}
 
+	config, ranges, err := splitParameterRanges(config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config for scenario '%s': %w", scenarioName, err)
+	}
+	if _, err := ParseDtype(config); err != nil {
+		return nil, err
+	}
 	if err := scenario.ValidateConfig(config); err != nil {
 		return nil, fmt.Errorf("invalid config for scenario '%s': %w", scenarioName, err)
 	}
 
-	return scenario.CreateEnvironment(config)
+	env, err := createEnvironment(scenario, config)
+	if err != nil || ranges == nil {
+		return env, err
+	}
+	// 以配置中的seed作为采样种子，与场景自身的随机数源一致，便于复现
+	seed, _, err := config.GetInt("seed")
+	if err != nil {
+		env.Close()
+		return nil, err
+	}
+	randomizer, err := NewDynamicsRandomizer(env, ranges, int64(seed))
+	if err != nil {
+		env.Close()
+		return nil, fmt.Errorf("invalid config for scenario '%s': %w", scenarioName, err)
+	}
+	return randomizer, nil
 }
@@ -0,0 +1,275 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// CheckOptions 环境检查参数
+type CheckOptions struct {
+	Steps int   // 随机动作的总步数，<=0时使用200
+	Seed  int64 // 随机动作的种子
+}
+
+// CheckReport 环境检查结果
+type CheckReport struct {
+	Steps    int      // 实际执行的步数
+	Episodes int      // 完成的回合数
+	Errors   []string // 违反接口约定的问题
+	Warnings []string // 可疑但不一定错误的情况
+}
+
+// OK 没有错误时返回true
+func (r *CheckReport) OK() bool {
+	return len(r.Errors) == 0
+}
+
+func (r *CheckReport) errorf(format string, args ...interface{}) {
+	r.Errors = append(r.Errors, fmt.Sprintf(format, args...))
+}
+
+// warnOnce 同一条警告只记录一次，避免逐步重复
+func (r *CheckReport) warnOnce(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	for _, w := range r.Warnings {
+		if w == msg {
+			return
+		}
+	}
+	r.Warnings = append(r.Warnings, msg)
+}
+
+// CheckEnvironment 用随机动作驱动环境，检查空间定义是否自洽、观察与空间形状是否一致、
+// Step返回值的长度与数值是否有效，以及回合结束后能否重置。检查结束后会关闭环境
+func CheckEnvironment(ctx context.Context, env Environment, opts CheckOptions) *CheckReport {
+	report := &CheckReport{}
+	steps := opts.Steps
+	if steps <= 0 {
+		steps = 200
+	}
+	rng := rand.New(rand.NewSource(opts.Seed))
+
+	spaces := env.GetSpaces()
+	checkActionSpace(report, spaces.ActionSpace)
+	checkObservationSpace(report, spaces.ObservationSpace)
+	if !report.OK() {
+		return report
+	}
+
+	observations, err := env.Reset(ctx)
+	if err != nil {
+		report.errorf("Reset failed: %v", err)
+		return report
+	}
+	checkObservations(report, "Reset", observations, spaces.ObservationSpace)
+
+	for report.Steps < steps && report.OK() {
+		actions, err := SampleActions(env, spaces.ActionSpace, len(observations), rng)
+		if err != nil {
+			report.errorf("failed to sample action: %v", err)
+			return report
+		}
+
+		var rewards []float64
+		var dones []bool
+		observations, rewards, dones, err = env.Step(ctx, actions)
+		report.Steps++
+		where := fmt.Sprintf("Step %d", report.Steps)
+		if err != nil {
+			report.errorf("%s failed: %v", where, err)
+			break
+		}
+		checkObservations(report, where, observations, spaces.ObservationSpace)
+		if len(rewards) != len(observations) || len(dones) != len(observations) {
+			report.errorf("%s returned %d observations, %d rewards and %d done flags; expected one of each per agent",
+				where, len(observations), len(rewards), len(dones))
+		}
+		for i, r := range rewards {
+			if math.IsNaN(r) || math.IsInf(r, 0) {
+				report.errorf("%s returned non-finite reward %v for agent %d", where, r, i)
+			}
+		}
+		if cached := env.GetReward(); len(cached) != len(rewards) {
+			report.warnOnce("GetReward returned %d values but Step returned %d rewards", len(cached), len(rewards))
+		}
+
+		if AllDone(dones) {
+			report.Episodes++
+			if observations, err = env.Reset(ctx); err != nil {
+				report.errorf("Reset after episode %d failed: %v", report.Episodes, err)
+				break
+			}
+			checkObservations(report, "Reset", observations, spaces.ObservationSpace)
+		}
+	}
+
+	if report.Episodes == 0 && report.OK() {
+		report.warnOnce("no episode finished within %d random steps", report.Steps)
+	}
+	if err := env.Close(); err != nil {
+		report.errorf("Close failed: %v", err)
+	}
+	return report
+}
+
+// SampleActions 为一步仿真采样随机动作：每个观察对应一个动作，回合制环境只采样当前行动方的一个动作。
+// 只需一个动作且环境提供动作掩码时，只在合法动作中采样
+func SampleActions(env Environment, space ActionSpace, numObservations int, rng *rand.Rand) ([]Action, error) {
+	n := numObservations
+	if _, ok := env.(TurnBasedEnvironment); ok {
+		n = 1
+	}
+
+	if masker, ok := env.(ActionMasker); ok && n == 1 && space.Type == SpaceTypeDiscrete && len(space.Low) > 0 {
+		var legal []int
+		for i, allowed := range masker.GetActionMask() {
+			if allowed {
+				legal = append(legal, i)
+			}
+		}
+		if len(legal) > 0 {
+			return []Action{NewGenericAction(int(space.Low[0]) + legal[rng.Intn(len(legal))])}, nil
+		}
+	}
+
+	actions := make([]Action, n)
+	for i := range actions {
+		action, err := SampleAction(space, rng)
+		if err != nil {
+			return nil, err
+		}
+		actions[i] = action
+	}
+	return actions, nil
+}
+
+func checkActionSpace(report *CheckReport, space ActionSpace) {
+	for _, dim := range space.Shape {
+		if dim <= 0 {
+			report.errorf("action space shape %v has non-positive dimension", space.Shape)
+			return
+		}
+	}
+	switch space.Type {
+	case SpaceTypeDiscrete:
+		if len(space.DiscreteValues) == 0 && (len(space.Low) == 0 || len(space.High) == 0) {
+			report.errorf("discrete action space needs low/high or discrete values")
+		} else if len(space.DiscreteValues) == 0 && space.High[0] < space.Low[0] {
+			report.errorf("discrete action space has high %v < low %v", space.High[0], space.Low[0])
+		}
+	case SpaceTypeBox, SpaceTypeMultiDiscrete, SpaceTypeMultiBinary:
+		checkBounds(report, "action", space.Low, space.High, space.Size())
+	case SpaceTypeText:
+		if space.MaxLength <= 0 {
+			report.errorf("text action space has non-positive max length %d", space.MaxLength)
+		}
+	case SpaceTypeDict:
+		if len(space.Subspaces) == 0 {
+			report.errorf("dict action space has no subspaces")
+		}
+		for _, name := range space.SubspaceNames() {
+			checkActionSpace(report, space.Subspaces[name])
+		}
+	case SpaceTypeHybrid:
+		if len(space.Parameters) == 0 {
+			report.errorf("hybrid action space has no actions")
+		}
+		for i, p := range space.Parameters {
+			switch {
+			case p.Type != SpaceTypeBox:
+				report.errorf("hybrid action %d has %v parameters, expected Box", i, p.Type)
+			case p.Size() > 0: // 无参数的动作Shape为[0]
+				checkActionSpace(report, p)
+			}
+		}
+	default:
+		report.errorf("unknown action space type %v", space.Type)
+	}
+}
+
+func checkObservationSpace(report *CheckReport, space ObservationSpace) {
+	for _, dim := range space.Shape {
+		if dim <= 0 {
+			report.errorf("observation space shape %v has non-positive dimension", space.Shape)
+			return
+		}
+	}
+	if space.Type == SpaceTypeText {
+		if space.MaxLength <= 0 {
+			report.errorf("text observation space has non-positive max length %d", space.MaxLength)
+		}
+		return
+	}
+	if space.Type == SpaceTypeImage && len(space.Shape) != 3 {
+		report.errorf("image observation space shape %v is not [height, width, channels]", space.Shape)
+		return
+	}
+	checkBounds(report, "observation", space.Low, space.High, space.Size())
+}
+
+// checkBounds 边界长度应为0（无界）、1（广播）或与展平后的维度一致
+func checkBounds(report *CheckReport, name string, low, high []float64, size int) {
+	for _, b := range [][]float64{low, high} {
+		if len(b) > 1 && len(b) != size {
+			report.errorf("%s space bounds have length %d, expected 1 or %d", name, len(b), size)
+			return
+		}
+	}
+	for i := 0; i < size && len(low) > 0 && len(high) > 0; i++ {
+		if boundAt(high, i, 0) < boundAt(low, i, 0) {
+			report.errorf("%s space dimension %d has high < low", name, i)
+			return
+		}
+	}
+}
+
+func checkObservations(report *CheckReport, where string, observations []Observation, space ObservationSpace) {
+	if len(observations) == 0 {
+		report.errorf("%s returned no observations", where)
+		return
+	}
+	size := space.Size()
+	for agent, obs := range observations {
+		if obs == nil {
+			report.errorf("%s returned nil observation for agent %d", where, agent)
+			continue
+		}
+		if space.Type == SpaceTypeText {
+			if text, ok := ObservationText(obs); !ok {
+				report.errorf("%s returned %T for agent %d, text observation space expects a TextObservation", where, obs, agent)
+			} else if !space.ContainsText(text) {
+				report.errorf("%s returned text observation %q for agent %d outside the text observation space", where, text, agent)
+			}
+			continue
+		}
+		if space.Type == SpaceTypeImage {
+			_, height, width, channels, ok := ObservationImage(obs)
+			if !ok {
+				report.errorf("%s returned %T for agent %d, image observation space expects an ImageObservation", where, obs, agent)
+				continue
+			}
+			if int32(height) != space.Shape[0] || int32(width) != space.Shape[1] || int32(channels) != space.Shape[2] {
+				report.errorf("%s returned image of shape [%d %d %d] for agent %d, space shape is %v",
+					where, height, width, channels, agent, space.Shape)
+				continue
+			}
+		}
+		data := obs.GetData()
+		if len(data) != size {
+			report.errorf("%s returned observation of length %d for agent %d, space shape %v expects %d",
+				where, len(data), agent, space.Shape, size)
+			continue
+		}
+		for i, v := range data {
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				report.errorf("%s returned non-finite observation value %v at index %d", where, v, i)
+				break
+			}
+			if v < boundAt(space.Low, i, math.Inf(-1)) || v > boundAt(space.High, i, math.Inf(1)) {
+				report.warnOnce("observation index %d left the declared bounds", i)
+			}
+		}
+	}
+}
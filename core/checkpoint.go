@@ -0,0 +1,102 @@
+package core
+
+import (
+	"errors"
+	"math/rand"
+)
+
+// Checkpointer 接口，可选实现，用于保存与恢复环境的完整内部状态（含随机数生成器），
+// 服务端据此在重启前后保存并恢复长时间运行的环境
+type Checkpointer interface {
+	// Checkpoint 序列化当前状态
+	Checkpoint() ([]byte, error)
+	// RestoreCheckpoint 从Checkpoint的结果恢复状态，环境需以相同的场景与配置创建
+	RestoreCheckpoint(data []byte) error
+}
+
+// ErrCheckpointUnsupported 环境（及其包装的环境）均未实现Checkpointer
+var ErrCheckpointUnsupported = errors.New("environment does not support checkpoints")
+
+// Checkpoint 沿Unwrap找到实现Checkpointer的环境并序列化其状态
+func Checkpoint(env Environment) ([]byte, error) {
+	checkpointer, ok := findCheckpointer(env)
+	if !ok {
+		return nil, ErrCheckpointUnsupported
+	}
+	return checkpointer.Checkpoint()
+}
+
+// RestoreCheckpoint 沿Unwrap找到实现Checkpointer的环境并恢复其状态
+func RestoreCheckpoint(env Environment, data []byte) error {
+	checkpointer, ok := findCheckpointer(env)
+	if !ok {
+		return ErrCheckpointUnsupported
+	}
+	return checkpointer.RestoreCheckpoint(data)
+}
+
+func findCheckpointer(env Environment) (Checkpointer, bool) {
+	for {
+		if checkpointer, ok := env.(Checkpointer); ok {
+			return checkpointer, true
+		}
+		wrapper, ok := env.(Unwrapper)
+		if !ok {
+			return nil, false
+		}
+		env = wrapper.Unwrap()
+	}
+}
+
+// RandState 随机数源的状态：种子与已产生的随机数个数
+type RandState struct {
+	Seed  int64  `json:"seed"`
+	Draws uint64 `json:"draws"`
+}
+
+// RandSource 可保存状态的随机数源，供实现Checkpointer的场景代替rand.NewSource。
+// math/rand的生成器无法导出内部状态，恢复时以相同种子重新生成Draws个随机数，耗时与Draws成正比
+type RandSource struct {
+	src   rand.Source64
+	state RandState
+}
+
+// NewRandSource 创建以seed为种子的随机数源
+func NewRandSource(seed int64) *RandSource {
+	return &RandSource{
+		src:   rand.NewSource(seed).(rand.Source64),
+		state: RandState{Seed: seed},
+	}
+}
+
+// Int63 实现rand.Source
+func (s *RandSource) Int63() int64 {
+	s.state.Draws++
+	return s.src.Int63()
+}
+
+// Uint64 实现rand.Source64
+func (s *RandSource) Uint64() uint64 {
+	s.state.Draws++
+	return s.src.Uint64()
+}
+
+// Seed 实现rand.Source，重新开始计数
+func (s *RandSource) Seed(seed int64) {
+	s.src.Seed(seed)
+	s.state = RandState{Seed: seed}
+}
+
+// State 返回当前状态
+func (s *RandSource) State() RandState {
+	return s.state
+}
+
+// Restore 恢复到state，之后产生的随机数与保存时的源相同
+func (s *RandSource) Restore(state RandState) {
+	s.src.Seed(state.Seed)
+	for i := uint64(0); i < state.Draws; i++ {
+		s.src.Uint64()
+	}
+	s.state = state
+}
@@ -0,0 +1,373 @@
+// Package config 将 core.Config 绑定到带标签的类型化结构体，统一处理默认值、类型转换与校验。
+//
+// 字段通过 cfg 标签声明配置键与规则：
+//
+//	type Config struct {
+//		MaxSteps  int     `cfg:"max_steps,default=500,min=1"`
+//		Tolerance float64 `cfg:"tolerance,default=0.1,gt=0,max=10"`
+//		Mode      string  `cfg:"mode,default=fast,oneof=fast|accurate"`
+//		Seed      int64   `cfg:"seed"`
+//	}
+//
+// 支持的规则：default（缺省值）、required（必须提供）、min/max（闭区间）、gt/lt（开区间）、
+// oneof（以|分隔的可选值）。字符串会被转换为数值/布尔/时长，整数值的浮点数（如JSON/protobuf
+// 中的数字）会被转换为整数；无法直接转换的复杂类型（切片、map、嵌套结构体）按弱类型规则解码。
+// 所有字段的错误会被汇总后一次性返回。
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jelech/rl_env_engine/core"
+	"github.com/mitchellh/mapstructure"
+)
+
+// TagName 结构体标签名
+const TagName = "cfg"
+
+// FieldError 单个配置项的绑定或校验错误
+type FieldError struct {
+	Key    string      // 配置键
+	Value  interface{} // 原始值，缺省时为nil
+	Reason string
+}
+
+func (e *FieldError) Error() string {
+	if e.Value == nil {
+		return fmt.Sprintf("%s: %s", e.Key, e.Reason)
+	}
+	return fmt.Sprintf("%s: %s (got %v)", e.Key, e.Reason, e.Value)
+}
+
+// Errors 汇总的配置错误
+type Errors []*FieldError
+
+func (e Errors) Error() string {
+	parts := make([]string, len(e))
+	for i, fe := range e {
+		parts[i] = fe.Error()
+	}
+	return fmt.Sprintf("%s: %s", core.ErrConfigInvalid.Error(), strings.Join(parts, "; "))
+}
+
+// Is 使 errors.Is(err, core.ErrConfigInvalid) 成立
+func (e Errors) Is(target error) bool {
+	return target == core.ErrConfigInvalid
+}
+
+// rule 解析后的cfg标签
+type rule struct {
+	key        string
+	def        *string
+	required   bool
+	min, max   *float64
+	gt, lt     *float64
+	oneof      []string
+	fieldIndex []int
+}
+
+// Bind 将src中的配置值绑定到out指向的结构体，src为nil时只应用默认值。
+// 返回的错误为 Errors 类型，包含所有字段的问题
+func Bind(src core.Config, out interface{}) error {
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("config.Bind requires a non-nil pointer to a struct, got %T", out)
+	}
+
+	rules, err := parseRules(rv.Elem().Type())
+	if err != nil {
+		return err
+	}
+
+	var errs Errors
+	for _, r := range rules {
+		field := rv.Elem().FieldByIndex(r.fieldIndex)
+
+		var raw interface{}
+		if src != nil {
+			raw = src.GetValue(r.key)
+		}
+		if raw == nil {
+			if r.required {
+				errs = append(errs, &FieldError{Key: r.key, Reason: "is required"})
+				continue
+			}
+			if r.def == nil {
+				// 无默认值时保留结构体中已有的值
+				if fe := r.check(r.key, field, nil); fe != nil {
+					errs = append(errs, fe)
+				}
+				continue
+			}
+			if err := assign(field, *r.def); err != nil {
+				return fmt.Errorf("invalid default for %s: %w", r.key, err)
+			}
+		} else if err := assign(field, raw); err != nil {
+			errs = append(errs, &FieldError{Key: r.key, Value: raw, Reason: err.Error()})
+			continue
+		}
+
+		if fe := r.check(r.key, field, raw); fe != nil {
+			errs = append(errs, fe)
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// MustDefaults 将out指向的结构体设置为标签中的默认值，默认值非法时panic，用于 DefaultConfig 等场合
+func MustDefaults(out interface{}) {
+	if err := Bind(nil, out); err != nil {
+		panic(err)
+	}
+}
+
+// Validate 按cfg标签中的范围与枚举规则校验结构体当前的值，用于直接构造而非经 Bind 得到的配置。
+// required与default不参与校验
+func Validate(in interface{}) error {
+	rv := reflect.ValueOf(in)
+	if rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("config.Validate requires a struct, got %T", in)
+	}
+
+	rules, err := parseRules(rv.Type())
+	if err != nil {
+		return err
+	}
+	var errs Errors
+	for _, r := range rules {
+		field := rv.FieldByIndex(r.fieldIndex)
+		if fe := r.check(r.key, field, field.Interface()); fe != nil {
+			errs = append(errs, fe)
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Values 将带cfg标签的结构体转换为配置键值表，可用于 core.NewBaseConfig 重新构造配置
+func Values(in interface{}) (map[string]interface{}, error) {
+	rv := reflect.ValueOf(in)
+	if rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("config.Values requires a struct, got %T", in)
+	}
+
+	rules, err := parseRules(rv.Type())
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]interface{}, len(rules))
+	for _, r := range rules {
+		values[r.key] = rv.FieldByIndex(r.fieldIndex).Interface()
+	}
+	return values, nil
+}
+
+// parseRules 解析结构体（含匿名嵌入结构体）上的cfg标签
+func parseRules(t reflect.Type) ([]*rule, error) {
+	var rules []*rule
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, ok := f.Tag.Lookup(TagName)
+		if !ok {
+			if f.Anonymous && f.Type.Kind() == reflect.Struct {
+				nested, err := parseRules(f.Type)
+				if err != nil {
+					return nil, err
+				}
+				for _, r := range nested {
+					r.fieldIndex = append([]int{i}, r.fieldIndex...)
+				}
+				rules = append(rules, nested...)
+			}
+			continue
+		}
+		if tag == "-" || !f.IsExported() {
+			continue
+		}
+
+		r, err := parseTag(tag)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", f.Name, err)
+		}
+		if r.key == "" {
+			r.key = f.Name
+		}
+		r.fieldIndex = []int{i}
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+// parseTag 解析形如 "max_steps,default=500,min=1" 的标签
+func parseTag(tag string) (*rule, error) {
+	parts := strings.Split(tag, ",")
+	r := &rule{key: strings.TrimSpace(parts[0])}
+	for _, part := range parts[1:] {
+		name, value, hasValue := strings.Cut(strings.TrimSpace(part), "=")
+		switch name {
+		case "required":
+			r.required = true
+		case "default":
+			v := value
+			r.def = &v
+		case "oneof":
+			r.oneof = strings.Split(value, "|")
+		case "min", "max", "gt", "lt":
+			if !hasValue {
+				return nil, fmt.Errorf("%s requires a value", name)
+			}
+			bound, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s bound %q", name, value)
+			}
+			switch name {
+			case "min":
+				r.min = &bound
+			case "max":
+				r.max = &bound
+			case "gt":
+				r.gt = &bound
+			default:
+				r.lt = &bound
+			}
+		default:
+			return nil, fmt.Errorf("unknown cfg option %q", name)
+		}
+	}
+	return r, nil
+}
+
+// check 校验绑定后的值是否满足范围和枚举约束
+func (r *rule) check(key string, field reflect.Value, raw interface{}) *FieldError {
+	if len(r.oneof) > 0 && field.Kind() == reflect.String {
+		s := field.String()
+		for _, option := range r.oneof {
+			if s == option {
+				return nil
+			}
+		}
+		return &FieldError{Key: key, Value: raw, Reason: fmt.Sprintf("must be one of %s", strings.Join(r.oneof, ", "))}
+	}
+
+	v, ok := numericValue(field)
+	if !ok {
+		return nil
+	}
+	switch {
+	case r.min != nil && v < *r.min:
+		return &FieldError{Key: key, Value: raw, Reason: fmt.Sprintf("must be >= %v", *r.min)}
+	case r.max != nil && v > *r.max:
+		return &FieldError{Key: key, Value: raw, Reason: fmt.Sprintf("must be <= %v", *r.max)}
+	case r.gt != nil && v <= *r.gt:
+		return &FieldError{Key: key, Value: raw, Reason: fmt.Sprintf("must be > %v", *r.gt)}
+	case r.lt != nil && v >= *r.lt:
+		return &FieldError{Key: key, Value: raw, Reason: fmt.Sprintf("must be < %v", *r.lt)}
+	}
+	return nil
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// numericValue 返回数值字段的float64值，时长按秒计算
+func numericValue(field reflect.Value) (float64, bool) {
+	if field.Type() == durationType {
+		return time.Duration(field.Int()).Seconds(), true
+	}
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(field.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(field.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return field.Float(), true
+	}
+	return 0, false
+}
+
+// assign 将原始值转换为字段类型并赋值
+func assign(field reflect.Value, raw interface{}) error {
+	if field.Type() == durationType {
+		d, err := core.ToDuration(raw)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.Bool:
+		b, err := core.ToBool(raw)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := core.ToInt64(raw)
+		if err != nil {
+			return err
+		}
+		if field.OverflowInt(n) {
+			return fmt.Errorf("value %d overflows %s", n, field.Type())
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := core.ToInt64(raw)
+		if err != nil {
+			return err
+		}
+		if n < 0 || field.OverflowUint(uint64(n)) {
+			return fmt.Errorf("value %d out of range for %s", n, field.Type())
+		}
+		field.SetUint(uint64(n))
+	case reflect.Float32, reflect.Float64:
+		f, err := core.ToFloat64(raw)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	case reflect.String:
+		s, err := core.ToString(raw)
+		if err != nil {
+			return err
+		}
+		field.SetString(s)
+	default:
+		// 复杂类型：默认值字符串按JSON解析，其余按弱类型规则解码
+		if s, ok := raw.(string); ok && field.Kind() != reflect.Slice {
+			var decoded interface{}
+			if err := json.Unmarshal([]byte(s), &decoded); err == nil {
+				raw = decoded
+			}
+		}
+		decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+			Result:           field.Addr().Interface(),
+			TagName:          "json",
+			WeaklyTypedInput: true,
+		})
+		if err != nil {
+			return err
+		}
+		if err := decoder.Decode(raw); err != nil {
+			return fmt.Errorf("cannot decode into %s: %v", field.Type(), err)
+		}
+	}
+	return nil
+}
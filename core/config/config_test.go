@@ -0,0 +1,169 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// Embedded 匿名嵌入的结构体，其字段的规则与外层字段一同解析
+type Embedded struct {
+	Seed int64 `cfg:"seed"`
+}
+
+type testConfig struct {
+	Embedded
+	MaxSteps  int           `cfg:"max_steps,default=500,min=1"`
+	Tolerance float64       `cfg:"tolerance,default=0.1,gt=0,max=10"`
+	Mode      string        `cfg:"mode,default=fast,oneof=fast|accurate"`
+	Timeout   time.Duration `cfg:"timeout,default=1s,lt=60"`
+	Verbose   bool          `cfg:"verbose"`
+	Weights   []float64     `cfg:"weights"`
+	Ignored   int           `cfg:"-"`
+}
+
+func TestBindDefaults(t *testing.T) {
+	var c testConfig
+	if err := Bind(nil, &c); err != nil {
+		t.Fatal(err)
+	}
+	want := testConfig{MaxSteps: 500, Tolerance: 0.1, Mode: "fast", Timeout: time.Second}
+	if !reflect.DeepEqual(c, want) {
+		t.Errorf("Bind(nil) = %+v, want %+v", c, want)
+	}
+}
+
+func TestBindConversions(t *testing.T) {
+	var c testConfig
+	src := core.NewBaseConfig(map[string]interface{}{
+		"seed":      float64(42), // JSON与protobuf中的数字
+		"max_steps": "200",
+		"tolerance": 2,
+		"mode":      "accurate",
+		"timeout":   "1.5s",
+		"verbose":   "true",
+		"weights":   []interface{}{0.5, 3},
+	})
+	if err := Bind(src, &c); err != nil {
+		t.Fatal(err)
+	}
+	want := testConfig{Embedded: Embedded{Seed: 42}, MaxSteps: 200, Tolerance: 2, Mode: "accurate",
+		Timeout: 1500 * time.Millisecond, Verbose: true, Weights: []float64{0.5, 3}}
+	if !reflect.DeepEqual(c, want) {
+		t.Errorf("Bind = %+v, want %+v", c, want)
+	}
+}
+
+func TestBindErrors(t *testing.T) {
+	var c testConfig
+	src := core.NewBaseConfig(map[string]interface{}{
+		"seed":      1.5,
+		"max_steps": 0,
+		"tolerance": 0,
+		"mode":      "slow",
+		"timeout":   "2m",
+	})
+	err := Bind(src, &c)
+	var errs Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Bind returned %v (%T), want Errors", err, err)
+	}
+	if !errors.Is(err, core.ErrConfigInvalid) {
+		t.Errorf("errors.Is(%v, ErrConfigInvalid) = false", err)
+	}
+	// 每个字段的问题都被汇总，而不是在第一个错误处停止
+	keys := make([]string, len(errs))
+	for i, fe := range errs {
+		keys[i] = fe.Key
+	}
+	if want := []string{"seed", "max_steps", "tolerance", "mode", "timeout"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("error keys = %v, want %v (%v)", keys, want, err)
+	}
+	for _, reason := range []string{"must be >= 1", "must be > 0", "must be one of fast, accurate", "must be < 60"} {
+		if !strings.Contains(err.Error(), reason) {
+			t.Errorf("%q does not mention %q", err, reason)
+		}
+	}
+}
+
+func TestBindRequired(t *testing.T) {
+	var c struct {
+		Path string `cfg:"path,required"`
+	}
+	err := Bind(core.NewBaseConfig(map[string]interface{}{}), &c)
+	if err == nil || !strings.Contains(err.Error(), "path: is required") {
+		t.Errorf("Bind without a required key = %v", err)
+	}
+	if err := Bind(core.NewBaseConfig(map[string]interface{}{"path": "data.csv"}), &c); err != nil || c.Path != "data.csv" {
+		t.Errorf("Bind = %v, path %q", err, c.Path)
+	}
+}
+
+func TestBindKeepsValueWithoutDefault(t *testing.T) {
+	c := testConfig{Embedded: Embedded{Seed: 7}}
+	if err := Bind(nil, &c); err != nil {
+		t.Fatal(err)
+	}
+	if c.Seed != 7 {
+		t.Errorf("seed without default = %d, want the preset 7", c.Seed)
+	}
+}
+
+func TestBindInvalidTarget(t *testing.T) {
+	var c testConfig
+	if err := Bind(nil, c); err == nil {
+		t.Error("Bind into a struct value returned no error")
+	}
+	var bad struct {
+		N int `cfg:"n,min"`
+	}
+	if err := Bind(nil, &bad); err == nil {
+		t.Error("Bind with a min rule without a bound returned no error")
+	}
+}
+
+func TestMustDefaultsPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("MustDefaults with an invalid default did not panic")
+		}
+	}()
+	var c struct {
+		N int `cfg:"n,default=many"`
+	}
+	MustDefaults(&c)
+}
+
+func TestValidate(t *testing.T) {
+	c := testConfig{MaxSteps: 10, Tolerance: 1, Mode: "fast", Timeout: time.Second}
+	if err := Validate(c); err != nil {
+		t.Errorf("Validate(valid) = %v", err)
+	}
+	c.Tolerance, c.Mode = 11, "slow"
+	err := Validate(&c)
+	if !errors.Is(err, core.ErrConfigInvalid) || !strings.Contains(err.Error(), "tolerance") || !strings.Contains(err.Error(), "mode") {
+		t.Errorf("Validate(invalid) = %v", err)
+	}
+}
+
+func TestValuesRoundTrip(t *testing.T) {
+	in := testConfig{Embedded: Embedded{Seed: 3}, MaxSteps: 20, Tolerance: 0.5, Mode: "accurate", Timeout: time.Minute / 2, Weights: []float64{4}}
+	values, err := Values(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := values["Ignored"]; ok {
+		t.Error("Values includes a field tagged cfg:\"-\"")
+	}
+	var out testConfig
+	if err := Bind(core.NewBaseConfig(values), &out); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(out, in) {
+		t.Errorf("Bind(Values(%+v)) = %+v", in, out)
+	}
+}
@@ -0,0 +1,145 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 配置值的宽松类型转换：JSON/protobuf中的数字统一为float64，命令行和环境变量中的值均为字符串，
+// 这里将它们统一转换为目标类型，无法无损转换时返回错误而不是静默忽略
+
+// ToInt64 将数值或字符串转换为int64，浮点数必须为整数值
+func ToInt64(raw interface{}) (int64, error) {
+	switch v := raw.(type) {
+	case int:
+		return int64(v), nil
+	case int8:
+		return int64(v), nil
+	case int16:
+		return int64(v), nil
+	case int32:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case uint:
+		return int64(v), nil
+	case uint8:
+		return int64(v), nil
+	case uint16:
+		return int64(v), nil
+	case uint32:
+		return int64(v), nil
+	case uint64:
+		if v > math.MaxInt64 {
+			return 0, fmt.Errorf("value %d overflows int64", v)
+		}
+		return int64(v), nil
+	case float32:
+		return floatToInt(float64(v))
+	case float64:
+		return floatToInt(v)
+	case json.Number:
+		return ToInt64(string(v))
+	case string:
+		s := strings.TrimSpace(v)
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return n, nil
+		}
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return floatToInt(f)
+		}
+		return 0, fmt.Errorf("must be an integer")
+	}
+	return 0, fmt.Errorf("must be an integer, got %T", raw)
+}
+
+func floatToInt(f float64) (int64, error) {
+	if f != math.Trunc(f) || math.IsInf(f, 0) || math.Abs(f) > 1<<53 {
+		return 0, fmt.Errorf("must be an integer")
+	}
+	return int64(f), nil
+}
+
+// ToFloat64 将数值或字符串转换为float64
+func ToFloat64(raw interface{}) (float64, error) {
+	switch v := raw.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case json.Number:
+		return ToFloat64(string(v))
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, fmt.Errorf("must be a number")
+		}
+		return f, nil
+	}
+	if n, err := ToInt64(raw); err == nil {
+		return float64(n), nil
+	}
+	return 0, fmt.Errorf("must be a number, got %T", raw)
+}
+
+// ToBool 将布尔值、字符串（true/false/1/0等）或数值0/1转换为bool
+func ToBool(raw interface{}) (bool, error) {
+	switch v := raw.(type) {
+	case bool:
+		return v, nil
+	case string:
+		b, err := strconv.ParseBool(strings.TrimSpace(v))
+		if err != nil {
+			return false, fmt.Errorf("must be a boolean")
+		}
+		return b, nil
+	}
+	if n, err := ToInt64(raw); err == nil && (n == 0 || n == 1) {
+		return n == 1, nil
+	}
+	return false, fmt.Errorf("must be a boolean, got %T", raw)
+}
+
+// ToString 将字符串或数值转换为string
+func ToString(raw interface{}) (string, error) {
+	switch v := raw.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), nil
+	}
+	if n, err := ToInt64(raw); err == nil {
+		return strconv.FormatInt(n, 10), nil
+	}
+	return "", fmt.Errorf("must be a string, got %T", raw)
+}
+
+// ToDuration 将时长字符串（如 "1.5s"、"200ms"）或以秒为单位的数值转换为time.Duration
+func ToDuration(raw interface{}) (time.Duration, error) {
+	switch v := raw.(type) {
+	case time.Duration:
+		return v, nil
+	case string:
+		s := strings.TrimSpace(v)
+		if d, err := time.ParseDuration(s); err == nil {
+			return d, nil
+		}
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return time.Duration(f * float64(time.Second)), nil
+		}
+		return 0, fmt.Errorf("must be a duration such as \"1.5s\" or a number of seconds")
+	}
+	f, err := ToFloat64(raw)
+	if err != nil {
+		return 0, fmt.Errorf("must be a duration, got %T", raw)
+	}
+	return time.Duration(f * float64(time.Second)), nil
+}
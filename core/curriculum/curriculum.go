@@ -0,0 +1,398 @@
+// Package curriculum 提供课程学习包装器：按声明的阶段在回合之间调整环境的动力学与任务参数
+// （如lunarlander的着陆区宽度、mountaincar的目标位置），阶段按回合数或最近回合的成功率推进，
+// 也可由客户端通过RPC指定。要求环境实现core.Parameterized；改变观察空间的参数（如迷宫尺寸）不在此列。
+package curriculum
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// ConfigKey 创建环境时配置中的该键为Options（JSON/YAML映射）时开启课程学习：
+//
+//	curriculum:
+//	  stages:
+//	    - {landing_pad_width: 1.2, gravity: 1.0}
+//	    - {landing_pad_width: 0.6}
+//	    - {landing_pad_width: 0.3, gravity: 1.6}
+//	  success_info_key: landed
+//	  success_threshold: 0.8
+//	  window: 20
+const ConfigKey = "curriculum"
+
+// InfoKey GetInfo中课程进度（Progress）的键
+const InfoKey = "curriculum"
+
+// DefaultWindow 按成功率推进时默认统计的最近回合数
+const DefaultWindow = 20
+
+// Options 课程的阶段与推进方式。EpisodesPerStage与SuccessThreshold至多设置一个，均未设置时阶段只由SetStage改变
+type Options struct {
+	// Stages 依次使用的参数取值，阶段中未列出的参数沿用上一阶段的值（第一阶段沿用环境创建时的值）
+	Stages []map[string]float64 `json:"stages"`
+	// EpisodesPerStage 按回合数推进：当前阶段完成该数量的回合后进入下一阶段
+	EpisodesPerStage int `json:"episodes_per_stage,omitempty"`
+	// Interpolate 按回合数推进时，阶段内的参数随回合线性过渡到下一阶段的值
+	Interpolate bool `json:"interpolate,omitempty"`
+	// SuccessThreshold 按成功率推进：当前阶段最近Window个回合的成功率达到该值时进入下一阶段
+	SuccessThreshold float64 `json:"success_threshold,omitempty"`
+	// DemoteThreshold 按成功率推进时，统计满Window个回合后成功率低于该值则退回上一阶段，为0时不退回
+	DemoteThreshold float64 `json:"demote_threshold,omitempty"`
+	// Window 成功率统计的最近回合数，为0时使用DefaultWindow
+	Window int `json:"window,omitempty"`
+	// SuccessInfoKey 回合结束时info中该键为true或非零数值即视为成功
+	SuccessInfoKey string `json:"success_info_key,omitempty"`
+	// SuccessReturn 未设置SuccessInfoKey时，回合累计奖励（各智能体之和）不低于该值即视为成功
+	SuccessReturn *float64 `json:"success_return,omitempty"`
+}
+
+// Progress 课程进度，由GetInfo的InfoKey与Curriculum.Progress报告
+type Progress struct {
+	Stage         int                `json:"stage"`          // 当前阶段，从0开始
+	Stages        int                `json:"stages"`         // 阶段总数
+	Episodes      int                `json:"episodes"`       // 已完成的回合总数
+	StageEpisodes int                `json:"stage_episodes"` // 当前阶段已完成的回合数
+	SuccessRate   float64            `json:"success_rate"`   // 当前阶段最近Window个回合的成功率，尚无回合时为0
+	Window        int                `json:"window"`         // 成功率统计的回合数（不超过Window）
+	Frozen        bool               `json:"frozen"`         // 为true时不自动推进
+	Parameters    map[string]float64 `json:"parameters"`     // 当前回合生效的参数
+}
+
+// Curriculum 包装一个环境，每次Reset前设置当前阶段的参数，回合结束时记录结果并推进阶段，自身也实现core.Environment。
+// 只有正常结束的回合计入统计，未结束就被重置的回合不计入
+type Curriculum struct {
+	env    core.Environment
+	opts   Options
+	params core.Parameterized
+	names  []string             // 课程调整的参数，按名称排序
+	stages []map[string]float64 // 补全了沿用值的各阶段参数
+
+	mu            sync.Mutex // 保护以下进度，SetStage与Progress可与步进并发调用
+	stage         int
+	episodes      int
+	stageEpisodes int
+	outcomes      []bool // 当前阶段最近的回合结果，最多Window个
+	frozen        bool
+	applied       map[string]float64
+
+	steps   int
+	returns float64
+}
+
+var (
+	_ core.Environment      = (*Curriculum)(nil)
+	_ core.MetadataProvider = (*Curriculum)(nil)
+	_ core.Unwrapper        = (*Curriculum)(nil)
+)
+
+// New 创建课程学习包装器并立即设置第一阶段的参数；各阶段的取值在创建时逐一校验
+func New(env core.Environment, opts Options) (*Curriculum, error) {
+	if len(opts.Stages) == 0 {
+		return nil, fmt.Errorf("curriculum requires at least one stage")
+	}
+	if opts.EpisodesPerStage < 0 {
+		return nil, fmt.Errorf("episodes_per_stage must be non-negative, got %d", opts.EpisodesPerStage)
+	}
+	if opts.EpisodesPerStage > 0 && opts.SuccessThreshold > 0 {
+		return nil, fmt.Errorf("episodes_per_stage and success_threshold are mutually exclusive")
+	}
+	if opts.Interpolate && opts.EpisodesPerStage == 0 {
+		return nil, fmt.Errorf("interpolate requires episodes_per_stage")
+	}
+	if opts.SuccessThreshold < 0 || opts.SuccessThreshold > 1 {
+		return nil, fmt.Errorf("success_threshold must be in [0, 1], got %v", opts.SuccessThreshold)
+	}
+	if opts.DemoteThreshold < 0 || (opts.DemoteThreshold > 0 && opts.DemoteThreshold >= opts.SuccessThreshold) {
+		return nil, fmt.Errorf("demote_threshold must be in [0, success_threshold), got %v", opts.DemoteThreshold)
+	}
+	if opts.SuccessThreshold > 0 && opts.SuccessInfoKey == "" && opts.SuccessReturn == nil {
+		return nil, fmt.Errorf("success_threshold requires success_info_key or success_return")
+	}
+	if opts.Window < 0 {
+		return nil, fmt.Errorf("window must be non-negative, got %d", opts.Window)
+	}
+	if opts.Window == 0 {
+		opts.Window = DefaultWindow
+	}
+
+	params, ok := core.FindParameterized(env)
+	if !ok {
+		return nil, fmt.Errorf("environment does not support changing parameters (core.Parameterized)")
+	}
+	c := &Curriculum{env: env, opts: opts, params: params}
+
+	// 按阶段补全沿用的值：第一阶段未列出的参数取环境当前值
+	current := params.Parameters()
+	previous := make(map[string]float64)
+	for _, stage := range opts.Stages {
+		for name := range stage {
+			if _, ok := current[name]; !ok {
+				return nil, core.UnknownParameterError(name, params)
+			}
+			if _, ok := previous[name]; !ok {
+				previous[name] = current[name]
+				c.names = append(c.names, name)
+			}
+		}
+	}
+	sort.Strings(c.names)
+	for _, stage := range opts.Stages {
+		values := make(map[string]float64, len(c.names))
+		for _, name := range c.names {
+			values[name] = previous[name]
+			if v, ok := stage[name]; ok {
+				values[name] = v
+			}
+		}
+		c.stages = append(c.stages, values)
+		previous = values
+	}
+
+	// 逐阶段设置一遍以校验取值，最后回到第一阶段
+	for i := len(c.stages) - 1; i >= 0; i-- {
+		if err := c.apply(c.stages[i]); err != nil {
+			return nil, fmt.Errorf("stage %d: %w", i, err)
+		}
+	}
+	return c, nil
+}
+
+// FromConfig 当配置中设置了ConfigKey时返回Curriculum，否则原样返回env
+func FromConfig(env core.Environment, config core.Config) (core.Environment, error) {
+	if config == nil {
+		return env, nil
+	}
+	value := config.GetValue(ConfigKey)
+	if value == nil {
+		return env, nil
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", ConfigKey, err)
+	}
+	var opts Options
+	if err := json.Unmarshal(raw, &opts); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", ConfigKey, err)
+	}
+	return New(env, opts)
+}
+
+// Find 沿Unwrap找到环境的课程学习包装器
+func Find(env core.Environment) (*Curriculum, bool) {
+	for {
+		if c, ok := env.(*Curriculum); ok {
+			return c, true
+		}
+		wrapper, ok := env.(core.Unwrapper)
+		if !ok {
+			return nil, false
+		}
+		env = wrapper.Unwrap()
+	}
+}
+
+// Unwrap 返回被包装的环境
+func (c *Curriculum) Unwrap() core.Environment {
+	return c.env
+}
+
+// apply 设置参数并记录为当前生效的值
+func (c *Curriculum) apply(values map[string]float64) error {
+	for _, name := range c.names {
+		if err := c.params.SetParameter(name, values[name]); err != nil {
+			return err
+		}
+	}
+	c.applied = values
+	return nil
+}
+
+// values 返回当前阶段本回合的参数，开启Interpolate时在当前阶段与下一阶段之间线性过渡
+func (c *Curriculum) values() map[string]float64 {
+	values := c.stages[c.stage]
+	if !c.opts.Interpolate || c.frozen || c.stage+1 >= len(c.stages) {
+		return values
+	}
+	next := c.stages[c.stage+1]
+	t := math.Min(1, float64(c.stageEpisodes)/float64(c.opts.EpisodesPerStage))
+	interpolated := make(map[string]float64, len(values))
+	for name, v := range values {
+		interpolated[name] = v + t*(next[name]-v)
+	}
+	return interpolated
+}
+
+// Reset 设置当前阶段的参数后重置环境
+func (c *Curriculum) Reset(ctx context.Context) ([]core.Observation, error) {
+	c.mu.Lock()
+	err := c.apply(c.values())
+	c.mu.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply curriculum stage: %w", err)
+	}
+	observations, err := c.env.Reset(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.steps, c.returns = 0, 0
+	return observations, nil
+}
+
+func (c *Curriculum) Step(ctx context.Context, actions []core.Action) ([]core.Observation, []float64, []bool, error) {
+	observations, rewards, dones, err := c.env.Step(ctx, actions)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	c.steps++
+	for _, r := range rewards {
+		c.returns += r
+	}
+	if core.AllDone(dones) {
+		c.finish(c.succeeded())
+		c.steps, c.returns = 0, 0
+	}
+	return observations, rewards, dones, nil
+}
+
+// succeeded 判断刚结束的回合是否成功
+func (c *Curriculum) succeeded() bool {
+	if c.opts.SuccessInfoKey != "" {
+		switch v := c.env.GetInfo()[c.opts.SuccessInfoKey].(type) {
+		case bool:
+			return v
+		case nil:
+			return false
+		default:
+			f, err := core.ToFloat64(v)
+			return err == nil && f != 0
+		}
+	}
+	if c.opts.SuccessReturn != nil {
+		return c.returns >= *c.opts.SuccessReturn
+	}
+	return false
+}
+
+// finish 记录回合结果，并按推进方式进入下一阶段或退回上一阶段
+func (c *Curriculum) finish(success bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.episodes++
+	c.stageEpisodes++
+	c.outcomes = append(c.outcomes, success)
+	if len(c.outcomes) > c.opts.Window {
+		c.outcomes = c.outcomes[len(c.outcomes)-c.opts.Window:]
+	}
+	if c.frozen {
+		return
+	}
+	switch {
+	case c.opts.EpisodesPerStage > 0:
+		if c.stageEpisodes >= c.opts.EpisodesPerStage {
+			c.moveTo(c.stage + 1)
+		}
+	case c.opts.SuccessThreshold > 0 && len(c.outcomes) == c.opts.Window:
+		rate := c.successRate()
+		if rate >= c.opts.SuccessThreshold {
+			c.moveTo(c.stage + 1)
+		} else if rate < c.opts.DemoteThreshold {
+			c.moveTo(c.stage - 1)
+		}
+	}
+}
+
+// moveTo 切换到stage（超出范围时保持不变），并清空当前阶段的统计；参数在下次Reset时生效
+func (c *Curriculum) moveTo(stage int) {
+	if stage < 0 || stage >= len(c.stages) || stage == c.stage {
+		return
+	}
+	c.stage = stage
+	c.stageEpisodes = 0
+	c.outcomes = c.outcomes[:0]
+}
+
+// successRate 返回当前阶段最近回合的成功率
+func (c *Curriculum) successRate() float64 {
+	if len(c.outcomes) == 0 {
+		return 0
+	}
+	n := 0
+	for _, ok := range c.outcomes {
+		if ok {
+			n++
+		}
+	}
+	return float64(n) / float64(len(c.outcomes))
+}
+
+// SetStage 切换到指定阶段，frozen为true时停止自动推进；新阶段的参数在下次Reset时生效
+func (c *Curriculum) SetStage(stage int, frozen bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if stage < 0 || stage >= len(c.stages) {
+		return fmt.Errorf("curriculum stage %d out of range [0, %d)", stage, len(c.stages))
+	}
+	c.moveTo(stage)
+	c.frozen = frozen
+	return nil
+}
+
+// Progress 返回当前的课程进度
+func (c *Curriculum) Progress() Progress {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	parameters := make(map[string]float64, len(c.applied))
+	for name, v := range c.applied {
+		parameters[name] = v
+	}
+	return Progress{
+		Stage:         c.stage,
+		Stages:        len(c.stages),
+		Episodes:      c.episodes,
+		StageEpisodes: c.stageEpisodes,
+		SuccessRate:   c.successRate(),
+		Window:        len(c.outcomes),
+		Frozen:        c.frozen,
+		Parameters:    parameters,
+	}
+}
+
+// GetInfo 返回被包装环境的info，并在InfoKey中附带课程进度
+func (c *Curriculum) GetInfo() map[string]interface{} {
+	info := c.env.GetInfo()
+	if info == nil {
+		info = make(map[string]interface{})
+	}
+	progress := c.Progress()
+	parameters := make(map[string]interface{}, len(progress.Parameters))
+	for name, v := range progress.Parameters {
+		parameters[name] = v
+	}
+	info[InfoKey] = map[string]interface{}{
+		"stage":          progress.Stage,
+		"stages":         progress.Stages,
+		"episodes":       progress.Episodes,
+		"stage_episodes": progress.StageEpisodes,
+		"success_rate":   progress.SuccessRate,
+		"window":         progress.Window,
+		"frozen":         progress.Frozen,
+		"parameters":     parameters,
+	}
+	return info
+}
+
+func (c *Curriculum) GetObservations() []core.Observation { return c.env.GetObservations() }
+func (c *Curriculum) GetReward() []float64                { return c.env.GetReward() }
+func (c *Curriculum) GetSpaces() core.SpaceDefinition     { return c.env.GetSpaces() }
+func (c *Curriculum) Close() error                        { return c.env.Close() }
+
+// Metadata 返回被包装环境的元数据
+func (c *Curriculum) Metadata() core.EnvMetadata {
+	return core.GetEnvMetadata(c.env)
+}
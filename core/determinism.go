@@ -0,0 +1,195 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// DeterminismOptions 确定性检查参数
+type DeterminismOptions struct {
+	Steps int   // 录制的总步数，<=0时使用200
+	Seed  int64 // 随机动作的种子
+	Runs  int   // 重新执行的次数，<=0时为1
+}
+
+// Trace 一次执行的完整记录：动作序列以及每次Reset、每步得到的观察、奖励和结束标志。
+// 对同一配置（含场景种子）新建的环境重新执行动作序列，结果应逐位相同
+type Trace struct {
+	Seed  int64 // 生成随机动作的种子，动作由外部给出时为0
+	Steps []TraceStep
+}
+
+// TraceStep Trace中的一次Reset（Actions为nil）或一步仿真
+type TraceStep struct {
+	Reset        bool
+	Actions      []Action
+	Observations [][]float64
+	Rewards      []float64
+	Dones        []bool
+}
+
+// Divergence 重新执行与记录出现的第一处差异
+type Divergence struct {
+	Step  int    // Trace中的下标
+	Field string // observation、reward、done、length或error
+	Agent int    // 智能体下标
+	Index int    // 观察中的分量下标
+	Want  float64
+	Got   float64
+	Err   error // Field为error时重新执行返回的错误
+}
+
+func (d *Divergence) Error() string {
+	switch d.Field {
+	case "error":
+		return fmt.Sprintf("step %d: replay failed: %v", d.Step, d.Err)
+	case "length":
+		return fmt.Sprintf("step %d: recorded %v values, replay returned %v", d.Step, d.Want, d.Got)
+	case "observation":
+		return fmt.Sprintf("step %d: observation %d of agent %d differs: recorded %v (%#016x), replay %v (%#016x)",
+			d.Step, d.Index, d.Agent, d.Want, math.Float64bits(d.Want), d.Got, math.Float64bits(d.Got))
+	}
+	return fmt.Sprintf("step %d: %s of agent %d differs: recorded %v, replay %v", d.Step, d.Field, d.Agent, d.Want, d.Got)
+}
+
+// RecordTrace 用以opts.Seed为种子的随机动作驱动环境opts.Steps步（回合结束后自动Reset），返回完整记录。
+// 不会关闭环境
+func RecordTrace(ctx context.Context, env Environment, opts DeterminismOptions) (*Trace, error) {
+	steps := opts.Steps
+	if steps <= 0 {
+		steps = 200
+	}
+	rng := rand.New(rand.NewSource(opts.Seed))
+	space := env.GetSpaces().ActionSpace
+	trace := &Trace{Seed: opts.Seed}
+
+	observations, err := env.Reset(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reset failed: %w", err)
+	}
+	trace.Steps = append(trace.Steps, TraceStep{Reset: true, Observations: observationData(observations)})
+	for i := 0; i < steps; i++ {
+		actions, err := SampleActions(env, space, len(observations), rng)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sample action: %w", err)
+		}
+		var rewards []float64
+		var dones []bool
+		observations, rewards, dones, err = env.Step(ctx, actions)
+		if err != nil {
+			return nil, fmt.Errorf("step %d failed: %w", i+1, err)
+		}
+		trace.Steps = append(trace.Steps, TraceStep{
+			Actions:      actions,
+			Observations: observationData(observations),
+			Rewards:      append([]float64(nil), rewards...),
+			Dones:        append([]bool(nil), dones...),
+		})
+		if AllDone(dones) && i+1 < steps {
+			if observations, err = env.Reset(ctx); err != nil {
+				return nil, fmt.Errorf("reset after step %d failed: %w", i+1, err)
+			}
+			trace.Steps = append(trace.Steps, TraceStep{Reset: true, Observations: observationData(observations)})
+		}
+	}
+	return trace, nil
+}
+
+// ReplayTrace 在环境上重新执行记录的Reset与动作序列，逐位比较观察、奖励和结束标志。
+// 完全一致时返回nil，否则返回第一处差异。不会关闭环境
+func ReplayTrace(ctx context.Context, env Environment, trace *Trace) *Divergence {
+	for i, recorded := range trace.Steps {
+		var observations []Observation
+		var rewards []float64
+		var dones []bool
+		var err error
+		if recorded.Reset {
+			observations, err = env.Reset(ctx)
+		} else {
+			observations, rewards, dones, err = env.Step(ctx, recorded.Actions)
+		}
+		if err != nil {
+			return &Divergence{Step: i, Field: "error", Err: err}
+		}
+
+		got := observationData(observations)
+		if len(got) != len(recorded.Observations) {
+			return &Divergence{Step: i, Field: "length", Want: float64(len(recorded.Observations)), Got: float64(len(got))}
+		}
+		for agent, want := range recorded.Observations {
+			if len(got[agent]) != len(want) {
+				return &Divergence{Step: i, Field: "length", Agent: agent, Want: float64(len(want)), Got: float64(len(got[agent]))}
+			}
+			for j := range want {
+				if math.Float64bits(got[agent][j]) != math.Float64bits(want[j]) {
+					return &Divergence{Step: i, Field: "observation", Agent: agent, Index: j, Want: want[j], Got: got[agent][j]}
+				}
+			}
+		}
+		if recorded.Reset {
+			continue
+		}
+		if len(rewards) != len(recorded.Rewards) || len(dones) != len(recorded.Dones) {
+			return &Divergence{Step: i, Field: "length", Want: float64(len(recorded.Rewards)), Got: float64(len(rewards))}
+		}
+		for agent, want := range recorded.Rewards {
+			if math.Float64bits(rewards[agent]) != math.Float64bits(want) {
+				return &Divergence{Step: i, Field: "reward", Agent: agent, Want: want, Got: rewards[agent]}
+			}
+		}
+		for agent, want := range recorded.Dones {
+			if dones[agent] != want {
+				return &Divergence{Step: i, Field: "done", Agent: agent, Want: boolFloat(want), Got: boolFloat(dones[agent])}
+			}
+		}
+	}
+	return nil
+}
+
+// CheckDeterminism 用newEnv创建环境录制一次执行，再用新创建的环境重新执行opts.Runs次，
+// 返回录制结果和第一处差异（完全一致时为nil）。newEnv应以相同的配置（含场景种子）创建环境，创建的环境会被关闭
+func CheckDeterminism(ctx context.Context, newEnv func() (Environment, error), opts DeterminismOptions) (*Trace, *Divergence, error) {
+	env, err := newEnv()
+	if err != nil {
+		return nil, nil, err
+	}
+	trace, err := RecordTrace(ctx, env, opts)
+	env.Close()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	runs := opts.Runs
+	if runs <= 0 {
+		runs = 1
+	}
+	for run := 0; run < runs; run++ {
+		env, err := newEnv()
+		if err != nil {
+			return trace, nil, err
+		}
+		divergence := ReplayTrace(ctx, env, trace)
+		env.Close()
+		if divergence != nil {
+			return trace, divergence, nil
+		}
+	}
+	return trace, nil, nil
+}
+
+func observationData(observations []Observation) [][]float64 {
+	data := make([][]float64, len(observations))
+	for i, obs := range observations {
+		data[i] = append([]float64(nil), obs.GetData()...)
+	}
+	return data
+}
+
+func boolFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
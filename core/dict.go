@@ -0,0 +1,113 @@
+package core
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+// DictAction 由命名分量组成的复合动作（如{"throttle": 0.4, "steer": -0.1}），与Gym的spaces.Dict对应。
+// 各分量是Dict动作空间中同名子空间的动作，GetData返回map[string]Action
+type DictAction struct {
+	components map[string]Action
+}
+
+var _ Action = (*DictAction)(nil)
+
+// NewDictAction 创建复合动作
+func NewDictAction(components map[string]Action) *DictAction {
+	return &DictAction{components: components}
+}
+
+// GetData 返回各分量，键为子空间名称
+func (a *DictAction) GetData() interface{} {
+	return a.components
+}
+
+// Validate 验证复合动作至少有一个分量且各分量有效
+func (a *DictAction) Validate() error {
+	if len(a.components) == 0 {
+		return fmt.Errorf("dict action has no components")
+	}
+	for name, component := range a.components {
+		if component == nil {
+			return fmt.Errorf("dict action component %q is nil", name)
+		}
+		if err := component.Validate(); err != nil {
+			return fmt.Errorf("dict action component %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Component 返回名为name的分量，不存在时ok为false
+func (a *DictAction) Component(name string) (Action, bool) {
+	component, ok := a.components[name]
+	return component, ok
+}
+
+// SubspaceNames 返回Dict空间的子空间名称，按字典序排列（与Gym的spaces.Dict相同），
+// 平铺动作值按该顺序依次排列各子空间的值
+func (s ActionSpace) SubspaceNames() []string {
+	names := make([]string, 0, len(s.Subspaces))
+	for name := range s.Subspaces {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DictComponents 将Dict空间的动作转换为各分量，并检查分量名称与子空间一一对应、各分量属于对应的子空间。
+// 接受DictAction，以及数据为map[string]Action或map[string]interface{}的动作
+func (s ActionSpace) DictComponents(action Action) (map[string]Action, error) {
+	if action == nil {
+		return nil, fmt.Errorf("action is nil")
+	}
+	var components map[string]Action
+	switch data := action.GetData().(type) {
+	case map[string]Action:
+		components = data
+	case map[string]interface{}:
+		components = make(map[string]Action, len(data))
+		for name, v := range data {
+			if a, ok := v.(Action); ok {
+				components[name] = a
+			} else {
+				components[name] = NewGenericAction(v)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("dict action must be a map of named components, got %T", data)
+	}
+	for name := range components {
+		if _, ok := s.Subspaces[name]; !ok {
+			return nil, fmt.Errorf("dict action has unknown component %q (expected %v)", name, s.SubspaceNames())
+		}
+	}
+	for _, name := range s.SubspaceNames() {
+		component, ok := components[name]
+		if !ok {
+			return nil, fmt.Errorf("dict action is missing component %q", name)
+		}
+		if err := s.Subspaces[name].Check(component); err != nil {
+			return nil, fmt.Errorf("dict action component %q: %w", name, err)
+		}
+	}
+	return components, nil
+}
+
+// sampleDict 对Dict空间的每个子空间分别采样
+func sampleDict(space ActionSpace, rng *rand.Rand) (Action, error) {
+	if len(space.Subspaces) == 0 {
+		return nil, fmt.Errorf("dict action space has no subspaces")
+	}
+	components := make(map[string]Action, len(space.Subspaces))
+	for _, name := range space.SubspaceNames() {
+		component, err := SampleAction(space.Subspaces[name], rng)
+		if err != nil {
+			return nil, fmt.Errorf("subspace %q: %w", name, err)
+		}
+		components[name] = component
+	}
+	return NewDictAction(components), nil
+}
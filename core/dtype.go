@@ -0,0 +1,50 @@
+package core
+
+import "fmt"
+
+// DtypeKey 创建环境时配置中的该键选择观察数据的存储精度
+const DtypeKey = "dtype"
+
+// 观察数据的存储精度
+const (
+	DtypeFloat64 = "float64" // 默认
+	DtypeFloat32 = "float32" // 观察以[]float32存储，pybridge与gRPC直接传输float32，数据量减半
+)
+
+// Float32Observation 可选实现：以float32存储数据的观察。
+// GetData32返回nil表示数据以float64存储，此时应使用GetData
+type Float32Observation interface {
+	GetData32() []float32
+}
+
+// ParseDtype 读取配置中的DtypeKey，未设置时为DtypeFloat64，其他取值返回错误
+func ParseDtype(config Config) (string, error) {
+	if config == nil {
+		return DtypeFloat64, nil
+	}
+	dtype, ok, err := config.GetString(DtypeKey)
+	if err != nil {
+		return "", err
+	}
+	if !ok || dtype == "" {
+		return DtypeFloat64, nil
+	}
+	if dtype != DtypeFloat64 && dtype != DtypeFloat32 {
+		return "", NewSimulationError(ErrConfigInvalid,
+			fmt.Sprintf("%s: must be %q or %q (got %q)", DtypeKey, DtypeFloat64, DtypeFloat32, dtype), nil)
+	}
+	return dtype, nil
+}
+
+// AppendData32 将观察数据以float32追加到dst，float32存储的观察直接复制，其余逐个转换
+func AppendData32(dst []float32, obs Observation) []float32 {
+	if o, ok := obs.(Float32Observation); ok {
+		if data := o.GetData32(); data != nil {
+			return append(dst, data...)
+		}
+	}
+	for _, v := range obs.GetData() {
+		dst = append(dst, float32(v))
+	}
+	return dst
+}
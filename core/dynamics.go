@@ -0,0 +1,164 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// InfoKeyDynamics GetInfo中本回合从配置范围采样的动力学参数
+const InfoKeyDynamics = "dynamics"
+
+// ParameterRange 创建配置中以{"min": a, "max": b}代替单个数值给出的参数范围，每回合Reset前在[Min, Max]上均匀采样
+type ParameterRange struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
+// parseParameterRange 判断配置值是否为只含min与max两个数值的范围
+func parseParameterRange(key string, value interface{}) (ParameterRange, bool, error) {
+	m, ok := value.(map[string]interface{})
+	if !ok || len(m) != 2 {
+		return ParameterRange{}, false, nil
+	}
+	rawMin, okMin := m["min"]
+	rawMax, okMax := m["max"]
+	if !okMin || !okMax {
+		return ParameterRange{}, false, nil
+	}
+	min, errMin := ToFloat64(rawMin)
+	max, errMax := ToFloat64(rawMax)
+	if errMin != nil || errMax != nil {
+		return ParameterRange{}, true, fmt.Errorf("config key %s: range bounds must be numbers, got %v", key, value)
+	}
+	if !(min <= max) || math.IsInf(min, 0) || math.IsInf(max, 0) {
+		return ParameterRange{}, true, fmt.Errorf("config key %s: range requires finite min <= max, got [%v, %v]", key, min, max)
+	}
+	return ParameterRange{Min: min, Max: max}, true, nil
+}
+
+// splitParameterRanges 分离配置中取值为范围的键，返回以范围下界代替范围的配置（供场景校验与创建环境）与各范围；
+// 没有范围时原样返回config
+func splitParameterRanges(config Config) (Config, map[string]ParameterRange, error) {
+	source, ok := config.(interface{ Values() map[string]interface{} })
+	if !ok {
+		return config, nil, nil
+	}
+	values := source.Values()
+	var ranges map[string]ParameterRange
+	for key, value := range values {
+		r, isRange, err := parseParameterRange(key, value)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !isRange {
+			continue
+		}
+		if ranges == nil {
+			ranges = make(map[string]ParameterRange)
+		}
+		ranges[key] = r
+		values[key] = r.Min
+	}
+	if ranges == nil {
+		return config, nil, nil
+	}
+	return NewBaseConfig(values), ranges, nil
+}
+
+// DynamicsRandomizer 包装一个实现Parameterized的环境，每次Reset前按配置中的范围重新采样参数，
+// 本回合的取值放在GetInfo的InfoKeyDynamics中，便于复现。SimulationEngine在配置含范围时自动创建
+type DynamicsRandomizer struct {
+	env    Environment
+	params Parameterized
+	ranges map[string]ParameterRange
+	names  []string // 按名称排序，保证同一种子下的采样顺序一致
+	rng    *rand.Rand
+	sample map[string]float64
+}
+
+var (
+	_ Environment      = (*DynamicsRandomizer)(nil)
+	_ MetadataProvider = (*DynamicsRandomizer)(nil)
+	_ Unwrapper        = (*DynamicsRandomizer)(nil)
+)
+
+// NewDynamicsRandomizer 创建按ranges采样参数的包装器，seed为0时使用当前时间；范围的两端在创建时校验
+func NewDynamicsRandomizer(env Environment, ranges map[string]ParameterRange, seed int64) (*DynamicsRandomizer, error) {
+	params, ok := FindParameterized(env)
+	if !ok {
+		return nil, fmt.Errorf("parameter ranges require an environment that supports changing parameters (core.Parameterized)")
+	}
+	current := params.Parameters()
+	w := &DynamicsRandomizer{env: env, params: params, ranges: ranges}
+	for name, r := range ranges {
+		if _, ok := current[name]; !ok {
+			return nil, fmt.Errorf("config key %s does not accept a range: %w", name, UnknownParameterError(name, params))
+		}
+		for _, v := range []float64{r.Max, r.Min} {
+			if err := params.SetParameter(name, v); err != nil {
+				return nil, err
+			}
+		}
+		w.names = append(w.names, name)
+	}
+	sort.Strings(w.names)
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	w.rng = rand.New(rand.NewSource(seed))
+	return w, nil
+}
+
+// Unwrap 返回被包装的环境
+func (w *DynamicsRandomizer) Unwrap() Environment {
+	return w.env
+}
+
+// Reset 采样并设置本回合的参数后重置环境
+func (w *DynamicsRandomizer) Reset(ctx context.Context) ([]Observation, error) {
+	sample := make(map[string]float64, len(w.names))
+	for _, name := range w.names {
+		r := w.ranges[name]
+		value := r.Min + w.rng.Float64()*(r.Max-r.Min)
+		if err := w.params.SetParameter(name, value); err != nil {
+			return nil, fmt.Errorf("failed to sample parameter: %w", err)
+		}
+		sample[name] = value
+	}
+	w.sample = sample
+	return w.env.Reset(ctx)
+}
+
+// GetInfo 返回被包装环境的info，并在InfoKeyDynamics中附带本回合采样的参数
+func (w *DynamicsRandomizer) GetInfo() map[string]interface{} {
+	info := w.env.GetInfo()
+	if w.sample == nil {
+		return info
+	}
+	if info == nil {
+		info = make(map[string]interface{})
+	}
+	sample := make(map[string]interface{}, len(w.sample))
+	for name, value := range w.sample {
+		sample[name] = value
+	}
+	info[InfoKeyDynamics] = sample
+	return info
+}
+
+func (w *DynamicsRandomizer) Step(ctx context.Context, actions []Action) ([]Observation, []float64, []bool, error) {
+	return w.env.Step(ctx, actions)
+}
+func (w *DynamicsRandomizer) GetObservations() []Observation { return w.env.GetObservations() }
+func (w *DynamicsRandomizer) GetReward() []float64           { return w.env.GetReward() }
+func (w *DynamicsRandomizer) GetSpaces() SpaceDefinition     { return w.env.GetSpaces() }
+func (w *DynamicsRandomizer) Close() error                   { return w.env.Close() }
+
+// Metadata 返回被包装环境的元数据
+func (w *DynamicsRandomizer) Metadata() EnvMetadata {
+	return GetEnvMetadata(w.env)
+}
@@ -15,6 +15,9 @@ var (
 	ErrScenarioNotFound ErrorCode = fmt.Errorf("scenario not found")
 	ErrDataLoadFailed   ErrorCode = fmt.Errorf("data load failed")
 	ErrStrategyFailed   ErrorCode = fmt.Errorf("strategy execution failed")
+	ErrEnvironmentPanic ErrorCode = fmt.Errorf("environment panicked")
+	ErrEnvironmentFault ErrorCode = fmt.Errorf("environment faulted")
+	ErrInvalidAction    ErrorCode = fmt.Errorf("invalid action") // 动作的个数、类型或取值不被环境接受，属于调用方的错误
 )
 
 // SimulationError 仿真专用错误类型
@@ -31,6 +34,16 @@ func (e *SimulationError) Error() string {
 	return fmt.Sprintf("%s: %s", e.Code.Error(), e.Message)
 }
 
+// Is 使 errors.Is(err, ErrXxx) 可以按错误代码匹配
+func (e *SimulationError) Is(target error) bool {
+	return e.Code != nil && target == e.Code
+}
+
+// Unwrap 返回底层原因
+func (e *SimulationError) Unwrap() error {
+	return e.Cause
+}
+
 func NewSimulationError(code ErrorCode, message string, cause error) *SimulationError {
 	return &SimulationError{
 		Code:    code,
@@ -2,6 +2,7 @@ package core
 
 import (
 	"fmt"
+	"math"
 	"reflect"
 )
 
@@ -15,6 +16,61 @@ func NewGenericAction(data interface{}) *GenericAction {
 	return &GenericAction{data: data}
 }
 
+// ActionValueCount 返回一个智能体的平铺动作值个数：离散空间为1，Dict空间为各子空间之和，
+// Hybrid空间为1（离散动作下标）加上所有动作的参数个数，其余为空间的Size()
+func ActionValueCount(space ActionSpace) int {
+	switch space.Type {
+	case SpaceTypeDiscrete:
+		return 1
+	case SpaceTypeDict:
+		count := 0
+		for _, sub := range space.Subspaces {
+			count += ActionValueCount(sub)
+		}
+		return count
+	case SpaceTypeHybrid:
+		return hybridValueCount(space)
+	}
+	return space.Size()
+}
+
+// NewActionFromValues 按动作空间将一个智能体的平铺动作值转换为Action：
+// 离散空间取整为int，MultiDiscrete空间逐个取整为[]int，MultiBinary空间以0.5为阈值转换为[]bool，
+// 单维连续空间为float64标量，Dict空间按SubspaceNames的顺序切分后逐个转换为DictAction，
+// Hybrid空间取整第一个值为离散动作下标并取出该动作的参数，转换为HybridAction，其余为[]float64
+func NewActionFromValues(space ActionSpace, values []float64) Action {
+	switch {
+	case space.Type == SpaceTypeDict:
+		components := make(map[string]Action, len(space.Subspaces))
+		for _, name := range space.SubspaceNames() {
+			sub := space.Subspaces[name]
+			n := min(ActionValueCount(sub), len(values))
+			components[name] = NewActionFromValues(sub, values[:n])
+			values = values[n:]
+		}
+		return NewDictAction(components)
+	case space.Type == SpaceTypeHybrid:
+		return hybridFromValues(space, values)
+	case space.Type == SpaceTypeDiscrete && len(values) > 0:
+		return NewGenericAction(int(values[0]))
+	case space.Type == SpaceTypeMultiDiscrete:
+		ints := make([]int, len(values))
+		for i, v := range values {
+			ints[i] = int(math.Round(v))
+		}
+		return NewGenericAction(ints)
+	case space.Type == SpaceTypeMultiBinary:
+		bools := make([]bool, len(values))
+		for i, v := range values {
+			bools[i] = v >= 0.5
+		}
+		return NewGenericAction(bools)
+	case space.Type == SpaceTypeBox && len(values) == 1:
+		return NewGenericAction(values[0])
+	}
+	return NewGenericAction(values)
+}
+
 // GetData 获取Action的数据
 func (a *GenericAction) GetData() interface{} {
 	return a.data
@@ -102,7 +158,7 @@ func (a *GenericAction) GetSlice() ([]interface{}, error) {
 	return result, nil
 }
 
-// GetFloat64Slice 尝试将数据转换为[]float64
+// GetFloat64Slice 尝试将数据转换为[]float64，布尔元素转换为0/1
 func (a *GenericAction) GetFloat64Slice() ([]float64, error) {
 	slice, err := a.GetSlice()
 	if err != nil {
@@ -122,9 +178,79 @@ func (a *GenericAction) GetFloat64Slice() ([]float64, error) {
 			result[i] = float64(val)
 		case int32:
 			result[i] = float64(val)
+		case bool:
+			if val {
+				result[i] = 1
+			}
 		default:
 			return nil, fmt.Errorf("cannot convert element %d (%T) to float64", i, val)
 		}
 	}
 	return result, nil
 }
+
+// GetFloat64Values 将数值数组或数值标量转换为[]float64，标量视为长度为1的数组
+func (a *GenericAction) GetFloat64Values() ([]float64, error) {
+	values, err := a.GetFloat64Slice()
+	if err == nil {
+		return values, nil
+	}
+	value, scalarErr := a.GetFloat64()
+	if scalarErr != nil {
+		return nil, err
+	}
+	return []float64{value}, nil
+}
+
+// GetIntSlice 尝试将数据转换为[]int，浮点元素须为整数值
+func (a *GenericAction) GetIntSlice() ([]int, error) {
+	slice, err := a.GetSlice()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]int, len(slice))
+	for i, v := range slice {
+		switch val := v.(type) {
+		case int:
+			result[i] = val
+		case int64:
+			result[i] = int(val)
+		case int32:
+			result[i] = int(val)
+		case float64:
+			if val != math.Trunc(val) {
+				return nil, fmt.Errorf("element %d (%v) is not an integer", i, val)
+			}
+			result[i] = int(val)
+		case float32:
+			if float64(val) != math.Trunc(float64(val)) {
+				return nil, fmt.Errorf("element %d (%v) is not an integer", i, val)
+			}
+			result[i] = int(val)
+		default:
+			return nil, fmt.Errorf("cannot convert element %d (%T) to int", i, val)
+		}
+	}
+	return result, nil
+}
+
+// GetBoolSlice 尝试将数据转换为[]bool，数值元素须为0或1
+func (a *GenericAction) GetBoolSlice() ([]bool, error) {
+	if values, ok := a.data.([]bool); ok {
+		return values, nil
+	}
+	values, err := a.GetFloat64Slice()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]bool, len(values))
+	for i, v := range values {
+		if v != 0 && v != 1 {
+			return nil, fmt.Errorf("element %d (%v) is not 0 or 1", i, v)
+		}
+		result[i] = v == 1
+	}
+	return result, nil
+}
@@ -0,0 +1,127 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync/atomic"
+)
+
+// Guard 隔离环境panic的包装器：被包装环境的方法panic时转换为ErrEnvironmentPanic错误并将环境标记为故障，
+// 之后的Reset与Step返回ErrEnvironmentFault错误，环境只能关闭；不返回错误的方法panic时返回零值。
+// 服务端以它包装每个环境，单个场景的panic不会影响其他环境，也不会使进程退出
+type Guard struct {
+	env     Environment
+	onPanic func(err error, stack []byte)
+	fault   atomic.Pointer[SimulationError]
+}
+
+var (
+	_ Environment      = (*Guard)(nil)
+	_ MetadataProvider = (*Guard)(nil)
+	_ Unwrapper        = (*Guard)(nil)
+)
+
+// NewGuard 创建panic隔离包装器，onPanic非nil时在每次panic后以转换得到的错误和panic时的调用栈调用
+func NewGuard(env Environment, onPanic func(err error, stack []byte)) *Guard {
+	return &Guard{env: env, onPanic: onPanic}
+}
+
+// Unwrap 返回被包装的环境
+func (g *Guard) Unwrap() Environment {
+	return g.env
+}
+
+// Fault 返回使环境进入故障状态的panic错误，未panic过时为nil
+func (g *Guard) Fault() error {
+	if fault := g.fault.Load(); fault != nil {
+		return fault
+	}
+	return nil
+}
+
+// FaultOf 沿Unwrap找到Guard并返回其Fault，环境未被Guard包装时为nil
+func FaultOf(env Environment) error {
+	for {
+		if guard, ok := env.(*Guard); ok {
+			return guard.Fault()
+		}
+		wrapper, ok := env.(Unwrapper)
+		if !ok {
+			return nil
+		}
+		env = wrapper.Unwrap()
+	}
+}
+
+// check 环境已故障时返回ErrEnvironmentFault错误
+func (g *Guard) check() error {
+	if fault := g.fault.Load(); fault != nil {
+		return NewSimulationError(ErrEnvironmentFault, "environment panicked earlier and must be closed", fault)
+	}
+	return nil
+}
+
+// recover 在defer中调用：恢复method中的panic，标记故障，并在err非nil时将其设为转换得到的错误
+func (g *Guard) recover(method string, err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	fault := NewSimulationError(ErrEnvironmentPanic, fmt.Sprintf("%s: %v", method, r), nil)
+	g.fault.CompareAndSwap(nil, fault)
+	if g.onPanic != nil {
+		g.onPanic(fault, debug.Stack())
+	}
+	if err != nil {
+		*err = fault
+	}
+}
+
+func (g *Guard) Reset(ctx context.Context) (observations []Observation, err error) {
+	if err := g.check(); err != nil {
+		return nil, err
+	}
+	defer g.recover("Reset", &err)
+	return g.env.Reset(ctx)
+}
+
+func (g *Guard) Step(ctx context.Context, actions []Action) (observations []Observation, rewards []float64, dones []bool, err error) {
+	if err := g.check(); err != nil {
+		return nil, nil, nil, err
+	}
+	defer g.recover("Step", &err)
+	return g.env.Step(ctx, actions)
+}
+
+func (g *Guard) GetObservations() []Observation {
+	defer g.recover("GetObservations", nil)
+	return g.env.GetObservations()
+}
+
+func (g *Guard) GetReward() []float64 {
+	defer g.recover("GetReward", nil)
+	return g.env.GetReward()
+}
+
+func (g *Guard) GetInfo() map[string]interface{} {
+	defer g.recover("GetInfo", nil)
+	return g.env.GetInfo()
+}
+
+func (g *Guard) GetSpaces() SpaceDefinition {
+	defer g.recover("GetSpaces", nil)
+	return g.env.GetSpaces()
+}
+
+// Metadata 返回被包装环境的元数据
+func (g *Guard) Metadata() EnvMetadata {
+	defer g.recover("Metadata", nil)
+	return GetEnvMetadata(g.env)
+}
+
+// Close 关闭环境，故障状态下同样关闭
+func (g *Guard) Close() (err error) {
+	defer g.recover("Close", &err)
+	return g.env.Close()
+}
@@ -0,0 +1,174 @@
+package core
+
+import (
+	"context"
+	"sync"
+)
+
+// EnvEvent 生命周期钩子收到的事件，与该钩子无关的字段为零值
+type EnvEvent struct {
+	EnvID    string
+	Scenario string
+	// Env 触发事件的环境（不含挂载钩子之后添加的外层包装器）；OnEnvClosed时已关闭
+	Env Environment
+	// Episode 环境创建以来的回合序号，从1开始，每次Reset递增；OnEnvCreated时为0
+	Episode int
+	// Step 回合内的步序号，从1开始；OnEpisodeEnd时为回合步数
+	Step int
+
+	Actions      []Action      // OnStep
+	Observations []Observation // OnReset、OnStep
+	Rewards      []float64     // OnStep
+	Dones        []bool        // OnStep
+	Return       float64       // OnEpisodeEnd：回合累计奖励（所有智能体之和）
+}
+
+// Hooks 环境生命周期钩子的注册表：OnEnvCreated、OnReset、OnStep、OnEpisodeEnd与OnEnvClosed，
+// 嵌入方无需逐个包装环境即可挂载录制、指标或自定义策略。钩子在环境所在的goroutine中同步调用，
+// 应尽快返回；同一事件的钩子按注册顺序调用。零值可用，并发安全
+type Hooks struct {
+	mu         sync.RWMutex
+	envCreated []func(EnvEvent)
+	reset      []func(EnvEvent)
+	step       []func(EnvEvent)
+	episodeEnd []func(EnvEvent)
+	envClosed  []func(EnvEvent)
+}
+
+// OnEnvCreated 注册环境创建后调用的钩子
+func (h *Hooks) OnEnvCreated(fn func(EnvEvent)) {
+	h.add(&h.envCreated, fn)
+}
+
+// OnReset 注册每次Reset成功后调用的钩子
+func (h *Hooks) OnReset(fn func(EnvEvent)) {
+	h.add(&h.reset, fn)
+}
+
+// OnStep 注册每次Step成功后调用的钩子
+func (h *Hooks) OnStep(fn func(EnvEvent)) {
+	h.add(&h.step, fn)
+}
+
+// OnEpisodeEnd 注册所有智能体都结束时（在该步的OnStep之后）调用的钩子；未结束就被重置或关闭的回合不触发
+func (h *Hooks) OnEpisodeEnd(fn func(EnvEvent)) {
+	h.add(&h.episodeEnd, fn)
+}
+
+// OnEnvClosed 注册环境关闭后调用的钩子
+func (h *Hooks) OnEnvClosed(fn func(EnvEvent)) {
+	h.add(&h.envClosed, fn)
+}
+
+func (h *Hooks) add(hooks *[]func(EnvEvent), fn func(EnvEvent)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	*hooks = append(*hooks, fn)
+}
+
+// empty 判断是否未注册任何钩子
+func (h *Hooks) empty() bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.envCreated)+len(h.reset)+len(h.step)+len(h.episodeEnd)+len(h.envClosed) == 0
+}
+
+// fire 按注册顺序调用hooks
+func (h *Hooks) fire(hooks *[]func(EnvEvent), event EnvEvent) {
+	h.mu.RLock()
+	fns := *hooks
+	h.mu.RUnlock()
+	for _, fn := range fns {
+		fn(event)
+	}
+}
+
+// Wrap 挂载钩子：立即触发OnEnvCreated，并返回在Reset、Step、回合结束与Close时触发相应钩子的包装器。
+// 未注册任何钩子时原样返回env，因此钩子应在创建环境之前注册；服务端对其创建的每个环境调用它
+func (h *Hooks) Wrap(env Environment, scenario, envID string) Environment {
+	if h == nil || h.empty() {
+		return env
+	}
+	hooked := &hookedEnv{env: env, hooks: h, scenario: scenario, envID: envID}
+	h.fire(&h.envCreated, hooked.event())
+	return hooked
+}
+
+// hookedEnv 触发生命周期钩子的环境包装器
+type hookedEnv struct {
+	env      Environment
+	hooks    *Hooks
+	scenario string
+	envID    string
+
+	episode int
+	step    int
+	returns float64
+}
+
+var (
+	_ Environment      = (*hookedEnv)(nil)
+	_ MetadataProvider = (*hookedEnv)(nil)
+	_ Unwrapper        = (*hookedEnv)(nil)
+)
+
+// event 返回带有环境、回合与步序号的事件
+func (e *hookedEnv) event() EnvEvent {
+	return EnvEvent{EnvID: e.envID, Scenario: e.scenario, Env: e.env, Episode: e.episode, Step: e.step}
+}
+
+// Unwrap 返回被包装的环境
+func (e *hookedEnv) Unwrap() Environment {
+	return e.env
+}
+
+func (e *hookedEnv) Reset(ctx context.Context) ([]Observation, error) {
+	observations, err := e.env.Reset(ctx)
+	if err != nil {
+		return nil, err
+	}
+	e.episode++
+	e.step, e.returns = 0, 0
+	event := e.event()
+	event.Observations = observations
+	e.hooks.fire(&e.hooks.reset, event)
+	return observations, nil
+}
+
+func (e *hookedEnv) Step(ctx context.Context, actions []Action) ([]Observation, []float64, []bool, error) {
+	observations, rewards, dones, err := e.env.Step(ctx, actions)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	e.step++
+	for _, reward := range rewards {
+		e.returns += reward
+	}
+	event := e.event()
+	event.Actions, event.Observations, event.Rewards, event.Dones = actions, observations, rewards, dones
+	e.hooks.fire(&e.hooks.step, event)
+
+	if AllDone(dones) {
+		event := e.event()
+		event.Return = e.returns
+		e.hooks.fire(&e.hooks.episodeEnd, event)
+	}
+	return observations, rewards, dones, nil
+}
+
+func (e *hookedEnv) GetObservations() []Observation  { return e.env.GetObservations() }
+func (e *hookedEnv) GetReward() []float64            { return e.env.GetReward() }
+func (e *hookedEnv) GetInfo() map[string]interface{} { return e.env.GetInfo() }
+func (e *hookedEnv) GetSpaces() SpaceDefinition      { return e.env.GetSpaces() }
+
+// Metadata 返回被包装环境的元数据
+func (e *hookedEnv) Metadata() EnvMetadata {
+	return GetEnvMetadata(e.env)
+}
+
+// Close 关闭环境并触发OnEnvClosed
+func (e *hookedEnv) Close() error {
+	err := e.env.Close()
+	e.hooks.fire(&e.hooks.envClosed, e.event())
+	return err
+}
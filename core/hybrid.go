@@ -0,0 +1,101 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// HybridAction 参数化动作：选择一个离散动作，并给出该动作的连续参数（如选择引擎并给出推力大小），
+// 对应Hybrid动作空间。GetData返回动作本身
+type HybridAction struct {
+	Choice     int       // 离散动作的下标，在[0, len(space.Parameters))内
+	Parameters []float64 // 所选动作的连续参数，个数为space.Parameters[Choice].Size()
+}
+
+var _ Action = (*HybridAction)(nil)
+
+// NewHybridAction 创建参数化动作
+func NewHybridAction(choice int, parameters []float64) *HybridAction {
+	return &HybridAction{Choice: choice, Parameters: parameters}
+}
+
+// GetData 返回动作本身，使用方可直接取出Choice与Parameters
+func (a *HybridAction) GetData() interface{} {
+	return a
+}
+
+// Validate 验证动作下标非负
+func (a *HybridAction) Validate() error {
+	if a.Choice < 0 {
+		return fmt.Errorf("hybrid action choice must be non-negative, got %d", a.Choice)
+	}
+	return nil
+}
+
+// String 返回"下标(参数...)"形式的文本，如2([0.5])
+func (a *HybridAction) String() string {
+	return fmt.Sprintf("%d(%v)", a.Choice, a.Parameters)
+}
+
+// HybridValue 将Hybrid空间的动作转换为离散动作下标与其连续参数，并检查下标范围、参数个数与参数边界。
+// 接受HybridAction，参数按所选动作的参数空间（Box）检查
+func (s ActionSpace) HybridValue(action Action) (choice int, parameters []float64, err error) {
+	if action == nil {
+		return 0, nil, fmt.Errorf("action is nil")
+	}
+	hybrid, ok := action.GetData().(*HybridAction)
+	if !ok {
+		return 0, nil, fmt.Errorf("hybrid action must be a choice with parameters, got %T", action.GetData())
+	}
+	if hybrid.Choice < 0 || hybrid.Choice >= len(s.Parameters) {
+		return 0, nil, fmt.Errorf("hybrid action choice %d is outside [0, %d)", hybrid.Choice, len(s.Parameters))
+	}
+	if err := s.Parameters[hybrid.Choice].Check(NewGenericAction(hybrid.Parameters)); err != nil {
+		return 0, nil, fmt.Errorf("hybrid action %d parameters: %w", hybrid.Choice, err)
+	}
+	return hybrid.Choice, hybrid.Parameters, nil
+}
+
+// hybridValueCount 返回Hybrid空间的平铺动作值个数：离散动作下标与所有动作的参数依次排列
+func hybridValueCount(space ActionSpace) int {
+	count := 1
+	for _, p := range space.Parameters {
+		count += p.Size()
+	}
+	return count
+}
+
+// hybridFromValues 将平铺动作值[choice, 动作0的参数..., 动作1的参数..., ...]转换为HybridAction，只保留所选动作的参数
+func hybridFromValues(space ActionSpace, values []float64) *HybridAction {
+	if len(values) == 0 {
+		return NewHybridAction(-1, nil)
+	}
+	choice := int(math.Round(values[0]))
+	offset := 1
+	for i, p := range space.Parameters {
+		size := p.Size()
+		if i == choice && offset+size <= len(values) {
+			return NewHybridAction(choice, append([]float64(nil), values[offset:offset+size]...))
+		}
+		offset += size
+	}
+	return NewHybridAction(choice, nil)
+}
+
+// sampleHybrid 均匀采样离散动作，再在其参数空间中采样参数
+func sampleHybrid(space ActionSpace, rng *rand.Rand) (Action, error) {
+	if len(space.Parameters) == 0 {
+		return nil, fmt.Errorf("hybrid action space has no actions")
+	}
+	choice := rng.Intn(len(space.Parameters))
+	sampled, err := SampleAction(space.Parameters[choice], rng)
+	if err != nil {
+		return nil, fmt.Errorf("hybrid action %d parameters: %w", choice, err)
+	}
+	parameters, err := NewGenericAction(sampled.GetData()).GetFloat64Values()
+	if err != nil {
+		return nil, fmt.Errorf("hybrid action %d parameters: %w", choice, err)
+	}
+	return NewHybridAction(choice, parameters), nil
+}
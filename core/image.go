@@ -0,0 +1,58 @@
+package core
+
+// ImageObservation 可选实现：Image观察空间的环境返回的图像观察，像素为行优先HWC排列的uint8数据。
+// 服务端在gRPC的image字段与HTTP的image（base64）中原样返回像素，不再展开为float64数组
+type ImageObservation interface {
+	GetImage() (pixels []uint8, height, width, channels int)
+}
+
+// BaseImageObservation 基础图像观察实现，GetData在首次调用时将像素转换为float64，供只处理数值观察的使用方
+type BaseImageObservation struct {
+	pixels   []uint8
+	height   int
+	width    int
+	channels int
+	data     []float64
+	metadata map[string]interface{}
+}
+
+var (
+	_ Observation      = (*BaseImageObservation)(nil)
+	_ ImageObservation = (*BaseImageObservation)(nil)
+)
+
+// NewImageObservation 创建图像观察，pixels为行优先HWC排列、长度为height*width*channels的像素，
+// 可直接使用FrameRenderer.RenderFrame的结果（channels为3）
+func NewImageObservation(pixels []uint8, height, width, channels int, metadata map[string]interface{}) *BaseImageObservation {
+	if metadata == nil {
+		metadata = make(map[string]interface{})
+	}
+	return &BaseImageObservation{pixels: pixels, height: height, width: width, channels: channels, metadata: metadata}
+}
+
+func (o *BaseImageObservation) GetImage() ([]uint8, int, int, int) {
+	return o.pixels, o.height, o.width, o.channels
+}
+
+func (o *BaseImageObservation) GetData() []float64 {
+	if o.data == nil {
+		o.data = make([]float64, len(o.pixels))
+		for i, v := range o.pixels {
+			o.data[i] = float64(v)
+		}
+	}
+	return o.data
+}
+
+func (o *BaseImageObservation) GetMetadata() map[string]interface{} {
+	return o.metadata
+}
+
+// ObservationImage 返回图像观察的像素与形状，obs未实现ImageObservation时ok为false
+func ObservationImage(obs Observation) (pixels []uint8, height, width, channels int, ok bool) {
+	if o, ok := obs.(ImageObservation); ok {
+		pixels, height, width, channels = o.GetImage()
+		return pixels, height, width, channels, true
+	}
+	return nil, 0, 0, 0, false
+}
@@ -0,0 +1,275 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"go/format"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// InferOptions 空间推断参数
+type InferOptions struct {
+	Episodes int     // 随机动作的回合数，<=0时使用5
+	MaxSteps int     // 每回合的最大步数，<=0时使用1000
+	Seed     int64   // 随机动作的种子
+	Margin   float64 // 在观察到的取值范围两侧各留出的余量，按范围的比例计算（如0.1为10%）
+}
+
+// SpaceInference 空间推断结果
+type SpaceInference struct {
+	EnvType  string // 环境的Go类型（如*simple.SimpleEnvironment），用于生成GetSpaces的接收者
+	Steps    int    // 实际执行的步数
+	Episodes int    // 执行的回合数（含达到MaxSteps而截断的回合）
+
+	Declared    SpaceDefinition  // 环境声明的空间
+	Observation ObservationSpace // 根据实际观察推断出的观察空间
+	Min         []float64        // 观察各分量的最小值
+	Max         []float64        // 观察各分量的最大值
+
+	Mismatches []string // 声明的观察空间与实际数据不一致之处
+}
+
+// Spaces 返回建议的空间定义：动作空间沿用声明，观察空间为推断结果
+func (r *SpaceInference) Spaces() SpaceDefinition {
+	return SpaceDefinition{ActionSpace: r.Declared.ActionSpace, ObservationSpace: r.Observation}
+}
+
+// InferSpaces 用随机动作驱动环境若干回合，统计观察各分量的实际取值范围与长度，推断紧致的观察空间，
+// 并与环境声明的观察空间比较。Text与Image观察沿用声明的空间。推断结束后会关闭环境
+func InferSpaces(ctx context.Context, env Environment, opts InferOptions) (*SpaceInference, error) {
+	episodes := opts.Episodes
+	if episodes <= 0 {
+		episodes = 5
+	}
+	maxSteps := opts.MaxSteps
+	if maxSteps <= 0 {
+		maxSteps = 1000
+	}
+	rng := rand.New(rand.NewSource(opts.Seed))
+	defer env.Close()
+
+	declared := env.GetSpaces()
+	result := &SpaceInference{
+		EnvType:  fmt.Sprintf("%T", env),
+		Declared: declared,
+	}
+	numeric := declared.ObservationSpace.Type != SpaceTypeText && declared.ObservationSpace.Type != SpaceTypeImage
+	minLen, maxLen := -1, 0
+	record := func(observations []Observation) {
+		if !numeric {
+			return
+		}
+		for _, obs := range observations {
+			if obs == nil {
+				continue
+			}
+			data := obs.GetData()
+			if minLen < 0 || len(data) < minLen {
+				minLen = len(data)
+			}
+			if len(data) > maxLen {
+				maxLen = len(data)
+			}
+			for i, v := range data {
+				if math.IsNaN(v) || math.IsInf(v, 0) {
+					continue
+				}
+				if i >= len(result.Min) {
+					result.Min = append(result.Min, v)
+					result.Max = append(result.Max, v)
+					continue
+				}
+				result.Min[i] = math.Min(result.Min[i], v)
+				result.Max[i] = math.Max(result.Max[i], v)
+			}
+		}
+	}
+
+	for result.Episodes < episodes {
+		observations, err := env.Reset(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("reset failed: %w", err)
+		}
+		record(observations)
+		for step := 0; step < maxSteps; step++ {
+			actions, err := SampleActions(env, declared.ActionSpace, len(observations), rng)
+			if err != nil {
+				return nil, fmt.Errorf("failed to sample action: %w", err)
+			}
+			var dones []bool
+			observations, _, dones, err = env.Step(ctx, actions)
+			if err != nil {
+				return nil, fmt.Errorf("step %d failed: %w", result.Steps+1, err)
+			}
+			result.Steps++
+			record(observations)
+			if AllDone(dones) {
+				break
+			}
+		}
+		result.Episodes++
+	}
+
+	result.Observation = declared.ObservationSpace
+	if !numeric || maxLen == 0 {
+		return result, nil
+	}
+	if minLen != maxLen {
+		result.mismatchf("observation length varied between %d and %d", minLen, maxLen)
+	}
+	if size := declared.ObservationSpace.Size(); size != maxLen {
+		result.mismatchf("observations have %d values, declared shape %v expects %d", maxLen, declared.ObservationSpace.Shape, size)
+		result.Observation.Shape = []int32{int32(maxLen)}
+	}
+	if result.Observation.Dtype == "" {
+		result.Observation.Dtype = "float32"
+	}
+	result.Observation.Low = make([]float64, maxLen)
+	result.Observation.High = make([]float64, maxLen)
+	for i := range result.Min {
+		low, high := result.Min[i], result.Max[i]
+		declaredLow := boundAt(declared.ObservationSpace.Low, i, math.Inf(-1))
+		declaredHigh := boundAt(declared.ObservationSpace.High, i, math.Inf(1))
+		if low < declaredLow || high > declaredHigh {
+			result.mismatchf("observation index %d ranged over [%v, %v], outside the declared [%v, %v]",
+				i, low, high, declaredLow, declaredHigh)
+		}
+		pad := opts.Margin * (high - low)
+		if high == low {
+			pad = opts.Margin * math.Max(math.Abs(high), 1)
+		}
+		result.Observation.Low[i] = roundOutward(low-pad, false)
+		result.Observation.High[i] = roundOutward(high+pad, true)
+	}
+	return result, nil
+}
+
+func (r *SpaceInference) mismatchf(format string, args ...interface{}) {
+	r.Mismatches = append(r.Mismatches, fmt.Sprintf(format, args...))
+}
+
+// roundOutward 将v保留3位有效数字，up为true时向上取整，否则向下取整，使取整后的边界仍包含v
+func roundOutward(v float64, up bool) float64 {
+	if v == 0 || math.IsInf(v, 0) || math.IsNaN(v) {
+		return v
+	}
+	scale := math.Pow(10, math.Floor(math.Log10(math.Abs(v)))-2)
+	q := v / scale
+	rounded := math.Round(q)
+	if math.Abs(q-rounded) > 1e-9 { // 已是3位有效数字时不再扩大
+		if up {
+			rounded = math.Ceil(q)
+		} else {
+			rounded = math.Floor(q)
+		}
+	}
+	// 经文本往返去掉乘法引入的尾数误差（如0.30000000000000004）
+	clean, _ := strconv.ParseFloat(strconv.FormatFloat(rounded*scale, 'g', 3, 64), 64)
+	return clean
+}
+
+// GoSource 生成建议的GetSpaces实现（已gofmt格式化），动作空间沿用声明，观察空间为推断结果，
+// 可直接替换场景中的同名方法
+func (r *SpaceInference) GoSource() (string, error) {
+	typeName := strings.TrimPrefix(r.EnvType, "*")
+	typeName = typeName[strings.LastIndex(typeName, ".")+1:]
+	receiver := "e"
+	if typeName != "" {
+		receiver = strings.ToLower(typeName[:1])
+	}
+
+	spaces := r.Spaces()
+	var b strings.Builder
+	fmt.Fprintf(&b, "func (%s *%s) GetSpaces() core.SpaceDefinition {\n", receiver, typeName)
+	b.WriteString("return core.SpaceDefinition{\n")
+	b.WriteString("ActionSpace: ")
+	writeGoActionSpace(&b, spaces.ActionSpace)
+	b.WriteString(",\nObservationSpace: core.ObservationSpace{\n")
+	writeGoFields(&b, spaces.ObservationSpace.Type, spaces.ObservationSpace.Low, spaces.ObservationSpace.High,
+		spaces.ObservationSpace.Shape, spaces.ObservationSpace.Dtype, spaces.ObservationSpace.MaxLength, spaces.ObservationSpace.Charset)
+	b.WriteString("},\n}\n}\n")
+
+	src, err := format.Source([]byte(b.String()))
+	if err != nil {
+		return "", fmt.Errorf("failed to format generated source: %w", err)
+	}
+	return string(src), nil
+}
+
+// writeGoActionSpace 以Go字面量写出动作空间，递归写出Dict的子空间与Hybrid的参数空间
+func writeGoActionSpace(b *strings.Builder, space ActionSpace) {
+	b.WriteString("core.ActionSpace{\n")
+	writeGoFields(b, space.Type, space.Low, space.High, space.Shape, space.Dtype, space.MaxLength, space.Charset)
+	if len(space.DiscreteValues) > 0 {
+		fmt.Fprintf(b, "DiscreteValues: %s,\n", goFloats(space.DiscreteValues))
+	}
+	if len(space.Subspaces) > 0 {
+		b.WriteString("Subspaces: map[string]core.ActionSpace{\n")
+		for _, name := range space.SubspaceNames() {
+			fmt.Fprintf(b, "%q: ", name)
+			writeGoActionSpace(b, space.Subspaces[name])
+			b.WriteString(",\n")
+		}
+		b.WriteString("},\n")
+	}
+	if len(space.Parameters) > 0 {
+		b.WriteString("Parameters: []core.ActionSpace{\n")
+		for _, p := range space.Parameters {
+			writeGoActionSpace(b, p)
+			b.WriteString(",\n")
+		}
+		b.WriteString("},\n")
+	}
+	b.WriteString("}")
+}
+
+// writeGoFields 写出动作空间与观察空间共有的字段，省略零值
+func writeGoFields(b *strings.Builder, t SpaceType, low, high []float64, shape []int32, dtype string, maxLength int, charset string) {
+	switch t {
+	case SpaceTypeBox, SpaceTypeDiscrete, SpaceTypeMultiDiscrete, SpaceTypeMultiBinary,
+		SpaceTypeText, SpaceTypeImage, SpaceTypeDict, SpaceTypeHybrid:
+		fmt.Fprintf(b, "Type: core.SpaceType%s,\n", t)
+	default:
+		fmt.Fprintf(b, "Type: core.SpaceType(%d),\n", int(t))
+	}
+	if len(low) > 0 {
+		fmt.Fprintf(b, "Low: %s,\n", goFloats(low))
+	}
+	if len(high) > 0 {
+		fmt.Fprintf(b, "High: %s,\n", goFloats(high))
+	}
+	if shape != nil {
+		dims := make([]string, len(shape))
+		for i, d := range shape {
+			dims[i] = strconv.Itoa(int(d))
+		}
+		fmt.Fprintf(b, "Shape: []int32{%s},\n", strings.Join(dims, ", "))
+	}
+	if dtype != "" {
+		fmt.Fprintf(b, "Dtype: %q,\n", dtype)
+	}
+	if maxLength != 0 {
+		fmt.Fprintf(b, "MaxLength: %d,\n", maxLength)
+	}
+	if charset != "" {
+		fmt.Fprintf(b, "Charset: %q,\n", charset)
+	}
+}
+
+func goFloats(values []float64) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		switch {
+		case math.IsInf(v, 1):
+			parts[i] = "math.Inf(1)"
+		case math.IsInf(v, -1):
+			parts[i] = "math.Inf(-1)"
+		default:
+			parts[i] = strconv.FormatFloat(v, 'g', -1, 64)
+		}
+	}
+	return "[]float64{" + strings.Join(parts, ", ") + "}"
+}
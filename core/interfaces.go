@@ -1,6 +1,9 @@
 package core
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // Observation 表示环境的观察状态
 type Observation interface {
@@ -58,9 +61,43 @@ type ActionCreator interface {
 	CreateAction(data []float64) (Action, error)
 }
 
+// ActionMasker 接口，可选实现，用于提供当前状态下的合法动作掩码
+// 掩码长度等于离散动作数，true 表示该动作合法
+type ActionMasker interface {
+	GetActionMask() []bool
+}
+
+// TurnBasedEnvironment 接口，可选实现，用于回合制环境：虽然每个智能体都有观察，但每步只有当前行动方提交一个动作
+type TurnBasedEnvironment interface {
+	CurrentPlayer() int
+}
+
+// Renderer 接口，可选实现，用于将环境当前状态渲染为文本（ANSI）
+type Renderer interface {
+	Render() string
+}
+
+// FrameRenderer 接口，可选实现，用于将环境当前状态渲染为RGB图像帧
+// 返回行优先的 height x width x 3 uint8 像素数据，供录制视频/GIF使用
+type FrameRenderer interface {
+	RenderFrame() (frame []uint8, height, width int)
+}
+
+// MetadataProvider 接口，可选实现，用于报告奖励范围、最大步数等环境元数据
+// 未实现时使用DefaultEnvMetadata；RenderModes为nil时根据渲染接口自动推断
+type MetadataProvider interface {
+	Metadata() EnvMetadata
+}
+
 // Config 定义配置接口
+// 类型化的Get方法在键不存在时返回ok=false；键存在但无法转换为目标类型时返回错误
 type Config interface {
 	GetValue(key string) interface{}
+	GetInt(key string) (value int, ok bool, err error)
+	GetFloat64(key string) (value float64, ok bool, err error)
+	GetBool(key string) (value bool, ok bool, err error)
+	GetString(key string) (value string, ok bool, err error)
+	GetDuration(key string) (value time.Duration, ok bool, err error)
 	Validate() error
 	Unmarshal(v interface{}) error
 }
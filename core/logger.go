@@ -0,0 +1,43 @@
+package core
+
+import (
+	"log/slog"
+	"sync/atomic"
+)
+
+// Logger 结构化日志接口，服务端与根包通过它输出运行日志，便于嵌入到使用自有日志系统的应用中。
+// fields为交替出现的键与值（与log/slog相同），*slog.Logger直接实现该接口。实现须保证并发安全
+type Logger interface {
+	Debug(msg string, fields ...interface{})
+	Info(msg string, fields ...interface{})
+	Warn(msg string, fields ...interface{})
+	Error(msg string, fields ...interface{})
+}
+
+// NopLogger 丢弃所有日志
+type NopLogger struct{}
+
+func (NopLogger) Debug(msg string, fields ...interface{}) {}
+func (NopLogger) Info(msg string, fields ...interface{})  {}
+func (NopLogger) Warn(msg string, fields ...interface{})  {}
+func (NopLogger) Error(msg string, fields ...interface{}) {}
+
+// loggerHolder 使atomic.Value总是保存同一具体类型
+type loggerHolder struct {
+	logger Logger
+}
+
+var defaultLogger atomic.Value
+
+// SetDefaultLogger 设置进程内的默认日志，未单独设置日志的服务端使用它；nil恢复为slog.Default()
+func SetDefaultLogger(logger Logger) {
+	defaultLogger.Store(loggerHolder{logger: logger})
+}
+
+// DefaultLogger 返回SetDefaultLogger设置的日志，未设置时为调用时的slog.Default()
+func DefaultLogger() Logger {
+	if holder, ok := defaultLogger.Load().(loggerHolder); ok && holder.logger != nil {
+		return holder.logger
+	}
+	return slog.Default()
+}
@@ -0,0 +1,100 @@
+package core
+
+import (
+	"encoding/json"
+	"math"
+)
+
+// 渲染模式，与Gym的render_modes取值一致
+const (
+	RenderModeANSI     = "ansi"      // Renderer：文本画面
+	RenderModeRGBArray = "rgb_array" // FrameRenderer：RGB图像帧
+)
+
+// EnvMetadata 环境元数据，相当于Gym的env.spec与env.metadata，供客户端自省
+type EnvMetadata struct {
+	RewardRange      [2]float64 `json:"reward_range"`      // 单步奖励的[最小值, 最大值]，无界时为±Inf
+	MaxEpisodeSteps  int        `json:"max_episode_steps"` // 每回合最大步数（截断），0表示不截断
+	RenderModes      []string   `json:"render_modes"`      // 支持的渲染模式
+	Nondeterministic bool       `json:"nondeterministic"`  // 固定种子下转移是否仍可能不同
+}
+
+// DefaultEnvMetadata 返回奖励无界、不截断的默认元数据
+func DefaultEnvMetadata() EnvMetadata {
+	return EnvMetadata{RewardRange: [2]float64{math.Inf(-1), math.Inf(1)}}
+}
+
+// MarshalJSON 将无界的奖励范围编码为null，因为JSON不支持Inf
+func (m EnvMetadata) MarshalJSON() ([]byte, error) {
+	type plain EnvMetadata
+	rewardRange := make([]interface{}, 2)
+	for i, v := range m.RewardRange {
+		if !math.IsInf(v, 0) {
+			rewardRange[i] = v
+		}
+	}
+	return json.Marshal(struct {
+		plain
+		RewardRange []interface{} `json:"reward_range"`
+	}{plain(m), rewardRange})
+}
+
+// GetEnvMetadata 获取环境元数据：优先使用MetadataProvider，未声明的渲染模式根据Renderer/FrameRenderer推断
+func GetEnvMetadata(env Environment) EnvMetadata {
+	metadata := DefaultEnvMetadata()
+	if provider, ok := env.(MetadataProvider); ok {
+		metadata = provider.Metadata()
+	}
+	if metadata.RenderModes == nil {
+		metadata.RenderModes = []string{}
+		if _, ok := env.(Renderer); ok {
+			metadata.RenderModes = append(metadata.RenderModes, RenderModeANSI)
+		}
+		if _, ok := env.(FrameRenderer); ok {
+			metadata.RenderModes = append(metadata.RenderModes, RenderModeRGBArray)
+		}
+	}
+	return metadata
+}
+
+// TruncationTracker 按Gymnasium（≥0.26）的语义将环境的结束标志拆分为terminated与truncated：
+// 回合步数达到MaxEpisodeSteps或info["truncated"]为true时，结束的智能体记为截断，其余记为终止。
+// 不能被多个goroutine并发使用
+type TruncationTracker struct {
+	maxSteps int
+	steps    int
+}
+
+// NewTruncationTracker 创建按env的MaxEpisodeSteps判断截断的跟踪器
+func NewTruncationTracker(env Environment) *TruncationTracker {
+	return &TruncationTracker{maxSteps: GetEnvMetadata(env).MaxEpisodeSteps}
+}
+
+// Reset 在环境Reset后调用，重新开始计数回合步数
+func (t *TruncationTracker) Reset() {
+	t.steps = 0
+}
+
+// Steps 返回当前回合已计数的步数
+func (t *TruncationTracker) Steps() int {
+	return t.steps
+}
+
+// Resume 从steps步继续计数，用于恢复检查点后的环境
+func (t *TruncationTracker) Resume(steps int) {
+	t.steps = steps
+}
+
+// Step 在每步之后调用，返回各智能体的terminated与truncated，两者之或等于dones
+func (t *TruncationTracker) Step(dones []bool, info map[string]interface{}) (terminated, truncated []bool) {
+	t.steps++
+	truncatedByInfo, _ := info["truncated"].(bool)
+	limit := truncatedByInfo || (t.maxSteps > 0 && t.steps >= t.maxSteps)
+	terminated = make([]bool, len(dones))
+	truncated = make([]bool, len(dones))
+	for i, done := range dones {
+		truncated[i] = done && limit
+		terminated[i] = done && !limit
+	}
+	return terminated, truncated
+}
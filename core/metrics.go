@@ -0,0 +1,43 @@
+package core
+
+// MetricsSink 指标输出接口，环境包装器和服务端通过它发布回合统计与运行事件，
+// 实现该接口即可将指标转发到W&B、MLflow、statsd等任意跟踪系统。
+// 方法不返回错误：指标发布失败不应影响仿真，实现应自行记录或丢弃错误，并保证并发安全
+type MetricsSink interface {
+	// Scalar 发布一个标量（如回合累计奖励）
+	Scalar(name string, value float64, tags map[string]string)
+
+	// Histogram 发布一个观测值，由后端聚合为分布（如单步耗时）
+	Histogram(name string, value float64, tags map[string]string)
+
+	// Event 发布一个离散事件（如环境创建、关闭）
+	Event(name string, message string, tags map[string]string)
+}
+
+// NopMetricsSink 丢弃所有指标
+type NopMetricsSink struct{}
+
+func (NopMetricsSink) Scalar(name string, value float64, tags map[string]string)    {}
+func (NopMetricsSink) Histogram(name string, value float64, tags map[string]string) {}
+func (NopMetricsSink) Event(name string, message string, tags map[string]string)    {}
+
+// MultiMetricsSink 将指标同时发布到多个输出
+type MultiMetricsSink []MetricsSink
+
+func (m MultiMetricsSink) Scalar(name string, value float64, tags map[string]string) {
+	for _, sink := range m {
+		sink.Scalar(name, value, tags)
+	}
+}
+
+func (m MultiMetricsSink) Histogram(name string, value float64, tags map[string]string) {
+	for _, sink := range m {
+		sink.Histogram(name, value, tags)
+	}
+}
+
+func (m MultiMetricsSink) Event(name string, message string, tags map[string]string) {
+	for _, sink := range m {
+		sink.Event(name, message, tags)
+	}
+}
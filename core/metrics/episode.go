@@ -0,0 +1,110 @@
+package metrics
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// EpisodeReporter 包装一个环境，每步发布单步耗时，每个回合结束时发布累计奖励与步数。
+// 未结束就被重置或关闭的回合同样会被发布
+type EpisodeReporter struct {
+	env  core.Environment
+	sink core.MetricsSink
+	tags map[string]string
+
+	steps   int
+	returns []float64
+}
+
+var (
+	_ core.Environment      = (*EpisodeReporter)(nil)
+	_ core.MetadataProvider = (*EpisodeReporter)(nil)
+)
+
+// NewEpisodeReporter 创建向sink发布指标的包装器，tags附加到每个指标上（如scenario、env_id）
+func NewEpisodeReporter(env core.Environment, sink core.MetricsSink, tags map[string]string) *EpisodeReporter {
+	return &EpisodeReporter{env: env, sink: sink, tags: tags}
+}
+
+// Unwrap 返回被包装的环境
+func (r *EpisodeReporter) Unwrap() core.Environment {
+	return r.env
+}
+
+func (r *EpisodeReporter) Reset(ctx context.Context) ([]core.Observation, error) {
+	observations, err := r.env.Reset(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.finish()
+	r.returns = make([]float64, len(observations))
+	return observations, nil
+}
+
+func (r *EpisodeReporter) Step(ctx context.Context, actions []core.Action) ([]core.Observation, []float64, []bool, error) {
+	start := time.Now()
+	observations, rewards, dones, err := r.env.Step(ctx, actions)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	r.sink.Histogram(MetricStepDuration, float64(time.Since(start))/float64(time.Millisecond), r.tags)
+
+	r.steps++
+	for i, reward := range rewards {
+		if i >= len(r.returns) {
+			r.returns = append(r.returns, 0)
+		}
+		r.returns[i] += reward
+	}
+	if core.AllDone(dones) {
+		r.finish()
+	}
+	return observations, rewards, dones, nil
+}
+
+// finish 发布当前回合的指标
+func (r *EpisodeReporter) finish() {
+	if r.steps == 0 {
+		return
+	}
+	total := 0.0
+	for i, value := range r.returns {
+		total += value
+		if len(r.returns) > 1 {
+			r.sink.Scalar(MetricEpisodeAgentReturn, value, withTag(r.tags, "agent", strconv.Itoa(i)))
+		}
+	}
+	r.sink.Scalar(MetricEpisodeReturn, total, r.tags)
+	r.sink.Scalar(MetricEpisodeLength, float64(r.steps), r.tags)
+	r.steps = 0
+	r.returns = r.returns[:0]
+}
+
+func (r *EpisodeReporter) GetObservations() []core.Observation { return r.env.GetObservations() }
+func (r *EpisodeReporter) GetReward() []float64                { return r.env.GetReward() }
+func (r *EpisodeReporter) GetInfo() map[string]interface{}     { return r.env.GetInfo() }
+func (r *EpisodeReporter) GetSpaces() core.SpaceDefinition     { return r.env.GetSpaces() }
+
+// Metadata 返回被包装环境的元数据
+func (r *EpisodeReporter) Metadata() core.EnvMetadata {
+	return core.GetEnvMetadata(r.env)
+}
+
+// Close 发布未结束回合的指标并关闭环境
+func (r *EpisodeReporter) Close() error {
+	r.finish()
+	return r.env.Close()
+}
+
+// withTag 返回附加了一个标签的副本
+func withTag(tags map[string]string, key, value string) map[string]string {
+	copied := make(map[string]string, len(tags)+1)
+	for k, v := range tags {
+		copied[k] = v
+	}
+	copied[key] = value
+	return copied
+}
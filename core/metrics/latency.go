@@ -0,0 +1,198 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// 延迟直方图按对数分桶：第i个桶的上界为latencyBase*latencyGrowth^i，覆盖1µs到约3分钟，分位数的相对误差约5%
+const (
+	latencyBase    = time.Microsecond
+	latencyGrowth  = 1.1
+	latencyBuckets = 200
+	// throughputWindow 统计吞吐的滑动窗口（秒）
+	throughputWindow = 60
+)
+
+// LatencySummary 延迟直方图的摘要，耗时以毫秒表示
+type LatencySummary struct {
+	Count     int64   `json:"count"`
+	MeanMs    float64 `json:"mean_ms"`
+	P50Ms     float64 `json:"p50_ms"`
+	P95Ms     float64 `json:"p95_ms"`
+	P99Ms     float64 `json:"p99_ms"`
+	MaxMs     float64 `json:"max_ms"`
+	PerSecond float64 `json:"per_second"` // 最近一分钟内每秒的平均次数
+}
+
+// LatencyHistogram 并发安全的延迟直方图，按对数分桶估计分位数，并统计最近一分钟的吞吐
+type LatencyHistogram struct {
+	mu      sync.Mutex
+	buckets [latencyBuckets]int64
+	count   int64
+	sum     time.Duration
+	max     time.Duration
+	started time.Time
+	// 按秒计数的环形窗口，seconds[i]为counts[i]所属的Unix秒
+	counts  [throughputWindow]int64
+	seconds [throughputWindow]int64
+}
+
+// NewLatencyHistogram 创建空的延迟直方图
+func NewLatencyHistogram() *LatencyHistogram {
+	return &LatencyHistogram{started: time.Now()}
+}
+
+// Observe 记录一次耗时
+func (h *LatencyHistogram) Observe(d time.Duration) {
+	now := time.Now().Unix()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.buckets[latencyBucket(d)]++
+	h.count++
+	h.sum += d
+	if d > h.max {
+		h.max = d
+	}
+	slot := now % throughputWindow
+	if h.seconds[slot] != now {
+		h.seconds[slot], h.counts[slot] = now, 0
+	}
+	h.counts[slot]++
+}
+
+// Summary 返回当前的摘要
+func (h *LatencyHistogram) Summary() LatencySummary {
+	now := time.Now()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	summary := LatencySummary{Count: h.count}
+	if h.count == 0 {
+		return summary
+	}
+	summary.MeanMs = milliseconds(h.sum) / float64(h.count)
+	summary.MaxMs = milliseconds(h.max)
+	summary.P50Ms = h.quantile(0.5)
+	summary.P95Ms = h.quantile(0.95)
+	summary.P99Ms = h.quantile(0.99)
+
+	var recent int64
+	for i, second := range h.seconds {
+		if now.Unix()-second < throughputWindow {
+			recent += h.counts[i]
+		}
+	}
+	// 运行不足一分钟时按实际时长计算
+	window := math.Max(math.Min(now.Sub(h.started).Seconds(), throughputWindow), 1)
+	summary.PerSecond = float64(recent) / window
+	return summary
+}
+
+// quantile 返回第q分位数所在桶的上界（不超过最大值），调用方需持有mu
+func (h *LatencyHistogram) quantile(q float64) float64 {
+	rank := int64(math.Ceil(q * float64(h.count)))
+	var seen int64
+	for i, n := range h.buckets {
+		seen += n
+		if seen >= rank {
+			return math.Min(milliseconds(latencyBase)*math.Pow(latencyGrowth, float64(i)), milliseconds(h.max))
+		}
+	}
+	return milliseconds(h.max)
+}
+
+// latencyBucket 返回d所在的桶
+func latencyBucket(d time.Duration) int {
+	if d <= latencyBase {
+		return 0
+	}
+	i := int(math.Ceil(math.Log(float64(d)/float64(latencyBase)) / math.Log(latencyGrowth)))
+	return min(i, latencyBuckets-1)
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// LatencyStats 按名称（如场景名）分组的延迟直方图，并发安全
+type LatencyStats struct {
+	mu         sync.RWMutex
+	histograms map[string]*LatencyHistogram
+}
+
+// NewLatencyStats 创建空的分组延迟统计
+func NewLatencyStats() *LatencyStats {
+	return &LatencyStats{histograms: make(map[string]*LatencyHistogram)}
+}
+
+// Observe 将一次耗时记录到name的直方图
+func (s *LatencyStats) Observe(name string, d time.Duration) {
+	s.mu.RLock()
+	h, ok := s.histograms[name]
+	s.mu.RUnlock()
+	if !ok {
+		s.mu.Lock()
+		if h, ok = s.histograms[name]; !ok {
+			h = NewLatencyHistogram()
+			s.histograms[name] = h
+		}
+		s.mu.Unlock()
+	}
+	h.Observe(d)
+}
+
+// Names 按字典序返回已记录过的名称
+func (s *LatencyStats) Names() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	names := make([]string, 0, len(s.histograms))
+	for name := range s.histograms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Summary 返回name的摘要，未记录过时Count为0
+func (s *LatencyStats) Summary(name string) LatencySummary {
+	s.mu.RLock()
+	h, ok := s.histograms[name]
+	s.mu.RUnlock()
+	if !ok {
+		return LatencySummary{}
+	}
+	return h.Summary()
+}
+
+// StepLatency 一个传输层按场景统计的步进延迟：Env为环境Step本身的耗时，即场景的固有开销；
+// Request为传输层处理一次步进请求的耗时（HTTP与gRPC为解码动作、步进与编码结果，不含网络传输），
+// 两者之差即该传输层在服务端的开销
+type StepLatency struct {
+	Env     *LatencyStats
+	Request *LatencyStats
+}
+
+// ScenarioLatency 一个场景的步进延迟摘要
+type ScenarioLatency struct {
+	Step    LatencySummary `json:"step"`
+	Request LatencySummary `json:"request"`
+}
+
+// NewStepLatency 创建空的步进延迟统计
+func NewStepLatency() *StepLatency {
+	return &StepLatency{Env: NewLatencyStats(), Request: NewLatencyStats()}
+}
+
+// Summaries 返回各场景的摘要，visible非nil时只返回它接受的场景
+func (l *StepLatency) Summaries(visible func(scenario string) bool) map[string]ScenarioLatency {
+	summaries := make(map[string]ScenarioLatency)
+	for _, name := range l.Env.Names() {
+		if visible != nil && !visible(name) {
+			continue
+		}
+		summaries[name] = ScenarioLatency{Step: l.Env.Summary(name), Request: l.Request.Summary(name)}
+	}
+	return summaries
+}
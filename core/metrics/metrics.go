@@ -0,0 +1,136 @@
+// Package metrics 提供core.MetricsSink的内置实现（标准输出、statsd）以及按回合发布指标的环境包装器。
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// 环境包装器与服务端发布的指标名
+const (
+	MetricEpisodeReturn      = "episode.return"       // 回合累计奖励（所有智能体之和）
+	MetricEpisodeAgentReturn = "episode.agent_return" // 多智能体环境中各智能体的累计奖励，带agent标签
+	MetricEpisodeLength      = "episode.length"       // 回合步数
+	MetricStepDuration       = "step.duration_ms"     // 单步耗时（毫秒），Histogram
+	MetricEpisodeSuccess     = "episode.success"      // 回合是否成功（1或0），评估时发布
+	MetricEvalMeanReturn     = "eval.mean_return"     // 评估的平均回合奖励
+	MetricEvalReturnLow      = "eval.return_ci_low"   // 平均回合奖励置信区间的下界
+	MetricEvalReturnHigh     = "eval.return_ci_high"  // 平均回合奖励置信区间的上界
+	MetricEvalSuccessRate    = "eval.success_rate"    // 评估的成功率
+	EventEnvCreated          = "env.created"
+	EventEnvClosed           = "env.closed"
+)
+
+// Stdout 将指标逐行写入io.Writer，格式为：时间 类型 名称=值 标签...
+type Stdout struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+var _ core.MetricsSink = (*Stdout)(nil)
+
+// NewStdout 创建写入w的指标输出，w为nil时写入标准输出
+func NewStdout(w io.Writer) *Stdout {
+	if w == nil {
+		w = os.Stdout
+	}
+	return &Stdout{w: w}
+}
+
+func (s *Stdout) Scalar(name string, value float64, tags map[string]string) {
+	s.write("scalar", fmt.Sprintf("%s=%g", name, value), tags)
+}
+
+func (s *Stdout) Histogram(name string, value float64, tags map[string]string) {
+	s.write("histogram", fmt.Sprintf("%s=%g", name, value), tags)
+}
+
+func (s *Stdout) Event(name string, message string, tags map[string]string) {
+	s.write("event", fmt.Sprintf("%s=%q", name, message), tags)
+}
+
+func (s *Stdout) write(kind, metric string, tags map[string]string) {
+	var b strings.Builder
+	b.WriteString(time.Now().Format(time.RFC3339))
+	b.WriteByte(' ')
+	b.WriteString(kind)
+	b.WriteByte(' ')
+	b.WriteString(metric)
+	for _, tag := range formatTags(tags) {
+		b.WriteByte(' ')
+		b.WriteString(tag)
+	}
+	b.WriteByte('\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	io.WriteString(s.w, b.String())
+}
+
+// formatTags 将标签按键排序并格式化为key:value
+func formatTags(tags map[string]string) []string {
+	formatted := make([]string, 0, len(tags))
+	for key, value := range tags {
+		formatted = append(formatted, key+":"+value)
+	}
+	sort.Strings(formatted)
+	return formatted
+}
+
+// Open 按描述创建指标输出，多个描述以逗号分隔时同时输出到所有目标：
+//
+//	stdout                              标准输出
+//	statsd://host:port[?prefix=rlenv]   statsd（UDP，DogStatsD标签扩展）
+//
+// 返回的close用于释放连接
+func Open(spec string) (core.MetricsSink, func() error, error) {
+	var sinks core.MultiMetricsSink
+	var closers []io.Closer
+	closeAll := func() error {
+		var firstErr error
+		for _, c := range closers {
+			if err := c.Close(); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+		return firstErr
+	}
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		switch {
+		case part == "":
+			continue
+		case part == "stdout":
+			sinks = append(sinks, NewStdout(os.Stdout))
+		case strings.HasPrefix(part, "statsd://"):
+			u, err := url.Parse(part)
+			if err != nil {
+				closeAll()
+				return nil, nil, fmt.Errorf("invalid statsd address %q: %w", part, err)
+			}
+			statsd, err := NewStatsd(u.Host, u.Query().Get("prefix"))
+			if err != nil {
+				closeAll()
+				return nil, nil, err
+			}
+			sinks = append(sinks, statsd)
+			closers = append(closers, statsd)
+		default:
+			closeAll()
+			return nil, nil, fmt.Errorf("unknown metrics sink %q (expected stdout or statsd://host:port)", part)
+		}
+	}
+	if len(sinks) == 1 {
+		return sinks[0], closeAll, nil
+	}
+	return sinks, closeAll, nil
+}
@@ -0,0 +1,180 @@
+package metrics
+
+import (
+	"math"
+	"sync"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// DefaultEpisodeStatsWindow EpisodeStats默认保留的最近回合数
+const DefaultEpisodeStatsWindow = 100
+
+// EpisodeStats 汇总EpisodeReporter所发布回合指标的MetricsSink：按env_id与scenario标签分别统计
+// 回合累计奖励与长度的次数、均值、最小值、最大值及最近window个回合，供仪表盘直接读取，无需从逐步数据还原回合。
+// 其余指标与事件被忽略；并发安全
+type EpisodeStats struct {
+	mu        sync.Mutex
+	window    int
+	envs      map[string]*episodeSeries
+	scenarios map[string]*episodeSeries
+}
+
+var _ core.MetricsSink = (*EpisodeStats)(nil)
+
+// SeriesSummary 一个回合指标（累计奖励或长度）的汇总
+type SeriesSummary struct {
+	Mean       float64   `json:"mean"`
+	Min        float64   `json:"min"`
+	Max        float64   `json:"max"`
+	RecentMean float64   `json:"recent_mean"` // 最近window个回合的均值
+	Recent     []float64 `json:"recent"`      // 最近window个回合的值，从旧到新
+}
+
+// EpisodeSummary 一个环境或场景的回合统计
+type EpisodeSummary struct {
+	Scenario string        `json:"scenario,omitempty"` // 环境所属的场景，场景的汇总中为空
+	Episodes int64         `json:"episodes"`
+	Returns  SeriesSummary `json:"returns"`
+	Lengths  SeriesSummary `json:"lengths"`
+}
+
+// episodeSeries 一个环境或场景的回合累计奖励与长度
+type episodeSeries struct {
+	scenario string
+	returns  rollingStats
+	lengths  rollingStats
+}
+
+// rollingStats 全部值的次数、总和与极值，以及最近window个值的环形缓冲区
+type rollingStats struct {
+	count    int64
+	sum      float64
+	min, max float64
+	recent   []float64
+	next     int // recent写满后下一个被覆盖的位置
+}
+
+// NewEpisodeStats 创建保留最近window个回合的统计，window<=0时为DefaultEpisodeStatsWindow
+func NewEpisodeStats(window int) *EpisodeStats {
+	if window <= 0 {
+		window = DefaultEpisodeStatsWindow
+	}
+	return &EpisodeStats{
+		window:    window,
+		envs:      make(map[string]*episodeSeries),
+		scenarios: make(map[string]*episodeSeries),
+	}
+}
+
+// Window 返回保留的最近回合数
+func (s *EpisodeStats) Window() int {
+	return s.window
+}
+
+// Scalar 记录MetricEpisodeReturn与MetricEpisodeLength，按tags中的env_id与scenario归类
+func (s *EpisodeStats) Scalar(name string, value float64, tags map[string]string) {
+	if name != MetricEpisodeReturn && name != MetricEpisodeLength {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	scenario := tags["scenario"]
+	if envID := tags["env_id"]; envID != "" {
+		s.series(s.envs, envID, scenario).add(name, value, s.window)
+	}
+	if scenario != "" {
+		s.series(s.scenarios, scenario, "").add(name, value, s.window)
+	}
+}
+
+func (s *EpisodeStats) Histogram(name string, value float64, tags map[string]string) {}
+func (s *EpisodeStats) Event(name string, message string, tags map[string]string)    {}
+
+// Forget 移除envID的统计（如环境关闭后），场景的汇总不受影响
+func (s *EpisodeStats) Forget(envID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.envs, envID)
+}
+
+// Environments 返回以env_id为键的各环境的统计
+func (s *EpisodeStats) Environments() map[string]EpisodeSummary {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return summarize(s.envs)
+}
+
+// Scenarios 返回以场景名为键的各场景的统计，包含已关闭的环境
+func (s *EpisodeStats) Scenarios() map[string]EpisodeSummary {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return summarize(s.scenarios)
+}
+
+// series 返回key的序列，不存在时创建，调用方需持有mu
+func (s *EpisodeStats) series(m map[string]*episodeSeries, key, scenario string) *episodeSeries {
+	series, ok := m[key]
+	if !ok {
+		series = &episodeSeries{scenario: scenario}
+		m[key] = series
+	}
+	return series
+}
+
+func (e *episodeSeries) add(name string, value float64, window int) {
+	if name == MetricEpisodeReturn {
+		e.returns.add(value, window)
+	} else {
+		e.lengths.add(value, window)
+	}
+}
+
+func summarize(m map[string]*episodeSeries) map[string]EpisodeSummary {
+	summaries := make(map[string]EpisodeSummary, len(m))
+	for key, series := range m {
+		summaries[key] = EpisodeSummary{
+			Scenario: series.scenario,
+			Episodes: series.returns.count,
+			Returns:  series.returns.summary(),
+			Lengths:  series.lengths.summary(),
+		}
+	}
+	return summaries
+}
+
+func (r *rollingStats) add(value float64, window int) {
+	if r.count == 0 || value < r.min {
+		r.min = value
+	}
+	if r.count == 0 || value > r.max {
+		r.max = value
+	}
+	r.count++
+	r.sum += value
+	if len(r.recent) < window {
+		r.recent = append(r.recent, value)
+		return
+	}
+	r.recent[r.next] = value
+	r.next = (r.next + 1) % window
+}
+
+func (r *rollingStats) summary() SeriesSummary {
+	if r.count == 0 {
+		return SeriesSummary{Recent: []float64{}}
+	}
+	recent := make([]float64, 0, len(r.recent))
+	recent = append(append(recent, r.recent[r.next:]...), r.recent[:r.next]...)
+	recentSum := 0.0
+	for _, v := range recent {
+		recentSum += v
+	}
+	return SeriesSummary{
+		Mean:       r.sum / float64(r.count),
+		Min:        r.min,
+		Max:        r.max,
+		RecentMean: recentSum / math.Max(float64(len(recent)), 1),
+		Recent:     recent,
+	}
+}
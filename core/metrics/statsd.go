@@ -0,0 +1,70 @@
+package metrics
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// Statsd 通过UDP向statsd发送指标：Scalar为gauge，Histogram为histogram，Event为DogStatsD事件，
+// 标签使用DogStatsD的|#key:value扩展。发送失败的指标直接丢弃
+type Statsd struct {
+	mu     sync.Mutex
+	conn   net.Conn
+	prefix string
+}
+
+var _ core.MetricsSink = (*Statsd)(nil)
+
+// NewStatsd 连接addr（host:port）上的statsd，prefix非空时作为所有指标名的前缀
+func NewStatsd(addr, prefix string) (*Statsd, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to statsd at %s: %w", addr, err)
+	}
+	if prefix != "" && !strings.HasSuffix(prefix, ".") {
+		prefix += "."
+	}
+	return &Statsd{conn: conn, prefix: prefix}, nil
+}
+
+func (s *Statsd) Scalar(name string, value float64, tags map[string]string) {
+	s.send(s.prefix+name+":"+strconv.FormatFloat(value, 'g', -1, 64)+"|g", tags)
+}
+
+func (s *Statsd) Histogram(name string, value float64, tags map[string]string) {
+	s.send(s.prefix+name+":"+strconv.FormatFloat(value, 'g', -1, 64)+"|h", tags)
+}
+
+func (s *Statsd) Event(name string, message string, tags map[string]string) {
+	title := s.prefix + name
+	message = strings.ReplaceAll(message, "\n", "\\n")
+	s.send(fmt.Sprintf("_e{%d,%d}:%s|%s", len(title), len(message), title, message), tags)
+}
+
+func (s *Statsd) send(line string, tags map[string]string) {
+	if formatted := formatTags(tags); len(formatted) > 0 {
+		line += "|#" + strings.Join(formatted, ",")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn != nil {
+		s.conn.Write([]byte(line))
+	}
+}
+
+// Close 关闭连接
+func (s *Statsd) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
@@ -0,0 +1,116 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// PoolResult StepPool中一个环境Reset或单步的结果，出错时Result为nil。
+// 步进池不跟踪回合步数，结束的智能体只在info["truncated"]为true时记为截断，其余记为终止
+type PoolResult struct {
+	Result *StepResult
+	Err    error
+}
+
+// StepPool 并发地对一组互相独立的环境执行Reset或Step，同时运行的goroutine数不超过Workers。
+// 单个环境panic时只有该环境的结果带有ErrEnvironmentPanic错误，其余环境不受影响；
+// panic后的环境状态不可信，调用方应将其关闭并重新创建。
+// StepPool本身无状态，可被多个goroutine共用，但同一个环境不能同时出现在两次调用中
+type StepPool struct {
+	workers int
+}
+
+// NewStepPool 创建最多使用workers个goroutine的步进池，workers<=0时使用runtime.GOMAXPROCS(0)
+func NewStepPool(workers int) *StepPool {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	return &StepPool{workers: workers}
+}
+
+// Workers 返回最大并发数
+func (p *StepPool) Workers() int {
+	return p.workers
+}
+
+// Reset 并发重置所有环境，结果与envs一一对应
+func (p *StepPool) Reset(ctx context.Context, envs []Environment) []PoolResult {
+	results := make([]PoolResult, len(envs))
+	p.run(ctx, len(envs), func(i int) {
+		observations, err := envs[i].Reset(ctx)
+		if err != nil {
+			results[i].Err = err
+			return
+		}
+		results[i].Result, results[i].Err = NewStepResult(AgentNames(envs[i], len(observations)), observations, nil, nil, nil)
+	}, results)
+	return results
+}
+
+// Step 并发地对envs[i]执行actions[i]，结果与envs一一对应。actions与envs长度不同时返回错误
+func (p *StepPool) Step(ctx context.Context, envs []Environment, actions [][]Action) ([]PoolResult, error) {
+	if len(actions) != len(envs) {
+		return nil, NewSimulationError(ErrInvalidParameter,
+			fmt.Sprintf("got actions for %d environments, expected %d", len(actions), len(envs)), nil)
+	}
+	results := make([]PoolResult, len(envs))
+	p.run(ctx, len(envs), func(i int) {
+		observations, rewards, dones, err := envs[i].Step(ctx, actions[i])
+		if err != nil {
+			results[i].Err = err
+			return
+		}
+		terminated, truncated := (&TruncationTracker{}).Step(dones, envs[i].GetInfo())
+		names := AgentNames(envs[i], len(observations))
+		results[i].Result, results[i].Err = NewStepResult(names, observations, rewards, terminated, truncated)
+	}, results)
+	return results, nil
+}
+
+// run 用不超过p.workers个goroutine对0..n-1执行fn，只有一个工作者时直接在当前goroutine中执行。
+// ctx取消后尚未开始的环境以ctx.Err()作为结果
+func (p *StepPool) run(ctx context.Context, n int, fn func(i int), results []PoolResult) {
+	call := func(i int) {
+		defer func() {
+			if r := recover(); r != nil {
+				results[i] = PoolResult{Err: NewSimulationError(ErrEnvironmentPanic, fmt.Sprintf("environment %d: %v", i, r), nil)}
+			}
+		}()
+		if err := ctx.Err(); err != nil {
+			results[i].Err = err
+			return
+		}
+		fn(i)
+	}
+
+	workers := p.workers
+	if workers > n {
+		workers = n
+	}
+	if workers <= 1 {
+		for i := 0; i < n; i++ {
+			call(i)
+		}
+		return
+	}
+
+	var next int64 = -1
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(atomic.AddInt64(&next, 1))
+				if i >= n {
+					return
+				}
+				call(i)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,48 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Parameterized 可选实现：在运行期间读取与修改环境的动力学参数，参数名与创建配置中的同名键一致。
+// 供域随机化、课程学习等包装器在回合之间调整参数，修改立即生效，通常在Reset之前调用
+type Parameterized interface {
+	// Parameters 返回可修改的参数及其当前值
+	Parameters() map[string]float64
+	// SetParameter 修改参数，参数不存在或取值无效时返回错误
+	SetParameter(name string, value float64) error
+}
+
+// FindParameterized 沿Unwrap找到实现Parameterized的环境
+func FindParameterized(env Environment) (Parameterized, bool) {
+	for {
+		if p, ok := env.(Parameterized); ok {
+			return p, true
+		}
+		wrapper, ok := env.(Unwrapper)
+		if !ok {
+			return nil, false
+		}
+		env = wrapper.Unwrap()
+	}
+}
+
+// UnknownParameterError 返回参数不存在的错误，列出可修改的参数
+func UnknownParameterError(name string, p Parameterized) error {
+	names := make([]string, 0)
+	for n := range p.Parameters() {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return fmt.Errorf("unknown parameter %q (available: %v)", name, names)
+}
+
+// PositiveParameter 检查参数取值为有限的正数
+func PositiveParameter(name string, value float64) error {
+	if !(value > 0) || math.IsInf(value, 0) {
+		return fmt.Errorf("parameter %s must be a positive finite number, got %v", name, value)
+	}
+	return nil
+}
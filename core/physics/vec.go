@@ -0,0 +1,61 @@
+// Package physics 提供一个无外部依赖的小型二维物理引擎：
+// 基于Verlet积分的质点、刚性连杆约束、带电机与角度限制的关节，以及带摩擦的水平地面，
+// 用于构建行走、跳跃等连续控制场景
+package physics
+
+import "math"
+
+// Vec2 二维向量
+type Vec2 struct {
+	X, Y float64
+}
+
+// V 创建二维向量
+func V(x, y float64) Vec2 {
+	return Vec2{X: x, Y: y}
+}
+
+// Add 向量加法
+func (v Vec2) Add(o Vec2) Vec2 {
+	return Vec2{v.X + o.X, v.Y + o.Y}
+}
+
+// Sub 向量减法
+func (v Vec2) Sub(o Vec2) Vec2 {
+	return Vec2{v.X - o.X, v.Y - o.Y}
+}
+
+// Scale 数乘
+func (v Vec2) Scale(s float64) Vec2 {
+	return Vec2{v.X * s, v.Y * s}
+}
+
+// Dot 点积
+func (v Vec2) Dot(o Vec2) float64 {
+	return v.X*o.X + v.Y*o.Y
+}
+
+// Cross 二维叉积（z分量）
+func (v Vec2) Cross(o Vec2) float64 {
+	return v.X*o.Y - v.Y*o.X
+}
+
+// Len 向量长度
+func (v Vec2) Len() float64 {
+	return math.Hypot(v.X, v.Y)
+}
+
+// Perp 逆时针旋转90度
+func (v Vec2) Perp() Vec2 {
+	return Vec2{-v.Y, v.X}
+}
+
+// Angle 向量相对x轴的角度
+func (v Vec2) Angle() float64 {
+	return math.Atan2(v.Y, v.X)
+}
+
+// AngleBetween 返回从a到b的有符号夹角，范围 (-π, π]，逆时针为正
+func AngleBetween(a, b Vec2) float64 {
+	return math.Atan2(a.Cross(b), a.Dot(b))
+}
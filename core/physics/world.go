@@ -0,0 +1,249 @@
+package physics
+
+import (
+	"fmt"
+	"math"
+)
+
+// Particle Verlet质点，速度由当前位置与上一时刻位置之差隐式表示
+type Particle struct {
+	Pos      Vec2
+	Prev     Vec2
+	InvMass  float64 // 质量的倒数，0表示固定不动
+	OnGround bool    // 最近一步是否与地面接触
+
+	force      Vec2 // 外力，在整个Step内保持
+	jointForce Vec2 // 关节内力，每个子步重新计算
+}
+
+// ApplyForce 累加作用在质点上的外力，在下一次Step后清零
+func (p *Particle) ApplyForce(f Vec2) {
+	p.force = p.force.Add(f)
+}
+
+// Stick 两个质点之间的距离约束（连杆）
+type Stick struct {
+	A, B      *Particle
+	Length    float64
+	Stiffness float64 // (0, 1]，1为刚性
+}
+
+// solve 按质量比例修正两端位置，使距离趋近于Length
+func (s *Stick) solve() {
+	delta := s.B.Pos.Sub(s.A.Pos)
+	dist := delta.Len()
+	w := s.A.InvMass + s.B.InvMass
+	if dist == 0 || w == 0 {
+		return
+	}
+	correction := delta.Scale((dist - s.Length) / dist * s.Stiffness / w)
+	s.A.Pos = s.A.Pos.Add(correction.Scale(s.A.InvMass))
+	s.B.Pos = s.B.Pos.Sub(correction.Scale(s.B.InvMass))
+}
+
+// Joint 由 A-Pivot 与 Pivot-B 两段连杆组成的铰链关节
+// 关节角为从 (Pivot-A) 到 (B-Pivot) 的有符号夹角，两段共线时为0
+type Joint struct {
+	A, Pivot, B *Particle
+
+	Lower, Upper   float64 // 角度限制
+	LimitStiffness float64 // 超出限制时的恢复扭矩系数
+	Damping        float64 // 角速度阻尼系数
+
+	// Torque 电机扭矩，每步由控制器设置，Step后保持不变
+	Torque float64
+}
+
+// Angle 返回当前关节角
+func (j *Joint) Angle() float64 {
+	return AngleBetween(j.Pivot.Pos.Sub(j.A.Pos), j.B.Pos.Sub(j.Pivot.Pos))
+}
+
+// prevAngle 返回上一时刻的关节角
+func (j *Joint) prevAngle() float64 {
+	return AngleBetween(j.Pivot.Prev.Sub(j.A.Prev), j.B.Prev.Sub(j.Pivot.Prev))
+}
+
+// angularVelocity 返回关节角在最近一个长度为dt的子步内的角速度
+func (j *Joint) angularVelocity(dt float64) float64 {
+	return wrapAngle(j.Angle()-j.prevAngle()) / dt
+}
+
+// applyTorque 将电机、限位和阻尼扭矩转换为作用在三个质点上的力（合力为零）
+func (j *Joint) applyTorque(dt float64) {
+	torque := j.Torque
+	angle := j.Angle()
+	if angle < j.Lower {
+		torque += j.LimitStiffness * (j.Lower - angle)
+	} else if angle > j.Upper {
+		torque += j.LimitStiffness * (j.Upper - angle)
+	}
+	torque -= j.Damping * j.angularVelocity(dt)
+	if torque == 0 {
+		return
+	}
+
+	// 正扭矩使B绕Pivot逆时针转动、A绕Pivot顺时针转动，二者均使关节角增大
+	rb := j.B.Pos.Sub(j.Pivot.Pos)
+	ra := j.A.Pos.Sub(j.Pivot.Pos)
+	lb, la := rb.Dot(rb), ra.Dot(ra)
+	if lb == 0 || la == 0 {
+		return
+	}
+	fb := rb.Perp().Scale(torque / lb)
+	fa := ra.Perp().Scale(-torque / la)
+	j.B.jointForce = j.B.jointForce.Add(fb)
+	j.A.jointForce = j.A.jointForce.Add(fa)
+	j.Pivot.jointForce = j.Pivot.jointForce.Sub(fa.Add(fb))
+}
+
+// World 物理世界
+type World struct {
+	Gravity    Vec2
+	GroundY    float64 // 地面高度，质点不能低于该高度
+	Friction   float64 // 地面摩擦系数 [0, 1]，1表示接触时水平速度完全消失
+	Damping    float64 // 全局速度保留比例 (0, 1]
+	Iterations int     // 每个子步的约束迭代次数
+	SubSteps   int     // 每次Step拆分的子步数
+
+	Particles []*Particle
+	Sticks    []*Stick
+	Joints    []*Joint
+
+	h float64 // 最近一个子步的时长
+}
+
+// NewWorld 创建带默认参数的物理世界
+func NewWorld(gravity Vec2) *World {
+	return &World{
+		Gravity:    gravity,
+		Friction:   0.8,
+		Damping:    0.999,
+		Iterations: 10,
+		SubSteps:   4,
+	}
+}
+
+// AddParticle 在指定位置添加一个静止质点，mass<=0 表示固定质点
+func (w *World) AddParticle(pos Vec2, mass float64) *Particle {
+	p := &Particle{Pos: pos, Prev: pos}
+	if mass > 0 {
+		p.InvMass = 1 / mass
+	}
+	w.Particles = append(w.Particles, p)
+	return p
+}
+
+// AddStick 在两个质点之间添加刚性连杆，长度取当前距离
+func (w *World) AddStick(a, b *Particle) *Stick {
+	s := &Stick{A: a, B: b, Length: b.Pos.Sub(a.Pos).Len(), Stiffness: 1}
+	w.Sticks = append(w.Sticks, s)
+	return s
+}
+
+// AddJoint 添加关节，关节角被限制在 [lower, upper] 内
+func (w *World) AddJoint(a, pivot, b *Particle, lower, upper float64) (*Joint, error) {
+	if lower > upper {
+		return nil, fmt.Errorf("joint lower limit %f exceeds upper limit %f", lower, upper)
+	}
+	j := &Joint{A: a, Pivot: pivot, B: b, Lower: lower, Upper: upper, LimitStiffness: 50}
+	w.Joints = append(w.Joints, j)
+	return j, nil
+}
+
+// Step 将世界推进dt时间
+func (w *World) Step(dt float64) {
+	subSteps := w.SubSteps
+	if subSteps <= 0 {
+		subSteps = 1
+	}
+	w.h = dt / float64(subSteps)
+	for i := 0; i < subSteps; i++ {
+		w.step(w.h)
+	}
+	for _, p := range w.Particles {
+		p.force = Vec2{}
+	}
+}
+
+// Velocity 返回质点在最近一个子步内的速度，尚未Step时为零
+func (w *World) Velocity(p *Particle) Vec2 {
+	if w.h == 0 {
+		return Vec2{}
+	}
+	return p.Pos.Sub(p.Prev).Scale(1 / w.h)
+}
+
+// JointVelocity 返回关节在最近一个子步内的角速度，尚未Step时为零
+func (w *World) JointVelocity(j *Joint) float64 {
+	if w.h == 0 {
+		return 0
+	}
+	return j.angularVelocity(w.h)
+}
+
+// step 执行单个子步：关节扭矩 -> Verlet积分 -> 约束求解 -> 地面碰撞
+func (w *World) step(h float64) {
+	for _, j := range w.Joints {
+		j.applyTorque(h)
+	}
+
+	for _, p := range w.Particles {
+		if p.InvMass == 0 {
+			p.Prev = p.Pos
+			continue
+		}
+		acc := w.Gravity.Add(p.force.Add(p.jointForce).Scale(p.InvMass))
+		velocity := p.Pos.Sub(p.Prev).Scale(w.Damping)
+		p.Prev = p.Pos
+		p.Pos = p.Pos.Add(velocity).Add(acc.Scale(h * h))
+		p.jointForce = Vec2{}
+	}
+
+	iterations := w.Iterations
+	if iterations <= 0 {
+		iterations = 1
+	}
+	for _, p := range w.Particles {
+		p.OnGround = false
+	}
+	for i := 0; i < iterations; i++ {
+		for _, s := range w.Sticks {
+			s.solve()
+		}
+		w.collideGround()
+	}
+
+	// 摩擦只在子步末尾施加一次，避免随迭代次数叠加
+	for _, p := range w.Particles {
+		if p.OnGround && p.InvMass > 0 {
+			vx := p.Pos.X - p.Prev.X
+			p.Prev.X = p.Pos.X - vx*(1-w.Friction)
+		}
+	}
+}
+
+// collideGround 将低于地面的质点投影回地面并消除其竖直速度
+func (w *World) collideGround() {
+	for _, p := range w.Particles {
+		if p.Pos.Y > w.GroundY || p.InvMass == 0 {
+			continue
+		}
+		p.OnGround = true
+		p.Pos.Y = w.GroundY
+		if p.Prev.Y < w.GroundY {
+			p.Prev.Y = w.GroundY
+		}
+	}
+}
+
+// wrapAngle 将角度规约到 (-π, π]
+func wrapAngle(a float64) float64 {
+	for a > math.Pi {
+		a -= 2 * math.Pi
+	}
+	for a <= -math.Pi {
+		a += 2 * math.Pi
+	}
+	return a
+}
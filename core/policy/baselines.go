@@ -0,0 +1,235 @@
+package policy
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// Constant 返回对每个观察都给出action的策略
+func Constant(action core.Action) Policy {
+	return Func(func(observations []core.Observation) ([]core.Action, error) {
+		actions := make([]core.Action, len(observations))
+		for i := range actions {
+			actions[i] = action
+		}
+		return actions, nil
+	})
+}
+
+// Zero 返回始终给出ZeroAction的策略，回合制环境只给出当前行动方的一个动作
+func Zero(env core.Environment) (Policy, error) {
+	action, err := ZeroAction(env.GetSpaces().ActionSpace)
+	if err != nil {
+		return nil, err
+	}
+	constant := Constant(action)
+	if _, ok := env.(core.TurnBasedEnvironment); !ok {
+		return constant, nil
+	}
+	return Func(func(observations []core.Observation) ([]core.Action, error) {
+		if len(observations) > 1 {
+			observations = observations[:1]
+		}
+		return constant.Act(observations)
+	}), nil
+}
+
+// Heuristic 返回scenario的手写控制器策略，作为比随机动作更强的基线；scenario须为场景名而非预设名。
+// 可用的场景见HeuristicScenarios
+func Heuristic(scenario string) (Policy, error) {
+	h, ok := heuristics[scenario]
+	if !ok {
+		return nil, fmt.Errorf("no heuristic policy for %q (available for: %s)", scenario, strings.Join(HeuristicScenarios(), ", "))
+	}
+	return Func(func(observations []core.Observation) ([]core.Action, error) {
+		actions := make([]core.Action, len(observations))
+		for i, obs := range observations {
+			action, err := h(obs)
+			if err != nil {
+				return nil, err
+			}
+			actions[i] = action
+		}
+		return actions, nil
+	}), nil
+}
+
+// HeuristicScenarios 返回提供手写控制器的场景名，按字典序排列
+func HeuristicScenarios() []string {
+	names := make([]string, 0, len(heuristics))
+	for name := range heuristics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Baselines 内置基线策略的名称，见Baseline
+var Baselines = []string{"random", "zero", "heuristic"}
+
+// Baseline 按名称创建内置基线策略：random在动作空间中均匀随机采样（见Random），zero始终给出ZeroAction，
+// heuristic为scenario的手写控制器（见Heuristic）
+func Baseline(name, scenario string, env core.Environment, rng *rand.Rand) (Policy, error) {
+	switch name {
+	case "random":
+		return Random(env, rng), nil
+	case "zero":
+		return Zero(env)
+	case "heuristic":
+		return Heuristic(scenario)
+	}
+	return nil, fmt.Errorf("unknown baseline policy %q (expected %s)", name, strings.Join(Baselines, ", "))
+}
+
+// heuristics 各经典场景的手写控制器，只根据单个观察选择动作
+var heuristics = map[string]func(obs core.Observation) (core.Action, error){
+	// 动作直接加到当前值上，一步消除与目标的差值
+	"simple": func(obs core.Observation) (core.Action, error) {
+		data, err := observationData(obs, 3)
+		if err != nil {
+			return nil, err
+		}
+		return core.NewGenericAction(math.Max(-10, math.Min(10, data[2]))), nil
+	},
+	// 向杆子倾倒的方向推小车
+	"cartpole": func(obs core.Observation) (core.Action, error) {
+		data, err := observationData(obs, 4)
+		if err != nil {
+			return nil, err
+		}
+		if data[2]+0.5*data[3] > 0 {
+			return core.NewGenericAction(1), nil
+		}
+		return core.NewGenericAction(0), nil
+	},
+	// 顺着速度方向加速以积累能量
+	"mountaincar": func(obs core.Observation) (core.Action, error) {
+		data, err := observationData(obs, 2)
+		if err != nil {
+			return nil, err
+		}
+		if data[1] >= 0 {
+			return core.NewGenericAction(2), nil
+		}
+		return core.NewGenericAction(0), nil
+	},
+	// 远离顶端时按能量偏差摆动蓄能，接近顶端时用PD控制稳定（按默认g=10、m=l=1设计）
+	"pendulum": func(obs core.Observation) (core.Action, error) {
+		data, err := observationData(obs, 3)
+		if err != nil {
+			return nil, err
+		}
+		theta, thetaDot := math.Atan2(data[1], data[0]), data[2]
+		var torque float64
+		if data[0] > 0.9 && math.Abs(thetaDot) < 3 {
+			torque = -10*theta - 2*thetaDot
+		} else {
+			energy := 0.5*thetaDot*thetaDot + 15*data[0] // 直立静止时为15
+			torque = (15 - energy) * thetaDot
+		}
+		return core.NewGenericAction(math.Max(-2, math.Min(2, torque))), nil
+	},
+	// 侧推进器只调整姿态，通过倾斜主推进器获得朝向着陆区的水平速度，并限制下降速度
+	"lunarlander": func(obs core.Observation) (core.Action, error) {
+		data, err := observationData(obs, 6)
+		if err != nil {
+			return nil, err
+		}
+		x, vx, vy, angle, angularV := data[0], data[2], data[3], data[4], data[5]
+		targetVx := math.Max(-0.3, math.Min(0.3, -0.5*x))
+		targetAngle := math.Max(-0.25, math.Min(0.25, 0.8*(targetVx-vx)))
+		angleErr := targetAngle - (angle + 0.5*angularV)
+		switch {
+		case vy < -0.45:
+			return core.NewGenericAction(2), nil
+		case angleErr > 0.05:
+			return core.NewGenericAction(1), nil
+		case angleErr < -0.05:
+			return core.NewGenericAction(3), nil
+		}
+		return core.NewGenericAction(0), nil
+	},
+	// 直接使用环境给出的LQR最优动作
+	"lqr": func(obs core.Observation) (core.Action, error) {
+		raw, ok := obs.GetMetadata()["optimal_action"].([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("observation has no optimal_action metadata (is %s disabled?)", core.ObservationMetadataKey)
+		}
+		u := make([]float64, len(raw))
+		for i, v := range raw {
+			f, err := core.ToFloat64(v)
+			if err != nil {
+				return nil, err
+			}
+			u[i] = f
+		}
+		if len(u) == 1 {
+			return core.NewGenericAction(u[0]), nil
+		}
+		return core.NewGenericAction(u), nil
+	},
+}
+
+func observationData(obs core.Observation, minLen int) ([]float64, error) {
+	data := obs.GetData()
+	if len(data) < minLen {
+		return nil, fmt.Errorf("expected at least %d observation values, got %d", minLen, len(data))
+	}
+	return data, nil
+}
+
+// ZeroAction 返回动作空间中最接近零的合法动作：Discrete为最小的动作，Box等为各维裁剪到边界内的0，
+// Text为空串，Dict对各子空间分别取值，Hybrid为不带偏移的第0个动作
+func ZeroAction(space core.ActionSpace) (core.Action, error) {
+	switch space.Type {
+	case core.SpaceTypeDiscrete:
+		if len(space.Low) == 0 {
+			return core.NewGenericAction(0), nil
+		}
+		return core.NewGenericAction(int(space.Low[0])), nil
+	case core.SpaceTypeBox, core.SpaceTypeMultiDiscrete, core.SpaceTypeMultiBinary:
+		values := make([]float64, space.Size())
+		for i := range values {
+			low, high := math.Inf(-1), math.Inf(1)
+			if len(space.Low) > 0 {
+				low = space.Low[min(i, len(space.Low)-1)]
+			}
+			if len(space.High) > 0 {
+				high = space.High[min(i, len(space.High)-1)]
+			}
+			values[i] = math.Max(low, math.Min(high, 0))
+		}
+		return core.NewActionFromValues(space, values), nil
+	case core.SpaceTypeText:
+		return core.NewGenericAction(""), nil
+	case core.SpaceTypeDict:
+		components := make(map[string]core.Action, len(space.Subspaces))
+		for name, sub := range space.Subspaces {
+			component, err := ZeroAction(sub)
+			if err != nil {
+				return nil, fmt.Errorf("subspace %q: %w", name, err)
+			}
+			components[name] = component
+		}
+		return core.NewDictAction(components), nil
+	case core.SpaceTypeHybrid:
+		if len(space.Parameters) == 0 {
+			return nil, fmt.Errorf("hybrid action space has no actions")
+		}
+		parameters, err := ZeroAction(space.Parameters[0])
+		if err != nil {
+			return nil, err
+		}
+		values, err := core.NewGenericAction(parameters.GetData()).GetFloat64Values()
+		if err != nil {
+			return nil, err
+		}
+		return core.NewHybridAction(0, values), nil
+	}
+	return nil, fmt.Errorf("unsupported action space type: %v", space.Type)
+}
@@ -0,0 +1,259 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// SuccessKeys EvalOptions既未设置SuccessKey也未设置Success时，按顺序查找的表示回合是否成功的info键，
+// 以回合最后一步之后第一个取值为bool的键为准
+var SuccessKeys = []string{"is_success", "success", "landed", "goal_reached", "reached"}
+
+// DefaultConfidence 置信区间的默认水平
+const DefaultConfidence = 0.95
+
+// EvalOptions Evaluate的配置
+type EvalOptions struct {
+	// MaxSteps 每回合的最大步数，<=0时为DefaultMaxSteps
+	MaxSteps int
+	// Confidence 置信区间的水平，不在(0, 1)内时为DefaultConfidence
+	Confidence float64
+	// SuccessKey 表示回合是否成功的info键，为空时依次尝试SuccessKeys
+	SuccessKey string
+	// Success 非nil时用它判断回合是否成功，代替info键
+	Success func(episode EvalEpisode) bool
+	// OnEpisode 非nil时在每个回合之后调用，返回错误时停止评估
+	OnEpisode func(episode EvalEpisode) error
+}
+
+// EvalEpisode 评估中的一个回合
+type EvalEpisode struct {
+	EpisodeResult
+	Success bool                   // 回合是否成功，Judged为false时为false
+	Judged  bool                   // 能否判断回合是否成功
+	Info    map[string]interface{} // 最后一步之后环境的info
+}
+
+// Evaluation 策略评估的结果
+type Evaluation struct {
+	Scenario   string
+	Episodes   []EvalEpisode
+	Steps      int           // 总步数
+	Elapsed    time.Duration // 评估耗时
+	Confidence float64       // 置信区间的水平
+
+	MeanReturn float64
+	StdReturn  float64 // 回报的样本标准差
+	// ReturnLow与ReturnHigh 平均回报的Student t置信区间，少于两个回合时都等于平均值
+	ReturnLow  float64
+	ReturnHigh float64
+	MeanLength float64
+
+	// Judged 能判断是否成功的回合数，SuccessRate在这些回合上计算，并给出Wilson区间；Judged为0时三者均为0
+	Judged      int
+	SuccessRate float64
+	SuccessLow  float64
+	SuccessHigh float64
+}
+
+// String 返回一行摘要，例如
+// "cartpole: mean return 187.4 ± 9.1 (95% CI) over 20 episodes, mean length 187.4, success 85% [64%, 95%]"
+func (e *Evaluation) String() string {
+	s := fmt.Sprintf("%s: mean return %.4g ± %.2g (%s%% CI) over %d episodes, mean length %.4g",
+		e.Scenario, e.MeanReturn, (e.ReturnHigh-e.ReturnLow)/2,
+		strconv.FormatFloat(e.Confidence*100, 'g', -1, 64), len(e.Episodes), e.MeanLength)
+	if e.Judged > 0 {
+		s += fmt.Sprintf(", success %.0f%% [%.0f%%, %.0f%%]", e.SuccessRate*100, e.SuccessLow*100, e.SuccessHigh*100)
+	}
+	return s
+}
+
+// Returns 返回各回合的回报
+func (e *Evaluation) Returns() []float64 {
+	returns := make([]float64, len(e.Episodes))
+	for i, episode := range e.Episodes {
+		returns[i] = episode.Return
+	}
+	return returns
+}
+
+// Lengths 返回各回合的长度
+func (e *Evaluation) Lengths() []int {
+	lengths := make([]int, len(e.Episodes))
+	for i, episode := range e.Episodes {
+		lengths[i] = episode.Length
+	}
+	return lengths
+}
+
+// Count 返回因reason结束的回合数
+func (e *Evaluation) Count(reason TerminationReason) int {
+	n := 0
+	for _, episode := range e.Episodes {
+		if episode.Reason == reason {
+			n++
+		}
+	}
+	return n
+}
+
+// Evaluate 用策略p在env中运行episodes个回合（见Run）并汇总：平均回报及其置信区间、成功率与每个回合的记录。
+// 回合制环境只把当前行动方的观察交给p，env不会被关闭。某个回合失败时返回已完成回合的评估及该错误
+func Evaluate(ctx context.Context, env core.Environment, p Policy, episodes int, opts EvalOptions) (*Evaluation, error) {
+	if episodes <= 0 {
+		return nil, fmt.Errorf("episodes must be positive, got %d", episodes)
+	}
+	evaluation := &Evaluation{Confidence: opts.Confidence, Episodes: make([]EvalEpisode, 0, episodes)}
+	var info map[string]interface{}
+	result, err := Run(ctx, env, CurrentPlayer(env, p), RunOptions{
+		Episodes: episodes,
+		MaxSteps: opts.MaxSteps,
+		OnStep: func(StepInfo) error {
+			info = env.GetInfo()
+			return nil
+		},
+		OnEpisode: func(episode EpisodeResult) error {
+			record := EvalEpisode{EpisodeResult: episode, Info: info}
+			if opts.Success != nil {
+				record.Success, record.Judged = opts.Success(record), true
+			} else {
+				record.Success, record.Judged = successFromInfo(info, opts.SuccessKey)
+			}
+			info = nil
+			evaluation.Episodes = append(evaluation.Episodes, record)
+			if opts.OnEpisode != nil {
+				return opts.OnEpisode(record)
+			}
+			return nil
+		},
+	})
+	evaluation.Steps, evaluation.Elapsed = result.Steps, result.Elapsed
+	evaluation.Summarize()
+	return evaluation, err
+}
+
+// RunEpisodes 用scenario和config创建环境，以ONNX模型作为策略运行episodes个回合后关闭环境
+func RunEpisodes(ctx context.Context, engine *core.SimulationEngine, scenario string, config core.Config, model *Model, episodes int) (*Evaluation, error) {
+	env, err := engine.CreateEnvironment(scenario, config)
+	if err != nil {
+		return nil, err
+	}
+	defer env.Close()
+	evaluation, err := Evaluate(ctx, env, model.Policy(env.GetSpaces().ActionSpace), episodes, EvalOptions{})
+	if evaluation != nil {
+		evaluation.Scenario = scenario
+	}
+	return evaluation, err
+}
+
+// Summarize 由Episodes计算评估的统计量，合并多次评估的回合后可再次调用
+func (e *Evaluation) Summarize() {
+	if e.Confidence <= 0 || e.Confidence >= 1 {
+		e.Confidence = DefaultConfidence
+	}
+	e.MeanReturn, e.StdReturn, e.MeanLength = 0, 0, 0
+	e.Judged, e.SuccessRate, e.SuccessLow, e.SuccessHigh = 0, 0, 0, 0
+	n := len(e.Episodes)
+	if n == 0 {
+		e.ReturnLow, e.ReturnHigh = 0, 0
+		return
+	}
+
+	returns := e.Returns()
+	successes := 0
+	for _, episode := range e.Episodes {
+		e.MeanLength += float64(episode.Length)
+		if episode.Judged {
+			e.Judged++
+			if episode.Success {
+				successes++
+			}
+		}
+	}
+	e.MeanReturn = mean(returns)
+	e.MeanLength /= float64(n)
+	e.StdReturn = sampleStd(returns)
+
+	e.ReturnLow, e.ReturnHigh = e.MeanReturn, e.MeanReturn
+	if n > 1 {
+		half := studentT(e.Confidence, n-1) * e.StdReturn / math.Sqrt(float64(n))
+		e.ReturnLow, e.ReturnHigh = e.MeanReturn-half, e.MeanReturn+half
+	}
+
+	if e.Judged > 0 {
+		e.SuccessRate = float64(successes) / float64(e.Judged)
+		e.SuccessLow, e.SuccessHigh = wilson(e.SuccessRate, e.Judged, e.Confidence)
+	}
+}
+
+// successFromInfo 从info[key]读取回合是否成功，key为空时取SuccessKeys中第一个取值为bool的键
+func successFromInfo(info map[string]interface{}, key string) (success, judged bool) {
+	if key != "" {
+		success, judged = info[key].(bool)
+		return success, judged
+	}
+	for _, k := range SuccessKeys {
+		if success, judged = info[k].(bool); judged {
+			return success, judged
+		}
+	}
+	return false, false
+}
+
+// studentT 用二分法求自由度为df的Student t分布中满足P(|T| <= t) = level的临界值t
+func studentT(level float64, df int) float64 {
+	if df > 1000 {
+		return math.Sqrt2 * math.Erfinv(level)
+	}
+	low, high := 0.0, 1.0
+	for studentTCentral(high, df) < level {
+		low, high = high, high*2
+	}
+	for i := 0; i < 100 && high-low > 1e-12; i++ {
+		mid := (low + high) / 2
+		if studentTCentral(mid, df) < level {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+	return (low + high) / 2
+}
+
+// studentTCentral 返回自由度为df的Student t分布的P(|T| <= t)，
+// 使用关于cos(θ)的有限级数，θ = atan(t/√df)
+func studentTCentral(t float64, df int) float64 {
+	theta := math.Atan(t / math.Sqrt(float64(df)))
+	sin, cos := math.Sincos(theta)
+	cos2 := cos * cos
+	term, sum := 1.0, 1.0
+	if df%2 == 1 {
+		if df == 1 {
+			return 2 / math.Pi * theta
+		}
+		for k := 3; k <= df-2; k += 2 {
+			term *= float64(k-1) / float64(k) * cos2
+			sum += term
+		}
+		return 2 / math.Pi * (theta + sin*cos*sum)
+	}
+	for k := 2; k <= df-2; k += 2 {
+		term *= float64(k-1) / float64(k) * cos2
+		sum += term
+	}
+	return sin * sum
+}
+
+// wilson 返回在n次试验中观测到比例p时的Wilson得分区间
+func wilson(p float64, n int, level float64) (low, high float64) {
+	z := math.Sqrt2 * math.Erfinv(level)
+	z2n := z * z / float64(n)
+	center := (p + z2n/2) / (1 + z2n)
+	half := z * math.Sqrt(p*(1-p)/float64(n)+z2n/float64(4*n)) / (1 + z2n)
+	return math.Max(0, center-half), math.Min(1, center+half)
+}
@@ -0,0 +1,281 @@
+package policy
+
+import (
+	"fmt"
+	"math"
+	"os"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// Model 解析后的ONNX模型：单个观察输入、以第一个输出作为动作（或动作logits）的前馈网络。
+// 推理由纯Go实现，不依赖onnxruntime，支持的算子见operators；
+// 加载时即检查算子，含不支持算子（或非默认域算子）的模型会返回错误
+type Model struct {
+	graph      *graph
+	opset      int64
+	input      string
+	inputShape []int64 // 图输入声明的形状，动态维度为-1
+	output     string
+}
+
+// LoadModel 从文件加载ONNX模型
+func LoadModel(path string) (*Model, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseModel(data)
+}
+
+// ParseModel 解析序列化的ONNX ModelProto
+func ParseModel(data []byte) (*Model, error) {
+	g, opset, err := decodeModel(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(g.inputs) != 1 {
+		return nil, fmt.Errorf("ONNX policy must have exactly one input, got %d", len(g.inputs))
+	}
+	if len(g.outputs) == 0 {
+		return nil, fmt.Errorf("ONNX model has no outputs")
+	}
+	for _, n := range g.nodes {
+		if n.domain != "" && n.domain != "ai.onnx" {
+			return nil, fmt.Errorf("unsupported ONNX operator %s.%s", n.domain, n.opType)
+		}
+		if _, ok := operators[n.opType]; !ok {
+			return nil, fmt.Errorf("unsupported ONNX operator %q", n.opType)
+		}
+		// opset 13之前Softmax默认沿axis=1
+		if (n.opType == "Softmax" || n.opType == "LogSoftmax") && opset < 13 {
+			if _, ok := n.attrs["axis"]; !ok {
+				n.attrs["axis"] = &attribute{i: 1}
+			}
+		}
+	}
+	return &Model{
+		graph:      g,
+		opset:      opset,
+		input:      g.inputs[0].name,
+		inputShape: g.inputs[0].shape,
+		output:     g.outputs[0],
+	}, nil
+}
+
+// InputShape 返回模型输入声明的形状（含批量维度），动态维度为-1
+func (m *Model) InputShape() []int64 {
+	return append([]int64(nil), m.inputShape...)
+}
+
+// Run 对一批观察执行推理，返回第一个输出按批量切分后的各行
+func (m *Model) Run(batch [][]float64) ([][]float64, error) {
+	out, err := m.run(batch)
+	if err != nil {
+		return nil, err
+	}
+	return rows(out, len(batch)), nil
+}
+
+// run 将观察堆叠为输入张量并按拓扑顺序执行图
+func (m *Model) run(batch [][]float64) (*tensor, error) {
+	if len(batch) == 0 {
+		return nil, fmt.Errorf("empty observation batch")
+	}
+	size := len(batch[0])
+	input := newTensor(m.batchShape(len(batch), size), false)
+	if input.size() != len(batch)*size {
+		return nil, fmt.Errorf("observation has %d values, model input %v expects %d", size, m.inputShape, input.size()/len(batch))
+	}
+	for i, obs := range batch {
+		if len(obs) != size {
+			return nil, fmt.Errorf("observations have different sizes: %d and %d", size, len(obs))
+		}
+		copy(input.data[i*size:], obs)
+	}
+
+	values := make(map[string]*tensor, len(m.graph.initializers)+len(m.graph.nodes)+1)
+	for name, t := range m.graph.initializers {
+		values[name] = t
+	}
+	values[m.input] = input
+	for _, n := range m.graph.nodes {
+		in := make([]*tensor, len(n.inputs))
+		for i, name := range n.inputs {
+			if name == "" {
+				continue
+			}
+			t, ok := values[name]
+			if !ok {
+				return nil, fmt.Errorf("%s node reads %q before it is computed", n.opType, name)
+			}
+			in[i] = t
+		}
+		if n.opType != "Constant" && (len(in) == 0 || in[0] == nil) {
+			return nil, fmt.Errorf("%s node has no input", n.opType)
+		}
+		out, err := operators[n.opType](n, in)
+		if err != nil {
+			return nil, fmt.Errorf("%s node: %w", n.opType, err)
+		}
+		for i, name := range n.outputs {
+			if i < len(out) && name != "" {
+				values[name] = out[i]
+			}
+		}
+	}
+
+	out, ok := values[m.output]
+	if !ok {
+		return nil, fmt.Errorf("model output %q is never computed", m.output)
+	}
+	return out, nil
+}
+
+// batchShape 按声明的输入形状确定批量输入的形状：首维为批量，其余维度已知时沿用（图像观察），否则为[n, size]
+func (m *Model) batchShape(n, size int) []int {
+	if len(m.inputShape) >= 2 {
+		shape := []int{n}
+		for _, d := range m.inputShape[1:] {
+			if d < 0 {
+				return []int{n, size}
+			}
+			shape = append(shape, int(d))
+		}
+		return shape
+	}
+	return []int{n, size}
+}
+
+// rows 将首维为批量的输出切分为n行
+func rows(t *tensor, n int) [][]float64 {
+	out := make([][]float64, n)
+	width := len(t.data) / n
+	for i := range out {
+		out[i] = t.data[i*width : (i+1)*width]
+	}
+	return out
+}
+
+// Policy 返回在给定动作空间中确定性行动的策略。模型输出按动作空间解释：
+//   - Discrete：一个值为动作本身（如SB3导出的整数动作），n个值为logits，取最大者
+//   - Box：每个智能体Size()个值，裁剪到边界
+//   - MultiDiscrete：Size()个值取整，或各组动作数之和个logits，逐组取最大者
+//   - MultiBinary：Size()个值，大于0.5的为1
+func (m *Model) Policy(space core.ActionSpace) Policy {
+	return &onnxPolicy{model: m, space: space}
+}
+
+type onnxPolicy struct {
+	model *Model
+	space core.ActionSpace
+}
+
+func (p *onnxPolicy) Act(observations []core.Observation) ([]core.Action, error) {
+	batch := make([][]float64, len(observations))
+	for i, obs := range observations {
+		batch[i] = obs.GetData()
+	}
+	out, err := p.model.run(batch)
+	if err != nil {
+		return nil, err
+	}
+	actions := make([]core.Action, len(observations))
+	for i, row := range rows(out, len(observations)) {
+		values, err := p.actionValues(row, out.integer)
+		if err != nil {
+			return nil, err
+		}
+		actions[i] = core.NewActionFromValues(p.space, values)
+	}
+	return actions, nil
+}
+
+// actionValues 将模型对一个观察的输出转换为平铺的动作值
+func (p *onnxPolicy) actionValues(row []float64, integer bool) ([]float64, error) {
+	space := p.space
+	bound := func(bounds []float64, i int, def float64) float64 {
+		if len(bounds) == 0 {
+			return def
+		}
+		return bounds[min(i, len(bounds)-1)]
+	}
+
+	switch space.Type {
+	case core.SpaceTypeDiscrete:
+		low := bound(space.Low, 0, 0)
+		n := int(bound(space.High, 0, low) - low + 1)
+		switch {
+		case len(row) == 1:
+			return []float64{clamp(math.Round(row[0]), low, low+float64(n-1))}, nil
+		case len(row) == n && !integer:
+			return []float64{low + float64(argmax(row))}, nil
+		}
+		return nil, fmt.Errorf("model output has %d values, expected 1 or %d for a discrete action space", len(row), n)
+
+	case core.SpaceTypeBox:
+		size := space.Size()
+		if len(row) != size {
+			return nil, fmt.Errorf("model output has %d values, expected %d for the box action space", len(row), size)
+		}
+		values := make([]float64, size)
+		for i, v := range row {
+			values[i] = clamp(v, bound(space.Low, i, math.Inf(-1)), bound(space.High, i, math.Inf(1)))
+		}
+		return values, nil
+
+	case core.SpaceTypeMultiDiscrete:
+		size := space.Size()
+		nvec := make([]int, size)
+		total := 0
+		for i := range nvec {
+			nvec[i] = int(bound(space.High, i, 0)-bound(space.Low, i, 0)) + 1
+			total += nvec[i]
+		}
+		values := make([]float64, size)
+		switch {
+		case len(row) == size:
+			for i, v := range row {
+				low := bound(space.Low, i, 0)
+				values[i] = clamp(math.Round(v), low, low+float64(nvec[i]-1))
+			}
+		case len(row) == total && !integer:
+			offset := 0
+			for i, n := range nvec {
+				values[i] = bound(space.Low, i, 0) + float64(argmax(row[offset:offset+n]))
+				offset += n
+			}
+		default:
+			return nil, fmt.Errorf("model output has %d values, expected %d or %d for the multi-discrete action space", len(row), size, total)
+		}
+		return values, nil
+
+	case core.SpaceTypeMultiBinary:
+		size := space.Size()
+		if len(row) != size {
+			return nil, fmt.Errorf("model output has %d values, expected %d for the multi-binary action space", len(row), size)
+		}
+		values := make([]float64, size)
+		for i, v := range row {
+			if v > 0.5 {
+				values[i] = 1
+			}
+		}
+		return values, nil
+	}
+	return nil, fmt.Errorf("unsupported action space type: %v", space.Type)
+}
+
+func argmax(values []float64) int {
+	best := 0
+	for i, v := range values {
+		if v > values[best] {
+			best = i
+		}
+	}
+	return best
+}
+
+func clamp(v, low, high float64) float64 {
+	return math.Max(low, math.Min(high, v))
+}
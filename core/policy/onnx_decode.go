@@ -0,0 +1,448 @@
+package policy
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// 解码ONNX ModelProto中推理所需的最小子集（图的节点、初始化张量、输入输出与算子集版本），
+// 直接按onnx.proto的字段编号读取，无需生成的protobuf代码
+
+// ONNX TensorProto.DataType
+const (
+	onnxFloat  = 1
+	onnxUint8  = 2
+	onnxInt8   = 3
+	onnxUint16 = 4
+	onnxInt16  = 5
+	onnxInt32  = 6
+	onnxInt64  = 7
+	onnxBool   = 9
+	onnxDouble = 11
+	onnxUint32 = 12
+	onnxUint64 = 13
+)
+
+type graph struct {
+	nodes        []*node
+	initializers map[string]*tensor
+	inputs       []valueInfo
+	outputs      []string
+}
+
+// valueInfo 图输入的名称与形状，动态维度为-1
+type valueInfo struct {
+	name  string
+	shape []int64
+}
+
+type node struct {
+	opType  string
+	domain  string
+	inputs  []string
+	outputs []string
+	attrs   map[string]*attribute
+}
+
+type attribute struct {
+	f      float64
+	i      int64
+	s      []byte
+	t      *tensor
+	floats []float64
+	ints   []int64
+}
+
+// attrInt 返回整数属性，未设置时返回def
+func (n *node) attrInt(name string, def int64) int64 {
+	if a, ok := n.attrs[name]; ok {
+		return a.i
+	}
+	return def
+}
+
+// attrFloat 返回浮点属性，未设置时返回def
+func (n *node) attrFloat(name string, def float64) float64 {
+	if a, ok := n.attrs[name]; ok {
+		return a.f
+	}
+	return def
+}
+
+// attrInts 返回整数列表属性及其是否设置
+func (n *node) attrInts(name string) ([]int64, bool) {
+	a, ok := n.attrs[name]
+	if !ok {
+		return nil, false
+	}
+	return a.ints, true
+}
+
+// walk 依次回调消息b中的每个字段：长度前缀字段给出v，其余给出x
+func walk(b []byte, fn func(num protowire.Number, typ protowire.Type, v []byte, x uint64) error) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		var v []byte
+		var x uint64
+		switch typ {
+		case protowire.VarintType:
+			x, n = protowire.ConsumeVarint(b)
+		case protowire.Fixed32Type:
+			var u uint32
+			u, n = protowire.ConsumeFixed32(b)
+			x = uint64(u)
+		case protowire.Fixed64Type:
+			x, n = protowire.ConsumeFixed64(b)
+		case protowire.BytesType:
+			v, n = protowire.ConsumeBytes(b)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		if err := fn(num, typ, v, x); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendVarints 追加重复的varint字段，兼容packed与非packed编码
+func appendVarints(dst []int64, typ protowire.Type, v []byte, x uint64) ([]int64, error) {
+	if typ != protowire.BytesType {
+		return append(dst, int64(x)), nil
+	}
+	for len(v) > 0 {
+		u, n := protowire.ConsumeVarint(v)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		dst = append(dst, int64(u))
+		v = v[n:]
+	}
+	return dst, nil
+}
+
+// appendFloats 追加重复的float字段，兼容packed与非packed编码
+func appendFloats(dst []float64, typ protowire.Type, v []byte, x uint64) []float64 {
+	if typ != protowire.BytesType {
+		return append(dst, float64(math.Float32frombits(uint32(x))))
+	}
+	for ; len(v) >= 4; v = v[4:] {
+		dst = append(dst, float64(math.Float32frombits(binary.LittleEndian.Uint32(v))))
+	}
+	return dst
+}
+
+// appendDoubles 追加重复的double字段，兼容packed与非packed编码
+func appendDoubles(dst []float64, typ protowire.Type, v []byte, x uint64) []float64 {
+	if typ != protowire.BytesType {
+		return append(dst, math.Float64frombits(x))
+	}
+	for ; len(v) >= 8; v = v[8:] {
+		dst = append(dst, math.Float64frombits(binary.LittleEndian.Uint64(v)))
+	}
+	return dst
+}
+
+// decodeModel 解码ModelProto，返回主图与默认域的算子集版本
+func decodeModel(b []byte) (*graph, int64, error) {
+	var g *graph
+	var opset int64
+	err := walk(b, func(num protowire.Number, typ protowire.Type, v []byte, x uint64) error {
+		switch num {
+		case 7: // graph
+			var err error
+			g, err = decodeGraph(v)
+			return err
+		case 8: // opset_import
+			var domain string
+			var version int64
+			if err := walk(v, func(num protowire.Number, _ protowire.Type, v []byte, x uint64) error {
+				switch num {
+				case 1:
+					domain = string(v)
+				case 2:
+					version = int64(x)
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+			if domain == "" || domain == "ai.onnx" {
+				opset = version
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid ONNX model: %w", err)
+	}
+	if g == nil {
+		return nil, 0, fmt.Errorf("invalid ONNX model: no graph")
+	}
+	return g, opset, nil
+}
+
+func decodeGraph(b []byte) (*graph, error) {
+	g := &graph{initializers: make(map[string]*tensor)}
+	err := walk(b, func(num protowire.Number, typ protowire.Type, v []byte, x uint64) error {
+		switch num {
+		case 1: // node
+			n, err := decodeNode(v)
+			if err != nil {
+				return err
+			}
+			g.nodes = append(g.nodes, n)
+		case 5: // initializer
+			t, name, err := decodeTensor(v)
+			if err != nil {
+				return err
+			}
+			g.initializers[name] = t
+		case 11: // input
+			info, err := decodeValueInfo(v)
+			if err != nil {
+				return err
+			}
+			g.inputs = append(g.inputs, info)
+		case 12: // output
+			info, err := decodeValueInfo(v)
+			if err != nil {
+				return err
+			}
+			g.outputs = append(g.outputs, info.name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	// 旧版本导出的模型把initializer也列为图输入
+	inputs := g.inputs[:0]
+	for _, in := range g.inputs {
+		if _, ok := g.initializers[in.name]; !ok {
+			inputs = append(inputs, in)
+		}
+	}
+	g.inputs = inputs
+	return g, nil
+}
+
+func decodeNode(b []byte) (*node, error) {
+	n := &node{attrs: make(map[string]*attribute)}
+	err := walk(b, func(num protowire.Number, typ protowire.Type, v []byte, x uint64) error {
+		switch num {
+		case 1:
+			n.inputs = append(n.inputs, string(v))
+		case 2:
+			n.outputs = append(n.outputs, string(v))
+		case 4:
+			n.opType = string(v)
+		case 5:
+			name, a, err := decodeAttribute(v)
+			if err != nil {
+				return err
+			}
+			n.attrs[name] = a
+		case 7:
+			n.domain = string(v)
+		}
+		return nil
+	})
+	return n, err
+}
+
+func decodeAttribute(b []byte) (string, *attribute, error) {
+	var name string
+	a := &attribute{}
+	err := walk(b, func(num protowire.Number, typ protowire.Type, v []byte, x uint64) error {
+		var err error
+		switch num {
+		case 1:
+			name = string(v)
+		case 2:
+			a.f = float64(math.Float32frombits(uint32(x)))
+		case 3:
+			a.i = int64(x)
+		case 4:
+			a.s = v
+		case 5:
+			a.t, _, err = decodeTensor(v)
+		case 7:
+			a.floats = appendFloats(a.floats, typ, v, x)
+		case 8:
+			a.ints, err = appendVarints(a.ints, typ, v, x)
+		}
+		return err
+	})
+	return name, a, err
+}
+
+func decodeValueInfo(b []byte) (valueInfo, error) {
+	var info valueInfo
+	err := walk(b, func(num protowire.Number, _ protowire.Type, v []byte, _ uint64) error {
+		switch num {
+		case 1:
+			info.name = string(v)
+		case 2: // TypeProto.tensor_type.shape.dim
+			return walk(v, func(num protowire.Number, _ protowire.Type, v []byte, _ uint64) error {
+				if num != 1 {
+					return nil
+				}
+				return walk(v, func(num protowire.Number, _ protowire.Type, v []byte, _ uint64) error {
+					if num != 2 {
+						return nil
+					}
+					return walk(v, func(num protowire.Number, _ protowire.Type, v []byte, _ uint64) error {
+						if num != 1 {
+							return nil
+						}
+						dim := int64(-1)
+						if err := walk(v, func(num protowire.Number, _ protowire.Type, _ []byte, x uint64) error {
+							if num == 1 {
+								dim = int64(x)
+							}
+							return nil
+						}); err != nil {
+							return err
+						}
+						info.shape = append(info.shape, dim)
+						return nil
+					})
+				})
+			})
+		}
+		return nil
+	})
+	return info, err
+}
+
+// decodeTensor 解码TensorProto，所有元素类型都转换为float64保存
+func decodeTensor(b []byte) (*tensor, string, error) {
+	var (
+		name     string
+		dims     []int64
+		dataType int64
+		values   []float64
+		ints     []int64
+		raw      []byte
+		external bool
+	)
+	err := walk(b, func(num protowire.Number, typ protowire.Type, v []byte, x uint64) error {
+		var err error
+		switch num {
+		case 1:
+			dims, err = appendVarints(dims, typ, v, x)
+		case 2:
+			dataType = int64(x)
+		case 4:
+			values = appendFloats(values, typ, v, x)
+		case 5, 7, 11: // int32_data、int64_data、uint64_data
+			ints, err = appendVarints(ints, typ, v, x)
+		case 8:
+			name = string(v)
+		case 9:
+			raw = v
+		case 10:
+			values = appendDoubles(values, typ, v, x)
+		case 14:
+			external = x == 1
+		}
+		return err
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	if external {
+		return nil, "", fmt.Errorf("tensor %q uses external data, which is not supported", name)
+	}
+
+	t := &tensor{shape: make([]int, len(dims))}
+	for i, d := range dims {
+		t.shape[i] = int(d)
+	}
+	switch dataType {
+	case onnxFloat, onnxDouble:
+	case onnxUint8, onnxInt8, onnxUint16, onnxInt16, onnxInt32, onnxInt64, onnxBool, onnxUint32, onnxUint64:
+		t.integer = true
+	default:
+		return nil, "", fmt.Errorf("tensor %q has unsupported data type %d", name, dataType)
+	}
+
+	switch {
+	case raw != nil:
+		t.data, err = decodeRaw(raw, dataType)
+		if err != nil {
+			return nil, "", fmt.Errorf("tensor %q: %w", name, err)
+		}
+	case t.integer:
+		t.data = make([]float64, len(ints))
+		for i, v := range ints {
+			if dataType == onnxInt32 || dataType == onnxInt16 || dataType == onnxInt8 {
+				v = int64(int32(v)) // 负数以64位varint编码
+			}
+			t.data[i] = float64(v)
+		}
+	default:
+		t.data = values
+	}
+	if len(t.data) != t.size() {
+		return nil, "", fmt.Errorf("tensor %q has %d values, expected %d for shape %v", name, len(t.data), t.size(), t.shape)
+	}
+	return t, name, nil
+}
+
+// decodeRaw 解码小端序的raw_data
+func decodeRaw(raw []byte, dataType int64) ([]float64, error) {
+	var width int
+	switch dataType {
+	case onnxUint8, onnxInt8, onnxBool:
+		width = 1
+	case onnxUint16, onnxInt16:
+		width = 2
+	case onnxFloat, onnxInt32, onnxUint32:
+		width = 4
+	default:
+		width = 8
+	}
+	if len(raw)%width != 0 {
+		return nil, fmt.Errorf("raw data length %d is not a multiple of %d", len(raw), width)
+	}
+	out := make([]float64, len(raw)/width)
+	for i := range out {
+		p := raw[i*width:]
+		switch dataType {
+		case onnxUint8, onnxBool:
+			out[i] = float64(p[0])
+		case onnxInt8:
+			out[i] = float64(int8(p[0]))
+		case onnxUint16:
+			out[i] = float64(binary.LittleEndian.Uint16(p))
+		case onnxInt16:
+			out[i] = float64(int16(binary.LittleEndian.Uint16(p)))
+		case onnxFloat:
+			out[i] = float64(math.Float32frombits(binary.LittleEndian.Uint32(p)))
+		case onnxInt32:
+			out[i] = float64(int32(binary.LittleEndian.Uint32(p)))
+		case onnxUint32:
+			out[i] = float64(binary.LittleEndian.Uint32(p))
+		case onnxInt64:
+			out[i] = float64(int64(binary.LittleEndian.Uint64(p)))
+		case onnxUint64:
+			out[i] = float64(binary.LittleEndian.Uint64(p))
+		case onnxDouble:
+			out[i] = math.Float64frombits(binary.LittleEndian.Uint64(p))
+		}
+	}
+	return out, nil
+}
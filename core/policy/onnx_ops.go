@@ -0,0 +1,770 @@
+package policy
+
+import (
+	"fmt"
+	"math"
+)
+
+// tensor 行主序的稠密张量，元素统一以float64保存；integer标记ONNX中的整数类型（ArgMax、Shape等的结果）
+type tensor struct {
+	shape   []int
+	data    []float64
+	integer bool
+}
+
+func newTensor(shape []int, integer bool) *tensor {
+	t := &tensor{shape: shape, integer: integer}
+	t.data = make([]float64, t.size())
+	return t
+}
+
+func (t *tensor) size() int {
+	n := 1
+	for _, d := range t.shape {
+		n *= d
+	}
+	return n
+}
+
+// ints 将一维整数张量（形状、轴等参数）转换为[]int
+func (t *tensor) ints() []int {
+	out := make([]int, len(t.data))
+	for i, v := range t.data {
+		out[i] = int(v)
+	}
+	return out
+}
+
+// reshaped 返回共享数据、形状不同的张量
+func (t *tensor) reshaped(shape []int) *tensor {
+	return &tensor{shape: shape, data: t.data, integer: t.integer}
+}
+
+func prod(dims []int) int {
+	n := 1
+	for _, d := range dims {
+		n *= d
+	}
+	return n
+}
+
+// normAxis 将负数轴转换为非负，超出范围时返回错误
+func normAxis(axis, rank int) (int, error) {
+	if axis < 0 {
+		axis += rank
+	}
+	if axis < 0 || axis >= rank {
+		return 0, fmt.Errorf("axis %d out of range for rank %d", axis, rank)
+	}
+	return axis, nil
+}
+
+// opFunc 执行一个节点，in中未提供的可选输入为nil
+type opFunc func(n *node, in []*tensor) ([]*tensor, error)
+
+// operators 支持的ONNX算子（默认域），覆盖常见的MLP/CNN策略导出（如SB3、CleanRL经torch.onnx.export）
+var operators = map[string]opFunc{
+	"Identity": func(n *node, in []*tensor) ([]*tensor, error) { return in[:1], nil },
+	"Dropout":  func(n *node, in []*tensor) ([]*tensor, error) { return in[:1], nil },
+
+	"Relu":     unary(func(x float64) float64 { return math.Max(x, 0) }),
+	"Tanh":     unary(math.Tanh),
+	"Sigmoid":  unary(func(x float64) float64 { return 1 / (1 + math.Exp(-x)) }),
+	"Exp":      unary(math.Exp),
+	"Log":      unary(math.Log),
+	"Neg":      unary(func(x float64) float64 { return -x }),
+	"Sqrt":     unary(math.Sqrt),
+	"Abs":      unary(math.Abs),
+	"Floor":    unary(math.Floor),
+	"Ceil":     unary(math.Ceil),
+	"Softplus": unary(func(x float64) float64 { return math.Log1p(math.Exp(-math.Abs(x))) + math.Max(x, 0) }),
+	"LeakyRelu": func(n *node, in []*tensor) ([]*tensor, error) {
+		alpha := n.attrFloat("alpha", 0.01)
+		return unary(func(x float64) float64 {
+			if x < 0 {
+				return alpha * x
+			}
+			return x
+		})(n, in)
+	},
+	"Elu": func(n *node, in []*tensor) ([]*tensor, error) {
+		alpha := n.attrFloat("alpha", 1)
+		return unary(func(x float64) float64 {
+			if x < 0 {
+				return alpha * (math.Exp(x) - 1)
+			}
+			return x
+		})(n, in)
+	},
+	"Selu": func(n *node, in []*tensor) ([]*tensor, error) {
+		alpha, gamma := n.attrFloat("alpha", 1.67326319217681884765625), n.attrFloat("gamma", 1.05070102214813232421875)
+		return unary(func(x float64) float64 {
+			if x < 0 {
+				return gamma * alpha * (math.Exp(x) - 1)
+			}
+			return gamma * x
+		})(n, in)
+	},
+
+	"Add": elementwise(func(x, y float64) float64 { return x + y }),
+	"Sub": elementwise(func(x, y float64) float64 { return x - y }),
+	"Mul": elementwise(func(x, y float64) float64 { return x * y }),
+	"Div": func(n *node, in []*tensor) ([]*tensor, error) {
+		if in[0].integer && in[1].integer {
+			return elementwise(func(x, y float64) float64 { return math.Trunc(x / y) })(n, in)
+		}
+		return elementwise(func(x, y float64) float64 { return x / y })(n, in)
+	},
+	"Pow": elementwise(math.Pow),
+
+	"MatMul":     opMatMul,
+	"Gemm":       opGemm,
+	"Softmax":    opSoftmax(false),
+	"LogSoftmax": opSoftmax(true),
+	"ArgMax":     opArgMax,
+	"Clip":       opClip,
+	"Constant":   opConstant,
+	"Cast":       opCast,
+	"Shape":      opShape,
+	"Flatten":    opFlatten,
+	"Reshape":    opReshape,
+	"Squeeze":    opSqueeze,
+	"Unsqueeze":  opUnsqueeze,
+	"Concat":     opConcat,
+	"Gather":     opGather,
+	"Transpose":  opTranspose,
+	"Slice":      opSlice,
+}
+
+func unary(f func(float64) float64) opFunc {
+	return func(n *node, in []*tensor) ([]*tensor, error) {
+		out := newTensor(in[0].shape, false)
+		for i, v := range in[0].data {
+			out.data[i] = f(v)
+		}
+		return []*tensor{out}, nil
+	}
+}
+
+// elementwise 按numpy规则广播后逐元素计算
+func elementwise(f func(x, y float64) float64) opFunc {
+	return func(n *node, in []*tensor) ([]*tensor, error) {
+		if len(in) < 2 || in[1] == nil {
+			return nil, fmt.Errorf("expected 2 inputs")
+		}
+		out, err := broadcast(in[0], in[1], f)
+		if err != nil {
+			return nil, err
+		}
+		return []*tensor{out}, nil
+	}
+}
+
+func broadcast(a, b *tensor, f func(x, y float64) float64) (*tensor, error) {
+	rank := max(len(a.shape), len(b.shape))
+	as, bs := padShape(a.shape, rank), padShape(b.shape, rank)
+	shape := make([]int, rank)
+	for i := range shape {
+		switch {
+		case as[i] == bs[i], bs[i] == 1:
+			shape[i] = as[i]
+		case as[i] == 1:
+			shape[i] = bs[i]
+		default:
+			return nil, fmt.Errorf("shapes %v and %v cannot be broadcast", a.shape, b.shape)
+		}
+	}
+	out := newTensor(shape, a.integer && b.integer)
+	if len(a.data) == len(out.data) && len(b.data) == len(out.data) {
+		for i := range out.data {
+			out.data[i] = f(a.data[i], b.data[i])
+		}
+		return out, nil
+	}
+
+	astr, bstr := broadcastStrides(as), broadcastStrides(bs)
+	idx := make([]int, rank)
+	for k := range out.data {
+		ai, bi := 0, 0
+		for d, i := range idx {
+			ai += i * astr[d]
+			bi += i * bstr[d]
+		}
+		out.data[k] = f(a.data[ai], b.data[bi])
+		for d := rank - 1; d >= 0; d-- {
+			idx[d]++
+			if idx[d] < shape[d] {
+				break
+			}
+			idx[d] = 0
+		}
+	}
+	return out, nil
+}
+
+// padShape 在前面补1使形状的维数为rank
+func padShape(shape []int, rank int) []int {
+	out := make([]int, rank)
+	for i := range out {
+		out[i] = 1
+	}
+	copy(out[rank-len(shape):], shape)
+	return out
+}
+
+// broadcastStrides 返回行主序的步长，长度为1的维度步长为0
+func broadcastStrides(shape []int) []int {
+	strides := make([]int, len(shape))
+	stride := 1
+	for i := len(shape) - 1; i >= 0; i-- {
+		if shape[i] != 1 {
+			strides[i] = stride
+		}
+		stride *= shape[i]
+	}
+	return strides
+}
+
+// matmul 计算c[m,n] += a[m,k]·b[k,n]
+func matmul(c, a, b []float64, m, k, n int) {
+	for i := 0; i < m; i++ {
+		row := c[i*n : (i+1)*n]
+		for p := 0; p < k; p++ {
+			av := a[i*k+p]
+			if av == 0 {
+				continue
+			}
+			brow := b[p*n : (p+1)*n]
+			for j, bv := range brow {
+				row[j] += av * bv
+			}
+		}
+	}
+}
+
+// opMatMul 支持一维向量与批量矩阵乘；批量维度需相同，或右侧为二维矩阵
+func opMatMul(n *node, in []*tensor) ([]*tensor, error) {
+	if len(in) < 2 || in[1] == nil {
+		return nil, fmt.Errorf("expected at least 2 inputs")
+	}
+	a, b := in[0], in[1]
+	as, bs := a.shape, b.shape
+	if len(as) == 1 {
+		as = []int{1, as[0]}
+	}
+	if len(bs) == 1 {
+		bs = []int{bs[0], 1}
+	}
+	m, k, nn := as[len(as)-2], as[len(as)-1], bs[len(bs)-1]
+	if bs[len(bs)-2] != k {
+		return nil, fmt.Errorf("MatMul shapes %v and %v do not match", a.shape, b.shape)
+	}
+	batchA, batchB := prod(as[:len(as)-2]), prod(bs[:len(bs)-2])
+	batchShape := as[:len(as)-2]
+	if batchB != 1 && batchB != batchA {
+		if batchA != 1 {
+			return nil, fmt.Errorf("MatMul batch dimensions of %v and %v do not match", a.shape, b.shape)
+		}
+		batchShape = bs[:len(bs)-2]
+	}
+	batch := max(batchA, batchB)
+
+	shape := append(append([]int(nil), batchShape...), m, nn)
+	out := newTensor(shape, a.integer && b.integer)
+	for i := 0; i < batch; i++ {
+		ai, bi := i%batchA, i%batchB
+		matmul(out.data[i*m*nn:(i+1)*m*nn], a.data[ai*m*k:(ai+1)*m*k], b.data[bi*k*nn:(bi+1)*k*nn], m, k, nn)
+	}
+	// 一维输入对应的维度不出现在结果中
+	if len(a.shape) == 1 {
+		shape = append(shape[:len(shape)-2], shape[len(shape)-1])
+	}
+	if len(b.shape) == 1 {
+		shape = shape[:len(shape)-1]
+	}
+	out.shape = shape
+	return []*tensor{out}, nil
+}
+
+// transpose2D 返回二维矩阵的转置数据
+func transpose2D(data []float64, rows, cols int) []float64 {
+	out := make([]float64, len(data))
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			out[j*rows+i] = data[i*cols+j]
+		}
+	}
+	return out
+}
+
+// opGemm Y = alpha·A'·B' + beta·C
+func opGemm(n *node, in []*tensor) ([]*tensor, error) {
+	if len(in) < 2 || in[1] == nil {
+		return nil, fmt.Errorf("expected at least 2 inputs")
+	}
+	a, b := in[0], in[1]
+	if len(a.shape) != 2 || len(b.shape) != 2 {
+		return nil, fmt.Errorf("Gemm expects 2-D inputs, got %v and %v", a.shape, b.shape)
+	}
+	m, k := a.shape[0], a.shape[1]
+	ad := a.data
+	if n.attrInt("transA", 0) != 0 {
+		ad, m, k = transpose2D(ad, m, k), k, m
+	}
+	kb, nn := b.shape[0], b.shape[1]
+	bd := b.data
+	if n.attrInt("transB", 0) != 0 {
+		bd, kb, nn = transpose2D(bd, kb, nn), nn, kb
+	}
+	if kb != k {
+		return nil, fmt.Errorf("Gemm shapes %v and %v do not match", a.shape, b.shape)
+	}
+
+	out := newTensor([]int{m, nn}, false)
+	matmul(out.data, ad, bd, m, k, nn)
+	if alpha := n.attrFloat("alpha", 1); alpha != 1 {
+		for i := range out.data {
+			out.data[i] *= alpha
+		}
+	}
+	if len(in) > 2 && in[2] != nil {
+		beta := n.attrFloat("beta", 1)
+		sum, err := broadcast(out, in[2], func(x, c float64) float64 { return x + beta*c })
+		if err != nil {
+			return nil, err
+		}
+		out = sum
+	}
+	return []*tensor{out}, nil
+}
+
+// forAxis 对张量沿axis的每一条一维切片调用f，切片以起点与步长给出
+func forAxis(shape []int, axis int, f func(start, stride int)) {
+	outer, dim, inner := prod(shape[:axis]), shape[axis], prod(shape[axis+1:])
+	for o := 0; o < outer; o++ {
+		for i := 0; i < inner; i++ {
+			f(o*dim*inner+i, inner)
+		}
+	}
+}
+
+func opSoftmax(logarithm bool) opFunc {
+	return func(n *node, in []*tensor) ([]*tensor, error) {
+		x := in[0]
+		axis, err := normAxis(int(n.attrInt("axis", -1)), len(x.shape))
+		if err != nil {
+			return nil, err
+		}
+		out := newTensor(x.shape, false)
+		dim := x.shape[axis]
+		forAxis(x.shape, axis, func(start, stride int) {
+			maxV := math.Inf(-1)
+			for j := 0; j < dim; j++ {
+				maxV = math.Max(maxV, x.data[start+j*stride])
+			}
+			sum := 0.0
+			for j := 0; j < dim; j++ {
+				sum += math.Exp(x.data[start+j*stride] - maxV)
+			}
+			for j := 0; j < dim; j++ {
+				v := x.data[start+j*stride] - maxV
+				if logarithm {
+					out.data[start+j*stride] = v - math.Log(sum)
+				} else {
+					out.data[start+j*stride] = math.Exp(v) / sum
+				}
+			}
+		})
+		return []*tensor{out}, nil
+	}
+}
+
+func opArgMax(n *node, in []*tensor) ([]*tensor, error) {
+	x := in[0]
+	axis, err := normAxis(int(n.attrInt("axis", 0)), len(x.shape))
+	if err != nil {
+		return nil, err
+	}
+	last := n.attrInt("select_last_index", 0) != 0
+
+	shape := append([]int(nil), x.shape...)
+	shape[axis] = 1
+	out := newTensor(shape, true)
+	dim, k := x.shape[axis], 0
+	forAxis(x.shape, axis, func(start, stride int) {
+		best := 0
+		for j := 1; j < dim; j++ {
+			v, b := x.data[start+j*stride], x.data[start+best*stride]
+			if v > b || (last && v == b) {
+				best = j
+			}
+		}
+		out.data[k] = float64(best)
+		k++
+	})
+	// forAxis按外层、内层的顺序遍历，与去掉axis后的行主序一致
+	if n.attrInt("keepdims", 1) == 0 {
+		out.shape = append(shape[:axis:axis], shape[axis+1:]...)
+	}
+	return []*tensor{out}, nil
+}
+
+// opClip 边界来自属性（opset<11）或可选输入
+func opClip(n *node, in []*tensor) ([]*tensor, error) {
+	low, high := n.attrFloat("min", math.Inf(-1)), n.attrFloat("max", math.Inf(1))
+	if len(in) > 1 && in[1] != nil {
+		low = in[1].data[0]
+	}
+	if len(in) > 2 && in[2] != nil {
+		high = in[2].data[0]
+	}
+	out := newTensor(in[0].shape, in[0].integer)
+	for i, v := range in[0].data {
+		out.data[i] = math.Max(low, math.Min(high, v))
+	}
+	return []*tensor{out}, nil
+}
+
+func opConstant(n *node, in []*tensor) ([]*tensor, error) {
+	if a, ok := n.attrs["value"]; ok && a.t != nil {
+		return []*tensor{a.t}, nil
+	}
+	if a, ok := n.attrs["value_float"]; ok {
+		return []*tensor{{shape: []int{}, data: []float64{a.f}}}, nil
+	}
+	if a, ok := n.attrs["value_floats"]; ok {
+		return []*tensor{{shape: []int{len(a.floats)}, data: a.floats}}, nil
+	}
+	if a, ok := n.attrs["value_int"]; ok {
+		return []*tensor{{shape: []int{}, data: []float64{float64(a.i)}, integer: true}}, nil
+	}
+	if a, ok := n.attrs["value_ints"]; ok {
+		t := &tensor{shape: []int{len(a.ints)}, data: make([]float64, len(a.ints)), integer: true}
+		for i, v := range a.ints {
+			t.data[i] = float64(v)
+		}
+		return []*tensor{t}, nil
+	}
+	return nil, fmt.Errorf("Constant has no supported value attribute")
+}
+
+func opCast(n *node, in []*tensor) ([]*tensor, error) {
+	to := n.attrInt("to", onnxFloat)
+	out := newTensor(in[0].shape, to != onnxFloat && to != onnxDouble)
+	for i, v := range in[0].data {
+		switch {
+		case to == onnxBool && v != 0:
+			out.data[i] = 1
+		case to == onnxBool:
+			out.data[i] = 0
+		case out.integer:
+			out.data[i] = math.Trunc(v)
+		default:
+			out.data[i] = v
+		}
+	}
+	return []*tensor{out}, nil
+}
+
+func opShape(n *node, in []*tensor) ([]*tensor, error) {
+	out := newTensor([]int{len(in[0].shape)}, true)
+	for i, d := range in[0].shape {
+		out.data[i] = float64(d)
+	}
+	return []*tensor{out}, nil
+}
+
+func opFlatten(n *node, in []*tensor) ([]*tensor, error) {
+	x := in[0]
+	axis := int(n.attrInt("axis", 1))
+	if axis < 0 {
+		axis += len(x.shape)
+	}
+	if axis < 0 || axis > len(x.shape) {
+		return nil, fmt.Errorf("Flatten axis %d out of range for rank %d", axis, len(x.shape))
+	}
+	return []*tensor{x.reshaped([]int{prod(x.shape[:axis]), prod(x.shape[axis:])})}, nil
+}
+
+// opReshape 目标形状中0表示沿用输入的维度，-1由其余维度推断
+func opReshape(n *node, in []*tensor) ([]*tensor, error) {
+	x := in[0]
+	if len(in) < 2 || in[1] == nil {
+		return nil, fmt.Errorf("Reshape requires the shape input (opset >= 5)")
+	}
+	shape := in[1].ints()
+	infer, known := -1, 1
+	for i, d := range shape {
+		switch {
+		case d == 0 && n.attrInt("allowzero", 0) == 0:
+			if i >= len(x.shape) {
+				return nil, fmt.Errorf("Reshape copies dimension %d of %v", i, x.shape)
+			}
+			shape[i] = x.shape[i]
+			known *= shape[i]
+		case d == -1:
+			infer = i
+		default:
+			known *= d
+		}
+	}
+	if infer >= 0 {
+		if known == 0 {
+			return nil, fmt.Errorf("cannot infer Reshape of %v to %v", x.shape, in[1].ints())
+		}
+		shape[infer] = x.size() / known
+	}
+	if prod(shape) != x.size() {
+		return nil, fmt.Errorf("cannot Reshape %v to %v", x.shape, in[1].ints())
+	}
+	return []*tensor{x.reshaped(shape)}, nil
+}
+
+// axesArg 读取轴参数：opset>=13来自第二个输入，之前来自axes属性
+func axesArg(n *node, in []*tensor) ([]int, bool) {
+	if len(in) > 1 && in[1] != nil {
+		return in[1].ints(), true
+	}
+	if axes, ok := n.attrInts("axes"); ok {
+		out := make([]int, len(axes))
+		for i, a := range axes {
+			out[i] = int(a)
+		}
+		return out, true
+	}
+	return nil, false
+}
+
+func opSqueeze(n *node, in []*tensor) ([]*tensor, error) {
+	x := in[0]
+	axes, ok := axesArg(n, in)
+	drop := make([]bool, len(x.shape))
+	for i, d := range x.shape {
+		drop[i] = !ok && d == 1
+	}
+	for _, a := range axes {
+		axis, err := normAxis(a, len(x.shape))
+		if err != nil {
+			return nil, err
+		}
+		if x.shape[axis] != 1 {
+			return nil, fmt.Errorf("cannot Squeeze axis %d of %v", a, x.shape)
+		}
+		drop[axis] = true
+	}
+	shape := []int{}
+	for i, d := range x.shape {
+		if !drop[i] {
+			shape = append(shape, d)
+		}
+	}
+	return []*tensor{x.reshaped(shape)}, nil
+}
+
+func opUnsqueeze(n *node, in []*tensor) ([]*tensor, error) {
+	x := in[0]
+	axes, _ := axesArg(n, in)
+	rank := len(x.shape) + len(axes)
+	insert := make([]bool, rank)
+	for _, a := range axes {
+		axis, err := normAxis(a, rank)
+		if err != nil {
+			return nil, err
+		}
+		insert[axis] = true
+	}
+	shape := make([]int, 0, rank)
+	j := 0
+	for i := 0; i < rank; i++ {
+		if insert[i] {
+			shape = append(shape, 1)
+		} else {
+			shape = append(shape, x.shape[j])
+			j++
+		}
+	}
+	return []*tensor{x.reshaped(shape)}, nil
+}
+
+func opConcat(n *node, in []*tensor) ([]*tensor, error) {
+	first := in[0]
+	axis, err := normAxis(int(n.attrInt("axis", 0)), len(first.shape))
+	if err != nil {
+		return nil, err
+	}
+	shape := append([]int(nil), first.shape...)
+	shape[axis] = 0
+	integer := true
+	for _, t := range in {
+		if len(t.shape) != len(shape) {
+			return nil, fmt.Errorf("Concat inputs have different ranks")
+		}
+		shape[axis] += t.shape[axis]
+		integer = integer && t.integer
+	}
+	out := newTensor(shape, integer)
+	outer := prod(shape[:axis])
+	offset := 0
+	for o := 0; o < outer; o++ {
+		for _, t := range in {
+			chunk := prod(t.shape[axis:])
+			offset += copy(out.data[offset:], t.data[o*chunk:(o+1)*chunk])
+		}
+	}
+	return []*tensor{out}, nil
+}
+
+func opGather(n *node, in []*tensor) ([]*tensor, error) {
+	if len(in) < 2 || in[1] == nil {
+		return nil, fmt.Errorf("expected at least 2 inputs")
+	}
+	data, indices := in[0], in[1]
+	axis, err := normAxis(int(n.attrInt("axis", 0)), len(data.shape))
+	if err != nil {
+		return nil, err
+	}
+	shape := append(append(append([]int(nil), data.shape[:axis]...), indices.shape...), data.shape[axis+1:]...)
+	out := newTensor(shape, data.integer)
+	outer, dim, inner := prod(data.shape[:axis]), data.shape[axis], prod(data.shape[axis+1:])
+	k := 0
+	for o := 0; o < outer; o++ {
+		for _, v := range indices.data {
+			i := int(v)
+			if i < 0 {
+				i += dim
+			}
+			if i < 0 || i >= dim {
+				return nil, fmt.Errorf("Gather index %d out of range for dimension %d", int(v), dim)
+			}
+			k += copy(out.data[k:], data.data[(o*dim+i)*inner:(o*dim+i+1)*inner])
+		}
+	}
+	return []*tensor{out}, nil
+}
+
+func opTranspose(n *node, in []*tensor) ([]*tensor, error) {
+	x := in[0]
+	rank := len(x.shape)
+	perm := make([]int, rank)
+	if p, ok := n.attrInts("perm"); ok && len(p) == rank {
+		for i, v := range p {
+			perm[i] = int(v)
+		}
+	} else {
+		for i := range perm {
+			perm[i] = rank - 1 - i
+		}
+	}
+
+	shape := make([]int, rank)
+	for i, p := range perm {
+		shape[i] = x.shape[p]
+	}
+	out := newTensor(shape, x.integer)
+	strides := make([]int, rank)
+	stride := 1
+	for i := rank - 1; i >= 0; i-- {
+		strides[i] = stride
+		stride *= x.shape[i]
+	}
+	idx := make([]int, rank)
+	for k := range out.data {
+		src := 0
+		for d, i := range idx {
+			src += i * strides[perm[d]]
+		}
+		out.data[k] = x.data[src]
+		for d := rank - 1; d >= 0; d-- {
+			idx[d]++
+			if idx[d] < shape[d] {
+				break
+			}
+			idx[d] = 0
+		}
+	}
+	return []*tensor{out}, nil
+}
+
+// opSlice 参数来自输入（opset>=10）或属性
+func opSlice(n *node, in []*tensor) ([]*tensor, error) {
+	x := in[0]
+	var starts, ends, axes, steps []int
+	if len(in) > 2 && in[1] != nil && in[2] != nil {
+		starts, ends = in[1].ints(), in[2].ints()
+		if len(in) > 3 && in[3] != nil {
+			axes = in[3].ints()
+		}
+		if len(in) > 4 && in[4] != nil {
+			steps = in[4].ints()
+		}
+	} else {
+		for name, dst := range map[string]*[]int{"starts": &starts, "ends": &ends, "axes": &axes} {
+			values, _ := n.attrInts(name)
+			for _, v := range values {
+				*dst = append(*dst, int(v))
+			}
+		}
+	}
+
+	rank := len(x.shape)
+	begin, step, shape := make([]int, rank), make([]int, rank), append([]int(nil), x.shape...)
+	for i := range step {
+		step[i] = 1
+	}
+	for i := range starts {
+		axis := i
+		if axes != nil {
+			var err error
+			if axis, err = normAxis(axes[i], rank); err != nil {
+				return nil, err
+			}
+		}
+		s := 1
+		if steps != nil {
+			s = steps[i]
+		}
+		if s == 0 {
+			return nil, fmt.Errorf("Slice step cannot be 0")
+		}
+		dim := x.shape[axis]
+		clamp := func(v, low, high int) int {
+			if v < 0 {
+				v += dim
+			}
+			return max(low, min(high, v))
+		}
+		var start, end int
+		if s > 0 {
+			start, end = clamp(starts[i], 0, dim), clamp(ends[i], 0, dim)
+			shape[axis] = max(0, (end-start+s-1)/s)
+		} else {
+			start, end = clamp(starts[i], 0, dim-1), clamp(ends[i], -1, dim-1)
+			shape[axis] = max(0, (start-end-s-1)/-s)
+		}
+		begin[axis], step[axis] = start, s
+	}
+
+	out := newTensor(shape, x.integer)
+	strides := make([]int, rank)
+	stride := 1
+	for i := rank - 1; i >= 0; i-- {
+		strides[i] = stride
+		stride *= x.shape[i]
+	}
+	idx := make([]int, rank)
+	for k := range out.data {
+		src := 0
+		for d, i := range idx {
+			src += (begin[d] + i*step[d]) * strides[d]
+		}
+		out.data[k] = x.data[src]
+		for d := rank - 1; d >= 0; d-- {
+			idx[d]++
+			if idx[d] < shape[d] {
+				break
+			}
+			idx[d] = 0
+		}
+	}
+	return []*tensor{out}, nil
+}
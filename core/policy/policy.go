@@ -0,0 +1,37 @@
+// Package policy 在服务端运行策略：加载ONNX模型在本地推理，并用它完成整段回合，
+// 评估时无需每步把观察和动作在Python与Go之间往返。
+//
+//	model, err := policy.LoadModel("ppo_cartpole.onnx")
+//	result, err := policy.RunEpisodes(ctx, engine, "cartpole", config, model, 10)
+//	fmt.Println(result.MeanReturn, result.StdReturn, result.MeanLength)
+package policy
+
+import (
+	"math/rand"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// DefaultMaxSteps 评估时每回合的默认最大步数，防止不会结束的环境使评估无法返回
+const DefaultMaxSteps = 10000
+
+// Policy 根据当前观察为一步仿真选择动作，每个观察对应一个动作
+type Policy interface {
+	Act(observations []core.Observation) ([]core.Action, error)
+}
+
+// Func 将函数适配为Policy
+type Func func(observations []core.Observation) ([]core.Action, error)
+
+// Act 调用f
+func (f Func) Act(observations []core.Observation) ([]core.Action, error) {
+	return f(observations)
+}
+
+// Random 返回在env的动作空间中均匀随机采样动作的策略（见core.SampleActions，遵守动作掩码），作为评估的基线
+func Random(env core.Environment, rng *rand.Rand) Policy {
+	space := env.GetSpaces().ActionSpace
+	return Func(func(observations []core.Observation) ([]core.Action, error) {
+		return core.SampleActions(env, space, len(observations), rng)
+	})
+}
@@ -0,0 +1,234 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// TerminationReason 回合结束的原因
+type TerminationReason string
+
+const (
+	// EpisodeTerminated 所有智能体都到达终止状态
+	EpisodeTerminated TerminationReason = "terminated"
+	// EpisodeTruncated 场景截断了回合（MaxEpisodeSteps或info["truncated"]）
+	EpisodeTruncated TerminationReason = "truncated"
+	// EpisodeMaxSteps 回合在自行结束前达到RunOptions.MaxSteps
+	EpisodeMaxSteps TerminationReason = "max_steps"
+)
+
+// RunOptions Run的配置
+type RunOptions struct {
+	// Episodes 运行的回合数，<=0时为1
+	Episodes int
+	// MaxSteps 每回合的最大步数，<=0时为DefaultMaxSteps，防止不会结束的场景使运行无法返回
+	MaxSteps int
+	// OnStep 非nil时在每步之后调用，返回错误时停止运行
+	OnStep func(step StepInfo) error
+	// OnEpisode 非nil时在每个回合之后调用，返回错误时停止运行
+	OnEpisode func(episode EpisodeResult) error
+}
+
+// StepInfo Run的一步。Observations为这一步返回的观察，池化的观察在OnStep与下一次选择动作之后归还
+// （见core.ReleaseObservations），需要在OnStep之外保留时应复制
+type StepInfo struct {
+	Episode      int
+	Step         int // 回合的第一步为1
+	Actions      []core.Action
+	Observations []core.Observation
+	Rewards      []float64
+	Dones        []bool
+}
+
+// EpisodeResult Run的一个回合
+type EpisodeResult struct {
+	Episode      int
+	Return       float64   // 所有智能体的奖励之和
+	AgentReturns []float64 // 各智能体的奖励之和
+	Length       int       // 步数
+	Reason       TerminationReason
+}
+
+// RunResult Run的全部回合
+type RunResult struct {
+	Episodes []EpisodeResult
+	Steps    int           // 总步数
+	Elapsed  time.Duration // 运行耗时
+}
+
+// Returns 返回各回合的回报
+func (r *RunResult) Returns() []float64 {
+	returns := make([]float64, len(r.Episodes))
+	for i, e := range r.Episodes {
+		returns[i] = e.Return
+	}
+	return returns
+}
+
+// Lengths 返回各回合的长度
+func (r *RunResult) Lengths() []int {
+	lengths := make([]int, len(r.Episodes))
+	for i, e := range r.Episodes {
+		lengths[i] = e.Length
+	}
+	return lengths
+}
+
+// MeanReturn 回报的平均值
+func (r *RunResult) MeanReturn() float64 {
+	return mean(r.Returns())
+}
+
+// StdReturn 回报的样本标准差，与Evaluation.StdReturn相同，少于两个回合时为0
+func (r *RunResult) StdReturn() float64 {
+	return sampleStd(r.Returns())
+}
+
+// MeanLength 回合长度的平均值
+func (r *RunResult) MeanLength() float64 {
+	if len(r.Episodes) == 0 {
+		return 0
+	}
+	return float64(r.Steps) / float64(len(r.Episodes))
+}
+
+// Count 返回因reason结束的回合数
+func (r *RunResult) Count(reason TerminationReason) int {
+	n := 0
+	for _, e := range r.Episodes {
+		if e.Reason == reason {
+			n++
+		}
+	}
+	return n
+}
+
+// Run 用策略p在env中运行回合，回合在所有智能体结束或达到opts.MaxSteps步时结束，env不会被关闭。
+// 观察在p与OnStep使用后归还对象池。某一步或回调失败时返回已完成的回合及该错误
+func Run(ctx context.Context, env core.Environment, p Policy, opts RunOptions) (*RunResult, error) {
+	episodes := opts.Episodes
+	if episodes <= 0 {
+		episodes = 1
+	}
+	maxSteps := opts.MaxSteps
+	if maxSteps <= 0 {
+		maxSteps = DefaultMaxSteps
+	}
+
+	result := &RunResult{Episodes: make([]EpisodeResult, 0, episodes)}
+	truncation := core.NewTruncationTracker(env)
+	start := time.Now()
+	defer func() { result.Elapsed = time.Since(start) }()
+	// 当前持有的观察，提前返回时由此处归还
+	var observations []core.Observation
+	defer func() { core.ReleaseObservations(observations) }()
+
+	for episode := 0; episode < episodes; episode++ {
+		var err error
+		observations, err = env.Reset(ctx)
+		if err != nil {
+			return result, fmt.Errorf("failed to reset environment at episode %d: %w", episode, err)
+		}
+		truncation.Reset()
+
+		current := EpisodeResult{Episode: episode, AgentReturns: make([]float64, len(observations)), Reason: EpisodeMaxSteps}
+		for current.Length < maxSteps {
+			if err := ctx.Err(); err != nil {
+				return result, err
+			}
+			actions, err := p.Act(observations)
+			if err != nil {
+				return result, fmt.Errorf("failed to choose actions at episode %d, step %d: %w", episode, current.Length, err)
+			}
+			next, rewards, dones, err := env.Step(ctx, actions)
+			if err != nil {
+				return result, fmt.Errorf("failed to step environment at episode %d, step %d: %w", episode, current.Length, err)
+			}
+			// 策略与OnStep已使用上一步的观察
+			core.ReleaseObservations(observations)
+			observations = next
+			current.Length++
+			result.Steps++
+			for i, r := range rewards {
+				current.Return += r
+				if i < len(current.AgentReturns) {
+					current.AgentReturns[i] += r
+				}
+			}
+
+			if opts.OnStep != nil {
+				info := StepInfo{Episode: episode, Step: current.Length, Actions: actions, Observations: observations, Rewards: rewards, Dones: dones}
+				if err := opts.OnStep(info); err != nil {
+					return result, err
+				}
+			}
+			if _, truncated := truncation.Step(dones, env.GetInfo()); core.AllDone(dones) {
+				current.Reason = EpisodeTerminated
+				if anyTrue(truncated) {
+					current.Reason = EpisodeTruncated
+				}
+				break
+			}
+		}
+		core.ReleaseObservations(observations)
+		observations = nil
+
+		result.Episodes = append(result.Episodes, current)
+		if opts.OnEpisode != nil {
+			if err := opts.OnEpisode(current); err != nil {
+				return result, err
+			}
+		}
+	}
+	return result, nil
+}
+
+// CurrentPlayer 返回只把当前行动方的观察交给p的策略；env不是回合制环境时原样返回p
+func CurrentPlayer(env core.Environment, p Policy) Policy {
+	turnBased, ok := env.(core.TurnBasedEnvironment)
+	if !ok {
+		return p
+	}
+	return Func(func(observations []core.Observation) ([]core.Action, error) {
+		if player := turnBased.CurrentPlayer(); player >= 0 && player < len(observations) {
+			observations = observations[player : player+1]
+		}
+		return p.Act(observations)
+	})
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// sampleStd 样本标准差（除以n-1），少于两个值时为0
+func sampleStd(values []float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+	m, sum := mean(values), 0.0
+	for _, v := range values {
+		sum += (v - m) * (v - m)
+	}
+	return math.Sqrt(sum / float64(len(values)-1))
+}
+
+func anyTrue(values []bool) bool {
+	for _, v := range values {
+		if v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,161 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// countingObservation 记录被归还次数的观察
+type countingObservation struct {
+	core.Observation
+	released *int
+}
+
+func (o countingObservation) Release() { *o.released++ }
+
+// scriptedEnv 单智能体测试环境：每步奖励为动作值，回合在lengths中对应的步数结束，
+// truncate为true的回合通过info["truncated"]截断。记录发出与归还的观察数
+type scriptedEnv struct {
+	lengths  []int
+	truncate []bool
+	episode  int
+	step     int
+	issued   int
+	released int
+	info     map[string]interface{}
+}
+
+func (e *scriptedEnv) observe() []core.Observation {
+	e.issued++
+	return []core.Observation{countingObservation{core.NewBaseObservation([]float64{float64(e.step)}, nil), &e.released}}
+}
+
+func (e *scriptedEnv) Reset(ctx context.Context) ([]core.Observation, error) {
+	e.step, e.info = 0, map[string]interface{}{}
+	return e.observe(), nil
+}
+
+func (e *scriptedEnv) Step(ctx context.Context, actions []core.Action) ([]core.Observation, []float64, []bool, error) {
+	e.step++
+	reward, _ := actions[0].GetData().(float64)
+	done := false
+	e.info = map[string]interface{}{}
+	if e.episode < len(e.lengths) && e.step >= e.lengths[e.episode] {
+		done = true
+		if e.episode < len(e.truncate) && e.truncate[e.episode] {
+			e.info["truncated"] = true
+		} else {
+			e.info["is_success"] = e.episode%2 == 0
+		}
+		e.episode++
+	}
+	return e.observe(), []float64{reward}, []bool{done}, nil
+}
+
+func (e *scriptedEnv) GetObservations() []core.Observation { return nil }
+func (e *scriptedEnv) GetReward() []float64                { return nil }
+func (e *scriptedEnv) GetInfo() map[string]interface{}     { return e.info }
+func (e *scriptedEnv) GetSpaces() core.SpaceDefinition     { return core.SpaceDefinition{} }
+func (e *scriptedEnv) Close() error                        { return nil }
+
+// constant 每步给出动作值v的策略
+func constant(v float64) Policy {
+	return Func(func(observations []core.Observation) ([]core.Action, error) {
+		return []core.Action{core.NewGenericAction(v)}, nil
+	})
+}
+
+func TestRun(t *testing.T) {
+	// 第三个回合不会自行结束，在MaxSteps处停止
+	env := &scriptedEnv{lengths: []int{3, 2, 100}, truncate: []bool{false, true}}
+	var steps, episodes int
+	result, err := Run(context.Background(), env, constant(2), RunOptions{
+		Episodes: 3,
+		MaxSteps: 5,
+		OnStep:   func(StepInfo) error { steps++; return nil },
+		OnEpisode: func(EpisodeResult) error {
+			episodes++
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{3, 2, 5}; !reflect.DeepEqual(result.Lengths(), want) {
+		t.Errorf("Lengths = %v, want %v", result.Lengths(), want)
+	}
+	if want := []float64{6, 4, 10}; !reflect.DeepEqual(result.Returns(), want) {
+		t.Errorf("Returns = %v, want %v", result.Returns(), want)
+	}
+	reasons := []TerminationReason{result.Episodes[0].Reason, result.Episodes[1].Reason, result.Episodes[2].Reason}
+	if want := []TerminationReason{EpisodeTerminated, EpisodeTruncated, EpisodeMaxSteps}; !reflect.DeepEqual(reasons, want) {
+		t.Errorf("reasons = %v, want %v", reasons, want)
+	}
+	if result.Steps != 10 || steps != 10 || episodes != 3 {
+		t.Errorf("Steps = %d, OnStep calls %d, OnEpisode calls %d, want 10, 10 and 3", result.Steps, steps, episodes)
+	}
+	if got, want := result.StdReturn(), math.Sqrt(28.0/3); math.Abs(got-want) > 1e-12 {
+		t.Errorf("StdReturn = %v, want the sample std %v", got, want)
+	}
+	if env.released != env.issued {
+		t.Errorf("%d of %d observations released", env.released, env.issued)
+	}
+}
+
+func TestRunCallbackError(t *testing.T) {
+	env := &scriptedEnv{lengths: []int{2, 2}}
+	stop := errors.New("stop")
+	result, err := Run(context.Background(), env, constant(1), RunOptions{
+		Episodes: 2,
+		OnStep: func(step StepInfo) error {
+			if step.Episode == 1 {
+				return stop
+			}
+			return nil
+		},
+	})
+	if !errors.Is(err, stop) {
+		t.Fatalf("Run = %v, want the OnStep error", err)
+	}
+	if len(result.Episodes) != 1 {
+		t.Errorf("%d episodes returned, want the completed one", len(result.Episodes))
+	}
+	if env.released != env.issued {
+		t.Errorf("%d of %d observations released after an early return", env.released, env.issued)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	env := &scriptedEnv{lengths: []int{1, 2, 3, 4}, truncate: []bool{false, false, false, true}}
+	evaluation, err := Evaluate(context.Background(), env, constant(1), 4, EvalOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if evaluation.MeanReturn != 2.5 || evaluation.MeanLength != 2.5 || evaluation.Steps != 10 {
+		t.Errorf("mean return %v, mean length %v, steps %d", evaluation.MeanReturn, evaluation.MeanLength, evaluation.Steps)
+	}
+	if !(evaluation.ReturnLow < evaluation.MeanReturn && evaluation.MeanReturn < evaluation.ReturnHigh) {
+		t.Errorf("confidence interval [%v, %v] does not contain the mean", evaluation.ReturnLow, evaluation.ReturnHigh)
+	}
+	// 截断的回合没有is_success，不参与成功率
+	if evaluation.Judged != 3 || math.Abs(evaluation.SuccessRate-2.0/3) > 1e-12 {
+		t.Errorf("judged %d, success rate %v, want 3 and 2/3", evaluation.Judged, evaluation.SuccessRate)
+	}
+	if evaluation.Count(EpisodeTruncated) != 1 {
+		t.Errorf("%d truncated episodes, want 1", evaluation.Count(EpisodeTruncated))
+	}
+}
+
+func TestStudentT(t *testing.T) {
+	// 双侧95%临界值
+	for df, want := range map[int]float64{1: 12.7062, 2: 4.3027, 5: 2.5706, 10: 2.2281, 30: 2.0423} {
+		if got := studentT(0.95, df); math.Abs(got-want) > 1e-4 {
+			t.Errorf("studentT(0.95, %d) = %v, want %v", df, got, want)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package core
+
+import "sync"
+
+// ObservationReleaser 可选实现：观察用完后归还对象池以便复用
+type ObservationReleaser interface {
+	Release()
+}
+
+var observationPool = sync.Pool{
+	New: func() interface{} { return &BaseObservation{} },
+}
+
+// AcquireObservation 从对象池取出一个观察，并将data复制到它可复用的数据缓冲区中，
+// 因此调用方可以传入栈上的临时切片。高频步进的场景用它代替NewBaseObservation以减少每步的内存分配。
+// 与NewBaseObservation不同，metadata为nil时不会分配空map，GetMetadata返回nil（可安全读取，不可写入）。
+//
+// 观察的使用方（服务端、pybridge等）在把数据复制或发送出去之后调用Release（或ReleaseObservations）归还；
+// 归还后不得再访问该观察及其GetData返回的切片。不调用Release也是安全的，观察会像普通对象一样被回收
+func AcquireObservation(data []float64, metadata map[string]interface{}) *BaseObservation {
+	o := observationPool.Get().(*BaseObservation)
+	o.data = append(o.data[:0], data...)
+	o.isFloat32 = false
+	o.metadata = metadata
+	o.pooled = true
+	return o
+}
+
+// AcquireObservation32 与AcquireObservation相同，但将data转换为float32存储，见DtypeFloat32
+func AcquireObservation32(data []float64, metadata map[string]interface{}) *BaseObservation {
+	o := observationPool.Get().(*BaseObservation)
+	o.data32 = o.data32[:0]
+	for _, v := range data {
+		o.data32 = append(o.data32, float32(v))
+	}
+	o.isFloat32 = true
+	o.metadata = metadata
+	o.pooled = true
+	return o
+}
+
+// Release 将由AcquireObservation取得的观察归还对象池，对其他观察无效果
+func (o *BaseObservation) Release() {
+	if !o.pooled {
+		return
+	}
+	o.pooled = false
+	o.metadata = nil
+	observationPool.Put(o)
+}
+
+// ReleaseObservations 归还所有实现了ObservationReleaser的观察
+func ReleaseObservations(observations []Observation) {
+	for _, obs := range observations {
+		if releaser, ok := obs.(ObservationReleaser); ok {
+			releaser.Release()
+		}
+	}
+}
+
+// ResetBuffer 返回长度为n、元素全为0的切片，并尽量复用*buf的底层数组。
+// 场景可以用它在每步之间复用观察数据缓冲区，再交给AcquireObservation复制
+func ResetBuffer(buf *[]float64, n int) []float64 {
+	if cap(*buf) < n {
+		*buf = make([]float64, n)
+		return *buf
+	}
+	*buf = (*buf)[:n]
+	clear(*buf)
+	return *buf
+}
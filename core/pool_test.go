@@ -0,0 +1,85 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAcquireObservationCopiesData(t *testing.T) {
+	data := []float64{1, 2, 3}
+	o := AcquireObservation(data, nil)
+	data[0] = 9
+	if got := o.GetData(); !reflect.DeepEqual(got, []float64{1, 2, 3}) {
+		t.Errorf("GetData = %v, want a copy of the data at acquire time", got)
+	}
+	if o.GetMetadata() != nil {
+		t.Errorf("GetMetadata = %v, want nil without metadata", o.GetMetadata())
+	}
+	if o.GetData32() != nil {
+		t.Error("GetData32 of a float64 observation is not nil")
+	}
+	o.Release()
+}
+
+func TestAcquireObservation32(t *testing.T) {
+	o := AcquireObservation32([]float64{0.5, -1.25}, map[string]interface{}{"k": 1})
+	defer o.Release()
+	if got := o.GetData32(); !reflect.DeepEqual(got, []float32{0.5, -1.25}) {
+		t.Errorf("GetData32 = %v", got)
+	}
+	if got := o.GetData(); !reflect.DeepEqual(got, []float64{0.5, -1.25}) {
+		t.Errorf("GetData = %v", got)
+	}
+	if o.GetMetadata()["k"] != 1 {
+		t.Errorf("GetMetadata = %v", o.GetMetadata())
+	}
+}
+
+func TestReleaseObservations(t *testing.T) {
+	pooled := AcquireObservation([]float64{1}, map[string]interface{}{"k": 1})
+	plain := NewBaseObservation([]float64{2}, map[string]interface{}{"k": 2})
+	ReleaseObservations([]Observation{pooled, plain, nil})
+
+	if pooled.pooled || pooled.metadata != nil {
+		t.Error("a released observation still holds its pool state or metadata")
+	}
+	// 只有由对象池取得的观察会被归还，普通观察保持可用
+	if plain.GetMetadata()["k"] != 2 || plain.GetData()[0] != 2 {
+		t.Error("ReleaseObservations changed an observation that did not come from the pool")
+	}
+	// 重复归还无效果，不会把同一个观察两次放回对象池
+	pooled.Release()
+}
+
+func TestAcquireReleaseAllocs(t *testing.T) {
+	data := make([]float64, 64)
+	AcquireObservation(data, nil).Release()
+	allocs := testing.AllocsPerRun(100, func() {
+		AcquireObservation(data, nil).Release()
+	})
+	if allocs != 0 {
+		t.Errorf("Acquire and Release allocate %v times, want 0 once the pool is warm", allocs)
+	}
+}
+
+func TestResetBuffer(t *testing.T) {
+	var buf []float64
+	first := ResetBuffer(&buf, 4)
+	if len(first) != 4 || &buf[0] != &first[0] {
+		t.Fatalf("ResetBuffer(4) = %v, buf %v", first, buf)
+	}
+	first[0], first[3] = 1, 2
+
+	second := ResetBuffer(&buf, 3)
+	if !reflect.DeepEqual(second, []float64{0, 0, 0}) {
+		t.Errorf("ResetBuffer(3) = %v, want zeros", second)
+	}
+	if &second[0] != &first[0] {
+		t.Error("ResetBuffer allocated although the buffer had enough capacity")
+	}
+
+	third := ResetBuffer(&buf, 8)
+	if len(third) != 8 || len(buf) != 8 {
+		t.Errorf("ResetBuffer(8) = %v, buf %v", third, buf)
+	}
+}
@@ -0,0 +1,125 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Preset 具名环境预设，将一个名称映射到场景及其配置
+// 客户端创建环境时可用预设名代替场景名，预设配置作为默认值，请求中的配置优先
+type Preset struct {
+	Name     string                 `json:"name" yaml:"name"`
+	Scenario string                 `json:"scenario" yaml:"scenario"`
+	Config   map[string]interface{} `json:"config,omitempty" yaml:"config,omitempty"`
+}
+
+// apply 将请求配置叠加到预设配置之上
+func (p Preset) apply(config Config) (Config, error) {
+	values := make(map[string]interface{}, len(p.Config))
+	for k, v := range p.Config {
+		values[k] = v
+	}
+	if config != nil {
+		overrides, ok := config.(interface{ Values() map[string]interface{} })
+		if !ok {
+			return nil, fmt.Errorf("preset '%s': config of type %T cannot be merged", p.Name, config)
+		}
+		for k, v := range overrides.Values() {
+			values[k] = v
+		}
+	}
+	return NewBaseConfig(values), nil
+}
+
+// validatePreset 检查预设引用的场景存在、名称不与场景冲突且配置合法
+func (s *SimulationEngine) validatePreset(preset Preset) error {
+	if preset.Name == "" {
+		return fmt.Errorf("preset name is required")
+	}
+	if s.hasScenario(preset.Name) {
+		return fmt.Errorf("preset '%s' conflicts with a scenario of the same name", preset.Name)
+	}
+	scenario, err := s.GetScenario(preset.Scenario)
+	if err != nil {
+		return fmt.Errorf("preset '%s': %w", preset.Name, err)
+	}
+	if err := scenario.ValidateConfig(NewBaseConfig(preset.Config)); err != nil {
+		return fmt.Errorf("preset '%s': invalid config for scenario '%s': %w", preset.Name, preset.Scenario, err)
+	}
+	return nil
+}
+
+// RegisterPreset 注册单个预设，名称已被占用时返回错误
+func (s *SimulationEngine) RegisterPreset(preset Preset) error {
+	if err := s.validatePreset(preset); err != nil {
+		return err
+	}
+	s.presetsMu.Lock()
+	defer s.presetsMu.Unlock()
+	if _, exists := s.registered[preset.Name]; exists {
+		return fmt.Errorf("preset '%s' is already registered", preset.Name)
+	}
+	s.registered[preset.Name] = preset
+	return nil
+}
+
+// SetPresets 校验并整体替换上一次SetPresets设置的预设（用于热加载）；任一预设无效时返回错误并保留原有预设
+// RegisterPreset注册的预设不受影响，同名时SetPresets设置的优先
+// 已创建的环境不受影响，新预设只作用于之后创建的环境
+func (s *SimulationEngine) SetPresets(presets []Preset) error {
+	next := make(map[string]Preset, len(presets))
+	for _, preset := range presets {
+		if err := s.validatePreset(preset); err != nil {
+			return err
+		}
+		if _, dup := next[preset.Name]; dup {
+			return fmt.Errorf("duplicate preset '%s'", preset.Name)
+		}
+		next[preset.Name] = preset
+	}
+
+	s.presetsMu.Lock()
+	s.presets = next
+	s.presetsMu.Unlock()
+	return nil
+}
+
+// GetPreset 按名称获取预设
+func (s *SimulationEngine) GetPreset(name string) (Preset, bool) {
+	s.presetsMu.RLock()
+	defer s.presetsMu.RUnlock()
+	if preset, ok := s.presets[name]; ok {
+		return preset, true
+	}
+	preset, ok := s.registered[name]
+	return preset, ok
+}
+
+// ListPresets 按字母顺序返回全部预设名
+func (s *SimulationEngine) ListPresets() []string {
+	s.presetsMu.RLock()
+	defer s.presetsMu.RUnlock()
+	names := make([]string, 0, len(s.presets)+len(s.registered))
+	for name := range s.presets {
+		names = append(names, name)
+	}
+	for name := range s.registered {
+		if _, overridden := s.presets[name]; !overridden {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Presets 按名称顺序返回全部预设
+func (s *SimulationEngine) Presets() []Preset {
+	names := s.ListPresets()
+	presets := make([]Preset, 0, len(names))
+	for _, name := range names {
+		if preset, ok := s.GetPreset(name); ok {
+			presets = append(presets, preset)
+		}
+	}
+	return presets
+}
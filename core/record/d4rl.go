@@ -0,0 +1,170 @@
+package record
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+// Dataset D4RL风格的离线数据集，按转移（transition）展平，第i项描述 (s_i, a_i, r_i, s'_i)：
+//
+//	observations       float32[N, observation_size]
+//	actions            int64或float32[N, action_size]
+//	rewards            float32[N]
+//	next_observations  float32[N, observation_size]
+//	terminals          bool[N]  因环境自身原因结束
+//	timeouts           bool[N]  因截断或录制中途停止而结束
+type Dataset struct {
+	Observations     [][]float64
+	Actions          [][]float64
+	Rewards          []float64
+	NextObservations [][]float64
+	Terminals        []bool
+	Timeouts         []bool
+
+	ObservationSize int
+	ActionSize      int
+	ActionDtype     string // DtypeInt64或DtypeFloat32
+}
+
+// Len 返回转移数
+func (d *Dataset) Len() int {
+	return len(d.Rewards)
+}
+
+// NewDataset 将轨迹记录中指定智能体的转移整理为D4RL数据集，actionDtype的含义同RLDSOptions.ActionDtype
+func NewDataset(records []Record, agent int, actionDtype string) (*Dataset, error) {
+	set, err := collectAgentEpisodes(records, agent, actionDtype)
+	if err != nil {
+		return nil, err
+	}
+
+	dataset := &Dataset{
+		ObservationSize: set.observationSize,
+		ActionSize:      set.actionSize,
+		ActionDtype:     set.actionDtype,
+	}
+	for _, episode := range set.episodes {
+		last := len(episode.actions) - 1
+		for t, action := range episode.actions {
+			dataset.Observations = append(dataset.Observations, episode.observations[t])
+			dataset.Actions = append(dataset.Actions, action)
+			dataset.Rewards = append(dataset.Rewards, episode.rewards[t])
+			dataset.NextObservations = append(dataset.NextObservations, episode.observations[t+1])
+			dataset.Terminals = append(dataset.Terminals, t == last && episode.terminal)
+			dataset.Timeouts = append(dataset.Timeouts, t == last && !episode.terminal)
+		}
+	}
+	return dataset, nil
+}
+
+// WriteNPZ 以numpy的.npz格式（未压缩的zip，每个数组一个.npy文件）写出数据集，
+// 可直接用 numpy.load 读取，或按相同的键写入HDF5供D4RL风格的代码使用
+func (d *Dataset) WriteNPZ(w io.Writer) error {
+	n := d.Len()
+	actions := npyArray{shape: []int{n, d.ActionSize}}
+	if d.ActionDtype == DtypeInt64 {
+		actions.descr = "<i8"
+		actions.data = make([]byte, 0, n*d.ActionSize*8)
+		for _, action := range d.Actions {
+			for _, v := range action {
+				actions.data = binary.LittleEndian.AppendUint64(actions.data, uint64(int64(v)))
+			}
+		}
+	} else {
+		actions = float32Matrix(d.Actions, d.ActionSize)
+	}
+
+	arrays := []struct {
+		name  string
+		array npyArray
+	}{
+		{"observations", float32Matrix(d.Observations, d.ObservationSize)},
+		{"actions", actions},
+		{"rewards", float32Vector(d.Rewards)},
+		{"next_observations", float32Matrix(d.NextObservations, d.ObservationSize)},
+		{"terminals", boolVector(d.Terminals)},
+		{"timeouts", boolVector(d.Timeouts)},
+	}
+
+	archive := zip.NewWriter(w)
+	for _, entry := range arrays {
+		// 与numpy.savez一致，不压缩
+		file, err := archive.CreateHeader(&zip.FileHeader{Name: entry.name + ".npy", Method: zip.Store})
+		if err != nil {
+			return err
+		}
+		if err := entry.array.write(file); err != nil {
+			return fmt.Errorf("failed to write %s: %w", entry.name, err)
+		}
+	}
+	return archive.Close()
+}
+
+// npyArray 一个.npy格式的数组
+type npyArray struct {
+	descr string // numpy的dtype描述，如"<f4"
+	shape []int
+	data  []byte // 按行优先排列的小端序数据
+}
+
+// write 写出NPY 1.0格式：魔数、版本、头长度、以换行结尾并按64字节对齐的头字典，然后是数据
+func (a npyArray) write(w io.Writer) error {
+	shape := ""
+	for _, dim := range a.shape {
+		shape += fmt.Sprintf("%d, ", dim)
+	}
+	if len(a.shape) > 1 {
+		shape = shape[:len(shape)-2]
+	} else {
+		shape = shape[:len(shape)-1]
+	}
+	header := fmt.Sprintf("{'descr': '%s', 'fortran_order': False, 'shape': (%s), }", a.descr, shape)
+	const prefixLen = 10 // 魔数6字节 + 版本2字节 + 头长度2字节
+	padding := 64 - (prefixLen+len(header)+1)%64
+	if padding == 64 {
+		padding = 0
+	}
+	header += string(bytes.Repeat([]byte{' '}, padding)) + "\n"
+
+	var buf bytes.Buffer
+	buf.WriteString("\x93NUMPY\x01\x00")
+	binary.Write(&buf, binary.LittleEndian, uint16(len(header)))
+	buf.WriteString(header)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	_, err := w.Write(a.data)
+	return err
+}
+
+func float32Matrix(rows [][]float64, width int) npyArray {
+	data := make([]byte, 0, len(rows)*width*4)
+	for _, row := range rows {
+		for _, v := range row {
+			data = binary.LittleEndian.AppendUint32(data, math.Float32bits(float32(v)))
+		}
+	}
+	return npyArray{descr: "<f4", shape: []int{len(rows), width}, data: data}
+}
+
+func float32Vector(values []float64) npyArray {
+	data := make([]byte, 0, len(values)*4)
+	for _, v := range values {
+		data = binary.LittleEndian.AppendUint32(data, math.Float32bits(float32(v)))
+	}
+	return npyArray{descr: "<f4", shape: []int{len(values)}, data: data}
+}
+
+func boolVector(values []bool) npyArray {
+	data := make([]byte, len(values))
+	for i, v := range values {
+		if v {
+			data[i] = 1
+		}
+	}
+	return npyArray{descr: "|b1", shape: []int{len(values)}, data: data}
+}
@@ -0,0 +1,460 @@
+// Package record 将环境交互轨迹以JSONL（每行一个JSON对象）格式录制下来，便于离线分析与训练。
+//
+// 每个回合以一行 reset 记录开始，之后每步一行 step 记录，所有智能体结束时追加一行 episode_end 记录：
+//
+//	{"type":"reset","episode":0,"step":0,"observations":[[0.01,0.02]],"info":{...},"spaces":{...}}
+//	{"type":"step","episode":0,"step":1,"actions":[1],"observations":[[0.03,0.01]],"rewards":[1],"terminated":[false],"truncated":[false],"info":{...}}
+//	{"type":"episode_end","episode":0,"step":1,"returns":[1]}
+//
+// step 记录中的 observations 是执行动作后的观察。录制开始后的第一条 reset 记录附带环境的动作/观察空间。
+// 非有限的浮点数（NaN、±Inf）编码为null。
+package record
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// ConfigKey 创建环境时配置中的该键指定录制文件路径，服务端会据此自动开启录制
+const ConfigKey = "record_path"
+
+// 记录类型
+const (
+	TypeReset      = "reset"
+	TypeStep       = "step"
+	TypeEpisodeEnd = "episode_end"
+)
+
+// Floats 可以编码非有限值的浮点数组：NaN和±Inf编码为null，解码时null还原为NaN
+type Floats []float64
+
+// MarshalJSON 将非有限值编码为null
+func (f Floats) MarshalJSON() ([]byte, error) {
+	if f == nil {
+		return []byte("null"), nil
+	}
+	values := make([]interface{}, len(f))
+	for i, v := range f {
+		if !math.IsNaN(v) && !math.IsInf(v, 0) {
+			values[i] = v
+		}
+	}
+	return json.Marshal(values)
+}
+
+// UnmarshalJSON 将null还原为NaN
+func (f *Floats) UnmarshalJSON(data []byte) error {
+	var values []*float64
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	if values == nil {
+		*f = nil
+		return nil
+	}
+	*f = make(Floats, len(values))
+	for i, v := range values {
+		if v == nil {
+			(*f)[i] = math.NaN()
+		} else {
+			(*f)[i] = *v
+		}
+	}
+	return nil
+}
+
+// Space 录制的空间定义，无界的边界编码为null
+type Space struct {
+	Type           core.SpaceType `json:"type"`
+	Low            Floats         `json:"low,omitempty"`
+	High           Floats         `json:"high,omitempty"`
+	Shape          []int32        `json:"shape,omitempty"`
+	Dtype          string         `json:"dtype,omitempty"`
+	DiscreteValues Floats         `json:"discrete_values,omitempty"`
+}
+
+// Spaces 录制的动作空间和观察空间
+type Spaces struct {
+	Action      Space `json:"action"`
+	Observation Space `json:"observation"`
+}
+
+func newSpaces(def core.SpaceDefinition) *Spaces {
+	return &Spaces{
+		Action: Space{
+			Type:           def.ActionSpace.Type,
+			Low:            def.ActionSpace.Low,
+			High:           def.ActionSpace.High,
+			Shape:          def.ActionSpace.Shape,
+			Dtype:          def.ActionSpace.Dtype,
+			DiscreteValues: def.ActionSpace.DiscreteValues,
+		},
+		Observation: Space{
+			Type:  def.ObservationSpace.Type,
+			Low:   def.ObservationSpace.Low,
+			High:  def.ObservationSpace.High,
+			Shape: def.ObservationSpace.Shape,
+			Dtype: def.ObservationSpace.Dtype,
+		},
+	}
+}
+
+// Definition 还原为core.SpaceDefinition，编码为null的下界和上界分别还原为-Inf和+Inf
+func (s *Spaces) Definition() core.SpaceDefinition {
+	return core.SpaceDefinition{
+		ActionSpace: core.ActionSpace{
+			Type:           s.Action.Type,
+			Low:            bounds(s.Action.Low, -1),
+			High:           bounds(s.Action.High, 1),
+			Shape:          s.Action.Shape,
+			Dtype:          s.Action.Dtype,
+			DiscreteValues: s.Action.DiscreteValues,
+		},
+		ObservationSpace: core.ObservationSpace{
+			Type:  s.Observation.Type,
+			Low:   bounds(s.Observation.Low, -1),
+			High:  bounds(s.Observation.High, 1),
+			Shape: s.Observation.Shape,
+			Dtype: s.Observation.Dtype,
+		},
+	}
+}
+
+// bounds 将NaN还原为指定符号的无穷大
+func bounds(values Floats, sign int) []float64 {
+	if values == nil {
+		return nil
+	}
+	restored := make([]float64, len(values))
+	for i, v := range values {
+		if math.IsNaN(v) {
+			v = math.Inf(sign)
+		}
+		restored[i] = v
+	}
+	return restored
+}
+
+// Record 轨迹文件中的一行
+type Record struct {
+	Type         string                 `json:"type"`
+	Episode      int                    `json:"episode"`
+	Step         int                    `json:"step"`                   // 回合内已执行的步数
+	Actions      []interface{}          `json:"actions,omitempty"`      // 各动作的GetData()
+	Observations []Floats               `json:"observations,omitempty"` // reset后或执行动作后的观察
+	Rewards      Floats                 `json:"rewards,omitempty"`
+	Terminated   []bool                 `json:"terminated,omitempty"` // 因环境自身原因结束
+	Truncated    []bool                 `json:"truncated,omitempty"`  // 因达到最大步数而截断
+	Returns      Floats                 `json:"returns,omitempty"`    // episode_end：各智能体的回合累计奖励
+	Info         map[string]interface{} `json:"info,omitempty"`
+	Spaces       *Spaces                `json:"spaces,omitempty"` // 仅录制开始后的第一条reset记录
+}
+
+// Recorder 包装一个环境，将每次Reset和Step写入JSONL轨迹，自身也实现core.Environment
+type Recorder struct {
+	env      core.Environment
+	maxSteps int
+
+	mu      sync.Mutex
+	writer  *bufio.Writer
+	closer  io.Closer
+	episode int // 当前回合序号，尚未Reset时为-1
+	step    int
+	returns []float64
+	ended   bool
+	started bool // 是否已写入带空间定义的reset记录
+}
+
+var (
+	_ core.Environment      = (*Recorder)(nil)
+	_ core.MetadataProvider = (*Recorder)(nil)
+)
+
+// New 创建写入w的录制器；w实现io.Closer时会在Stop或Close时关闭
+func New(env core.Environment, w io.Writer) *Recorder {
+	r := &Recorder{
+		env:      env,
+		maxSteps: core.GetEnvMetadata(env).MaxEpisodeSteps,
+		writer:   bufio.NewWriter(w),
+		episode:  -1,
+	}
+	if closer, ok := w.(io.Closer); ok {
+		r.closer = closer
+	}
+	return r
+}
+
+// NewFile 创建写入指定文件的录制器，必要时创建父目录，已存在的文件会被覆盖
+func NewFile(env core.Environment, path string) (*Recorder, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create record directory: %w", err)
+		}
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create record file: %w", err)
+	}
+	return New(env, file), nil
+}
+
+// FromConfig 当配置中设置了ConfigKey时返回录制该环境的Recorder，否则原样返回env
+func FromConfig(env core.Environment, config core.Config) (core.Environment, error) {
+	if config == nil {
+		return env, nil
+	}
+	path, ok, err := config.GetString(ConfigKey)
+	if err != nil {
+		return nil, err
+	}
+	if !ok || path == "" {
+		return env, nil
+	}
+	return NewFile(env, path)
+}
+
+// Unwrap 返回被录制的环境
+func (r *Recorder) Unwrap() core.Environment {
+	return r.env
+}
+
+func (r *Recorder) Reset(ctx context.Context) ([]core.Observation, error) {
+	observations, err := r.env.Reset(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.episode++
+	r.step = 0
+	r.returns = make([]float64, len(observations))
+	r.ended = false
+	reset := Record{
+		Type:         TypeReset,
+		Episode:      r.episode,
+		Observations: observationData(observations),
+		Info:         r.env.GetInfo(),
+	}
+	if !r.started {
+		reset.Spaces = newSpaces(r.env.GetSpaces())
+		r.started = true
+	}
+	if err := r.write(reset); err != nil {
+		return nil, err
+	}
+	return observations, nil
+}
+
+func (r *Recorder) Step(ctx context.Context, actions []core.Action) ([]core.Observation, []float64, []bool, error) {
+	observations, rewards, dones, err := r.env.Step(ctx, actions)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.episode < 0 {
+		// 未经Reset直接Step的环境同样记录，视为第0回合
+		r.episode = 0
+	}
+	r.step++
+
+	info := r.env.GetInfo()
+	truncatedByInfo, _ := info["truncated"].(bool)
+	terminated := make([]bool, len(dones))
+	truncated := make([]bool, len(dones))
+	for i, done := range dones {
+		truncated[i] = done && (truncatedByInfo || (r.maxSteps > 0 && r.step >= r.maxSteps))
+		terminated[i] = done && !truncated[i]
+	}
+
+	actionData := make([]interface{}, len(actions))
+	for i, action := range actions {
+		actionData[i] = action.GetData()
+	}
+
+	if err := r.write(Record{
+		Type:         TypeStep,
+		Episode:      r.episode,
+		Step:         r.step,
+		Actions:      actionData,
+		Observations: observationData(observations),
+		Rewards:      rewards,
+		Terminated:   terminated,
+		Truncated:    truncated,
+		Info:         info,
+	}); err != nil {
+		return nil, nil, nil, err
+	}
+
+	for i, reward := range rewards {
+		if i >= len(r.returns) {
+			r.returns = append(r.returns, 0)
+		}
+		r.returns[i] += reward
+	}
+	if !r.ended && core.AllDone(dones) {
+		r.ended = true
+		if err := r.write(Record{
+			Type:    TypeEpisodeEnd,
+			Episode: r.episode,
+			Step:    r.step,
+			Returns: append(Floats(nil), r.returns...),
+		}); err != nil {
+			return nil, nil, nil, err
+		}
+		// 每个回合结束时落盘，便于录制过程中查看已完成的回合
+		if r.writer != nil {
+			if err := r.writer.Flush(); err != nil {
+				return nil, nil, nil, fmt.Errorf("failed to flush records: %w", err)
+			}
+		}
+	}
+	return observations, rewards, dones, nil
+}
+
+func (r *Recorder) GetObservations() []core.Observation { return r.env.GetObservations() }
+func (r *Recorder) GetReward() []float64                { return r.env.GetReward() }
+func (r *Recorder) GetInfo() map[string]interface{}     { return r.env.GetInfo() }
+func (r *Recorder) GetSpaces() core.SpaceDefinition     { return r.env.GetSpaces() }
+
+// Metadata 返回被录制环境的元数据
+func (r *Recorder) Metadata() core.EnvMetadata {
+	return core.GetEnvMetadata(r.env)
+}
+
+// Flush 将缓冲的记录写入底层Writer
+func (r *Recorder) Flush() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.writer == nil {
+		return nil
+	}
+	return r.writer.Flush()
+}
+
+// Stop 停止录制并关闭轨迹文件，不关闭环境，可通过Unwrap继续使用
+func (r *Recorder) Stop() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.writer == nil {
+		return nil
+	}
+	err := r.writer.Flush()
+	if r.closer != nil {
+		if closeErr := r.closer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	r.writer, r.closer = nil, nil
+	return err
+}
+
+// Close 停止录制并关闭环境
+func (r *Recorder) Close() error {
+	stopErr := r.Stop()
+	if err := r.env.Close(); err != nil {
+		return err
+	}
+	return stopErr
+}
+
+// write 写入一行记录，录制已停止时忽略
+func (r *Recorder) write(record Record) error {
+	if r.writer == nil {
+		return nil
+	}
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s record: %w", record.Type, err)
+	}
+	line = append(line, '\n')
+	if _, err := r.writer.Write(line); err != nil {
+		return fmt.Errorf("failed to write %s record: %w", record.Type, err)
+	}
+	return nil
+}
+
+// Episode 按回合分组的记录
+type Episode struct {
+	Index   int
+	Reset   Record   // 回合开始时的reset记录
+	Steps   []Record // 按顺序排列的step记录
+	Returns Floats   // 各智能体的累计奖励，回合未结束（录制中途停止）时为nil
+}
+
+// Done 报告回合是否正常结束
+func (e Episode) Done() bool {
+	return e.Returns != nil
+}
+
+// Episodes 将记录按回合分组，缺少reset记录的回合（未经Reset直接Step）会被跳过
+func Episodes(records []Record) []Episode {
+	var episodes []Episode
+	var current *Episode
+	for _, record := range records {
+		switch record.Type {
+		case TypeReset:
+			episodes = append(episodes, Episode{Index: record.Episode, Reset: record})
+			current = &episodes[len(episodes)-1]
+		case TypeStep:
+			if current != nil && current.Index == record.Episode {
+				current.Steps = append(current.Steps, record)
+			}
+		case TypeEpisodeEnd:
+			if current != nil && current.Index == record.Episode {
+				current.Returns = record.Returns
+			}
+		}
+	}
+	return episodes
+}
+
+// Read 逐行读取轨迹记录
+func Read(reader io.Reader) ([]Record, error) {
+	var records []Record
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 64<<20)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// ReadFile 读取轨迹文件中的全部记录
+func ReadFile(path string) ([]Record, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return Read(file)
+}
+
+func observationData(observations []core.Observation) []Floats {
+	data := make([]Floats, len(observations))
+	for i, obs := range observations {
+		data[i] = obs.GetData()
+	}
+	return data
+}
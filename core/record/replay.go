@@ -0,0 +1,198 @@
+package record
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// ErrActionMismatch 校验模式下传入的动作与录制的动作不一致
+var ErrActionMismatch = errors.New("action does not match the recording")
+
+// ReplayOptions 回放选项
+type ReplayOptions struct {
+	VerifyActions bool    // 校验传入的动作与录制的动作一致，不一致时Step返回ErrActionMismatch
+	Tolerance     float64 // 校验数值动作时允许的绝对误差
+	Loop          bool    // 播放完最后一个回合后从第一个回合重新开始，否则Reset返回错误
+}
+
+// ReplayEnvironment 按顺序回放录制的轨迹的环境：每次Reset播放下一个回合，Step返回录制的观察、奖励和结束标志。
+// 默认忽略传入的动作，开启VerifyActions后可用于检查学习器或策略是否复现了录制时的动作
+type ReplayEnvironment struct {
+	episodes []Episode
+	spaces   core.SpaceDefinition
+	opts     ReplayOptions
+
+	next         int      // 下一次Reset播放的回合下标
+	current      *Episode // 正在播放的回合
+	step         int      // 当前回合已回放的步数
+	observations []core.Observation
+	rewards      []float64
+	info         map[string]interface{}
+}
+
+var _ core.Environment = (*ReplayEnvironment)(nil)
+
+// NewReplayEnvironment 由轨迹记录创建回放环境；记录中没有空间定义时，按首个观察和动作推断无界的Box空间
+func NewReplayEnvironment(records []Record, opts ReplayOptions) (*ReplayEnvironment, error) {
+	episodes := Episodes(records)
+	if len(episodes) == 0 {
+		return nil, fmt.Errorf("recording contains no episodes")
+	}
+
+	env := &ReplayEnvironment{episodes: episodes, opts: opts}
+	for _, record := range records {
+		if record.Type == TypeReset && record.Spaces != nil {
+			env.spaces = record.Spaces.Definition()
+			return env, nil
+		}
+	}
+	env.spaces = inferSpaces(episodes)
+	return env, nil
+}
+
+// OpenReplay 读取轨迹文件并创建回放环境
+func OpenReplay(path string, opts ReplayOptions) (*ReplayEnvironment, error) {
+	records, err := ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recording %s: %w", path, err)
+	}
+	return NewReplayEnvironment(records, opts)
+}
+
+// inferSpaces 按首个观察和首个动作的长度推断无界的Box空间
+func inferSpaces(episodes []Episode) core.SpaceDefinition {
+	unbounded := func(n int) ([]float64, []float64, []int32) {
+		low, high := make([]float64, n), make([]float64, n)
+		for i := range low {
+			low[i], high[i] = math.Inf(-1), math.Inf(1)
+		}
+		return low, high, []int32{int32(n)}
+	}
+
+	var spaces core.SpaceDefinition
+	if observations := episodes[0].Reset.Observations; len(observations) > 0 {
+		low, high, shape := unbounded(len(observations[0]))
+		spaces.ObservationSpace = core.ObservationSpace{Type: core.SpaceTypeBox, Low: low, High: high, Shape: shape, Dtype: "float64"}
+	}
+	for _, episode := range episodes {
+		if len(episode.Steps) > 0 && len(episode.Steps[0].Actions) > 0 {
+			if action, err := flattenAction(episode.Steps[0].Actions[0]); err == nil {
+				low, high, shape := unbounded(len(action))
+				spaces.ActionSpace = core.ActionSpace{Type: core.SpaceTypeBox, Low: low, High: high, Shape: shape, Dtype: "float64"}
+			}
+			break
+		}
+	}
+	return spaces
+}
+
+// Episodes 返回录制的回合数
+func (e *ReplayEnvironment) Episodes() int {
+	return len(e.episodes)
+}
+
+func (e *ReplayEnvironment) Reset(ctx context.Context) ([]core.Observation, error) {
+	if e.next >= len(e.episodes) {
+		if !e.opts.Loop {
+			return nil, fmt.Errorf("recording exhausted after %d episodes", len(e.episodes))
+		}
+		e.next = 0
+	}
+	e.current = &e.episodes[e.next]
+	e.next++
+	e.step = 0
+	e.observations = toObservations(e.current.Reset.Observations)
+	e.rewards = nil
+	e.info = e.replayInfo(e.current.Reset.Info)
+	return e.observations, nil
+}
+
+func (e *ReplayEnvironment) Step(ctx context.Context, actions []core.Action) ([]core.Observation, []float64, []bool, error) {
+	if e.current == nil {
+		return nil, nil, nil, fmt.Errorf("reset must be called before step")
+	}
+	if e.step >= len(e.current.Steps) {
+		return nil, nil, nil, fmt.Errorf("episode %d has no more recorded steps (%d)", e.current.Index, len(e.current.Steps))
+	}
+
+	record := e.current.Steps[e.step]
+	if e.opts.VerifyActions {
+		if err := e.verify(record, actions); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	e.step++
+
+	dones := make([]bool, len(record.Rewards))
+	for i := range dones {
+		dones[i] = (i < len(record.Terminated) && record.Terminated[i]) || (i < len(record.Truncated) && record.Truncated[i])
+	}
+	e.info = e.replayInfo(record.Info)
+	// 录制中途停止的回合在最后一步视为截断，避免学习器在回合中途遇到错误
+	if e.step == len(e.current.Steps) && !e.current.Done() {
+		for i := range dones {
+			dones[i] = true
+		}
+		e.info["truncated"] = true
+	}
+
+	e.observations = toObservations(record.Observations)
+	e.rewards = record.Rewards
+	return e.observations, e.rewards, dones, nil
+}
+
+// verify 比较传入的动作与录制的动作
+func (e *ReplayEnvironment) verify(record Record, actions []core.Action) error {
+	if len(actions) != len(record.Actions) {
+		return fmt.Errorf("%w: episode %d step %d: got %d actions, recorded %d",
+			ErrActionMismatch, e.current.Index, record.Step, len(actions), len(record.Actions))
+	}
+	for i, action := range actions {
+		got, err := flattenAction(action.GetData())
+		if err != nil {
+			return err
+		}
+		want, err := flattenAction(record.Actions[i])
+		if err != nil {
+			return err
+		}
+		match := len(got) == len(want)
+		for j := 0; match && j < len(got); j++ {
+			match = math.Abs(got[j]-want[j]) <= e.opts.Tolerance
+		}
+		if !match {
+			return fmt.Errorf("%w: episode %d step %d action %d: got %v, recorded %v",
+				ErrActionMismatch, e.current.Index, record.Step, i, got, want)
+		}
+	}
+	return nil
+}
+
+// replayInfo 复制录制的info并附加回放位置
+func (e *ReplayEnvironment) replayInfo(recorded map[string]interface{}) map[string]interface{} {
+	info := make(map[string]interface{}, len(recorded)+2)
+	for k, v := range recorded {
+		info[k] = v
+	}
+	info["replay_episode"] = e.current.Index
+	info["replay_step"] = e.step
+	return info
+}
+
+func (e *ReplayEnvironment) GetObservations() []core.Observation { return e.observations }
+func (e *ReplayEnvironment) GetReward() []float64                { return e.rewards }
+func (e *ReplayEnvironment) GetInfo() map[string]interface{}     { return e.info }
+func (e *ReplayEnvironment) GetSpaces() core.SpaceDefinition     { return e.spaces }
+func (e *ReplayEnvironment) Close() error                        { return nil }
+
+func toObservations(data []Floats) []core.Observation {
+	observations := make([]core.Observation, len(data))
+	for i, values := range data {
+		observations[i] = core.NewBaseObservation(values, nil)
+	}
+	return observations
+}
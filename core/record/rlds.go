@@ -0,0 +1,262 @@
+package record
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// RLDS 动作的数据类型
+const (
+	DtypeInt64   = "int64"
+	DtypeFloat32 = "float32"
+)
+
+// RLDSOptions RLDS导出选项
+type RLDSOptions struct {
+	Agent       int    // 导出哪个智能体的轨迹，多智能体环境每个智能体需单独导出
+	ActionDtype string // DtypeInt64或DtypeFloat32，为空时若所有动作都是整数则为int64
+}
+
+// RLDSInfo 导出结果的特征说明，用于在TF/JAX端构造解析规格
+type RLDSInfo struct {
+	Episodes        int    `json:"episodes"`
+	Steps           int    `json:"steps"` // 含每回合末尾的is_last步
+	Agent           int    `json:"agent"`
+	ObservationSize int    `json:"observation_size"`
+	ActionSize      int    `json:"action_size"`
+	ActionDtype     string `json:"action_dtype"`
+}
+
+// agentEpisode 单个智能体在一个回合中的观察、动作和奖励
+type agentEpisode struct {
+	index        int
+	observations [][]float64 // T+1个观察
+	actions      [][]float64 // T个动作
+	rewards      []float64   // T个奖励
+	terminal     bool
+}
+
+// ExportRLDS 将轨迹记录导出为RLDS格式的TFRecord，每个回合一条tf.train.Example：
+//
+//	episode_id          int64[1]
+//	steps/observation   float32[(T+1) * observation_size]
+//	steps/action        int64或float32[(T+1) * action_size]
+//	steps/reward        float32[T+1]
+//	steps/discount      float32[T+1]
+//	steps/is_first      int64[T+1]
+//	steps/is_last       int64[T+1]
+//	steps/is_terminal   int64[T+1]
+//
+// 按RLDS约定，第t步包含观察o_t、在其上执行的动作a_t以及随后获得的奖励r_t和折扣；
+// 回合末尾追加一步只含最终观察的is_last步，其动作、奖励和折扣为0。
+// 因环境自身原因结束的回合is_terminal为1，导致结束的那一步折扣为0；被截断或录制中途停止的回合is_terminal为0。
+func ExportRLDS(w io.Writer, records []Record, opts RLDSOptions) (RLDSInfo, error) {
+	info := RLDSInfo{Agent: opts.Agent}
+	set, err := collectAgentEpisodes(records, opts.Agent, opts.ActionDtype)
+	if err != nil {
+		return info, err
+	}
+	info.ObservationSize, info.ActionSize, info.ActionDtype = set.observationSize, set.actionSize, set.actionDtype
+
+	writer := NewTFRecordWriter(w)
+	for _, episode := range set.episodes {
+		features := encodeRLDSEpisode(episode, info)
+		if err := writer.Write(encodeExample(features)); err != nil {
+			return info, fmt.Errorf("failed to write episode %d: %w", episode.index, err)
+		}
+		info.Episodes++
+		info.Steps += len(episode.observations)
+	}
+	return info, nil
+}
+
+// agentEpisodeSet 单个智能体的全部回合及统一的观察/动作维度
+type agentEpisodeSet struct {
+	episodes        []agentEpisode
+	observationSize int
+	actionSize      int
+	actionDtype     string
+}
+
+// collectAgentEpisodes 提取指定智能体的所有回合，并检查各步的观察和动作维度一致；
+// actionDtype为空时若所有动作都是整数则为int64，否则为float32
+func collectAgentEpisodes(records []Record, agent int, actionDtype string) (agentEpisodeSet, error) {
+	set := agentEpisodeSet{observationSize: -1, actionSize: -1}
+	switch actionDtype {
+	case "", DtypeInt64, DtypeFloat32:
+	default:
+		return set, fmt.Errorf("unsupported action dtype %q", actionDtype)
+	}
+
+	integral := true
+	for _, episode := range Episodes(records) {
+		converted, err := toAgentEpisode(episode, agent)
+		if err != nil {
+			return set, fmt.Errorf("episode %d: %w", episode.Index, err)
+		}
+		for _, obs := range converted.observations {
+			if set.observationSize < 0 {
+				set.observationSize = len(obs)
+			} else if len(obs) != set.observationSize {
+				return set, fmt.Errorf("episode %d: observation size %d differs from %d", episode.Index, len(obs), set.observationSize)
+			}
+		}
+		for _, action := range converted.actions {
+			if set.actionSize < 0 {
+				set.actionSize = len(action)
+			} else if len(action) != set.actionSize {
+				return set, fmt.Errorf("episode %d: action size %d differs from %d", episode.Index, len(action), set.actionSize)
+			}
+			for _, v := range action {
+				if v != math.Trunc(v) {
+					integral = false
+				}
+			}
+		}
+		set.episodes = append(set.episodes, converted)
+	}
+	set.observationSize = max(set.observationSize, 0)
+	set.actionSize = max(set.actionSize, 0)
+	set.actionDtype = actionDtype
+	if set.actionDtype == "" {
+		set.actionDtype = DtypeFloat32
+		if integral {
+			set.actionDtype = DtypeInt64
+		}
+	}
+	return set, nil
+}
+
+// toAgentEpisode 提取指定智能体在一个回合中的观察、动作和奖励
+func toAgentEpisode(episode Episode, agent int) (agentEpisode, error) {
+	converted := agentEpisode{index: episode.Index}
+	if agent < 0 || agent >= len(episode.Reset.Observations) {
+		return converted, fmt.Errorf("agent %d out of range (%d agents)", agent, len(episode.Reset.Observations))
+	}
+	converted.observations = append(converted.observations, episode.Reset.Observations[agent])
+
+	for _, step := range episode.Steps {
+		if agent >= len(step.Observations) || agent >= len(step.Rewards) {
+			return converted, fmt.Errorf("step %d has no data for agent %d", step.Step, agent)
+		}
+		// 回合制环境每步只有一个动作，由当前行动方提交
+		actionIndex := agent
+		if len(step.Actions) == 1 {
+			actionIndex = 0
+		}
+		if actionIndex >= len(step.Actions) {
+			return converted, fmt.Errorf("step %d has no action for agent %d", step.Step, agent)
+		}
+		action, err := flattenAction(step.Actions[actionIndex])
+		if err != nil {
+			return converted, fmt.Errorf("step %d: %w", step.Step, err)
+		}
+
+		converted.actions = append(converted.actions, action)
+		converted.rewards = append(converted.rewards, step.Rewards[agent])
+		converted.observations = append(converted.observations, step.Observations[agent])
+		if agent < len(step.Terminated) && step.Terminated[agent] {
+			converted.terminal = true
+			break
+		}
+		if agent < len(step.Truncated) && step.Truncated[agent] {
+			break
+		}
+	}
+	return converted, nil
+}
+
+// flattenAction 将动作数据展开为数值数组，布尔值按0/1处理
+func flattenAction(data interface{}) ([]float64, error) {
+	switch v := data.(type) {
+	case nil:
+		return nil, fmt.Errorf("missing action")
+	case bool:
+		if v {
+			return []float64{1}, nil
+		}
+		return []float64{0}, nil
+	case []float64:
+		return v, nil
+	case []int:
+		values := make([]float64, len(v))
+		for i, n := range v {
+			values[i] = float64(n)
+		}
+		return values, nil
+	case []interface{}:
+		var values []float64
+		for _, item := range v {
+			nested, err := flattenAction(item)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, nested...)
+		}
+		return values, nil
+	}
+	value, err := core.ToFloat64(data)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported action %v: %w", data, err)
+	}
+	return []float64{value}, nil
+}
+
+// encodeRLDSEpisode 将回合展开为RLDS的steps特征
+func encodeRLDSEpisode(episode agentEpisode, info RLDSInfo) map[string]Feature {
+	n := len(episode.observations)
+	observations := make([]float32, 0, n*info.ObservationSize)
+	rewards := make([]float32, n)
+	discounts := make([]float32, n)
+	isFirst := make([]int64, n)
+	isLast := make([]int64, n)
+	isTerminal := make([]int64, n)
+	actionFloats := make([]float32, 0, n*info.ActionSize)
+	actionInts := make([]int64, 0, n*info.ActionSize)
+
+	for t, obs := range episode.observations {
+		for _, v := range obs {
+			observations = append(observations, float32(v))
+		}
+		if t < len(episode.actions) {
+			rewards[t] = float32(episode.rewards[t])
+			discounts[t] = 1
+			for _, v := range episode.actions[t] {
+				actionFloats = append(actionFloats, float32(v))
+				actionInts = append(actionInts, int64(v))
+			}
+		} else {
+			// is_last步没有动作，以0填充
+			for i := 0; i < info.ActionSize; i++ {
+				actionFloats = append(actionFloats, 0)
+				actionInts = append(actionInts, 0)
+			}
+		}
+	}
+	isFirst[0] = 1
+	isLast[n-1] = 1
+	if episode.terminal {
+		isTerminal[n-1] = 1
+		if n > 1 {
+			discounts[n-2] = 0
+		}
+	}
+
+	action := Feature{Floats: actionFloats}
+	if info.ActionDtype == DtypeInt64 {
+		action = Feature{Int64s: actionInts}
+	}
+	return map[string]Feature{
+		"episode_id":        {Int64s: []int64{int64(episode.index)}},
+		"steps/observation": {Floats: observations},
+		"steps/action":      action,
+		"steps/reward":      {Floats: rewards},
+		"steps/discount":    {Floats: discounts},
+		"steps/is_first":    {Int64s: isFirst},
+		"steps/is_last":     {Int64s: isLast},
+		"steps/is_terminal": {Int64s: isTerminal},
+	}
+}
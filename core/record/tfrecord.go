@@ -0,0 +1,121 @@
+package record
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"math"
+	"sort"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// maskedCRC TFRecord使用的掩码CRC32C校验和
+func maskedCRC(data []byte) uint32 {
+	crc := crc32.Checksum(data, castagnoli)
+	return ((crc >> 15) | (crc << 17)) + 0xa282ead8
+}
+
+// TFRecordWriter 按TFRecord格式写入记录：
+// uint64长度 | uint32长度的掩码CRC | 数据 | uint32数据的掩码CRC（均为小端序）
+type TFRecordWriter struct {
+	w io.Writer
+}
+
+// NewTFRecordWriter 创建TFRecord写入器
+func NewTFRecordWriter(w io.Writer) *TFRecordWriter {
+	return &TFRecordWriter{w: w}
+}
+
+// Write 写入一条记录
+func (t *TFRecordWriter) Write(data []byte) error {
+	header := make([]byte, 12)
+	binary.LittleEndian.PutUint64(header[:8], uint64(len(data)))
+	binary.LittleEndian.PutUint32(header[8:], maskedCRC(header[:8]))
+	footer := make([]byte, 4)
+	binary.LittleEndian.PutUint32(footer, maskedCRC(data))
+	for _, part := range [][]byte{header, data, footer} {
+		if _, err := t.w.Write(part); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Feature tf.train.Feature的取值，三个字段中只应设置一个
+type Feature struct {
+	Bytes  [][]byte
+	Floats []float32
+	Int64s []int64
+}
+
+// encodeExample 将特征表编码为tf.train.Example的protobuf字节，键按字典序排列以保证输出稳定：
+//
+//	Example{features=1: Features{feature=1: map<string, Feature>}}
+//	Feature{bytes_list=1 | float_list=2 | int64_list=3}，各List的value字段号均为1
+func encodeExample(features map[string]Feature) []byte {
+	keys := make([]string, 0, len(features))
+	for key := range features {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var featureMap []byte
+	for _, key := range keys {
+		var entry []byte
+		entry = protowire.AppendTag(entry, 1, protowire.BytesType)
+		entry = protowire.AppendString(entry, key)
+		entry = protowire.AppendTag(entry, 2, protowire.BytesType)
+		entry = protowire.AppendBytes(entry, encodeFeature(features[key]))
+
+		featureMap = protowire.AppendTag(featureMap, 1, protowire.BytesType)
+		featureMap = protowire.AppendBytes(featureMap, entry)
+	}
+
+	var example []byte
+	example = protowire.AppendTag(example, 1, protowire.BytesType)
+	example = protowire.AppendBytes(example, featureMap)
+	return example
+}
+
+func encodeFeature(feature Feature) []byte {
+	var list []byte
+	var field protowire.Number
+	switch {
+	case feature.Floats != nil:
+		field = 2
+		var packed []byte
+		for _, v := range feature.Floats {
+			packed = protowire.AppendFixed32(packed, math.Float32bits(v))
+		}
+		list = appendPacked(list, packed)
+	case feature.Int64s != nil:
+		field = 3
+		var packed []byte
+		for _, v := range feature.Int64s {
+			packed = protowire.AppendVarint(packed, uint64(v))
+		}
+		list = appendPacked(list, packed)
+	default:
+		field = 1
+		for _, v := range feature.Bytes {
+			list = protowire.AppendTag(list, 1, protowire.BytesType)
+			list = protowire.AppendBytes(list, v)
+		}
+	}
+
+	var out []byte
+	out = protowire.AppendTag(out, field, protowire.BytesType)
+	return protowire.AppendBytes(out, list)
+}
+
+// appendPacked 以packed编码写入value字段，空列表不写入
+func appendPacked(b, packed []byte) []byte {
+	if len(packed) == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, 1, protowire.BytesType)
+	return protowire.AppendBytes(b, packed)
+}
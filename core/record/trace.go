@@ -0,0 +1,47 @@
+package record
+
+import (
+	"fmt"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// NewTrace 将轨迹记录转换为core.Trace，以便用core.ReplayTrace在新建的环境上重新执行录制的动作，
+// 检查观察和奖励是否逐位相同。JSON中的数值可以精确往返，但非有限值录制为null，回放时还原为NaN，
+// 因此含±Inf的观察会被报告为差异
+func NewTrace(records []Record) (*core.Trace, error) {
+	episodes := Episodes(records)
+	if len(episodes) == 0 {
+		return nil, fmt.Errorf("recording contains no episodes")
+	}
+
+	trace := &core.Trace{}
+	for _, episode := range episodes {
+		trace.Steps = append(trace.Steps, core.TraceStep{Reset: true, Observations: floatsList(episode.Reset.Observations)})
+		for _, step := range episode.Steps {
+			actions := make([]core.Action, len(step.Actions))
+			for i, action := range step.Actions {
+				actions[i] = core.NewGenericAction(action)
+			}
+			dones := make([]bool, len(step.Rewards))
+			for i := range dones {
+				dones[i] = (i < len(step.Terminated) && step.Terminated[i]) || (i < len(step.Truncated) && step.Truncated[i])
+			}
+			trace.Steps = append(trace.Steps, core.TraceStep{
+				Actions:      actions,
+				Observations: floatsList(step.Observations),
+				Rewards:      []float64(step.Rewards),
+				Dones:        dones,
+			})
+		}
+	}
+	return trace, nil
+}
+
+func floatsList(data []Floats) [][]float64 {
+	result := make([][]float64, len(data))
+	for i, values := range data {
+		result[i] = values
+	}
+	return result
+}
@@ -0,0 +1,297 @@
+// Package runstore 将环境的创建、回合结果及汇总统计持久化到SQLite，便于事后分析长期运行的仿真服务。
+//
+// 数据库包含两张表，可直接用sqlite3命令行或任意SQL工具查询：
+//
+//	runs(id, env_id, scenario, config, created_at, closed_at)
+//	episodes(id, run_id, episode, steps, total_return, returns, truncated, started_at, ended_at)
+package runstore
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const schema = `
+CREATE TABLE IF NOT EXISTS runs (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	env_id     TEXT NOT NULL,
+	scenario   TEXT NOT NULL,
+	config     TEXT NOT NULL DEFAULT '{}',
+	created_at DATETIME NOT NULL,
+	closed_at  DATETIME
+);
+CREATE INDEX IF NOT EXISTS runs_scenario ON runs(scenario);
+CREATE INDEX IF NOT EXISTS runs_env_id ON runs(env_id);
+
+CREATE TABLE IF NOT EXISTS episodes (
+	id           INTEGER PRIMARY KEY AUTOINCREMENT,
+	run_id       INTEGER NOT NULL REFERENCES runs(id),
+	episode      INTEGER NOT NULL,
+	steps        INTEGER NOT NULL,
+	total_return REAL NOT NULL,
+	returns      TEXT NOT NULL,
+	truncated    BOOLEAN NOT NULL,
+	started_at   DATETIME NOT NULL,
+	ended_at     DATETIME NOT NULL
+);
+CREATE INDEX IF NOT EXISTS episodes_run_id ON episodes(run_id, episode);
+`
+
+// Store 基于SQLite的运行与回合存储，可被多个goroutine并发使用
+type Store struct {
+	db *sql.DB
+}
+
+// Run 一次环境运行（从创建到关闭）及其回合汇总统计
+type Run struct {
+	ID        int64                  `json:"id"`
+	EnvID     string                 `json:"env_id"`
+	Scenario  string                 `json:"scenario"`
+	Config    map[string]interface{} `json:"config"`
+	CreatedAt time.Time              `json:"created_at"`
+	ClosedAt  *time.Time             `json:"closed_at,omitempty"` // 运行中的环境为nil
+	Summary   Summary                `json:"summary"`
+}
+
+// Summary 一次运行中所有回合的汇总统计，没有回合时各统计量为0
+type Summary struct {
+	Episodes          int     `json:"episodes"`
+	TruncatedEpisodes int     `json:"truncated_episodes"`
+	TotalSteps        int64   `json:"total_steps"`
+	MeanReturn        float64 `json:"mean_return"`
+	StdReturn         float64 `json:"std_return"`
+	MinReturn         float64 `json:"min_return"`
+	MaxReturn         float64 `json:"max_return"`
+	MeanLength        float64 `json:"mean_length"`
+}
+
+// Episode 一个回合的结果
+type Episode struct {
+	RunID     int64     `json:"run_id"`
+	Episode   int       `json:"episode"`    // 回合在运行中的序号，从0开始
+	Steps     int       `json:"steps"`      // 回合步数
+	Return    float64   `json:"return"`     // 所有智能体累计奖励之和
+	Returns   []float64 `json:"returns"`    // 各智能体的累计奖励
+	Truncated bool      `json:"truncated"`  // 因步数上限或中途重置/关闭而结束
+	StartedAt time.Time `json:"started_at"` // Reset的时间
+	EndedAt   time.Time `json:"ended_at"`   // 最后一步的时间
+}
+
+// RunFilter 查询运行的条件，零值字段不作限制
+type RunFilter struct {
+	Scenario string
+	EnvID    string
+	Since    time.Time // 只返回此后创建的运行
+	Active   bool      // 只返回尚未关闭的运行
+	Limit    int       // 最多返回的运行数，<=0时为100
+}
+
+// Open 打开（不存在时创建）path处的SQLite数据库并初始化表结构
+func Open(path string) (*Store, error) {
+	db, err := sql.Open("sqlite3", "file:"+path+"?_busy_timeout=5000&_journal_mode=WAL")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open run store: %w", err)
+	}
+	// SQLite同一时刻只允许一个写入者，单连接可避免SQLITE_BUSY
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize run store %s: %w", path, err)
+	}
+	return &Store{db: db}, nil
+}
+
+// DB 返回底层数据库连接，便于执行自定义查询
+func (s *Store) DB() *sql.DB {
+	return s.db
+}
+
+// Close 关闭数据库
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// CreateRun 记录一次环境创建，返回运行ID
+func (s *Store) CreateRun(envID, scenario string, config map[string]interface{}) (int64, error) {
+	if config == nil {
+		config = map[string]interface{}{}
+	}
+	data, err := json.Marshal(config)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode config of %s: %w", envID, err)
+	}
+	result, err := s.db.Exec(`INSERT INTO runs (env_id, scenario, config, created_at) VALUES (?, ?, ?, ?)`,
+		envID, scenario, string(data), time.Now().UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to record run of %s: %w", envID, err)
+	}
+	return result.LastInsertId()
+}
+
+// CloseRun 记录运行结束的时间
+func (s *Store) CloseRun(runID int64) error {
+	if _, err := s.db.Exec(`UPDATE runs SET closed_at = ? WHERE id = ? AND closed_at IS NULL`, time.Now().UTC(), runID); err != nil {
+		return fmt.Errorf("failed to close run %d: %w", runID, err)
+	}
+	return nil
+}
+
+// AddEpisode 记录一个回合的结果
+func (s *Store) AddEpisode(episode Episode) error {
+	returns, err := json.Marshal(finite(episode.Returns))
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`INSERT INTO episodes (run_id, episode, steps, total_return, returns, truncated, started_at, ended_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		episode.RunID, episode.Episode, episode.Steps, episode.Return, string(returns), episode.Truncated,
+		episode.StartedAt.UTC(), episode.EndedAt.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to record episode %d of run %d: %w", episode.Episode, episode.RunID, err)
+	}
+	return nil
+}
+
+// runColumns 运行及其汇总统计的查询列，与scanRun对应
+const runColumns = `
+	r.id, r.env_id, r.scenario, r.config, r.created_at, r.closed_at,
+	COUNT(e.id),
+	COALESCE(SUM(e.truncated), 0),
+	COALESCE(SUM(e.steps), 0),
+	COALESCE(AVG(e.total_return), 0),
+	COALESCE(AVG(e.total_return * e.total_return), 0),
+	COALESCE(MIN(e.total_return), 0),
+	COALESCE(MAX(e.total_return), 0),
+	COALESCE(AVG(e.steps), 0)
+FROM runs r LEFT JOIN episodes e ON e.run_id = r.id`
+
+// Runs 按创建时间倒序返回满足条件的运行及其汇总统计
+func (s *Store) Runs(filter RunFilter) ([]Run, error) {
+	var conditions []string
+	var args []interface{}
+	if filter.Scenario != "" {
+		conditions = append(conditions, "r.scenario = ?")
+		args = append(args, filter.Scenario)
+	}
+	if filter.EnvID != "" {
+		conditions = append(conditions, "r.env_id = ?")
+		args = append(args, filter.EnvID)
+	}
+	if !filter.Since.IsZero() {
+		conditions = append(conditions, "r.created_at >= ?")
+		args = append(args, filter.Since.UTC())
+	}
+	if filter.Active {
+		conditions = append(conditions, "r.closed_at IS NULL")
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	query := "SELECT " + runColumns
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " GROUP BY r.id ORDER BY r.id DESC LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query runs: %w", err)
+	}
+	defer rows.Close()
+
+	runs := []Run{}
+	for rows.Next() {
+		run, err := scanRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
+
+// Run 返回一次运行及其汇总统计，不存在时返回的错误包装了sql.ErrNoRows
+func (s *Store) Run(runID int64) (Run, error) {
+	row := s.db.QueryRow("SELECT "+runColumns+" WHERE r.id = ? GROUP BY r.id", runID)
+	run, err := scanRun(row)
+	if err != nil {
+		return Run{}, fmt.Errorf("failed to query run %d: %w", runID, err)
+	}
+	return run, nil
+}
+
+// Episodes 按回合序号返回一次运行的回合，limit<=0时返回全部
+func (s *Store) Episodes(runID int64, limit int) ([]Episode, error) {
+	query := `SELECT run_id, episode, steps, total_return, returns, truncated, started_at, ended_at
+		FROM episodes WHERE run_id = ? ORDER BY episode`
+	args := []interface{}{runID}
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query episodes of run %d: %w", runID, err)
+	}
+	defer rows.Close()
+
+	episodes := []Episode{}
+	for rows.Next() {
+		var episode Episode
+		var returns string
+		if err := rows.Scan(&episode.RunID, &episode.Episode, &episode.Steps, &episode.Return, &returns,
+			&episode.Truncated, &episode.StartedAt, &episode.EndedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(returns), &episode.Returns); err != nil {
+			return nil, fmt.Errorf("invalid returns of episode %d in run %d: %w", episode.Episode, runID, err)
+		}
+		episodes = append(episodes, episode)
+	}
+	return episodes, rows.Err()
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanRun(row scanner) (Run, error) {
+	var run Run
+	var config string
+	var closedAt sql.NullTime
+	var meanSquare float64
+	summary := &run.Summary
+	if err := row.Scan(&run.ID, &run.EnvID, &run.Scenario, &config, &run.CreatedAt, &closedAt,
+		&summary.Episodes, &summary.TruncatedEpisodes, &summary.TotalSteps, &summary.MeanReturn, &meanSquare,
+		&summary.MinReturn, &summary.MaxReturn, &summary.MeanLength); err != nil {
+		return Run{}, err
+	}
+	if closedAt.Valid {
+		run.ClosedAt = &closedAt.Time
+	}
+	if err := json.Unmarshal([]byte(config), &run.Config); err != nil {
+		return Run{}, fmt.Errorf("invalid config of run %d: %w", run.ID, err)
+	}
+	summary.StdReturn = math.Sqrt(math.Max(0, meanSquare-summary.MeanReturn*summary.MeanReturn))
+	return run, nil
+}
+
+// finite 将非有限值替换为0，因为JSON不支持NaN和Inf
+func finite(values []float64) []float64 {
+	result := make([]float64, len(values))
+	for i, v := range values {
+		if !math.IsNaN(v) && !math.IsInf(v, 0) {
+			result[i] = v
+		}
+	}
+	return result
+}
@@ -0,0 +1,122 @@
+package runstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// Tracker 包装一个环境，将每个回合的结果写入Store。
+// 未结束就被重置或关闭的回合记为截断；写入失败只记录日志，不影响仿真
+type Tracker struct {
+	env   core.Environment
+	store *Store
+	runID int64
+
+	maxSteps  int
+	episode   int
+	steps     int
+	returns   []float64
+	startedAt time.Time
+	endedAt   time.Time
+}
+
+var (
+	_ core.Environment      = (*Tracker)(nil)
+	_ core.MetadataProvider = (*Tracker)(nil)
+)
+
+// NewTracker 创建将回合写入store中runID运行的包装器
+func NewTracker(env core.Environment, store *Store, runID int64) *Tracker {
+	return &Tracker{env: env, store: store, runID: runID, maxSteps: core.GetEnvMetadata(env).MaxEpisodeSteps}
+}
+
+// RunID 返回回合所属的运行ID
+func (t *Tracker) RunID() int64 {
+	return t.runID
+}
+
+// Unwrap 返回被包装的环境
+func (t *Tracker) Unwrap() core.Environment {
+	return t.env
+}
+
+func (t *Tracker) Reset(ctx context.Context) ([]core.Observation, error) {
+	observations, err := t.env.Reset(ctx)
+	if err != nil {
+		return nil, err
+	}
+	t.finish(true)
+	t.returns = make([]float64, len(observations))
+	t.startedAt = time.Now()
+	return observations, nil
+}
+
+func (t *Tracker) Step(ctx context.Context, actions []core.Action) ([]core.Observation, []float64, []bool, error) {
+	observations, rewards, dones, err := t.env.Step(ctx, actions)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	t.steps++
+	t.endedAt = time.Now()
+	for i, reward := range rewards {
+		if i >= len(t.returns) {
+			t.returns = append(t.returns, 0)
+		}
+		t.returns[i] += reward
+	}
+	if core.AllDone(dones) {
+		truncated, _ := t.env.GetInfo()["truncated"].(bool)
+		t.finish(truncated || (t.maxSteps > 0 && t.steps >= t.maxSteps))
+	}
+	return observations, rewards, dones, nil
+}
+
+// finish 写入当前回合
+func (t *Tracker) finish(truncated bool) {
+	if t.steps == 0 {
+		return
+	}
+	episode := Episode{
+		RunID:     t.runID,
+		Episode:   t.episode,
+		Steps:     t.steps,
+		Returns:   append([]float64(nil), t.returns...),
+		Truncated: truncated,
+		StartedAt: t.startedAt,
+		EndedAt:   t.endedAt,
+	}
+	for _, r := range t.returns {
+		episode.Return += r
+	}
+	if err := t.store.AddEpisode(episode); err != nil {
+		core.DefaultLogger().Warn("run store: failed to record episode", "error", err)
+	}
+	t.episode++
+	t.steps = 0
+	// 结束后未Reset就继续步进的环境从此刻开始下一回合
+	t.startedAt = time.Now()
+	for i := range t.returns {
+		t.returns[i] = 0
+	}
+}
+
+func (t *Tracker) GetObservations() []core.Observation { return t.env.GetObservations() }
+func (t *Tracker) GetReward() []float64                { return t.env.GetReward() }
+func (t *Tracker) GetInfo() map[string]interface{}     { return t.env.GetInfo() }
+func (t *Tracker) GetSpaces() core.SpaceDefinition     { return t.env.GetSpaces() }
+
+// Metadata 返回被包装环境的元数据
+func (t *Tracker) Metadata() core.EnvMetadata {
+	return core.GetEnvMetadata(t.env)
+}
+
+// Close 写入未结束的回合，记录运行结束并关闭环境
+func (t *Tracker) Close() error {
+	t.finish(true)
+	if err := t.store.CloseRun(t.runID); err != nil {
+		core.DefaultLogger().Warn("run store: failed to close run", "run_id", t.runID, "error", err)
+	}
+	return t.env.Close()
+}
@@ -1,5 +1,11 @@
 package core
 
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
 // SpaceType 定义空间类型
 type SpaceType int
 
@@ -8,6 +14,14 @@ const (
 	SpaceTypeDiscrete
 	SpaceTypeMultiDiscrete
 	SpaceTypeMultiBinary
+	_ // 与protobuf的DISCRETE_FLOAT对应，core中离散浮点值由Discrete空间的DiscreteValues表示
+	SpaceTypeText
+	// SpaceTypeImage 图像观察空间：Shape为[height, width, channels]，像素为uint8，观察实现ImageObservation
+	SpaceTypeImage
+	// SpaceTypeDict 由命名子空间组成的复合动作空间：子空间在Subspaces中，动作为DictAction
+	SpaceTypeDict
+	// SpaceTypeHybrid 参数化动作空间：在len(Parameters)个离散动作中选择一个，并给出该动作的连续参数，动作为HybridAction
+	SpaceTypeHybrid
 )
 
 // ActionSpace 定义动作空间
@@ -18,15 +32,22 @@ type ActionSpace struct {
 	Shape          []int32
 	Dtype          string
 	DiscreteValues []float64 // 仅在Type为SpaceTypeDiscrete时使用，表示离散动作的具体取值
+	MaxLength      int       // 仅在Type为SpaceTypeText时使用，表示文本的最大字符数
+	Charset        string    // 仅在Type为SpaceTypeText时使用，表示允许的字符，为空时为DefaultCharset
+
+	Subspaces  map[string]ActionSpace // 仅在Type为SpaceTypeDict时使用，表示各命名分量的动作空间
+	Parameters []ActionSpace          // 仅在Type为SpaceTypeHybrid时使用，第i个离散动作的连续参数空间（Box，无参数时Shape为[0]）
 }
 
 // ObservationSpace 定义观察空间
 type ObservationSpace struct {
-	Type  SpaceType
-	Low   []float64
-	High  []float64
-	Shape []int32
-	Dtype string
+	Type      SpaceType
+	Low       []float64
+	High      []float64
+	Shape     []int32
+	Dtype     string
+	MaxLength int    // 仅在Type为SpaceTypeText时使用，表示文本的最大字符数
+	Charset   string // 仅在Type为SpaceTypeText时使用，表示允许的字符，为空时为DefaultCharset
 }
 
 // SpaceDefinition 包含动作空间和观察空间的定义
@@ -34,3 +55,276 @@ type SpaceDefinition struct {
 	ActionSpace      ActionSpace
 	ObservationSpace ObservationSpace
 }
+
+// Size 返回空间展平后的元素个数，Shape为空时视为标量
+func (s ObservationSpace) Size() int {
+	return shapeSize(s.Shape)
+}
+
+// Size 返回动作展平后的元素个数，Shape为空时视为标量
+func (s ActionSpace) Size() int {
+	return shapeSize(s.Shape)
+}
+
+// Nvec 返回MultiDiscrete空间各维的取值个数（High-Low+1），与Gym的MultiDiscrete.nvec相同，各维的最小值为Low；
+// 其他类型的空间返回nil
+func (s ActionSpace) Nvec() []int {
+	if s.Type != SpaceTypeMultiDiscrete {
+		return nil
+	}
+	nvec := make([]int, s.Size())
+	for i := range nvec {
+		nvec[i] = int(boundAt(s.High, i, 0)-boundAt(s.Low, i, 0)) + 1
+	}
+	return nvec
+}
+
+// MultiDiscreteValues 将MultiDiscrete空间的动作转换为各维的整数取值，并检查个数与[Low, High]范围。
+// 接受整数或整数值的浮点数组成的数组（gRPC的IntArray与FloatArray、HTTP的values、pybridge的动作数组），
+// Size()为1时也接受标量
+func (s ActionSpace) MultiDiscreteValues(action Action) ([]int, error) {
+	if action == nil {
+		return nil, fmt.Errorf("action is nil")
+	}
+	generic := NewGenericAction(action.GetData())
+	values, err := generic.GetIntSlice()
+	if err != nil {
+		value, scalarErr := generic.GetInt64()
+		if scalarErr != nil {
+			return nil, fmt.Errorf("multi-discrete action must be an integer array: %w", err)
+		}
+		values = []int{int(value)}
+	}
+	if len(values) != s.Size() {
+		return nil, fmt.Errorf("multi-discrete action needs %d values, got %d", s.Size(), len(values))
+	}
+	for i, v := range values {
+		low, high := int(boundAt(s.Low, i, 0)), int(boundAt(s.High, i, 0))
+		if v < low || v > high {
+			return nil, fmt.Errorf("multi-discrete action value %d is %d, outside [%d, %d]", i, v, low, high)
+		}
+	}
+	return values, nil
+}
+
+// MultiBinaryValues 将MultiBinary空间的动作转换为各维的开关状态，并检查个数与取值。
+// 接受布尔数组（gRPC的BoolArray）或取值为0/1的数值数组（IntArray、FloatArray、HTTP的values、pybridge的动作数组），
+// Size()为1时也接受标量
+func (s ActionSpace) MultiBinaryValues(action Action) ([]bool, error) {
+	if action == nil {
+		return nil, fmt.Errorf("action is nil")
+	}
+	generic := NewGenericAction(action.GetData())
+	values, err := generic.GetBoolSlice()
+	if err != nil {
+		scalar, scalarErr := NewGenericAction([]interface{}{action.GetData()}).GetBoolSlice()
+		if scalarErr != nil {
+			return nil, fmt.Errorf("multi-binary action must be an array of booleans or 0/1 values: %w", err)
+		}
+		values = scalar
+	}
+	if len(values) != s.Size() {
+		return nil, fmt.Errorf("multi-binary action needs %d values, got %d", s.Size(), len(values))
+	}
+	return values, nil
+}
+
+// Contains 判断动作是否属于该空间，与Gym的Space.contains相同，判断规则见Check
+func (s ActionSpace) Contains(action Action) bool {
+	return s.Check(action) == nil
+}
+
+// Check 检查动作是否属于该空间，不属于时返回说明原因（维数、取值范围或数据类型）的错误：
+// 离散动作为[Low, High]内的整数（有DiscreteValues时为其下标），MultiDiscrete、MultiBinary与Text动作能被
+// MultiDiscreteValues、MultiBinaryValues与TextValue接受，Dict与Hybrid动作能被DictComponents与HybridValue接受，连续动作的个数为Size()且各维在边界内。
+// Dtype不是float64的连续空间按float32精度比较边界，使客户端裁剪到float32边界的动作不会因舍入而越界
+func (s ActionSpace) Check(action Action) error {
+	if action == nil {
+		return fmt.Errorf("action is nil")
+	}
+	generic := NewGenericAction(action.GetData())
+	switch s.Type {
+	case SpaceTypeDiscrete:
+		value, err := generic.GetFloat64()
+		if err != nil {
+			return fmt.Errorf("discrete action must be an integer, got %T", action.GetData())
+		}
+		if value != math.Trunc(value) {
+			return fmt.Errorf("discrete action must be an integer, got %v", value)
+		}
+		if len(s.DiscreteValues) > 0 {
+			if value < 0 || int(value) >= len(s.DiscreteValues) {
+				return fmt.Errorf("discrete action %v is not an index of the %d discrete values", value, len(s.DiscreteValues))
+			}
+			return nil
+		}
+		if len(s.Low) == 0 || len(s.High) == 0 {
+			return fmt.Errorf("discrete action space has no bounds")
+		}
+		if value < s.Low[0] || value > s.High[0] {
+			return fmt.Errorf("discrete action %v is outside [%v, %v]", value, s.Low[0], s.High[0])
+		}
+		return nil
+
+	case SpaceTypeMultiDiscrete:
+		_, err := s.MultiDiscreteValues(action)
+		return err
+
+	case SpaceTypeMultiBinary:
+		_, err := s.MultiBinaryValues(action)
+		return err
+
+	case SpaceTypeText:
+		_, err := s.TextValue(action)
+		return err
+
+	case SpaceTypeDict:
+		_, err := s.DictComponents(action)
+		return err
+
+	case SpaceTypeHybrid:
+		_, _, err := s.HybridValue(action)
+		return err
+
+	case SpaceTypeBox:
+		values, err := generic.GetFloat64Values()
+		if err != nil {
+			return fmt.Errorf("continuous action must be a number or an array of numbers, got %T", action.GetData())
+		}
+		if len(values) != s.Size() {
+			return fmt.Errorf("continuous action needs %d values, got %d", s.Size(), len(values))
+		}
+		for i, v := range values {
+			low, high := boundAt(s.Low, i, math.Inf(-1)), boundAt(s.High, i, math.Inf(1))
+			checked := v
+			if s.Dtype != "float64" {
+				checked, low, high = float64(float32(v)), float64(float32(low)), float64(float32(high))
+			}
+			// NaN不满足任何比较，同样视为越界
+			if !(checked >= low && checked <= high) {
+				return fmt.Errorf("continuous action value %d is %v, outside [%v, %v]", i, v, low, high)
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported action space type: %v", s.Type)
+}
+
+func shapeSize(shape []int32) int {
+	size := 1
+	for _, dim := range shape {
+		size *= int(dim)
+	}
+	return size
+}
+
+// boundAt 返回第i维的边界，边界长度为1时按广播处理，缺失时返回def
+func boundAt(bounds []float64, i int, def float64) float64 {
+	switch {
+	case len(bounds) == 1:
+		return bounds[0]
+	case i < len(bounds):
+		return bounds[i]
+	}
+	return def
+}
+
+// SampleAction 从动作空间中均匀随机采样一个动作
+// 离散动作返回整数，MultiDiscrete动作返回[]int，MultiBinary动作返回[]bool，Text动作返回字符集中长度为[0, MaxLength]的字符串，
+// Dict动作返回各子空间分别采样的DictAction，Hybrid动作返回均匀选择的离散动作及其参数空间中采样的参数，
+// 单维连续动作返回float64，多维连续动作返回[]float64。
+// 连续动作的某维无界（边界超过±1e6）时，在有界一侧附近宽度为2的区间内采样，两侧均无界时在[-1, 1]内采样
+func SampleAction(space ActionSpace, rng *rand.Rand) (Action, error) {
+	switch space.Type {
+	case SpaceTypeDiscrete:
+		if len(space.DiscreteValues) > 0 {
+			return NewGenericAction(rng.Intn(len(space.DiscreteValues))), nil
+		}
+		if len(space.Low) == 0 || len(space.High) == 0 {
+			return nil, fmt.Errorf("discrete action space has no bounds")
+		}
+		low, high := int(space.Low[0]), int(space.High[0])
+		if high < low {
+			return nil, fmt.Errorf("discrete action space has high %d < low %d", high, low)
+		}
+		return NewGenericAction(low + rng.Intn(high-low+1)), nil
+
+	case SpaceTypeMultiDiscrete:
+		values := make([]int, space.Size())
+		for i := range values {
+			low, high := int(boundAt(space.Low, i, 0)), int(boundAt(space.High, i, 0))
+			if high < low {
+				return nil, fmt.Errorf("action dimension %d has high %d < low %d", i, high, low)
+			}
+			values[i] = low + rng.Intn(high-low+1)
+		}
+		return NewGenericAction(values), nil
+
+	case SpaceTypeMultiBinary:
+		values := make([]bool, space.Size())
+		for i := range values {
+			values[i] = rng.Intn(2) == 1
+		}
+		return NewGenericAction(values), nil
+
+	case SpaceTypeText:
+		text, err := sampleText(space.MaxLength, space.Charset, rng)
+		if err != nil {
+			return nil, err
+		}
+		return NewGenericAction(text), nil
+
+	case SpaceTypeDict:
+		return sampleDict(space, rng)
+
+	case SpaceTypeHybrid:
+		return sampleHybrid(space, rng)
+
+	case SpaceTypeBox:
+		values := make([]float64, space.Size())
+		for i := range values {
+			low, high := boundAt(space.Low, i, math.Inf(-1)), boundAt(space.High, i, math.Inf(1))
+			lowBounded, highBounded := low > -1e6, high < 1e6
+			switch {
+			case !lowBounded && !highBounded:
+				low, high = -1, 1
+			case !lowBounded:
+				low = high - 2
+			case !highBounded:
+				high = low + 2
+			}
+			if high < low {
+				return nil, fmt.Errorf("action dimension %d has high %v < low %v", i, high, low)
+			}
+			values[i] = low + rng.Float64()*(high-low)
+		}
+		if len(values) == 1 {
+			return NewGenericAction(values[0]), nil
+		}
+		return NewGenericAction(values), nil
+	}
+	return nil, fmt.Errorf("unsupported action space type: %v", space.Type)
+}
+
+// String 返回空间类型的名称，与Gym中的类名一致
+func (t SpaceType) String() string {
+	switch t {
+	case SpaceTypeBox:
+		return "Box"
+	case SpaceTypeDiscrete:
+		return "Discrete"
+	case SpaceTypeMultiDiscrete:
+		return "MultiDiscrete"
+	case SpaceTypeMultiBinary:
+		return "MultiBinary"
+	case SpaceTypeText:
+		return "Text"
+	case SpaceTypeImage:
+		return "Image"
+	case SpaceTypeDict:
+		return "Dict"
+	case SpaceTypeHybrid:
+		return "Hybrid"
+	}
+	return fmt.Sprintf("SpaceType(%d)", int(t))
+}
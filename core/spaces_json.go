@@ -0,0 +1,348 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// GymSpace 一个空间的Gym兼容JSON形式，字段与gymnasium.spaces中对应类的构造参数一致，
+// 客户端可直接构造spaces.Box(low, high, shape, dtype)、spaces.Discrete(n, start=start)、
+// spaces.MultiDiscrete(nvec, start=start)、spaces.MultiBinary(shape)、spaces.Text(max_length, min_length, charset)、
+// spaces.Dict(spaces)与spaces.Tuple(elements)。
+// JSON不支持Inf，无界的边界编码为null，省略low/high表示两侧均无界
+type GymSpace struct {
+	Type      string      `json:"type"` // Gym空间类名：Box、Discrete、MultiDiscrete、MultiBinary、Text、Dict、Tuple
+	Shape     []int       `json:"shape"`
+	Dtype     string      `json:"dtype,omitempty"`
+	Low       interface{} `json:"low,omitempty"`        // Box的下界：标量（广播到整个shape）或展平后长度为元素个数的数组
+	High      interface{} `json:"high,omitempty"`       // Box的上界，形式同low
+	N         int         `json:"n,omitempty"`          // Discrete的取值个数，MultiBinary的元素个数
+	Start     interface{} `json:"start,omitempty"`      // Discrete的最小值（整数）或MultiDiscrete各维的最小值（数组），为0时省略
+	Nvec      []int       `json:"nvec,omitempty"`       // MultiDiscrete各维的取值个数，展平排列
+	Values    []float64   `json:"values,omitempty"`     // Discrete的DiscreteValues，动作i对应的取值为values[i]
+	MaxLength int         `json:"max_length,omitempty"` // Text的最大字符数
+	MinLength *int        `json:"min_length,omitempty"` // Text的最小字符数，恒为0（gymnasium默认为1）
+	Charset   string      `json:"charset,omitempty"`    // Text允许的字符，省略时为DefaultCharset
+	Image     bool        `json:"image,omitempty"`      // 为true时是Image空间，即dtype为uint8、shape为[height, width, channels]的Box
+
+	Spaces   map[string]GymSpace `json:"spaces,omitempty"`   // Dict的各命名子空间
+	Elements []GymSpace          `json:"elements,omitempty"` // Tuple的各元素空间
+	Hybrid   bool                `json:"hybrid,omitempty"`   // 为true时是Hybrid空间，即Tuple(Discrete(n), Tuple(动作0的参数Box, ...))
+}
+
+// GymSpaces SpaceDefinition的JSON形式，HTTP的/spaces与gRPC GetSpaces的spaces_json返回它
+type GymSpaces struct {
+	ActionSpace      GymSpace `json:"action_space"`
+	ObservationSpace GymSpace `json:"observation_space"`
+}
+
+// MarshalJSON 将空间定义编码为GymSpaces
+func (d SpaceDefinition) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.GymSpaces())
+}
+
+// UnmarshalJSON 从GymSpaces解码空间定义
+func (d *SpaceDefinition) UnmarshalJSON(data []byte) error {
+	var spaces GymSpaces
+	if err := json.Unmarshal(data, &spaces); err != nil {
+		return err
+	}
+	action, err := spaces.ActionSpace.ActionSpace()
+	if err != nil {
+		return fmt.Errorf("action_space: %w", err)
+	}
+	observation, err := spaces.ObservationSpace.ObservationSpace()
+	if err != nil {
+		return fmt.Errorf("observation_space: %w", err)
+	}
+	*d = SpaceDefinition{ActionSpace: action, ObservationSpace: observation}
+	return nil
+}
+
+// GymSpaces 返回空间定义的Gym兼容形式
+func (d SpaceDefinition) GymSpaces() GymSpaces {
+	return GymSpaces{ActionSpace: d.ActionSpace.GymSpace(), ObservationSpace: d.ObservationSpace.GymSpace()}
+}
+
+// GymSpace 返回动作空间的Gym兼容形式，Dtype为空时使用gymnasium中该类空间的默认dtype
+func (s ActionSpace) GymSpace() GymSpace {
+	g := GymSpace{Type: s.Type.String(), Shape: make([]int, len(s.Shape)), Dtype: s.Dtype}
+	for i, dim := range s.Shape {
+		g.Shape[i] = int(dim)
+	}
+	switch s.Type {
+	case SpaceTypeBox:
+		g.Low, g.High = gymBound(s.Low, s.Size()), gymBound(s.High, s.Size())
+		g.Dtype = orDefault(g.Dtype, "float32")
+
+	case SpaceTypeImage:
+		g.Type, g.Image = SpaceTypeBox.String(), true
+		g.Low, g.High, g.Dtype = 0, 255, "uint8"
+
+	case SpaceTypeDiscrete:
+		g.Shape = []int{}
+		if len(s.DiscreteValues) > 0 {
+			g.N, g.Values = len(s.DiscreteValues), s.DiscreteValues
+		} else {
+			low := int(boundAt(s.Low, 0, 0))
+			g.N = int(boundAt(s.High, 0, 0)) - low + 1
+			if low != 0 {
+				g.Start = low
+			}
+		}
+		g.Dtype = orDefault(g.Dtype, "int64")
+
+	case SpaceTypeMultiDiscrete:
+		g.Nvec = s.Nvec()
+		start, nonzero := make([]int, len(g.Nvec)), false
+		for i := range start {
+			start[i] = int(boundAt(s.Low, i, 0))
+			nonzero = nonzero || start[i] != 0
+		}
+		if nonzero {
+			g.Start = start
+		}
+		g.Dtype = orDefault(g.Dtype, "int64")
+
+	case SpaceTypeMultiBinary:
+		g.N = s.Size()
+		g.Dtype = orDefault(g.Dtype, "int8")
+
+	case SpaceTypeText:
+		minLength := 0
+		g.MaxLength, g.MinLength, g.Charset = s.MaxLength, &minLength, s.Charset
+
+	case SpaceTypeDict:
+		g.Spaces = make(map[string]GymSpace, len(s.Subspaces))
+		for name, sub := range s.Subspaces {
+			g.Spaces[name] = sub.GymSpace()
+		}
+
+	case SpaceTypeHybrid:
+		// 与参数化动作空间的常见Gym表示（如gym-platform）一致：离散动作下标与每个动作的参数空间组成的Tuple
+		parameters := GymSpace{Type: "Tuple", Shape: []int{}, Elements: make([]GymSpace, len(s.Parameters))}
+		for i, p := range s.Parameters {
+			parameters.Elements[i] = p.GymSpace()
+		}
+		choice := ActionSpace{Type: SpaceTypeDiscrete, Low: []float64{0}, High: []float64{float64(len(s.Parameters) - 1)}}.GymSpace()
+		g.Type, g.Shape, g.Hybrid, g.Elements = "Tuple", []int{}, true, []GymSpace{choice, parameters}
+	}
+	return g
+}
+
+// GymSpace 返回观察空间的Gym兼容形式
+func (s ObservationSpace) GymSpace() GymSpace {
+	return ActionSpace{
+		Type:      s.Type,
+		Low:       s.Low,
+		High:      s.High,
+		Shape:     s.Shape,
+		Dtype:     s.Dtype,
+		MaxLength: s.MaxLength,
+		Charset:   s.Charset,
+	}.GymSpace()
+}
+
+// ActionSpace 将Gym兼容形式转换为动作空间，Discrete与MultiDiscrete的边界为[start, start+n-1]
+func (g GymSpace) ActionSpace() (ActionSpace, error) {
+	s := ActionSpace{Shape: make([]int32, len(g.Shape)), Dtype: g.Dtype}
+	for i, dim := range g.Shape {
+		if dim < 0 {
+			return ActionSpace{}, fmt.Errorf("shape has negative dimension %d", dim)
+		}
+		s.Shape[i] = int32(dim)
+	}
+
+	switch g.Type {
+	case "Box":
+		if g.Image {
+			if len(s.Shape) != 3 {
+				return ActionSpace{}, fmt.Errorf("image space needs shape [height, width, channels], got %v", g.Shape)
+			}
+			s.Type, s.Low, s.High = SpaceTypeImage, []float64{0}, []float64{255}
+			return s, nil
+		}
+		s.Type = SpaceTypeBox
+		var err error
+		if s.Low, err = parseGymBound(g.Low, s.Size(), math.Inf(-1)); err != nil {
+			return ActionSpace{}, fmt.Errorf("low %w", err)
+		}
+		if s.High, err = parseGymBound(g.High, s.Size(), math.Inf(1)); err != nil {
+			return ActionSpace{}, fmt.Errorf("high %w", err)
+		}
+
+	case "Discrete":
+		if g.N <= 0 {
+			return ActionSpace{}, fmt.Errorf("discrete space needs positive n, got %d", g.N)
+		}
+		starts, err := parseGymInts(g.Start, 1)
+		if err != nil {
+			return ActionSpace{}, fmt.Errorf("start %w", err)
+		}
+		s.Type = SpaceTypeDiscrete
+		s.Low, s.High = []float64{float64(starts[0])}, []float64{float64(starts[0] + g.N - 1)}
+		if len(g.Values) > 0 {
+			if len(g.Values) != g.N {
+				return ActionSpace{}, fmt.Errorf("discrete space has %d values for n %d", len(g.Values), g.N)
+			}
+			s.Low, s.High, s.DiscreteValues = []float64{0}, []float64{float64(g.N - 1)}, g.Values
+		}
+
+	case "MultiDiscrete":
+		if len(g.Nvec) != s.Size() {
+			return ActionSpace{}, fmt.Errorf("multi-discrete space has %d nvec entries for shape %v", len(g.Nvec), g.Shape)
+		}
+		starts, err := parseGymInts(g.Start, len(g.Nvec))
+		if err != nil {
+			return ActionSpace{}, fmt.Errorf("start %w", err)
+		}
+		s.Type = SpaceTypeMultiDiscrete
+		s.Low, s.High = make([]float64, len(g.Nvec)), make([]float64, len(g.Nvec))
+		for i, n := range g.Nvec {
+			if n <= 0 {
+				return ActionSpace{}, fmt.Errorf("multi-discrete space has nvec[%d] = %d, not positive", i, n)
+			}
+			s.Low[i], s.High[i] = float64(starts[i]), float64(starts[i]+n-1)
+		}
+
+	case "MultiBinary":
+		s.Type = SpaceTypeMultiBinary
+
+	case "Text":
+		if g.MaxLength < 0 {
+			return ActionSpace{}, fmt.Errorf("text space has negative max_length %d", g.MaxLength)
+		}
+		s.Type, s.MaxLength, s.Charset = SpaceTypeText, g.MaxLength, g.Charset
+
+	case "Dict":
+		if len(g.Spaces) == 0 {
+			return ActionSpace{}, fmt.Errorf("dict space has no subspaces")
+		}
+		s.Type, s.Subspaces = SpaceTypeDict, make(map[string]ActionSpace, len(g.Spaces))
+		for name, sub := range g.Spaces {
+			var err error
+			if s.Subspaces[name], err = sub.ActionSpace(); err != nil {
+				return ActionSpace{}, fmt.Errorf("spaces[%q]: %w", name, err)
+			}
+		}
+
+	case "Tuple":
+		if !g.Hybrid {
+			return ActionSpace{}, fmt.Errorf("tuple space is only supported as a hybrid space")
+		}
+		if len(g.Elements) != 2 || g.Elements[0].Type != "Discrete" || g.Elements[1].Type != "Tuple" {
+			return ActionSpace{}, fmt.Errorf("hybrid space must be a tuple of a Discrete and a Tuple of parameter spaces")
+		}
+		choice, parameters := g.Elements[0], g.Elements[1].Elements
+		if choice.N != len(parameters) || choice.Start != nil {
+			return ActionSpace{}, fmt.Errorf("hybrid space has Discrete(%d) for %d parameter spaces", choice.N, len(parameters))
+		}
+		s.Type, s.Parameters = SpaceTypeHybrid, make([]ActionSpace, len(parameters))
+		for i, p := range parameters {
+			var err error
+			if s.Parameters[i], err = p.ActionSpace(); err != nil {
+				return ActionSpace{}, fmt.Errorf("elements[1].elements[%d]: %w", i, err)
+			}
+		}
+
+	default:
+		return ActionSpace{}, fmt.Errorf("unsupported space type %q", g.Type)
+	}
+	return s, nil
+}
+
+// ObservationSpace 将Gym兼容形式转换为观察空间，Discrete的values被忽略
+func (g GymSpace) ObservationSpace() (ObservationSpace, error) {
+	s, err := g.ActionSpace()
+	if err != nil {
+		return ObservationSpace{}, err
+	}
+	return ObservationSpace{
+		Type:      s.Type,
+		Low:       s.Low,
+		High:      s.High,
+		Shape:     s.Shape,
+		Dtype:     s.Dtype,
+		MaxLength: s.MaxLength,
+		Charset:   s.Charset,
+	}, nil
+}
+
+// gymBound 将边界编码为标量（长度为1时）或长度为size的数组，±Inf与NaN编码为nil；没有边界时返回nil
+func gymBound(bounds []float64, size int) interface{} {
+	if len(bounds) == 0 {
+		return nil
+	}
+	if len(bounds) == 1 {
+		return finiteBound(bounds[0])
+	}
+	out := make([]interface{}, size)
+	for i := range out {
+		out[i] = finiteBound(boundAt(bounds, i, math.NaN()))
+	}
+	return out
+}
+
+func finiteBound(v float64) interface{} {
+	if math.IsInf(v, 0) || math.IsNaN(v) {
+		return nil
+	}
+	return v
+}
+
+// parseGymBound 解码gymBound编码的边界，null解码为unbounded
+func parseGymBound(v interface{}, size int, unbounded float64) ([]float64, error) {
+	switch v := v.(type) {
+	case nil:
+		return []float64{unbounded}, nil
+	case float64:
+		return []float64{v}, nil
+	case []interface{}:
+		if len(v) != size {
+			return nil, fmt.Errorf("has %d values for %d elements", len(v), size)
+		}
+		out := make([]float64, len(v))
+		for i, x := range v {
+			switch x := x.(type) {
+			case nil:
+				out[i] = unbounded
+			case float64:
+				out[i] = x
+			default:
+				return nil, fmt.Errorf("value %d must be a number or null, got %T", i, x)
+			}
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("must be a number, an array or null, got %T", v)
+}
+
+// parseGymInts 解码start：省略时为n个0，n为1时为整数，否则为长度为n的整数数组
+func parseGymInts(v interface{}, n int) ([]int, error) {
+	out := make([]int, n)
+	values, isArray := v.([]interface{})
+	switch {
+	case v == nil:
+		return out, nil
+	case n == 1 && !isArray:
+		values = []interface{}{v}
+	case !isArray || len(values) != n:
+		return nil, fmt.Errorf("must be an array of %d integers", n)
+	}
+	for i, x := range values {
+		f, ok := x.(float64)
+		if !ok || f != math.Trunc(f) {
+			return nil, fmt.Errorf("value %d must be an integer, got %v", i, x)
+		}
+		out[i] = int(f)
+	}
+	return out, nil
+}
+
+func orDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
@@ -0,0 +1,44 @@
+package core
+
+// StateDumper 接口，可选实现，用于导出环境的内部状态（物理量、计数器等），供管理接口排查卡住或异常的环境。
+// 返回值需可JSON编码
+type StateDumper interface {
+	DumpState() map[string]interface{}
+}
+
+// Unwrapper 由包装环境（录制、指标上报等）实现，返回被包装的环境
+type Unwrapper interface {
+	Unwrap() Environment
+}
+
+// DumpState 导出环境的内部状态：沿Unwrap找到实现StateDumper的环境并使用其结果；
+// 均未实现时返回当前观察与info，实现Renderer时附带文本画面
+func DumpState(env Environment) map[string]interface{} {
+	inner := env
+	for {
+		if dumper, ok := inner.(StateDumper); ok {
+			return dumper.DumpState()
+		}
+		wrapper, ok := inner.(Unwrapper)
+		if !ok {
+			break
+		}
+		inner = wrapper.Unwrap()
+	}
+
+	observations := env.GetObservations()
+	data := make([][]float64, len(observations))
+	for i, obs := range observations {
+		data[i] = append([]float64(nil), obs.GetData()...)
+	}
+	ReleaseObservations(observations)
+	state := map[string]interface{}{
+		"observations": data,
+		"info":         env.GetInfo(),
+	}
+	// 包装环境不一定转发Render，使用最内层的环境
+	if renderer, ok := inner.(Renderer); ok {
+		state["render"] = renderer.Render()
+	}
+	return state
+}
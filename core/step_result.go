@@ -0,0 +1,185 @@
+package core
+
+import (
+	"context"
+	"fmt"
+)
+
+// AgentNamer 可选实现：多智能体环境中各智能体的名称（如predator_0、prey_1），未实现时名称为agent_0、agent_1……
+type AgentNamer interface {
+	AgentName(i int) string
+}
+
+// AgentNames 返回环境前n个智能体的名称，沿Unwrap查找AgentNamer
+func AgentNames(env Environment, n int) []string {
+	namer, _ := findAgentNamer(env)
+	names := make([]string, n)
+	for i := range names {
+		if namer != nil {
+			names[i] = namer.AgentName(i)
+		} else {
+			names[i] = fmt.Sprintf("agent_%d", i)
+		}
+	}
+	return names
+}
+
+// findAgentNamer 沿Unwrap找到实现AgentNamer的环境
+func findAgentNamer(env Environment) (AgentNamer, bool) {
+	for {
+		if namer, ok := env.(AgentNamer); ok {
+			return namer, true
+		}
+		wrapper, ok := env.(Unwrapper)
+		if !ok {
+			return nil, false
+		}
+		env = wrapper.Unwrap()
+	}
+}
+
+// StepResult 一步（或Reset）的结果，以智能体名称为键，代替按下标对齐的观察、奖励与结束标志切片。
+// Infos为各智能体的信息，即其观察的元数据；环境级的信息仍由GetInfo返回
+type StepResult struct {
+	Agents       []string // 智能体名称，按环境中的顺序
+	Observations map[string]Observation
+	Rewards      map[string]float64
+	Terminated   map[string]bool
+	Truncated    map[string]bool
+	Infos        map[string]map[string]interface{}
+}
+
+// NewStepResult 按智能体名称组装结果，各切片的长度须与agents一致；rewards、terminated与truncated为nil时
+// （如Reset的结果）取零值
+func NewStepResult(agents []string, observations []Observation, rewards []float64, terminated, truncated []bool) (*StepResult, error) {
+	n := len(agents)
+	if len(observations) != n {
+		return nil, fmt.Errorf("step result has %d observations for %d agents", len(observations), n)
+	}
+	if rewards != nil && len(rewards) != n {
+		return nil, fmt.Errorf("step result has %d rewards for %d agents", len(rewards), n)
+	}
+	if terminated != nil && len(terminated) != n {
+		return nil, fmt.Errorf("step result has %d terminated flags for %d agents", len(terminated), n)
+	}
+	if truncated != nil && len(truncated) != n {
+		return nil, fmt.Errorf("step result has %d truncated flags for %d agents", len(truncated), n)
+	}
+	result := &StepResult{
+		Agents:       agents,
+		Observations: make(map[string]Observation, n),
+		Rewards:      make(map[string]float64, n),
+		Terminated:   make(map[string]bool, n),
+		Truncated:    make(map[string]bool, n),
+		Infos:        make(map[string]map[string]interface{}, n),
+	}
+	for i, name := range agents {
+		if _, ok := result.Observations[name]; ok {
+			return nil, fmt.Errorf("duplicate agent name %q", name)
+		}
+		result.Observations[name] = observations[i]
+		result.Infos[name] = observations[i].GetMetadata()
+		if rewards != nil {
+			result.Rewards[name] = rewards[i]
+		}
+		if terminated != nil {
+			result.Terminated[name] = terminated[i]
+		}
+		if truncated != nil {
+			result.Truncated[name] = truncated[i]
+		}
+	}
+	return result, nil
+}
+
+// Done 判断所有智能体是否都已终止或被截断
+func (r *StepResult) Done() bool {
+	if len(r.Agents) == 0 {
+		return false
+	}
+	for _, name := range r.Agents {
+		if !r.Terminated[name] && !r.Truncated[name] {
+			return false
+		}
+	}
+	return true
+}
+
+// MultiAgentEnv 将环境适配为以智能体名称为键的多智能体接口（类似PettingZoo的Parallel API）：
+// Step接收按名称给出的动作，返回StepResult，截断按env的MaxEpisodeSteps与info["truncated"]判断。
+// 结果中的观察来自对象池，使用完毕后可用ReleaseObservations归还。不能被多个goroutine并发使用
+type MultiAgentEnv struct {
+	env        Environment
+	truncation *TruncationTracker
+	agents     []string
+}
+
+// NewMultiAgentEnv 创建适配env的MultiAgentEnv，智能体名称在第一次Reset时确定
+func NewMultiAgentEnv(env Environment) *MultiAgentEnv {
+	return &MultiAgentEnv{env: env, truncation: NewTruncationTracker(env)}
+}
+
+// Unwrap 返回被适配的环境
+func (e *MultiAgentEnv) Unwrap() Environment {
+	return e.env
+}
+
+// Agents 返回智能体名称，Reset之前为nil
+func (e *MultiAgentEnv) Agents() []string {
+	return e.agents
+}
+
+// Reset 重置环境，返回各智能体的初始观察，奖励与结束标志为零值
+func (e *MultiAgentEnv) Reset(ctx context.Context) (*StepResult, error) {
+	observations, err := e.env.Reset(ctx)
+	if err != nil {
+		return nil, err
+	}
+	e.truncation.Reset()
+	e.agents = AgentNames(e.env, len(observations))
+	result, err := NewStepResult(e.agents, observations, nil, nil, nil)
+	if err != nil {
+		ReleaseObservations(observations)
+		return nil, err
+	}
+	return result, nil
+}
+
+// Step 按智能体名称执行一步，actions须为每个智能体各给出一个动作
+func (e *MultiAgentEnv) Step(ctx context.Context, actions map[string]Action) (*StepResult, error) {
+	if e.agents == nil {
+		return nil, fmt.Errorf("environment must be reset before stepping")
+	}
+	if len(actions) != len(e.agents) {
+		return nil, fmt.Errorf("expected actions for %d agents %v, got %d", len(e.agents), e.agents, len(actions))
+	}
+	ordered := make([]Action, len(e.agents))
+	for i, name := range e.agents {
+		action, ok := actions[name]
+		if !ok {
+			return nil, fmt.Errorf("missing action for agent %q", name)
+		}
+		ordered[i] = action
+	}
+	observations, rewards, dones, err := e.env.Step(ctx, ordered)
+	if err != nil {
+		return nil, err
+	}
+	terminated, truncated := e.truncation.Step(dones, e.env.GetInfo())
+	result, err := NewStepResult(e.agents, observations, rewards, terminated, truncated)
+	if err != nil {
+		ReleaseObservations(observations)
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetInfo 返回环境级的信息
+func (e *MultiAgentEnv) GetInfo() map[string]interface{} {
+	return e.env.GetInfo()
+}
+
+// Close 关闭环境
+func (e *MultiAgentEnv) Close() error {
+	return e.env.Close()
+}
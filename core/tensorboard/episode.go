@@ -0,0 +1,177 @@
+package tensorboard
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// ConfigKey 创建环境时配置中的该键指定TensorBoard日志目录，服务端会据此自动记录回合统计
+const ConfigKey = "tensorboard_dir"
+
+// EpisodeLogger 包装一个环境，每个回合结束时以回合序号为step写入：
+//
+//	episode/return          所有智能体的累计奖励之和
+//	episode/return/agent_i  多智能体环境中各智能体的累计奖励
+//	episode/length          回合步数
+//	info/<key>              回合结束时info中的数值字段（自定义标量）
+//
+// 未结束就被重置的回合同样会被记录
+type EpisodeLogger struct {
+	env        core.Environment
+	writer     *Writer
+	ownsWriter bool
+
+	episode int64
+	steps   int
+	returns []float64
+	active  bool
+}
+
+var (
+	_ core.Environment      = (*EpisodeLogger)(nil)
+	_ core.MetadataProvider = (*EpisodeLogger)(nil)
+)
+
+// NewEpisodeLogger 创建写入writer的回合统计包装器，writer由调用方关闭
+func NewEpisodeLogger(env core.Environment, writer *Writer) *EpisodeLogger {
+	return &EpisodeLogger{env: env, writer: writer}
+}
+
+// FromConfig 当配置中设置了ConfigKey时返回写入该目录的EpisodeLogger，否则原样返回env
+func FromConfig(env core.Environment, config core.Config) (core.Environment, error) {
+	if config == nil {
+		return env, nil
+	}
+	dir, ok, err := config.GetString(ConfigKey)
+	if err != nil {
+		return nil, err
+	}
+	if !ok || dir == "" {
+		return env, nil
+	}
+	writer, err := NewWriter(dir)
+	if err != nil {
+		return nil, err
+	}
+	logger := NewEpisodeLogger(env, writer)
+	logger.ownsWriter = true
+	return logger, nil
+}
+
+// Unwrap 返回被包装的环境
+func (l *EpisodeLogger) Unwrap() core.Environment {
+	return l.env
+}
+
+func (l *EpisodeLogger) Reset(ctx context.Context) ([]core.Observation, error) {
+	observations, err := l.env.Reset(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := l.finish(); err != nil {
+		return nil, err
+	}
+	l.steps = 0
+	l.returns = make([]float64, len(observations))
+	l.active = true
+	return observations, nil
+}
+
+func (l *EpisodeLogger) Step(ctx context.Context, actions []core.Action) ([]core.Observation, []float64, []bool, error) {
+	observations, rewards, dones, err := l.env.Step(ctx, actions)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	l.active = true
+	l.steps++
+	for i, reward := range rewards {
+		if i >= len(l.returns) {
+			l.returns = append(l.returns, 0)
+		}
+		l.returns[i] += reward
+	}
+	if core.AllDone(dones) {
+		if err := l.finish(); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	return observations, rewards, dones, nil
+}
+
+// finish 写入当前回合的统计
+func (l *EpisodeLogger) finish() error {
+	if !l.active || l.steps == 0 {
+		return nil
+	}
+	l.active = false
+
+	scalars := map[string]float64{"episode/length": float64(l.steps)}
+	total := 0.0
+	for i, r := range l.returns {
+		total += r
+		if len(l.returns) > 1 {
+			scalars[fmt.Sprintf("episode/return/agent_%d", i)] = r
+		}
+	}
+	scalars["episode/return"] = total
+	for key, value := range l.env.GetInfo() {
+		if v, ok := numeric(value); ok {
+			scalars["info/"+key] = v
+		}
+	}
+
+	step := l.episode
+	l.episode++
+	if err := l.writer.AddScalars(scalars, step); err != nil {
+		return fmt.Errorf("failed to write episode statistics: %w", err)
+	}
+	return l.writer.Flush()
+}
+
+func (l *EpisodeLogger) GetObservations() []core.Observation { return l.env.GetObservations() }
+func (l *EpisodeLogger) GetReward() []float64                { return l.env.GetReward() }
+func (l *EpisodeLogger) GetInfo() map[string]interface{}     { return l.env.GetInfo() }
+func (l *EpisodeLogger) GetSpaces() core.SpaceDefinition     { return l.env.GetSpaces() }
+
+// Metadata 返回被包装环境的元数据
+func (l *EpisodeLogger) Metadata() core.EnvMetadata {
+	return core.GetEnvMetadata(l.env)
+}
+
+// Close 写入未结束回合的统计并关闭环境，事件文件由FromConfig创建时一并关闭
+func (l *EpisodeLogger) Close() error {
+	err := l.finish()
+	if l.ownsWriter {
+		if closeErr := l.writer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if closeErr := l.env.Close(); closeErr != nil {
+		return closeErr
+	}
+	return err
+}
+
+// numeric 将info中的数值（含布尔值）转换为float64
+func numeric(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case bool:
+		if v {
+			return 1, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
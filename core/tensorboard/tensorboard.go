@@ -0,0 +1,138 @@
+// Package tensorboard 在Go端直接写TensorBoard事件文件（events.out.tfevents.*），
+// 使服务端驱动的评估与训练过程显示在同一个TensorBoard面板中，无需依赖TensorFlow。
+package tensorboard
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jelech/rl_env_engine/core/record"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Writer 向日志目录中的事件文件追加标量
+type Writer struct {
+	mu     sync.Mutex
+	path   string
+	file   *os.File
+	buf    *bufio.Writer
+	writer *record.TFRecordWriter
+}
+
+// NewWriter 在logDir中创建新的事件文件，文件名与TensorFlow的约定一致：events.out.tfevents.<秒级时间戳>.<主机名>
+func NewWriter(logDir string) (*Writer, error) {
+	if err := os.MkdirAll(logDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "localhost"
+	}
+	path := filepath.Join(logDir, fmt.Sprintf("events.out.tfevents.%d.%s", time.Now().Unix(), hostname))
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create event file: %w", err)
+	}
+
+	buf := bufio.NewWriter(file)
+	w := &Writer{path: path, file: file, buf: buf, writer: record.NewTFRecordWriter(buf)}
+	// 事件文件的第一条记录声明版本
+	if err := w.writer.Write(encodeEvent(time.Now(), 0, func(b []byte) []byte {
+		b = protowire.AppendTag(b, 3, protowire.BytesType)
+		return protowire.AppendString(b, "brain.Event:2")
+	})); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if err := w.buf.Flush(); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+// Path 返回事件文件路径
+func (w *Writer) Path() string {
+	return w.path
+}
+
+// AddScalar 写入一个标量，TensorBoard按tag分组、以step为横轴显示
+func (w *Writer) AddScalar(tag string, value float64, step int64) error {
+	return w.AddScalars(map[string]float64{tag: value}, step)
+}
+
+// AddScalars 在同一个step写入多个标量
+func (w *Writer) AddScalars(values map[string]float64, step int64) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return fmt.Errorf("event writer is closed")
+	}
+	event := encodeEvent(time.Now(), step, func(b []byte) []byte {
+		var summary []byte
+		for _, tag := range sortedTags(values) {
+			var v []byte
+			v = protowire.AppendTag(v, 1, protowire.BytesType)
+			v = protowire.AppendString(v, tag)
+			v = protowire.AppendTag(v, 2, protowire.Fixed32Type)
+			v = protowire.AppendFixed32(v, math.Float32bits(float32(values[tag])))
+
+			summary = protowire.AppendTag(summary, 1, protowire.BytesType)
+			summary = protowire.AppendBytes(summary, v)
+		}
+		b = protowire.AppendTag(b, 5, protowire.BytesType)
+		return protowire.AppendBytes(b, summary)
+	})
+	return w.writer.Write(event)
+}
+
+// Flush 将缓冲的事件写入文件
+func (w *Writer) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return nil
+	}
+	return w.buf.Flush()
+}
+
+// Close 写入缓冲的事件并关闭文件
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return nil
+	}
+	err := w.buf.Flush()
+	if closeErr := w.file.Close(); err == nil {
+		err = closeErr
+	}
+	w.file = nil
+	return err
+}
+
+// encodeEvent 编码tensorflow.Event：wall_time=1（秒，double）、step=2，其余字段由what追加
+func encodeEvent(now time.Time, step int64, what func([]byte) []byte) []byte {
+	var b []byte
+	b = protowire.AppendTag(b, 1, protowire.Fixed64Type)
+	b = protowire.AppendFixed64(b, math.Float64bits(float64(now.UnixNano())/1e9))
+	b = protowire.AppendTag(b, 2, protowire.VarintType)
+	b = protowire.AppendVarint(b, uint64(step))
+	return what(b)
+}
+
+// sortedTags 返回排序后的标签，使同一事件中的标量顺序稳定
+func sortedTags(values map[string]float64) []string {
+	tags := make([]string, 0, len(values))
+	for tag := range values {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
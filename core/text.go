@@ -0,0 +1,111 @@
+package core
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultCharset Text空间未设置Charset时允许的字符，与Gym的spaces.Text默认字符集相同（大小写字母与数字）
+const DefaultCharset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// TextObservation 可选实现：Text观察空间的环境返回的文本观察，服务端在gRPC的text字段与HTTP的text中返回它
+type TextObservation interface {
+	GetText() string
+}
+
+// BaseTextObservation 基础文本观察实现，GetData返回空切片
+type BaseTextObservation struct {
+	text     string
+	metadata map[string]interface{}
+}
+
+var (
+	_ Observation     = (*BaseTextObservation)(nil)
+	_ TextObservation = (*BaseTextObservation)(nil)
+)
+
+// NewTextObservation 创建文本观察
+func NewTextObservation(text string, metadata map[string]interface{}) *BaseTextObservation {
+	if metadata == nil {
+		metadata = make(map[string]interface{})
+	}
+	return &BaseTextObservation{text: text, metadata: metadata}
+}
+
+func (o *BaseTextObservation) GetText() string {
+	return o.text
+}
+
+func (o *BaseTextObservation) GetData() []float64 {
+	return []float64{}
+}
+
+func (o *BaseTextObservation) GetMetadata() map[string]interface{} {
+	return o.metadata
+}
+
+// ObservationText 返回文本观察的文本，obs未实现TextObservation时ok为false
+func ObservationText(obs Observation) (text string, ok bool) {
+	if o, ok := obs.(TextObservation); ok {
+		return o.GetText(), true
+	}
+	return "", false
+}
+
+// TextValue 将Text空间的动作转换为字符串，并检查长度（按字符计）与字符集。
+// 动作数据须为字符串（gRPC的string_value、HTTP的text）
+func (s ActionSpace) TextValue(action Action) (string, error) {
+	if action == nil {
+		return "", fmt.Errorf("action is nil")
+	}
+	text, ok := action.GetData().(string)
+	if !ok {
+		return "", fmt.Errorf("text action must be a string, got %T", action.GetData())
+	}
+	if err := checkText(text, s.MaxLength, s.Charset); err != nil {
+		return "", fmt.Errorf("text action %w", err)
+	}
+	return text, nil
+}
+
+// ContainsText 判断文本是否属于Text观察空间
+func (s ObservationSpace) ContainsText(text string) bool {
+	return s.Type == SpaceTypeText && checkText(text, s.MaxLength, s.Charset) == nil
+}
+
+// checkText 检查文本长度不超过maxLength个字符且只包含charset中的字符，charset为空时使用DefaultCharset
+func checkText(text string, maxLength int, charset string) error {
+	if !utf8.ValidString(text) {
+		return fmt.Errorf("is not valid UTF-8")
+	}
+	if n := utf8.RuneCountInString(text); n > maxLength {
+		return fmt.Errorf("has %d characters, more than the maximum %d", n, maxLength)
+	}
+	if charset == "" {
+		charset = DefaultCharset
+	}
+	for i, r := range text {
+		if !strings.ContainsRune(charset, r) {
+			return fmt.Errorf("contains %q at byte %d, which is not in the charset", r, i)
+		}
+	}
+	return nil
+}
+
+// sampleText 在charset中均匀采样长度为[0, maxLength]的文本
+func sampleText(maxLength int, charset string, rng *rand.Rand) (string, error) {
+	if maxLength < 0 {
+		return "", fmt.Errorf("text space has negative max length %d", maxLength)
+	}
+	if charset == "" {
+		charset = DefaultCharset
+	}
+	runes := []rune(charset)
+	var b strings.Builder
+	for n := rng.Intn(maxLength + 1); n > 0; n-- {
+		b.WriteRune(runes[rng.Intn(len(runes))])
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,144 @@
+package core
+
+import (
+	"context"
+	"fmt"
+)
+
+// StepType dm_env中时间步的类型
+type StepType int
+
+const (
+	StepFirst StepType = iota // 回合的第一个时间步（Reset的结果）
+	StepMid                   // 回合中间的时间步
+	StepLast                  // 回合的最后一个时间步
+)
+
+func (t StepType) String() string {
+	switch t {
+	case StepFirst:
+		return "FIRST"
+	case StepMid:
+		return "MID"
+	case StepLast:
+		return "LAST"
+	}
+	return fmt.Sprintf("StepType(%d)", int(t))
+}
+
+// TimeStep dm_env风格的时间步。FIRST时间步的Reward与Discount为0（dm_env中为None）；
+// 终止的LAST时间步Discount为0，因截断结束的LAST时间步Discount为1，以便算法继续自举
+type TimeStep struct {
+	StepType    StepType
+	Reward      float64
+	Discount    float64
+	Observation Observation
+}
+
+func (t TimeStep) First() bool { return t.StepType == StepFirst }
+func (t TimeStep) Mid() bool   { return t.StepType == StepMid }
+func (t TimeStep) Last() bool  { return t.StepType == StepLast }
+
+// StepTransition 返回一个智能体在Reset之后的时间步类型与折扣
+func StepTransition(terminated, truncated bool) (StepType, float64) {
+	switch {
+	case terminated:
+		return StepLast, 0
+	case truncated:
+		return StepLast, 1
+	}
+	return StepMid, 1
+}
+
+// StepTransitions 对每个智能体调用StepTransition
+func StepTransitions(terminated, truncated []bool) ([]StepType, []float64) {
+	types := make([]StepType, len(terminated))
+	discounts := make([]float64, len(terminated))
+	for i := range terminated {
+		types[i], discounts[i] = StepTransition(terminated[i], i < len(truncated) && truncated[i])
+	}
+	return types, discounts
+}
+
+// AllDone 判断是否所有智能体都已结束，即一个回合是否结束；没有智能体时为false
+func AllDone(dones []bool) bool {
+	if len(dones) == 0 {
+		return false
+	}
+	for _, d := range dones {
+		if !d {
+			return false
+		}
+	}
+	return true
+}
+
+// TimeStepEnv 将环境适配为dm_env的接口：Reset与Step返回每个智能体的TimeStep。
+// 与dm_env一致，所有智能体都到达LAST后再次Step会先Reset并返回FIRST时间步，忽略本次动作。
+// 不能被多个goroutine并发使用
+type TimeStepEnv struct {
+	env        Environment
+	truncation *TruncationTracker
+	needsReset bool
+}
+
+// NewTimeStepEnv 创建适配env的TimeStepEnv，截断按env的MaxEpisodeSteps与info["truncated"]判断
+func NewTimeStepEnv(env Environment) *TimeStepEnv {
+	return &TimeStepEnv{env: env, truncation: NewTruncationTracker(env), needsReset: true}
+}
+
+// Unwrap 返回被适配的环境
+func (e *TimeStepEnv) Unwrap() Environment {
+	return e.env
+}
+
+// Reset 重置环境，返回各智能体的FIRST时间步
+func (e *TimeStepEnv) Reset(ctx context.Context) ([]TimeStep, error) {
+	observations, err := e.env.Reset(ctx)
+	if err != nil {
+		return nil, err
+	}
+	e.truncation.Reset()
+	e.needsReset = false
+	steps := make([]TimeStep, len(observations))
+	for i, obs := range observations {
+		steps[i] = TimeStep{StepType: StepFirst, Observation: obs}
+	}
+	return steps, nil
+}
+
+// Step 执行一步，返回各智能体的MID或LAST时间步；尚未Reset或上一回合已结束时改为Reset
+func (e *TimeStepEnv) Step(ctx context.Context, actions []Action) ([]TimeStep, error) {
+	if e.needsReset {
+		return e.Reset(ctx)
+	}
+	observations, rewards, dones, err := e.env.Step(ctx, actions)
+	if err != nil {
+		return nil, err
+	}
+	terminated, truncated := e.truncation.Step(dones, e.env.GetInfo())
+	e.needsReset = AllDone(dones)
+	steps := make([]TimeStep, len(observations))
+	for i, obs := range observations {
+		steps[i].Observation = obs
+		if i < len(rewards) {
+			steps[i].Reward = rewards[i]
+		}
+		if i < len(dones) {
+			steps[i].StepType, steps[i].Discount = StepTransition(terminated[i], truncated[i])
+		} else {
+			steps[i].StepType, steps[i].Discount = StepMid, 1
+		}
+	}
+	return steps, nil
+}
+
+// GetSpaces 返回被适配环境的空间定义
+func (e *TimeStepEnv) GetSpaces() SpaceDefinition {
+	return e.env.GetSpaces()
+}
+
+// Close 关闭被适配的环境
+func (e *TimeStepEnv) Close() error {
+	return e.env.Close()
+}
@@ -0,0 +1,37 @@
+package core
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName 引擎、环境包装器与服务端创建OpenTelemetry span所用的tracer名称
+const TracerName = "github.com/jelech/rl_env_engine"
+
+// span属性
+const (
+	TraceAttrEnvID    = attribute.Key("rl.env_id")
+	TraceAttrScenario = attribute.Key("rl.scenario")
+	TraceAttrEpisode  = attribute.Key("rl.episode") // 环境创建以来的回合序号，从1开始
+	TraceAttrStep     = attribute.Key("rl.step")    // 回合内的步序号，从1开始
+	TraceAttrReward   = attribute.Key("rl.reward")  // 一步中所有智能体的奖励之和
+	TraceAttrDone     = attribute.Key("rl.done")    // 一步后是否所有智能体都已结束
+)
+
+// CreateEnvironmentContext 与CreateEnvironment相同，并以ctx中的span为父span记录一个engine.CreateEnvironment span；
+// 未设置OpenTelemetry的TracerProvider时不记录
+func (s *SimulationEngine) CreateEnvironmentContext(ctx context.Context, scenarioName string, config Config) (Environment, error) {
+	_, span := otel.Tracer(TracerName).Start(ctx, "engine.CreateEnvironment",
+		trace.WithAttributes(TraceAttrScenario.String(scenarioName)))
+	defer span.End()
+	env, err := s.CreateEnvironment(scenarioName, config)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return env, err
+}
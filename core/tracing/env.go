@@ -0,0 +1,97 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// Env 包装一个环境，每次Reset与Step创建一个以ctx中的span为父span的span（env.Reset、env.Step），
+// 带有env_id、scenario、回合序号与步序号属性，Step的span还记录所有智能体的奖励之和与是否全部结束
+type Env struct {
+	env     core.Environment
+	attrs   []attribute.KeyValue
+	episode int
+	step    int
+}
+
+var (
+	_ core.Environment      = (*Env)(nil)
+	_ core.MetadataProvider = (*Env)(nil)
+	_ core.Unwrapper        = (*Env)(nil)
+)
+
+// Wrap 创建追踪包装器，scenario与envID作为每个span的属性
+func Wrap(env core.Environment, scenario, envID string) *Env {
+	return &Env{
+		env: env,
+		attrs: []attribute.KeyValue{
+			core.TraceAttrEnvID.String(envID),
+			core.TraceAttrScenario.String(scenario),
+		},
+	}
+}
+
+// Unwrap 返回被包装的环境
+func (e *Env) Unwrap() core.Environment {
+	return e.env
+}
+
+// start 创建带有环境属性与extra的span
+func (e *Env) start(ctx context.Context, name string, extra ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs := make([]attribute.KeyValue, 0, len(e.attrs)+len(extra))
+	attrs = append(append(attrs, e.attrs...), extra...)
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// fail 将err记录到span
+func fail(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+func (e *Env) Reset(ctx context.Context) ([]core.Observation, error) {
+	e.episode++
+	e.step = 0
+	ctx, span := e.start(ctx, "env.Reset", core.TraceAttrEpisode.Int(e.episode))
+	defer span.End()
+	observations, err := e.env.Reset(ctx)
+	if err != nil {
+		fail(span, err)
+	}
+	return observations, err
+}
+
+func (e *Env) Step(ctx context.Context, actions []core.Action) ([]core.Observation, []float64, []bool, error) {
+	e.step++
+	ctx, span := e.start(ctx, "env.Step", core.TraceAttrEpisode.Int(e.episode), core.TraceAttrStep.Int(e.step))
+	defer span.End()
+	observations, rewards, dones, err := e.env.Step(ctx, actions)
+	if err != nil {
+		fail(span, err)
+		return nil, nil, nil, err
+	}
+	if span.IsRecording() {
+		var reward float64
+		for _, r := range rewards {
+			reward += r
+		}
+		span.SetAttributes(core.TraceAttrReward.Float64(reward), core.TraceAttrDone.Bool(core.AllDone(dones)))
+	}
+	return observations, rewards, dones, nil
+}
+
+func (e *Env) GetObservations() []core.Observation { return e.env.GetObservations() }
+func (e *Env) GetReward() []float64                { return e.env.GetReward() }
+func (e *Env) GetInfo() map[string]interface{}     { return e.env.GetInfo() }
+func (e *Env) GetSpaces() core.SpaceDefinition     { return e.env.GetSpaces() }
+func (e *Env) Close() error                        { return e.env.Close() }
+
+// Metadata 返回被包装环境的元数据
+func (e *Env) Metadata() core.EnvMetadata {
+	return core.GetEnvMetadata(e.env)
+}
@@ -0,0 +1,108 @@
+// Package tracing 为引擎与服务端提供OpenTelemetry追踪：Setup将span导出到OTLP端点，Env为每次Reset与Step创建span。
+// 未调用Setup或SetTracerProvider时追踪关闭，服务端不包装环境，也不为请求创建span
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// DefaultServiceName Options.ServiceName为空时上报的服务名
+const DefaultServiceName = "rl_env_engine"
+
+// Options OTLP导出选项
+type Options struct {
+	// Endpoint OTLP/gRPC端点（host:port），为空时使用OTEL_EXPORTER_OTLP_ENDPOINT环境变量，默认localhost:4317
+	Endpoint string `json:"endpoint,omitempty" yaml:"endpoint,omitempty"`
+	// Insecure 不使用TLS连接端点（如同一主机上的collector）
+	Insecure bool `json:"insecure,omitempty" yaml:"insecure,omitempty"`
+	// Headers 每次导出附带的gRPC元数据，如后端要求的认证头
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	// ServiceName 上报的service.name，为空时为DefaultServiceName
+	ServiceName string `json:"service_name,omitempty" yaml:"service_name,omitempty"`
+	// SampleRatio 新trace的采样比例(0, 1]，为0时全部采样；请求已带有上游的采样决定时沿用该决定
+	SampleRatio float64 `json:"sample_ratio,omitempty" yaml:"sample_ratio,omitempty"`
+}
+
+var enabled atomic.Bool
+
+// Setup 创建批量导出到OTLP端点的TracerProvider并通过SetTracerProvider开启追踪。
+// 返回的函数导出剩余的span、关闭导出器并关闭追踪，应在进程退出前调用
+func Setup(ctx context.Context, opts Options) (shutdown func(context.Context) error, err error) {
+	if opts.SampleRatio < 0 || opts.SampleRatio > 1 {
+		return nil, fmt.Errorf("tracing sample_ratio must be in [0, 1], got %v", opts.SampleRatio)
+	}
+	var clientOpts []otlptracegrpc.Option
+	if opts.Endpoint != "" {
+		clientOpts = append(clientOpts, otlptracegrpc.WithEndpoint(opts.Endpoint))
+	}
+	if opts.Insecure {
+		clientOpts = append(clientOpts, otlptracegrpc.WithInsecure())
+	}
+	if len(opts.Headers) > 0 {
+		clientOpts = append(clientOpts, otlptracegrpc.WithHeaders(opts.Headers))
+	}
+	exporter, err := otlptracegrpc.New(ctx, clientOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+
+	name := opts.ServiceName
+	if name == "" {
+		name = DefaultServiceName
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(attribute.String("service.name", name)))
+	if err != nil {
+		return nil, err
+	}
+	sampler := sdktrace.AlwaysSample()
+	if opts.SampleRatio > 0 {
+		sampler = sdktrace.TraceIDRatioBased(opts.SampleRatio)
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sampler)),
+	)
+	SetTracerProvider(provider)
+	return func(ctx context.Context) error {
+		SetTracerProvider(nil)
+		return provider.Shutdown(ctx)
+	}, nil
+}
+
+// SetTracerProvider 将provider设为OpenTelemetry的全局TracerProvider并开启追踪，
+// 嵌入到已配置OpenTelemetry SDK的应用中时可直接传入其provider；同时使用W3C Trace Context与Baggage传播上下文。
+// nil关闭追踪
+func SetTracerProvider(provider trace.TracerProvider) {
+	if provider == nil {
+		enabled.Store(false)
+		otel.SetTracerProvider(noop.NewTracerProvider())
+		return
+	}
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	enabled.Store(true)
+}
+
+// Enabled 判断是否已通过Setup或SetTracerProvider开启追踪
+func Enabled() bool {
+	return enabled.Load()
+}
+
+// Tracer 返回引擎的tracer
+func Tracer() trace.Tracer {
+	return otel.Tracer(core.TracerName)
+}
@@ -0,0 +1,386 @@
+// Package video 录制环境回合的画面：按间隔采集core.FrameRenderer渲染的RGB帧，
+// 每个被选中的回合编码为一个GIF文件或一组PNG图片。
+package video
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// 输出格式
+const (
+	FormatGIF = "gif" // 每个回合一个GIF动画
+	FormatPNG = "png" // 每个回合一个目录，每帧一张PNG
+)
+
+// 创建环境时配置中的以下键用于开启录像，服务端会据此自动包装环境
+const (
+	ConfigKeyDir             = "video_dir"            // 输出目录，设置后开启录像
+	ConfigKeyFormat          = "video_format"         // gif | png，默认gif
+	ConfigKeyEverySteps      = "video_every_steps"    // 每隔多少步采集一帧，默认1
+	ConfigKeyEveryEpisodes   = "video_every_episodes" // 每隔多少回合录制一次，默认按CubicSchedule
+	ConfigKeyFramesPerSecond = "video_fps"            // GIF播放帧率，默认30
+)
+
+// InfoKeyPath GetInfo中最近一次完成的录像路径
+const InfoKeyPath = "video_path"
+
+// Options 录像选项
+type Options struct {
+	Dir             string                 // 输出目录
+	Format          string                 // FormatGIF或FormatPNG，为空时为GIF
+	EverySteps      int                    // 每隔多少步采集一帧（reset后的初始画面总会采集），<=0时为1
+	EpisodeTrigger  func(episode int) bool // 决定第几个回合（从0开始）需要录制，为nil时使用CubicSchedule
+	FramesPerSecond int                    // GIF播放帧率，<=0时为30
+	Prefix          string                 // 文件名前缀，为空时为"episode"
+}
+
+// CubicSchedule 与Gym的capped_cubic_video_schedule一致：前1000个回合录制立方数回合（0、1、8、27…），之后每1000个回合录制一次
+func CubicSchedule(episode int) bool {
+	if episode < 1000 {
+		root := int(math.Round(math.Cbrt(float64(episode))))
+		return root*root*root == episode
+	}
+	return episode%1000 == 0
+}
+
+// EveryEpisodes 返回每隔n个回合录制一次的触发函数
+func EveryEpisodes(n int) func(int) bool {
+	if n <= 0 {
+		n = 1
+	}
+	return func(episode int) bool { return episode%n == 0 }
+}
+
+// Recorder 包装一个可渲染RGB帧的环境，按计划录制回合画面，自身也实现core.Environment
+type Recorder struct {
+	env      core.Environment
+	renderer core.FrameRenderer
+	opts     Options
+
+	mu        sync.Mutex
+	episode   int // 当前回合序号，尚未Reset时为-1
+	step      int
+	recording bool
+	frames    []*image.Paletted
+	lastPath  string
+	lastErr   error
+}
+
+var (
+	_ core.Environment      = (*Recorder)(nil)
+	_ core.MetadataProvider = (*Recorder)(nil)
+)
+
+// New 创建录像包装器，环境（或其Unwrap链上的环境，如配置含参数范围时引擎返回的DynamicsRandomizer）必须实现core.FrameRenderer
+func New(env core.Environment, opts Options) (*Recorder, error) {
+	renderer, ok := findFrameRenderer(env)
+	if !ok {
+		return nil, fmt.Errorf("environment does not render RGB frames (core.FrameRenderer)")
+	}
+	if opts.Dir == "" {
+		return nil, fmt.Errorf("video output directory is required")
+	}
+	switch opts.Format {
+	case "":
+		opts.Format = FormatGIF
+	case FormatGIF, FormatPNG:
+	default:
+		return nil, fmt.Errorf("unsupported video format %q (expected gif or png)", opts.Format)
+	}
+	if opts.EverySteps <= 0 {
+		opts.EverySteps = 1
+	}
+	if opts.EpisodeTrigger == nil {
+		opts.EpisodeTrigger = CubicSchedule
+	}
+	if opts.FramesPerSecond <= 0 {
+		opts.FramesPerSecond = 30
+	}
+	if opts.Prefix == "" {
+		opts.Prefix = "episode"
+	}
+	if err := os.MkdirAll(opts.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create video directory: %w", err)
+	}
+	return &Recorder{env: env, renderer: renderer, opts: opts, episode: -1}, nil
+}
+
+// findFrameRenderer 沿Unwrap找到实现core.FrameRenderer的环境
+func findFrameRenderer(env core.Environment) (core.FrameRenderer, bool) {
+	for {
+		if renderer, ok := env.(core.FrameRenderer); ok {
+			return renderer, true
+		}
+		wrapper, ok := env.(core.Unwrapper)
+		if !ok {
+			return nil, false
+		}
+		env = wrapper.Unwrap()
+	}
+}
+
+// FromConfig 当配置中设置了ConfigKeyDir时返回录像包装器，否则原样返回env
+func FromConfig(env core.Environment, config core.Config) (core.Environment, error) {
+	if config == nil {
+		return env, nil
+	}
+	dir, ok, err := config.GetString(ConfigKeyDir)
+	if err != nil {
+		return nil, err
+	}
+	if !ok || dir == "" {
+		return env, nil
+	}
+
+	opts := Options{Dir: dir}
+	if opts.Format, _, err = config.GetString(ConfigKeyFormat); err != nil {
+		return nil, err
+	}
+	if opts.EverySteps, _, err = config.GetInt(ConfigKeyEverySteps); err != nil {
+		return nil, err
+	}
+	if opts.FramesPerSecond, _, err = config.GetInt(ConfigKeyFramesPerSecond); err != nil {
+		return nil, err
+	}
+	everyEpisodes, ok, err := config.GetInt(ConfigKeyEveryEpisodes)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		opts.EpisodeTrigger = EveryEpisodes(everyEpisodes)
+	}
+	return New(env, opts)
+}
+
+// Unwrap 返回被录制的环境
+func (r *Recorder) Unwrap() core.Environment {
+	return r.env
+}
+
+func (r *Recorder) Reset(ctx context.Context) ([]core.Observation, error) {
+	observations, err := r.env.Reset(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// 上一回合未结束就被重置时，保存已采集的画面
+	r.finish()
+	r.episode++
+	r.step = 0
+	r.recording = r.opts.EpisodeTrigger(r.episode)
+	if r.recording {
+		r.capture()
+	}
+	return observations, nil
+}
+
+func (r *Recorder) Step(ctx context.Context, actions []core.Action) ([]core.Observation, []float64, []bool, error) {
+	observations, rewards, dones, err := r.env.Step(ctx, actions)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.step++
+	if r.recording && r.step%r.opts.EverySteps == 0 {
+		r.capture()
+	}
+	if core.AllDone(dones) {
+		r.finish()
+	}
+	return observations, rewards, dones, nil
+}
+
+func (r *Recorder) GetObservations() []core.Observation { return r.env.GetObservations() }
+func (r *Recorder) GetReward() []float64                { return r.env.GetReward() }
+func (r *Recorder) GetSpaces() core.SpaceDefinition     { return r.env.GetSpaces() }
+
+// GetInfo 在被包装环境的信息中附加最近一次完成的录像路径及写入错误
+func (r *Recorder) GetInfo() map[string]interface{} {
+	info := r.env.GetInfo()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.lastPath == "" && r.lastErr == nil {
+		return info
+	}
+	if info == nil {
+		info = make(map[string]interface{})
+	}
+	if r.lastPath != "" {
+		info[InfoKeyPath] = r.lastPath
+	}
+	if r.lastErr != nil {
+		info["video_error"] = r.lastErr.Error()
+	}
+	return info
+}
+
+// RenderFrame 返回被包装环境的当前画面
+func (r *Recorder) RenderFrame() ([]uint8, int, int) {
+	return r.renderer.RenderFrame()
+}
+
+// Metadata 返回被录制环境的元数据
+func (r *Recorder) Metadata() core.EnvMetadata {
+	return core.GetEnvMetadata(r.env)
+}
+
+// LastPath 返回最近一次完成的录像路径，尚未完成任何录像时为空
+func (r *Recorder) LastPath() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.lastPath
+}
+
+// Close 保存正在录制的回合并关闭环境
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	r.finish()
+	err := r.lastErr
+	r.mu.Unlock()
+	if closeErr := r.env.Close(); closeErr != nil {
+		return closeErr
+	}
+	return err
+}
+
+// capture 采集一帧
+func (r *Recorder) capture() {
+	pixels, height, width := r.renderer.RenderFrame()
+	if height <= 0 || width <= 0 || len(pixels) < height*width*3 {
+		return
+	}
+	r.frames = append(r.frames, quantize(pixels, height, width))
+}
+
+// finish 保存当前回合已采集的画面
+func (r *Recorder) finish() {
+	if !r.recording {
+		return
+	}
+	r.recording = false
+	frames := r.frames
+	r.frames = nil
+	if len(frames) == 0 {
+		return
+	}
+
+	name := fmt.Sprintf("%s-%06d", r.opts.Prefix, r.episode)
+	var path string
+	var err error
+	if r.opts.Format == FormatPNG {
+		path = filepath.Join(r.opts.Dir, name)
+		err = writePNGs(path, frames)
+	} else {
+		path = filepath.Join(r.opts.Dir, name+".gif")
+		err = writeGIF(path, frames, r.opts.FramesPerSecond)
+	}
+	if err != nil {
+		r.lastErr = fmt.Errorf("failed to write video of episode %d: %w", r.episode, err)
+		return
+	}
+	r.lastPath, r.lastErr = path, nil
+}
+
+// quantize 将RGB帧转换为调色板图像：颜色不超过256种时使用精确调色板，否则映射到Web安全色
+func quantize(pixels []uint8, height, width int) *image.Paletted {
+	rect := image.Rect(0, 0, width, height)
+	index := make(map[[3]uint8]uint8)
+	var colors color.Palette
+	exact := true
+	var last [3]uint8
+	for i := 0; i < height*width*3; i += 3 {
+		c := [3]uint8{pixels[i], pixels[i+1], pixels[i+2]}
+		if i > 0 && c == last {
+			continue
+		}
+		last = c
+		if _, ok := index[c]; !ok {
+			if len(colors) == 256 {
+				exact = false
+				break
+			}
+			index[c] = uint8(len(colors))
+			colors = append(colors, color.RGBA{c[0], c[1], c[2], 255})
+		}
+	}
+
+	if exact {
+		img := image.NewPaletted(rect, colors)
+		// 渲染画面大多是成片的纯色，缓存上一个像素的颜色以减少map查找
+		var last [3]uint8
+		var lastIndex uint8
+		for i := range img.Pix {
+			c := [3]uint8{pixels[i*3], pixels[i*3+1], pixels[i*3+2]}
+			if i == 0 || c != last {
+				last, lastIndex = c, index[c]
+			}
+			img.Pix[i] = lastIndex
+		}
+		return img
+	}
+
+	rgba := image.NewRGBA(rect)
+	for i := 0; i < height*width; i++ {
+		copy(rgba.Pix[i*4:i*4+3], pixels[i*3:i*3+3])
+		rgba.Pix[i*4+3] = 255
+	}
+	img := image.NewPaletted(rect, palette.WebSafe)
+	draw.Draw(img, rect, rgba, image.Point{}, draw.Src)
+	return img
+}
+
+func writeGIF(path string, frames []*image.Paletted, fps int) error {
+	delay := int(math.Round(100 / float64(fps))) // GIF的延迟单位为1/100秒
+	animation := &gif.GIF{}
+	for _, frame := range frames {
+		animation.Image = append(animation.Image, frame)
+		animation.Delay = append(animation.Delay, delay)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := gif.EncodeAll(file, animation); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func writePNGs(dir string, frames []*image.Paletted) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for i, frame := range frames {
+		file, err := os.Create(filepath.Join(dir, fmt.Sprintf("frame-%06d.png", i)))
+		if err != nil {
+			return err
+		}
+		if err := png.Encode(file, frame); err != nil {
+			file.Close()
+			return err
+		}
+		if err := file.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,512 @@
+package wasm
+
+import (
+	"fmt"
+)
+
+// 操作码，0xFC前缀的扩展指令映射到MVP未使用的0xE0..0xEB
+const (
+	opUnreachable  = 0x00
+	opNop          = 0x01
+	opBlock        = 0x02
+	opLoop         = 0x03
+	opIf           = 0x04
+	opElse         = 0x05
+	opEnd          = 0x0B
+	opBr           = 0x0C
+	opBrIf         = 0x0D
+	opBrTable      = 0x0E
+	opReturn       = 0x0F
+	opCall         = 0x10
+	opCallIndirect = 0x11
+	opDrop         = 0x1A
+	opSelect       = 0x1B
+	opSelectT      = 0x1C
+	opLocalGet     = 0x20
+	opLocalSet     = 0x21
+	opLocalTee     = 0x22
+	opGlobalGet    = 0x23
+	opGlobalSet    = 0x24
+	opI32Load      = 0x28
+	opI64Store32   = 0x3E
+	opMemorySize   = 0x3F
+	opMemoryGrow   = 0x40
+	opI32Const     = 0x41
+	opI64Const     = 0x42
+	opF32Const     = 0x43
+	opF64Const     = 0x44
+	opI32Eqz       = 0x45
+	opI64Eqz       = 0x50
+	opI64Extend32S = 0xC4
+	opPrefixFC     = 0xFC
+
+	opTruncSatBase = 0xE0 // 0xFC 0..7
+	opMemoryInit   = 0xE8 // 0xFC 8
+	opDataDrop     = 0xE9 // 0xFC 9
+	opMemoryCopy   = 0xEA // 0xFC 10
+	opMemoryFill   = 0xEB // 0xFC 11
+)
+
+// maxLocals 单个函数的局部变量（含参数）上限
+const maxLocals = 50000
+
+// instr 编译后的指令。分支在编译时解析为目标指令位置与栈高度，执行时无需维护标签栈：
+//
+//	br/br_if/return: x=目标位置, y=保留的值个数, w=目标标签的栈底高度（相对操作数栈底）
+//	br_table:        x=function.tables中的下标
+//	if:              x=条件为假时跳转的位置（else之后或end）
+//	else:            x=end的位置
+//	call:            x=函数下标；call_indirect: x=类型下标
+//	局部/全局变量:    x=下标
+//	内存访问:         z=偏移；常量: z=值的位表示
+type instr struct {
+	op byte
+	x  uint32
+	y  uint32
+	w  uint32
+	z  uint64
+}
+
+// brTarget br_table的一个目标
+type brTarget struct {
+	pc     uint32
+	arity  uint32
+	height uint32
+}
+
+// ctrl 编译时的控制块
+type ctrl struct {
+	op          byte
+	start       int // block/loop/if指令的位置
+	params      int
+	results     int
+	height      int // 块参数之下的栈高度
+	unreachable bool
+	fixups      []int    // 需要回填为块结束位置的分支指令
+	tableFixups [][2]int // 需要回填的br_table目标（tables下标, 目标下标）
+}
+
+// labelArity 分支到该块时保留的值个数：循环为参数，其余为结果
+func (c *ctrl) labelArity() int {
+	if c.op == opLoop {
+		return c.params
+	}
+	return c.results
+}
+
+// compiler 单个函数的编译状态，同时按操作数栈高度做结构校验（不检查值类型：
+// 栈中的值均为uint64，类型错误的模块只会得到无意义的结果，不会越出沙箱）
+type compiler struct {
+	m      *Module
+	fn     *function
+	r      *reader
+	code   []instr
+	ctrls  []*ctrl
+	height int
+	max    int
+}
+
+func (m *Module) compileFunction(fn *function, r *reader) error {
+	typ := m.types[fn.typ]
+	total := len(typ.Params)
+	for n := r.u32(); n > 0 && r.err == nil; n-- {
+		count := r.u32()
+		v := r.valueType()
+		if total+int(count) > maxLocals {
+			return fmt.Errorf("too many locals")
+		}
+		total += int(count)
+		for i := uint32(0); i < count; i++ {
+			fn.locals = append(fn.locals, v)
+		}
+	}
+	if r.err != nil {
+		return r.err
+	}
+
+	c := &compiler{m: m, fn: fn, r: r}
+	c.ctrls = []*ctrl{{op: opBlock, results: len(typ.Results)}}
+	for len(c.ctrls) > 0 {
+		if r.done() {
+			if r.err != nil {
+				return r.err
+			}
+			return fmt.Errorf("missing end of function")
+		}
+		if err := c.instr(); err != nil {
+			return fmt.Errorf("at byte %d: %w", r.pos, err)
+		}
+	}
+	if !r.done() {
+		return fmt.Errorf("trailing bytes after end of function")
+	}
+	fn.code = c.code
+	fn.maxHeight = c.max
+	return nil
+}
+
+func (c *compiler) top() *ctrl { return c.ctrls[len(c.ctrls)-1] }
+
+func (c *compiler) emit(in instr) int {
+	c.code = append(c.code, in)
+	return len(c.code) - 1
+}
+
+// pop 弹出n个值，不可达代码中栈是多态的，不会下溢
+func (c *compiler) pop(n int) error {
+	top := c.top()
+	if c.height-n < top.height {
+		if top.unreachable {
+			c.height = top.height
+			return nil
+		}
+		return fmt.Errorf("operand stack underflow")
+	}
+	c.height -= n
+	return nil
+}
+
+func (c *compiler) push(n int) {
+	c.height += n
+	if c.height > c.max {
+		c.max = c.height
+	}
+}
+
+// setUnreachable 无条件跳转之后直到块结束的代码不可达
+func (c *compiler) setUnreachable() {
+	top := c.top()
+	top.unreachable = true
+	c.height = top.height
+}
+
+// blockType 读取块类型，返回参数与结果个数
+func (c *compiler) blockType() (int, int, error) {
+	r := c.r
+	if r.pos < len(r.data) {
+		switch b := r.data[r.pos]; b {
+		case 0x40:
+			r.pos++
+			return 0, 0, nil
+		case I32, I64, F32, F64:
+			r.pos++
+			return 0, 1, nil
+		}
+	}
+	idx := r.sleb(33)
+	if r.err != nil {
+		return 0, 0, r.err
+	}
+	if idx < 0 || idx >= int64(len(c.m.types)) {
+		return 0, 0, fmt.Errorf("invalid block type %d", idx)
+	}
+	t := c.m.types[idx]
+	return len(t.Params), len(t.Results), nil
+}
+
+// branch 解析到第depth层外的块的分支，返回目标标签的保留值个数与栈底高度，目标位置未知时登记回填
+func (c *compiler) branch(depth uint32, pc int) (brTarget, error) {
+	if int(depth) >= len(c.ctrls) {
+		return brTarget{}, fmt.Errorf("invalid branch depth %d", depth)
+	}
+	target := c.ctrls[len(c.ctrls)-1-int(depth)]
+	arity := target.labelArity()
+	if c.height-arity < c.top().height && !c.top().unreachable {
+		return brTarget{}, fmt.Errorf("operand stack underflow")
+	}
+	t := brTarget{arity: uint32(arity), height: uint32(target.height)}
+	if target.op == opLoop {
+		t.pc = uint32(target.start + 1)
+	} else if pc >= 0 {
+		target.fixups = append(target.fixups, pc)
+	}
+	return t, nil
+}
+
+func (c *compiler) memarg() (uint64, error) {
+	if c.m.memory == nil {
+		return 0, fmt.Errorf("memory instruction without a memory")
+	}
+	c.r.u32() // 对齐只是提示
+	return uint64(c.r.u32()), c.r.err
+}
+
+func (c *compiler) instr() error {
+	r := c.r
+	op := r.byte()
+	if r.err != nil {
+		return r.err
+	}
+	switch {
+	case op == opUnreachable:
+		c.emit(instr{op: op})
+		c.setUnreachable()
+	case op == opNop:
+	case op == opBlock || op == opLoop || op == opIf:
+		params, results, err := c.blockType()
+		if err != nil {
+			return err
+		}
+		if op == opIf {
+			if err := c.pop(1); err != nil {
+				return err
+			}
+		}
+		if err := c.pop(params); err != nil {
+			return err
+		}
+		pc := c.emit(instr{op: op})
+		c.ctrls = append(c.ctrls, &ctrl{op: op, start: pc, params: params, results: results, height: c.height})
+		c.push(params)
+	case op == opElse:
+		top := c.top()
+		if top.op != opIf || len(c.ctrls) == 1 {
+			return fmt.Errorf("else without if")
+		}
+		if !top.unreachable && c.height != top.height+top.results {
+			return fmt.Errorf("type mismatch at else")
+		}
+		pc := c.emit(instr{op: op})
+		top.fixups = append(top.fixups, pc)
+		c.code[top.start].x = uint32(pc + 1)
+		top.op = opElse
+		top.unreachable = false
+		c.height = top.height
+		c.push(top.params)
+	case op == opEnd:
+		top := c.top()
+		if !top.unreachable && c.height != top.height+top.results {
+			return fmt.Errorf("type mismatch at end of block")
+		}
+		pc := c.emit(instr{op: op})
+		if top.op == opIf {
+			if top.params != top.results {
+				return fmt.Errorf("if without else must leave its parameters unchanged")
+			}
+			c.code[top.start].x = uint32(pc)
+		}
+		for _, fix := range top.fixups {
+			c.code[fix].x = uint32(pc)
+		}
+		for _, fix := range top.tableFixups {
+			c.fn.tables[fix[0]][fix[1]].pc = uint32(pc)
+		}
+		c.ctrls = c.ctrls[:len(c.ctrls)-1]
+		c.height = top.height
+		c.push(top.results)
+	case op == opBr || op == opBrIf:
+		depth := r.u32()
+		if op == opBrIf {
+			if err := c.pop(1); err != nil {
+				return err
+			}
+		}
+		pc := len(c.code)
+		t, err := c.branch(depth, pc)
+		if err != nil {
+			return err
+		}
+		c.emit(instr{op: op, x: t.pc, y: t.arity, w: t.height})
+		if op == opBr {
+			c.setUnreachable()
+		}
+	case op == opBrTable:
+		n := r.u32()
+		if uint64(n) > uint64(len(r.data)) {
+			return errUnexpectedEnd
+		}
+		if err := c.pop(1); err != nil {
+			return err
+		}
+		table := len(c.fn.tables)
+		targets := make([]brTarget, 0, n+1)
+		c.fn.tables = append(c.fn.tables, nil)
+		for i := uint32(0); i <= n && r.err == nil; i++ {
+			depth := r.u32()
+			t, err := c.branch(depth, -1)
+			if err != nil {
+				return err
+			}
+			if len(targets) > 0 && t.arity != targets[0].arity {
+				return fmt.Errorf("br_table targets have different arities")
+			}
+			if target := c.ctrls[len(c.ctrls)-1-int(depth)]; target.op != opLoop {
+				target.tableFixups = append(target.tableFixups, [2]int{table, len(targets)})
+			}
+			targets = append(targets, t)
+		}
+		c.fn.tables[table] = targets
+		c.emit(instr{op: op, x: uint32(table)})
+		c.setUnreachable()
+	case op == opReturn:
+		pc := len(c.code)
+		t, err := c.branch(uint32(len(c.ctrls)-1), pc)
+		if err != nil {
+			return err
+		}
+		c.emit(instr{op: opBr, x: t.pc, y: t.arity, w: t.height})
+		c.setUnreachable()
+	case op == opCall:
+		idx := r.u32()
+		if int(idx) >= len(c.m.funcs) {
+			return fmt.Errorf("call to invalid function %d", idx)
+		}
+		t := c.m.types[c.m.funcs[idx].typ]
+		if err := c.pop(len(t.Params)); err != nil {
+			return err
+		}
+		c.push(len(t.Results))
+		c.emit(instr{op: op, x: idx})
+	case op == opCallIndirect:
+		idx, table := r.u32(), r.u32()
+		if int(idx) >= len(c.m.types) {
+			return fmt.Errorf("call_indirect with invalid type %d", idx)
+		}
+		if table != 0 || c.m.table == nil {
+			return fmt.Errorf("call_indirect without a table")
+		}
+		t := c.m.types[idx]
+		if err := c.pop(1 + len(t.Params)); err != nil {
+			return err
+		}
+		c.push(len(t.Results))
+		c.emit(instr{op: op, x: idx})
+	case op == opDrop:
+		if err := c.pop(1); err != nil {
+			return err
+		}
+		c.emit(instr{op: op})
+	case op == opSelect || op == opSelectT:
+		if op == opSelectT {
+			if n := r.u32(); n != 1 {
+				return fmt.Errorf("select must have exactly one result type")
+			}
+			r.valueType()
+		}
+		if err := c.pop(3); err != nil {
+			return err
+		}
+		c.push(1)
+		c.emit(instr{op: opSelect})
+	case op >= opLocalGet && op <= opLocalTee:
+		idx := r.u32()
+		if int(idx) >= len(c.m.types[c.fn.typ].Params)+len(c.fn.locals) {
+			return fmt.Errorf("invalid local %d", idx)
+		}
+		return c.simple(instr{op: op, x: idx}, stackEffect[op])
+	case op == opGlobalGet || op == opGlobalSet:
+		idx := r.u32()
+		if int(idx) >= len(c.m.globals) {
+			return fmt.Errorf("invalid global %d", idx)
+		}
+		if op == opGlobalSet && !c.m.globals[idx].mutable {
+			return fmt.Errorf("global %d is immutable", idx)
+		}
+		return c.simple(instr{op: op, x: idx}, stackEffect[op])
+	case op >= opI32Load && op <= opI64Store32:
+		offset, err := c.memarg()
+		if err != nil {
+			return err
+		}
+		return c.simple(instr{op: op, z: offset}, stackEffect[op])
+	case op == opMemorySize || op == opMemoryGrow:
+		if r.byte() != 0 || c.m.memory == nil {
+			return fmt.Errorf("memory instruction without a memory")
+		}
+		return c.simple(instr{op: op}, stackEffect[op])
+	case op == opI32Const:
+		return c.simple(instr{op: op, z: uint64(uint32(r.s32()))}, stackEffect[op])
+	case op == opI64Const:
+		return c.simple(instr{op: op, z: uint64(r.s64())}, stackEffect[op])
+	case op == opF32Const:
+		return c.simple(instr{op: op, z: uint64(r.u32le())}, stackEffect[op])
+	case op == opF64Const:
+		return c.simple(instr{op: op, z: r.u64le()}, stackEffect[op])
+	case op >= opI32Eqz && op <= opI64Extend32S:
+		return c.simple(instr{op: op}, stackEffect[op])
+	case op == opPrefixFC:
+		return c.prefixFC()
+	default:
+		return fmt.Errorf("unsupported opcode 0x%02x", op)
+	}
+	return r.err
+}
+
+// prefixFC 编译0xFC前缀的非陷入浮点转整数与批量内存指令
+func (c *compiler) prefixFC() error {
+	r := c.r
+	sub := r.u32()
+	switch {
+	case sub <= 7:
+		return c.simple(instr{op: opTruncSatBase + byte(sub)}, effect{1, 1})
+	case sub == 8:
+		idx := r.u32()
+		if r.byte() != 0 || c.m.memory == nil {
+			return fmt.Errorf("memory.init without a memory")
+		}
+		return c.simple(instr{op: opMemoryInit, x: idx}, effect{3, 0})
+	case sub == 9:
+		return c.simple(instr{op: opDataDrop, x: r.u32()}, effect{0, 0})
+	case sub == 10:
+		if r.byte() != 0 || r.byte() != 0 || c.m.memory == nil {
+			return fmt.Errorf("memory.copy without a memory")
+		}
+		return c.simple(instr{op: opMemoryCopy}, effect{3, 0})
+	case sub == 11:
+		if r.byte() != 0 || c.m.memory == nil {
+			return fmt.Errorf("memory.fill without a memory")
+		}
+		return c.simple(instr{op: opMemoryFill}, effect{3, 0})
+	}
+	return fmt.Errorf("unsupported opcode 0xfc %d", sub)
+}
+
+func (c *compiler) simple(in instr, e effect) error {
+	if c.r.err != nil {
+		return c.r.err
+	}
+	if err := c.pop(int(e.pops)); err != nil {
+		return err
+	}
+	c.push(int(e.pushes))
+	c.emit(in)
+	return nil
+}
+
+// effect 指令弹出与压入的值个数
+type effect struct {
+	pops, pushes uint8
+}
+
+// stackEffect 固定栈效果的指令
+var stackEffect = func() [256]effect {
+	var t [256]effect
+	t[opLocalGet] = effect{0, 1}
+	t[opLocalSet] = effect{1, 0}
+	t[opLocalTee] = effect{1, 1}
+	t[opGlobalGet] = effect{0, 1}
+	t[opGlobalSet] = effect{1, 0}
+	for op := 0x28; op <= 0x35; op++ { // 加载
+		t[op] = effect{1, 1}
+	}
+	for op := 0x36; op <= 0x3E; op++ { // 存储
+		t[op] = effect{2, 0}
+	}
+	t[opMemorySize] = effect{0, 1}
+	t[opMemoryGrow] = effect{1, 1}
+	for op := opI32Const; op <= opF64Const; op++ {
+		t[op] = effect{0, 1}
+	}
+	for op := 0x45; op <= 0xC4; op++ {
+		t[op] = effect{2, 1} // 比较与二元运算
+	}
+	for _, op := range []int{0x45, 0x50} { // eqz
+		t[op] = effect{1, 1}
+	}
+	for _, r := range [][2]int{{0x67, 0x69}, {0x79, 0x7B}, {0x8B, 0x91}, {0x99, 0x9F}, {0xA7, 0xC4}} { // 一元运算与转换
+		for op := r[0]; op <= r[1]; op++ {
+			t[op] = effect{1, 1}
+		}
+	}
+	return t
+}()
@@ -0,0 +1,669 @@
+package wasm
+
+import (
+	"encoding/binary"
+	"math"
+	"math/bits"
+)
+
+// call 执行函数fidx，参数位于stack[fp:]，返回时结果写回stack[fp:]
+func (in *Instance) call(fidx uint32, fp int) {
+	in.depth++
+	if in.depth > in.limits.MaxCallDepth {
+		trap("call stack exhausted")
+	}
+	fn := in.module.funcs[fidx]
+	typ := in.module.types[fn.typ]
+	base := fp + len(typ.Params) + len(fn.locals) // 操作数栈底
+	in.ensureStack(base + fn.maxHeight)
+	stack := in.stack
+	for i := fp + len(typ.Params); i < base; i++ {
+		stack[i] = 0
+	}
+	sp := base
+	code := fn.code
+
+	for pc := 0; ; pc++ {
+		if in.fuel == 0 {
+			panic(ErrFuelExhausted)
+		}
+		in.fuel--
+		ins := &code[pc]
+		switch ins.op {
+		case opUnreachable:
+			trap("unreachable executed")
+		case opBlock, opLoop:
+		case opIf:
+			sp--
+			if uint32(stack[sp]) == 0 {
+				pc = int(ins.x) - 1
+			}
+		case opElse:
+			pc = int(ins.x) - 1
+		case opEnd:
+			if pc == len(code)-1 {
+				n := len(typ.Results)
+				copy(stack[fp:fp+n], stack[sp-n:sp])
+				in.depth--
+				return
+			}
+		case opBr:
+			sp = branch(stack, sp, base, ins.y, ins.w)
+			pc = int(ins.x) - 1
+		case opBrIf:
+			sp--
+			if uint32(stack[sp]) != 0 {
+				sp = branch(stack, sp, base, ins.y, ins.w)
+				pc = int(ins.x) - 1
+			}
+		case opBrTable:
+			sp--
+			targets := fn.tables[ins.x]
+			i := stack[sp]
+			if i >= uint64(len(targets)-1) {
+				i = uint64(len(targets) - 1)
+			}
+			t := targets[i]
+			sp = branch(stack, sp, base, t.arity, t.height)
+			pc = int(t.pc) - 1
+		case opCall:
+			callee := in.module.types[in.module.funcs[ins.x].typ]
+			args := sp - len(callee.Params)
+			in.call(ins.x, args)
+			stack = in.stack
+			sp = args + len(callee.Results)
+		case opCallIndirect:
+			sp--
+			i := uint32(stack[sp])
+			if i >= uint32(len(in.table)) || in.table[i] < 0 {
+				trap("undefined table element %d", i)
+			}
+			callee := uint32(in.table[i])
+			want := in.module.types[ins.x]
+			if got := in.module.types[in.module.funcs[callee].typ]; !sameType(got, want) {
+				trap("indirect call type mismatch: expected %s, got %s", want, got)
+			}
+			args := sp - len(want.Params)
+			in.call(callee, args)
+			stack = in.stack
+			sp = args + len(want.Results)
+		case opDrop:
+			sp--
+		case opSelect:
+			sp -= 2
+			if uint32(stack[sp+1]) == 0 {
+				stack[sp-1] = stack[sp]
+			}
+		case opLocalGet:
+			stack[sp] = stack[fp+int(ins.x)]
+			sp++
+		case opLocalSet:
+			sp--
+			stack[fp+int(ins.x)] = stack[sp]
+		case opLocalTee:
+			stack[fp+int(ins.x)] = stack[sp-1]
+		case opGlobalGet:
+			stack[sp] = in.globals[ins.x]
+			sp++
+		case opGlobalSet:
+			sp--
+			in.globals[ins.x] = stack[sp]
+		case opMemorySize:
+			stack[sp] = uint64(len(in.memory) / pageSize)
+			sp++
+		case opMemoryGrow:
+			stack[sp-1] = uint64(uint32(in.grow(uint32(stack[sp-1]))))
+		case opI32Const, opI64Const, opF32Const, opF64Const:
+			stack[sp] = ins.z
+			sp++
+		case opMemoryInit:
+			sp -= 3
+			in.memoryInit(ins.x, uint32(stack[sp]), uint32(stack[sp+1]), uint32(stack[sp+2]))
+		case opDataDrop:
+			if int(ins.x) >= len(in.dropped) {
+				trap("invalid data segment %d", ins.x)
+			}
+			in.dropped[ins.x] = true
+		case opMemoryCopy:
+			sp -= 3
+			dst, src, n := uint64(uint32(stack[sp])), uint64(uint32(stack[sp+1])), uint64(uint32(stack[sp+2]))
+			if src+n > uint64(len(in.memory)) || dst+n > uint64(len(in.memory)) {
+				trap("out of bounds memory access")
+			}
+			copy(in.memory[dst:dst+n], in.memory[src:src+n])
+		case opMemoryFill:
+			sp -= 3
+			dst, v, n := uint64(uint32(stack[sp])), byte(stack[sp+1]), uint64(uint32(stack[sp+2]))
+			if dst+n > uint64(len(in.memory)) {
+				trap("out of bounds memory access")
+			}
+			mem := in.memory[dst : dst+n]
+			for i := range mem {
+				mem[i] = v
+			}
+		default:
+			switch {
+			case ins.op >= 0x28 && ins.op <= 0x35:
+				stack[sp-1] = in.load(ins.op, stack[sp-1], ins.z)
+			case ins.op >= 0x36 && ins.op <= 0x3E:
+				sp -= 2
+				in.store(ins.op, stack[sp], ins.z, stack[sp+1])
+			case stackEffect[ins.op].pops == 2:
+				sp--
+				stack[sp-1] = binop(ins.op, stack[sp-1], stack[sp])
+			default:
+				stack[sp-1] = unop(ins.op, stack[sp-1])
+			}
+		}
+	}
+}
+
+// branch 保留栈顶arity个值并将操作数栈降到标签的高度，返回新的栈顶
+func branch(stack []uint64, sp, base int, arity, height uint32) int {
+	to := base + int(height)
+	n := int(arity)
+	if to != sp-n {
+		copy(stack[to:to+n], stack[sp-n:sp])
+	}
+	return to + n
+}
+
+func sameType(a, b FuncType) bool {
+	return string(a.Params) == string(b.Params) && string(a.Results) == string(b.Results)
+}
+
+// grow 将内存增加delta页，返回原页数，超出上限时返回-1
+func (in *Instance) grow(delta uint32) int32 {
+	old := uint32(len(in.memory) / pageSize)
+	max := in.limits.MaxMemoryPages
+	if m := in.module.memory; m.hasMax && m.max < max {
+		max = m.max
+	}
+	if uint64(old)+uint64(delta) > uint64(max) {
+		return -1
+	}
+	if delta > 0 {
+		grown := make([]byte, int(old+delta)*pageSize)
+		copy(grown, in.memory)
+		in.memory = grown
+	}
+	return int32(old)
+}
+
+func (in *Instance) memoryInit(seg, dst, src, n uint32) {
+	if int(seg) >= len(in.module.data) {
+		trap("invalid data segment %d", seg)
+	}
+	var data []byte
+	if !in.dropped[seg] {
+		data = in.module.data[seg].data
+	}
+	if uint64(src)+uint64(n) > uint64(len(data)) || uint64(dst)+uint64(n) > uint64(len(in.memory)) {
+		trap("out of bounds memory access")
+	}
+	copy(in.memory[dst:], data[src:src+n])
+}
+
+// addr 计算有效地址并检查越界
+func (in *Instance) addr(base, offset uint64, size uint64) uint64 {
+	ea := uint64(uint32(base)) + offset
+	if ea+size > uint64(len(in.memory)) {
+		trap("out of bounds memory access")
+	}
+	return ea
+}
+
+func (in *Instance) load(op byte, base, offset uint64) uint64 {
+	mem := in.memory
+	switch op {
+	case 0x28: // i32.load
+		return uint64(binary.LittleEndian.Uint32(mem[in.addr(base, offset, 4):]))
+	case 0x29: // i64.load
+		return binary.LittleEndian.Uint64(mem[in.addr(base, offset, 8):])
+	case 0x2A: // f32.load
+		return uint64(binary.LittleEndian.Uint32(mem[in.addr(base, offset, 4):]))
+	case 0x2B: // f64.load
+		return binary.LittleEndian.Uint64(mem[in.addr(base, offset, 8):])
+	case 0x2C: // i32.load8_s
+		return uint64(uint32(int32(int8(mem[in.addr(base, offset, 1)]))))
+	case 0x2D: // i32.load8_u
+		return uint64(mem[in.addr(base, offset, 1)])
+	case 0x2E: // i32.load16_s
+		return uint64(uint32(int32(int16(binary.LittleEndian.Uint16(mem[in.addr(base, offset, 2):])))))
+	case 0x2F: // i32.load16_u
+		return uint64(binary.LittleEndian.Uint16(mem[in.addr(base, offset, 2):]))
+	case 0x30: // i64.load8_s
+		return uint64(int64(int8(mem[in.addr(base, offset, 1)])))
+	case 0x31: // i64.load8_u
+		return uint64(mem[in.addr(base, offset, 1)])
+	case 0x32: // i64.load16_s
+		return uint64(int64(int16(binary.LittleEndian.Uint16(mem[in.addr(base, offset, 2):]))))
+	case 0x33: // i64.load16_u
+		return uint64(binary.LittleEndian.Uint16(mem[in.addr(base, offset, 2):]))
+	case 0x34: // i64.load32_s
+		return uint64(int64(int32(binary.LittleEndian.Uint32(mem[in.addr(base, offset, 4):]))))
+	default: // i64.load32_u
+		return uint64(binary.LittleEndian.Uint32(mem[in.addr(base, offset, 4):]))
+	}
+}
+
+func (in *Instance) store(op byte, base, offset, v uint64) {
+	mem := in.memory
+	switch op {
+	case 0x36, 0x38, 0x3E: // i32.store, f32.store, i64.store32
+		binary.LittleEndian.PutUint32(mem[in.addr(base, offset, 4):], uint32(v))
+	case 0x37, 0x39: // i64.store, f64.store
+		binary.LittleEndian.PutUint64(mem[in.addr(base, offset, 8):], v)
+	case 0x3A, 0x3C: // i32.store8, i64.store8
+		mem[in.addr(base, offset, 1)] = byte(v)
+	default: // i32.store16, i64.store16
+		binary.LittleEndian.PutUint16(mem[in.addr(base, offset, 2):], uint16(v))
+	}
+}
+
+func b2u(b bool) uint64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func f32(v uint64) float32 { return math.Float32frombits(uint32(v)) }
+func f64(v uint64) float64 { return math.Float64frombits(v) }
+
+// binop 比较与二元运算
+func binop(op byte, a, b uint64) uint64 {
+	x32, y32 := uint32(a), uint32(b)
+	switch op {
+	// i32比较
+	case 0x46:
+		return b2u(x32 == y32)
+	case 0x47:
+		return b2u(x32 != y32)
+	case 0x48:
+		return b2u(int32(x32) < int32(y32))
+	case 0x49:
+		return b2u(x32 < y32)
+	case 0x4A:
+		return b2u(int32(x32) > int32(y32))
+	case 0x4B:
+		return b2u(x32 > y32)
+	case 0x4C:
+		return b2u(int32(x32) <= int32(y32))
+	case 0x4D:
+		return b2u(x32 <= y32)
+	case 0x4E:
+		return b2u(int32(x32) >= int32(y32))
+	case 0x4F:
+		return b2u(x32 >= y32)
+	// i64比较
+	case 0x51:
+		return b2u(a == b)
+	case 0x52:
+		return b2u(a != b)
+	case 0x53:
+		return b2u(int64(a) < int64(b))
+	case 0x54:
+		return b2u(a < b)
+	case 0x55:
+		return b2u(int64(a) > int64(b))
+	case 0x56:
+		return b2u(a > b)
+	case 0x57:
+		return b2u(int64(a) <= int64(b))
+	case 0x58:
+		return b2u(a <= b)
+	case 0x59:
+		return b2u(int64(a) >= int64(b))
+	case 0x5A:
+		return b2u(a >= b)
+	// f32比较
+	case 0x5B:
+		return b2u(f32(a) == f32(b))
+	case 0x5C:
+		return b2u(f32(a) != f32(b))
+	case 0x5D:
+		return b2u(f32(a) < f32(b))
+	case 0x5E:
+		return b2u(f32(a) > f32(b))
+	case 0x5F:
+		return b2u(f32(a) <= f32(b))
+	case 0x60:
+		return b2u(f32(a) >= f32(b))
+	// f64比较
+	case 0x61:
+		return b2u(f64(a) == f64(b))
+	case 0x62:
+		return b2u(f64(a) != f64(b))
+	case 0x63:
+		return b2u(f64(a) < f64(b))
+	case 0x64:
+		return b2u(f64(a) > f64(b))
+	case 0x65:
+		return b2u(f64(a) <= f64(b))
+	case 0x66:
+		return b2u(f64(a) >= f64(b))
+	// i32运算
+	case 0x6A:
+		return uint64(x32 + y32)
+	case 0x6B:
+		return uint64(x32 - y32)
+	case 0x6C:
+		return uint64(x32 * y32)
+	case 0x6D:
+		if y32 == 0 {
+			trap("integer divide by zero")
+		}
+		if int32(x32) == math.MinInt32 && int32(y32) == -1 {
+			trap("integer overflow")
+		}
+		return uint64(uint32(int32(x32) / int32(y32)))
+	case 0x6E:
+		if y32 == 0 {
+			trap("integer divide by zero")
+		}
+		return uint64(x32 / y32)
+	case 0x6F:
+		if y32 == 0 {
+			trap("integer divide by zero")
+		}
+		if int32(y32) == -1 {
+			return 0
+		}
+		return uint64(uint32(int32(x32) % int32(y32)))
+	case 0x70:
+		if y32 == 0 {
+			trap("integer divide by zero")
+		}
+		return uint64(x32 % y32)
+	case 0x71:
+		return uint64(x32 & y32)
+	case 0x72:
+		return uint64(x32 | y32)
+	case 0x73:
+		return uint64(x32 ^ y32)
+	case 0x74:
+		return uint64(x32 << (y32 & 31))
+	case 0x75:
+		return uint64(uint32(int32(x32) >> (y32 & 31)))
+	case 0x76:
+		return uint64(x32 >> (y32 & 31))
+	case 0x77:
+		return uint64(bits.RotateLeft32(x32, int(y32&31)))
+	case 0x78:
+		return uint64(bits.RotateLeft32(x32, -int(y32&31)))
+	// i64运算
+	case 0x7C:
+		return a + b
+	case 0x7D:
+		return a - b
+	case 0x7E:
+		return a * b
+	case 0x7F:
+		if b == 0 {
+			trap("integer divide by zero")
+		}
+		if int64(a) == math.MinInt64 && int64(b) == -1 {
+			trap("integer overflow")
+		}
+		return uint64(int64(a) / int64(b))
+	case 0x80:
+		if b == 0 {
+			trap("integer divide by zero")
+		}
+		return a / b
+	case 0x81:
+		if b == 0 {
+			trap("integer divide by zero")
+		}
+		if int64(b) == -1 {
+			return 0
+		}
+		return uint64(int64(a) % int64(b))
+	case 0x82:
+		if b == 0 {
+			trap("integer divide by zero")
+		}
+		return a % b
+	case 0x83:
+		return a & b
+	case 0x84:
+		return a | b
+	case 0x85:
+		return a ^ b
+	case 0x86:
+		return a << (b & 63)
+	case 0x87:
+		return uint64(int64(a) >> (b & 63))
+	case 0x88:
+		return a >> (b & 63)
+	case 0x89:
+		return bits.RotateLeft64(a, int(b&63))
+	case 0x8A:
+		return bits.RotateLeft64(a, -int(b&63))
+	// f32运算
+	case 0x92:
+		return f32bits(f32(a) + f32(b))
+	case 0x93:
+		return f32bits(f32(a) - f32(b))
+	case 0x94:
+		return f32bits(f32(a) * f32(b))
+	case 0x95:
+		return f32bits(f32(a) / f32(b))
+	case 0x96:
+		return f32bits(float32(fmin(float64(f32(a)), float64(f32(b)))))
+	case 0x97:
+		return f32bits(float32(fmax(float64(f32(a)), float64(f32(b)))))
+	case 0x98:
+		return uint64(x32&0x7FFFFFFF | y32&0x80000000)
+	// f64运算
+	case 0xA0:
+		return f64bits(f64(a) + f64(b))
+	case 0xA1:
+		return f64bits(f64(a) - f64(b))
+	case 0xA2:
+		return f64bits(f64(a) * f64(b))
+	case 0xA3:
+		return f64bits(f64(a) / f64(b))
+	case 0xA4:
+		return f64bits(fmin(f64(a), f64(b)))
+	case 0xA5:
+		return f64bits(fmax(f64(a), f64(b)))
+	default: // f64.copysign
+		return a&0x7FFFFFFFFFFFFFFF | b&0x8000000000000000
+	}
+}
+
+// fmin与fmax按WebAssembly语义处理NaN与带符号零
+func fmin(a, b float64) float64 {
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return math.NaN()
+	}
+	return math.Min(a, b)
+}
+
+func fmax(a, b float64) float64 {
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return math.NaN()
+	}
+	return math.Max(a, b)
+}
+
+// unop 一元运算、测试与类型转换
+func unop(op byte, a uint64) uint64 {
+	x32 := uint32(a)
+	switch op {
+	case opI32Eqz:
+		return b2u(x32 == 0)
+	case opI64Eqz:
+		return b2u(a == 0)
+	case 0x67:
+		return uint64(bits.LeadingZeros32(x32))
+	case 0x68:
+		return uint64(bits.TrailingZeros32(x32))
+	case 0x69:
+		return uint64(bits.OnesCount32(x32))
+	case 0x79:
+		return uint64(bits.LeadingZeros64(a))
+	case 0x7A:
+		return uint64(bits.TrailingZeros64(a))
+	case 0x7B:
+		return uint64(bits.OnesCount64(a))
+	// f32一元运算
+	case 0x8B:
+		return uint64(x32 & 0x7FFFFFFF)
+	case 0x8C:
+		return uint64(x32 ^ 0x80000000)
+	case 0x8D:
+		return f32bits(float32(math.Ceil(float64(f32(a)))))
+	case 0x8E:
+		return f32bits(float32(math.Floor(float64(f32(a)))))
+	case 0x8F:
+		return f32bits(float32(math.Trunc(float64(f32(a)))))
+	case 0x90:
+		return f32bits(float32(math.RoundToEven(float64(f32(a)))))
+	case 0x91:
+		return f32bits(float32(math.Sqrt(float64(f32(a)))))
+	// f64一元运算
+	case 0x99:
+		return a & 0x7FFFFFFFFFFFFFFF
+	case 0x9A:
+		return a ^ 0x8000000000000000
+	case 0x9B:
+		return f64bits(math.Ceil(f64(a)))
+	case 0x9C:
+		return f64bits(math.Floor(f64(a)))
+	case 0x9D:
+		return f64bits(math.Trunc(f64(a)))
+	case 0x9E:
+		return f64bits(math.RoundToEven(f64(a)))
+	case 0x9F:
+		return f64bits(math.Sqrt(f64(a)))
+	// 转换
+	case 0xA7: // i32.wrap_i64
+		return uint64(x32)
+	case 0xA8: // i32.trunc_f32_s
+		return uint64(uint32(int32(truncChecked(float64(f32(a)), -2147483649, 2147483648))))
+	case 0xA9: // i32.trunc_f32_u
+		return uint64(uint32(truncChecked(float64(f32(a)), -1, 4294967296)))
+	case 0xAA: // i32.trunc_f64_s
+		return uint64(uint32(int32(truncChecked(f64(a), -2147483649, 2147483648))))
+	case 0xAB: // i32.trunc_f64_u
+		return uint64(uint32(truncChecked(f64(a), -1, 4294967296)))
+	case 0xAC: // i64.extend_i32_s
+		return uint64(int64(int32(x32)))
+	case 0xAD: // i64.extend_i32_u
+		return uint64(x32)
+	case 0xAE: // i64.trunc_f32_s
+		return uint64(int64(truncChecked(float64(f32(a)), -9223372036854777856, 9223372036854775808)))
+	case 0xAF: // i64.trunc_f32_u
+		return truncU64(truncChecked(float64(f32(a)), -1, 18446744073709551616))
+	case 0xB0: // i64.trunc_f64_s
+		return uint64(int64(truncChecked(f64(a), -9223372036854777856, 9223372036854775808)))
+	case 0xB1: // i64.trunc_f64_u
+		return truncU64(truncChecked(f64(a), -1, 18446744073709551616))
+	case 0xB2: // f32.convert_i32_s
+		return f32bits(float32(int32(x32)))
+	case 0xB3: // f32.convert_i32_u
+		return f32bits(float32(x32))
+	case 0xB4: // f32.convert_i64_s
+		return f32bits(float32(int64(a)))
+	case 0xB5: // f32.convert_i64_u
+		return f32bits(float32(a))
+	case 0xB6: // f32.demote_f64
+		return f32bits(float32(f64(a)))
+	case 0xB7: // f64.convert_i32_s
+		return f64bits(float64(int32(x32)))
+	case 0xB8: // f64.convert_i32_u
+		return f64bits(float64(x32))
+	case 0xB9: // f64.convert_i64_s
+		return f64bits(float64(int64(a)))
+	case 0xBA: // f64.convert_i64_u
+		return f64bits(float64(a))
+	case 0xBB: // f64.promote_f32
+		return f64bits(float64(f32(a)))
+	case 0xBC, 0xBD, 0xBE, 0xBF: // 位重解释
+		return a
+	case 0xC0: // i32.extend8_s
+		return uint64(uint32(int32(int8(a))))
+	case 0xC1: // i32.extend16_s
+		return uint64(uint32(int32(int16(a))))
+	case 0xC2: // i64.extend8_s
+		return uint64(int64(int8(a)))
+	case 0xC3: // i64.extend16_s
+		return uint64(int64(int16(a)))
+	case opI64Extend32S:
+		return uint64(int64(int32(a)))
+	// 非陷入转换，NaN为0，溢出时取饱和值
+	case opTruncSatBase + 0: // i32.trunc_sat_f32_s
+		return uint64(uint32(int32(truncSat(float64(f32(a)), math.MinInt32, math.MaxInt32))))
+	case opTruncSatBase + 1: // i32.trunc_sat_f32_u
+		return uint64(uint32(truncSat(float64(f32(a)), 0, math.MaxUint32)))
+	case opTruncSatBase + 2: // i32.trunc_sat_f64_s
+		return uint64(uint32(int32(truncSat(f64(a), math.MinInt32, math.MaxInt32))))
+	case opTruncSatBase + 3: // i32.trunc_sat_f64_u
+		return uint64(uint32(truncSat(f64(a), 0, math.MaxUint32)))
+	case opTruncSatBase + 4: // i64.trunc_sat_f32_s
+		return truncSatS64(float64(f32(a)))
+	case opTruncSatBase + 5: // i64.trunc_sat_f32_u
+		return truncSatU64(float64(f32(a)))
+	case opTruncSatBase + 6: // i64.trunc_sat_f64_s
+		return truncSatS64(f64(a))
+	default: // i64.trunc_sat_f64_u
+		return truncSatU64(f64(a))
+	}
+}
+
+// truncChecked 向零取整，NaN或结果不在开区间(lo, hi)内时陷入
+func truncChecked(v, lo, hi float64) float64 {
+	if math.IsNaN(v) {
+		trap("invalid conversion to integer")
+	}
+	t := math.Trunc(v)
+	if t <= lo || t >= hi {
+		trap("integer overflow")
+	}
+	return t
+}
+
+// truncU64 将[0, 2^64)内的整数值转换为uint64，超过2^63的值不能直接转换
+func truncU64(t float64) uint64 {
+	if t >= 9223372036854775808 {
+		return uint64(t-9223372036854775808) | 1<<63
+	}
+	return uint64(t)
+}
+
+func truncSat(v, lo, hi float64) int64 {
+	switch {
+	case math.IsNaN(v):
+		return 0
+	case v <= lo:
+		return int64(lo)
+	case v >= hi:
+		return int64(hi)
+	}
+	return int64(math.Trunc(v))
+}
+
+func truncSatS64(v float64) uint64 {
+	switch {
+	case math.IsNaN(v):
+		return 0
+	case v <= math.MinInt64:
+		return 1 << 63
+	case v >= 9223372036854775808:
+		return math.MaxInt64
+	}
+	return uint64(int64(math.Trunc(v)))
+}
+
+func truncSatU64(v float64) uint64 {
+	switch {
+	case math.IsNaN(v) || v <= 0:
+		return 0
+	case v >= 18446744073709551616:
+		return math.MaxUint64
+	}
+	return truncU64(math.Trunc(v))
+}
@@ -0,0 +1,239 @@
+package wasm
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+)
+
+// Limits 沙箱的资源上限
+type Limits struct {
+	// MaxMemoryPages 线性内存的页数上限（每页64KiB），模块声明的初始页数超出时实例化失败，memory.grow超出时返回-1
+	MaxMemoryPages uint32 `json:"max_memory_pages" yaml:"max_memory_pages"`
+	// Fuel 单次Call可执行的指令数上限，耗尽时陷入，防止死循环占用服务端
+	Fuel uint64 `json:"fuel" yaml:"fuel"`
+	// MaxCallDepth 调用深度上限
+	MaxCallDepth int `json:"max_call_depth" yaml:"max_call_depth"`
+	// MaxStack 操作数栈与局部变量的槽位总数上限
+	MaxStack int `json:"max_stack" yaml:"max_stack"`
+}
+
+// DefaultLimits 返回默认资源上限：16MiB内存、每次调用一千万条指令
+func DefaultLimits() Limits {
+	return Limits{
+		MaxMemoryPages: 256,
+		Fuel:           10_000_000,
+		MaxCallDepth:   1000,
+		MaxStack:       1 << 20,
+	}
+}
+
+// withDefaults 以默认值补全未设置（零值）的上限
+func (l Limits) withDefaults() Limits {
+	def := DefaultLimits()
+	if l.MaxMemoryPages == 0 {
+		l.MaxMemoryPages = def.MaxMemoryPages
+	}
+	if l.Fuel == 0 {
+		l.Fuel = def.Fuel
+	}
+	if l.MaxCallDepth <= 0 {
+		l.MaxCallDepth = def.MaxCallDepth
+	}
+	if l.MaxStack <= 0 {
+		l.MaxStack = def.MaxStack
+	}
+	return l
+}
+
+// Trap 模块执行时陷入（越界访问、除零、unreachable、资源耗尽等），陷入后实例状态可能不一致
+type Trap struct {
+	Reason string
+}
+
+func (t *Trap) Error() string { return "wasm trap: " + t.Reason }
+
+// ErrFuelExhausted 单次调用执行的指令数超过Limits.Fuel
+var ErrFuelExhausted = &Trap{Reason: "fuel exhausted"}
+
+func trap(format string, args ...interface{}) {
+	panic(&Trap{Reason: fmt.Sprintf(format, args...)})
+}
+
+// Instance 模块的一个实例，拥有独立的线性内存、全局变量与表，非并发安全
+type Instance struct {
+	module  *Module
+	limits  Limits
+	memory  []byte
+	globals []uint64
+	table   []int32 // 函数下标，-1为空
+	dropped []bool  // 已被data.drop的数据段
+
+	stack []uint64
+	fuel  uint64
+	depth int
+}
+
+// Instantiate 创建实例：分配内存、初始化全局变量、表与数据段，然后执行start函数
+func (m *Module) Instantiate(limits Limits) (*Instance, error) {
+	limits = limits.withDefaults()
+	in := &Instance{module: m, limits: limits, dropped: make([]bool, len(m.data))}
+	if m.memory != nil {
+		if m.memory.min > limits.MaxMemoryPages {
+			return nil, fmt.Errorf("wasm: module requires %d memory pages, limit is %d", m.memory.min, limits.MaxMemoryPages)
+		}
+		in.memory = make([]byte, int(m.memory.min)*pageSize)
+	}
+	in.globals = make([]uint64, len(m.globals))
+	for i, g := range m.globals {
+		in.globals[i] = g.init
+	}
+	if m.table != nil {
+		if m.table.min > 1<<20 {
+			return nil, fmt.Errorf("wasm: table too large")
+		}
+		in.table = make([]int32, m.table.min)
+		for i := range in.table {
+			in.table[i] = -1
+		}
+	}
+	for _, seg := range m.elements {
+		if uint64(seg.offset)+uint64(len(seg.funcs)) > uint64(len(in.table)) {
+			return nil, fmt.Errorf("wasm: element segment out of bounds")
+		}
+		for i, f := range seg.funcs {
+			in.table[int(seg.offset)+i] = int32(f)
+		}
+	}
+	for i, seg := range m.data {
+		if seg.passive {
+			continue
+		}
+		if uint64(seg.offset)+uint64(len(seg.data)) > uint64(len(in.memory)) {
+			return nil, fmt.Errorf("wasm: data segment out of bounds")
+		}
+		copy(in.memory[seg.offset:], seg.data)
+		in.dropped[i] = true
+	}
+	if m.start != nil {
+		if err := in.run(*m.start, nil); err != nil {
+			return nil, err
+		}
+	}
+	return in, nil
+}
+
+// Call 调用导出函数，参数与返回值以位表示传递（i32为零扩展的uint32，浮点数为IEEE 754位）
+func (in *Instance) Call(name string, args ...uint64) ([]uint64, error) {
+	e, ok := in.module.exports[name]
+	if !ok || e.kind != exportFunc {
+		return nil, fmt.Errorf("wasm: function %q is not exported", name)
+	}
+	if n := len(in.module.types[in.module.funcs[e.index].typ].Params); n != len(args) {
+		return nil, fmt.Errorf("wasm: function %q takes %d arguments, got %d", name, n, len(args))
+	}
+	results := in.module.types[in.module.funcs[e.index].typ].Results
+	if err := in.run(e.index, args); err != nil {
+		return nil, err
+	}
+	out := make([]uint64, len(results))
+	copy(out, in.stack[:len(results)])
+	return out, nil
+}
+
+// run 以新的燃料执行函数，陷入（含Go运行时的越界等错误）转换为*Trap
+func (in *Instance) run(fidx uint32, args []uint64) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			switch v := r.(type) {
+			case *Trap:
+				err = v
+			case runtime.Error:
+				err = &Trap{Reason: v.Error()}
+			default:
+				panic(r)
+			}
+		}
+	}()
+	in.fuel = in.limits.Fuel
+	in.depth = 0
+	in.ensureStack(len(args))
+	copy(in.stack, args)
+	in.call(fidx, 0)
+	return nil
+}
+
+// ensureStack 确保栈至少有n个槽位
+func (in *Instance) ensureStack(n int) {
+	if n <= len(in.stack) {
+		return
+	}
+	if n > in.limits.MaxStack {
+		trap("stack overflow")
+	}
+	size := 2 * len(in.stack)
+	if size < 256 {
+		size = 256
+	}
+	for size < n {
+		size *= 2
+	}
+	if size > in.limits.MaxStack {
+		size = in.limits.MaxStack
+	}
+	grown := make([]uint64, size)
+	copy(grown, in.stack)
+	in.stack = grown
+}
+
+// Memory 返回实例的线性内存，memory.grow后底层数组会改变，不应长期持有
+func (in *Instance) Memory() []byte {
+	return in.memory
+}
+
+// State 实例的可变状态：线性内存、可变全局变量与表，用于检查点
+type State struct {
+	Memory  []byte   `json:"memory"`
+	Globals []uint64 `json:"globals"`
+	Table   []int32  `json:"table,omitempty"`
+	Dropped []bool   `json:"dropped,omitempty"`
+}
+
+// Save 复制实例的当前状态
+func (in *Instance) Save() State {
+	return State{
+		Memory:  append([]byte(nil), in.memory...),
+		Globals: append([]uint64(nil), in.globals...),
+		Table:   append([]int32(nil), in.table...),
+		Dropped: append([]bool(nil), in.dropped...),
+	}
+}
+
+// Restore 恢复Save保存的状态，状态须来自同一模块的实例
+func (in *Instance) Restore(s State) error {
+	if len(s.Globals) != len(in.globals) || len(s.Table) != len(in.table) || len(s.Dropped) != len(in.dropped) {
+		return errors.New("wasm: state does not match the module")
+	}
+	if len(s.Memory)%pageSize != 0 || len(s.Memory) < in.module.minMemory() ||
+		uint32(len(s.Memory)/pageSize) > in.limits.MaxMemoryPages {
+		return errors.New("wasm: invalid memory size in state")
+	}
+	for i, v := range s.Table {
+		if v < -1 || int(v) >= len(in.module.funcs) {
+			return fmt.Errorf("wasm: invalid table entry %d in state", i)
+		}
+	}
+	in.memory = append(in.memory[:0:0], s.Memory...)
+	copy(in.globals, s.Globals)
+	copy(in.table, s.Table)
+	copy(in.dropped, s.Dropped)
+	return nil
+}
+
+// minMemory 返回线性内存的初始字节数
+func (m *Module) minMemory() int {
+	if m.memory == nil {
+		return 0
+	}
+	return int(m.memory.min) * pageSize
+}
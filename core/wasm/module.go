@@ -0,0 +1,548 @@
+package wasm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// 解码WebAssembly 1.0（MVP）二进制模块，另支持多返回值、符号扩展、非陷入浮点转整数与批量内存操作，
+// 即主流编译器（clang、rustc、TinyGo）默认产生的指令集。
+// 模块必须自包含：不支持导入，沙箱内的代码只能访问自己的线性内存
+
+// 值类型
+const (
+	I32 byte = 0x7F
+	I64 byte = 0x7E
+	F32 byte = 0x7D
+	F64 byte = 0x7C
+)
+
+const (
+	funcRef   byte = 0x70
+	pageSize       = 64 << 10
+	maxPages       = 1 << 16
+	wasmMagic      = "\x00asm"
+)
+
+// 段编号
+const (
+	sectionCustom    = 0
+	sectionType      = 1
+	sectionImport    = 2
+	sectionFunction  = 3
+	sectionTable     = 4
+	sectionMemory    = 5
+	sectionGlobal    = 6
+	sectionExport    = 7
+	sectionStart     = 8
+	sectionElement   = 9
+	sectionCode      = 10
+	sectionData      = 11
+	sectionDataCount = 12
+)
+
+// 导出类型
+const (
+	exportFunc   = 0
+	exportTable  = 1
+	exportMemory = 2
+	exportGlobal = 3
+)
+
+// ErrImportsUnsupported 模块声明了导入，沙箱不向模块提供任何宿主函数
+var ErrImportsUnsupported = errors.New("wasm: modules with imports are not supported")
+
+// FuncType 函数签名
+type FuncType struct {
+	Params  []byte
+	Results []byte
+}
+
+func (t FuncType) String() string {
+	name := func(types []byte) string {
+		var buf bytes.Buffer
+		buf.WriteByte('(')
+		for i, v := range types {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString(typeName(v))
+		}
+		buf.WriteByte(')')
+		return buf.String()
+	}
+	return name(t.Params) + " -> " + name(t.Results)
+}
+
+func typeName(v byte) string {
+	switch v {
+	case I32:
+		return "i32"
+	case I64:
+		return "i64"
+	case F32:
+		return "f32"
+	case F64:
+		return "f64"
+	}
+	return fmt.Sprintf("0x%02x", v)
+}
+
+type limits struct {
+	min    uint32
+	max    uint32
+	hasMax bool
+}
+
+type global struct {
+	typ     byte
+	mutable bool
+	init    uint64
+}
+
+type export struct {
+	kind  byte
+	index uint32
+}
+
+type elemSegment struct {
+	offset uint32
+	funcs  []uint32
+}
+
+type dataSegment struct {
+	passive bool
+	offset  uint32
+	data    []byte
+}
+
+type function struct {
+	typ       uint32
+	locals    []byte // 不含参数
+	code      []instr
+	tables    [][]brTarget // br_table的目标，最后一个为默认目标
+	maxHeight int          // 操作数栈的最大高度
+}
+
+// Module 解码并编译后的模块，可被多次实例化，实例之间不共享状态
+type Module struct {
+	types    []FuncType
+	funcs    []*function
+	table    *limits
+	memory   *limits
+	globals  []global
+	exports  map[string]export
+	start    *uint32
+	elements []elemSegment
+	data     []dataSegment
+}
+
+// Compile 解码并编译二进制模块，格式错误、含导入或不支持的指令时返回错误
+func Compile(data []byte) (*Module, error) {
+	if len(data) < 8 || string(data[:4]) != wasmMagic {
+		return nil, fmt.Errorf("wasm: not a WebAssembly module")
+	}
+	if v := uint32(data[4]) | uint32(data[5])<<8 | uint32(data[6])<<16 | uint32(data[7])<<24; v != 1 {
+		return nil, fmt.Errorf("wasm: unsupported binary version %d", v)
+	}
+	m := &Module{exports: make(map[string]export)}
+	var funcTypes []uint32
+	var bodies []*reader
+	r := &reader{data: data, pos: 8}
+	for !r.done() {
+		id := r.byte()
+		size := r.u32()
+		section := r.sub(size)
+		if r.err != nil {
+			break
+		}
+		if err := m.decodeSection(id, section, &funcTypes, &bodies); err != nil {
+			return nil, fmt.Errorf("wasm: section %d: %w", id, err)
+		}
+	}
+	if r.err != nil {
+		return nil, fmt.Errorf("wasm: %w", r.err)
+	}
+	if len(funcTypes) != len(bodies) {
+		return nil, fmt.Errorf("wasm: %d functions declared but %d bodies found", len(funcTypes), len(bodies))
+	}
+
+	m.funcs = make([]*function, len(funcTypes))
+	for i, typ := range funcTypes {
+		if int(typ) >= len(m.types) {
+			return nil, fmt.Errorf("wasm: function %d has unknown type %d", i, typ)
+		}
+		m.funcs[i] = &function{typ: typ}
+	}
+	for i, body := range bodies {
+		if err := m.compileFunction(m.funcs[i], body); err != nil {
+			return nil, fmt.Errorf("wasm: function %d: %w", i, err)
+		}
+	}
+	if err := m.validateIndices(); err != nil {
+		return nil, fmt.Errorf("wasm: %w", err)
+	}
+	return m, nil
+}
+
+func (m *Module) decodeSection(id byte, r *reader, funcTypes *[]uint32, bodies *[]*reader) error {
+	switch id {
+	case sectionCustom, sectionDataCount:
+		return nil
+	case sectionImport:
+		if n := r.u32(); n > 0 {
+			return ErrImportsUnsupported
+		}
+	case sectionType:
+		for n := r.u32(); n > 0 && r.err == nil; n-- {
+			if r.byte() != 0x60 {
+				return fmt.Errorf("invalid function type")
+			}
+			t := FuncType{Params: r.valueTypes(), Results: r.valueTypes()}
+			m.types = append(m.types, t)
+		}
+	case sectionFunction:
+		for n := r.u32(); n > 0 && r.err == nil; n-- {
+			*funcTypes = append(*funcTypes, r.u32())
+		}
+	case sectionTable:
+		for n := r.u32(); n > 0 && r.err == nil; n-- {
+			if r.byte() != funcRef {
+				return fmt.Errorf("only funcref tables are supported")
+			}
+			if m.table != nil {
+				return fmt.Errorf("multiple tables are not supported")
+			}
+			l := r.limits()
+			m.table = &l
+		}
+	case sectionMemory:
+		for n := r.u32(); n > 0 && r.err == nil; n-- {
+			if m.memory != nil {
+				return fmt.Errorf("multiple memories are not supported")
+			}
+			l := r.limits()
+			if l.min > maxPages || (l.hasMax && (l.max > maxPages || l.max < l.min)) {
+				return fmt.Errorf("invalid memory limits")
+			}
+			m.memory = &l
+		}
+	case sectionGlobal:
+		for n := r.u32(); n > 0 && r.err == nil; n-- {
+			g := global{typ: r.valueType(), mutable: r.byte() == 1}
+			v, err := m.constExpr(r, g.typ)
+			if err != nil {
+				return err
+			}
+			g.init = v
+			m.globals = append(m.globals, g)
+		}
+	case sectionExport:
+		for n := r.u32(); n > 0 && r.err == nil; n-- {
+			name := r.name()
+			e := export{kind: r.byte(), index: r.u32()}
+			if _, dup := m.exports[name]; dup {
+				return fmt.Errorf("duplicate export %q", name)
+			}
+			m.exports[name] = e
+		}
+	case sectionStart:
+		idx := r.u32()
+		m.start = &idx
+	case sectionElement:
+		for n := r.u32(); n > 0 && r.err == nil; n-- {
+			if flags := r.u32(); flags != 0 {
+				return fmt.Errorf("unsupported element segment kind %d", flags)
+			}
+			offset, err := m.constExpr(r, I32)
+			if err != nil {
+				return err
+			}
+			seg := elemSegment{offset: uint32(offset)}
+			for k := r.u32(); k > 0 && r.err == nil; k-- {
+				seg.funcs = append(seg.funcs, r.u32())
+			}
+			m.elements = append(m.elements, seg)
+		}
+	case sectionCode:
+		for n := r.u32(); n > 0 && r.err == nil; n-- {
+			*bodies = append(*bodies, r.sub(r.u32()))
+		}
+	case sectionData:
+		for n := r.u32(); n > 0 && r.err == nil; n-- {
+			var seg dataSegment
+			switch r.u32() {
+			case 0:
+				offset, err := m.constExpr(r, I32)
+				if err != nil {
+					return err
+				}
+				seg.offset = uint32(offset)
+			case 1:
+				seg.passive = true
+			default:
+				return fmt.Errorf("unsupported data segment kind")
+			}
+			seg.data = r.bytes(r.u32())
+			m.data = append(m.data, seg)
+		}
+	default:
+		return fmt.Errorf("unknown section")
+	}
+	if r.err != nil {
+		return r.err
+	}
+	if !r.done() {
+		return fmt.Errorf("trailing bytes")
+	}
+	return nil
+}
+
+// constExpr 求值全局变量与段偏移的常量表达式，global.get只能引用此前定义的不可变全局变量
+func (m *Module) constExpr(r *reader, typ byte) (uint64, error) {
+	var v uint64
+	var got byte
+	switch op := r.byte(); op {
+	case opI32Const:
+		v, got = uint64(uint32(r.s32())), I32
+	case opI64Const:
+		v, got = uint64(r.s64()), I64
+	case opF32Const:
+		v, got = uint64(r.u32le()), F32
+	case opF64Const:
+		v, got = r.u64le(), F64
+	case opGlobalGet:
+		idx := r.u32()
+		if int(idx) >= len(m.globals) || m.globals[idx].mutable {
+			return 0, fmt.Errorf("constant expression refers to invalid global %d", idx)
+		}
+		v, got = m.globals[idx].init, m.globals[idx].typ
+	default:
+		return 0, fmt.Errorf("unsupported constant expression opcode 0x%02x", op)
+	}
+	if r.byte() != opEnd {
+		return 0, fmt.Errorf("constant expression must be a single instruction")
+	}
+	if r.err != nil {
+		return 0, r.err
+	}
+	if got != typ {
+		return 0, fmt.Errorf("constant expression has type %s, expected %s", typeName(got), typeName(typ))
+	}
+	return v, nil
+}
+
+// validateIndices 检查编译后的指令与各段引用的索引均有效
+func (m *Module) validateIndices() error {
+	for name, e := range m.exports {
+		var ok bool
+		switch e.kind {
+		case exportFunc:
+			ok = int(e.index) < len(m.funcs)
+		case exportTable:
+			ok = e.index == 0 && m.table != nil
+		case exportMemory:
+			ok = e.index == 0 && m.memory != nil
+		case exportGlobal:
+			ok = int(e.index) < len(m.globals)
+		}
+		if !ok {
+			return fmt.Errorf("export %q refers to an invalid index", name)
+		}
+	}
+	if m.start != nil {
+		if int(*m.start) >= len(m.funcs) {
+			return fmt.Errorf("invalid start function %d", *m.start)
+		}
+		if t := m.types[m.funcs[*m.start].typ]; len(t.Params) > 0 || len(t.Results) > 0 {
+			return fmt.Errorf("start function must have type () -> ()")
+		}
+	}
+	if len(m.elements) > 0 && m.table == nil {
+		return fmt.Errorf("element segments without a table")
+	}
+	for _, seg := range m.elements {
+		for _, f := range seg.funcs {
+			if int(f) >= len(m.funcs) {
+				return fmt.Errorf("element segment refers to invalid function %d", f)
+			}
+		}
+	}
+	if len(m.data) > 0 && m.memory == nil {
+		return fmt.Errorf("data segments without a memory")
+	}
+	return nil
+}
+
+// ExportedFunction 返回导出函数的签名
+func (m *Module) ExportedFunction(name string) (FuncType, bool) {
+	e, ok := m.exports[name]
+	if !ok || e.kind != exportFunc {
+		return FuncType{}, false
+	}
+	return m.types[m.funcs[e.index].typ], true
+}
+
+// ExportsMemory 判断模块是否导出名为name的线性内存
+func (m *Module) ExportsMemory(name string) bool {
+	e, ok := m.exports[name]
+	return ok && e.kind == exportMemory
+}
+
+// reader 二进制格式读取器，出错后后续读取返回零值，由调用方在适当时机检查err
+type reader struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+var errUnexpectedEnd = errors.New("unexpected end of data")
+
+func (r *reader) done() bool { return r.err != nil || r.pos >= len(r.data) }
+
+func (r *reader) fail(err error) {
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+func (r *reader) byte() byte {
+	if r.err != nil {
+		return 0
+	}
+	if r.pos >= len(r.data) {
+		r.fail(errUnexpectedEnd)
+		return 0
+	}
+	b := r.data[r.pos]
+	r.pos++
+	return b
+}
+
+func (r *reader) bytes(n uint32) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if uint64(len(r.data)-r.pos) < uint64(n) {
+		r.fail(errUnexpectedEnd)
+		return nil
+	}
+	b := r.data[r.pos : r.pos+int(n)]
+	r.pos += int(n)
+	return b
+}
+
+func (r *reader) sub(n uint32) *reader {
+	return &reader{data: r.bytes(n), err: r.err}
+}
+
+// uleb 读取至多bits位的无符号LEB128整数
+func (r *reader) uleb(bits uint) uint64 {
+	var v uint64
+	for shift := uint(0); ; shift += 7 {
+		b := r.byte()
+		if r.err != nil {
+			return 0
+		}
+		if shift >= bits || (shift+7 > bits && uint64(b&0x7F)>>(bits-shift) != 0) {
+			r.fail(errors.New("integer representation too long"))
+			return 0
+		}
+		v |= uint64(b&0x7F) << shift
+		if b&0x80 == 0 {
+			return v
+		}
+	}
+}
+
+// sleb 读取至多bits位的有符号LEB128整数
+func (r *reader) sleb(bits uint) int64 {
+	var v int64
+	var shift uint
+	for {
+		b := r.byte()
+		if r.err != nil {
+			return 0
+		}
+		if shift >= bits {
+			r.fail(errors.New("integer representation too long"))
+			return 0
+		}
+		v |= int64(b&0x7F) << shift
+		shift += 7
+		if b&0x80 == 0 {
+			if shift < 64 && b&0x40 != 0 {
+				v |= -1 << shift
+			}
+			if shift > bits {
+				// 超出位宽的填充位必须与符号位一致
+				if v != v<<(64-bits)>>(64-bits) {
+					r.fail(errors.New("integer too large"))
+					return 0
+				}
+			}
+			return v
+		}
+	}
+}
+
+func (r *reader) u32() uint32 { return uint32(r.uleb(32)) }
+func (r *reader) s32() int32  { return int32(r.sleb(32)) }
+func (r *reader) s64() int64  { return r.sleb(64) }
+
+func (r *reader) u32le() uint32 {
+	b := r.bytes(4)
+	if b == nil {
+		return 0
+	}
+	return uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16 | uint32(b[3])<<24
+}
+
+func (r *reader) u64le() uint64 {
+	lo := r.u32le()
+	return uint64(lo) | uint64(r.u32le())<<32
+}
+
+func (r *reader) name() string { return string(r.bytes(r.u32())) }
+
+func (r *reader) valueType() byte {
+	v := r.byte()
+	switch v {
+	case I32, I64, F32, F64:
+		return v
+	}
+	r.fail(fmt.Errorf("unsupported value type 0x%02x", v))
+	return 0
+}
+
+func (r *reader) valueTypes() []byte {
+	n := r.u32()
+	if uint64(n) > uint64(len(r.data)) {
+		r.fail(errUnexpectedEnd)
+		return nil
+	}
+	types := make([]byte, 0, n)
+	for ; n > 0 && r.err == nil; n-- {
+		types = append(types, r.valueType())
+	}
+	return types
+}
+
+func (r *reader) limits() limits {
+	var l limits
+	switch r.byte() {
+	case 0:
+		l.min = r.u32()
+	case 1:
+		l.min, l.max, l.hasMax = r.u32(), r.u32(), true
+	default:
+		r.fail(errors.New("invalid limits"))
+	}
+	return l
+}
+
+// f32bits与f64bits便于以统一的uint64栈存放浮点数
+func f32bits(v float32) uint64 { return uint64(math.Float32bits(v)) }
+func f64bits(v float64) uint64 { return math.Float64bits(v) }
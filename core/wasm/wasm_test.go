@@ -0,0 +1,296 @@
+package wasm
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// testFunc 测试模块中的一个导出函数，body为不含局部变量声明的指令序列（不含结尾的end）
+type testFunc struct {
+	name    string
+	params  []byte
+	results []byte
+	locals  uint32 // i32局部变量个数
+	body    []byte
+}
+
+// buildModule 组装只含类型、函数、内存、导出与代码段的二进制模块，memPages为0时不声明内存
+func buildModule(memPages uint32, funcs ...testFunc) []byte {
+	vec := func(items ...[]byte) []byte {
+		out := uleb(uint32(len(items)))
+		for _, item := range items {
+			out = append(out, item...)
+		}
+		return out
+	}
+	section := func(id byte, content []byte) []byte {
+		return append(append([]byte{id}, uleb(uint32(len(content)))...), content...)
+	}
+
+	var types, indices, exports, bodies [][]byte
+	for i, f := range funcs {
+		types = append(types, append(append([]byte{0x60}, vec(bytesOf(f.params)...)...), vec(bytesOf(f.results)...)...))
+		indices = append(indices, uleb(uint32(i)))
+		exports = append(exports, append(append(vec(bytesOf([]byte(f.name))...), exportFunc), uleb(uint32(i))...))
+
+		var locals []byte
+		if f.locals > 0 {
+			locals = vec(append(uleb(f.locals), I32))
+		} else {
+			locals = vec()
+		}
+		body := append(append(locals, f.body...), 0x0B)
+		bodies = append(bodies, append(uleb(uint32(len(body))), body...))
+	}
+
+	out := []byte(wasmMagic + "\x01\x00\x00\x00")
+	out = append(out, section(sectionType, vec(types...))...)
+	out = append(out, section(sectionFunction, vec(indices...))...)
+	if memPages > 0 {
+		out = append(out, section(sectionMemory, vec(append([]byte{0x00}, uleb(memPages)...)))...)
+	}
+	out = append(out, section(sectionExport, vec(exports...))...)
+	out = append(out, section(sectionCode, vec(bodies...))...)
+	return out
+}
+
+// bytesOf 将字节切片拆为单字节的项，用于vec编码
+func bytesOf(b []byte) [][]byte {
+	items := make([][]byte, len(b))
+	for i := range b {
+		items[i] = b[i : i+1]
+	}
+	return items
+}
+
+func uleb(v uint32) []byte {
+	var out []byte
+	for {
+		b := byte(v & 0x7F)
+		v >>= 7
+		if v != 0 {
+			out = append(out, b|0x80)
+			continue
+		}
+		return append(out, b)
+	}
+}
+
+var (
+	addFunc = testFunc{
+		name: "add", params: []byte{I32, I32}, results: []byte{I32},
+		body: []byte{0x20, 0x00, 0x20, 0x01, 0x6A}, // local.get 0; local.get 1; i32.add
+	}
+	spinFunc = testFunc{
+		name: "spin",
+		body: []byte{0x03, 0x40, 0x0C, 0x00, 0x0B}, // loop; br 0; end
+	}
+	recurseFunc = testFunc{
+		name: "recurse",
+		body: []byte{0x10, 0x00}, // call 0（自身须为0号函数）
+	}
+)
+
+func instantiate(t *testing.T, limits Limits, memPages uint32, funcs ...testFunc) *Instance {
+	t.Helper()
+	m, err := Compile(buildModule(memPages, funcs...))
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	in, err := m.Instantiate(limits)
+	if err != nil {
+		t.Fatalf("Instantiate: %v", err)
+	}
+	return in
+}
+
+func TestCall(t *testing.T) {
+	in := instantiate(t, Limits{}, 0, addFunc)
+	out, err := in.Call("add", 2, 40)
+	if err != nil {
+		t.Fatalf("Call: %v", err)
+	}
+	if len(out) != 1 || out[0] != 42 {
+		t.Fatalf("add(2, 40) = %v, want [42]", out)
+	}
+	if _, err := in.Call("add", 1); err == nil {
+		t.Error("Call with the wrong number of arguments succeeded")
+	}
+	if _, err := in.Call("missing"); err == nil {
+		t.Error("Call of a function that is not exported succeeded")
+	}
+}
+
+func TestFuelLimit(t *testing.T) {
+	in := instantiate(t, Limits{Fuel: 1000}, 0, spinFunc, addFunc)
+	for i := 0; i < 2; i++ {
+		if _, err := in.Call("spin"); !errors.Is(err, ErrFuelExhausted) {
+			t.Fatalf("call %d: got %v, want %v", i, err, ErrFuelExhausted)
+		}
+	}
+	// 每次调用的燃料是独立的，陷入之后的调用照常执行
+	if out, err := in.Call("add", 1, 2); err != nil || out[0] != 3 {
+		t.Fatalf("add after fuel trap = %v, %v", out, err)
+	}
+}
+
+func TestCallDepthLimit(t *testing.T) {
+	in := instantiate(t, Limits{MaxCallDepth: 64}, 0, recurseFunc, addFunc)
+	_, err := in.Call("recurse")
+	var trapErr *Trap
+	if !errors.As(err, &trapErr) || !strings.Contains(trapErr.Reason, "call stack exhausted") {
+		t.Fatalf("got %v, want a call stack trap", err)
+	}
+	// 陷入时调用深度被重置
+	if out, err := in.Call("add", 5, 6); err != nil || out[0] != 11 {
+		t.Fatalf("add after call depth trap = %v, %v", out, err)
+	}
+}
+
+func TestStackLimit(t *testing.T) {
+	manyLocals := testFunc{name: "locals", locals: 4096}
+	in := instantiate(t, Limits{MaxStack: 1024}, 0, manyLocals, addFunc)
+	_, err := in.Call("locals")
+	var trapErr *Trap
+	if !errors.As(err, &trapErr) || !strings.Contains(trapErr.Reason, "stack overflow") {
+		t.Fatalf("got %v, want a stack overflow trap", err)
+	}
+	// 每层调用的局部变量都占用栈，递归在达到调用深度上限之前就会耗尽栈
+	recurseWithLocals := recurseFunc
+	recurseWithLocals.locals = 16
+	in = instantiate(t, Limits{MaxStack: 1024, MaxCallDepth: 1000}, 0, recurseWithLocals, addFunc)
+	if _, err := in.Call("recurse"); !errors.As(err, &trapErr) || !strings.Contains(trapErr.Reason, "stack overflow") {
+		t.Fatalf("got %v, want a stack overflow trap", err)
+	}
+	if out, err := in.Call("add", 1, 1); err != nil || out[0] != 2 {
+		t.Fatalf("add after stack trap = %v, %v", out, err)
+	}
+}
+
+func TestTraps(t *testing.T) {
+	tests := []struct {
+		name   string
+		fn     testFunc
+		reason string
+	}{
+		{
+			name:   "unreachable",
+			fn:     testFunc{name: "f", body: []byte{0x00}},
+			reason: "unreachable",
+		},
+		{
+			name: "divide by zero",
+			fn: testFunc{name: "f", results: []byte{I32},
+				body: []byte{0x41, 0x01, 0x41, 0x00, 0x6D}}, // i32.const 1; i32.const 0; i32.div_s
+			reason: "divide by zero",
+		},
+		{
+			name: "out of bounds load",
+			fn: testFunc{name: "f", results: []byte{I32},
+				body: []byte{0x41, 0x80, 0x80, 0x04, 0x28, 0x02, 0x00}}, // i32.const 65536; i32.load
+			reason: "out of bounds",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := instantiate(t, Limits{}, 1, tt.fn, addFunc)
+			_, err := in.Call("f")
+			var trapErr *Trap
+			if !errors.As(err, &trapErr) || !strings.Contains(trapErr.Reason, tt.reason) {
+				t.Fatalf("got %v, want a trap containing %q", err, tt.reason)
+			}
+			if out, err := in.Call("add", 20, 22); err != nil || out[0] != 42 {
+				t.Fatalf("add after trap = %v, %v", out, err)
+			}
+		})
+	}
+}
+
+func TestMemoryLimit(t *testing.T) {
+	m, err := Compile(buildModule(4, addFunc))
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	if _, err := m.Instantiate(Limits{MaxMemoryPages: 2}); err == nil {
+		t.Fatal("Instantiate succeeded with more initial pages than MaxMemoryPages")
+	}
+
+	grow := testFunc{name: "grow", params: []byte{I32}, results: []byte{I32},
+		body: []byte{0x20, 0x00, 0x40, 0x00}} // local.get 0; memory.grow
+	in := instantiate(t, Limits{MaxMemoryPages: 2}, 1, grow)
+	if out, err := in.Call("grow", 1); err != nil || out[0] != 1 {
+		t.Fatalf("grow(1) = %v, %v, want previous size 1", out, err)
+	}
+	if out, err := in.Call("grow", 1); err != nil || int32(out[0]) != -1 {
+		t.Fatalf("grow beyond the limit = %v, %v, want -1", out, err)
+	}
+	if len(in.Memory()) != 2*pageSize {
+		t.Fatalf("memory is %d bytes, want %d", len(in.Memory()), 2*pageSize)
+	}
+}
+
+func TestCompileMalformed(t *testing.T) {
+	valid := buildModule(0, addFunc)
+	withImport := append([]byte(wasmMagic+"\x01\x00\x00\x00"),
+		sectionImport, 0x0B, 0x01, 0x03, 'e', 'n', 'v', 0x03, 'l', 'o', 'g', 0x00, 0x00)
+	missingBody := append([]byte(wasmMagic+"\x01\x00\x00\x00"),
+		sectionType, 0x04, 0x01, 0x60, 0x00, 0x00, sectionFunction, 0x02, 0x01, 0x00)
+
+	tests := []struct {
+		name string
+		data []byte
+		is   error
+	}{
+		{name: "empty", data: nil},
+		{name: "bad magic", data: []byte("\x00wsm\x01\x00\x00\x00")},
+		{name: "bad version", data: []byte(wasmMagic + "\x02\x00\x00\x00")},
+		{name: "truncated", data: valid[:len(valid)-3]},
+		{name: "imports", data: withImport, is: ErrImportsUnsupported},
+		{name: "missing body", data: missingBody},
+		{name: "stack underflow", data: buildModule(0, testFunc{name: "f", results: []byte{I32}, body: []byte{0x6A}})},
+		{name: "result type mismatch", data: buildModule(0, testFunc{name: "f", results: []byte{I32}})},
+		{name: "unknown opcode", data: buildModule(0, testFunc{name: "f", body: []byte{0xFF}})},
+		{name: "local out of range", data: buildModule(0, testFunc{name: "f", body: []byte{0x20, 0x05, 0x1A}})},
+		{name: "memory access without memory", data: buildModule(0, testFunc{name: "f", results: []byte{I32},
+			body: []byte{0x41, 0x00, 0x28, 0x02, 0x00}})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := Compile(tt.data)
+			if err == nil {
+				t.Fatalf("Compile succeeded: %+v", m)
+			}
+			if tt.is != nil && !errors.Is(err, tt.is) {
+				t.Fatalf("got %v, want %v", err, tt.is)
+			}
+		})
+	}
+}
+
+func TestSaveRestore(t *testing.T) {
+	store := testFunc{name: "store", params: []byte{I32},
+		body: []byte{0x41, 0x00, 0x20, 0x00, 0x36, 0x02, 0x00}} // i32.const 0; local.get 0; i32.store
+	load := testFunc{name: "load", results: []byte{I32},
+		body: []byte{0x41, 0x00, 0x28, 0x02, 0x00}} // i32.const 0; i32.load
+	in := instantiate(t, Limits{}, 1, store, load)
+
+	if _, err := in.Call("store", 7); err != nil {
+		t.Fatalf("store: %v", err)
+	}
+	state := in.Save()
+	if _, err := in.Call("store", 9); err != nil {
+		t.Fatalf("store: %v", err)
+	}
+	if err := in.Restore(state); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if out, err := in.Call("load"); err != nil || out[0] != 7 {
+		t.Fatalf("load after Restore = %v, %v, want 7", out, err)
+	}
+
+	state.Memory = state.Memory[:100]
+	if err := in.Restore(state); err == nil {
+		t.Fatal("Restore accepted memory that is not a whole number of pages")
+	}
+}
@@ -0,0 +1,122 @@
+package wrappers
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// ConfigKeyRescaleAction 创建环境时配置中的该键为true时，动作空间重新缩放到[-1, 1]（见RescaleAction）
+const ConfigKeyRescaleAction = "rescale_action"
+
+// ActionRescale 包装一个Box动作空间的环境，把[min, max]内的动作线性映射到环境声明的[Low, High]，
+// 使输出经tanh压缩的策略可以直接驱动连续控制场景，自身也实现core.Environment
+type ActionRescale struct {
+	env      core.Environment
+	min, max float64
+	low      []float64 // 被包装环境每一维的下界
+	high     []float64 // 被包装环境每一维的上界
+	inner    core.ActionSpace
+}
+
+var (
+	_ core.Environment      = (*ActionRescale)(nil)
+	_ core.MetadataProvider = (*ActionRescale)(nil)
+	_ core.Unwrapper        = (*ActionRescale)(nil)
+)
+
+// RescaleAction 创建动作缩放包装器：环境的动作空间须为各维边界有限的Box，GetSpaces报告的动作空间变为[min, max]，
+// 超出该范围的动作先被裁剪
+func RescaleAction(env core.Environment, min, max float64) (*ActionRescale, error) {
+	if !(min < max) || math.IsInf(min, 0) || math.IsInf(max, 0) {
+		return nil, fmt.Errorf("invalid action range [%v, %v]", min, max)
+	}
+	space := env.GetSpaces().ActionSpace
+	if space.Type != core.SpaceTypeBox {
+		return nil, fmt.Errorf("action rescaling requires a Box action space, got %s", space.Type)
+	}
+	size := space.Size()
+	w := &ActionRescale{env: env, min: min, max: max, low: make([]float64, size), high: make([]float64, size), inner: space}
+	for i := 0; i < size; i++ {
+		w.low[i] = bound(space.Low, i, math.Inf(-1))
+		w.high[i] = bound(space.High, i, math.Inf(1))
+		if math.IsInf(w.low[i], 0) || math.IsInf(w.high[i], 0) || w.low[i] > w.high[i] {
+			return nil, fmt.Errorf("action dimension %d has unbounded or invalid bounds [%v, %v]", i, w.low[i], w.high[i])
+		}
+	}
+	return w, nil
+}
+
+// rescaleFromConfig 当配置中ConfigKeyRescaleAction为true时把动作空间缩放到[-1, 1]
+func rescaleFromConfig(env core.Environment, config core.Config) (core.Environment, error) {
+	enabled, _, err := config.GetBool(ConfigKeyRescaleAction)
+	if err != nil || !enabled {
+		return env, err
+	}
+	return RescaleAction(env, -1, 1)
+}
+
+// Unwrap 返回被包装的环境
+func (w *ActionRescale) Unwrap() core.Environment {
+	return w.env
+}
+
+func (w *ActionRescale) Reset(ctx context.Context) ([]core.Observation, error) {
+	return w.env.Reset(ctx)
+}
+
+func (w *ActionRescale) Step(ctx context.Context, actions []core.Action) ([]core.Observation, []float64, []bool, error) {
+	rescaled := make([]core.Action, len(actions))
+	for i, action := range actions {
+		values, err := actionValues(action)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if len(values) != len(w.low) {
+			return nil, nil, nil, fmt.Errorf("action must have %d dimensions, got %d", len(w.low), len(values))
+		}
+		for j, v := range values {
+			v = math.Max(w.min, math.Min(w.max, v))
+			values[j] = w.low[j] + (v-w.min)/(w.max-w.min)*(w.high[j]-w.low[j])
+		}
+		rescaled[i] = core.NewActionFromValues(w.inner, values)
+	}
+	return w.env.Step(ctx, rescaled)
+}
+
+// actionValues 将动作数据（数值或数值列表）转换为新的[]float64
+func actionValues(action core.Action) ([]float64, error) {
+	generic := core.NewGenericAction(action.GetData())
+	if values, err := generic.GetFloat64Slice(); err == nil {
+		return values, nil
+	}
+	v, err := generic.GetFloat64()
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract action values: %w", err)
+	}
+	return []float64{v}, nil
+}
+
+// GetSpaces 返回缩放后的动作空间与被包装环境的观察空间
+func (w *ActionRescale) GetSpaces() core.SpaceDefinition {
+	spaces := w.env.GetSpaces()
+	spaces.ActionSpace.Low = make([]float64, len(w.low))
+	spaces.ActionSpace.High = make([]float64, len(w.high))
+	for i := range w.low {
+		spaces.ActionSpace.Low[i] = w.min
+		spaces.ActionSpace.High[i] = w.max
+	}
+	return spaces
+}
+
+func (w *ActionRescale) GetObservations() []core.Observation { return w.env.GetObservations() }
+func (w *ActionRescale) GetReward() []float64                { return w.env.GetReward() }
+func (w *ActionRescale) GetInfo() map[string]interface{}     { return w.env.GetInfo() }
+func (w *ActionRescale) Close() error                        { return w.env.Close() }
+
+// Metadata 返回被包装环境的元数据
+func (w *ActionRescale) Metadata() core.EnvMetadata {
+	return core.GetEnvMetadata(w.env)
+}
@@ -0,0 +1,98 @@
+package wrappers
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// normalizeEpsilon 归一化时加到方差上的小量，避免除以零
+const normalizeEpsilon = 1e-8
+
+// ObservationNormalizer 包装一个环境，用各分量的滑动均值与方差把观察归一化为近似零均值、单位方差，
+// 与Gym的NormalizeObservation相同。统计量随每个智能体的每次观察更新，评估时可用SetUpdate(false)冻结；
+// GetObservations只归一化不更新统计量。自身也实现core.Environment
+type ObservationNormalizer struct {
+	*ObservationTransform
+	mean   []float64
+	m2     []float64 // 各分量与均值之差的平方和（Welford算法）
+	count  float64
+	update bool
+}
+
+var (
+	_ core.Environment      = (*ObservationNormalizer)(nil)
+	_ core.MetadataProvider = (*ObservationNormalizer)(nil)
+	_ core.Unwrapper        = (*ObservationNormalizer)(nil)
+)
+
+// NormalizeObservation 创建观察归一化包装器，GetSpaces报告的观察空间变为形状不变、无界的Box
+func NormalizeObservation(env core.Environment) (*ObservationNormalizer, error) {
+	inner := env.GetSpaces().ObservationSpace
+	if inner.Type == core.SpaceTypeText || inner.Type == core.SpaceTypeImage {
+		return nil, fmt.Errorf("observation normalization requires a numeric observation space, got %s", inner.Type)
+	}
+	size := inner.Size()
+	w := &ObservationNormalizer{mean: make([]float64, size), m2: make([]float64, size), update: true}
+	space := core.ObservationSpace{Type: core.SpaceTypeBox, Shape: inner.Shape, Dtype: inner.Dtype}
+	transform, err := TransformObservation(env, w.normalize, space)
+	if err != nil {
+		return nil, err
+	}
+	w.ObservationTransform = transform
+	return w, nil
+}
+
+// SetUpdate 设置是否继续用新的观察更新统计量
+func (w *ObservationNormalizer) SetUpdate(update bool) {
+	w.update = update
+}
+
+// Mean 返回各分量的滑动均值
+func (w *ObservationNormalizer) Mean() []float64 {
+	return append([]float64(nil), w.mean...)
+}
+
+// Variance 返回各分量的滑动方差（总体方差），尚无观察时为0
+func (w *ObservationNormalizer) Variance() []float64 {
+	variance := make([]float64, len(w.m2))
+	if w.count > 0 {
+		for i, m2 := range w.m2 {
+			variance[i] = m2 / w.count
+		}
+	}
+	return variance
+}
+
+// GetObservations 返回归一化后的当前观察，不更新统计量
+func (w *ObservationNormalizer) GetObservations() []core.Observation {
+	update := w.update
+	w.update = false
+	defer func() { w.update = update }()
+	return w.ObservationTransform.GetObservations()
+}
+
+// normalize 更新统计量（开启时）并归一化data，长度不符时原样返回，由mapObservations报告错误
+func (w *ObservationNormalizer) normalize(data []float64) []float64 {
+	if len(data) != len(w.mean) {
+		return data
+	}
+	if w.update {
+		w.count++
+		for i, v := range data {
+			delta := v - w.mean[i]
+			w.mean[i] += delta / w.count
+			w.m2[i] += delta * (v - w.mean[i])
+		}
+	}
+	normalized := make([]float64, len(data))
+	for i, v := range data {
+		variance := 1.0 // 尚无观察时不缩放
+		if w.count > 0 {
+			variance = w.m2[i] / w.count
+		}
+		normalized[i] = (v - w.mean[i]) / math.Sqrt(variance+normalizeEpsilon)
+	}
+	return normalized
+}
@@ -0,0 +1,153 @@
+package wrappers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// ObservationTransform 包装一个环境，对每个智能体的观察数据应用transform（裁剪、缩放、选取特征等），
+// 并以构造时给定的观察空间代替被包装环境的观察空间，自身也实现core.Environment
+type ObservationTransform struct {
+	env       core.Environment
+	transform func([]float64) []float64
+	space     core.ObservationSpace
+}
+
+var (
+	_ core.Environment      = (*ObservationTransform)(nil)
+	_ core.MetadataProvider = (*ObservationTransform)(nil)
+	_ core.Unwrapper        = (*ObservationTransform)(nil)
+)
+
+// TransformObservation 创建观察变换包装器：transform接收被包装环境的观察数据（不得保留或修改该切片），
+// 返回变换后的数据，其长度须等于space.Size()；space为变换后的观察空间，由GetSpaces报告
+func TransformObservation(env core.Environment, transform func([]float64) []float64, space core.ObservationSpace) (*ObservationTransform, error) {
+	if transform == nil {
+		return nil, fmt.Errorf("observation transform is required")
+	}
+	if space.Size() <= 0 {
+		return nil, fmt.Errorf("observation space shape %v is empty", space.Shape)
+	}
+	return &ObservationTransform{env: env, transform: transform, space: space}, nil
+}
+
+// SelectFeatures 只保留观察中下标为indices的特征，观察空间的边界随之选取
+func SelectFeatures(env core.Environment, indices ...int) (*ObservationTransform, error) {
+	inner := env.GetSpaces().ObservationSpace
+	size := inner.Size()
+	for _, i := range indices {
+		if i < 0 || i >= size {
+			return nil, fmt.Errorf("feature index %d out of range [0, %d)", i, size)
+		}
+	}
+	space := core.ObservationSpace{
+		Type:  inner.Type,
+		Shape: []int32{int32(len(indices))},
+		Dtype: inner.Dtype,
+	}
+	if len(inner.Low) > 0 || len(inner.High) > 0 {
+		space.Low = make([]float64, len(indices))
+		space.High = make([]float64, len(indices))
+		for j, i := range indices {
+			space.Low[j] = bound(inner.Low, i, space.Low[j])
+			space.High[j] = bound(inner.High, i, space.High[j])
+		}
+	}
+	return TransformObservation(env, func(data []float64) []float64 {
+		selected := make([]float64, len(indices))
+		for j, i := range indices {
+			selected[j] = data[i]
+		}
+		return selected
+	}, space)
+}
+
+// bound 返回第i维的边界，边界长度为1时按广播处理
+func bound(bounds []float64, i int, def float64) float64 {
+	switch {
+	case len(bounds) == 1:
+		return bounds[0]
+	case i < len(bounds):
+		return bounds[i]
+	}
+	return def
+}
+
+// Unwrap 返回被包装的环境
+func (w *ObservationTransform) Unwrap() core.Environment {
+	return w.env
+}
+
+func (w *ObservationTransform) Reset(ctx context.Context) ([]core.Observation, error) {
+	observations, err := w.env.Reset(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return w.apply(observations)
+}
+
+func (w *ObservationTransform) Step(ctx context.Context, actions []core.Action) ([]core.Observation, []float64, []bool, error) {
+	observations, rewards, dones, err := w.env.Step(ctx, actions)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	transformed, err := w.apply(observations)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return transformed, rewards, dones, nil
+}
+
+// GetObservations 返回变换后的当前观察，变换结果的长度不符时返回被包装环境的观察
+func (w *ObservationTransform) GetObservations() []core.Observation {
+	observations := w.env.GetObservations()
+	transformed, err := w.apply(observations)
+	if err != nil {
+		return w.env.GetObservations()
+	}
+	return transformed
+}
+
+// apply 变换各智能体的观察，见mapObservations
+func (w *ObservationTransform) apply(observations []core.Observation) ([]core.Observation, error) {
+	return mapObservations(observations, w.transform, w.space.Size())
+}
+
+// mapObservations 对各智能体的观察数据应用transform，保留其元数据与存储精度，并把原观察归还对象池；
+// size非负时结果的长度须等于size
+func mapObservations(observations []core.Observation, transform func([]float64) []float64, size int) ([]core.Observation, error) {
+	transformed := make([]core.Observation, len(observations))
+	for i, obs := range observations {
+		data := transform(obs.GetData())
+		if size >= 0 && len(data) != size {
+			core.ReleaseObservations(transformed[:i])
+			core.ReleaseObservations(observations)
+			return nil, fmt.Errorf("observation transform returned %d values, expected %d", len(data), size)
+		}
+		if typed, ok := obs.(core.Float32Observation); ok && typed.GetData32() != nil {
+			transformed[i] = core.AcquireObservation32(data, obs.GetMetadata())
+		} else {
+			transformed[i] = core.AcquireObservation(data, obs.GetMetadata())
+		}
+	}
+	core.ReleaseObservations(observations)
+	return transformed, nil
+}
+
+// GetSpaces 返回被包装环境的动作空间与变换后的观察空间
+func (w *ObservationTransform) GetSpaces() core.SpaceDefinition {
+	spaces := w.env.GetSpaces()
+	spaces.ObservationSpace = w.space
+	return spaces
+}
+
+func (w *ObservationTransform) GetReward() []float64            { return w.env.GetReward() }
+func (w *ObservationTransform) GetInfo() map[string]interface{} { return w.env.GetInfo() }
+func (w *ObservationTransform) Close() error                    { return w.env.Close() }
+
+// Metadata 返回被包装环境的元数据
+func (w *ObservationTransform) Metadata() core.EnvMetadata {
+	return core.GetEnvMetadata(w.env)
+}
@@ -0,0 +1,247 @@
+package wrappers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// ConfigKeyRandomize 创建环境时配置中的该键为RandomizeOptions（JSON/YAML映射）时开启噪声注入与域随机化：
+//
+//	randomize:
+//	  seed: 7
+//	  observation_noise: {type: normal, std: 0.01}
+//	  action_noise: {type: uniform, min: -0.1, max: 0.1}
+//	  parameters:
+//	    length: {type: uniform, min: 0.4, max: 0.6}
+const ConfigKeyRandomize = "randomize"
+
+// InfoKeyRandomizedParameters GetInfo中本回合采样的动力学参数
+const InfoKeyRandomizedParameters = "randomized_parameters"
+
+// 分布类型
+const (
+	DistributionUniform = "uniform" // [Min, Max]上的均匀分布
+	DistributionNormal  = "normal"  // 均值Mean、标准差Std的正态分布
+)
+
+// Distribution 噪声或参数的分布
+type Distribution struct {
+	Type string  `json:"type"` // DistributionUniform或DistributionNormal，为空时为均匀分布
+	Min  float64 `json:"min"`
+	Max  float64 `json:"max"`
+	Mean float64 `json:"mean"`
+	Std  float64 `json:"std"`
+}
+
+// validate 检查分布的类型与参数
+func (d Distribution) validate() error {
+	switch d.Type {
+	case "", DistributionUniform:
+		if !(d.Min <= d.Max) || math.IsInf(d.Min, 0) || math.IsInf(d.Max, 0) {
+			return fmt.Errorf("uniform distribution requires finite min <= max, got [%v, %v]", d.Min, d.Max)
+		}
+	case DistributionNormal:
+		if !(d.Std >= 0) || math.IsInf(d.Std, 0) || math.IsNaN(d.Mean) || math.IsInf(d.Mean, 0) {
+			return fmt.Errorf("normal distribution requires a finite mean and std >= 0, got mean %v std %v", d.Mean, d.Std)
+		}
+	default:
+		return fmt.Errorf("unknown distribution type %q (expected uniform or normal)", d.Type)
+	}
+	return nil
+}
+
+// sample 从分布中采样
+func (d Distribution) sample(rng *rand.Rand) float64 {
+	if d.Type == DistributionNormal {
+		return d.Mean + d.Std*rng.NormFloat64()
+	}
+	return d.Min + rng.Float64()*(d.Max-d.Min)
+}
+
+// RandomizeOptions 噪声注入与域随机化的选项，未设置的部分不生效
+type RandomizeOptions struct {
+	// ObservationNoise 每步加到每个观察值上的噪声
+	ObservationNoise *Distribution `json:"observation_noise,omitempty"`
+	// ActionNoise 每步加到每个连续动作值上的噪声，加噪后裁剪到动作空间的边界；要求Box动作空间
+	ActionNoise *Distribution `json:"action_noise,omitempty"`
+	// Parameters 每回合Reset前按分布采样并设置的动力学参数，要求环境实现core.Parameterized
+	Parameters map[string]Distribution `json:"parameters,omitempty"`
+	// Seed 噪声与参数采样的随机种子，为0时使用当前时间
+	Seed int64 `json:"seed,omitempty"`
+}
+
+// Randomizer 包装一个环境，每步对观察与动作注入噪声，每回合重新采样动力学参数，自身也实现core.Environment
+type Randomizer struct {
+	env    core.Environment
+	opts   RandomizeOptions
+	params core.Parameterized
+	names  []string // 按名称排序的随机化参数，保证同一种子下的采样顺序一致
+	rng    *rand.Rand
+	space  core.ActionSpace
+	sample map[string]float64 // 本回合采样的参数
+}
+
+var (
+	_ core.Environment      = (*Randomizer)(nil)
+	_ core.MetadataProvider = (*Randomizer)(nil)
+	_ core.Unwrapper        = (*Randomizer)(nil)
+)
+
+// Randomize 创建噪声注入与域随机化包装器，参数在每次Reset时采样
+func Randomize(env core.Environment, opts RandomizeOptions) (*Randomizer, error) {
+	w := &Randomizer{env: env, opts: opts, space: env.GetSpaces().ActionSpace}
+	for _, d := range []*Distribution{opts.ObservationNoise, opts.ActionNoise} {
+		if d == nil {
+			continue
+		}
+		if err := d.validate(); err != nil {
+			return nil, err
+		}
+	}
+	if opts.ActionNoise != nil && w.space.Type != core.SpaceTypeBox {
+		return nil, fmt.Errorf("action noise requires a Box action space, got %s", w.space.Type)
+	}
+	if len(opts.Parameters) > 0 {
+		params, ok := core.FindParameterized(env)
+		if !ok {
+			return nil, fmt.Errorf("environment does not support changing dynamics parameters (core.Parameterized)")
+		}
+		current := params.Parameters()
+		for name, d := range opts.Parameters {
+			if _, ok := current[name]; !ok {
+				return nil, core.UnknownParameterError(name, params)
+			}
+			if err := d.validate(); err != nil {
+				return nil, fmt.Errorf("parameter %s: %w", name, err)
+			}
+			w.names = append(w.names, name)
+		}
+		sort.Strings(w.names)
+		w.params = params
+	}
+	seed := opts.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	w.rng = rand.New(rand.NewSource(seed))
+	return w, nil
+}
+
+// randomizeFromConfig 当配置中设置了ConfigKeyRandomize时返回Randomizer
+func randomizeFromConfig(env core.Environment, config core.Config) (core.Environment, error) {
+	value := config.GetValue(ConfigKeyRandomize)
+	if value == nil {
+		return env, nil
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", ConfigKeyRandomize, err)
+	}
+	var opts RandomizeOptions
+	if err := json.Unmarshal(raw, &opts); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", ConfigKeyRandomize, err)
+	}
+	return Randomize(env, opts)
+}
+
+// Unwrap 返回被包装的环境
+func (w *Randomizer) Unwrap() core.Environment {
+	return w.env
+}
+
+// Reset 采样并设置本回合的动力学参数后重置环境，初始观察同样注入噪声
+func (w *Randomizer) Reset(ctx context.Context) ([]core.Observation, error) {
+	if w.params != nil {
+		w.sample = make(map[string]float64, len(w.names))
+		for _, name := range w.names {
+			value := w.opts.Parameters[name].sample(w.rng)
+			if err := w.params.SetParameter(name, value); err != nil {
+				return nil, fmt.Errorf("failed to randomize parameter: %w", err)
+			}
+			w.sample[name] = value
+		}
+	}
+	observations, err := w.env.Reset(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return w.noisy(observations)
+}
+
+func (w *Randomizer) Step(ctx context.Context, actions []core.Action) ([]core.Observation, []float64, []bool, error) {
+	if w.opts.ActionNoise != nil {
+		noisy := make([]core.Action, len(actions))
+		for i, action := range actions {
+			values, err := actionValues(action)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			for j := range values {
+				values[j] += w.opts.ActionNoise.sample(w.rng)
+				low := bound(w.space.Low, j, math.Inf(-1))
+				high := bound(w.space.High, j, math.Inf(1))
+				values[j] = math.Max(low, math.Min(high, values[j]))
+			}
+			noisy[i] = core.NewActionFromValues(w.space, values)
+		}
+		actions = noisy
+	}
+	observations, rewards, dones, err := w.env.Step(ctx, actions)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	observations, err = w.noisy(observations)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return observations, rewards, dones, nil
+}
+
+// noisy 对观察注入噪声，未设置观察噪声时原样返回
+func (w *Randomizer) noisy(observations []core.Observation) ([]core.Observation, error) {
+	if w.opts.ObservationNoise == nil {
+		return observations, nil
+	}
+	return mapObservations(observations, func(data []float64) []float64 {
+		out := make([]float64, len(data))
+		for i, v := range data {
+			out[i] = v + w.opts.ObservationNoise.sample(w.rng)
+		}
+		return out
+	}, -1)
+}
+
+// GetInfo 返回被包装环境的info，并在InfoKeyRandomizedParameters中附带本回合采样的参数
+func (w *Randomizer) GetInfo() map[string]interface{} {
+	info := w.env.GetInfo()
+	if w.sample == nil {
+		return info
+	}
+	if info == nil {
+		info = make(map[string]interface{})
+	}
+	sample := make(map[string]interface{}, len(w.sample))
+	for name, value := range w.sample {
+		sample[name] = value
+	}
+	info[InfoKeyRandomizedParameters] = sample
+	return info
+}
+
+// GetObservations 返回被包装环境的当前观察（不注入噪声）
+func (w *Randomizer) GetObservations() []core.Observation { return w.env.GetObservations() }
+func (w *Randomizer) GetReward() []float64                { return w.env.GetReward() }
+func (w *Randomizer) GetSpaces() core.SpaceDefinition     { return w.env.GetSpaces() }
+func (w *Randomizer) Close() error                        { return w.env.Close() }
+
+// Metadata 返回被包装环境的元数据
+func (w *Randomizer) Metadata() core.EnvMetadata {
+	return core.GetEnvMetadata(w.env)
+}
@@ -0,0 +1,161 @@
+package wrappers
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// 创建环境时配置中的以下键用于变换奖励，同时设置时依次应用缩放、裁剪与取符号
+const (
+	ConfigKeyRewardScale = "reward_scale" // 奖励乘以该系数
+	ConfigKeyRewardClip  = "reward_clip"  // 奖励裁剪到[min, max]；为单个数c时裁剪到[-c, c]
+	ConfigKeyRewardSign  = "reward_sign"  // 为true时奖励取符号（-1、0或1）
+)
+
+// InfoKeyOriginalReward GetInfo中最近一步变换前的各智能体奖励；多层奖励变换时保留最内层的原始奖励
+const InfoKeyOriginalReward = "original_reward"
+
+// RewardTransform 包装一个环境，对每一步的各智能体奖励应用transform，自身也实现core.Environment
+type RewardTransform struct {
+	env       core.Environment
+	transform func(float64) float64
+	bounds    func([2]float64) [2]float64 // 由被包装环境的奖励范围计算变换后的范围
+
+	original []float64 // 最近一步变换前的奖励，尚未Step时为nil
+	rewards  []float64 // 最近一步变换后的奖励
+}
+
+var (
+	_ core.Environment      = (*RewardTransform)(nil)
+	_ core.MetadataProvider = (*RewardTransform)(nil)
+	_ core.Unwrapper        = (*RewardTransform)(nil)
+)
+
+// ClipReward 将奖励裁剪到[min, max]
+func ClipReward(env core.Environment, min, max float64) (*RewardTransform, error) {
+	if math.IsNaN(min) || math.IsNaN(max) || min > max {
+		return nil, fmt.Errorf("invalid reward clip range [%v, %v]", min, max)
+	}
+	clip := func(r float64) float64 { return math.Max(min, math.Min(max, r)) }
+	return &RewardTransform{
+		env:       env,
+		transform: clip,
+		bounds: func(b [2]float64) [2]float64 {
+			return [2]float64{clip(b[0]), clip(b[1])}
+		},
+	}, nil
+}
+
+// ScaleReward 将奖励乘以scale
+func ScaleReward(env core.Environment, scale float64) (*RewardTransform, error) {
+	if math.IsNaN(scale) || math.IsInf(scale, 0) {
+		return nil, fmt.Errorf("invalid reward scale %v", scale)
+	}
+	return &RewardTransform{
+		env:       env,
+		transform: func(r float64) float64 { return r * scale },
+		bounds: func(b [2]float64) [2]float64 {
+			if scale == 0 {
+				return [2]float64{0, 0}
+			}
+			low, high := b[0]*scale, b[1]*scale
+			return [2]float64{math.Min(low, high), math.Max(low, high)}
+		},
+	}, nil
+}
+
+// SignReward 将奖励替换为其符号（-1、0或1），常用于Atari等奖励尺度差异很大的环境
+func SignReward(env core.Environment) *RewardTransform {
+	return &RewardTransform{
+		env:       env,
+		transform: sign,
+		bounds: func(b [2]float64) [2]float64 {
+			return [2]float64{sign(b[0]), sign(b[1])}
+		},
+	}
+}
+
+// sign 返回r的符号，NaN保持不变
+func sign(r float64) float64 {
+	switch {
+	case r > 0:
+		return 1
+	case r < 0:
+		return -1
+	}
+	return r
+}
+
+// Unwrap 返回被包装的环境
+func (w *RewardTransform) Unwrap() core.Environment {
+	return w.env
+}
+
+func (w *RewardTransform) Reset(ctx context.Context) ([]core.Observation, error) {
+	observations, err := w.env.Reset(ctx)
+	if err != nil {
+		return nil, err
+	}
+	w.original, w.rewards = nil, nil
+	return observations, nil
+}
+
+func (w *RewardTransform) Step(ctx context.Context, actions []core.Action) ([]core.Observation, []float64, []bool, error) {
+	observations, rewards, dones, err := w.env.Step(ctx, actions)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	w.original = append(w.original[:0], rewards...)
+	w.rewards = make([]float64, len(rewards))
+	for i, r := range rewards {
+		w.rewards[i] = w.transform(r)
+	}
+	return observations, w.rewards, dones, nil
+}
+
+// GetReward 返回最近一步变换后的奖励
+func (w *RewardTransform) GetReward() []float64 {
+	if w.rewards == nil {
+		rewards := w.env.GetReward()
+		transformed := make([]float64, len(rewards))
+		for i, r := range rewards {
+			transformed[i] = w.transform(r)
+		}
+		return transformed
+	}
+	return w.rewards
+}
+
+// GetInfo 返回被包装环境的info，并在InfoKeyOriginalReward中附带最近一步变换前的奖励
+func (w *RewardTransform) GetInfo() map[string]interface{} {
+	info := w.env.GetInfo()
+	if w.original == nil {
+		return info
+	}
+	if _, ok := info[InfoKeyOriginalReward]; ok {
+		return info
+	}
+	if info == nil {
+		info = make(map[string]interface{})
+	}
+	original := make([]interface{}, len(w.original))
+	for i, r := range w.original {
+		original[i] = r
+	}
+	info[InfoKeyOriginalReward] = original
+	return info
+}
+
+func (w *RewardTransform) GetObservations() []core.Observation { return w.env.GetObservations() }
+func (w *RewardTransform) GetSpaces() core.SpaceDefinition     { return w.env.GetSpaces() }
+func (w *RewardTransform) Close() error                        { return w.env.Close() }
+
+// Metadata 返回被包装环境的元数据，奖励范围按变换调整
+func (w *RewardTransform) Metadata() core.EnvMetadata {
+	metadata := core.GetEnvMetadata(w.env)
+	metadata.RewardRange = w.bounds(metadata.RewardRange)
+	return metadata
+}
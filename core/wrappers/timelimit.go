@@ -0,0 +1,96 @@
+package wrappers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// TimeLimit 包装一个环境，回合达到maxSteps步时将所有智能体标记为结束，并在该步的info中设置truncated为true；
+// Metadata的MaxEpisodeSteps取maxSteps与被包装环境中的较小者，core.TruncationTracker据此把这类结束记为截断。
+// 自身也实现core.Environment
+type TimeLimit struct {
+	env       core.Environment
+	maxSteps  int
+	steps     int
+	truncated bool // 最近一步因达到步数上限而结束
+}
+
+var (
+	_ core.Environment      = (*TimeLimit)(nil)
+	_ core.MetadataProvider = (*TimeLimit)(nil)
+	_ core.Unwrapper        = (*TimeLimit)(nil)
+)
+
+// LimitEpisodeSteps 创建回合步数上限包装器，maxSteps须为正数
+func LimitEpisodeSteps(env core.Environment, maxSteps int) (*TimeLimit, error) {
+	if maxSteps <= 0 {
+		return nil, fmt.Errorf("max episode steps must be positive, got %d", maxSteps)
+	}
+	return &TimeLimit{env: env, maxSteps: maxSteps}, nil
+}
+
+// Unwrap 返回被包装的环境
+func (w *TimeLimit) Unwrap() core.Environment {
+	return w.env
+}
+
+// Steps 返回当前回合已执行的步数
+func (w *TimeLimit) Steps() int {
+	return w.steps
+}
+
+func (w *TimeLimit) Reset(ctx context.Context) ([]core.Observation, error) {
+	observations, err := w.env.Reset(ctx)
+	if err != nil {
+		return nil, err
+	}
+	w.steps, w.truncated = 0, false
+	return observations, nil
+}
+
+func (w *TimeLimit) Step(ctx context.Context, actions []core.Action) ([]core.Observation, []float64, []bool, error) {
+	observations, rewards, dones, err := w.env.Step(ctx, actions)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	w.steps++
+	w.truncated = false
+	if w.steps >= w.maxSteps && !core.AllDone(dones) {
+		limited := make([]bool, len(dones))
+		for i := range limited {
+			limited[i] = true
+		}
+		dones, w.truncated = limited, true
+	}
+	return observations, rewards, dones, nil
+}
+
+// GetInfo 返回被包装环境的info，回合在最近一步因达到步数上限而结束时附带truncated为true
+func (w *TimeLimit) GetInfo() map[string]interface{} {
+	info := w.env.GetInfo()
+	if !w.truncated {
+		return info
+	}
+	limited := make(map[string]interface{}, len(info)+1)
+	for k, v := range info {
+		limited[k] = v
+	}
+	limited["truncated"] = true
+	return limited
+}
+
+func (w *TimeLimit) GetObservations() []core.Observation { return w.env.GetObservations() }
+func (w *TimeLimit) GetReward() []float64                { return w.env.GetReward() }
+func (w *TimeLimit) GetSpaces() core.SpaceDefinition     { return w.env.GetSpaces() }
+func (w *TimeLimit) Close() error                        { return w.env.Close() }
+
+// Metadata 返回被包装环境的元数据，MaxEpisodeSteps不超过步数上限
+func (w *TimeLimit) Metadata() core.EnvMetadata {
+	metadata := core.GetEnvMetadata(w.env)
+	if metadata.MaxEpisodeSteps <= 0 || metadata.MaxEpisodeSteps > w.maxSteps {
+		metadata.MaxEpisodeSteps = w.maxSteps
+	}
+	return metadata
+}
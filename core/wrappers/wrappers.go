@@ -0,0 +1,76 @@
+// Package wrappers 提供逐步变换环境的动作、观察与奖励的通用包装器，可在进程内直接使用，
+// 其中动作缩放、奖励变换与噪声注入也可由创建配置中的键开启，服务端据此自动包装远程客户端创建的环境。
+package wrappers
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// FromConfig 按配置中的ConfigKeyRandomize、ConfigKeyRescaleAction、ConfigKeyRewardScale、ConfigKeyRewardClip
+// 与ConfigKeyRewardSign依次包装环境，均未设置时原样返回env。动作噪声以环境自身的动作单位注入，因此在动作缩放之内
+func FromConfig(env core.Environment, config core.Config) (core.Environment, error) {
+	if config == nil {
+		return env, nil
+	}
+	env, err := randomizeFromConfig(env, config)
+	if err != nil {
+		return nil, err
+	}
+	if env, err = rescaleFromConfig(env, config); err != nil {
+		return nil, err
+	}
+	if scale, ok, err := config.GetFloat64(ConfigKeyRewardScale); err != nil {
+		return nil, err
+	} else if ok {
+		wrapped, err := ScaleReward(env, scale)
+		if err != nil {
+			return nil, err
+		}
+		env = wrapped
+	}
+	if value := config.GetValue(ConfigKeyRewardClip); value != nil {
+		min, max, err := clipRange(value)
+		if err != nil {
+			return nil, err
+		}
+		wrapped, err := ClipReward(env, min, max)
+		if err != nil {
+			return nil, err
+		}
+		env = wrapped
+	}
+	if enabled, _, err := config.GetBool(ConfigKeyRewardSign); err != nil {
+		return nil, err
+	} else if enabled {
+		env = SignReward(env)
+	}
+	return env, nil
+}
+
+// clipRange 解析ConfigKeyRewardClip：单个数c表示[-c, c]，两个数的列表表示[min, max]
+func clipRange(value interface{}) (float64, float64, error) {
+	var bounds []interface{}
+	switch v := value.(type) {
+	case []interface{}:
+		bounds = v
+	case []float64:
+		for _, b := range v {
+			bounds = append(bounds, b)
+		}
+	default:
+		if c, err := core.ToFloat64(v); err == nil {
+			return -math.Abs(c), math.Abs(c), nil
+		}
+	}
+	if len(bounds) == 2 {
+		min, errMin := core.ToFloat64(bounds[0])
+		max, errMax := core.ToFloat64(bounds[1])
+		if errMin == nil && errMax == nil {
+			return min, max, nil
+		}
+	}
+	return 0, 0, fmt.Errorf("%s must be a number or a [min, max] list, got %v", ConfigKeyRewardClip, value)
+}
@@ -0,0 +1,55 @@
+package rl_env_engine
+
+import (
+	"context"
+
+	"github.com/jelech/rl_env_engine/core/policy"
+)
+
+// Policy chooses the actions of a step from the observations, see core/policy for the
+// built-in baselines (random, zero and per-scenario heuristics) and ONNX models
+type Policy = policy.Policy
+
+// TerminationReason tells why an episode ended
+type TerminationReason = policy.TerminationReason
+
+const (
+	// EpisodeTerminated means every agent reached a terminal state
+	EpisodeTerminated = policy.EpisodeTerminated
+	// EpisodeTruncated means the scenario cut the episode short, through its MaxEpisodeSteps
+	// or info["truncated"]
+	EpisodeTruncated = policy.EpisodeTruncated
+	// EpisodeMaxSteps means the episode reached RunOptions.MaxSteps before ending on its own
+	EpisodeMaxSteps = policy.EpisodeMaxSteps
+)
+
+// RunOptions configures RunEpisodes and RunPolicy: the number of episodes, the step cap
+// of each episode (policy.DefaultMaxSteps when <= 0) and the OnStep and OnEpisode callbacks
+type RunOptions = policy.RunOptions
+
+// StepInfo describes one step of RunEpisodes. Pooled observations are released once
+// OnStep and the next action choice have used them, so copy any kept beyond OnStep
+type StepInfo = policy.StepInfo
+
+// EpisodeResult summarizes one episode of RunEpisodes
+type EpisodeResult = policy.EpisodeResult
+
+// RunResult aggregates the episodes of RunEpisodes
+type RunResult = policy.RunResult
+
+// RunEpisodes runs episodes of sim, choosing the actions of each step with actionFunc.
+// An episode ends when every agent is done or after opts.MaxSteps steps. The simulation
+// is left open. When a step or a callback fails, the episodes completed so far are
+// returned along with the error
+func RunEpisodes(ctx context.Context, sim Simulation, actionFunc func([]Observation) []Action, opts RunOptions) (*RunResult, error) {
+	return policy.Run(ctx, sim, policy.Func(func(observations []Observation) ([]Action, error) {
+		return actionFunc(observations), nil
+	}), opts)
+}
+
+// RunPolicy runs episodes of sim like RunEpisodes, choosing the actions with p, e.g. one of
+// the baselines of core/policy or an ONNX model. In turn-based simulations only the
+// observation of the player to move is passed to p
+func RunPolicy(ctx context.Context, sim Simulation, p Policy, opts RunOptions) (*RunResult, error) {
+	return policy.Run(ctx, sim, policy.CurrentPlayer(sim, p), opts)
+}
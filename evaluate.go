@@ -0,0 +1,161 @@
+package rl_env_engine
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/jelech/rl_env_engine/core"
+	"github.com/jelech/rl_env_engine/core/metrics"
+	"github.com/jelech/rl_env_engine/core/policy"
+)
+
+// SuccessKeys are the info keys Evaluate looks for, in order, to decide whether an episode
+// succeeded when EvalOptions sets neither SuccessKey nor Success. The first one holding a
+// bool after the last step of the episode wins
+var SuccessKeys = policy.SuccessKeys
+
+// EvalOptions configures Evaluate and EvaluateBaseline
+type EvalOptions struct {
+	// MaxSteps caps the steps of each episode, policy.DefaultMaxSteps when <= 0
+	MaxSteps int
+	// Confidence is the level of the confidence intervals, 0.95 when outside (0, 1)
+	Confidence float64
+	// SuccessKey is the info key holding whether an episode succeeded; SuccessKeys are
+	// tried when it is empty
+	SuccessKey string
+	// Success, when set, decides whether an episode succeeded instead of the info keys
+	Success func(episode EvalEpisode) bool
+	// Wrappers are applied to the simulation before evaluating, e.g. TimeLimit
+	Wrappers []Wrapper
+	// Metrics, when set, receives the return, length and success of every episode and the
+	// summary of the evaluation, tagged with the scenario and Tags
+	Metrics core.MetricsSink
+	Tags    map[string]string
+	// Seed seeds the random baseline of EvaluateBaseline, offset by the worker index
+	Seed int64
+	// Workers runs the episodes across that many goroutines like RunParallel, 1 when <= 0.
+	// Evaluate then shares its policy between the workers, so it must be safe for
+	// concurrent use, and Metrics receives the episodes in completion order
+	Workers int
+}
+
+// EvalEpisode records one episode of an evaluation: its result, whether it succeeded
+// (when that could be Judged) and the info of the simulation after its last step
+type EvalEpisode = policy.EvalEpisode
+
+// Evaluation summarizes the episodes of Evaluate: the mean return with its sample standard
+// deviation and Student t confidence interval, the mean length, and the success rate with
+// its Wilson interval. Its String method gives a one-line summary, e.g.
+// "cartpole: mean return 187.4 ± 9.1 (95% CI) over 20 episodes, mean length 187.4, success 85% [64%, 95%]"
+type Evaluation = policy.Evaluation
+
+// Evaluate runs episodes of the scenario or preset with p and summarizes them: the mean
+// return with its confidence interval, the success rate and a record of every episode.
+// In turn-based scenarios only the observation of the player to move is passed to p.
+// The simulation is created from config, wrapped with opts.Wrappers and closed afterwards.
+// When an episode fails, the evaluation of the episodes completed so far is returned
+// along with the error
+func Evaluate(ctx context.Context, p Policy, scenario string, config map[string]interface{}, episodes int, opts EvalOptions) (*Evaluation, error) {
+	if p == nil {
+		return nil, fmt.Errorf("policy is required")
+	}
+	return evaluate(ctx, scenario, config, episodes, opts, func(Simulation, string, int) (Policy, error) {
+		return p, nil
+	})
+}
+
+// EvaluateBaseline evaluates one of the baselines of core/policy (random, zero or heuristic)
+// like Evaluate. The random baseline is seeded with opts.Seed, each worker getting its own,
+// and a preset uses the heuristic of its scenario
+func EvaluateBaseline(ctx context.Context, baseline, scenario string, config map[string]interface{}, episodes int, opts EvalOptions) (*Evaluation, error) {
+	return evaluate(ctx, scenario, config, episodes, opts, func(sim Simulation, scenario string, worker int) (Policy, error) {
+		return policy.Baseline(baseline, scenario, sim, rand.New(rand.NewSource(opts.Seed+int64(worker))))
+	})
+}
+
+func evaluate(ctx context.Context, scenario string, config map[string]interface{}, episodes int, opts EvalOptions,
+	newPolicy func(sim Simulation, scenario string, worker int) (Policy, error)) (*Evaluation, error) {
+	engine, err := newBuiltinEngine()
+	if err != nil {
+		return nil, err
+	}
+	base := scenario
+	if preset, ok := engine.GetPreset(scenario); ok {
+		base = preset.Scenario
+	}
+
+	tags := map[string]string{"scenario": scenario}
+	for k, v := range opts.Tags {
+		tags[k] = v
+	}
+	var mu sync.Mutex
+	onEpisode := func(episode EvalEpisode) error {
+		if opts.Metrics == nil {
+			return nil
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		opts.Metrics.Scalar(metrics.MetricEpisodeReturn, episode.Return, tags)
+		opts.Metrics.Scalar(metrics.MetricEpisodeLength, float64(episode.Length), tags)
+		if episode.Judged {
+			opts.Metrics.Scalar(metrics.MetricEpisodeSuccess, boolValue(episode.Success), tags)
+		}
+		return nil
+	}
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = 1
+	}
+	results := make([]*Evaluation, workers)
+	firsts := make([]int, workers)
+	start := time.Now()
+	err = runWorkers(ctx, engine, scenario, config, opts.Wrappers, workers, episodes,
+		func(ctx context.Context, worker int, sim Simulation, first, count int) error {
+			p, err := newPolicy(sim, base, worker)
+			if err != nil {
+				return err
+			}
+			firsts[worker] = first
+			results[worker], err = policy.Evaluate(ctx, sim, p, count, policy.EvalOptions{
+				MaxSteps:   opts.MaxSteps,
+				SuccessKey: opts.SuccessKey,
+				Success:    opts.Success,
+				OnEpisode:  onEpisode,
+			})
+			return err
+		})
+
+	evaluation := &Evaluation{Scenario: scenario, Confidence: opts.Confidence}
+	for w, result := range results {
+		if result == nil {
+			continue
+		}
+		for _, episode := range result.Episodes {
+			episode.Episode += firsts[w]
+			evaluation.Episodes = append(evaluation.Episodes, episode)
+		}
+		evaluation.Steps += result.Steps
+	}
+	evaluation.Elapsed = time.Since(start)
+	evaluation.Summarize()
+	if opts.Metrics != nil && len(evaluation.Episodes) > 0 {
+		opts.Metrics.Scalar(metrics.MetricEvalMeanReturn, evaluation.MeanReturn, tags)
+		opts.Metrics.Scalar(metrics.MetricEvalReturnLow, evaluation.ReturnLow, tags)
+		opts.Metrics.Scalar(metrics.MetricEvalReturnHigh, evaluation.ReturnHigh, tags)
+		if evaluation.Judged > 0 {
+			opts.Metrics.Scalar(metrics.MetricEvalSuccessRate, evaluation.SuccessRate, tags)
+		}
+	}
+	return evaluation, err
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -1,18 +1,33 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"log"
 
 	simulations "github.com/jelech/rl_env_engine"
 )
 
 func main() {
+	configPath := flag.String("config", "", "Path to a YAML/JSON server file, or a simulation file with a server section")
+	flag.Parse()
+
 	// 创建服务器配置
 	config := &simulations.ServerConfig{
 		HTTPConfig: simulations.NewHTTPServerConfig(8080).WithHost("0.0.0.0"),
 		GrpcConfig: simulations.NewGrpcServerConfig(9090).WithHost("0.0.0.0"),
 	}
 
+	// 从配置文件覆盖服务器地址、TLS、限制与日志级别等
+	if *configPath != "" {
+		file, err := simulations.LoadServerConfigFile(*configPath)
+		if err != nil {
+			log.Fatalf("Failed to load config: %v", err)
+		}
+		file.ApplyTo(config)
+		log.Printf("Loaded server configuration from %s", *configPath)
+	}
+
 	log.Println("Starting both HTTP and gRPC simulation servers...")
 	log.Printf("HTTP server will listen on %s", config.HTTPConfig.Address())
 	log.Printf("gRPC server will listen on %s", config.GrpcConfig.Address())
@@ -33,8 +48,9 @@ func main() {
 	log.Println("  - CloseEnvironment: Close environment")
 	log.Println("  - StreamStep: Stream simulation steps")
 
-	// 启动两个服务器并等待
-	if err := simulations.StartServersAndWait(config); err != nil {
+	// 启动两个服务器并等待，Ctrl-C时优雅关闭并关闭全部环境
+	config.HandleSignals = true
+	if err := simulations.StartServersAndWait(context.Background(), config); err != nil {
 		log.Fatalf("Server error: %v", err)
 	}
 }
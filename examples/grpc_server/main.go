@@ -1,15 +1,29 @@
 package main
 
 import (
+	"flag"
 	"log"
 
 	simulations "github.com/jelech/rl_env_engine"
 )
 
 func main() {
+	configPath := flag.String("config", "", "Path to a YAML/JSON simulation file (scenario, config and optional server section)")
+	flag.Parse()
+
 	// 创建gRPC服务器配置
 	grpcConfig := simulations.NewGrpcServerConfig(9090).WithHost("0.0.0.0")
 
+	// 从配置文件覆盖服务器地址
+	if *configPath != "" {
+		file, err := simulations.LoadSimulationFile(*configPath)
+		if err != nil {
+			log.Fatalf("Failed to load config: %v", err)
+		}
+		file.Server.ApplyTo(&simulations.ServerConfig{GrpcConfig: grpcConfig})
+		log.Printf("Loaded %s: scenario=%s config=%v", *configPath, file.Scenario, file.Config)
+	}
+
 	log.Println("Starting gRPC simulation server...")
 	log.Printf("gRPC server will listen on %s", grpcConfig.Address())
 	log.Println("Available gRPC methods:")
@@ -12,11 +12,22 @@ func main() {
 	// Parse command line flags
 	port := flag.Int("port", 8080, "Port to run the server on")
 	host := flag.String("host", "localhost", "Host to bind the server to")
+	configPath := flag.String("config", "", "Path to a YAML/JSON simulation file (scenario, config and optional server section)")
 	flag.Parse()
 
 	// Create server configuration
 	config := simulations.NewHTTPServerConfig(*port).WithHost(*host)
 
+	// Values from the config file take precedence over flags
+	if *configPath != "" {
+		file, err := simulations.LoadSimulationFile(*configPath)
+		if err != nil {
+			log.Fatalf("Failed to load config: %v", err)
+		}
+		file.Server.ApplyTo(&simulations.ServerConfig{HTTPConfig: config})
+		log.Printf("Loaded %s: scenario=%s config=%v", *configPath, file.Scenario, file.Config)
+	}
+
 	log.Printf("Starting simulation HTTP server on %s", config.Address())
 	log.Println("This server provides OpenAI Gym-compatible API for:")
 	log.Println("  - Python reinforcement learning libraries")
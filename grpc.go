@@ -1,16 +1,71 @@
 package rl_env_engine
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"time"
 
+	"github.com/jelech/rl_env_engine/core"
+	"github.com/jelech/rl_env_engine/core/runstore"
+	"github.com/jelech/rl_env_engine/core/wasm"
 	"github.com/jelech/rl_env_engine/server"
 )
 
 // GrpcServerConfig represents gRPC server configuration
 type GrpcServerConfig struct {
 	Port int
+	// Host is the TCP host, or a unix:///path/to.sock address to serve on a Unix socket
+	// instead (Port is then ignored; the socket is created with mode 0600)
 	Host string
+	// PresetDir, when set, is a directory of YAML/JSON simulation files served as named
+	// presets and reloaded while the server runs
+	PresetDir string
+	// MetricsSink, when set, receives episode metrics and environment lifecycle events
+	MetricsSink core.MetricsSink
+	// Hooks, when set, are the lifecycle hooks fired for every environment the server creates
+	// (share one value between servers to observe all of them)
+	Hooks *core.Hooks
+	// Logger, when set, receives the server's log output instead of the default logger
+	Logger Logger
+	// RunStore, when set, records environment creations and episode results
+	RunStore *runstore.Store
+	// OutputDir, when set, is the server directory that output paths given by clients
+	// (record_path, video_dir and tensorboard_dir) are resolved under; absolute paths and
+	// paths containing .. are rejected. Without it the server writes no files requested by
+	// clients
+	OutputDir string
+	// GymnasiumAPI makes every environment report terminated and truncated flags by default;
+	// a create request can still override it with the gymnasium_api option
+	GymnasiumAPI bool
+	// Limits caps the environments a server hosts, per client and per session, and their
+	// observation size; zero fields are unlimited
+	Limits server.Limits
+	// AdminToken, when set, must accompany admin requests (listing, force-closing, dumping
+	// and draining environments); when empty the admin API is unauthenticated
+	AdminToken string
+	// Tenants, when set, splits the server between teams: each request must carry one of
+	// its tenant's API keys and only sees that tenant's environments, scenarios and limits
+	Tenants []server.Tenant
+	// WasmScenarios, when set, lets clients upload WebAssembly modules implementing the
+	// wasmenv ABI as new scenarios; every environment runs in a sandbox bounded by these limits.
+	// Uploads are admin requests, so WasmScenarios requires AdminToken
+	WasmScenarios *wasm.Limits
+	// Scenarios, when set, restricts the scenarios and presets clients may create to these names
+	Scenarios []string
+	// SessionTTL is the idle timeout of sessions opened without one (server.DefaultSessionTTL
+	// when zero); MaxSessionTTL, when set, caps the timeout a client may request
+	SessionTTL    time.Duration
+	MaxSessionTTL time.Duration
+	// TLSCertFile and TLSKeyFile, when both set, are the PEM certificate and key the server
+	// uses to serve over TLS
+	TLSCertFile string
+	TLSKeyFile  string
+	// SnapshotPath, when set, is where the server periodically saves the environments that
+	// support checkpoints; they are restored from it on startup
+	SnapshotPath string
+	// SnapshotInterval is how often the snapshot is saved (server.DefaultSnapshotInterval
+	// when zero)
+	SnapshotInterval time.Duration
 }
 
 // DefaultGrpcServerConfig returns default gRPC server configuration
@@ -24,32 +79,108 @@ func DefaultGrpcServerConfig() *GrpcServerConfig {
 // StartGrpcServer starts the gRPC API server for reinforcement learning integration
 // This allows gRPC clients to interact with the simulation
 func StartGrpcServer(config *GrpcServerConfig) error {
-	if config == nil {
-		config = DefaultGrpcServerConfig()
-	}
-
-	grpcServer := server.NewGrpcServer()
+	return StartGrpcServerContext(context.Background(), config)
+}
 
-	log.Printf("Starting Simulation gRPC server...")
-	log.Printf("Server will be available at %s:%d", config.Host, config.Port)
-	log.Printf("gRPC clients can connect to this server for RL training")
+// StartGrpcServerContext starts the gRPC server like StartGrpcServer and serves until ctx
+// is canceled. It then waits up to DefaultShutdownTimeout for pending RPCs and streams,
+// saves a final snapshot when snapshots are enabled and closes every active environment
+// before returning nil
+func StartGrpcServerContext(ctx context.Context, config *GrpcServerConfig) error {
+	srv, err := listenGrpc(config)
+	if err != nil {
+		return err
+	}
+	defer srv.close()
+	return serveContext(ctx, srv.serve, srv.shutdown)
+}
 
-	return grpcServer.StartGrpcServer(config.Port)
+// StartGrpcServerAsync sets up the gRPC server, binds its address and serves it in a separate
+// goroutine. Setup and listen errors are returned at once; the returned handle reports the
+// bound address (the actual port when Port is 0), later server errors and stops the server
+func StartGrpcServerAsync(config *GrpcServerConfig) (*ServerHandle, error) {
+	srv, err := listenGrpc(config)
+	if err != nil {
+		return nil, err
+	}
+	return newServerHandle(srv), nil
 }
 
-// StartGrpcServerAsync starts the gRPC server in a separate goroutine
-// Returns a channel that will receive any error from the server
-func StartGrpcServerAsync(config *GrpcServerConfig) <-chan error {
-	errCh := make(chan error, 1)
+// listenGrpc sets up the gRPC server and binds its address without serving yet
+func listenGrpc(config *GrpcServerConfig) (_ *startedServer, err error) {
+	if config == nil {
+		config = DefaultGrpcServerConfig()
+	}
 
-	go func() {
-		defer close(errCh)
-		if err := StartGrpcServer(config); err != nil {
-			errCh <- err
+	grpcServer := server.NewGrpcServer()
+	srv := &startedServer{shutdown: grpcServer.Shutdown}
+	srv.onClose(func() { grpcServer.CloseEnvironments() })
+	defer func() {
+		if err != nil {
+			srv.close()
 		}
 	}()
+	InstallScenarios(grpcServer.Engine())
+	if err := InstallPresets(grpcServer.Engine()); err != nil {
+		return nil, err
+	}
+	if config.Hooks != nil {
+		grpcServer.Engine().SetHooks(config.Hooks)
+	}
+	grpcServer.SetMetricsSink(config.MetricsSink)
+	grpcServer.SetLogger(config.Logger)
+	logger := loggerOr(config.Logger)
+	if config.RunStore != nil {
+		grpcServer.SetRunStore(config.RunStore)
+	}
+	grpcServer.SetOutputDir(config.OutputDir)
+	grpcServer.SetGymnasiumAPI(config.GymnasiumAPI)
+	grpcServer.SetLimits(config.Limits)
+	grpcServer.SetAdminToken(config.AdminToken)
+	if err := grpcServer.SetTenants(config.Tenants); err != nil {
+		return nil, err
+	}
+	if config.WasmScenarios != nil {
+		if config.AdminToken == "" {
+			return nil, fmt.Errorf("uploading WASM scenarios requires an admin token")
+		}
+		grpcServer.EnableWasmScenarios(*config.WasmScenarios)
+	}
+	grpcServer.SetScenarios(config.Scenarios)
+	grpcServer.SetSessionTTL(config.SessionTTL, config.MaxSessionTTL)
+	if config.TLSCertFile != "" || config.TLSKeyFile != "" {
+		tlsConfig, err := server.LoadTLSConfig(config.TLSCertFile, config.TLSKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		grpcServer.SetTLS(tlsConfig)
+	}
+	if config.SnapshotPath != "" {
+		restored, err := grpcServer.RestoreSnapshot(config.SnapshotPath)
+		if err != nil {
+			return nil, err
+		}
+		if restored > 0 {
+			logger.Info("Restored environments from snapshot", "environments", restored, "path", config.SnapshotPath)
+		}
+		srv.onClose(grpcServer.StartSnapshots(config.SnapshotPath, config.SnapshotInterval))
+	}
+	if config.PresetDir != "" {
+		stop, err := watchPresetDir(grpcServer.Engine(), config.PresetDir, DefaultPresetReloadInterval, logger)
+		if err != nil {
+			return nil, err
+		}
+		srv.onClose(stop)
+	}
 
-	return errCh
+	lis, addr, err := listenAddress(config.Host, config.Port)
+	if err != nil {
+		return nil, err
+	}
+	srv.addr = addr
+	srv.serve = func() error { return grpcServer.Serve(lis) }
+	logger.Info("Starting Simulation gRPC server", "addr", addr)
+	return srv, nil
 }
 
 // NewGrpcServerConfig creates a new gRPC server configuration
@@ -60,7 +191,7 @@ func NewGrpcServerConfig(port int) *GrpcServerConfig {
 	}
 }
 
-// WithHost sets the host for gRPC server
+// WithHost sets the host for gRPC server, or a unix:// address to serve on a Unix socket
 func (c *GrpcServerConfig) WithHost(host string) *GrpcServerConfig {
 	c.Host = host
 	return c
@@ -72,7 +203,104 @@ func (c *GrpcServerConfig) WithPort(port int) *GrpcServerConfig {
 	return c
 }
 
-// Address returns the full address string
+// WithPresetDir sets the directory of hot-reloaded environment presets
+func (c *GrpcServerConfig) WithPresetDir(dir string) *GrpcServerConfig {
+	c.PresetDir = dir
+	return c
+}
+
+// WithMetricsSink sets where episode metrics and environment events are published
+func (c *GrpcServerConfig) WithMetricsSink(sink core.MetricsSink) *GrpcServerConfig {
+	c.MetricsSink = sink
+	return c
+}
+
+// WithHooks sets the lifecycle hooks fired for the server's environments
+func (c *GrpcServerConfig) WithHooks(hooks *core.Hooks) *GrpcServerConfig {
+	c.Hooks = hooks
+	return c
+}
+
+// WithLogger sets the logger receiving the server's log output
+func (c *GrpcServerConfig) WithLogger(logger Logger) *GrpcServerConfig {
+	c.Logger = logger
+	return c
+}
+
+// WithRunStore sets the store that records runs and episodes
+func (c *GrpcServerConfig) WithRunStore(store *runstore.Store) *GrpcServerConfig {
+	c.RunStore = store
+	return c
+}
+
+// WithOutputDir sets the server directory that client output paths are resolved under
+func (c *GrpcServerConfig) WithOutputDir(dir string) *GrpcServerConfig {
+	c.OutputDir = dir
+	return c
+}
+
+// WithGymnasiumAPI sets whether environments report terminated and truncated flags by default
+func (c *GrpcServerConfig) WithGymnasiumAPI(enabled bool) *GrpcServerConfig {
+	c.GymnasiumAPI = enabled
+	return c
+}
+
+// WithLimits sets the resource limits checked when creating environments
+func (c *GrpcServerConfig) WithLimits(limits server.Limits) *GrpcServerConfig {
+	c.Limits = limits
+	return c
+}
+
+// WithAdminToken sets the token required by the admin API
+func (c *GrpcServerConfig) WithAdminToken(token string) *GrpcServerConfig {
+	c.AdminToken = token
+	return c
+}
+
+// WithTenants sets the tenants sharing the server
+func (c *GrpcServerConfig) WithTenants(tenants []server.Tenant) *GrpcServerConfig {
+	c.Tenants = tenants
+	return c
+}
+
+// WithWasmScenarios lets clients upload WebAssembly scenarios sandboxed by limits; the
+// server then requires an admin token
+func (c *GrpcServerConfig) WithWasmScenarios(limits wasm.Limits) *GrpcServerConfig {
+	c.WasmScenarios = &limits
+	return c
+}
+
+// WithScenarios restricts the scenarios and presets clients may create
+func (c *GrpcServerConfig) WithScenarios(names ...string) *GrpcServerConfig {
+	c.Scenarios = names
+	return c
+}
+
+// WithSessionTTL sets the default and maximum idle timeout of client sessions
+func (c *GrpcServerConfig) WithSessionTTL(def, max time.Duration) *GrpcServerConfig {
+	c.SessionTTL = def
+	c.MaxSessionTTL = max
+	return c
+}
+
+// WithTLS serves over TLS with the PEM certificate and key files
+func (c *GrpcServerConfig) WithTLS(certFile, keyFile string) *GrpcServerConfig {
+	c.TLSCertFile = certFile
+	c.TLSKeyFile = keyFile
+	return c
+}
+
+// WithSnapshot sets where and how often active environments are saved for restoring on startup
+func (c *GrpcServerConfig) WithSnapshot(path string, interval time.Duration) *GrpcServerConfig {
+	c.SnapshotPath = path
+	c.SnapshotInterval = interval
+	return c
+}
+
+// Address returns the full address string, or the unix:// address of a Unix socket
 func (c *GrpcServerConfig) Address() string {
+	if server.IsUnixAddress(c.Host) {
+		return c.Host
+	}
 	return fmt.Sprintf("%s:%d", c.Host, c.Port)
 }
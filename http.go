@@ -1,16 +1,81 @@
 package rl_env_engine
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"net"
+	"strconv"
+	"time"
 
+	"github.com/jelech/rl_env_engine/core"
+	"github.com/jelech/rl_env_engine/core/runstore"
+	"github.com/jelech/rl_env_engine/core/wasm"
 	"github.com/jelech/rl_env_engine/server"
 )
 
 // HTTPServerConfig represents HTTP server configuration
 type HTTPServerConfig struct {
 	Port int
+	// Host is the TCP host, or a unix:///path/to.sock address to serve on a Unix socket
+	// instead (Port is then ignored; the socket is created with mode 0600)
 	Host string
+	// PresetDir, when set, is a directory of YAML/JSON simulation files served as named
+	// presets and reloaded while the server runs
+	PresetDir string
+	// MetricsSink, when set, receives episode metrics and environment lifecycle events
+	MetricsSink core.MetricsSink
+	// Hooks, when set, are the lifecycle hooks fired for every environment the server creates
+	// (share one value between servers to observe all of them)
+	Hooks *core.Hooks
+	// Logger, when set, receives the server's log output instead of the default logger
+	Logger Logger
+	// RunStore, when set, records environment creations and episode results
+	RunStore *runstore.Store
+	// OutputDir, when set, is the server directory that output paths given by clients
+	// (record_path, video_dir, tensorboard_dir and /record) are resolved under; absolute
+	// paths and paths containing .. are rejected. Without it the server writes no files
+	// requested by clients
+	OutputDir string
+	// GymnasiumAPI makes every environment report terminated and truncated flags by default;
+	// a create request can still override it with the gymnasium_api option
+	GymnasiumAPI bool
+	// Limits caps the environments a server hosts, per client and per session, and their
+	// observation size; zero fields are unlimited
+	Limits server.Limits
+	// AdminToken, when set, must accompany admin requests (listing, force-closing, dumping
+	// and draining environments); when empty the admin API is unauthenticated
+	AdminToken string
+	// Tenants, when set, splits the server between teams: each request must carry one of
+	// its tenant's API keys and only sees that tenant's environments, scenarios and limits
+	Tenants []server.Tenant
+	// WasmScenarios, when set, lets clients upload WebAssembly modules implementing the
+	// wasmenv ABI as new scenarios; every environment runs in a sandbox bounded by these limits.
+	// Uploads are admin requests, so WasmScenarios requires AdminToken
+	WasmScenarios *wasm.Limits
+	// Scenarios, when set, restricts the scenarios and presets clients may create to these names
+	Scenarios []string
+	// SessionTTL is the idle timeout of sessions opened without one (server.DefaultSessionTTL
+	// when zero); MaxSessionTTL, when set, caps the timeout a client may request
+	SessionTTL    time.Duration
+	MaxSessionTTL time.Duration
+	// TLSCertFile and TLSKeyFile, when both set, are the PEM certificate and key the server
+	// uses to serve over TLS
+	TLSCertFile string
+	TLSKeyFile  string
+	// SnapshotPath, when set, is where the server periodically saves the environments that
+	// support checkpoints; they are restored from it on startup
+	SnapshotPath string
+	// SnapshotInterval is how often the snapshot is saved (server.DefaultSnapshotInterval
+	// when zero)
+	SnapshotInterval time.Duration
+	// DebugPort, when set, serves net/http/pprof profiles (/debug/pprof/) and expvar variables
+	// (/debug/vars) on this separate port of DebugHost, without TLS and behind AdminToken when
+	// one is set
+	DebugPort int
+	// DebugHost is the host of DebugPort, localhost when empty. The profiles expose the process
+	// internals, so a host other than a loopback one requires AdminToken
+	DebugHost string
 }
 
 // DefaultHTTPServerConfig returns default HTTP server configuration
@@ -24,32 +89,135 @@ func DefaultHTTPServerConfig() *HTTPServerConfig {
 // StartHTTPServer starts the HTTP API server for reinforcement learning integration
 // This allows Python clients and other HTTP clients to interact with the simulation
 func StartHTTPServer(config *HTTPServerConfig) error {
-	if config == nil {
-		config = DefaultHTTPServerConfig()
-	}
-
-	api := server.NewGymAPI()
+	return StartHTTPServerContext(context.Background(), config)
+}
 
-	log.Printf("Starting Simulation HTTP API server...")
-	log.Printf("Server will be available at http://%s:%d", config.Host, config.Port)
-	log.Printf("Python clients can connect to this server for RL training")
+// StartHTTPServerContext starts the HTTP API server like StartHTTPServer and serves until
+// ctx is canceled. It then waits up to DefaultShutdownTimeout for pending requests, saves a
+// final snapshot when snapshots are enabled and closes every active environment before
+// returning nil
+func StartHTTPServerContext(ctx context.Context, config *HTTPServerConfig) error {
+	srv, err := listenHTTP(config)
+	if err != nil {
+		return err
+	}
+	defer srv.close()
+	return serveContext(ctx, srv.serve, srv.shutdown)
+}
 
-	return api.StartServer(config.Port)
+// StartHTTPServerAsync sets up the HTTP server, binds its address and serves it in a separate
+// goroutine. Setup and listen errors are returned at once; the returned handle reports the
+// bound address (the actual port when Port is 0), later server errors and stops the server
+func StartHTTPServerAsync(config *HTTPServerConfig) (*ServerHandle, error) {
+	srv, err := listenHTTP(config)
+	if err != nil {
+		return nil, err
+	}
+	return newServerHandle(srv), nil
 }
 
-// StartHTTPServerAsync starts the HTTP server in a separate goroutine
-// Returns a channel that will receive any error from the server
-func StartHTTPServerAsync(config *HTTPServerConfig) <-chan error {
-	errCh := make(chan error, 1)
+// listenHTTP sets up the HTTP server and binds its address without serving yet
+func listenHTTP(config *HTTPServerConfig) (_ *startedServer, err error) {
+	if config == nil {
+		config = DefaultHTTPServerConfig()
+	}
 
-	go func() {
-		defer close(errCh)
-		if err := StartHTTPServer(config); err != nil {
-			errCh <- err
+	api := server.NewGymAPI()
+	srv := &startedServer{shutdown: api.Shutdown}
+	srv.onClose(func() { api.CloseEnvironments() })
+	defer func() {
+		if err != nil {
+			srv.close()
 		}
 	}()
+	InstallScenarios(api.Engine())
+	if err := InstallPresets(api.Engine()); err != nil {
+		return nil, err
+	}
+	if config.Hooks != nil {
+		api.Engine().SetHooks(config.Hooks)
+	}
+	api.SetMetricsSink(config.MetricsSink)
+	api.SetLogger(config.Logger)
+	logger := loggerOr(config.Logger)
+	if config.RunStore != nil {
+		api.SetRunStore(config.RunStore)
+	}
+	api.SetOutputDir(config.OutputDir)
+	api.SetGymnasiumAPI(config.GymnasiumAPI)
+	api.SetLimits(config.Limits)
+	api.SetAdminToken(config.AdminToken)
+	if err := api.SetTenants(config.Tenants); err != nil {
+		return nil, err
+	}
+	if config.WasmScenarios != nil {
+		if config.AdminToken == "" {
+			return nil, fmt.Errorf("uploading WASM scenarios requires an admin token")
+		}
+		api.EnableWasmScenarios(*config.WasmScenarios)
+	}
+	api.SetScenarios(config.Scenarios)
+	api.SetSessionTTL(config.SessionTTL, config.MaxSessionTTL)
+	if config.TLSCertFile != "" || config.TLSKeyFile != "" {
+		tlsConfig, err := server.LoadTLSConfig(config.TLSCertFile, config.TLSKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		api.SetTLS(tlsConfig)
+	}
+	if config.SnapshotPath != "" {
+		restored, err := api.RestoreSnapshot(config.SnapshotPath)
+		if err != nil {
+			return nil, err
+		}
+		if restored > 0 {
+			logger.Info("Restored environments from snapshot", "environments", restored, "path", config.SnapshotPath)
+		}
+		srv.onClose(api.StartSnapshots(config.SnapshotPath, config.SnapshotInterval))
+	}
+	if config.PresetDir != "" {
+		stop, err := watchPresetDir(api.Engine(), config.PresetDir, DefaultPresetReloadInterval, logger)
+		if err != nil {
+			return nil, err
+		}
+		srv.onClose(stop)
+	}
+	if config.DebugPort != 0 {
+		host := config.DebugHost
+		if host == "" {
+			host = "localhost"
+		}
+		if !isLoopbackHost(host) && config.AdminToken == "" {
+			return nil, fmt.Errorf("serving the debug port on %s requires an admin token", host)
+		}
+		lis, err := net.Listen("tcp", net.JoinHostPort(host, strconv.Itoa(config.DebugPort)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to listen on debug port: %w", err)
+		}
+		srv.onClose(func() { lis.Close() })
+		go func() {
+			if err := api.ServeDebug(lis); !errors.Is(err, net.ErrClosed) {
+				logger.Error("Debug server stopped", "addr", lis.Addr().String(), "error", err)
+			}
+		}()
+	}
 
-	return errCh
+	lis, addr, err := listenAddress(config.Host, config.Port)
+	if err != nil {
+		return nil, err
+	}
+	srv.addr = addr
+	srv.serve = func() error { return api.Serve(lis) }
+	if server.IsUnixAddress(config.Host) {
+		logger.Info("Starting Simulation HTTP API server", "addr", addr)
+		return srv, nil
+	}
+	scheme := "http"
+	if config.TLSCertFile != "" {
+		scheme = "https"
+	}
+	logger.Info("Starting Simulation HTTP API server", "addr", scheme+"://"+addr)
+	return srv, nil
 }
 
 // NewHTTPServerConfig creates a new HTTP server configuration
@@ -60,7 +228,7 @@ func NewHTTPServerConfig(port int) *HTTPServerConfig {
 	}
 }
 
-// WithHost sets the host for HTTP server
+// WithHost sets the host for HTTP server, or a unix:// address to serve on a Unix socket
 func (c *HTTPServerConfig) WithHost(host string) *HTTPServerConfig {
 	c.Host = host
 	return c
@@ -72,7 +240,125 @@ func (c *HTTPServerConfig) WithPort(port int) *HTTPServerConfig {
 	return c
 }
 
-// Address returns the full address string
+// WithPresetDir sets the directory of hot-reloaded environment presets
+func (c *HTTPServerConfig) WithPresetDir(dir string) *HTTPServerConfig {
+	c.PresetDir = dir
+	return c
+}
+
+// WithMetricsSink sets where episode metrics and environment events are published
+func (c *HTTPServerConfig) WithMetricsSink(sink core.MetricsSink) *HTTPServerConfig {
+	c.MetricsSink = sink
+	return c
+}
+
+// WithHooks sets the lifecycle hooks fired for the server's environments
+func (c *HTTPServerConfig) WithHooks(hooks *core.Hooks) *HTTPServerConfig {
+	c.Hooks = hooks
+	return c
+}
+
+// WithLogger sets the logger receiving the server's log output
+func (c *HTTPServerConfig) WithLogger(logger Logger) *HTTPServerConfig {
+	c.Logger = logger
+	return c
+}
+
+// WithRunStore sets the store that records runs and episodes
+func (c *HTTPServerConfig) WithRunStore(store *runstore.Store) *HTTPServerConfig {
+	c.RunStore = store
+	return c
+}
+
+// WithOutputDir sets the server directory that client output paths are resolved under
+func (c *HTTPServerConfig) WithOutputDir(dir string) *HTTPServerConfig {
+	c.OutputDir = dir
+	return c
+}
+
+// WithGymnasiumAPI sets whether environments report terminated and truncated flags by default
+func (c *HTTPServerConfig) WithGymnasiumAPI(enabled bool) *HTTPServerConfig {
+	c.GymnasiumAPI = enabled
+	return c
+}
+
+// WithLimits sets the resource limits checked when creating environments
+func (c *HTTPServerConfig) WithLimits(limits server.Limits) *HTTPServerConfig {
+	c.Limits = limits
+	return c
+}
+
+// WithAdminToken sets the token required by the admin API
+func (c *HTTPServerConfig) WithAdminToken(token string) *HTTPServerConfig {
+	c.AdminToken = token
+	return c
+}
+
+// WithTenants sets the tenants sharing the server
+func (c *HTTPServerConfig) WithTenants(tenants []server.Tenant) *HTTPServerConfig {
+	c.Tenants = tenants
+	return c
+}
+
+// WithWasmScenarios lets clients upload WebAssembly scenarios sandboxed by limits; the
+// server then requires an admin token
+func (c *HTTPServerConfig) WithWasmScenarios(limits wasm.Limits) *HTTPServerConfig {
+	c.WasmScenarios = &limits
+	return c
+}
+
+// WithScenarios restricts the scenarios and presets clients may create
+func (c *HTTPServerConfig) WithScenarios(names ...string) *HTTPServerConfig {
+	c.Scenarios = names
+	return c
+}
+
+// WithSessionTTL sets the default and maximum idle timeout of client sessions
+func (c *HTTPServerConfig) WithSessionTTL(def, max time.Duration) *HTTPServerConfig {
+	c.SessionTTL = def
+	c.MaxSessionTTL = max
+	return c
+}
+
+// WithTLS serves over TLS with the PEM certificate and key files
+func (c *HTTPServerConfig) WithTLS(certFile, keyFile string) *HTTPServerConfig {
+	c.TLSCertFile = certFile
+	c.TLSKeyFile = keyFile
+	return c
+}
+
+// WithSnapshot sets where and how often active environments are saved for restoring on startup
+func (c *HTTPServerConfig) WithSnapshot(path string, interval time.Duration) *HTTPServerConfig {
+	c.SnapshotPath = path
+	c.SnapshotInterval = interval
+	return c
+}
+
+// WithDebugPort serves pprof and expvar on a separate port
+func (c *HTTPServerConfig) WithDebugPort(port int) *HTTPServerConfig {
+	c.DebugPort = port
+	return c
+}
+
+// WithDebugHost sets the host of the debug port; hosts other than loopback ones require an admin token
+func (c *HTTPServerConfig) WithDebugHost(host string) *HTTPServerConfig {
+	c.DebugHost = host
+	return c
+}
+
+// isLoopbackHost reports whether host only accepts connections from this machine
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// Address returns the full address string, or the unix:// address of a Unix socket
 func (c *HTTPServerConfig) Address() string {
+	if server.IsUnixAddress(c.Host) {
+		return c.Host
+	}
 	return fmt.Sprintf("%s:%d", c.Host, c.Port)
 }
@@ -0,0 +1,65 @@
+package rl_env_engine
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// Logger is the structured logger servers write to: leveled methods taking a message and
+// alternating key/value fields. *slog.Logger implements it, and adapters for other logging
+// stacks only need these four methods
+type Logger = core.Logger
+
+// SetLogger sends the log output of servers started without their own Logger to logger;
+// nil restores the default, which follows slog.Default()
+func SetLogger(logger Logger) {
+	core.SetDefaultLogger(logger)
+}
+
+// SetLogLevel sets the minimum level (debug, info, warn or error) of the process-wide logger.
+// Plain log.Printf messages are logged at info, so "warn" keeps only warnings and failures
+func SetLogLevel(level string) error {
+	return ConfigureLogging(level, "")
+}
+
+// ConfigureLogging replaces the process-wide slog logger with one writing to stderr at level
+// (debug, info, warn or error; info when empty) in format (text or json; text when empty).
+// It has no effect on servers given their own Logger or after SetLogger
+func ConfigureLogging(level, format string) error {
+	handler, err := newLogHandler(os.Stderr, level, format)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(slog.New(handler))
+	return nil
+}
+
+// newLogHandler creates the slog handler of ConfigureLogging
+func newLogHandler(w io.Writer, level, format string) (slog.Handler, error) {
+	var lvl slog.Level
+	if level != "" {
+		if err := lvl.UnmarshalText([]byte(level)); err != nil {
+			return nil, fmt.Errorf("invalid log level %q (expected debug, info, warn or error)", level)
+		}
+	}
+	options := &slog.HandlerOptions{Level: lvl}
+	switch format {
+	case "", "text":
+		return slog.NewTextHandler(w, options), nil
+	case "json":
+		return slog.NewJSONHandler(w, options), nil
+	}
+	return nil, fmt.Errorf("invalid log format %q (expected text or json)", format)
+}
+
+// loggerOr returns logger, or the process-wide default when it is nil
+func loggerOr(logger Logger) Logger {
+	if logger != nil {
+		return logger
+	}
+	return core.DefaultLogger()
+}
@@ -0,0 +1,151 @@
+package rl_env_engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// ParallelOptions configures RunParallel
+type ParallelOptions struct {
+	// Episodes is the total number of episodes to run, 1 when <= 0
+	Episodes int
+	// MaxSteps caps the steps of each episode, policy.DefaultMaxSteps when <= 0
+	MaxSteps int
+	// Workers is the number of goroutines, runtime.GOMAXPROCS(0) when <= 0, and never
+	// more than Episodes
+	Workers int
+	// Wrappers are applied to the simulation of every worker, e.g. TimeLimit
+	Wrappers []Wrapper
+	// OnEpisode, when set, is called after every episode, from one worker at a time and in
+	// completion order; returning an error stops the run
+	OnEpisode func(episode EpisodeResult) error
+}
+
+// RunParallel runs opts.Episodes episodes of the scenario or preset across opts.Workers
+// goroutines so that large evaluations use every core. Each worker has its own simulation,
+// created from config with a non-zero seed offset by the worker index like
+// NewVectorSimulation, and its own policy from newPolicy. Worker w runs a fixed, contiguous
+// block of episodes and the results are merged in episode order, so with a fixed seed and
+// number of workers the result does not depend on scheduling. When a worker fails the
+// others are cancelled and the episodes completed so far are returned along with its error
+func RunParallel(ctx context.Context, scenario string, config map[string]interface{},
+	newPolicy func(sim Simulation, worker int) (Policy, error), opts ParallelOptions) (*RunResult, error) {
+	engine, err := newBuiltinEngine()
+	if err != nil {
+		return nil, err
+	}
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	var mu sync.Mutex
+	results := make([]*RunResult, workers)
+	start := time.Now()
+	err = runWorkers(ctx, engine, scenario, config, opts.Wrappers, workers, opts.Episodes,
+		func(ctx context.Context, worker int, sim Simulation, first, count int) error {
+			p, err := newPolicy(sim, worker)
+			if err != nil {
+				return err
+			}
+			result, err := RunPolicy(ctx, sim, p, RunOptions{
+				Episodes: count,
+				MaxSteps: opts.MaxSteps,
+				OnEpisode: func(episode EpisodeResult) error {
+					if opts.OnEpisode == nil {
+						return nil
+					}
+					episode.Episode += first
+					mu.Lock()
+					defer mu.Unlock()
+					return opts.OnEpisode(episode)
+				},
+			})
+			if result != nil {
+				for i := range result.Episodes {
+					result.Episodes[i].Episode += first
+				}
+			}
+			results[worker] = result
+			return err
+		})
+
+	merged := &RunResult{}
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+		merged.Episodes = append(merged.Episodes, result.Episodes...)
+		merged.Steps += result.Steps
+	}
+	merged.Elapsed = time.Since(start)
+	return merged, err
+}
+
+// runWorkers splits episodes into contiguous blocks, one per worker, and calls run for each
+// block on its own goroutine with the index of its first episode and its own simulation,
+// which is closed afterwards. The simulation of worker w is created from config with the
+// seed offset by w (see instanceConfig). The first failure cancels the other workers and is
+// returned rather than the cancellations it caused
+func runWorkers(ctx context.Context, engine *core.SimulationEngine, scenario string, config map[string]interface{},
+	wrappers []Wrapper, workers, episodes int, run func(ctx context.Context, worker int, sim Simulation, first, count int) error) error {
+	if episodes <= 0 {
+		episodes = 1
+	}
+	if workers <= 0 {
+		workers = 1
+	}
+	if workers > episodes {
+		workers = episodes
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	first := 0
+	for w := 0; w < workers; w++ {
+		count := episodes / workers
+		if w < episodes%workers {
+			count++
+		}
+		wg.Add(1)
+		go func(worker, first, count int) {
+			defer wg.Done()
+			sim, err := New().Engine(engine).Scenario(scenario).Config(instanceConfig(config, worker)).Wrap(wrappers...).Build()
+			if err != nil {
+				errs[worker] = fmt.Errorf("worker %d: %w", worker, err)
+				cancel()
+				return
+			}
+			defer sim.Close()
+			if err := run(ctx, worker, sim, first, count); err != nil {
+				errs[worker] = fmt.Errorf("worker %d: %w", worker, err)
+				cancel()
+			}
+		}(w, first, count)
+		first += count
+	}
+	wg.Wait()
+
+	// a failing worker cancels the others, so report its error over the cancellations
+	var canceled error
+	for _, err := range errs {
+		switch {
+		case err == nil:
+		case errors.Is(err, context.Canceled):
+			if canceled == nil {
+				canceled = err
+			}
+		default:
+			return err
+		}
+	}
+	return canceled
+}
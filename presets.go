@@ -0,0 +1,195 @@
+package rl_env_engine
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// Preset is a named scenario configuration that clients can create by name
+type Preset = core.Preset
+
+// defaultPresets holds the built-in presets and those added with RegisterPreset
+var (
+	defaultPresetsMu sync.Mutex
+	defaultPresets   = []Preset{
+		{Name: "cartpole-long", Scenario: "cartpole", Config: map[string]interface{}{"max_steps": 2000}},
+		{Name: "simple-strict", Scenario: "simple", Config: map[string]interface{}{"tolerance": 0.01}},
+	}
+)
+
+// RegisterPreset adds a preset to the default registry consulted by NewSimulation,
+// StartHTTPServer and StartGrpcServer, so clients can create it by name:
+//
+//	simulations.RegisterPreset(simulations.Preset{
+//		Name:     "cartpole-short",
+//		Scenario: "cartpole",
+//		Config:   map[string]interface{}{"max_steps": 50},
+//	})
+//
+// Its config is validated when it is installed into an engine.
+func RegisterPreset(preset Preset) error {
+	if preset.Name == "" || preset.Scenario == "" {
+		return fmt.Errorf("preset name and scenario are required")
+	}
+	defaultPresetsMu.Lock()
+	defer defaultPresetsMu.Unlock()
+	for _, p := range defaultPresets {
+		if p.Name == preset.Name {
+			return fmt.Errorf("preset %q is already registered", preset.Name)
+		}
+	}
+	defaultPresets = append(defaultPresets, preset)
+	return nil
+}
+
+// InstallPresets registers the default presets with the engine. Presets whose scenario
+// the engine does not provide are skipped; an invalid config is an error.
+func InstallPresets(engine *core.SimulationEngine) error {
+	defaultPresetsMu.Lock()
+	presets := append([]Preset(nil), defaultPresets...)
+	defaultPresetsMu.Unlock()
+
+	for _, preset := range presets {
+		if _, err := engine.GetScenario(preset.Scenario); err != nil {
+			continue
+		}
+		if err := engine.RegisterPreset(preset); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DefaultPresetReloadInterval is how often a watched preset directory is checked for changes
+const DefaultPresetReloadInterval = 2 * time.Second
+
+// presetFiles lists the simulation files of a preset directory in name order
+func presetFiles(dir string) ([]os.DirEntry, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read preset directory: %w", err)
+	}
+	files := entries[:0]
+	for _, entry := range entries {
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".yaml", ".yml", ".json":
+			if !entry.IsDir() {
+				files = append(files, entry)
+			}
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Name() < files[j].Name() })
+	return files, nil
+}
+
+// LoadPresetDir loads every YAML/JSON simulation file in dir as a preset named after the file,
+// e.g. presets/cartpole-long.yaml becomes the preset "cartpole-long"
+func LoadPresetDir(dir string) ([]Preset, error) {
+	files, err := presetFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	presets := make([]Preset, 0, len(files))
+	for _, entry := range files {
+		file, err := LoadSimulationFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		presets = append(presets, Preset{
+			Name:     strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name())),
+			Scenario: file.Scenario,
+			Config:   file.Config,
+		})
+	}
+	return presets, nil
+}
+
+// presetDirFingerprint summarizes names, sizes and modification times so changes can be detected cheaply
+func presetDirFingerprint(dir string) (string, error) {
+	files, err := presetFiles(dir)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	for _, entry := range files {
+		info, err := entry.Info()
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&sb, "%s:%d:%d;", entry.Name(), info.Size(), info.ModTime().UnixNano())
+	}
+	return sb.String(), nil
+}
+
+// WatchPresetDir loads the presets in dir into the engine and reloads them whenever a file
+// is added, changed or removed. The initial load must succeed; later invalid edits are logged
+// and the previously loaded presets stay active. Environments that already exist are not affected.
+// The returned function stops watching.
+func WatchPresetDir(engine *core.SimulationEngine, dir string, interval time.Duration) (stop func(), err error) {
+	return watchPresetDir(engine, dir, interval, nil)
+}
+
+// watchPresetDir is WatchPresetDir logging to logger, or the default logger when it is nil
+func watchPresetDir(engine *core.SimulationEngine, dir string, interval time.Duration, logger Logger) (stop func(), err error) {
+	logger = loggerOr(logger)
+	if interval <= 0 {
+		interval = DefaultPresetReloadInterval
+	}
+
+	fingerprint, err := presetDirFingerprint(dir)
+	if err != nil {
+		return nil, err
+	}
+	presets, err := LoadPresetDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := engine.SetPresets(presets); err != nil {
+		return nil, fmt.Errorf("failed to load presets from %s: %w", dir, err)
+	}
+	logger.Info("Loaded presets", "presets", len(presets), "dir", dir)
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+
+			current, err := presetDirFingerprint(dir)
+			if err != nil {
+				logger.Warn("Failed to check preset directory", "dir", dir, "error", err)
+				continue
+			}
+			if current == fingerprint {
+				continue
+			}
+			// Remember the failed state too, so a broken file is reported once rather than on every tick
+			fingerprint = current
+
+			presets, err := LoadPresetDir(dir)
+			if err == nil {
+				err = engine.SetPresets(presets)
+			}
+			if err != nil {
+				logger.Warn("Keeping previous presets, reload failed", "dir", dir, "error", err)
+				continue
+			}
+			logger.Info("Reloaded presets", "presets", len(presets), "dir", dir)
+		}
+	}()
+
+	var once sync.Once
+	return func() { once.Do(func() { close(done) }) }, nil
+}
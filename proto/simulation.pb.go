@@ -31,6 +31,10 @@ const (
 	SpaceType_MULTI_DISCRETE SpaceType = 2 // 多离散空间 - shape=[groups], high=[n1-1,n2-1,...]每组动作数
 	SpaceType_MULTI_BINARY   SpaceType = 3 // 多二进制空间 - shape=[bits], low/high全为[0]/[1]
 	SpaceType_DISCRETE_FLOAT SpaceType = 4 // 离散浮点空间 - 预定义的浮点值列表，使用discrete_values字段
+	SpaceType_TEXT           SpaceType = 5 // 文本空间 (gym.spaces.Text) - max_length为最大字符数，charset为允许的字符
+	SpaceType_IMAGE          SpaceType = 6 // 图像空间 - shape=[height, width, channels]，uint8像素在Observation.image中返回
+	SpaceType_DICT           SpaceType = 7 // 复合空间 (gym.spaces.Dict) - 命名子空间在ActionSpace.spaces中
+	SpaceType_HYBRID         SpaceType = 8 // 参数化空间 - 选择一个离散动作并给出其连续参数，参数空间在ActionSpace.parameters中
 )
 
 // Enum value maps for SpaceType.
@@ -41,6 +45,10 @@ var (
 		2: "MULTI_DISCRETE",
 		3: "MULTI_BINARY",
 		4: "DISCRETE_FLOAT",
+		5: "TEXT",
+		6: "IMAGE",
+		7: "DICT",
+		8: "HYBRID",
 	}
 	SpaceType_value = map[string]int32{
 		"BOX":            0,
@@ -48,6 +56,10 @@ var (
 		"MULTI_DISCRETE": 2,
 		"MULTI_BINARY":   3,
 		"DISCRETE_FLOAT": 4,
+		"TEXT":           5,
+		"IMAGE":          6,
+		"DICT":           7,
+		"HYBRID":         8,
 	}
 )
 
@@ -78,6 +90,56 @@ func (SpaceType) EnumDescriptor() ([]byte, []int) {
 	return file_proto_simulation_proto_rawDescGZIP(), []int{0}
 }
 
+// dm_env的时间步类型，Reset的结果总是FIRST
+type StepType int32
+
+const (
+	StepType_FIRST StepType = 0
+	StepType_MID   StepType = 1
+	StepType_LAST  StepType = 2
+)
+
+// Enum value maps for StepType.
+var (
+	StepType_name = map[int32]string{
+		0: "FIRST",
+		1: "MID",
+		2: "LAST",
+	}
+	StepType_value = map[string]int32{
+		"FIRST": 0,
+		"MID":   1,
+		"LAST":  2,
+	}
+)
+
+func (x StepType) Enum() *StepType {
+	p := new(StepType)
+	*p = x
+	return p
+}
+
+func (x StepType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (StepType) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_simulation_proto_enumTypes[1].Descriptor()
+}
+
+func (StepType) Type() protoreflect.EnumType {
+	return &file_proto_simulation_proto_enumTypes[1]
+}
+
+func (x StepType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use StepType.Descriptor instead.
+func (StepType) EnumDescriptor() ([]byte, []int) {
+	return file_proto_simulation_proto_rawDescGZIP(), []int{1}
+}
+
 // 基础消息类型
 type GetInfoRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -116,12 +178,20 @@ func (*GetInfoRequest) Descriptor() ([]byte, []int) {
 }
 
 type GetInfoResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Scenarios     []string               `protobuf:"bytes,1,rep,name=scenarios,proto3" json:"scenarios,omitempty"`
-	EnvIds        []string               `protobuf:"bytes,2,rep,name=env_ids,json=envIds,proto3" json:"env_ids,omitempty"`
-	Info          *structpb.Struct       `protobuf:"bytes,3,opt,name=info,proto3" json:"info,omitempty"`
-	Version       string                 `protobuf:"bytes,4,opt,name=version,proto3" json:"version,omitempty"`
-	Name          string                 `protobuf:"bytes,5,opt,name=name,proto3" json:"name,omitempty"`
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Scenarios []string               `protobuf:"bytes,1,rep,name=scenarios,proto3" json:"scenarios,omitempty"`
+	EnvIds    []string               `protobuf:"bytes,2,rep,name=env_ids,json=envIds,proto3" json:"env_ids,omitempty"`
+	Info      *structpb.Struct       `protobuf:"bytes,3,opt,name=info,proto3" json:"info,omitempty"`
+	Version   string                 `protobuf:"bytes,4,opt,name=version,proto3" json:"version,omitempty"`
+	Name      string                 `protobuf:"bytes,5,opt,name=name,proto3" json:"name,omitempty"`
+	// 以场景为键的步进延迟与吞吐，只包含调用方可创建且已步进过的场景
+	StepLatency map[string]*ScenarioLatency `protobuf:"bytes,6,rep,name=step_latency,json=stepLatency,proto3" json:"step_latency,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// info中的标量字段（计数、名称等）以带类型的Value重复返回，整数不再变成double；
+	// info仍保留全部字段以兼容旧客户端
+	TypedInfo map[string]*Value `protobuf:"bytes,7,rep,name=typed_info,json=typedInfo,proto3" json:"typed_info,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// 服务端实现的协议版本与仍支持的最低版本：1为只返回done的四元组语义，2起步进响应返回terminated与truncated
+	ApiVersion    int32 `protobuf:"varint,8,opt,name=api_version,json=apiVersion,proto3" json:"api_version,omitempty"`
+	MinApiVersion int32 `protobuf:"varint,9,opt,name=min_api_version,json=minApiVersion,proto3" json:"min_api_version,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -191,18 +261,196 @@ func (x *GetInfoResponse) GetName() string {
 	return ""
 }
 
-type CreateEnvironmentRequest struct {
+func (x *GetInfoResponse) GetStepLatency() map[string]*ScenarioLatency {
+	if x != nil {
+		return x.StepLatency
+	}
+	return nil
+}
+
+func (x *GetInfoResponse) GetTypedInfo() map[string]*Value {
+	if x != nil {
+		return x.TypedInfo
+	}
+	return nil
+}
+
+func (x *GetInfoResponse) GetApiVersion() int32 {
+	if x != nil {
+		return x.ApiVersion
+	}
+	return 0
+}
+
+func (x *GetInfoResponse) GetMinApiVersion() int32 {
+	if x != nil {
+		return x.MinApiVersion
+	}
+	return 0
+}
+
+// ScenarioLatency 一个场景的步进延迟：step为环境Step本身的耗时，
+// request为服务端处理一次步进请求的耗时（解码动作、步进与编码结果，不含网络传输）
+type ScenarioLatency struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	EnvId         string                 `protobuf:"bytes,1,opt,name=env_id,json=envId,proto3" json:"env_id,omitempty"`
-	Scenario      string                 `protobuf:"bytes,2,opt,name=scenario,proto3" json:"scenario,omitempty"`
-	Config        *structpb.Struct       `protobuf:"bytes,3,opt,name=config,proto3" json:"config,omitempty"`
+	Step          *LatencySummary        `protobuf:"bytes,1,opt,name=step,proto3" json:"step,omitempty"`
+	Request       *LatencySummary        `protobuf:"bytes,2,opt,name=request,proto3" json:"request,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScenarioLatency) Reset() {
+	*x = ScenarioLatency{}
+	mi := &file_proto_simulation_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScenarioLatency) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScenarioLatency) ProtoMessage() {}
+
+func (x *ScenarioLatency) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simulation_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScenarioLatency.ProtoReflect.Descriptor instead.
+func (*ScenarioLatency) Descriptor() ([]byte, []int) {
+	return file_proto_simulation_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ScenarioLatency) GetStep() *LatencySummary {
+	if x != nil {
+		return x.Step
+	}
+	return nil
+}
+
+func (x *ScenarioLatency) GetRequest() *LatencySummary {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+// LatencySummary 延迟直方图的摘要，耗时以毫秒表示
+type LatencySummary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Count         int64                  `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	MeanMs        float64                `protobuf:"fixed64,2,opt,name=mean_ms,json=meanMs,proto3" json:"mean_ms,omitempty"`
+	P50Ms         float64                `protobuf:"fixed64,3,opt,name=p50_ms,json=p50Ms,proto3" json:"p50_ms,omitempty"`
+	P95Ms         float64                `protobuf:"fixed64,4,opt,name=p95_ms,json=p95Ms,proto3" json:"p95_ms,omitempty"`
+	P99Ms         float64                `protobuf:"fixed64,5,opt,name=p99_ms,json=p99Ms,proto3" json:"p99_ms,omitempty"`
+	MaxMs         float64                `protobuf:"fixed64,6,opt,name=max_ms,json=maxMs,proto3" json:"max_ms,omitempty"`
+	PerSecond     float64                `protobuf:"fixed64,7,opt,name=per_second,json=perSecond,proto3" json:"per_second,omitempty"` // 最近一分钟内每秒的平均步数
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LatencySummary) Reset() {
+	*x = LatencySummary{}
+	mi := &file_proto_simulation_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LatencySummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LatencySummary) ProtoMessage() {}
+
+func (x *LatencySummary) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simulation_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LatencySummary.ProtoReflect.Descriptor instead.
+func (*LatencySummary) Descriptor() ([]byte, []int) {
+	return file_proto_simulation_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *LatencySummary) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *LatencySummary) GetMeanMs() float64 {
+	if x != nil {
+		return x.MeanMs
+	}
+	return 0
+}
+
+func (x *LatencySummary) GetP50Ms() float64 {
+	if x != nil {
+		return x.P50Ms
+	}
+	return 0
+}
+
+func (x *LatencySummary) GetP95Ms() float64 {
+	if x != nil {
+		return x.P95Ms
+	}
+	return 0
+}
+
+func (x *LatencySummary) GetP99Ms() float64 {
+	if x != nil {
+		return x.P99Ms
+	}
+	return 0
+}
+
+func (x *LatencySummary) GetMaxMs() float64 {
+	if x != nil {
+		return x.MaxMs
+	}
+	return 0
+}
+
+func (x *LatencySummary) GetPerSecond() float64 {
+	if x != nil {
+		return x.PerSecond
+	}
+	return 0
+}
+
+type CreateEnvironmentRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	EnvId    string                 `protobuf:"bytes,1,opt,name=env_id,json=envId,proto3" json:"env_id,omitempty"`
+	Scenario string                 `protobuf:"bytes,2,opt,name=scenario,proto3" json:"scenario,omitempty"`
+	Config   *structpb.Struct       `protobuf:"bytes,3,opt,name=config,proto3" json:"config,omitempty"`
+	// 客户端期望的协议版本（见GetInfoResponse.api_version），0表示未指定。
+	// 版本1关闭gymnasium_api、版本2开启；服务端不支持该版本或config中的gymnasium_api与之冲突时拒绝创建
+	ApiVersion    int32 `protobuf:"varint,4,opt,name=api_version,json=apiVersion,proto3" json:"api_version,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CreateEnvironmentRequest) Reset() {
 	*x = CreateEnvironmentRequest{}
-	mi := &file_proto_simulation_proto_msgTypes[2]
+	mi := &file_proto_simulation_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -214,7 +462,7 @@ func (x *CreateEnvironmentRequest) String() string {
 func (*CreateEnvironmentRequest) ProtoMessage() {}
 
 func (x *CreateEnvironmentRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_simulation_proto_msgTypes[2]
+	mi := &file_proto_simulation_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -227,7 +475,7 @@ func (x *CreateEnvironmentRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateEnvironmentRequest.ProtoReflect.Descriptor instead.
 func (*CreateEnvironmentRequest) Descriptor() ([]byte, []int) {
-	return file_proto_simulation_proto_rawDescGZIP(), []int{2}
+	return file_proto_simulation_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *CreateEnvironmentRequest) GetEnvId() string {
@@ -251,17 +499,25 @@ func (x *CreateEnvironmentRequest) GetConfig() *structpb.Struct {
 	return nil
 }
 
+func (x *CreateEnvironmentRequest) GetApiVersion() int32 {
+	if x != nil {
+		return x.ApiVersion
+	}
+	return 0
+}
+
 type CreateEnvironmentResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Error         *Error                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"` // success为false时的错误
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CreateEnvironmentResponse) Reset() {
 	*x = CreateEnvironmentResponse{}
-	mi := &file_proto_simulation_proto_msgTypes[3]
+	mi := &file_proto_simulation_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -273,7 +529,7 @@ func (x *CreateEnvironmentResponse) String() string {
 func (*CreateEnvironmentResponse) ProtoMessage() {}
 
 func (x *CreateEnvironmentResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_simulation_proto_msgTypes[3]
+	mi := &file_proto_simulation_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -286,7 +542,7 @@ func (x *CreateEnvironmentResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateEnvironmentResponse.ProtoReflect.Descriptor instead.
 func (*CreateEnvironmentResponse) Descriptor() ([]byte, []int) {
-	return file_proto_simulation_proto_rawDescGZIP(), []int{3}
+	return file_proto_simulation_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *CreateEnvironmentResponse) GetSuccess() bool {
@@ -303,6 +559,76 @@ func (x *CreateEnvironmentResponse) GetMessage() string {
 	return ""
 }
 
+func (x *CreateEnvironmentResponse) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+// Error 两种传输共用的错误模型：code为规范错误码（与gRPC状态码同名，如NOT_FOUND、INVALID_ARGUMENT），
+// message为可读描述，details为附加的键值（如env_id）。失败的gRPC调用在状态详情中携带一个Error，
+// 带success字段的响应在失败时填充error；HTTP错误响应的status、message与details与之一一对应
+type Error struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Details       map[string]string      `protobuf:"bytes,3,rep,name=details,proto3" json:"details,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Error) Reset() {
+	*x = Error{}
+	mi := &file_proto_simulation_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Error) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Error) ProtoMessage() {}
+
+func (x *Error) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simulation_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Error.ProtoReflect.Descriptor instead.
+func (*Error) Descriptor() ([]byte, []int) {
+	return file_proto_simulation_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Error) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Error) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Error) GetDetails() map[string]string {
+	if x != nil {
+		return x.Details
+	}
+	return nil
+}
+
 type ResetEnvironmentRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	EnvId         string                 `protobuf:"bytes,1,opt,name=env_id,json=envId,proto3" json:"env_id,omitempty"`
@@ -312,7 +638,7 @@ type ResetEnvironmentRequest struct {
 
 func (x *ResetEnvironmentRequest) Reset() {
 	*x = ResetEnvironmentRequest{}
-	mi := &file_proto_simulation_proto_msgTypes[4]
+	mi := &file_proto_simulation_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -324,7 +650,7 @@ func (x *ResetEnvironmentRequest) String() string {
 func (*ResetEnvironmentRequest) ProtoMessage() {}
 
 func (x *ResetEnvironmentRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_simulation_proto_msgTypes[4]
+	mi := &file_proto_simulation_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -337,7 +663,7 @@ func (x *ResetEnvironmentRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ResetEnvironmentRequest.ProtoReflect.Descriptor instead.
 func (*ResetEnvironmentRequest) Descriptor() ([]byte, []int) {
-	return file_proto_simulation_proto_rawDescGZIP(), []int{4}
+	return file_proto_simulation_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *ResetEnvironmentRequest) GetEnvId() string {
@@ -348,16 +674,20 @@ func (x *ResetEnvironmentRequest) GetEnvId() string {
 }
 
 type ResetEnvironmentResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Observations  []*Observation         `protobuf:"bytes,1,rep,name=observations,proto3" json:"observations,omitempty"`
-	Info          *structpb.Struct       `protobuf:"bytes,2,opt,name=info,proto3" json:"info,omitempty"`
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Observations []*Observation         `protobuf:"bytes,1,rep,name=observations,proto3" json:"observations,omitempty"`
+	Info         *structpb.Struct       `protobuf:"bytes,2,opt,name=info,proto3" json:"info,omitempty"`
+	TypedInfo    map[string]*Value      `protobuf:"bytes,3,rep,name=typed_info,json=typedInfo,proto3" json:"typed_info,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // 创建时设置typed_values的环境在此返回标量信息，info只保留列表等复合值
+	// 开启agent_dict的环境以智能体名称为键返回各智能体的结果，observations留空
+	Agents        map[string]*AgentStep `protobuf:"bytes,4,rep,name=agents,proto3" json:"agents,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	AgentIds      []string              `protobuf:"bytes,5,rep,name=agent_ids,json=agentIds,proto3" json:"agent_ids,omitempty"` // observations中各观察所属智能体的名称，开启agent_dict时留空
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ResetEnvironmentResponse) Reset() {
 	*x = ResetEnvironmentResponse{}
-	mi := &file_proto_simulation_proto_msgTypes[5]
+	mi := &file_proto_simulation_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -369,7 +699,7 @@ func (x *ResetEnvironmentResponse) String() string {
 func (*ResetEnvironmentResponse) ProtoMessage() {}
 
 func (x *ResetEnvironmentResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_simulation_proto_msgTypes[5]
+	mi := &file_proto_simulation_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -382,7 +712,7 @@ func (x *ResetEnvironmentResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ResetEnvironmentResponse.ProtoReflect.Descriptor instead.
 func (*ResetEnvironmentResponse) Descriptor() ([]byte, []int) {
-	return file_proto_simulation_proto_rawDescGZIP(), []int{5}
+	return file_proto_simulation_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *ResetEnvironmentResponse) GetObservations() []*Observation {
@@ -399,6 +729,27 @@ func (x *ResetEnvironmentResponse) GetInfo() *structpb.Struct {
 	return nil
 }
 
+func (x *ResetEnvironmentResponse) GetTypedInfo() map[string]*Value {
+	if x != nil {
+		return x.TypedInfo
+	}
+	return nil
+}
+
+func (x *ResetEnvironmentResponse) GetAgents() map[string]*AgentStep {
+	if x != nil {
+		return x.Agents
+	}
+	return nil
+}
+
+func (x *ResetEnvironmentResponse) GetAgentIds() []string {
+	if x != nil {
+		return x.AgentIds
+	}
+	return nil
+}
+
 type StepEnvironmentRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	EnvId         string                 `protobuf:"bytes,1,opt,name=env_id,json=envId,proto3" json:"env_id,omitempty"`
@@ -409,7 +760,7 @@ type StepEnvironmentRequest struct {
 
 func (x *StepEnvironmentRequest) Reset() {
 	*x = StepEnvironmentRequest{}
-	mi := &file_proto_simulation_proto_msgTypes[6]
+	mi := &file_proto_simulation_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -421,7 +772,7 @@ func (x *StepEnvironmentRequest) String() string {
 func (*StepEnvironmentRequest) ProtoMessage() {}
 
 func (x *StepEnvironmentRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_simulation_proto_msgTypes[6]
+	mi := &file_proto_simulation_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -434,7 +785,7 @@ func (x *StepEnvironmentRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StepEnvironmentRequest.ProtoReflect.Descriptor instead.
 func (*StepEnvironmentRequest) Descriptor() ([]byte, []int) {
-	return file_proto_simulation_proto_rawDescGZIP(), []int{6}
+	return file_proto_simulation_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *StepEnvironmentRequest) GetEnvId() string {
@@ -452,18 +803,35 @@ func (x *StepEnvironmentRequest) GetActions() []*Action {
 }
 
 type StepEnvironmentResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Observations  []*Observation         `protobuf:"bytes,1,rep,name=observations,proto3" json:"observations,omitempty"`
-	Rewards       []float64              `protobuf:"fixed64,2,rep,packed,name=rewards,proto3" json:"rewards,omitempty"`
-	Done          []bool                 `protobuf:"varint,3,rep,packed,name=done,proto3" json:"done,omitempty"`
-	Info          *structpb.Struct       `protobuf:"bytes,4,opt,name=info,proto3" json:"info,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Observations []*Observation         `protobuf:"bytes,1,rep,name=observations,proto3" json:"observations,omitempty"`
+	Rewards      []float64              `protobuf:"fixed64,2,rep,packed,name=rewards,proto3" json:"rewards,omitempty"`
+	Done         []bool                 `protobuf:"varint,3,rep,packed,name=done,proto3" json:"done,omitempty"`
+	Info         *structpb.Struct       `protobuf:"bytes,4,opt,name=info,proto3" json:"info,omitempty"`
+	TypedInfo    map[string]*Value      `protobuf:"bytes,5,rep,name=typed_info,json=typedInfo,proto3" json:"typed_info,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // 同ResetEnvironmentResponse.typed_info
+	// 开启gymnasium_api的环境填充以下两个字段（Gymnasium ≥0.26语义），done为两者之或
+	Terminated []bool `protobuf:"varint,6,rep,packed,name=terminated,proto3" json:"terminated,omitempty"`
+	Truncated  []bool `protobuf:"varint,7,rep,packed,name=truncated,proto3" json:"truncated,omitempty"`
+	// 开启dm_env的环境填充以下两个字段：各智能体的时间步类型（MID或LAST）与折扣，
+	// 终止时折扣为0，截断时为1
+	StepType []StepType `protobuf:"varint,8,rep,packed,name=step_type,json=stepType,proto3,enum=simulation.StepType" json:"step_type,omitempty"`
+	Discount []float64  `protobuf:"fixed64,9,rep,packed,name=discount,proto3" json:"discount,omitempty"`
+	// 开启agent_dict的环境以智能体名称为键返回各智能体的结果，
+	// observations、rewards、done、terminated与truncated留空
+	Agents map[string]*AgentStep `protobuf:"bytes,10,rep,name=agents,proto3" json:"agents,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// observations、rewards、done、terminated、truncated、step_type与discount中下标i对应的智能体名称，
+	// 开启agent_dict时留空
+	AgentIds []string `protobuf:"bytes,11,rep,name=agent_ids,json=agentIds,proto3" json:"agent_ids,omitempty"`
+	// 开启auto_reset且所有智能体都已结束时，自动重置前各智能体的最后观察（observations已是新回合的初始观察），
+	// 截断时据此自举价值；编码方式与observations相同。info中的terminal_observation保留以兼容旧客户端
+	FinalObservations []*Observation `protobuf:"bytes,12,rep,name=final_observations,json=finalObservations,proto3" json:"final_observations,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
 }
 
 func (x *StepEnvironmentResponse) Reset() {
 	*x = StepEnvironmentResponse{}
-	mi := &file_proto_simulation_proto_msgTypes[7]
+	mi := &file_proto_simulation_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -475,7 +843,7 @@ func (x *StepEnvironmentResponse) String() string {
 func (*StepEnvironmentResponse) ProtoMessage() {}
 
 func (x *StepEnvironmentResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_simulation_proto_msgTypes[7]
+	mi := &file_proto_simulation_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -488,7 +856,7 @@ func (x *StepEnvironmentResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StepEnvironmentResponse.ProtoReflect.Descriptor instead.
 func (*StepEnvironmentResponse) Descriptor() ([]byte, []int) {
-	return file_proto_simulation_proto_rawDescGZIP(), []int{7}
+	return file_proto_simulation_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *StepEnvironmentResponse) GetObservations() []*Observation {
@@ -519,6 +887,131 @@ func (x *StepEnvironmentResponse) GetInfo() *structpb.Struct {
 	return nil
 }
 
+func (x *StepEnvironmentResponse) GetTypedInfo() map[string]*Value {
+	if x != nil {
+		return x.TypedInfo
+	}
+	return nil
+}
+
+func (x *StepEnvironmentResponse) GetTerminated() []bool {
+	if x != nil {
+		return x.Terminated
+	}
+	return nil
+}
+
+func (x *StepEnvironmentResponse) GetTruncated() []bool {
+	if x != nil {
+		return x.Truncated
+	}
+	return nil
+}
+
+func (x *StepEnvironmentResponse) GetStepType() []StepType {
+	if x != nil {
+		return x.StepType
+	}
+	return nil
+}
+
+func (x *StepEnvironmentResponse) GetDiscount() []float64 {
+	if x != nil {
+		return x.Discount
+	}
+	return nil
+}
+
+func (x *StepEnvironmentResponse) GetAgents() map[string]*AgentStep {
+	if x != nil {
+		return x.Agents
+	}
+	return nil
+}
+
+func (x *StepEnvironmentResponse) GetAgentIds() []string {
+	if x != nil {
+		return x.AgentIds
+	}
+	return nil
+}
+
+func (x *StepEnvironmentResponse) GetFinalObservations() []*Observation {
+	if x != nil {
+		return x.FinalObservations
+	}
+	return nil
+}
+
+// AgentStep 一个智能体在一步（或重置）中的结果，观察的元数据即该智能体的信息
+type AgentStep struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Observation   *Observation           `protobuf:"bytes,1,opt,name=observation,proto3" json:"observation,omitempty"`
+	Reward        float64                `protobuf:"fixed64,2,opt,name=reward,proto3" json:"reward,omitempty"`
+	Terminated    bool                   `protobuf:"varint,3,opt,name=terminated,proto3" json:"terminated,omitempty"`
+	Truncated     bool                   `protobuf:"varint,4,opt,name=truncated,proto3" json:"truncated,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AgentStep) Reset() {
+	*x = AgentStep{}
+	mi := &file_proto_simulation_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentStep) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentStep) ProtoMessage() {}
+
+func (x *AgentStep) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simulation_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentStep.ProtoReflect.Descriptor instead.
+func (*AgentStep) Descriptor() ([]byte, []int) {
+	return file_proto_simulation_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *AgentStep) GetObservation() *Observation {
+	if x != nil {
+		return x.Observation
+	}
+	return nil
+}
+
+func (x *AgentStep) GetReward() float64 {
+	if x != nil {
+		return x.Reward
+	}
+	return 0
+}
+
+func (x *AgentStep) GetTerminated() bool {
+	if x != nil {
+		return x.Terminated
+	}
+	return false
+}
+
+func (x *AgentStep) GetTruncated() bool {
+	if x != nil {
+		return x.Truncated
+	}
+	return false
+}
+
 type CloseEnvironmentRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	EnvId         string                 `protobuf:"bytes,1,opt,name=env_id,json=envId,proto3" json:"env_id,omitempty"`
@@ -528,7 +1021,7 @@ type CloseEnvironmentRequest struct {
 
 func (x *CloseEnvironmentRequest) Reset() {
 	*x = CloseEnvironmentRequest{}
-	mi := &file_proto_simulation_proto_msgTypes[8]
+	mi := &file_proto_simulation_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -540,7 +1033,7 @@ func (x *CloseEnvironmentRequest) String() string {
 func (*CloseEnvironmentRequest) ProtoMessage() {}
 
 func (x *CloseEnvironmentRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_simulation_proto_msgTypes[8]
+	mi := &file_proto_simulation_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -553,7 +1046,7 @@ func (x *CloseEnvironmentRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CloseEnvironmentRequest.ProtoReflect.Descriptor instead.
 func (*CloseEnvironmentRequest) Descriptor() ([]byte, []int) {
-	return file_proto_simulation_proto_rawDescGZIP(), []int{8}
+	return file_proto_simulation_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *CloseEnvironmentRequest) GetEnvId() string {
@@ -567,13 +1060,14 @@ type CloseEnvironmentResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Error         *Error                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"` // success为false时的错误
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CloseEnvironmentResponse) Reset() {
 	*x = CloseEnvironmentResponse{}
-	mi := &file_proto_simulation_proto_msgTypes[9]
+	mi := &file_proto_simulation_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -585,7 +1079,7 @@ func (x *CloseEnvironmentResponse) String() string {
 func (*CloseEnvironmentResponse) ProtoMessage() {}
 
 func (x *CloseEnvironmentResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_simulation_proto_msgTypes[9]
+	mi := &file_proto_simulation_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -598,7 +1092,7 @@ func (x *CloseEnvironmentResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CloseEnvironmentResponse.ProtoReflect.Descriptor instead.
 func (*CloseEnvironmentResponse) Descriptor() ([]byte, []int) {
-	return file_proto_simulation_proto_rawDescGZIP(), []int{9}
+	return file_proto_simulation_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *CloseEnvironmentResponse) GetSuccess() bool {
@@ -615,18 +1109,30 @@ func (x *CloseEnvironmentResponse) GetMessage() string {
 	return ""
 }
 
+func (x *CloseEnvironmentResponse) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
 // 数据类型定义
 type Observation struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Data          []float64              `protobuf:"fixed64,1,rep,packed,name=data,proto3" json:"data,omitempty"`
 	Metadata      *structpb.Struct       `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	DataF32       []float32              `protobuf:"fixed32,3,rep,packed,name=data_f32,json=dataF32,proto3" json:"data_f32,omitempty"`                                                                                    // dtype为float32的环境填充此字段，data为空
+	TypedMetadata map[string]*Value      `protobuf:"bytes,4,rep,name=typed_metadata,json=typedMetadata,proto3" json:"typed_metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // 创建时设置typed_values的环境在此返回标量元数据，metadata只保留复合值
+	Text          string                 `protobuf:"bytes,5,opt,name=text,proto3" json:"text,omitempty"`                                                                                                                  // Text观察空间的环境在此返回文本观察，data为空
+	Image         *Image                 `protobuf:"bytes,6,opt,name=image,proto3" json:"image,omitempty"`                                                                                                                // Image观察空间的环境在此返回图像观察，data为空
+	AgentId       string                 `protobuf:"bytes,7,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`                                                                                             // 该观察所属智能体的名称（场景实现AgentNamer时如predator_0，否则为agent_0、agent_1……）
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Observation) Reset() {
 	*x = Observation{}
-	mi := &file_proto_simulation_proto_msgTypes[10]
+	mi := &file_proto_simulation_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -638,7 +1144,7 @@ func (x *Observation) String() string {
 func (*Observation) ProtoMessage() {}
 
 func (x *Observation) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_simulation_proto_msgTypes[10]
+	mi := &file_proto_simulation_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -651,7 +1157,7 @@ func (x *Observation) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Observation.ProtoReflect.Descriptor instead.
 func (*Observation) Descriptor() ([]byte, []int) {
-	return file_proto_simulation_proto_rawDescGZIP(), []int{10}
+	return file_proto_simulation_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *Observation) GetData() []float64 {
@@ -668,40 +1174,67 @@ func (x *Observation) GetMetadata() *structpb.Struct {
 	return nil
 }
 
-type Action struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// 通用的action数据，支持多种类型
-	//
-	// Types that are valid to be assigned to Data:
-	//
-	//	*Action_FloatValue
-	//	*Action_IntValue
-	//	*Action_BoolValue
-	//	*Action_FloatArray
-	//	*Action_IntArray
-	//	*Action_BoolArray
-	//	*Action_StringValue
-	//	*Action_RawData
-	Data          isAction_Data `protobuf_oneof:"data"`
+func (x *Observation) GetDataF32() []float32 {
+	if x != nil {
+		return x.DataF32
+	}
+	return nil
+}
+
+func (x *Observation) GetTypedMetadata() map[string]*Value {
+	if x != nil {
+		return x.TypedMetadata
+	}
+	return nil
+}
+
+func (x *Observation) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *Observation) GetImage() *Image {
+	if x != nil {
+		return x.Image
+	}
+	return nil
+}
+
+func (x *Observation) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+// 图像观察：行优先HWC排列的uint8像素
+type Image struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pixels        []byte                 `protobuf:"bytes,1,opt,name=pixels,proto3" json:"pixels,omitempty"` // 长度为height*width*channels
+	Height        int32                  `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	Width         int32                  `protobuf:"varint,3,opt,name=width,proto3" json:"width,omitempty"`
+	Channels      int32                  `protobuf:"varint,4,opt,name=channels,proto3" json:"channels,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Action) Reset() {
-	*x = Action{}
-	mi := &file_proto_simulation_proto_msgTypes[11]
+func (x *Image) Reset() {
+	*x = Image{}
+	mi := &file_proto_simulation_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Action) String() string {
+func (x *Image) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Action) ProtoMessage() {}
+func (*Image) ProtoMessage() {}
 
-func (x *Action) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_simulation_proto_msgTypes[11]
+func (x *Image) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simulation_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -712,123 +1245,335 @@ func (x *Action) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Action.ProtoReflect.Descriptor instead.
-func (*Action) Descriptor() ([]byte, []int) {
-	return file_proto_simulation_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use Image.ProtoReflect.Descriptor instead.
+func (*Image) Descriptor() ([]byte, []int) {
+	return file_proto_simulation_proto_rawDescGZIP(), []int{15}
 }
 
-func (x *Action) GetData() isAction_Data {
+func (x *Image) GetPixels() []byte {
 	if x != nil {
-		return x.Data
+		return x.Pixels
 	}
 	return nil
 }
 
-func (x *Action) GetFloatValue() float64 {
+func (x *Image) GetHeight() int32 {
 	if x != nil {
-		if x, ok := x.Data.(*Action_FloatValue); ok {
-			return x.FloatValue
-		}
+		return x.Height
 	}
 	return 0
 }
 
-func (x *Action) GetIntValue() int64 {
+func (x *Image) GetWidth() int32 {
 	if x != nil {
-		if x, ok := x.Data.(*Action_IntValue); ok {
-			return x.IntValue
-		}
+		return x.Width
 	}
 	return 0
 }
 
-func (x *Action) GetBoolValue() bool {
+func (x *Image) GetChannels() int32 {
 	if x != nil {
-		if x, ok := x.Data.(*Action_BoolValue); ok {
-			return x.BoolValue
-		}
+		return x.Channels
 	}
-	return false
+	return 0
 }
 
-func (x *Action) GetFloatArray() *FloatArray {
+// 带类型的标量值，整数不会像Struct中的数值那样变为double
+type Value struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Kind:
+	//
+	//	*Value_DoubleValue
+	//	*Value_IntValue
+	//	*Value_BoolValue
+	//	*Value_StringValue
+	Kind          isValue_Kind `protobuf_oneof:"kind"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Value) Reset() {
+	*x = Value{}
+	mi := &file_proto_simulation_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Value) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Value) ProtoMessage() {}
+
+func (x *Value) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simulation_proto_msgTypes[16]
 	if x != nil {
-		if x, ok := x.Data.(*Action_FloatArray); ok {
-			return x.FloatArray
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Value.ProtoReflect.Descriptor instead.
+func (*Value) Descriptor() ([]byte, []int) {
+	return file_proto_simulation_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Value) GetKind() isValue_Kind {
+	if x != nil {
+		return x.Kind
 	}
 	return nil
 }
 
-func (x *Action) GetIntArray() *IntArray {
+func (x *Value) GetDoubleValue() float64 {
 	if x != nil {
-		if x, ok := x.Data.(*Action_IntArray); ok {
-			return x.IntArray
+		if x, ok := x.Kind.(*Value_DoubleValue); ok {
+			return x.DoubleValue
 		}
 	}
-	return nil
+	return 0
 }
 
-func (x *Action) GetBoolArray() *BoolArray {
+func (x *Value) GetIntValue() int64 {
 	if x != nil {
-		if x, ok := x.Data.(*Action_BoolArray); ok {
-			return x.BoolArray
+		if x, ok := x.Kind.(*Value_IntValue); ok {
+			return x.IntValue
 		}
 	}
-	return nil
+	return 0
 }
 
-func (x *Action) GetStringValue() string {
+func (x *Value) GetBoolValue() bool {
 	if x != nil {
-		if x, ok := x.Data.(*Action_StringValue); ok {
-			return x.StringValue
+		if x, ok := x.Kind.(*Value_BoolValue); ok {
+			return x.BoolValue
 		}
 	}
-	return ""
+	return false
 }
 
-func (x *Action) GetRawData() []byte {
+func (x *Value) GetStringValue() string {
 	if x != nil {
-		if x, ok := x.Data.(*Action_RawData); ok {
-			return x.RawData
+		if x, ok := x.Kind.(*Value_StringValue); ok {
+			return x.StringValue
 		}
 	}
-	return nil
+	return ""
 }
 
-type isAction_Data interface {
-	isAction_Data()
+type isValue_Kind interface {
+	isValue_Kind()
 }
 
-type Action_FloatValue struct {
-	// 单个数值（最常见）
-	FloatValue float64 `protobuf:"fixed64,1,opt,name=float_value,json=floatValue,proto3,oneof"`
+type Value_DoubleValue struct {
+	DoubleValue float64 `protobuf:"fixed64,1,opt,name=double_value,json=doubleValue,proto3,oneof"`
 }
 
-type Action_IntValue struct {
+type Value_IntValue struct {
 	IntValue int64 `protobuf:"varint,2,opt,name=int_value,json=intValue,proto3,oneof"`
 }
 
-type Action_BoolValue struct {
+type Value_BoolValue struct {
 	BoolValue bool `protobuf:"varint,3,opt,name=bool_value,json=boolValue,proto3,oneof"`
 }
 
-type Action_FloatArray struct {
-	// 数组类型
-	FloatArray *FloatArray `protobuf:"bytes,4,opt,name=float_array,json=floatArray,proto3,oneof"`
+type Value_StringValue struct {
+	StringValue string `protobuf:"bytes,4,opt,name=string_value,json=stringValue,proto3,oneof"`
 }
 
-type Action_IntArray struct {
-	IntArray *IntArray `protobuf:"bytes,5,opt,name=int_array,json=intArray,proto3,oneof"`
-}
+func (*Value_DoubleValue) isValue_Kind() {}
 
-type Action_BoolArray struct {
-	BoolArray *BoolArray `protobuf:"bytes,6,opt,name=bool_array,json=boolArray,proto3,oneof"`
-}
+func (*Value_IntValue) isValue_Kind() {}
 
-type Action_StringValue struct {
-	// 字符串类型（用于离散动作）
-	StringValue string `protobuf:"bytes,7,opt,name=string_value,json=stringValue,proto3,oneof"`
+func (*Value_BoolValue) isValue_Kind() {}
+
+func (*Value_StringValue) isValue_Kind() {}
+
+type Action struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 通用的action数据，支持多种类型
+	//
+	// Types that are valid to be assigned to Data:
+	//
+	//	*Action_FloatValue
+	//	*Action_IntValue
+	//	*Action_BoolValue
+	//	*Action_FloatArray
+	//	*Action_IntArray
+	//	*Action_BoolArray
+	//	*Action_StringValue
+	//	*Action_RawData
+	//	*Action_Dict
+	//	*Action_Hybrid
+	Data          isAction_Data `protobuf_oneof:"data"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Action) Reset() {
+	*x = Action{}
+	mi := &file_proto_simulation_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Action) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Action) ProtoMessage() {}
+
+func (x *Action) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simulation_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Action.ProtoReflect.Descriptor instead.
+func (*Action) Descriptor() ([]byte, []int) {
+	return file_proto_simulation_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *Action) GetData() isAction_Data {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *Action) GetFloatValue() float64 {
+	if x != nil {
+		if x, ok := x.Data.(*Action_FloatValue); ok {
+			return x.FloatValue
+		}
+	}
+	return 0
+}
+
+func (x *Action) GetIntValue() int64 {
+	if x != nil {
+		if x, ok := x.Data.(*Action_IntValue); ok {
+			return x.IntValue
+		}
+	}
+	return 0
+}
+
+func (x *Action) GetBoolValue() bool {
+	if x != nil {
+		if x, ok := x.Data.(*Action_BoolValue); ok {
+			return x.BoolValue
+		}
+	}
+	return false
+}
+
+func (x *Action) GetFloatArray() *FloatArray {
+	if x != nil {
+		if x, ok := x.Data.(*Action_FloatArray); ok {
+			return x.FloatArray
+		}
+	}
+	return nil
+}
+
+func (x *Action) GetIntArray() *IntArray {
+	if x != nil {
+		if x, ok := x.Data.(*Action_IntArray); ok {
+			return x.IntArray
+		}
+	}
+	return nil
+}
+
+func (x *Action) GetBoolArray() *BoolArray {
+	if x != nil {
+		if x, ok := x.Data.(*Action_BoolArray); ok {
+			return x.BoolArray
+		}
+	}
+	return nil
+}
+
+func (x *Action) GetStringValue() string {
+	if x != nil {
+		if x, ok := x.Data.(*Action_StringValue); ok {
+			return x.StringValue
+		}
+	}
+	return ""
+}
+
+func (x *Action) GetRawData() []byte {
+	if x != nil {
+		if x, ok := x.Data.(*Action_RawData); ok {
+			return x.RawData
+		}
+	}
+	return nil
+}
+
+func (x *Action) GetDict() *ActionDict {
+	if x != nil {
+		if x, ok := x.Data.(*Action_Dict); ok {
+			return x.Dict
+		}
+	}
+	return nil
+}
+
+func (x *Action) GetHybrid() *HybridAction {
+	if x != nil {
+		if x, ok := x.Data.(*Action_Hybrid); ok {
+			return x.Hybrid
+		}
+	}
+	return nil
+}
+
+type isAction_Data interface {
+	isAction_Data()
+}
+
+type Action_FloatValue struct {
+	// 单个数值（最常见）
+	FloatValue float64 `protobuf:"fixed64,1,opt,name=float_value,json=floatValue,proto3,oneof"`
+}
+
+type Action_IntValue struct {
+	IntValue int64 `protobuf:"varint,2,opt,name=int_value,json=intValue,proto3,oneof"`
+}
+
+type Action_BoolValue struct {
+	BoolValue bool `protobuf:"varint,3,opt,name=bool_value,json=boolValue,proto3,oneof"`
+}
+
+type Action_FloatArray struct {
+	// 数组类型
+	FloatArray *FloatArray `protobuf:"bytes,4,opt,name=float_array,json=floatArray,proto3,oneof"`
+}
+
+type Action_IntArray struct {
+	IntArray *IntArray `protobuf:"bytes,5,opt,name=int_array,json=intArray,proto3,oneof"` // MultiDiscrete动作：每维一个整数
+}
+
+type Action_BoolArray struct {
+	BoolArray *BoolArray `protobuf:"bytes,6,opt,name=bool_array,json=boolArray,proto3,oneof"` // MultiBinary动作：每维一个开关
+}
+
+type Action_StringValue struct {
+	// 字符串类型（用于离散动作与Text动作）
+	StringValue string `protobuf:"bytes,7,opt,name=string_value,json=stringValue,proto3,oneof"`
 }
 
 type Action_RawData struct {
@@ -836,6 +1581,16 @@ type Action_RawData struct {
 	RawData []byte `protobuf:"bytes,8,opt,name=raw_data,json=rawData,proto3,oneof"`
 }
 
+type Action_Dict struct {
+	// 命名分量组成的复合动作（用于DICT动作空间）
+	Dict *ActionDict `protobuf:"bytes,9,opt,name=dict,proto3,oneof"`
+}
+
+type Action_Hybrid struct {
+	// 参数化动作（用于HYBRID动作空间）
+	Hybrid *HybridAction `protobuf:"bytes,10,opt,name=hybrid,proto3,oneof"`
+}
+
 func (*Action_FloatValue) isAction_Data() {}
 
 func (*Action_IntValue) isAction_Data() {}
@@ -852,6 +1607,108 @@ func (*Action_StringValue) isAction_Data() {}
 
 func (*Action_RawData) isAction_Data() {}
 
+func (*Action_Dict) isAction_Data() {}
+
+func (*Action_Hybrid) isAction_Data() {}
+
+// 参数化动作：所选的离散动作及其连续参数
+type HybridAction struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Choice        int64                  `protobuf:"varint,1,opt,name=choice,proto3" json:"choice,omitempty"`                 // 离散动作的下标
+	Parameters    []float64              `protobuf:"fixed64,2,rep,packed,name=parameters,proto3" json:"parameters,omitempty"` // 所选动作的参数，个数与ActionSpace.parameters[choice]的元素个数一致
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HybridAction) Reset() {
+	*x = HybridAction{}
+	mi := &file_proto_simulation_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HybridAction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HybridAction) ProtoMessage() {}
+
+func (x *HybridAction) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simulation_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HybridAction.ProtoReflect.Descriptor instead.
+func (*HybridAction) Descriptor() ([]byte, []int) {
+	return file_proto_simulation_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *HybridAction) GetChoice() int64 {
+	if x != nil {
+		return x.Choice
+	}
+	return 0
+}
+
+func (x *HybridAction) GetParameters() []float64 {
+	if x != nil {
+		return x.Parameters
+	}
+	return nil
+}
+
+// 复合动作：键为DICT动作空间中子空间的名称，每个子空间一个分量
+type ActionDict struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Actions       map[string]*Action     `protobuf:"bytes,1,rep,name=actions,proto3" json:"actions,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ActionDict) Reset() {
+	*x = ActionDict{}
+	mi := &file_proto_simulation_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ActionDict) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ActionDict) ProtoMessage() {}
+
+func (x *ActionDict) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simulation_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ActionDict.ProtoReflect.Descriptor instead.
+func (*ActionDict) Descriptor() ([]byte, []int) {
+	return file_proto_simulation_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ActionDict) GetActions() map[string]*Action {
+	if x != nil {
+		return x.Actions
+	}
+	return nil
+}
+
 // 辅助消息类型
 type FloatArray struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -862,19 +1719,1817 @@ type FloatArray struct {
 
 func (x *FloatArray) Reset() {
 	*x = FloatArray{}
-	mi := &file_proto_simulation_proto_msgTypes[12]
+	mi := &file_proto_simulation_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FloatArray) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FloatArray) ProtoMessage() {}
+
+func (x *FloatArray) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simulation_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FloatArray.ProtoReflect.Descriptor instead.
+func (*FloatArray) Descriptor() ([]byte, []int) {
+	return file_proto_simulation_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *FloatArray) GetValues() []float64 {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type IntArray struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Values        []int64                `protobuf:"varint,1,rep,packed,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IntArray) Reset() {
+	*x = IntArray{}
+	mi := &file_proto_simulation_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IntArray) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IntArray) ProtoMessage() {}
+
+func (x *IntArray) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simulation_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IntArray.ProtoReflect.Descriptor instead.
+func (*IntArray) Descriptor() ([]byte, []int) {
+	return file_proto_simulation_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *IntArray) GetValues() []int64 {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type BoolArray struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Values        []bool                 `protobuf:"varint,1,rep,packed,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BoolArray) Reset() {
+	*x = BoolArray{}
+	mi := &file_proto_simulation_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BoolArray) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BoolArray) ProtoMessage() {}
+
+func (x *BoolArray) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simulation_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BoolArray.ProtoReflect.Descriptor instead.
+func (*BoolArray) Descriptor() ([]byte, []int) {
+	return file_proto_simulation_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *BoolArray) GetValues() []bool {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+// 空间定义相关消息
+type GetSpacesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EnvId         string                 `protobuf:"bytes,1,opt,name=env_id,json=envId,proto3" json:"env_id,omitempty"` // 指定特定env, 由于可以通过config配置设置action space
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSpacesRequest) Reset() {
+	*x = GetSpacesRequest{}
+	mi := &file_proto_simulation_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSpacesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSpacesRequest) ProtoMessage() {}
+
+func (x *GetSpacesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simulation_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSpacesRequest.ProtoReflect.Descriptor instead.
+func (*GetSpacesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_simulation_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *GetSpacesRequest) GetEnvId() string {
+	if x != nil {
+		return x.EnvId
+	}
+	return ""
+}
+
+type GetSpacesResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	ActionSpace      *ActionSpace           `protobuf:"bytes,1,opt,name=action_space,json=actionSpace,proto3" json:"action_space,omitempty"`
+	ObservationSpace *ObservationSpace      `protobuf:"bytes,2,opt,name=observation_space,json=observationSpace,proto3" json:"observation_space,omitempty"`
+	SpacesJson       string                 `protobuf:"bytes,3,opt,name=spaces_json,json=spacesJson,proto3" json:"spaces_json,omitempty"` // 与HTTP /spaces相同的Gym兼容JSON（core.GymSpaces），可直接构造gymnasium空间
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *GetSpacesResponse) Reset() {
+	*x = GetSpacesResponse{}
+	mi := &file_proto_simulation_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSpacesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSpacesResponse) ProtoMessage() {}
+
+func (x *GetSpacesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simulation_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSpacesResponse.ProtoReflect.Descriptor instead.
+func (*GetSpacesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_simulation_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GetSpacesResponse) GetActionSpace() *ActionSpace {
+	if x != nil {
+		return x.ActionSpace
+	}
+	return nil
+}
+
+func (x *GetSpacesResponse) GetObservationSpace() *ObservationSpace {
+	if x != nil {
+		return x.ObservationSpace
+	}
+	return nil
+}
+
+func (x *GetSpacesResponse) GetSpacesJson() string {
+	if x != nil {
+		return x.SpacesJson
+	}
+	return ""
+}
+
+type ActionSpace struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Type  SpaceType              `protobuf:"varint,1,opt,name=type,proto3,enum=simulation.SpaceType" json:"type,omitempty"`
+	Low   []float64              `protobuf:"fixed64,2,rep,packed,name=low,proto3" json:"low,omitempty"`    // 最小值 (每维度一个值)
+	High  []float64              `protobuf:"fixed64,3,rep,packed,name=high,proto3" json:"high,omitempty"`  // 最大值 (每维度一个值)
+	Shape []int32                `protobuf:"varint,4,rep,packed,name=shape,proto3" json:"shape,omitempty"` // 形状: [action_dimensions]
+	// Discrete: [] (标量)
+	// Box: [action_dim]
+	// MultiDiscrete: [num_discrete_groups]
+	// MultiBinary: [num_binary_actions]
+	Dtype string `protobuf:"bytes,5,opt,name=dtype,proto3" json:"dtype,omitempty"` // 数据类型: "int32", "float32", etc.
+	// 支持离散浮点值
+	DiscreteValues []float64               `protobuf:"fixed64,6,rep,packed,name=discrete_values,json=discreteValues,proto3" json:"discrete_values,omitempty"`                             // 当type=DISCRETE时，可选的具体离散值列表
+	Nvec           []int64                 `protobuf:"varint,7,rep,packed,name=nvec,proto3" json:"nvec,omitempty"`                                                                        // 当type=MULTI_DISCRETE时，各维的取值个数（high-low+1），各维取值从low开始
+	MaxLength      int32                   `protobuf:"varint,8,opt,name=max_length,json=maxLength,proto3" json:"max_length,omitempty"`                                                    // 当type=TEXT时，文本的最大字符数；动作以string_value发送
+	Charset        string                  `protobuf:"bytes,9,opt,name=charset,proto3" json:"charset,omitempty"`                                                                          // 当type=TEXT时，允许的字符，为空时为大小写字母与数字
+	Spaces         map[string]*ActionSpace `protobuf:"bytes,10,rep,name=spaces,proto3" json:"spaces,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // 当type=DICT时，各命名分量的动作空间；动作以Action.dict发送
+	Parameters     []*ActionSpace          `protobuf:"bytes,11,rep,name=parameters,proto3" json:"parameters,omitempty"`                                                                   // 当type=HYBRID时，每个离散动作的参数空间（BOX）；动作以Action.hybrid发送
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ActionSpace) Reset() {
+	*x = ActionSpace{}
+	mi := &file_proto_simulation_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ActionSpace) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ActionSpace) ProtoMessage() {}
+
+func (x *ActionSpace) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simulation_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ActionSpace.ProtoReflect.Descriptor instead.
+func (*ActionSpace) Descriptor() ([]byte, []int) {
+	return file_proto_simulation_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ActionSpace) GetType() SpaceType {
+	if x != nil {
+		return x.Type
+	}
+	return SpaceType_BOX
+}
+
+func (x *ActionSpace) GetLow() []float64 {
+	if x != nil {
+		return x.Low
+	}
+	return nil
+}
+
+func (x *ActionSpace) GetHigh() []float64 {
+	if x != nil {
+		return x.High
+	}
+	return nil
+}
+
+func (x *ActionSpace) GetShape() []int32 {
+	if x != nil {
+		return x.Shape
+	}
+	return nil
+}
+
+func (x *ActionSpace) GetDtype() string {
+	if x != nil {
+		return x.Dtype
+	}
+	return ""
+}
+
+func (x *ActionSpace) GetDiscreteValues() []float64 {
+	if x != nil {
+		return x.DiscreteValues
+	}
+	return nil
+}
+
+func (x *ActionSpace) GetNvec() []int64 {
+	if x != nil {
+		return x.Nvec
+	}
+	return nil
+}
+
+func (x *ActionSpace) GetMaxLength() int32 {
+	if x != nil {
+		return x.MaxLength
+	}
+	return 0
+}
+
+func (x *ActionSpace) GetCharset() string {
+	if x != nil {
+		return x.Charset
+	}
+	return ""
+}
+
+func (x *ActionSpace) GetSpaces() map[string]*ActionSpace {
+	if x != nil {
+		return x.Spaces
+	}
+	return nil
+}
+
+func (x *ActionSpace) GetParameters() []*ActionSpace {
+	if x != nil {
+		return x.Parameters
+	}
+	return nil
+}
+
+type ObservationSpace struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          SpaceType              `protobuf:"varint,1,opt,name=type,proto3,enum=simulation.SpaceType" json:"type,omitempty"`
+	Low           []float64              `protobuf:"fixed64,2,rep,packed,name=low,proto3" json:"low,omitempty"`                      // 最小值
+	High          []float64              `protobuf:"fixed64,3,rep,packed,name=high,proto3" json:"high,omitempty"`                    // 最大值
+	Shape         []int32                `protobuf:"varint,4,rep,packed,name=shape,proto3" json:"shape,omitempty"`                   // 形状
+	Dtype         string                 `protobuf:"bytes,5,opt,name=dtype,proto3" json:"dtype,omitempty"`                           // 数据类型
+	MaxLength     int32                  `protobuf:"varint,6,opt,name=max_length,json=maxLength,proto3" json:"max_length,omitempty"` // 当type=TEXT时，文本的最大字符数；观察在Observation.text中返回
+	Charset       string                 `protobuf:"bytes,7,opt,name=charset,proto3" json:"charset,omitempty"`                       // 当type=TEXT时，允许的字符，为空时为大小写字母与数字
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ObservationSpace) Reset() {
+	*x = ObservationSpace{}
+	mi := &file_proto_simulation_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ObservationSpace) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ObservationSpace) ProtoMessage() {}
+
+func (x *ObservationSpace) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simulation_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ObservationSpace.ProtoReflect.Descriptor instead.
+func (*ObservationSpace) Descriptor() ([]byte, []int) {
+	return file_proto_simulation_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ObservationSpace) GetType() SpaceType {
+	if x != nil {
+		return x.Type
+	}
+	return SpaceType_BOX
+}
+
+func (x *ObservationSpace) GetLow() []float64 {
+	if x != nil {
+		return x.Low
+	}
+	return nil
+}
+
+func (x *ObservationSpace) GetHigh() []float64 {
+	if x != nil {
+		return x.High
+	}
+	return nil
+}
+
+func (x *ObservationSpace) GetShape() []int32 {
+	if x != nil {
+		return x.Shape
+	}
+	return nil
+}
+
+func (x *ObservationSpace) GetDtype() string {
+	if x != nil {
+		return x.Dtype
+	}
+	return ""
+}
+
+func (x *ObservationSpace) GetMaxLength() int32 {
+	if x != nil {
+		return x.MaxLength
+	}
+	return 0
+}
+
+func (x *ObservationSpace) GetCharset() string {
+	if x != nil {
+		return x.Charset
+	}
+	return ""
+}
+
+// 环境元数据相关消息
+type GetMetadataRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EnvId         string                 `protobuf:"bytes,1,opt,name=env_id,json=envId,proto3" json:"env_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMetadataRequest) Reset() {
+	*x = GetMetadataRequest{}
+	mi := &file_proto_simulation_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMetadataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMetadataRequest) ProtoMessage() {}
+
+func (x *GetMetadataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simulation_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMetadataRequest.ProtoReflect.Descriptor instead.
+func (*GetMetadataRequest) Descriptor() ([]byte, []int) {
+	return file_proto_simulation_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *GetMetadataRequest) GetEnvId() string {
+	if x != nil {
+		return x.EnvId
+	}
+	return ""
+}
+
+type GetMetadataResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	RewardRange      []float64              `protobuf:"fixed64,1,rep,packed,name=reward_range,json=rewardRange,proto3" json:"reward_range,omitempty"`       // 单步奖励的[最小值, 最大值]，无界时为-inf/+inf
+	MaxEpisodeSteps  int32                  `protobuf:"varint,2,opt,name=max_episode_steps,json=maxEpisodeSteps,proto3" json:"max_episode_steps,omitempty"` // 每回合最大步数（截断），0表示不截断
+	RenderModes      []string               `protobuf:"bytes,3,rep,name=render_modes,json=renderModes,proto3" json:"render_modes,omitempty"`                // 支持的渲染模式: "ansi", "rgb_array"
+	Nondeterministic bool                   `protobuf:"varint,4,opt,name=nondeterministic,proto3" json:"nondeterministic,omitempty"`                        // 固定种子下转移是否仍可能不同
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *GetMetadataResponse) Reset() {
+	*x = GetMetadataResponse{}
+	mi := &file_proto_simulation_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMetadataResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMetadataResponse) ProtoMessage() {}
+
+func (x *GetMetadataResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simulation_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMetadataResponse.ProtoReflect.Descriptor instead.
+func (*GetMetadataResponse) Descriptor() ([]byte, []int) {
+	return file_proto_simulation_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *GetMetadataResponse) GetRewardRange() []float64 {
+	if x != nil {
+		return x.RewardRange
+	}
+	return nil
+}
+
+func (x *GetMetadataResponse) GetMaxEpisodeSteps() int32 {
+	if x != nil {
+		return x.MaxEpisodeSteps
+	}
+	return 0
+}
+
+func (x *GetMetadataResponse) GetRenderModes() []string {
+	if x != nil {
+		return x.RenderModes
+	}
+	return nil
+}
+
+func (x *GetMetadataResponse) GetNondeterministic() bool {
+	if x != nil {
+		return x.Nondeterministic
+	}
+	return false
+}
+
+// 环境调试相关消息
+type DebugEnvironmentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EnvId         string                 `protobuf:"bytes,1,opt,name=env_id,json=envId,proto3" json:"env_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DebugEnvironmentRequest) Reset() {
+	*x = DebugEnvironmentRequest{}
+	mi := &file_proto_simulation_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DebugEnvironmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DebugEnvironmentRequest) ProtoMessage() {}
+
+func (x *DebugEnvironmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simulation_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DebugEnvironmentRequest.ProtoReflect.Descriptor instead.
+func (*DebugEnvironmentRequest) Descriptor() ([]byte, []int) {
+	return file_proto_simulation_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *DebugEnvironmentRequest) GetEnvId() string {
+	if x != nil {
+		return x.EnvId
+	}
+	return ""
+}
+
+type DebugEnvironmentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DumpJson      string                 `protobuf:"bytes,1,opt,name=dump_json,json=dumpJson,proto3" json:"dump_json,omitempty"` // JSON编码的环境内部状态，字段与HTTP的GET /debug/env/{id}相同
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DebugEnvironmentResponse) Reset() {
+	*x = DebugEnvironmentResponse{}
+	mi := &file_proto_simulation_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DebugEnvironmentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DebugEnvironmentResponse) ProtoMessage() {}
+
+func (x *DebugEnvironmentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simulation_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DebugEnvironmentResponse.ProtoReflect.Descriptor instead.
+func (*DebugEnvironmentResponse) Descriptor() ([]byte, []int) {
+	return file_proto_simulation_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *DebugEnvironmentResponse) GetDumpJson() string {
+	if x != nil {
+		return x.DumpJson
+	}
+	return ""
+}
+
+type EvaluatePolicyRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Scenario string                 `protobuf:"bytes,1,opt,name=scenario,proto3" json:"scenario,omitempty"`
+	Config   *structpb.Struct       `protobuf:"bytes,2,opt,name=config,proto3" json:"config,omitempty"`                      // 与CreateEnvironmentRequest.config相同
+	Model    []byte                 `protobuf:"bytes,3,opt,name=model,proto3" json:"model,omitempty"`                        // 序列化的ONNX ModelProto，支持的算子见core/policy
+	Episodes int32                  `protobuf:"varint,4,opt,name=episodes,proto3" json:"episodes,omitempty"`                 // 回合数，0表示1
+	MaxSteps int32                  `protobuf:"varint,5,opt,name=max_steps,json=maxSteps,proto3" json:"max_steps,omitempty"` // 每回合最大步数，0表示10000
+	Policy   string                 `protobuf:"bytes,6,opt,name=policy,proto3" json:"policy,omitempty"`                      // "onnx"（为空时的默认值）使用model；内置基线忽略model："random"在动作空间中均匀随机采样，
+	// "zero"始终给出最接近零的动作，"heuristic"为经典场景的手写控制器（如cartpole、pendulum）
+	Seed          int64 `protobuf:"varint,7,opt,name=seed,proto3" json:"seed,omitempty"` // random策略的随机数种子
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EvaluatePolicyRequest) Reset() {
+	*x = EvaluatePolicyRequest{}
+	mi := &file_proto_simulation_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EvaluatePolicyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EvaluatePolicyRequest) ProtoMessage() {}
+
+func (x *EvaluatePolicyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simulation_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EvaluatePolicyRequest.ProtoReflect.Descriptor instead.
+func (*EvaluatePolicyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_simulation_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *EvaluatePolicyRequest) GetScenario() string {
+	if x != nil {
+		return x.Scenario
+	}
+	return ""
+}
+
+func (x *EvaluatePolicyRequest) GetConfig() *structpb.Struct {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+func (x *EvaluatePolicyRequest) GetModel() []byte {
+	if x != nil {
+		return x.Model
+	}
+	return nil
+}
+
+func (x *EvaluatePolicyRequest) GetEpisodes() int32 {
+	if x != nil {
+		return x.Episodes
+	}
+	return 0
+}
+
+func (x *EvaluatePolicyRequest) GetMaxSteps() int32 {
+	if x != nil {
+		return x.MaxSteps
+	}
+	return 0
+}
+
+func (x *EvaluatePolicyRequest) GetPolicy() string {
+	if x != nil {
+		return x.Policy
+	}
+	return ""
+}
+
+func (x *EvaluatePolicyRequest) GetSeed() int64 {
+	if x != nil {
+		return x.Seed
+	}
+	return 0
+}
+
+type EvaluatePolicyResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Returns        []float64              `protobuf:"fixed64,1,rep,packed,name=returns,proto3" json:"returns,omitempty"` // 每回合所有智能体的奖励之和
+	Lengths        []int32                `protobuf:"varint,2,rep,packed,name=lengths,proto3" json:"lengths,omitempty"`  // 每回合的步数
+	Truncated      int32                  `protobuf:"varint,3,opt,name=truncated,proto3" json:"truncated,omitempty"`     // 因截断（场景的最大步数、info中的truncated或max_steps）结束的回合数
+	MeanReturn     float64                `protobuf:"fixed64,4,opt,name=mean_return,json=meanReturn,proto3" json:"mean_return,omitempty"`
+	StdReturn      float64                `protobuf:"fixed64,5,opt,name=std_return,json=stdReturn,proto3" json:"std_return,omitempty"` // 回报的样本标准差
+	MeanLength     float64                `protobuf:"fixed64,6,opt,name=mean_length,json=meanLength,proto3" json:"mean_length,omitempty"`
+	TotalSteps     int64                  `protobuf:"varint,7,opt,name=total_steps,json=totalSteps,proto3" json:"total_steps,omitempty"`
+	ElapsedSeconds float64                `protobuf:"fixed64,8,opt,name=elapsed_seconds,json=elapsedSeconds,proto3" json:"elapsed_seconds,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *EvaluatePolicyResponse) Reset() {
+	*x = EvaluatePolicyResponse{}
+	mi := &file_proto_simulation_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EvaluatePolicyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EvaluatePolicyResponse) ProtoMessage() {}
+
+func (x *EvaluatePolicyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simulation_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EvaluatePolicyResponse.ProtoReflect.Descriptor instead.
+func (*EvaluatePolicyResponse) Descriptor() ([]byte, []int) {
+	return file_proto_simulation_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *EvaluatePolicyResponse) GetReturns() []float64 {
+	if x != nil {
+		return x.Returns
+	}
+	return nil
+}
+
+func (x *EvaluatePolicyResponse) GetLengths() []int32 {
+	if x != nil {
+		return x.Lengths
+	}
+	return nil
+}
+
+func (x *EvaluatePolicyResponse) GetTruncated() int32 {
+	if x != nil {
+		return x.Truncated
+	}
+	return 0
+}
+
+func (x *EvaluatePolicyResponse) GetMeanReturn() float64 {
+	if x != nil {
+		return x.MeanReturn
+	}
+	return 0
+}
+
+func (x *EvaluatePolicyResponse) GetStdReturn() float64 {
+	if x != nil {
+		return x.StdReturn
+	}
+	return 0
+}
+
+func (x *EvaluatePolicyResponse) GetMeanLength() float64 {
+	if x != nil {
+		return x.MeanLength
+	}
+	return 0
+}
+
+func (x *EvaluatePolicyResponse) GetTotalSteps() int64 {
+	if x != nil {
+		return x.TotalSteps
+	}
+	return 0
+}
+
+func (x *EvaluatePolicyResponse) GetElapsedSeconds() float64 {
+	if x != nil {
+		return x.ElapsedSeconds
+	}
+	return 0
+}
+
+type OpenSessionRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Client         string                 `protobuf:"bytes,1,opt,name=client,proto3" json:"client,omitempty"`                                        // 客户端名称，仅用于展示
+	TtlSeconds     int32                  `protobuf:"varint,2,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"`             // 空闲超时，0表示服务端默认值；每个携带会话ID的请求都会续期
+	BindConnection bool                   `protobuf:"varint,3,opt,name=bind_connection,json=bindConnection,proto3" json:"bind_connection,omitempty"` // 为true时会话随当前gRPC连接断开而关闭
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *OpenSessionRequest) Reset() {
+	*x = OpenSessionRequest{}
+	mi := &file_proto_simulation_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OpenSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OpenSessionRequest) ProtoMessage() {}
+
+func (x *OpenSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simulation_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OpenSessionRequest.ProtoReflect.Descriptor instead.
+func (*OpenSessionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_simulation_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *OpenSessionRequest) GetClient() string {
+	if x != nil {
+		return x.Client
+	}
+	return ""
+}
+
+func (x *OpenSessionRequest) GetTtlSeconds() int32 {
+	if x != nil {
+		return x.TtlSeconds
+	}
+	return 0
+}
+
+func (x *OpenSessionRequest) GetBindConnection() bool {
+	if x != nil {
+		return x.BindConnection
+	}
+	return false
+}
+
+type OpenSessionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	TtlSeconds    int32                  `protobuf:"varint,2,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OpenSessionResponse) Reset() {
+	*x = OpenSessionResponse{}
+	mi := &file_proto_simulation_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OpenSessionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OpenSessionResponse) ProtoMessage() {}
+
+func (x *OpenSessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simulation_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OpenSessionResponse.ProtoReflect.Descriptor instead.
+func (*OpenSessionResponse) Descriptor() ([]byte, []int) {
+	return file_proto_simulation_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *OpenSessionResponse) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *OpenSessionResponse) GetTtlSeconds() int32 {
+	if x != nil {
+		return x.TtlSeconds
+	}
+	return 0
+}
+
+type CloseSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CloseSessionRequest) Reset() {
+	*x = CloseSessionRequest{}
+	mi := &file_proto_simulation_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CloseSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloseSessionRequest) ProtoMessage() {}
+
+func (x *CloseSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simulation_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloseSessionRequest.ProtoReflect.Descriptor instead.
+func (*CloseSessionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_simulation_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *CloseSessionRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+type CloseSessionResponse struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	ClosedEnvironments int32                  `protobuf:"varint,1,opt,name=closed_environments,json=closedEnvironments,proto3" json:"closed_environments,omitempty"` // 随会话关闭的环境数
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *CloseSessionResponse) Reset() {
+	*x = CloseSessionResponse{}
+	mi := &file_proto_simulation_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CloseSessionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloseSessionResponse) ProtoMessage() {}
+
+func (x *CloseSessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simulation_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloseSessionResponse.ProtoReflect.Descriptor instead.
+func (*CloseSessionResponse) Descriptor() ([]byte, []int) {
+	return file_proto_simulation_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *CloseSessionResponse) GetClosedEnvironments() int32 {
+	if x != nil {
+		return x.ClosedEnvironments
+	}
+	return 0
+}
+
+// 管理接口的消息，服务端设置了管理令牌时请求元数据admin-token需携带该令牌
+type EnvironmentStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EnvId         string                 `protobuf:"bytes,1,opt,name=env_id,json=envId,proto3" json:"env_id,omitempty"` // 注册表中的完整键，会话内的环境为session_id/env_id
+	Scenario      string                 `protobuf:"bytes,2,opt,name=scenario,proto3" json:"scenario,omitempty"`
+	SessionId     string                 `protobuf:"bytes,3,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`         // 所属会话，不属于会话时为空
+	Client        string                 `protobuf:"bytes,4,opt,name=client,proto3" json:"client,omitempty"`                                // 创建该环境的客户端主机
+	AgeSeconds    float64                `protobuf:"fixed64,5,opt,name=age_seconds,json=ageSeconds,proto3" json:"age_seconds,omitempty"`    // 创建至今的秒数
+	IdleSeconds   float64                `protobuf:"fixed64,6,opt,name=idle_seconds,json=idleSeconds,proto3" json:"idle_seconds,omitempty"` // 最近一次reset或step至今的秒数
+	Steps         int64                  `protobuf:"varint,7,opt,name=steps,proto3" json:"steps,omitempty"`                                 // 累计步数
+	Episodes      int64                  `protobuf:"varint,8,opt,name=episodes,proto3" json:"episodes,omitempty"`                           // 累计reset次数
+	Tenant        string                 `protobuf:"bytes,9,opt,name=tenant,proto3" json:"tenant,omitempty"`                                // 所属租户，未配置租户时为空
+	Fault         string                 `protobuf:"bytes,10,opt,name=fault,proto3" json:"fault,omitempty"`                                 // 环境panic后的故障原因，此后只能关闭；正常时为空
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnvironmentStatus) Reset() {
+	*x = EnvironmentStatus{}
+	mi := &file_proto_simulation_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnvironmentStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnvironmentStatus) ProtoMessage() {}
+
+func (x *EnvironmentStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simulation_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnvironmentStatus.ProtoReflect.Descriptor instead.
+func (*EnvironmentStatus) Descriptor() ([]byte, []int) {
+	return file_proto_simulation_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *EnvironmentStatus) GetEnvId() string {
+	if x != nil {
+		return x.EnvId
+	}
+	return ""
+}
+
+func (x *EnvironmentStatus) GetScenario() string {
+	if x != nil {
+		return x.Scenario
+	}
+	return ""
+}
+
+func (x *EnvironmentStatus) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *EnvironmentStatus) GetClient() string {
+	if x != nil {
+		return x.Client
+	}
+	return ""
+}
+
+func (x *EnvironmentStatus) GetAgeSeconds() float64 {
+	if x != nil {
+		return x.AgeSeconds
+	}
+	return 0
+}
+
+func (x *EnvironmentStatus) GetIdleSeconds() float64 {
+	if x != nil {
+		return x.IdleSeconds
+	}
+	return 0
+}
+
+func (x *EnvironmentStatus) GetSteps() int64 {
+	if x != nil {
+		return x.Steps
+	}
+	return 0
+}
+
+func (x *EnvironmentStatus) GetEpisodes() int64 {
+	if x != nil {
+		return x.Episodes
+	}
+	return 0
+}
+
+func (x *EnvironmentStatus) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+func (x *EnvironmentStatus) GetFault() string {
+	if x != nil {
+		return x.Fault
+	}
+	return ""
+}
+
+type ListEnvironmentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEnvironmentsRequest) Reset() {
+	*x = ListEnvironmentsRequest{}
+	mi := &file_proto_simulation_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEnvironmentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEnvironmentsRequest) ProtoMessage() {}
+
+func (x *ListEnvironmentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simulation_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEnvironmentsRequest.ProtoReflect.Descriptor instead.
+func (*ListEnvironmentsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_simulation_proto_rawDescGZIP(), []int{38}
+}
+
+type ListEnvironmentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Environments  []*EnvironmentStatus   `protobuf:"bytes,1,rep,name=environments,proto3" json:"environments,omitempty"`
+	Draining      bool                   `protobuf:"varint,2,opt,name=draining,proto3" json:"draining,omitempty"` // 服务端是否已停止创建新环境
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEnvironmentsResponse) Reset() {
+	*x = ListEnvironmentsResponse{}
+	mi := &file_proto_simulation_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEnvironmentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEnvironmentsResponse) ProtoMessage() {}
+
+func (x *ListEnvironmentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simulation_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEnvironmentsResponse.ProtoReflect.Descriptor instead.
+func (*ListEnvironmentsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_simulation_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *ListEnvironmentsResponse) GetEnvironments() []*EnvironmentStatus {
+	if x != nil {
+		return x.Environments
+	}
+	return nil
+}
+
+func (x *ListEnvironmentsResponse) GetDraining() bool {
+	if x != nil {
+		return x.Draining
+	}
+	return false
+}
+
+type ForceCloseEnvironmentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EnvId         string                 `protobuf:"bytes,1,opt,name=env_id,json=envId,proto3" json:"env_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ForceCloseEnvironmentRequest) Reset() {
+	*x = ForceCloseEnvironmentRequest{}
+	mi := &file_proto_simulation_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForceCloseEnvironmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForceCloseEnvironmentRequest) ProtoMessage() {}
+
+func (x *ForceCloseEnvironmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simulation_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForceCloseEnvironmentRequest.ProtoReflect.Descriptor instead.
+func (*ForceCloseEnvironmentRequest) Descriptor() ([]byte, []int) {
+	return file_proto_simulation_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *ForceCloseEnvironmentRequest) GetEnvId() string {
+	if x != nil {
+		return x.EnvId
+	}
+	return ""
+}
+
+type ForceCloseEnvironmentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Error         *Error                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"` // success为false时的错误
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ForceCloseEnvironmentResponse) Reset() {
+	*x = ForceCloseEnvironmentResponse{}
+	mi := &file_proto_simulation_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForceCloseEnvironmentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForceCloseEnvironmentResponse) ProtoMessage() {}
+
+func (x *ForceCloseEnvironmentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simulation_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForceCloseEnvironmentResponse.ProtoReflect.Descriptor instead.
+func (*ForceCloseEnvironmentResponse) Descriptor() ([]byte, []int) {
+	return file_proto_simulation_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *ForceCloseEnvironmentResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ForceCloseEnvironmentResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ForceCloseEnvironmentResponse) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+type DumpEnvironmentStateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EnvId         string                 `protobuf:"bytes,1,opt,name=env_id,json=envId,proto3" json:"env_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DumpEnvironmentStateRequest) Reset() {
+	*x = DumpEnvironmentStateRequest{}
+	mi := &file_proto_simulation_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DumpEnvironmentStateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DumpEnvironmentStateRequest) ProtoMessage() {}
+
+func (x *DumpEnvironmentStateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simulation_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DumpEnvironmentStateRequest.ProtoReflect.Descriptor instead.
+func (*DumpEnvironmentStateRequest) Descriptor() ([]byte, []int) {
+	return file_proto_simulation_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *DumpEnvironmentStateRequest) GetEnvId() string {
+	if x != nil {
+		return x.EnvId
+	}
+	return ""
+}
+
+type DumpEnvironmentStateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StateJson     string                 `protobuf:"bytes,1,opt,name=state_json,json=stateJson,proto3" json:"state_json,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DumpEnvironmentStateResponse) Reset() {
+	*x = DumpEnvironmentStateResponse{}
+	mi := &file_proto_simulation_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DumpEnvironmentStateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DumpEnvironmentStateResponse) ProtoMessage() {}
+
+func (x *DumpEnvironmentStateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simulation_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DumpEnvironmentStateResponse.ProtoReflect.Descriptor instead.
+func (*DumpEnvironmentStateResponse) Descriptor() ([]byte, []int) {
+	return file_proto_simulation_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *DumpEnvironmentStateResponse) GetStateJson() string {
+	if x != nil {
+		return x.StateJson
+	}
+	return ""
+}
+
+type DrainRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	TimeoutSeconds float64                `protobuf:"fixed64,1,opt,name=timeout_seconds,json=timeoutSeconds,proto3" json:"timeout_seconds,omitempty"` // 等待已有环境关闭的最长时间，0表示不等待
+	Force          bool                   `protobuf:"varint,2,opt,name=force,proto3" json:"force,omitempty"`                                          // 等待结束后强制关闭剩余环境
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *DrainRequest) Reset() {
+	*x = DrainRequest{}
+	mi := &file_proto_simulation_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DrainRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DrainRequest) ProtoMessage() {}
+
+func (x *DrainRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simulation_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DrainRequest.ProtoReflect.Descriptor instead.
+func (*DrainRequest) Descriptor() ([]byte, []int) {
+	return file_proto_simulation_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *DrainRequest) GetTimeoutSeconds() float64 {
+	if x != nil {
+		return x.TimeoutSeconds
+	}
+	return 0
+}
+
+func (x *DrainRequest) GetForce() bool {
+	if x != nil {
+		return x.Force
+	}
+	return false
+}
+
+type DrainResponse struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	RemainingEnvironments int32                  `protobuf:"varint,1,opt,name=remaining_environments,json=remainingEnvironments,proto3" json:"remaining_environments,omitempty"` // 结束时仍在运行的环境数
+	ClosedEnvironments    int32                  `protobuf:"varint,2,opt,name=closed_environments,json=closedEnvironments,proto3" json:"closed_environments,omitempty"`          // 被强制关闭的环境数
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *DrainResponse) Reset() {
+	*x = DrainResponse{}
+	mi := &file_proto_simulation_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DrainResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DrainResponse) ProtoMessage() {}
+
+func (x *DrainResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simulation_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DrainResponse.ProtoReflect.Descriptor instead.
+func (*DrainResponse) Descriptor() ([]byte, []int) {
+	return file_proto_simulation_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *DrainResponse) GetRemainingEnvironments() int32 {
+	if x != nil {
+		return x.RemainingEnvironments
+	}
+	return 0
+}
+
+func (x *DrainResponse) GetClosedEnvironments() int32 {
+	if x != nil {
+		return x.ClosedEnvironments
+	}
+	return 0
+}
+
+type ExportEnvironmentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EnvId         string                 `protobuf:"bytes,1,opt,name=env_id,json=envId,proto3" json:"env_id,omitempty"` // 注册表中的完整键
+	Detach        bool                   `protobuf:"varint,2,opt,name=detach,proto3" json:"detach,omitempty"`           // 导出后从本服务端移除并关闭这些环境（及会话）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportEnvironmentRequest) Reset() {
+	*x = ExportEnvironmentRequest{}
+	mi := &file_proto_simulation_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportEnvironmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportEnvironmentRequest) ProtoMessage() {}
+
+func (x *ExportEnvironmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simulation_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportEnvironmentRequest.ProtoReflect.Descriptor instead.
+func (*ExportEnvironmentRequest) Descriptor() ([]byte, []int) {
+	return file_proto_simulation_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *ExportEnvironmentRequest) GetEnvId() string {
+	if x != nil {
+		return x.EnvId
+	}
+	return ""
+}
+
+func (x *ExportEnvironmentRequest) GetDetach() bool {
+	if x != nil {
+		return x.Detach
+	}
+	return false
+}
+
+type ExportEnvironmentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Snapshot      []byte                 `protobuf:"bytes,1,opt,name=snapshot,proto3" json:"snapshot,omitempty"`          // JSON编码的快照，格式与快照文件相同
+	Environments  int32                  `protobuf:"varint,2,opt,name=environments,proto3" json:"environments,omitempty"` // 快照中的环境数
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportEnvironmentResponse) Reset() {
+	*x = ExportEnvironmentResponse{}
+	mi := &file_proto_simulation_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportEnvironmentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportEnvironmentResponse) ProtoMessage() {}
+
+func (x *ExportEnvironmentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simulation_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportEnvironmentResponse.ProtoReflect.Descriptor instead.
+func (*ExportEnvironmentResponse) Descriptor() ([]byte, []int) {
+	return file_proto_simulation_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *ExportEnvironmentResponse) GetSnapshot() []byte {
+	if x != nil {
+		return x.Snapshot
+	}
+	return nil
+}
+
+func (x *ExportEnvironmentResponse) GetEnvironments() int32 {
+	if x != nil {
+		return x.Environments
+	}
+	return 0
+}
+
+type ImportEnvironmentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Snapshot      []byte                 `protobuf:"bytes,1,opt,name=snapshot,proto3" json:"snapshot,omitempty"` // ExportEnvironmentResponse.snapshot
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportEnvironmentRequest) Reset() {
+	*x = ImportEnvironmentRequest{}
+	mi := &file_proto_simulation_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportEnvironmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportEnvironmentRequest) ProtoMessage() {}
+
+func (x *ImportEnvironmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simulation_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportEnvironmentRequest.ProtoReflect.Descriptor instead.
+func (*ImportEnvironmentRequest) Descriptor() ([]byte, []int) {
+	return file_proto_simulation_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *ImportEnvironmentRequest) GetSnapshot() []byte {
+	if x != nil {
+		return x.Snapshot
+	}
+	return nil
+}
+
+type ImportEnvironmentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Environments  int32                  `protobuf:"varint,1,opt,name=environments,proto3" json:"environments,omitempty"` // 恢复的环境数
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportEnvironmentResponse) Reset() {
+	*x = ImportEnvironmentResponse{}
+	mi := &file_proto_simulation_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportEnvironmentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportEnvironmentResponse) ProtoMessage() {}
+
+func (x *ImportEnvironmentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simulation_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportEnvironmentResponse.ProtoReflect.Descriptor instead.
+func (*ImportEnvironmentResponse) Descriptor() ([]byte, []int) {
+	return file_proto_simulation_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *ImportEnvironmentResponse) GetEnvironments() int32 {
+	if x != nil {
+		return x.Environments
+	}
+	return 0
+}
+
+type MigrateEnvironmentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EnvId         string                 `protobuf:"bytes,1,opt,name=env_id,json=envId,proto3" json:"env_id,omitempty"` // 注册表中的完整键（ListEnvironments中的env_id）
+	Worker        string                 `protobuf:"bytes,2,opt,name=worker,proto3" json:"worker,omitempty"`            // 目标工作节点的地址
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MigrateEnvironmentRequest) Reset() {
+	*x = MigrateEnvironmentRequest{}
+	mi := &file_proto_simulation_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MigrateEnvironmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MigrateEnvironmentRequest) ProtoMessage() {}
+
+func (x *MigrateEnvironmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simulation_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MigrateEnvironmentRequest.ProtoReflect.Descriptor instead.
+func (*MigrateEnvironmentRequest) Descriptor() ([]byte, []int) {
+	return file_proto_simulation_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *MigrateEnvironmentRequest) GetEnvId() string {
+	if x != nil {
+		return x.EnvId
+	}
+	return ""
+}
+
+func (x *MigrateEnvironmentRequest) GetWorker() string {
+	if x != nil {
+		return x.Worker
+	}
+	return ""
+}
+
+type MigrateEnvironmentResponse struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	MigratedEnvironments int32                  `protobuf:"varint,1,opt,name=migrated_environments,json=migratedEnvironments,proto3" json:"migrated_environments,omitempty"` // 迁移的环境数，环境属于会话时包含会话的全部环境
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *MigrateEnvironmentResponse) Reset() {
+	*x = MigrateEnvironmentResponse{}
+	mi := &file_proto_simulation_proto_msgTypes[51]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *FloatArray) String() string {
+func (x *MigrateEnvironmentResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*FloatArray) ProtoMessage() {}
+func (*MigrateEnvironmentResponse) ProtoMessage() {}
 
-func (x *FloatArray) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_simulation_proto_msgTypes[12]
+func (x *MigrateEnvironmentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simulation_proto_msgTypes[51]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -885,40 +3540,40 @@ func (x *FloatArray) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use FloatArray.ProtoReflect.Descriptor instead.
-func (*FloatArray) Descriptor() ([]byte, []int) {
-	return file_proto_simulation_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use MigrateEnvironmentResponse.ProtoReflect.Descriptor instead.
+func (*MigrateEnvironmentResponse) Descriptor() ([]byte, []int) {
+	return file_proto_simulation_proto_rawDescGZIP(), []int{51}
 }
 
-func (x *FloatArray) GetValues() []float64 {
+func (x *MigrateEnvironmentResponse) GetMigratedEnvironments() int32 {
 	if x != nil {
-		return x.Values
+		return x.MigratedEnvironments
 	}
-	return nil
+	return 0
 }
 
-type IntArray struct {
+type DrainWorkerRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Values        []int64                `protobuf:"varint,1,rep,packed,name=values,proto3" json:"values,omitempty"`
+	Worker        string                 `protobuf:"bytes,1,opt,name=worker,proto3" json:"worker,omitempty"` // 工作节点的地址
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *IntArray) Reset() {
-	*x = IntArray{}
-	mi := &file_proto_simulation_proto_msgTypes[13]
+func (x *DrainWorkerRequest) Reset() {
+	*x = DrainWorkerRequest{}
+	mi := &file_proto_simulation_proto_msgTypes[52]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *IntArray) String() string {
+func (x *DrainWorkerRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*IntArray) ProtoMessage() {}
+func (*DrainWorkerRequest) ProtoMessage() {}
 
-func (x *IntArray) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_simulation_proto_msgTypes[13]
+func (x *DrainWorkerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simulation_proto_msgTypes[52]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -929,40 +3584,41 @@ func (x *IntArray) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use IntArray.ProtoReflect.Descriptor instead.
-func (*IntArray) Descriptor() ([]byte, []int) {
-	return file_proto_simulation_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use DrainWorkerRequest.ProtoReflect.Descriptor instead.
+func (*DrainWorkerRequest) Descriptor() ([]byte, []int) {
+	return file_proto_simulation_proto_rawDescGZIP(), []int{52}
 }
 
-func (x *IntArray) GetValues() []int64 {
+func (x *DrainWorkerRequest) GetWorker() string {
 	if x != nil {
-		return x.Values
+		return x.Worker
 	}
-	return nil
+	return ""
 }
 
-type BoolArray struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Values        []bool                 `protobuf:"varint,1,rep,packed,name=values,proto3" json:"values,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+type DrainWorkerResponse struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	MigratedEnvironments  int32                  `protobuf:"varint,1,opt,name=migrated_environments,json=migratedEnvironments,proto3" json:"migrated_environments,omitempty"`    // 迁移到其余节点的环境数
+	RemainingEnvironments int32                  `protobuf:"varint,2,opt,name=remaining_environments,json=remainingEnvironments,proto3" json:"remaining_environments,omitempty"` // 无法迁移（未实现检查点）而留在该节点的环境数
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
 }
 
-func (x *BoolArray) Reset() {
-	*x = BoolArray{}
-	mi := &file_proto_simulation_proto_msgTypes[14]
+func (x *DrainWorkerResponse) Reset() {
+	*x = DrainWorkerResponse{}
+	mi := &file_proto_simulation_proto_msgTypes[53]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *BoolArray) String() string {
+func (x *DrainWorkerResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BoolArray) ProtoMessage() {}
+func (*DrainWorkerResponse) ProtoMessage() {}
 
-func (x *BoolArray) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_simulation_proto_msgTypes[14]
+func (x *DrainWorkerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simulation_proto_msgTypes[53]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -973,41 +3629,49 @@ func (x *BoolArray) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BoolArray.ProtoReflect.Descriptor instead.
-func (*BoolArray) Descriptor() ([]byte, []int) {
-	return file_proto_simulation_proto_rawDescGZIP(), []int{14}
+// Deprecated: Use DrainWorkerResponse.ProtoReflect.Descriptor instead.
+func (*DrainWorkerResponse) Descriptor() ([]byte, []int) {
+	return file_proto_simulation_proto_rawDescGZIP(), []int{53}
 }
 
-func (x *BoolArray) GetValues() []bool {
+func (x *DrainWorkerResponse) GetMigratedEnvironments() int32 {
 	if x != nil {
-		return x.Values
+		return x.MigratedEnvironments
 	}
-	return nil
+	return 0
 }
 
-// 空间定义相关消息
-type GetSpacesRequest struct {
+func (x *DrainWorkerResponse) GetRemainingEnvironments() int32 {
+	if x != nil {
+		return x.RemainingEnvironments
+	}
+	return 0
+}
+
+type RegisterScenarioRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	EnvId         string                 `protobuf:"bytes,1,opt,name=env_id,json=envId,proto3" json:"env_id,omitempty"` // 指定特定env, 由于可以通过config配置设置action space
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"` // 场景名，不能与已有的场景（含此前上传的场景）或预设重名
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Wasm          []byte                 `protobuf:"bytes,3,opt,name=wasm,proto3" json:"wasm,omitempty"` // WebAssembly二进制模块
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetSpacesRequest) Reset() {
-	*x = GetSpacesRequest{}
-	mi := &file_proto_simulation_proto_msgTypes[15]
+func (x *RegisterScenarioRequest) Reset() {
+	*x = RegisterScenarioRequest{}
+	mi := &file_proto_simulation_proto_msgTypes[54]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetSpacesRequest) String() string {
+func (x *RegisterScenarioRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetSpacesRequest) ProtoMessage() {}
+func (*RegisterScenarioRequest) ProtoMessage() {}
 
-func (x *GetSpacesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_simulation_proto_msgTypes[15]
+func (x *RegisterScenarioRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simulation_proto_msgTypes[54]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1018,41 +3682,53 @@ func (x *GetSpacesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetSpacesRequest.ProtoReflect.Descriptor instead.
-func (*GetSpacesRequest) Descriptor() ([]byte, []int) {
-	return file_proto_simulation_proto_rawDescGZIP(), []int{15}
+// Deprecated: Use RegisterScenarioRequest.ProtoReflect.Descriptor instead.
+func (*RegisterScenarioRequest) Descriptor() ([]byte, []int) {
+	return file_proto_simulation_proto_rawDescGZIP(), []int{54}
 }
 
-func (x *GetSpacesRequest) GetEnvId() string {
+func (x *RegisterScenarioRequest) GetName() string {
 	if x != nil {
-		return x.EnvId
+		return x.Name
 	}
 	return ""
 }
 
-type GetSpacesResponse struct {
-	state            protoimpl.MessageState `protogen:"open.v1"`
-	ActionSpace      *ActionSpace           `protobuf:"bytes,1,opt,name=action_space,json=actionSpace,proto3" json:"action_space,omitempty"`
-	ObservationSpace *ObservationSpace      `protobuf:"bytes,2,opt,name=observation_space,json=observationSpace,proto3" json:"observation_space,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+func (x *RegisterScenarioRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
 }
 
-func (x *GetSpacesResponse) Reset() {
-	*x = GetSpacesResponse{}
-	mi := &file_proto_simulation_proto_msgTypes[16]
+func (x *RegisterScenarioRequest) GetWasm() []byte {
+	if x != nil {
+		return x.Wasm
+	}
+	return nil
+}
+
+type RegisterScenarioResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterScenarioResponse) Reset() {
+	*x = RegisterScenarioResponse{}
+	mi := &file_proto_simulation_proto_msgTypes[55]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetSpacesResponse) String() string {
+func (x *RegisterScenarioResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetSpacesResponse) ProtoMessage() {}
+func (*RegisterScenarioResponse) ProtoMessage() {}
 
-func (x *GetSpacesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_simulation_proto_msgTypes[16]
+func (x *RegisterScenarioResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simulation_proto_msgTypes[55]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1063,57 +3739,33 @@ func (x *GetSpacesResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetSpacesResponse.ProtoReflect.Descriptor instead.
-func (*GetSpacesResponse) Descriptor() ([]byte, []int) {
-	return file_proto_simulation_proto_rawDescGZIP(), []int{16}
-}
-
-func (x *GetSpacesResponse) GetActionSpace() *ActionSpace {
-	if x != nil {
-		return x.ActionSpace
-	}
-	return nil
-}
-
-func (x *GetSpacesResponse) GetObservationSpace() *ObservationSpace {
-	if x != nil {
-		return x.ObservationSpace
-	}
-	return nil
+// Deprecated: Use RegisterScenarioResponse.ProtoReflect.Descriptor instead.
+func (*RegisterScenarioResponse) Descriptor() ([]byte, []int) {
+	return file_proto_simulation_proto_rawDescGZIP(), []int{55}
 }
 
-type ActionSpace struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	Type  SpaceType              `protobuf:"varint,1,opt,name=type,proto3,enum=simulation.SpaceType" json:"type,omitempty"`
-	Low   []float64              `protobuf:"fixed64,2,rep,packed,name=low,proto3" json:"low,omitempty"`    // 最小值 (每维度一个值)
-	High  []float64              `protobuf:"fixed64,3,rep,packed,name=high,proto3" json:"high,omitempty"`  // 最大值 (每维度一个值)
-	Shape []int32                `protobuf:"varint,4,rep,packed,name=shape,proto3" json:"shape,omitempty"` // 形状: [action_dimensions]
-	// Discrete: [] (标量)
-	// Box: [action_dim]
-	// MultiDiscrete: [num_discrete_groups]
-	// MultiBinary: [num_binary_actions]
-	Dtype string `protobuf:"bytes,5,opt,name=dtype,proto3" json:"dtype,omitempty"` // 数据类型: "int32", "float32", etc.
-	// 支持离散浮点值
-	DiscreteValues []float64 `protobuf:"fixed64,6,rep,packed,name=discrete_values,json=discreteValues,proto3" json:"discrete_values,omitempty"` // 当type=DISCRETE时，可选的具体离散值列表
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+type GetCurriculumRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EnvId         string                 `protobuf:"bytes,1,opt,name=env_id,json=envId,proto3" json:"env_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ActionSpace) Reset() {
-	*x = ActionSpace{}
-	mi := &file_proto_simulation_proto_msgTypes[17]
+func (x *GetCurriculumRequest) Reset() {
+	*x = GetCurriculumRequest{}
+	mi := &file_proto_simulation_proto_msgTypes[56]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ActionSpace) String() string {
+func (x *GetCurriculumRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ActionSpace) ProtoMessage() {}
+func (*GetCurriculumRequest) ProtoMessage() {}
 
-func (x *ActionSpace) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_simulation_proto_msgTypes[17]
+func (x *GetCurriculumRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simulation_proto_msgTypes[56]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1124,79 +3776,107 @@ func (x *ActionSpace) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ActionSpace.ProtoReflect.Descriptor instead.
-func (*ActionSpace) Descriptor() ([]byte, []int) {
-	return file_proto_simulation_proto_rawDescGZIP(), []int{17}
+// Deprecated: Use GetCurriculumRequest.ProtoReflect.Descriptor instead.
+func (*GetCurriculumRequest) Descriptor() ([]byte, []int) {
+	return file_proto_simulation_proto_rawDescGZIP(), []int{56}
 }
 
-func (x *ActionSpace) GetType() SpaceType {
+func (x *GetCurriculumRequest) GetEnvId() string {
 	if x != nil {
-		return x.Type
+		return x.EnvId
 	}
-	return SpaceType_BOX
+	return ""
 }
 
-func (x *ActionSpace) GetLow() []float64 {
-	if x != nil {
-		return x.Low
-	}
-	return nil
+type SetCurriculumStageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EnvId         string                 `protobuf:"bytes,1,opt,name=env_id,json=envId,proto3" json:"env_id,omitempty"`
+	Stage         int32                  `protobuf:"varint,2,opt,name=stage,proto3" json:"stage,omitempty"`   // 目标阶段，从0开始
+	Frozen        bool                   `protobuf:"varint,3,opt,name=frozen,proto3" json:"frozen,omitempty"` // 为true时停止按回合数或成功率自动推进，为false时恢复
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ActionSpace) GetHigh() []float64 {
+func (x *SetCurriculumStageRequest) Reset() {
+	*x = SetCurriculumStageRequest{}
+	mi := &file_proto_simulation_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetCurriculumStageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetCurriculumStageRequest) ProtoMessage() {}
+
+func (x *SetCurriculumStageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simulation_proto_msgTypes[57]
 	if x != nil {
-		return x.High
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *ActionSpace) GetShape() []int32 {
+// Deprecated: Use SetCurriculumStageRequest.ProtoReflect.Descriptor instead.
+func (*SetCurriculumStageRequest) Descriptor() ([]byte, []int) {
+	return file_proto_simulation_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *SetCurriculumStageRequest) GetEnvId() string {
 	if x != nil {
-		return x.Shape
+		return x.EnvId
 	}
-	return nil
+	return ""
 }
 
-func (x *ActionSpace) GetDtype() string {
+func (x *SetCurriculumStageRequest) GetStage() int32 {
 	if x != nil {
-		return x.Dtype
+		return x.Stage
 	}
-	return ""
+	return 0
 }
 
-func (x *ActionSpace) GetDiscreteValues() []float64 {
+func (x *SetCurriculumStageRequest) GetFrozen() bool {
 	if x != nil {
-		return x.DiscreteValues
+		return x.Frozen
 	}
-	return nil
+	return false
 }
 
-type ObservationSpace struct {
+type CurriculumProgress struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Type          SpaceType              `protobuf:"varint,1,opt,name=type,proto3,enum=simulation.SpaceType" json:"type,omitempty"`
-	Low           []float64              `protobuf:"fixed64,2,rep,packed,name=low,proto3" json:"low,omitempty"`    // 最小值
-	High          []float64              `protobuf:"fixed64,3,rep,packed,name=high,proto3" json:"high,omitempty"`  // 最大值
-	Shape         []int32                `protobuf:"varint,4,rep,packed,name=shape,proto3" json:"shape,omitempty"` // 形状
-	Dtype         string                 `protobuf:"bytes,5,opt,name=dtype,proto3" json:"dtype,omitempty"`         // 数据类型
+	Stage         int32                  `protobuf:"varint,1,opt,name=stage,proto3" json:"stage,omitempty"`                                                                                      // 当前阶段，从0开始
+	Stages        int32                  `protobuf:"varint,2,opt,name=stages,proto3" json:"stages,omitempty"`                                                                                    // 阶段总数
+	Episodes      int64                  `protobuf:"varint,3,opt,name=episodes,proto3" json:"episodes,omitempty"`                                                                                // 已完成的回合总数
+	StageEpisodes int64                  `protobuf:"varint,4,opt,name=stage_episodes,json=stageEpisodes,proto3" json:"stage_episodes,omitempty"`                                                 // 当前阶段已完成的回合数
+	SuccessRate   float64                `protobuf:"fixed64,5,opt,name=success_rate,json=successRate,proto3" json:"success_rate,omitempty"`                                                      // 当前阶段最近回合的成功率
+	Window        int32                  `protobuf:"varint,6,opt,name=window,proto3" json:"window,omitempty"`                                                                                    // 成功率统计的回合数
+	Frozen        bool                   `protobuf:"varint,7,opt,name=frozen,proto3" json:"frozen,omitempty"`                                                                                    // 是否停止自动推进
+	Parameters    map[string]float64     `protobuf:"bytes,8,rep,name=parameters,proto3" json:"parameters,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"` // 当前回合生效的参数
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ObservationSpace) Reset() {
-	*x = ObservationSpace{}
-	mi := &file_proto_simulation_proto_msgTypes[18]
+func (x *CurriculumProgress) Reset() {
+	*x = CurriculumProgress{}
+	mi := &file_proto_simulation_proto_msgTypes[58]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ObservationSpace) String() string {
+func (x *CurriculumProgress) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ObservationSpace) ProtoMessage() {}
+func (*CurriculumProgress) ProtoMessage() {}
 
-func (x *ObservationSpace) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_simulation_proto_msgTypes[18]
+func (x *CurriculumProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simulation_proto_msgTypes[58]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1207,44 +3887,65 @@ func (x *ObservationSpace) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ObservationSpace.ProtoReflect.Descriptor instead.
-func (*ObservationSpace) Descriptor() ([]byte, []int) {
-	return file_proto_simulation_proto_rawDescGZIP(), []int{18}
+// Deprecated: Use CurriculumProgress.ProtoReflect.Descriptor instead.
+func (*CurriculumProgress) Descriptor() ([]byte, []int) {
+	return file_proto_simulation_proto_rawDescGZIP(), []int{58}
 }
 
-func (x *ObservationSpace) GetType() SpaceType {
+func (x *CurriculumProgress) GetStage() int32 {
 	if x != nil {
-		return x.Type
+		return x.Stage
 	}
-	return SpaceType_BOX
+	return 0
 }
 
-func (x *ObservationSpace) GetLow() []float64 {
+func (x *CurriculumProgress) GetStages() int32 {
 	if x != nil {
-		return x.Low
+		return x.Stages
 	}
-	return nil
+	return 0
 }
 
-func (x *ObservationSpace) GetHigh() []float64 {
+func (x *CurriculumProgress) GetEpisodes() int64 {
 	if x != nil {
-		return x.High
+		return x.Episodes
 	}
-	return nil
+	return 0
 }
 
-func (x *ObservationSpace) GetShape() []int32 {
+func (x *CurriculumProgress) GetStageEpisodes() int64 {
 	if x != nil {
-		return x.Shape
+		return x.StageEpisodes
 	}
-	return nil
+	return 0
 }
 
-func (x *ObservationSpace) GetDtype() string {
+func (x *CurriculumProgress) GetSuccessRate() float64 {
 	if x != nil {
-		return x.Dtype
+		return x.SuccessRate
 	}
-	return ""
+	return 0
+}
+
+func (x *CurriculumProgress) GetWindow() int32 {
+	if x != nil {
+		return x.Window
+	}
+	return 0
+}
+
+func (x *CurriculumProgress) GetFrozen() bool {
+	if x != nil {
+		return x.Frozen
+	}
+	return false
+}
+
+func (x *CurriculumProgress) GetParameters() map[string]float64 {
+	if x != nil {
+		return x.Parameters
+	}
+	return nil
 }
 
 var File_proto_simulation_proto protoreflect.FileDescriptor
@@ -1253,41 +3954,131 @@ const file_proto_simulation_proto_rawDesc = "" +
 	"\n" +
 	"\x16proto/simulation.proto\x12\n" +
 	"simulation\x1a\x1cgoogle/protobuf/struct.proto\"\x10\n" +
-	"\x0eGetInfoRequest\"\xa3\x01\n" +
+	"\x0eGetInfoRequest\"\xb6\x04\n" +
 	"\x0fGetInfoResponse\x12\x1c\n" +
 	"\tscenarios\x18\x01 \x03(\tR\tscenarios\x12\x17\n" +
 	"\aenv_ids\x18\x02 \x03(\tR\x06envIds\x12+\n" +
 	"\x04info\x18\x03 \x01(\v2\x17.google.protobuf.StructR\x04info\x12\x18\n" +
 	"\aversion\x18\x04 \x01(\tR\aversion\x12\x12\n" +
-	"\x04name\x18\x05 \x01(\tR\x04name\"~\n" +
+	"\x04name\x18\x05 \x01(\tR\x04name\x12O\n" +
+	"\fstep_latency\x18\x06 \x03(\v2,.simulation.GetInfoResponse.StepLatencyEntryR\vstepLatency\x12I\n" +
+	"\n" +
+	"typed_info\x18\a \x03(\v2*.simulation.GetInfoResponse.TypedInfoEntryR\ttypedInfo\x12\x1f\n" +
+	"\vapi_version\x18\b \x01(\x05R\n" +
+	"apiVersion\x12&\n" +
+	"\x0fmin_api_version\x18\t \x01(\x05R\rminApiVersion\x1a[\n" +
+	"\x10StepLatencyEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x121\n" +
+	"\x05value\x18\x02 \x01(\v2\x1b.simulation.ScenarioLatencyR\x05value:\x028\x01\x1aO\n" +
+	"\x0eTypedInfoEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12'\n" +
+	"\x05value\x18\x02 \x01(\v2\x11.simulation.ValueR\x05value:\x028\x01\"w\n" +
+	"\x0fScenarioLatency\x12.\n" +
+	"\x04step\x18\x01 \x01(\v2\x1a.simulation.LatencySummaryR\x04step\x124\n" +
+	"\arequest\x18\x02 \x01(\v2\x1a.simulation.LatencySummaryR\arequest\"\xba\x01\n" +
+	"\x0eLatencySummary\x12\x14\n" +
+	"\x05count\x18\x01 \x01(\x03R\x05count\x12\x17\n" +
+	"\amean_ms\x18\x02 \x01(\x01R\x06meanMs\x12\x15\n" +
+	"\x06p50_ms\x18\x03 \x01(\x01R\x05p50Ms\x12\x15\n" +
+	"\x06p95_ms\x18\x04 \x01(\x01R\x05p95Ms\x12\x15\n" +
+	"\x06p99_ms\x18\x05 \x01(\x01R\x05p99Ms\x12\x15\n" +
+	"\x06max_ms\x18\x06 \x01(\x01R\x05maxMs\x12\x1d\n" +
+	"\n" +
+	"per_second\x18\a \x01(\x01R\tperSecond\"\x9f\x01\n" +
 	"\x18CreateEnvironmentRequest\x12\x15\n" +
 	"\x06env_id\x18\x01 \x01(\tR\x05envId\x12\x1a\n" +
 	"\bscenario\x18\x02 \x01(\tR\bscenario\x12/\n" +
-	"\x06config\x18\x03 \x01(\v2\x17.google.protobuf.StructR\x06config\"O\n" +
+	"\x06config\x18\x03 \x01(\v2\x17.google.protobuf.StructR\x06config\x12\x1f\n" +
+	"\vapi_version\x18\x04 \x01(\x05R\n" +
+	"apiVersion\"x\n" +
 	"\x19CreateEnvironmentResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\"0\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12'\n" +
+	"\x05error\x18\x03 \x01(\v2\x11.simulation.ErrorR\x05error\"\xab\x01\n" +
+	"\x05Error\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x128\n" +
+	"\adetails\x18\x03 \x03(\v2\x1e.simulation.Error.DetailsEntryR\adetails\x1a:\n" +
+	"\fDetailsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"0\n" +
 	"\x17ResetEnvironmentRequest\x12\x15\n" +
-	"\x06env_id\x18\x01 \x01(\tR\x05envId\"\x84\x01\n" +
+	"\x06env_id\x18\x01 \x01(\tR\x05envId\"\xe2\x03\n" +
 	"\x18ResetEnvironmentResponse\x12;\n" +
 	"\fobservations\x18\x01 \x03(\v2\x17.simulation.ObservationR\fobservations\x12+\n" +
-	"\x04info\x18\x02 \x01(\v2\x17.google.protobuf.StructR\x04info\"]\n" +
+	"\x04info\x18\x02 \x01(\v2\x17.google.protobuf.StructR\x04info\x12R\n" +
+	"\n" +
+	"typed_info\x18\x03 \x03(\v23.simulation.ResetEnvironmentResponse.TypedInfoEntryR\ttypedInfo\x12H\n" +
+	"\x06agents\x18\x04 \x03(\v20.simulation.ResetEnvironmentResponse.AgentsEntryR\x06agents\x12\x1b\n" +
+	"\tagent_ids\x18\x05 \x03(\tR\bagentIds\x1aO\n" +
+	"\x0eTypedInfoEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12'\n" +
+	"\x05value\x18\x02 \x01(\v2\x11.simulation.ValueR\x05value:\x028\x01\x1aP\n" +
+	"\vAgentsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12+\n" +
+	"\x05value\x18\x02 \x01(\v2\x15.simulation.AgentStepR\x05value:\x028\x01\"]\n" +
 	"\x16StepEnvironmentRequest\x12\x15\n" +
 	"\x06env_id\x18\x01 \x01(\tR\x05envId\x12,\n" +
-	"\aactions\x18\x02 \x03(\v2\x12.simulation.ActionR\aactions\"\xb1\x01\n" +
+	"\aactions\x18\x02 \x03(\v2\x12.simulation.ActionR\aactions\"\xe2\x05\n" +
 	"\x17StepEnvironmentResponse\x12;\n" +
 	"\fobservations\x18\x01 \x03(\v2\x17.simulation.ObservationR\fobservations\x12\x18\n" +
 	"\arewards\x18\x02 \x03(\x01R\arewards\x12\x12\n" +
 	"\x04done\x18\x03 \x03(\bR\x04done\x12+\n" +
-	"\x04info\x18\x04 \x01(\v2\x17.google.protobuf.StructR\x04info\"0\n" +
+	"\x04info\x18\x04 \x01(\v2\x17.google.protobuf.StructR\x04info\x12Q\n" +
+	"\n" +
+	"typed_info\x18\x05 \x03(\v22.simulation.StepEnvironmentResponse.TypedInfoEntryR\ttypedInfo\x12\x1e\n" +
+	"\n" +
+	"terminated\x18\x06 \x03(\bR\n" +
+	"terminated\x12\x1c\n" +
+	"\ttruncated\x18\a \x03(\bR\ttruncated\x121\n" +
+	"\tstep_type\x18\b \x03(\x0e2\x14.simulation.StepTypeR\bstepType\x12\x1a\n" +
+	"\bdiscount\x18\t \x03(\x01R\bdiscount\x12G\n" +
+	"\x06agents\x18\n" +
+	" \x03(\v2/.simulation.StepEnvironmentResponse.AgentsEntryR\x06agents\x12\x1b\n" +
+	"\tagent_ids\x18\v \x03(\tR\bagentIds\x12F\n" +
+	"\x12final_observations\x18\f \x03(\v2\x17.simulation.ObservationR\x11finalObservations\x1aO\n" +
+	"\x0eTypedInfoEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12'\n" +
+	"\x05value\x18\x02 \x01(\v2\x11.simulation.ValueR\x05value:\x028\x01\x1aP\n" +
+	"\vAgentsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12+\n" +
+	"\x05value\x18\x02 \x01(\v2\x15.simulation.AgentStepR\x05value:\x028\x01\"\x9c\x01\n" +
+	"\tAgentStep\x129\n" +
+	"\vobservation\x18\x01 \x01(\v2\x17.simulation.ObservationR\vobservation\x12\x16\n" +
+	"\x06reward\x18\x02 \x01(\x01R\x06reward\x12\x1e\n" +
+	"\n" +
+	"terminated\x18\x03 \x01(\bR\n" +
+	"terminated\x12\x1c\n" +
+	"\ttruncated\x18\x04 \x01(\bR\ttruncated\"0\n" +
 	"\x17CloseEnvironmentRequest\x12\x15\n" +
-	"\x06env_id\x18\x01 \x01(\tR\x05envId\"N\n" +
+	"\x06env_id\x18\x01 \x01(\tR\x05envId\"w\n" +
 	"\x18CloseEnvironmentResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\"V\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12'\n" +
+	"\x05error\x18\x03 \x01(\v2\x11.simulation.ErrorR\x05error\"\xf1\x02\n" +
 	"\vObservation\x12\x12\n" +
 	"\x04data\x18\x01 \x03(\x01R\x04data\x123\n" +
-	"\bmetadata\x18\x02 \x01(\v2\x17.google.protobuf.StructR\bmetadata\"\xdd\x02\n" +
+	"\bmetadata\x18\x02 \x01(\v2\x17.google.protobuf.StructR\bmetadata\x12\x19\n" +
+	"\bdata_f32\x18\x03 \x03(\x02R\adataF32\x12Q\n" +
+	"\x0etyped_metadata\x18\x04 \x03(\v2*.simulation.Observation.TypedMetadataEntryR\rtypedMetadata\x12\x12\n" +
+	"\x04text\x18\x05 \x01(\tR\x04text\x12'\n" +
+	"\x05image\x18\x06 \x01(\v2\x11.simulation.ImageR\x05image\x12\x19\n" +
+	"\bagent_id\x18\a \x01(\tR\aagentId\x1aS\n" +
+	"\x12TypedMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12'\n" +
+	"\x05value\x18\x02 \x01(\v2\x11.simulation.ValueR\x05value:\x028\x01\"i\n" +
+	"\x05Image\x12\x16\n" +
+	"\x06pixels\x18\x01 \x01(\fR\x06pixels\x12\x16\n" +
+	"\x06height\x18\x02 \x01(\x05R\x06height\x12\x14\n" +
+	"\x05width\x18\x03 \x01(\x05R\x05width\x12\x1a\n" +
+	"\bchannels\x18\x04 \x01(\x05R\bchannels\"\x99\x01\n" +
+	"\x05Value\x12#\n" +
+	"\fdouble_value\x18\x01 \x01(\x01H\x00R\vdoubleValue\x12\x1d\n" +
+	"\tint_value\x18\x02 \x01(\x03H\x00R\bintValue\x12\x1f\n" +
+	"\n" +
+	"bool_value\x18\x03 \x01(\bH\x00R\tboolValue\x12#\n" +
+	"\fstring_value\x18\x04 \x01(\tH\x00R\vstringValueB\x06\n" +
+	"\x04kind\"\xbf\x03\n" +
 	"\x06Action\x12!\n" +
 	"\vfloat_value\x18\x01 \x01(\x01H\x00R\n" +
 	"floatValue\x12\x1d\n" +
@@ -1300,8 +4091,22 @@ const file_proto_simulation_proto_rawDesc = "" +
 	"\n" +
 	"bool_array\x18\x06 \x01(\v2\x15.simulation.BoolArrayH\x00R\tboolArray\x12#\n" +
 	"\fstring_value\x18\a \x01(\tH\x00R\vstringValue\x12\x1b\n" +
-	"\braw_data\x18\b \x01(\fH\x00R\arawDataB\x06\n" +
-	"\x04data\"$\n" +
+	"\braw_data\x18\b \x01(\fH\x00R\arawData\x12,\n" +
+	"\x04dict\x18\t \x01(\v2\x16.simulation.ActionDictH\x00R\x04dict\x122\n" +
+	"\x06hybrid\x18\n" +
+	" \x01(\v2\x18.simulation.HybridActionH\x00R\x06hybridB\x06\n" +
+	"\x04data\"F\n" +
+	"\fHybridAction\x12\x16\n" +
+	"\x06choice\x18\x01 \x01(\x03R\x06choice\x12\x1e\n" +
+	"\n" +
+	"parameters\x18\x02 \x03(\x01R\n" +
+	"parameters\"\x9b\x01\n" +
+	"\n" +
+	"ActionDict\x12=\n" +
+	"\aactions\x18\x01 \x03(\v2#.simulation.ActionDict.ActionsEntryR\aactions\x1aN\n" +
+	"\fActionsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12(\n" +
+	"\x05value\x18\x02 \x01(\v2\x12.simulation.ActionR\x05value:\x028\x01\"$\n" +
 	"\n" +
 	"FloatArray\x12\x16\n" +
 	"\x06values\x18\x01 \x03(\x01R\x06values\"\"\n" +
@@ -1310,36 +4115,205 @@ const file_proto_simulation_proto_rawDesc = "" +
 	"\tBoolArray\x12\x16\n" +
 	"\x06values\x18\x01 \x03(\bR\x06values\")\n" +
 	"\x10GetSpacesRequest\x12\x15\n" +
-	"\x06env_id\x18\x01 \x01(\tR\x05envId\"\x9a\x01\n" +
+	"\x06env_id\x18\x01 \x01(\tR\x05envId\"\xbb\x01\n" +
 	"\x11GetSpacesResponse\x12:\n" +
 	"\faction_space\x18\x01 \x01(\v2\x17.simulation.ActionSpaceR\vactionSpace\x12I\n" +
-	"\x11observation_space\x18\x02 \x01(\v2\x1c.simulation.ObservationSpaceR\x10observationSpace\"\xb3\x01\n" +
+	"\x11observation_space\x18\x02 \x01(\v2\x1c.simulation.ObservationSpaceR\x10observationSpace\x12\x1f\n" +
+	"\vspaces_json\x18\x03 \x01(\tR\n" +
+	"spacesJson\"\xca\x03\n" +
 	"\vActionSpace\x12)\n" +
 	"\x04type\x18\x01 \x01(\x0e2\x15.simulation.SpaceTypeR\x04type\x12\x10\n" +
 	"\x03low\x18\x02 \x03(\x01R\x03low\x12\x12\n" +
 	"\x04high\x18\x03 \x03(\x01R\x04high\x12\x14\n" +
 	"\x05shape\x18\x04 \x03(\x05R\x05shape\x12\x14\n" +
 	"\x05dtype\x18\x05 \x01(\tR\x05dtype\x12'\n" +
-	"\x0fdiscrete_values\x18\x06 \x03(\x01R\x0ediscreteValues\"\x8f\x01\n" +
+	"\x0fdiscrete_values\x18\x06 \x03(\x01R\x0ediscreteValues\x12\x12\n" +
+	"\x04nvec\x18\a \x03(\x03R\x04nvec\x12\x1d\n" +
+	"\n" +
+	"max_length\x18\b \x01(\x05R\tmaxLength\x12\x18\n" +
+	"\acharset\x18\t \x01(\tR\acharset\x12;\n" +
+	"\x06spaces\x18\n" +
+	" \x03(\v2#.simulation.ActionSpace.SpacesEntryR\x06spaces\x127\n" +
+	"\n" +
+	"parameters\x18\v \x03(\v2\x17.simulation.ActionSpaceR\n" +
+	"parameters\x1aR\n" +
+	"\vSpacesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12-\n" +
+	"\x05value\x18\x02 \x01(\v2\x17.simulation.ActionSpaceR\x05value:\x028\x01\"\xc8\x01\n" +
 	"\x10ObservationSpace\x12)\n" +
 	"\x04type\x18\x01 \x01(\x0e2\x15.simulation.SpaceTypeR\x04type\x12\x10\n" +
 	"\x03low\x18\x02 \x03(\x01R\x03low\x12\x12\n" +
 	"\x04high\x18\x03 \x03(\x01R\x04high\x12\x14\n" +
 	"\x05shape\x18\x04 \x03(\x05R\x05shape\x12\x14\n" +
-	"\x05dtype\x18\x05 \x01(\tR\x05dtype*\\\n" +
+	"\x05dtype\x18\x05 \x01(\tR\x05dtype\x12\x1d\n" +
+	"\n" +
+	"max_length\x18\x06 \x01(\x05R\tmaxLength\x12\x18\n" +
+	"\acharset\x18\a \x01(\tR\acharset\"+\n" +
+	"\x12GetMetadataRequest\x12\x15\n" +
+	"\x06env_id\x18\x01 \x01(\tR\x05envId\"\xb3\x01\n" +
+	"\x13GetMetadataResponse\x12!\n" +
+	"\freward_range\x18\x01 \x03(\x01R\vrewardRange\x12*\n" +
+	"\x11max_episode_steps\x18\x02 \x01(\x05R\x0fmaxEpisodeSteps\x12!\n" +
+	"\frender_modes\x18\x03 \x03(\tR\vrenderModes\x12*\n" +
+	"\x10nondeterministic\x18\x04 \x01(\bR\x10nondeterministic\"0\n" +
+	"\x17DebugEnvironmentRequest\x12\x15\n" +
+	"\x06env_id\x18\x01 \x01(\tR\x05envId\"7\n" +
+	"\x18DebugEnvironmentResponse\x12\x1b\n" +
+	"\tdump_json\x18\x01 \x01(\tR\bdumpJson\"\xdf\x01\n" +
+	"\x15EvaluatePolicyRequest\x12\x1a\n" +
+	"\bscenario\x18\x01 \x01(\tR\bscenario\x12/\n" +
+	"\x06config\x18\x02 \x01(\v2\x17.google.protobuf.StructR\x06config\x12\x14\n" +
+	"\x05model\x18\x03 \x01(\fR\x05model\x12\x1a\n" +
+	"\bepisodes\x18\x04 \x01(\x05R\bepisodes\x12\x1b\n" +
+	"\tmax_steps\x18\x05 \x01(\x05R\bmaxSteps\x12\x16\n" +
+	"\x06policy\x18\x06 \x01(\tR\x06policy\x12\x12\n" +
+	"\x04seed\x18\a \x01(\x03R\x04seed\"\x95\x02\n" +
+	"\x16EvaluatePolicyResponse\x12\x18\n" +
+	"\areturns\x18\x01 \x03(\x01R\areturns\x12\x18\n" +
+	"\alengths\x18\x02 \x03(\x05R\alengths\x12\x1c\n" +
+	"\ttruncated\x18\x03 \x01(\x05R\ttruncated\x12\x1f\n" +
+	"\vmean_return\x18\x04 \x01(\x01R\n" +
+	"meanReturn\x12\x1d\n" +
+	"\n" +
+	"std_return\x18\x05 \x01(\x01R\tstdReturn\x12\x1f\n" +
+	"\vmean_length\x18\x06 \x01(\x01R\n" +
+	"meanLength\x12\x1f\n" +
+	"\vtotal_steps\x18\a \x01(\x03R\n" +
+	"totalSteps\x12'\n" +
+	"\x0felapsed_seconds\x18\b \x01(\x01R\x0eelapsedSeconds\"v\n" +
+	"\x12OpenSessionRequest\x12\x16\n" +
+	"\x06client\x18\x01 \x01(\tR\x06client\x12\x1f\n" +
+	"\vttl_seconds\x18\x02 \x01(\x05R\n" +
+	"ttlSeconds\x12'\n" +
+	"\x0fbind_connection\x18\x03 \x01(\bR\x0ebindConnection\"U\n" +
+	"\x13OpenSessionResponse\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x1f\n" +
+	"\vttl_seconds\x18\x02 \x01(\x05R\n" +
+	"ttlSeconds\"4\n" +
+	"\x13CloseSessionRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\"G\n" +
+	"\x14CloseSessionResponse\x12/\n" +
+	"\x13closed_environments\x18\x01 \x01(\x05R\x12closedEnvironments\"\xa1\x02\n" +
+	"\x11EnvironmentStatus\x12\x15\n" +
+	"\x06env_id\x18\x01 \x01(\tR\x05envId\x12\x1a\n" +
+	"\bscenario\x18\x02 \x01(\tR\bscenario\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x03 \x01(\tR\tsessionId\x12\x16\n" +
+	"\x06client\x18\x04 \x01(\tR\x06client\x12\x1f\n" +
+	"\vage_seconds\x18\x05 \x01(\x01R\n" +
+	"ageSeconds\x12!\n" +
+	"\fidle_seconds\x18\x06 \x01(\x01R\vidleSeconds\x12\x14\n" +
+	"\x05steps\x18\a \x01(\x03R\x05steps\x12\x1a\n" +
+	"\bepisodes\x18\b \x01(\x03R\bepisodes\x12\x16\n" +
+	"\x06tenant\x18\t \x01(\tR\x06tenant\x12\x14\n" +
+	"\x05fault\x18\n" +
+	" \x01(\tR\x05fault\"\x19\n" +
+	"\x17ListEnvironmentsRequest\"y\n" +
+	"\x18ListEnvironmentsResponse\x12A\n" +
+	"\fenvironments\x18\x01 \x03(\v2\x1d.simulation.EnvironmentStatusR\fenvironments\x12\x1a\n" +
+	"\bdraining\x18\x02 \x01(\bR\bdraining\"5\n" +
+	"\x1cForceCloseEnvironmentRequest\x12\x15\n" +
+	"\x06env_id\x18\x01 \x01(\tR\x05envId\"|\n" +
+	"\x1dForceCloseEnvironmentResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12'\n" +
+	"\x05error\x18\x03 \x01(\v2\x11.simulation.ErrorR\x05error\"4\n" +
+	"\x1bDumpEnvironmentStateRequest\x12\x15\n" +
+	"\x06env_id\x18\x01 \x01(\tR\x05envId\"=\n" +
+	"\x1cDumpEnvironmentStateResponse\x12\x1d\n" +
+	"\n" +
+	"state_json\x18\x01 \x01(\tR\tstateJson\"M\n" +
+	"\fDrainRequest\x12'\n" +
+	"\x0ftimeout_seconds\x18\x01 \x01(\x01R\x0etimeoutSeconds\x12\x14\n" +
+	"\x05force\x18\x02 \x01(\bR\x05force\"w\n" +
+	"\rDrainResponse\x125\n" +
+	"\x16remaining_environments\x18\x01 \x01(\x05R\x15remainingEnvironments\x12/\n" +
+	"\x13closed_environments\x18\x02 \x01(\x05R\x12closedEnvironments\"I\n" +
+	"\x18ExportEnvironmentRequest\x12\x15\n" +
+	"\x06env_id\x18\x01 \x01(\tR\x05envId\x12\x16\n" +
+	"\x06detach\x18\x02 \x01(\bR\x06detach\"[\n" +
+	"\x19ExportEnvironmentResponse\x12\x1a\n" +
+	"\bsnapshot\x18\x01 \x01(\fR\bsnapshot\x12\"\n" +
+	"\fenvironments\x18\x02 \x01(\x05R\fenvironments\"6\n" +
+	"\x18ImportEnvironmentRequest\x12\x1a\n" +
+	"\bsnapshot\x18\x01 \x01(\fR\bsnapshot\"?\n" +
+	"\x19ImportEnvironmentResponse\x12\"\n" +
+	"\fenvironments\x18\x01 \x01(\x05R\fenvironments\"J\n" +
+	"\x19MigrateEnvironmentRequest\x12\x15\n" +
+	"\x06env_id\x18\x01 \x01(\tR\x05envId\x12\x16\n" +
+	"\x06worker\x18\x02 \x01(\tR\x06worker\"Q\n" +
+	"\x1aMigrateEnvironmentResponse\x123\n" +
+	"\x15migrated_environments\x18\x01 \x01(\x05R\x14migratedEnvironments\",\n" +
+	"\x12DrainWorkerRequest\x12\x16\n" +
+	"\x06worker\x18\x01 \x01(\tR\x06worker\"\x81\x01\n" +
+	"\x13DrainWorkerResponse\x123\n" +
+	"\x15migrated_environments\x18\x01 \x01(\x05R\x14migratedEnvironments\x125\n" +
+	"\x16remaining_environments\x18\x02 \x01(\x05R\x15remainingEnvironments\"c\n" +
+	"\x17RegisterScenarioRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x12\n" +
+	"\x04wasm\x18\x03 \x01(\fR\x04wasm\"*\n" +
+	"\x18RegisterScenarioResponseJ\x04\b\x01\x10\x02R\breplaced\"-\n" +
+	"\x14GetCurriculumRequest\x12\x15\n" +
+	"\x06env_id\x18\x01 \x01(\tR\x05envId\"`\n" +
+	"\x19SetCurriculumStageRequest\x12\x15\n" +
+	"\x06env_id\x18\x01 \x01(\tR\x05envId\x12\x14\n" +
+	"\x05stage\x18\x02 \x01(\x05R\x05stage\x12\x16\n" +
+	"\x06frozen\x18\x03 \x01(\bR\x06frozen\"\xe7\x02\n" +
+	"\x12CurriculumProgress\x12\x14\n" +
+	"\x05stage\x18\x01 \x01(\x05R\x05stage\x12\x16\n" +
+	"\x06stages\x18\x02 \x01(\x05R\x06stages\x12\x1a\n" +
+	"\bepisodes\x18\x03 \x01(\x03R\bepisodes\x12%\n" +
+	"\x0estage_episodes\x18\x04 \x01(\x03R\rstageEpisodes\x12!\n" +
+	"\fsuccess_rate\x18\x05 \x01(\x01R\vsuccessRate\x12\x16\n" +
+	"\x06window\x18\x06 \x01(\x05R\x06window\x12\x16\n" +
+	"\x06frozen\x18\a \x01(\bR\x06frozen\x12N\n" +
+	"\n" +
+	"parameters\x18\b \x03(\v2..simulation.CurriculumProgress.ParametersEntryR\n" +
+	"parameters\x1a=\n" +
+	"\x0fParametersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01*\x87\x01\n" +
 	"\tSpaceType\x12\a\n" +
 	"\x03BOX\x10\x00\x12\f\n" +
 	"\bDISCRETE\x10\x01\x12\x12\n" +
 	"\x0eMULTI_DISCRETE\x10\x02\x12\x10\n" +
 	"\fMULTI_BINARY\x10\x03\x12\x12\n" +
-	"\x0eDISCRETE_FLOAT\x10\x042\xf8\x04\n" +
+	"\x0eDISCRETE_FLOAT\x10\x04\x12\b\n" +
+	"\x04TEXT\x10\x05\x12\t\n" +
+	"\x05IMAGE\x10\x06\x12\b\n" +
+	"\x04DICT\x10\a\x12\n" +
+	"\n" +
+	"\x06HYBRID\x10\b*(\n" +
+	"\bStepType\x12\t\n" +
+	"\x05FIRST\x10\x00\x12\a\n" +
+	"\x03MID\x10\x01\x12\b\n" +
+	"\x04LAST\x10\x022\xa1\x10\n" +
 	"\x11SimulationService\x12B\n" +
 	"\aGetInfo\x12\x1a.simulation.GetInfoRequest\x1a\x1b.simulation.GetInfoResponse\x12`\n" +
 	"\x11CreateEnvironment\x12$.simulation.CreateEnvironmentRequest\x1a%.simulation.CreateEnvironmentResponse\x12]\n" +
 	"\x10ResetEnvironment\x12#.simulation.ResetEnvironmentRequest\x1a$.simulation.ResetEnvironmentResponse\x12Z\n" +
 	"\x0fStepEnvironment\x12\".simulation.StepEnvironmentRequest\x1a#.simulation.StepEnvironmentResponse\x12]\n" +
 	"\x10CloseEnvironment\x12#.simulation.CloseEnvironmentRequest\x1a$.simulation.CloseEnvironmentResponse\x12H\n" +
-	"\tGetSpaces\x12\x1c.simulation.GetSpacesRequest\x1a\x1d.simulation.GetSpacesResponse\x12Y\n" +
+	"\tGetSpaces\x12\x1c.simulation.GetSpacesRequest\x1a\x1d.simulation.GetSpacesResponse\x12N\n" +
+	"\vGetMetadata\x12\x1e.simulation.GetMetadataRequest\x1a\x1f.simulation.GetMetadataResponse\x12]\n" +
+	"\x10DebugEnvironment\x12#.simulation.DebugEnvironmentRequest\x1a$.simulation.DebugEnvironmentResponse\x12W\n" +
+	"\x0eEvaluatePolicy\x12!.simulation.EvaluatePolicyRequest\x1a\".simulation.EvaluatePolicyResponse\x12N\n" +
+	"\vOpenSession\x12\x1e.simulation.OpenSessionRequest\x1a\x1f.simulation.OpenSessionResponse\x12Q\n" +
+	"\fCloseSession\x12\x1f.simulation.CloseSessionRequest\x1a .simulation.CloseSessionResponse\x12]\n" +
+	"\x10ListEnvironments\x12#.simulation.ListEnvironmentsRequest\x1a$.simulation.ListEnvironmentsResponse\x12l\n" +
+	"\x15ForceCloseEnvironment\x12(.simulation.ForceCloseEnvironmentRequest\x1a).simulation.ForceCloseEnvironmentResponse\x12i\n" +
+	"\x14DumpEnvironmentState\x12'.simulation.DumpEnvironmentStateRequest\x1a(.simulation.DumpEnvironmentStateResponse\x12<\n" +
+	"\x05Drain\x12\x18.simulation.DrainRequest\x1a\x19.simulation.DrainResponse\x12`\n" +
+	"\x11ExportEnvironment\x12$.simulation.ExportEnvironmentRequest\x1a%.simulation.ExportEnvironmentResponse\x12`\n" +
+	"\x11ImportEnvironment\x12$.simulation.ImportEnvironmentRequest\x1a%.simulation.ImportEnvironmentResponse\x12c\n" +
+	"\x12MigrateEnvironment\x12%.simulation.MigrateEnvironmentRequest\x1a&.simulation.MigrateEnvironmentResponse\x12N\n" +
+	"\vDrainWorker\x12\x1e.simulation.DrainWorkerRequest\x1a\x1f.simulation.DrainWorkerResponse\x12]\n" +
+	"\x10RegisterScenario\x12#.simulation.RegisterScenarioRequest\x1a$.simulation.RegisterScenarioResponse\x12Q\n" +
+	"\rGetCurriculum\x12 .simulation.GetCurriculumRequest\x1a\x1e.simulation.CurriculumProgress\x12[\n" +
+	"\x12SetCurriculumStage\x12%.simulation.SetCurriculumStageRequest\x1a\x1e.simulation.CurriculumProgress\x12Y\n" +
 	"\n" +
 	"StreamStep\x12\".simulation.StepEnvironmentRequest\x1a#.simulation.StepEnvironmentResponse(\x010\x01B2Z0github.com/jelech/rl_env_engine/proto/simulationb\x06proto3"
 
@@ -1355,66 +4329,184 @@ func file_proto_simulation_proto_rawDescGZIP() []byte {
 	return file_proto_simulation_proto_rawDescData
 }
 
-var file_proto_simulation_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_proto_simulation_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
+var file_proto_simulation_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_proto_simulation_proto_msgTypes = make([]protoimpl.MessageInfo, 70)
 var file_proto_simulation_proto_goTypes = []any{
-	(SpaceType)(0),                    // 0: simulation.SpaceType
-	(*GetInfoRequest)(nil),            // 1: simulation.GetInfoRequest
-	(*GetInfoResponse)(nil),           // 2: simulation.GetInfoResponse
-	(*CreateEnvironmentRequest)(nil),  // 3: simulation.CreateEnvironmentRequest
-	(*CreateEnvironmentResponse)(nil), // 4: simulation.CreateEnvironmentResponse
-	(*ResetEnvironmentRequest)(nil),   // 5: simulation.ResetEnvironmentRequest
-	(*ResetEnvironmentResponse)(nil),  // 6: simulation.ResetEnvironmentResponse
-	(*StepEnvironmentRequest)(nil),    // 7: simulation.StepEnvironmentRequest
-	(*StepEnvironmentResponse)(nil),   // 8: simulation.StepEnvironmentResponse
-	(*CloseEnvironmentRequest)(nil),   // 9: simulation.CloseEnvironmentRequest
-	(*CloseEnvironmentResponse)(nil),  // 10: simulation.CloseEnvironmentResponse
-	(*Observation)(nil),               // 11: simulation.Observation
-	(*Action)(nil),                    // 12: simulation.Action
-	(*FloatArray)(nil),                // 13: simulation.FloatArray
-	(*IntArray)(nil),                  // 14: simulation.IntArray
-	(*BoolArray)(nil),                 // 15: simulation.BoolArray
-	(*GetSpacesRequest)(nil),          // 16: simulation.GetSpacesRequest
-	(*GetSpacesResponse)(nil),         // 17: simulation.GetSpacesResponse
-	(*ActionSpace)(nil),               // 18: simulation.ActionSpace
-	(*ObservationSpace)(nil),          // 19: simulation.ObservationSpace
-	(*structpb.Struct)(nil),           // 20: google.protobuf.Struct
+	(SpaceType)(0),                        // 0: simulation.SpaceType
+	(StepType)(0),                         // 1: simulation.StepType
+	(*GetInfoRequest)(nil),                // 2: simulation.GetInfoRequest
+	(*GetInfoResponse)(nil),               // 3: simulation.GetInfoResponse
+	(*ScenarioLatency)(nil),               // 4: simulation.ScenarioLatency
+	(*LatencySummary)(nil),                // 5: simulation.LatencySummary
+	(*CreateEnvironmentRequest)(nil),      // 6: simulation.CreateEnvironmentRequest
+	(*CreateEnvironmentResponse)(nil),     // 7: simulation.CreateEnvironmentResponse
+	(*Error)(nil),                         // 8: simulation.Error
+	(*ResetEnvironmentRequest)(nil),       // 9: simulation.ResetEnvironmentRequest
+	(*ResetEnvironmentResponse)(nil),      // 10: simulation.ResetEnvironmentResponse
+	(*StepEnvironmentRequest)(nil),        // 11: simulation.StepEnvironmentRequest
+	(*StepEnvironmentResponse)(nil),       // 12: simulation.StepEnvironmentResponse
+	(*AgentStep)(nil),                     // 13: simulation.AgentStep
+	(*CloseEnvironmentRequest)(nil),       // 14: simulation.CloseEnvironmentRequest
+	(*CloseEnvironmentResponse)(nil),      // 15: simulation.CloseEnvironmentResponse
+	(*Observation)(nil),                   // 16: simulation.Observation
+	(*Image)(nil),                         // 17: simulation.Image
+	(*Value)(nil),                         // 18: simulation.Value
+	(*Action)(nil),                        // 19: simulation.Action
+	(*HybridAction)(nil),                  // 20: simulation.HybridAction
+	(*ActionDict)(nil),                    // 21: simulation.ActionDict
+	(*FloatArray)(nil),                    // 22: simulation.FloatArray
+	(*IntArray)(nil),                      // 23: simulation.IntArray
+	(*BoolArray)(nil),                     // 24: simulation.BoolArray
+	(*GetSpacesRequest)(nil),              // 25: simulation.GetSpacesRequest
+	(*GetSpacesResponse)(nil),             // 26: simulation.GetSpacesResponse
+	(*ActionSpace)(nil),                   // 27: simulation.ActionSpace
+	(*ObservationSpace)(nil),              // 28: simulation.ObservationSpace
+	(*GetMetadataRequest)(nil),            // 29: simulation.GetMetadataRequest
+	(*GetMetadataResponse)(nil),           // 30: simulation.GetMetadataResponse
+	(*DebugEnvironmentRequest)(nil),       // 31: simulation.DebugEnvironmentRequest
+	(*DebugEnvironmentResponse)(nil),      // 32: simulation.DebugEnvironmentResponse
+	(*EvaluatePolicyRequest)(nil),         // 33: simulation.EvaluatePolicyRequest
+	(*EvaluatePolicyResponse)(nil),        // 34: simulation.EvaluatePolicyResponse
+	(*OpenSessionRequest)(nil),            // 35: simulation.OpenSessionRequest
+	(*OpenSessionResponse)(nil),           // 36: simulation.OpenSessionResponse
+	(*CloseSessionRequest)(nil),           // 37: simulation.CloseSessionRequest
+	(*CloseSessionResponse)(nil),          // 38: simulation.CloseSessionResponse
+	(*EnvironmentStatus)(nil),             // 39: simulation.EnvironmentStatus
+	(*ListEnvironmentsRequest)(nil),       // 40: simulation.ListEnvironmentsRequest
+	(*ListEnvironmentsResponse)(nil),      // 41: simulation.ListEnvironmentsResponse
+	(*ForceCloseEnvironmentRequest)(nil),  // 42: simulation.ForceCloseEnvironmentRequest
+	(*ForceCloseEnvironmentResponse)(nil), // 43: simulation.ForceCloseEnvironmentResponse
+	(*DumpEnvironmentStateRequest)(nil),   // 44: simulation.DumpEnvironmentStateRequest
+	(*DumpEnvironmentStateResponse)(nil),  // 45: simulation.DumpEnvironmentStateResponse
+	(*DrainRequest)(nil),                  // 46: simulation.DrainRequest
+	(*DrainResponse)(nil),                 // 47: simulation.DrainResponse
+	(*ExportEnvironmentRequest)(nil),      // 48: simulation.ExportEnvironmentRequest
+	(*ExportEnvironmentResponse)(nil),     // 49: simulation.ExportEnvironmentResponse
+	(*ImportEnvironmentRequest)(nil),      // 50: simulation.ImportEnvironmentRequest
+	(*ImportEnvironmentResponse)(nil),     // 51: simulation.ImportEnvironmentResponse
+	(*MigrateEnvironmentRequest)(nil),     // 52: simulation.MigrateEnvironmentRequest
+	(*MigrateEnvironmentResponse)(nil),    // 53: simulation.MigrateEnvironmentResponse
+	(*DrainWorkerRequest)(nil),            // 54: simulation.DrainWorkerRequest
+	(*DrainWorkerResponse)(nil),           // 55: simulation.DrainWorkerResponse
+	(*RegisterScenarioRequest)(nil),       // 56: simulation.RegisterScenarioRequest
+	(*RegisterScenarioResponse)(nil),      // 57: simulation.RegisterScenarioResponse
+	(*GetCurriculumRequest)(nil),          // 58: simulation.GetCurriculumRequest
+	(*SetCurriculumStageRequest)(nil),     // 59: simulation.SetCurriculumStageRequest
+	(*CurriculumProgress)(nil),            // 60: simulation.CurriculumProgress
+	nil,                                   // 61: simulation.GetInfoResponse.StepLatencyEntry
+	nil,                                   // 62: simulation.GetInfoResponse.TypedInfoEntry
+	nil,                                   // 63: simulation.Error.DetailsEntry
+	nil,                                   // 64: simulation.ResetEnvironmentResponse.TypedInfoEntry
+	nil,                                   // 65: simulation.ResetEnvironmentResponse.AgentsEntry
+	nil,                                   // 66: simulation.StepEnvironmentResponse.TypedInfoEntry
+	nil,                                   // 67: simulation.StepEnvironmentResponse.AgentsEntry
+	nil,                                   // 68: simulation.Observation.TypedMetadataEntry
+	nil,                                   // 69: simulation.ActionDict.ActionsEntry
+	nil,                                   // 70: simulation.ActionSpace.SpacesEntry
+	nil,                                   // 71: simulation.CurriculumProgress.ParametersEntry
+	(*structpb.Struct)(nil),               // 72: google.protobuf.Struct
 }
 var file_proto_simulation_proto_depIdxs = []int32{
-	20, // 0: simulation.GetInfoResponse.info:type_name -> google.protobuf.Struct
-	20, // 1: simulation.CreateEnvironmentRequest.config:type_name -> google.protobuf.Struct
-	11, // 2: simulation.ResetEnvironmentResponse.observations:type_name -> simulation.Observation
-	20, // 3: simulation.ResetEnvironmentResponse.info:type_name -> google.protobuf.Struct
-	12, // 4: simulation.StepEnvironmentRequest.actions:type_name -> simulation.Action
-	11, // 5: simulation.StepEnvironmentResponse.observations:type_name -> simulation.Observation
-	20, // 6: simulation.StepEnvironmentResponse.info:type_name -> google.protobuf.Struct
-	20, // 7: simulation.Observation.metadata:type_name -> google.protobuf.Struct
-	13, // 8: simulation.Action.float_array:type_name -> simulation.FloatArray
-	14, // 9: simulation.Action.int_array:type_name -> simulation.IntArray
-	15, // 10: simulation.Action.bool_array:type_name -> simulation.BoolArray
-	18, // 11: simulation.GetSpacesResponse.action_space:type_name -> simulation.ActionSpace
-	19, // 12: simulation.GetSpacesResponse.observation_space:type_name -> simulation.ObservationSpace
-	0,  // 13: simulation.ActionSpace.type:type_name -> simulation.SpaceType
-	0,  // 14: simulation.ObservationSpace.type:type_name -> simulation.SpaceType
-	1,  // 15: simulation.SimulationService.GetInfo:input_type -> simulation.GetInfoRequest
-	3,  // 16: simulation.SimulationService.CreateEnvironment:input_type -> simulation.CreateEnvironmentRequest
-	5,  // 17: simulation.SimulationService.ResetEnvironment:input_type -> simulation.ResetEnvironmentRequest
-	7,  // 18: simulation.SimulationService.StepEnvironment:input_type -> simulation.StepEnvironmentRequest
-	9,  // 19: simulation.SimulationService.CloseEnvironment:input_type -> simulation.CloseEnvironmentRequest
-	16, // 20: simulation.SimulationService.GetSpaces:input_type -> simulation.GetSpacesRequest
-	7,  // 21: simulation.SimulationService.StreamStep:input_type -> simulation.StepEnvironmentRequest
-	2,  // 22: simulation.SimulationService.GetInfo:output_type -> simulation.GetInfoResponse
-	4,  // 23: simulation.SimulationService.CreateEnvironment:output_type -> simulation.CreateEnvironmentResponse
-	6,  // 24: simulation.SimulationService.ResetEnvironment:output_type -> simulation.ResetEnvironmentResponse
-	8,  // 25: simulation.SimulationService.StepEnvironment:output_type -> simulation.StepEnvironmentResponse
-	10, // 26: simulation.SimulationService.CloseEnvironment:output_type -> simulation.CloseEnvironmentResponse
-	17, // 27: simulation.SimulationService.GetSpaces:output_type -> simulation.GetSpacesResponse
-	8,  // 28: simulation.SimulationService.StreamStep:output_type -> simulation.StepEnvironmentResponse
-	22, // [22:29] is the sub-list for method output_type
-	15, // [15:22] is the sub-list for method input_type
-	15, // [15:15] is the sub-list for extension type_name
-	15, // [15:15] is the sub-list for extension extendee
-	0,  // [0:15] is the sub-list for field type_name
+	72, // 0: simulation.GetInfoResponse.info:type_name -> google.protobuf.Struct
+	61, // 1: simulation.GetInfoResponse.step_latency:type_name -> simulation.GetInfoResponse.StepLatencyEntry
+	62, // 2: simulation.GetInfoResponse.typed_info:type_name -> simulation.GetInfoResponse.TypedInfoEntry
+	5,  // 3: simulation.ScenarioLatency.step:type_name -> simulation.LatencySummary
+	5,  // 4: simulation.ScenarioLatency.request:type_name -> simulation.LatencySummary
+	72, // 5: simulation.CreateEnvironmentRequest.config:type_name -> google.protobuf.Struct
+	8,  // 6: simulation.CreateEnvironmentResponse.error:type_name -> simulation.Error
+	63, // 7: simulation.Error.details:type_name -> simulation.Error.DetailsEntry
+	16, // 8: simulation.ResetEnvironmentResponse.observations:type_name -> simulation.Observation
+	72, // 9: simulation.ResetEnvironmentResponse.info:type_name -> google.protobuf.Struct
+	64, // 10: simulation.ResetEnvironmentResponse.typed_info:type_name -> simulation.ResetEnvironmentResponse.TypedInfoEntry
+	65, // 11: simulation.ResetEnvironmentResponse.agents:type_name -> simulation.ResetEnvironmentResponse.AgentsEntry
+	19, // 12: simulation.StepEnvironmentRequest.actions:type_name -> simulation.Action
+	16, // 13: simulation.StepEnvironmentResponse.observations:type_name -> simulation.Observation
+	72, // 14: simulation.StepEnvironmentResponse.info:type_name -> google.protobuf.Struct
+	66, // 15: simulation.StepEnvironmentResponse.typed_info:type_name -> simulation.StepEnvironmentResponse.TypedInfoEntry
+	1,  // 16: simulation.StepEnvironmentResponse.step_type:type_name -> simulation.StepType
+	67, // 17: simulation.StepEnvironmentResponse.agents:type_name -> simulation.StepEnvironmentResponse.AgentsEntry
+	16, // 18: simulation.StepEnvironmentResponse.final_observations:type_name -> simulation.Observation
+	16, // 19: simulation.AgentStep.observation:type_name -> simulation.Observation
+	8,  // 20: simulation.CloseEnvironmentResponse.error:type_name -> simulation.Error
+	72, // 21: simulation.Observation.metadata:type_name -> google.protobuf.Struct
+	68, // 22: simulation.Observation.typed_metadata:type_name -> simulation.Observation.TypedMetadataEntry
+	17, // 23: simulation.Observation.image:type_name -> simulation.Image
+	22, // 24: simulation.Action.float_array:type_name -> simulation.FloatArray
+	23, // 25: simulation.Action.int_array:type_name -> simulation.IntArray
+	24, // 26: simulation.Action.bool_array:type_name -> simulation.BoolArray
+	21, // 27: simulation.Action.dict:type_name -> simulation.ActionDict
+	20, // 28: simulation.Action.hybrid:type_name -> simulation.HybridAction
+	69, // 29: simulation.ActionDict.actions:type_name -> simulation.ActionDict.ActionsEntry
+	27, // 30: simulation.GetSpacesResponse.action_space:type_name -> simulation.ActionSpace
+	28, // 31: simulation.GetSpacesResponse.observation_space:type_name -> simulation.ObservationSpace
+	0,  // 32: simulation.ActionSpace.type:type_name -> simulation.SpaceType
+	70, // 33: simulation.ActionSpace.spaces:type_name -> simulation.ActionSpace.SpacesEntry
+	27, // 34: simulation.ActionSpace.parameters:type_name -> simulation.ActionSpace
+	0,  // 35: simulation.ObservationSpace.type:type_name -> simulation.SpaceType
+	72, // 36: simulation.EvaluatePolicyRequest.config:type_name -> google.protobuf.Struct
+	39, // 37: simulation.ListEnvironmentsResponse.environments:type_name -> simulation.EnvironmentStatus
+	8,  // 38: simulation.ForceCloseEnvironmentResponse.error:type_name -> simulation.Error
+	71, // 39: simulation.CurriculumProgress.parameters:type_name -> simulation.CurriculumProgress.ParametersEntry
+	4,  // 40: simulation.GetInfoResponse.StepLatencyEntry.value:type_name -> simulation.ScenarioLatency
+	18, // 41: simulation.GetInfoResponse.TypedInfoEntry.value:type_name -> simulation.Value
+	18, // 42: simulation.ResetEnvironmentResponse.TypedInfoEntry.value:type_name -> simulation.Value
+	13, // 43: simulation.ResetEnvironmentResponse.AgentsEntry.value:type_name -> simulation.AgentStep
+	18, // 44: simulation.StepEnvironmentResponse.TypedInfoEntry.value:type_name -> simulation.Value
+	13, // 45: simulation.StepEnvironmentResponse.AgentsEntry.value:type_name -> simulation.AgentStep
+	18, // 46: simulation.Observation.TypedMetadataEntry.value:type_name -> simulation.Value
+	19, // 47: simulation.ActionDict.ActionsEntry.value:type_name -> simulation.Action
+	27, // 48: simulation.ActionSpace.SpacesEntry.value:type_name -> simulation.ActionSpace
+	2,  // 49: simulation.SimulationService.GetInfo:input_type -> simulation.GetInfoRequest
+	6,  // 50: simulation.SimulationService.CreateEnvironment:input_type -> simulation.CreateEnvironmentRequest
+	9,  // 51: simulation.SimulationService.ResetEnvironment:input_type -> simulation.ResetEnvironmentRequest
+	11, // 52: simulation.SimulationService.StepEnvironment:input_type -> simulation.StepEnvironmentRequest
+	14, // 53: simulation.SimulationService.CloseEnvironment:input_type -> simulation.CloseEnvironmentRequest
+	25, // 54: simulation.SimulationService.GetSpaces:input_type -> simulation.GetSpacesRequest
+	29, // 55: simulation.SimulationService.GetMetadata:input_type -> simulation.GetMetadataRequest
+	31, // 56: simulation.SimulationService.DebugEnvironment:input_type -> simulation.DebugEnvironmentRequest
+	33, // 57: simulation.SimulationService.EvaluatePolicy:input_type -> simulation.EvaluatePolicyRequest
+	35, // 58: simulation.SimulationService.OpenSession:input_type -> simulation.OpenSessionRequest
+	37, // 59: simulation.SimulationService.CloseSession:input_type -> simulation.CloseSessionRequest
+	40, // 60: simulation.SimulationService.ListEnvironments:input_type -> simulation.ListEnvironmentsRequest
+	42, // 61: simulation.SimulationService.ForceCloseEnvironment:input_type -> simulation.ForceCloseEnvironmentRequest
+	44, // 62: simulation.SimulationService.DumpEnvironmentState:input_type -> simulation.DumpEnvironmentStateRequest
+	46, // 63: simulation.SimulationService.Drain:input_type -> simulation.DrainRequest
+	48, // 64: simulation.SimulationService.ExportEnvironment:input_type -> simulation.ExportEnvironmentRequest
+	50, // 65: simulation.SimulationService.ImportEnvironment:input_type -> simulation.ImportEnvironmentRequest
+	52, // 66: simulation.SimulationService.MigrateEnvironment:input_type -> simulation.MigrateEnvironmentRequest
+	54, // 67: simulation.SimulationService.DrainWorker:input_type -> simulation.DrainWorkerRequest
+	56, // 68: simulation.SimulationService.RegisterScenario:input_type -> simulation.RegisterScenarioRequest
+	58, // 69: simulation.SimulationService.GetCurriculum:input_type -> simulation.GetCurriculumRequest
+	59, // 70: simulation.SimulationService.SetCurriculumStage:input_type -> simulation.SetCurriculumStageRequest
+	11, // 71: simulation.SimulationService.StreamStep:input_type -> simulation.StepEnvironmentRequest
+	3,  // 72: simulation.SimulationService.GetInfo:output_type -> simulation.GetInfoResponse
+	7,  // 73: simulation.SimulationService.CreateEnvironment:output_type -> simulation.CreateEnvironmentResponse
+	10, // 74: simulation.SimulationService.ResetEnvironment:output_type -> simulation.ResetEnvironmentResponse
+	12, // 75: simulation.SimulationService.StepEnvironment:output_type -> simulation.StepEnvironmentResponse
+	15, // 76: simulation.SimulationService.CloseEnvironment:output_type -> simulation.CloseEnvironmentResponse
+	26, // 77: simulation.SimulationService.GetSpaces:output_type -> simulation.GetSpacesResponse
+	30, // 78: simulation.SimulationService.GetMetadata:output_type -> simulation.GetMetadataResponse
+	32, // 79: simulation.SimulationService.DebugEnvironment:output_type -> simulation.DebugEnvironmentResponse
+	34, // 80: simulation.SimulationService.EvaluatePolicy:output_type -> simulation.EvaluatePolicyResponse
+	36, // 81: simulation.SimulationService.OpenSession:output_type -> simulation.OpenSessionResponse
+	38, // 82: simulation.SimulationService.CloseSession:output_type -> simulation.CloseSessionResponse
+	41, // 83: simulation.SimulationService.ListEnvironments:output_type -> simulation.ListEnvironmentsResponse
+	43, // 84: simulation.SimulationService.ForceCloseEnvironment:output_type -> simulation.ForceCloseEnvironmentResponse
+	45, // 85: simulation.SimulationService.DumpEnvironmentState:output_type -> simulation.DumpEnvironmentStateResponse
+	47, // 86: simulation.SimulationService.Drain:output_type -> simulation.DrainResponse
+	49, // 87: simulation.SimulationService.ExportEnvironment:output_type -> simulation.ExportEnvironmentResponse
+	51, // 88: simulation.SimulationService.ImportEnvironment:output_type -> simulation.ImportEnvironmentResponse
+	53, // 89: simulation.SimulationService.MigrateEnvironment:output_type -> simulation.MigrateEnvironmentResponse
+	55, // 90: simulation.SimulationService.DrainWorker:output_type -> simulation.DrainWorkerResponse
+	57, // 91: simulation.SimulationService.RegisterScenario:output_type -> simulation.RegisterScenarioResponse
+	60, // 92: simulation.SimulationService.GetCurriculum:output_type -> simulation.CurriculumProgress
+	60, // 93: simulation.SimulationService.SetCurriculumStage:output_type -> simulation.CurriculumProgress
+	12, // 94: simulation.SimulationService.StreamStep:output_type -> simulation.StepEnvironmentResponse
+	72, // [72:95] is the sub-list for method output_type
+	49, // [49:72] is the sub-list for method input_type
+	49, // [49:49] is the sub-list for extension type_name
+	49, // [49:49] is the sub-list for extension extendee
+	0,  // [0:49] is the sub-list for field type_name
 }
 
 func init() { file_proto_simulation_proto_init() }
@@ -1422,7 +4514,13 @@ func file_proto_simulation_proto_init() {
 	if File_proto_simulation_proto != nil {
 		return
 	}
-	file_proto_simulation_proto_msgTypes[11].OneofWrappers = []any{
+	file_proto_simulation_proto_msgTypes[16].OneofWrappers = []any{
+		(*Value_DoubleValue)(nil),
+		(*Value_IntValue)(nil),
+		(*Value_BoolValue)(nil),
+		(*Value_StringValue)(nil),
+	}
+	file_proto_simulation_proto_msgTypes[17].OneofWrappers = []any{
 		(*Action_FloatValue)(nil),
 		(*Action_IntValue)(nil),
 		(*Action_BoolValue)(nil),
@@ -1431,14 +4529,16 @@ func file_proto_simulation_proto_init() {
 		(*Action_BoolArray)(nil),
 		(*Action_StringValue)(nil),
 		(*Action_RawData)(nil),
+		(*Action_Dict)(nil),
+		(*Action_Hybrid)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_simulation_proto_rawDesc), len(file_proto_simulation_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   19,
+			NumEnums:      2,
+			NumMessages:   70,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
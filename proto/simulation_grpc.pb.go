@@ -19,13 +19,29 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	SimulationService_GetInfo_FullMethodName           = "/simulation.SimulationService/GetInfo"
-	SimulationService_CreateEnvironment_FullMethodName = "/simulation.SimulationService/CreateEnvironment"
-	SimulationService_ResetEnvironment_FullMethodName  = "/simulation.SimulationService/ResetEnvironment"
-	SimulationService_StepEnvironment_FullMethodName   = "/simulation.SimulationService/StepEnvironment"
-	SimulationService_CloseEnvironment_FullMethodName  = "/simulation.SimulationService/CloseEnvironment"
-	SimulationService_GetSpaces_FullMethodName         = "/simulation.SimulationService/GetSpaces"
-	SimulationService_StreamStep_FullMethodName        = "/simulation.SimulationService/StreamStep"
+	SimulationService_GetInfo_FullMethodName               = "/simulation.SimulationService/GetInfo"
+	SimulationService_CreateEnvironment_FullMethodName     = "/simulation.SimulationService/CreateEnvironment"
+	SimulationService_ResetEnvironment_FullMethodName      = "/simulation.SimulationService/ResetEnvironment"
+	SimulationService_StepEnvironment_FullMethodName       = "/simulation.SimulationService/StepEnvironment"
+	SimulationService_CloseEnvironment_FullMethodName      = "/simulation.SimulationService/CloseEnvironment"
+	SimulationService_GetSpaces_FullMethodName             = "/simulation.SimulationService/GetSpaces"
+	SimulationService_GetMetadata_FullMethodName           = "/simulation.SimulationService/GetMetadata"
+	SimulationService_DebugEnvironment_FullMethodName      = "/simulation.SimulationService/DebugEnvironment"
+	SimulationService_EvaluatePolicy_FullMethodName        = "/simulation.SimulationService/EvaluatePolicy"
+	SimulationService_OpenSession_FullMethodName           = "/simulation.SimulationService/OpenSession"
+	SimulationService_CloseSession_FullMethodName          = "/simulation.SimulationService/CloseSession"
+	SimulationService_ListEnvironments_FullMethodName      = "/simulation.SimulationService/ListEnvironments"
+	SimulationService_ForceCloseEnvironment_FullMethodName = "/simulation.SimulationService/ForceCloseEnvironment"
+	SimulationService_DumpEnvironmentState_FullMethodName  = "/simulation.SimulationService/DumpEnvironmentState"
+	SimulationService_Drain_FullMethodName                 = "/simulation.SimulationService/Drain"
+	SimulationService_ExportEnvironment_FullMethodName     = "/simulation.SimulationService/ExportEnvironment"
+	SimulationService_ImportEnvironment_FullMethodName     = "/simulation.SimulationService/ImportEnvironment"
+	SimulationService_MigrateEnvironment_FullMethodName    = "/simulation.SimulationService/MigrateEnvironment"
+	SimulationService_DrainWorker_FullMethodName           = "/simulation.SimulationService/DrainWorker"
+	SimulationService_RegisterScenario_FullMethodName      = "/simulation.SimulationService/RegisterScenario"
+	SimulationService_GetCurriculum_FullMethodName         = "/simulation.SimulationService/GetCurriculum"
+	SimulationService_SetCurriculumStage_FullMethodName    = "/simulation.SimulationService/SetCurriculumStage"
+	SimulationService_StreamStep_FullMethodName            = "/simulation.SimulationService/StreamStep"
 )
 
 // SimulationServiceClient is the client API for SimulationService service.
@@ -46,6 +62,40 @@ type SimulationServiceClient interface {
 	CloseEnvironment(ctx context.Context, in *CloseEnvironmentRequest, opts ...grpc.CallOption) (*CloseEnvironmentResponse, error)
 	// GetSpaces 获取环境的动作空间和观察空间定义
 	GetSpaces(ctx context.Context, in *GetSpacesRequest, opts ...grpc.CallOption) (*GetSpacesResponse, error)
+	// GetMetadata 获取环境元数据（奖励范围、最大步数、渲染模式等）
+	GetMetadata(ctx context.Context, in *GetMetadataRequest, opts ...grpc.CallOption) (*GetMetadataResponse, error)
+	// DebugEnvironment 以JSON导出调用方自己的环境的内部状态（检查点、StateDumper的结果与包装层），供场景作者排查异常的奖励或结束标志
+	DebugEnvironment(ctx context.Context, in *DebugEnvironmentRequest, opts ...grpc.CallOption) (*DebugEnvironmentResponse, error)
+	// EvaluatePolicy 在服务端以ONNX策略运行若干回合，推理在本地完成，无需逐步往返动作
+	EvaluatePolicy(ctx context.Context, in *EvaluatePolicyRequest, opts ...grpc.CallOption) (*EvaluatePolicyResponse, error)
+	// OpenSession 打开会话：请求元数据session-id携带会话ID时，env_id只在该会话内可见，
+	// 会话过期、关闭或（bind_connection时）连接断开时其环境全部关闭
+	OpenSession(ctx context.Context, in *OpenSessionRequest, opts ...grpc.CallOption) (*OpenSessionResponse, error)
+	// CloseSession 关闭会话及其所有环境
+	CloseSession(ctx context.Context, in *CloseSessionRequest, opts ...grpc.CallOption) (*CloseSessionResponse, error)
+	// ListEnvironments 管理接口：列出全部环境（含各会话的环境）及其所属会话、客户端、存活时间与步数
+	ListEnvironments(ctx context.Context, in *ListEnvironmentsRequest, opts ...grpc.CallOption) (*ListEnvironmentsResponse, error)
+	// ForceCloseEnvironment 管理接口：强制关闭环境，env_id为注册表中的完整键
+	ForceCloseEnvironment(ctx context.Context, in *ForceCloseEnvironmentRequest, opts ...grpc.CallOption) (*ForceCloseEnvironmentResponse, error)
+	// DumpEnvironmentState 管理接口：以JSON导出环境的内部状态
+	DumpEnvironmentState(ctx context.Context, in *DumpEnvironmentStateRequest, opts ...grpc.CallOption) (*DumpEnvironmentStateResponse, error)
+	// Drain 管理接口：停止创建新环境，等待已有环境关闭，超时后可强制关闭剩余环境
+	Drain(ctx context.Context, in *DrainRequest, opts ...grpc.CallOption) (*DrainResponse, error)
+	// ExportEnvironment 管理接口：导出环境（属于会话时连同会话的全部环境）的检查点，detach时随后从本服务端移除
+	ExportEnvironment(ctx context.Context, in *ExportEnvironmentRequest, opts ...grpc.CallOption) (*ExportEnvironmentResponse, error)
+	// ImportEnvironment 管理接口：按ExportEnvironment的结果重新创建环境并恢复状态
+	ImportEnvironment(ctx context.Context, in *ImportEnvironmentRequest, opts ...grpc.CallOption) (*ImportEnvironmentResponse, error)
+	// MigrateEnvironment 路由器的管理接口：把环境迁移到另一个工作节点，持有env_id的客户端无需改动
+	MigrateEnvironment(ctx context.Context, in *MigrateEnvironmentRequest, opts ...grpc.CallOption) (*MigrateEnvironmentResponse, error)
+	// DrainWorker 路由器的管理接口：不再向工作节点分配新环境，并把其上的环境迁移到其余节点
+	DrainWorker(ctx context.Context, in *DrainWorkerRequest, opts ...grpc.CallOption) (*DrainWorkerResponse, error)
+	// RegisterScenario 上传实现环境ABI的WASM模块，在服务端沙箱中运行并注册为场景（需服务端开启，并携带管理令牌）；
+	// 场景名已被占用时返回ALREADY_EXISTS
+	RegisterScenario(ctx context.Context, in *RegisterScenarioRequest, opts ...grpc.CallOption) (*RegisterScenarioResponse, error)
+	// GetCurriculum 获取以curriculum配置创建的环境的课程进度
+	GetCurriculum(ctx context.Context, in *GetCurriculumRequest, opts ...grpc.CallOption) (*CurriculumProgress, error)
+	// SetCurriculumStage 切换环境的课程阶段（下次Reset时生效），frozen时停止自动推进
+	SetCurriculumStage(ctx context.Context, in *SetCurriculumStageRequest, opts ...grpc.CallOption) (*CurriculumProgress, error)
 	// StreamStep 流式执行仿真步骤 (可选，用于实时仿真)
 	StreamStep(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[StepEnvironmentRequest, StepEnvironmentResponse], error)
 }
@@ -118,6 +168,166 @@ func (c *simulationServiceClient) GetSpaces(ctx context.Context, in *GetSpacesRe
 	return out, nil
 }
 
+func (c *simulationServiceClient) GetMetadata(ctx context.Context, in *GetMetadataRequest, opts ...grpc.CallOption) (*GetMetadataResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetMetadataResponse)
+	err := c.cc.Invoke(ctx, SimulationService_GetMetadata_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simulationServiceClient) DebugEnvironment(ctx context.Context, in *DebugEnvironmentRequest, opts ...grpc.CallOption) (*DebugEnvironmentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DebugEnvironmentResponse)
+	err := c.cc.Invoke(ctx, SimulationService_DebugEnvironment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simulationServiceClient) EvaluatePolicy(ctx context.Context, in *EvaluatePolicyRequest, opts ...grpc.CallOption) (*EvaluatePolicyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EvaluatePolicyResponse)
+	err := c.cc.Invoke(ctx, SimulationService_EvaluatePolicy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simulationServiceClient) OpenSession(ctx context.Context, in *OpenSessionRequest, opts ...grpc.CallOption) (*OpenSessionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OpenSessionResponse)
+	err := c.cc.Invoke(ctx, SimulationService_OpenSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simulationServiceClient) CloseSession(ctx context.Context, in *CloseSessionRequest, opts ...grpc.CallOption) (*CloseSessionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CloseSessionResponse)
+	err := c.cc.Invoke(ctx, SimulationService_CloseSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simulationServiceClient) ListEnvironments(ctx context.Context, in *ListEnvironmentsRequest, opts ...grpc.CallOption) (*ListEnvironmentsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListEnvironmentsResponse)
+	err := c.cc.Invoke(ctx, SimulationService_ListEnvironments_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simulationServiceClient) ForceCloseEnvironment(ctx context.Context, in *ForceCloseEnvironmentRequest, opts ...grpc.CallOption) (*ForceCloseEnvironmentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ForceCloseEnvironmentResponse)
+	err := c.cc.Invoke(ctx, SimulationService_ForceCloseEnvironment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simulationServiceClient) DumpEnvironmentState(ctx context.Context, in *DumpEnvironmentStateRequest, opts ...grpc.CallOption) (*DumpEnvironmentStateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DumpEnvironmentStateResponse)
+	err := c.cc.Invoke(ctx, SimulationService_DumpEnvironmentState_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simulationServiceClient) Drain(ctx context.Context, in *DrainRequest, opts ...grpc.CallOption) (*DrainResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DrainResponse)
+	err := c.cc.Invoke(ctx, SimulationService_Drain_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simulationServiceClient) ExportEnvironment(ctx context.Context, in *ExportEnvironmentRequest, opts ...grpc.CallOption) (*ExportEnvironmentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportEnvironmentResponse)
+	err := c.cc.Invoke(ctx, SimulationService_ExportEnvironment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simulationServiceClient) ImportEnvironment(ctx context.Context, in *ImportEnvironmentRequest, opts ...grpc.CallOption) (*ImportEnvironmentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ImportEnvironmentResponse)
+	err := c.cc.Invoke(ctx, SimulationService_ImportEnvironment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simulationServiceClient) MigrateEnvironment(ctx context.Context, in *MigrateEnvironmentRequest, opts ...grpc.CallOption) (*MigrateEnvironmentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MigrateEnvironmentResponse)
+	err := c.cc.Invoke(ctx, SimulationService_MigrateEnvironment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simulationServiceClient) DrainWorker(ctx context.Context, in *DrainWorkerRequest, opts ...grpc.CallOption) (*DrainWorkerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DrainWorkerResponse)
+	err := c.cc.Invoke(ctx, SimulationService_DrainWorker_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simulationServiceClient) RegisterScenario(ctx context.Context, in *RegisterScenarioRequest, opts ...grpc.CallOption) (*RegisterScenarioResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RegisterScenarioResponse)
+	err := c.cc.Invoke(ctx, SimulationService_RegisterScenario_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simulationServiceClient) GetCurriculum(ctx context.Context, in *GetCurriculumRequest, opts ...grpc.CallOption) (*CurriculumProgress, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CurriculumProgress)
+	err := c.cc.Invoke(ctx, SimulationService_GetCurriculum_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simulationServiceClient) SetCurriculumStage(ctx context.Context, in *SetCurriculumStageRequest, opts ...grpc.CallOption) (*CurriculumProgress, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CurriculumProgress)
+	err := c.cc.Invoke(ctx, SimulationService_SetCurriculumStage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *simulationServiceClient) StreamStep(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[StepEnvironmentRequest, StepEnvironmentResponse], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	stream, err := c.cc.NewStream(ctx, &SimulationService_ServiceDesc.Streams[0], SimulationService_StreamStep_FullMethodName, cOpts...)
@@ -149,6 +359,40 @@ type SimulationServiceServer interface {
 	CloseEnvironment(context.Context, *CloseEnvironmentRequest) (*CloseEnvironmentResponse, error)
 	// GetSpaces 获取环境的动作空间和观察空间定义
 	GetSpaces(context.Context, *GetSpacesRequest) (*GetSpacesResponse, error)
+	// GetMetadata 获取环境元数据（奖励范围、最大步数、渲染模式等）
+	GetMetadata(context.Context, *GetMetadataRequest) (*GetMetadataResponse, error)
+	// DebugEnvironment 以JSON导出调用方自己的环境的内部状态（检查点、StateDumper的结果与包装层），供场景作者排查异常的奖励或结束标志
+	DebugEnvironment(context.Context, *DebugEnvironmentRequest) (*DebugEnvironmentResponse, error)
+	// EvaluatePolicy 在服务端以ONNX策略运行若干回合，推理在本地完成，无需逐步往返动作
+	EvaluatePolicy(context.Context, *EvaluatePolicyRequest) (*EvaluatePolicyResponse, error)
+	// OpenSession 打开会话：请求元数据session-id携带会话ID时，env_id只在该会话内可见，
+	// 会话过期、关闭或（bind_connection时）连接断开时其环境全部关闭
+	OpenSession(context.Context, *OpenSessionRequest) (*OpenSessionResponse, error)
+	// CloseSession 关闭会话及其所有环境
+	CloseSession(context.Context, *CloseSessionRequest) (*CloseSessionResponse, error)
+	// ListEnvironments 管理接口：列出全部环境（含各会话的环境）及其所属会话、客户端、存活时间与步数
+	ListEnvironments(context.Context, *ListEnvironmentsRequest) (*ListEnvironmentsResponse, error)
+	// ForceCloseEnvironment 管理接口：强制关闭环境，env_id为注册表中的完整键
+	ForceCloseEnvironment(context.Context, *ForceCloseEnvironmentRequest) (*ForceCloseEnvironmentResponse, error)
+	// DumpEnvironmentState 管理接口：以JSON导出环境的内部状态
+	DumpEnvironmentState(context.Context, *DumpEnvironmentStateRequest) (*DumpEnvironmentStateResponse, error)
+	// Drain 管理接口：停止创建新环境，等待已有环境关闭，超时后可强制关闭剩余环境
+	Drain(context.Context, *DrainRequest) (*DrainResponse, error)
+	// ExportEnvironment 管理接口：导出环境（属于会话时连同会话的全部环境）的检查点，detach时随后从本服务端移除
+	ExportEnvironment(context.Context, *ExportEnvironmentRequest) (*ExportEnvironmentResponse, error)
+	// ImportEnvironment 管理接口：按ExportEnvironment的结果重新创建环境并恢复状态
+	ImportEnvironment(context.Context, *ImportEnvironmentRequest) (*ImportEnvironmentResponse, error)
+	// MigrateEnvironment 路由器的管理接口：把环境迁移到另一个工作节点，持有env_id的客户端无需改动
+	MigrateEnvironment(context.Context, *MigrateEnvironmentRequest) (*MigrateEnvironmentResponse, error)
+	// DrainWorker 路由器的管理接口：不再向工作节点分配新环境，并把其上的环境迁移到其余节点
+	DrainWorker(context.Context, *DrainWorkerRequest) (*DrainWorkerResponse, error)
+	// RegisterScenario 上传实现环境ABI的WASM模块，在服务端沙箱中运行并注册为场景（需服务端开启，并携带管理令牌）；
+	// 场景名已被占用时返回ALREADY_EXISTS
+	RegisterScenario(context.Context, *RegisterScenarioRequest) (*RegisterScenarioResponse, error)
+	// GetCurriculum 获取以curriculum配置创建的环境的课程进度
+	GetCurriculum(context.Context, *GetCurriculumRequest) (*CurriculumProgress, error)
+	// SetCurriculumStage 切换环境的课程阶段（下次Reset时生效），frozen时停止自动推进
+	SetCurriculumStage(context.Context, *SetCurriculumStageRequest) (*CurriculumProgress, error)
 	// StreamStep 流式执行仿真步骤 (可选，用于实时仿真)
 	StreamStep(grpc.BidiStreamingServer[StepEnvironmentRequest, StepEnvironmentResponse]) error
 	mustEmbedUnimplementedSimulationServiceServer()
@@ -179,6 +423,54 @@ func (UnimplementedSimulationServiceServer) CloseEnvironment(context.Context, *C
 func (UnimplementedSimulationServiceServer) GetSpaces(context.Context, *GetSpacesRequest) (*GetSpacesResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetSpaces not implemented")
 }
+func (UnimplementedSimulationServiceServer) GetMetadata(context.Context, *GetMetadataRequest) (*GetMetadataResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetMetadata not implemented")
+}
+func (UnimplementedSimulationServiceServer) DebugEnvironment(context.Context, *DebugEnvironmentRequest) (*DebugEnvironmentResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DebugEnvironment not implemented")
+}
+func (UnimplementedSimulationServiceServer) EvaluatePolicy(context.Context, *EvaluatePolicyRequest) (*EvaluatePolicyResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method EvaluatePolicy not implemented")
+}
+func (UnimplementedSimulationServiceServer) OpenSession(context.Context, *OpenSessionRequest) (*OpenSessionResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method OpenSession not implemented")
+}
+func (UnimplementedSimulationServiceServer) CloseSession(context.Context, *CloseSessionRequest) (*CloseSessionResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CloseSession not implemented")
+}
+func (UnimplementedSimulationServiceServer) ListEnvironments(context.Context, *ListEnvironmentsRequest) (*ListEnvironmentsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListEnvironments not implemented")
+}
+func (UnimplementedSimulationServiceServer) ForceCloseEnvironment(context.Context, *ForceCloseEnvironmentRequest) (*ForceCloseEnvironmentResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ForceCloseEnvironment not implemented")
+}
+func (UnimplementedSimulationServiceServer) DumpEnvironmentState(context.Context, *DumpEnvironmentStateRequest) (*DumpEnvironmentStateResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DumpEnvironmentState not implemented")
+}
+func (UnimplementedSimulationServiceServer) Drain(context.Context, *DrainRequest) (*DrainResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Drain not implemented")
+}
+func (UnimplementedSimulationServiceServer) ExportEnvironment(context.Context, *ExportEnvironmentRequest) (*ExportEnvironmentResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ExportEnvironment not implemented")
+}
+func (UnimplementedSimulationServiceServer) ImportEnvironment(context.Context, *ImportEnvironmentRequest) (*ImportEnvironmentResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ImportEnvironment not implemented")
+}
+func (UnimplementedSimulationServiceServer) MigrateEnvironment(context.Context, *MigrateEnvironmentRequest) (*MigrateEnvironmentResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method MigrateEnvironment not implemented")
+}
+func (UnimplementedSimulationServiceServer) DrainWorker(context.Context, *DrainWorkerRequest) (*DrainWorkerResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DrainWorker not implemented")
+}
+func (UnimplementedSimulationServiceServer) RegisterScenario(context.Context, *RegisterScenarioRequest) (*RegisterScenarioResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RegisterScenario not implemented")
+}
+func (UnimplementedSimulationServiceServer) GetCurriculum(context.Context, *GetCurriculumRequest) (*CurriculumProgress, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetCurriculum not implemented")
+}
+func (UnimplementedSimulationServiceServer) SetCurriculumStage(context.Context, *SetCurriculumStageRequest) (*CurriculumProgress, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetCurriculumStage not implemented")
+}
 func (UnimplementedSimulationServiceServer) StreamStep(grpc.BidiStreamingServer[StepEnvironmentRequest, StepEnvironmentResponse]) error {
 	return status.Error(codes.Unimplemented, "method StreamStep not implemented")
 }
@@ -311,6 +603,294 @@ func _SimulationService_GetSpaces_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SimulationService_GetMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimulationServiceServer).GetMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimulationService_GetMetadata_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimulationServiceServer).GetMetadata(ctx, req.(*GetMetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimulationService_DebugEnvironment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DebugEnvironmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimulationServiceServer).DebugEnvironment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimulationService_DebugEnvironment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimulationServiceServer).DebugEnvironment(ctx, req.(*DebugEnvironmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimulationService_EvaluatePolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EvaluatePolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimulationServiceServer).EvaluatePolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimulationService_EvaluatePolicy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimulationServiceServer).EvaluatePolicy(ctx, req.(*EvaluatePolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimulationService_OpenSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OpenSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimulationServiceServer).OpenSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimulationService_OpenSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimulationServiceServer).OpenSession(ctx, req.(*OpenSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimulationService_CloseSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloseSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimulationServiceServer).CloseSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimulationService_CloseSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimulationServiceServer).CloseSession(ctx, req.(*CloseSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimulationService_ListEnvironments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListEnvironmentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimulationServiceServer).ListEnvironments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimulationService_ListEnvironments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimulationServiceServer).ListEnvironments(ctx, req.(*ListEnvironmentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimulationService_ForceCloseEnvironment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ForceCloseEnvironmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimulationServiceServer).ForceCloseEnvironment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimulationService_ForceCloseEnvironment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimulationServiceServer).ForceCloseEnvironment(ctx, req.(*ForceCloseEnvironmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimulationService_DumpEnvironmentState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DumpEnvironmentStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimulationServiceServer).DumpEnvironmentState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimulationService_DumpEnvironmentState_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimulationServiceServer).DumpEnvironmentState(ctx, req.(*DumpEnvironmentStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimulationService_Drain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DrainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimulationServiceServer).Drain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimulationService_Drain_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimulationServiceServer).Drain(ctx, req.(*DrainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimulationService_ExportEnvironment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportEnvironmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimulationServiceServer).ExportEnvironment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimulationService_ExportEnvironment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimulationServiceServer).ExportEnvironment(ctx, req.(*ExportEnvironmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimulationService_ImportEnvironment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportEnvironmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimulationServiceServer).ImportEnvironment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimulationService_ImportEnvironment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimulationServiceServer).ImportEnvironment(ctx, req.(*ImportEnvironmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimulationService_MigrateEnvironment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MigrateEnvironmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimulationServiceServer).MigrateEnvironment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimulationService_MigrateEnvironment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimulationServiceServer).MigrateEnvironment(ctx, req.(*MigrateEnvironmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimulationService_DrainWorker_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DrainWorkerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimulationServiceServer).DrainWorker(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimulationService_DrainWorker_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimulationServiceServer).DrainWorker(ctx, req.(*DrainWorkerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimulationService_RegisterScenario_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterScenarioRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimulationServiceServer).RegisterScenario(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimulationService_RegisterScenario_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimulationServiceServer).RegisterScenario(ctx, req.(*RegisterScenarioRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimulationService_GetCurriculum_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCurriculumRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimulationServiceServer).GetCurriculum(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimulationService_GetCurriculum_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimulationServiceServer).GetCurriculum(ctx, req.(*GetCurriculumRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimulationService_SetCurriculumStage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetCurriculumStageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimulationServiceServer).SetCurriculumStage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimulationService_SetCurriculumStage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimulationServiceServer).SetCurriculumStage(ctx, req.(*SetCurriculumStageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _SimulationService_StreamStep_Handler(srv interface{}, stream grpc.ServerStream) error {
 	return srv.(SimulationServiceServer).StreamStep(&grpc.GenericServerStream[StepEnvironmentRequest, StepEnvironmentResponse]{ServerStream: stream})
 }
@@ -349,6 +929,70 @@ var SimulationService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetSpaces",
 			Handler:    _SimulationService_GetSpaces_Handler,
 		},
+		{
+			MethodName: "GetMetadata",
+			Handler:    _SimulationService_GetMetadata_Handler,
+		},
+		{
+			MethodName: "DebugEnvironment",
+			Handler:    _SimulationService_DebugEnvironment_Handler,
+		},
+		{
+			MethodName: "EvaluatePolicy",
+			Handler:    _SimulationService_EvaluatePolicy_Handler,
+		},
+		{
+			MethodName: "OpenSession",
+			Handler:    _SimulationService_OpenSession_Handler,
+		},
+		{
+			MethodName: "CloseSession",
+			Handler:    _SimulationService_CloseSession_Handler,
+		},
+		{
+			MethodName: "ListEnvironments",
+			Handler:    _SimulationService_ListEnvironments_Handler,
+		},
+		{
+			MethodName: "ForceCloseEnvironment",
+			Handler:    _SimulationService_ForceCloseEnvironment_Handler,
+		},
+		{
+			MethodName: "DumpEnvironmentState",
+			Handler:    _SimulationService_DumpEnvironmentState_Handler,
+		},
+		{
+			MethodName: "Drain",
+			Handler:    _SimulationService_Drain_Handler,
+		},
+		{
+			MethodName: "ExportEnvironment",
+			Handler:    _SimulationService_ExportEnvironment_Handler,
+		},
+		{
+			MethodName: "ImportEnvironment",
+			Handler:    _SimulationService_ImportEnvironment_Handler,
+		},
+		{
+			MethodName: "MigrateEnvironment",
+			Handler:    _SimulationService_MigrateEnvironment_Handler,
+		},
+		{
+			MethodName: "DrainWorker",
+			Handler:    _SimulationService_DrainWorker_Handler,
+		},
+		{
+			MethodName: "RegisterScenario",
+			Handler:    _SimulationService_RegisterScenario_Handler,
+		},
+		{
+			MethodName: "GetCurriculum",
+			Handler:    _SimulationService_GetCurriculum_Handler,
+		},
+		{
+			MethodName: "SetCurriculumStage",
+			Handler:    _SimulationService_SetCurriculumStage_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -4,9 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"sync"
+	"time"
 	"unsafe"
 
 	"github.com/jelech/rl_env_engine/core"
+	"github.com/jelech/rl_env_engine/core/metrics"
+	"github.com/jelech/rl_env_engine/scenarios"
 )
 
 var (
@@ -22,9 +25,24 @@ var (
 	LastObs     = make(map[int][]float64)
 	LastRewards = make(map[int][]float64)
 	LastDones   = make(map[int][]bool)
+	// LastObs32 dtype为float32的环境的观测值，此类环境不写入LastObs
+	LastObs32 = make(map[int][]float32)
+
+	// StepLatency 按场景统计的步进延迟与吞吐：Env为环境Step本身的耗时，
+	// Request为一次Step调用（含动作构造与观测平铺）或环境池中一个环境一步的耗时
+	StepLatency = metrics.NewStepLatency()
+	// envScenarios 每个环境实例的场景名，用于按场景统计延迟
+	envScenarios = make(map[int]string)
 )
 
-// Register 注册一个场景
+// 预先注册全部内置场景，生成的共享库可按名称创建任意内置场景
+func init() {
+	for _, s := range scenarios.All() {
+		Register(s)
+	}
+}
+
+// Register 注册一个场景，与已注册场景同名时替换它
 func Register(s core.Scenario) {
 	Registry[s.GetName()] = s
 }
@@ -36,17 +54,13 @@ func CreateEnv(scenarioName string, configJson string) int {
 	if !ok {
 		return -1 // 场景未找到
 	}
-
-	// 解析配置 JSON
-	var cfgMap map[string]interface{}
-	if err := json.Unmarshal([]byte(configJson), &cfgMap); err != nil {
-		return -2 // JSON 解析错误
+	cfgMap, code := parseConfig(configJson)
+	if code != 0 {
+		return code
 	}
-
-	// 创建环境
-	env, err := s.CreateEnvironment(core.NewBaseConfig(cfgMap))
-	if err != nil {
-		return -3 // 创建失败
+	env, dtype, code := newEnvironment(s, cfgMap)
+	if code != 0 {
+		return code
 	}
 
 	envMu.Lock()
@@ -54,9 +68,48 @@ func CreateEnv(scenarioName string, configJson string) int {
 	id := nextID
 	nextID++
 	Envs[id] = env
+	envScenarios[id] = scenarioName
+	// 按观察空间预分配平铺缓冲区，之后每步复用，多智能体环境在首次Reset时扩容
+	size := observationSize(env)
+	if dtype == core.DtypeFloat32 {
+		LastObs32[id] = make([]float32, 0, size)
+	} else {
+		LastObs[id] = make([]float64, 0, size)
+	}
 	return id
 }
 
+// parseConfig 解析配置 JSON，失败时返回CreateEnv的错误码
+func parseConfig(configJson string) (map[string]interface{}, int) {
+	var cfgMap map[string]interface{}
+	if err := json.Unmarshal([]byte(configJson), &cfgMap); err != nil {
+		return nil, -2 // JSON 解析错误
+	}
+	// Python端只读取平铺后的观察数据，未显式开启时跳过观察元数据的构建
+	if cfgMap == nil {
+		cfgMap = make(map[string]interface{})
+	}
+	if _, ok := cfgMap[core.ObservationMetadataKey]; !ok {
+		cfgMap[core.ObservationMetadataKey] = false
+	}
+	return cfgMap, 0
+}
+
+// newEnvironment 按配置创建场景的环境并返回其dtype，失败时返回CreateEnv的错误码。
+// 环境以core.Guard包装，场景的panic成为错误返回值而不会使Python进程退出
+func newEnvironment(s core.Scenario, cfgMap map[string]interface{}) (core.Environment, string, int) {
+	config := core.NewBaseConfig(cfgMap)
+	dtype, err := core.ParseDtype(config)
+	if err != nil {
+		return nil, "", -3
+	}
+	env, err := s.CreateEnvironment(config)
+	if err != nil {
+		return nil, "", -3 // 创建失败
+	}
+	return core.NewGuard(env, nil), dtype, 0
+}
+
 // Reset 重置环境
 func Reset(id int) int {
 	envMu.RLock()
@@ -75,45 +128,58 @@ func Reset(id int) int {
 	// 假设目前是单智能体/单观测，或者是所有观测的平铺
 	// 对于 CacheRL，它返回每个 SKU 的观测列表
 	// 我们需要将其平铺为单个 float 数组供 Python 使用
-	flattened := FlattenObservations(obs)
-
 	envMu.Lock()
-	LastObs[id] = flattened
+	n := storeObservations(id, obs)
 	envMu.Unlock()
+	core.ReleaseObservations(obs)
 
-	return len(flattened)
+	return n
 }
 
 // Step 执行一步环境仿真
 func Step(id int, actionData []float64) int {
+	// 构造 Action
+	// 由于 Core 的 Action 接口比较通用，这里我们假设使用 GenericAction
+	// CacheRL 环境的实现 (env.go) 已经支持识别 core.GenericAction
+	return step(id, core.NewGenericAction(actionData))
+}
+
+// StepInt 以整数动作执行一步，用于MultiDiscrete等整数动作空间：actionData为每维一个取值，
+// 环境收到[]int；调用方的缓冲区只在调用期间读取
+func StepInt(id int, actionData []int64) int {
+	values := make([]int, len(actionData))
+	for i, v := range actionData {
+		values[i] = int(v)
+	}
+	return step(id, core.NewGenericAction(values))
+}
+
+func step(id int, action core.Action) int {
+	start := time.Now()
 	envMu.RLock()
 	env, ok := Envs[id]
+	scenario := envScenarios[id]
 	envMu.RUnlock()
 	if !ok {
 		return -1 // 环境 ID 无效
 	}
-
-	// 构造 Action
-	// 由于 Core 的 Action 接口比较通用，这里我们假设使用 GenericAction
-	// CacheRL 环境的实现 (env.go) 已经支持识别 core.GenericAction
-	var actions []core.Action
-	act := core.NewGenericAction(actionData)
-	actions = append(actions, act)
+	actions := []core.Action{action}
 
 	// 执行 Step
+	stepStart := time.Now()
 	obs, rewards, dones, err := env.Step(context.Background(), actions)
 	if err != nil {
 		return -2 // Step 执行失败
 	}
-
-	flattenedObs := FlattenObservations(obs)
-	flattenedRewards := rewards
+	StepLatency.Env.Observe(scenario, time.Since(stepStart))
 
 	envMu.Lock()
-	LastObs[id] = flattenedObs
-	LastRewards[id] = flattenedRewards
+	storeObservations(id, obs)
+	LastRewards[id] = rewards
 	LastDones[id] = dones
 	envMu.Unlock()
+	core.ReleaseObservations(obs)
+	StepLatency.Request.Observe(scenario, time.Since(start))
 
 	return 0 // 成功
 }
@@ -121,8 +187,11 @@ func Step(id int, actionData []float64) int {
 // GetObservation 将观测数据复制到 C 指针指向的内存
 func GetObservation(id int, dest unsafe.Pointer, maxLen int) int {
 	envMu.RLock()
+	defer envMu.RUnlock()
+	if data, ok := LastObs32[id]; ok {
+		return copyToCFromFloat32(data, dest, maxLen)
+	}
 	data, ok := LastObs[id]
-	envMu.RUnlock()
 	if !ok {
 		return 0
 	}
@@ -130,6 +199,20 @@ func GetObservation(id int, dest unsafe.Pointer, maxLen int) int {
 	return copyToC(data, dest, maxLen)
 }
 
+// GetObservation32 将观测数据复制到 C float 数组，dtype为float32的环境无需转换
+func GetObservation32(id int, dest unsafe.Pointer, maxLen int) int {
+	envMu.RLock()
+	defer envMu.RUnlock()
+	if data, ok := LastObs32[id]; ok {
+		return copyToC32(data, dest, maxLen)
+	}
+	data, ok := LastObs[id]
+	if !ok {
+		return 0
+	}
+	return copyToC32FromFloat64(data, dest, maxLen)
+}
+
 // GetReward 将奖励数据复制到 C 指针指向的内存
 func GetReward(id int, dest unsafe.Pointer, maxLen int) int {
 	envMu.RLock()
@@ -141,6 +224,17 @@ func GetReward(id int, dest unsafe.Pointer, maxLen int) int {
 	return copyToC(data, dest, maxLen)
 }
 
+// GetReward32 将奖励数据复制到 C float 数组
+func GetReward32(id int, dest unsafe.Pointer, maxLen int) int {
+	envMu.RLock()
+	data, ok := LastRewards[id]
+	envMu.RUnlock()
+	if !ok {
+		return 0
+	}
+	return copyToC32FromFloat64(data, dest, maxLen)
+}
+
 // GetDone 将 Done (结束标志) 数据复制到 C 指针指向的内存
 // 注意：C/Python 端通常期望 bool 为 byte (0/1) 或 int
 // 这里我们将其转换为 byte (char) 数组
@@ -172,11 +266,54 @@ func GetDone(id int, dest unsafe.Pointer, maxLen int) int {
 
 // FlattenObservations 辅助函数：将观测对象列表平铺为 float64 数组
 func FlattenObservations(obs []core.Observation) []float64 {
-	var flat []float64
+	return AppendObservations(nil, obs)
+}
+
+// AppendObservations 将观测对象列表平铺追加到 dst，传入 dst[:0] 即可复用其底层数组
+func AppendObservations(dst []float64, obs []core.Observation) []float64 {
+	for _, o := range obs {
+		dst = append(dst, o.GetData()...)
+	}
+	return dst
+}
+
+// FlattenObservations32 辅助函数：将观测对象列表平铺为 float32 数组
+func FlattenObservations32(obs []core.Observation) []float32 {
+	return AppendObservations32(nil, obs)
+}
+
+// AppendObservations32 将观测对象列表以 float32 平铺追加到 dst
+func AppendObservations32(dst []float32, obs []core.Observation) []float32 {
 	for _, o := range obs {
-		flat = append(flat, o.GetData()...)
+		dst = core.AppendData32(dst, o)
+	}
+	return dst
+}
+
+// storeObservations 按观测的存储精度平铺到LastObs或LastObs32，复用上一步的缓冲区，
+// 返回观测长度，调用方需持有envMu
+func storeObservations(id int, obs []core.Observation) int {
+	if len(obs) > 0 {
+		if o, ok := obs[0].(core.Float32Observation); ok && o.GetData32() != nil {
+			flat := AppendObservations32(LastObs32[id][:0], obs)
+			LastObs32[id] = flat
+			return len(flat)
+		}
+	}
+	flat := AppendObservations(LastObs[id][:0], obs)
+	LastObs[id] = flat
+	return len(flat)
+}
+
+// observationSize 返回单个观察的元素个数，用于预分配缓冲区
+func observationSize(env core.Environment) int {
+	size := 1
+	for _, dim := range env.GetSpaces().ObservationSpace.Shape {
+		if dim > 0 {
+			size *= int(dim)
+		}
 	}
-	return flat
+	return size
 }
 
 // copyToC 辅助函数：将 float64 切片复制到 C double 数组
@@ -198,12 +335,73 @@ func copyToC(src []float64, dest unsafe.Pointer, maxLen int) int {
 	return count
 }
 
+// copyToC32 辅助函数：将 float32 切片复制到 C float 数组
+func copyToC32(src []float32, dest unsafe.Pointer, maxLen int) int {
+	count := min(len(src), maxLen)
+	if count <= 0 {
+		return 0
+	}
+	copy(unsafe.Slice((*float32)(dest), count), src[:count])
+	return count
+}
+
+// copyToC32FromFloat64 辅助函数：将 float64 切片转换后复制到 C float 数组
+func copyToC32FromFloat64(src []float64, dest unsafe.Pointer, maxLen int) int {
+	count := min(len(src), maxLen)
+	if count <= 0 {
+		return 0
+	}
+	cArray := unsafe.Slice((*float32)(dest), count)
+	for i := range cArray {
+		cArray[i] = float32(src[i])
+	}
+	return count
+}
+
+// copyToCFromFloat32 辅助函数：将 float32 切片转换后复制到 C double 数组
+func copyToCFromFloat32(src []float32, dest unsafe.Pointer, maxLen int) int {
+	count := min(len(src), maxLen)
+	if count <= 0 {
+		return 0
+	}
+	cArray := unsafe.Slice((*float64)(dest), count)
+	for i := range cArray {
+		cArray[i] = float64(src[i])
+	}
+	return count
+}
+
 // CloseEnv 关闭并移除环境实例
 func CloseEnv(id int) {
 	envMu.Lock()
 	delete(Envs, id)
 	delete(LastObs, id)
+	delete(LastObs32, id)
 	delete(LastRewards, id)
 	delete(LastDones, id)
+	delete(envScenarios, id)
 	envMu.Unlock()
 }
+
+// StepLatencyJSON 以JSON返回各场景的步进延迟与吞吐，结构与HTTP /metrics相同（transport为pybridge），
+// 与Python端测得的调用耗时对比即可区分cgo调用开销与环境本身的开销
+func StepLatencyJSON() []byte {
+	data, err := json.Marshal(map[string]interface{}{
+		"transport":    "pybridge",
+		"step_latency": StepLatency.Summaries(nil),
+	})
+	if err != nil {
+		return []byte("{}")
+	}
+	return data
+}
+
+// GetStepLatency 将StepLatencyJSON复制到 C 指针指向的内存（最多maxLen字节），
+// 返回JSON的完整长度，大于maxLen时调用方应以更大的缓冲区重试
+func GetStepLatency(dest unsafe.Pointer, maxLen int) int {
+	data := StepLatencyJSON()
+	if n := min(len(data), maxLen); n > 0 {
+		copy(unsafe.Slice((*byte)(dest), n), data)
+	}
+	return len(data)
+}
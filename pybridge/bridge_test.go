@@ -0,0 +1,127 @@
+package pybridge
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+	"unsafe"
+
+	"github.com/jelech/rl_env_engine/core"
+	"github.com/jelech/rl_env_engine/scenarios"
+)
+
+// BenchmarkStep 测量每个内置场景经pybridge导出函数单步的耗时与内存分配：Step之后像Python端一样
+// 把观察、奖励与结束标志复制到调用方的缓冲区，回合结束时Reset（计入耗时）。
+// Step与StepInt只向环境传递数组动作，标量动作空间的场景（如cartpole）在此跳过，它们经环境池的吞吐见BenchmarkPoolStep；
+// 多智能体场景与随机动作无法驱动的场景同样跳过
+func BenchmarkStep(b *testing.B) {
+	for _, scenario := range scenarios.All() {
+		name := scenario.GetName()
+		b.Run(name, func(b *testing.B) {
+			actions := sampleBridgeActions(b, scenario)
+			id := CreateEnv(name, "{}")
+			if id < 0 {
+				b.Skipf("CreateEnv returned %d", id)
+			}
+			defer CloseEnv(id)
+			n := Reset(id)
+			if n < 0 {
+				b.Fatalf("Reset returned %d", n)
+			}
+			obs := make([]float64, n)
+			rewards := make([]float64, 1)
+			dones := make([]byte, 1)
+			step := func(action bridgeAction) error {
+				code := 0
+				if action.ints != nil {
+					code = StepInt(id, action.ints)
+				} else {
+					code = Step(id, action.floats)
+				}
+				if code != 0 {
+					return fmt.Errorf("Step returned %d", code)
+				}
+				GetObservation(id, unsafe.Pointer(&obs[0]), len(obs))
+				GetReward(id, unsafe.Pointer(&rewards[0]), len(rewards))
+				if GetDone(id, unsafe.Pointer(&dones[0]), len(dones)) > 0 && dones[0] != 0 && Reset(id) < 0 {
+					return fmt.Errorf("Reset failed")
+				}
+				return nil
+			}
+			for _, action := range actions {
+				if err := step(action); err != nil {
+					b.Skipf("random actions: %v", err)
+				}
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := step(actions[i%len(actions)]); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// bridgeAction 一步的平铺动作，整数动作（Discrete与MultiDiscrete）经StepInt发送，其余经Step发送
+type bridgeAction struct {
+	floats []float64
+	ints   []int64
+}
+
+// values 返回平铺的动作值，整数动作转换为浮点数
+func (a bridgeAction) values() []float64 {
+	if a.ints == nil {
+		return a.floats
+	}
+	values := make([]float64, len(a.ints))
+	for i, v := range a.ints {
+		values[i] = float64(v)
+	}
+	return values
+}
+
+// sampleBridgeActions 用场景的Go环境采样256个随机动作并平铺，计时期间循环使用
+func sampleBridgeActions(b *testing.B, scenario core.Scenario) []bridgeAction {
+	b.Helper()
+	env, err := scenario.CreateEnvironment(core.NewBaseConfig(map[string]interface{}{}))
+	if err != nil {
+		b.Skipf("default config: %v", err)
+	}
+	defer env.Close()
+	observations, err := env.Reset(context.Background())
+	if err != nil {
+		b.Fatalf("reset: %v", err)
+	}
+	defer core.ReleaseObservations(observations)
+	if len(observations) != 1 {
+		b.Skipf("%d agents, pybridge steps a single agent", len(observations))
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	actions := make([]bridgeAction, 256)
+	for i := range actions {
+		action, err := core.SampleAction(env.GetSpaces().ActionSpace, rng)
+		if err != nil {
+			b.Skipf("sample action: %v", err)
+		}
+		switch v := action.GetData().(type) {
+		case int:
+			actions[i].ints = []int64{int64(v)}
+		case []int:
+			for _, x := range v {
+				actions[i].ints = append(actions[i].ints, int64(x))
+			}
+		case float64:
+			actions[i].floats = []float64{v}
+		case []float64:
+			actions[i].floats = v
+		default:
+			b.Skipf("action %T cannot be sent through pybridge", v)
+		}
+	}
+	return actions
+}
@@ -0,0 +1,341 @@
+package pybridge
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+var (
+	// Pools 存储活跃的环境池
+	Pools      = make(map[int]*Pool)
+	poolMu     sync.RWMutex
+	nextPoolID = 1
+)
+
+// Pool 与EnvPool语义相同的异步环境池：Send提交部分环境的动作后立即返回，各环境在后台并行步进，
+// Recv阻塞到batchSize个环境完成，按完成顺序返回它们的结果及环境下标。
+// 与EnvPool一致，回合结束（terminated或truncated）的环境在下一次Send时改为Reset，返回初始观察且奖励为0。
+// 同一时刻每个环境最多有一个未Recv的请求；Send与Recv应由同一个调用方依次调用
+type Pool struct {
+	slots      []poolSlot
+	batchSize  int
+	obsSize    int // 每个环境一行观察的长度，多智能体环境的观察平铺后按此截断或补零
+	actionSize int // 每个环境一次动作的值个数
+	space      core.ActionSpace
+	scenario   string // 按场景统计步进延迟
+	results    chan int
+	pending    atomic.Int64 // 已Send而尚未Recv的环境数
+}
+
+// poolSlot 池中的一个环境及其最近一次的结果，busy期间只由步进它的goroutine访问
+type poolSlot struct {
+	env        core.Environment
+	truncation *core.TruncationTracker
+	busy       atomic.Bool
+	needsReset bool
+
+	obs        []float64
+	obs32      []float32 // dtype为float32的环境写入此处
+	reward     float64
+	terminated bool
+	truncated  bool
+	elapsed    int
+	failed     bool
+}
+
+// CreatePool 创建numEnvs个相同配置的环境组成的池，返回池ID（失败时返回CreateEnv的错误码）。
+// batchSize<=0或大于numEnvs时等于numEnvs（同步模式）；配置中seed非0时第i个环境使用seed+i
+func CreatePool(scenarioName string, configJson string, numEnvs int, batchSize int) int {
+	s, ok := Registry[scenarioName]
+	if !ok {
+		return -1 // 场景未找到
+	}
+	if numEnvs <= 0 {
+		return -3
+	}
+	if batchSize <= 0 || batchSize > numEnvs {
+		batchSize = numEnvs
+	}
+
+	pool := &Pool{slots: make([]poolSlot, numEnvs), batchSize: batchSize, scenario: scenarioName, results: make(chan int, numEnvs)}
+	for i := range pool.slots {
+		cfgMap, code := parseConfig(configJson)
+		if code != 0 {
+			pool.close()
+			return code
+		}
+		if seed, ok := cfgMap["seed"].(float64); ok && seed != 0 {
+			cfgMap["seed"] = seed + float64(i)
+		}
+		env, _, code := newEnvironment(s, cfgMap)
+		if code != 0 {
+			pool.close()
+			return code
+		}
+		pool.slots[i] = poolSlot{env: env, truncation: core.NewTruncationTracker(env), needsReset: true}
+	}
+	pool.obsSize = observationSize(pool.slots[0].env)
+	pool.space = pool.slots[0].env.GetSpaces().ActionSpace
+	pool.actionSize = core.ActionValueCount(pool.space)
+
+	poolMu.Lock()
+	defer poolMu.Unlock()
+	id := nextPoolID
+	nextPoolID++
+	Pools[id] = pool
+	return id
+}
+
+func getPool(id int) (*Pool, bool) {
+	poolMu.RLock()
+	defer poolMu.RUnlock()
+	pool, ok := Pools[id]
+	return pool, ok
+}
+
+// PoolObservationSize 返回池中每个环境一行观察的长度，池ID无效时返回-1
+func PoolObservationSize(id int) int {
+	pool, ok := getPool(id)
+	if !ok {
+		return -1
+	}
+	return pool.obsSize
+}
+
+// PoolActionSize 返回池中每个环境一次动作的值个数，池ID无效时返回-1
+func PoolActionSize(id int) int {
+	pool, ok := getPool(id)
+	if !ok {
+		return -1
+	}
+	return pool.actionSize
+}
+
+// PoolAsyncReset 在后台重置池中所有环境，结果由之后的Recv返回。
+// 有环境的请求尚未Recv时返回-3
+func PoolAsyncReset(id int) int {
+	pool, ok := getPool(id)
+	if !ok {
+		return -1
+	}
+	for i := range pool.slots {
+		if !pool.slots[i].busy.CompareAndSwap(false, true) {
+			for j := 0; j < i; j++ {
+				pool.release(j)
+			}
+			return -3
+		}
+	}
+	pool.pending.Add(int64(len(pool.slots)))
+	for i := range pool.slots {
+		pool.slots[i].needsReset = true
+		go pool.run(i, nil)
+	}
+	return 0
+}
+
+// PoolSend 提交envIDs中各环境的动作，actions按环境依次平铺（每个环境PoolActionSize个值）后立即返回，
+// 动作值按动作空间转换（离散空间取整）。
+// 环境下标越界或动作长度不符时返回-2，某个环境的上一个请求尚未Recv时返回-3，两种情况都不会提交任何动作
+func PoolSend(id int, actions []float64, envIDs []int32) int {
+	pool, ok := getPool(id)
+	if !ok {
+		return -1
+	}
+	if len(actions) != len(envIDs)*pool.actionSize {
+		return -2
+	}
+	for _, envID := range envIDs {
+		if envID < 0 || int(envID) >= len(pool.slots) {
+			return -2
+		}
+	}
+	for k, envID := range envIDs {
+		if !pool.slots[envID].busy.CompareAndSwap(false, true) {
+			for _, sent := range envIDs[:k] {
+				pool.release(int(sent))
+			}
+			return -3
+		}
+	}
+	pool.pending.Add(int64(len(envIDs)))
+	for k, envID := range envIDs {
+		values := append([]float64(nil), actions[k*pool.actionSize:(k+1)*pool.actionSize]...)
+		action := core.NewActionFromValues(pool.space, values)
+		go pool.run(int(envID), action)
+	}
+	return 0
+}
+
+// PoolRecv 等待batchSize个环境完成，将结果按完成顺序写入C数组：
+// obs（batchSize*PoolObservationSize个double）、rewards（double）、terminated与truncated（char 0/1）、
+// envIDs与elapsed（int，环境下标与当前回合步数）。返回写入的环境数；
+// 有环境步进失败时仍写入其余结果，该环境的行为全零并返回-2；未完成的请求不足batchSize个时返回-4
+func PoolRecv(id int, obs, rewards, terminated, truncated, envIDs, elapsed unsafe.Pointer) int {
+	return poolRecv(id, obs, rewards, terminated, truncated, envIDs, elapsed, false)
+}
+
+// PoolRecv32 与PoolRecv相同，但obs与rewards为C float数组，dtype为float32的环境无需转换
+func PoolRecv32(id int, obs, rewards, terminated, truncated, envIDs, elapsed unsafe.Pointer) int {
+	return poolRecv(id, obs, rewards, terminated, truncated, envIDs, elapsed, true)
+}
+
+func poolRecv(id int, obs, rewards, terminated, truncated, envIDs, elapsed unsafe.Pointer, f32 bool) int {
+	pool, ok := getPool(id)
+	if !ok {
+		return -1
+	}
+	if pool.pending.Load() < int64(pool.batchSize) {
+		return -4
+	}
+	n := pool.batchSize
+	term := unsafe.Slice((*byte)(terminated), n)
+	trunc := unsafe.Slice((*byte)(truncated), n)
+	ids := unsafe.Slice((*int32)(envIDs), n)
+	steps := unsafe.Slice((*int32)(elapsed), n)
+	failed := false
+	for k := 0; k < n; k++ {
+		i := <-pool.results
+		slot := &pool.slots[i]
+		ids[k] = int32(i)
+		steps[k] = int32(slot.elapsed)
+		term[k], trunc[k] = boolByte(slot.terminated), boolByte(slot.truncated)
+		if f32 {
+			row := unsafe.Slice((*float32)(unsafe.Add(obs, k*pool.obsSize*4)), pool.obsSize)
+			fillRow32(row, slot)
+			unsafe.Slice((*float32)(rewards), n)[k] = float32(slot.reward)
+		} else {
+			row := unsafe.Slice((*float64)(unsafe.Add(obs, k*pool.obsSize*8)), pool.obsSize)
+			fillRow(row, slot)
+			unsafe.Slice((*float64)(rewards), n)[k] = slot.reward
+		}
+		failed = failed || slot.failed
+		pool.release(i)
+	}
+	pool.pending.Add(-int64(n))
+	if failed {
+		return -2
+	}
+	return n
+}
+
+// run 步进（或重置）第i个环境并把结果写入其slot，完成后将下标放入results
+func (p *Pool) run(i int, action core.Action) {
+	start := time.Now()
+	slot := &p.slots[i]
+	ctx := context.Background()
+	slot.failed = false
+	var observations []core.Observation
+	var err error
+	stepped := false
+	if slot.needsReset {
+		observations, err = slot.env.Reset(ctx)
+		slot.truncation.Reset()
+		slot.needsReset = false
+		slot.reward, slot.terminated, slot.truncated, slot.elapsed = 0, false, false, 0
+	} else {
+		var rewards []float64
+		var dones []bool
+		observations, rewards, dones, err = slot.env.Step(ctx, []core.Action{action})
+		if err == nil {
+			StepLatency.Env.Observe(p.scenario, time.Since(start))
+			stepped = true
+			slot.elapsed++
+			slot.reward = 0
+			if len(rewards) > 0 {
+				slot.reward = rewards[0]
+			}
+			terminated, truncated := slot.truncation.Step(dones, slot.env.GetInfo())
+			if core.AllDone(dones) {
+				slot.terminated, slot.truncated = terminated[0], truncated[0]
+			} else {
+				slot.terminated, slot.truncated = false, false
+			}
+			slot.needsReset = slot.terminated || slot.truncated
+		}
+	}
+	if err != nil {
+		// 失败的环境下一次Send时重新开始回合
+		slot.failed, slot.needsReset = true, true
+		slot.terminated, slot.truncated = false, false
+		slot.obs, slot.obs32 = slot.obs[:0], slot.obs32[:0]
+	} else if len(observations) > 0 {
+		if o, ok := observations[0].(core.Float32Observation); ok && o.GetData32() != nil {
+			slot.obs32, slot.obs = AppendObservations32(slot.obs32[:0], observations), slot.obs[:0]
+		} else {
+			slot.obs, slot.obs32 = AppendObservations(slot.obs[:0], observations), slot.obs32[:0]
+		}
+	}
+	core.ReleaseObservations(observations)
+	if stepped {
+		StepLatency.Request.Observe(p.scenario, time.Since(start))
+	}
+	p.results <- i
+}
+
+// release 结束第i个环境的请求，之后才能再次Send
+func (p *Pool) release(i int) {
+	p.slots[i].busy.Store(false)
+}
+
+// close 关闭池中已创建的环境
+func (p *Pool) close() {
+	for i := range p.slots {
+		if env := p.slots[i].env; env != nil {
+			env.Close()
+		}
+	}
+}
+
+// ClosePool 等待未完成的请求后关闭并移除环境池
+func ClosePool(id int) {
+	poolMu.Lock()
+	pool, ok := Pools[id]
+	delete(Pools, id)
+	poolMu.Unlock()
+	if !ok {
+		return
+	}
+	for pending := pool.pending.Load(); pending > 0; pending-- {
+		<-pool.results
+	}
+	pool.close()
+}
+
+// fillRow 将环境的观察写入一行，超出部分截断、不足部分补零
+func fillRow(row []float64, slot *poolSlot) {
+	n := 0
+	if len(slot.obs32) > 0 {
+		for ; n < len(row) && n < len(slot.obs32); n++ {
+			row[n] = float64(slot.obs32[n])
+		}
+	} else {
+		n = copy(row, slot.obs)
+	}
+	clear(row[n:])
+}
+
+// fillRow32 与fillRow相同，写入float32行
+func fillRow32(row []float32, slot *poolSlot) {
+	n := 0
+	if len(slot.obs32) > 0 {
+		n = copy(row, slot.obs32)
+	} else {
+		for ; n < len(row) && n < len(slot.obs); n++ {
+			row[n] = float32(slot.obs[n])
+		}
+	}
+	clear(row[n:])
+}
+
+func boolByte(b bool) byte {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,66 @@
+package pybridge
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/jelech/rl_env_engine/scenarios"
+)
+
+// BenchmarkPoolStep 测量每个内置场景经同步模式的单环境池（PoolSend后PoolRecv）单步的耗时与内存分配，
+// 动作按动作空间转换，与Python端EnvPool兼容接口的路径相同；回合结束的环境在下一次Send时重置
+func BenchmarkPoolStep(b *testing.B) {
+	for _, scenario := range scenarios.All() {
+		name := scenario.GetName()
+		b.Run(name, func(b *testing.B) {
+			sampled := sampleBridgeActions(b, scenario)
+			id := CreatePool(name, "{}", 1, 1)
+			if id < 0 {
+				b.Skipf("CreatePool returned %d", id)
+			}
+			defer ClosePool(id)
+
+			obs := make([]float64, PoolObservationSize(id))
+			if len(obs) == 0 {
+				obs = make([]float64, 1)
+			}
+			rewards := make([]float64, 1)
+			terminated, truncated := make([]byte, 1), make([]byte, 1)
+			envIDs, elapsed := make([]int32, 1), make([]int32, 1)
+			recv := func() int {
+				return PoolRecv(id, unsafe.Pointer(&obs[0]), unsafe.Pointer(&rewards[0]), unsafe.Pointer(&terminated[0]),
+					unsafe.Pointer(&truncated[0]), unsafe.Pointer(&envIDs[0]), unsafe.Pointer(&elapsed[0]))
+			}
+			if code := PoolAsyncReset(id); code != 0 || recv() != 1 {
+				b.Fatalf("reset failed")
+			}
+			actions := make([][]float64, len(sampled))
+			for i, action := range sampled {
+				actions[i] = action.values()
+				if len(actions[i]) != PoolActionSize(id) {
+					b.Skipf("%d action values, the pool expects %d", len(actions[i]), PoolActionSize(id))
+				}
+			}
+			send := []int32{0}
+			step := func(action []float64) int {
+				if code := PoolSend(id, action, send); code != 0 {
+					return code
+				}
+				return recv()
+			}
+			for _, action := range actions {
+				if n := step(action); n != 1 {
+					b.Skipf("random actions: PoolRecv returned %d", n)
+				}
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if n := step(actions[i%len(actions)]); n != 1 {
+					b.Fatalf("PoolRecv returned %d", n)
+				}
+			}
+		})
+	}
+}
@@ -1,6 +1,6 @@
 """rl_env_engine_client
 
-通用gRPC环境客户端包，为仿真引擎提供标准化的强化学习环境接口。
+通用环境客户端包（gRPC、HTTP与共享内存），为仿真引擎提供标准化的强化学习环境接口。
 
 本地安装（从仓库根目录）:
     pip install -e python_client
@@ -9,15 +9,29 @@
     pip install -e "python_client[rl]"
 
 使用示例:
-    from rl_env_engine_client import GrpcEnv
+    from rl_env_engine_client import GrpcEnv, RemoteEnv
+    env = RemoteEnv("cartpole", transport="http")
+
+SB3向量化环境（需要rl扩展依赖）:
+    from rl_env_engine_client.vec_env import RemoteVecEnv
 """
 
 __all__ = [
     "GrpcEnv",
+    "HttpEnv",
+    "RemoteEnv",
+    "RemoteError",
+    "ShmEnv",
     "SimulationGrpcClient",
+    "hybrid_action_parts",
+    "space_from_json",
 ]
 
 __version__ = "0.1.0"
 
-from .grpc_env import GrpcEnv  # noqa: E402
+from .grpc_env import GrpcEnv, hybrid_action_parts, space_from_json  # noqa: E402
+from .http_env import HttpEnv  # noqa: E402
+from .remote import RemoteEnv  # noqa: E402
+from .shm_env import ShmEnv  # noqa: E402
 from .grpc_client import SimulationGrpcClient  # noqa: E402
+from .errors import RemoteError  # noqa: E402
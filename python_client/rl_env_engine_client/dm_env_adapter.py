@@ -0,0 +1,106 @@
+#!/usr/bin/env python3
+"""
+dm_env适配器
+将远程gRPC仿真环境包装为dm_env.Environment，供Acme等DeepMind生态的库直接使用。
+需要额外安装 dm-env：pip install -e "python_client[dm]"
+"""
+
+from typing import Any, Dict, Optional
+
+import dm_env
+import numpy as np
+from dm_env import specs
+from gymnasium import spaces
+
+from .grpc_env import GrpcEnv, simulation_pb2
+
+
+def _space_to_spec(space: spaces.Space, name: str) -> specs.Array:
+    """将gymnasium空间转换为dm_env的spec"""
+    if isinstance(space, spaces.Discrete):
+        return specs.DiscreteArray(num_values=int(space.n), dtype=np.int64, name=name)
+    if isinstance(space, spaces.Box):
+        return specs.BoundedArray(space.shape, space.dtype, space.low, space.high, name=name)
+    if isinstance(space, spaces.MultiDiscrete):
+        start = getattr(space, "start", np.zeros(space.shape, dtype=space.dtype))
+        return specs.BoundedArray(space.shape, space.dtype, start, start + space.nvec - 1, name=name)
+    if isinstance(space, spaces.MultiBinary):
+        return specs.BoundedArray(space.shape, space.dtype, 0, 1, name=name)
+    return specs.Array(space.shape, space.dtype, name=name)
+
+
+class DmEnv(dm_env.Environment):
+    """
+    dm_env环境包装器
+
+    以dm_env选项创建远程环境，步进响应中的step_type与discount直接转换为TimeStep：
+    终止的LAST时间步折扣为0，因截断结束的LAST时间步折扣为1。
+    与dm_env约定一致，回合结束后再次调用step会先重置环境并返回FIRST时间步。
+    """
+
+    def __init__(
+        self,
+        scenario: str,
+        host: str = "127.0.0.1",
+        port: int = 9090,
+        env_id: Optional[str] = None,
+        config: Optional[Dict[str, Any]] = None,
+        verbose: bool = False,
+    ):
+        """
+        Args:
+            scenario: 服务器端的场景名称
+            host: gRPC服务器地址
+            port: gRPC服务器端口
+            env_id: 环境实例ID（如果为None则自动生成）
+            config: 传递给服务器的配置参数
+        """
+        config = dict(config or {})
+        config.setdefault("dm_env", True)
+        self._env = GrpcEnv(scenario, host=host, port=port, env_id=env_id, config=config, verbose=verbose)
+        self._reset_next_step = True
+
+    def reset(self) -> dm_env.TimeStep:
+        """重置环境，返回FIRST时间步"""
+        observation, _ = self._env.reset()
+        self._reset_next_step = False
+        return dm_env.restart(observation)
+
+    def step(self, action) -> dm_env.TimeStep:
+        """执行一步，返回MID或LAST时间步"""
+        if self._reset_next_step:
+            return self.reset()
+
+        request = simulation_pb2.StepEnvironmentRequest(
+            env_id=self._env.env_id, actions=self._env._convert_actions_to_proto(action)
+        )
+        response = self._env.client.StepEnvironment(request)
+        if not response.observations:
+            raise RuntimeError("No observations received from environment step")
+
+        observation = self._env._proto_observation(response.observations[0])
+        reward = float(response.rewards[0]) if response.rewards else 0.0
+        if response.step_type:
+            last = response.step_type[0] == simulation_pb2.LAST
+            discount = float(response.discount[0])
+        else:
+            # 服务端未开启dm_env时无法区分截断，结束一律视为终止
+            last = bool(response.done[0]) if response.done else False
+            discount = 0.0 if last else 1.0
+
+        if not last:
+            return dm_env.transition(reward, observation, discount)
+        self._reset_next_step = True
+        if discount == 0.0:
+            return dm_env.termination(reward, observation)
+        return dm_env.truncation(reward, observation, discount)
+
+    def observation_spec(self) -> specs.Array:
+        return _space_to_spec(self._env.observation_space, "observation")
+
+    def action_spec(self) -> specs.Array:
+        return _space_to_spec(self._env.action_space, "action")
+
+    def close(self):
+        """关闭远程环境与连接"""
+        self._env.close()
@@ -0,0 +1,210 @@
+#!/usr/bin/env python3
+"""
+EnvPool兼容接口
+通过ctypes加载gen_so生成的共享库，提供与EnvPool（gymnasium风格）相同的批量异步接口：
+async_reset / send / recv 以及同步的 reset / step，可替换现有训练脚本中的envpool.make。
+
+生成并编译共享库（在项目根目录）:
+    go run ./cmd/gen_so -pkg github.com/jelech/rl_env_engine/scenarios/cartpole -name CartPole -out build/cartpole
+    go build -buildmode=c-shared -o libcartpole.so ./build/cartpole
+
+使用示例:
+    from rl_env_engine_client.envpool_compat import make
+    env = make("./libcartpole.so", "cartpole", num_envs=8, batch_size=4, seed=1)
+    env.async_reset()
+    while True:
+        obs, rew, terminated, truncated, info = env.recv()
+        env.send(policy(obs), info["env_id"])
+"""
+
+import ctypes
+import json
+from typing import Any, Dict, Optional
+
+import numpy as np
+from gymnasium import spaces
+
+_c_double_p = ctypes.POINTER(ctypes.c_double)
+_c_float_p = ctypes.POINTER(ctypes.c_float)
+_c_char_p = ctypes.POINTER(ctypes.c_char)
+_c_int_p = ctypes.POINTER(ctypes.c_int)
+
+
+def _load(lib_path: str) -> ctypes.CDLL:
+    """加载共享库并声明池接口的签名"""
+    lib = ctypes.CDLL(lib_path)
+    lib.CreatePool.argtypes = [ctypes.c_char_p, ctypes.c_char_p, ctypes.c_int, ctypes.c_int]
+    lib.CreatePool.restype = ctypes.c_int
+    for name in ("PoolObservationSize", "PoolActionSize", "PoolAsyncReset"):
+        getattr(lib, name).argtypes = [ctypes.c_int]
+        getattr(lib, name).restype = ctypes.c_int
+    lib.PoolSend.argtypes = [ctypes.c_int, _c_double_p, _c_int_p, ctypes.c_int]
+    lib.PoolSend.restype = ctypes.c_int
+    lib.PoolRecv.argtypes = [ctypes.c_int, _c_double_p, _c_double_p, _c_char_p, _c_char_p, _c_int_p, _c_int_p]
+    lib.PoolRecv.restype = ctypes.c_int
+    lib.PoolRecv32.argtypes = [ctypes.c_int, _c_float_p, _c_float_p, _c_char_p, _c_char_p, _c_int_p, _c_int_p]
+    lib.PoolRecv32.restype = ctypes.c_int
+    lib.ClosePool.argtypes = [ctypes.c_int]
+    lib.ClosePool.restype = None
+    lib.GetStepLatency.argtypes = [ctypes.c_char_p, ctypes.c_int]
+    lib.GetStepLatency.restype = ctypes.c_int
+    return lib
+
+
+def _ptr(array: np.ndarray, ctype):
+    return array.ctypes.data_as(ctypes.POINTER(ctype))
+
+
+class EnvPool:
+    """
+    EnvPool兼容的环境池
+
+    num_envs个环境在Go侧并行步进，recv返回最先完成的batch_size个环境的结果，
+    info中的env_id与elapsed_step与EnvPool相同。回合结束的环境在下一次send时自动重置，
+    返回初始观察且奖励为0。动作按环境平铺传入，离散动作传入整数即可
+    """
+
+    def __init__(
+        self,
+        lib_path: str,
+        scenario: str,
+        num_envs: int = 1,
+        batch_size: Optional[int] = None,
+        seed: Optional[int] = None,
+        config: Optional[Dict[str, Any]] = None,
+        float32: bool = True,
+        observation_space: Optional[spaces.Space] = None,
+        action_space: Optional[spaces.Space] = None,
+    ):
+        """
+        Args:
+            lib_path: gen_so生成的共享库路径
+            scenario: 共享库中注册的场景名称
+            num_envs: 环境个数
+            batch_size: 每次recv返回的环境个数，默认等于num_envs（同步模式）
+            seed: 场景种子，第i个环境使用seed+i
+            config: 传递给场景的配置参数
+            float32: 观察与奖励以float32返回（与EnvPool一致），否则为float64
+            observation_space/action_space: 空间定义，默认按共享库报告的长度构造Box
+        """
+        self._lib = _load(lib_path)
+        config = dict(config or {})
+        if seed is not None:
+            config["seed"] = seed
+        self.num_envs = num_envs
+        self.batch_size = batch_size or num_envs
+        self._id = self._lib.CreatePool(scenario.encode(), json.dumps(config).encode(), num_envs, self.batch_size)
+        if self._id < 0:
+            raise RuntimeError(f"CreatePool('{scenario}') failed with code {self._id}")
+
+        self._obs_size = self._lib.PoolObservationSize(self._id)
+        self._action_size = self._lib.PoolActionSize(self._id)
+        self._float32 = float32
+        dtype = np.float32 if float32 else np.float64
+        self.observation_space = observation_space or spaces.Box(-np.inf, np.inf, (self._obs_size,), dtype)
+        self.action_space = action_space or spaces.Box(-np.inf, np.inf, (self._action_size,), np.float64)
+
+        self._obs = np.zeros((self.batch_size, self._obs_size), dtype=dtype)
+        self._rew = np.zeros(self.batch_size, dtype=dtype)
+        self._terminated = np.zeros(self.batch_size, dtype=np.uint8)
+        self._truncated = np.zeros(self.batch_size, dtype=np.uint8)
+        self._env_id = np.zeros(self.batch_size, dtype=np.int32)
+        self._elapsed = np.zeros(self.batch_size, dtype=np.int32)
+
+    def async_reset(self) -> None:
+        """在后台重置所有环境，结果由之后的recv返回"""
+        code = self._lib.PoolAsyncReset(self._id)
+        if code != 0:
+            raise RuntimeError(f"PoolAsyncReset failed with code {code}")
+
+    def send(self, action, env_id=None) -> None:
+        """提交env_id中各环境的动作后立即返回，env_id默认为0..len(action)-1"""
+        action = np.ascontiguousarray(action, dtype=np.float64).reshape(-1)
+        if env_id is None:
+            env_id = np.arange(len(action) // max(self._action_size, 1), dtype=np.int32)
+        env_id = np.ascontiguousarray(env_id, dtype=np.int32).reshape(-1)
+        code = self._lib.PoolSend(self._id, _ptr(action, ctypes.c_double), _ptr(env_id, ctypes.c_int), len(env_id))
+        if code != 0:
+            raise RuntimeError(f"PoolSend failed with code {code}")
+
+    def recv(self):
+        """等待batch_size个环境完成，返回(obs, reward, terminated, truncated, info)"""
+        if self._float32:
+            code = self._lib.PoolRecv32(
+                self._id,
+                _ptr(self._obs, ctypes.c_float),
+                _ptr(self._rew, ctypes.c_float),
+                _ptr(self._terminated, ctypes.c_char),
+                _ptr(self._truncated, ctypes.c_char),
+                _ptr(self._env_id, ctypes.c_int),
+                _ptr(self._elapsed, ctypes.c_int),
+            )
+        else:
+            code = self._lib.PoolRecv(
+                self._id,
+                _ptr(self._obs, ctypes.c_double),
+                _ptr(self._rew, ctypes.c_double),
+                _ptr(self._terminated, ctypes.c_char),
+                _ptr(self._truncated, ctypes.c_char),
+                _ptr(self._env_id, ctypes.c_int),
+                _ptr(self._elapsed, ctypes.c_int),
+            )
+        if code < 0:
+            raise RuntimeError(f"PoolRecv failed with code {code}")
+        info = {"env_id": self._env_id.copy(), "elapsed_step": self._elapsed.copy()}
+        return (
+            self._obs.copy(),
+            self._rew.copy(),
+            self._terminated.astype(bool),
+            self._truncated.astype(bool),
+            info,
+        )
+
+    def reset(self, env_id=None):
+        """同步重置所有环境，返回按env_id排序的(obs, info)；仅支持batch_size等于num_envs"""
+        if env_id is not None or self.batch_size != self.num_envs:
+            raise NotImplementedError("synchronous reset requires batch_size == num_envs and resets all envs")
+        self.async_reset()
+        obs, _, _, _, info = self._sorted(self.recv())
+        return obs, info
+
+    def step(self, action, env_id=None):
+        """send之后立即recv，返回(obs, reward, terminated, truncated, info)；
+        batch_size等于num_envs时结果按env_id排序"""
+        self.send(action, env_id)
+        result = self.recv()
+        if self.batch_size == self.num_envs:
+            return self._sorted(result)
+        return result
+
+    @staticmethod
+    def _sorted(result):
+        """将recv的结果按env_id排序"""
+        obs, rew, terminated, truncated, info = result
+        order = np.argsort(info["env_id"])
+        return obs[order], rew[order], terminated[order], truncated[order], {k: v[order] for k, v in info.items()}
+
+    def step_latency(self) -> Dict[str, Any]:
+        """获取共享库中按场景的步进延迟（p50/p95/p99，毫秒）与吞吐，结构与HTTP /metrics相同：
+        step为环境Step本身的耗时，request为池中一个环境一步的耗时"""
+        size = 4096
+        while True:
+            buf = ctypes.create_string_buffer(size)
+            n = self._lib.GetStepLatency(buf, size)
+            if n <= size:
+                return json.loads(buf.raw[:n])
+            size = n
+
+    def close(self) -> None:
+        """等待未完成的步进后关闭所有环境"""
+        if self._id > 0:
+            self._lib.ClosePool(self._id)
+            self._id = -1
+
+    def __len__(self) -> int:
+        return self.num_envs
+
+
+def make(lib_path: str, scenario: str, **kwargs) -> EnvPool:
+    """与envpool.make对应的构造函数，参数见EnvPool"""
+    return EnvPool(lib_path, scenario, **kwargs)
@@ -0,0 +1,93 @@
+"""
+服务端错误的统一表示：gRPC状态详情中的simulation.Error、带success字段响应的error，
+以及HTTP ErrorResponse的status、message与details，都转换为RemoteError
+"""
+from typing import Any, Dict, Iterator, Mapping, Optional, Tuple
+
+try:
+    from . import simulation_pb2  # type: ignore
+except Exception:  # pragma: no cover
+    import simulation_pb2  # type: ignore
+
+# gRPC在此尾部元数据中携带序列化的google.rpc.Status
+_STATUS_DETAILS_KEY = "grpc-status-details-bin"
+_ERROR_TYPE_URL = "type.googleapis.com/simulation.Error"
+
+
+class RemoteError(RuntimeError):
+    """服务端返回的错误：code为规范错误码（如NOT_FOUND），details为附加的键值（如env_id）"""
+
+    def __init__(self, code: str, message: str, details: Optional[Mapping[str, str]] = None, context: str = ""):
+        super().__init__(f"{context}{code}: {message}")
+        self.code = code
+        self.message = message
+        self.details: Dict[str, str] = dict(details or {})
+
+
+def from_proto(error, context: str = "") -> RemoteError:
+    """将simulation.Error转换为RemoteError，context为错误信息的前缀"""
+    return RemoteError(error.code, error.message, error.details, context)
+
+
+def from_rpc_error(e) -> RemoteError:
+    """将grpc.RpcError转换为RemoteError，优先使用状态详情中的simulation.Error"""
+    metadata = e.trailing_metadata() if hasattr(e, "trailing_metadata") else None
+    for key, value in metadata or ():
+        if key != _STATUS_DETAILS_KEY:
+            continue
+        for type_url, payload in _status_details(value):
+            if type_url == _ERROR_TYPE_URL:
+                return from_proto(simulation_pb2.Error.FromString(payload))
+    code = e.code().name if hasattr(e, "code") and e.code() is not None else "UNKNOWN"
+    return RemoteError(code, e.details() if hasattr(e, "details") else str(e))
+
+
+def from_error_response(response: Mapping[str, Any], default: str = "", context: str = "") -> RemoteError:
+    """将HTTP ErrorResponse转换为RemoteError，旧服务端没有status时记为UNKNOWN"""
+    return RemoteError(
+        response.get("status") or "UNKNOWN", response.get("message", default), response.get("details"), context
+    )
+
+
+def from_api_error(error: Mapping[str, Any], context: str = "") -> RemoteError:
+    """将HTTP响应中的error字段（APIError，与simulation.Error字段相同）转换为RemoteError"""
+    return RemoteError(error["code"], error["message"], error.get("details"), context)
+
+
+def _status_details(data: bytes) -> Iterator[Tuple[str, bytes]]:
+    """从序列化的google.rpc.Status中取出各个详情（Any）的type_url与value"""
+    for number, value in _fields(data):
+        if number == 3:
+            any_fields = dict(_fields(value))
+            yield any_fields.get(1, b"").decode(), any_fields.get(2, b"")
+
+
+def _fields(data: bytes) -> Iterator[Tuple[int, Any]]:
+    """逐个解析protobuf线格式的字段，长度前缀字段返回bytes，varint字段返回int"""
+    i = 0
+    while i < len(data):
+        key, i = _varint(data, i)
+        number, wire_type = key >> 3, key & 7
+        if wire_type == 0:
+            value, i = _varint(data, i)
+        elif wire_type == 2:
+            length, i = _varint(data, i)
+            value, i = data[i : i + length], i + length
+        elif wire_type == 1:
+            value, i = data[i : i + 8], i + 8
+        elif wire_type == 5:
+            value, i = data[i : i + 4], i + 4
+        else:
+            return
+        yield number, value
+
+
+def _varint(data: bytes, i: int) -> Tuple[int, int]:
+    result, shift = 0, 0
+    while True:
+        b = data[i]
+        i += 1
+        result |= (b & 0x7F) << shift
+        if not b & 0x80:
+            return result, i
+        shift += 7
@@ -2,6 +2,8 @@
 """
 gRPC客户端示例，用于与仿真服务器进行通信
 """
+import collections
+import json
 import time
 import grpc
 from google.protobuf.json_format import MessageToDict
@@ -19,29 +21,214 @@ except Exception:  # pragma: no cover
         ) from e
 
 
+def _values_dict(struct, typed):
+    """合并Struct与typed_values开启时的带类型标量，整数保持为int"""
+    values = MessageToDict(struct) if struct else {}
+    for key, value in typed.items():
+        kind = value.WhichOneof("kind")
+        values[key] = getattr(value, kind) if kind else None
+    return values
+
+
+def _result_dict(response):
+    """将带success字段的响应转换为字典，失败时error为服务端的Error（code、message与details）"""
+    result = {"success": response.success, "message": response.message}
+    if response.HasField("error"):
+        error = response.error
+        result["error"] = {"code": error.code, "message": error.message, "details": dict(error.details)}
+    return result
+
+
+def _latency_dict(summary):
+    """将LatencySummary转换为与HTTP /metrics相同的字典"""
+    return {field: getattr(summary, field) for field in ("count", "mean_ms", "p50_ms", "p95_ms", "p99_ms", "max_ms", "per_second")}
+
+
+# 请求元数据中携带会话ID的键，与服务端SessionMetadataKey一致
+SESSION_METADATA_KEY = "session-id"
+
+# 请求元数据中携带管理令牌的键，与服务端AdminTokenMetadataKey一致
+ADMIN_TOKEN_METADATA_KEY = "admin-token"
+
+# 请求元数据中携带租户API密钥的键，与服务端APIKeyMetadataKey一致
+API_KEY_METADATA_KEY = "api-key"
+
+
+class _CallDetails(
+    collections.namedtuple(
+        "_CallDetails", ("method", "timeout", "metadata", "credentials", "wait_for_ready", "compression")
+    ),
+    grpc.ClientCallDetails,
+):
+    pass
+
+
+class MetadataInterceptor(grpc.UnaryUnaryClientInterceptor, grpc.StreamStreamClientInterceptor):
+    """在每个请求的元数据中附加固定的键值对"""
+
+    def __init__(self, metadata):
+        self.metadata = list(metadata)
+
+    def _details(self, details):
+        metadata = list(details.metadata or []) + self.metadata
+        return _CallDetails(
+            details.method,
+            details.timeout,
+            metadata,
+            details.credentials,
+            getattr(details, "wait_for_ready", None),
+            getattr(details, "compression", None),
+        )
+
+    def intercept_unary_unary(self, continuation, client_call_details, request):
+        return continuation(self._details(client_call_details), request)
+
+    def intercept_stream_stream(self, continuation, client_call_details, request_iterator):
+        return continuation(self._details(client_call_details), request_iterator)
+
+
+class SessionInterceptor(MetadataInterceptor):
+    """在每个请求的元数据中携带会话ID，使env_id只在该会话内可见"""
+
+    def __init__(self, session_id: str):
+        super().__init__([(SESSION_METADATA_KEY, session_id)])
+        self.session_id = session_id
+
+
+def _curriculum_dict(progress):
+    """将CurriculumProgress转换为与HTTP /curriculum相同的字典"""
+    return {
+        "stage": progress.stage,
+        "stages": progress.stages,
+        "episodes": progress.episodes,
+        "stage_episodes": progress.stage_episodes,
+        "success_rate": progress.success_rate,
+        "window": progress.window,
+        "frozen": progress.frozen,
+        "parameters": dict(progress.parameters),
+    }
+
+
+def _agents_dict(agents):
+    """将开启agent_dict时的agents映射转换为以智能体名称为键的字典"""
+    result = {}
+    for name, step in agents.items():
+        obs = step.observation
+        result[name] = {
+            "observation": list(obs.data_f32 or obs.data),
+            "reward": step.reward,
+            "terminated": step.terminated,
+            "truncated": step.truncated,
+            "info": _values_dict(obs.metadata, obs.typed_metadata),
+        }
+    return result
+
+
+def open_channel(target, options=None, tls=False, ca_file=None):
+    """
+    打开到target的通道，tls为True或指定ca_file时使用TLS
+
+    Args:
+        target: 服务端地址
+        options: 通道选项
+        tls: 是否使用TLS，证书由系统根证书校验
+        ca_file: 校验服务端证书的PEM格式CA证书（或自签名证书）文件，指定时启用TLS
+    """
+    if not tls and ca_file is None:
+        return grpc.insecure_channel(target, options=options)
+    root_certificates = None
+    if ca_file is not None:
+        with open(ca_file, "rb") as f:
+            root_certificates = f.read()
+    return grpc.secure_channel(target, grpc.ssl_channel_credentials(root_certificates), options=options)
+
+
+def intercept(channel, session=None, api_key=None):
+    """按需为通道附加会话ID与租户API密钥"""
+    if api_key:
+        channel = grpc.intercept_channel(channel, MetadataInterceptor([(API_KEY_METADATA_KEY, api_key)]))
+    if session:
+        channel = grpc.intercept_channel(channel, SessionInterceptor(session))
+    return channel
+
+
 class SimulationGrpcClient:
-    def __init__(self, server_address="localhost:9090"):
+    def __init__(self, server_address="localhost:9090", admin_token=None, api_key=None, tls=False, ca_file=None):
         """
         初始化gRPC客户端
 
         Args:
-            server_address: gRPC服务器地址，默认为localhost:9090
+            server_address: gRPC服务器地址，默认为localhost:9090；Unix套接字使用unix:///path/to.sock
+            admin_token: 管理令牌，调用管理接口（list_environments、register_scenario等）时携带
+            api_key: 租户API密钥，服务端配置了租户时每个请求都需携带
+            tls: 服务端启用了TLS时为True
+            ca_file: 校验服务端证书的CA证书文件（如自签名证书），指定时启用TLS
         """
         self.server_address = server_address
+        self.admin_token = admin_token
+        self.api_key = api_key
+        self.tls = tls
+        self.ca_file = ca_file
         self.channel = None
         self.stub = None
+        self.session_id = None
 
     def connect(self):
         """连接到gRPC服务器"""
         try:
-            self.channel = grpc.insecure_channel(self.server_address)
-            self.stub = simulation_pb2_grpc.SimulationServiceStub(self.channel)
+            self.channel = open_channel(
+                self.server_address,
+                options=[
+                    ("grpc.max_send_message_length", 64 * 1024 * 1024),
+                    ("grpc.max_receive_message_length", 64 * 1024 * 1024),
+                ],
+                tls=self.tls,
+                ca_file=self.ca_file,
+            )
+            self.stub = simulation_pb2_grpc.SimulationServiceStub(intercept(self.channel, api_key=self.api_key))
             print(f"Connected to gRPC server at {self.server_address}")
             return True
         except Exception as e:
             print(f"Failed to connect to gRPC server: {e}")
             return False
 
+    def open_session(self, client="", ttl_seconds=0, bind_connection=False):
+        """
+        打开会话，之后本客户端的请求都携带会话ID：env_id只在会话内可见，会话关闭或过期时其环境全部关闭
+
+        Args:
+            client: 客户端名称，仅用于展示
+            ttl_seconds: 空闲超时（秒），0表示服务端默认值
+            bind_connection: 为True时会话随当前连接断开而关闭
+        """
+        try:
+            request = simulation_pb2.OpenSessionRequest(
+                client=client, ttl_seconds=ttl_seconds, bind_connection=bind_connection
+            )
+            response = self.stub.OpenSession(request)
+        except grpc.RpcError as e:
+            print(f"gRPC error in open_session: {e}")
+            return None
+        # 在同一连接上附加会话元数据，bind_connection绑定的连接保持不变
+        self.session_id = response.session_id
+        self.stub = simulation_pb2_grpc.SimulationServiceStub(
+            intercept(self.channel, session=self.session_id, api_key=self.api_key)
+        )
+        return self.session_id
+
+    def close_session(self):
+        """关闭当前会话，返回随会话关闭的环境数"""
+        if self.session_id is None:
+            return 0
+        try:
+            response = self.stub.CloseSession(simulation_pb2.CloseSessionRequest(session_id=self.session_id))
+        except grpc.RpcError as e:
+            print(f"gRPC error in close_session: {e}")
+            return None
+        self.session_id = None
+        self.stub = simulation_pb2_grpc.SimulationServiceStub(intercept(self.channel, api_key=self.api_key))
+        return response.closed_environments
+
     def disconnect(self):
         """断开与gRPC服务器的连接"""
         if self.channel:
@@ -53,13 +240,21 @@ class SimulationGrpcClient:
         try:
             request = simulation_pb2.GetInfoRequest()
             response = self.stub.GetInfo(request)
-            info_dict = MessageToDict(response.info) if response.info else {}
+            info_dict = _values_dict(response.info, response.typed_info)
             return {
                 "scenarios": list(response.scenarios),
                 "env_ids": list(response.env_ids),
                 "info": info_dict,
                 "version": response.version,
                 "name": response.name,
+                # 服务端实现的协议版本与仍支持的最低版本，旧服务端为0
+                "api_version": response.api_version,
+                "min_api_version": response.min_api_version,
+                # 以场景为键的步进延迟与吞吐，结构与HTTP /metrics的step_latency相同
+                "step_latency": {
+                    scenario: {"step": _latency_dict(latency.step), "request": _latency_dict(latency.request)}
+                    for scenario, latency in response.step_latency.items()
+                },
             }
         except grpc.RpcError as e:
             print(f"gRPC error in get_info: {e}")
@@ -75,12 +270,12 @@ class SimulationGrpcClient:
             config: 配置字典
         """
         try:
-            if config is None:
-                config = {}
+            # 默认开启typed_values，元数据与info中的整数、布尔等标量保持原类型
+            config = {"typed_values": True, **(config or {})}
 
             request = simulation_pb2.CreateEnvironmentRequest(env_id=env_id, scenario=scenario, config=config)
             response = self.stub.CreateEnvironment(request)
-            return {"success": response.success, "message": response.message}
+            return _result_dict(response)
         except grpc.RpcError as e:
             print(f"gRPC error in create_environment: {e}")
             return None
@@ -98,11 +293,14 @@ class SimulationGrpcClient:
 
             observations = []
             for obs in response.observations:
-                metadata_dict = MessageToDict(obs.metadata) if obs.metadata else {}
-                observations.append({"data": list(obs.data), "metadata": metadata_dict})
-
-            info_dict = MessageToDict(response.info) if response.info else {}
-            return {"observations": observations, "info": info_dict}
+                metadata_dict = _values_dict(obs.metadata, obs.typed_metadata)
+                observations.append({"data": list(obs.data_f32 or obs.data), "metadata": metadata_dict, "agent_id": obs.agent_id})
+
+            info_dict = _values_dict(response.info, response.typed_info)
+            result = {"observations": observations, "agent_ids": list(response.agent_ids), "info": info_dict}
+            if response.agents:
+                result["agents"] = _agents_dict(response.agents)
+            return result
         except grpc.RpcError as e:
             print(f"gRPC error in reset_environment: {e}")
             return None
@@ -123,16 +321,29 @@ class SimulationGrpcClient:
 
             observations = []
             for obs in response.observations:
-                metadata_dict = MessageToDict(obs.metadata) if obs.metadata else {}
-                observations.append({"data": list(obs.data), "metadata": metadata_dict})
+                metadata_dict = _values_dict(obs.metadata, obs.typed_metadata)
+                observations.append({"data": list(obs.data_f32 or obs.data), "metadata": metadata_dict, "agent_id": obs.agent_id})
 
-            info_dict = MessageToDict(response.info) if response.info else {}
-            return {
+            info_dict = _values_dict(response.info, response.typed_info)
+            result = {
                 "observations": observations,
+                # rewards、done等的下标i对应agent_ids[i]
+                "agent_ids": list(response.agent_ids),
                 "rewards": list(response.rewards),
                 "done": list(response.done),
+                "terminated": list(response.terminated),
+                "truncated": list(response.truncated),
                 "info": info_dict,
             }
+            if response.final_observations:
+                # 自动重置前各智能体的最后观察，observations已是新回合的初始观察
+                result["final_observations"] = [
+                    {"data": list(obs.data_f32 or obs.data), "metadata": _values_dict(obs.metadata, obs.typed_metadata), "agent_id": obs.agent_id}
+                    for obs in response.final_observations
+                ]
+            if response.agents:
+                result["agents"] = _agents_dict(response.agents)
+            return result
         except grpc.RpcError as e:
             print(f"gRPC error in step_environment: {e}")
             return None
@@ -147,11 +358,255 @@ class SimulationGrpcClient:
         try:
             request = simulation_pb2.CloseEnvironmentRequest(env_id=env_id)
             response = self.stub.CloseEnvironment(request)
-            return {"success": response.success, "message": response.message}
+            return _result_dict(response)
         except grpc.RpcError as e:
             print(f"gRPC error in close_environment: {e}")
             return None
 
+    def get_metadata(self, env_id):
+        """
+        获取环境元数据（奖励范围、最大步数、渲染模式等）
+
+        Args:
+            env_id: 环境ID
+        """
+        try:
+            request = simulation_pb2.GetMetadataRequest(env_id=env_id)
+            response = self.stub.GetMetadata(request)
+            return {
+                "reward_range": tuple(response.reward_range),
+                "max_episode_steps": response.max_episode_steps,
+                "render_modes": list(response.render_modes),
+                "nondeterministic": response.nondeterministic,
+            }
+        except grpc.RpcError as e:
+            print(f"gRPC error in get_metadata: {e}")
+            return None
+
+    def debug_environment(self, env_id):
+        """
+        导出环境的内部状态（检查点、StateDumper的结果与各包装层），用于排查异常的奖励或结束标志
+
+        Args:
+            env_id: 环境ID
+        """
+        try:
+            request = simulation_pb2.DebugEnvironmentRequest(env_id=env_id)
+            return json.loads(self.stub.DebugEnvironment(request).dump_json)
+        except grpc.RpcError as e:
+            print(f"gRPC error in debug_environment: {e}")
+            return None
+
+    def get_curriculum(self, env_id):
+        """
+        获取以curriculum配置创建的环境的课程进度
+
+        Args:
+            env_id: 环境ID
+        """
+        try:
+            request = simulation_pb2.GetCurriculumRequest(env_id=env_id)
+            return _curriculum_dict(self.stub.GetCurriculum(request))
+        except grpc.RpcError as e:
+            print(f"gRPC error in get_curriculum: {e}")
+            return None
+
+    def set_curriculum_stage(self, env_id, stage, frozen=False):
+        """
+        切换环境的课程阶段，新阶段的参数在下次reset时生效
+
+        Args:
+            env_id: 环境ID
+            stage: 目标阶段，从0开始
+            frozen: 为True时停止按回合数或成功率自动推进
+        """
+        try:
+            request = simulation_pb2.SetCurriculumStageRequest(env_id=env_id, stage=stage, frozen=frozen)
+            return _curriculum_dict(self.stub.SetCurriculumStage(request))
+        except grpc.RpcError as e:
+            print(f"gRPC error in set_curriculum_stage: {e}")
+            return None
+
+    def evaluate_policy(self, scenario, model=None, episodes=10, max_steps=0, config=None, policy="onnx", seed=0):
+        """
+        在服务端以ONNX策略（或内置基线策略）运行若干回合，推理在服务端完成
+
+        Args:
+            scenario: 场景名称
+            model: ONNX模型文件路径或序列化后的字节，使用内置基线策略时不需要
+            episodes: 回合数
+            max_steps: 每回合最大步数，0表示使用服务端默认值
+            config: 配置字典
+            policy: "onnx"使用model；内置基线"random"在动作空间中均匀随机采样，"zero"始终给出最接近零的动作，
+                "heuristic"为经典场景（cartpole、pendulum等）的手写控制器
+            seed: random策略的随机数种子
+        """
+        if isinstance(model, str):
+            with open(model, "rb") as f:
+                model = f.read()
+        try:
+            request = simulation_pb2.EvaluatePolicyRequest(
+                scenario=scenario,
+                config=config or {},
+                model=model or b"",
+                episodes=episodes,
+                max_steps=max_steps,
+                policy=policy,
+                seed=seed,
+            )
+            response = self.stub.EvaluatePolicy(request)
+            return {
+                "returns": list(response.returns),
+                "lengths": list(response.lengths),
+                "truncated": response.truncated,
+                "mean_return": response.mean_return,
+                "std_return": response.std_return,
+                "mean_length": response.mean_length,
+                "total_steps": response.total_steps,
+                "elapsed_seconds": response.elapsed_seconds,
+            }
+        except grpc.RpcError as e:
+            print(f"gRPC error in evaluate_policy: {e}")
+            return None
+
+    def register_scenario(self, name, wasm, description=""):
+        """
+        上传实现环境ABI的WASM模块并注册为场景，服务端需以--allow-wasm与--admin-token启动；
+        场景对所有客户端可见，因此需以admin_token创建客户端
+
+        Args:
+            name: 场景名称，不能与服务端已有的场景（含此前上传的场景）重名
+            wasm: WASM模块文件路径或二进制内容
+            description: 场景描述
+
+        Returns:
+            成功时返回True，失败（如重名）时返回None
+        """
+        if isinstance(wasm, str):
+            with open(wasm, "rb") as f:
+                wasm = f.read()
+        try:
+            request = simulation_pb2.RegisterScenarioRequest(name=name, description=description, wasm=wasm)
+            self.stub.RegisterScenario(request, metadata=self._admin_metadata())
+            return True
+        except grpc.RpcError as e:
+            print(f"gRPC error in register_scenario: {e}")
+            return None
+
+
+    def _admin_metadata(self):
+        return [(ADMIN_TOKEN_METADATA_KEY, self.admin_token)] if self.admin_token else None
+
+    def list_environments(self):
+        """列出服务端的全部环境（含各会话的环境）及其所属会话、客户端、存活时间与步数（管理接口）"""
+        try:
+            response = self.stub.ListEnvironments(
+                simulation_pb2.ListEnvironmentsRequest(), metadata=self._admin_metadata()
+            )
+        except grpc.RpcError as e:
+            print(f"gRPC error in list_environments: {e}")
+            return None
+        return {
+            "environments": [
+                {
+                    "env_id": env.env_id,
+                    "scenario": env.scenario,
+                    "session_id": env.session_id,
+                    "client": env.client,
+                    "age_seconds": env.age_seconds,
+                    "idle_seconds": env.idle_seconds,
+                    "steps": env.steps,
+                    "episodes": env.episodes,
+                    "tenant": env.tenant,
+                    "fault": env.fault,
+                }
+                for env in response.environments
+            ],
+            "draining": response.draining,
+        }
+
+    def force_close_environment(self, env_id):
+        """
+        强制关闭环境（管理接口）
+
+        Args:
+            env_id: 注册表中的完整键，会话内的环境为session_id/env_id（见list_environments）
+        """
+        try:
+            request = simulation_pb2.ForceCloseEnvironmentRequest(env_id=env_id)
+            response = self.stub.ForceCloseEnvironment(request, metadata=self._admin_metadata())
+            return _result_dict(response)
+        except grpc.RpcError as e:
+            print(f"gRPC error in force_close_environment: {e}")
+            return None
+
+    def dump_environment_state(self, env_id):
+        """
+        导出环境的内部状态（管理接口），返回解析后的字典
+
+        Args:
+            env_id: 注册表中的完整键
+        """
+        try:
+            request = simulation_pb2.DumpEnvironmentStateRequest(env_id=env_id)
+            response = self.stub.DumpEnvironmentState(request, metadata=self._admin_metadata())
+        except grpc.RpcError as e:
+            print(f"gRPC error in dump_environment_state: {e}")
+            return None
+        return json.loads(response.state_json)
+
+    def drain(self, timeout_seconds=30.0, force=False):
+        """
+        排空服务端（管理接口）：停止创建新环境，最多等待timeout_seconds让客户端关闭已有环境
+
+        Args:
+            timeout_seconds: 等待秒数
+            force: 为True时超时后强制关闭剩余环境
+        """
+        try:
+            request = simulation_pb2.DrainRequest(timeout_seconds=timeout_seconds, force=force)
+            response = self.stub.Drain(request, metadata=self._admin_metadata())
+            return {
+                "remaining_environments": response.remaining_environments,
+                "closed_environments": response.closed_environments,
+            }
+        except grpc.RpcError as e:
+            print(f"gRPC error in drain: {e}")
+            return None
+
+    def migrate_environment(self, env_id, worker):
+        """
+        把环境迁移到另一个工作节点（路由器的管理接口），环境属于会话时连同会话的全部环境
+
+        Args:
+            env_id: list_environments返回的完整env_id
+            worker: 目标工作节点的地址
+        """
+        try:
+            request = simulation_pb2.MigrateEnvironmentRequest(env_id=env_id, worker=worker)
+            response = self.stub.MigrateEnvironment(request, metadata=self._admin_metadata())
+            return response.migrated_environments
+        except grpc.RpcError as e:
+            print(f"gRPC error in migrate_environment: {e}")
+            return None
+
+    def drain_worker(self, worker):
+        """
+        排空工作节点（路由器的管理接口）：不再在其上创建环境，并把其上的环境迁移到其余节点
+
+        Args:
+            worker: 工作节点的地址
+        """
+        try:
+            request = simulation_pb2.DrainWorkerRequest(worker=worker)
+            response = self.stub.DrainWorker(request, metadata=self._admin_metadata())
+            return {
+                "migrated_environments": response.migrated_environments,
+                "remaining_environments": response.remaining_environments,
+            }
+        except grpc.RpcError as e:
+            print(f"gRPC error in drain_worker: {e}")
+            return None
 
 def demo_simple_simulation():
     """演示简单仿真的完整流程"""
@@ -4,11 +4,13 @@
 提供与gRPC服务器的标准化强化学习环境接口
 """
 
+import json
+
 import grpc
 import numpy as np
 import gymnasium as gym
 from gymnasium import spaces
-from typing import Dict, Any, Optional, Union, Tuple
+from typing import Dict, Any, List, Optional, Union, Tuple
 from google.protobuf.json_format import MessageToDict
 import sys
 
@@ -26,6 +28,130 @@ except Exception:  # pragma: no cover
             "Cannot import simulation_pb2. Generate it via protoc or ensure package is installed."  # noqa: E501
         ) from e
 
+from .errors import from_proto  # noqa: E402
+from .grpc_client import _curriculum_dict, intercept, open_channel  # noqa: E402
+
+# 与服务端 MaxMessageSize 保持一致，图像观察会超过gRPC默认的4MB限制
+MAX_MESSAGE_LENGTH = 64 * 1024 * 1024
+CHANNEL_OPTIONS = [
+    ("grpc.max_send_message_length", MAX_MESSAGE_LENGTH),
+    ("grpc.max_receive_message_length", MAX_MESSAGE_LENGTH),
+]
+
+# 创建配置开启auto_reset时，服务端在回合结束后立即重置，结束时的观察放在info的此键中
+TERMINAL_OBSERVATION_KEY = "terminal_observation"
+
+# 客户端实现的协议版本，与服务端APIVersion一致：1为只返回done的四元组语义，2起返回terminated与truncated
+API_VERSION = 2
+
+
+def _api_version(config):
+    """按创建配置中的gymnasium_api选择期望的协议版本，使服务端拒绝语义不兼容的创建请求"""
+    return API_VERSION if config.get("gymnasium_api", True) else 1
+
+
+def _observation_data(observation):
+    """返回观察数据，dtype为float32的环境使用data_f32字段"""
+    return observation.data_f32 or observation.data
+
+
+def _values_dict(struct, typed):
+    """合并Struct与typed_values开启时的带类型标量，整数保持为int"""
+    values = MessageToDict(struct) if struct else {}
+    for key, value in typed.items():
+        kind = value.WhichOneof("kind")
+        values[key] = getattr(value, kind) if kind else None
+    return values
+
+
+def _multi_discrete_space(proto_space):
+    """构造MultiDiscrete空间：优先使用服务端的nvec，旧服务端按high-low+1推导；low非零时作为各维的起始值"""
+    shape = tuple(proto_space.shape) or (1,)
+    low = np.broadcast_to(np.asarray(proto_space.low or [0], dtype=np.int64), shape)
+    if proto_space.nvec:
+        nvec = np.asarray(proto_space.nvec, dtype=np.int64).reshape(shape)
+    else:
+        high = np.broadcast_to(np.asarray(proto_space.high or [1], dtype=np.int64), shape)
+        nvec = high - low + 1
+    if np.any(low != 0):
+        return spaces.MultiDiscrete(nvec, start=low)
+    return spaces.MultiDiscrete(nvec)
+
+
+def _text_space(proto_space):
+    """构造Text空间：长度为[0, max_length]，charset为空时使用gymnasium的默认字符集（大小写字母与数字）"""
+    if proto_space.charset:
+        return spaces.Text(max_length=proto_space.max_length, min_length=0, charset=proto_space.charset)
+    return spaces.Text(max_length=proto_space.max_length, min_length=0)
+
+
+def _image_pixels(pixels: bytes, height: int, width: int, channels: int) -> np.ndarray:
+    """将行优先HWC排列的uint8像素还原为(height, width, channels)数组"""
+    return np.frombuffer(pixels, dtype=np.uint8).reshape(height, width, channels)
+
+
+def _json_bound(value, unbounded: float):
+    """还原JSON边界：null为unbounded，数组中的null同样处理"""
+    if value is None:
+        return unbounded
+    if isinstance(value, list):
+        return np.asarray([unbounded if v is None else v for v in value], dtype=np.float64)
+    return float(value)
+
+
+def hybrid_action_parts(action) -> Tuple[int, List[float]]:
+    """拆分Hybrid动作空间（Tuple(Discrete(n), Tuple(参数空间...))）的动作 (choice, parameters)：
+    parameters为元组时（如action_space.sample()的结果）取出所选动作的参数，否则即为所选动作的参数"""
+    choice, parameters = int(action[0]), action[1]
+    if isinstance(parameters, tuple):
+        parameters = parameters[choice]
+    return choice, np.asarray(parameters, dtype=np.float64).reshape(-1).tolist()
+
+
+def _is_hybrid_action(action) -> bool:
+    """判断是否为 (choice, parameters) 形式的单个参数化动作"""
+    return (
+        isinstance(action, tuple)
+        and len(action) == 2
+        and isinstance(action[0], (int, np.integer))
+        and not isinstance(action[0], (bool, np.bool_))
+    )
+
+
+def space_from_json(space) -> gym.Space:
+    """按服务端的Gym兼容空间JSON（/spaces与GetSpaces的spaces_json中的action_space、observation_space）构造gymnasium空间"""
+    kind = space["type"]
+    shape = tuple(space.get("shape") or ())
+    if kind == "Box":
+        dtype = np.dtype(space.get("dtype") or "float32")
+        low = _json_bound(space.get("low"), -np.inf)
+        high = _json_bound(space.get("high"), np.inf)
+        if isinstance(low, np.ndarray):
+            low = low.reshape(shape)
+        if isinstance(high, np.ndarray):
+            high = high.reshape(shape)
+        return spaces.Box(low=low, high=high, shape=shape, dtype=dtype)
+    if kind == "Discrete":
+        return spaces.Discrete(space["n"], start=space.get("start", 0))
+    if kind == "MultiDiscrete":
+        nvec = np.asarray(space["nvec"], dtype=np.int64).reshape(shape)
+        start = space.get("start")
+        if start is not None:
+            return spaces.MultiDiscrete(nvec, start=np.asarray(start, dtype=np.int64).reshape(shape))
+        return spaces.MultiDiscrete(nvec)
+    if kind == "MultiBinary":
+        return spaces.MultiBinary(list(shape))
+    if kind == "Text":
+        charset = space.get("charset")
+        if charset:
+            return spaces.Text(max_length=space["max_length"], min_length=space.get("min_length", 0), charset=charset)
+        return spaces.Text(max_length=space.get("max_length", 0), min_length=space.get("min_length", 0))
+    if kind == "Dict":
+        return spaces.Dict({name: space_from_json(sub) for name, sub in space["spaces"].items()})
+    if kind == "Tuple":
+        return spaces.Tuple(tuple(space_from_json(element) for element in space["elements"]))
+    raise ValueError(f"Unsupported space type: {kind}")
+
 
 class GrpcEnv(gym.Env):
     """
@@ -50,17 +176,25 @@ class GrpcEnv(gym.Env):
         config: Optional[Dict[str, Any]] = None,
         auto_reset: bool = True,
         verbose: bool = False,
+        session: Optional[str] = None,
+        api_key: Optional[str] = None,
+        tls: bool = False,
+        ca_file: Optional[str] = None,
     ):
         """
         初始化gRPC环境连接
 
         Args:
             scenario: 服务器端的场景名称
-            host: gRPC服务器地址
+            host: gRPC服务器地址，或Unix套接字地址（如unix:///tmp/rlenv-grpc.sock）
             port: gRPC服务器端口
             env_id: 环境实例ID（如果为None则自动生成）
             config: 传递给服务器的配置参数
             auto_reset: 是否自动重置环境
+            session: 会话ID（SimulationGrpcClient.open_session的返回值），设置后env_id只在该会话内可见
+            api_key: 租户API密钥，服务端配置了租户时需要
+            tls: 服务端启用了TLS时为True
+            ca_file: 校验服务端证书的CA证书文件（如自签名证书），指定时启用TLS
         """
         super(GrpcEnv, self).__init__()
 
@@ -70,6 +204,10 @@ class GrpcEnv(gym.Env):
         self.env_id = env_id or f"grpc_env_{scenario}_{np.random.randint(1000, 9999)}"
         self.config = config or {}
         self.auto_reset = auto_reset
+        self.session = session
+        self.api_key = api_key
+        self.tls = tls
+        self.ca_file = ca_file
 
         self.channel = None
         self.client = None
@@ -88,6 +226,11 @@ class GrpcEnv(gym.Env):
         # 获取空间定义
         self._setup_spaces()
 
+        # 获取奖励范围、最大步数等元数据
+        self.reward_range = (-float("inf"), float("inf"))
+        self.max_episode_steps = None
+        self._setup_metadata()
+
     def verbose_print(self, *args, **kwargs):
         if self.verbose:
             print(*args, **kwargs)
@@ -95,7 +238,8 @@ class GrpcEnv(gym.Env):
     def _connect(self):
         """连接到gRPC服务器"""
         try:
-            self.channel = grpc.insecure_channel(f"{self.host}:{self.port}")
+            self.channel = open_channel(self._target(), options=CHANNEL_OPTIONS, tls=self.tls, ca_file=self.ca_file)
+            self.channel = intercept(self.channel, session=self.session, api_key=self.api_key)
             self.client = simulation_pb2_grpc.SimulationServiceStub(self.channel)
 
             # 测试连接
@@ -103,7 +247,13 @@ class GrpcEnv(gym.Env):
             self.client.GetInfo(info_request)
 
         except Exception as e:
-            raise ConnectionError(f"Failed to connect to gRPC server at {self.host}:{self.port}: {e}")
+            raise ConnectionError(f"Failed to connect to gRPC server at {self._target()}: {e}")
+
+    def _target(self) -> str:
+        """gRPC连接目标：host为unix://地址时连接Unix套接字并忽略端口"""
+        if self.host.startswith("unix:"):
+            return self.host
+        return f"{self.host}:{self.port}"
 
     def _setup_spaces(self):
         """从服务器获取并设置动作空间和观察空间"""
@@ -111,8 +261,16 @@ class GrpcEnv(gym.Env):
             request = simulation_pb2.GetSpacesRequest(env_id=self.env_id)
             response = self.client.GetSpaces(request)
 
-            self.action_space = self._convert_proto_space_to_gym(response.action_space, is_action_space=True)
-            self.observation_space = self._convert_proto_space_to_gym(response.observation_space, is_action_space=False)
+            if response.spaces_json:
+                spaces_json = json.loads(response.spaces_json)
+                self.action_space = space_from_json(spaces_json["action_space"])
+                self.observation_space = space_from_json(spaces_json["observation_space"])
+            else:
+                # 旧服务端没有spaces_json，按protobuf空间字段转换
+                self.action_space = self._convert_proto_space_to_gym(response.action_space, is_action_space=True)
+                self.observation_space = self._convert_proto_space_to_gym(
+                    response.observation_space, is_action_space=False
+                )
 
             self.verbose_print(f"Scenario '{self.scenario}' loaded:")
             self.verbose_print(f"  Action space: {self.action_space}")
@@ -128,6 +286,25 @@ class GrpcEnv(gym.Env):
             self.observation_space = spaces.Box(low=-np.inf, high=np.inf, shape=(1,), dtype=np.float32)
             self._spaces_loaded = False
 
+    def _setup_metadata(self):
+        """从服务器获取环境元数据，旧版本服务器不支持时保留默认值"""
+        try:
+            response = self.client.GetMetadata(simulation_pb2.GetMetadataRequest(env_id=self.env_id))
+        except grpc.RpcError as e:
+            if e.code() != grpc.StatusCode.UNIMPLEMENTED:
+                print(f"Warning: Could not get metadata from server for scenario '{self.scenario}': {e}")
+            return
+
+        if len(response.reward_range) == 2:
+            self.reward_range = tuple(response.reward_range)
+        if response.max_episode_steps > 0:
+            self.max_episode_steps = response.max_episode_steps
+        self.metadata = {
+            **self.metadata,
+            "render_modes": list(response.render_modes),
+            "nondeterministic": response.nondeterministic,
+        }
+
     def _convert_proto_space_to_gym_box(self, proto_space, is_action_space: bool = False) -> gym.Space:
         shape = tuple(proto_space.shape) if proto_space.shape else (1,)
         # 如果 low/high 为空，根据情况使用默认值
@@ -194,13 +371,22 @@ class GrpcEnv(gym.Env):
         if proto_space.type == 0:  # BOX type
             return self._convert_proto_space_to_gym_box(proto_space, is_action_space)
         elif proto_space.type == 1:  # DISCRETE type
-            # 对于离散空间，使用shape[0]作为n
-            n = int(proto_space.shape[0]) if proto_space.shape else 2
+            # 对于离散空间，优先使用 high=[n-1] 推导n，兼容旧服务端的 shape=[n]
+            if proto_space.high:
+                n = int(proto_space.high[0]) + 1
+            elif proto_space.shape:
+                n = int(proto_space.shape[0])
+            else:
+                n = 2
             return spaces.Discrete(n)
         elif proto_space.type == 2:  # MULTI_DISCRETE type
-            return spaces.MultiDiscrete(proto_space.shape)
+            return _multi_discrete_space(proto_space)
         elif proto_space.type == 3:  # MULTI_BINARY type
             return spaces.MultiBinary(proto_space.shape)
+        elif proto_space.type == 5:  # TEXT type
+            return _text_space(proto_space)
+        elif proto_space.type == 6:  # IMAGE type
+            return spaces.Box(low=0, high=255, shape=tuple(proto_space.shape), dtype=np.uint8)
         else:
             print(f"Unknown space type: {proto_space.type}, using Box as fallback")
             return spaces.Box(low=-1.0, high=1.0, shape=(1,), dtype=np.float32)
@@ -212,10 +398,19 @@ class GrpcEnv(gym.Env):
 
         # 将配置转换为字符串字典（gRPC要求）
         config_str = {k: v for k, v in self.config.items()}
-        request = simulation_pb2.CreateEnvironmentRequest(env_id=self.env_id, scenario=self.scenario, config=config_str)
+        # 由服务端区分terminated与truncated，使step符合gymnasium.Env的五元组语义
+        config_str.setdefault("gymnasium_api", True)
+        # 元数据与info中的整数、布尔等标量保持原类型，不经Struct变成double
+        config_str.setdefault("typed_values", True)
+        request = simulation_pb2.CreateEnvironmentRequest(
+            env_id=self.env_id, scenario=self.scenario, config=config_str, api_version=_api_version(config_str)
+        )
         response = self.client.CreateEnvironment(request)
         if not response.success:
-            raise RuntimeError(f"Failed to create environment '{self.scenario}': {response.message}")
+            context = f"Failed to create environment '{self.scenario}': "
+            if response.HasField("error"):
+                raise from_proto(response.error, context)
+            raise RuntimeError(context + response.message)
 
         self._env_created = True
         self.verbose_print(f"Environment created: {self.env_id} (scenario: {self.scenario})")
@@ -240,11 +435,10 @@ class GrpcEnv(gym.Env):
         if not response.observations:
             raise RuntimeError("No observations received from environment reset")
 
-        obs_data = response.observations[0].data
-        observation = np.array([float(x) for x in obs_data], dtype=np.float32)
+        observation = self._proto_observation(response.observations[0])
 
         # 构建info字典，包含服务器返回的所有信息
-        info = MessageToDict(response.info) if response.info else {}
+        info = _values_dict(response.info, response.typed_info)
 
         # 添加一些通用信息
         if len(observation) >= 1:
@@ -264,19 +458,50 @@ class GrpcEnv(gym.Env):
         if not response.observations:
             raise RuntimeError("No observations received from environment step")
 
-        obs_data = response.observations[0].data
-        observation = np.array([float(x) for x in obs_data], dtype=np.float32)
+        observation = self._proto_observation(response.observations[0])
         reward = float(response.rewards[0]) if response.rewards else 0.0
-        terminated = bool(response.done[0]) if response.done else False
-        truncated = False  # 可以根据需要扩展
+        if response.terminated:
+            terminated = bool(response.terminated[0])
+            truncated = bool(response.truncated[0])
+        else:
+            # 服务端未开启gymnasium_api时无法区分截断
+            terminated = bool(response.done[0]) if response.done else False
+            truncated = False
 
         # 构建info字典
-        info = MessageToDict(response.info) if response.info else {}
+        info = _values_dict(response.info, response.typed_info)
         info["action_taken"] = action
         info["num_actions"] = len(grpc_actions)
+        if response.final_observations:
+            # 服务端已自动重置，observation为新回合的初始观察；final_observations保留了dtype、文本与图像
+            info[TERMINAL_OBSERVATION_KEY] = self._proto_observation(response.final_observations[0])
+        elif TERMINAL_OBSERVATION_KEY in info:
+            # 旧服务端只在info中返回结束时的观察
+            info[TERMINAL_OBSERVATION_KEY] = self._convert_observation(info[TERMINAL_OBSERVATION_KEY][0])
 
         return observation, reward, terminated, truncated, info
 
+    def _proto_observation(self, message):
+        """将protobuf观察转换为观察，Text观察空间返回text字段中的字符串，图像观察由image字段的像素还原"""
+        if isinstance(self.observation_space, spaces.Text):
+            return message.text
+        if message.HasField("image"):
+            image = message.image
+            return _image_pixels(image.pixels, image.height, image.width, image.channels)
+        return self._convert_observation(_observation_data(message))
+
+    def _convert_observation(self, obs_data) -> np.ndarray:
+        """将观察数据转换为与观察空间一致的数组（图像观察会还原为多维形状和uint8类型），Text观察空间返回字符串"""
+        space = self.observation_space
+        if isinstance(space, spaces.Text):
+            return obs_data if isinstance(obs_data, str) else ""
+        if isinstance(space, spaces.Box) and len(space.shape) > 1:
+            observation = np.asarray(obs_data, dtype=space.dtype)
+            if observation.size == int(np.prod(space.shape)):
+                return observation.reshape(space.shape)
+            return observation
+        return np.array([float(x) for x in obs_data], dtype=np.float32)
+
     def _convert_single_action_to_proto_cached(self, action):
         """带缓存的动作转换（适用于离散动作）"""
         if isinstance(action, (int, float, bool)) and len(self._action_cache) < self._max_cache_size:
@@ -289,7 +514,9 @@ class GrpcEnv(gym.Env):
 
     def _convert_actions_to_proto(self, actions: Union[int, float, np.ndarray, list]) -> list:
         """将Python actions转换为protobuf Action列表"""
-        if not isinstance(actions, (list, tuple)):
+        if not isinstance(actions, (list, tuple)) or (
+            isinstance(self.action_space, spaces.Tuple) and _is_hybrid_action(actions)
+        ):
             # 单个动作，包装成列表
             return [self._convert_single_action_to_proto_cached(actions)]
 
@@ -298,17 +525,31 @@ class GrpcEnv(gym.Env):
 
     def _convert_single_action_to_proto(self, action: Union[int, float, np.ndarray]) -> simulation_pb2.Action:
         """将单个Python action转换为protobuf Action"""
+        # Hybrid空间的动作 (choice, parameters) 以HybridAction发送
+        if isinstance(getattr(self, "action_space", None), spaces.Tuple) and _is_hybrid_action(action):
+            choice, parameters = hybrid_action_parts(action)
+            return simulation_pb2.Action(hybrid=simulation_pb2.HybridAction(choice=choice, parameters=parameters))
+
+        # Dict空间的动作（以子空间名称为键的字典）以ActionDict发送
+        if isinstance(action, dict):
+            return self._handle_dict_action(action, getattr(self, "action_space", None))
+
+        # MultiBinary空间的动作（gymnasium采样得到int8数组）以布尔数组发送
+        if isinstance(getattr(self, "action_space", None), spaces.MultiBinary):
+            values = np.asarray(action).reshape(-1)
+            return simulation_pb2.Action(bool_array=simulation_pb2.BoolArray(values=[bool(x) for x in values]))
+
         # numpy数组处理
         if isinstance(action, np.ndarray):
             return self._handle_numpy_action(action)
 
-        # 基本类型处理
-        if isinstance(action, (int, np.integer)):
+        # 基本类型处理（bool是int的子类，需先判断）
+        if isinstance(action, (bool, np.bool_)):
+            return simulation_pb2.Action(bool_value=bool(action))
+        elif isinstance(action, (int, np.integer)):
             return simulation_pb2.Action(int_value=int(action))
         elif isinstance(action, (float, np.floating)):
             return simulation_pb2.Action(float_value=float(action))
-        elif isinstance(action, bool):
-            return simulation_pb2.Action(bool_value=action)
         elif isinstance(action, str):
             return simulation_pb2.Action(string_value=action)
 
@@ -319,6 +560,24 @@ class GrpcEnv(gym.Env):
         # 回退处理
         return self._fallback_action_conversion(action)
 
+    def _handle_dict_action(self, action: dict, space) -> simulation_pb2.Action:
+        """处理Dict空间的动作，各分量按对应的子空间转换（MultiBinary分量以布尔数组发送）"""
+        components = {}
+        for name, value in action.items():
+            subspace = space[name] if isinstance(space, spaces.Dict) and name in space.spaces else None
+            if isinstance(value, dict):
+                components[name] = self._handle_dict_action(value, subspace)
+            elif isinstance(subspace, spaces.MultiBinary):
+                values = np.asarray(value).reshape(-1)
+                components[name] = simulation_pb2.Action(
+                    bool_array=simulation_pb2.BoolArray(values=[bool(x) for x in values])
+                )
+            elif isinstance(value, np.ndarray):
+                components[name] = self._handle_numpy_action(value)
+            else:
+                components[name] = self._convert_single_action_to_proto(value)
+        return simulation_pb2.Action(dict=simulation_pb2.ActionDict(actions=components))
+
     def _handle_numpy_action(self, action: np.ndarray) -> simulation_pb2.Action:
         """处理numpy数组动作"""
         if action.size == 1:
@@ -349,13 +608,13 @@ class GrpcEnv(gym.Env):
         if not action:
             raise ValueError("Empty action sequence")
 
-        # 类型检查并转换
-        if all(isinstance(x, (int, np.integer)) for x in action):
+        # 类型检查并转换（bool是int的子类，需先判断）
+        if all(isinstance(x, (bool, np.bool_)) for x in action):
+            return simulation_pb2.Action(bool_array=simulation_pb2.BoolArray(values=[bool(x) for x in action]))
+        elif all(isinstance(x, (int, np.integer)) for x in action):
             return simulation_pb2.Action(int_array=simulation_pb2.IntArray(values=[int(x) for x in action]))
         elif all(isinstance(x, (float, np.floating)) for x in action):
             return simulation_pb2.Action(float_array=simulation_pb2.FloatArray(values=[float(x) for x in action]))
-        elif all(isinstance(x, bool) for x in action):
-            return simulation_pb2.Action(bool_array=simulation_pb2.BoolArray(values=list(action)))
         else:
             # 混合类型，尝试转换为float数组
             try:
@@ -390,6 +649,16 @@ class GrpcEnv(gym.Env):
         """渲染（目前为空实现）"""
         pass
 
+    def get_curriculum(self) -> Dict[str, Any]:
+        """获取课程进度（环境需以curriculum配置创建），与info["curriculum"]相同"""
+        response = self.client.GetCurriculum(simulation_pb2.GetCurriculumRequest(env_id=self.env_id))
+        return _curriculum_dict(response)
+
+    def set_curriculum_stage(self, stage: int, frozen: bool = False) -> Dict[str, Any]:
+        """切换课程阶段，新阶段的参数在下次reset时生效；frozen为True时停止自动推进"""
+        request = simulation_pb2.SetCurriculumStageRequest(env_id=self.env_id, stage=stage, frozen=frozen)
+        return _curriculum_dict(self.client.SetCurriculumStage(request))
+
     def get_available_scenarios(self) -> list:
         """获取服务器支持的所有场景"""
         try:
@@ -0,0 +1,353 @@
+#!/usr/bin/env python3
+"""
+HTTP强化学习环境包装器
+通过Gym兼容的HTTP API（/create、/spaces、/reset、/step等）连接仿真服务，接口与GrpcEnv相同。
+请求与响应的结构见http_schema（由cmd/gen_pyschema从Go定义生成），仅依赖标准库urllib。
+"""
+
+import base64
+import http.client
+import json
+import socket
+import ssl
+import urllib.error
+import urllib.parse
+import urllib.request
+from typing import Any, Dict, Optional, Tuple, Union, cast
+
+import numpy as np
+from gymnasium import spaces
+
+from .errors import RemoteError, from_api_error, from_error_response
+from .grpc_env import TERMINAL_OBSERVATION_KEY, GrpcEnv, _api_version, _image_pixels, hybrid_action_parts, space_from_json
+from .http_schema import (
+    CreateEnvRequest,
+    CreateEnvResponse,
+    EnvDebugDump,
+    EnvMetadata,
+    MetricsResponse,
+    ResetResponse,
+    GymSpaces,
+    StatsResponse,
+    StepRequest,
+    StepResponse,
+)
+
+# 携带会话ID的请求头，与服务端SessionHeader一致
+SESSION_HEADER = "X-Session-Id"
+
+# 携带租户API密钥的请求头，与服务端APIKeyHeader一致
+API_KEY_HEADER = "X-API-Key"
+
+# 创建环境时携带期望协议版本的请求头，与服务端APIVersionHeader一致
+API_VERSION_HEADER = "X-API-Version"
+
+
+def _apply_metadata(env: GrpcEnv, response: EnvMetadata):
+    """将JSON编码的环境元数据设置到env（奖励范围中的null还原为±inf）"""
+    reward_range = response.get("reward_range") or []
+    if len(reward_range) == 2:
+        low, high = reward_range
+        env.reward_range = (-np.inf if low is None else low, np.inf if high is None else high)
+    if response.get("max_episode_steps", 0) > 0:
+        env.max_episode_steps = response["max_episode_steps"]
+    env.metadata = {
+        **env.metadata,
+        "render_modes": list(response.get("render_modes") or []),
+        "nondeterministic": response.get("nondeterministic", False),
+    }
+
+
+def _json_dict_action(action: dict) -> Dict[str, Any]:
+    """将Dict空间的动作转换为JSON对象：数组分量为列表，numpy标量为Python数值，嵌套的字典递归转换"""
+    components: Dict[str, Any] = {}
+    for name, value in action.items():
+        if isinstance(value, dict):
+            components[name] = _json_dict_action(value)
+        elif isinstance(value, (np.ndarray, np.generic)):
+            components[name] = value.tolist()
+        else:
+            components[name] = value
+    return components
+
+
+def _remote_error(path: str, status: int, payload: bytes, default: str) -> RemoteError:
+    """将服务端返回的ErrorResponse转换为RemoteError，响应不是JSON时记为UNKNOWN"""
+    try:
+        response = json.loads(payload)
+    except ValueError:
+        response = {}
+    return from_error_response(response, default, context=f"{path} failed ({status}): ")
+
+
+class _UnixHTTPConnection(http.client.HTTPConnection):
+    """经Unix套接字发送请求的HTTP连接"""
+
+    def __init__(self, socket_path: str, timeout: float):
+        super().__init__("localhost", timeout=timeout)
+        self.socket_path = socket_path
+
+    def connect(self):
+        sock = socket.socket(socket.AF_UNIX, socket.SOCK_STREAM)
+        sock.settimeout(self.timeout)
+        sock.connect(self.socket_path)
+        self.sock = sock
+
+
+class HttpEnv(GrpcEnv):
+    """
+    通用HTTP环境包装器
+
+    与GrpcEnv行为一致（空间发现、元数据、gymnasium五元组），动作按动作空间平铺为
+    数值列表通过StepRequest.values发送，适用于任意场景
+    """
+
+    def __init__(
+        self,
+        scenario: str,
+        host: str = "127.0.0.1",
+        port: int = 8080,
+        env_id: Optional[str] = None,
+        config: Optional[Dict[str, Any]] = None,
+        auto_reset: bool = True,
+        verbose: bool = False,
+        timeout: float = 30.0,
+        session: Optional[str] = None,
+        api_key: Optional[str] = None,
+        tls: bool = False,
+        ca_file: Optional[str] = None,
+    ):
+        """
+        初始化HTTP环境连接
+
+        Args:
+            scenario: 服务器端的场景名称
+            host: HTTP服务器地址，或Unix套接字地址（如unix:///tmp/rlenv-http.sock）
+            port: HTTP服务器端口
+            env_id: 环境实例ID（如果为None则自动生成）
+            config: 传递给服务器的配置参数
+            auto_reset: 是否自动重置环境
+            timeout: 每个请求的超时时间（秒）
+            session: 会话ID（POST /session/open的返回值），设置后env_id只在该会话内可见
+            api_key: 租户API密钥（X-API-Key头），服务端配置了租户时需要
+            tls: 服务端启用了TLS时为True，经HTTPS连接
+            ca_file: 校验服务端证书的CA证书文件（如自签名证书），指定时启用TLS
+        """
+        # host为unix://地址时经Unix套接字连接，端口不使用
+        self.socket_path = host[len("unix://") :] if host.startswith("unix://") else None
+        scheme = "https" if tls or ca_file is not None else "http"
+        self.base_url = host if self.socket_path is not None else f"{scheme}://{host}:{port}"
+        self.ssl_context = ssl.create_default_context(cafile=ca_file) if scheme == "https" else None
+        self.timeout = timeout
+        super().__init__(
+            scenario,
+            host=host,
+            port=port,
+            env_id=env_id or f"http_env_{scenario}_{np.random.randint(1000, 9999)}",
+            config=config,
+            auto_reset=auto_reset,
+            verbose=verbose,
+            session=session,
+            api_key=api_key,
+            tls=tls,
+            ca_file=ca_file,
+        )
+
+    def _headers(self) -> Dict[str, str]:
+        headers = {"Content-Type": "application/json"}
+        if self.session:
+            headers[SESSION_HEADER] = self.session
+        if self.api_key:
+            headers[API_KEY_HEADER] = self.api_key
+        # 服务端只在创建环境时读取，配置不变因此每个请求都可以携带
+        headers[API_VERSION_HEADER] = str(_api_version(self.config))
+        return headers
+
+    def _request(self, path: str, body: Optional[Dict[str, Any]] = None) -> Dict[str, Any]:
+        """发送请求并解析JSON响应，body为None时使用GET；服务端返回的ErrorResponse转换为RuntimeError"""
+        data = None if body is None else json.dumps(body).encode()
+        if self.socket_path is not None:
+            return self._unix_request(path, data)
+        request = urllib.request.Request(
+            self.base_url + path, data=data, headers=self._headers()
+        )
+        try:
+            with urllib.request.urlopen(request, timeout=self.timeout, context=self.ssl_context) as response:
+                return json.loads(response.read())
+        except urllib.error.HTTPError as e:
+            raise _remote_error(path, e.code, e.read(), e.reason) from e
+
+    def _unix_request(self, path: str, data: Optional[bytes]) -> Dict[str, Any]:
+        """经Unix套接字发送请求，每个请求使用新的连接"""
+        connection = _UnixHTTPConnection(self.socket_path, self.timeout)
+        try:
+            connection.request(
+                "GET" if data is None else "POST", path, body=data, headers=self._headers()
+            )
+            response = connection.getresponse()
+            payload = response.read()
+        finally:
+            connection.close()
+        if response.status >= 400:
+            raise _remote_error(path, response.status, payload, response.reason)
+        return json.loads(payload)
+
+    def _connect(self):
+        """检查HTTP服务器是否可用"""
+        try:
+            self._request("/info")
+        except Exception as e:
+            raise ConnectionError(f"Failed to connect to HTTP server at {self.base_url}: {e}")
+
+    def _create_environment(self):
+        """创建环境（如果尚未创建）"""
+        if self._env_created:
+            return
+
+        config = dict(self.config)
+        # 由服务端区分terminated与truncated，使step符合gymnasium.Env的五元组语义
+        config.setdefault("gymnasium_api", True)
+        request: CreateEnvRequest = {"env_id": self.env_id, "scenario": self.scenario, "config": config}
+        response = cast(CreateEnvResponse, self._request("/create", dict(request)))
+        if not response["success"]:
+            context = f"Failed to create environment '{self.scenario}': "
+            if response.get("error"):
+                raise from_api_error(response["error"], context)
+            raise RuntimeError(context + response["message"])
+
+        self._env_created = True
+        self.verbose_print(f"Environment created: {self.env_id} (scenario: {self.scenario})")
+
+    def _setup_spaces(self):
+        """从服务器获取并设置动作空间和观察空间"""
+        try:
+            response = cast(GymSpaces, self._request("/spaces", {"env_id": self.env_id}))
+            self.action_space = space_from_json(response["action_space"])
+            self.observation_space = space_from_json(response["observation_space"])
+
+            self.verbose_print(f"Scenario '{self.scenario}' loaded:")
+            self.verbose_print(f"  Action space: {self.action_space}")
+            self.verbose_print(f"  Observation space: {self.observation_space}")
+
+            self._spaces_loaded = True
+
+        except Exception as e:
+            print(f"Warning: Could not get spaces from server for scenario '{self.scenario}': {e}")
+            print("Using default fallback spaces.")
+            self.action_space = spaces.Box(low=-1.0, high=1.0, shape=(1,), dtype=np.float32)
+            self.observation_space = spaces.Box(low=-np.inf, high=np.inf, shape=(1,), dtype=np.float32)
+            self._spaces_loaded = False
+
+    def _setup_metadata(self):
+        """从服务器获取环境元数据，旧版本服务器不支持时保留默认值"""
+        try:
+            response = cast(EnvMetadata, self._request("/metadata", {"env_id": self.env_id}))
+        except Exception as e:
+            print(f"Warning: Could not get metadata from server for scenario '{self.scenario}': {e}")
+            return
+        _apply_metadata(self, response)
+
+    def reset(self, seed: Optional[int] = None, options: Optional[Dict] = None) -> Tuple[np.ndarray, Dict]:
+        """重置环境"""
+        super(GrpcEnv, self).reset(seed=seed)
+
+        self._create_environment()
+
+        response = cast(ResetResponse, self._request("/reset", {"env_id": self.env_id}))
+        if not response["observation"]:
+            raise RuntimeError("No observations received from environment reset")
+
+        observation = self._response_observation(response)
+        info = dict(response.get("info") or {})
+        if len(observation) >= 1:
+            info["observation_size"] = len(observation)
+
+        return observation, info
+
+    def step(self, action: Union[int, float, np.ndarray, list]) -> Tuple[np.ndarray, float, bool, bool, Dict]:
+        """执行一步"""
+        request: StepRequest = {"env_id": self.env_id}
+        if isinstance(self.action_space, spaces.Text):
+            request["text"] = [action]
+        elif isinstance(self.action_space, spaces.Dict):
+            request["action"] = _json_dict_action(action)
+        elif isinstance(self.action_space, spaces.Tuple):
+            choice, parameters = hybrid_action_parts(action)
+            request["action"] = {"choice": choice, "parameters": parameters}
+        else:
+            request["values"] = np.asarray(action, dtype=np.float64).reshape(-1).tolist()
+        response = cast(StepResponse, self._request("/step", dict(request)))
+
+        if not response["observation"]:
+            raise RuntimeError("No observations received from environment step")
+
+        observation = self._response_observation(response)
+        reward = float(response["reward"][0]) if response["reward"] else 0.0
+        if "terminated" in response:
+            terminated = bool(response["terminated"][0])
+            truncated = bool(response["truncated"][0])
+        else:
+            # 服务端未开启gymnasium_api时无法区分截断
+            terminated = bool(response["done"][0]) if response["done"] else False
+            truncated = False
+
+        info = dict(response.get("info") or {})
+        info["action_taken"] = action
+        if TERMINAL_OBSERVATION_KEY in info:
+            # 服务端已自动重置，observation为新回合的初始观察
+            info[TERMINAL_OBSERVATION_KEY] = self._convert_observation(info[TERMINAL_OBSERVATION_KEY][0])
+        return observation, reward, terminated, truncated, info
+
+    def _response_observation(self, response: Union[ResetResponse, StepResponse]):
+        """返回/reset与/step响应中第一个智能体的观察，Text观察空间返回text中的字符串，图像观察由image中的base64像素还原"""
+        if isinstance(self.observation_space, spaces.Text):
+            return (response.get("text") or [""])[0]
+        image = (response.get("image") or [None])[0]
+        if image:
+            return _image_pixels(base64.b64decode(image["pixels"]), image["height"], image["width"], image["channels"])
+        return self._convert_observation(response["observation"][0])
+
+    def close(self):
+        """关闭环境"""
+        if self._env_created:
+            try:
+                response = self._request("/close", {"env_id": self.env_id})
+                self.verbose_print(f"Environment closed: {response.get('message')}")
+            except Exception as e:
+                print(f"Error closing environment: {e}")
+            finally:
+                self._env_created = False
+
+    def get_curriculum(self) -> Dict[str, Any]:
+        """获取课程进度（环境需以curriculum配置创建），与info["curriculum"]相同"""
+        return self._request("/curriculum", {"env_id": self.env_id})
+
+    def set_curriculum_stage(self, stage: int, frozen: bool = False) -> Dict[str, Any]:
+        """切换课程阶段，新阶段的参数在下次reset时生效；frozen为True时停止自动推进"""
+        return self._request("/curriculum/stage", {"env_id": self.env_id, "stage": stage, "frozen": frozen})
+
+    def get_step_latency(self) -> MetricsResponse:
+        """获取服务端按场景的步进延迟（p50/p95/p99，毫秒）与吞吐：step为环境Step本身的耗时，
+        request为服务端处理整个步进请求的耗时，与客户端测得的往返耗时对比即可区分传输开销与环境开销"""
+        return self._request("/metrics")
+
+    def get_stats(self, scenario: Optional[str] = None, env_id: Optional[str] = None) -> StatsResponse:
+        """获取服务端回合累计奖励与长度的滚动统计（次数、均值、最小值、最大值与最近回合），
+        按环境（完整的注册表键）与场景汇总，可按scenario与env_id过滤"""
+        query = {key: value for key, value in (("scenario", scenario), ("env_id", env_id)) if value}
+        path = "/stats"
+        if query:
+            path += "?" + urllib.parse.urlencode(query)
+        return cast(StatsResponse, self._request(path))
+
+    def debug_state(self) -> EnvDebugDump:
+        """导出环境的内部状态（检查点、StateDumper的结果与各包装层），用于排查异常的奖励或结束标志"""
+        return cast(EnvDebugDump, self._request("/debug/env/" + urllib.parse.quote(self.env_id, safe="")))
+
+    def get_available_scenarios(self) -> list:
+        """获取服务器支持的所有场景"""
+        try:
+            return list(self._request("/info").get("scenarios") or [])
+        except Exception as e:
+            print(f"Error getting scenarios: {e}")
+            return []
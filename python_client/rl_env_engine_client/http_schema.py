@@ -0,0 +1,302 @@
+"""JSON request and response shapes of the rl_env_engine HTTP API.
+
+Code generated by cmd/gen_pyschema; DO NOT EDIT.
+"""
+
+from typing import Any, Dict, List, Optional, Union
+
+try:
+    from typing import TypedDict
+except ImportError:  # pragma: no cover
+    from typing_extensions import TypedDict
+
+
+class CreateEnvRequest(TypedDict):
+    env_id: str
+    scenario: str
+    config: Dict[str, Any]
+
+
+class _APIErrorRequired(TypedDict):
+    code: str
+    message: str
+
+
+class APIError(_APIErrorRequired, total=False):
+    details: Dict[str, str]
+
+
+class _CreateEnvResponseRequired(TypedDict):
+    success: bool
+    message: str
+
+
+class CreateEnvResponse(_CreateEnvResponseRequired, total=False):
+    error: Optional[APIError]
+
+
+class ResetRequest(TypedDict):
+    env_id: str
+
+
+class ImageData(TypedDict):
+    pixels: str
+    height: int
+    width: int
+    channels: int
+
+
+class _AgentStepRequired(TypedDict):
+    observation: List[float]
+    reward: float
+    terminated: bool
+    truncated: bool
+
+
+class AgentStep(_AgentStepRequired, total=False):
+    text: str
+    image: Optional[ImageData]
+    info: Dict[str, Any]
+
+
+class _ResetResponseRequired(TypedDict):
+    observation: List[List[float]]
+    info: Dict[str, Any]
+
+
+class ResetResponse(_ResetResponseRequired, total=False):
+    text: List[str]
+    image: List[Optional[ImageData]]
+    agent_ids: List[str]
+    agents: Dict[str, AgentStep]
+
+
+class _StepRequestRequired(TypedDict):
+    env_id: str
+
+
+class StepRequest(_StepRequestRequired, total=False):
+    action: Dict[str, Any]
+    values: List[float]
+    text: List[str]
+
+
+class _StepResponseRequired(TypedDict):
+    observation: List[List[float]]
+    reward: List[float]
+    done: List[bool]
+    info: Dict[str, Any]
+
+
+class StepResponse(_StepResponseRequired, total=False):
+    text: List[str]
+    image: List[Optional[ImageData]]
+    agent_ids: List[str]
+    terminated: List[bool]
+    truncated: List[bool]
+    step_type: List[str]
+    discount: List[float]
+    agents: Dict[str, AgentStep]
+
+
+class _GymSpaceRequired(TypedDict):
+    type: str
+    shape: List[int]
+
+
+class GymSpace(_GymSpaceRequired, total=False):
+    dtype: str
+    low: Union[float, List[Optional[float]], None]
+    high: Union[float, List[Optional[float]], None]
+    n: int
+    start: Union[int, List[int]]
+    nvec: List[int]
+    values: List[float]
+    max_length: int
+    min_length: int
+    charset: str
+    image: bool
+    spaces: Dict[str, "GymSpace"]
+    elements: List["GymSpace"]
+    hybrid: bool
+
+
+class GymSpaces(TypedDict):
+    action_space: GymSpace
+    observation_space: GymSpace
+
+
+class RecordRequest(TypedDict):
+    env_id: str
+    path: str
+
+
+class CurriculumStageRequest(TypedDict):
+    env_id: str
+    stage: int
+    frozen: bool
+
+
+class Progress(TypedDict):
+    stage: int
+    stages: int
+    episodes: int
+    stage_episodes: int
+    success_rate: float
+    window: int
+    frozen: bool
+    parameters: Dict[str, float]
+
+
+class _PresetRequired(TypedDict):
+    name: str
+    scenario: str
+
+
+class Preset(_PresetRequired, total=False):
+    config: Dict[str, Any]
+
+
+class InfoResponse(TypedDict):
+    scenarios: List[str]
+    presets: List[Preset]
+    env_ids: List[str]
+    info: Dict[str, Any]
+    api_version: int
+    min_api_version: int
+
+
+class LatencySummary(TypedDict):
+    count: int
+    mean_ms: float
+    p50_ms: float
+    p95_ms: float
+    p99_ms: float
+    max_ms: float
+    per_second: float
+
+
+class ScenarioLatency(TypedDict):
+    step: LatencySummary
+    request: LatencySummary
+
+
+class MetricsResponse(TypedDict):
+    transport: str
+    step_latency: Dict[str, ScenarioLatency]
+
+
+class SeriesSummary(TypedDict):
+    mean: float
+    min: float
+    max: float
+    recent_mean: float
+    recent: List[float]
+
+
+class _EpisodeSummaryRequired(TypedDict):
+    episodes: int
+    returns: SeriesSummary
+    lengths: SeriesSummary
+
+
+class EpisodeSummary(_EpisodeSummaryRequired, total=False):
+    scenario: str
+
+
+class StatsResponse(TypedDict):
+    window: int
+    scenarios: Dict[str, EpisodeSummary]
+    environments: Dict[str, EpisodeSummary]
+
+
+class _EnvDebugDumpRequired(TypedDict):
+    env_id: str
+    scenario: str
+    steps: int
+    episodes: int
+    wrappers: List[str]
+    state: Dict[str, Any]
+
+
+class EnvDebugDump(_EnvDebugDumpRequired, total=False):
+    checkpoint: Any
+    checkpoint_error: str
+    fault: str
+
+
+class OpenSessionRequest(TypedDict):
+    client: str
+    ttl_seconds: int
+
+
+class OpenSessionResponse(TypedDict):
+    session_id: str
+    ttl_seconds: int
+
+
+class CloseSessionRequest(TypedDict):
+    session_id: str
+
+
+class CloseSessionResponse(TypedDict):
+    closed_environments: int
+
+
+class _EnvStatusRequired(TypedDict):
+    env_id: str
+    scenario: str
+    session_id: str
+    client: str
+    age_seconds: float
+    idle_seconds: float
+    steps: int
+    episodes: int
+
+
+class EnvStatus(_EnvStatusRequired, total=False):
+    tenant: str
+    fault: str
+
+
+class AdminEnvsResponse(TypedDict):
+    environments: List[EnvStatus]
+    draining: bool
+
+
+class DrainRequest(TypedDict):
+    timeout_seconds: float
+    force: bool
+
+
+class DrainResponse(TypedDict):
+    remaining_environments: int
+    closed_environments: int
+
+
+class RegisterScenarioRequest(TypedDict):
+    name: str
+    description: str
+    wasm: str
+
+
+class RegisterScenarioResponse(TypedDict):
+    scenario: str
+
+
+class EnvMetadata(TypedDict):
+    reward_range: List[Optional[float]]
+    max_episode_steps: int
+    render_modes: List[str]
+    nondeterministic: bool
+
+
+class _ErrorResponseRequired(TypedDict):
+    error: bool
+    message: str
+    code: int
+    status: str
+
+
+class ErrorResponse(_ErrorResponseRequired, total=False):
+    details: Dict[str, str]
@@ -0,0 +1,58 @@
+#!/usr/bin/env python3
+"""
+按传输方式创建远程环境
+RemoteEnv 根据transport选择GrpcEnv、HttpEnv或ShmEnv，训练脚本切换协议时无需改动其他代码:
+    env = RemoteEnv("cartpole", transport="http", port=8080)
+"""
+
+from typing import Any, Dict, Optional
+
+import gymnasium as gym
+
+from .grpc_env import GrpcEnv
+from .http_env import HttpEnv
+from .shm_env import ShmEnv
+
+# 各传输方式的环境类与默认端口，共享内存传输经Unix套接字连接，没有端口
+TRANSPORTS = {
+    "grpc": (GrpcEnv, 9090),
+    "http": (HttpEnv, 8080),
+    "shm": (ShmEnv, None),
+}
+
+
+class RemoteEnv(gym.Wrapper):
+    """
+    单个远程环境
+
+    包装所选传输方式的环境，step/reset的语义与gymnasium.Env相同，
+    env_id、reward_range、max_episode_steps等属性透传到内部环境
+    """
+
+    def __init__(
+        self,
+        scenario: str,
+        transport: str = "grpc",
+        host: str = "127.0.0.1",
+        port: Optional[int] = None,
+        config: Optional[Dict[str, Any]] = None,
+        **kwargs: Any,
+    ):
+        """
+        Args:
+            scenario: 服务器端的场景名称
+            transport: "grpc"、"http" 或 "shm"
+            host: 服务器地址（shm不使用）
+            port: 服务器端口，默认gRPC为9090、HTTP为8080（shm不使用）
+            config: 传递给服务器的配置参数
+            kwargs: 传给GrpcEnv/HttpEnv/ShmEnv的其他参数（env_id、verbose、socket_path等）
+        """
+        if transport not in TRANSPORTS:
+            raise ValueError(f"unknown transport '{transport}', expected one of {sorted(TRANSPORTS)}")
+        env_class, default_port = TRANSPORTS[transport]
+        if default_port is None:
+            env = env_class(scenario, config=config, **kwargs)
+        else:
+            env = env_class(scenario, host=host, port=port or default_port, config=config, **kwargs)
+        super().__init__(env)
+        self.transport = transport
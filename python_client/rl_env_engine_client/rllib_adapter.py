@@ -0,0 +1,139 @@
+#!/usr/bin/env python3
+"""
+RLlib外部环境适配器
+让仿真引擎作为Ray RLlib的外部环境，无需编写自定义连接器：
+- GrpcExternalEnv: ExternalEnv子类，由训练进程内的RLlib驱动远程环境的回合
+- run_policy_client: 作为PolicyClient连接训练端的PolicyServerInput，把回合逐步推送给训练器
+
+需要额外安装 ray[rllib]：pip install -e "python_client[rllib]"
+
+命令行（训练端已用PolicyServerInput监听9900端口）:
+    python -m rl_env_engine_client.rllib_adapter --server http://localhost:9900 --scenario cartpole
+"""
+
+import argparse
+from typing import Any, Dict, Optional
+
+from ray.rllib.env.external_env import ExternalEnv
+from ray.rllib.env.policy_client import PolicyClient
+
+from .grpc_env import GrpcEnv
+
+
+def _run_episode(start_episode, get_action, log_returns, end_episode, env: GrpcEnv, training_enabled: bool) -> float:
+    """用RLlib的回合接口驱动env完成一个回合，返回回合总奖励"""
+    episode_id = start_episode(training_enabled=training_enabled)
+    observation, _ = env.reset()
+    total = 0.0
+    while True:
+        action = get_action(episode_id, observation)
+        observation, reward, terminated, truncated, info = env.step(action)
+        total += reward
+        log_returns(episode_id, reward, info=info)
+        if terminated or truncated:
+            end_episode(episode_id, observation)
+            return total
+
+
+class GrpcExternalEnv(ExternalEnv):
+    """
+    RLlib外部环境
+
+    在RLlib的环境线程中循环运行远程环境的回合，动作由训练中的策略给出。
+    截断由服务端的gymnasium_api标志区分，回合统计与普通环境一致。
+    注册后即可在算法配置中使用：
+        tune.register_env("engine", lambda cfg: GrpcExternalEnv("cartpole", **cfg))
+    """
+
+    def __init__(
+        self,
+        scenario: str,
+        host: str = "127.0.0.1",
+        port: int = 9090,
+        env_id: Optional[str] = None,
+        config: Optional[Dict[str, Any]] = None,
+        verbose: bool = False,
+    ):
+        """
+        Args:
+            scenario: 服务器端的场景名称
+            host: gRPC服务器地址
+            port: gRPC服务器端口
+            env_id: 环境实例ID（如果为None则自动生成）
+            config: 传递给服务器的配置参数
+        """
+        self.env = GrpcEnv(scenario, host=host, port=port, env_id=env_id, config=config, verbose=verbose)
+        super().__init__(self.env.action_space, self.env.observation_space)
+
+    def run(self):
+        """RLlib在独立线程中调用，持续产生回合"""
+        while True:
+            _run_episode(self.start_episode, self.get_action, self.log_returns, self.end_episode, self.env, True)
+
+
+def run_policy_client(
+    server_address: str,
+    scenario: str,
+    host: str = "127.0.0.1",
+    port: int = 9090,
+    config: Optional[Dict[str, Any]] = None,
+    episodes: Optional[int] = None,
+    inference_mode: str = "remote",
+    training_enabled: bool = True,
+    verbose: bool = False,
+) -> None:
+    """
+    作为RLlib PolicyClient运行：连接server_address上的PolicyServerInput，
+    在远程环境中执行训练端给出的动作，并把奖励与回合边界推送给训练器
+
+    Args:
+        server_address: PolicyServerInput的地址，例如 http://localhost:9900
+        scenario: 服务器端的场景名称
+        host: 仿真gRPC服务器地址
+        port: 仿真gRPC服务器端口
+        config: 传递给仿真服务器的配置参数
+        episodes: 运行的回合数，None表示一直运行
+        inference_mode: "remote"由训练端推理，"local"在本地保存策略副本推理
+        training_enabled: 回合数据是否用于训练
+    """
+    client = PolicyClient(server_address, inference_mode=inference_mode)
+    env = GrpcEnv(scenario, host=host, port=port, config=config, verbose=verbose)
+    try:
+        episode = 0
+        while episodes is None or episode < episodes:
+            total = _run_episode(
+                client.start_episode, client.get_action, client.log_returns, client.end_episode, env, training_enabled
+            )
+            episode += 1
+            if verbose:
+                print(f"Episode {episode}: return={total:.3f}")
+    finally:
+        env.close()
+
+
+def main():
+    parser = argparse.ArgumentParser(description="Run an rl_env_engine environment as an RLlib PolicyClient")
+    parser.add_argument("--server", default="http://localhost:9900", help="PolicyServerInput address")
+    parser.add_argument("--scenario", required=True, help="scenario name on the simulation server")
+    parser.add_argument("--host", default="127.0.0.1", help="simulation gRPC server host")
+    parser.add_argument("--port", type=int, default=9090, help="simulation gRPC server port")
+    parser.add_argument("--episodes", type=int, default=None, help="number of episodes, unlimited by default")
+    parser.add_argument("--inference-mode", choices=["remote", "local"], default="remote")
+    parser.add_argument("--no-train", action="store_true", help="only evaluate, do not send data for training")
+    parser.add_argument("--verbose", action="store_true")
+    args = parser.parse_args()
+
+    run_policy_client(
+        args.server,
+        args.scenario,
+        host=args.host,
+        port=args.port,
+        episodes=args.episodes,
+        inference_mode=args.inference_mode,
+        training_enabled=not args.no_train,
+        verbose=args.verbose,
+    )
+
+
+if __name__ == "__main__":
+    main()
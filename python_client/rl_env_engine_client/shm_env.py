@@ -0,0 +1,251 @@
+#!/usr/bin/env python3
+"""
+共享内存强化学习环境包装器
+连接 `rlenv serve --protocol shm` 启动的服务：控制消息经Unix套接字发送，观察与动作通过内存映射文件交换，
+每步只在套接字上往返几十字节，适合与服务端同机的训练进程。协议与映射文件布局见server/shm.go。
+"""
+
+import json
+import mmap
+import os
+import socket
+import struct
+import tempfile
+from typing import Any, Dict, Optional, Tuple, Union
+
+import numpy as np
+from gymnasium import spaces
+
+from .grpc_env import GrpcEnv, hybrid_action_parts, space_from_json
+from .http_env import _apply_metadata
+
+# 默认控制套接字，与Go端DefaultShmServerConfig一致
+DEFAULT_SOCKET_PATH = os.path.join(tempfile.gettempdir(), "rl_env_engine.sock")
+
+_OP_CREATE, _OP_RESET, _OP_STEP, _OP_CLOSE = 1, 2, 3, 4
+
+_MAGIC = b"RLENVSHM"
+# 文件头：魔数、版本、浮点数宽度、槽数、保留、槽大小、首个槽的偏移、动作区偏移、动作区容量、保留
+_HEADER = struct.Struct("<8sIIII4Q8x")
+_FRAME = struct.Struct("<BI")
+_REPLY = struct.Struct("<QQ")
+
+
+def _flat_dict_values(action: dict) -> list:
+    """按名称的字典序平铺Dict空间的动作，与服务端切分平铺动作值的顺序一致"""
+    values: list = []
+    for name in sorted(action):
+        value = action[name]
+        if isinstance(value, dict):
+            values.extend(_flat_dict_values(value))
+        else:
+            values.extend(np.asarray(value, dtype=np.float64).reshape(-1).tolist())
+    return values
+
+
+def _flat_hybrid_values(action, space: spaces.Tuple) -> list:
+    """平铺Hybrid空间的动作：离散动作下标后依次为每个动作的参数，未选择的动作参数填0"""
+    choice, parameters = hybrid_action_parts(action)
+    values: list = [float(choice)]
+    for i, parameter_space in enumerate(space[1].spaces):
+        values.extend(parameters if i == choice else [0.0] * int(np.prod(parameter_space.shape)))
+    return values
+
+
+class ShmEnv(GrpcEnv):
+    """
+    通用共享内存环境包装器
+
+    与GrpcEnv行为一致（空间发现、元数据、gymnasium五元组），每个实例独占一条控制连接和一个映射文件。
+    copy=False时返回的观察直接引用映射内存，只在之后的slots-1步内有效
+    """
+
+    def __init__(
+        self,
+        scenario: str,
+        socket_path: str = DEFAULT_SOCKET_PATH,
+        config: Optional[Dict[str, Any]] = None,
+        auto_reset: bool = True,
+        verbose: bool = False,
+        slots: int = 4,
+        copy: bool = True,
+        info: bool = True,
+        timeout: float = 30.0,
+        **kwargs: Any,
+    ):
+        """
+        初始化共享内存环境连接
+
+        Args:
+            scenario: 服务器端的场景名称
+            socket_path: 服务端的控制套接字路径
+            config: 传递给服务器的配置参数（dtype为float32时映射文件中的数值为float32）
+            auto_reset: 是否自动重置环境
+            slots: 映射文件中结果槽的个数
+            copy: 是否复制观察，为False时直接引用映射内存
+            info: reset与step是否返回服务端的info（关闭可省去每步的JSON编码）
+            timeout: 控制消息的超时时间（秒）
+            kwargs: 兼容RemoteEnv传入的env_id等参数，不会发送给服务端
+        """
+        self.socket_path = socket_path
+        self.slots = slots
+        self.copy = copy
+        self.info = info
+        self.timeout = timeout
+        self._sock = None
+        self._map = None
+        self._file = None
+        super().__init__(
+            scenario,
+            env_id=kwargs.get("env_id") or f"shm_env_{scenario}_{np.random.randint(1000, 9999)}",
+            config=config,
+            auto_reset=auto_reset,
+            verbose=verbose,
+        )
+
+    def _call(self, op: int, payload: bytes = b"") -> bytes:
+        """发送一条控制消息并返回响应负载，服务端返回错误时抛出RuntimeError"""
+        self._sock.sendall(_FRAME.pack(op, len(payload)) + payload)
+        status, n = _FRAME.unpack(self._recv(_FRAME.size))
+        data = self._recv(n)
+        if status != 0:
+            raise RuntimeError(data.decode(errors="replace"))
+        return data
+
+    def _recv(self, n: int) -> bytes:
+        buf = bytearray()
+        while len(buf) < n:
+            chunk = self._sock.recv(n - len(buf))
+            if not chunk:
+                raise ConnectionError("shared-memory server closed the connection")
+            buf.extend(chunk)
+        return bytes(buf)
+
+    def _connect(self):
+        """连接到控制套接字"""
+        try:
+            self._sock = socket.socket(socket.AF_UNIX, socket.SOCK_STREAM)
+            self._sock.settimeout(self.timeout)
+            self._sock.connect(self.socket_path)
+        except Exception as e:
+            raise ConnectionError(f"Failed to connect to shared-memory server at {self.socket_path}: {e}")
+
+    def _create_environment(self):
+        """创建环境并映射服务端创建的文件"""
+        if self._env_created:
+            return
+
+        request = {"scenario": self.scenario, "config": self.config, "slots": self.slots, "info": self.info}
+        try:
+            self._created = json.loads(self._call(_OP_CREATE, json.dumps(request).encode()))
+        except RuntimeError as e:
+            raise RuntimeError(f"Failed to create environment '{self.scenario}': {e}") from e
+        self._file = open(self._created["path"], "r+b")
+        self._remap(self._created["size"])
+
+        self._env_created = True
+        self.verbose_print(f"Environment created: {self.env_id} (scenario: {self.scenario}, file: {self._file.name})")
+
+    def _remap(self, size: int):
+        """映射文件并读取文件头；之前返回的零拷贝观察仍引用旧映射，因此不主动关闭旧映射"""
+        self._map = mmap.mmap(self._file.fileno(), size)
+        magic, _, width, _, _, _, _, action_offset, action_capacity = _HEADER.unpack_from(self._map)
+        if magic != _MAGIC:
+            raise RuntimeError(f"{self._file.name} is not a shared-memory environment file")
+        self._size = size
+        self._dtype = np.dtype("<f4" if width == 4 else "<f8")
+        self._action_count = np.frombuffer(self._map, dtype="<u4", count=1, offset=action_offset)
+        self._actions = np.frombuffer(self._map, dtype=self._dtype, count=action_capacity, offset=action_offset + 8)
+
+    def _setup_spaces(self):
+        """使用create响应中的动作空间和观察空间"""
+        response = self._created["spaces"]
+        self.action_space = space_from_json(response["action_space"])
+        self.observation_space = space_from_json(response["observation_space"])
+        self._spaces_loaded = True
+
+        self.verbose_print(f"Scenario '{self.scenario}' loaded:")
+        self.verbose_print(f"  Action space: {self.action_space}")
+        self.verbose_print(f"  Observation space: {self.observation_space}")
+
+    def _setup_metadata(self):
+        """使用create响应中的环境元数据"""
+        _apply_metadata(self, self._created["metadata"])
+
+    def _result(self, reply: bytes):
+        """解析reset/step的响应与对应槽中的结果，返回首个智能体的观察、奖励、终止、截断标志与info"""
+        offset, size = _REPLY.unpack_from(reply)
+        if size != self._size:
+            self._remap(size)
+        info = json.loads(reply[_REPLY.size :]) if len(reply) > _REPLY.size else {}
+
+        buf, width = self._map, self._dtype.itemsize
+        (n,) = struct.unpack_from("<I", buf, offset)
+        if n == 0:
+            raise RuntimeError("No observations received from environment")
+        lengths = np.frombuffer(buf, dtype="<u4", count=n, offset=offset + 4)
+        pos = offset + 4 + 4 * n
+        observation = np.frombuffer(buf, dtype=self._dtype, count=int(lengths[0]), offset=pos)
+        # 观察之后依次为n个奖励、n个结束标志与n个截断标志
+        flags = np.frombuffer(buf, dtype=self._dtype, count=3 * n, offset=pos + int(lengths.sum()) * width)
+        reward, done, truncated = float(flags[0]), bool(flags[n]), bool(flags[2 * n])
+
+        space = self.observation_space
+        if isinstance(space, spaces.Box):
+            # 图像观察还原为多维形状和uint8类型，其余为float32
+            if observation.size == int(np.prod(space.shape)):
+                observation = observation.reshape(space.shape)
+            observation = observation.astype(space.dtype if len(space.shape) > 1 else np.float32, copy=self.copy)
+        elif self.copy:
+            observation = observation.copy()
+        return observation, reward, done and not truncated, truncated, info
+
+    def reset(self, seed: Optional[int] = None, options: Optional[Dict] = None) -> Tuple[np.ndarray, Dict]:
+        """重置环境"""
+        super(GrpcEnv, self).reset(seed=seed)
+
+        self._create_environment()
+        observation, _, _, _, info = self._result(self._call(_OP_RESET))
+        if len(observation) >= 1:
+            info["observation_size"] = len(observation)
+        return observation, info
+
+    def step(self, action: Union[int, float, np.ndarray, list]) -> Tuple[np.ndarray, float, bool, bool, Dict]:
+        """执行一步：动作值写入映射文件的动作区，套接字上只发送step指令"""
+        if isinstance(action, dict):
+            values = np.asarray(_flat_dict_values(action), dtype=self._dtype)
+        elif isinstance(self.action_space, spaces.Tuple):
+            values = np.asarray(_flat_hybrid_values(action, self.action_space), dtype=self._dtype)
+        else:
+            values = np.asarray(action, dtype=self._dtype).reshape(-1)
+        if values.size > self._actions.size:
+            raise ValueError(f"{values.size} action values exceed the capacity of {self._actions.size}")
+        self._actions[: values.size] = values
+        self._action_count[0] = values.size
+
+        observation, reward, terminated, truncated, info = self._result(self._call(_OP_STEP))
+        info["action_taken"] = action
+        return observation, reward, terminated, truncated, info
+
+    def close(self):
+        """关闭环境，服务端随后删除映射文件"""
+        if self._env_created:
+            try:
+                self._call(_OP_CLOSE)
+                self.verbose_print(f"Environment closed: {self.env_id}")
+            except Exception as e:
+                print(f"Error closing environment: {e}")
+            finally:
+                self._env_created = False
+        if self._file is not None:
+            # 映射在不再被观察引用后由垃圾回收释放
+            self._map = self._actions = self._action_count = None
+            self._file.close()
+            self._file = None
+        if self._sock is not None:
+            self._sock.close()
+            self._sock = None
+
+    def get_available_scenarios(self) -> list:
+        """共享内存传输不提供场景列表"""
+        return []
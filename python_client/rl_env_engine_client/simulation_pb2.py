@@ -25,7 +25,7 @@ _sym_db = _symbol_database.Default()
 from google.protobuf import struct_pb2 as google_dot_protobuf_dot_struct__pb2
 
 
-DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x10simulation.proto\x12\nsimulation\x1a\x1cgoogle/protobuf/struct.proto\"\x10\n\x0eGetInfoRequest\"{\n\x0fGetInfoResponse\x12\x11\n\tscenarios\x18\x01 \x03(\t\x12\x0f\n\x07\x65nv_ids\x18\x02 \x03(\t\x12%\n\x04info\x18\x03 \x01(\x0b\x32\x17.google.protobuf.Struct\x12\x0f\n\x07version\x18\x04 \x01(\t\x12\x0c\n\x04name\x18\x05 \x01(\t\"e\n\x18\x43reateEnvironmentRequest\x12\x0e\n\x06\x65nv_id\x18\x01 \x01(\t\x12\x10\n\x08scenario\x18\x02 \x01(\t\x12\'\n\x06\x63onfig\x18\x03 \x01(\x0b\x32\x17.google.protobuf.Struct\"=\n\x19\x43reateEnvironmentResponse\x12\x0f\n\x07success\x18\x01 \x01(\x08\x12\x0f\n\x07message\x18\x02 \x01(\t\")\n\x17ResetEnvironmentRequest\x12\x0e\n\x06\x65nv_id\x18\x01 \x01(\t\"p\n\x18ResetEnvironmentResponse\x12-\n\x0cobservations\x18\x01 \x03(\x0b\x32\x17.simulation.Observation\x12%\n\x04info\x18\x02 \x01(\x0b\x32\x17.google.protobuf.Struct\"M\n\x16StepEnvironmentRequest\x12\x0e\n\x06\x65nv_id\x18\x01 \x01(\t\x12#\n\x07\x61\x63tions\x18\x02 \x03(\x0b\x32\x12.simulation.Action\"\x8e\x01\n\x17StepEnvironmentResponse\x12-\n\x0cobservations\x18\x01 \x03(\x0b\x32\x17.simulation.Observation\x12\x0f\n\x07rewards\x18\x02 \x03(\x01\x12\x0c\n\x04\x64one\x18\x03 \x03(\x08\x12%\n\x04info\x18\x04 \x01(\x0b\x32\x17.google.protobuf.Struct\")\n\x17\x43loseEnvironmentRequest\x12\x0e\n\x06\x65nv_id\x18\x01 \x01(\t\"<\n\x18\x43loseEnvironmentResponse\x12\x0f\n\x07success\x18\x01 \x01(\x08\x12\x0f\n\x07message\x18\x02 \x01(\t\"F\n\x0bObservation\x12\x0c\n\x04\x64\x61ta\x18\x01 \x03(\x01\x12)\n\x08metadata\x18\x02 \x01(\x0b\x32\x17.google.protobuf.Struct\"\x85\x02\n\x06\x41\x63tion\x12\x15\n\x0b\x66loat_value\x18\x01 \x01(\x01H\x00\x12\x13\n\tint_value\x18\x02 \x01(\x03H\x00\x12\x14\n\nbool_value\x18\x03 \x01(\x08H\x00\x12-\n\x0b\x66loat_array\x18\x04 \x01(\x0b\x32\x16.simulation.FloatArrayH\x00\x12)\n\tint_array\x18\x05 \x01(\x0b\x32\x14.simulation.IntArrayH\x00\x12+\n\nbool_array\x18\x06 \x01(\x0b\x32\x15.simulation.BoolArrayH\x00\x12\x16\n\x0cstring_value\x18\x07 \x01(\tH\x00\x12\x12\n\x08raw_data\x18\x08 \x01(\x0cH\x00\x42\x06\n\x04\x64\x61ta\"\x1c\n\nFloatArray\x12\x0e\n\x06values\x18\x01 \x03(\x01\"\x1a\n\x08IntArray\x12\x0e\n\x06values\x18\x01 \x03(\x03\"\x1b\n\tBoolArray\x12\x0e\n\x06values\x18\x01 \x03(\x08\"\"\n\x10GetSpacesRequest\x12\x0e\n\x06\x65nv_id\x18\x01 \x01(\t\"{\n\x11GetSpacesResponse\x12-\n\x0c\x61\x63tion_space\x18\x01 \x01(\x0b\x32\x17.simulation.ActionSpace\x12\x37\n\x11observation_space\x18\x02 \x01(\x0b\x32\x1c.simulation.ObservationSpace\"\x84\x01\n\x0b\x41\x63tionSpace\x12#\n\x04type\x18\x01 \x01(\x0e\x32\x15.simulation.SpaceType\x12\x0b\n\x03low\x18\x02 \x03(\x01\x12\x0c\n\x04high\x18\x03 \x03(\x01\x12\r\n\x05shape\x18\x04 \x03(\x05\x12\r\n\x05\x64type\x18\x05 \x01(\t\x12\x17\n\x0f\x64iscrete_values\x18\x06 \x03(\x01\"p\n\x10ObservationSpace\x12#\n\x04type\x18\x01 \x01(\x0e\x32\x15.simulation.SpaceType\x12\x0b\n\x03low\x18\x02 \x03(\x01\x12\x0c\n\x04high\x18\x03 \x03(\x01\x12\r\n\x05shape\x18\x04 \x03(\x05\x12\r\n\x05\x64type\x18\x05 \x01(\t*\\\n\tSpaceType\x12\x07\n\x03\x42OX\x10\x00\x12\x0c\n\x08\x44ISCRETE\x10\x01\x12\x12\n\x0eMULTI_DISCRETE\x10\x02\x12\x10\n\x0cMULTI_BINARY\x10\x03\x12\x12\n\x0e\x44ISCRETE_FLOAT\x10\x04\x32\xf8\x04\n\x11SimulationService\x12\x42\n\x07GetInfo\x12\x1a.simulation.GetInfoRequest\x1a\x1b.simulation.GetInfoResponse\x12`\n\x11\x43reateEnvironment\x12$.simulation.CreateEnvironmentRequest\x1a%.simulation.CreateEnvironmentResponse\x12]\n\x10ResetEnvironment\x12#.simulation.ResetEnvironmentRequest\x1a$.simulation.ResetEnvironmentResponse\x12Z\n\x0fStepEnvironment\x12\".simulation.StepEnvironmentRequest\x1a#.simulation.StepEnvironmentResponse\x12]\n\x10\x43loseEnvironment\x12#.simulation.CloseEnvironmentRequest\x1a$.simulation.CloseEnvironmentResponse\x12H\n\tGetSpaces\x12\x1c.simulation.GetSpacesRequest\x1a\x1d.simulation.GetSpacesResponse\x12Y\n\nStreamStep\x12\".simulation.StepEnvironmentRequest\x1a#.simulation.StepEnvironmentResponse(\x01\x30\x01\x42\x32Z0github.com/jelech/rl_env_engine/proto/simulationb\x06proto3')
+DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x10simulation.proto\x12\nsimulation\x1a\x1cgoogle/protobuf/struct.proto\"\x10\n\x0eGetInfoRequest\"\xc3\x03\n\x0fGetInfoResponse\x12\x11\n\tscenarios\x18\x01 \x03(\t\x12\x0f\n\x07\x65nv_ids\x18\x02 \x03(\t\x12%\n\x04info\x18\x03 \x01(\x0b\x32\x17.google.protobuf.Struct\x12\x0f\n\x07version\x18\x04 \x01(\t\x12\x0c\n\x04name\x18\x05 \x01(\t\x12\x42\n\x0cstep_latency\x18\x06 \x03(\x0b\x32,.simulation.GetInfoResponse.StepLatencyEntry\x12>\n\ntyped_info\x18\x07 \x03(\x0b\x32*.simulation.GetInfoResponse.TypedInfoEntry\x12\x13\n\x0b\x61pi_version\x18\x08 \x01(\x05\x12\x17\n\x0fmin_api_version\x18\t \x01(\x05\x1aO\n\x10StepLatencyEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12*\n\x05value\x18\x02 \x01(\x0b\x32\x1b.simulation.ScenarioLatency:\x02\x38\x01\x1a\x43\n\x0eTypedInfoEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12 \n\x05value\x18\x02 \x01(\x0b\x32\x11.simulation.Value:\x02\x38\x01\"h\n\x0fScenarioLatency\x12(\n\x04step\x18\x01 \x01(\x0b\x32\x1a.simulation.LatencySummary\x12+\n\x07request\x18\x02 \x01(\x0b\x32\x1a.simulation.LatencySummary\"\x84\x01\n\x0eLatencySummary\x12\r\n\x05\x63ount\x18\x01 \x01(\x03\x12\x0f\n\x07mean_ms\x18\x02 \x01(\x01\x12\x0e\n\x06p50_ms\x18\x03 \x01(\x01\x12\x0e\n\x06p95_ms\x18\x04 \x01(\x01\x12\x0e\n\x06p99_ms\x18\x05 \x01(\x01\x12\x0e\n\x06max_ms\x18\x06 \x01(\x01\x12\x12\n\nper_second\x18\x07 \x01(\x01\"z\n\x18\x43reateEnvironmentRequest\x12\x0e\n\x06\x65nv_id\x18\x01 \x01(\t\x12\x10\n\x08scenario\x18\x02 \x01(\t\x12\'\n\x06\x63onfig\x18\x03 \x01(\x0b\x32\x17.google.protobuf.Struct\x12\x13\n\x0b\x61pi_version\x18\x04 \x01(\x05\"_\n\x19\x43reateEnvironmentResponse\x12\x0f\n\x07success\x18\x01 \x01(\x08\x12\x0f\n\x07message\x18\x02 \x01(\t\x12 \n\x05\x65rror\x18\x03 \x01(\x0b\x32\x11.simulation.Error\"\x87\x01\n\x05\x45rror\x12\x0c\n\x04\x63ode\x18\x01 \x01(\t\x12\x0f\n\x07message\x18\x02 \x01(\t\x12/\n\x07\x64\x65tails\x18\x03 \x03(\x0b\x32\x1e.simulation.Error.DetailsEntry\x1a.\n\x0c\x44\x65tailsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\")\n\x17ResetEnvironmentRequest\x12\x0e\n\x06\x65nv_id\x18\x01 \x01(\t\"\x99\x03\n\x18ResetEnvironmentResponse\x12-\n\x0cobservations\x18\x01 \x03(\x0b\x32\x17.simulation.Observation\x12%\n\x04info\x18\x02 \x01(\x0b\x32\x17.google.protobuf.Struct\x12G\n\ntyped_info\x18\x03 \x03(\x0b\x32\x33.simulation.ResetEnvironmentResponse.TypedInfoEntry\x12@\n\x06\x61gents\x18\x04 \x03(\x0b\x32\x30.simulation.ResetEnvironmentResponse.AgentsEntry\x12\x11\n\tagent_ids\x18\x05 \x03(\t\x1a\x43\n\x0eTypedInfoEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12 \n\x05value\x18\x02 \x01(\x0b\x32\x11.simulation.Value:\x02\x38\x01\x1a\x44\n\x0b\x41gentsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12$\n\x05value\x18\x02 \x01(\x0b\x32\x15.simulation.AgentStep:\x02\x38\x01\"M\n\x16StepEnvironmentRequest\x12\x0e\n\x06\x65nv_id\x18\x01 \x01(\t\x12#\n\x07\x61\x63tions\x18\x02 \x03(\x0b\x32\x12.simulation.Action\"\xcc\x04\n\x17StepEnvironmentResponse\x12-\n\x0cobservations\x18\x01 \x03(\x0b\x32\x17.simulation.Observation\x12\x0f\n\x07rewards\x18\x02 \x03(\x01\x12\x0c\n\x04\x64one\x18\x03 \x03(\x08\x12%\n\x04info\x18\x04 \x01(\x0b\x32\x17.google.protobuf.Struct\x12\x46\n\ntyped_info\x18\x05 \x03(\x0b\x32\x32.simulation.StepEnvironmentResponse.TypedInfoEntry\x12\x12\n\nterminated\x18\x06 \x03(\x08\x12\x11\n\ttruncated\x18\x07 \x03(\x08\x12\'\n\tstep_type\x18\x08 \x03(\x0e\x32\x14.simulation.StepType\x12\x10\n\x08\x64iscount\x18\t \x03(\x01\x12?\n\x06\x61gents\x18\n \x03(\x0b\x32/.simulation.StepEnvironmentResponse.AgentsEntry\x12\x11\n\tagent_ids\x18\x0b \x03(\t\x12\x33\n\x12\x66inal_observations\x18\x0c \x03(\x0b\x32\x17.simulation.Observation\x1a\x43\n\x0eTypedInfoEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12 \n\x05value\x18\x02 \x01(\x0b\x32\x11.simulation.Value:\x02\x38\x01\x1a\x44\n\x0b\x41gentsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12$\n\x05value\x18\x02 \x01(\x0b\x32\x15.simulation.AgentStep:\x02\x38\x01\"p\n\tAgentStep\x12,\n\x0bobservation\x18\x01 \x01(\x0b\x32\x17.simulation.Observation\x12\x0e\n\x06reward\x18\x02 \x01(\x01\x12\x12\n\nterminated\x18\x03 \x01(\x08\x12\x11\n\ttruncated\x18\x04 \x01(\x08\")\n\x17\x43loseEnvironmentRequest\x12\x0e\n\x06\x65nv_id\x18\x01 \x01(\t\"^\n\x18\x43loseEnvironmentResponse\x12\x0f\n\x07success\x18\x01 \x01(\x08\x12\x0f\n\x07message\x18\x02 \x01(\t\x12 \n\x05\x65rror\x18\x03 \x01(\x0b\x32\x11.simulation.Error\"\xa7\x02\n\x0bObservation\x12\x0c\n\x04\x64\x61ta\x18\x01 \x03(\x01\x12)\n\x08metadata\x18\x02 \x01(\x0b\x32\x17.google.protobuf.Struct\x12\x10\n\x08\x64\x61ta_f32\x18\x03 \x03(\x02\x12\x42\n\x0etyped_metadata\x18\x04 \x03(\x0b\x32*.simulation.Observation.TypedMetadataEntry\x12\x0c\n\x04text\x18\x05 \x01(\t\x12 \n\x05image\x18\x06 \x01(\x0b\x32\x11.simulation.Image\x12\x10\n\x08\x61gent_id\x18\x07 \x01(\t\x1aG\n\x12TypedMetadataEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12 \n\x05value\x18\x02 \x01(\x0b\x32\x11.simulation.Value:\x02\x38\x01\"H\n\x05Image\x12\x0e\n\x06pixels\x18\x01 \x01(\x0c\x12\x0e\n\x06height\x18\x02 \x01(\x05\x12\r\n\x05width\x18\x03 \x01(\x05\x12\x10\n\x08\x63hannels\x18\x04 \x01(\x05\"j\n\x05Value\x12\x16\n\x0c\x64ouble_value\x18\x01 \x01(\x01H\x00\x12\x13\n\tint_value\x18\x02 \x01(\x03H\x00\x12\x14\n\nbool_value\x18\x03 \x01(\x08H\x00\x12\x16\n\x0cstring_value\x18\x04 \x01(\tH\x00\x42\x06\n\x04kind\"\xd9\x02\n\x06\x41\x63tion\x12\x15\n\x0b\x66loat_value\x18\x01 \x01(\x01H\x00\x12\x13\n\tint_value\x18\x02 \x01(\x03H\x00\x12\x14\n\nbool_value\x18\x03 \x01(\x08H\x00\x12-\n\x0b\x66loat_array\x18\x04 \x01(\x0b\x32\x16.simulation.FloatArrayH\x00\x12)\n\tint_array\x18\x05 \x01(\x0b\x32\x14.simulation.IntArrayH\x00\x12+\n\nbool_array\x18\x06 \x01(\x0b\x32\x15.simulation.BoolArrayH\x00\x12\x16\n\x0cstring_value\x18\x07 \x01(\tH\x00\x12\x12\n\x08raw_data\x18\x08 \x01(\x0cH\x00\x12&\n\x04\x64ict\x18\t \x01(\x0b\x32\x16.simulation.ActionDictH\x00\x12*\n\x06hybrid\x18\n \x01(\x0b\x32\x18.simulation.HybridActionH\x00\x42\x06\n\x04\x64\x61ta\"2\n\x0cHybridAction\x12\x0e\n\x06\x63hoice\x18\x01 \x01(\x03\x12\x12\n\nparameters\x18\x02 \x03(\x01\"\x86\x01\n\nActionDict\x12\x34\n\x07\x61\x63tions\x18\x01 \x03(\x0b\x32#.simulation.ActionDict.ActionsEntry\x1a\x42\n\x0c\x41\x63tionsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12!\n\x05value\x18\x02 \x01(\x0b\x32\x12.simulation.Action:\x02\x38\x01\"\x1c\n\nFloatArray\x12\x0e\n\x06values\x18\x01 \x03(\x01\"\x1a\n\x08IntArray\x12\x0e\n\x06values\x18\x01 \x03(\x03\"\x1b\n\tBoolArray\x12\x0e\n\x06values\x18\x01 \x03(\x08\"\"\n\x10GetSpacesRequest\x12\x0e\n\x06\x65nv_id\x18\x01 \x01(\t\"\x90\x01\n\x11GetSpacesResponse\x12-\n\x0c\x61\x63tion_space\x18\x01 \x01(\x0b\x32\x17.simulation.ActionSpace\x12\x37\n\x11observation_space\x18\x02 \x01(\x0b\x32\x1c.simulation.ObservationSpace\x12\x13\n\x0bspaces_json\x18\x03 \x01(\t\"\xe1\x02\n\x0b\x41\x63tionSpace\x12#\n\x04type\x18\x01 \x01(\x0e\x32\x15.simulation.SpaceType\x12\x0b\n\x03low\x18\x02 \x03(\x01\x12\x0c\n\x04high\x18\x03 \x03(\x01\x12\r\n\x05shape\x18\x04 \x03(\x05\x12\r\n\x05\x64type\x18\x05 \x01(\t\x12\x17\n\x0f\x64iscrete_values\x18\x06 \x03(\x01\x12\x0c\n\x04nvec\x18\x07 \x03(\x03\x12\x12\n\nmax_length\x18\x08 \x01(\x05\x12\x0f\n\x07\x63harset\x18\t \x01(\t\x12\x33\n\x06spaces\x18\n \x03(\x0b\x32#.simulation.ActionSpace.SpacesEntry\x12+\n\nparameters\x18\x0b \x03(\x0b\x32\x17.simulation.ActionSpace\x1a\x46\n\x0bSpacesEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12&\n\x05value\x18\x02 \x01(\x0b\x32\x17.simulation.ActionSpace:\x02\x38\x01\"\x95\x01\n\x10ObservationSpace\x12#\n\x04type\x18\x01 \x01(\x0e\x32\x15.simulation.SpaceType\x12\x0b\n\x03low\x18\x02 \x03(\x01\x12\x0c\n\x04high\x18\x03 \x03(\x01\x12\r\n\x05shape\x18\x04 \x03(\x05\x12\r\n\x05\x64type\x18\x05 \x01(\t\x12\x12\n\nmax_length\x18\x06 \x01(\x05\x12\x0f\n\x07\x63harset\x18\x07 \x01(\t\"$\n\x12GetMetadataRequest\x12\x0e\n\x06\x65nv_id\x18\x01 \x01(\t\"v\n\x13GetMetadataResponse\x12\x14\n\x0creward_range\x18\x01 \x03(\x01\x12\x19\n\x11max_episode_steps\x18\x02 \x01(\x05\x12\x14\n\x0crender_modes\x18\x03 \x03(\t\x12\x18\n\x10nondeterministic\x18\x04 \x01(\x08\")\n\x17\x44\x65\x62ugEnvironmentRequest\x12\x0e\n\x06\x65nv_id\x18\x01 \x01(\t\"-\n\x18\x44\x65\x62ugEnvironmentResponse\x12\x11\n\tdump_json\x18\x01 \x01(\t\"\xa4\x01\n\x15\x45valuatePolicyRequest\x12\x10\n\x08scenario\x18\x01 \x01(\t\x12\'\n\x06\x63onfig\x18\x02 \x01(\x0b\x32\x17.google.protobuf.Struct\x12\r\n\x05model\x18\x03 \x01(\x0c\x12\x10\n\x08\x65pisodes\x18\x04 \x01(\x05\x12\x11\n\tmax_steps\x18\x05 \x01(\x05\x12\x0e\n\x06policy\x18\x06 \x01(\t\x12\x0c\n\x04seed\x18\x07 \x01(\x03\"\xb9\x01\n\x16\x45valuatePolicyResponse\x12\x0f\n\x07returns\x18\x01 \x03(\x01\x12\x0f\n\x07lengths\x18\x02 \x03(\x05\x12\x11\n\ttruncated\x18\x03 \x01(\x05\x12\x13\n\x0bmean_return\x18\x04 \x01(\x01\x12\x12\n\nstd_return\x18\x05 \x01(\x01\x12\x13\n\x0bmean_length\x18\x06 \x01(\x01\x12\x13\n\x0btotal_steps\x18\x07 \x01(\x03\x12\x17\n\x0f\x65lapsed_seconds\x18\x08 \x01(\x01\"R\n\x12OpenSessionRequest\x12\x0e\n\x06\x63lient\x18\x01 \x01(\t\x12\x13\n\x0bttl_seconds\x18\x02 \x01(\x05\x12\x17\n\x0f\x62ind_connection\x18\x03 \x01(\x08\">\n\x13OpenSessionResponse\x12\x12\n\nsession_id\x18\x01 \x01(\t\x12\x13\n\x0bttl_seconds\x18\x02 \x01(\x05\")\n\x13\x43loseSessionRequest\x12\x12\n\nsession_id\x18\x01 \x01(\t\"3\n\x14\x43loseSessionResponse\x12\x1b\n\x13\x63losed_environments\x18\x01 \x01(\x05\"\xc4\x01\n\x11\x45nvironmentStatus\x12\x0e\n\x06\x65nv_id\x18\x01 \x01(\t\x12\x10\n\x08scenario\x18\x02 \x01(\t\x12\x12\n\nsession_id\x18\x03 \x01(\t\x12\x0e\n\x06\x63lient\x18\x04 \x01(\t\x12\x13\n\x0b\x61ge_seconds\x18\x05 \x01(\x01\x12\x14\n\x0cidle_seconds\x18\x06 \x01(\x01\x12\r\n\x05steps\x18\x07 \x01(\x03\x12\x10\n\x08\x65pisodes\x18\x08 \x01(\x03\x12\x0e\n\x06tenant\x18\t \x01(\t\x12\r\n\x05\x66\x61ult\x18\n \x01(\t\"\x19\n\x17ListEnvironmentsRequest\"a\n\x18ListEnvironmentsResponse\x12\x33\n\x0c\x65nvironments\x18\x01 \x03(\x0b\x32\x1d.simulation.EnvironmentStatus\x12\x10\n\x08\x64raining\x18\x02 \x01(\x08\".\n\x1c\x46orceCloseEnvironmentRequest\x12\x0e\n\x06\x65nv_id\x18\x01 \x01(\t\"c\n\x1d\x46orceCloseEnvironmentResponse\x12\x0f\n\x07success\x18\x01 \x01(\x08\x12\x0f\n\x07message\x18\x02 \x01(\t\x12 \n\x05\x65rror\x18\x03 \x01(\x0b\x32\x11.simulation.Error\"-\n\x1b\x44umpEnvironmentStateRequest\x12\x0e\n\x06\x65nv_id\x18\x01 \x01(\t\"2\n\x1c\x44umpEnvironmentStateResponse\x12\x12\n\nstate_json\x18\x01 \x01(\t\"6\n\x0c\x44rainRequest\x12\x17\n\x0ftimeout_seconds\x18\x01 \x01(\x01\x12\r\n\x05\x66orce\x18\x02 \x01(\x08\"L\n\rDrainResponse\x12\x1e\n\x16remaining_environments\x18\x01 \x01(\x05\x12\x1b\n\x13\x63losed_environments\x18\x02 \x01(\x05\":\n\x18\x45xportEnvironmentRequest\x12\x0e\n\x06\x65nv_id\x18\x01 \x01(\t\x12\x0e\n\x06\x64\x65tach\x18\x02 \x01(\x08\"C\n\x19\x45xportEnvironmentResponse\x12\x10\n\x08snapshot\x18\x01 \x01(\x0c\x12\x14\n\x0c\x65nvironments\x18\x02 \x01(\x05\",\n\x18ImportEnvironmentRequest\x12\x10\n\x08snapshot\x18\x01 \x01(\x0c\"1\n\x19ImportEnvironmentResponse\x12\x14\n\x0c\x65nvironments\x18\x01 \x01(\x05\";\n\x19MigrateEnvironmentRequest\x12\x0e\n\x06\x65nv_id\x18\x01 \x01(\t\x12\x0e\n\x06worker\x18\x02 \x01(\t\";\n\x1aMigrateEnvironmentResponse\x12\x1d\n\x15migrated_environments\x18\x01 \x01(\x05\"$\n\x12\x44rainWorkerRequest\x12\x0e\n\x06worker\x18\x01 \x01(\t\"T\n\x13\x44rainWorkerResponse\x12\x1d\n\x15migrated_environments\x18\x01 \x01(\x05\x12\x1e\n\x16remaining_environments\x18\x02 \x01(\x05\"J\n\x17RegisterScenarioRequest\x12\x0c\n\x04name\x18\x01 \x01(\t\x12\x13\n\x0b\x64\x65scription\x18\x02 \x01(\t\x12\x0c\n\x04wasm\x18\x03 \x01(\x0c\"*\n\x18RegisterScenarioResponseJ\x04\x08\x01\x10\x02R\x08replaced\"&\n\x14GetCurriculumRequest\x12\x0e\n\x06\x65nv_id\x18\x01 \x01(\t\"J\n\x19SetCurriculumStageRequest\x12\x0e\n\x06\x65nv_id\x18\x01 \x01(\t\x12\r\n\x05stage\x18\x02 \x01(\x05\x12\x0e\n\x06\x66rozen\x18\x03 \x01(\x08\"\x8a\x02\n\x12\x43urriculumProgress\x12\r\n\x05stage\x18\x01 \x01(\x05\x12\x0e\n\x06stages\x18\x02 \x01(\x05\x12\x10\n\x08\x65pisodes\x18\x03 \x01(\x03\x12\x16\n\x0estage_episodes\x18\x04 \x01(\x03\x12\x14\n\x0csuccess_rate\x18\x05 \x01(\x01\x12\x0e\n\x06window\x18\x06 \x01(\x05\x12\x0e\n\x06\x66rozen\x18\x07 \x01(\x08\x12\x42\n\nparameters\x18\x08 \x03(\x0b\x32..simulation.CurriculumProgress.ParametersEntry\x1a\x31\n\x0fParametersEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\x01:\x02\x38\x01*\x87\x01\n\tSpaceType\x12\x07\n\x03\x42OX\x10\x00\x12\x0c\n\x08\x44ISCRETE\x10\x01\x12\x12\n\x0eMULTI_DISCRETE\x10\x02\x12\x10\n\x0cMULTI_BINARY\x10\x03\x12\x12\n\x0e\x44ISCRETE_FLOAT\x10\x04\x12\x08\n\x04TEXT\x10\x05\x12\t\n\x05IMAGE\x10\x06\x12\x08\n\x04\x44ICT\x10\x07\x12\n\n\x06HYBRID\x10\x08*(\n\x08StepType\x12\t\n\x05\x46IRST\x10\x00\x12\x07\n\x03MID\x10\x01\x12\x08\n\x04LAST\x10\x02\x32\xa1\x10\n\x11SimulationService\x12\x42\n\x07GetInfo\x12\x1a.simulation.GetInfoRequest\x1a\x1b.simulation.GetInfoResponse\x12`\n\x11\x43reateEnvironment\x12$.simulation.CreateEnvironmentRequest\x1a%.simulation.CreateEnvironmentResponse\x12]\n\x10ResetEnvironment\x12#.simulation.ResetEnvironmentRequest\x1a$.simulation.ResetEnvironmentResponse\x12Z\n\x0fStepEnvironment\x12\".simulation.StepEnvironmentRequest\x1a#.simulation.StepEnvironmentResponse\x12]\n\x10\x43loseEnvironment\x12#.simulation.CloseEnvironmentRequest\x1a$.simulation.CloseEnvironmentResponse\x12H\n\tGetSpaces\x12\x1c.simulation.GetSpacesRequest\x1a\x1d.simulation.GetSpacesResponse\x12N\n\x0bGetMetadata\x12\x1e.simulation.GetMetadataRequest\x1a\x1f.simulation.GetMetadataResponse\x12]\n\x10\x44\x65\x62ugEnvironment\x12#.simulation.DebugEnvironmentRequest\x1a$.simulation.DebugEnvironmentResponse\x12W\n\x0e\x45valuatePolicy\x12!.simulation.EvaluatePolicyRequest\x1a\".simulation.EvaluatePolicyResponse\x12N\n\x0bOpenSession\x12\x1e.simulation.OpenSessionRequest\x1a\x1f.simulation.OpenSessionResponse\x12Q\n\x0c\x43loseSession\x12\x1f.simulation.CloseSessionRequest\x1a .simulation.CloseSessionResponse\x12]\n\x10ListEnvironments\x12#.simulation.ListEnvironmentsRequest\x1a$.simulation.ListEnvironmentsResponse\x12l\n\x15\x46orceCloseEnvironment\x12(.simulation.ForceCloseEnvironmentRequest\x1a).simulation.ForceCloseEnvironmentResponse\x12i\n\x14\x44umpEnvironmentState\x12\'.simulation.DumpEnvironmentStateRequest\x1a(.simulation.DumpEnvironmentStateResponse\x12<\n\x05\x44rain\x12\x18.simulation.DrainRequest\x1a\x19.simulation.DrainResponse\x12`\n\x11\x45xportEnvironment\x12$.simulation.ExportEnvironmentRequest\x1a%.simulation.ExportEnvironmentResponse\x12`\n\x11ImportEnvironment\x12$.simulation.ImportEnvironmentRequest\x1a%.simulation.ImportEnvironmentResponse\x12\x63\n\x12MigrateEnvironment\x12%.simulation.MigrateEnvironmentRequest\x1a&.simulation.MigrateEnvironmentResponse\x12N\n\x0b\x44rainWorker\x12\x1e.simulation.DrainWorkerRequest\x1a\x1f.simulation.DrainWorkerResponse\x12]\n\x10RegisterScenario\x12#.simulation.RegisterScenarioRequest\x1a$.simulation.RegisterScenarioResponse\x12Q\n\rGetCurriculum\x12 .simulation.GetCurriculumRequest\x1a\x1e.simulation.CurriculumProgress\x12[\n\x12SetCurriculumStage\x12%.simulation.SetCurriculumStageRequest\x1a\x1e.simulation.CurriculumProgress\x12Y\n\nStreamStep\x12\".simulation.StepEnvironmentRequest\x1a#.simulation.StepEnvironmentResponse(\x01\x30\x01\x42\x32Z0github.com/jelech/rl_env_engine/proto/simulationb\x06proto3')
 
 _globals = globals()
 _builder.BuildMessageAndEnumDescriptors(DESCRIPTOR, _globals)
@@ -33,46 +33,172 @@ _builder.BuildTopDescriptorsAndMessages(DESCRIPTOR, 'simulation_pb2', _globals)
 if not _descriptor._USE_C_DESCRIPTORS:
   _globals['DESCRIPTOR']._loaded_options = None
   _globals['DESCRIPTOR']._serialized_options = b'Z0github.com/jelech/rl_env_engine/proto/simulation'
-  _globals['_SPACETYPE']._serialized_start=1690
-  _globals['_SPACETYPE']._serialized_end=1782
+  _globals['_GETINFORESPONSE_STEPLATENCYENTRY']._loaded_options = None
+  _globals['_GETINFORESPONSE_STEPLATENCYENTRY']._serialized_options = b'8\001'
+  _globals['_GETINFORESPONSE_TYPEDINFOENTRY']._loaded_options = None
+  _globals['_GETINFORESPONSE_TYPEDINFOENTRY']._serialized_options = b'8\001'
+  _globals['_ERROR_DETAILSENTRY']._loaded_options = None
+  _globals['_ERROR_DETAILSENTRY']._serialized_options = b'8\001'
+  _globals['_RESETENVIRONMENTRESPONSE_TYPEDINFOENTRY']._loaded_options = None
+  _globals['_RESETENVIRONMENTRESPONSE_TYPEDINFOENTRY']._serialized_options = b'8\001'
+  _globals['_RESETENVIRONMENTRESPONSE_AGENTSENTRY']._loaded_options = None
+  _globals['_RESETENVIRONMENTRESPONSE_AGENTSENTRY']._serialized_options = b'8\001'
+  _globals['_STEPENVIRONMENTRESPONSE_TYPEDINFOENTRY']._loaded_options = None
+  _globals['_STEPENVIRONMENTRESPONSE_TYPEDINFOENTRY']._serialized_options = b'8\001'
+  _globals['_STEPENVIRONMENTRESPONSE_AGENTSENTRY']._loaded_options = None
+  _globals['_STEPENVIRONMENTRESPONSE_AGENTSENTRY']._serialized_options = b'8\001'
+  _globals['_OBSERVATION_TYPEDMETADATAENTRY']._loaded_options = None
+  _globals['_OBSERVATION_TYPEDMETADATAENTRY']._serialized_options = b'8\001'
+  _globals['_ACTIONDICT_ACTIONSENTRY']._loaded_options = None
+  _globals['_ACTIONDICT_ACTIONSENTRY']._serialized_options = b'8\001'
+  _globals['_ACTIONSPACE_SPACESENTRY']._loaded_options = None
+  _globals['_ACTIONSPACE_SPACESENTRY']._serialized_options = b'8\001'
+  _globals['_CURRICULUMPROGRESS_PARAMETERSENTRY']._loaded_options = None
+  _globals['_CURRICULUMPROGRESS_PARAMETERSENTRY']._serialized_options = b'8\001'
+  _globals['_SPACETYPE']._serialized_start=6839
+  _globals['_SPACETYPE']._serialized_end=6974
+  _globals['_STEPTYPE']._serialized_start=6976
+  _globals['_STEPTYPE']._serialized_end=7016
   _globals['_GETINFOREQUEST']._serialized_start=62
   _globals['_GETINFOREQUEST']._serialized_end=78
-  _globals['_GETINFORESPONSE']._serialized_start=80
-  _globals['_GETINFORESPONSE']._serialized_end=203
-  _globals['_CREATEENVIRONMENTREQUEST']._serialized_start=205
-  _globals['_CREATEENVIRONMENTREQUEST']._serialized_end=306
-  _globals['_CREATEENVIRONMENTRESPONSE']._serialized_start=308
-  _globals['_CREATEENVIRONMENTRESPONSE']._serialized_end=369
-  _globals['_RESETENVIRONMENTREQUEST']._serialized_start=371
-  _globals['_RESETENVIRONMENTREQUEST']._serialized_end=412
-  _globals['_RESETENVIRONMENTRESPONSE']._serialized_start=414
-  _globals['_RESETENVIRONMENTRESPONSE']._serialized_end=526
-  _globals['_STEPENVIRONMENTREQUEST']._serialized_start=528
-  _globals['_STEPENVIRONMENTREQUEST']._serialized_end=605
-  _globals['_STEPENVIRONMENTRESPONSE']._serialized_start=608
-  _globals['_STEPENVIRONMENTRESPONSE']._serialized_end=750
-  _globals['_CLOSEENVIRONMENTREQUEST']._serialized_start=752
-  _globals['_CLOSEENVIRONMENTREQUEST']._serialized_end=793
-  _globals['_CLOSEENVIRONMENTRESPONSE']._serialized_start=795
-  _globals['_CLOSEENVIRONMENTRESPONSE']._serialized_end=855
-  _globals['_OBSERVATION']._serialized_start=857
-  _globals['_OBSERVATION']._serialized_end=927
-  _globals['_ACTION']._serialized_start=930
-  _globals['_ACTION']._serialized_end=1191
-  _globals['_FLOATARRAY']._serialized_start=1193
-  _globals['_FLOATARRAY']._serialized_end=1221
-  _globals['_INTARRAY']._serialized_start=1223
-  _globals['_INTARRAY']._serialized_end=1249
-  _globals['_BOOLARRAY']._serialized_start=1251
-  _globals['_BOOLARRAY']._serialized_end=1278
-  _globals['_GETSPACESREQUEST']._serialized_start=1280
-  _globals['_GETSPACESREQUEST']._serialized_end=1314
-  _globals['_GETSPACESRESPONSE']._serialized_start=1316
-  _globals['_GETSPACESRESPONSE']._serialized_end=1439
-  _globals['_ACTIONSPACE']._serialized_start=1442
-  _globals['_ACTIONSPACE']._serialized_end=1574
-  _globals['_OBSERVATIONSPACE']._serialized_start=1576
-  _globals['_OBSERVATIONSPACE']._serialized_end=1688
-  _globals['_SIMULATIONSERVICE']._serialized_start=1785
-  _globals['_SIMULATIONSERVICE']._serialized_end=2417
+  _globals['_GETINFORESPONSE']._serialized_start=81
+  _globals['_GETINFORESPONSE']._serialized_end=532
+  _globals['_GETINFORESPONSE_STEPLATENCYENTRY']._serialized_start=384
+  _globals['_GETINFORESPONSE_STEPLATENCYENTRY']._serialized_end=463
+  _globals['_GETINFORESPONSE_TYPEDINFOENTRY']._serialized_start=465
+  _globals['_GETINFORESPONSE_TYPEDINFOENTRY']._serialized_end=532
+  _globals['_SCENARIOLATENCY']._serialized_start=534
+  _globals['_SCENARIOLATENCY']._serialized_end=638
+  _globals['_LATENCYSUMMARY']._serialized_start=641
+  _globals['_LATENCYSUMMARY']._serialized_end=773
+  _globals['_CREATEENVIRONMENTREQUEST']._serialized_start=775
+  _globals['_CREATEENVIRONMENTREQUEST']._serialized_end=897
+  _globals['_CREATEENVIRONMENTRESPONSE']._serialized_start=899
+  _globals['_CREATEENVIRONMENTRESPONSE']._serialized_end=994
+  _globals['_ERROR']._serialized_start=997
+  _globals['_ERROR']._serialized_end=1132
+  _globals['_ERROR_DETAILSENTRY']._serialized_start=1086
+  _globals['_ERROR_DETAILSENTRY']._serialized_end=1132
+  _globals['_RESETENVIRONMENTREQUEST']._serialized_start=1134
+  _globals['_RESETENVIRONMENTREQUEST']._serialized_end=1175
+  _globals['_RESETENVIRONMENTRESPONSE']._serialized_start=1178
+  _globals['_RESETENVIRONMENTRESPONSE']._serialized_end=1587
+  _globals['_RESETENVIRONMENTRESPONSE_TYPEDINFOENTRY']._serialized_start=1450
+  _globals['_RESETENVIRONMENTRESPONSE_TYPEDINFOENTRY']._serialized_end=1517
+  _globals['_RESETENVIRONMENTRESPONSE_AGENTSENTRY']._serialized_start=1519
+  _globals['_RESETENVIRONMENTRESPONSE_AGENTSENTRY']._serialized_end=1587
+  _globals['_STEPENVIRONMENTREQUEST']._serialized_start=1589
+  _globals['_STEPENVIRONMENTREQUEST']._serialized_end=1666
+  _globals['_STEPENVIRONMENTRESPONSE']._serialized_start=1669
+  _globals['_STEPENVIRONMENTRESPONSE']._serialized_end=2257
+  _globals['_STEPENVIRONMENTRESPONSE_TYPEDINFOENTRY']._serialized_start=2120
+  _globals['_STEPENVIRONMENTRESPONSE_TYPEDINFOENTRY']._serialized_end=2187
+  _globals['_STEPENVIRONMENTRESPONSE_AGENTSENTRY']._serialized_start=2189
+  _globals['_STEPENVIRONMENTRESPONSE_AGENTSENTRY']._serialized_end=2257
+  _globals['_AGENTSTEP']._serialized_start=2259
+  _globals['_AGENTSTEP']._serialized_end=2371
+  _globals['_CLOSEENVIRONMENTREQUEST']._serialized_start=2373
+  _globals['_CLOSEENVIRONMENTREQUEST']._serialized_end=2414
+  _globals['_CLOSEENVIRONMENTRESPONSE']._serialized_start=2416
+  _globals['_CLOSEENVIRONMENTRESPONSE']._serialized_end=2510
+  _globals['_OBSERVATION']._serialized_start=2513
+  _globals['_OBSERVATION']._serialized_end=2808
+  _globals['_OBSERVATION_TYPEDMETADATAENTRY']._serialized_start=2737
+  _globals['_OBSERVATION_TYPEDMETADATAENTRY']._serialized_end=2808
+  _globals['_IMAGE']._serialized_start=2810
+  _globals['_IMAGE']._serialized_end=2882
+  _globals['_VALUE']._serialized_start=2884
+  _globals['_VALUE']._serialized_end=2990
+  _globals['_ACTION']._serialized_start=2993
+  _globals['_ACTION']._serialized_end=3338
+  _globals['_HYBRIDACTION']._serialized_start=3340
+  _globals['_HYBRIDACTION']._serialized_end=3390
+  _globals['_ACTIONDICT']._serialized_start=3393
+  _globals['_ACTIONDICT']._serialized_end=3527
+  _globals['_ACTIONDICT_ACTIONSENTRY']._serialized_start=3461
+  _globals['_ACTIONDICT_ACTIONSENTRY']._serialized_end=3527
+  _globals['_FLOATARRAY']._serialized_start=3529
+  _globals['_FLOATARRAY']._serialized_end=3557
+  _globals['_INTARRAY']._serialized_start=3559
+  _globals['_INTARRAY']._serialized_end=3585
+  _globals['_BOOLARRAY']._serialized_start=3587
+  _globals['_BOOLARRAY']._serialized_end=3614
+  _globals['_GETSPACESREQUEST']._serialized_start=3616
+  _globals['_GETSPACESREQUEST']._serialized_end=3650
+  _globals['_GETSPACESRESPONSE']._serialized_start=3653
+  _globals['_GETSPACESRESPONSE']._serialized_end=3797
+  _globals['_ACTIONSPACE']._serialized_start=3800
+  _globals['_ACTIONSPACE']._serialized_end=4153
+  _globals['_ACTIONSPACE_SPACESENTRY']._serialized_start=4083
+  _globals['_ACTIONSPACE_SPACESENTRY']._serialized_end=4153
+  _globals['_OBSERVATIONSPACE']._serialized_start=4156
+  _globals['_OBSERVATIONSPACE']._serialized_end=4305
+  _globals['_GETMETADATAREQUEST']._serialized_start=4307
+  _globals['_GETMETADATAREQUEST']._serialized_end=4343
+  _globals['_GETMETADATARESPONSE']._serialized_start=4345
+  _globals['_GETMETADATARESPONSE']._serialized_end=4463
+  _globals['_DEBUGENVIRONMENTREQUEST']._serialized_start=4465
+  _globals['_DEBUGENVIRONMENTREQUEST']._serialized_end=4506
+  _globals['_DEBUGENVIRONMENTRESPONSE']._serialized_start=4508
+  _globals['_DEBUGENVIRONMENTRESPONSE']._serialized_end=4553
+  _globals['_EVALUATEPOLICYREQUEST']._serialized_start=4556
+  _globals['_EVALUATEPOLICYREQUEST']._serialized_end=4720
+  _globals['_EVALUATEPOLICYRESPONSE']._serialized_start=4723
+  _globals['_EVALUATEPOLICYRESPONSE']._serialized_end=4908
+  _globals['_OPENSESSIONREQUEST']._serialized_start=4910
+  _globals['_OPENSESSIONREQUEST']._serialized_end=4992
+  _globals['_OPENSESSIONRESPONSE']._serialized_start=4994
+  _globals['_OPENSESSIONRESPONSE']._serialized_end=5056
+  _globals['_CLOSESESSIONREQUEST']._serialized_start=5058
+  _globals['_CLOSESESSIONREQUEST']._serialized_end=5099
+  _globals['_CLOSESESSIONRESPONSE']._serialized_start=5101
+  _globals['_CLOSESESSIONRESPONSE']._serialized_end=5152
+  _globals['_ENVIRONMENTSTATUS']._serialized_start=5155
+  _globals['_ENVIRONMENTSTATUS']._serialized_end=5351
+  _globals['_LISTENVIRONMENTSREQUEST']._serialized_start=5353
+  _globals['_LISTENVIRONMENTSREQUEST']._serialized_end=5378
+  _globals['_LISTENVIRONMENTSRESPONSE']._serialized_start=5380
+  _globals['_LISTENVIRONMENTSRESPONSE']._serialized_end=5477
+  _globals['_FORCECLOSEENVIRONMENTREQUEST']._serialized_start=5479
+  _globals['_FORCECLOSEENVIRONMENTREQUEST']._serialized_end=5525
+  _globals['_FORCECLOSEENVIRONMENTRESPONSE']._serialized_start=5527
+  _globals['_FORCECLOSEENVIRONMENTRESPONSE']._serialized_end=5626
+  _globals['_DUMPENVIRONMENTSTATEREQUEST']._serialized_start=5628
+  _globals['_DUMPENVIRONMENTSTATEREQUEST']._serialized_end=5673
+  _globals['_DUMPENVIRONMENTSTATERESPONSE']._serialized_start=5675
+  _globals['_DUMPENVIRONMENTSTATERESPONSE']._serialized_end=5725
+  _globals['_DRAINREQUEST']._serialized_start=5727
+  _globals['_DRAINREQUEST']._serialized_end=5781
+  _globals['_DRAINRESPONSE']._serialized_start=5783
+  _globals['_DRAINRESPONSE']._serialized_end=5859
+  _globals['_EXPORTENVIRONMENTREQUEST']._serialized_start=5861
+  _globals['_EXPORTENVIRONMENTREQUEST']._serialized_end=5919
+  _globals['_EXPORTENVIRONMENTRESPONSE']._serialized_start=5921
+  _globals['_EXPORTENVIRONMENTRESPONSE']._serialized_end=5988
+  _globals['_IMPORTENVIRONMENTREQUEST']._serialized_start=5990
+  _globals['_IMPORTENVIRONMENTREQUEST']._serialized_end=6034
+  _globals['_IMPORTENVIRONMENTRESPONSE']._serialized_start=6036
+  _globals['_IMPORTENVIRONMENTRESPONSE']._serialized_end=6085
+  _globals['_MIGRATEENVIRONMENTREQUEST']._serialized_start=6087
+  _globals['_MIGRATEENVIRONMENTREQUEST']._serialized_end=6146
+  _globals['_MIGRATEENVIRONMENTRESPONSE']._serialized_start=6148
+  _globals['_MIGRATEENVIRONMENTRESPONSE']._serialized_end=6207
+  _globals['_DRAINWORKERREQUEST']._serialized_start=6209
+  _globals['_DRAINWORKERREQUEST']._serialized_end=6245
+  _globals['_DRAINWORKERRESPONSE']._serialized_start=6247
+  _globals['_DRAINWORKERRESPONSE']._serialized_end=6331
+  _globals['_REGISTERSCENARIOREQUEST']._serialized_start=6333
+  _globals['_REGISTERSCENARIOREQUEST']._serialized_end=6407
+  _globals['_REGISTERSCENARIORESPONSE']._serialized_start=6409
+  _globals['_REGISTERSCENARIORESPONSE']._serialized_end=6451
+  _globals['_GETCURRICULUMREQUEST']._serialized_start=6453
+  _globals['_GETCURRICULUMREQUEST']._serialized_end=6491
+  _globals['_SETCURRICULUMSTAGEREQUEST']._serialized_start=6493
+  _globals['_SETCURRICULUMSTAGEREQUEST']._serialized_end=6567
+  _globals['_CURRICULUMPROGRESS']._serialized_start=6570
+  _globals['_CURRICULUMPROGRESS']._serialized_end=6836
+  _globals['_CURRICULUMPROGRESS_PARAMETERSENTRY']._serialized_start=6787
+  _globals['_CURRICULUMPROGRESS_PARAMETERSENTRY']._serialized_end=6836
+  _globals['_SIMULATIONSERVICE']._serialized_start=7019
+  _globals['_SIMULATIONSERVICE']._serialized_end=9100
 # @@protoc_insertion_point(module_scope)
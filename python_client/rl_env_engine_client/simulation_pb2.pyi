@@ -38,6 +38,14 @@ class _SpaceTypeEnumTypeWrapper(google.protobuf.internal.enum_type_wrapper._Enum
     """多二进制空间 - shape=[bits], low/high全为[0]/[1]"""
     DISCRETE_FLOAT: _SpaceType.ValueType  # 4
     """离散浮点空间 - 预定义的浮点值列表，使用discrete_values字段"""
+    TEXT: _SpaceType.ValueType  # 5
+    """文本空间 (gym.spaces.Text) - max_length为最大字符数，charset为允许的字符"""
+    IMAGE: _SpaceType.ValueType  # 6
+    """图像空间 - shape=[height, width, channels]，uint8像素在Observation.image中返回"""
+    DICT: _SpaceType.ValueType  # 7
+    """复合空间 (gym.spaces.Dict) - 命名子空间在ActionSpace.spaces中"""
+    HYBRID: _SpaceType.ValueType  # 8
+    """参数化空间 - 选择一个离散动作并给出其连续参数，参数空间在ActionSpace.parameters中"""
 
 class SpaceType(_SpaceType, metaclass=_SpaceTypeEnumTypeWrapper): ...
 
@@ -53,8 +61,34 @@ MULTI_BINARY: SpaceType.ValueType  # 3
 """多二进制空间 - shape=[bits], low/high全为[0]/[1]"""
 DISCRETE_FLOAT: SpaceType.ValueType  # 4
 """离散浮点空间 - 预定义的浮点值列表，使用discrete_values字段"""
+TEXT: SpaceType.ValueType  # 5
+"""文本空间 (gym.spaces.Text) - max_length为最大字符数，charset为允许的字符"""
+IMAGE: SpaceType.ValueType  # 6
+"""图像空间 - shape=[height, width, channels]，uint8像素在Observation.image中返回"""
+DICT: SpaceType.ValueType  # 7
+"""复合空间 (gym.spaces.Dict) - 命名子空间在ActionSpace.spaces中"""
+HYBRID: SpaceType.ValueType  # 8
+"""参数化空间 - 选择一个离散动作并给出其连续参数，参数空间在ActionSpace.parameters中"""
 Global___SpaceType: typing_extensions.TypeAlias = SpaceType
 
+class _StepType:
+    ValueType = typing.NewType("ValueType", builtins.int)
+    V: typing_extensions.TypeAlias = ValueType
+
+class _StepTypeEnumTypeWrapper(google.protobuf.internal.enum_type_wrapper._EnumTypeWrapper[_StepType.ValueType], builtins.type):
+    DESCRIPTOR: google.protobuf.descriptor.EnumDescriptor
+    FIRST: _StepType.ValueType  # 0
+    MID: _StepType.ValueType  # 1
+    LAST: _StepType.ValueType  # 2
+
+class StepType(_StepType, metaclass=_StepTypeEnumTypeWrapper):
+    """dm_env的时间步类型，Reset的结果总是FIRST"""
+
+FIRST: StepType.ValueType  # 0
+MID: StepType.ValueType  # 1
+LAST: StepType.ValueType  # 2
+Global___StepType: typing_extensions.TypeAlias = StepType
+
 @typing.final
 class GetInfoRequest(google.protobuf.message.Message):
     """基础消息类型"""
@@ -71,19 +105,76 @@ Global___GetInfoRequest: typing_extensions.TypeAlias = GetInfoRequest
 class GetInfoResponse(google.protobuf.message.Message):
     DESCRIPTOR: google.protobuf.descriptor.Descriptor
 
+    @typing.final
+    class StepLatencyEntry(google.protobuf.message.Message):
+        DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+        KEY_FIELD_NUMBER: builtins.int
+        VALUE_FIELD_NUMBER: builtins.int
+        key: builtins.str
+        @property
+        def value(self) -> Global___ScenarioLatency: ...
+        def __init__(
+            self,
+            *,
+            key: builtins.str = ...,
+            value: Global___ScenarioLatency | None = ...,
+        ) -> None: ...
+        _HasFieldArgType: typing_extensions.TypeAlias = typing.Literal["value", b"value"]
+        def HasField(self, field_name: _HasFieldArgType) -> builtins.bool: ...
+        _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["key", b"key", "value", b"value"]
+        def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
+
+    @typing.final
+    class TypedInfoEntry(google.protobuf.message.Message):
+        DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+        KEY_FIELD_NUMBER: builtins.int
+        VALUE_FIELD_NUMBER: builtins.int
+        key: builtins.str
+        @property
+        def value(self) -> Global___Value: ...
+        def __init__(
+            self,
+            *,
+            key: builtins.str = ...,
+            value: Global___Value | None = ...,
+        ) -> None: ...
+        _HasFieldArgType: typing_extensions.TypeAlias = typing.Literal["value", b"value"]
+        def HasField(self, field_name: _HasFieldArgType) -> builtins.bool: ...
+        _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["key", b"key", "value", b"value"]
+        def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
+
     SCENARIOS_FIELD_NUMBER: builtins.int
     ENV_IDS_FIELD_NUMBER: builtins.int
     INFO_FIELD_NUMBER: builtins.int
     VERSION_FIELD_NUMBER: builtins.int
     NAME_FIELD_NUMBER: builtins.int
+    STEP_LATENCY_FIELD_NUMBER: builtins.int
+    TYPED_INFO_FIELD_NUMBER: builtins.int
+    API_VERSION_FIELD_NUMBER: builtins.int
+    MIN_API_VERSION_FIELD_NUMBER: builtins.int
     version: builtins.str
     name: builtins.str
+    api_version: builtins.int
+    """服务端实现的协议版本与仍支持的最低版本：1为只返回done的四元组语义，2起步进响应返回terminated与truncated"""
+    min_api_version: builtins.int
     @property
     def scenarios(self) -> google.protobuf.internal.containers.RepeatedScalarFieldContainer[builtins.str]: ...
     @property
     def env_ids(self) -> google.protobuf.internal.containers.RepeatedScalarFieldContainer[builtins.str]: ...
     @property
     def info(self) -> google.protobuf.struct_pb2.Struct: ...
+    @property
+    def step_latency(self) -> google.protobuf.internal.containers.MessageMap[builtins.str, Global___ScenarioLatency]:
+        """以场景为键的步进延迟与吞吐，只包含调用方可创建且已步进过的场景"""
+
+    @property
+    def typed_info(self) -> google.protobuf.internal.containers.MessageMap[builtins.str, Global___Value]:
+        """info中的标量字段（计数、名称等）以带类型的Value重复返回，整数不再变成double；
+        info仍保留全部字段以兼容旧客户端
+        """
+
     def __init__(
         self,
         *,
@@ -92,14 +183,82 @@ class GetInfoResponse(google.protobuf.message.Message):
         info: google.protobuf.struct_pb2.Struct | None = ...,
         version: builtins.str = ...,
         name: builtins.str = ...,
+        step_latency: collections.abc.Mapping[builtins.str, Global___ScenarioLatency] | None = ...,
+        typed_info: collections.abc.Mapping[builtins.str, Global___Value] | None = ...,
+        api_version: builtins.int = ...,
+        min_api_version: builtins.int = ...,
     ) -> None: ...
     _HasFieldArgType: typing_extensions.TypeAlias = typing.Literal["info", b"info"]
     def HasField(self, field_name: _HasFieldArgType) -> builtins.bool: ...
-    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["env_ids", b"env_ids", "info", b"info", "name", b"name", "scenarios", b"scenarios", "version", b"version"]
+    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["api_version", b"api_version", "env_ids", b"env_ids", "info", b"info", "min_api_version", b"min_api_version", "name", b"name", "scenarios", b"scenarios", "step_latency", b"step_latency", "typed_info", b"typed_info", "version", b"version"]
     def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
 
 Global___GetInfoResponse: typing_extensions.TypeAlias = GetInfoResponse
 
+@typing.final
+class ScenarioLatency(google.protobuf.message.Message):
+    """ScenarioLatency 一个场景的步进延迟：step为环境Step本身的耗时，
+    request为服务端处理一次步进请求的耗时（解码动作、步进与编码结果，不含网络传输）
+    """
+
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    STEP_FIELD_NUMBER: builtins.int
+    REQUEST_FIELD_NUMBER: builtins.int
+    @property
+    def step(self) -> Global___LatencySummary: ...
+    @property
+    def request(self) -> Global___LatencySummary: ...
+    def __init__(
+        self,
+        *,
+        step: Global___LatencySummary | None = ...,
+        request: Global___LatencySummary | None = ...,
+    ) -> None: ...
+    _HasFieldArgType: typing_extensions.TypeAlias = typing.Literal["request", b"request", "step", b"step"]
+    def HasField(self, field_name: _HasFieldArgType) -> builtins.bool: ...
+    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["request", b"request", "step", b"step"]
+    def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
+
+Global___ScenarioLatency: typing_extensions.TypeAlias = ScenarioLatency
+
+@typing.final
+class LatencySummary(google.protobuf.message.Message):
+    """LatencySummary 延迟直方图的摘要，耗时以毫秒表示"""
+
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    COUNT_FIELD_NUMBER: builtins.int
+    MEAN_MS_FIELD_NUMBER: builtins.int
+    P50_MS_FIELD_NUMBER: builtins.int
+    P95_MS_FIELD_NUMBER: builtins.int
+    P99_MS_FIELD_NUMBER: builtins.int
+    MAX_MS_FIELD_NUMBER: builtins.int
+    PER_SECOND_FIELD_NUMBER: builtins.int
+    count: builtins.int
+    mean_ms: builtins.float
+    p50_ms: builtins.float
+    p95_ms: builtins.float
+    p99_ms: builtins.float
+    max_ms: builtins.float
+    per_second: builtins.float
+    """最近一分钟内每秒的平均步数"""
+    def __init__(
+        self,
+        *,
+        count: builtins.int = ...,
+        mean_ms: builtins.float = ...,
+        p50_ms: builtins.float = ...,
+        p95_ms: builtins.float = ...,
+        p99_ms: builtins.float = ...,
+        max_ms: builtins.float = ...,
+        per_second: builtins.float = ...,
+    ) -> None: ...
+    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["count", b"count", "max_ms", b"max_ms", "mean_ms", b"mean_ms", "p50_ms", b"p50_ms", "p95_ms", b"p95_ms", "p99_ms", b"p99_ms", "per_second", b"per_second"]
+    def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
+
+Global___LatencySummary: typing_extensions.TypeAlias = LatencySummary
+
 @typing.final
 class CreateEnvironmentRequest(google.protobuf.message.Message):
     DESCRIPTOR: google.protobuf.descriptor.Descriptor
@@ -107,8 +266,13 @@ class CreateEnvironmentRequest(google.protobuf.message.Message):
     ENV_ID_FIELD_NUMBER: builtins.int
     SCENARIO_FIELD_NUMBER: builtins.int
     CONFIG_FIELD_NUMBER: builtins.int
+    API_VERSION_FIELD_NUMBER: builtins.int
     env_id: builtins.str
     scenario: builtins.str
+    api_version: builtins.int
+    """客户端期望的协议版本（见GetInfoResponse.api_version），0表示未指定。
+    版本1关闭gymnasium_api、版本2开启；服务端不支持该版本或config中的gymnasium_api与之冲突时拒绝创建
+    """
     @property
     def config(self) -> google.protobuf.struct_pb2.Struct: ...
     def __init__(
@@ -117,10 +281,11 @@ class CreateEnvironmentRequest(google.protobuf.message.Message):
         env_id: builtins.str = ...,
         scenario: builtins.str = ...,
         config: google.protobuf.struct_pb2.Struct | None = ...,
+        api_version: builtins.int = ...,
     ) -> None: ...
     _HasFieldArgType: typing_extensions.TypeAlias = typing.Literal["config", b"config"]
     def HasField(self, field_name: _HasFieldArgType) -> builtins.bool: ...
-    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["config", b"config", "env_id", b"env_id", "scenario", b"scenario"]
+    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["api_version", b"api_version", "config", b"config", "env_id", b"env_id", "scenario", b"scenario"]
     def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
 
 Global___CreateEnvironmentRequest: typing_extensions.TypeAlias = CreateEnvironmentRequest
@@ -131,19 +296,72 @@ class CreateEnvironmentResponse(google.protobuf.message.Message):
 
     SUCCESS_FIELD_NUMBER: builtins.int
     MESSAGE_FIELD_NUMBER: builtins.int
+    ERROR_FIELD_NUMBER: builtins.int
     success: builtins.bool
     message: builtins.str
+    @property
+    def error(self) -> Global___Error:
+        """success为false时的错误"""
+
     def __init__(
         self,
         *,
         success: builtins.bool = ...,
         message: builtins.str = ...,
+        error: Global___Error | None = ...,
     ) -> None: ...
-    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["message", b"message", "success", b"success"]
+    _HasFieldArgType: typing_extensions.TypeAlias = typing.Literal["error", b"error"]
+    def HasField(self, field_name: _HasFieldArgType) -> builtins.bool: ...
+    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["error", b"error", "message", b"message", "success", b"success"]
     def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
 
 Global___CreateEnvironmentResponse: typing_extensions.TypeAlias = CreateEnvironmentResponse
 
+@typing.final
+class Error(google.protobuf.message.Message):
+    """Error 两种传输共用的错误模型：code为规范错误码（与gRPC状态码同名，如NOT_FOUND、INVALID_ARGUMENT），
+    message为可读描述，details为附加的键值（如env_id）。失败的gRPC调用在状态详情中携带一个Error，
+    带success字段的响应在失败时填充error；HTTP错误响应的status、message与details与之一一对应
+    """
+
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    @typing.final
+    class DetailsEntry(google.protobuf.message.Message):
+        DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+        KEY_FIELD_NUMBER: builtins.int
+        VALUE_FIELD_NUMBER: builtins.int
+        key: builtins.str
+        value: builtins.str
+        def __init__(
+            self,
+            *,
+            key: builtins.str = ...,
+            value: builtins.str = ...,
+        ) -> None: ...
+        _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["key", b"key", "value", b"value"]
+        def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
+
+    CODE_FIELD_NUMBER: builtins.int
+    MESSAGE_FIELD_NUMBER: builtins.int
+    DETAILS_FIELD_NUMBER: builtins.int
+    code: builtins.str
+    message: builtins.str
+    @property
+    def details(self) -> google.protobuf.internal.containers.ScalarMap[builtins.str, builtins.str]: ...
+    def __init__(
+        self,
+        *,
+        code: builtins.str = ...,
+        message: builtins.str = ...,
+        details: collections.abc.Mapping[builtins.str, builtins.str] | None = ...,
+    ) -> None: ...
+    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["code", b"code", "details", b"details", "message", b"message"]
+    def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
+
+Global___Error: typing_extensions.TypeAlias = Error
+
 @typing.final
 class ResetEnvironmentRequest(google.protobuf.message.Message):
     DESCRIPTOR: google.protobuf.descriptor.Descriptor
@@ -164,21 +382,79 @@ Global___ResetEnvironmentRequest: typing_extensions.TypeAlias = ResetEnvironment
 class ResetEnvironmentResponse(google.protobuf.message.Message):
     DESCRIPTOR: google.protobuf.descriptor.Descriptor
 
+    @typing.final
+    class TypedInfoEntry(google.protobuf.message.Message):
+        DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+        KEY_FIELD_NUMBER: builtins.int
+        VALUE_FIELD_NUMBER: builtins.int
+        key: builtins.str
+        @property
+        def value(self) -> Global___Value: ...
+        def __init__(
+            self,
+            *,
+            key: builtins.str = ...,
+            value: Global___Value | None = ...,
+        ) -> None: ...
+        _HasFieldArgType: typing_extensions.TypeAlias = typing.Literal["value", b"value"]
+        def HasField(self, field_name: _HasFieldArgType) -> builtins.bool: ...
+        _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["key", b"key", "value", b"value"]
+        def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
+
+    @typing.final
+    class AgentsEntry(google.protobuf.message.Message):
+        DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+        KEY_FIELD_NUMBER: builtins.int
+        VALUE_FIELD_NUMBER: builtins.int
+        key: builtins.str
+        @property
+        def value(self) -> Global___AgentStep: ...
+        def __init__(
+            self,
+            *,
+            key: builtins.str = ...,
+            value: Global___AgentStep | None = ...,
+        ) -> None: ...
+        _HasFieldArgType: typing_extensions.TypeAlias = typing.Literal["value", b"value"]
+        def HasField(self, field_name: _HasFieldArgType) -> builtins.bool: ...
+        _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["key", b"key", "value", b"value"]
+        def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
+
     OBSERVATIONS_FIELD_NUMBER: builtins.int
     INFO_FIELD_NUMBER: builtins.int
+    TYPED_INFO_FIELD_NUMBER: builtins.int
+    AGENTS_FIELD_NUMBER: builtins.int
+    AGENT_IDS_FIELD_NUMBER: builtins.int
     @property
     def observations(self) -> google.protobuf.internal.containers.RepeatedCompositeFieldContainer[Global___Observation]: ...
     @property
     def info(self) -> google.protobuf.struct_pb2.Struct: ...
+    @property
+    def typed_info(self) -> google.protobuf.internal.containers.MessageMap[builtins.str, Global___Value]:
+        """创建时设置typed_values的环境在此返回标量信息，info只保留列表等复合值"""
+
+    @property
+    def agents(self) -> google.protobuf.internal.containers.MessageMap[builtins.str, Global___AgentStep]:
+        """开启agent_dict的环境以智能体名称为键返回各智能体的结果，observations留空"""
+
+    @property
+    def agent_ids(self) -> google.protobuf.internal.containers.RepeatedScalarFieldContainer[builtins.str]:
+        """observations中各观察所属智能体的名称，开启agent_dict时留空"""
+
     def __init__(
         self,
         *,
         observations: collections.abc.Iterable[Global___Observation] | None = ...,
         info: google.protobuf.struct_pb2.Struct | None = ...,
+        typed_info: collections.abc.Mapping[builtins.str, Global___Value] | None = ...,
+        agents: collections.abc.Mapping[builtins.str, Global___AgentStep] | None = ...,
+        agent_ids: collections.abc.Iterable[builtins.str] | None = ...,
     ) -> None: ...
     _HasFieldArgType: typing_extensions.TypeAlias = typing.Literal["info", b"info"]
     def HasField(self, field_name: _HasFieldArgType) -> builtins.bool: ...
-    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["info", b"info", "observations", b"observations"]
+    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["agent_ids", b"agent_ids", "agents", b"agents", "info", b"info", "observations", b"observations", "typed_info", b"typed_info"]
     def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
 
 Global___ResetEnvironmentResponse: typing_extensions.TypeAlias = ResetEnvironmentResponse
@@ -207,10 +483,58 @@ Global___StepEnvironmentRequest: typing_extensions.TypeAlias = StepEnvironmentRe
 class StepEnvironmentResponse(google.protobuf.message.Message):
     DESCRIPTOR: google.protobuf.descriptor.Descriptor
 
+    @typing.final
+    class TypedInfoEntry(google.protobuf.message.Message):
+        DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+        KEY_FIELD_NUMBER: builtins.int
+        VALUE_FIELD_NUMBER: builtins.int
+        key: builtins.str
+        @property
+        def value(self) -> Global___Value: ...
+        def __init__(
+            self,
+            *,
+            key: builtins.str = ...,
+            value: Global___Value | None = ...,
+        ) -> None: ...
+        _HasFieldArgType: typing_extensions.TypeAlias = typing.Literal["value", b"value"]
+        def HasField(self, field_name: _HasFieldArgType) -> builtins.bool: ...
+        _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["key", b"key", "value", b"value"]
+        def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
+
+    @typing.final
+    class AgentsEntry(google.protobuf.message.Message):
+        DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+        KEY_FIELD_NUMBER: builtins.int
+        VALUE_FIELD_NUMBER: builtins.int
+        key: builtins.str
+        @property
+        def value(self) -> Global___AgentStep: ...
+        def __init__(
+            self,
+            *,
+            key: builtins.str = ...,
+            value: Global___AgentStep | None = ...,
+        ) -> None: ...
+        _HasFieldArgType: typing_extensions.TypeAlias = typing.Literal["value", b"value"]
+        def HasField(self, field_name: _HasFieldArgType) -> builtins.bool: ...
+        _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["key", b"key", "value", b"value"]
+        def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
+
     OBSERVATIONS_FIELD_NUMBER: builtins.int
     REWARDS_FIELD_NUMBER: builtins.int
     DONE_FIELD_NUMBER: builtins.int
     INFO_FIELD_NUMBER: builtins.int
+    TYPED_INFO_FIELD_NUMBER: builtins.int
+    TERMINATED_FIELD_NUMBER: builtins.int
+    TRUNCATED_FIELD_NUMBER: builtins.int
+    STEP_TYPE_FIELD_NUMBER: builtins.int
+    DISCOUNT_FIELD_NUMBER: builtins.int
+    AGENTS_FIELD_NUMBER: builtins.int
+    AGENT_IDS_FIELD_NUMBER: builtins.int
+    FINAL_OBSERVATIONS_FIELD_NUMBER: builtins.int
     @property
     def observations(self) -> google.protobuf.internal.containers.RepeatedCompositeFieldContainer[Global___Observation]: ...
     @property
@@ -219,6 +543,42 @@ class StepEnvironmentResponse(google.protobuf.message.Message):
     def done(self) -> google.protobuf.internal.containers.RepeatedScalarFieldContainer[builtins.bool]: ...
     @property
     def info(self) -> google.protobuf.struct_pb2.Struct: ...
+    @property
+    def typed_info(self) -> google.protobuf.internal.containers.MessageMap[builtins.str, Global___Value]:
+        """同ResetEnvironmentResponse.typed_info"""
+
+    @property
+    def terminated(self) -> google.protobuf.internal.containers.RepeatedScalarFieldContainer[builtins.bool]:
+        """开启gymnasium_api的环境填充以下两个字段（Gymnasium ≥0.26语义），done为两者之或"""
+
+    @property
+    def truncated(self) -> google.protobuf.internal.containers.RepeatedScalarFieldContainer[builtins.bool]: ...
+    @property
+    def step_type(self) -> google.protobuf.internal.containers.RepeatedScalarFieldContainer[Global___StepType.ValueType]:
+        """开启dm_env的环境填充以下两个字段：各智能体的时间步类型（MID或LAST）与折扣，
+        终止时折扣为0，截断时为1
+        """
+
+    @property
+    def discount(self) -> google.protobuf.internal.containers.RepeatedScalarFieldContainer[builtins.float]: ...
+    @property
+    def agents(self) -> google.protobuf.internal.containers.MessageMap[builtins.str, Global___AgentStep]:
+        """开启agent_dict的环境以智能体名称为键返回各智能体的结果，
+        observations、rewards、done、terminated与truncated留空
+        """
+
+    @property
+    def agent_ids(self) -> google.protobuf.internal.containers.RepeatedScalarFieldContainer[builtins.str]:
+        """observations、rewards、done、terminated、truncated、step_type与discount中下标i对应的智能体名称，
+        开启agent_dict时留空
+        """
+
+    @property
+    def final_observations(self) -> google.protobuf.internal.containers.RepeatedCompositeFieldContainer[Global___Observation]:
+        """开启auto_reset且所有智能体都已结束时，自动重置前各智能体的最后观察（observations已是新回合的初始观察），
+        截断时据此自举价值；编码方式与observations相同。info中的terminal_observation保留以兼容旧客户端
+        """
+
     def __init__(
         self,
         *,
@@ -226,14 +586,52 @@ class StepEnvironmentResponse(google.protobuf.message.Message):
         rewards: collections.abc.Iterable[builtins.float] | None = ...,
         done: collections.abc.Iterable[builtins.bool] | None = ...,
         info: google.protobuf.struct_pb2.Struct | None = ...,
+        typed_info: collections.abc.Mapping[builtins.str, Global___Value] | None = ...,
+        terminated: collections.abc.Iterable[builtins.bool] | None = ...,
+        truncated: collections.abc.Iterable[builtins.bool] | None = ...,
+        step_type: collections.abc.Iterable[Global___StepType.ValueType] | None = ...,
+        discount: collections.abc.Iterable[builtins.float] | None = ...,
+        agents: collections.abc.Mapping[builtins.str, Global___AgentStep] | None = ...,
+        agent_ids: collections.abc.Iterable[builtins.str] | None = ...,
+        final_observations: collections.abc.Iterable[Global___Observation] | None = ...,
     ) -> None: ...
     _HasFieldArgType: typing_extensions.TypeAlias = typing.Literal["info", b"info"]
     def HasField(self, field_name: _HasFieldArgType) -> builtins.bool: ...
-    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["done", b"done", "info", b"info", "observations", b"observations", "rewards", b"rewards"]
+    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["agent_ids", b"agent_ids", "agents", b"agents", "discount", b"discount", "done", b"done", "final_observations", b"final_observations", "info", b"info", "observations", b"observations", "rewards", b"rewards", "step_type", b"step_type", "terminated", b"terminated", "truncated", b"truncated", "typed_info", b"typed_info"]
     def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
 
 Global___StepEnvironmentResponse: typing_extensions.TypeAlias = StepEnvironmentResponse
 
+@typing.final
+class AgentStep(google.protobuf.message.Message):
+    """AgentStep 一个智能体在一步（或重置）中的结果，观察的元数据即该智能体的信息"""
+
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    OBSERVATION_FIELD_NUMBER: builtins.int
+    REWARD_FIELD_NUMBER: builtins.int
+    TERMINATED_FIELD_NUMBER: builtins.int
+    TRUNCATED_FIELD_NUMBER: builtins.int
+    reward: builtins.float
+    terminated: builtins.bool
+    truncated: builtins.bool
+    @property
+    def observation(self) -> Global___Observation: ...
+    def __init__(
+        self,
+        *,
+        observation: Global___Observation | None = ...,
+        reward: builtins.float = ...,
+        terminated: builtins.bool = ...,
+        truncated: builtins.bool = ...,
+    ) -> None: ...
+    _HasFieldArgType: typing_extensions.TypeAlias = typing.Literal["observation", b"observation"]
+    def HasField(self, field_name: _HasFieldArgType) -> builtins.bool: ...
+    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["observation", b"observation", "reward", b"reward", "terminated", b"terminated", "truncated", b"truncated"]
+    def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
+
+Global___AgentStep: typing_extensions.TypeAlias = AgentStep
+
 @typing.final
 class CloseEnvironmentRequest(google.protobuf.message.Message):
     DESCRIPTOR: google.protobuf.descriptor.Descriptor
@@ -256,15 +654,23 @@ class CloseEnvironmentResponse(google.protobuf.message.Message):
 
     SUCCESS_FIELD_NUMBER: builtins.int
     MESSAGE_FIELD_NUMBER: builtins.int
+    ERROR_FIELD_NUMBER: builtins.int
     success: builtins.bool
     message: builtins.str
+    @property
+    def error(self) -> Global___Error:
+        """success为false时的错误"""
+
     def __init__(
         self,
         *,
         success: builtins.bool = ...,
         message: builtins.str = ...,
+        error: Global___Error | None = ...,
     ) -> None: ...
-    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["message", b"message", "success", b"success"]
+    _HasFieldArgType: typing_extensions.TypeAlias = typing.Literal["error", b"error"]
+    def HasField(self, field_name: _HasFieldArgType) -> builtins.bool: ...
+    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["error", b"error", "message", b"message", "success", b"success"]
     def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
 
 Global___CloseEnvironmentResponse: typing_extensions.TypeAlias = CloseEnvironmentResponse
@@ -275,25 +681,131 @@ class Observation(google.protobuf.message.Message):
 
     DESCRIPTOR: google.protobuf.descriptor.Descriptor
 
+    @typing.final
+    class TypedMetadataEntry(google.protobuf.message.Message):
+        DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+        KEY_FIELD_NUMBER: builtins.int
+        VALUE_FIELD_NUMBER: builtins.int
+        key: builtins.str
+        @property
+        def value(self) -> Global___Value: ...
+        def __init__(
+            self,
+            *,
+            key: builtins.str = ...,
+            value: Global___Value | None = ...,
+        ) -> None: ...
+        _HasFieldArgType: typing_extensions.TypeAlias = typing.Literal["value", b"value"]
+        def HasField(self, field_name: _HasFieldArgType) -> builtins.bool: ...
+        _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["key", b"key", "value", b"value"]
+        def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
+
     DATA_FIELD_NUMBER: builtins.int
     METADATA_FIELD_NUMBER: builtins.int
+    DATA_F32_FIELD_NUMBER: builtins.int
+    TYPED_METADATA_FIELD_NUMBER: builtins.int
+    TEXT_FIELD_NUMBER: builtins.int
+    IMAGE_FIELD_NUMBER: builtins.int
+    AGENT_ID_FIELD_NUMBER: builtins.int
+    text: builtins.str
+    """Text观察空间的环境在此返回文本观察，data为空"""
+    agent_id: builtins.str
+    """该观察所属智能体的名称（场景实现AgentNamer时如predator_0，否则为agent_0、agent_1……）"""
     @property
     def data(self) -> google.protobuf.internal.containers.RepeatedScalarFieldContainer[builtins.float]: ...
     @property
     def metadata(self) -> google.protobuf.struct_pb2.Struct: ...
+    @property
+    def data_f32(self) -> google.protobuf.internal.containers.RepeatedScalarFieldContainer[builtins.float]:
+        """dtype为float32的环境填充此字段，data为空"""
+
+    @property
+    def typed_metadata(self) -> google.protobuf.internal.containers.MessageMap[builtins.str, Global___Value]:
+        """创建时设置typed_values的环境在此返回标量元数据，metadata只保留复合值"""
+
+    @property
+    def image(self) -> Global___Image:
+        """Image观察空间的环境在此返回图像观察，data为空"""
+
     def __init__(
         self,
         *,
         data: collections.abc.Iterable[builtins.float] | None = ...,
         metadata: google.protobuf.struct_pb2.Struct | None = ...,
+        data_f32: collections.abc.Iterable[builtins.float] | None = ...,
+        typed_metadata: collections.abc.Mapping[builtins.str, Global___Value] | None = ...,
+        text: builtins.str = ...,
+        image: Global___Image | None = ...,
+        agent_id: builtins.str = ...,
     ) -> None: ...
-    _HasFieldArgType: typing_extensions.TypeAlias = typing.Literal["metadata", b"metadata"]
+    _HasFieldArgType: typing_extensions.TypeAlias = typing.Literal["image", b"image", "metadata", b"metadata"]
     def HasField(self, field_name: _HasFieldArgType) -> builtins.bool: ...
-    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["data", b"data", "metadata", b"metadata"]
+    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["agent_id", b"agent_id", "data", b"data", "data_f32", b"data_f32", "image", b"image", "metadata", b"metadata", "text", b"text", "typed_metadata", b"typed_metadata"]
     def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
 
 Global___Observation: typing_extensions.TypeAlias = Observation
 
+@typing.final
+class Image(google.protobuf.message.Message):
+    """图像观察：行优先HWC排列的uint8像素"""
+
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    PIXELS_FIELD_NUMBER: builtins.int
+    HEIGHT_FIELD_NUMBER: builtins.int
+    WIDTH_FIELD_NUMBER: builtins.int
+    CHANNELS_FIELD_NUMBER: builtins.int
+    pixels: builtins.bytes
+    """长度为height*width*channels"""
+    height: builtins.int
+    width: builtins.int
+    channels: builtins.int
+    def __init__(
+        self,
+        *,
+        pixels: builtins.bytes = ...,
+        height: builtins.int = ...,
+        width: builtins.int = ...,
+        channels: builtins.int = ...,
+    ) -> None: ...
+    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["channels", b"channels", "height", b"height", "pixels", b"pixels", "width", b"width"]
+    def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
+
+Global___Image: typing_extensions.TypeAlias = Image
+
+@typing.final
+class Value(google.protobuf.message.Message):
+    """带类型的标量值，整数不会像Struct中的数值那样变为double"""
+
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    DOUBLE_VALUE_FIELD_NUMBER: builtins.int
+    INT_VALUE_FIELD_NUMBER: builtins.int
+    BOOL_VALUE_FIELD_NUMBER: builtins.int
+    STRING_VALUE_FIELD_NUMBER: builtins.int
+    double_value: builtins.float
+    int_value: builtins.int
+    bool_value: builtins.bool
+    string_value: builtins.str
+    def __init__(
+        self,
+        *,
+        double_value: builtins.float = ...,
+        int_value: builtins.int = ...,
+        bool_value: builtins.bool = ...,
+        string_value: builtins.str = ...,
+    ) -> None: ...
+    _HasFieldArgType: typing_extensions.TypeAlias = typing.Literal["bool_value", b"bool_value", "double_value", b"double_value", "int_value", b"int_value", "kind", b"kind", "string_value", b"string_value"]
+    def HasField(self, field_name: _HasFieldArgType) -> builtins.bool: ...
+    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["bool_value", b"bool_value", "double_value", b"double_value", "int_value", b"int_value", "kind", b"kind", "string_value", b"string_value"]
+    def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
+    _WhichOneofReturnType_kind: typing_extensions.TypeAlias = typing.Literal["double_value", "int_value", "bool_value", "string_value"]
+    _WhichOneofArgType_kind: typing_extensions.TypeAlias = typing.Literal["kind", b"kind"]
+    def WhichOneof(self, oneof_group: _WhichOneofArgType_kind) -> _WhichOneofReturnType_kind | None: ...
+
+Global___Value: typing_extensions.TypeAlias = Value
+
 @typing.final
 class Action(google.protobuf.message.Message):
     DESCRIPTOR: google.protobuf.descriptor.Descriptor
@@ -306,12 +818,14 @@ class Action(google.protobuf.message.Message):
     BOOL_ARRAY_FIELD_NUMBER: builtins.int
     STRING_VALUE_FIELD_NUMBER: builtins.int
     RAW_DATA_FIELD_NUMBER: builtins.int
+    DICT_FIELD_NUMBER: builtins.int
+    HYBRID_FIELD_NUMBER: builtins.int
     float_value: builtins.float
     """单个数值（最常见）"""
     int_value: builtins.int
     bool_value: builtins.bool
     string_value: builtins.str
-    """字符串类型（用于离散动作）"""
+    """字符串类型（用于离散动作与Text动作）"""
     raw_data: builtins.bytes
     """原始字节数据（用于复杂自定义类型）"""
     @property
@@ -319,9 +833,21 @@ class Action(google.protobuf.message.Message):
         """数组类型"""
 
     @property
-    def int_array(self) -> Global___IntArray: ...
+    def int_array(self) -> Global___IntArray:
+        """MultiDiscrete动作：每维一个整数"""
+
+    @property
+    def bool_array(self) -> Global___BoolArray:
+        """MultiBinary动作：每维一个开关"""
+
+    @property
+    def dict(self) -> Global___ActionDict:
+        """命名分量组成的复合动作（用于DICT动作空间）"""
+
     @property
-    def bool_array(self) -> Global___BoolArray: ...
+    def hybrid(self) -> Global___HybridAction:
+        """参数化动作（用于HYBRID动作空间）"""
+
     def __init__(
         self,
         *,
@@ -333,17 +859,83 @@ class Action(google.protobuf.message.Message):
         bool_array: Global___BoolArray | None = ...,
         string_value: builtins.str = ...,
         raw_data: builtins.bytes = ...,
+        dict: Global___ActionDict | None = ...,
+        hybrid: Global___HybridAction | None = ...,
     ) -> None: ...
-    _HasFieldArgType: typing_extensions.TypeAlias = typing.Literal["bool_array", b"bool_array", "bool_value", b"bool_value", "data", b"data", "float_array", b"float_array", "float_value", b"float_value", "int_array", b"int_array", "int_value", b"int_value", "raw_data", b"raw_data", "string_value", b"string_value"]
+    _HasFieldArgType: typing_extensions.TypeAlias = typing.Literal["bool_array", b"bool_array", "bool_value", b"bool_value", "data", b"data", "dict", b"dict", "float_array", b"float_array", "float_value", b"float_value", "hybrid", b"hybrid", "int_array", b"int_array", "int_value", b"int_value", "raw_data", b"raw_data", "string_value", b"string_value"]
     def HasField(self, field_name: _HasFieldArgType) -> builtins.bool: ...
-    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["bool_array", b"bool_array", "bool_value", b"bool_value", "data", b"data", "float_array", b"float_array", "float_value", b"float_value", "int_array", b"int_array", "int_value", b"int_value", "raw_data", b"raw_data", "string_value", b"string_value"]
+    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["bool_array", b"bool_array", "bool_value", b"bool_value", "data", b"data", "dict", b"dict", "float_array", b"float_array", "float_value", b"float_value", "hybrid", b"hybrid", "int_array", b"int_array", "int_value", b"int_value", "raw_data", b"raw_data", "string_value", b"string_value"]
     def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
-    _WhichOneofReturnType_data: typing_extensions.TypeAlias = typing.Literal["float_value", "int_value", "bool_value", "float_array", "int_array", "bool_array", "string_value", "raw_data"]
+    _WhichOneofReturnType_data: typing_extensions.TypeAlias = typing.Literal["float_value", "int_value", "bool_value", "float_array", "int_array", "bool_array", "string_value", "raw_data", "dict", "hybrid"]
     _WhichOneofArgType_data: typing_extensions.TypeAlias = typing.Literal["data", b"data"]
     def WhichOneof(self, oneof_group: _WhichOneofArgType_data) -> _WhichOneofReturnType_data | None: ...
 
 Global___Action: typing_extensions.TypeAlias = Action
 
+@typing.final
+class ActionDict(google.protobuf.message.Message):
+    """复合动作：键为DICT动作空间中子空间的名称，每个子空间一个分量"""
+
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    @typing.final
+    class ActionsEntry(google.protobuf.message.Message):
+        DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+        KEY_FIELD_NUMBER: builtins.int
+        VALUE_FIELD_NUMBER: builtins.int
+        key: builtins.str
+        @property
+        def value(self) -> Global___Action: ...
+        def __init__(
+            self,
+            *,
+            key: builtins.str = ...,
+            value: Global___Action | None = ...,
+        ) -> None: ...
+        _HasFieldArgType: typing_extensions.TypeAlias = typing.Literal["value", b"value"]
+        def HasField(self, field_name: _HasFieldArgType) -> builtins.bool: ...
+        _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["key", b"key", "value", b"value"]
+        def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
+
+    ACTIONS_FIELD_NUMBER: builtins.int
+    @property
+    def actions(self) -> google.protobuf.internal.containers.MessageMap[builtins.str, Global___Action]: ...
+    def __init__(
+        self,
+        *,
+        actions: collections.abc.Mapping[builtins.str, Global___Action] | None = ...,
+    ) -> None: ...
+    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["actions", b"actions"]
+    def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
+
+Global___ActionDict: typing_extensions.TypeAlias = ActionDict
+
+@typing.final
+class HybridAction(google.protobuf.message.Message):
+    """参数化动作：所选的离散动作及其连续参数"""
+
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    CHOICE_FIELD_NUMBER: builtins.int
+    PARAMETERS_FIELD_NUMBER: builtins.int
+    choice: builtins.int
+    """离散动作的下标"""
+    @property
+    def parameters(self) -> google.protobuf.internal.containers.RepeatedScalarFieldContainer[builtins.float]:
+        """所选动作的参数，个数与ActionSpace.parameters[choice]的元素个数一致"""
+
+    def __init__(
+        self,
+        *,
+        choice: builtins.int = ...,
+        parameters: collections.abc.Iterable[builtins.float] | None = ...,
+    ) -> None: ...
+    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["choice", b"choice", "parameters", b"parameters"]
+    def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
+
+Global___HybridAction: typing_extensions.TypeAlias = HybridAction
+
 @typing.final
 class FloatArray(google.protobuf.message.Message):
     """辅助消息类型"""
@@ -422,6 +1014,9 @@ class GetSpacesResponse(google.protobuf.message.Message):
 
     ACTION_SPACE_FIELD_NUMBER: builtins.int
     OBSERVATION_SPACE_FIELD_NUMBER: builtins.int
+    SPACES_JSON_FIELD_NUMBER: builtins.int
+    spaces_json: builtins.str
+    """与HTTP /spaces相同的Gym兼容JSON（core.GymSpaces），可直接构造gymnasium空间"""
     @property
     def action_space(self) -> Global___ActionSpace: ...
     @property
@@ -431,10 +1026,11 @@ class GetSpacesResponse(google.protobuf.message.Message):
         *,
         action_space: Global___ActionSpace | None = ...,
         observation_space: Global___ObservationSpace | None = ...,
+        spaces_json: builtins.str = ...,
     ) -> None: ...
     _HasFieldArgType: typing_extensions.TypeAlias = typing.Literal["action_space", b"action_space", "observation_space", b"observation_space"]
     def HasField(self, field_name: _HasFieldArgType) -> builtins.bool: ...
-    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["action_space", b"action_space", "observation_space", b"observation_space"]
+    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["action_space", b"action_space", "observation_space", b"observation_space", "spaces_json", b"spaces_json"]
     def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
 
 Global___GetSpacesResponse: typing_extensions.TypeAlias = GetSpacesResponse
@@ -443,12 +1039,37 @@ Global___GetSpacesResponse: typing_extensions.TypeAlias = GetSpacesResponse
 class ActionSpace(google.protobuf.message.Message):
     DESCRIPTOR: google.protobuf.descriptor.Descriptor
 
+    @typing.final
+    class SpacesEntry(google.protobuf.message.Message):
+        DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+        KEY_FIELD_NUMBER: builtins.int
+        VALUE_FIELD_NUMBER: builtins.int
+        key: builtins.str
+        @property
+        def value(self) -> Global___ActionSpace: ...
+        def __init__(
+            self,
+            *,
+            key: builtins.str = ...,
+            value: Global___ActionSpace | None = ...,
+        ) -> None: ...
+        _HasFieldArgType: typing_extensions.TypeAlias = typing.Literal["value", b"value"]
+        def HasField(self, field_name: _HasFieldArgType) -> builtins.bool: ...
+        _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["key", b"key", "value", b"value"]
+        def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
+
     TYPE_FIELD_NUMBER: builtins.int
     LOW_FIELD_NUMBER: builtins.int
     HIGH_FIELD_NUMBER: builtins.int
     SHAPE_FIELD_NUMBER: builtins.int
     DTYPE_FIELD_NUMBER: builtins.int
     DISCRETE_VALUES_FIELD_NUMBER: builtins.int
+    NVEC_FIELD_NUMBER: builtins.int
+    MAX_LENGTH_FIELD_NUMBER: builtins.int
+    CHARSET_FIELD_NUMBER: builtins.int
+    SPACES_FIELD_NUMBER: builtins.int
+    PARAMETERS_FIELD_NUMBER: builtins.int
     type: Global___SpaceType.ValueType
     dtype: builtins.str
     """Discrete: [] (标量)
@@ -457,6 +1078,10 @@ class ActionSpace(google.protobuf.message.Message):
     MultiBinary: [num_binary_actions]
     数据类型: "int32", "float32", etc.
     """
+    max_length: builtins.int
+    """当type=TEXT时，文本的最大字符数；动作以string_value发送"""
+    charset: builtins.str
+    """当type=TEXT时，允许的字符，为空时为大小写字母与数字"""
     @property
     def low(self) -> google.protobuf.internal.containers.RepeatedScalarFieldContainer[builtins.float]:
         """最小值 (每维度一个值)"""
@@ -475,6 +1100,20 @@ class ActionSpace(google.protobuf.message.Message):
         当type=DISCRETE时，可选的具体离散值列表
         """
 
+    @property
+    def nvec(self) -> google.protobuf.internal.containers.RepeatedScalarFieldContainer[builtins.int]:
+        """当type=MULTI_DISCRETE时，各维的取值个数（high-low+1），各维取值从low开始
+        动作以IntArray（或整数值的FloatArray）发送，每维一个值
+        """
+
+    @property
+    def spaces(self) -> google.protobuf.internal.containers.MessageMap[builtins.str, Global___ActionSpace]:
+        """当type=DICT时，各命名分量的动作空间；动作以Action.dict发送"""
+
+    @property
+    def parameters(self) -> google.protobuf.internal.containers.RepeatedCompositeFieldContainer[Global___ActionSpace]:
+        """当type=HYBRID时，每个离散动作的参数空间（BOX）；动作以Action.hybrid发送"""
+
     def __init__(
         self,
         *,
@@ -484,8 +1123,13 @@ class ActionSpace(google.protobuf.message.Message):
         shape: collections.abc.Iterable[builtins.int] | None = ...,
         dtype: builtins.str = ...,
         discrete_values: collections.abc.Iterable[builtins.float] | None = ...,
+        nvec: collections.abc.Iterable[builtins.int] | None = ...,
+        max_length: builtins.int = ...,
+        charset: builtins.str = ...,
+        spaces: collections.abc.Mapping[builtins.str, Global___ActionSpace] | None = ...,
+        parameters: collections.abc.Iterable[Global___ActionSpace] | None = ...,
     ) -> None: ...
-    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["discrete_values", b"discrete_values", "dtype", b"dtype", "high", b"high", "low", b"low", "shape", b"shape", "type", b"type"]
+    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["charset", b"charset", "discrete_values", b"discrete_values", "dtype", b"dtype", "high", b"high", "low", b"low", "max_length", b"max_length", "nvec", b"nvec", "parameters", b"parameters", "shape", b"shape", "spaces", b"spaces", "type", b"type"]
     def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
 
 Global___ActionSpace: typing_extensions.TypeAlias = ActionSpace
@@ -499,9 +1143,15 @@ class ObservationSpace(google.protobuf.message.Message):
     HIGH_FIELD_NUMBER: builtins.int
     SHAPE_FIELD_NUMBER: builtins.int
     DTYPE_FIELD_NUMBER: builtins.int
+    MAX_LENGTH_FIELD_NUMBER: builtins.int
+    CHARSET_FIELD_NUMBER: builtins.int
     type: Global___SpaceType.ValueType
     dtype: builtins.str
     """数据类型"""
+    max_length: builtins.int
+    """当type=TEXT时，文本的最大字符数；观察在Observation.text中返回"""
+    charset: builtins.str
+    """当type=TEXT时，允许的字符，为空时为大小写字母与数字"""
     @property
     def low(self) -> google.protobuf.internal.containers.RepeatedScalarFieldContainer[builtins.float]:
         """最小值"""
@@ -522,8 +1172,754 @@ class ObservationSpace(google.protobuf.message.Message):
         high: collections.abc.Iterable[builtins.float] | None = ...,
         shape: collections.abc.Iterable[builtins.int] | None = ...,
         dtype: builtins.str = ...,
+        max_length: builtins.int = ...,
+        charset: builtins.str = ...,
     ) -> None: ...
-    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["dtype", b"dtype", "high", b"high", "low", b"low", "shape", b"shape", "type", b"type"]
+    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["charset", b"charset", "dtype", b"dtype", "high", b"high", "low", b"low", "max_length", b"max_length", "shape", b"shape", "type", b"type"]
     def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
 
 Global___ObservationSpace: typing_extensions.TypeAlias = ObservationSpace
+
+@typing.final
+class GetMetadataRequest(google.protobuf.message.Message):
+    """环境元数据相关消息"""
+
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    ENV_ID_FIELD_NUMBER: builtins.int
+    env_id: builtins.str
+    def __init__(
+        self,
+        *,
+        env_id: builtins.str = ...,
+    ) -> None: ...
+    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["env_id", b"env_id"]
+    def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
+
+Global___GetMetadataRequest: typing_extensions.TypeAlias = GetMetadataRequest
+
+@typing.final
+class GetMetadataResponse(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    REWARD_RANGE_FIELD_NUMBER: builtins.int
+    MAX_EPISODE_STEPS_FIELD_NUMBER: builtins.int
+    RENDER_MODES_FIELD_NUMBER: builtins.int
+    NONDETERMINISTIC_FIELD_NUMBER: builtins.int
+    max_episode_steps: builtins.int
+    """每回合最大步数（截断），0表示不截断"""
+    nondeterministic: builtins.bool
+    """固定种子下转移是否仍可能不同"""
+    @property
+    def reward_range(self) -> google.protobuf.internal.containers.RepeatedScalarFieldContainer[builtins.float]:
+        """单步奖励的[最小值, 最大值]，无界时为-inf/+inf"""
+
+    @property
+    def render_modes(self) -> google.protobuf.internal.containers.RepeatedScalarFieldContainer[builtins.str]:
+        """支持的渲染模式: "ansi", "rgb_array\""""
+
+    def __init__(
+        self,
+        *,
+        reward_range: collections.abc.Iterable[builtins.float] | None = ...,
+        max_episode_steps: builtins.int = ...,
+        render_modes: collections.abc.Iterable[builtins.str] | None = ...,
+        nondeterministic: builtins.bool = ...,
+    ) -> None: ...
+    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["max_episode_steps", b"max_episode_steps", "nondeterministic", b"nondeterministic", "render_modes", b"render_modes", "reward_range", b"reward_range"]
+    def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
+
+Global___GetMetadataResponse: typing_extensions.TypeAlias = GetMetadataResponse
+
+@typing.final
+class DebugEnvironmentRequest(google.protobuf.message.Message):
+    """环境调试相关消息"""
+
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    ENV_ID_FIELD_NUMBER: builtins.int
+    env_id: builtins.str
+    def __init__(
+        self,
+        *,
+        env_id: builtins.str = ...,
+    ) -> None: ...
+    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["env_id", b"env_id"]
+    def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
+
+Global___DebugEnvironmentRequest: typing_extensions.TypeAlias = DebugEnvironmentRequest
+
+@typing.final
+class DebugEnvironmentResponse(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    DUMP_JSON_FIELD_NUMBER: builtins.int
+    dump_json: builtins.str
+    """JSON编码的环境内部状态，字段与HTTP的GET /debug/env/{id}相同"""
+    def __init__(
+        self,
+        *,
+        dump_json: builtins.str = ...,
+    ) -> None: ...
+    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["dump_json", b"dump_json"]
+    def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
+
+Global___DebugEnvironmentResponse: typing_extensions.TypeAlias = DebugEnvironmentResponse
+
+@typing.final
+class EvaluatePolicyRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    SCENARIO_FIELD_NUMBER: builtins.int
+    CONFIG_FIELD_NUMBER: builtins.int
+    MODEL_FIELD_NUMBER: builtins.int
+    EPISODES_FIELD_NUMBER: builtins.int
+    MAX_STEPS_FIELD_NUMBER: builtins.int
+    POLICY_FIELD_NUMBER: builtins.int
+    SEED_FIELD_NUMBER: builtins.int
+    scenario: builtins.str
+    model: builtins.bytes
+    """序列化的ONNX ModelProto，支持的算子见core/policy"""
+    episodes: builtins.int
+    """回合数，0表示1"""
+    max_steps: builtins.int
+    """每回合最大步数，0表示10000"""
+    policy: builtins.str
+    """"onnx"（为空时的默认值）使用model；"random"在动作空间中均匀随机采样作为基线，忽略model"""
+    seed: builtins.int
+    """random策略的随机数种子"""
+    @property
+    def config(self) -> google.protobuf.struct_pb2.Struct:
+        """与CreateEnvironmentRequest.config相同"""
+
+    def __init__(
+        self,
+        *,
+        scenario: builtins.str = ...,
+        config: google.protobuf.struct_pb2.Struct | None = ...,
+        model: builtins.bytes = ...,
+        episodes: builtins.int = ...,
+        max_steps: builtins.int = ...,
+        policy: builtins.str = ...,
+        seed: builtins.int = ...,
+    ) -> None: ...
+    _HasFieldArgType: typing_extensions.TypeAlias = typing.Literal["config", b"config"]
+    def HasField(self, field_name: _HasFieldArgType) -> builtins.bool: ...
+    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["config", b"config", "episodes", b"episodes", "max_steps", b"max_steps", "model", b"model", "policy", b"policy", "scenario", b"scenario", "seed", b"seed"]
+    def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
+
+Global___EvaluatePolicyRequest: typing_extensions.TypeAlias = EvaluatePolicyRequest
+
+@typing.final
+class EvaluatePolicyResponse(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    RETURNS_FIELD_NUMBER: builtins.int
+    LENGTHS_FIELD_NUMBER: builtins.int
+    TRUNCATED_FIELD_NUMBER: builtins.int
+    MEAN_RETURN_FIELD_NUMBER: builtins.int
+    STD_RETURN_FIELD_NUMBER: builtins.int
+    MEAN_LENGTH_FIELD_NUMBER: builtins.int
+    TOTAL_STEPS_FIELD_NUMBER: builtins.int
+    ELAPSED_SECONDS_FIELD_NUMBER: builtins.int
+    truncated: builtins.int
+    """因截断（场景的最大步数、info中的truncated或max_steps）结束的回合数"""
+    mean_return: builtins.float
+    std_return: builtins.float
+    """回报的样本标准差"""
+    mean_length: builtins.float
+    total_steps: builtins.int
+    elapsed_seconds: builtins.float
+    @property
+    def returns(self) -> google.protobuf.internal.containers.RepeatedScalarFieldContainer[builtins.float]:
+        """每回合所有智能体的奖励之和"""
+
+    @property
+    def lengths(self) -> google.protobuf.internal.containers.RepeatedScalarFieldContainer[builtins.int]:
+        """每回合的步数"""
+
+    def __init__(
+        self,
+        *,
+        returns: collections.abc.Iterable[builtins.float] | None = ...,
+        lengths: collections.abc.Iterable[builtins.int] | None = ...,
+        truncated: builtins.int = ...,
+        mean_return: builtins.float = ...,
+        std_return: builtins.float = ...,
+        mean_length: builtins.float = ...,
+        total_steps: builtins.int = ...,
+        elapsed_seconds: builtins.float = ...,
+    ) -> None: ...
+    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["elapsed_seconds", b"elapsed_seconds", "lengths", b"lengths", "mean_length", b"mean_length", "mean_return", b"mean_return", "returns", b"returns", "std_return", b"std_return", "total_steps", b"total_steps", "truncated", b"truncated"]
+    def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
+
+Global___EvaluatePolicyResponse: typing_extensions.TypeAlias = EvaluatePolicyResponse
+
+@typing.final
+class OpenSessionRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    CLIENT_FIELD_NUMBER: builtins.int
+    TTL_SECONDS_FIELD_NUMBER: builtins.int
+    BIND_CONNECTION_FIELD_NUMBER: builtins.int
+    client: builtins.str
+    """客户端名称，仅用于展示"""
+    ttl_seconds: builtins.int
+    """空闲超时，0表示服务端默认值；每个携带会话ID的请求都会续期"""
+    bind_connection: builtins.bool
+    """为true时会话随当前gRPC连接断开而关闭"""
+    def __init__(
+        self,
+        *,
+        client: builtins.str = ...,
+        ttl_seconds: builtins.int = ...,
+        bind_connection: builtins.bool = ...,
+    ) -> None: ...
+    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["bind_connection", b"bind_connection", "client", b"client", "ttl_seconds", b"ttl_seconds"]
+    def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
+
+Global___OpenSessionRequest: typing_extensions.TypeAlias = OpenSessionRequest
+
+@typing.final
+class OpenSessionResponse(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    SESSION_ID_FIELD_NUMBER: builtins.int
+    TTL_SECONDS_FIELD_NUMBER: builtins.int
+    session_id: builtins.str
+    ttl_seconds: builtins.int
+    def __init__(
+        self,
+        *,
+        session_id: builtins.str = ...,
+        ttl_seconds: builtins.int = ...,
+    ) -> None: ...
+    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["session_id", b"session_id", "ttl_seconds", b"ttl_seconds"]
+    def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
+
+Global___OpenSessionResponse: typing_extensions.TypeAlias = OpenSessionResponse
+
+@typing.final
+class CloseSessionRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    SESSION_ID_FIELD_NUMBER: builtins.int
+    session_id: builtins.str
+    def __init__(
+        self,
+        *,
+        session_id: builtins.str = ...,
+    ) -> None: ...
+    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["session_id", b"session_id"]
+    def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
+
+Global___CloseSessionRequest: typing_extensions.TypeAlias = CloseSessionRequest
+
+@typing.final
+class CloseSessionResponse(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    CLOSED_ENVIRONMENTS_FIELD_NUMBER: builtins.int
+    closed_environments: builtins.int
+    """随会话关闭的环境数"""
+    def __init__(
+        self,
+        *,
+        closed_environments: builtins.int = ...,
+    ) -> None: ...
+    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["closed_environments", b"closed_environments"]
+    def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
+
+Global___CloseSessionResponse: typing_extensions.TypeAlias = CloseSessionResponse
+
+@typing.final
+class EnvironmentStatus(google.protobuf.message.Message):
+    """管理接口的消息，服务端设置了管理令牌时请求元数据admin-token需携带该令牌"""
+
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    ENV_ID_FIELD_NUMBER: builtins.int
+    SCENARIO_FIELD_NUMBER: builtins.int
+    SESSION_ID_FIELD_NUMBER: builtins.int
+    CLIENT_FIELD_NUMBER: builtins.int
+    AGE_SECONDS_FIELD_NUMBER: builtins.int
+    IDLE_SECONDS_FIELD_NUMBER: builtins.int
+    STEPS_FIELD_NUMBER: builtins.int
+    EPISODES_FIELD_NUMBER: builtins.int
+    TENANT_FIELD_NUMBER: builtins.int
+    FAULT_FIELD_NUMBER: builtins.int
+    env_id: builtins.str
+    """注册表中的完整键，会话内的环境为session_id/env_id"""
+    scenario: builtins.str
+    session_id: builtins.str
+    """所属会话，不属于会话时为空"""
+    client: builtins.str
+    """创建该环境的客户端主机"""
+    age_seconds: builtins.float
+    """创建至今的秒数"""
+    idle_seconds: builtins.float
+    """最近一次reset或step至今的秒数"""
+    steps: builtins.int
+    """累计步数"""
+    episodes: builtins.int
+    """累计reset次数"""
+    tenant: builtins.str
+    """所属租户，未配置租户时为空"""
+    fault: builtins.str
+    """环境panic后的故障原因，此后只能关闭；正常时为空"""
+    def __init__(
+        self,
+        *,
+        env_id: builtins.str = ...,
+        scenario: builtins.str = ...,
+        session_id: builtins.str = ...,
+        client: builtins.str = ...,
+        age_seconds: builtins.float = ...,
+        idle_seconds: builtins.float = ...,
+        steps: builtins.int = ...,
+        episodes: builtins.int = ...,
+        tenant: builtins.str = ...,
+        fault: builtins.str = ...,
+    ) -> None: ...
+    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["age_seconds", b"age_seconds", "client", b"client", "env_id", b"env_id", "episodes", b"episodes", "fault", b"fault", "idle_seconds", b"idle_seconds", "scenario", b"scenario", "session_id", b"session_id", "steps", b"steps", "tenant", b"tenant"]
+    def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
+
+Global___EnvironmentStatus: typing_extensions.TypeAlias = EnvironmentStatus
+
+@typing.final
+class ListEnvironmentsRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    def __init__(
+        self,
+    ) -> None: ...
+
+Global___ListEnvironmentsRequest: typing_extensions.TypeAlias = ListEnvironmentsRequest
+
+@typing.final
+class ListEnvironmentsResponse(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    ENVIRONMENTS_FIELD_NUMBER: builtins.int
+    DRAINING_FIELD_NUMBER: builtins.int
+    draining: builtins.bool
+    """服务端是否已停止创建新环境"""
+    @property
+    def environments(self) -> google.protobuf.internal.containers.RepeatedCompositeFieldContainer[Global___EnvironmentStatus]: ...
+    def __init__(
+        self,
+        *,
+        environments: collections.abc.Iterable[Global___EnvironmentStatus] | None = ...,
+        draining: builtins.bool = ...,
+    ) -> None: ...
+    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["draining", b"draining", "environments", b"environments"]
+    def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
+
+Global___ListEnvironmentsResponse: typing_extensions.TypeAlias = ListEnvironmentsResponse
+
+@typing.final
+class ForceCloseEnvironmentRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    ENV_ID_FIELD_NUMBER: builtins.int
+    env_id: builtins.str
+    def __init__(
+        self,
+        *,
+        env_id: builtins.str = ...,
+    ) -> None: ...
+    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["env_id", b"env_id"]
+    def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
+
+Global___ForceCloseEnvironmentRequest: typing_extensions.TypeAlias = ForceCloseEnvironmentRequest
+
+@typing.final
+class ForceCloseEnvironmentResponse(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    SUCCESS_FIELD_NUMBER: builtins.int
+    MESSAGE_FIELD_NUMBER: builtins.int
+    ERROR_FIELD_NUMBER: builtins.int
+    success: builtins.bool
+    message: builtins.str
+    @property
+    def error(self) -> Global___Error:
+        """success为false时的错误"""
+
+    def __init__(
+        self,
+        *,
+        success: builtins.bool = ...,
+        message: builtins.str = ...,
+        error: Global___Error | None = ...,
+    ) -> None: ...
+    _HasFieldArgType: typing_extensions.TypeAlias = typing.Literal["error", b"error"]
+    def HasField(self, field_name: _HasFieldArgType) -> builtins.bool: ...
+    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["error", b"error", "message", b"message", "success", b"success"]
+    def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
+
+Global___ForceCloseEnvironmentResponse: typing_extensions.TypeAlias = ForceCloseEnvironmentResponse
+
+@typing.final
+class DumpEnvironmentStateRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    ENV_ID_FIELD_NUMBER: builtins.int
+    env_id: builtins.str
+    def __init__(
+        self,
+        *,
+        env_id: builtins.str = ...,
+    ) -> None: ...
+    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["env_id", b"env_id"]
+    def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
+
+Global___DumpEnvironmentStateRequest: typing_extensions.TypeAlias = DumpEnvironmentStateRequest
+
+@typing.final
+class DumpEnvironmentStateResponse(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    STATE_JSON_FIELD_NUMBER: builtins.int
+    state_json: builtins.str
+    def __init__(
+        self,
+        *,
+        state_json: builtins.str = ...,
+    ) -> None: ...
+    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["state_json", b"state_json"]
+    def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
+
+Global___DumpEnvironmentStateResponse: typing_extensions.TypeAlias = DumpEnvironmentStateResponse
+
+@typing.final
+class DrainRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    TIMEOUT_SECONDS_FIELD_NUMBER: builtins.int
+    FORCE_FIELD_NUMBER: builtins.int
+    timeout_seconds: builtins.float
+    """等待已有环境关闭的最长时间，0表示不等待"""
+    force: builtins.bool
+    """等待结束后强制关闭剩余环境"""
+    def __init__(
+        self,
+        *,
+        timeout_seconds: builtins.float = ...,
+        force: builtins.bool = ...,
+    ) -> None: ...
+    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["force", b"force", "timeout_seconds", b"timeout_seconds"]
+    def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
+
+Global___DrainRequest: typing_extensions.TypeAlias = DrainRequest
+
+@typing.final
+class DrainResponse(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    REMAINING_ENVIRONMENTS_FIELD_NUMBER: builtins.int
+    CLOSED_ENVIRONMENTS_FIELD_NUMBER: builtins.int
+    remaining_environments: builtins.int
+    """结束时仍在运行的环境数"""
+    closed_environments: builtins.int
+    """被强制关闭的环境数"""
+    def __init__(
+        self,
+        *,
+        remaining_environments: builtins.int = ...,
+        closed_environments: builtins.int = ...,
+    ) -> None: ...
+    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["closed_environments", b"closed_environments", "remaining_environments", b"remaining_environments"]
+    def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
+
+Global___DrainResponse: typing_extensions.TypeAlias = DrainResponse
+
+@typing.final
+class ExportEnvironmentRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    ENV_ID_FIELD_NUMBER: builtins.int
+    DETACH_FIELD_NUMBER: builtins.int
+    env_id: builtins.str
+    """注册表中的完整键"""
+    detach: builtins.bool
+    """导出后从本服务端移除并关闭这些环境（及会话）"""
+    def __init__(
+        self,
+        *,
+        env_id: builtins.str = ...,
+        detach: builtins.bool = ...,
+    ) -> None: ...
+    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["detach", b"detach", "env_id", b"env_id"]
+    def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
+
+Global___ExportEnvironmentRequest: typing_extensions.TypeAlias = ExportEnvironmentRequest
+
+@typing.final
+class ExportEnvironmentResponse(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    SNAPSHOT_FIELD_NUMBER: builtins.int
+    ENVIRONMENTS_FIELD_NUMBER: builtins.int
+    snapshot: builtins.bytes
+    """JSON编码的快照，格式与快照文件相同"""
+    environments: builtins.int
+    """快照中的环境数"""
+    def __init__(
+        self,
+        *,
+        snapshot: builtins.bytes = ...,
+        environments: builtins.int = ...,
+    ) -> None: ...
+    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["environments", b"environments", "snapshot", b"snapshot"]
+    def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
+
+Global___ExportEnvironmentResponse: typing_extensions.TypeAlias = ExportEnvironmentResponse
+
+@typing.final
+class ImportEnvironmentRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    SNAPSHOT_FIELD_NUMBER: builtins.int
+    snapshot: builtins.bytes
+    """ExportEnvironmentResponse.snapshot"""
+    def __init__(
+        self,
+        *,
+        snapshot: builtins.bytes = ...,
+    ) -> None: ...
+    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["snapshot", b"snapshot"]
+    def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
+
+Global___ImportEnvironmentRequest: typing_extensions.TypeAlias = ImportEnvironmentRequest
+
+@typing.final
+class ImportEnvironmentResponse(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    ENVIRONMENTS_FIELD_NUMBER: builtins.int
+    environments: builtins.int
+    """恢复的环境数"""
+    def __init__(
+        self,
+        *,
+        environments: builtins.int = ...,
+    ) -> None: ...
+    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["environments", b"environments"]
+    def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
+
+Global___ImportEnvironmentResponse: typing_extensions.TypeAlias = ImportEnvironmentResponse
+
+@typing.final
+class MigrateEnvironmentRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    ENV_ID_FIELD_NUMBER: builtins.int
+    WORKER_FIELD_NUMBER: builtins.int
+    env_id: builtins.str
+    """注册表中的完整键（ListEnvironments中的env_id）"""
+    worker: builtins.str
+    """目标工作节点的地址"""
+    def __init__(
+        self,
+        *,
+        env_id: builtins.str = ...,
+        worker: builtins.str = ...,
+    ) -> None: ...
+    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["env_id", b"env_id", "worker", b"worker"]
+    def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
+
+Global___MigrateEnvironmentRequest: typing_extensions.TypeAlias = MigrateEnvironmentRequest
+
+@typing.final
+class MigrateEnvironmentResponse(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    MIGRATED_ENVIRONMENTS_FIELD_NUMBER: builtins.int
+    migrated_environments: builtins.int
+    """迁移的环境数，环境属于会话时包含会话的全部环境"""
+    def __init__(
+        self,
+        *,
+        migrated_environments: builtins.int = ...,
+    ) -> None: ...
+    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["migrated_environments", b"migrated_environments"]
+    def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
+
+Global___MigrateEnvironmentResponse: typing_extensions.TypeAlias = MigrateEnvironmentResponse
+
+@typing.final
+class DrainWorkerRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    WORKER_FIELD_NUMBER: builtins.int
+    worker: builtins.str
+    """工作节点的地址"""
+    def __init__(
+        self,
+        *,
+        worker: builtins.str = ...,
+    ) -> None: ...
+    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["worker", b"worker"]
+    def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
+
+Global___DrainWorkerRequest: typing_extensions.TypeAlias = DrainWorkerRequest
+
+@typing.final
+class DrainWorkerResponse(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    MIGRATED_ENVIRONMENTS_FIELD_NUMBER: builtins.int
+    REMAINING_ENVIRONMENTS_FIELD_NUMBER: builtins.int
+    migrated_environments: builtins.int
+    """迁移到其余节点的环境数"""
+    remaining_environments: builtins.int
+    """无法迁移（未实现检查点）而留在该节点的环境数"""
+    def __init__(
+        self,
+        *,
+        migrated_environments: builtins.int = ...,
+        remaining_environments: builtins.int = ...,
+    ) -> None: ...
+    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["migrated_environments", b"migrated_environments", "remaining_environments", b"remaining_environments"]
+    def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
+
+Global___DrainWorkerResponse: typing_extensions.TypeAlias = DrainWorkerResponse
+
+@typing.final
+class RegisterScenarioRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    NAME_FIELD_NUMBER: builtins.int
+    DESCRIPTION_FIELD_NUMBER: builtins.int
+    WASM_FIELD_NUMBER: builtins.int
+    name: builtins.str
+    """场景名，不能与已有的场景（含此前上传的场景）或预设重名"""
+    description: builtins.str
+    wasm: builtins.bytes
+    """WebAssembly二进制模块"""
+    def __init__(
+        self,
+        *,
+        name: builtins.str = ...,
+        description: builtins.str = ...,
+        wasm: builtins.bytes = ...,
+    ) -> None: ...
+    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["description", b"description", "name", b"name", "wasm", b"wasm"]
+    def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
+
+Global___RegisterScenarioRequest: typing_extensions.TypeAlias = RegisterScenarioRequest
+
+@typing.final
+class RegisterScenarioResponse(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    def __init__(
+        self,
+    ) -> None: ...
+
+Global___RegisterScenarioResponse: typing_extensions.TypeAlias = RegisterScenarioResponse
+
+@typing.final
+class GetCurriculumRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    ENV_ID_FIELD_NUMBER: builtins.int
+    env_id: builtins.str
+    def __init__(
+        self,
+        *,
+        env_id: builtins.str = ...,
+    ) -> None: ...
+    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["env_id", b"env_id"]
+    def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
+
+Global___GetCurriculumRequest: typing_extensions.TypeAlias = GetCurriculumRequest
+
+@typing.final
+class SetCurriculumStageRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    ENV_ID_FIELD_NUMBER: builtins.int
+    STAGE_FIELD_NUMBER: builtins.int
+    FROZEN_FIELD_NUMBER: builtins.int
+    env_id: builtins.str
+    stage: builtins.int
+    """目标阶段，从0开始"""
+    frozen: builtins.bool
+    """为true时停止按回合数或成功率自动推进，为false时恢复"""
+    def __init__(
+        self,
+        *,
+        env_id: builtins.str = ...,
+        stage: builtins.int = ...,
+        frozen: builtins.bool = ...,
+    ) -> None: ...
+    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["env_id", b"env_id", "frozen", b"frozen", "stage", b"stage"]
+    def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
+
+Global___SetCurriculumStageRequest: typing_extensions.TypeAlias = SetCurriculumStageRequest
+
+@typing.final
+class CurriculumProgress(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    @typing.final
+    class ParametersEntry(google.protobuf.message.Message):
+        DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+        KEY_FIELD_NUMBER: builtins.int
+        VALUE_FIELD_NUMBER: builtins.int
+        key: builtins.str
+        value: builtins.float
+        def __init__(
+            self,
+            *,
+            key: builtins.str = ...,
+            value: builtins.float = ...,
+        ) -> None: ...
+        _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["key", b"key", "value", b"value"]
+        def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
+
+    STAGE_FIELD_NUMBER: builtins.int
+    STAGES_FIELD_NUMBER: builtins.int
+    EPISODES_FIELD_NUMBER: builtins.int
+    STAGE_EPISODES_FIELD_NUMBER: builtins.int
+    SUCCESS_RATE_FIELD_NUMBER: builtins.int
+    WINDOW_FIELD_NUMBER: builtins.int
+    FROZEN_FIELD_NUMBER: builtins.int
+    PARAMETERS_FIELD_NUMBER: builtins.int
+    stage: builtins.int
+    """当前阶段，从0开始"""
+    stages: builtins.int
+    """阶段总数"""
+    episodes: builtins.int
+    """已完成的回合总数"""
+    stage_episodes: builtins.int
+    """当前阶段已完成的回合数"""
+    success_rate: builtins.float
+    """当前阶段最近回合的成功率"""
+    window: builtins.int
+    """成功率统计的回合数"""
+    frozen: builtins.bool
+    """是否停止自动推进"""
+    @property
+    def parameters(self) -> google.protobuf.internal.containers.ScalarMap[builtins.str, builtins.float]:
+        """当前回合生效的参数"""
+
+    def __init__(
+        self,
+        *,
+        stage: builtins.int = ...,
+        stages: builtins.int = ...,
+        episodes: builtins.int = ...,
+        stage_episodes: builtins.int = ...,
+        success_rate: builtins.float = ...,
+        window: builtins.int = ...,
+        frozen: builtins.bool = ...,
+        parameters: collections.abc.Mapping[builtins.str, builtins.float] | None = ...,
+    ) -> None: ...
+    _ClearFieldArgType: typing_extensions.TypeAlias = typing.Literal["episodes", b"episodes", "frozen", b"frozen", "parameters", b"parameters", "stage", b"stage", "stage_episodes", b"stage_episodes", "stages", b"stages", "success_rate", b"success_rate", "window", b"window"]
+    def ClearField(self, field_name: _ClearFieldArgType) -> None: ...
+
+Global___CurriculumProgress: typing_extensions.TypeAlias = CurriculumProgress
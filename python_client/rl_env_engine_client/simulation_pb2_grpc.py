@@ -65,6 +65,86 @@ class SimulationServiceStub(object):
                 request_serializer=simulation__pb2.GetSpacesRequest.SerializeToString,
                 response_deserializer=simulation__pb2.GetSpacesResponse.FromString,
                 _registered_method=True)
+        self.GetMetadata = channel.unary_unary(
+                '/simulation.SimulationService/GetMetadata',
+                request_serializer=simulation__pb2.GetMetadataRequest.SerializeToString,
+                response_deserializer=simulation__pb2.GetMetadataResponse.FromString,
+                _registered_method=True)
+        self.DebugEnvironment = channel.unary_unary(
+                '/simulation.SimulationService/DebugEnvironment',
+                request_serializer=simulation__pb2.DebugEnvironmentRequest.SerializeToString,
+                response_deserializer=simulation__pb2.DebugEnvironmentResponse.FromString,
+                _registered_method=True)
+        self.EvaluatePolicy = channel.unary_unary(
+                '/simulation.SimulationService/EvaluatePolicy',
+                request_serializer=simulation__pb2.EvaluatePolicyRequest.SerializeToString,
+                response_deserializer=simulation__pb2.EvaluatePolicyResponse.FromString,
+                _registered_method=True)
+        self.OpenSession = channel.unary_unary(
+                '/simulation.SimulationService/OpenSession',
+                request_serializer=simulation__pb2.OpenSessionRequest.SerializeToString,
+                response_deserializer=simulation__pb2.OpenSessionResponse.FromString,
+                _registered_method=True)
+        self.CloseSession = channel.unary_unary(
+                '/simulation.SimulationService/CloseSession',
+                request_serializer=simulation__pb2.CloseSessionRequest.SerializeToString,
+                response_deserializer=simulation__pb2.CloseSessionResponse.FromString,
+                _registered_method=True)
+        self.ListEnvironments = channel.unary_unary(
+                '/simulation.SimulationService/ListEnvironments',
+                request_serializer=simulation__pb2.ListEnvironmentsRequest.SerializeToString,
+                response_deserializer=simulation__pb2.ListEnvironmentsResponse.FromString,
+                _registered_method=True)
+        self.ForceCloseEnvironment = channel.unary_unary(
+                '/simulation.SimulationService/ForceCloseEnvironment',
+                request_serializer=simulation__pb2.ForceCloseEnvironmentRequest.SerializeToString,
+                response_deserializer=simulation__pb2.ForceCloseEnvironmentResponse.FromString,
+                _registered_method=True)
+        self.DumpEnvironmentState = channel.unary_unary(
+                '/simulation.SimulationService/DumpEnvironmentState',
+                request_serializer=simulation__pb2.DumpEnvironmentStateRequest.SerializeToString,
+                response_deserializer=simulation__pb2.DumpEnvironmentStateResponse.FromString,
+                _registered_method=True)
+        self.Drain = channel.unary_unary(
+                '/simulation.SimulationService/Drain',
+                request_serializer=simulation__pb2.DrainRequest.SerializeToString,
+                response_deserializer=simulation__pb2.DrainResponse.FromString,
+                _registered_method=True)
+        self.ExportEnvironment = channel.unary_unary(
+                '/simulation.SimulationService/ExportEnvironment',
+                request_serializer=simulation__pb2.ExportEnvironmentRequest.SerializeToString,
+                response_deserializer=simulation__pb2.ExportEnvironmentResponse.FromString,
+                _registered_method=True)
+        self.ImportEnvironment = channel.unary_unary(
+                '/simulation.SimulationService/ImportEnvironment',
+                request_serializer=simulation__pb2.ImportEnvironmentRequest.SerializeToString,
+                response_deserializer=simulation__pb2.ImportEnvironmentResponse.FromString,
+                _registered_method=True)
+        self.MigrateEnvironment = channel.unary_unary(
+                '/simulation.SimulationService/MigrateEnvironment',
+                request_serializer=simulation__pb2.MigrateEnvironmentRequest.SerializeToString,
+                response_deserializer=simulation__pb2.MigrateEnvironmentResponse.FromString,
+                _registered_method=True)
+        self.DrainWorker = channel.unary_unary(
+                '/simulation.SimulationService/DrainWorker',
+                request_serializer=simulation__pb2.DrainWorkerRequest.SerializeToString,
+                response_deserializer=simulation__pb2.DrainWorkerResponse.FromString,
+                _registered_method=True)
+        self.RegisterScenario = channel.unary_unary(
+                '/simulation.SimulationService/RegisterScenario',
+                request_serializer=simulation__pb2.RegisterScenarioRequest.SerializeToString,
+                response_deserializer=simulation__pb2.RegisterScenarioResponse.FromString,
+                _registered_method=True)
+        self.GetCurriculum = channel.unary_unary(
+                '/simulation.SimulationService/GetCurriculum',
+                request_serializer=simulation__pb2.GetCurriculumRequest.SerializeToString,
+                response_deserializer=simulation__pb2.CurriculumProgress.FromString,
+                _registered_method=True)
+        self.SetCurriculumStage = channel.unary_unary(
+                '/simulation.SimulationService/SetCurriculumStage',
+                request_serializer=simulation__pb2.SetCurriculumStageRequest.SerializeToString,
+                response_deserializer=simulation__pb2.CurriculumProgress.FromString,
+                _registered_method=True)
         self.StreamStep = channel.stream_stream(
                 '/simulation.SimulationService/StreamStep',
                 request_serializer=simulation__pb2.StepEnvironmentRequest.SerializeToString,
@@ -118,6 +198,119 @@ class SimulationServiceServicer(object):
         context.set_details('Method not implemented!')
         raise NotImplementedError('Method not implemented!')
 
+    def GetMetadata(self, request, context):
+        """GetMetadata 获取环境元数据（奖励范围、最大步数、渲染模式等）
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def DebugEnvironment(self, request, context):
+        """DebugEnvironment 以JSON导出调用方自己的环境的内部状态（检查点、StateDumper的结果与包装层），供场景作者排查异常的奖励或结束标志
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def EvaluatePolicy(self, request, context):
+        """EvaluatePolicy 在服务端以ONNX策略运行若干回合，推理在本地完成，无需逐步往返动作
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def OpenSession(self, request, context):
+        """OpenSession 打开会话：请求元数据session-id携带会话ID时，env_id只在该会话内可见，
+        会话过期、关闭或（bind_connection时）连接断开时其环境全部关闭
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def CloseSession(self, request, context):
+        """CloseSession 关闭会话及其所有环境
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def ListEnvironments(self, request, context):
+        """ListEnvironments 管理接口：列出全部环境（含各会话的环境）及其所属会话、客户端、存活时间与步数
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def ForceCloseEnvironment(self, request, context):
+        """ForceCloseEnvironment 管理接口：强制关闭环境，env_id为注册表中的完整键
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def DumpEnvironmentState(self, request, context):
+        """DumpEnvironmentState 管理接口：以JSON导出环境的内部状态
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def Drain(self, request, context):
+        """Drain 管理接口：停止创建新环境，等待已有环境关闭，超时后可强制关闭剩余环境
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def ExportEnvironment(self, request, context):
+        """ExportEnvironment 管理接口：导出环境（属于会话时连同会话的全部环境）的检查点，detach时随后从本服务端移除
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def ImportEnvironment(self, request, context):
+        """ImportEnvironment 管理接口：按ExportEnvironment的结果重新创建环境并恢复状态
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def MigrateEnvironment(self, request, context):
+        """MigrateEnvironment 路由器的管理接口：把环境迁移到另一个工作节点，持有env_id的客户端无需改动
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def DrainWorker(self, request, context):
+        """DrainWorker 路由器的管理接口：不再向工作节点分配新环境，并把其上的环境迁移到其余节点
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def RegisterScenario(self, request, context):
+        """RegisterScenario 上传实现环境ABI的WASM模块，在服务端沙箱中运行并注册为场景（需服务端开启）
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def GetCurriculum(self, request, context):
+        """GetCurriculum 获取以curriculum配置创建的环境的课程进度
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def SetCurriculumStage(self, request, context):
+        """SetCurriculumStage 切换环境的课程阶段（下次Reset时生效），frozen时停止自动推进
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
     def StreamStep(self, request_iterator, context):
         """StreamStep 流式执行仿真步骤 (可选，用于实时仿真)
         """
@@ -158,6 +351,86 @@ def add_SimulationServiceServicer_to_server(servicer, server):
                     request_deserializer=simulation__pb2.GetSpacesRequest.FromString,
                     response_serializer=simulation__pb2.GetSpacesResponse.SerializeToString,
             ),
+            'GetMetadata': grpc.unary_unary_rpc_method_handler(
+                    servicer.GetMetadata,
+                    request_deserializer=simulation__pb2.GetMetadataRequest.FromString,
+                    response_serializer=simulation__pb2.GetMetadataResponse.SerializeToString,
+            ),
+            'DebugEnvironment': grpc.unary_unary_rpc_method_handler(
+                    servicer.DebugEnvironment,
+                    request_deserializer=simulation__pb2.DebugEnvironmentRequest.FromString,
+                    response_serializer=simulation__pb2.DebugEnvironmentResponse.SerializeToString,
+            ),
+            'EvaluatePolicy': grpc.unary_unary_rpc_method_handler(
+                    servicer.EvaluatePolicy,
+                    request_deserializer=simulation__pb2.EvaluatePolicyRequest.FromString,
+                    response_serializer=simulation__pb2.EvaluatePolicyResponse.SerializeToString,
+            ),
+            'OpenSession': grpc.unary_unary_rpc_method_handler(
+                    servicer.OpenSession,
+                    request_deserializer=simulation__pb2.OpenSessionRequest.FromString,
+                    response_serializer=simulation__pb2.OpenSessionResponse.SerializeToString,
+            ),
+            'CloseSession': grpc.unary_unary_rpc_method_handler(
+                    servicer.CloseSession,
+                    request_deserializer=simulation__pb2.CloseSessionRequest.FromString,
+                    response_serializer=simulation__pb2.CloseSessionResponse.SerializeToString,
+            ),
+            'ListEnvironments': grpc.unary_unary_rpc_method_handler(
+                    servicer.ListEnvironments,
+                    request_deserializer=simulation__pb2.ListEnvironmentsRequest.FromString,
+                    response_serializer=simulation__pb2.ListEnvironmentsResponse.SerializeToString,
+            ),
+            'ForceCloseEnvironment': grpc.unary_unary_rpc_method_handler(
+                    servicer.ForceCloseEnvironment,
+                    request_deserializer=simulation__pb2.ForceCloseEnvironmentRequest.FromString,
+                    response_serializer=simulation__pb2.ForceCloseEnvironmentResponse.SerializeToString,
+            ),
+            'DumpEnvironmentState': grpc.unary_unary_rpc_method_handler(
+                    servicer.DumpEnvironmentState,
+                    request_deserializer=simulation__pb2.DumpEnvironmentStateRequest.FromString,
+                    response_serializer=simulation__pb2.DumpEnvironmentStateResponse.SerializeToString,
+            ),
+            'Drain': grpc.unary_unary_rpc_method_handler(
+                    servicer.Drain,
+                    request_deserializer=simulation__pb2.DrainRequest.FromString,
+                    response_serializer=simulation__pb2.DrainResponse.SerializeToString,
+            ),
+            'ExportEnvironment': grpc.unary_unary_rpc_method_handler(
+                    servicer.ExportEnvironment,
+                    request_deserializer=simulation__pb2.ExportEnvironmentRequest.FromString,
+                    response_serializer=simulation__pb2.ExportEnvironmentResponse.SerializeToString,
+            ),
+            'ImportEnvironment': grpc.unary_unary_rpc_method_handler(
+                    servicer.ImportEnvironment,
+                    request_deserializer=simulation__pb2.ImportEnvironmentRequest.FromString,
+                    response_serializer=simulation__pb2.ImportEnvironmentResponse.SerializeToString,
+            ),
+            'MigrateEnvironment': grpc.unary_unary_rpc_method_handler(
+                    servicer.MigrateEnvironment,
+                    request_deserializer=simulation__pb2.MigrateEnvironmentRequest.FromString,
+                    response_serializer=simulation__pb2.MigrateEnvironmentResponse.SerializeToString,
+            ),
+            'DrainWorker': grpc.unary_unary_rpc_method_handler(
+                    servicer.DrainWorker,
+                    request_deserializer=simulation__pb2.DrainWorkerRequest.FromString,
+                    response_serializer=simulation__pb2.DrainWorkerResponse.SerializeToString,
+            ),
+            'RegisterScenario': grpc.unary_unary_rpc_method_handler(
+                    servicer.RegisterScenario,
+                    request_deserializer=simulation__pb2.RegisterScenarioRequest.FromString,
+                    response_serializer=simulation__pb2.RegisterScenarioResponse.SerializeToString,
+            ),
+            'GetCurriculum': grpc.unary_unary_rpc_method_handler(
+                    servicer.GetCurriculum,
+                    request_deserializer=simulation__pb2.GetCurriculumRequest.FromString,
+                    response_serializer=simulation__pb2.CurriculumProgress.SerializeToString,
+            ),
+            'SetCurriculumStage': grpc.unary_unary_rpc_method_handler(
+                    servicer.SetCurriculumStage,
+                    request_deserializer=simulation__pb2.SetCurriculumStageRequest.FromString,
+                    response_serializer=simulation__pb2.CurriculumProgress.SerializeToString,
+            ),
             'StreamStep': grpc.stream_stream_rpc_method_handler(
                     servicer.StreamStep,
                     request_deserializer=simulation__pb2.StepEnvironmentRequest.FromString,
@@ -337,6 +610,438 @@ class SimulationService(object):
             metadata,
             _registered_method=True)
 
+    @staticmethod
+    def GetMetadata(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/simulation.SimulationService/GetMetadata',
+            simulation__pb2.GetMetadataRequest.SerializeToString,
+            simulation__pb2.GetMetadataResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def DebugEnvironment(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/simulation.SimulationService/DebugEnvironment',
+            simulation__pb2.DebugEnvironmentRequest.SerializeToString,
+            simulation__pb2.DebugEnvironmentResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def EvaluatePolicy(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/simulation.SimulationService/EvaluatePolicy',
+            simulation__pb2.EvaluatePolicyRequest.SerializeToString,
+            simulation__pb2.EvaluatePolicyResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def OpenSession(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/simulation.SimulationService/OpenSession',
+            simulation__pb2.OpenSessionRequest.SerializeToString,
+            simulation__pb2.OpenSessionResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def CloseSession(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/simulation.SimulationService/CloseSession',
+            simulation__pb2.CloseSessionRequest.SerializeToString,
+            simulation__pb2.CloseSessionResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def ListEnvironments(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/simulation.SimulationService/ListEnvironments',
+            simulation__pb2.ListEnvironmentsRequest.SerializeToString,
+            simulation__pb2.ListEnvironmentsResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def ForceCloseEnvironment(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/simulation.SimulationService/ForceCloseEnvironment',
+            simulation__pb2.ForceCloseEnvironmentRequest.SerializeToString,
+            simulation__pb2.ForceCloseEnvironmentResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def DumpEnvironmentState(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/simulation.SimulationService/DumpEnvironmentState',
+            simulation__pb2.DumpEnvironmentStateRequest.SerializeToString,
+            simulation__pb2.DumpEnvironmentStateResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def Drain(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/simulation.SimulationService/Drain',
+            simulation__pb2.DrainRequest.SerializeToString,
+            simulation__pb2.DrainResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def ExportEnvironment(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/simulation.SimulationService/ExportEnvironment',
+            simulation__pb2.ExportEnvironmentRequest.SerializeToString,
+            simulation__pb2.ExportEnvironmentResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def ImportEnvironment(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/simulation.SimulationService/ImportEnvironment',
+            simulation__pb2.ImportEnvironmentRequest.SerializeToString,
+            simulation__pb2.ImportEnvironmentResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def MigrateEnvironment(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/simulation.SimulationService/MigrateEnvironment',
+            simulation__pb2.MigrateEnvironmentRequest.SerializeToString,
+            simulation__pb2.MigrateEnvironmentResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def DrainWorker(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/simulation.SimulationService/DrainWorker',
+            simulation__pb2.DrainWorkerRequest.SerializeToString,
+            simulation__pb2.DrainWorkerResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def RegisterScenario(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/simulation.SimulationService/RegisterScenario',
+            simulation__pb2.RegisterScenarioRequest.SerializeToString,
+            simulation__pb2.RegisterScenarioResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def GetCurriculum(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/simulation.SimulationService/GetCurriculum',
+            simulation__pb2.GetCurriculumRequest.SerializeToString,
+            simulation__pb2.CurriculumProgress.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def SetCurriculumStage(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/simulation.SimulationService/SetCurriculumStage',
+            simulation__pb2.SetCurriculumStageRequest.SerializeToString,
+            simulation__pb2.CurriculumProgress.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
     @staticmethod
     def StreamStep(request_iterator,
             target,
@@ -0,0 +1,130 @@
+#!/usr/bin/env python3
+"""
+Stable-Baselines3 向量化远程环境
+RemoteVecEnv 在服务端创建num_envs个环境，用线程池并发步进，可直接传给SB3算法替代SubprocVecEnv:
+    env = RemoteVecEnv("cartpole", num_envs=8, transport="grpc")
+    model = PPO("MlpPolicy", env)
+
+需要额外安装 stable-baselines3：pip install -e "python_client[rl]"
+"""
+
+from concurrent.futures import ThreadPoolExecutor
+from typing import Any, Dict, List, Optional, Sequence, Type
+
+import gymnasium as gym
+import numpy as np
+from stable_baselines3.common.env_util import is_wrapped
+from stable_baselines3.common.vec_env import VecEnv
+
+from .remote import RemoteEnv
+
+
+class RemoteVecEnv(VecEnv):
+    """
+    SB3兼容的向量化远程环境
+
+    与SB3的约定一致：回合结束（terminated或truncated）的环境立即重置，
+    返回的观察为新回合的初始观察，结束时的观察保存在info["terminal_observation"]，
+    因截断结束时info["TimeLimit.truncated"]为True。gRPC与HTTP传输默认由服务端在同一次
+    step请求中重置（auto_reset），每回合省去一次reset往返
+    """
+
+    def __init__(
+        self,
+        scenario: str,
+        num_envs: int,
+        transport: str = "grpc",
+        host: str = "127.0.0.1",
+        port: Optional[int] = None,
+        config: Optional[Dict[str, Any]] = None,
+        seed: Optional[int] = None,
+        max_workers: Optional[int] = None,
+        server_reset: bool = True,
+        **kwargs: Any,
+    ):
+        """
+        Args:
+            scenario: 服务器端的场景名称
+            num_envs: 环境个数
+            transport: "grpc"、"http" 或 "shm"
+            host: 服务器地址
+            port: 服务器端口，默认gRPC为9090、HTTP为8080
+            config: 传递给服务器的配置参数
+            seed: 场景种子，第i个环境使用seed+i
+            max_workers: 并发请求的线程数，默认等于num_envs
+            server_reset: 是否由服务端自动重置结束的环境（创建配置的auto_reset），shm传输始终由客户端重置
+            kwargs: 传给GrpcEnv/HttpEnv的其他参数
+        """
+        self.envs: List[RemoteEnv] = []
+        for i in range(num_envs):
+            env_config = dict(config or {})
+            if seed is not None:
+                env_config["seed"] = seed + i
+            if server_reset and transport != "shm":
+                env_config.setdefault("auto_reset", True)
+            self.envs.append(RemoteEnv(scenario, transport=transport, host=host, port=port, config=env_config, **kwargs))
+        self._executor = ThreadPoolExecutor(max_workers=max_workers or num_envs)
+        self._futures: list = []
+
+        env = self.envs[0]
+        super().__init__(num_envs, env.observation_space, env.action_space)
+
+    def reset(self):
+        """并发重置所有环境，返回堆叠后的观察"""
+        seeds, options = self._seeds, self._options
+        results = list(
+            self._executor.map(lambda i: self.envs[i].reset(seed=seeds[i], options=options[i]), range(self.num_envs))
+        )
+        self._reset_seeds()
+        self._reset_options()
+        self.reset_infos = [info for _, info in results]
+        return np.stack([obs for obs, _ in results])
+
+    def step_async(self, actions: np.ndarray) -> None:
+        """提交每个环境的动作后立即返回"""
+        self._futures = [self._executor.submit(env.step, action) for env, action in zip(self.envs, actions)]
+
+    def step_wait(self):
+        """等待所有环境完成，结束的环境自动重置"""
+        observations, rewards, dones, infos = [], [], [], []
+        for i, future in enumerate(self._futures):
+            obs, reward, terminated, truncated, info = future.result()
+            done = terminated or truncated
+            if done:
+                info["TimeLimit.truncated"] = truncated and not terminated
+                if "terminal_observation" in info:
+                    # 服务端已重置，obs为新回合的初始观察
+                    self.reset_infos[i] = {}
+                else:
+                    info["terminal_observation"] = obs
+                    obs, self.reset_infos[i] = self.envs[i].reset()
+            observations.append(obs)
+            rewards.append(reward)
+            dones.append(done)
+            infos.append(info)
+        self._futures = []
+        return np.stack(observations), np.array(rewards, dtype=np.float32), np.array(dones), infos
+
+    def close(self) -> None:
+        """关闭所有远程环境与线程池"""
+        for future in self._futures:
+            future.result()
+        for env in self.envs:
+            env.close()
+        self._executor.shutdown()
+
+    def get_attr(self, attr_name: str, indices=None) -> List[Any]:
+        return [getattr(self.envs[i], attr_name) for i in self._get_indices(indices)]
+
+    def set_attr(self, attr_name: str, value: Any, indices=None) -> None:
+        for i in self._get_indices(indices):
+            setattr(self.envs[i], attr_name, value)
+
+    def env_method(self, method_name: str, *method_args, indices=None, **method_kwargs) -> List[Any]:
+        return [getattr(self.envs[i], method_name)(*method_args, **method_kwargs) for i in self._get_indices(indices)]
+
+    def env_is_wrapped(self, wrapper_class: Type[gym.Wrapper], indices=None) -> List[bool]:
+        return [is_wrapped(self.envs[i], wrapper_class) for i in self._get_indices(indices)]
+
+    def get_images(self) -> Sequence[Optional[np.ndarray]]:
+        return [None for _ in range(self.num_envs)]
@@ -0,0 +1,84 @@
+package rl_env_engine
+
+import (
+	"fmt"
+
+	"github.com/jelech/rl_env_engine/server"
+)
+
+// RouterConfig represents the configuration of a gRPC routing front-end, which serves the
+// same API as the gRPC server but forwards every environment to one of a pool of workers
+type RouterConfig struct {
+	Port int
+	// Host is the TCP host, or a unix:///path/to.sock address to serve on a Unix socket
+	// instead (Port is then ignored)
+	Host string
+	// Workers are the host:port (or unix://) addresses of the gRPC servers hosting the
+	// environments; tenants, limits and the admin token are configured on the workers
+	Workers []string
+	// Logger, when set, receives the router's log output instead of the default logger
+	Logger Logger
+}
+
+// NewRouterConfig creates a routing front-end configuration forwarding to workers
+func NewRouterConfig(port int, workers []string) *RouterConfig {
+	return &RouterConfig{
+		Port:    port,
+		Host:    "localhost",
+		Workers: workers,
+	}
+}
+
+// StartRouter starts the routing front-end. Environments are placed on workers by
+// consistent hashing of env_id, and the environments of a session on the worker that
+// opened it, so adding a worker only moves the environment ids adjacent to it on the ring
+func StartRouter(config *RouterConfig) error {
+	router, err := server.NewRouter(config.Workers)
+	if err != nil {
+		return err
+	}
+	defer router.Close()
+	router.SetLogger(config.Logger)
+
+	lis, err := server.Listen(config.Address())
+	if err != nil {
+		return err
+	}
+	loggerOr(config.Logger).Info("Starting Simulation gRPC router", "addr", config.Address())
+	return router.Serve(lis)
+}
+
+// StartRouterAsync starts the routing front-end in a separate goroutine
+// Returns a channel that will receive any error from the router
+func StartRouterAsync(config *RouterConfig) <-chan error {
+	errCh := make(chan error, 1)
+
+	go func() {
+		defer close(errCh)
+		if err := StartRouter(config); err != nil {
+			errCh <- err
+		}
+	}()
+
+	return errCh
+}
+
+// WithLogger sets the logger receiving the router's log output
+func (c *RouterConfig) WithLogger(logger Logger) *RouterConfig {
+	c.Logger = logger
+	return c
+}
+
+// WithHost sets the host of the router, or a unix:// address to serve on a Unix socket
+func (c *RouterConfig) WithHost(host string) *RouterConfig {
+	c.Host = host
+	return c
+}
+
+// Address returns the full address string, or the unix:// address of a Unix socket
+func (c *RouterConfig) Address() string {
+	if server.IsUnixAddress(c.Host) {
+		return c.Host
+	}
+	return fmt.Sprintf("%s:%d", c.Host, c.Port)
+}
@@ -0,0 +1,1094 @@
+package rl_env_engine
+
+import (
+	"github.com/jelech/rl_env_engine/core"
+	"github.com/jelech/rl_env_engine/core/config"
+	"github.com/jelech/rl_env_engine/scenarios/cartpole"
+	"github.com/jelech/rl_env_engine/scenarios/game2048"
+	"github.com/jelech/rl_env_engine/scenarios/inventory"
+	"github.com/jelech/rl_env_engine/scenarios/lqr"
+	"github.com/jelech/rl_env_engine/scenarios/lunarlander"
+	"github.com/jelech/rl_env_engine/scenarios/maze"
+	"github.com/jelech/rl_env_engine/scenarios/mountaincar"
+	"github.com/jelech/rl_env_engine/scenarios/pendulum"
+	"github.com/jelech/rl_env_engine/scenarios/predatorprey"
+	"github.com/jelech/rl_env_engine/scenarios/queueing"
+	"github.com/jelech/rl_env_engine/scenarios/replay"
+	"github.com/jelech/rl_env_engine/scenarios/scripted"
+	"github.com/jelech/rl_env_engine/scenarios/snake"
+	"github.com/jelech/rl_env_engine/scenarios/tictactoe"
+	"github.com/jelech/rl_env_engine/scenarios/trading"
+	"github.com/jelech/rl_env_engine/scenarios/traffic"
+	"github.com/jelech/rl_env_engine/scenarios/walker"
+)
+
+// newScenarioSimulation converts a typed scenario configuration into a core.Config,
+// validates it and creates the environment directly from the scenario
+func newScenarioSimulation(scenario core.Scenario, typed interface{}) (Simulation, error) {
+	values, err := config.Values(typed)
+	if err != nil {
+		return nil, err
+	}
+	cfg := core.NewBaseConfig(values)
+	if err := scenario.ValidateConfig(cfg); err != nil {
+		return nil, err
+	}
+	return scenario.CreateEnvironment(cfg)
+}
+
+// CartPoleConfig represents cartpole simulation configuration
+type CartPoleConfig = cartpole.Config
+
+// CartPoleOption is a function type for configuring cartpole simulation
+type CartPoleOption func(*CartPoleConfig)
+
+// NewCartPoleSimulation creates a cartpole simulation; unset parameters match Gym's CartPole-v1
+func NewCartPoleSimulation(opts ...CartPoleOption) (Simulation, error) {
+	cfg := cartpole.DefaultConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return newScenarioSimulation(cartpole.NewCartPoleScenario(), cfg)
+}
+
+// WithCartPoleMaxSteps sets the episode length limit
+func WithCartPoleMaxSteps(steps int) CartPoleOption {
+	return func(c *CartPoleConfig) {
+		c.MaxSteps = steps
+	}
+}
+
+// WithCartPoleGravity sets the gravitational acceleration
+func WithCartPoleGravity(gravity float64) CartPoleOption {
+	return func(c *CartPoleConfig) {
+		c.Gravity = gravity
+	}
+}
+
+// WithCartPoleMasses sets the cart and pole masses
+func WithCartPoleMasses(cart, pole float64) CartPoleOption {
+	return func(c *CartPoleConfig) {
+		c.MassCart = cart
+		c.MassPole = pole
+	}
+}
+
+// WithCartPoleHalfLength sets half of the pole length
+func WithCartPoleHalfLength(length float64) CartPoleOption {
+	return func(c *CartPoleConfig) {
+		c.Length = length
+	}
+}
+
+// WithCartPoleForceMag sets the magnitude of the force applied by each push
+func WithCartPoleForceMag(force float64) CartPoleOption {
+	return func(c *CartPoleConfig) {
+		c.ForceMag = force
+	}
+}
+
+// WithCartPoleTau sets the integration time step in seconds
+func WithCartPoleTau(tau float64) CartPoleOption {
+	return func(c *CartPoleConfig) {
+		c.Tau = tau
+	}
+}
+
+// WithCartPoleThresholds sets the pole angle (in degrees) and cart position limits that end an episode
+func WithCartPoleThresholds(thetaDegrees, x float64) CartPoleOption {
+	return func(c *CartPoleConfig) {
+		c.ThetaThresholdDegrees = thetaDegrees
+		c.XThreshold = x
+	}
+}
+
+// PendulumConfig represents pendulum simulation configuration
+type PendulumConfig = pendulum.Config
+
+// PendulumOption is a function type for configuring pendulum simulation
+type PendulumOption func(*PendulumConfig)
+
+// NewPendulumSimulation creates a pendulum simulation; unset parameters match Gym's Pendulum-v1
+func NewPendulumSimulation(opts ...PendulumOption) (Simulation, error) {
+	cfg := pendulum.DefaultConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return newScenarioSimulation(pendulum.NewPendulumScenario(), cfg)
+}
+
+// WithPendulumMaxSteps sets the episode length limit
+func WithPendulumMaxSteps(steps int) PendulumOption {
+	return func(c *PendulumConfig) {
+		c.MaxSteps = steps
+	}
+}
+
+// WithPendulumGravity sets the gravitational acceleration
+func WithPendulumGravity(gravity float64) PendulumOption {
+	return func(c *PendulumConfig) {
+		c.Gravity = gravity
+	}
+}
+
+// WithPendulumMassLength sets the pendulum mass and length
+func WithPendulumMassLength(mass, length float64) PendulumOption {
+	return func(c *PendulumConfig) {
+		c.Mass = mass
+		c.Length = length
+	}
+}
+
+// WithPendulumMaxTorque sets the torque limit; actions are clipped to [-torque, torque]
+func WithPendulumMaxTorque(torque float64) PendulumOption {
+	return func(c *PendulumConfig) {
+		c.MaxTorque = torque
+	}
+}
+
+// WithPendulumMaxSpeed sets the angular velocity limit
+func WithPendulumMaxSpeed(speed float64) PendulumOption {
+	return func(c *PendulumConfig) {
+		c.MaxSpeed = speed
+	}
+}
+
+// WithPendulumDt sets the integration time step in seconds
+func WithPendulumDt(dt float64) PendulumOption {
+	return func(c *PendulumConfig) {
+		c.Dt = dt
+	}
+}
+
+// MountainCarConfig represents mountain car simulation configuration
+type MountainCarConfig = mountaincar.Config
+
+// MountainCarOption is a function type for configuring mountain car simulation
+type MountainCarOption func(*MountainCarConfig)
+
+// NewMountainCarSimulation creates a mountain car simulation; unset parameters match Gym's MountainCar-v0
+func NewMountainCarSimulation(opts ...MountainCarOption) (Simulation, error) {
+	cfg := mountaincar.DefaultConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return newScenarioSimulation(mountaincar.NewMountainCarScenario(), cfg)
+}
+
+// WithMountainCarMaxSteps sets the episode length limit
+func WithMountainCarMaxSteps(steps int) MountainCarOption {
+	return func(c *MountainCarConfig) {
+		c.MaxSteps = steps
+	}
+}
+
+// WithMountainCarGravity sets the gravity term of the hill dynamics
+func WithMountainCarGravity(gravity float64) MountainCarOption {
+	return func(c *MountainCarConfig) {
+		c.Gravity = gravity
+	}
+}
+
+// WithMountainCarForce sets the engine force applied by each push
+func WithMountainCarForce(force float64) MountainCarOption {
+	return func(c *MountainCarConfig) {
+		c.Force = force
+	}
+}
+
+// WithMountainCarMaxSpeed sets the velocity limit
+func WithMountainCarMaxSpeed(speed float64) MountainCarOption {
+	return func(c *MountainCarConfig) {
+		c.MaxSpeed = speed
+	}
+}
+
+// WithMountainCarGoalPosition sets the position the car must reach
+func WithMountainCarGoalPosition(position float64) MountainCarOption {
+	return func(c *MountainCarConfig) {
+		c.GoalPosition = position
+	}
+}
+
+// LunarLanderConfig represents lunar lander simulation configuration
+type LunarLanderConfig = lunarlander.Config
+
+// LunarLanderOption is a function type for configuring lunar lander simulation
+type LunarLanderOption func(*LunarLanderConfig)
+
+// NewLunarLanderSimulation creates a lunar lander simulation with the given options
+func NewLunarLanderSimulation(opts ...LunarLanderOption) (Simulation, error) {
+	cfg := lunarlander.DefaultConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return newScenarioSimulation(lunarlander.NewLunarLanderScenario(), cfg)
+}
+
+// WithLunarLanderMaxSteps sets the episode length limit
+func WithLunarLanderMaxSteps(steps int) LunarLanderOption {
+	return func(c *LunarLanderConfig) {
+		c.MaxSteps = steps
+	}
+}
+
+// WithLunarLanderGravity sets the gravitational acceleration
+func WithLunarLanderGravity(gravity float64) LunarLanderOption {
+	return func(c *LunarLanderConfig) {
+		c.Gravity = gravity
+	}
+}
+
+// WithLunarLanderEnginePower sets the main and lateral thruster power
+func WithLunarLanderEnginePower(main, lateral float64) LunarLanderOption {
+	return func(c *LunarLanderConfig) {
+		c.ThrustPower = main
+		c.LateralPower = lateral
+	}
+}
+
+// WithLunarLanderPadWidth sets the width of the landing pad
+func WithLunarLanderPadWidth(width float64) LunarLanderOption {
+	return func(c *LunarLanderConfig) {
+		c.LandingPadWidth = width
+	}
+}
+
+// WithLunarLanderHybridActions switches to parameterized actions: an engine choice plus a
+// [0, 1] throttle scaling its thrust and fuel use
+func WithLunarLanderHybridActions(enabled bool) LunarLanderOption {
+	return func(c *LunarLanderConfig) {
+		c.HybridActions = enabled
+	}
+}
+
+// Game2048Config represents 2048 simulation configuration
+type Game2048Config = game2048.Config
+
+// Game2048Option is a function type for configuring 2048 simulation
+type Game2048Option func(*Game2048Config)
+
+// NewGame2048Simulation creates a 2048 simulation; unset parameters give the classic 4x4 game
+func NewGame2048Simulation(opts ...Game2048Option) (Simulation, error) {
+	cfg := game2048.DefaultConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return newScenarioSimulation(game2048.NewGame2048Scenario(), cfg)
+}
+
+// WithGame2048MaxSteps sets the episode length limit
+func WithGame2048MaxSteps(steps int) Game2048Option {
+	return func(c *Game2048Config) {
+		c.MaxSteps = steps
+	}
+}
+
+// WithGame2048Size sets the side length of the board
+func WithGame2048Size(size int) Game2048Option {
+	return func(c *Game2048Config) {
+		c.Size = size
+	}
+}
+
+// WithGame2048FourProbability sets the probability that a new tile is a 4
+func WithGame2048FourProbability(p float64) Game2048Option {
+	return func(c *Game2048Config) {
+		c.FourProbability = p
+	}
+}
+
+// WithGame2048InvalidMovePenalty sets the penalty for a move that leaves the board unchanged
+func WithGame2048InvalidMovePenalty(penalty float64) Game2048Option {
+	return func(c *Game2048Config) {
+		c.InvalidMovePenalty = penalty
+	}
+}
+
+// WithGame2048Seed seeds the tile spawns
+func WithGame2048Seed(seed int64) Game2048Option {
+	return func(c *Game2048Config) {
+		c.Seed = seed
+	}
+}
+
+// TradingConfig represents trading simulation configuration
+type TradingConfig = trading.Config
+
+// TradingOption is a function type for configuring trading simulation
+type TradingOption func(*TradingConfig)
+
+// NewTradingSimulation creates a trading simulation; by default prices follow a geometric
+// Brownian motion over one year of daily steps
+func NewTradingSimulation(opts ...TradingOption) (Simulation, error) {
+	cfg := trading.DefaultConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return newScenarioSimulation(trading.NewTradingScenario(), cfg)
+}
+
+// WithTradingMaxSteps sets the episode length limit
+func WithTradingMaxSteps(steps int) TradingOption {
+	return func(c *TradingConfig) {
+		c.MaxSteps = steps
+	}
+}
+
+// WithTradingGBM generates prices with a geometric Brownian motion from the initial price with
+// the given annualized drift and volatility
+func WithTradingGBM(initialPrice, mu, sigma float64) TradingOption {
+	return func(c *TradingConfig) {
+		c.PriceSource = trading.PriceSourceGBM
+		c.InitialPrice = initialPrice
+		c.Mu = mu
+		c.Sigma = sigma
+	}
+}
+
+// WithTradingCSV replays prices from a column of a CSV file
+func WithTradingCSV(path, column string) TradingOption {
+	return func(c *TradingConfig) {
+		c.PriceSource = trading.PriceSourceCSV
+		c.CSVPath = path
+		c.CSVColumn = column
+	}
+}
+
+// WithTradingDt sets the annualized time of one step
+func WithTradingDt(dt float64) TradingOption {
+	return func(c *TradingConfig) {
+		c.Dt = dt
+	}
+}
+
+// WithTradingTransactionCost sets the cost per unit of position change
+func WithTradingTransactionCost(cost float64) TradingOption {
+	return func(c *TradingConfig) {
+		c.TransactionCost = cost
+	}
+}
+
+// WithTradingMaxPosition sets the largest absolute position an action maps to
+func WithTradingMaxPosition(position float64) TradingOption {
+	return func(c *TradingConfig) {
+		c.MaxPosition = position
+	}
+}
+
+// WithTradingWindow sets how many past log returns the observation includes
+func WithTradingWindow(window int) TradingOption {
+	return func(c *TradingConfig) {
+		c.Window = window
+	}
+}
+
+// WithTradingSeed seeds the generated prices
+func WithTradingSeed(seed int64) TradingOption {
+	return func(c *TradingConfig) {
+		c.Seed = seed
+	}
+}
+
+// QueueingConfig represents queueing simulation configuration
+type QueueingConfig = queueing.Config
+
+// QueueingOption is a function type for configuring queueing simulation
+type QueueingOption func(*QueueingConfig)
+
+// NewQueueingSimulation creates a queueing simulation with the given options
+func NewQueueingSimulation(opts ...QueueingOption) (Simulation, error) {
+	cfg := queueing.DefaultConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return newScenarioSimulation(queueing.NewQueueingScenario(), cfg)
+}
+
+// WithQueueingMaxSteps sets the episode length limit
+func WithQueueingMaxSteps(steps int) QueueingOption {
+	return func(c *QueueingConfig) {
+		c.MaxSteps = steps
+	}
+}
+
+// WithQueueingServers sets the number of servers, whose speeds are spread evenly over [0.5, 1.5]
+func WithQueueingServers(n int) QueueingOption {
+	return func(c *QueueingConfig) {
+		c.NumServers = n
+		c.ServiceRates = nil
+	}
+}
+
+// WithQueueingServiceRates sets the speed of each server, one server per rate
+func WithQueueingServiceRates(rates ...float64) QueueingOption {
+	return func(c *QueueingConfig) {
+		c.NumServers = len(rates)
+		c.ServiceRates = rates
+	}
+}
+
+// WithQueueingArrivalRate sets the Poisson arrival rate of jobs
+func WithQueueingArrivalRate(rate float64) QueueingOption {
+	return func(c *QueueingConfig) {
+		c.ArrivalRate = rate
+	}
+}
+
+// WithQueueingMeanJobSize sets the mean of the exponentially distributed job sizes
+func WithQueueingMeanJobSize(size float64) QueueingOption {
+	return func(c *QueueingConfig) {
+		c.MeanJobSize = size
+	}
+}
+
+// WithQueueingMaxQueue sets the queue length bound of the observation space
+func WithQueueingMaxQueue(length int) QueueingOption {
+	return func(c *QueueingConfig) {
+		c.MaxQueue = length
+	}
+}
+
+// WithQueueingSeed seeds the arrivals and job sizes
+func WithQueueingSeed(seed int64) QueueingOption {
+	return func(c *QueueingConfig) {
+		c.Seed = seed
+	}
+}
+
+// TrafficConfig represents traffic simulation configuration
+type TrafficConfig = traffic.Config
+
+// TrafficOption is a function type for configuring traffic simulation
+type TrafficOption func(*TrafficConfig)
+
+// NewTrafficSimulation creates a traffic signal simulation with the given options
+func NewTrafficSimulation(opts ...TrafficOption) (Simulation, error) {
+	cfg := traffic.DefaultConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return newScenarioSimulation(traffic.NewTrafficScenario(), cfg)
+}
+
+// WithTrafficMaxSteps sets the episode length limit
+func WithTrafficMaxSteps(steps int) TrafficOption {
+	return func(c *TrafficConfig) {
+		c.MaxSteps = steps
+	}
+}
+
+// WithTrafficGrid sets the intersection grid, one agent per intersection
+func WithTrafficGrid(rows, cols int) TrafficOption {
+	return func(c *TrafficConfig) {
+		c.Rows = rows
+		c.Cols = cols
+	}
+}
+
+// WithTrafficArrivalRate sets the mean number of vehicles arriving at each boundary lane per step
+func WithTrafficArrivalRate(rate float64) TrafficOption {
+	return func(c *TrafficConfig) {
+		c.ArrivalRate = rate
+	}
+}
+
+// WithTrafficSaturationFlow sets how many vehicles a green lane passes per step
+func WithTrafficSaturationFlow(flow int) TrafficOption {
+	return func(c *TrafficConfig) {
+		c.SaturationFlow = flow
+	}
+}
+
+// WithTrafficLaneCapacity sets the longest queue a lane holds
+func WithTrafficLaneCapacity(capacity int) TrafficOption {
+	return func(c *TrafficConfig) {
+		c.LaneCapacity = capacity
+	}
+}
+
+// WithTrafficYellowTime sets the all-red steps of a phase change
+func WithTrafficYellowTime(steps int) TrafficOption {
+	return func(c *TrafficConfig) {
+		c.YellowTime = steps
+	}
+}
+
+// WithTrafficSeed seeds the vehicle arrivals
+func WithTrafficSeed(seed int64) TrafficOption {
+	return func(c *TrafficConfig) {
+		c.Seed = seed
+	}
+}
+
+// PredatorPreyConfig represents predator-prey simulation configuration
+type PredatorPreyConfig = predatorprey.Config
+
+// PredatorPreyOption is a function type for configuring predator-prey simulation
+type PredatorPreyOption func(*PredatorPreyConfig)
+
+// NewPredatorPreySimulation creates a predator-prey simulation with the given options
+func NewPredatorPreySimulation(opts ...PredatorPreyOption) (Simulation, error) {
+	cfg := predatorprey.DefaultConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return newScenarioSimulation(predatorprey.NewPredatorPreyScenario(), cfg)
+}
+
+// WithPredatorPreyMaxSteps sets the episode length limit
+func WithPredatorPreyMaxSteps(steps int) PredatorPreyOption {
+	return func(c *PredatorPreyConfig) {
+		c.MaxSteps = steps
+	}
+}
+
+// WithPredatorPreyGridSize sets the side length of the grid
+func WithPredatorPreyGridSize(size int) PredatorPreyOption {
+	return func(c *PredatorPreyConfig) {
+		c.GridSize = size
+	}
+}
+
+// WithPredatorPreyTeams sets the number of predators and prey
+func WithPredatorPreyTeams(predators, prey int) PredatorPreyOption {
+	return func(c *PredatorPreyConfig) {
+		c.NumPredators = predators
+		c.NumPrey = prey
+	}
+}
+
+// WithPredatorPreyRewards sets the capture reward and the per-step penalty of predators, which
+// prey receive as a survival reward
+func WithPredatorPreyRewards(capture, stepPenalty float64) PredatorPreyOption {
+	return func(c *PredatorPreyConfig) {
+		c.CaptureReward = capture
+		c.StepPenalty = stepPenalty
+	}
+}
+
+// WithPredatorPreySharedReward makes every predator receive the capture reward (cooperative)
+// instead of only those on the prey's cell
+func WithPredatorPreySharedReward(shared bool) PredatorPreyOption {
+	return func(c *PredatorPreyConfig) {
+		c.SharedReward = shared
+	}
+}
+
+// WithPredatorPreySeed seeds the agent placement
+func WithPredatorPreySeed(seed int64) PredatorPreyOption {
+	return func(c *PredatorPreyConfig) {
+		c.Seed = seed
+	}
+}
+
+// TicTacToeConfig represents TicTacToe simulation configuration
+type TicTacToeConfig = tictactoe.Config
+
+// TicTacToeOption is a function type for configuring TicTacToe simulation
+type TicTacToeOption func(*TicTacToeConfig)
+
+// NewTicTacToeSimulation creates a TicTacToe simulation; by default two agents play each other
+func NewTicTacToeSimulation(opts ...TicTacToeOption) (Simulation, error) {
+	cfg := tictactoe.DefaultConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return newScenarioSimulation(tictactoe.NewTicTacToeScenario(), cfg)
+}
+
+// WithTicTacToeOpponent plays against a built-in opponent (tictactoe.OpponentRandom or
+// tictactoe.OpponentMinimax), the agent holding player "x" or "o"
+func WithTicTacToeOpponent(opponent, agentPlayer string) TicTacToeOption {
+	return func(c *TicTacToeConfig) {
+		c.Opponent = opponent
+		c.AgentPlayer = agentPlayer
+	}
+}
+
+// WithTicTacToeIllegalMovePenalty sets the penalty for playing an occupied cell
+func WithTicTacToeIllegalMovePenalty(penalty float64) TicTacToeOption {
+	return func(c *TicTacToeConfig) {
+		c.IllegalMovePenalty = penalty
+	}
+}
+
+// WithTicTacToeSeed seeds the random opponent
+func WithTicTacToeSeed(seed int64) TicTacToeOption {
+	return func(c *TicTacToeConfig) {
+		c.Seed = seed
+	}
+}
+
+// SnakeConfig represents snake simulation configuration
+type SnakeConfig = snake.Config
+
+// SnakeOption is a function type for configuring snake simulation
+type SnakeOption func(*SnakeConfig)
+
+// NewSnakeSimulation creates a snake simulation; by default observations are feature vectors
+func NewSnakeSimulation(opts ...SnakeOption) (Simulation, error) {
+	cfg := snake.DefaultConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return newScenarioSimulation(snake.NewSnakeScenario(), cfg)
+}
+
+// WithSnakeMaxSteps sets the episode length limit
+func WithSnakeMaxSteps(steps int) SnakeOption {
+	return func(c *SnakeConfig) {
+		c.MaxSteps = steps
+	}
+}
+
+// WithSnakeGrid sets the width and height of the grid
+func WithSnakeGrid(width, height int) SnakeOption {
+	return func(c *SnakeConfig) {
+		c.Width = width
+		c.Height = height
+	}
+}
+
+// WithSnakePixels observes HxWx3 uint8 images with cellSize pixels per grid cell
+func WithSnakePixels(cellSize int) SnakeOption {
+	return func(c *SnakeConfig) {
+		c.ObsType = snake.ObsPixels
+		c.CellSize = cellSize
+	}
+}
+
+// WithSnakeHungerLimit ends an episode after that many steps without food, 0 for no limit
+func WithSnakeHungerLimit(steps int) SnakeOption {
+	return func(c *SnakeConfig) {
+		c.HungerLimit = steps
+	}
+}
+
+// WithSnakeRewards sets the food reward and the death and per-step penalties
+func WithSnakeRewards(food, death, step float64) SnakeOption {
+	return func(c *SnakeConfig) {
+		c.FoodReward = food
+		c.DeathPenalty = death
+		c.StepPenalty = step
+	}
+}
+
+// WithSnakeSeed seeds the food placement
+func WithSnakeSeed(seed int64) SnakeOption {
+	return func(c *SnakeConfig) {
+		c.Seed = seed
+	}
+}
+
+// MazeConfig represents maze simulation configuration
+type MazeConfig = maze.Config
+
+// MazeOption is a function type for configuring maze simulation
+type MazeOption func(*MazeConfig)
+
+// NewMazeSimulation creates a maze simulation; by default each reset generates a new perfect maze
+func NewMazeSimulation(opts ...MazeOption) (Simulation, error) {
+	cfg := maze.DefaultConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return newScenarioSimulation(maze.NewMazeScenario(), cfg)
+}
+
+// WithMazeMaxSteps sets the episode length limit
+func WithMazeMaxSteps(steps int) MazeOption {
+	return func(c *MazeConfig) {
+		c.MaxSteps = steps
+	}
+}
+
+// WithMazeSize sets the width and height of the maze in rooms
+func WithMazeSize(width, height int) MazeOption {
+	return func(c *MazeConfig) {
+		c.Width = width
+		c.Height = height
+	}
+}
+
+// WithMazeWallDensity sets the share of walls kept outside the spanning tree, 1 for a perfect maze
+func WithMazeWallDensity(density float64) MazeOption {
+	return func(c *MazeConfig) {
+		c.WallDensity = density
+	}
+}
+
+// WithMazeViewRadius observes only the cells within radius of the agent, 0 for the whole maze
+func WithMazeViewRadius(radius int) MazeOption {
+	return func(c *MazeConfig) {
+		c.ViewRadius = radius
+	}
+}
+
+// WithMazeLayouts draws each layout from the n mazes seeded seed..seed+n-1, e.g. to keep
+// training and test layouts apart
+func WithMazeLayouts(seed int64, n int) MazeOption {
+	return func(c *MazeConfig) {
+		c.MazeSeed = seed
+		c.NumMazes = n
+	}
+}
+
+// WithMazeRewards sets the goal reward and the per-step penalty
+func WithMazeRewards(goal, stepPenalty float64) MazeOption {
+	return func(c *MazeConfig) {
+		c.GoalReward = goal
+		c.StepPenalty = stepPenalty
+	}
+}
+
+// WithMazeSeed seeds the generated layouts and start positions
+func WithMazeSeed(seed int64) MazeOption {
+	return func(c *MazeConfig) {
+		c.Seed = seed
+	}
+}
+
+// WalkerConfig represents walker simulation configuration
+type WalkerConfig = walker.Config
+
+// WalkerOption is a function type for configuring walker simulation
+type WalkerOption func(*WalkerConfig)
+
+// NewWalkerSimulation creates a 2D biped walker simulation with the given options
+func NewWalkerSimulation(opts ...WalkerOption) (Simulation, error) {
+	cfg := walker.DefaultConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return newScenarioSimulation(walker.NewWalkerScenario(), cfg)
+}
+
+// WithWalkerMaxSteps sets the episode length limit
+func WithWalkerMaxSteps(steps int) WalkerOption {
+	return func(c *WalkerConfig) {
+		c.MaxSteps = steps
+	}
+}
+
+// WithWalkerDt sets the integration time step in seconds
+func WithWalkerDt(dt float64) WalkerOption {
+	return func(c *WalkerConfig) {
+		c.Dt = dt
+	}
+}
+
+// WithWalkerMaxTorque sets the joint torque of a full [-1, 1] action
+func WithWalkerMaxTorque(torque float64) WalkerOption {
+	return func(c *WalkerConfig) {
+		c.MaxTorque = torque
+	}
+}
+
+// WithWalkerGround sets the gravitational acceleration and the ground friction
+func WithWalkerGround(gravity, friction float64) WalkerOption {
+	return func(c *WalkerConfig) {
+		c.Gravity = gravity
+		c.Friction = friction
+	}
+}
+
+// WithWalkerRewardWeights sets the forward velocity weight, the reward for each step upright and
+// the control cost weight
+func WithWalkerRewardWeights(forward, healthy, ctrlCost float64) WalkerOption {
+	return func(c *WalkerConfig) {
+		c.ForwardWeight = forward
+		c.HealthyReward = healthy
+		c.CtrlCostWeight = ctrlCost
+	}
+}
+
+// WithWalkerFall sets the torso angle beyond which the walker has fallen and the penalty for it
+func WithWalkerFall(maxTorsoAngle, penalty float64) WalkerOption {
+	return func(c *WalkerConfig) {
+		c.MaxTorsoAngle = maxTorsoAngle
+		c.FallPenalty = penalty
+	}
+}
+
+// WithWalkerResetNoise sets the amplitude of the uniform noise on the initial positions
+func WithWalkerResetNoise(noise float64) WalkerOption {
+	return func(c *WalkerConfig) {
+		c.ResetNoise = noise
+	}
+}
+
+// WithWalkerNamedActions switches to a Dict action space keyed by joint name
+func WithWalkerNamedActions(enabled bool) WalkerOption {
+	return func(c *WalkerConfig) {
+		c.NamedActions = enabled
+	}
+}
+
+// WithWalkerSeed seeds the reset noise
+func WithWalkerSeed(seed int64) WalkerOption {
+	return func(c *WalkerConfig) {
+		c.Seed = seed
+	}
+}
+
+// LQRConfig represents linear system simulation configuration
+type LQRConfig = lqr.Config
+
+// LQROption is a function type for configuring linear system simulation
+type LQROption func(*LQRConfig)
+
+// NewLQRSimulation creates a linear system simulation; by default the system is a chain of
+// integrators sized by the state and action dimensions
+func NewLQRSimulation(opts ...LQROption) (Simulation, error) {
+	cfg := lqr.DefaultConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return newScenarioSimulation(lqr.NewLQRScenario(), cfg)
+}
+
+// WithLQRMaxSteps sets the episode length limit
+func WithLQRMaxSteps(steps int) LQROption {
+	return func(c *LQRConfig) {
+		c.MaxSteps = steps
+	}
+}
+
+// WithLQRDims sets the state and action dimensions of the default integrator chain with step dt
+func WithLQRDims(state, action int, dt float64) LQROption {
+	return func(c *LQRConfig) {
+		c.StateDim = state
+		c.ActionDim = action
+		c.Dt = dt
+	}
+}
+
+// WithLQRSystem sets the dynamics x' = Ax + Bu + w, which also fix the dimensions
+func WithLQRSystem(a, b lqr.Matrix) LQROption {
+	return func(c *LQRConfig) {
+		c.A = a
+		c.B = b
+	}
+}
+
+// WithLQRCosts sets the state and control cost matrices, identity when nil
+func WithLQRCosts(q, r lqr.Matrix) LQROption {
+	return func(c *LQRConfig) {
+		c.Q = q
+		c.R = r
+	}
+}
+
+// WithLQRNoise sets the standard deviations of the process noise and of the initial state
+func WithLQRNoise(noiseStd, initStd float64) LQROption {
+	return func(c *LQRConfig) {
+		c.NoiseStd = noiseStd
+		c.InitStd = initStd
+	}
+}
+
+// WithLQRLimits clips each action component to action and ends an episode once a state
+// component exceeds state, 0 for no limit
+func WithLQRLimits(action, state float64) LQROption {
+	return func(c *LQRConfig) {
+		c.ActionLimit = action
+		c.StateLimit = state
+	}
+}
+
+// WithLQRSeed seeds the initial state and the process noise
+func WithLQRSeed(seed int64) LQROption {
+	return func(c *LQRConfig) {
+		c.Seed = seed
+	}
+}
+
+// ScriptedConfig represents scripted simulation configuration
+type ScriptedConfig = scripted.Config
+
+// ScriptedOption is a function type for configuring scripted simulation
+type ScriptedOption func(*ScriptedConfig)
+
+// NewScriptedSimulation creates a scripted simulation over the given state variables; the
+// options supply its expressions, which default to constant state, zero reward and no termination
+func NewScriptedSimulation(stateVars []string, opts ...ScriptedOption) (Simulation, error) {
+	cfg := scripted.DefaultConfig()
+	cfg.StateVars = stateVars
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return newScenarioSimulation(scripted.NewScriptedScenario(), cfg)
+}
+
+// WithScriptedMaxSteps sets the episode length limit
+func WithScriptedMaxSteps(steps int) ScriptedOption {
+	return func(c *ScriptedConfig) {
+		c.MaxSteps = steps
+	}
+}
+
+// WithScriptedParams sets the named constants the expressions may use
+func WithScriptedParams(params map[string]float64) ScriptedOption {
+	return func(c *ScriptedConfig) {
+		c.Params = params
+	}
+}
+
+// WithScriptedInit sets the initial value expression of each state variable
+func WithScriptedInit(init map[string]string) ScriptedOption {
+	return func(c *ScriptedConfig) {
+		c.Init = init
+	}
+}
+
+// WithScriptedDynamics sets the transition expression of each state variable
+func WithScriptedDynamics(dynamics map[string]string) ScriptedOption {
+	return func(c *ScriptedConfig) {
+		c.Dynamics = dynamics
+	}
+}
+
+// WithScriptedReward sets the reward and termination expressions, evaluated after the transition
+func WithScriptedReward(reward, done string) ScriptedOption {
+	return func(c *ScriptedConfig) {
+		c.Reward = reward
+		c.Done = done
+	}
+}
+
+// WithScriptedObservation sets the observation expressions, all state variables when unset
+func WithScriptedObservation(exprs ...string) ScriptedOption {
+	return func(c *ScriptedConfig) {
+		c.Observation = exprs
+	}
+}
+
+// WithScriptedContinuousActions uses dim continuous actions clipped to [low, high]
+func WithScriptedContinuousActions(dim int, low, high float64) ScriptedOption {
+	return func(c *ScriptedConfig) {
+		c.ActionType = scripted.ActionContinuous
+		c.ActionDim = dim
+		c.ActionLow = low
+		c.ActionHigh = high
+	}
+}
+
+// WithScriptedDiscreteActions uses a discrete action in 0..n-1
+func WithScriptedDiscreteActions(n int) ScriptedOption {
+	return func(c *ScriptedConfig) {
+		c.ActionType = scripted.ActionDiscrete
+		c.NumActions = n
+	}
+}
+
+// WithScriptedSeed seeds the random functions of the expressions
+func WithScriptedSeed(seed int64) ScriptedOption {
+	return func(c *ScriptedConfig) {
+		c.Seed = seed
+	}
+}
+
+// InventoryConfig represents inventory simulation configuration
+type InventoryConfig = inventory.Config
+
+// InventoryOption is a function type for configuring inventory simulation
+type InventoryOption func(*InventoryConfig)
+
+// NewInventorySimulation creates an inventory replenishment simulation with the given options
+func NewInventorySimulation(opts ...InventoryOption) (Simulation, error) {
+	cfg := inventory.DefaultConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return newScenarioSimulation(inventory.NewInventoryScenario(), cfg)
+}
+
+// WithInventoryMaxSteps sets the episode length limit
+func WithInventoryMaxSteps(steps int) InventoryOption {
+	return func(c *InventoryConfig) {
+		c.MaxSteps = steps
+	}
+}
+
+// WithInventoryProducts sets the number of products, whose mean demands are spread evenly over [1, 3]
+func WithInventoryProducts(n int) InventoryOption {
+	return func(c *InventoryConfig) {
+		c.NumProducts = n
+		c.DemandRates = nil
+	}
+}
+
+// WithInventoryDemandRates sets the mean demand per step of each product, one product per rate
+func WithInventoryDemandRates(rates ...float64) InventoryOption {
+	return func(c *InventoryConfig) {
+		c.NumProducts = len(rates)
+		c.DemandRates = rates
+	}
+}
+
+// WithInventoryLimits sets the largest order per product and step and the stock capacity
+func WithInventoryLimits(maxOrder, capacity int) InventoryOption {
+	return func(c *InventoryConfig) {
+		c.MaxOrder = maxOrder
+		c.Capacity = capacity
+	}
+}
+
+// WithInventoryLeadTime sets the steps before an order arrives, 0 for the same step
+func WithInventoryLeadTime(steps int) InventoryOption {
+	return func(c *InventoryConfig) {
+		c.LeadTime = steps
+	}
+}
+
+// WithInventoryCosts sets the holding cost per unit and step, the stockout cost per unit of
+// unmet demand and the fixed cost per order
+func WithInventoryCosts(holding, stockout, order float64) InventoryOption {
+	return func(c *InventoryConfig) {
+		c.HoldingCost = holding
+		c.StockoutCost = stockout
+		c.OrderCost = order
+	}
+}
+
+// WithInventorySeed seeds the demand
+func WithInventorySeed(seed int64) InventoryOption {
+	return func(c *InventoryConfig) {
+		c.Seed = seed
+	}
+}
+
+// ReplayConfig represents replay simulation configuration
+type ReplayConfig = replay.Config
+
+// ReplayOption is a function type for configuring replay simulation
+type ReplayOption func(*ReplayConfig)
+
+// NewReplaySimulation creates a simulation replaying the JSONL trajectory at path
+func NewReplaySimulation(path string, opts ...ReplayOption) (Simulation, error) {
+	var cfg ReplayConfig
+	if err := config.Bind(core.NewBaseConfig(map[string]interface{}{"path": path}), &cfg); err != nil {
+		return nil, err
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return newScenarioSimulation(replay.NewReplayScenario(), cfg)
+}
+
+// WithReplayVerifyActions checks that incoming actions match the recorded ones, numeric
+// actions within tolerance
+func WithReplayVerifyActions(tolerance float64) ReplayOption {
+	return func(c *ReplayConfig) {
+		c.VerifyActions = true
+		c.Tolerance = tolerance
+	}
+}
+
+// WithReplayLoop sets whether the replay restarts from the first episode once it ends
+func WithReplayLoop(loop bool) ReplayOption {
+	return func(c *ReplayConfig) {
+		c.Loop = loop
+	}
+}
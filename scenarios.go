@@ -0,0 +1,54 @@
+package rl_env_engine
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// Scenario creates the environments of a named simulation; implement it to plug your own
+// environments into NewSimulation and the servers
+type Scenario = core.Scenario
+
+// defaultScenarios holds the scenarios added with RegisterScenario
+var (
+	defaultScenariosMu sync.Mutex
+	defaultScenarios   []Scenario
+)
+
+// RegisterScenario adds a scenario to the default registry consulted by NewSimulation,
+// StartHTTPServer, StartGrpcServer and StartShmServer, so it can be created by name both
+// in-process and by remote clients:
+//
+//	simulations.RegisterScenario(myenv.NewScenario())
+//	sim, err := simulations.NewSimulation("myenv", nil)
+//
+// A scenario named like a built-in one replaces it. Register scenarios before creating
+// simulations or starting servers; presets may refer to them.
+func RegisterScenario(scenario Scenario) error {
+	if scenario == nil || scenario.GetName() == "" {
+		return fmt.Errorf("scenario name is required")
+	}
+	defaultScenariosMu.Lock()
+	defer defaultScenariosMu.Unlock()
+	for _, s := range defaultScenarios {
+		if s.GetName() == scenario.GetName() {
+			return fmt.Errorf("scenario %q is already registered", scenario.GetName())
+		}
+	}
+	defaultScenarios = append(defaultScenarios, scenario)
+	return nil
+}
+
+// InstallScenarios registers the scenarios added with RegisterScenario with the engine,
+// replacing engine scenarios of the same name
+func InstallScenarios(engine *core.SimulationEngine) {
+	defaultScenariosMu.Lock()
+	scenarios := append([]Scenario(nil), defaultScenarios...)
+	defaultScenariosMu.Unlock()
+
+	for _, scenario := range scenarios {
+		engine.RegisterScenario(scenario)
+	}
+}
@@ -0,0 +1,56 @@
+// Package scenarios 汇总随仓库发布的全部内置场景，NewSimulation、各服务端与pybridge都从这里注册场景，
+// 保证每个场景在所有入口都能按名称创建
+package scenarios
+
+import (
+	"github.com/jelech/rl_env_engine/core"
+	"github.com/jelech/rl_env_engine/scenarios/cartpole"
+	"github.com/jelech/rl_env_engine/scenarios/game2048"
+	"github.com/jelech/rl_env_engine/scenarios/inventory"
+	"github.com/jelech/rl_env_engine/scenarios/lqr"
+	"github.com/jelech/rl_env_engine/scenarios/lunarlander"
+	"github.com/jelech/rl_env_engine/scenarios/maze"
+	"github.com/jelech/rl_env_engine/scenarios/mountaincar"
+	"github.com/jelech/rl_env_engine/scenarios/pendulum"
+	"github.com/jelech/rl_env_engine/scenarios/predatorprey"
+	"github.com/jelech/rl_env_engine/scenarios/queueing"
+	"github.com/jelech/rl_env_engine/scenarios/replay"
+	"github.com/jelech/rl_env_engine/scenarios/scripted"
+	"github.com/jelech/rl_env_engine/scenarios/simple"
+	"github.com/jelech/rl_env_engine/scenarios/snake"
+	"github.com/jelech/rl_env_engine/scenarios/tictactoe"
+	"github.com/jelech/rl_env_engine/scenarios/trading"
+	"github.com/jelech/rl_env_engine/scenarios/traffic"
+	"github.com/jelech/rl_env_engine/scenarios/walker"
+)
+
+// All 返回全部内置场景的新实例。WASM场景（wasmenv）由客户端上传模块后才能创建，不在其中
+func All() []core.Scenario {
+	return []core.Scenario{
+		simple.NewSimpleScenario(),
+		cartpole.NewCartPoleScenario(),
+		pendulum.NewPendulumScenario(),
+		mountaincar.NewMountainCarScenario(),
+		lunarlander.NewLunarLanderScenario(),
+		trading.NewTradingScenario(),
+		queueing.NewQueueingScenario(),
+		inventory.NewInventoryScenario(),
+		traffic.NewTrafficScenario(),
+		predatorprey.NewPredatorPreyScenario(),
+		game2048.NewGame2048Scenario(),
+		tictactoe.NewTicTacToeScenario(),
+		snake.NewSnakeScenario(),
+		maze.NewMazeScenario(),
+		walker.NewWalkerScenario(),
+		lqr.NewLQRScenario(),
+		scripted.NewScriptedScenario(),
+		replay.NewReplayScenario(),
+	}
+}
+
+// RegisterAll 将全部内置场景注册到引擎
+func RegisterAll(engine *core.SimulationEngine) {
+	for _, scenario := range All() {
+		engine.RegisterScenario(scenario)
+	}
+}
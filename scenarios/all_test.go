@@ -0,0 +1,77 @@
+package scenarios
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// benchActionSets 每个场景预先采样的动作组数，计时期间循环使用，采样本身不计入单步开销
+const benchActionSets = 256
+
+// BenchmarkStep 在进程内测量每个内置场景以默认配置单步的耗时与内存分配，回合结束时重置环境（重置计入耗时）。
+// 与rlenv bench的inproc一列对应，pybridge、HTTP与gRPC的同名基准分别位于pybridge与server包
+func BenchmarkStep(b *testing.B) {
+	ctx := context.Background()
+	for _, scenario := range All() {
+		b.Run(scenario.GetName(), func(b *testing.B) {
+			env, err := scenario.CreateEnvironment(core.NewBaseConfig(map[string]interface{}{}))
+			if err != nil {
+				b.Skipf("default config: %v", err)
+			}
+			defer env.Close()
+			actions := sampleActionSets(b, env)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := benchStep(ctx, env, actions[i%len(actions)]); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// sampleActionSets 采样benchActionSets组随机动作，并先用它们空跑一轮；
+// 随机动作无法驱动的场景（如回合制场景的非法落子）跳过
+func sampleActionSets(b *testing.B, env core.Environment) [][]core.Action {
+	b.Helper()
+	ctx := context.Background()
+	observations, err := env.Reset(ctx)
+	if err != nil {
+		b.Fatalf("reset: %v", err)
+	}
+	rng := rand.New(rand.NewSource(1))
+	actions := make([][]core.Action, benchActionSets)
+	for i := range actions {
+		if actions[i], err = core.SampleActions(env, env.GetSpaces().ActionSpace, len(observations), rng); err != nil {
+			b.Skipf("sample actions: %v", err)
+		}
+	}
+	core.ReleaseObservations(observations)
+	for _, set := range actions {
+		if err := benchStep(ctx, env, set); err != nil {
+			b.Skipf("random actions: %v", err)
+		}
+	}
+	return actions
+}
+
+// benchStep 执行一步，所有智能体结束时重置环境，并归还观察
+func benchStep(ctx context.Context, env core.Environment, actions []core.Action) error {
+	observations, _, dones, err := env.Step(ctx, actions)
+	if err != nil {
+		return err
+	}
+	core.ReleaseObservations(observations)
+	if core.AllDone(dones) {
+		if observations, err = env.Reset(ctx); err != nil {
+			return err
+		}
+		core.ReleaseObservations(observations)
+	}
+	return nil
+}
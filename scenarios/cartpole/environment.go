@@ -2,15 +2,44 @@ package cartpole
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"math"
 	"math/rand"
-	"strconv"
 	"time"
 
 	"github.com/jelech/rl_env_engine/core"
+	"github.com/jelech/rl_env_engine/core/config"
 )
 
+// Config CartPole环境配置
+// 物理参数默认值与OpenAI Gym的CartPole-v1一致
+type Config struct {
+	MaxSteps              int     `cfg:"max_steps,default=500,min=1"`
+	Gravity               float64 `cfg:"gravity,default=9.8,gt=0"`
+	MassCart              float64 `cfg:"mass_cart,default=1.0,gt=0"`
+	MassPole              float64 `cfg:"mass_pole,default=0.1,gt=0"`
+	Length                float64 `cfg:"length,default=0.5,gt=0"` // 杆子长度的一半
+	ForceMag              float64 `cfg:"force_mag,default=10.0,gt=0"`
+	Tau                   float64 `cfg:"tau,default=0.02,gt=0"`                         // 时间步长
+	ThetaThresholdDegrees float64 `cfg:"theta_threshold_degrees,default=12,gt=0,lt=90"` // 杆子角度终止阈值
+	XThreshold            float64 `cfg:"x_threshold,default=2.4,gt=0"`                  // 小车位置终止阈值
+}
+
+// DefaultConfig 返回默认配置
+func DefaultConfig() Config {
+	var cfg Config
+	config.MustDefaults(&cfg)
+	return cfg
+}
+
+// parseConfig 将core.Config绑定为CartPole环境配置，未提供的字段使用默认值
+func parseConfig(src core.Config) (Config, error) {
+	var cfg Config
+	err := config.Bind(src, &cfg)
+	return cfg, err
+}
+
 // CartPoleEnvironment 经典的平衡杆控制环境
 // 目标：通过向左或向右移动小车来保持杆子平衡
 type CartPoleEnvironment struct {
@@ -38,39 +67,39 @@ type CartPoleEnvironment struct {
 	xThreshold            float64
 
 	rng *rand.Rand
+	src *core.RandSource
 }
 
-// NewCartPoleEnvironment 创建新的CartPole环境
-func NewCartPoleEnvironment(config core.Config) *CartPoleEnvironment {
+// NewCartPoleEnvironment 创建新的CartPole环境，配置无效时返回错误
+func NewCartPoleEnvironment(config core.Config) (*CartPoleEnvironment, error) {
+	cfg, err := parseConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return newCartPoleEnvironment(config, cfg), nil
+}
+
+// newCartPoleEnvironment 以绑定后的配置创建环境
+func newCartPoleEnvironment(config core.Config, cfg Config) *CartPoleEnvironment {
 	baseEnv := core.NewBaseEnvironment("cartpole", "Classic CartPole control environment", config)
 
-	// 从配置中获取参数，如果没有则使用默认值
-	maxSteps := 500
-	if val := config.GetValue("max_steps"); val != nil {
-		switch v := val.(type) {
-		case int:
-			maxSteps = v
-		case string:
-			if parsed, err := strconv.Atoi(v); err == nil {
-				maxSteps = parsed
-			}
-		}
-	}
+	maxSteps := cfg.MaxSteps
 
-	// 物理参数（基于OpenAI Gym的CartPole-v1）
-	gravity := 9.8
-	masscart := 1.0
-	masspole := 0.1
+	// 物理参数
+	gravity := cfg.Gravity
+	masscart := cfg.MassCart
+	masspole := cfg.MassPole
 	totalMass := masspole + masscart
-	length := 0.5 // 实际上是杆子长度的一半
+	length := cfg.Length // 实际上是杆子长度的一半
 	polemassLength := masspole * length
-	forceMag := 10.0
-	tau := 0.02 // 50 FPS
+	forceMag := cfg.ForceMag
+	tau := cfg.Tau
 
 	// 阈值
-	thetaThresholdRadians := 12 * 2 * math.Pi / 360 // ±12°
-	xThreshold := 2.4
+	thetaThresholdRadians := cfg.ThetaThresholdDegrees * 2 * math.Pi / 360
+	xThreshold := cfg.XThreshold
 
+	src := core.NewRandSource(time.Now().UnixNano())
 	env := &CartPoleEnvironment{
 		BaseEnvironment:       baseEnv,
 		maxSteps:              maxSteps,
@@ -85,7 +114,8 @@ func NewCartPoleEnvironment(config core.Config) *CartPoleEnvironment {
 		tau:                   tau,
 		thetaThresholdRadians: thetaThresholdRadians,
 		xThreshold:            xThreshold,
-		rng:                   rand.New(rand.NewSource(time.Now().UnixNano())),
+		rng:                   rand.New(src),
+		src:                   src,
 	}
 
 	return env
@@ -107,7 +137,7 @@ func (e *CartPoleEnvironment) Reset(ctx context.Context) ([]core.Observation, er
 // Step 执行一步
 func (e *CartPoleEnvironment) Step(ctx context.Context, actions []core.Action) ([]core.Observation, []float64, []bool, error) {
 	if len(actions) == 0 {
-		return nil, nil, nil, fmt.Errorf("no actions provided")
+		return nil, nil, nil, fmt.Errorf("%w: no actions provided", core.ErrInvalidAction)
 	}
 
 	e.currentStep++
@@ -119,7 +149,7 @@ func (e *CartPoleEnvironment) Step(ctx context.Context, actions []core.Action) (
 	if genericAction, ok := actions[0].(*core.GenericAction); ok {
 		actionValue, err := genericAction.GetFloat64()
 		if err != nil {
-			return nil, nil, nil, fmt.Errorf("failed to extract action value: %w", err)
+			return nil, nil, nil, fmt.Errorf("%w: failed to extract action value: %w", core.ErrInvalidAction, err)
 		}
 		// 将连续动作转换为离散动作
 		if actionValue < 0.5 {
@@ -135,7 +165,7 @@ func (e *CartPoleEnvironment) Step(ctx context.Context, actions []core.Action) (
 			force = e.forceMag
 		}
 	} else {
-		return nil, nil, nil, fmt.Errorf("unsupported action type: %T", actions[0])
+		return nil, nil, nil, fmt.Errorf("%w: unsupported action type: %T", core.ErrInvalidAction, actions[0])
 	}
 
 	// 物理仿真（使用Euler方法）
@@ -179,16 +209,19 @@ func (e *CartPoleEnvironment) GetObservations() []core.Observation {
 		e.thetaDot, // 杆子角速度
 	}
 
-	metadata := map[string]interface{}{
-		"x":         e.x,
-		"x_dot":     e.xDot,
-		"theta":     e.theta,
-		"theta_dot": e.thetaDot,
-		"step":      e.currentStep,
-		"max_steps": e.maxSteps,
+	var metadata map[string]interface{}
+	if e.ObservationMetadataEnabled() {
+		metadata = map[string]interface{}{
+			"x":         e.x,
+			"x_dot":     e.xDot,
+			"theta":     e.theta,
+			"theta_dot": e.thetaDot,
+			"step":      e.currentStep,
+			"max_steps": e.maxSteps,
+		}
 	}
 
-	observation := core.NewBaseObservation(data, metadata)
+	observation := e.AcquireObservation(data, metadata)
 	return []core.Observation{observation}
 }
 
@@ -213,6 +246,178 @@ func (e *CartPoleEnvironment) Close() error {
 	return e.BaseEnvironment.Close()
 }
 
+// 渲染画面尺寸与颜色，与Gym的CartPole保持一致
+const (
+	screenWidth  = 600
+	screenHeight = 400
+	cartWidth    = 50.0
+	cartHeight   = 30.0
+	poleWidth    = 10.0
+)
+
+var (
+	colorBackground = [3]uint8{255, 255, 255}
+	colorCart       = [3]uint8{0, 0, 0}
+	colorPole       = [3]uint8{202, 152, 101}
+	colorAxle       = [3]uint8{129, 132, 203}
+)
+
+// RenderFrame 将当前状态渲染为行优先的RGB图像帧（600x400）
+func (e *CartPoleEnvironment) RenderFrame() ([]uint8, int, int) {
+	frame := make([]uint8, screenHeight*screenWidth*3)
+	for i := 0; i < len(frame); i += 3 {
+		copy(frame[i:i+3], colorBackground[:])
+	}
+	set := func(px, py int, color [3]uint8) {
+		if px >= 0 && px < screenWidth && py >= 0 && py < screenHeight {
+			offset := (py*screenWidth + px) * 3
+			copy(frame[offset:offset+3], color[:])
+		}
+	}
+
+	scale := screenWidth / (2 * e.xThreshold)
+	cartX := e.x*scale + screenWidth/2
+	trackY := screenHeight * 0.75 // 图像坐标，y向下
+	axleY := trackY - cartHeight/4
+
+	// 轨道
+	for px := 0; px < screenWidth; px++ {
+		set(px, int(trackY), colorCart)
+	}
+	// 小车
+	for py := int(trackY - cartHeight*3/4); py < int(trackY+cartHeight/4); py++ {
+		for px := int(cartX - cartWidth/2); px < int(cartX+cartWidth/2); px++ {
+			set(px, py, colorCart)
+		}
+	}
+	// 杆子：以轴为起点、沿theta方向（0为竖直向上，正值向右倾）的旋转矩形
+	poleLen := scale * 2 * e.length
+	dirX, dirY := math.Sin(e.theta), -math.Cos(e.theta)
+	reach := int(poleLen + poleWidth)
+	for py := int(axleY) - reach; py <= int(axleY)+reach; py++ {
+		for px := int(cartX) - reach; px <= int(cartX)+reach; px++ {
+			dx, dy := float64(px)-cartX, float64(py)-axleY
+			along := dx*dirX + dy*dirY
+			across := dx*dirY - dy*dirX
+			if along >= -poleWidth/2 && along <= poleLen && math.Abs(across) <= poleWidth/2 {
+				set(px, py, colorPole)
+			}
+		}
+	}
+	// 轴
+	radius := poleWidth / 2
+	for py := int(axleY - radius); py <= int(axleY+radius); py++ {
+		for px := int(cartX - radius); px <= int(cartX+radius); px++ {
+			if math.Hypot(float64(px)-cartX, float64(py)-axleY) <= radius {
+				set(px, py, colorAxle)
+			}
+		}
+	}
+	return frame, screenHeight, screenWidth
+}
+
+// Metadata 返回环境元数据：每步存活奖励1，失败时为0
+func (e *CartPoleEnvironment) Metadata() core.EnvMetadata {
+	return core.EnvMetadata{
+		RewardRange:     [2]float64{0, 1},
+		MaxEpisodeSteps: e.maxSteps,
+	}
+}
+
+// DumpState 导出小车与杆子的状态及终止阈值
+func (e *CartPoleEnvironment) DumpState() map[string]interface{} {
+	return map[string]interface{}{
+		"x":                       e.x,
+		"x_dot":                   e.xDot,
+		"theta":                   e.theta,
+		"theta_dot":               e.thetaDot,
+		"step":                    e.currentStep,
+		"max_steps":               e.maxSteps,
+		"theta_threshold_radians": e.thetaThresholdRadians,
+		"x_threshold":             e.xThreshold,
+	}
+}
+
+// checkpoint CartPole环境的检查点
+type checkpoint struct {
+	X        float64        `json:"x"`
+	XDot     float64        `json:"x_dot"`
+	Theta    float64        `json:"theta"`
+	ThetaDot float64        `json:"theta_dot"`
+	Step     int            `json:"step"`
+	Rand     core.RandState `json:"rand"`
+	// Parameters 检查点时的物理参数，域随机化等包装器修改过参数时恢复后保持一致
+	Parameters map[string]float64 `json:"parameters,omitempty"`
+}
+
+// Checkpoint 实现core.Checkpointer
+func (e *CartPoleEnvironment) Checkpoint() ([]byte, error) {
+	return json.Marshal(checkpoint{
+		X:          e.x,
+		XDot:       e.xDot,
+		Theta:      e.theta,
+		ThetaDot:   e.thetaDot,
+		Step:       e.currentStep,
+		Rand:       e.src.State(),
+		Parameters: e.Parameters(),
+	})
+}
+
+// RestoreCheckpoint 实现core.Checkpointer
+func (e *CartPoleEnvironment) RestoreCheckpoint(data []byte) error {
+	var cp checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return fmt.Errorf("invalid cartpole checkpoint: %w", err)
+	}
+	e.x, e.xDot, e.theta, e.thetaDot = cp.X, cp.XDot, cp.Theta, cp.ThetaDot
+	e.currentStep = cp.Step
+	e.src.Restore(cp.Rand)
+	for name, value := range cp.Parameters {
+		if err := e.SetParameter(name, value); err != nil {
+			return fmt.Errorf("invalid cartpole checkpoint: %w", err)
+		}
+	}
+	return nil
+}
+
+// Parameters 实现core.Parameterized：可在回合之间修改的物理参数
+func (e *CartPoleEnvironment) Parameters() map[string]float64 {
+	return map[string]float64{
+		"gravity":   e.gravity,
+		"mass_cart": e.masscart,
+		"mass_pole": e.masspole,
+		"length":    e.length,
+		"force_mag": e.forceMag,
+		"tau":       e.tau,
+	}
+}
+
+// SetParameter 实现core.Parameterized，同时更新由参数派生的总质量与质量长度积
+func (e *CartPoleEnvironment) SetParameter(name string, value float64) error {
+	if err := core.PositiveParameter(name, value); err != nil {
+		return err
+	}
+	switch name {
+	case "gravity":
+		e.gravity = value
+	case "mass_cart":
+		e.masscart = value
+	case "mass_pole":
+		e.masspole = value
+	case "length":
+		e.length = value
+	case "force_mag":
+		e.forceMag = value
+	case "tau":
+		e.tau = value
+	default:
+		return core.UnknownParameterError(name, e)
+	}
+	e.totalMass = e.masspole + e.masscart
+	e.polemassLength = e.masspole * e.length
+	return nil
+}
+
 // GetSpaces 获取CartPole场景的动作空间和观察空间定义
 func (e *CartPoleEnvironment) GetSpaces() core.SpaceDefinition {
 	return core.SpaceDefinition{
@@ -225,8 +430,8 @@ func (e *CartPoleEnvironment) GetSpaces() core.SpaceDefinition {
 		},
 		ObservationSpace: core.ObservationSpace{
 			Type:  core.SpaceTypeBox,
-			Low:   []float64{-4.8, -1e6, -0.42, -1e6}, // [x, x_dot, theta, theta_dot]
-			High:  []float64{4.8, 1e6, 0.42, 1e6},
+			Low:   []float64{-2 * e.xThreshold, -1e6, -2 * e.thetaThresholdRadians, -1e6}, // [x, x_dot, theta, theta_dot]
+			High:  []float64{2 * e.xThreshold, 1e6, 2 * e.thetaThresholdRadians, 1e6},
 			Shape: []int32{4},
 			Dtype: "float32",
 		},
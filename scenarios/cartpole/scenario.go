@@ -35,26 +35,15 @@ func (s *CartPoleScenario) GetDescription() string {
 
 // CreateEnvironment 创建环境实例
 func (s *CartPoleScenario) CreateEnvironment(config core.Config) (core.Environment, error) {
-	env := NewCartPoleEnvironment(config)
-	return env, nil
+	cfg, err := parseConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cartpole environment: %w", err)
+	}
+	return newCartPoleEnvironment(config, cfg), nil
 }
 
 // ValidateConfig 验证配置
 func (s *CartPoleScenario) ValidateConfig(config core.Config) error {
-	// 验证max_steps
-	if val := config.GetValue("max_steps"); val != nil {
-		switch v := val.(type) {
-		case int:
-			if v <= 0 {
-				return fmt.Errorf("max_steps must be positive, got %d", v)
-			}
-		case string:
-			// 尝试解析字符串
-			// 这里可以添加更严格的验证
-		default:
-			return fmt.Errorf("max_steps must be int or string, got %T", v)
-		}
-	}
-
-	return nil
+	_, err := parseConfig(config)
+	return err
 }
@@ -0,0 +1,413 @@
+package game2048
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jelech/rl_env_engine/core"
+	"github.com/jelech/rl_env_engine/core/config"
+)
+
+// 滑动方向
+const (
+	MoveUp = iota
+	MoveRight
+	MoveDown
+	MoveLeft
+	numMoves
+)
+
+var moveNames = [numMoves]string{"up", "right", "down", "left"}
+
+// Config 2048环境配置
+type Config struct {
+	MaxSteps           int     `cfg:"max_steps,default=5000,min=1"`
+	Size               int     `cfg:"size,default=4,min=2,max=8"`
+	FourProbability    float64 `cfg:"four_probability,default=0.1,min=0,max=1"` // 新方块为4的概率
+	InvalidMovePenalty float64 `cfg:"invalid_move_penalty,default=1.0,min=0"`   // 非法移动（棋盘不变）的惩罚
+	Seed               int64   `cfg:"seed"`
+}
+
+// DefaultConfig 返回默认配置
+func DefaultConfig() Config {
+	var cfg Config
+	config.MustDefaults(&cfg)
+	return cfg
+}
+
+// Validate 按cfg标签中的约束验证配置，用于校验直接构造的配置
+func (c Config) Validate() error {
+	return config.Validate(c)
+}
+
+// parseConfig 将core.Config绑定为2048配置，未提供的字段使用默认值
+func parseConfig(src core.Config) (Config, error) {
+	var cfg Config
+	if err := config.Bind(src, &cfg); err != nil {
+		return cfg, err
+	}
+	return cfg, cfg.Validate()
+}
+
+// Game2048Environment 2048滑块游戏环境
+// 动作为滑动方向（0: 上, 1: 右, 2: 下, 3: 左），合并后在随机空格生成2或4；
+// 奖励为 log2(1 + 本步合并得分)，非法移动不改变棋盘并给予惩罚。
+// 观察为每个格子的 log2(方块值)（空格为0），合法动作掩码见 GetActionMask
+type Game2048Environment struct {
+	*core.BaseEnvironment
+	cfg Config
+
+	board       []int // 行优先存储的方块值，0表示空格
+	score       int
+	currentStep int
+	lastReward  float64
+	lastMove    int
+	invalid     int // 本episode非法移动次数
+
+	rng *rand.Rand
+
+	obsBuf []float64 // GetObservations复用的观察数据缓冲区
+}
+
+// 确保Game2048Environment实现了可选的掩码和渲染接口
+var (
+	_ core.ActionMasker = (*Game2048Environment)(nil)
+	_ core.Renderer     = (*Game2048Environment)(nil)
+)
+
+// NewGame2048Environment 创建新的2048环境
+func NewGame2048Environment(config core.Config) (*Game2048Environment, error) {
+	cfg, err := parseConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	baseEnv := core.NewBaseEnvironment("2048", "2048 sliding tile puzzle", config)
+
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	return &Game2048Environment{
+		BaseEnvironment: baseEnv,
+		cfg:             cfg,
+		board:           make([]int, cfg.Size*cfg.Size),
+		lastMove:        -1,
+		rng:             rand.New(rand.NewSource(seed)),
+	}, nil
+}
+
+// Reset 重置环境：清空棋盘并生成两个初始方块
+func (e *Game2048Environment) Reset(ctx context.Context) ([]core.Observation, error) {
+	for i := range e.board {
+		e.board[i] = 0
+	}
+	e.score = 0
+	e.currentStep = 0
+	e.lastReward = 0
+	e.lastMove = -1
+	e.invalid = 0
+	e.spawnTile()
+	e.spawnTile()
+
+	return e.GetObservations(), nil
+}
+
+// Step 执行一步滑动
+func (e *Game2048Environment) Step(ctx context.Context, actions []core.Action) ([]core.Observation, []float64, []bool, error) {
+	if len(actions) == 0 {
+		return nil, nil, nil, fmt.Errorf("%w: no actions provided", core.ErrInvalidAction)
+	}
+
+	var move int
+	if genericAction, ok := actions[0].(*core.GenericAction); ok {
+		value, err := genericAction.GetInt64()
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("%w: failed to extract action value: %w", core.ErrInvalidAction, err)
+		}
+		move = int(value)
+	} else if gameAction, ok := actions[0].(*Game2048Action); ok {
+		move = gameAction.Move
+	} else {
+		return nil, nil, nil, fmt.Errorf("%w: unsupported action type: %T", core.ErrInvalidAction, actions[0])
+	}
+	if move < 0 || move >= numMoves {
+		return nil, nil, nil, fmt.Errorf("%w: move must be in [0, %d), got %d", core.ErrInvalidAction, numMoves, move)
+	}
+
+	e.currentStep++
+	e.lastMove = move
+
+	next, gained, changed := e.slide(e.board, move)
+	if changed {
+		e.board = next
+		e.score += gained
+		e.spawnTile()
+		e.lastReward = math.Log2(1 + float64(gained))
+	} else {
+		e.invalid++
+		e.lastReward = -e.cfg.InvalidMovePenalty
+	}
+
+	done := !e.hasValidMove() || e.currentStep >= e.cfg.MaxSteps
+
+	return e.GetObservations(), []float64{e.lastReward}, []bool{done}, nil
+}
+
+// slide 计算向给定方向滑动后的棋盘，返回新棋盘、合并得分以及棋盘是否变化
+func (e *Game2048Environment) slide(board []int, move int) ([]int, int, bool) {
+	n := e.cfg.Size
+	next := make([]int, len(board))
+	copy(next, board)
+
+	gained := 0
+	line := make([]int, n)
+	for k := 0; k < n; k++ {
+		// 按滑动方向取出一行/列，索引0为滑动目标端
+		for i := 0; i < n; i++ {
+			line[i] = next[e.cellIndex(move, k, i)]
+		}
+		gained += mergeLine(line)
+		for i := 0; i < n; i++ {
+			next[e.cellIndex(move, k, i)] = line[i]
+		}
+	}
+
+	changed := false
+	for i := range board {
+		if board[i] != next[i] {
+			changed = true
+			break
+		}
+	}
+	return next, gained, changed
+}
+
+// cellIndex 返回第k条线上第i个格子在棋盘中的索引，i=0为滑动目标端
+func (e *Game2048Environment) cellIndex(move, k, i int) int {
+	n := e.cfg.Size
+	switch move {
+	case MoveUp:
+		return i*n + k
+	case MoveDown:
+		return (n-1-i)*n + k
+	case MoveLeft:
+		return k*n + i
+	default: // MoveRight
+		return k*n + (n - 1 - i)
+	}
+}
+
+// mergeLine 将一条线向索引0方向压缩并合并（每个方块每步最多合并一次），返回合并得分
+func mergeLine(line []int) int {
+	tiles := make([]int, 0, len(line))
+	for _, v := range line {
+		if v != 0 {
+			tiles = append(tiles, v)
+		}
+	}
+
+	gained := 0
+	n := len(line)
+	out := line[:0]
+	for i := 0; i < len(tiles); i++ {
+		if i+1 < len(tiles) && tiles[i] == tiles[i+1] {
+			merged := tiles[i] * 2
+			gained += merged
+			out = append(out, merged)
+			i++
+		} else {
+			out = append(out, tiles[i])
+		}
+	}
+	for len(out) < n {
+		out = append(out, 0)
+	}
+	return gained
+}
+
+// spawnTile 在随机空格生成新方块
+func (e *Game2048Environment) spawnTile() {
+	var empty []int
+	for i, v := range e.board {
+		if v == 0 {
+			empty = append(empty, i)
+		}
+	}
+	if len(empty) == 0 {
+		return
+	}
+	value := 2
+	if e.rng.Float64() < e.cfg.FourProbability {
+		value = 4
+	}
+	e.board[empty[e.rng.Intn(len(empty))]] = value
+}
+
+// hasValidMove 判断是否还有能改变棋盘的移动
+func (e *Game2048Environment) hasValidMove() bool {
+	for _, ok := range e.GetActionMask() {
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// GetActionMask 返回当前合法动作掩码（能改变棋盘的方向为true）
+func (e *Game2048Environment) GetActionMask() []bool {
+	mask := make([]bool, numMoves)
+	for move := 0; move < numMoves; move++ {
+		_, _, changed := e.slide(e.board, move)
+		mask[move] = changed
+	}
+	return mask
+}
+
+// maxTile 返回棋盘上的最大方块
+func (e *Game2048Environment) maxTile() int {
+	best := 0
+	for _, v := range e.board {
+		if v > best {
+			best = v
+		}
+	}
+	return best
+}
+
+// GetObservations 获取当前观察
+func (e *Game2048Environment) GetObservations() []core.Observation {
+	data := core.ResetBuffer(&e.obsBuf, len(e.board))
+	for i, v := range e.board {
+		if v > 0 {
+			data[i] = math.Log2(float64(v))
+		}
+	}
+
+	var metadata map[string]interface{}
+	if e.ObservationMetadataEnabled() {
+		mask := e.GetActionMask()
+		maskValues := make([]interface{}, len(mask))
+		for i, ok := range mask {
+			maskValues[i] = ok
+		}
+		metadata = map[string]interface{}{
+			"score":         e.score,
+			"max_tile":      e.maxTile(),
+			"action_mask":   maskValues,
+			"invalid_moves": e.invalid,
+			"step":          e.currentStep,
+			"max_steps":     e.cfg.MaxSteps,
+		}
+	}
+
+	observation := e.AcquireObservation(data, metadata)
+	return []core.Observation{observation}
+}
+
+// GetReward 返回最近一步的奖励
+func (e *Game2048Environment) GetReward() []float64 {
+	return []float64{e.lastReward}
+}
+
+// Render 将棋盘渲染为文本
+func (e *Game2048Environment) Render() string {
+	n := e.cfg.Size
+	width := len(strconv.Itoa(e.maxTile()))
+	if width < 4 {
+		width = 4
+	}
+	border := "+" + strings.Repeat(strings.Repeat("-", width+2)+"+", n) + "\n"
+
+	var sb strings.Builder
+	last := "none"
+	if e.lastMove >= 0 {
+		last = moveNames[e.lastMove]
+	}
+	fmt.Fprintf(&sb, "score: %d  step: %d  last move: %s\n", e.score, e.currentStep, last)
+	sb.WriteString(border)
+	for r := 0; r < n; r++ {
+		sb.WriteString("|")
+		for c := 0; c < n; c++ {
+			cell := "."
+			if v := e.board[r*n+c]; v > 0 {
+				cell = strconv.Itoa(v)
+			}
+			fmt.Fprintf(&sb, " %*s |", width, cell)
+		}
+		sb.WriteString("\n")
+		sb.WriteString(border)
+	}
+	return sb.String()
+}
+
+// Close 关闭环境
+func (e *Game2048Environment) Close() error {
+	e.board = nil
+	return e.BaseEnvironment.Close()
+}
+
+// Metadata 返回环境元数据：合并得分取对数后无上界，非法移动时为负惩罚
+func (e *Game2048Environment) Metadata() core.EnvMetadata {
+	return core.EnvMetadata{
+		RewardRange:     [2]float64{-e.cfg.InvalidMovePenalty, math.Inf(1)},
+		MaxEpisodeSteps: e.cfg.MaxSteps,
+	}
+}
+
+// GetSpaces 获取2048场景的动作空间和观察空间定义
+func (e *Game2048Environment) GetSpaces() core.SpaceDefinition {
+	cells := e.cfg.Size * e.cfg.Size
+	low := make([]float64, cells)
+	high := make([]float64, cells)
+	for i := range high {
+		// 理论上的最大方块为 2^(cells+1)
+		high[i] = float64(cells + 1)
+	}
+
+	return core.SpaceDefinition{
+		ActionSpace: core.ActionSpace{
+			Type:  core.SpaceTypeDiscrete,
+			Low:   []float64{0},
+			High:  []float64{numMoves - 1}, // 0: 上, 1: 右, 2: 下, 3: 左
+			Shape: []int32{},
+			Dtype: "int32",
+		},
+		ObservationSpace: core.ObservationSpace{
+			Type:  core.SpaceTypeBox,
+			Low:   low,
+			High:  high,
+			Shape: []int32{int32(cells)},
+			Dtype: "float32",
+		},
+	}
+}
+
+// Game2048Action 2048专用动作
+type Game2048Action struct {
+	Move int // 0: 上, 1: 右, 2: 下, 3: 左
+}
+
+// NewGame2048Action 创建新的2048动作
+func NewGame2048Action(move int) *Game2048Action {
+	return &Game2048Action{Move: move}
+}
+
+// GetData 获取动作数据
+func (a *Game2048Action) GetData() interface{} {
+	return a.Move
+}
+
+// Validate 验证动作
+func (a *Game2048Action) Validate() error {
+	if a.Move < 0 || a.Move >= numMoves {
+		return fmt.Errorf("2048 move must be in [0, %d), got %d", numMoves, a.Move)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package game2048
+
+import (
+	"fmt"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// Game2048Scenario 2048场景实现
+type Game2048Scenario struct {
+	name        string
+	description string
+}
+
+// 确保Game2048Scenario实现了core.Scenario接口
+var _ core.Scenario = (*Game2048Scenario)(nil)
+
+// NewGame2048Scenario 创建新的2048场景
+func NewGame2048Scenario() *Game2048Scenario {
+	return &Game2048Scenario{
+		name:        "2048",
+		description: "2048 sliding tile puzzle with invalid-move masking and log-score reward",
+	}
+}
+
+// GetName 获取场景名称
+func (s *Game2048Scenario) GetName() string {
+	return s.name
+}
+
+// GetDescription 获取场景描述
+func (s *Game2048Scenario) GetDescription() string {
+	return s.description
+}
+
+// CreateEnvironment 创建环境实例
+func (s *Game2048Scenario) CreateEnvironment(config core.Config) (core.Environment, error) {
+	env, err := NewGame2048Environment(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create 2048 environment: %w", err)
+	}
+	return env, nil
+}
+
+// ValidateConfig 验证配置
+func (s *Game2048Scenario) ValidateConfig(config core.Config) error {
+	if config == nil {
+		return fmt.Errorf("config cannot be nil")
+	}
+	_, err := parseConfig(config)
+	return err
+}
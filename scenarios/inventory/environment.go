@@ -0,0 +1,388 @@
+package inventory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/jelech/rl_env_engine/core"
+	"github.com/jelech/rl_env_engine/core/config"
+)
+
+// Config 库存补货环境配置
+type Config struct {
+	MaxSteps     int       `cfg:"max_steps,default=200,min=1"`
+	NumProducts  int       `cfg:"num_products,default=3,min=1"`
+	MaxOrder     int       `cfg:"max_order,default=5,min=1"`       // 每个商品每步最多订购的数量，动作各维取值为[0, max_order]
+	Capacity     int       `cfg:"capacity,default=20,min=1"`       // 每个商品的库存上限，超出的到货被丢弃
+	LeadTime     int       `cfg:"lead_time,default=2,min=0"`       // 订单到货所需的步数，0表示当步到货
+	DemandRates  []float64 `cfg:"demand_rates"`                    // 各商品每步的平均需求（泊松），为空时自动生成
+	HoldingCost  float64   `cfg:"holding_cost,default=0.1,min=0"`  // 每件库存每步的持有成本
+	StockoutCost float64   `cfg:"stockout_cost,default=1.0,min=0"` // 每件未满足需求的缺货成本
+	OrderCost    float64   `cfg:"order_cost,default=0.5,min=0"`    // 每个商品下单一次的固定成本
+	Seed         int64     `cfg:"seed"`
+}
+
+// DefaultConfig 返回默认配置
+func DefaultConfig() Config {
+	var cfg Config
+	config.MustDefaults(&cfg)
+	return cfg
+}
+
+// Validate 验证配置：cfg标签中的约束以及标签无法表达的字段间约束
+func (c Config) Validate() error {
+	if err := config.Validate(c); err != nil {
+		return err
+	}
+	if len(c.DemandRates) != 0 && len(c.DemandRates) != c.NumProducts {
+		return fmt.Errorf("demand_rates must have num_products (%d) entries, got %d", c.NumProducts, len(c.DemandRates))
+	}
+	for i, r := range c.DemandRates {
+		if r < 0 {
+			return fmt.Errorf("demand_rates[%d] must be non-negative, got %f", i, r)
+		}
+	}
+	return nil
+}
+
+// parseConfig 将core.Config绑定为库存补货配置，未提供的字段使用默认值
+func parseConfig(src core.Config) (Config, error) {
+	var cfg Config
+	if err := config.Bind(src, &cfg); err != nil {
+		return cfg, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return cfg, err
+	}
+
+	// 默认需求：在 [1, 3] 上均匀分布
+	if len(cfg.DemandRates) == 0 {
+		cfg.DemandRates = make([]float64, cfg.NumProducts)
+		for i := range cfg.DemandRates {
+			if cfg.NumProducts == 1 {
+				cfg.DemandRates[i] = 2.0
+			} else {
+				cfg.DemandRates[i] = 1.0 + 2.0*float64(i)/float64(cfg.NumProducts-1)
+			}
+		}
+	}
+	return cfg, nil
+}
+
+// InventoryEnvironment 多商品库存补货环境
+// 单智能体，动作为MultiDiscrete：每个商品一维，取值为本步订购的数量（0表示不订购）。
+// 每一步先入库到货，再下单（lead_time步后到货），最后按泊松需求销售，
+// 奖励为负的总成本（持有成本 + 缺货成本 + 下单固定成本）
+type InventoryEnvironment struct {
+	*core.BaseEnvironment
+	cfg Config
+
+	stock       []int
+	pipeline    [][]int // pipeline[t][i]为t步后到货的商品i的数量
+	lastDemand  []int
+	lastLost    []int
+	lastCost    float64
+	totalCost   float64
+	currentStep int
+
+	actionSpace core.ActionSpace
+
+	rng *rand.Rand
+	src *core.RandSource
+
+	obsBuf []float64 // GetObservations复用的观察数据缓冲区
+}
+
+// NewInventoryEnvironment 创建新的库存补货环境
+func NewInventoryEnvironment(config core.Config) (*InventoryEnvironment, error) {
+	cfg, err := parseConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	baseEnv := core.NewBaseEnvironment("inventory", "Multi-product inventory replenishment environment", config)
+
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	src := core.NewRandSource(seed)
+
+	k := cfg.NumProducts
+	high := make([]float64, k)
+	for i := range high {
+		high[i] = float64(cfg.MaxOrder)
+	}
+	env := &InventoryEnvironment{
+		BaseEnvironment: baseEnv,
+		cfg:             cfg,
+		stock:           make([]int, k),
+		pipeline:        make([][]int, cfg.LeadTime),
+		lastDemand:      make([]int, k),
+		lastLost:        make([]int, k),
+		actionSpace: core.ActionSpace{
+			Type:  core.SpaceTypeMultiDiscrete,
+			Low:   make([]float64, k),
+			High:  high,
+			Shape: []int32{int32(k)},
+			Dtype: "int64",
+		},
+		rng: rand.New(src),
+		src: src,
+	}
+	for t := range env.pipeline {
+		env.pipeline[t] = make([]int, k)
+	}
+	return env, nil
+}
+
+// Reset 重置环境：库存为上限的一半，清空在途订单
+func (e *InventoryEnvironment) Reset(ctx context.Context) ([]core.Observation, error) {
+	for i := range e.stock {
+		e.stock[i] = e.cfg.Capacity / 2
+		e.lastDemand[i] = 0
+		e.lastLost[i] = 0
+	}
+	for _, arrivals := range e.pipeline {
+		clear(arrivals)
+	}
+	e.lastCost = 0
+	e.totalCost = 0
+	e.currentStep = 0
+
+	return e.GetObservations(), nil
+}
+
+// Step 执行一步：入库到货、下单、销售，并计算成本
+func (e *InventoryEnvironment) Step(ctx context.Context, actions []core.Action) ([]core.Observation, []float64, []bool, error) {
+	if len(actions) == 0 {
+		return nil, nil, nil, fmt.Errorf("%w: no actions provided", core.ErrInvalidAction)
+	}
+	orders, err := e.actionSpace.MultiDiscreteValues(actions[0])
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("%w: %w", core.ErrInvalidAction, err)
+	}
+
+	// 入库本步到货的订单
+	if len(e.pipeline) > 0 {
+		arrivals := e.pipeline[0]
+		for i, n := range arrivals {
+			e.stock[i] = min(e.stock[i]+n, e.cfg.Capacity)
+		}
+		copy(e.pipeline, e.pipeline[1:])
+		clear(arrivals)
+		e.pipeline[len(e.pipeline)-1] = arrivals
+	}
+
+	cost := 0.0
+	for i, n := range orders {
+		if n == 0 {
+			continue
+		}
+		cost += e.cfg.OrderCost
+		if len(e.pipeline) == 0 {
+			e.stock[i] = min(e.stock[i]+n, e.cfg.Capacity)
+		} else {
+			e.pipeline[len(e.pipeline)-1][i] += n
+		}
+	}
+
+	for i, rate := range e.cfg.DemandRates {
+		demand := e.poisson(rate)
+		sold := min(demand, e.stock[i])
+		e.stock[i] -= sold
+		e.lastDemand[i] = demand
+		e.lastLost[i] = demand - sold
+		cost += e.cfg.HoldingCost*float64(e.stock[i]) + e.cfg.StockoutCost*float64(e.lastLost[i])
+	}
+
+	e.lastCost = cost
+	e.totalCost += cost
+	e.currentStep++
+	done := e.currentStep >= e.cfg.MaxSteps
+
+	return e.GetObservations(), []float64{-cost}, []bool{done}, nil
+}
+
+// poisson 按泊松分布采样需求
+func (e *InventoryEnvironment) poisson(lambda float64) int {
+	if lambda <= 0 {
+		return 0
+	}
+	limit := math.Exp(-lambda)
+	k, p := 0, 1.0
+	for {
+		p *= e.rng.Float64()
+		if p <= limit {
+			return k
+		}
+		k++
+	}
+}
+
+// inTransit 返回商品i的在途数量
+func (e *InventoryEnvironment) inTransit(i int) int {
+	n := 0
+	for _, arrivals := range e.pipeline {
+		n += arrivals[i]
+	}
+	return n
+}
+
+// GetObservations 获取当前观察
+// [各商品库存..., 各商品在途数量..., 各商品上一步需求...]
+func (e *InventoryEnvironment) GetObservations() []core.Observation {
+	k := e.cfg.NumProducts
+	data := core.ResetBuffer(&e.obsBuf, 3*k)
+	for i := 0; i < k; i++ {
+		data[i] = float64(e.stock[i])
+		data[k+i] = float64(e.inTransit(i))
+		data[2*k+i] = float64(e.lastDemand[i])
+	}
+
+	var metadata map[string]interface{}
+	if e.ObservationMetadataEnabled() {
+		metadata = map[string]interface{}{
+			"last_cost":  e.lastCost,
+			"total_cost": e.totalCost,
+			"step":       e.currentStep,
+			"max_steps":  e.cfg.MaxSteps,
+		}
+	}
+
+	observation := e.AcquireObservation(data, metadata)
+	return []core.Observation{observation}
+}
+
+// GetReward 返回上一步的负成本
+func (e *InventoryEnvironment) GetReward() []float64 {
+	return []float64{-e.lastCost}
+}
+
+// Close 关闭环境
+func (e *InventoryEnvironment) Close() error {
+	e.pipeline = nil
+	return e.BaseEnvironment.Close()
+}
+
+// Metadata 返回环境元数据：单步奖励不超过0，下界无界
+func (e *InventoryEnvironment) Metadata() core.EnvMetadata {
+	metadata := core.DefaultEnvMetadata()
+	metadata.RewardRange[1] = 0
+	metadata.MaxEpisodeSteps = e.cfg.MaxSteps
+	return metadata
+}
+
+// DumpState 导出各商品的库存、在途订单与上一步的需求和缺货
+func (e *InventoryEnvironment) DumpState() map[string]interface{} {
+	return map[string]interface{}{
+		"stock":        e.stock,
+		"pipeline":     e.pipeline,
+		"last_demand":  e.lastDemand,
+		"last_lost":    e.lastLost,
+		"demand_rates": e.cfg.DemandRates,
+		"total_cost":   e.totalCost,
+		"step":         e.currentStep,
+		"max_steps":    e.cfg.MaxSteps,
+	}
+}
+
+// checkpoint 库存补货环境的检查点
+type checkpoint struct {
+	Stock      []int          `json:"stock"`
+	Pipeline   [][]int        `json:"pipeline"`
+	LastDemand []int          `json:"last_demand"`
+	LastLost   []int          `json:"last_lost"`
+	LastCost   float64        `json:"last_cost"`
+	TotalCost  float64        `json:"total_cost"`
+	Step       int            `json:"step"`
+	Rand       core.RandState `json:"rand"`
+}
+
+// Checkpoint 实现core.Checkpointer
+func (e *InventoryEnvironment) Checkpoint() ([]byte, error) {
+	return json.Marshal(checkpoint{
+		Stock:      e.stock,
+		Pipeline:   e.pipeline,
+		LastDemand: e.lastDemand,
+		LastLost:   e.lastLost,
+		LastCost:   e.lastCost,
+		TotalCost:  e.totalCost,
+		Step:       e.currentStep,
+		Rand:       e.src.State(),
+	})
+}
+
+// RestoreCheckpoint 实现core.Checkpointer
+func (e *InventoryEnvironment) RestoreCheckpoint(data []byte) error {
+	var cp checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return fmt.Errorf("invalid inventory checkpoint: %w", err)
+	}
+	k := e.cfg.NumProducts
+	if len(cp.Stock) != k || len(cp.LastDemand) != k || len(cp.LastLost) != k || len(cp.Pipeline) != e.cfg.LeadTime {
+		return fmt.Errorf("invalid inventory checkpoint: shape does not match %d products with lead time %d", k, e.cfg.LeadTime)
+	}
+	for _, arrivals := range cp.Pipeline {
+		if len(arrivals) != k {
+			return fmt.Errorf("invalid inventory checkpoint: pipeline entry has %d products, expected %d", len(arrivals), k)
+		}
+	}
+	e.stock, e.pipeline, e.lastDemand, e.lastLost = cp.Stock, cp.Pipeline, cp.LastDemand, cp.LastLost
+	e.lastCost, e.totalCost = cp.LastCost, cp.TotalCost
+	e.currentStep = cp.Step
+	e.src.Restore(cp.Rand)
+	return nil
+}
+
+// GetSpaces 获取库存补货场景的动作空间和观察空间定义
+func (e *InventoryEnvironment) GetSpaces() core.SpaceDefinition {
+	k := e.cfg.NumProducts
+	low := make([]float64, 3*k)
+	high := make([]float64, 3*k)
+	for i := 0; i < k; i++ {
+		high[i] = float64(e.cfg.Capacity)
+		high[k+i] = float64(e.cfg.MaxOrder * e.cfg.LeadTime)
+		high[2*k+i] = math.Inf(1)
+	}
+
+	return core.SpaceDefinition{
+		ActionSpace: e.actionSpace,
+		ObservationSpace: core.ObservationSpace{
+			Type:  core.SpaceTypeBox,
+			Low:   low,
+			High:  high,
+			Shape: []int32{int32(3 * k)},
+			Dtype: "float32",
+		},
+	}
+}
+
+// InventoryAction 库存补货专用动作
+type InventoryAction struct {
+	Orders []int // 各商品的订购数量
+}
+
+// NewInventoryAction 创建新的库存补货动作
+func NewInventoryAction(orders ...int) *InventoryAction {
+	return &InventoryAction{Orders: orders}
+}
+
+// GetData 获取动作数据
+func (a *InventoryAction) GetData() interface{} {
+	return a.Orders
+}
+
+// Validate 验证动作
+func (a *InventoryAction) Validate() error {
+	for i, n := range a.Orders {
+		if n < 0 {
+			return fmt.Errorf("inventory order %d must be non-negative, got %d", i, n)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package inventory
+
+import (
+	"fmt"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// InventoryScenario 多商品库存补货场景实现
+type InventoryScenario struct {
+	name        string
+	description string
+}
+
+// 确保InventoryScenario实现了core.Scenario接口
+var _ core.Scenario = (*InventoryScenario)(nil)
+
+// NewInventoryScenario 创建新的库存补货场景
+func NewInventoryScenario() *InventoryScenario {
+	return &InventoryScenario{
+		name:        "inventory",
+		description: "Multi-product inventory replenishment - choose an order quantity per product (MultiDiscrete actions)",
+	}
+}
+
+// GetName 获取场景名称
+func (s *InventoryScenario) GetName() string {
+	return s.name
+}
+
+// GetDescription 获取场景描述
+func (s *InventoryScenario) GetDescription() string {
+	return s.description
+}
+
+// CreateEnvironment 创建环境实例
+func (s *InventoryScenario) CreateEnvironment(config core.Config) (core.Environment, error) {
+	env, err := NewInventoryEnvironment(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create inventory environment: %w", err)
+	}
+	return env, nil
+}
+
+// ValidateConfig 验证配置
+func (s *InventoryScenario) ValidateConfig(config core.Config) error {
+	if config == nil {
+		return fmt.Errorf("config cannot be nil")
+	}
+	_, err := parseConfig(config)
+	return err
+}
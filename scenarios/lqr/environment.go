@@ -0,0 +1,349 @@
+package lqr
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/jelech/rl_env_engine/core"
+	"github.com/jelech/rl_env_engine/core/config"
+)
+
+// Config 线性系统环境配置
+// 未提供A/B时，按 state_dim/action_dim 构造离散化的积分链：
+// A = I + dt*上移位矩阵，B的最后action_dim行为 dt*I，即控制量作用在链末端
+type Config struct {
+	MaxSteps    int     `cfg:"max_steps,default=200,min=1"`
+	StateDim    int     `cfg:"state_dim,default=2,min=1"`
+	ActionDim   int     `cfg:"action_dim,default=1,min=1"`
+	Dt          float64 `cfg:"dt,default=0.1"` // 仅用于默认积分链
+	A           Matrix  `cfg:"a"`
+	B           Matrix  `cfg:"b"`
+	Q           Matrix  `cfg:"q"`                          // 状态代价矩阵，默认单位阵
+	R           Matrix  `cfg:"r"`                          // 控制代价矩阵，默认单位阵
+	NoiseStd    float64 `cfg:"noise_std,min=0"`            // 过程噪声w的标准差
+	InitStd     float64 `cfg:"init_std,default=1.0,min=0"` // 初始状态的标准差
+	ActionLimit float64 `cfg:"action_limit,min=0"`         // 动作每维的绝对值上限，0表示不限制
+	StateLimit  float64 `cfg:"state_limit,min=0"`          // 状态任一维绝对值超过该值时终止，0表示不限制
+	Seed        int64   `cfg:"seed"`
+}
+
+// DefaultConfig 返回默认配置
+func DefaultConfig() Config {
+	var cfg Config
+	config.MustDefaults(&cfg)
+	return cfg
+}
+
+// fillDefaults 根据维度补全未提供的矩阵
+func (c *Config) fillDefaults() {
+	if len(c.A) > 0 {
+		c.StateDim = len(c.A)
+	}
+	if len(c.B) > 0 && len(c.B[0]) > 0 {
+		c.ActionDim = len(c.B[0])
+	}
+	n, m := c.StateDim, c.ActionDim
+	if n <= 0 || m <= 0 || m > n {
+		return // 由Validate报告
+	}
+
+	if len(c.A) == 0 {
+		c.A = Identity(n)
+		for i := 0; i+1 < n; i++ {
+			c.A[i][i+1] = c.Dt
+		}
+	}
+	if len(c.B) == 0 {
+		c.B = newMatrix(n, m)
+		for i := 0; i < m; i++ {
+			c.B[n-m+i][i] = c.Dt
+		}
+	}
+	if len(c.Q) == 0 {
+		c.Q = Identity(n)
+	}
+	if len(c.R) == 0 {
+		c.R = Identity(m)
+	}
+}
+
+// Validate 验证配置：cfg标签中的约束以及标签无法表达的字段间约束
+func (c Config) Validate() error {
+	if err := config.Validate(c); err != nil {
+		return err
+	}
+	if c.ActionDim > c.StateDim && (len(c.A) == 0 || len(c.B) == 0) {
+		return fmt.Errorf("action_dim (%d) cannot exceed state_dim (%d) for the default system", c.ActionDim, c.StateDim)
+	}
+	n, m := c.StateDim, c.ActionDim
+	if err := c.A.checkShape("a", n, n); err != nil {
+		return err
+	}
+	if err := c.B.checkShape("b", n, m); err != nil {
+		return err
+	}
+	if err := c.Q.checkShape("q", n, n); err != nil {
+		return err
+	}
+	return c.R.checkShape("r", m, m)
+}
+
+// parseConfig 将core.Config绑定为线性系统配置，未提供的字段使用默认值，并按维度补全矩阵
+func parseConfig(src core.Config) (Config, error) {
+	var cfg Config
+	if err := config.Bind(src, &cfg); err != nil {
+		return cfg, err
+	}
+	cfg.fillDefaults()
+	return cfg, cfg.Validate()
+}
+
+// LQREnvironment 参数化线性系统环境
+// 动力学为 x' = Ax + Bu + w，w ~ N(0, noise_std^2 I)，奖励为 -(x^T Q x + u^T R u)。
+// 创建时求解离散代数Riccati方程，观察元数据中给出最优动作 -Kx 与最优代价 x^T P x，便于验证策略
+type LQREnvironment struct {
+	*core.BaseEnvironment
+	cfg Config
+
+	gain   Matrix // 最优反馈增益K，求解失败时为nil
+	cost   Matrix // Riccati方程的解P
+	lqrErr error
+
+	state       []float64
+	currentStep int
+	lastReward  float64
+	totalCost   float64
+
+	rng *rand.Rand
+
+	obsBuf []float64 // GetObservations复用的观察数据缓冲区
+}
+
+// NewLQREnvironment 创建新的线性系统环境
+func NewLQREnvironment(config core.Config) (*LQREnvironment, error) {
+	cfg, err := parseConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	baseEnv := core.NewBaseEnvironment("lqr", "Linear quadratic regulator environment", config)
+
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	env := &LQREnvironment{
+		BaseEnvironment: baseEnv,
+		cfg:             cfg,
+		state:           make([]float64, cfg.StateDim),
+		rng:             rand.New(rand.NewSource(seed)),
+	}
+	// 系统不可镇定时环境仍可使用，只是不提供最优解
+	env.gain, env.cost, env.lqrErr = SolveDARE(cfg.A, cfg.B, cfg.Q, cfg.R)
+	return env, nil
+}
+
+// OptimalGain 返回无限时域LQR最优反馈增益K（u = -Kx）
+func (e *LQREnvironment) OptimalGain() (Matrix, error) {
+	return e.gain, e.lqrErr
+}
+
+// OptimalAction 返回当前状态下的最优动作 -Kx
+func (e *LQREnvironment) OptimalAction() ([]float64, error) {
+	if e.lqrErr != nil {
+		return nil, e.lqrErr
+	}
+	u := e.gain.MulVec(e.state)
+	for i := range u {
+		u[i] = -u[i]
+	}
+	return u, nil
+}
+
+// Reset 重置环境：从 N(0, init_std^2 I) 采样初始状态
+func (e *LQREnvironment) Reset(ctx context.Context) ([]core.Observation, error) {
+	for i := range e.state {
+		e.state[i] = e.rng.NormFloat64() * e.cfg.InitStd
+	}
+	e.currentStep = 0
+	e.lastReward = 0
+	e.totalCost = 0
+
+	return e.GetObservations(), nil
+}
+
+// Step 执行一步仿真
+func (e *LQREnvironment) Step(ctx context.Context, actions []core.Action) ([]core.Observation, []float64, []bool, error) {
+	if len(actions) == 0 {
+		return nil, nil, nil, fmt.Errorf("%w: no actions provided", core.ErrInvalidAction)
+	}
+
+	var u []float64
+	if genericAction, ok := actions[0].(*core.GenericAction); ok {
+		if values, err := genericAction.GetFloat64Slice(); err == nil {
+			u = values
+		} else if value, err := genericAction.GetFloat64(); err == nil {
+			u = []float64{value}
+		} else {
+			return nil, nil, nil, fmt.Errorf("%w: failed to extract action values: %w", core.ErrInvalidAction, err)
+		}
+	} else if lqrAction, ok := actions[0].(*LQRAction); ok {
+		u = lqrAction.Control
+	} else {
+		return nil, nil, nil, fmt.Errorf("%w: unsupported action type: %T", core.ErrInvalidAction, actions[0])
+	}
+	if len(u) != e.cfg.ActionDim {
+		return nil, nil, nil, fmt.Errorf("%w: action must have %d dimensions, got %d", core.ErrInvalidAction, e.cfg.ActionDim, len(u))
+	}
+
+	if limit := e.cfg.ActionLimit; limit > 0 {
+		clipped := make([]float64, len(u))
+		for i, v := range u {
+			clipped[i] = math.Max(-limit, math.Min(limit, v))
+		}
+		u = clipped
+	}
+
+	stepCost := quadForm(e.cfg.Q, e.state) + quadForm(e.cfg.R, u)
+
+	next := e.cfg.A.MulVec(e.state)
+	bu := e.cfg.B.MulVec(u)
+	for i := range next {
+		next[i] += bu[i] + e.rng.NormFloat64()*e.cfg.NoiseStd
+	}
+	e.state = next
+
+	e.currentStep++
+	e.lastReward = -stepCost
+	e.totalCost += stepCost
+
+	done := e.currentStep >= e.cfg.MaxSteps
+	if limit := e.cfg.StateLimit; limit > 0 {
+		for _, v := range e.state {
+			if math.Abs(v) > limit {
+				done = true
+				break
+			}
+		}
+	}
+
+	return e.GetObservations(), []float64{e.lastReward}, []bool{done}, nil
+}
+
+// GetObservations 获取当前观察（完整状态x）
+func (e *LQREnvironment) GetObservations() []core.Observation {
+	data := core.ResetBuffer(&e.obsBuf, len(e.state))
+	copy(data, e.state)
+
+	var metadata map[string]interface{}
+	if e.ObservationMetadataEnabled() {
+		metadata = map[string]interface{}{
+			"total_cost": e.totalCost,
+			"step":       e.currentStep,
+			"max_steps":  e.cfg.MaxSteps,
+		}
+		if u, err := e.OptimalAction(); err == nil {
+			values := make([]interface{}, len(u))
+			for i, v := range u {
+				values[i] = v
+			}
+			metadata["optimal_action"] = values
+			metadata["optimal_cost_to_go"] = quadForm(e.cost, e.state)
+		}
+	}
+
+	observation := e.AcquireObservation(data, metadata)
+	return []core.Observation{observation}
+}
+
+// GetReward 返回最近一步的奖励
+func (e *LQREnvironment) GetReward() []float64 {
+	return []float64{e.lastReward}
+}
+
+// GetInfo 获取环境信息
+func (e *LQREnvironment) GetInfo() map[string]interface{} {
+	info := e.BaseEnvironment.GetInfo()
+	info["state_dim"] = e.cfg.StateDim
+	info["action_dim"] = e.cfg.ActionDim
+	if e.lqrErr != nil {
+		info["lqr_error"] = e.lqrErr.Error()
+	} else {
+		rows := make([]interface{}, len(e.gain))
+		for i, row := range e.gain {
+			values := make([]interface{}, len(row))
+			for j, v := range row {
+				values[j] = v
+			}
+			rows[i] = values
+		}
+		info["optimal_gain"] = rows
+	}
+	return info
+}
+
+// Close 关闭环境
+func (e *LQREnvironment) Close() error {
+	e.state = nil
+	return e.BaseEnvironment.Close()
+}
+
+// Metadata 返回环境元数据：奖励为负的二次型代价，Q与R由配置给出且不要求半正定，因此两端都不设界
+func (e *LQREnvironment) Metadata() core.EnvMetadata {
+	metadata := core.DefaultEnvMetadata()
+	metadata.MaxEpisodeSteps = e.cfg.MaxSteps
+	return metadata
+}
+
+// GetSpaces 获取线性系统场景的动作空间和观察空间定义
+func (e *LQREnvironment) GetSpaces() core.SpaceDefinition {
+	actionSpace := core.ActionSpace{
+		Type:  core.SpaceTypeBox,
+		Shape: []int32{int32(e.cfg.ActionDim)},
+		Dtype: "float32",
+	}
+	if limit := e.cfg.ActionLimit; limit > 0 {
+		actionSpace.Low = make([]float64, e.cfg.ActionDim)
+		actionSpace.High = make([]float64, e.cfg.ActionDim)
+		for i := range actionSpace.Low {
+			actionSpace.Low[i] = -limit
+			actionSpace.High[i] = limit
+		}
+	}
+
+	return core.SpaceDefinition{
+		ActionSpace: actionSpace,
+		ObservationSpace: core.ObservationSpace{
+			Type:  core.SpaceTypeBox,
+			Shape: []int32{int32(e.cfg.StateDim)},
+			Dtype: "float32",
+		},
+	}
+}
+
+// LQRAction 线性系统专用动作
+type LQRAction struct {
+	Control []float64 // 控制量u
+}
+
+// NewLQRAction 创建新的线性系统动作
+func NewLQRAction(control []float64) *LQRAction {
+	return &LQRAction{Control: control}
+}
+
+// GetData 获取动作数据
+func (a *LQRAction) GetData() interface{} {
+	return a.Control
+}
+
+// Validate 验证动作
+func (a *LQRAction) Validate() error {
+	if len(a.Control) == 0 {
+		return fmt.Errorf("lqr action must not be empty")
+	}
+	return nil
+}
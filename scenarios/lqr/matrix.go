@@ -0,0 +1,203 @@
+package lqr
+
+import (
+	"fmt"
+	"math"
+)
+
+// Matrix 行优先的稠密矩阵
+type Matrix [][]float64
+
+// newMatrix 创建rows x cols的零矩阵
+func newMatrix(rows, cols int) Matrix {
+	m := make(Matrix, rows)
+	for i := range m {
+		m[i] = make([]float64, cols)
+	}
+	return m
+}
+
+// Identity 创建n阶单位矩阵
+func Identity(n int) Matrix {
+	m := newMatrix(n, n)
+	for i := 0; i < n; i++ {
+		m[i][i] = 1
+	}
+	return m
+}
+
+// Rows 行数
+func (m Matrix) Rows() int {
+	return len(m)
+}
+
+// Cols 列数
+func (m Matrix) Cols() int {
+	if len(m) == 0 {
+		return 0
+	}
+	return len(m[0])
+}
+
+// checkShape 检查矩阵为rows x cols且每行长度一致
+func (m Matrix) checkShape(name string, rows, cols int) error {
+	if len(m) != rows {
+		return fmt.Errorf("%s must have %d rows, got %d", name, rows, len(m))
+	}
+	for i, row := range m {
+		if len(row) != cols {
+			return fmt.Errorf("%s row %d must have %d columns, got %d", name, i, cols, len(row))
+		}
+	}
+	return nil
+}
+
+// Mul 矩阵乘法
+func (m Matrix) Mul(o Matrix) Matrix {
+	out := newMatrix(m.Rows(), o.Cols())
+	for i := range m {
+		for k, v := range m[i] {
+			if v == 0 {
+				continue
+			}
+			for j := range o[k] {
+				out[i][j] += v * o[k][j]
+			}
+		}
+	}
+	return out
+}
+
+// MulVec 矩阵与向量相乘
+func (m Matrix) MulVec(v []float64) []float64 {
+	out := make([]float64, m.Rows())
+	for i, row := range m {
+		for j, a := range row {
+			out[i] += a * v[j]
+		}
+	}
+	return out
+}
+
+// T 转置
+func (m Matrix) T() Matrix {
+	out := newMatrix(m.Cols(), m.Rows())
+	for i := range m {
+		for j := range m[i] {
+			out[j][i] = m[i][j]
+		}
+	}
+	return out
+}
+
+// Add 矩阵加法
+func (m Matrix) Add(o Matrix) Matrix {
+	out := newMatrix(m.Rows(), m.Cols())
+	for i := range m {
+		for j := range m[i] {
+			out[i][j] = m[i][j] + o[i][j]
+		}
+	}
+	return out
+}
+
+// Sub 矩阵减法
+func (m Matrix) Sub(o Matrix) Matrix {
+	out := newMatrix(m.Rows(), m.Cols())
+	for i := range m {
+		for j := range m[i] {
+			out[i][j] = m[i][j] - o[i][j]
+		}
+	}
+	return out
+}
+
+// Inverse 使用带部分主元的高斯-约当消元求逆
+func (m Matrix) Inverse() (Matrix, error) {
+	n := m.Rows()
+	aug := newMatrix(n, 2*n)
+	for i := 0; i < n; i++ {
+		copy(aug[i], m[i])
+		aug[i][n+i] = 1
+	}
+
+	for col := 0; col < n; col++ {
+		pivot := col
+		for r := col + 1; r < n; r++ {
+			if math.Abs(aug[r][col]) > math.Abs(aug[pivot][col]) {
+				pivot = r
+			}
+		}
+		if math.Abs(aug[pivot][col]) < 1e-12 {
+			return nil, fmt.Errorf("matrix is singular")
+		}
+		aug[col], aug[pivot] = aug[pivot], aug[col]
+
+		p := aug[col][col]
+		for j := range aug[col] {
+			aug[col][j] /= p
+		}
+		for r := 0; r < n; r++ {
+			if r == col || aug[r][col] == 0 {
+				continue
+			}
+			f := aug[r][col]
+			for j := range aug[r] {
+				aug[r][j] -= f * aug[col][j]
+			}
+		}
+	}
+
+	inv := newMatrix(n, n)
+	for i := range inv {
+		copy(inv[i], aug[i][n:])
+	}
+	return inv, nil
+}
+
+// quadForm 计算 v^T M v
+func quadForm(m Matrix, v []float64) float64 {
+	total := 0.0
+	for i, row := range m {
+		for j, a := range row {
+			total += v[i] * a * v[j]
+		}
+	}
+	return total
+}
+
+// SolveDARE 通过Riccati迭代求解离散时间代数Riccati方程，
+// 返回无限时域LQR的最优反馈增益K（u = -Kx）与代价矩阵P（最优代价 x^T P x）
+func SolveDARE(a, b, q, r Matrix) (Matrix, Matrix, error) {
+	const (
+		maxIterations = 100000
+		tolerance     = 1e-10
+	)
+
+	at, bt := a.T(), b.T()
+	p := q
+	for iter := 0; iter < maxIterations; iter++ {
+		btp := bt.Mul(p)
+		inv, err := r.Add(btp.Mul(b)).Inverse()
+		if err != nil {
+			return nil, nil, fmt.Errorf("R + B'PB is not invertible: %w", err)
+		}
+		k := inv.Mul(btp.Mul(a))
+		next := q.Add(at.Mul(p).Mul(a.Sub(b.Mul(k))))
+
+		diff := 0.0
+		for i := range next {
+			for j := range next[i] {
+				diff = math.Max(diff, math.Abs(next[i][j]-p[i][j]))
+				if math.IsNaN(next[i][j]) || math.IsInf(next[i][j], 0) {
+					return nil, nil, fmt.Errorf("riccati iteration diverged (system may not be stabilizable)")
+				}
+			}
+		}
+		p = next
+		if diff < tolerance {
+			return k, p, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("riccati iteration did not converge after %d iterations", maxIterations)
+}
@@ -0,0 +1,52 @@
+package lqr
+
+import (
+	"fmt"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// LQRScenario 线性系统场景实现
+type LQRScenario struct {
+	name        string
+	description string
+}
+
+// 确保LQRScenario实现了core.Scenario接口
+var _ core.Scenario = (*LQRScenario)(nil)
+
+// NewLQRScenario 创建新的线性系统场景
+func NewLQRScenario() *LQRScenario {
+	return &LQRScenario{
+		name:        "lqr",
+		description: "Linear system x' = Ax + Bu + w with quadratic cost and closed-form LQR baseline",
+	}
+}
+
+// GetName 获取场景名称
+func (s *LQRScenario) GetName() string {
+	return s.name
+}
+
+// GetDescription 获取场景描述
+func (s *LQRScenario) GetDescription() string {
+	return s.description
+}
+
+// CreateEnvironment 创建环境实例
+func (s *LQRScenario) CreateEnvironment(config core.Config) (core.Environment, error) {
+	env, err := NewLQREnvironment(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create lqr environment: %w", err)
+	}
+	return env, nil
+}
+
+// ValidateConfig 验证配置
+func (s *LQRScenario) ValidateConfig(config core.Config) error {
+	if config == nil {
+		return fmt.Errorf("config cannot be nil")
+	}
+	_, err := parseConfig(config)
+	return err
+}
@@ -5,12 +5,37 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
-	"strconv"
+	"strings"
 	"time"
 
 	"github.com/jelech/rl_env_engine/core"
+	"github.com/jelech/rl_env_engine/core/config"
 )
 
+// Config LunarLander环境配置
+type Config struct {
+	MaxSteps        int     `cfg:"max_steps,default=400,min=1"`
+	Gravity         float64 `cfg:"gravity,default=1.6,min=0"`          // 月球重力
+	ThrustPower     float64 `cfg:"thrust_power,default=13.0,min=0"`    // 主推进器功率
+	LateralPower    float64 `cfg:"lateral_power,default=0.6,min=0"`    // 侧推进器功率
+	LandingPadWidth float64 `cfg:"landing_pad_width,default=0.3,gt=0"` // 着陆区宽度
+	HybridActions   bool    `cfg:"hybrid_actions,default=false"`       // 参数化动作：选择引擎并给出[0, 1]的油门，推力与燃料消耗按油门缩放
+}
+
+// DefaultConfig 返回默认配置
+func DefaultConfig() Config {
+	var cfg Config
+	config.MustDefaults(&cfg)
+	return cfg
+}
+
+// parseConfig 将core.Config绑定为LunarLander环境配置，未提供的字段使用默认值
+func parseConfig(src core.Config) (Config, error) {
+	var cfg Config
+	err := config.Bind(src, &cfg)
+	return cfg, err
+}
+
 // LunarLanderEnvironment 简化版的月球着陆器控制环境
 // 目标：安全着陆在指定区域
 type LunarLanderEnvironment struct {
@@ -33,37 +58,43 @@ type LunarLanderEnvironment struct {
 	landingPadX  float64
 	landingPadY  float64
 	landingPadW  float64
+	hybrid       bool
 	crashed      bool
 	landed       bool
+	lastAction   int // 上一步执行的动作，仅用于渲染
 
 	rng *rand.Rand
 }
 
-// NewLunarLanderEnvironment 创建新的LunarLander环境
-func NewLunarLanderEnvironment(config core.Config) *LunarLanderEnvironment {
-	baseEnv := core.NewBaseEnvironment("lunarlander", "Simplified Lunar Lander control environment", config)
+// 确保LunarLanderEnvironment实现了可选的渲染与参数修改接口
+var (
+	_ core.Renderer      = (*LunarLanderEnvironment)(nil)
+	_ core.Parameterized = (*LunarLanderEnvironment)(nil)
+)
 
-	// 从配置中获取参数
-	maxSteps := 400
-	if val := config.GetValue("max_steps"); val != nil {
-		switch v := val.(type) {
-		case int:
-			maxSteps = v
-		case string:
-			if parsed, err := strconv.Atoi(v); err == nil {
-				maxSteps = parsed
-			}
-		}
+// NewLunarLanderEnvironment 创建新的LunarLander环境，配置无效时返回错误
+func NewLunarLanderEnvironment(config core.Config) (*LunarLanderEnvironment, error) {
+	cfg, err := parseConfig(config)
+	if err != nil {
+		return nil, err
 	}
+	return newLunarLanderEnvironment(config, cfg), nil
+}
+
+// newLunarLanderEnvironment 以绑定后的配置创建环境
+func newLunarLanderEnvironment(config core.Config, cfg Config) *LunarLanderEnvironment {
+	baseEnv := core.NewBaseEnvironment("lunarlander", "Simplified Lunar Lander control environment", config)
+
+	maxSteps := cfg.MaxSteps
 
 	// 环境参数
-	gravity := 1.6      // 月球重力
-	thrustPower := 13.0 // 主推进器功率
-	lateralPower := 0.6 // 侧推进器功率
-	dt := 1.0 / 60.0    // 60 FPS
-	landingPadX := 0.0  // 着陆区中心X
-	landingPadY := 0.0  // 着陆区Y
-	landingPadW := 0.3  // 着陆区宽度
+	gravity := cfg.Gravity
+	thrustPower := cfg.ThrustPower
+	lateralPower := cfg.LateralPower
+	dt := 1.0 / 60.0   // 60 FPS
+	landingPadX := 0.0 // 着陆区中心X
+	landingPadY := 0.0 // 着陆区Y
+	landingPadW := cfg.LandingPadWidth
 
 	env := &LunarLanderEnvironment{
 		BaseEnvironment: baseEnv,
@@ -76,6 +107,7 @@ func NewLunarLanderEnvironment(config core.Config) *LunarLanderEnvironment {
 		landingPadX:     landingPadX,
 		landingPadY:     landingPadY,
 		landingPadW:     landingPadW,
+		hybrid:          cfg.HybridActions,
 		crashed:         false,
 		landed:          false,
 		rng:             rand.New(rand.NewSource(time.Now().UnixNano())),
@@ -96,6 +128,7 @@ func (e *LunarLanderEnvironment) Reset(ctx context.Context) ([]core.Observation,
 	e.currentStep = 0
 	e.crashed = false
 	e.landed = false
+	e.lastAction = 0
 
 	return e.GetObservations(), nil
 }
@@ -103,19 +136,31 @@ func (e *LunarLanderEnvironment) Reset(ctx context.Context) ([]core.Observation,
 // Step 执行一步
 func (e *LunarLanderEnvironment) Step(ctx context.Context, actions []core.Action) ([]core.Observation, []float64, []bool, error) {
 	if len(actions) == 0 {
-		return nil, nil, nil, fmt.Errorf("no actions provided")
+		return nil, nil, nil, fmt.Errorf("%w: no actions provided", core.ErrInvalidAction)
 	}
 
 	e.currentStep++
 
-	// 解析动作（4个离散动作：0: 不动, 1: 左引擎, 2: 主引擎, 3: 右引擎）
+	// 解析动作（4个离散动作：0: 不动, 1: 左引擎, 2: 主引擎, 3: 右引擎），
+	// 参数化动作另带所选引擎的油门，离散动作为全油门
 	var actionValue int
+	throttle := 1.0
 
-	// 尝试从GenericAction中提取
-	if genericAction, ok := actions[0].(*core.GenericAction); ok {
+	if hybridAction, ok := actions[0].(*core.HybridAction); ok {
+		actionValue = hybridAction.Choice
+		if actionValue < 0 || actionValue > 3 {
+			return nil, nil, nil, fmt.Errorf("%w: lunarlander engine must be 0-3, got %d", core.ErrInvalidAction, actionValue)
+		}
+		if actionValue != 0 {
+			if len(hybridAction.Parameters) != 1 {
+				return nil, nil, nil, fmt.Errorf("%w: lunarlander engine %d needs 1 throttle parameter, got %d", core.ErrInvalidAction, actionValue, len(hybridAction.Parameters))
+			}
+			throttle = math.Max(0, math.Min(1, hybridAction.Parameters[0]))
+		}
+	} else if genericAction, ok := actions[0].(*core.GenericAction); ok {
 		actionFloat, err := genericAction.GetFloat64()
 		if err != nil {
-			return nil, nil, nil, fmt.Errorf("failed to extract action value: %w", err)
+			return nil, nil, nil, fmt.Errorf("%w: failed to extract action value: %w", core.ErrInvalidAction, err)
 		}
 		actionValue = int(actionFloat)
 		if actionValue < 0 || actionValue > 3 {
@@ -124,8 +169,9 @@ func (e *LunarLanderEnvironment) Step(ctx context.Context, actions []core.Action
 	} else if lunarAction, ok := actions[0].(*LunarLanderAction); ok {
 		actionValue = lunarAction.Action
 	} else {
-		return nil, nil, nil, fmt.Errorf("unsupported action type: %T", actions[0])
+		return nil, nil, nil, fmt.Errorf("%w: unsupported action type: %T", core.ErrInvalidAction, actions[0])
 	}
+	e.lastAction = actionValue
 
 	// 物理仿真
 	// 重力作用
@@ -134,14 +180,14 @@ func (e *LunarLanderEnvironment) Step(ctx context.Context, actions []core.Action
 	// 根据动作施加推力
 	switch actionValue {
 	case 1: // 左引擎
-		e.vx -= e.lateralPower * e.dt
-		e.angularV += 0.1
+		e.vx -= throttle * e.lateralPower * e.dt
+		e.angularV += throttle * 0.1
 	case 2: // 主引擎
-		e.vy += e.thrustPower * math.Cos(e.angle) * e.dt
-		e.vx += e.thrustPower * math.Sin(e.angle) * e.dt
+		e.vy += throttle * e.thrustPower * math.Cos(e.angle) * e.dt
+		e.vx += throttle * e.thrustPower * math.Sin(e.angle) * e.dt
 	case 3: // 右引擎
-		e.vx += e.lateralPower * e.dt
-		e.angularV -= 0.1
+		e.vx += throttle * e.lateralPower * e.dt
+		e.angularV -= throttle * 0.1
 	}
 
 	// 更新位置和角度
@@ -173,7 +219,7 @@ func (e *LunarLanderEnvironment) Step(ctx context.Context, actions []core.Action
 	}
 
 	// 计算奖励
-	reward := e.calculateReward(actionValue)
+	reward := e.calculateReward(actionValue, throttle)
 
 	// 检查是否结束
 	done := e.crashed || e.landed || e.currentStep >= e.maxSteps
@@ -185,8 +231,8 @@ func (e *LunarLanderEnvironment) Step(ctx context.Context, actions []core.Action
 	return observations, rewards, dones, nil
 }
 
-// calculateReward 计算奖励
-func (e *LunarLanderEnvironment) calculateReward(action int) float64 {
+// calculateReward 计算奖励，燃料消耗按油门缩放
+func (e *LunarLanderEnvironment) calculateReward(action int, throttle float64) float64 {
 	reward := 0.0
 
 	// 基础距离奖励（越接近着陆区越好）
@@ -201,9 +247,9 @@ func (e *LunarLanderEnvironment) calculateReward(action int) float64 {
 
 	// 燃料使用惩罚
 	if action == 1 || action == 3 {
-		reward -= 0.03 // 侧推进器
+		reward -= 0.03 * throttle // 侧推进器
 	} else if action == 2 {
-		reward -= 0.3 // 主推进器
+		reward -= 0.3 * throttle // 主推进器
 	}
 
 	// 着陆奖励
@@ -231,37 +277,141 @@ func (e *LunarLanderEnvironment) GetObservations() []core.Observation {
 		0.0, // leg2_contact (简化为0)
 	}
 
-	metadata := map[string]interface{}{
-		"x":         e.x,
-		"y":         e.y,
-		"vx":        e.vx,
-		"vy":        e.vy,
-		"angle":     e.angle,
-		"angular_v": e.angularV,
-		"step":      e.currentStep,
-		"max_steps": e.maxSteps,
-		"crashed":   e.crashed,
-		"landed":    e.landed,
+	var metadata map[string]interface{}
+	if e.ObservationMetadataEnabled() {
+		metadata = map[string]interface{}{
+			"x":         e.x,
+			"y":         e.y,
+			"vx":        e.vx,
+			"vy":        e.vy,
+			"angle":     e.angle,
+			"angular_v": e.angularV,
+			"step":      e.currentStep,
+			"max_steps": e.maxSteps,
+			"crashed":   e.crashed,
+			"landed":    e.landed,
+		}
 	}
 
-	observation := core.NewBaseObservation(data, metadata)
+	observation := e.AcquireObservation(data, metadata)
 	return []core.Observation{observation}
 }
 
 // GetReward 计算奖励
 func (e *LunarLanderEnvironment) GetReward() []float64 {
-	reward := e.calculateReward(0) // 假设无动作的基础奖励
+	reward := e.calculateReward(0, 0) // 假设无动作的基础奖励
 	return []float64{reward}
 }
 
+// GetInfo 获取环境信息，包含本回合是否已着陆或坠毁
+func (e *LunarLanderEnvironment) GetInfo() map[string]interface{} {
+	info := e.BaseEnvironment.GetInfo()
+	info["landed"] = e.landed
+	info["crashed"] = e.crashed
+	return info
+}
+
+// Render 将当前状态渲染为ASCII画面
+// 画面覆盖 x∈[-3, 3]、y∈[0, 3]：'=' 为着陆区，'A' 为着陆器（倾斜时为 '/' 或 '\'），'*' 为主引擎火焰
+func (e *LunarLanderEnvironment) Render() string {
+	const width, height = 41, 15
+	col := func(x float64) int { return int(math.Round((x + 3) / 6 * (width - 1))) }
+	row := func(y float64) int { return height - 1 - int(math.Round(y/3*(height-1))) }
+
+	grid := make([][]byte, height+1)
+	for r := range grid {
+		grid[r] = []byte(strings.Repeat(" ", width))
+	}
+	for c := 0; c < width; c++ {
+		grid[height][c] = '-'
+	}
+	for c := col(e.landingPadX - e.landingPadW/2); c <= col(e.landingPadX+e.landingPadW/2); c++ {
+		if c >= 0 && c < width {
+			grid[height][c] = '='
+		}
+	}
+
+	r, c := row(math.Max(0, math.Min(3, e.y))), col(e.x)
+	if c >= 0 && c < width {
+		lander := byte('A')
+		if e.angle > 0.15 {
+			lander = '/'
+		} else if e.angle < -0.15 {
+			lander = '\\'
+		}
+		grid[r][c] = lander
+		if e.lastAction == 2 && r+1 < height {
+			grid[r+1][c] = '*'
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "step: %d/%d  x: %+.2f  y: %.2f  vx: %+.2f  vy: %+.2f  angle: %+.2f\n",
+		e.currentStep, e.maxSteps, e.x, e.y, e.vx, e.vy, e.angle)
+	for _, line := range grid {
+		sb.Write(line)
+		sb.WriteString("\n")
+	}
+	if e.landed {
+		sb.WriteString("landed\n")
+	} else if e.crashed {
+		sb.WriteString("crashed\n")
+	}
+	return sb.String()
+}
+
+// Parameters 实现core.Parameterized：可在回合之间修改的物理参数与着陆区宽度
+func (e *LunarLanderEnvironment) Parameters() map[string]float64 {
+	return map[string]float64{
+		"gravity":           e.gravity,
+		"thrust_power":      e.thrustPower,
+		"lateral_power":     e.lateralPower,
+		"landing_pad_width": e.landingPadW,
+	}
+}
+
+// SetParameter 实现core.Parameterized，取值范围与配置一致：着陆区宽度须为正数，其余参数可以为0
+func (e *LunarLanderEnvironment) SetParameter(name string, value float64) error {
+	var target *float64
+	switch name {
+	case "gravity":
+		target = &e.gravity
+	case "thrust_power":
+		target = &e.thrustPower
+	case "lateral_power":
+		target = &e.lateralPower
+	case "landing_pad_width":
+		if err := core.PositiveParameter(name, value); err != nil {
+			return err
+		}
+		e.landingPadW = value
+		return nil
+	default:
+		return core.UnknownParameterError(name, e)
+	}
+	if !(value >= 0) || math.IsInf(value, 0) {
+		return fmt.Errorf("parameter %s must be a non-negative finite number, got %v", name, value)
+	}
+	*target = value
+	return nil
+}
+
 // Close 关闭环境
 func (e *LunarLanderEnvironment) Close() error {
 	return e.BaseEnvironment.Close()
 }
 
+// Metadata 返回环境元数据：着陆时最多获得100，坠毁时的惩罚随距离无界
+func (e *LunarLanderEnvironment) Metadata() core.EnvMetadata {
+	return core.EnvMetadata{
+		RewardRange:     [2]float64{math.Inf(-1), 100},
+		MaxEpisodeSteps: e.maxSteps,
+	}
+}
+
 // GetSpaces 获取LunarLander场景的动作空间和观察空间定义
 func (e *LunarLanderEnvironment) GetSpaces() core.SpaceDefinition {
-	return core.SpaceDefinition{
+	spaces := core.SpaceDefinition{
 		ActionSpace: core.ActionSpace{
 			Type:  core.SpaceTypeDiscrete,
 			Low:   []float64{0}, // 离散动作的最小值
@@ -277,6 +427,15 @@ func (e *LunarLanderEnvironment) GetSpaces() core.SpaceDefinition {
 			Dtype: "float32",
 		},
 	}
+	if e.hybrid {
+		// 不动没有参数，三个引擎各有一个[0, 1]的油门
+		throttle := core.ActionSpace{Type: core.SpaceTypeBox, Low: []float64{0}, High: []float64{1}, Shape: []int32{1}, Dtype: "float32"}
+		spaces.ActionSpace = core.ActionSpace{
+			Type:       core.SpaceTypeHybrid,
+			Parameters: []core.ActionSpace{{Type: core.SpaceTypeBox, Shape: []int32{0}, Dtype: "float32"}, throttle, throttle, throttle},
+		}
+	}
+	return spaces
 }
 
 // LunarLanderAction LunarLander专用动作
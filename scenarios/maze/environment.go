@@ -0,0 +1,378 @@
+package maze
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/jelech/rl_env_engine/core"
+	"github.com/jelech/rl_env_engine/core/config"
+)
+
+// 移动方向
+const (
+	MoveUp = iota
+	MoveRight
+	MoveDown
+	MoveLeft
+	numMoves
+)
+
+// 网格中的格子类型，同时作为观察中的取值
+const (
+	tileFree  = 0
+	tileWall  = 1
+	tileGoal  = 2
+	tileAgent = 3
+)
+
+var moveDeltas = [numMoves][2]int{{-1, 0}, {0, 1}, {1, 0}, {0, -1}} // (行, 列)
+
+// Config 迷宫环境配置
+type Config struct {
+	MaxSteps    int     `cfg:"max_steps,default=200,min=1"`
+	Width       int     `cfg:"width,default=5,min=2"`                // 迷宫宽度（房间数），网格宽度为 2*width+1
+	Height      int     `cfg:"height,default=5,min=2"`               // 迷宫高度（房间数），网格高度为 2*height+1
+	WallDensity float64 `cfg:"wall_density,default=1.0,min=0,max=1"` // 生成树之外保留的墙比例：1为完美迷宫（唯一路径），0为无内部墙
+	ViewRadius  int     `cfg:"view_radius,min=0"`                    // 局部视野半径，0表示观察整个迷宫
+	MazeSeed    int64   `cfg:"maze_seed"`                            // 迷宫布局种子，配合num_mazes划分训练/测试布局
+	NumMazes    int     `cfg:"num_mazes,min=0"`                      // 布局池大小：>0时每次Reset从 maze_seed..maze_seed+num_mazes-1 中抽取，0表示每次生成新布局
+	GoalReward  float64 `cfg:"goal_reward,default=1.0"`
+	StepPenalty float64 `cfg:"step_penalty,default=0.01,min=0"`
+	Seed        int64   `cfg:"seed"`
+}
+
+// DefaultConfig 返回默认配置
+func DefaultConfig() Config {
+	var cfg Config
+	config.MustDefaults(&cfg)
+	return cfg
+}
+
+// Validate 按cfg标签中的约束验证配置，用于校验直接构造的配置
+func (c Config) Validate() error {
+	return config.Validate(c)
+}
+
+// parseConfig 将core.Config绑定为迷宫配置，未提供的字段使用默认值
+func parseConfig(src core.Config) (Config, error) {
+	var cfg Config
+	if err := config.Bind(src, &cfg); err != nil {
+		return cfg, err
+	}
+	return cfg, cfg.Validate()
+}
+
+// Generate 使用给定种子生成迷宫网格（行优先，大小为 (2*height+1) x (2*width+1)）
+// 先用随机深度优先搜索生成完美迷宫，再按 1-wallDensity 的比例随机打通剩余内部墙，
+// 因此任意两个房间始终连通，相同参数与种子总是生成相同布局
+func Generate(width, height int, wallDensity float64, seed int64) []int {
+	rng := rand.New(rand.NewSource(seed))
+	rows, cols := 2*height+1, 2*width+1
+	grid := make([]int, rows*cols)
+	for i := range grid {
+		grid[i] = tileWall
+	}
+
+	// 房间(r, c)对应网格(2r+1, 2c+1)
+	visited := make([]bool, width*height)
+	stack := []int{0}
+	visited[0] = true
+	grid[cols+1] = tileFree
+	for len(stack) > 0 {
+		room := stack[len(stack)-1]
+		r, c := room/width, room%width
+
+		var candidates []int
+		for _, d := range moveDeltas {
+			nr, nc := r+d[0], c+d[1]
+			if nr >= 0 && nr < height && nc >= 0 && nc < width && !visited[nr*width+nc] {
+				candidates = append(candidates, nr*width+nc)
+			}
+		}
+		if len(candidates) == 0 {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		next := candidates[rng.Intn(len(candidates))]
+		nr, nc := next/width, next%width
+		grid[(r+nr+1)*cols+(c+nc+1)] = tileFree // 打通两房间之间的墙
+		grid[(2*nr+1)*cols+(2*nc+1)] = tileFree
+		visited[next] = true
+		stack = append(stack, next)
+	}
+
+	// 打通部分剩余的内部墙（仅限两侧都是房间的墙段）以产生环路
+	for r := 1; r < rows-1; r++ {
+		for c := 1; c < cols-1; c++ {
+			if grid[r*cols+c] != tileWall || (r%2 == 1) == (c%2 == 1) {
+				continue
+			}
+			if rng.Float64() >= wallDensity {
+				grid[r*cols+c] = tileFree
+			}
+		}
+	}
+	return grid
+}
+
+// MazeEnvironment 程序化迷宫环境
+// 智能体从左上角房间出发，到达右下角房间获得奖励；撞墙时原地不动。
+// 观察为以格子类型（0: 空地, 1: 墙, 2: 终点, 3: 智能体）表示的整个网格，
+// 或 view_radius>0 时以智能体为中心的 (2r+1)x(2r+1) 局部窗口（越界视为墙）
+type MazeEnvironment struct {
+	*core.BaseEnvironment
+	cfg Config
+
+	rows, cols int
+	grid       []int
+	layoutSeed int64
+	agent      [2]int
+	goal       [2]int
+
+	currentStep int
+	lastReward  float64
+	reached     bool
+	bumps       int // 本episode撞墙次数
+
+	rng *rand.Rand
+
+	obsBuf []float64 // GetObservations复用的观察数据缓冲区
+}
+
+// 确保MazeEnvironment实现了可选的渲染接口
+var _ core.Renderer = (*MazeEnvironment)(nil)
+
+// NewMazeEnvironment 创建新的迷宫环境
+func NewMazeEnvironment(config core.Config) (*MazeEnvironment, error) {
+	cfg, err := parseConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	baseEnv := core.NewBaseEnvironment("maze", "Procedurally generated maze", config)
+
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	return &MazeEnvironment{
+		BaseEnvironment: baseEnv,
+		cfg:             cfg,
+		rows:            2*cfg.Height + 1,
+		cols:            2*cfg.Width + 1,
+		rng:             rand.New(rand.NewSource(seed)),
+	}, nil
+}
+
+// Reset 重置环境：选择（或生成）迷宫布局并将智能体放回起点
+func (e *MazeEnvironment) Reset(ctx context.Context) ([]core.Observation, error) {
+	if e.cfg.NumMazes > 0 {
+		e.layoutSeed = e.cfg.MazeSeed + int64(e.rng.Intn(e.cfg.NumMazes))
+	} else {
+		e.layoutSeed = e.rng.Int63()
+	}
+	e.grid = Generate(e.cfg.Width, e.cfg.Height, e.cfg.WallDensity, e.layoutSeed)
+
+	e.agent = [2]int{1, 1}
+	e.goal = [2]int{e.rows - 2, e.cols - 2}
+	e.currentStep = 0
+	e.lastReward = 0
+	e.reached = false
+	e.bumps = 0
+
+	return e.GetObservations(), nil
+}
+
+// Step 执行一步移动
+func (e *MazeEnvironment) Step(ctx context.Context, actions []core.Action) ([]core.Observation, []float64, []bool, error) {
+	if len(actions) == 0 {
+		return nil, nil, nil, fmt.Errorf("%w: no actions provided", core.ErrInvalidAction)
+	}
+
+	var move int
+	if genericAction, ok := actions[0].(*core.GenericAction); ok {
+		value, err := genericAction.GetInt64()
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("%w: failed to extract action value: %w", core.ErrInvalidAction, err)
+		}
+		move = int(value)
+	} else if mazeAction, ok := actions[0].(*MazeAction); ok {
+		move = mazeAction.Move
+	} else {
+		return nil, nil, nil, fmt.Errorf("%w: unsupported action type: %T", core.ErrInvalidAction, actions[0])
+	}
+	if move < 0 || move >= numMoves {
+		return nil, nil, nil, fmt.Errorf("%w: move must be in [0, %d), got %d", core.ErrInvalidAction, numMoves, move)
+	}
+
+	e.currentStep++
+	e.lastReward = -e.cfg.StepPenalty
+
+	r, c := e.agent[0]+moveDeltas[move][0], e.agent[1]+moveDeltas[move][1]
+	if e.tile(r, c) == tileWall {
+		e.bumps++
+	} else {
+		e.agent = [2]int{r, c}
+	}
+
+	if e.agent == e.goal {
+		e.reached = true
+		e.lastReward += e.cfg.GoalReward
+	}
+
+	done := e.reached || e.currentStep >= e.cfg.MaxSteps
+
+	return e.GetObservations(), []float64{e.lastReward}, []bool{done}, nil
+}
+
+// tile 返回网格中(r, c)处的格子类型，越界视为墙
+func (e *MazeEnvironment) tile(r, c int) int {
+	if r < 0 || r >= e.rows || c < 0 || c >= e.cols {
+		return tileWall
+	}
+	switch {
+	case r == e.agent[0] && c == e.agent[1]:
+		return tileAgent
+	case r == e.goal[0] && c == e.goal[1]:
+		return tileGoal
+	}
+	return e.grid[r*e.cols+c]
+}
+
+// viewShape 返回观察的 (行数, 列数)
+func (e *MazeEnvironment) viewShape() (int, int) {
+	if e.cfg.ViewRadius > 0 {
+		side := 2*e.cfg.ViewRadius + 1
+		return side, side
+	}
+	return e.rows, e.cols
+}
+
+// GetObservations 获取当前观察
+func (e *MazeEnvironment) GetObservations() []core.Observation {
+	h, w := e.viewShape()
+	data := core.ResetBuffer(&e.obsBuf, h*w)[:0]
+	if e.cfg.ViewRadius > 0 {
+		for dr := -e.cfg.ViewRadius; dr <= e.cfg.ViewRadius; dr++ {
+			for dc := -e.cfg.ViewRadius; dc <= e.cfg.ViewRadius; dc++ {
+				data = append(data, float64(e.tile(e.agent[0]+dr, e.agent[1]+dc)))
+			}
+		}
+	} else {
+		for r := 0; r < e.rows; r++ {
+			for c := 0; c < e.cols; c++ {
+				data = append(data, float64(e.tile(r, c)))
+			}
+		}
+	}
+
+	var metadata map[string]interface{}
+	if e.ObservationMetadataEnabled() {
+		metadata = map[string]interface{}{
+			"position":    []interface{}{e.agent[0], e.agent[1]},
+			"goal":        []interface{}{e.goal[0], e.goal[1]},
+			"layout_seed": e.layoutSeed,
+			"reached":     e.reached,
+			"bumps":       e.bumps,
+			"step":        e.currentStep,
+			"max_steps":   e.cfg.MaxSteps,
+		}
+	}
+
+	observation := e.AcquireObservation(data, metadata)
+	return []core.Observation{observation}
+}
+
+// GetReward 返回最近一步的奖励
+func (e *MazeEnvironment) GetReward() []float64 {
+	return []float64{e.lastReward}
+}
+
+// GetInfo 获取环境信息
+func (e *MazeEnvironment) GetInfo() map[string]interface{} {
+	info := e.BaseEnvironment.GetInfo()
+	info["layout_seed"] = e.layoutSeed
+	info["partial_observability"] = e.cfg.ViewRadius > 0
+	info["reached"] = e.reached
+	return info
+}
+
+// Render 将迷宫渲染为文本
+func (e *MazeEnvironment) Render() string {
+	symbols := [4]byte{' ', '#', 'G', 'A'}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "layout: %d  step: %d\n", e.layoutSeed, e.currentStep)
+	for r := 0; r < e.rows; r++ {
+		for c := 0; c < e.cols; c++ {
+			sb.WriteByte(symbols[e.tile(r, c)])
+		}
+		sb.WriteString("\n")
+	}
+	if e.reached {
+		sb.WriteString("goal reached\n")
+	}
+	return sb.String()
+}
+
+// Close 关闭环境
+func (e *MazeEnvironment) Close() error {
+	e.grid = nil
+	return e.BaseEnvironment.Close()
+}
+
+// Metadata 返回环境元数据：每步扣除step_penalty，到达终点时加goal_reward
+func (e *MazeEnvironment) Metadata() core.EnvMetadata {
+	return core.EnvMetadata{
+		RewardRange:     [2]float64{-e.cfg.StepPenalty, e.cfg.GoalReward - e.cfg.StepPenalty},
+		MaxEpisodeSteps: e.cfg.MaxSteps,
+	}
+}
+
+// GetSpaces 获取迷宫场景的动作空间和观察空间定义
+func (e *MazeEnvironment) GetSpaces() core.SpaceDefinition {
+	h, w := e.viewShape()
+	return core.SpaceDefinition{
+		ActionSpace: core.ActionSpace{
+			Type:  core.SpaceTypeDiscrete,
+			Low:   []float64{0},
+			High:  []float64{numMoves - 1}, // 0: 上, 1: 右, 2: 下, 3: 左
+			Shape: []int32{},
+			Dtype: "int32",
+		},
+		ObservationSpace: core.ObservationSpace{
+			Type:  core.SpaceTypeBox,
+			Low:   []float64{tileFree},
+			High:  []float64{tileAgent},
+			Shape: []int32{int32(h), int32(w)},
+			Dtype: "float32",
+		},
+	}
+}
+
+// MazeAction 迷宫专用动作
+type MazeAction struct {
+	Move int // 0: 上, 1: 右, 2: 下, 3: 左
+}
+
+// NewMazeAction 创建新的迷宫动作
+func NewMazeAction(move int) *MazeAction {
+	return &MazeAction{Move: move}
+}
+
+// GetData 获取动作数据
+func (a *MazeAction) GetData() interface{} {
+	return a.Move
+}
+
+// Validate 验证动作
+func (a *MazeAction) Validate() error {
+	if a.Move < 0 || a.Move >= numMoves {
+		return fmt.Errorf("maze move must be in [0, %d), got %d", numMoves, a.Move)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package maze
+
+import (
+	"fmt"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// MazeScenario 迷宫场景实现
+type MazeScenario struct {
+	name        string
+	description string
+}
+
+// 确保MazeScenario实现了core.Scenario接口
+var _ core.Scenario = (*MazeScenario)(nil)
+
+// NewMazeScenario 创建新的迷宫场景
+func NewMazeScenario() *MazeScenario {
+	return &MazeScenario{
+		name:        "maze",
+		description: "Seeded procedural maze with configurable wall density and optional local view window",
+	}
+}
+
+// GetName 获取场景名称
+func (s *MazeScenario) GetName() string {
+	return s.name
+}
+
+// GetDescription 获取场景描述
+func (s *MazeScenario) GetDescription() string {
+	return s.description
+}
+
+// CreateEnvironment 创建环境实例
+func (s *MazeScenario) CreateEnvironment(config core.Config) (core.Environment, error) {
+	env, err := NewMazeEnvironment(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create maze environment: %w", err)
+	}
+	return env, nil
+}
+
+// ValidateConfig 验证配置
+func (s *MazeScenario) ValidateConfig(config core.Config) error {
+	if config == nil {
+		return fmt.Errorf("config cannot be nil")
+	}
+	_, err := parseConfig(config)
+	return err
+}
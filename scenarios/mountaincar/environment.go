@@ -5,12 +5,36 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
-	"strconv"
 	"time"
 
 	"github.com/jelech/rl_env_engine/core"
+	"github.com/jelech/rl_env_engine/core/config"
 )
 
+// Config MountainCar环境配置
+// 物理参数默认值与OpenAI Gym的MountainCar-v0一致
+type Config struct {
+	MaxSteps     int     `cfg:"max_steps,default=200,min=1"`
+	MaxSpeed     float64 `cfg:"max_speed,default=0.07,gt=0"`
+	GoalPosition float64 `cfg:"goal_position,default=0.5,gt=-0.6,max=0.6"` // 需位于初始区间右侧
+	Force        float64 `cfg:"force,default=0.001,gt=0"`
+	Gravity      float64 `cfg:"gravity,default=0.0025,min=0"`
+}
+
+// DefaultConfig 返回默认配置
+func DefaultConfig() Config {
+	var cfg Config
+	config.MustDefaults(&cfg)
+	return cfg
+}
+
+// parseConfig 将core.Config绑定为MountainCar环境配置，未提供的字段使用默认值
+func parseConfig(src core.Config) (Config, error) {
+	var cfg Config
+	err := config.Bind(src, &cfg)
+	return cfg, err
+}
+
 // MountainCarEnvironment 经典的小车上山环境
 // 目标：通过向左或向右加速来让小车到达右侧山顶
 type MountainCarEnvironment struct {
@@ -33,31 +57,29 @@ type MountainCarEnvironment struct {
 	rng *rand.Rand
 }
 
-// NewMountainCarEnvironment 创建新的MountainCar环境
-func NewMountainCarEnvironment(config core.Config) *MountainCarEnvironment {
+// NewMountainCarEnvironment 创建新的MountainCar环境，配置无效时返回错误
+func NewMountainCarEnvironment(config core.Config) (*MountainCarEnvironment, error) {
+	cfg, err := parseConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return newMountainCarEnvironment(config, cfg), nil
+}
+
+// newMountainCarEnvironment 以绑定后的配置创建环境
+func newMountainCarEnvironment(config core.Config, cfg Config) *MountainCarEnvironment {
 	baseEnv := core.NewBaseEnvironment("mountaincar", "Classic MountainCar control environment", config)
 
-	// 从配置中获取参数
-	maxSteps := 200
-	if val := config.GetValue("max_steps"); val != nil {
-		switch v := val.(type) {
-		case int:
-			maxSteps = v
-		case string:
-			if parsed, err := strconv.Atoi(v); err == nil {
-				maxSteps = parsed
-			}
-		}
-	}
+	maxSteps := cfg.MaxSteps
 
-	// 环境参数（基于OpenAI Gym的MountainCar-v0）
+	// 环境参数
 	minPosition := -1.2
 	maxPosition := 0.6
-	maxSpeed := 0.07
-	goalPosition := 0.5
+	maxSpeed := cfg.MaxSpeed
+	goalPosition := cfg.GoalPosition
 	goalVelocity := 0.0
-	force := 0.001
-	gravity := 0.0025
+	force := cfg.Force
+	gravity := cfg.Gravity
 
 	env := &MountainCarEnvironment{
 		BaseEnvironment: baseEnv,
@@ -89,7 +111,7 @@ func (e *MountainCarEnvironment) Reset(ctx context.Context) ([]core.Observation,
 // Step 执行一步
 func (e *MountainCarEnvironment) Step(ctx context.Context, actions []core.Action) ([]core.Observation, []float64, []bool, error) {
 	if len(actions) == 0 {
-		return nil, nil, nil, fmt.Errorf("no actions provided")
+		return nil, nil, nil, fmt.Errorf("%w: no actions provided", core.ErrInvalidAction)
 	}
 
 	e.currentStep++
@@ -101,7 +123,7 @@ func (e *MountainCarEnvironment) Step(ctx context.Context, actions []core.Action
 	if genericAction, ok := actions[0].(*core.GenericAction); ok {
 		actionFloat, err := genericAction.GetFloat64()
 		if err != nil {
-			return nil, nil, nil, fmt.Errorf("failed to extract action value: %w", err)
+			return nil, nil, nil, fmt.Errorf("%w: failed to extract action value: %w", core.ErrInvalidAction, err)
 		}
 		// 将连续动作转换为离散动作
 		if actionFloat < 0.33 {
@@ -114,7 +136,7 @@ func (e *MountainCarEnvironment) Step(ctx context.Context, actions []core.Action
 	} else if mountainCarAction, ok := actions[0].(*MountainCarAction); ok {
 		actionValue = mountainCarAction.Action
 	} else {
-		return nil, nil, nil, fmt.Errorf("unsupported action type: %T", actions[0])
+		return nil, nil, nil, fmt.Errorf("%w: unsupported action type: %T", core.ErrInvalidAction, actions[0])
 	}
 
 	// 计算新速度
@@ -161,15 +183,18 @@ func (e *MountainCarEnvironment) GetObservations() []core.Observation {
 		e.velocity, // 小车速度
 	}
 
-	metadata := map[string]interface{}{
-		"position":     e.position,
-		"velocity":     e.velocity,
-		"step":         e.currentStep,
-		"max_steps":    e.maxSteps,
-		"goal_reached": e.position >= e.goalPosition,
+	var metadata map[string]interface{}
+	if e.ObservationMetadataEnabled() {
+		metadata = map[string]interface{}{
+			"position":     e.position,
+			"velocity":     e.velocity,
+			"step":         e.currentStep,
+			"max_steps":    e.maxSteps,
+			"goal_reached": e.position >= e.goalPosition,
+		}
 	}
 
-	observation := core.NewBaseObservation(data, metadata)
+	observation := e.AcquireObservation(data, metadata)
 	return []core.Observation{observation}
 }
 
@@ -183,11 +208,59 @@ func (e *MountainCarEnvironment) GetReward() []float64 {
 	return []float64{reward}
 }
 
+// GetInfo 获取环境信息，包含是否已到达目标
+func (e *MountainCarEnvironment) GetInfo() map[string]interface{} {
+	info := e.BaseEnvironment.GetInfo()
+	info["goal_reached"] = e.position >= e.goalPosition
+	return info
+}
+
+// Parameters 实现core.Parameterized：可在回合之间修改的物理参数与目标位置（max_speed决定观察空间，不可修改）
+func (e *MountainCarEnvironment) Parameters() map[string]float64 {
+	return map[string]float64{
+		"goal_position": e.goalPosition,
+		"force":         e.force,
+		"gravity":       e.gravity,
+	}
+}
+
+// SetParameter 实现core.Parameterized，取值范围与配置一致
+func (e *MountainCarEnvironment) SetParameter(name string, value float64) error {
+	switch name {
+	case "goal_position":
+		if !(value > -0.6 && value <= 0.6) {
+			return fmt.Errorf("parameter goal_position must be in (-0.6, 0.6], got %v", value)
+		}
+		e.goalPosition = value
+	case "force":
+		if err := core.PositiveParameter(name, value); err != nil {
+			return err
+		}
+		e.force = value
+	case "gravity":
+		if !(value >= 0) || math.IsInf(value, 0) {
+			return fmt.Errorf("parameter gravity must be a non-negative finite number, got %v", value)
+		}
+		e.gravity = value
+	default:
+		return core.UnknownParameterError(name, e)
+	}
+	return nil
+}
+
 // Close 关闭环境
 func (e *MountainCarEnvironment) Close() error {
 	return e.BaseEnvironment.Close()
 }
 
+// Metadata 返回环境元数据：每步-1，到达目标时为0
+func (e *MountainCarEnvironment) Metadata() core.EnvMetadata {
+	return core.EnvMetadata{
+		RewardRange:     [2]float64{-1, 0},
+		MaxEpisodeSteps: e.maxSteps,
+	}
+}
+
 // GetSpaces 获取MountainCar场景的动作空间和观察空间定义
 func (e *MountainCarEnvironment) GetSpaces() core.SpaceDefinition {
 	return core.SpaceDefinition{
@@ -200,8 +273,8 @@ func (e *MountainCarEnvironment) GetSpaces() core.SpaceDefinition {
 		},
 		ObservationSpace: core.ObservationSpace{
 			Type:  core.SpaceTypeBox,
-			Low:   []float64{-1.2, -0.07}, // [position, velocity]
-			High:  []float64{0.6, 0.07},
+			Low:   []float64{e.minPosition, -e.maxSpeed}, // [position, velocity]
+			High:  []float64{e.maxPosition, e.maxSpeed},
 			Shape: []int32{2},
 			Dtype: "float32",
 		},
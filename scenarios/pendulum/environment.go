@@ -5,12 +5,38 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
-	"strconv"
 	"time"
 
 	"github.com/jelech/rl_env_engine/core"
+	"github.com/jelech/rl_env_engine/core/config"
 )
 
+// Config Pendulum环境配置
+// 物理参数默认值与OpenAI Gym的Pendulum-v1一致
+type Config struct {
+	MaxSteps  int     `cfg:"max_steps,default=200,min=1"`
+	MaxSpeed  float64 `cfg:"max_speed,default=8.0,gt=0"`
+	MaxTorque float64 `cfg:"max_torque,default=2.0,gt=0"`
+	Dt        float64 `cfg:"dt,default=0.05,gt=0"`
+	Gravity   float64 `cfg:"gravity,default=10.0,min=0"`
+	Mass      float64 `cfg:"mass,default=1.0,gt=0"`
+	Length    float64 `cfg:"length,default=1.0,gt=0"`
+}
+
+// DefaultConfig 返回默认配置
+func DefaultConfig() Config {
+	var cfg Config
+	config.MustDefaults(&cfg)
+	return cfg
+}
+
+// parseConfig 将core.Config绑定为Pendulum环境配置，未提供的字段使用默认值
+func parseConfig(src core.Config) (Config, error) {
+	var cfg Config
+	err := config.Bind(src, &cfg)
+	return cfg, err
+}
+
 // PendulumEnvironment 经典的倒立摆控制环境
 // 目标：通过施加扭矩来保持摆锤直立
 type PendulumEnvironment struct {
@@ -32,30 +58,28 @@ type PendulumEnvironment struct {
 	rng *rand.Rand
 }
 
-// NewPendulumEnvironment 创建新的Pendulum环境
-func NewPendulumEnvironment(config core.Config) *PendulumEnvironment {
+// NewPendulumEnvironment 创建新的Pendulum环境，配置无效时返回错误
+func NewPendulumEnvironment(config core.Config) (*PendulumEnvironment, error) {
+	cfg, err := parseConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return newPendulumEnvironment(config, cfg), nil
+}
+
+// newPendulumEnvironment 以绑定后的配置创建环境
+func newPendulumEnvironment(config core.Config, cfg Config) *PendulumEnvironment {
 	baseEnv := core.NewBaseEnvironment("pendulum", "Classic Pendulum control environment", config)
 
-	// 从配置中获取参数
-	maxSteps := 200
-	if val := config.GetValue("max_steps"); val != nil {
-		switch v := val.(type) {
-		case int:
-			maxSteps = v
-		case string:
-			if parsed, err := strconv.Atoi(v); err == nil {
-				maxSteps = parsed
-			}
-		}
-	}
+	maxSteps := cfg.MaxSteps
 
-	// 环境参数（基于OpenAI Gym的Pendulum-v1）
-	maxSpeed := 8.0
-	maxTorque := 2.0
-	dt := 0.05
-	g := 10.0
-	m := 1.0
-	l := 1.0
+	// 环境参数
+	maxSpeed := cfg.MaxSpeed
+	maxTorque := cfg.MaxTorque
+	dt := cfg.Dt
+	g := cfg.Gravity
+	m := cfg.Mass
+	l := cfg.Length
 
 	env := &PendulumEnvironment{
 		BaseEnvironment: baseEnv,
@@ -86,7 +110,7 @@ func (e *PendulumEnvironment) Reset(ctx context.Context) ([]core.Observation, er
 // Step 执行一步
 func (e *PendulumEnvironment) Step(ctx context.Context, actions []core.Action) ([]core.Observation, []float64, []bool, error) {
 	if len(actions) == 0 {
-		return nil, nil, nil, fmt.Errorf("no actions provided")
+		return nil, nil, nil, fmt.Errorf("%w: no actions provided", core.ErrInvalidAction)
 	}
 
 	e.currentStep++
@@ -99,12 +123,12 @@ func (e *PendulumEnvironment) Step(ctx context.Context, actions []core.Action) (
 		var err error
 		torque, err = genericAction.GetFloat64()
 		if err != nil {
-			return nil, nil, nil, fmt.Errorf("failed to extract action value: %w", err)
+			return nil, nil, nil, fmt.Errorf("%w: failed to extract action value: %w", core.ErrInvalidAction, err)
 		}
 	} else if pendulumAction, ok := actions[0].(*PendulumAction); ok {
 		torque = pendulumAction.Torque
 	} else {
-		return nil, nil, nil, fmt.Errorf("unsupported action type: %T", actions[0])
+		return nil, nil, nil, fmt.Errorf("%w: unsupported action type: %T", core.ErrInvalidAction, actions[0])
 	}
 
 	// 限制扭矩
@@ -150,14 +174,17 @@ func (e *PendulumEnvironment) GetObservations() []core.Observation {
 		e.thetaDot,
 	}
 
-	metadata := map[string]interface{}{
-		"theta":     e.theta,
-		"theta_dot": e.thetaDot,
-		"step":      e.currentStep,
-		"max_steps": e.maxSteps,
+	var metadata map[string]interface{}
+	if e.ObservationMetadataEnabled() {
+		metadata = map[string]interface{}{
+			"theta":     e.theta,
+			"theta_dot": e.thetaDot,
+			"step":      e.currentStep,
+			"max_steps": e.maxSteps,
+		}
 	}
 
-	observation := core.NewBaseObservation(data, metadata)
+	observation := e.AcquireObservation(data, metadata)
 	return []core.Observation{observation}
 }
 
@@ -169,25 +196,69 @@ func (e *PendulumEnvironment) GetReward() []float64 {
 	return []float64{reward}
 }
 
+// Parameters 实现core.Parameterized：可在回合之间修改的物理参数（max_speed与max_torque决定空间与奖励范围，不可修改）
+func (e *PendulumEnvironment) Parameters() map[string]float64 {
+	return map[string]float64{
+		"gravity": e.g,
+		"mass":    e.m,
+		"length":  e.l,
+		"dt":      e.dt,
+	}
+}
+
+// SetParameter 实现core.Parameterized
+func (e *PendulumEnvironment) SetParameter(name string, value float64) error {
+	if name == "gravity" {
+		// 与配置一致，重力可以为0
+		if !(value >= 0) || math.IsInf(value, 0) {
+			return fmt.Errorf("parameter gravity must be a non-negative finite number, got %v", value)
+		}
+		e.g = value
+		return nil
+	}
+	if err := core.PositiveParameter(name, value); err != nil {
+		return err
+	}
+	switch name {
+	case "mass":
+		e.m = value
+	case "length":
+		e.l = value
+	case "dt":
+		e.dt = value
+	default:
+		return core.UnknownParameterError(name, e)
+	}
+	return nil
+}
+
 // Close 关闭环境
 func (e *PendulumEnvironment) Close() error {
 	return e.BaseEnvironment.Close()
 }
 
+// Metadata 返回环境元数据：奖励为负成本，成本上限为 π² + 0.1·max_speed² + 0.001·max_torque²
+func (e *PendulumEnvironment) Metadata() core.EnvMetadata {
+	return core.EnvMetadata{
+		RewardRange:     [2]float64{-(math.Pi*math.Pi + 0.1*e.maxSpeed*e.maxSpeed + 0.001*e.maxTorque*e.maxTorque), 0},
+		MaxEpisodeSteps: e.maxSteps,
+	}
+}
+
 // GetSpaces 获取Pendulum场景的动作空间和观察空间定义
 func (e *PendulumEnvironment) GetSpaces() core.SpaceDefinition {
 	return core.SpaceDefinition{
 		ActionSpace: core.ActionSpace{
 			Type:  core.SpaceTypeBox,
-			Low:   []float64{-2.0}, // 扭矩范围
-			High:  []float64{2.0},
+			Low:   []float64{-e.maxTorque}, // 扭矩范围
+			High:  []float64{e.maxTorque},
 			Shape: []int32{1},
 			Dtype: "float32",
 		},
 		ObservationSpace: core.ObservationSpace{
 			Type:  core.SpaceTypeBox,
-			Low:   []float64{-1.0, -1.0, -8.0}, // [cos(theta), sin(theta), theta_dot]
-			High:  []float64{1.0, 1.0, 8.0},
+			Low:   []float64{-1.0, -1.0, -e.maxSpeed}, // [cos(theta), sin(theta), theta_dot]
+			High:  []float64{1.0, 1.0, e.maxSpeed},
 			Shape: []int32{3},
 			Dtype: "float32",
 		},
@@ -196,7 +267,12 @@ func (e *PendulumEnvironment) GetSpaces() core.SpaceDefinition {
 
 // angleNormalize 将角度规范化到 [-π, π]
 func angleNormalize(x float64) float64 {
-	return math.Mod(x+math.Pi, 2*math.Pi) - math.Pi
+	// math.Mod 对负数返回负余数，需要平移回 [0, 2π)
+	x = math.Mod(x+math.Pi, 2*math.Pi)
+	if x < 0 {
+		x += 2 * math.Pi
+	}
+	return x - math.Pi
 }
 
 // PendulumAction Pendulum专用动作
@@ -35,25 +35,15 @@ func (s *PendulumScenario) GetDescription() string {
 
 // CreateEnvironment 创建环境实例
 func (s *PendulumScenario) CreateEnvironment(config core.Config) (core.Environment, error) {
-	env := NewPendulumEnvironment(config)
-	return env, nil
+	cfg, err := parseConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pendulum environment: %w", err)
+	}
+	return newPendulumEnvironment(config, cfg), nil
 }
 
 // ValidateConfig 验证配置
 func (s *PendulumScenario) ValidateConfig(config core.Config) error {
-	// 验证max_steps
-	if val := config.GetValue("max_steps"); val != nil {
-		switch v := val.(type) {
-		case int:
-			if v <= 0 {
-				return fmt.Errorf("max_steps must be positive, got %d", v)
-			}
-		case string:
-			// 允许字符串形式的配置
-		default:
-			return fmt.Errorf("max_steps must be int or string, got %T", v)
-		}
-	}
-
-	return nil
+	_, err := parseConfig(config)
+	return err
 }
@@ -0,0 +1,420 @@
+package predatorprey
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/jelech/rl_env_engine/core"
+	"github.com/jelech/rl_env_engine/core/config"
+)
+
+// 移动动作
+const (
+	MoveStay = iota
+	MoveUp
+	MoveDown
+	MoveLeft
+	MoveRight
+	numMoves
+)
+
+// Config 捕食者-猎物环境配置
+type Config struct {
+	MaxSteps      int     `cfg:"max_steps,default=100,min=1"`
+	GridSize      int     `cfg:"grid_size,default=7,min=2"`
+	NumPredators  int     `cfg:"num_predators,default=2,min=1"`
+	NumPrey       int     `cfg:"num_prey,default=1,min=1"`
+	CaptureReward float64 `cfg:"capture_reward,default=1.0,min=0"`
+	StepPenalty   float64 `cfg:"step_penalty,default=0.01,min=0"` // 捕食者每步的惩罚，猎物每步获得相同的生存奖励
+	SharedReward  bool    `cfg:"shared_reward,default=true"`      // 为true时捕获奖励由所有捕食者共享（合作模式）
+	Seed          int64   `cfg:"seed"`
+}
+
+// DefaultConfig 返回默认配置
+func DefaultConfig() Config {
+	var cfg Config
+	config.MustDefaults(&cfg)
+	return cfg
+}
+
+// Validate 验证配置：cfg标签中的约束以及标签无法表达的字段间约束
+func (c Config) Validate() error {
+	if err := config.Validate(c); err != nil {
+		return err
+	}
+	if c.NumPredators+c.NumPrey > c.GridSize*c.GridSize {
+		return fmt.Errorf("grid %dx%d cannot hold %d agents", c.GridSize, c.GridSize, c.NumPredators+c.NumPrey)
+	}
+	return nil
+}
+
+// parseConfig 将core.Config绑定为捕食者-猎物配置，未提供的字段使用默认值
+func parseConfig(src core.Config) (Config, error) {
+	var cfg Config
+	if err := config.Bind(src, &cfg); err != nil {
+		return cfg, err
+	}
+	return cfg, cfg.Validate()
+}
+
+// agent 单个智能体的状态
+type agent struct {
+	x, y     int
+	predator bool
+	done     bool // 猎物被捕获后为true
+}
+
+// PredatorPreyEnvironment 网格世界中的多智能体捕食者-猎物环境
+// 智能体顺序为先捕食者后猎物（predator_0..predator_{n-1}, prey_0..prey_{m-1}），
+// Step 需要按该顺序为每个智能体提供一个动作（0: 不动, 1: 上, 2: 下, 3: 左, 4: 右）。
+// 捕食者移动到猎物所在格子即完成捕获，被捕获的猎物done为true且后续动作被忽略；
+// 所有猎物被捕获或达到最大步数时所有智能体结束
+type PredatorPreyEnvironment struct {
+	*core.BaseEnvironment
+	cfg Config
+
+	agents      []agent
+	currentStep int
+	captures    int
+	lastRewards []float64
+
+	rng *rand.Rand
+
+	obsBuf []float64 // GetObservations复用的观察数据缓冲区
+}
+
+// NewPredatorPreyEnvironment 创建新的捕食者-猎物环境
+func NewPredatorPreyEnvironment(config core.Config) (*PredatorPreyEnvironment, error) {
+	cfg, err := parseConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	baseEnv := core.NewBaseEnvironment("predator_prey", "Multi-agent predator-prey gridworld", config)
+
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	n := cfg.NumPredators + cfg.NumPrey
+	return &PredatorPreyEnvironment{
+		BaseEnvironment: baseEnv,
+		cfg:             cfg,
+		agents:          make([]agent, n),
+		lastRewards:     make([]float64, n),
+		rng:             rand.New(rand.NewSource(seed)),
+	}, nil
+}
+
+// NumAgents 返回智能体数量
+func (e *PredatorPreyEnvironment) NumAgents() int {
+	return len(e.agents)
+}
+
+// AgentName 返回智能体名称，如 predator_0、prey_1
+func (e *PredatorPreyEnvironment) AgentName(i int) string {
+	if i < e.cfg.NumPredators {
+		return fmt.Sprintf("predator_%d", i)
+	}
+	return fmt.Sprintf("prey_%d", i-e.cfg.NumPredators)
+}
+
+// Reset 重置环境：将所有智能体随机放置在互不重叠的格子上
+func (e *PredatorPreyEnvironment) Reset(ctx context.Context) ([]core.Observation, error) {
+	cells := e.rng.Perm(e.cfg.GridSize * e.cfg.GridSize)
+	for i := range e.agents {
+		e.agents[i] = agent{
+			x:        cells[i] % e.cfg.GridSize,
+			y:        cells[i] / e.cfg.GridSize,
+			predator: i < e.cfg.NumPredators,
+		}
+		e.lastRewards[i] = 0
+	}
+	e.currentStep = 0
+	e.captures = 0
+
+	return e.GetObservations(), nil
+}
+
+// Step 执行一步：所有智能体同时移动，然后结算捕获
+func (e *PredatorPreyEnvironment) Step(ctx context.Context, actions []core.Action) ([]core.Observation, []float64, []bool, error) {
+	actions = e.unbatch(actions)
+	if len(actions) != len(e.agents) {
+		return nil, nil, nil, fmt.Errorf("%w: expected %d actions (one per agent), got %d", core.ErrInvalidAction, len(e.agents), len(actions))
+	}
+
+	moves := make([]int, len(actions))
+	for i, action := range actions {
+		move, err := parseMove(action)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("agent %s: %w", e.AgentName(i), err)
+		}
+		moves[i] = move
+	}
+
+	rewards := make([]float64, len(e.agents))
+	for i := range e.agents {
+		a := &e.agents[i]
+		if a.done {
+			continue
+		}
+		a.x, a.y = e.move(a.x, a.y, moves[i])
+		if a.predator {
+			rewards[i] -= e.cfg.StepPenalty
+		} else {
+			rewards[i] += e.cfg.StepPenalty
+		}
+	}
+
+	// 结算捕获：与捕食者处于同一格子的存活猎物被捕获
+	for j := e.cfg.NumPredators; j < len(e.agents); j++ {
+		prey := &e.agents[j]
+		if prey.done {
+			continue
+		}
+		var catchers []int
+		for i := 0; i < e.cfg.NumPredators; i++ {
+			if e.agents[i].x == prey.x && e.agents[i].y == prey.y {
+				catchers = append(catchers, i)
+			}
+		}
+		if len(catchers) == 0 {
+			continue
+		}
+
+		prey.done = true
+		e.captures++
+		rewards[j] -= e.cfg.CaptureReward
+		if e.cfg.SharedReward {
+			for i := 0; i < e.cfg.NumPredators; i++ {
+				rewards[i] += e.cfg.CaptureReward
+			}
+		} else {
+			for _, i := range catchers {
+				rewards[i] += e.cfg.CaptureReward / float64(len(catchers))
+			}
+		}
+	}
+
+	e.currentStep++
+	episodeDone := e.captures == e.cfg.NumPrey || e.currentStep >= e.cfg.MaxSteps
+
+	dones := make([]bool, len(e.agents))
+	for i, a := range e.agents {
+		dones[i] = episodeDone || a.done
+	}
+	copy(e.lastRewards, rewards)
+
+	return e.GetObservations(), rewards, dones, nil
+}
+
+// unbatch 支持以单个数组动作批量提交所有智能体的动作，如 int_array [0, 3, 1]
+func (e *PredatorPreyEnvironment) unbatch(actions []core.Action) []core.Action {
+	if len(actions) != 1 || len(e.agents) == 1 {
+		return actions
+	}
+	genericAction, ok := actions[0].(*core.GenericAction)
+	if !ok {
+		return actions
+	}
+	values, err := genericAction.GetFloat64Slice()
+	if err != nil || len(values) != len(e.agents) {
+		return actions
+	}
+	unbatched := make([]core.Action, len(values))
+	for i, v := range values {
+		unbatched[i] = core.NewGenericAction(v)
+	}
+	return unbatched
+}
+
+// parseMove 从动作中解析移动方向
+func parseMove(action core.Action) (int, error) {
+	var move int
+	if genericAction, ok := action.(*core.GenericAction); ok {
+		value, err := genericAction.GetInt64()
+		if err != nil {
+			return 0, fmt.Errorf("%w: failed to extract action value: %w", core.ErrInvalidAction, err)
+		}
+		move = int(value)
+	} else if ppAction, ok := action.(*PredatorPreyAction); ok {
+		move = ppAction.Move
+	} else {
+		return 0, fmt.Errorf("%w: unsupported action type: %T", core.ErrInvalidAction, action)
+	}
+	if move < 0 || move >= numMoves {
+		return 0, fmt.Errorf("%w: move must be in [0, %d), got %d", core.ErrInvalidAction, numMoves, move)
+	}
+	return move, nil
+}
+
+// move 计算移动后的位置，撞墙时保持不动
+func (e *PredatorPreyEnvironment) move(x, y, move int) (int, int) {
+	switch move {
+	case MoveUp:
+		y--
+	case MoveDown:
+		y++
+	case MoveLeft:
+		x--
+	case MoveRight:
+		x++
+	}
+	if x < 0 {
+		x = 0
+	} else if x >= e.cfg.GridSize {
+		x = e.cfg.GridSize - 1
+	}
+	if y < 0 {
+		y = 0
+	} else if y >= e.cfg.GridSize {
+		y = e.cfg.GridSize - 1
+	}
+	return x, y
+}
+
+// GetObservations 获取每个智能体的观察
+// [自身x, 自身y, 是否捕食者, 其余智能体按顺序的 (dx, dy, 是否存活)...]，坐标按网格大小归一化
+func (e *PredatorPreyEnvironment) GetObservations() []core.Observation {
+	scale := float64(e.cfg.GridSize - 1)
+	observations := make([]core.Observation, len(e.agents))
+	for i, a := range e.agents {
+		data := core.ResetBuffer(&e.obsBuf, e.obsDim())[:0]
+		data = append(data, float64(a.x)/scale, float64(a.y)/scale, boolToFloat(a.predator))
+		for j, other := range e.agents {
+			if j == i {
+				continue
+			}
+			data = append(data,
+				float64(other.x-a.x)/scale,
+				float64(other.y-a.y)/scale,
+				boolToFloat(!other.done),
+			)
+		}
+
+		var metadata map[string]interface{}
+		if e.ObservationMetadataEnabled() {
+			metadata = map[string]interface{}{
+				"agent_id":   i,
+				"agent_name": e.AgentName(i),
+				"predator":   a.predator,
+				"caught":     a.done,
+				"x":          a.x,
+				"y":          a.y,
+				"step":       e.currentStep,
+				"max_steps":  e.cfg.MaxSteps,
+			}
+		}
+		observations[i] = e.AcquireObservation(data, metadata)
+	}
+	return observations
+}
+
+// obsDim 单个智能体观察向量的长度
+func (e *PredatorPreyEnvironment) obsDim() int {
+	return 3 + 3*(len(e.agents)-1)
+}
+
+// GetReward 返回最近一步各智能体的奖励
+func (e *PredatorPreyEnvironment) GetReward() []float64 {
+	rewards := make([]float64, len(e.lastRewards))
+	copy(rewards, e.lastRewards)
+	return rewards
+}
+
+// GetInfo 获取环境信息
+func (e *PredatorPreyEnvironment) GetInfo() map[string]interface{} {
+	info := e.BaseEnvironment.GetInfo()
+	agents := make([]interface{}, len(e.agents))
+	for i := range e.agents {
+		agents[i] = e.AgentName(i)
+	}
+	info["num_agents"] = len(e.agents)
+	info["agents"] = agents
+	info["captures"] = e.captures
+	return info
+}
+
+// Close 关闭环境
+func (e *PredatorPreyEnvironment) Close() error {
+	e.agents = nil
+	return e.BaseEnvironment.Close()
+}
+
+// Metadata 返回环境元数据：捕食者每步扣除step_penalty、捕获时获得capture_reward（非共享时由同格捕食者平分），
+// 猎物每步获得step_penalty、被捕获时扣除capture_reward
+func (e *PredatorPreyEnvironment) Metadata() core.EnvMetadata {
+	metadata := core.DefaultEnvMetadata()
+	metadata.MaxEpisodeSteps = e.cfg.MaxSteps
+	metadata.RewardRange = [2]float64{
+		math.Min(-e.cfg.StepPenalty, e.cfg.StepPenalty-e.cfg.CaptureReward),
+		math.Max(e.cfg.StepPenalty, float64(e.cfg.NumPrey)*e.cfg.CaptureReward-e.cfg.StepPenalty),
+	}
+	return metadata
+}
+
+// GetSpaces 获取单个智能体的动作空间和观察空间定义（所有智能体同构）
+func (e *PredatorPreyEnvironment) GetSpaces() core.SpaceDefinition {
+	dim := e.obsDim()
+	low := make([]float64, dim)
+	high := make([]float64, dim)
+	for i := range low {
+		low[i], high[i] = -1, 1
+	}
+	// 自身坐标与类型标志为 [0, 1]
+	low[0], low[1], low[2] = 0, 0, 0
+	for i := 5; i < dim; i += 3 {
+		low[i] = 0
+	}
+
+	return core.SpaceDefinition{
+		ActionSpace: core.ActionSpace{
+			Type:  core.SpaceTypeDiscrete,
+			Low:   []float64{0},
+			High:  []float64{numMoves - 1}, // 0: 不动, 1: 上, 2: 下, 3: 左, 4: 右
+			Shape: []int32{},
+			Dtype: "int32",
+		},
+		ObservationSpace: core.ObservationSpace{
+			Type:  core.SpaceTypeBox,
+			Low:   low,
+			High:  high,
+			Shape: []int32{int32(dim)},
+			Dtype: "float32",
+		},
+	}
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// PredatorPreyAction 捕食者-猎物专用动作
+type PredatorPreyAction struct {
+	Move int // 0: 不动, 1: 上, 2: 下, 3: 左, 4: 右
+}
+
+// NewPredatorPreyAction 创建新的移动动作
+func NewPredatorPreyAction(move int) *PredatorPreyAction {
+	return &PredatorPreyAction{Move: move}
+}
+
+// GetData 获取动作数据
+func (a *PredatorPreyAction) GetData() interface{} {
+	return a.Move
+}
+
+// Validate 验证动作
+func (a *PredatorPreyAction) Validate() error {
+	if a.Move < 0 || a.Move >= numMoves {
+		return fmt.Errorf("predator-prey move must be in [0, %d), got %d", numMoves, a.Move)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package predatorprey
+
+import (
+	"fmt"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// PredatorPreyScenario 捕食者-猎物场景实现
+type PredatorPreyScenario struct {
+	name        string
+	description string
+}
+
+// 确保PredatorPreyScenario实现了core.Scenario接口
+var _ core.Scenario = (*PredatorPreyScenario)(nil)
+
+// NewPredatorPreyScenario 创建新的捕食者-猎物场景
+func NewPredatorPreyScenario() *PredatorPreyScenario {
+	return &PredatorPreyScenario{
+		name:        "predator_prey",
+		description: "Multi-agent predator-prey gridworld with configurable team sizes",
+	}
+}
+
+// GetName 获取场景名称
+func (s *PredatorPreyScenario) GetName() string {
+	return s.name
+}
+
+// GetDescription 获取场景描述
+func (s *PredatorPreyScenario) GetDescription() string {
+	return s.description
+}
+
+// CreateEnvironment 创建环境实例
+func (s *PredatorPreyScenario) CreateEnvironment(config core.Config) (core.Environment, error) {
+	env, err := NewPredatorPreyEnvironment(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create predator-prey environment: %w", err)
+	}
+	return env, nil
+}
+
+// ValidateConfig 验证配置
+func (s *PredatorPreyScenario) ValidateConfig(config core.Config) error {
+	if config == nil {
+		return fmt.Errorf("config cannot be nil")
+	}
+	_, err := parseConfig(config)
+	return err
+}
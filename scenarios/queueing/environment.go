@@ -0,0 +1,293 @@
+package queueing
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/jelech/rl_env_engine/core"
+	"github.com/jelech/rl_env_engine/core/config"
+)
+
+// Config 排队环境配置
+type Config struct {
+	MaxSteps     int       `cfg:"max_steps,default=500,min=1"`
+	NumServers   int       `cfg:"num_servers,default=4,min=1"`
+	ArrivalRate  float64   `cfg:"arrival_rate,default=3.0,gt=0"`  // 泊松到达率（任务/单位时间）
+	MeanJobSize  float64   `cfg:"mean_job_size,default=1.0,gt=0"` // 任务工作量均值（指数分布）
+	ServiceRates []float64 `cfg:"service_rates"`                  // 各服务器处理速度，为空时自动生成异构速度
+	MaxQueue     int       `cfg:"max_queue,default=100,min=1"`    // 观察空间中队列长度的上界
+	Seed         int64     `cfg:"seed"`
+}
+
+// DefaultConfig 返回默认配置
+func DefaultConfig() Config {
+	var cfg Config
+	config.MustDefaults(&cfg)
+	return cfg
+}
+
+// Validate 验证配置：cfg标签中的约束以及标签无法表达的字段间约束
+func (c Config) Validate() error {
+	if err := config.Validate(c); err != nil {
+		return err
+	}
+	if len(c.ServiceRates) != 0 && len(c.ServiceRates) != c.NumServers {
+		return fmt.Errorf("service_rates must have num_servers (%d) entries, got %d", c.NumServers, len(c.ServiceRates))
+	}
+	for i, r := range c.ServiceRates {
+		if r <= 0 {
+			return fmt.Errorf("service_rates[%d] must be positive, got %f", i, r)
+		}
+	}
+	return nil
+}
+
+// parseConfig 将core.Config绑定为排队配置，未提供的字段使用默认值
+func parseConfig(src core.Config) (Config, error) {
+	var cfg Config
+	if err := config.Bind(src, &cfg); err != nil {
+		return cfg, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return cfg, err
+	}
+
+	// 默认异构速度：在 [0.5, 1.5] 上均匀分布
+	if len(cfg.ServiceRates) == 0 {
+		cfg.ServiceRates = make([]float64, cfg.NumServers)
+		for i := range cfg.ServiceRates {
+			if cfg.NumServers == 1 {
+				cfg.ServiceRates[i] = 1.0
+			} else {
+				cfg.ServiceRates[i] = 0.5 + float64(i)/float64(cfg.NumServers-1)
+			}
+		}
+	}
+	return cfg, nil
+}
+
+// QueueingEnvironment 负载均衡环境
+// 每一步对应一个新到达的任务，动作选择将其路由到哪台服务器（FIFO队列），
+// 奖励为该任务的负延迟（排队等待时间 + 服务时间）
+type QueueingEnvironment struct {
+	*core.BaseEnvironment
+	cfg Config
+
+	clock        float64     // 当前仿真时间（即当前任务到达时刻）
+	completions  [][]float64 // 每台服务器上未完成任务的完成时刻（升序）
+	jobSize      float64     // 当前待路由任务的工作量
+	currentStep  int
+	lastLatency  float64
+	totalLatency float64
+
+	rng *rand.Rand
+
+	obsBuf []float64 // GetObservations复用的观察数据缓冲区
+}
+
+// NewQueueingEnvironment 创建新的排队环境
+func NewQueueingEnvironment(config core.Config) (*QueueingEnvironment, error) {
+	cfg, err := parseConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	baseEnv := core.NewBaseEnvironment("queueing", "Job routing / load balancing environment", config)
+
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	return &QueueingEnvironment{
+		BaseEnvironment: baseEnv,
+		cfg:             cfg,
+		completions:     make([][]float64, cfg.NumServers),
+		rng:             rand.New(rand.NewSource(seed)),
+	}, nil
+}
+
+// Reset 重置环境：清空所有队列并生成第一个任务
+func (e *QueueingEnvironment) Reset(ctx context.Context) ([]core.Observation, error) {
+	e.clock = 0
+	for i := range e.completions {
+		e.completions[i] = e.completions[i][:0]
+	}
+	e.currentStep = 0
+	e.lastLatency = 0
+	e.totalLatency = 0
+	e.jobSize = e.sampleJobSize()
+
+	return e.GetObservations(), nil
+}
+
+// Step 执行一步：路由当前任务，然后推进时间到下一个任务到达
+func (e *QueueingEnvironment) Step(ctx context.Context, actions []core.Action) ([]core.Observation, []float64, []bool, error) {
+	if len(actions) == 0 {
+		return nil, nil, nil, fmt.Errorf("%w: no actions provided", core.ErrInvalidAction)
+	}
+
+	var server int
+	if genericAction, ok := actions[0].(*core.GenericAction); ok {
+		value, err := genericAction.GetInt64()
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("%w: failed to extract action value: %w", core.ErrInvalidAction, err)
+		}
+		server = int(value)
+	} else if queueingAction, ok := actions[0].(*QueueingAction); ok {
+		server = queueingAction.Server
+	} else {
+		return nil, nil, nil, fmt.Errorf("%w: unsupported action type: %T", core.ErrInvalidAction, actions[0])
+	}
+
+	if server < 0 || server >= e.cfg.NumServers {
+		return nil, nil, nil, fmt.Errorf("server index must be in [0, %d), got %d", e.cfg.NumServers, server)
+	}
+
+	// FIFO：任务在该服务器上最后一个任务完成后开始处理
+	start := e.clock
+	if queue := e.completions[server]; len(queue) > 0 && queue[len(queue)-1] > start {
+		start = queue[len(queue)-1]
+	}
+	finish := start + e.jobSize/e.cfg.ServiceRates[server]
+	e.completions[server] = append(e.completions[server], finish)
+
+	e.lastLatency = finish - e.clock
+	e.totalLatency += e.lastLatency
+	e.currentStep++
+
+	// 推进到下一个任务到达时刻，并移除已完成的任务
+	e.clock += e.rng.ExpFloat64() / e.cfg.ArrivalRate
+	e.drainCompleted()
+	e.jobSize = e.sampleJobSize()
+
+	done := e.currentStep >= e.cfg.MaxSteps
+
+	return e.GetObservations(), []float64{-e.lastLatency}, []bool{done}, nil
+}
+
+// drainCompleted 移除在当前时刻之前已完成的任务
+func (e *QueueingEnvironment) drainCompleted() {
+	for i, queue := range e.completions {
+		n := 0
+		for n < len(queue) && queue[n] <= e.clock {
+			n++
+		}
+		e.completions[i] = append(queue[:0], queue[n:]...)
+	}
+}
+
+// sampleJobSize 采样任务工作量
+func (e *QueueingEnvironment) sampleJobSize() float64 {
+	return e.rng.ExpFloat64() * e.cfg.MeanJobSize
+}
+
+// GetObservations 获取当前观察
+// [各服务器队列长度..., 各服务器剩余工作时间..., 各服务器处理速度..., 当前任务工作量]
+func (e *QueueingEnvironment) GetObservations() []core.Observation {
+	k := e.cfg.NumServers
+	data := core.ResetBuffer(&e.obsBuf, 3*k+1)
+	for i, queue := range e.completions {
+		data[i] = float64(len(queue))
+		if len(queue) > 0 {
+			data[k+i] = queue[len(queue)-1] - e.clock
+		}
+		data[2*k+i] = e.cfg.ServiceRates[i]
+	}
+	data[3*k] = e.jobSize
+
+	avgLatency := 0.0
+	if e.currentStep > 0 {
+		avgLatency = e.totalLatency / float64(e.currentStep)
+	}
+
+	var metadata map[string]interface{}
+	if e.ObservationMetadataEnabled() {
+		metadata = map[string]interface{}{
+			"clock":        e.clock,
+			"job_size":     e.jobSize,
+			"last_latency": e.lastLatency,
+			"avg_latency":  avgLatency,
+			"step":         e.currentStep,
+			"max_steps":    e.cfg.MaxSteps,
+		}
+	}
+
+	observation := e.AcquireObservation(data, metadata)
+	return []core.Observation{observation}
+}
+
+// GetReward 返回最近一个任务的负延迟
+func (e *QueueingEnvironment) GetReward() []float64 {
+	return []float64{-e.lastLatency}
+}
+
+// Close 关闭环境
+func (e *QueueingEnvironment) Close() error {
+	e.completions = nil
+	return e.BaseEnvironment.Close()
+}
+
+// Metadata 返回环境元数据：奖励为最近一个任务的负延迟，不超过0，下界无界
+func (e *QueueingEnvironment) Metadata() core.EnvMetadata {
+	metadata := core.DefaultEnvMetadata()
+	metadata.MaxEpisodeSteps = e.cfg.MaxSteps
+	metadata.RewardRange[1] = 0
+	return metadata
+}
+
+// GetSpaces 获取排队场景的动作空间和观察空间定义
+func (e *QueueingEnvironment) GetSpaces() core.SpaceDefinition {
+	k := e.cfg.NumServers
+	low := make([]float64, 3*k+1)
+	high := make([]float64, 3*k+1)
+	for i := 0; i < k; i++ {
+		high[i] = float64(e.cfg.MaxQueue)
+		high[k+i] = 1e6
+		high[2*k+i] = e.cfg.ServiceRates[i]
+		low[2*k+i] = e.cfg.ServiceRates[i]
+	}
+	high[3*k] = 1e6
+
+	return core.SpaceDefinition{
+		ActionSpace: core.ActionSpace{
+			Type:  core.SpaceTypeDiscrete,
+			Low:   []float64{0},
+			High:  []float64{float64(k - 1)},
+			Shape: []int32{},
+			Dtype: "int32",
+		},
+		ObservationSpace: core.ObservationSpace{
+			Type:  core.SpaceTypeBox,
+			Low:   low,
+			High:  high,
+			Shape: []int32{int32(3*k + 1)},
+			Dtype: "float32",
+		},
+	}
+}
+
+// QueueingAction 排队专用动作
+type QueueingAction struct {
+	Server int // 目标服务器索引
+}
+
+// NewQueueingAction 创建新的排队动作
+func NewQueueingAction(server int) *QueueingAction {
+	return &QueueingAction{Server: server}
+}
+
+// GetData 获取动作数据
+func (a *QueueingAction) GetData() interface{} {
+	return a.Server
+}
+
+// Validate 验证动作
+func (a *QueueingAction) Validate() error {
+	if a.Server < 0 {
+		return fmt.Errorf("queueing server index must be non-negative, got %d", a.Server)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package queueing
+
+import (
+	"fmt"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// QueueingScenario 排队/负载均衡场景实现
+type QueueingScenario struct {
+	name        string
+	description string
+}
+
+// 确保QueueingScenario实现了core.Scenario接口
+var _ core.Scenario = (*QueueingScenario)(nil)
+
+// NewQueueingScenario 创建新的排队场景
+func NewQueueingScenario() *QueueingScenario {
+	return &QueueingScenario{
+		name:        "queueing",
+		description: "Load balancing environment - route arriving jobs to K heterogeneous servers",
+	}
+}
+
+// GetName 获取场景名称
+func (s *QueueingScenario) GetName() string {
+	return s.name
+}
+
+// GetDescription 获取场景描述
+func (s *QueueingScenario) GetDescription() string {
+	return s.description
+}
+
+// CreateEnvironment 创建环境实例
+func (s *QueueingScenario) CreateEnvironment(config core.Config) (core.Environment, error) {
+	env, err := NewQueueingEnvironment(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create queueing environment: %w", err)
+	}
+	return env, nil
+}
+
+// ValidateConfig 验证配置
+func (s *QueueingScenario) ValidateConfig(config core.Config) error {
+	if config == nil {
+		return fmt.Errorf("config cannot be nil")
+	}
+	_, err := parseConfig(config)
+	return err
+}
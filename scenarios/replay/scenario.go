@@ -0,0 +1,74 @@
+package replay
+
+import (
+	"fmt"
+
+	"github.com/jelech/rl_env_engine/core"
+	"github.com/jelech/rl_env_engine/core/config"
+	"github.com/jelech/rl_env_engine/core/record"
+)
+
+// Config 回放环境配置
+type Config struct {
+	Path          string  `cfg:"path,required"`                    // JSONL轨迹文件路径（位于服务端）
+	VerifyActions bool    `cfg:"verify_actions,default=false"`     // 校验传入的动作与录制的动作一致
+	Tolerance     float64 `cfg:"tolerance,default=0.000001,min=0"` // 校验数值动作时允许的绝对误差
+	Loop          bool    `cfg:"loop,default=true"`                // 播放完后从第一个回合重新开始
+}
+
+// parseConfig 将core.Config绑定为回放环境配置
+func parseConfig(src core.Config) (Config, error) {
+	var cfg Config
+	err := config.Bind(src, &cfg)
+	return cfg, err
+}
+
+// ReplayScenario 轨迹回放场景实现
+type ReplayScenario struct {
+	name        string
+	description string
+}
+
+// 确保ReplayScenario实现了core.Scenario接口
+var _ core.Scenario = (*ReplayScenario)(nil)
+
+// NewReplayScenario 创建新的轨迹回放场景
+func NewReplayScenario() *ReplayScenario {
+	return &ReplayScenario{
+		name:        "replay",
+		description: "Replays a recorded JSONL trajectory, optionally verifying that incoming actions match the recording",
+	}
+}
+
+// GetName 获取场景名称
+func (s *ReplayScenario) GetName() string {
+	return s.name
+}
+
+// GetDescription 获取场景描述
+func (s *ReplayScenario) GetDescription() string {
+	return s.description
+}
+
+// CreateEnvironment 创建环境实例
+func (s *ReplayScenario) CreateEnvironment(config core.Config) (core.Environment, error) {
+	cfg, err := parseConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	env, err := record.OpenReplay(cfg.Path, record.ReplayOptions{
+		VerifyActions: cfg.VerifyActions,
+		Tolerance:     cfg.Tolerance,
+		Loop:          cfg.Loop,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create replay environment: %w", err)
+	}
+	return env, nil
+}
+
+// ValidateConfig 验证配置
+func (s *ReplayScenario) ValidateConfig(config core.Config) error {
+	_, err := parseConfig(config)
+	return err
+}
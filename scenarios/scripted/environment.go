@@ -0,0 +1,500 @@
+package scripted
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jelech/rl_env_engine/core"
+	"github.com/jelech/rl_env_engine/core/config"
+)
+
+// 动作类型
+const (
+	ActionContinuous = "continuous"
+	ActionDiscrete   = "discrete"
+)
+
+// Config 脚本化环境配置，动力学与奖励全部由表达式描述，例如：
+//
+//	{
+//	  "state_vars": ["x", "v"],
+//	  "params": {"dt": 0.05, "k": 1.0},
+//	  "init": {"x": "uniform(-1, 1)", "v": "0"},
+//	  "dynamics": {"x": "x + v*dt", "v": "v + (a - k*x)*dt"},
+//	  "reward": "-(x^2 + 0.1*a^2)",
+//	  "done": "abs(x) > 5",
+//	  "action_type": "continuous", "action_low": -1, "action_high": 1
+//	}
+//
+// 表达式中可用的变量：状态变量、参数、动作 a（即 a0）与 a0..a{n-1}、当前步数 t
+type Config struct {
+	MaxSteps    int                `cfg:"max_steps,default=200,min=1"`
+	StateVars   []string           `cfg:"state_vars"`
+	Params      map[string]float64 `cfg:"params"`
+	Init        map[string]string  `cfg:"init"`             // 初始状态表达式（可使用参数与随机函数），缺省为0
+	Dynamics    map[string]string  `cfg:"dynamics"`         // 状态转移表达式，所有状态同时更新，缺省为保持不变
+	Reward      string             `cfg:"reward,default=0"` // 奖励表达式，在转移后求值，可使用 prev_<状态名> 访问转移前状态
+	Done        string             `cfg:"done,default=0"`   // 终止条件表达式，非零为终止
+	Observation []string           `cfg:"observation"`      // 观察表达式列表，缺省为全部状态变量
+	ActionType  string             `cfg:"action_type,default=continuous,oneof=continuous|discrete"`
+	ActionDim   int                `cfg:"action_dim,default=1"`  // 连续动作维度
+	NumActions  int                `cfg:"num_actions,default=2"` // 离散动作数，动作取值为 0..num_actions-1
+	ActionLow   float64            `cfg:"action_low,default=-1"` // 连续动作下界（动作会被裁剪）
+	ActionHigh  float64            `cfg:"action_high,default=1"` // 连续动作上界
+	Seed        int64              `cfg:"seed"`
+}
+
+// DefaultConfig 返回默认配置
+func DefaultConfig() Config {
+	var cfg Config
+	config.MustDefaults(&cfg)
+	return cfg
+}
+
+// Validate 验证配置（表达式的语法与变量在编译时检查）
+func (c Config) Validate() error {
+	if err := config.Validate(c); err != nil {
+		return err
+	}
+	if len(c.StateVars) == 0 {
+		return fmt.Errorf("state_vars must not be empty")
+	}
+	switch c.ActionType {
+	case ActionContinuous:
+		if c.ActionDim <= 0 {
+			return fmt.Errorf("action_dim must be positive, got %d", c.ActionDim)
+		}
+		if c.ActionLow > c.ActionHigh {
+			return fmt.Errorf("action_low (%f) must not exceed action_high (%f)", c.ActionLow, c.ActionHigh)
+		}
+	case ActionDiscrete:
+		if c.NumActions <= 0 {
+			return fmt.Errorf("num_actions must be positive, got %d", c.NumActions)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, name := range c.StateVars {
+		if seen[name] {
+			return fmt.Errorf("duplicate state variable %q", name)
+		}
+		seen[name] = true
+		if _, ok := c.Params[name]; ok {
+			return fmt.Errorf("%q is both a state variable and a parameter", name)
+		}
+	}
+	for name := range c.Init {
+		if !seen[name] {
+			return fmt.Errorf("init refers to unknown state variable %q", name)
+		}
+	}
+	for name := range c.Dynamics {
+		if !seen[name] {
+			return fmt.Errorf("dynamics refers to unknown state variable %q", name)
+		}
+	}
+	return nil
+}
+
+// parseConfig 将core.Config绑定为脚本化环境配置，未提供的字段使用默认值
+func parseConfig(src core.Config) (Config, error) {
+	var cfg Config
+	if err := config.Bind(src, &cfg); err != nil {
+		return cfg, err
+	}
+	return cfg, cfg.Validate()
+}
+
+// program 编译后的环境脚本
+// 变量槽位布局：[状态..., prev_状态..., 参数..., a0..a{n-1}, t]
+type program struct {
+	numState  int
+	numAction int
+	slotPrev  int
+	slotParam int
+	slotAct   int
+	slotT     int
+	numSlots  int
+
+	params   []float64
+	init     []*Expr // 与状态一一对应，nil表示0
+	dynamics []*Expr // 与状态一一对应，nil表示保持不变
+	reward   *Expr
+	done     *Expr
+	obs      []*Expr // 为空表示直接输出状态
+}
+
+// compile 将配置编译为可执行脚本
+func compile(cfg Config) (*program, error) {
+	numAction := cfg.ActionDim
+	if cfg.ActionType == ActionDiscrete {
+		numAction = 1
+	}
+
+	paramNames := make([]string, 0, len(cfg.Params))
+	for name := range cfg.Params {
+		paramNames = append(paramNames, name)
+	}
+	sort.Strings(paramNames)
+
+	n := len(cfg.StateVars)
+	p := &program{
+		numState:  n,
+		numAction: numAction,
+		slotPrev:  n,
+		slotParam: 2 * n,
+		slotAct:   2*n + len(paramNames),
+	}
+	p.slotT = p.slotAct + numAction
+	p.numSlots = p.slotT + 1
+
+	// initVars 仅包含参数，initial表达式不能引用状态
+	initVars := make(map[string]int)
+	vars := make(map[string]int)
+	declare := func(name string, slot int) error {
+		if _, exists := vars[name]; exists {
+			return fmt.Errorf("variable name %q is already defined", name)
+		}
+		vars[name] = slot
+		return nil
+	}
+	for i, name := range cfg.StateVars {
+		if err := declare(name, i); err != nil {
+			return nil, err
+		}
+		if err := declare("prev_"+name, p.slotPrev+i); err != nil {
+			return nil, err
+		}
+	}
+	for i, name := range paramNames {
+		if err := declare(name, p.slotParam+i); err != nil {
+			return nil, err
+		}
+		initVars[name] = p.slotParam + i
+		p.params = append(p.params, cfg.Params[name])
+	}
+	for i := 0; i < numAction; i++ {
+		if err := declare("a"+strconv.Itoa(i), p.slotAct+i); err != nil {
+			return nil, err
+		}
+	}
+	if err := declare("a", p.slotAct); err != nil {
+		return nil, err
+	}
+	if err := declare("t", p.slotT); err != nil {
+		return nil, err
+	}
+
+	var err error
+	p.init = make([]*Expr, n)
+	p.dynamics = make([]*Expr, n)
+	for i, name := range cfg.StateVars {
+		if src, ok := cfg.Init[name]; ok {
+			if p.init[i], err = Compile(src, initVars); err != nil {
+				return nil, fmt.Errorf("init.%s: %w", name, err)
+			}
+		}
+		if src, ok := cfg.Dynamics[name]; ok {
+			if p.dynamics[i], err = Compile(src, vars); err != nil {
+				return nil, fmt.Errorf("dynamics.%s: %w", name, err)
+			}
+		}
+	}
+	if p.reward, err = Compile(cfg.Reward, vars); err != nil {
+		return nil, fmt.Errorf("reward: %w", err)
+	}
+	if p.done, err = Compile(cfg.Done, vars); err != nil {
+		return nil, fmt.Errorf("done: %w", err)
+	}
+	for i, src := range cfg.Observation {
+		expr, err := Compile(src, vars)
+		if err != nil {
+			return nil, fmt.Errorf("observation[%d]: %w", i, err)
+		}
+		p.obs = append(p.obs, expr)
+	}
+	return p, nil
+}
+
+// ScriptedEnvironment 由表达式定义动力学与奖励的环境，无需编写和编译Go代码即可通过API创建
+type ScriptedEnvironment struct {
+	*core.BaseEnvironment
+	cfg  Config
+	prog *program
+
+	scope       scope
+	currentStep int
+	lastReward  float64
+	totalReward float64
+
+	obsBuf []float64 // GetObservations复用的观察数据缓冲区
+}
+
+// NewScriptedEnvironment 创建新的脚本化环境，表达式错误会在此处返回
+func NewScriptedEnvironment(config core.Config) (*ScriptedEnvironment, error) {
+	cfg, err := parseConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	prog, err := compile(cfg)
+	if err != nil {
+		return nil, core.NewSimulationError(core.ErrConfigInvalid, "failed to compile scripted environment", err)
+	}
+
+	baseEnv := core.NewBaseEnvironment("scripted", "Environment defined by user-supplied expressions", config)
+
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	env := &ScriptedEnvironment{
+		BaseEnvironment: baseEnv,
+		cfg:             cfg,
+		prog:            prog,
+		scope: scope{
+			vars: make([]float64, prog.numSlots),
+			rng:  rand.New(rand.NewSource(seed)),
+		},
+	}
+	copy(env.scope.vars[prog.slotParam:], prog.params)
+	return env, nil
+}
+
+// Reset 重置环境：按init表达式计算初始状态
+func (e *ScriptedEnvironment) Reset(ctx context.Context) ([]core.Observation, error) {
+	p := e.prog
+	vars := e.scope.vars
+	for i := range vars {
+		if i < p.slotParam || i >= p.slotAct {
+			vars[i] = 0
+		}
+	}
+	for i, expr := range p.init {
+		if expr != nil {
+			vars[i] = expr.eval(&e.scope)
+		}
+	}
+	copy(vars[p.slotPrev:p.slotParam], vars[:p.numState])
+	e.currentStep = 0
+	e.lastReward = 0
+	e.totalReward = 0
+
+	return e.GetObservations(), nil
+}
+
+// Step 执行一步：同时更新所有状态，然后计算奖励与终止条件
+func (e *ScriptedEnvironment) Step(ctx context.Context, actions []core.Action) ([]core.Observation, []float64, []bool, error) {
+	if len(actions) == 0 {
+		return nil, nil, nil, fmt.Errorf("%w: no actions provided", core.ErrInvalidAction)
+	}
+	action, err := e.parseAction(actions[0])
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	p := e.prog
+	vars := e.scope.vars
+	copy(vars[p.slotAct:p.slotT], action)
+	vars[p.slotT] = float64(e.currentStep)
+
+	// 先基于旧状态计算所有新值，再统一写回
+	next := make([]float64, p.numState)
+	for i, expr := range p.dynamics {
+		if expr != nil {
+			next[i] = expr.eval(&e.scope)
+		} else {
+			next[i] = vars[i]
+		}
+	}
+	copy(vars[p.slotPrev:p.slotParam], vars[:p.numState])
+	copy(vars[:p.numState], next)
+
+	e.currentStep++
+	vars[p.slotT] = float64(e.currentStep)
+	e.lastReward = p.reward.eval(&e.scope)
+	e.totalReward += e.lastReward
+
+	done := p.done.eval(&e.scope) != 0 || e.currentStep >= e.cfg.MaxSteps
+	for _, v := range next {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			done = true
+			break
+		}
+	}
+
+	return e.GetObservations(), []float64{e.lastReward}, []bool{done}, nil
+}
+
+// parseAction 将动作解析为与动作槽位对应的数值，连续动作会裁剪到 [action_low, action_high]
+func (e *ScriptedEnvironment) parseAction(action core.Action) ([]float64, error) {
+	var values []float64
+	switch a := action.(type) {
+	case *core.GenericAction:
+		if slice, err := a.GetFloat64Slice(); err == nil {
+			values = slice
+		} else if v, err := a.GetFloat64(); err == nil {
+			values = []float64{v}
+		} else {
+			return nil, fmt.Errorf("%w: failed to extract action value: %w", core.ErrInvalidAction, err)
+		}
+	case *ScriptedAction:
+		values = a.Values
+	default:
+		return nil, fmt.Errorf("%w: unsupported action type: %T", core.ErrInvalidAction, action)
+	}
+
+	if e.cfg.ActionType == ActionDiscrete {
+		if len(values) != 1 {
+			return nil, fmt.Errorf("%w: discrete action must be a single value, got %d", core.ErrInvalidAction, len(values))
+		}
+		idx := int(values[0])
+		if float64(idx) != values[0] || idx < 0 || idx >= e.cfg.NumActions {
+			return nil, fmt.Errorf("%w: discrete action must be an integer in [0, %d), got %v", core.ErrInvalidAction, e.cfg.NumActions, values[0])
+		}
+		return values, nil
+	}
+
+	if len(values) != e.cfg.ActionDim {
+		return nil, fmt.Errorf("%w: action must have %d dimensions, got %d", core.ErrInvalidAction, e.cfg.ActionDim, len(values))
+	}
+	clipped := make([]float64, len(values))
+	for i, v := range values {
+		clipped[i] = math.Max(e.cfg.ActionLow, math.Min(e.cfg.ActionHigh, v))
+	}
+	return clipped, nil
+}
+
+// GetObservations 获取当前观察
+func (e *ScriptedEnvironment) GetObservations() []core.Observation {
+	p := e.prog
+	var data []float64
+	if len(p.obs) == 0 {
+		data = core.ResetBuffer(&e.obsBuf, p.numState)
+		copy(data, e.scope.vars[:p.numState])
+	} else {
+		data = core.ResetBuffer(&e.obsBuf, len(p.obs))
+		for i, expr := range p.obs {
+			data[i] = expr.eval(&e.scope)
+		}
+	}
+
+	var metadata map[string]interface{}
+	if e.ObservationMetadataEnabled() {
+		state := make(map[string]interface{}, p.numState)
+		for i, name := range e.cfg.StateVars {
+			state[name] = e.scope.vars[i]
+		}
+		metadata = map[string]interface{}{
+			"state":        state,
+			"total_reward": e.totalReward,
+			"step":         e.currentStep,
+			"max_steps":    e.cfg.MaxSteps,
+		}
+	}
+
+	observation := e.AcquireObservation(data, metadata)
+	return []core.Observation{observation}
+}
+
+// GetReward 返回最近一步的奖励
+func (e *ScriptedEnvironment) GetReward() []float64 {
+	return []float64{e.lastReward}
+}
+
+// GetInfo 获取环境信息
+func (e *ScriptedEnvironment) GetInfo() map[string]interface{} {
+	info := e.BaseEnvironment.GetInfo()
+	vars := make([]interface{}, len(e.cfg.StateVars))
+	for i, name := range e.cfg.StateVars {
+		vars[i] = name
+	}
+	info["state_vars"] = vars
+	info["reward_expr"] = e.prog.reward.String()
+	info["done_expr"] = e.prog.done.String()
+	return info
+}
+
+// Close 关闭环境
+func (e *ScriptedEnvironment) Close() error {
+	e.scope.vars = nil
+	return e.BaseEnvironment.Close()
+}
+
+// Metadata 返回环境元数据：奖励由配置的表达式计算，范围无法预知
+func (e *ScriptedEnvironment) Metadata() core.EnvMetadata {
+	metadata := core.DefaultEnvMetadata()
+	metadata.MaxEpisodeSteps = e.cfg.MaxSteps
+	return metadata
+}
+
+// GetSpaces 获取脚本化环境的动作空间和观察空间定义
+func (e *ScriptedEnvironment) GetSpaces() core.SpaceDefinition {
+	obsDim := len(e.prog.obs)
+	if obsDim == 0 {
+		obsDim = e.prog.numState
+	}
+	observationSpace := core.ObservationSpace{
+		Type:  core.SpaceTypeBox,
+		Shape: []int32{int32(obsDim)},
+		Dtype: "float32",
+	}
+
+	if e.cfg.ActionType == ActionDiscrete {
+		return core.SpaceDefinition{
+			ActionSpace: core.ActionSpace{
+				Type:  core.SpaceTypeDiscrete,
+				Low:   []float64{0},
+				High:  []float64{float64(e.cfg.NumActions - 1)},
+				Shape: []int32{},
+				Dtype: "int32",
+			},
+			ObservationSpace: observationSpace,
+		}
+	}
+
+	low := make([]float64, e.cfg.ActionDim)
+	high := make([]float64, e.cfg.ActionDim)
+	for i := range low {
+		low[i] = e.cfg.ActionLow
+		high[i] = e.cfg.ActionHigh
+	}
+	return core.SpaceDefinition{
+		ActionSpace: core.ActionSpace{
+			Type:  core.SpaceTypeBox,
+			Low:   low,
+			High:  high,
+			Shape: []int32{int32(e.cfg.ActionDim)},
+			Dtype: "float32",
+		},
+		ObservationSpace: observationSpace,
+	}
+}
+
+// ScriptedAction 脚本化环境专用动作
+type ScriptedAction struct {
+	Values []float64
+}
+
+// NewScriptedAction 创建新的脚本化环境动作
+func NewScriptedAction(values ...float64) *ScriptedAction {
+	return &ScriptedAction{Values: values}
+}
+
+// GetData 获取动作数据
+func (a *ScriptedAction) GetData() interface{} {
+	return a.Values
+}
+
+// Validate 验证动作
+func (a *ScriptedAction) Validate() error {
+	if len(a.Values) == 0 {
+		return fmt.Errorf("scripted action must not be empty")
+	}
+	return nil
+}
@@ -0,0 +1,482 @@
+package scripted
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// 表达式语言：
+//   数字、变量、括号、函数调用 f(x, ...)
+//   运算符（优先级从低到高）：?:  ||  &&  == !=  < <= > >=  + -  * / %  一元- !  ^（右结合）
+//   布尔值以 1/0 表示，非零即真
+// 变量在编译时解析为槽位索引，求值时不做map查找
+
+// scope 表达式求值上下文
+type scope struct {
+	vars []float64
+	rng  *rand.Rand
+}
+
+// node 表达式语法树节点
+type node interface {
+	eval(s *scope) float64
+}
+
+type numberNode float64
+
+func (n numberNode) eval(*scope) float64 { return float64(n) }
+
+type varNode int
+
+func (n varNode) eval(s *scope) float64 { return s.vars[n] }
+
+type unaryNode struct {
+	op string
+	x  node
+}
+
+func (n *unaryNode) eval(s *scope) float64 {
+	v := n.x.eval(s)
+	if n.op == "!" {
+		return boolValue(v == 0)
+	}
+	return -v
+}
+
+type binaryNode struct {
+	op   string
+	l, r node
+}
+
+func (n *binaryNode) eval(s *scope) float64 {
+	// 逻辑运算短路求值
+	switch n.op {
+	case "&&":
+		return boolValue(n.l.eval(s) != 0 && n.r.eval(s) != 0)
+	case "||":
+		return boolValue(n.l.eval(s) != 0 || n.r.eval(s) != 0)
+	}
+
+	l, r := n.l.eval(s), n.r.eval(s)
+	switch n.op {
+	case "+":
+		return l + r
+	case "-":
+		return l - r
+	case "*":
+		return l * r
+	case "/":
+		return l / r
+	case "%":
+		return math.Mod(l, r)
+	case "^":
+		return math.Pow(l, r)
+	case "==":
+		return boolValue(l == r)
+	case "!=":
+		return boolValue(l != r)
+	case "<":
+		return boolValue(l < r)
+	case "<=":
+		return boolValue(l <= r)
+	case ">":
+		return boolValue(l > r)
+	default: // ">="
+		return boolValue(l >= r)
+	}
+}
+
+type condNode struct {
+	cond, then, otherwise node
+}
+
+func (n *condNode) eval(s *scope) float64 {
+	if n.cond.eval(s) != 0 {
+		return n.then.eval(s)
+	}
+	return n.otherwise.eval(s)
+}
+
+type callNode struct {
+	fn   *function
+	args []node
+}
+
+func (n *callNode) eval(s *scope) float64 {
+	var buf [4]float64
+	args := buf[:0]
+	for _, a := range n.args {
+		args = append(args, a.eval(s))
+	}
+	return n.fn.call(s, args)
+}
+
+// function 内置函数，arity为-1表示至少一个参数的变参函数
+type function struct {
+	arity int
+	call  func(s *scope, args []float64) float64
+}
+
+func unary(f func(float64) float64) *function {
+	return &function{arity: 1, call: func(_ *scope, a []float64) float64 { return f(a[0]) }}
+}
+
+var functions = map[string]*function{
+	"sin":   unary(math.Sin),
+	"cos":   unary(math.Cos),
+	"tan":   unary(math.Tan),
+	"tanh":  unary(math.Tanh),
+	"asin":  unary(math.Asin),
+	"acos":  unary(math.Acos),
+	"atan":  unary(math.Atan),
+	"exp":   unary(math.Exp),
+	"log":   unary(math.Log),
+	"sqrt":  unary(math.Sqrt),
+	"abs":   unary(math.Abs),
+	"floor": unary(math.Floor),
+	"ceil":  unary(math.Ceil),
+	"round": unary(math.Round),
+	"sign": unary(func(x float64) float64 {
+		switch {
+		case x > 0:
+			return 1
+		case x < 0:
+			return -1
+		}
+		return 0
+	}),
+	"atan2": {arity: 2, call: func(_ *scope, a []float64) float64 { return math.Atan2(a[0], a[1]) }},
+	"pow":   {arity: 2, call: func(_ *scope, a []float64) float64 { return math.Pow(a[0], a[1]) }},
+	"clip": {arity: 3, call: func(_ *scope, a []float64) float64 {
+		return math.Max(a[1], math.Min(a[2], a[0]))
+	}},
+	"min": {arity: -1, call: func(_ *scope, a []float64) float64 {
+		m := a[0]
+		for _, v := range a[1:] {
+			m = math.Min(m, v)
+		}
+		return m
+	}},
+	"max": {arity: -1, call: func(_ *scope, a []float64) float64 {
+		m := a[0]
+		for _, v := range a[1:] {
+			m = math.Max(m, v)
+		}
+		return m
+	}},
+	// 随机函数使用环境的随机数生成器，保证相同seed可复现
+	"rand":  {arity: 0, call: func(s *scope, _ []float64) float64 { return s.rng.Float64() }},
+	"randn": {arity: 0, call: func(s *scope, _ []float64) float64 { return s.rng.NormFloat64() }},
+	"uniform": {arity: 2, call: func(s *scope, a []float64) float64 {
+		return a[0] + s.rng.Float64()*(a[1]-a[0])
+	}},
+	"normal": {arity: 2, call: func(s *scope, a []float64) float64 {
+		return a[0] + s.rng.NormFloat64()*a[1]
+	}},
+}
+
+// constants 内置常量
+var constants = map[string]float64{
+	"pi": math.Pi,
+	"e":  math.E,
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// Expr 编译后的表达式
+type Expr struct {
+	source string
+	root   node
+}
+
+// String 返回表达式源码
+func (e *Expr) String() string {
+	return e.source
+}
+
+// Compile 编译表达式，vars将变量名映射到求值时的槽位索引
+func Compile(source string, vars map[string]int) (*Expr, error) {
+	p := &parser{src: source, vars: vars}
+	if err := p.tokenize(); err != nil {
+		return nil, fmt.Errorf("expression %q: %w", source, err)
+	}
+	if len(p.tokens) == 0 {
+		return nil, fmt.Errorf("expression is empty")
+	}
+	root, err := p.parseExpr()
+	if err == nil && p.pos < len(p.tokens) {
+		err = fmt.Errorf("unexpected %q", p.tokens[p.pos].text)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("expression %q: %w", source, err)
+	}
+	return &Expr{source: source, root: root}, nil
+}
+
+// eval 在给定上下文中求值
+func (e *Expr) eval(s *scope) float64 {
+	return e.root.eval(s)
+}
+
+type tokenKind int
+
+const (
+	tokNumber tokenKind = iota
+	tokIdent
+	tokOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	num  float64
+}
+
+// parser 递归下降解析器
+type parser struct {
+	src    string
+	vars   map[string]int
+	tokens []token
+	pos    int
+}
+
+// 运算符按最长匹配优先
+var operators = []string{"&&", "||", "==", "!=", "<=", ">=", "+", "-", "*", "/", "%", "^", "<", ">", "!", "(", ")", ",", "?", ":"}
+
+func (p *parser) tokenize() error {
+	s := p.src
+	for i := 0; i < len(s); {
+		c := rune(s[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case unicode.IsDigit(c) || c == '.':
+			j := i
+			for j < len(s) && (unicode.IsDigit(rune(s[j])) || s[j] == '.') {
+				j++
+			}
+			// 科学计数法
+			if j < len(s) && (s[j] == 'e' || s[j] == 'E') {
+				k := j + 1
+				if k < len(s) && (s[k] == '+' || s[k] == '-') {
+					k++
+				}
+				if k < len(s) && unicode.IsDigit(rune(s[k])) {
+					for k < len(s) && unicode.IsDigit(rune(s[k])) {
+						k++
+					}
+					j = k
+				}
+			}
+			v, err := strconv.ParseFloat(s[i:j], 64)
+			if err != nil {
+				return fmt.Errorf("invalid number %q", s[i:j])
+			}
+			p.tokens = append(p.tokens, token{kind: tokNumber, text: s[i:j], num: v})
+			i = j
+		case unicode.IsLetter(c) || c == '_':
+			j := i
+			for j < len(s) && (unicode.IsLetter(rune(s[j])) || unicode.IsDigit(rune(s[j])) || s[j] == '_') {
+				j++
+			}
+			p.tokens = append(p.tokens, token{kind: tokIdent, text: s[i:j]})
+			i = j
+		default:
+			matched := false
+			for _, op := range operators {
+				if strings.HasPrefix(s[i:], op) {
+					p.tokens = append(p.tokens, token{kind: tokOp, text: op})
+					i += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return fmt.Errorf("unexpected character %q at offset %d", c, i)
+			}
+		}
+	}
+	return nil
+}
+
+// accept 若下一个token为给定运算符之一则消费并返回它
+func (p *parser) accept(ops ...string) (string, bool) {
+	if p.pos >= len(p.tokens) || p.tokens[p.pos].kind != tokOp {
+		return "", false
+	}
+	for _, op := range ops {
+		if p.tokens[p.pos].text == op {
+			p.pos++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *parser) expect(op string) error {
+	if _, ok := p.accept(op); !ok {
+		if p.pos >= len(p.tokens) {
+			return fmt.Errorf("expected %q at end of expression", op)
+		}
+		return fmt.Errorf("expected %q, got %q", op, p.tokens[p.pos].text)
+	}
+	return nil
+}
+
+// parseExpr 解析三元条件表达式
+func (p *parser) parseExpr() (node, error) {
+	cond, err := p.parseBinary(0)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := p.accept("?"); !ok {
+		return cond, nil
+	}
+	then, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(":"); err != nil {
+		return nil, err
+	}
+	otherwise, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	return &condNode{cond: cond, then: then, otherwise: otherwise}, nil
+}
+
+// binaryLevels 二元运算符的优先级，从低到高（^单独处理为右结合）
+var binaryLevels = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!="},
+	{"<", "<=", ">", ">="},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *parser) parseBinary(level int) (node, error) {
+	if level == len(binaryLevels) {
+		return p.parseUnary()
+	}
+	left, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept(binaryLevels[level]...)
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: op, l: left, r: right}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if op, ok := p.accept("-", "!", "+"); ok {
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if op == "+" {
+			return x, nil
+		}
+		return &unaryNode{op: op, x: x}, nil
+	}
+	return p.parsePower()
+}
+
+func (p *parser) parsePower() (node, error) {
+	base, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := p.accept("^"); ok {
+		// 右结合，且指数允许一元负号：2^-x
+		exp, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &binaryNode{op: "^", l: base, r: exp}, nil
+	}
+	return base, nil
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	tok := p.tokens[p.pos]
+	p.pos++
+
+	switch tok.kind {
+	case tokNumber:
+		return numberNode(tok.num), nil
+	case tokIdent:
+		if _, ok := p.accept("("); ok {
+			return p.parseCall(tok.text)
+		}
+		if slot, ok := p.vars[tok.text]; ok {
+			return varNode(slot), nil
+		}
+		if v, ok := constants[tok.text]; ok {
+			return numberNode(v), nil
+		}
+		return nil, fmt.Errorf("unknown variable %q", tok.text)
+	default:
+		if tok.text == "(" {
+			inner, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			return inner, p.expect(")")
+		}
+		return nil, fmt.Errorf("unexpected %q", tok.text)
+	}
+}
+
+func (p *parser) parseCall(name string) (node, error) {
+	fn, ok := functions[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %q", name)
+	}
+
+	var args []node
+	if _, ok := p.accept(")"); !ok {
+		for {
+			arg, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if _, ok := p.accept(","); !ok {
+				break
+			}
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+	}
+
+	if fn.arity >= 0 && len(args) != fn.arity {
+		return nil, fmt.Errorf("function %s expects %d arguments, got %d", name, fn.arity, len(args))
+	}
+	if fn.arity < 0 && len(args) == 0 {
+		return nil, fmt.Errorf("function %s expects at least one argument", name)
+	}
+	return &callNode{fn: fn, args: args}, nil
+}
@@ -0,0 +1,56 @@
+package scripted
+
+import (
+	"fmt"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// ScriptedScenario 脚本化场景实现
+type ScriptedScenario struct {
+	name        string
+	description string
+}
+
+// 确保ScriptedScenario实现了core.Scenario接口
+var _ core.Scenario = (*ScriptedScenario)(nil)
+
+// NewScriptedScenario 创建新的脚本化场景
+func NewScriptedScenario() *ScriptedScenario {
+	return &ScriptedScenario{
+		name:        "scripted",
+		description: "Custom environment whose dynamics, reward and termination are expressions supplied in the config",
+	}
+}
+
+// GetName 获取场景名称
+func (s *ScriptedScenario) GetName() string {
+	return s.name
+}
+
+// GetDescription 获取场景描述
+func (s *ScriptedScenario) GetDescription() string {
+	return s.description
+}
+
+// CreateEnvironment 创建环境实例
+func (s *ScriptedScenario) CreateEnvironment(config core.Config) (core.Environment, error) {
+	env, err := NewScriptedEnvironment(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create scripted environment: %w", err)
+	}
+	return env, nil
+}
+
+// ValidateConfig 验证配置，包括编译所有表达式
+func (s *ScriptedScenario) ValidateConfig(config core.Config) error {
+	if config == nil {
+		return fmt.Errorf("config cannot be nil")
+	}
+	cfg, err := parseConfig(config)
+	if err != nil {
+		return err
+	}
+	_, err = compile(cfg)
+	return err
+}
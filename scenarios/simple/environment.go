@@ -2,15 +2,36 @@ package simple
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"math"
 	"math/rand"
-	"strconv"
 	"time"
 
 	"github.com/jelech/rl_env_engine/core"
+	"github.com/jelech/rl_env_engine/core/config"
 )
 
+// Config 简单环境配置
+type Config struct {
+	MaxSteps  int     `cfg:"max_steps,default=100,min=1,max=1000"`
+	Tolerance float64 `cfg:"tolerance,default=0.1,gt=0,max=10"` // 判定到达目标的距离阈值
+}
+
+// DefaultConfig 返回默认配置
+func DefaultConfig() Config {
+	var cfg Config
+	config.MustDefaults(&cfg)
+	return cfg
+}
+
+// parseConfig 将core.Config绑定为简单环境配置，未提供的字段使用默认值
+func parseConfig(src core.Config) (Config, error) {
+	var cfg Config
+	err := config.Bind(src, &cfg)
+	return cfg, err
+}
+
 // SimpleEnvironment 简单的数学测试环境
 // 目标：通过调整action让观察值接近目标值
 type SimpleEnvironment struct {
@@ -21,47 +42,32 @@ type SimpleEnvironment struct {
 	currentStep  int
 	tolerance    float64
 	rng          *rand.Rand
+	src          *core.RandSource
 }
 
-// NewSimpleEnvironment 创建新的简单环境
-func NewSimpleEnvironment(config core.Config) *SimpleEnvironment {
-	baseEnv := core.NewBaseEnvironment("simple", "Simple mathematical test environment", config)
-
-	// 从配置中获取参数，如果没有则使用默认值
-	maxSteps := 100
-	if val := config.GetValue("max_steps"); val != nil {
-		switch v := val.(type) {
-		case int:
-			maxSteps = v
-		case string:
-			if parsed, err := strconv.Atoi(v); err == nil {
-				maxSteps = parsed
-			}
-		}
+// NewSimpleEnvironment 创建新的简单环境，配置无效时返回错误
+func NewSimpleEnvironment(config core.Config) (*SimpleEnvironment, error) {
+	cfg, err := parseConfig(config)
+	if err != nil {
+		return nil, err
 	}
+	return newSimpleEnvironment(config, cfg), nil
+}
 
-	tolerance := 0.1
-	if val := config.GetValue("tolerance"); val != nil {
-		switch v := val.(type) {
-		case float64:
-			tolerance = v
-		case float32:
-			tolerance = float64(v)
-		case string:
-			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
-				tolerance = parsed
-			}
-		}
-	}
+// newSimpleEnvironment 以绑定后的配置创建环境
+func newSimpleEnvironment(config core.Config, cfg Config) *SimpleEnvironment {
+	baseEnv := core.NewBaseEnvironment("simple", "Simple mathematical test environment", config)
 
+	src := core.NewRandSource(time.Now().UnixNano())
 	return &SimpleEnvironment{
 		BaseEnvironment: baseEnv,
 		currentValue:    0.0,
 		targetValue:     10.0, // 目标值
-		maxSteps:        maxSteps,
+		maxSteps:        cfg.MaxSteps,
 		currentStep:     0,
-		tolerance:       tolerance,
-		rng:             rand.New(rand.NewSource(time.Now().UnixNano())),
+		tolerance:       cfg.Tolerance,
+		rng:             rand.New(src),
+		src:             src,
 	}
 }
 
@@ -79,7 +85,7 @@ func (e *SimpleEnvironment) Reset(ctx context.Context) ([]core.Observation, erro
 // Step 执行一步仿真
 func (e *SimpleEnvironment) Step(ctx context.Context, actions []core.Action) ([]core.Observation, []float64, []bool, error) {
 	if len(actions) == 0 {
-		return nil, nil, nil, fmt.Errorf("no actions provided")
+		return nil, nil, nil, fmt.Errorf("%w: no actions provided", core.ErrInvalidAction)
 	}
 
 	// 从GenericAction中提取数值
@@ -90,13 +96,13 @@ func (e *SimpleEnvironment) Step(ctx context.Context, actions []core.Action) ([]
 		var err error
 		actionValue, err = genericAction.GetFloat64()
 		if err != nil {
-			return nil, nil, nil, fmt.Errorf("failed to extract float64 from generic action: %w", err)
+			return nil, nil, nil, fmt.Errorf("%w: failed to extract float64 from generic action: %w", core.ErrInvalidAction, err)
 		}
 	} else if simpleAction, ok := actions[0].(*SimpleAction); ok {
 		// 兼容旧的SimpleAction
 		actionValue = simpleAction.Value
 	} else {
-		return nil, nil, nil, fmt.Errorf("invalid action type: %T", actions[0])
+		return nil, nil, nil, fmt.Errorf("%w: invalid action type: %T", core.ErrInvalidAction, actions[0])
 	}
 
 	// 应用action：简单地将action值添加到当前值
@@ -133,15 +139,18 @@ func (e *SimpleEnvironment) GetObservations() []core.Observation {
 		float64(e.currentStep) / float64(e.maxSteps), // 进度比例
 	}
 
-	metadata := map[string]interface{}{
-		"current_value": e.currentValue,
-		"target_value":  e.targetValue,
-		"current_step":  e.currentStep,
-		"max_steps":     e.maxSteps,
-		"distance":      math.Abs(e.currentValue - e.targetValue),
+	var metadata map[string]interface{}
+	if e.ObservationMetadataEnabled() {
+		metadata = map[string]interface{}{
+			"current_value": e.currentValue,
+			"target_value":  e.targetValue,
+			"current_step":  e.currentStep,
+			"max_steps":     e.maxSteps,
+			"distance":      math.Abs(e.currentValue - e.targetValue),
+		}
 	}
 
-	baseObs := core.NewBaseObservation(data, metadata)
+	baseObs := e.AcquireObservation(data, metadata)
 	return []core.Observation{baseObs}
 }
 
@@ -155,6 +164,43 @@ func (e *SimpleEnvironment) GetReward() []float64 {
 	return []float64{reward}
 }
 
+// Metadata 返回环境元数据：奖励为负距离，进入容差范围时额外+10
+func (e *SimpleEnvironment) Metadata() core.EnvMetadata {
+	return core.EnvMetadata{
+		RewardRange:     [2]float64{math.Inf(-1), 10},
+		MaxEpisodeSteps: e.maxSteps,
+	}
+}
+
+// checkpoint 简单环境的检查点
+type checkpoint struct {
+	CurrentValue float64        `json:"current_value"`
+	TargetValue  float64        `json:"target_value"`
+	Step         int            `json:"step"`
+	Rand         core.RandState `json:"rand"`
+}
+
+// Checkpoint 实现core.Checkpointer
+func (e *SimpleEnvironment) Checkpoint() ([]byte, error) {
+	return json.Marshal(checkpoint{
+		CurrentValue: e.currentValue,
+		TargetValue:  e.targetValue,
+		Step:         e.currentStep,
+		Rand:         e.src.State(),
+	})
+}
+
+// RestoreCheckpoint 实现core.Checkpointer
+func (e *SimpleEnvironment) RestoreCheckpoint(data []byte) error {
+	var cp checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return fmt.Errorf("invalid simple checkpoint: %w", err)
+	}
+	e.currentValue, e.targetValue, e.currentStep = cp.CurrentValue, cp.TargetValue, cp.Step
+	e.src.Restore(cp.Rand)
+	return nil
+}
+
 // GetSpaces 获取简单场景的动作空间和观察空间定义
 func (s *SimpleEnvironment) GetSpaces() core.SpaceDefinition {
 	return core.SpaceDefinition{
@@ -2,7 +2,6 @@ package simple
 
 import (
 	"fmt"
-	"strconv"
 
 	"github.com/jelech/rl_env_engine/core"
 )
@@ -39,8 +38,11 @@ func (s *SimpleScenario) CreateEnvironment(config core.Config) (core.Environment
 		return nil, fmt.Errorf("invalid config: %w", err)
 	}
 
-	env := NewSimpleEnvironment(config)
-	return env, nil
+	cfg, err := parseConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return newSimpleEnvironment(config, cfg), nil
 }
 
 // ValidateConfig 验证配置
@@ -49,49 +51,6 @@ func (s *SimpleScenario) ValidateConfig(config core.Config) error {
 		return fmt.Errorf("config cannot be nil")
 	}
 
-	// 验证max_steps参数
-	if val := config.GetValue("max_steps"); val != nil {
-		var steps int
-		switch v := val.(type) {
-		case int:
-			steps = v
-		case string:
-			if parsed, err := strconv.Atoi(v); err != nil {
-				return fmt.Errorf("max_steps must be a valid integer, got %s", v)
-			} else {
-				steps = parsed
-			}
-		default:
-			return fmt.Errorf("max_steps must be an integer or string, got %T", val)
-		}
-
-		if steps <= 0 || steps > 1000 {
-			return fmt.Errorf("max_steps must be between 1 and 1000, got %d", steps)
-		}
-	}
-
-	// 验证tolerance参数
-	if val := config.GetValue("tolerance"); val != nil {
-		var tol float64
-		switch v := val.(type) {
-		case float64:
-			tol = v
-		case float32:
-			tol = float64(v)
-		case string:
-			if parsed, err := strconv.ParseFloat(v, 64); err != nil {
-				return fmt.Errorf("tolerance must be a valid float, got %s", v)
-			} else {
-				tol = parsed
-			}
-		default:
-			return fmt.Errorf("tolerance must be a float or string, got %T", val)
-		}
-
-		if tol <= 0 || tol > 10 {
-			return fmt.Errorf("tolerance must be between 0 and 10, got %f", tol)
-		}
-	}
-
-	return nil
+	_, err := parseConfig(config)
+	return err
 }
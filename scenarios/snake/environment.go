@@ -0,0 +1,462 @@
+package snake
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/jelech/rl_env_engine/core"
+	"github.com/jelech/rl_env_engine/core/config"
+)
+
+// 移动方向
+const (
+	DirUp = iota
+	DirRight
+	DirDown
+	DirLeft
+	numDirs
+)
+
+// 观察类型
+const (
+	ObsFeatures = "features" // 紧凑特征向量
+	ObsPixels   = "pixels"   // HxWx3 uint8 图像
+)
+
+// numFeatures 特征向量长度：危险(直行/右转/左转) + 方向one-hot(4) + 食物相对位置(上/右/下/左)
+const numFeatures = 11
+
+var (
+	dirDeltas = [numDirs][2]int{{-1, 0}, {0, 1}, {1, 0}, {0, -1}} // (行, 列)
+	dirNames  = [numDirs]string{"up", "right", "down", "left"}
+)
+
+// 像素颜色 (R, G, B)，背景为黑色
+var (
+	colorBody = [3]uint8{0, 160, 0}
+	colorHead = [3]uint8{0, 255, 0}
+	colorFood = [3]uint8{255, 0, 0}
+)
+
+// Config 贪吃蛇环境配置
+type Config struct {
+	MaxSteps     int     `cfg:"max_steps,default=1000,min=1"`
+	Width        int     `cfg:"width,default=10,min=4"`
+	Height       int     `cfg:"height,default=10,min=4"`
+	ObsType      string  `cfg:"obs_type,default=features,oneof=features|pixels"`
+	CellSize     int     `cfg:"cell_size,default=1,min=1"`       // 像素观察中每个格子的边长（像素）
+	HungerLimit  int     `cfg:"hunger_limit,default=200,min=0"`  // 连续多少步未吃到食物后结束，0表示不限制
+	FoodReward   float64 `cfg:"food_reward,default=1.0"`         // 吃到食物的奖励
+	DeathPenalty float64 `cfg:"death_penalty,default=1.0,min=0"` // 撞墙/撞到自身的惩罚
+	StepPenalty  float64 `cfg:"step_penalty,min=0"`              // 每步的惩罚，鼓励尽快吃到食物
+	Seed         int64   `cfg:"seed"`
+}
+
+// DefaultConfig 返回默认配置
+func DefaultConfig() Config {
+	var cfg Config
+	config.MustDefaults(&cfg)
+	return cfg
+}
+
+// Validate 按cfg标签中的约束验证配置，用于校验直接构造的配置
+func (c Config) Validate() error {
+	return config.Validate(c)
+}
+
+// parseConfig 将core.Config绑定为贪吃蛇配置，未提供的字段使用默认值
+func parseConfig(src core.Config) (Config, error) {
+	var cfg Config
+	if err := config.Bind(src, &cfg); err != nil {
+		return cfg, err
+	}
+	return cfg, cfg.Validate()
+}
+
+// cell 网格坐标
+type cell struct {
+	row, col int
+}
+
+// SnakeEnvironment 贪吃蛇环境
+// 动作为绝对方向（0: 上, 1: 右, 2: 下, 3: 左），反向移动视为保持当前方向。
+// 观察可以是紧凑特征向量，也可以是 (height*cell_size) x (width*cell_size) x 3 的 uint8 图像（core.ImageObservation）
+type SnakeEnvironment struct {
+	*core.BaseEnvironment
+	cfg Config
+
+	body      []cell // body[0] 为蛇头
+	direction int
+	food      cell
+	score     int
+
+	currentStep int
+	hunger      int // 距上次吃到食物的步数
+	lastReward  float64
+	dead        bool
+	won         bool
+
+	rng *rand.Rand
+}
+
+// 确保SnakeEnvironment实现了可选的渲染接口
+var (
+	_ core.Renderer      = (*SnakeEnvironment)(nil)
+	_ core.FrameRenderer = (*SnakeEnvironment)(nil)
+)
+
+// NewSnakeEnvironment 创建新的贪吃蛇环境
+func NewSnakeEnvironment(config core.Config) (*SnakeEnvironment, error) {
+	cfg, err := parseConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	baseEnv := core.NewBaseEnvironment("snake", "Snake grid game", config)
+
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	return &SnakeEnvironment{
+		BaseEnvironment: baseEnv,
+		cfg:             cfg,
+		rng:             rand.New(rand.NewSource(seed)),
+	}, nil
+}
+
+// Reset 重置环境：长度为3的蛇位于中央并朝右，随机放置食物
+func (e *SnakeEnvironment) Reset(ctx context.Context) ([]core.Observation, error) {
+	row, col := e.cfg.Height/2, e.cfg.Width/2
+	e.body = e.body[:0]
+	for i := 0; i < 3; i++ {
+		e.body = append(e.body, cell{row, col - i})
+	}
+	e.direction = DirRight
+	e.score = 0
+	e.currentStep = 0
+	e.hunger = 0
+	e.lastReward = 0
+	e.dead = false
+	e.won = false
+	e.spawnFood()
+
+	return e.GetObservations(), nil
+}
+
+// Step 执行一步移动
+func (e *SnakeEnvironment) Step(ctx context.Context, actions []core.Action) ([]core.Observation, []float64, []bool, error) {
+	if len(actions) == 0 {
+		return nil, nil, nil, fmt.Errorf("%w: no actions provided", core.ErrInvalidAction)
+	}
+	if e.dead || e.won {
+		return nil, nil, nil, fmt.Errorf("episode is over, call Reset first")
+	}
+
+	var dir int
+	if genericAction, ok := actions[0].(*core.GenericAction); ok {
+		value, err := genericAction.GetInt64()
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("%w: failed to extract action value: %w", core.ErrInvalidAction, err)
+		}
+		dir = int(value)
+	} else if snakeAction, ok := actions[0].(*SnakeAction); ok {
+		dir = snakeAction.Direction
+	} else {
+		return nil, nil, nil, fmt.Errorf("%w: unsupported action type: %T", core.ErrInvalidAction, actions[0])
+	}
+	if dir < 0 || dir >= numDirs {
+		return nil, nil, nil, fmt.Errorf("direction must be in [0, %d), got %d", numDirs, dir)
+	}
+
+	// 反向移动会直接撞到自身，视为保持当前方向
+	if dir != (e.direction+2)%numDirs {
+		e.direction = dir
+	}
+
+	e.currentStep++
+	e.hunger++
+	e.lastReward = -e.cfg.StepPenalty
+
+	head := e.next(e.body[0], e.direction)
+	ate := head == e.food
+	// 未吃到食物时尾巴会移开，因此移动到当前尾巴位置是合法的
+	tailLen := len(e.body)
+	if !ate {
+		tailLen--
+	}
+
+	if e.collides(head, tailLen) {
+		e.dead = true
+		e.lastReward = -e.cfg.DeathPenalty
+	} else {
+		e.body = append(e.body, cell{})
+		copy(e.body[1:], e.body[:len(e.body)-1])
+		e.body[0] = head
+		if ate {
+			e.score++
+			e.hunger = 0
+			e.lastReward += e.cfg.FoodReward
+			if len(e.body) == e.cfg.Width*e.cfg.Height {
+				e.won = true
+			} else {
+				e.spawnFood()
+			}
+		} else {
+			e.body = e.body[:len(e.body)-1]
+		}
+	}
+
+	done := e.dead || e.won || e.currentStep >= e.cfg.MaxSteps ||
+		(e.cfg.HungerLimit > 0 && e.hunger >= e.cfg.HungerLimit)
+
+	return e.GetObservations(), []float64{e.lastReward}, []bool{done}, nil
+}
+
+// next 返回从c沿dir方向移动一格后的位置
+func (e *SnakeEnvironment) next(c cell, dir int) cell {
+	return cell{c.row + dirDeltas[dir][0], c.col + dirDeltas[dir][1]}
+}
+
+// collides 判断位置是否撞墙或撞到蛇身的前bodyLen节
+func (e *SnakeEnvironment) collides(c cell, bodyLen int) bool {
+	if c.row < 0 || c.row >= e.cfg.Height || c.col < 0 || c.col >= e.cfg.Width {
+		return true
+	}
+	for _, b := range e.body[:bodyLen] {
+		if b == c {
+			return true
+		}
+	}
+	return false
+}
+
+// spawnFood 在随机空格放置食物
+func (e *SnakeEnvironment) spawnFood() {
+	occupied := make(map[cell]bool, len(e.body))
+	for _, b := range e.body {
+		occupied[b] = true
+	}
+	var empty []cell
+	for r := 0; r < e.cfg.Height; r++ {
+		for c := 0; c < e.cfg.Width; c++ {
+			if !occupied[cell{r, c}] {
+				empty = append(empty, cell{r, c})
+			}
+		}
+	}
+	if len(empty) > 0 {
+		e.food = empty[e.rng.Intn(len(empty))]
+	}
+}
+
+// features 计算紧凑特征向量
+func (e *SnakeEnvironment) features() []float64 {
+	data := make([]float64, numFeatures)
+	head := e.body[0]
+	// 危险：直行、右转、左转后的下一格是否会碰撞
+	for i, turn := range [3]int{0, 1, numDirs - 1} {
+		if e.collides(e.next(head, (e.direction+turn)%numDirs), len(e.body)-1) {
+			data[i] = 1
+		}
+	}
+	data[3+e.direction] = 1
+	if e.food.row < head.row {
+		data[7] = 1
+	}
+	if e.food.col > head.col {
+		data[8] = 1
+	}
+	if e.food.row > head.row {
+		data[9] = 1
+	}
+	if e.food.col < head.col {
+		data[10] = 1
+	}
+	return data
+}
+
+// RenderFrame 将当前状态渲染为行优先的RGB图像帧
+func (e *SnakeEnvironment) RenderFrame() ([]uint8, int, int) {
+	cs := e.cfg.CellSize
+	height, width := e.cfg.Height*cs, e.cfg.Width*cs
+	frame := make([]uint8, height*width*3)
+
+	paint := func(c cell, color [3]uint8) {
+		for dr := 0; dr < cs; dr++ {
+			offset := ((c.row*cs+dr)*width + c.col*cs) * 3
+			for dc := 0; dc < cs; dc++ {
+				copy(frame[offset+dc*3:offset+dc*3+3], color[:])
+			}
+		}
+	}
+
+	if !e.won {
+		paint(e.food, colorFood)
+	}
+	for _, b := range e.body[1:] {
+		paint(b, colorBody)
+	}
+	paint(e.body[0], colorHead)
+	return frame, height, width
+}
+
+// GetObservations 获取当前观察
+func (e *SnakeEnvironment) GetObservations() []core.Observation {
+	var metadata map[string]interface{}
+	if e.ObservationMetadataEnabled() {
+		metadata = map[string]interface{}{
+			"score":     e.score,
+			"length":    len(e.body),
+			"direction": dirNames[e.direction],
+			"head":      []interface{}{e.body[0].row, e.body[0].col},
+			"food":      []interface{}{e.food.row, e.food.col},
+			"dead":      e.dead,
+			"won":       e.won,
+			"step":      e.currentStep,
+			"max_steps": e.cfg.MaxSteps,
+		}
+	}
+
+	if e.cfg.ObsType == ObsPixels {
+		frame, height, width := e.RenderFrame()
+		return []core.Observation{core.NewImageObservation(frame, height, width, 3, metadata)}
+	}
+	observation := e.AcquireObservation(e.features(), metadata)
+	return []core.Observation{observation}
+}
+
+// GetReward 返回最近一步的奖励
+func (e *SnakeEnvironment) GetReward() []float64 {
+	return []float64{e.lastReward}
+}
+
+// GetInfo 获取环境信息
+func (e *SnakeEnvironment) GetInfo() map[string]interface{} {
+	info := e.BaseEnvironment.GetInfo()
+	info["obs_type"] = e.cfg.ObsType
+	info["width"] = e.cfg.Width
+	info["height"] = e.cfg.Height
+	return info
+}
+
+// Render 将棋盘渲染为文本
+func (e *SnakeEnvironment) Render() string {
+	grid := make([][]byte, e.cfg.Height)
+	for r := range grid {
+		grid[r] = []byte(strings.Repeat(".", e.cfg.Width))
+	}
+	if !e.won {
+		grid[e.food.row][e.food.col] = '*'
+	}
+	for i, b := range e.body {
+		if i == 0 {
+			grid[b.row][b.col] = '@'
+		} else {
+			grid[b.row][b.col] = 'o'
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "score: %d  length: %d  step: %d\n", e.score, len(e.body), e.currentStep)
+	border := "+" + strings.Repeat("-", e.cfg.Width) + "+\n"
+	sb.WriteString(border)
+	for _, row := range grid {
+		sb.WriteString("|" + string(row) + "|\n")
+	}
+	sb.WriteString(border)
+	switch {
+	case e.dead:
+		sb.WriteString("game over\n")
+	case e.won:
+		sb.WriteString("board filled\n")
+	}
+	return sb.String()
+}
+
+// Close 关闭环境
+func (e *SnakeEnvironment) Close() error {
+	e.body = nil
+	return e.BaseEnvironment.Close()
+}
+
+// Metadata 返回环境元数据：死亡时为-death_penalty，吃到食物时为food_reward-step_penalty
+func (e *SnakeEnvironment) Metadata() core.EnvMetadata {
+	return core.EnvMetadata{
+		RewardRange:     [2]float64{math.Min(-e.cfg.DeathPenalty, -e.cfg.StepPenalty), e.cfg.FoodReward - e.cfg.StepPenalty},
+		MaxEpisodeSteps: e.cfg.MaxSteps,
+	}
+}
+
+// GetSpaces 获取贪吃蛇场景的动作空间和观察空间定义
+func (e *SnakeEnvironment) GetSpaces() core.SpaceDefinition {
+	actionSpace := core.ActionSpace{
+		Type:  core.SpaceTypeDiscrete,
+		Low:   []float64{0},
+		High:  []float64{numDirs - 1}, // 0: 上, 1: 右, 2: 下, 3: 左
+		Shape: []int32{},
+		Dtype: "int32",
+	}
+
+	if e.cfg.ObsType == ObsPixels {
+		cs := e.cfg.CellSize
+		return core.SpaceDefinition{
+			ActionSpace: actionSpace,
+			ObservationSpace: core.ObservationSpace{
+				Type:  core.SpaceTypeImage,
+				Low:   []float64{0},
+				High:  []float64{255},
+				Shape: []int32{int32(e.cfg.Height * cs), int32(e.cfg.Width * cs), 3},
+				Dtype: "uint8",
+			},
+		}
+	}
+
+	return core.SpaceDefinition{
+		ActionSpace: actionSpace,
+		ObservationSpace: core.ObservationSpace{
+			Type:  core.SpaceTypeBox,
+			Low:   make([]float64, numFeatures),
+			High:  filled(numFeatures, 1),
+			Shape: []int32{numFeatures},
+			Dtype: "float32",
+		},
+	}
+}
+
+// filled 返回长度为n、元素全为v的切片
+func filled(n int, v float64) []float64 {
+	s := make([]float64, n)
+	for i := range s {
+		s[i] = v
+	}
+	return s
+}
+
+// SnakeAction 贪吃蛇专用动作
+type SnakeAction struct {
+	Direction int // 0: 上, 1: 右, 2: 下, 3: 左
+}
+
+// NewSnakeAction 创建新的贪吃蛇动作
+func NewSnakeAction(direction int) *SnakeAction {
+	return &SnakeAction{Direction: direction}
+}
+
+// GetData 获取动作数据
+func (a *SnakeAction) GetData() interface{} {
+	return a.Direction
+}
+
+// Validate 验证动作
+func (a *SnakeAction) Validate() error {
+	if a.Direction < 0 || a.Direction >= numDirs {
+		return fmt.Errorf("snake direction must be in [0, %d), got %d", numDirs, a.Direction)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package snake
+
+import (
+	"fmt"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// SnakeScenario 贪吃蛇场景实现
+type SnakeScenario struct {
+	name        string
+	description string
+}
+
+// 确保SnakeScenario实现了core.Scenario接口
+var _ core.Scenario = (*SnakeScenario)(nil)
+
+// NewSnakeScenario 创建新的贪吃蛇场景
+func NewSnakeScenario() *SnakeScenario {
+	return &SnakeScenario{
+		name:        "snake",
+		description: "Snake on a grid with feature-vector or HxWx3 uint8 pixel observations",
+	}
+}
+
+// GetName 获取场景名称
+func (s *SnakeScenario) GetName() string {
+	return s.name
+}
+
+// GetDescription 获取场景描述
+func (s *SnakeScenario) GetDescription() string {
+	return s.description
+}
+
+// CreateEnvironment 创建环境实例
+func (s *SnakeScenario) CreateEnvironment(config core.Config) (core.Environment, error) {
+	env, err := NewSnakeEnvironment(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create snake environment: %w", err)
+	}
+	return env, nil
+}
+
+// ValidateConfig 验证配置
+func (s *SnakeScenario) ValidateConfig(config core.Config) error {
+	if config == nil {
+		return fmt.Errorf("config cannot be nil")
+	}
+	_, err := parseConfig(config)
+	return err
+}
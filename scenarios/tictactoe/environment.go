@@ -0,0 +1,481 @@
+package tictactoe
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/jelech/rl_env_engine/core"
+	"github.com/jelech/rl_env_engine/core/config"
+)
+
+// 内置对手策略
+const (
+	OpponentNone    = "none"    // 自我对弈：两个智能体轮流提交动作
+	OpponentRandom  = "random"  // 随机选择合法位置
+	OpponentMinimax = "minimax" // 完美对手
+)
+
+// 玩家编号，同时也是智能体索引
+const (
+	PlayerX = 0
+	PlayerO = 1
+)
+
+var playerNames = [2]string{"player_x", "player_o"}
+
+// winLines 所有获胜连线
+var winLines = [8][3]int{
+	{0, 1, 2}, {3, 4, 5}, {6, 7, 8},
+	{0, 3, 6}, {1, 4, 7}, {2, 5, 8},
+	{0, 4, 8}, {2, 4, 6},
+}
+
+// Config 井字棋环境配置
+type Config struct {
+	Opponent           string  `cfg:"opponent,default=none"`  // none | random | minimax，不区分大小写
+	AgentPlayer        string  `cfg:"agent_player,default=x"` // 有内置对手时智能体执子："x"（先手）或 "o"，不区分大小写
+	IllegalMovePenalty float64 `cfg:"illegal_move_penalty,default=1.0,min=0"`
+	Seed               int64   `cfg:"seed"`
+}
+
+// DefaultConfig 返回默认配置
+func DefaultConfig() Config {
+	var cfg Config
+	config.MustDefaults(&cfg)
+	return cfg
+}
+
+// Validate 验证配置：cfg标签中的约束，以及（转换为小写后的）对手与执子取值
+func (c Config) Validate() error {
+	if err := config.Validate(c); err != nil {
+		return err
+	}
+	switch c.Opponent {
+	case OpponentNone, OpponentRandom, OpponentMinimax:
+	default:
+		return fmt.Errorf("opponent must be one of %q, %q, %q, got %q", OpponentNone, OpponentRandom, OpponentMinimax, c.Opponent)
+	}
+	if c.AgentPlayer != "x" && c.AgentPlayer != "o" {
+		return fmt.Errorf("agent_player must be \"x\" or \"o\", got %q", c.AgentPlayer)
+	}
+	return nil
+}
+
+// parseConfig 将core.Config绑定为井字棋配置，未提供的字段使用默认值，对手与执子不区分大小写
+func parseConfig(src core.Config) (Config, error) {
+	var cfg Config
+	if err := config.Bind(src, &cfg); err != nil {
+		return cfg, err
+	}
+	cfg.Opponent = strings.ToLower(cfg.Opponent)
+	cfg.AgentPlayer = strings.ToLower(cfg.AgentPlayer)
+	return cfg, cfg.Validate()
+}
+
+// TicTacToeEnvironment 回合制井字棋环境
+//
+// 自我对弈模式（opponent=none）下有两个智能体 player_x、player_o，轮流行动：
+// 每次 Step 只接收当前行动方的一个动作（0-8，行优先的格子索引），
+// 返回两个智能体各自视角的观察、奖励和结束标志，当前行动方见元数据 current_player。
+//
+// 内置对手模式（opponent=random/minimax）下只有一个智能体，对手在同一个 Step 内自动应答。
+//
+// 观察为9个格子（己方1，对方-1，空0）加上是否轮到自己行动；非法落子直接结束对局并给予惩罚
+type TicTacToeEnvironment struct {
+	*core.BaseEnvironment
+	cfg Config
+
+	board       [9]int // 0: 空, 1: X, 2: O
+	current     int    // 当前行动方
+	agent       int    // 内置对手模式下智能体执子
+	winner      int    // -1: 无, PlayerX / PlayerO
+	finished    bool
+	illegal     bool
+	moves       int
+	lastRewards []float64
+
+	rng *rand.Rand
+
+	obsBuf []float64 // GetObservations复用的观察数据缓冲区
+}
+
+// 确保TicTacToeEnvironment实现了可选的掩码和渲染接口
+var (
+	_ core.ActionMasker = (*TicTacToeEnvironment)(nil)
+	_ core.Renderer     = (*TicTacToeEnvironment)(nil)
+)
+
+// NewTicTacToeEnvironment 创建新的井字棋环境
+func NewTicTacToeEnvironment(config core.Config) (*TicTacToeEnvironment, error) {
+	cfg, err := parseConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	baseEnv := core.NewBaseEnvironment("tictactoe", "Turn-based TicTacToe environment", config)
+
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	agent := PlayerX
+	if cfg.AgentPlayer == "o" {
+		agent = PlayerO
+	}
+
+	return &TicTacToeEnvironment{
+		BaseEnvironment: baseEnv,
+		cfg:             cfg,
+		agent:           agent,
+		winner:          -1,
+		rng:             rand.New(rand.NewSource(seed)),
+	}, nil
+}
+
+// selfPlay 是否为自我对弈模式
+func (e *TicTacToeEnvironment) selfPlay() bool {
+	return e.cfg.Opponent == OpponentNone
+}
+
+// NumAgents 返回智能体数量
+func (e *TicTacToeEnvironment) NumAgents() int {
+	if e.selfPlay() {
+		return 2
+	}
+	return 1
+}
+
+// CurrentPlayer 返回当前行动方（PlayerX 或 PlayerO）
+func (e *TicTacToeEnvironment) CurrentPlayer() int {
+	return e.current
+}
+
+// Reset 重置棋盘；内置对手执X时由对手先行
+func (e *TicTacToeEnvironment) Reset(ctx context.Context) ([]core.Observation, error) {
+	e.board = [9]int{}
+	e.current = PlayerX
+	e.winner = -1
+	e.finished = false
+	e.illegal = false
+	e.moves = 0
+	e.lastRewards = make([]float64, e.NumAgents())
+
+	if !e.selfPlay() && e.agent == PlayerO {
+		e.play(e.opponentMove())
+	}
+
+	return e.GetObservations(), nil
+}
+
+// Step 当前行动方落子；内置对手模式下对手随后自动应答
+func (e *TicTacToeEnvironment) Step(ctx context.Context, actions []core.Action) ([]core.Observation, []float64, []bool, error) {
+	if len(actions) != 1 {
+		return nil, nil, nil, fmt.Errorf("%w: expected exactly 1 action from %s, got %d", core.ErrInvalidAction, playerNames[e.current], len(actions))
+	}
+	if e.finished {
+		return nil, nil, nil, fmt.Errorf("game is over, call reset")
+	}
+
+	var cell int
+	if genericAction, ok := actions[0].(*core.GenericAction); ok {
+		value, err := genericAction.GetInt64()
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("%w: failed to extract action value: %w", core.ErrInvalidAction, err)
+		}
+		cell = int(value)
+	} else if tttAction, ok := actions[0].(*TicTacToeAction); ok {
+		cell = tttAction.Cell
+	} else {
+		return nil, nil, nil, fmt.Errorf("%w: unsupported action type: %T", core.ErrInvalidAction, actions[0])
+	}
+
+	mover := e.current
+	if cell < 0 || cell >= 9 || e.board[cell] != 0 {
+		// 非法落子：对局结束，行动方受罚
+		e.illegal = true
+		e.finished = true
+	} else {
+		e.play(cell)
+		if !e.finished && !e.selfPlay() {
+			e.play(e.opponentMove())
+		}
+	}
+
+	rewards := e.rewards(mover)
+	copy(e.lastRewards, rewards)
+	dones := make([]bool, e.NumAgents())
+	for i := range dones {
+		dones[i] = e.finished
+	}
+
+	return e.GetObservations(), rewards, dones, nil
+}
+
+// play 在cell落子并切换行动方，同时判定胜负
+func (e *TicTacToeEnvironment) play(cell int) {
+	e.board[cell] = e.current + 1
+	e.moves++
+	if lineWinner(e.board) == e.current+1 {
+		e.winner = e.current
+		e.finished = true
+	} else if e.moves == 9 {
+		e.finished = true
+	}
+	e.current = 1 - e.current
+}
+
+// rewards 计算各智能体的奖励，mover为本步提交动作的一方
+func (e *TicTacToeEnvironment) rewards(mover int) []float64 {
+	// 以玩家为索引的奖励
+	var byPlayer [2]float64
+	switch {
+	case e.illegal:
+		byPlayer[mover] = -e.cfg.IllegalMovePenalty
+	case e.winner >= 0:
+		byPlayer[e.winner] = 1
+		byPlayer[1-e.winner] = -1
+	}
+
+	if e.selfPlay() {
+		return []float64{byPlayer[PlayerX], byPlayer[PlayerO]}
+	}
+	return []float64{byPlayer[e.agent]}
+}
+
+// opponentMove 内置对手选择落子位置
+func (e *TicTacToeEnvironment) opponentMove() int {
+	if e.cfg.Opponent == OpponentMinimax {
+		_, cell := minimax(e.board, e.current+1)
+		return cell
+	}
+	legal := e.legalCells()
+	return legal[e.rng.Intn(len(legal))]
+}
+
+// legalCells 返回所有空格
+func (e *TicTacToeEnvironment) legalCells() []int {
+	var cells []int
+	for i, v := range e.board {
+		if v == 0 {
+			cells = append(cells, i)
+		}
+	}
+	return cells
+}
+
+// lineWinner 返回已连成一线的棋子（1或2），没有则返回0
+func lineWinner(board [9]int) int {
+	for _, line := range winLines {
+		if v := board[line[0]]; v != 0 && v == board[line[1]] && v == board[line[2]] {
+			return v
+		}
+	}
+	return 0
+}
+
+// minimax 以piece（1或2）的视角搜索，返回 (评分, 最佳落子)，评分 1 胜 / 0 平 / -1 负
+func minimax(board [9]int, piece int) (int, int) {
+	best, bestCell := -2, -1
+	for cell := 0; cell < 9; cell++ {
+		if board[cell] != 0 {
+			continue
+		}
+		board[cell] = piece
+		var score int
+		if lineWinner(board) == piece {
+			score = 1
+		} else if full(board) {
+			score = 0
+		} else {
+			opp, _ := minimax(board, 3-piece)
+			score = -opp
+		}
+		board[cell] = 0
+		if score > best {
+			best, bestCell = score, cell
+		}
+	}
+	return best, bestCell
+}
+
+func full(board [9]int) bool {
+	for _, v := range board {
+		if v == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// GetActionMask 返回当前行动方的合法落子掩码
+func (e *TicTacToeEnvironment) GetActionMask() []bool {
+	mask := make([]bool, 9)
+	if e.finished {
+		return mask
+	}
+	for i, v := range e.board {
+		mask[i] = v == 0
+	}
+	return mask
+}
+
+// GetObservations 获取各智能体视角的观察
+func (e *TicTacToeEnvironment) GetObservations() []core.Observation {
+	players := []int{PlayerX, PlayerO}
+	if !e.selfPlay() {
+		players = []int{e.agent}
+	}
+
+	mask := e.GetActionMask()
+	maskValues := make([]interface{}, len(mask))
+	for i, ok := range mask {
+		maskValues[i] = ok
+	}
+	winner := "none"
+	if e.winner >= 0 {
+		winner = playerNames[e.winner]
+	}
+
+	observations := make([]core.Observation, len(players))
+	for i, player := range players {
+		data := core.ResetBuffer(&e.obsBuf, 10)
+		for cell, v := range e.board {
+			switch v {
+			case 0:
+			case player + 1:
+				data[cell] = 1
+			default:
+				data[cell] = -1
+			}
+		}
+		if !e.finished && e.current == player {
+			data[9] = 1
+		}
+
+		var metadata map[string]interface{}
+		if e.ObservationMetadataEnabled() {
+			metadata = map[string]interface{}{
+				"agent_id":       i,
+				"agent_name":     playerNames[player],
+				"current_player": playerNames[e.current],
+				"action_mask":    maskValues,
+				"winner":         winner,
+				"illegal_move":   e.illegal,
+				"moves":          e.moves,
+			}
+		}
+		observations[i] = e.AcquireObservation(data, metadata)
+	}
+	return observations
+}
+
+// GetReward 返回最近一步各智能体的奖励
+func (e *TicTacToeEnvironment) GetReward() []float64 {
+	rewards := make([]float64, len(e.lastRewards))
+	copy(rewards, e.lastRewards)
+	return rewards
+}
+
+// GetInfo 获取环境信息
+func (e *TicTacToeEnvironment) GetInfo() map[string]interface{} {
+	info := e.BaseEnvironment.GetInfo()
+	info["num_agents"] = e.NumAgents()
+	info["opponent"] = e.cfg.Opponent
+	info["current_player"] = playerNames[e.current]
+	return info
+}
+
+// Render 将棋盘渲染为文本
+func (e *TicTacToeEnvironment) Render() string {
+	symbols := [3]string{".", "X", "O"}
+	var sb strings.Builder
+	for r := 0; r < 3; r++ {
+		row := make([]string, 3)
+		for c := 0; c < 3; c++ {
+			row[c] = symbols[e.board[r*3+c]]
+		}
+		sb.WriteString(" " + strings.Join(row, " | ") + "\n")
+		if r < 2 {
+			sb.WriteString("---+---+---\n")
+		}
+	}
+	switch {
+	case e.illegal:
+		fmt.Fprintf(&sb, "illegal move by %s\n", playerNames[1-e.current])
+	case e.winner >= 0:
+		fmt.Fprintf(&sb, "%s wins\n", playerNames[e.winner])
+	case e.finished:
+		sb.WriteString("draw\n")
+	default:
+		fmt.Fprintf(&sb, "%s to move\n", playerNames[e.current])
+	}
+	return sb.String()
+}
+
+// Close 关闭环境
+func (e *TicTacToeEnvironment) Close() error {
+	return e.BaseEnvironment.Close()
+}
+
+// Metadata 返回环境元数据：胜负为±1，非法落子时为-illegal_move_penalty；棋盘填满即结束，无需截断
+func (e *TicTacToeEnvironment) Metadata() core.EnvMetadata {
+	return core.EnvMetadata{
+		RewardRange:     [2]float64{math.Min(-1, -e.cfg.IllegalMovePenalty), 1},
+		MaxEpisodeSteps: 0,
+	}
+}
+
+// GetSpaces 获取单个智能体的动作空间和观察空间定义
+func (e *TicTacToeEnvironment) GetSpaces() core.SpaceDefinition {
+	low := make([]float64, 10)
+	high := make([]float64, 10)
+	for i := 0; i < 9; i++ {
+		low[i], high[i] = -1, 1
+	}
+	high[9] = 1
+
+	return core.SpaceDefinition{
+		ActionSpace: core.ActionSpace{
+			Type:  core.SpaceTypeDiscrete,
+			Low:   []float64{0},
+			High:  []float64{8}, // 行优先的格子索引
+			Shape: []int32{},
+			Dtype: "int32",
+		},
+		ObservationSpace: core.ObservationSpace{
+			Type:  core.SpaceTypeBox,
+			Low:   low,
+			High:  high,
+			Shape: []int32{10},
+			Dtype: "float32",
+		},
+	}
+}
+
+// TicTacToeAction 井字棋专用动作
+type TicTacToeAction struct {
+	Cell int // 0-8，行优先
+}
+
+// NewTicTacToeAction 创建新的井字棋动作
+func NewTicTacToeAction(cell int) *TicTacToeAction {
+	return &TicTacToeAction{Cell: cell}
+}
+
+// GetData 获取动作数据
+func (a *TicTacToeAction) GetData() interface{} {
+	return a.Cell
+}
+
+// Validate 验证动作
+func (a *TicTacToeAction) Validate() error {
+	if a.Cell < 0 || a.Cell >= 9 {
+		return fmt.Errorf("tictactoe cell must be in [0, 9), got %d", a.Cell)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package tictactoe
+
+import (
+	"fmt"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// TicTacToeScenario 井字棋场景实现
+type TicTacToeScenario struct {
+	name        string
+	description string
+}
+
+// 确保TicTacToeScenario实现了core.Scenario接口
+var _ core.Scenario = (*TicTacToeScenario)(nil)
+
+// NewTicTacToeScenario 创建新的井字棋场景
+func NewTicTacToeScenario() *TicTacToeScenario {
+	return &TicTacToeScenario{
+		name:        "tictactoe",
+		description: "Turn-based TicTacToe with action masks, self-play or built-in random/minimax opponent",
+	}
+}
+
+// GetName 获取场景名称
+func (s *TicTacToeScenario) GetName() string {
+	return s.name
+}
+
+// GetDescription 获取场景描述
+func (s *TicTacToeScenario) GetDescription() string {
+	return s.description
+}
+
+// CreateEnvironment 创建环境实例
+func (s *TicTacToeScenario) CreateEnvironment(config core.Config) (core.Environment, error) {
+	env, err := NewTicTacToeEnvironment(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tictactoe environment: %w", err)
+	}
+	return env, nil
+}
+
+// ValidateConfig 验证配置
+func (s *TicTacToeScenario) ValidateConfig(config core.Config) error {
+	if config == nil {
+		return fmt.Errorf("config cannot be nil")
+	}
+	_, err := parseConfig(config)
+	return err
+}
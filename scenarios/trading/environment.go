@@ -0,0 +1,342 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/jelech/rl_env_engine/core"
+	"github.com/jelech/rl_env_engine/core/config"
+)
+
+// 价格来源
+const (
+	PriceSourceGBM = "gbm" // 几何布朗运动生成
+	PriceSourceCSV = "csv" // 通过DataLoader从CSV加载
+)
+
+// Config 交易环境配置
+type Config struct {
+	MaxSteps        int     `cfg:"max_steps,default=252,min=1"`
+	PriceSource     string  `cfg:"price_source,default=gbm,oneof=gbm|csv"`
+	CSVPath         string  `cfg:"csv_path"`
+	CSVColumn       string  `cfg:"csv_column"`
+	InitialPrice    float64 `cfg:"initial_price,default=100,gt=0"`
+	Mu              float64 `cfg:"mu,default=0.05"`                      // GBM年化漂移
+	Sigma           float64 `cfg:"sigma,default=0.2,min=0"`              // GBM年化波动率
+	Dt              float64 `cfg:"dt,default=0.003968253968253968,gt=0"` // 每步对应的年化时间，默认为1/252
+	TransactionCost float64 `cfg:"transaction_cost,default=0.001,min=0"`
+	MaxPosition     float64 `cfg:"max_position,default=1.0,gt=0"`
+	Window          int     `cfg:"window,default=10,min=1"` // 观察中包含的历史对数收益个数
+	Seed            int64   `cfg:"seed"`
+}
+
+// DefaultConfig 返回默认配置
+func DefaultConfig() Config {
+	var cfg Config
+	config.MustDefaults(&cfg)
+	return cfg
+}
+
+// Validate 验证配置：cfg标签中的约束以及标签无法表达的字段间约束
+func (c Config) Validate() error {
+	if err := config.Validate(c); err != nil {
+		return err
+	}
+	if c.PriceSource == PriceSourceCSV && c.CSVPath == "" {
+		return fmt.Errorf("csv_path is required when price_source is %q", PriceSourceCSV)
+	}
+	return nil
+}
+
+// parseConfig 将core.Config绑定为交易配置，未提供的字段使用默认值
+func parseConfig(src core.Config) (Config, error) {
+	var cfg Config
+	if err := config.Bind(src, &cfg); err != nil {
+		return cfg, err
+	}
+	return cfg, cfg.Validate()
+}
+
+// TradingEnvironment 单资产交易环境
+// 动作为目标仓位（[-max_position, max_position]，负数为做空），
+// 奖励为该步仓位带来的收益减去调仓的交易成本
+type TradingEnvironment struct {
+	*core.BaseEnvironment
+	cfg Config
+
+	// 当前episode的价格序列
+	prices      []float64
+	position    float64
+	equity      float64 // 累计收益
+	currentStep int
+	maxSteps    int // 本episode实际步数（CSV数据不足时可能小于配置值）
+	lastReward  float64
+
+	// CSV模式下加载的完整价格序列，只加载一次
+	history []float64
+
+	rng *rand.Rand
+
+	obsBuf []float64 // GetObservations复用的观察数据缓冲区
+}
+
+// NewTradingEnvironment 创建新的交易环境
+func NewTradingEnvironment(config core.Config) (*TradingEnvironment, error) {
+	cfg, err := parseConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	baseEnv := core.NewBaseEnvironment("trading", "Single-asset trading environment with transaction costs", config)
+
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	env := &TradingEnvironment{
+		BaseEnvironment: baseEnv,
+		cfg:             cfg,
+		maxSteps:        cfg.MaxSteps,
+		rng:             rand.New(rand.NewSource(seed)),
+	}
+
+	if cfg.PriceSource == PriceSourceCSV {
+		env.SetDataLoader(NewCSVPriceLoader(cfg.CSVColumn))
+		if err := env.loadHistory(); err != nil {
+			return nil, err
+		}
+	}
+
+	return env, nil
+}
+
+// loadHistory 通过DataLoader加载CSV价格序列
+func (e *TradingEnvironment) loadHistory() error {
+	loader := e.GetDataLoader()
+	if loader == nil {
+		return core.NewSimulationError(core.ErrDataLoadFailed, "no data loader configured", nil)
+	}
+
+	data, err := loader.Load(e.cfg.CSVPath)
+	if err != nil {
+		return err
+	}
+	if err := loader.Validate(data); err != nil {
+		return err
+	}
+
+	prices, ok := data.([]float64)
+	if !ok {
+		return core.NewSimulationError(core.ErrDataLoadFailed, fmt.Sprintf("data loader returned %T, expected []float64", data), nil)
+	}
+	e.history = prices
+	return nil
+}
+
+// Reset 重置环境：生成（GBM）或截取（CSV）新的价格序列
+func (e *TradingEnvironment) Reset(ctx context.Context) ([]core.Observation, error) {
+	switch e.cfg.PriceSource {
+	case PriceSourceCSV:
+		e.maxSteps = e.cfg.MaxSteps
+		if e.maxSteps > len(e.history)-1 {
+			e.maxSteps = len(e.history) - 1
+		}
+		// 随机选择起点，使不同episode覆盖不同区间
+		start := 0
+		if span := len(e.history) - 1 - e.maxSteps; span > 0 {
+			start = e.rng.Intn(span + 1)
+		}
+		e.prices = e.history[start : start+e.maxSteps+1]
+	default:
+		e.maxSteps = e.cfg.MaxSteps
+		e.prices = e.generateGBM(e.maxSteps + 1)
+	}
+
+	e.position = 0
+	e.equity = 0
+	e.lastReward = 0
+	e.currentStep = 0
+
+	return e.GetObservations(), nil
+}
+
+// generateGBM 用几何布朗运动生成价格序列
+func (e *TradingEnvironment) generateGBM(n int) []float64 {
+	prices := make([]float64, n)
+	prices[0] = e.cfg.InitialPrice
+	drift := (e.cfg.Mu - 0.5*e.cfg.Sigma*e.cfg.Sigma) * e.cfg.Dt
+	vol := e.cfg.Sigma * math.Sqrt(e.cfg.Dt)
+	for i := 1; i < n; i++ {
+		prices[i] = prices[i-1] * math.Exp(drift+vol*e.rng.NormFloat64())
+	}
+	return prices
+}
+
+// Step 执行一步：调整仓位，然后结算价格变动
+func (e *TradingEnvironment) Step(ctx context.Context, actions []core.Action) ([]core.Observation, []float64, []bool, error) {
+	if len(actions) == 0 {
+		return nil, nil, nil, fmt.Errorf("%w: no actions provided", core.ErrInvalidAction)
+	}
+	if e.prices == nil {
+		return nil, nil, nil, fmt.Errorf("environment must be reset before step")
+	}
+	if e.currentStep >= e.maxSteps {
+		return nil, nil, nil, fmt.Errorf("episode is done, call reset")
+	}
+
+	var target float64
+	if genericAction, ok := actions[0].(*core.GenericAction); ok {
+		var err error
+		target, err = genericAction.GetFloat64()
+		if err != nil {
+			// 兼容以长度为1的数组传入的Box动作
+			values, sliceErr := genericAction.GetFloat64Slice()
+			if sliceErr != nil || len(values) == 0 {
+				return nil, nil, nil, fmt.Errorf("%w: failed to extract action value: %w", core.ErrInvalidAction, err)
+			}
+			target = values[0]
+		}
+	} else if tradingAction, ok := actions[0].(*TradingAction); ok {
+		target = tradingAction.Position
+	} else {
+		return nil, nil, nil, fmt.Errorf("%w: unsupported action type: %T", core.ErrInvalidAction, actions[0])
+	}
+
+	// 限制仓位
+	target = math.Max(-e.cfg.MaxPosition, math.Min(e.cfg.MaxPosition, target))
+
+	// 交易成本按调仓幅度计算
+	cost := e.cfg.TransactionCost * math.Abs(target-e.position)
+	e.position = target
+
+	// 结算：仓位 × 价格收益率
+	ret := e.prices[e.currentStep+1]/e.prices[e.currentStep] - 1
+	pnl := e.position * ret
+
+	e.currentStep++
+	e.lastReward = pnl - cost
+	e.equity += e.lastReward
+
+	done := e.currentStep >= e.maxSteps
+
+	return e.GetObservations(), []float64{e.lastReward}, []bool{done}, nil
+}
+
+// GetObservations 获取当前观察
+// [最近window个对数收益..., 当前仓位, 相对起始价格, 累计收益, 进度]
+func (e *TradingEnvironment) GetObservations() []core.Observation {
+	window := e.cfg.Window
+	data := core.ResetBuffer(&e.obsBuf, window+4)[:0]
+
+	// 不足window的部分用0补齐
+	for i := e.currentStep - window + 1; i <= e.currentStep; i++ {
+		if i <= 0 || i >= len(e.prices) {
+			data = append(data, 0)
+			continue
+		}
+		data = append(data, math.Log(e.prices[i]/e.prices[i-1]))
+	}
+
+	price, relPrice := 0.0, 1.0
+	if len(e.prices) > 0 {
+		price = e.prices[e.currentStep]
+		relPrice = price / e.prices[0]
+	}
+	progress := 0.0
+	if e.maxSteps > 0 {
+		progress = float64(e.currentStep) / float64(e.maxSteps)
+	}
+
+	data = append(data, e.position, relPrice, e.equity, progress)
+
+	var metadata map[string]interface{}
+	if e.ObservationMetadataEnabled() {
+		metadata = map[string]interface{}{
+			"price":     price,
+			"position":  e.position,
+			"equity":    e.equity,
+			"step":      e.currentStep,
+			"max_steps": e.maxSteps,
+			"source":    e.cfg.PriceSource,
+		}
+	}
+
+	observation := e.AcquireObservation(data, metadata)
+	return []core.Observation{observation}
+}
+
+// GetReward 返回最近一步的奖励
+func (e *TradingEnvironment) GetReward() []float64 {
+	return []float64{e.lastReward}
+}
+
+// Close 关闭环境
+func (e *TradingEnvironment) Close() error {
+	e.prices = nil
+	e.history = nil
+	return e.BaseEnvironment.Close()
+}
+
+// Metadata 返回环境元数据：奖励为扣除交易成本后的单步盈亏，随价格变动双向无界
+func (e *TradingEnvironment) Metadata() core.EnvMetadata {
+	metadata := core.DefaultEnvMetadata()
+	metadata.MaxEpisodeSteps = e.cfg.MaxSteps
+	return metadata
+}
+
+// GetSpaces 获取交易场景的动作空间和观察空间定义
+func (e *TradingEnvironment) GetSpaces() core.SpaceDefinition {
+	obsDim := e.cfg.Window + 4
+	low := make([]float64, obsDim)
+	high := make([]float64, obsDim)
+	for i := 0; i < e.cfg.Window; i++ {
+		low[i], high[i] = -1e6, 1e6
+	}
+	// [position, rel_price, equity, progress]
+	copy(low[e.cfg.Window:], []float64{-e.cfg.MaxPosition, 0, -1e6, 0})
+	copy(high[e.cfg.Window:], []float64{e.cfg.MaxPosition, 1e6, 1e6, 1})
+
+	return core.SpaceDefinition{
+		ActionSpace: core.ActionSpace{
+			Type:  core.SpaceTypeBox,
+			Low:   []float64{-e.cfg.MaxPosition},
+			High:  []float64{e.cfg.MaxPosition},
+			Shape: []int32{1},
+			Dtype: "float32",
+		},
+		ObservationSpace: core.ObservationSpace{
+			Type:  core.SpaceTypeBox,
+			Low:   low,
+			High:  high,
+			Shape: []int32{int32(obsDim)},
+			Dtype: "float32",
+		},
+	}
+}
+
+// TradingAction 交易专用动作
+type TradingAction struct {
+	Position float64 // 目标仓位
+}
+
+// NewTradingAction 创建新的交易动作
+func NewTradingAction(position float64) *TradingAction {
+	return &TradingAction{Position: position}
+}
+
+// GetData 获取动作数据
+func (a *TradingAction) GetData() interface{} {
+	return a.Position
+}
+
+// Validate 验证动作
+func (a *TradingAction) Validate() error {
+	if math.IsNaN(a.Position) || math.IsInf(a.Position, 0) {
+		return fmt.Errorf("trading position must be finite, got %f", a.Position)
+	}
+	return nil
+}
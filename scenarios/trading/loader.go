@@ -0,0 +1,119 @@
+package trading
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// CSVPriceLoader 从CSV文件加载价格序列
+// 支持单列价格文件，或通过列名/列索引指定价格列；首行非数字时视为表头
+type CSVPriceLoader struct {
+	Column string // 价格列名，为空时使用 ColumnIndex
+	// ColumnIndex 价格列索引（从0开始），仅在 Column 为空时使用
+	ColumnIndex int
+}
+
+// 确保CSVPriceLoader实现了core.DataLoader接口
+var _ core.DataLoader = (*CSVPriceLoader)(nil)
+
+// NewCSVPriceLoader 创建新的CSV价格加载器
+func NewCSVPriceLoader(column string) *CSVPriceLoader {
+	return &CSVPriceLoader{Column: column}
+}
+
+// Load 读取CSV文件并返回 []float64 价格序列
+func (l *CSVPriceLoader) Load(path string) (interface{}, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, core.NewSimulationError(core.ErrDataLoadFailed, fmt.Sprintf("failed to open %s", path), err)
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	col := l.ColumnIndex
+	var prices []float64
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, core.NewSimulationError(core.ErrDataLoadFailed, fmt.Sprintf("failed to read %s", path), err)
+		}
+
+		// 首行：解析表头
+		if line == 1 {
+			if idx, ok := l.headerIndex(record); ok {
+				col = idx
+				continue
+			}
+			if l.Column != "" {
+				return nil, core.NewSimulationError(core.ErrDataLoadFailed, fmt.Sprintf("column %q not found in %s", l.Column, path), nil)
+			}
+			if _, err := strconv.ParseFloat(strings.TrimSpace(field(record, col)), 64); err != nil {
+				continue // 未指定列名的表头行
+			}
+		}
+
+		if col < 0 || col >= len(record) {
+			return nil, core.NewSimulationError(core.ErrDataLoadFailed, fmt.Sprintf("line %d has no column %d", line, col), nil)
+		}
+		price, err := strconv.ParseFloat(strings.TrimSpace(record[col]), 64)
+		if err != nil {
+			return nil, core.NewSimulationError(core.ErrDataLoadFailed, fmt.Sprintf("invalid price at line %d", line), err)
+		}
+		prices = append(prices, price)
+	}
+
+	if err := l.Validate(prices); err != nil {
+		return nil, err
+	}
+	return prices, nil
+}
+
+// Validate 验证价格序列：至少两个点且均为正的有限数
+func (l *CSVPriceLoader) Validate(data interface{}) error {
+	prices, ok := data.([]float64)
+	if !ok {
+		return core.NewSimulationError(core.ErrInvalidParameter, fmt.Sprintf("expected []float64 price series, got %T", data), nil)
+	}
+	if len(prices) < 2 {
+		return core.NewSimulationError(core.ErrDataLoadFailed, fmt.Sprintf("price series needs at least 2 points, got %d", len(prices)), nil)
+	}
+	for i, p := range prices {
+		if p <= 0 || math.IsNaN(p) || math.IsInf(p, 0) {
+			return core.NewSimulationError(core.ErrDataLoadFailed, fmt.Sprintf("price at index %d must be positive and finite, got %v", i, p), nil)
+		}
+	}
+	return nil
+}
+
+// headerIndex 在表头中查找列名
+func (l *CSVPriceLoader) headerIndex(record []string) (int, bool) {
+	if l.Column == "" {
+		return 0, false
+	}
+	for i, name := range record {
+		if strings.EqualFold(strings.TrimSpace(name), l.Column) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func field(record []string, i int) string {
+	if i < 0 || i >= len(record) {
+		return ""
+	}
+	return record[i]
+}
@@ -0,0 +1,52 @@
+package trading
+
+import (
+	"fmt"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// TradingScenario 交易场景实现
+type TradingScenario struct {
+	name        string
+	description string
+}
+
+// 确保TradingScenario实现了core.Scenario接口
+var _ core.Scenario = (*TradingScenario)(nil)
+
+// NewTradingScenario 创建新的交易场景
+func NewTradingScenario() *TradingScenario {
+	return &TradingScenario{
+		name:        "trading",
+		description: "Toy single-asset trading environment - GBM or CSV prices, PnL reward with transaction costs",
+	}
+}
+
+// GetName 获取场景名称
+func (s *TradingScenario) GetName() string {
+	return s.name
+}
+
+// GetDescription 获取场景描述
+func (s *TradingScenario) GetDescription() string {
+	return s.description
+}
+
+// CreateEnvironment 创建环境实例
+func (s *TradingScenario) CreateEnvironment(config core.Config) (core.Environment, error) {
+	env, err := NewTradingEnvironment(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trading environment: %w", err)
+	}
+	return env, nil
+}
+
+// ValidateConfig 验证配置
+func (s *TradingScenario) ValidateConfig(config core.Config) error {
+	if config == nil {
+		return fmt.Errorf("config cannot be nil")
+	}
+	_, err := parseConfig(config)
+	return err
+}
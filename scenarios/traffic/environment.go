@@ -0,0 +1,418 @@
+package traffic
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/jelech/rl_env_engine/core"
+	"github.com/jelech/rl_env_engine/core/config"
+)
+
+// 进口车道方向（车辆来自的方向）
+const (
+	laneNorth = iota // 来自北侧，向南行驶
+	laneSouth        // 来自南侧，向北行驶
+	laneEast         // 来自东侧，向西行驶
+	laneWest         // 来自西侧，向东行驶
+	numLanes
+)
+
+// 信号相位
+const (
+	PhaseNS = 0 // 南北方向绿灯
+	PhaseEW = 1 // 东西方向绿灯
+)
+
+// Config 交通信号环境配置
+type Config struct {
+	MaxSteps       int     `cfg:"max_steps,default=300,min=1"`
+	Rows           int     `cfg:"rows,default=2,min=1"`
+	Cols           int     `cfg:"cols,default=2,min=1"`
+	ArrivalRate    float64 `cfg:"arrival_rate,default=0.3,min=0"`  // 边界进口每步的平均到达车辆数（泊松）
+	SaturationFlow int     `cfg:"saturation_flow,default=1,min=1"` // 绿灯时每条车道每步最多通过的车辆数
+	LaneCapacity   int     `cfg:"lane_capacity,default=40,min=1"`  // 每条车道最大排队长度
+	YellowTime     int     `cfg:"yellow_time,default=2,min=0"`     // 切换相位时的全红步数
+	Seed           int64   `cfg:"seed"`
+}
+
+// DefaultConfig 返回默认配置
+func DefaultConfig() Config {
+	var cfg Config
+	config.MustDefaults(&cfg)
+	return cfg
+}
+
+// Validate 按cfg标签中的约束验证配置，用于校验直接构造的配置
+func (c Config) Validate() error {
+	return config.Validate(c)
+}
+
+// parseConfig 将core.Config绑定为交通信号配置，未提供的字段使用默认值
+func parseConfig(src core.Config) (Config, error) {
+	var cfg Config
+	if err := config.Bind(src, &cfg); err != nil {
+		return cfg, err
+	}
+	return cfg, cfg.Validate()
+}
+
+// intersection 单个路口的状态
+type intersection struct {
+	queues      [numLanes]int
+	phase       int
+	yellowLeft  int // 剩余全红步数，>0时所有方向红灯
+	sincePhase  int // 当前相位已持续的步数
+	lastWaiting int // 上一步结束时的排队总数
+}
+
+// TrafficEnvironment 网格路网的多智能体交通信号控制环境
+// 每个路口一个智能体，动作为期望相位（0: 南北绿灯, 1: 东西绿灯）；
+// Step 接收与智能体数量相同的动作，返回逐智能体的观察、奖励和结束标志。
+// 智能体按行优先顺序编号：agent_id = row*cols + col
+type TrafficEnvironment struct {
+	*core.BaseEnvironment
+	cfg Config
+
+	nodes       []intersection
+	currentStep int
+	throughput  int // 本episode离开路网的车辆数
+	lastRewards []float64
+
+	rng *rand.Rand
+
+	obsBuf []float64 // GetObservations复用的观察数据缓冲区
+}
+
+// NewTrafficEnvironment 创建新的交通信号环境
+func NewTrafficEnvironment(config core.Config) (*TrafficEnvironment, error) {
+	cfg, err := parseConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	baseEnv := core.NewBaseEnvironment("traffic", "Multi-agent traffic signal control environment", config)
+
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	n := cfg.Rows * cfg.Cols
+	return &TrafficEnvironment{
+		BaseEnvironment: baseEnv,
+		cfg:             cfg,
+		nodes:           make([]intersection, n),
+		lastRewards:     make([]float64, n),
+		rng:             rand.New(rand.NewSource(seed)),
+	}, nil
+}
+
+// NumAgents 返回智能体（路口）数量
+func (e *TrafficEnvironment) NumAgents() int {
+	return len(e.nodes)
+}
+
+// Reset 重置环境：清空所有车道，随机初始化相位
+func (e *TrafficEnvironment) Reset(ctx context.Context) ([]core.Observation, error) {
+	for i := range e.nodes {
+		e.nodes[i] = intersection{phase: e.rng.Intn(2)}
+	}
+	for i := range e.lastRewards {
+		e.lastRewards[i] = 0
+	}
+	e.currentStep = 0
+	e.throughput = 0
+
+	return e.GetObservations(), nil
+}
+
+// Step 执行一步：应用各路口相位，放行绿灯车道车辆并生成新到达车辆
+func (e *TrafficEnvironment) Step(ctx context.Context, actions []core.Action) ([]core.Observation, []float64, []bool, error) {
+	if len(actions) != len(e.nodes) {
+		return nil, nil, nil, fmt.Errorf("%w: expected %d actions (one per intersection), got %d", core.ErrInvalidAction, len(e.nodes), len(actions))
+	}
+
+	// 解析并应用相位
+	for i, action := range actions {
+		phase, err := parsePhase(action)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("agent %d: %w", i, err)
+		}
+		node := &e.nodes[i]
+		if node.yellowLeft > 0 {
+			node.yellowLeft--
+			continue
+		}
+		if phase != node.phase {
+			node.phase = phase
+			node.sincePhase = 0
+			node.yellowLeft = e.cfg.YellowTime
+		}
+	}
+
+	// 基于步初状态计算放行量，再统一更新，保证各路口同步推进
+	moved := make([][numLanes]int, len(e.nodes))
+	for i := range e.nodes {
+		node := &e.nodes[i]
+		if node.yellowLeft > 0 {
+			continue
+		}
+		for _, lane := range greenLanes(node.phase) {
+			n := node.queues[lane]
+			if n > e.cfg.SaturationFlow {
+				n = e.cfg.SaturationFlow
+			}
+			// 下游车道满时阻塞
+			if next, nextLane, ok := e.downstream(i, lane); ok {
+				if space := e.cfg.LaneCapacity - e.nodes[next].queues[nextLane]; n > space {
+					n = space
+				}
+			}
+			moved[i][lane] = n
+		}
+	}
+	for i := range e.nodes {
+		for lane := 0; lane < numLanes; lane++ {
+			n := moved[i][lane]
+			if n == 0 {
+				continue
+			}
+			e.nodes[i].queues[lane] -= n
+			if next, nextLane, ok := e.downstream(i, lane); ok {
+				e.nodes[next].queues[nextLane] += n
+			} else {
+				e.throughput += n
+			}
+		}
+	}
+
+	// 边界进口的新到达车辆
+	for i := range e.nodes {
+		for _, lane := range e.boundaryLanes(i) {
+			arrivals := e.poisson(e.cfg.ArrivalRate)
+			node := &e.nodes[i]
+			node.queues[lane] += arrivals
+			if node.queues[lane] > e.cfg.LaneCapacity {
+				node.queues[lane] = e.cfg.LaneCapacity
+			}
+		}
+	}
+
+	// 奖励：各路口进口车道排队总数的负值
+	for i := range e.nodes {
+		node := &e.nodes[i]
+		waiting := 0
+		for _, q := range node.queues {
+			waiting += q
+		}
+		node.lastWaiting = waiting
+		node.sincePhase++
+		e.lastRewards[i] = -float64(waiting)
+	}
+
+	e.currentStep++
+	done := e.currentStep >= e.cfg.MaxSteps
+
+	rewards := make([]float64, len(e.nodes))
+	copy(rewards, e.lastRewards)
+	dones := make([]bool, len(e.nodes))
+	for i := range dones {
+		dones[i] = done
+	}
+
+	return e.GetObservations(), rewards, dones, nil
+}
+
+// parsePhase 从动作中解析相位
+func parsePhase(action core.Action) (int, error) {
+	var phase int
+	if genericAction, ok := action.(*core.GenericAction); ok {
+		value, err := genericAction.GetInt64()
+		if err != nil {
+			return 0, fmt.Errorf("%w: failed to extract action value: %w", core.ErrInvalidAction, err)
+		}
+		phase = int(value)
+	} else if trafficAction, ok := action.(*TrafficAction); ok {
+		phase = trafficAction.Phase
+	} else {
+		return 0, fmt.Errorf("%w: unsupported action type: %T", core.ErrInvalidAction, action)
+	}
+	if phase != PhaseNS && phase != PhaseEW {
+		return 0, fmt.Errorf("phase must be 0 (NS) or 1 (EW), got %d", phase)
+	}
+	return phase, nil
+}
+
+// greenLanes 返回给定相位下的绿灯车道
+func greenLanes(phase int) []int {
+	if phase == PhaseNS {
+		return []int{laneNorth, laneSouth}
+	}
+	return []int{laneEast, laneWest}
+}
+
+// downstream 返回车辆通过路口后进入的下游路口及车道，驶出路网时ok为false
+func (e *TrafficEnvironment) downstream(i, lane int) (int, int, bool) {
+	r, c := i/e.cfg.Cols, i%e.cfg.Cols
+	switch lane {
+	case laneNorth: // 向南
+		r++
+	case laneSouth: // 向北
+		r--
+	case laneEast: // 向西
+		c--
+	case laneWest: // 向东
+		c++
+	}
+	if r < 0 || r >= e.cfg.Rows || c < 0 || c >= e.cfg.Cols {
+		return 0, 0, false
+	}
+	return r*e.cfg.Cols + c, lane, true
+}
+
+// boundaryLanes 返回路口i在路网边界上的进口车道
+func (e *TrafficEnvironment) boundaryLanes(i int) []int {
+	r, c := i/e.cfg.Cols, i%e.cfg.Cols
+	var lanes []int
+	if r == 0 {
+		lanes = append(lanes, laneNorth)
+	}
+	if r == e.cfg.Rows-1 {
+		lanes = append(lanes, laneSouth)
+	}
+	if c == e.cfg.Cols-1 {
+		lanes = append(lanes, laneEast)
+	}
+	if c == 0 {
+		lanes = append(lanes, laneWest)
+	}
+	return lanes
+}
+
+// poisson 采样泊松分布（Knuth算法，适用于较小的lambda）
+func (e *TrafficEnvironment) poisson(lambda float64) int {
+	if lambda <= 0 {
+		return 0
+	}
+	limit := math.Exp(-lambda)
+	k, p := 0, 1.0
+	for {
+		p *= e.rng.Float64()
+		if p <= limit {
+			return k
+		}
+		k++
+	}
+}
+
+// GetObservations 获取每个智能体的观察
+// [北/南/东/西进口排队数..., 南北绿灯, 东西绿灯, 是否全红, 相位持续步数]
+func (e *TrafficEnvironment) GetObservations() []core.Observation {
+	observations := make([]core.Observation, len(e.nodes))
+	for i, node := range e.nodes {
+		data := core.ResetBuffer(&e.obsBuf, numLanes+4)[:0]
+		for _, q := range node.queues {
+			data = append(data, float64(q))
+		}
+		ns, ew, yellow := 0.0, 0.0, 0.0
+		if node.yellowLeft > 0 {
+			yellow = 1
+		} else if node.phase == PhaseNS {
+			ns = 1
+		} else {
+			ew = 1
+		}
+		data = append(data, ns, ew, yellow, float64(node.sincePhase))
+
+		var metadata map[string]interface{}
+		if e.ObservationMetadataEnabled() {
+			metadata = map[string]interface{}{
+				"agent_id":  i,
+				"row":       i / e.cfg.Cols,
+				"col":       i % e.cfg.Cols,
+				"phase":     node.phase,
+				"waiting":   node.lastWaiting,
+				"step":      e.currentStep,
+				"max_steps": e.cfg.MaxSteps,
+			}
+		}
+		observations[i] = e.AcquireObservation(data, metadata)
+	}
+	return observations
+}
+
+// GetReward 返回最近一步各智能体的奖励
+func (e *TrafficEnvironment) GetReward() []float64 {
+	rewards := make([]float64, len(e.lastRewards))
+	copy(rewards, e.lastRewards)
+	return rewards
+}
+
+// GetInfo 获取环境信息
+func (e *TrafficEnvironment) GetInfo() map[string]interface{} {
+	info := e.BaseEnvironment.GetInfo()
+	info["num_agents"] = len(e.nodes)
+	info["throughput"] = e.throughput
+	return info
+}
+
+// Close 关闭环境
+func (e *TrafficEnvironment) Close() error {
+	e.nodes = nil
+	return e.BaseEnvironment.Close()
+}
+
+// Metadata 返回环境元数据：奖励为路口各进口车道排队总数的负值，各车道最多排队lane_capacity辆
+func (e *TrafficEnvironment) Metadata() core.EnvMetadata {
+	metadata := core.DefaultEnvMetadata()
+	metadata.MaxEpisodeSteps = e.cfg.MaxSteps
+	metadata.RewardRange = [2]float64{-float64(numLanes * e.cfg.LaneCapacity), 0}
+	return metadata
+}
+
+// GetSpaces 获取单个智能体的动作空间和观察空间定义（所有智能体同构）
+func (e *TrafficEnvironment) GetSpaces() core.SpaceDefinition {
+	capacity := float64(e.cfg.LaneCapacity)
+	return core.SpaceDefinition{
+		ActionSpace: core.ActionSpace{
+			Type:  core.SpaceTypeDiscrete,
+			Low:   []float64{0},
+			High:  []float64{1}, // 0: 南北绿灯, 1: 东西绿灯
+			Shape: []int32{},
+			Dtype: "int32",
+		},
+		ObservationSpace: core.ObservationSpace{
+			Type:  core.SpaceTypeBox,
+			Low:   []float64{0, 0, 0, 0, 0, 0, 0, 0},
+			High:  []float64{capacity, capacity, capacity, capacity, 1, 1, 1, float64(e.cfg.MaxSteps)},
+			Shape: []int32{numLanes + 4},
+			Dtype: "float32",
+		},
+	}
+}
+
+// TrafficAction 交通信号专用动作
+type TrafficAction struct {
+	Phase int // 0: 南北绿灯, 1: 东西绿灯
+}
+
+// NewTrafficAction 创建新的交通信号动作
+func NewTrafficAction(phase int) *TrafficAction {
+	return &TrafficAction{Phase: phase}
+}
+
+// GetData 获取动作数据
+func (a *TrafficAction) GetData() interface{} {
+	return a.Phase
+}
+
+// Validate 验证动作
+func (a *TrafficAction) Validate() error {
+	if a.Phase != PhaseNS && a.Phase != PhaseEW {
+		return fmt.Errorf("traffic phase must be 0 (NS) or 1 (EW), got %d", a.Phase)
+	}
+	return nil
+}
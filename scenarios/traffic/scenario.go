@@ -0,0 +1,52 @@
+package traffic
+
+import (
+	"fmt"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// TrafficScenario 交通信号控制场景实现
+type TrafficScenario struct {
+	name        string
+	description string
+}
+
+// 确保TrafficScenario实现了core.Scenario接口
+var _ core.Scenario = (*TrafficScenario)(nil)
+
+// NewTrafficScenario 创建新的交通信号场景
+func NewTrafficScenario() *TrafficScenario {
+	return &TrafficScenario{
+		name:        "traffic",
+		description: "Multi-agent traffic signal control - one agent per intersection in a grid network",
+	}
+}
+
+// GetName 获取场景名称
+func (s *TrafficScenario) GetName() string {
+	return s.name
+}
+
+// GetDescription 获取场景描述
+func (s *TrafficScenario) GetDescription() string {
+	return s.description
+}
+
+// CreateEnvironment 创建环境实例
+func (s *TrafficScenario) CreateEnvironment(config core.Config) (core.Environment, error) {
+	env, err := NewTrafficEnvironment(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create traffic environment: %w", err)
+	}
+	return env, nil
+}
+
+// ValidateConfig 验证配置
+func (s *TrafficScenario) ValidateConfig(config core.Config) error {
+	if config == nil {
+		return fmt.Errorf("config cannot be nil")
+	}
+	_, err := parseConfig(config)
+	return err
+}
@@ -0,0 +1,365 @@
+package walker
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/jelech/rl_env_engine/core"
+	"github.com/jelech/rl_env_engine/core/config"
+	"github.com/jelech/rl_env_engine/core/physics"
+)
+
+// 身体尺寸与质量
+const (
+	torsoLength = 0.6
+	thighLength = 0.5
+	shinLength  = 0.5
+	headMass    = 3.0
+	hipMass     = 3.0
+	kneeMass    = 1.0
+	footMass    = 1.0
+)
+
+// 关节顺序：左髋、左膝、右髋、右膝
+const numJoints = 4
+
+// jointNames 开启named_actions时动作各分量的名称，按关节顺序排列（同时也是字典序）
+var jointNames = [numJoints]string{"left_hip", "left_knee", "right_hip", "right_knee"}
+
+// 观察维度：躯干角度、躯干角速度、髋部水平/竖直速度、髋部高度、4个关节角、4个关节角速度、2个足部触地
+const numObs = 5 + 2*numJoints + 2
+
+// Config 步行者环境配置
+type Config struct {
+	MaxSteps       int     `cfg:"max_steps,default=1000,min=1"`
+	Dt             float64 `cfg:"dt,default=0.02,gt=0,max=0.1"`
+	MaxTorque      float64 `cfg:"max_torque,default=60.0,gt=0"`                           // 动作[-1, 1]对应的最大关节扭矩
+	Gravity        float64 `cfg:"gravity,default=9.8,min=0"`                              // 重力加速度（正值，方向向下）
+	Friction       float64 `cfg:"friction,default=0.9,min=0,max=1"`                       // 地面摩擦系数
+	ForwardWeight  float64 `cfg:"forward_weight,default=1.0"`                             // 前进速度奖励系数
+	HealthyReward  float64 `cfg:"healthy_reward,default=1.0"`                             // 每步未摔倒的奖励
+	CtrlCostWeight float64 `cfg:"ctrl_cost,default=0.001"`                                // 控制代价系数
+	FallPenalty    float64 `cfg:"fall_penalty,default=10.0"`                              // 摔倒惩罚
+	MaxTorsoAngle  float64 `cfg:"max_torso_angle,default=1.0,gt=0,max=3.141592653589793"` // 躯干偏离竖直方向的最大角度，超过视为摔倒，不超过π
+	ResetNoise     float64 `cfg:"reset_noise,default=0.01,min=0"`                         // 初始位置的均匀噪声幅度
+	Seed           int64   `cfg:"seed"`
+	NamedActions   bool    `cfg:"named_actions,default=false"` // 为true时动作空间为以关节名称为键的Dict，每个关节一个[-1, 1]的扭矩
+}
+
+// DefaultConfig 返回默认配置
+func DefaultConfig() Config {
+	var cfg Config
+	config.MustDefaults(&cfg)
+	return cfg
+}
+
+// Validate 按cfg标签中的约束验证配置，用于校验直接构造的配置
+func (c Config) Validate() error {
+	return config.Validate(c)
+}
+
+// parseConfig 将core.Config绑定为步行者配置，未提供的字段使用默认值
+func parseConfig(src core.Config) (Config, error) {
+	var cfg Config
+	if err := config.Bind(src, &cfg); err != nil {
+		return cfg, err
+	}
+	return cfg, cfg.Validate()
+}
+
+// body 步行者的质点与关节
+type body struct {
+	world  *physics.World
+	head   *physics.Particle
+	hip    *physics.Particle
+	knees  [2]*physics.Particle
+	feet   [2]*physics.Particle
+	joints [numJoints]*physics.Joint
+}
+
+// WalkerEnvironment 二维双足步行者环境，基于 core/physics 的Verlet物理引擎
+// 身体由躯干（头-髋）和两条腿（大腿、小腿）组成，动作为4个关节（左髋、左膝、右髋、右膝）的
+// 归一化扭矩 [-1, 1]。奖励为 前进速度 + 存活奖励 - 控制代价，躯干倾斜过大或髋部过低时视为摔倒
+type WalkerEnvironment struct {
+	*core.BaseEnvironment
+	cfg Config
+
+	body        *body
+	torsoAngle  float64
+	torsoVel    float64
+	currentStep int
+	lastReward  float64
+	fallen      bool
+
+	rng *rand.Rand
+
+	obsBuf []float64 // GetObservations复用的观察数据缓冲区
+}
+
+// NewWalkerEnvironment 创建新的步行者环境
+func NewWalkerEnvironment(config core.Config) (*WalkerEnvironment, error) {
+	cfg, err := parseConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	baseEnv := core.NewBaseEnvironment("walker", "2D biped walker continuous control environment", config)
+
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	return &WalkerEnvironment{
+		BaseEnvironment: baseEnv,
+		cfg:             cfg,
+		rng:             rand.New(rand.NewSource(seed)),
+	}, nil
+}
+
+// buildBody 在新的物理世界中构建站立姿态的步行者
+func (e *WalkerEnvironment) buildBody() (*body, error) {
+	world := physics.NewWorld(physics.V(0, -e.cfg.Gravity))
+	world.Friction = e.cfg.Friction
+
+	noise := func() float64 {
+		return (e.rng.Float64()*2 - 1) * e.cfg.ResetNoise
+	}
+
+	legLength := thighLength + shinLength
+	b := &body{world: world}
+	b.hip = world.AddParticle(physics.V(noise(), legLength), hipMass)
+	b.head = world.AddParticle(physics.V(noise(), legLength+torsoLength), headMass)
+	world.AddStick(b.hip, b.head)
+
+	for i, side := range [2]float64{1, -1} {
+		// 两腿略微前后错开，膝盖稍向前弯曲
+		b.knees[i] = world.AddParticle(physics.V(0.02+side*0.01+noise(), shinLength), kneeMass)
+		b.feet[i] = world.AddParticle(physics.V(side*0.01+noise(), 0), footMass)
+		world.AddStick(b.hip, b.knees[i])
+		world.AddStick(b.knees[i], b.feet[i])
+
+		hipJoint, err := world.AddJoint(b.head, b.hip, b.knees[i], -0.8, 1.2)
+		if err != nil {
+			return nil, err
+		}
+		kneeJoint, err := world.AddJoint(b.hip, b.knees[i], b.feet[i], -1.6, 0)
+		if err != nil {
+			return nil, err
+		}
+		b.joints[2*i] = hipJoint
+		b.joints[2*i+1] = kneeJoint
+	}
+
+	for _, j := range b.joints {
+		j.LimitStiffness = 400
+		j.Damping = 0.5
+	}
+	return b, nil
+}
+
+// Reset 重置环境：重建物理世界并回到站立姿态
+func (e *WalkerEnvironment) Reset(ctx context.Context) ([]core.Observation, error) {
+	b, err := e.buildBody()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build walker body: %w", err)
+	}
+	e.body = b
+	e.torsoAngle = e.computeTorsoAngle()
+	e.torsoVel = 0
+	e.currentStep = 0
+	e.lastReward = 0
+	e.fallen = false
+
+	return e.GetObservations(), nil
+}
+
+// Step 执行一步仿真
+func (e *WalkerEnvironment) Step(ctx context.Context, actions []core.Action) ([]core.Observation, []float64, []bool, error) {
+	if len(actions) == 0 {
+		return nil, nil, nil, fmt.Errorf("%w: no actions provided", core.ErrInvalidAction)
+	}
+	if e.body == nil {
+		return nil, nil, nil, fmt.Errorf("environment not reset")
+	}
+
+	var torques []float64
+	if dictAction, ok := actions[0].(*core.DictAction); ok {
+		torques = make([]float64, 0, numJoints)
+		for _, name := range jointNames {
+			component, ok := dictAction.Component(name)
+			if !ok {
+				return nil, nil, nil, fmt.Errorf("%w: walker action is missing joint %q", core.ErrInvalidAction, name)
+			}
+			torque, err := core.NewGenericAction(component.GetData()).GetFloat64()
+			if err != nil {
+				return nil, nil, nil, fmt.Errorf("%w: failed to extract torque of joint %q: %w", core.ErrInvalidAction, name, err)
+			}
+			torques = append(torques, torque)
+		}
+	} else if genericAction, ok := actions[0].(*core.GenericAction); ok {
+		values, err := genericAction.GetFloat64Slice()
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("%w: failed to extract action values: %w", core.ErrInvalidAction, err)
+		}
+		torques = values
+	} else if walkerAction, ok := actions[0].(*WalkerAction); ok {
+		torques = walkerAction.Torques
+	} else {
+		return nil, nil, nil, fmt.Errorf("%w: unsupported action type: %T", core.ErrInvalidAction, actions[0])
+	}
+	if len(torques) != numJoints {
+		return nil, nil, nil, fmt.Errorf("walker expects %d joint torques, got %d", numJoints, len(torques))
+	}
+
+	ctrlCost := 0.0
+	for i, t := range torques {
+		t = math.Max(-1, math.Min(1, t))
+		ctrlCost += t * t
+		e.body.joints[i].Torque = t * e.cfg.MaxTorque
+	}
+
+	prevX := e.body.hip.Pos.X
+	prevAngle := e.torsoAngle
+	e.body.world.Step(e.cfg.Dt)
+	e.currentStep++
+
+	e.torsoAngle = e.computeTorsoAngle()
+	e.torsoVel = math.Remainder(e.torsoAngle-prevAngle, 2*math.Pi) / e.cfg.Dt
+	forwardVel := (e.body.hip.Pos.X - prevX) / e.cfg.Dt
+
+	e.fallen = math.Abs(e.torsoAngle) > e.cfg.MaxTorsoAngle ||
+		e.body.hip.Pos.Y < 0.5*(thighLength+shinLength) ||
+		e.body.head.OnGround
+
+	e.lastReward = e.cfg.ForwardWeight*forwardVel - e.cfg.CtrlCostWeight*ctrlCost
+	if e.fallen {
+		e.lastReward -= e.cfg.FallPenalty
+	} else {
+		e.lastReward += e.cfg.HealthyReward
+	}
+
+	done := e.fallen || e.currentStep >= e.cfg.MaxSteps
+
+	return e.GetObservations(), []float64{e.lastReward}, []bool{done}, nil
+}
+
+// computeTorsoAngle 返回躯干相对竖直方向的角度，逆时针（向后仰）为正
+func (e *WalkerEnvironment) computeTorsoAngle() float64 {
+	return physics.AngleBetween(physics.V(0, 1), e.body.head.Pos.Sub(e.body.hip.Pos))
+}
+
+// GetObservations 获取当前观察
+func (e *WalkerEnvironment) GetObservations() []core.Observation {
+	if e.body == nil {
+		return []core.Observation{core.NewBaseObservation(make([]float64, numObs), map[string]interface{}{})}
+	}
+
+	data := core.ResetBuffer(&e.obsBuf, numObs)[:0]
+	world := e.body.world
+	hipVel := world.Velocity(e.body.hip)
+	data = append(data, e.torsoAngle, e.torsoVel, hipVel.X, hipVel.Y, e.body.hip.Pos.Y)
+	for _, j := range e.body.joints {
+		data = append(data, j.Angle())
+	}
+	for _, j := range e.body.joints {
+		data = append(data, world.JointVelocity(j))
+	}
+	for _, foot := range e.body.feet {
+		if foot.OnGround {
+			data = append(data, 1)
+		} else {
+			data = append(data, 0)
+		}
+	}
+
+	var metadata map[string]interface{}
+	if e.ObservationMetadataEnabled() {
+		metadata = map[string]interface{}{
+			"distance":  e.body.hip.Pos.X,
+			"fallen":    e.fallen,
+			"step":      e.currentStep,
+			"max_steps": e.cfg.MaxSteps,
+		}
+	}
+
+	observation := e.AcquireObservation(data, metadata)
+	return []core.Observation{observation}
+}
+
+// GetReward 返回最近一步的奖励
+func (e *WalkerEnvironment) GetReward() []float64 {
+	return []float64{e.lastReward}
+}
+
+// Close 关闭环境
+func (e *WalkerEnvironment) Close() error {
+	e.body = nil
+	return e.BaseEnvironment.Close()
+}
+
+// Metadata 返回环境元数据：奖励含前进速度项与摔倒惩罚，物理仿真的速度没有上下界
+func (e *WalkerEnvironment) Metadata() core.EnvMetadata {
+	metadata := core.DefaultEnvMetadata()
+	metadata.MaxEpisodeSteps = e.cfg.MaxSteps
+	return metadata
+}
+
+// GetSpaces 获取步行者场景的动作空间和观察空间定义
+func (e *WalkerEnvironment) GetSpaces() core.SpaceDefinition {
+	low := make([]float64, numJoints)
+	high := make([]float64, numJoints)
+	for i := range low {
+		low[i] = -1
+		high[i] = 1
+	}
+
+	spaces := core.SpaceDefinition{
+		ActionSpace: core.ActionSpace{
+			Type:  core.SpaceTypeBox,
+			Low:   low,
+			High:  high,
+			Shape: []int32{numJoints}, // 左髋、左膝、右髋、右膝
+			Dtype: "float32",
+		},
+		ObservationSpace: core.ObservationSpace{
+			Type:  core.SpaceTypeBox,
+			Shape: []int32{numObs},
+			Dtype: "float32",
+		},
+	}
+	if e.cfg.NamedActions {
+		subspaces := make(map[string]core.ActionSpace, numJoints)
+		for _, name := range jointNames {
+			subspaces[name] = core.ActionSpace{Type: core.SpaceTypeBox, Low: []float64{-1}, High: []float64{1}, Dtype: "float32"}
+		}
+		spaces.ActionSpace = core.ActionSpace{Type: core.SpaceTypeDict, Subspaces: subspaces}
+	}
+	return spaces
+}
+
+// WalkerAction 步行者专用动作
+type WalkerAction struct {
+	Torques []float64 // 左髋、左膝、右髋、右膝的归一化扭矩
+}
+
+// NewWalkerAction 创建新的步行者动作
+func NewWalkerAction(torques []float64) *WalkerAction {
+	return &WalkerAction{Torques: torques}
+}
+
+// GetData 获取动作数据
+func (a *WalkerAction) GetData() interface{} {
+	return a.Torques
+}
+
+// Validate 验证动作
+func (a *WalkerAction) Validate() error {
+	if len(a.Torques) != numJoints {
+		return fmt.Errorf("walker action must have %d torques, got %d", numJoints, len(a.Torques))
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package walker
+
+import (
+	"fmt"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// WalkerScenario 步行者场景实现
+type WalkerScenario struct {
+	name        string
+	description string
+}
+
+// 确保WalkerScenario实现了core.Scenario接口
+var _ core.Scenario = (*WalkerScenario)(nil)
+
+// NewWalkerScenario 创建新的步行者场景
+func NewWalkerScenario() *WalkerScenario {
+	return &WalkerScenario{
+		name:        "walker",
+		description: "2D biped walker on the built-in verlet physics engine - continuous joint torque control",
+	}
+}
+
+// GetName 获取场景名称
+func (s *WalkerScenario) GetName() string {
+	return s.name
+}
+
+// GetDescription 获取场景描述
+func (s *WalkerScenario) GetDescription() string {
+	return s.description
+}
+
+// CreateEnvironment 创建环境实例
+func (s *WalkerScenario) CreateEnvironment(config core.Config) (core.Environment, error) {
+	env, err := NewWalkerEnvironment(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create walker environment: %w", err)
+	}
+	return env, nil
+}
+
+// ValidateConfig 验证配置
+func (s *WalkerScenario) ValidateConfig(config core.Config) error {
+	if config == nil {
+		return fmt.Errorf("config cannot be nil")
+	}
+	_, err := parseConfig(config)
+	return err
+}
@@ -0,0 +1,322 @@
+package wasmenv
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/jelech/rl_env_engine/core"
+	"github.com/jelech/rl_env_engine/core/config"
+	"github.com/jelech/rl_env_engine/core/wasm"
+)
+
+// Config WASM环境配置，动力学全部由模块定义，配置只控制回合长度与随机种子
+type Config struct {
+	MaxSteps int   `cfg:"max_steps,min=0"` // 每回合的最大步数，0表示使用模块的max_steps
+	Seed     int64 `cfg:"seed"`            // 为0时使用当前时间，每次reset传给模块的种子由它派生
+}
+
+// parseConfig 将core.Config绑定为WASM环境配置，max_steps缺省时使用模块声明的值
+func parseConfig(src core.Config, l layout) (Config, error) {
+	var cfg Config
+	if err := config.Bind(src, &cfg); err != nil {
+		return cfg, err
+	}
+	if cfg.MaxSteps == 0 {
+		cfg.MaxSteps = l.maxSteps
+	}
+	return cfg, nil
+}
+
+// WasmEnvironment 在沙箱实例中运行上传模块的环境。模块陷入（越界、除零、燃料耗尽等）时
+// 当前调用返回错误，之后的reset会丢弃该实例并重新实例化
+type WasmEnvironment struct {
+	*core.BaseEnvironment
+	scenario *WasmScenario
+	cfg      Config
+	inst     *wasm.Instance
+	src      *core.RandSource
+	trapped  bool
+
+	currentStep int
+	lastReward  float64
+	totalReward float64
+
+	obsBuf []float64 // GetObservations复用的观察数据缓冲区
+}
+
+// NewWasmEnvironment 为场景创建新的模块实例
+func NewWasmEnvironment(s *WasmScenario, config core.Config) (*WasmEnvironment, error) {
+	cfg, err := parseConfig(config, s.layout)
+	if err != nil {
+		return nil, err
+	}
+	inst, err := s.module.Instantiate(s.limits)
+	if err != nil {
+		return nil, err
+	}
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &WasmEnvironment{
+		BaseEnvironment: core.NewBaseEnvironment(s.name, s.description, config),
+		scenario:        s,
+		cfg:             cfg,
+		inst:            inst,
+		src:             core.NewRandSource(seed),
+	}, nil
+}
+
+// call 调用模块函数，陷入时标记实例需要重新实例化
+func (e *WasmEnvironment) call(name string, args ...uint64) (uint64, error) {
+	if e.trapped {
+		return 0, fmt.Errorf("wasm environment %s trapped earlier, reset required", e.scenario.name)
+	}
+	out, err := e.inst.Call(name, args...)
+	if err != nil {
+		e.trapped = true
+		return 0, fmt.Errorf("%s: %w", name, err)
+	}
+	if len(out) == 0 {
+		return 0, nil
+	}
+	return out[0], nil
+}
+
+// Reset 重置环境：以派生的种子调用模块的reset
+func (e *WasmEnvironment) Reset(ctx context.Context) ([]core.Observation, error) {
+	if e.trapped {
+		inst, err := e.scenario.module.Instantiate(e.scenario.limits)
+		if err != nil {
+			return nil, err
+		}
+		e.inst, e.trapped = inst, false
+	}
+	if _, err := e.call("reset", uint64(e.src.Int63())); err != nil {
+		return nil, err
+	}
+	e.currentStep = 0
+	e.lastReward = 0
+	e.totalReward = 0
+	return e.GetObservations(), nil
+}
+
+// Step 写入动作并调用模块的step与terminated
+func (e *WasmEnvironment) Step(ctx context.Context, actions []core.Action) ([]core.Observation, []float64, []bool, error) {
+	if len(actions) == 0 {
+		return nil, nil, nil, fmt.Errorf("%w: no actions provided", core.ErrInvalidAction)
+	}
+	values, err := e.parseAction(actions[0])
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	buf := e.inst.Memory()[e.scenario.layout.actionPtr:]
+	for i, v := range values {
+		binary.LittleEndian.PutUint64(buf[8*i:], math.Float64bits(v))
+	}
+
+	reward, err := e.call("step")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	terminated, err := e.call("terminated")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	e.currentStep++
+	e.lastReward = math.Float64frombits(reward)
+	e.totalReward += e.lastReward
+
+	observations := e.GetObservations()
+	done := uint32(terminated) != 0 || e.currentStep >= e.cfg.MaxSteps
+	for _, v := range observations[0].GetData() {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			done = true
+			break
+		}
+	}
+	return observations, []float64{e.lastReward}, []bool{done}, nil
+}
+
+// parseAction 将动作解析为写入动作缓冲区的数值，连续动作会裁剪到模块声明的动作界
+func (e *WasmEnvironment) parseAction(action core.Action) ([]float64, error) {
+	var values []float64
+	switch a := action.(type) {
+	case *core.GenericAction:
+		if slice, err := a.GetFloat64Slice(); err == nil {
+			values = slice
+		} else if v, err := a.GetFloat64(); err == nil {
+			values = []float64{v}
+		} else {
+			return nil, fmt.Errorf("%w: failed to extract action value: %w", core.ErrInvalidAction, err)
+		}
+	case *WasmAction:
+		values = a.Values
+	default:
+		return nil, fmt.Errorf("%w: unsupported action type: %T", core.ErrInvalidAction, action)
+	}
+
+	l := e.scenario.layout
+	if l.numActions > 0 {
+		if len(values) != 1 {
+			return nil, fmt.Errorf("%w: discrete action must be a single value, got %d", core.ErrInvalidAction, len(values))
+		}
+		idx := int(values[0])
+		if float64(idx) != values[0] || idx < 0 || idx >= l.numActions {
+			return nil, fmt.Errorf("%w: discrete action must be an integer in [0, %d), got %v", core.ErrInvalidAction, l.numActions, values[0])
+		}
+		return values, nil
+	}
+
+	if len(values) != l.actionDim {
+		return nil, fmt.Errorf("%w: action must have %d dimensions, got %d", core.ErrInvalidAction, l.actionDim, len(values))
+	}
+	clipped := make([]float64, len(values))
+	for i, v := range values {
+		clipped[i] = math.Max(l.actionLow[i], math.Min(l.actionHigh[i], v))
+	}
+	return clipped, nil
+}
+
+// GetObservations 从模块的观察缓冲区读取当前观察
+func (e *WasmEnvironment) GetObservations() []core.Observation {
+	l := e.scenario.layout
+	data := core.ResetBuffer(&e.obsBuf, l.obsDim)
+	buf := e.inst.Memory()[l.obsPtr:]
+	for i := range data {
+		data[i] = math.Float64frombits(binary.LittleEndian.Uint64(buf[8*i:]))
+	}
+
+	var metadata map[string]interface{}
+	if e.ObservationMetadataEnabled() {
+		metadata = map[string]interface{}{
+			"total_reward": e.totalReward,
+			"step":         e.currentStep,
+			"max_steps":    e.cfg.MaxSteps,
+		}
+	}
+	return []core.Observation{e.AcquireObservation(data, metadata)}
+}
+
+// GetReward 返回最近一步的奖励
+func (e *WasmEnvironment) GetReward() []float64 {
+	return []float64{e.lastReward}
+}
+
+// GetInfo 获取环境信息
+func (e *WasmEnvironment) GetInfo() map[string]interface{} {
+	info := e.BaseEnvironment.GetInfo()
+	info["wasm_module_sha256"] = e.scenario.digest
+	info["wasm_memory_bytes"] = len(e.inst.Memory())
+	return info
+}
+
+// Close 关闭环境，释放实例的线性内存
+func (e *WasmEnvironment) Close() error {
+	e.inst = nil
+	return e.BaseEnvironment.Close()
+}
+
+// Metadata 返回环境元数据：奖励由模块的step函数返回，范围无法预知
+func (e *WasmEnvironment) Metadata() core.EnvMetadata {
+	metadata := core.DefaultEnvMetadata()
+	metadata.MaxEpisodeSteps = e.cfg.MaxSteps
+	return metadata
+}
+
+// GetSpaces 获取模块声明的动作空间和观察空间
+func (e *WasmEnvironment) GetSpaces() core.SpaceDefinition {
+	l := e.scenario.layout
+	observationSpace := core.ObservationSpace{
+		Type:  core.SpaceTypeBox,
+		Shape: []int32{int32(l.obsDim)},
+		Dtype: "float32",
+	}
+	if l.numActions > 0 {
+		return core.SpaceDefinition{
+			ActionSpace: core.ActionSpace{
+				Type:  core.SpaceTypeDiscrete,
+				Low:   []float64{0},
+				High:  []float64{float64(l.numActions - 1)},
+				Shape: []int32{},
+				Dtype: "int32",
+			},
+			ObservationSpace: observationSpace,
+		}
+	}
+	return core.SpaceDefinition{
+		ActionSpace: core.ActionSpace{
+			Type:  core.SpaceTypeBox,
+			Low:   append([]float64(nil), l.actionLow...),
+			High:  append([]float64(nil), l.actionHigh...),
+			Shape: []int32{int32(l.actionDim)},
+			Dtype: "float32",
+		},
+		ObservationSpace: observationSpace,
+	}
+}
+
+// checkpoint WASM环境的检查点：实例的线性内存与全局变量及回合进度
+type checkpoint struct {
+	Instance    wasm.State     `json:"instance"`
+	Step        int            `json:"step"`
+	LastReward  float64        `json:"last_reward"`
+	TotalReward float64        `json:"total_reward"`
+	Rand        core.RandState `json:"rand"`
+}
+
+// Checkpoint 实现core.Checkpointer
+func (e *WasmEnvironment) Checkpoint() ([]byte, error) {
+	if e.trapped {
+		return nil, fmt.Errorf("wasm environment %s trapped, reset required", e.scenario.name)
+	}
+	return json.Marshal(checkpoint{
+		Instance:    e.inst.Save(),
+		Step:        e.currentStep,
+		LastReward:  e.lastReward,
+		TotalReward: e.totalReward,
+		Rand:        e.src.State(),
+	})
+}
+
+// RestoreCheckpoint 实现core.Checkpointer
+func (e *WasmEnvironment) RestoreCheckpoint(data []byte) error {
+	var cp checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return fmt.Errorf("invalid wasm checkpoint: %w", err)
+	}
+	if err := e.inst.Restore(cp.Instance); err != nil {
+		return err
+	}
+	e.currentStep, e.lastReward, e.totalReward = cp.Step, cp.LastReward, cp.TotalReward
+	e.src.Restore(cp.Rand)
+	e.trapped = false
+	return nil
+}
+
+// WasmAction WASM环境专用动作
+type WasmAction struct {
+	Values []float64
+}
+
+// NewWasmAction 创建新的WASM环境动作
+func NewWasmAction(values ...float64) *WasmAction {
+	return &WasmAction{Values: values}
+}
+
+// GetData 获取动作数据
+func (a *WasmAction) GetData() interface{} {
+	return a.Values
+}
+
+// Validate 验证动作
+func (a *WasmAction) Validate() error {
+	if len(a.Values) == 0 {
+		return fmt.Errorf("wasm action must not be empty")
+	}
+	return nil
+}
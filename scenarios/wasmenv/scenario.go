@@ -0,0 +1,261 @@
+package wasmenv
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/jelech/rl_env_engine/core"
+	"github.com/jelech/rl_env_engine/core/wasm"
+)
+
+// ABIVersion 环境模块须从rlenv_abi_version返回的ABI版本
+//
+// 模块须导出（所有缓冲区为小端f64数组，模块不能有导入）：
+//
+//	memory                          线性内存
+//	rlenv_abi_version() -> i32      ABIVersion
+//	observation_dim() -> i32        观察维度
+//	action_dim() -> i32             连续动作维度，num_actions大于0时忽略
+//	num_actions() -> i32            离散动作数，大于0时动作为0..num_actions-1
+//	observation_ptr() -> i32        观察缓冲区地址，reset与step向其写入observation_dim个值
+//	action_ptr() -> i32             动作缓冲区地址，step前写入action_dim个值（离散动作为1个）
+//	reset(seed i64)                 开始新回合，写入初始观察
+//	step() -> f64                   读取动作、推进一步并写入观察，返回奖励
+//	terminated() -> i32             非零表示回合已结束
+//
+// 可选导出：
+//
+//	action_low(i i32) -> f64        第i维连续动作的下界，缺省为-1（动作会被裁剪）
+//	action_high(i i32) -> f64       第i维连续动作的上界，缺省为1
+//	max_steps() -> i32              每回合的最大步数，缺省为200，配置中的max_steps优先
+const ABIVersion = 1
+
+// maxDim 观察与动作维度的上限
+const maxDim = 1 << 16
+
+// abi 需要的导出函数及其签名
+var abi = map[string]wasm.FuncType{
+	"rlenv_abi_version": {Results: []byte{wasm.I32}},
+	"observation_dim":   {Results: []byte{wasm.I32}},
+	"action_dim":        {Results: []byte{wasm.I32}},
+	"num_actions":       {Results: []byte{wasm.I32}},
+	"observation_ptr":   {Results: []byte{wasm.I32}},
+	"action_ptr":        {Results: []byte{wasm.I32}},
+	"reset":             {Params: []byte{wasm.I64}},
+	"step":              {Results: []byte{wasm.F64}},
+	"terminated":        {Results: []byte{wasm.I32}},
+}
+
+// optionalABI 可选的导出函数及其签名
+var optionalABI = map[string]wasm.FuncType{
+	"action_low":  {Params: []byte{wasm.I32}, Results: []byte{wasm.F64}},
+	"action_high": {Params: []byte{wasm.I32}, Results: []byte{wasm.F64}},
+	"max_steps":   {Results: []byte{wasm.I32}},
+}
+
+// layout 模块在实例化后报告的维度、缓冲区与动作界
+type layout struct {
+	obsDim     int
+	actionDim  int // 离散动作为1
+	numActions int // 连续动作为0
+	obsPtr     uint32
+	actionPtr  uint32
+	actionLow  []float64
+	actionHigh []float64
+	maxSteps   int
+}
+
+// WasmScenario 由客户端上传的WebAssembly模块定义的场景，模块在沙箱中解释执行，
+// 每个环境拥有独立的实例，资源受限于limits
+type WasmScenario struct {
+	name        string
+	description string
+	source      []byte
+	digest      string // 模块的SHA-256，环境信息据此标识创建它的模块版本
+	module      *wasm.Module
+	limits      wasm.Limits
+	layout      layout
+}
+
+// 确保WasmScenario实现了core.Scenario接口
+var _ core.Scenario = (*WasmScenario)(nil)
+
+// NewWasmScenario 编译并校验模块：导出须符合ABI，并试实例化一次读取维度与缓冲区
+func NewWasmScenario(name, description string, source []byte, limits wasm.Limits) (*WasmScenario, error) {
+	if name == "" || strings.ContainsAny(name, "/: ") {
+		return nil, fmt.Errorf("invalid scenario name %q", name)
+	}
+	module, err := wasm.Compile(source)
+	if err != nil {
+		return nil, err
+	}
+	if !module.ExportsMemory("memory") {
+		return nil, fmt.Errorf("module must export its memory as \"memory\"")
+	}
+	for fn, want := range abi {
+		if err := checkExport(module, fn, want, true); err != nil {
+			return nil, err
+		}
+	}
+	for fn, want := range optionalABI {
+		if err := checkExport(module, fn, want, false); err != nil {
+			return nil, err
+		}
+	}
+
+	sum := sha256.Sum256(source)
+	s := &WasmScenario{
+		name:        name,
+		description: description,
+		source:      append([]byte(nil), source...),
+		digest:      hex.EncodeToString(sum[:]),
+		module:      module,
+		limits:      limits,
+	}
+	inst, err := module.Instantiate(limits)
+	if err != nil {
+		return nil, err
+	}
+	if s.layout, err = readLayout(module, inst); err != nil {
+		return nil, err
+	}
+	if s.description == "" {
+		s.description = "Environment defined by an uploaded WebAssembly module"
+	}
+	return s, nil
+}
+
+// checkExport 检查导出函数的签名，required时还要求函数存在
+func checkExport(module *wasm.Module, name string, want wasm.FuncType, required bool) error {
+	got, ok := module.ExportedFunction(name)
+	if !ok {
+		if required {
+			return fmt.Errorf("module does not export %s %s", name, want)
+		}
+		return nil
+	}
+	if got.String() != want.String() {
+		return fmt.Errorf("export %s has type %s, expected %s", name, got, want)
+	}
+	return nil
+}
+
+// readLayout 调用实例的ABI函数读取维度、缓冲区地址与动作界
+func readLayout(module *wasm.Module, inst *wasm.Instance) (layout, error) {
+	call := func(name string, args ...uint64) (uint64, error) {
+		out, err := inst.Call(name, args...)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", name, err)
+		}
+		return out[0], nil
+	}
+	var l layout
+	values := make(map[string]int)
+	for _, name := range []string{"rlenv_abi_version", "observation_dim", "action_dim", "num_actions", "observation_ptr", "action_ptr"} {
+		v, err := call(name)
+		if err != nil {
+			return l, err
+		}
+		values[name] = int(int32(v))
+	}
+	if v := values["rlenv_abi_version"]; v != ABIVersion {
+		return l, fmt.Errorf("module implements ABI version %d, expected %d", v, ABIVersion)
+	}
+	l.obsDim, l.numActions, l.actionDim = values["observation_dim"], values["num_actions"], values["action_dim"]
+	if l.obsDim <= 0 || l.obsDim > maxDim {
+		return l, fmt.Errorf("observation_dim must be in [1, %d], got %d", maxDim, l.obsDim)
+	}
+	if l.numActions < 0 || l.numActions > maxDim {
+		return l, fmt.Errorf("num_actions must be in [0, %d], got %d", maxDim, l.numActions)
+	}
+	if l.numActions > 0 {
+		l.actionDim = 1
+	} else if l.actionDim <= 0 || l.actionDim > maxDim {
+		return l, fmt.Errorf("action_dim must be in [1, %d], got %d", maxDim, l.actionDim)
+	}
+	l.obsPtr, l.actionPtr = uint32(values["observation_ptr"]), uint32(values["action_ptr"])
+	memory := uint64(len(inst.Memory()))
+	if uint64(l.obsPtr)+8*uint64(l.obsDim) > memory || uint64(l.actionPtr)+8*uint64(l.actionDim) > memory {
+		return l, fmt.Errorf("observation or action buffer lies outside the initial memory")
+	}
+
+	if l.numActions == 0 {
+		l.actionLow = make([]float64, l.actionDim)
+		l.actionHigh = make([]float64, l.actionDim)
+		bound := func(name string, i int, def float64) (float64, error) {
+			if _, ok := module.ExportedFunction(name); !ok {
+				return def, nil
+			}
+			v, err := call(name, uint64(i))
+			return math.Float64frombits(v), err
+		}
+		var err error
+		for i := range l.actionLow {
+			if l.actionLow[i], err = bound("action_low", i, -1); err != nil {
+				return l, err
+			}
+			if l.actionHigh[i], err = bound("action_high", i, 1); err != nil {
+				return l, err
+			}
+		}
+		for i := range l.actionLow {
+			if !(l.actionLow[i] <= l.actionHigh[i]) {
+				return l, fmt.Errorf("action bounds of dimension %d are invalid: [%v, %v]", i, l.actionLow[i], l.actionHigh[i])
+			}
+		}
+	}
+
+	l.maxSteps = 200
+	if _, ok := module.ExportedFunction("max_steps"); ok {
+		v, err := call("max_steps")
+		if err != nil {
+			return l, err
+		}
+		l.maxSteps = int(int32(v))
+		if l.maxSteps <= 0 {
+			return l, fmt.Errorf("max_steps must be positive, got %d", l.maxSteps)
+		}
+	}
+	return l, nil
+}
+
+// GetName 获取场景名称
+func (s *WasmScenario) GetName() string {
+	return s.name
+}
+
+// GetDescription 获取场景描述
+func (s *WasmScenario) GetDescription() string {
+	return s.description
+}
+
+// Source 返回上传的模块，用于快照与迁移时在其他服务端重新注册
+func (s *WasmScenario) Source() []byte {
+	return s.source
+}
+
+// Limits 返回模块实例的资源上限
+func (s *WasmScenario) Limits() wasm.Limits {
+	return s.limits
+}
+
+// CreateEnvironment 创建环境实例
+func (s *WasmScenario) CreateEnvironment(config core.Config) (core.Environment, error) {
+	env, err := NewWasmEnvironment(s, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create wasm environment: %w", err)
+	}
+	return env, nil
+}
+
+// ValidateConfig 验证配置
+func (s *WasmScenario) ValidateConfig(config core.Config) error {
+	if config == nil {
+		return fmt.Errorf("config cannot be nil")
+	}
+	_, err := parseConfig(config, s.layout)
+	return err
+}
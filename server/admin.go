@@ -0,0 +1,144 @@
+package server
+
+import (
+	"crypto/subtle"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// AdminTokenMetadataKey gRPC请求元数据中携带管理令牌的键
+const AdminTokenMetadataKey = "admin-token"
+
+// AdminTokenHeader HTTP请求中携带管理令牌的头
+const AdminTokenHeader = "X-Admin-Token"
+
+// errAdminToken 管理令牌缺失或不匹配
+var errAdminToken = errors.New("invalid admin token")
+
+// errNoAdminToken 服务端未设置管理令牌，需要令牌的操作一律拒绝
+var errNoAdminToken = errors.New("this operation requires an admin token configured on the server")
+
+// EnvStatus 管理接口中一个环境的状态
+type EnvStatus struct {
+	EnvID       string  `json:"env_id"`     // 注册表中的完整键，会话内的环境为session_id/env_id
+	Scenario    string  `json:"scenario"`   // 创建该环境的场景
+	SessionID   string  `json:"session_id"` // 所属会话，不属于会话时为空
+	Client      string  `json:"client"`     // 创建该环境的客户端主机
+	AgeSeconds  float64 `json:"age_seconds"`
+	IdleSeconds float64 `json:"idle_seconds"` // 最近一次reset或step至今的秒数
+	Steps       int64   `json:"steps"`
+	Episodes    int64   `json:"episodes"`         // 累计reset次数
+	Tenant      string  `json:"tenant,omitempty"` // 所属租户，未配置租户时为空
+	Fault       string  `json:"fault,omitempty"`  // 环境panic后的故障原因，此后只能关闭；正常时为空
+}
+
+// AdminEnvsResponse 管理接口的环境列表响应
+type AdminEnvsResponse struct {
+	Environments []EnvStatus `json:"environments"`
+	Draining     bool        `json:"draining"`
+}
+
+// DrainRequest 排空请求：停止创建新环境，最多等待TimeoutSeconds让客户端关闭已有环境，Force时随后强制关闭剩余环境
+type DrainRequest struct {
+	TimeoutSeconds float64 `json:"timeout_seconds"`
+	Force          bool    `json:"force"`
+}
+
+// DrainResponse 排空响应
+type DrainResponse struct {
+	RemainingEnvironments int `json:"remaining_environments"`
+	ClosedEnvironments    int `json:"closed_environments"`
+}
+
+// checkAdminToken 校验管理令牌，服务端未设置令牌时不校验
+func checkAdminToken(expected, got string) error {
+	if expected == "" {
+		return nil
+	}
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(got)) != 1 {
+		return errAdminToken
+	}
+	return nil
+}
+
+// requireAdminToken 与checkAdminToken相同，但服务端未设置令牌时返回errNoAdminToken，用于会在服务端写文件或执行上传代码的操作
+func requireAdminToken(expected, got string) error {
+	if expected == "" {
+		return errNoAdminToken
+	}
+	return checkAdminToken(expected, got)
+}
+
+// envStatuses 按env_id顺序返回注册表中所有环境的状态
+func envStatuses(registry *EnvRegistry, sessions *SessionManager) []EnvStatus {
+	now := time.Now()
+	statuses := make([]EnvStatus, 0, registry.Len())
+	registry.each(func(key string, entry *envEntry) {
+		item := EnvStatus{
+			EnvID:       key,
+			Scenario:    entry.scenario,
+			Client:      entry.client,
+			AgeSeconds:  now.Sub(entry.stats.created).Seconds(),
+			IdleSeconds: now.Sub(time.Unix(0, entry.stats.lastUsed.Load())).Seconds(),
+			Steps:       entry.stats.steps.Load(),
+			Episodes:    entry.stats.episodes.Load(),
+			Tenant:      entry.tenant,
+		}
+		if err := core.FaultOf(entry.env); err != nil {
+			item.Fault = err.Error()
+		}
+		if sessions.owned(key) {
+			item.SessionID = key[:strings.IndexByte(key, '/')]
+		}
+		statuses = append(statuses, item)
+	})
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].EnvID < statuses[j].EnvID })
+	return statuses
+}
+
+// forceClose 关闭注册表中键为key的环境并将其移出所属会话，环境不存在时返回false
+func forceClose(registry *EnvRegistry, sessions *SessionManager, key string, t telemetry) bool {
+	env, ok := registry.Remove(key)
+	if !ok {
+		return false
+	}
+	if i := strings.IndexByte(key, '/'); i > 0 {
+		if sess, ok := sessions.lookup(key[:i]); ok {
+			sess.untrack(key)
+		}
+	}
+	env.Close()
+	t.reportClosed(key)
+	return true
+}
+
+// dumpState 在两步之间导出键为key的环境的内部状态，环境不存在时返回false
+func dumpState(registry *EnvRegistry, key string) (map[string]interface{}, bool) {
+	entry, ok := registry.entry(key)
+	if !ok {
+		return nil, false
+	}
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	return core.DumpState(entry.env), true
+}
+
+// drain 等待注册表中的环境在timeout内被关闭，force时强制关闭剩余环境；返回剩余与强制关闭的环境数
+func drain(registry *EnvRegistry, sessions *SessionManager, t telemetry, timeout time.Duration, force bool) (remaining, closed int) {
+	deadline := time.Now().Add(timeout)
+	for registry.Len() > 0 && time.Now().Before(deadline) {
+		time.Sleep(100 * time.Millisecond)
+	}
+	if force {
+		for _, key := range registry.IDs() {
+			if forceClose(registry, sessions, key, t) {
+				closed++
+			}
+		}
+	}
+	return registry.Len(), closed
+}
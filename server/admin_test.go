@@ -0,0 +1,35 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jelech/rl_env_engine/core/wasm"
+)
+
+func TestRegisterScenarioRequiresAdminToken(t *testing.T) {
+	tests := []struct {
+		serverToken, token string
+		want               int
+	}{
+		{"", "", http.StatusPreconditionFailed},
+		{"", "anything", http.StatusPreconditionFailed},
+		{"secret", "", http.StatusUnauthorized},
+		{"secret", "wrong", http.StatusUnauthorized},
+		{"secret", "secret", http.StatusBadRequest}, // 令牌通过后因模块无效被拒绝
+	}
+	for _, tt := range tests {
+		api := NewGymAPI()
+		api.SetAdminToken(tt.serverToken)
+		api.EnableWasmScenarios(wasm.Limits{})
+		req := httptest.NewRequest(http.MethodPost, "/scenario/register", strings.NewReader(`{"name":"uploaded","wasm":""}`))
+		req.Header.Set(AdminTokenHeader, tt.token)
+		rec := httptest.NewRecorder()
+		api.Handler().ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("server token %q, token %q: %d %s, want %d", tt.serverToken, tt.token, rec.Code, rec.Body, tt.want)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package server
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"strings"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// EnvDebugDump 环境的内部状态（GET /debug/env/{id}与gRPC DebugEnvironment），供场景作者排查导致异常奖励或结束标志的状态
+type EnvDebugDump struct {
+	EnvID    string   `json:"env_id"`
+	Scenario string   `json:"scenario"`
+	Steps    int64    `json:"steps"`
+	Episodes int64    `json:"episodes"` // 累计reset次数
+	Wrappers []string `json:"wrappers"` // 从外到内各包装层的类型，最后一个为场景创建的环境
+	// Checkpoint 环境实现core.Checkpointer且检查点为JSON时的完整内部状态（含随机数生成器状态）
+	Checkpoint json.RawMessage `json:"checkpoint,omitempty"`
+	// CheckpointError 未实现core.Checkpointer或检查点失败、不是JSON时的原因
+	CheckpointError string                 `json:"checkpoint_error,omitempty"`
+	State           map[string]interface{} `json:"state"`           // core.DumpState的结果
+	Fault           string                 `json:"fault,omitempty"` // 环境panic后的故障原因
+}
+
+// debugDump 导出注册表中key的环境的内部状态，envID为调用方使用的env_id
+func debugDump(registry *EnvRegistry, key, envID string) (EnvDebugDump, bool) {
+	entry, ok := registry.entry(key)
+	if !ok {
+		return EnvDebugDump{}, false
+	}
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	dump := EnvDebugDump{
+		EnvID:    envID,
+		Scenario: entry.scenario,
+		Steps:    entry.stats.steps.Load(),
+		Episodes: entry.stats.episodes.Load(),
+		State:    core.DumpState(entry.env),
+	}
+	for env := entry.env; env != nil; {
+		dump.Wrappers = append(dump.Wrappers, fmt.Sprintf("%T", env))
+		wrapper, ok := env.(core.Unwrapper)
+		if !ok {
+			break
+		}
+		env = wrapper.Unwrap()
+	}
+	checkpoint, err := core.Checkpoint(entry.env)
+	switch {
+	case err != nil:
+		dump.CheckpointError = err.Error()
+	case !json.Valid(checkpoint):
+		dump.CheckpointError = "checkpoint is not JSON"
+	default:
+		dump.Checkpoint = checkpoint
+	}
+	if err := core.FaultOf(entry.env); err != nil {
+		dump.Fault = err.Error()
+	}
+	return dump, true
+}
+
+// DebugHandler 返回运行时调试接口：/debug/pprof/下的net/http/pprof性能剖析与/debug/vars下的expvar变量。
+// 设置了管理令牌时请求需在X-Admin-Token头中携带
+func (api *GymAPI) DebugHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !api.checkAdmin(w, r) {
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// ServeDebug 在lis上提供DebugHandler，应监听在与API不同、仅运维可达的端口上；不使用TLS
+func (api *GymAPI) ServeDebug(lis net.Listener) error {
+	api.telemetry.log().Info("Serving debug endpoints", "addr", lis.Addr().String(), "routes", "/debug/pprof/, /debug/vars")
+	return http.Serve(lis, api.DebugHandler())
+}
+
+// handleDebugEnv 返回调用方的环境/debug/env/{id}的内部状态，按会话与租户解析env_id
+func (api *GymAPI) handleDebugEnv(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	envID := strings.TrimPrefix(r.URL.Path, "/debug/env/")
+	if envID == "" {
+		api.writeError(w, "Missing env_id in path", http.StatusBadRequest)
+		return
+	}
+	key, _, ok := api.envKey(w, r, envID)
+	if !ok {
+		return
+	}
+	dump, exists := debugDump(api.environments, key, envID)
+	if !exists {
+		api.writeEnvNotFound(w, envID)
+		return
+	}
+	api.writeJSON(w, dump)
+}
@@ -0,0 +1,175 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/jelech/rl_env_engine/core"
+	pb "github.com/jelech/rl_env_engine/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// APIError 两种传输共用的错误模型，对应protobuf的Error：Code为规范错误码（与gRPC状态码同名，如NOT_FOUND），
+// Message为可读描述，Details为附加的键值（如env_id）。HTTP的CreateEnvResponse等在失败时以error字段返回它
+type APIError struct {
+	Code    string            `json:"code"`
+	Message string            `json:"message"`
+	Details map[string]string `json:"details,omitempty"`
+}
+
+// errorCodes gRPC状态码的规范名称，与google.rpc.Code一致
+var errorCodes = map[codes.Code]string{
+	codes.OK:                 "OK",
+	codes.Canceled:           "CANCELLED",
+	codes.Unknown:            "UNKNOWN",
+	codes.InvalidArgument:    "INVALID_ARGUMENT",
+	codes.DeadlineExceeded:   "DEADLINE_EXCEEDED",
+	codes.NotFound:           "NOT_FOUND",
+	codes.AlreadyExists:      "ALREADY_EXISTS",
+	codes.PermissionDenied:   "PERMISSION_DENIED",
+	codes.ResourceExhausted:  "RESOURCE_EXHAUSTED",
+	codes.FailedPrecondition: "FAILED_PRECONDITION",
+	codes.Aborted:            "ABORTED",
+	codes.OutOfRange:         "OUT_OF_RANGE",
+	codes.Unimplemented:      "UNIMPLEMENTED",
+	codes.Internal:           "INTERNAL",
+	codes.Unavailable:        "UNAVAILABLE",
+	codes.DataLoss:           "DATA_LOSS",
+	codes.Unauthenticated:    "UNAUTHENTICATED",
+}
+
+// errorCode 返回gRPC状态码的规范名称，如codes.NotFound为NOT_FOUND
+func errorCode(c codes.Code) string {
+	if name, ok := errorCodes[c]; ok {
+		return name
+	}
+	return errorCodes[codes.Unknown]
+}
+
+// httpErrorCodes HTTP状态码对应的gRPC状态码，未列出的4xx为InvalidArgument、5xx为Internal
+var httpErrorCodes = map[int]codes.Code{
+	http.StatusBadRequest:            codes.InvalidArgument,
+	http.StatusUnauthorized:          codes.Unauthenticated,
+	http.StatusForbidden:             codes.PermissionDenied,
+	http.StatusNotFound:              codes.NotFound,
+	http.StatusMethodNotAllowed:      codes.Unimplemented,
+	http.StatusConflict:              codes.AlreadyExists,
+	http.StatusPreconditionFailed:    codes.FailedPrecondition,
+	http.StatusRequestEntityTooLarge: codes.ResourceExhausted,
+	http.StatusTooManyRequests:       codes.ResourceExhausted,
+	http.StatusNotImplemented:        codes.Unimplemented,
+	http.StatusServiceUnavailable:    codes.Unavailable,
+	http.StatusGatewayTimeout:        codes.DeadlineExceeded,
+}
+
+// httpErrorCode 返回HTTP状态码对应的gRPC状态码
+func httpErrorCode(httpStatus int) codes.Code {
+	if c, ok := httpErrorCodes[httpStatus]; ok {
+		return c
+	}
+	if httpStatus < http.StatusInternalServerError {
+		return codes.InvalidArgument
+	}
+	return codes.Internal
+}
+
+// protoError 构造protobuf的Error
+func protoError(c codes.Code, message string, details map[string]string) *pb.Error {
+	return &pb.Error{Code: errorCode(c), Message: message, Details: details}
+}
+
+// createFailure 创建环境失败的响应，error与message一致
+func createFailure(c codes.Code, envID, message string) *pb.CreateEnvironmentResponse {
+	return &pb.CreateEnvironmentResponse{
+		Success: false,
+		Message: message,
+		Error:   protoError(c, message, map[string]string{"env_id": envID}),
+	}
+}
+
+// createEnvFailure HTTP创建环境失败的响应，error与message一致
+func createEnvFailure(c codes.Code, envID, message string) CreateEnvResponse {
+	return CreateEnvResponse{
+		Success: false,
+		Message: message,
+		Error:   &APIError{Code: errorCode(c), Message: message, Details: map[string]string{"env_id": envID}},
+	}
+}
+
+// newError 返回状态详情中携带Error的gRPC错误，details为附加的键值
+func newError(c codes.Code, details map[string]string, format string, args ...interface{}) error {
+	message := fmt.Sprintf(format, args...)
+	st, err := status.New(c, message).WithDetails(protoError(c, message, details))
+	if err != nil {
+		return status.Error(c, message)
+	}
+	return st.Err()
+}
+
+// errEnvNotFound 环境不存在的错误
+func errEnvNotFound(envID string) error {
+	return newError(codes.NotFound, map[string]string{"env_id": envID}, "environment %s not found", envID)
+}
+
+// errSessionNotFound 会话不存在的错误
+func errSessionNotFound(sessionID string) error {
+	return newError(codes.NotFound, map[string]string{"session_id": sessionID}, "session %s not found", sessionID)
+}
+
+// envErrorStatus 环境Reset/Step返回的错误对应的gRPC与HTTP状态码：动作或参数不被环境接受是调用方的错误，
+// 为InvalidArgument（400）；超时为DeadlineExceeded（504）；其余为环境自身的故障，为Internal（500）
+func envErrorStatus(err error) (codes.Code, int) {
+	switch {
+	case errors.Is(err, core.ErrInvalidAction), errors.Is(err, core.ErrInvalidParameter), errors.Is(err, core.ErrConfigInvalid):
+		return codes.InvalidArgument, http.StatusBadRequest
+	case errors.Is(err, context.DeadlineExceeded):
+		return codes.DeadlineExceeded, http.StatusGatewayTimeout
+	}
+	return codes.Internal, http.StatusInternalServerError
+}
+
+// errEnvFailed 环境Reset/Step失败的错误，op为reset或step，状态码由envErrorStatus得出
+func errEnvFailed(envID, op string, err error) error {
+	c, _ := envErrorStatus(err)
+	return newError(c, map[string]string{"env_id": envID}, "failed to %s environment: %v", op, err)
+}
+
+// withErrorDetails 为未携带Error详情的错误补上Error：普通错误为UNKNOWN，context的取消与超时为CANCELLED与DEADLINE_EXCEEDED
+func withErrorDetails(err error) error {
+	if err == nil {
+		return nil
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			st = status.FromContextError(err)
+		} else {
+			st = status.New(codes.Unknown, err.Error())
+		}
+	}
+	for _, detail := range st.Details() {
+		if _, ok := detail.(*pb.Error); ok {
+			return err
+		}
+	}
+	withDetails, detailsErr := st.WithDetails(protoError(st.Code(), st.Message(), nil))
+	if detailsErr != nil {
+		return st.Err()
+	}
+	return withDetails.Err()
+}
+
+// errorUnary 使一元调用返回的错误都在状态详情中携带Error
+func errorUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	return resp, withErrorDetails(err)
+}
+
+// errorStream 使流调用返回的错误都在状态详情中携带Error
+func errorStream(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return withErrorDetails(handler(srv, stream))
+}
@@ -2,125 +2,444 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"math/rand"
 	"net"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/jelech/rl_env_engine/core"
+	"github.com/jelech/rl_env_engine/core/curriculum"
+	"github.com/jelech/rl_env_engine/core/metrics"
+	"github.com/jelech/rl_env_engine/core/policy"
+	"github.com/jelech/rl_env_engine/core/runstore"
+	"github.com/jelech/rl_env_engine/core/wasm"
 	pb "github.com/jelech/rl_env_engine/proto"
-	"github.com/jelech/rl_env_engine/scenarios/cartpole"
-	"github.com/jelech/rl_env_engine/scenarios/simple"
+	"github.com/jelech/rl_env_engine/scenarios"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/reflection"
-	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/grpc/status"
 )
 
+// MaxMessageSize 是gRPC收发消息的大小上限，图像观察（HxWx3）会远超默认的4MB
+const MaxMessageSize = 64 << 20
+
 // GrpcServer implements the gRPC simulation service
 type GrpcServer struct {
 	pb.UnimplementedSimulationServiceServer
 	engine       *core.SimulationEngine
-	environments map[string]core.Environment
-	configs      map[string]core.Config
+	environments *EnvRegistry
+	sessions     *SessionManager
+	limits       Limits
+	telemetry    telemetry
+	gymnasium    bool
+	adminToken   string
+	draining     atomic.Bool
+	tenants      *tenantSet
+	scenarios    []string // 启用的场景与预设，为空时不限制
+	wasmLimits   *wasm.Limits
+	tlsConfig    *tls.Config
+	latency      *metrics.StepLatency
+
+	serveMu sync.Mutex
+	servers []*grpc.Server // started by Serve and stopped by Shutdown
+	stopped bool           // Shutdown was called, later calls to Serve return at once
 }
 
 // NewGrpcServer creates a new gRPC server instance
 func NewGrpcServer() *GrpcServer {
-	engine := core.NewSimulationEngine()
-
-	// 注册简单测试场景
-	simpleScenario := simple.NewSimpleScenario()
-	engine.RegisterScenario(simpleScenario)
-	engine.RegisterScenario(cartpole.NewCartPoleScenario())
-
 	return &GrpcServer{
-		engine:       engine,
-		environments: make(map[string]core.Environment),
-		configs:      make(map[string]core.Config),
+		engine:       newBuiltinEngine(),
+		environments: NewEnvRegistry(),
+		sessions:     NewSessionManager(),
+		latency:      metrics.NewStepLatency(),
 	}
 }
 
+// newBuiltinEngine 创建注册了全部内置场景的引擎
+func newBuiltinEngine() *core.SimulationEngine {
+	engine := core.NewSimulationEngine()
+	scenarios.RegisterAll(engine)
+	return engine
+}
+
 func (s *GrpcServer) ResetEngine(engine *core.SimulationEngine) {
 	s.engine = engine
 }
 
+// SetMetricsSink publishes episode metrics and environment lifecycle events of
+// environments created afterwards to sink; nil disables publishing
+func (s *GrpcServer) SetMetricsSink(sink core.MetricsSink) {
+	s.telemetry.metrics = sink
+}
+
+// SetRunStore records runs and episodes of environments created afterwards into
+// store; nil disables persistence
+func (s *GrpcServer) SetRunStore(store *runstore.Store) {
+	s.telemetry.runs = store
+}
+
+// SetOutputDir sets the server directory that client output paths (record_path,
+// video_dir and tensorboard_dir) are resolved under; empty rejects them
+func (s *GrpcServer) SetOutputDir(dir string) {
+	s.telemetry.outputDir = dir
+}
+
+// SetLogger sends the server's log output to logger; nil uses core.DefaultLogger()
+func (s *GrpcServer) SetLogger(logger core.Logger) {
+	s.telemetry.logger = logger
+}
+
+// StepLatency returns the per-scenario step latency and throughput reported by GetInfo
+func (s *GrpcServer) StepLatency() *metrics.StepLatency {
+	return s.latency
+}
+
+// SetGymnasiumAPI sets whether step responses of environments created afterwards carry
+// terminated and truncated (Gymnasium >= 0.26 semantics) unless their config sets gymnasium_api
+func (s *GrpcServer) SetGymnasiumAPI(enabled bool) {
+	s.gymnasium = enabled
+}
+
+// SetLimits sets the resource limits checked when creating environments; a create request
+// exceeding them fails with codes.ResourceExhausted
+func (s *GrpcServer) SetLimits(limits Limits) {
+	s.limits = limits
+}
+
+// SetTenants splits the server between named tenants: every request must then carry one
+// of its tenant's API keys in the api-key metadata, sees only that tenant's environments
+// and sessions, and may only create the tenant's allowed scenarios within its limits.
+// An empty list serves a single anonymous namespace
+func (s *GrpcServer) SetTenants(tenants []Tenant) error {
+	set, err := newTenantSet(tenants)
+	if err != nil {
+		return err
+	}
+	s.tenants = set
+	return nil
+}
+
+// SetScenarios restricts the scenarios and presets clients may create, and GetInfo lists,
+// to names; tenants can narrow them further. An empty list enables all of them
+func (s *GrpcServer) SetScenarios(names []string) {
+	s.scenarios = names
+}
+
+// SetSessionTTL sets the idle timeout of sessions opened without one (DefaultSessionTTL
+// when def <= 0) and caps the timeout a client may request (no cap when max <= 0)
+func (s *GrpcServer) SetSessionTTL(def, max time.Duration) {
+	s.sessions.SetTTL(def, max)
+}
+
+// SetTLS serves the service over TLS with config (see LoadTLSConfig); nil serves plaintext.
+// It must be called before Serve
+func (s *GrpcServer) SetTLS(config *tls.Config) {
+	s.tlsConfig = config
+}
+
+// EnableWasmScenarios lets clients upload WebAssembly modules implementing the wasmenv
+// environment ABI through RegisterScenario; each environment of an uploaded scenario runs
+// in its own sandboxed instance bounded by limits (zero fields take wasm.DefaultLimits).
+// Uploads require the admin token and are refused while none is set
+func (s *GrpcServer) EnableWasmScenarios(limits wasm.Limits) {
+	s.wasmLimits = &limits
+}
+
+// SetAdminToken requires the admin RPCs to carry token in the admin-token metadata;
+// an empty token leaves them open to every client
+func (s *GrpcServer) SetAdminToken(token string) {
+	s.adminToken = token
+}
+
+// DrainEnvironments stops creating environments, waits up to timeout for clients to close
+// the existing ones and, if force is set, closes the rest. It returns the number of
+// environments still open and the number force-closed
+func (s *GrpcServer) DrainEnvironments(timeout time.Duration, force bool) (remaining, closed int) {
+	s.draining.Store(true)
+	return drain(s.environments, s.sessions, s.telemetry, timeout, force)
+}
+
+// Shutdown stops accepting connections and waits for pending RPCs, streams included, to
+// finish; when ctx ends first the remaining ones are cancelled and ctx's error returned.
+// Serve then returns nil. The environments stay open, see CloseEnvironments
+func (s *GrpcServer) Shutdown(ctx context.Context) error {
+	s.serveMu.Lock()
+	s.stopped = true
+	servers := s.servers
+	s.serveMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for _, srv := range servers {
+			srv.GracefulStop()
+		}
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		for _, srv := range servers {
+			srv.Stop()
+		}
+		<-done
+		return ctx.Err()
+	}
+}
+
+// CloseEnvironments stops creating environments and closes all the active ones at once.
+// It returns the number of environments closed
+func (s *GrpcServer) CloseEnvironments() int {
+	_, closed := s.DrainEnvironments(0, true)
+	return closed
+}
+
+// SaveSnapshot writes the environments implementing core.Checkpointer, and the sessions
+// owning them, to path. It returns the number of environments saved
+func (s *GrpcServer) SaveSnapshot(path string) (int, error) {
+	snap := takeSnapshot(s.engine, s.environments, s.sessions, s.telemetry.log())
+	return len(snap.Environments), writeSnapshot(path, snap)
+}
+
+// RestoreSnapshot recreates the environments saved at path with their state, typically
+// on startup. A missing file restores nothing; environments that fail to restore are
+// logged and skipped. It returns the number of environments restored
+func (s *GrpcServer) RestoreSnapshot(path string) (int, error) {
+	snap, err := readSnapshot(path)
+	if err != nil || snap == nil {
+		return 0, err
+	}
+	return restoreSnapshot(s.engine, s.wasmLimits, s.environments, s.sessions, s.telemetry, s.gymnasium, snap), nil
+}
+
+// StartSnapshots saves a snapshot to path every interval (DefaultSnapshotInterval when
+// zero). The returned function stops the saves after writing a final snapshot
+func (s *GrpcServer) StartSnapshots(path string, interval time.Duration) (stop func()) {
+	return watchSnapshots(func() error {
+		_, err := s.SaveSnapshot(path)
+		return err
+	}, interval, s.telemetry.log())
+}
+
+// Registry returns the registry holding the active environments
+func (s *GrpcServer) Registry() *EnvRegistry {
+	return s.environments
+}
+
+// SetRegistry replaces the registry holding the active environments; sharing one with a
+// GymAPI lets both protocols operate on the same environments. Call it before creating any
+func (s *GrpcServer) SetRegistry(registry *EnvRegistry) {
+	s.environments = registry
+}
+
+// Sessions returns the table of open client sessions
+func (s *GrpcServer) Sessions() *SessionManager {
+	return s.sessions
+}
+
+// SetSessions replaces the table of open client sessions; share it together with the
+// registry so a session opened over one protocol scopes requests over the other
+func (s *GrpcServer) SetSessions(sessions *SessionManager) {
+	s.sessions = sessions
+}
+
+// Engine returns the simulation engine holding the registered scenarios
+func (s *GrpcServer) Engine() *core.SimulationEngine {
+	return s.engine
+}
+
 // StartGrpcServer starts the gRPC server on the specified port
 func (s *GrpcServer) StartGrpcServer(port int) error {
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
 	if err != nil {
 		return fmt.Errorf("failed to listen: %v", err)
 	}
+	s.telemetry.log().Info("Starting gRPC Simulation server", "port", port)
+	return s.Serve(lis)
+}
 
-	grpcServer := grpc.NewServer()
+// Serve serves the gRPC service on lis, e.g. a Unix socket listener from Listen
+func (s *GrpcServer) Serve(lis net.Listener) error {
+	options := []grpc.ServerOption{
+		grpc.MaxRecvMsgSize(MaxMessageSize),
+		grpc.MaxSendMsgSize(MaxMessageSize),
+		grpc.StatsHandler(sessionConnHandler{sessions: s.sessions}),
+		grpc.ChainUnaryInterceptor(traceUnary, errorUnary, s.recoverUnary),
+		grpc.ChainStreamInterceptor(traceStream, errorStream, s.recoverStream),
+	}
+	if s.tlsConfig != nil {
+		options = append(options, grpc.Creds(credentials.NewTLS(s.tlsConfig)))
+	}
+	grpcServer := grpc.NewServer(options...)
 	pb.RegisterSimulationServiceServer(grpcServer, s)
 
 	// Enable reflection for debugging
 	reflection.Register(grpcServer)
 
-	log.Printf("Starting gRPC Simulation server on port %d", port)
-	log.Printf("gRPC endpoints available:")
-	log.Printf("  GetInfo - Get service information")
-	log.Printf("  CreateEnvironment - Create a new environment")
-	log.Printf("  ResetEnvironment - Reset an environment")
-	log.Printf("  StepEnvironment - Execute one simulation step")
-	log.Printf("  CloseEnvironment - Close an environment")
-	log.Printf("  StreamStep - Stream simulation steps")
+	log := s.telemetry.log()
+	log.Info("gRPC endpoints available", "addr", lis.Addr().String())
+	log.Info("gRPC endpoint", "rpc", "GetInfo", "description", "Get service information")
+	log.Info("gRPC endpoint", "rpc", "CreateEnvironment", "description", "Create a new environment")
+	log.Info("gRPC endpoint", "rpc", "ResetEnvironment", "description", "Reset an environment")
+	log.Info("gRPC endpoint", "rpc", "StepEnvironment", "description", "Execute one simulation step")
+	log.Info("gRPC endpoint", "rpc", "CloseEnvironment", "description", "Close an environment")
+	log.Info("gRPC endpoint", "rpc", "GetMetadata", "description", "Get reward range, max steps and render modes of an environment")
+	log.Info("gRPC endpoint", "rpc", "EvaluatePolicy", "description", "Roll out an ONNX policy on the server")
+	log.Info("gRPC endpoint", "rpc", "GetCurriculum / SetCurriculumStage", "description", "Inspect or control the curriculum of an environment")
+	log.Info("gRPC endpoint", "rpc", "OpenSession", "description", "Open a session scoping the environments of a client")
+	log.Info("gRPC endpoint", "rpc", "CloseSession", "description", "Close a session and all of its environments")
+	log.Info("gRPC endpoint", "rpc", "ListEnvironments / ForceCloseEnvironment / DumpEnvironmentState / Drain", "description", "Admin operations")
+	log.Info("gRPC endpoint", "rpc", "StreamStep", "description", "Stream simulation steps")
 
+	s.serveMu.Lock()
+	if s.stopped {
+		s.serveMu.Unlock()
+		return lis.Close()
+	}
+	s.servers = append(s.servers, grpcServer)
+	s.serveMu.Unlock()
 	return grpcServer.Serve(lis)
 }
 
 // GetInfo returns information about the simulation service
 func (s *GrpcServer) GetInfo(ctx context.Context, req *pb.GetInfoRequest) (*pb.GetInfoResponse, error) {
-	scenarios := s.engine.ListScenarios()
-	envIDs := make([]string, 0, len(s.environments))
-	for envID := range s.environments {
-		envIDs = append(envIDs, envID)
+	tenant, err := s.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	scenarios := creatable(s.scenarios, tenant, s.engine.ListScenarios())
+	_, sess, err := s.sessions.scope(tenant.namespace(), sessionIDFromContext(ctx), "")
+	if err != nil {
+		return nil, err
+	}
+	envIDs := s.sessions.visibleIDs(s.environments, sess, tenant.namespace())
+
+	presetList := make([]interface{}, 0)
+	for _, preset := range s.engine.Presets() {
+		if !canCreate(s.scenarios, tenant, preset.Name) {
+			continue
+		}
+		presetList = append(presetList, map[string]interface{}{
+			"name":     preset.Name,
+			"scenario": preset.Scenario,
+			"config":   preset.Config,
+		})
 	}
 
 	info := map[string]interface{}{
-		"total_scenarios":     fmt.Sprintf("%d", len(scenarios)),
-		"active_environments": fmt.Sprintf("%d", len(envIDs)),
+		"total_scenarios":     len(scenarios),
+		"active_environments": len(envIDs),
 		"server_type":         "gRPC",
+		"presets":             presetList,
+	}
+	if tenant != nil {
+		info["tenant"] = tenant.Name
 	}
 
-	infoStruct, err := structpb.NewStruct(info)
+	infoStruct, err := protoStruct(info)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create info struct: %v", err)
 	}
 
 	return &pb.GetInfoResponse{
-		Scenarios: scenarios,
-		EnvIds:    envIDs,
-		Info:      infoStruct,
-		Version:   "1.0.0",
-		Name:      "Simulation gRPC Service",
+		Scenarios:     scenarios,
+		EnvIds:        envIDs,
+		Info:          infoStruct,
+		TypedInfo:     typedScalars(info),
+		Version:       "1.0.0",
+		Name:          "Simulation gRPC Service",
+		ApiVersion:    APIVersion,
+		MinApiVersion: MinAPIVersion,
+		StepLatency:   protoStepLatency(s.latency.Summaries(func(scenario string) bool { return canCreate(s.scenarios, tenant, scenario) })),
 	}, nil
 }
 
 // CreateEnvironment creates a new simulation environment
 func (s *GrpcServer) CreateEnvironment(ctx context.Context, req *pb.CreateEnvironmentRequest) (*pb.CreateEnvironmentResponse, error) {
+	tenant, err := s.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCreate(s.scenarios, tenant, req.Scenario); err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	key, sess, err := s.sessions.scope(tenant.namespace(), sessionIDFromContext(ctx), req.EnvId)
+	if err != nil {
+		return nil, err
+	}
+
 	// 检查环境是否已存在
-	if _, exists := s.environments[req.EnvId]; exists {
-		return &pb.CreateEnvironmentResponse{
-			Success: false,
-			Message: fmt.Sprintf("Environment %s already exists", req.EnvId),
-		}, nil
+	if _, exists := s.environments.Get(key); exists {
+		return createFailure(codes.AlreadyExists, req.EnvId, fmt.Sprintf("Environment %s already exists", req.EnvId)), nil
 	}
 
-	// 创建配置
-	config := core.NewBaseConfig(req.Config.AsMap())
+	if s.draining.Load() {
+		return nil, status.Error(codes.Unavailable, "server is draining")
+	}
 
-	// 创建环境
-	env, err := s.engine.CreateEnvironment(req.Scenario, config)
+	// 检查资源上限
+	client := grpcClient(ctx)
+	if err := s.limits.checkCounts(s.environments, sess, client, 1); err != nil {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
+	if err := tenant.checkCounts(s.environments, sess, client, 1); err != nil {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
+
+	// 创建配置，按客户端期望的协议版本调整步进语义
+	values, err := negotiateAPIVersion(int(req.ApiVersion), req.Config.AsMap())
 	if err != nil {
-		return &pb.CreateEnvironmentResponse{
-			Success: false,
-			Message: fmt.Sprintf("Failed to create environment: %v", err),
-		}, nil
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	config := core.NewBaseConfig(values)
+	if err := validateServerOptions(config); err != nil {
+		return createFailure(codes.InvalidArgument, req.EnvId, fmt.Sprintf("Failed to create environment: %v", err)), nil
 	}
 
-	// 保存环境和配置
-	s.environments[req.EnvId] = env
-	s.configs[req.EnvId] = config
+	// 创建环境，并按配置开启轨迹录制、录像或指标发布
+	env, err := s.engine.CreateEnvironmentContext(ctx, req.Scenario, config)
+	if err == nil {
+		if err := s.limits.checkObservation(env); err != nil {
+			env.Close()
+			return nil, status.Error(codes.ResourceExhausted, err.Error())
+		}
+		if err := tenant.checkObservation(env); err != nil {
+			env.Close()
+			return nil, status.Error(codes.ResourceExhausted, err.Error())
+		}
+		env, err = s.telemetry.wrapEnvironment(s.engine.Hooks(), env, config, req.Scenario, key, values)
+	}
+	if err != nil {
+		return createFailure(codes.InvalidArgument, req.EnvId, fmt.Sprintf("Failed to create environment: %v", err)), nil
+	}
+
+	// 保存环境和配置；并发创建同名环境时只保留先注册的一个
+	entry := newEnvEntry(env, config, s.gymnasium)
+	entry.client, entry.scenario, entry.tenant = client, req.Scenario, tenant.namespace()
+	if !s.environments.add(key, entry) {
+		env.Close()
+		return createFailure(codes.AlreadyExists, req.EnvId, fmt.Sprintf("Environment %s already exists", req.EnvId)), nil
+	}
+	if sess != nil && !sess.own(s.environments, key, s.telemetry) {
+		return nil, fmt.Errorf("session %s closed", sess.ID)
+	}
+	// 并发创建时复核数量上限
+	err = s.limits.checkCounts(s.environments, sess, client, 0)
+	if err == nil {
+		err = tenant.checkCounts(s.environments, sess, client, 0)
+	}
+	if err != nil {
+		discard(s.environments, sess, key, s.telemetry)
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
 
 	return &pb.CreateEnvironmentResponse{
 		Success: true,
@@ -130,105 +449,194 @@ func (s *GrpcServer) CreateEnvironment(ctx context.Context, req *pb.CreateEnviro
 
 // ResetEnvironment resets an existing environment
 func (s *GrpcServer) ResetEnvironment(ctx context.Context, req *pb.ResetEnvironmentRequest) (*pb.ResetEnvironmentResponse, error) {
-	env, exists := s.environments[req.EnvId]
+	key, _, err := s.scope(ctx, req.EnvId)
+	if err != nil {
+		return nil, err
+	}
+	entry, exists := s.environments.entry(key)
 	if !exists {
-		return nil, fmt.Errorf("environment %s not found", req.EnvId)
+		return nil, errEnvNotFound(req.EnvId)
 	}
+	env := entry.env
 
+	entry.mu.Lock()
 	observations, err := env.Reset(ctx)
+	if err == nil && entry.truncation != nil {
+		entry.truncation.Reset()
+	}
+	entry.mu.Unlock()
 	if err != nil {
-		return nil, fmt.Errorf("failed to reset environment: %v", err)
+		return nil, errEnvFailed(req.EnvId, "reset", err)
 	}
+	entry.stats.reset()
 
-	// 转换观察为protobuf格式
-	protoObservations := make([]*pb.Observation, len(observations))
-	for i, obs := range observations {
-		metadataStruct, err := structpb.NewStruct(obs.GetMetadata())
-		if err != nil {
-			return nil, fmt.Errorf("failed to create metadata struct for observation %d: %v", i, err)
-		}
-
-		protoObservations[i] = &pb.Observation{
-			Data:     obs.GetData(),
-			Metadata: metadataStruct,
-		}
+	// 转换观察为protobuf格式；数据已复制到消息中，归还对象池中的观察
+	encoder := stepEncoder{typed: entry.typedValues}
+	result, err := entry.stepResult(observations, nil, nil, nil)
+	if err != nil {
+		core.ReleaseObservations(observations)
+		return nil, err
+	}
+	agentIDs := entry.agentNames(len(observations))
+	protoObservations, err := encoder.observations(observations, agentIDs)
+	core.ReleaseObservations(observations)
+	if err != nil {
+		return nil, err
 	}
 
-	infoStruct, err := structpb.NewStruct(env.GetInfo())
+	infoStruct, typedInfo, err := encoder.infoStruct(env.GetInfo())
 	if err != nil {
 		return nil, fmt.Errorf("failed to create info struct: %v", err)
 	}
 
-	return &pb.ResetEnvironmentResponse{
+	resp := &pb.ResetEnvironmentResponse{
 		Observations: protoObservations,
 		Info:         infoStruct,
-	}, nil
+		TypedInfo:    typedInfo,
+		AgentIds:     agentIDs,
+	}
+	if result != nil {
+		resp.Agents, resp.Observations, resp.AgentIds = protoAgents(result, protoObservations), nil, nil
+	}
+	return resp, nil
 }
 
 // StepEnvironment executes one step in the simulation
 func (s *GrpcServer) StepEnvironment(ctx context.Context, req *pb.StepEnvironmentRequest) (*pb.StepEnvironmentResponse, error) {
-	env, exists := s.environments[req.EnvId]
+	// 一元调用返回后消息何时序列化完毕无从得知，因此每次使用新的编码器
+	resp := &pb.StepEnvironmentResponse{}
+	if err := s.step(ctx, req, &stepEncoder{}, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// step 执行一步仿真并将结果写入resp，resp引用encoder的缓冲区
+func (s *GrpcServer) step(ctx context.Context, req *pb.StepEnvironmentRequest, encoder *stepEncoder, resp *pb.StepEnvironmentResponse) error {
+	start := time.Now()
+	key, _, err := s.scope(ctx, req.EnvId)
+	if err != nil {
+		return err
+	}
+	entry, exists := s.environments.entry(key)
 	if !exists {
-		return nil, fmt.Errorf("environment %s not found", req.EnvId)
+		return errEnvNotFound(req.EnvId)
 	}
+	env := entry.env
+	encoder.typed = entry.typedValues
 
-	var actions []core.Action
+	actions := encoder.actions[:0]
 	for _, v := range req.Actions {
 		action, err := s.convertProtoAction(v)
 		if err != nil {
-			return nil, fmt.Errorf("failed to convert action: %v", err)
+			return newError(codes.InvalidArgument, map[string]string{"env_id": req.EnvId}, "invalid action: %v", err)
 		}
 		actions = append(actions, action...)
 	}
+	encoder.actions = actions
+	if err := checkActions(env.GetSpaces().ActionSpace, actions); err != nil {
+		return newError(codes.InvalidArgument, map[string]string{"env_id": req.EnvId}, "invalid action: %v", err)
+	}
 
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	stepStart := time.Now()
 	observations, rewards, done, err := env.Step(ctx, actions)
 	if err != nil {
-		return nil, fmt.Errorf("failed to step environment: %v", err)
+		return errEnvFailed(req.EnvId, "step", err)
 	}
+	s.latency.Env.Observe(entry.scenario, time.Since(stepStart))
+	entry.stats.step()
 
-	// 转换观察为protobuf格式
-	protoObservations := make([]*pb.Observation, len(observations))
-	for i, obs := range observations {
-		metadataStruct, err := structpb.NewStruct(obs.GetMetadata())
-		if err != nil {
-			return nil, fmt.Errorf("failed to create metadata struct for observation %d: %v", i, err)
+	info := env.GetInfo()
+	var terminated, truncated []bool
+	if entry.truncation != nil {
+		terminated, truncated = entry.truncation.Step(done, info)
+	}
+	// 自动重置会归还结束时的观察，因此先将其编码为final_observations
+	var final []*pb.Observation
+	if entry.autoReset && core.AllDone(done) {
+		if encoder.final == nil {
+			encoder.final = &stepEncoder{}
 		}
-
-		protoObservations[i] = &pb.Observation{
-			Data:     obs.GetData(),
-			Metadata: metadataStruct,
+		encoder.final.typed = encoder.typed
+		if final, err = encoder.final.observations(observations, entry.agentNames(len(observations))); err != nil {
+			return err
 		}
 	}
+	observations, err = entry.resetIfDone(ctx, observations, done, info)
+	if err != nil {
+		return errEnvFailed(req.EnvId, "auto-reset", err)
+	}
 
-	infoStruct, err := structpb.NewStruct(env.GetInfo())
+	// 转换观察为protobuf格式；数据已复制到消息中，归还对象池中的观察
+	result, err := entry.stepResult(observations, rewards, terminated, truncated)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create info struct: %v", err)
+		core.ReleaseObservations(observations)
+		return err
+	}
+	agentIDs := entry.agentNames(len(observations))
+	protoObservations, err := encoder.observations(observations, agentIDs)
+	core.ReleaseObservations(observations)
+	if err != nil {
+		return err
 	}
 
-	return &pb.StepEnvironmentResponse{
-		Observations: protoObservations,
-		Rewards:      rewards,
-		Done:         done,
-		Info:         infoStruct,
-	}, nil
+	infoStruct, typedInfo, err := encoder.infoStruct(info)
+	if err != nil {
+		return fmt.Errorf("failed to create info struct: %v", err)
+	}
+
+	resp.Observations = protoObservations
+	resp.Rewards = rewards
+	resp.Done = done
+	resp.Info = infoStruct
+	resp.TypedInfo = typedInfo
+	resp.AgentIds = agentIDs
+	resp.FinalObservations = final
+	resp.Terminated, resp.Truncated = nil, nil
+	resp.StepType, resp.Discount = nil, nil
+	if entry.gymnasium {
+		resp.Terminated, resp.Truncated = terminated, truncated
+	}
+	if entry.dmEnv {
+		resp.StepType, resp.Discount = protoStepTypes(terminated, truncated)
+	}
+	resp.Agents = nil
+	if result != nil {
+		resp.Agents = protoAgents(result, protoObservations)
+		resp.Observations, resp.Rewards, resp.Done = nil, nil, nil
+		resp.Terminated, resp.Truncated, resp.AgentIds = nil, nil, nil
+	}
+	s.latency.Request.Observe(entry.scenario, time.Since(start))
+	return nil
 }
 
 // CloseEnvironment closes an existing environment
 func (s *GrpcServer) CloseEnvironment(ctx context.Context, req *pb.CloseEnvironmentRequest) (*pb.CloseEnvironmentResponse, error) {
-	env, exists := s.environments[req.EnvId]
+	key, sess, err := s.scope(ctx, req.EnvId)
+	if err != nil {
+		return nil, err
+	}
+	env, exists := s.environments.Get(key)
 	if !exists {
-		return nil, fmt.Errorf("environment %s not found", req.EnvId)
+		return nil, errEnvNotFound(req.EnvId)
 	}
 
 	if err := env.Close(); err != nil {
+		message := fmt.Sprintf("Failed to close environment: %v", err)
 		return &pb.CloseEnvironmentResponse{
 			Success: false,
-			Message: fmt.Sprintf("Failed to close environment: %v", err),
+			Message: message,
+			Error:   protoError(codes.Internal, message, map[string]string{"env_id": req.EnvId}),
 		}, nil
 	}
 
-	delete(s.environments, req.EnvId)
-	delete(s.configs, req.EnvId)
+	s.environments.Remove(key)
+	s.telemetry.reportClosed(key)
+	if sess != nil {
+		sess.untrack(key)
+	}
 
 	return &pb.CloseEnvironmentResponse{
 		Success: true,
@@ -238,15 +646,17 @@ func (s *GrpcServer) CloseEnvironment(ctx context.Context, req *pb.CloseEnvironm
 
 // StreamStep implements streaming simulation steps
 func (s *GrpcServer) StreamStep(stream pb.SimulationService_StreamStepServer) error {
+	var encoder stepEncoder
+	resp := &pb.StepEnvironmentResponse{}
 	for {
 		req, err := stream.Recv()
 		if err != nil {
 			return err
 		}
 
-		// 处理步进请求
-		resp, err := s.StepEnvironment(stream.Context(), req)
-		if err != nil {
+		// 处理步进请求；Send返回时消息已序列化（服务端的stats handler只处理连接事件，不会延迟读取消息），
+		// 因此整个流复用同一个响应消息及其缓冲区
+		if err := s.step(stream.Context(), req, &encoder, resp); err != nil {
 			return err
 		}
 
@@ -259,40 +669,444 @@ func (s *GrpcServer) StreamStep(stream pb.SimulationService_StreamStepServer) er
 
 // GetSpaces 获取指定场景的动作空间和观察空间定义
 func (s *GrpcServer) GetSpaces(ctx context.Context, req *pb.GetSpacesRequest) (*pb.GetSpacesResponse, error) {
-	env, ok := s.environments[req.EnvId]
+	key, _, err := s.scope(ctx, req.EnvId)
+	if err != nil {
+		return nil, err
+	}
+	env, ok := s.environments.Get(key)
 	if !ok {
-		return nil, fmt.Errorf("environment %s not found", req.EnvId)
+		return nil, errEnvNotFound(req.EnvId)
 	}
 
 	// 获取空间定义
 	spacesDef := env.GetSpaces()
 
 	// 转换为protobuf格式
-	actionSpace := &pb.ActionSpace{
-		Type:           pb.SpaceType(spacesDef.ActionSpace.Type),
-		Low:            spacesDef.ActionSpace.Low,
-		High:           spacesDef.ActionSpace.High,
-		Shape:          spacesDef.ActionSpace.Shape,
-		Dtype:          spacesDef.ActionSpace.Dtype,
-		DiscreteValues: spacesDef.ActionSpace.DiscreteValues,
-	}
+	actionSpace := protoActionSpace(spacesDef.ActionSpace)
 
 	observationSpace := &pb.ObservationSpace{
-		Type:  pb.SpaceType(spacesDef.ObservationSpace.Type),
-		Low:   spacesDef.ObservationSpace.Low,
-		High:  spacesDef.ObservationSpace.High,
-		Shape: spacesDef.ObservationSpace.Shape,
-		Dtype: spacesDef.ObservationSpace.Dtype,
+		Type:      pb.SpaceType(spacesDef.ObservationSpace.Type),
+		Low:       spacesDef.ObservationSpace.Low,
+		High:      spacesDef.ObservationSpace.High,
+		Shape:     spacesDef.ObservationSpace.Shape,
+		Dtype:     spacesDef.ObservationSpace.Dtype,
+		MaxLength: int32(spacesDef.ObservationSpace.MaxLength),
+		Charset:   spacesDef.ObservationSpace.Charset,
+	}
+
+	spacesJSON, err := json.Marshal(spacesDef)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode spaces: %w", err)
 	}
 
 	return &pb.GetSpacesResponse{
 		ActionSpace:      actionSpace,
 		ObservationSpace: observationSpace,
+		SpacesJson:       string(spacesJSON),
+	}, nil
+}
+
+// protoActionSpace 将动作空间转换为protobuf格式，Dict空间的子空间与Hybrid空间的参数空间递归转换
+func protoActionSpace(space core.ActionSpace) *pb.ActionSpace {
+	actionSpace := &pb.ActionSpace{
+		Type:           pb.SpaceType(space.Type),
+		Low:            space.Low,
+		High:           space.High,
+		Shape:          space.Shape,
+		Dtype:          space.Dtype,
+		DiscreteValues: space.DiscreteValues,
+		MaxLength:      int32(space.MaxLength),
+		Charset:        space.Charset,
+	}
+	for _, n := range space.Nvec() {
+		actionSpace.Nvec = append(actionSpace.Nvec, int64(n))
+	}
+	if len(space.Subspaces) > 0 {
+		actionSpace.Spaces = make(map[string]*pb.ActionSpace, len(space.Subspaces))
+		for name, sub := range space.Subspaces {
+			actionSpace.Spaces[name] = protoActionSpace(sub)
+		}
+	}
+	for _, p := range space.Parameters {
+		actionSpace.Parameters = append(actionSpace.Parameters, protoActionSpace(p))
+	}
+	return actionSpace
+}
+
+// GetMetadata 获取环境元数据（奖励范围、最大步数、渲染模式等）
+func (s *GrpcServer) GetMetadata(ctx context.Context, req *pb.GetMetadataRequest) (*pb.GetMetadataResponse, error) {
+	key, _, err := s.scope(ctx, req.EnvId)
+	if err != nil {
+		return nil, err
+	}
+	env, ok := s.environments.Get(key)
+	if !ok {
+		return nil, errEnvNotFound(req.EnvId)
+	}
+
+	metadata := core.GetEnvMetadata(env)
+	return &pb.GetMetadataResponse{
+		RewardRange:      metadata.RewardRange[:],
+		MaxEpisodeSteps:  int32(metadata.MaxEpisodeSteps),
+		RenderModes:      metadata.RenderModes,
+		Nondeterministic: metadata.Nondeterministic,
 	}, nil
 }
 
+// DebugEnvironment 以JSON导出调用方的环境的内部状态，字段与HTTP的GET /debug/env/{id}相同
+func (s *GrpcServer) DebugEnvironment(ctx context.Context, req *pb.DebugEnvironmentRequest) (*pb.DebugEnvironmentResponse, error) {
+	key, _, err := s.scope(ctx, req.EnvId)
+	if err != nil {
+		return nil, err
+	}
+	dump, ok := debugDump(s.environments, key, req.EnvId)
+	if !ok {
+		return nil, errEnvNotFound(req.EnvId)
+	}
+	data, err := json.Marshal(dump)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode state: %v", err)
+	}
+	return &pb.DebugEnvironmentResponse{DumpJson: string(data)}, nil
+}
+
+// GetCurriculum 获取环境的课程进度，环境未以curriculum配置创建时返回FailedPrecondition
+func (s *GrpcServer) GetCurriculum(ctx context.Context, req *pb.GetCurriculumRequest) (*pb.CurriculumProgress, error) {
+	c, err := s.curriculum(ctx, req.EnvId)
+	if err != nil {
+		return nil, err
+	}
+	return curriculumProgress(c.Progress()), nil
+}
+
+// SetCurriculumStage 切换环境的课程阶段，新阶段的参数在下次Reset时生效
+func (s *GrpcServer) SetCurriculumStage(ctx context.Context, req *pb.SetCurriculumStageRequest) (*pb.CurriculumProgress, error) {
+	c, err := s.curriculum(ctx, req.EnvId)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.SetStage(int(req.Stage), req.Frozen); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return curriculumProgress(c.Progress()), nil
+}
+
+// curriculum 返回环境的课程学习包装器
+func (s *GrpcServer) curriculum(ctx context.Context, envID string) (*curriculum.Curriculum, error) {
+	key, _, err := s.scope(ctx, envID)
+	if err != nil {
+		return nil, err
+	}
+	env, ok := s.environments.Get(key)
+	if !ok {
+		return nil, errEnvNotFound(envID)
+	}
+	c, ok := curriculum.Find(env)
+	if !ok {
+		return nil, status.Errorf(codes.FailedPrecondition, "environment %s has no curriculum", envID)
+	}
+	return c, nil
+}
+
+// curriculumProgress 将课程进度转换为protobuf格式
+func curriculumProgress(p curriculum.Progress) *pb.CurriculumProgress {
+	return &pb.CurriculumProgress{
+		Stage:         int32(p.Stage),
+		Stages:        int32(p.Stages),
+		Episodes:      int64(p.Episodes),
+		StageEpisodes: int64(p.StageEpisodes),
+		SuccessRate:   p.SuccessRate,
+		Window:        int32(p.Window),
+		Frozen:        p.Frozen,
+		Parameters:    p.Parameters,
+	}
+}
+
+// EvaluatePolicy 在临时环境中以ONNX模型（或policy指定的内置基线策略）作为策略运行若干回合，推理在服务端完成；
+// 环境不加入注册表，评估结束后即关闭
+func (s *GrpcServer) EvaluatePolicy(ctx context.Context, req *pb.EvaluatePolicyRequest) (*pb.EvaluatePolicyResponse, error) {
+	tenant, err := s.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCreate(s.scenarios, tenant, req.Scenario); err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	baseline := false
+	for _, name := range policy.Baselines {
+		baseline = baseline || req.Policy == name
+	}
+	if req.Policy != "" && req.Policy != "onnx" && !baseline {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown policy %q, expected onnx, random, zero or heuristic", req.Policy)
+	}
+	var model *policy.Model
+	if !baseline {
+		var err error
+		if model, err = policy.ParseModel(req.Model); err != nil {
+			return nil, err
+		}
+	}
+	episodes := int(req.Episodes)
+	if episodes <= 0 {
+		episodes = 1
+	}
+
+	env, err := s.engine.CreateEnvironmentContext(ctx, req.Scenario, core.NewBaseConfig(req.Config.AsMap()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create environment: %w", err)
+	}
+	defer env.Close()
+
+	var p policy.Policy
+	if model != nil {
+		p = model.Policy(env.GetSpaces().ActionSpace)
+	} else {
+		// 手写控制器按场景编写，预设使用其场景的控制器
+		scenario := req.Scenario
+		if preset, ok := s.engine.GetPreset(scenario); ok {
+			scenario = preset.Scenario
+		}
+		if p, err = policy.Baseline(req.Policy, scenario, env, rand.New(rand.NewSource(req.Seed))); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	result, err := policy.Evaluate(ctx, env, p, episodes, policy.EvalOptions{MaxSteps: int(req.MaxSteps)})
+	if err != nil {
+		return nil, err
+	}
+	lengths := make([]int32, len(result.Episodes))
+	for i, episode := range result.Episodes {
+		lengths[i] = int32(episode.Length)
+	}
+	return &pb.EvaluatePolicyResponse{
+		Returns:        result.Returns(),
+		Lengths:        lengths,
+		Truncated:      int32(len(result.Episodes) - result.Count(policy.EpisodeTerminated)),
+		MeanReturn:     result.MeanReturn,
+		StdReturn:      result.StdReturn,
+		MeanLength:     result.MeanLength,
+		TotalSteps:     int64(result.Steps),
+		ElapsedSeconds: result.Elapsed.Seconds(),
+	}, nil
+}
+
+// ListEnvironments 管理接口：列出全部环境（含各会话的环境）的所属会话、客户端、存活时间与步数
+func (s *GrpcServer) ListEnvironments(ctx context.Context, req *pb.ListEnvironmentsRequest) (*pb.ListEnvironmentsResponse, error) {
+	if err := s.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+	statuses := envStatuses(s.environments, s.sessions)
+	resp := &pb.ListEnvironmentsResponse{
+		Environments: make([]*pb.EnvironmentStatus, len(statuses)),
+		Draining:     s.draining.Load(),
+	}
+	for i, item := range statuses {
+		resp.Environments[i] = &pb.EnvironmentStatus{
+			EnvId:       item.EnvID,
+			Scenario:    item.Scenario,
+			SessionId:   item.SessionID,
+			Client:      item.Client,
+			AgeSeconds:  item.AgeSeconds,
+			IdleSeconds: item.IdleSeconds,
+			Steps:       item.Steps,
+			Episodes:    item.Episodes,
+			Tenant:      item.Tenant,
+			Fault:       item.Fault,
+		}
+	}
+	return resp, nil
+}
+
+// ForceCloseEnvironment 管理接口：强制关闭环境，env_id为注册表中的完整键，不受会话限制
+func (s *GrpcServer) ForceCloseEnvironment(ctx context.Context, req *pb.ForceCloseEnvironmentRequest) (*pb.ForceCloseEnvironmentResponse, error) {
+	if err := s.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if !forceClose(s.environments, s.sessions, req.EnvId, s.telemetry) {
+		return nil, errEnvNotFound(req.EnvId)
+	}
+	s.telemetry.log().Info("Environment force-closed by admin", "env_id", req.EnvId)
+	return &pb.ForceCloseEnvironmentResponse{
+		Success: true,
+		Message: fmt.Sprintf("Environment %s closed successfully", req.EnvId),
+	}, nil
+}
+
+// DumpEnvironmentState 管理接口：以JSON导出环境的内部状态（core.DumpState）
+func (s *GrpcServer) DumpEnvironmentState(ctx context.Context, req *pb.DumpEnvironmentStateRequest) (*pb.DumpEnvironmentStateResponse, error) {
+	if err := s.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+	dump, ok := dumpState(s.environments, req.EnvId)
+	if !ok {
+		return nil, errEnvNotFound(req.EnvId)
+	}
+	state, err := json.Marshal(dump)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode state: %v", err)
+	}
+	return &pb.DumpEnvironmentStateResponse{StateJson: string(state)}, nil
+}
+
+// Drain 管理接口：停止创建新环境，等待已有环境关闭，force时随后强制关闭剩余环境
+func (s *GrpcServer) Drain(ctx context.Context, req *pb.DrainRequest) (*pb.DrainResponse, error) {
+	if err := s.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+	remaining, closed := s.DrainEnvironments(time.Duration(req.TimeoutSeconds*float64(time.Second)), req.Force)
+	s.telemetry.log().Info("Draining", "remaining", remaining, "force_closed", closed)
+	return &pb.DrainResponse{RemainingEnvironments: int32(remaining), ClosedEnvironments: int32(closed)}, nil
+}
+
+// ExportEnvironment 管理接口：导出环境（属于会话时连同会话的全部环境）的检查点，detach时随后从本服务端移除
+func (s *GrpcServer) ExportEnvironment(ctx context.Context, req *pb.ExportEnvironmentRequest) (*pb.ExportEnvironmentResponse, error) {
+	if err := s.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+	snap, err := exportEnv(s.engine, s.environments, s.sessions, s.telemetry, req.EnvId, req.Detach)
+	if errors.Is(err, core.ErrCheckpointUnsupported) {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode snapshot: %v", err)
+	}
+	if req.Detach {
+		s.telemetry.log().Info("Environment exported and detached", "env_id", req.EnvId, "environments", len(snap.Environments))
+	}
+	return &pb.ExportEnvironmentResponse{Snapshot: data, Environments: int32(len(snap.Environments))}, nil
+}
+
+// ImportEnvironment 管理接口：按ExportEnvironment的结果重新创建环境并恢复状态
+func (s *GrpcServer) ImportEnvironment(ctx context.Context, req *pb.ImportEnvironmentRequest) (*pb.ImportEnvironmentResponse, error) {
+	if err := s.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if s.draining.Load() {
+		return nil, status.Error(codes.Unavailable, "server is draining")
+	}
+	snap, err := decodeSnapshot(req.Snapshot)
+	if err != nil {
+		return nil, err
+	}
+	n, err := importSnapshot(s.engine, s.wasmLimits, s.environments, s.sessions, s.telemetry, s.gymnasium, snap)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ImportEnvironmentResponse{Environments: int32(n)}, nil
+}
+
+// RegisterScenario 编译上传的WASM模块并注册为场景。场景对所有客户端可见，因此需要管理令牌，且不能与已有的场景重名
+func (s *GrpcServer) RegisterScenario(ctx context.Context, req *pb.RegisterScenarioRequest) (*pb.RegisterScenarioResponse, error) {
+	// 上传的模块会在服务端执行，服务端未设置管理令牌时同样拒绝
+	if err := requireAdminToken(s.adminToken, metadataValue(ctx, AdminTokenMetadataKey)); err != nil {
+		if errors.Is(err, errNoAdminToken) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	tenant, err := s.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCreate(s.scenarios, tenant, req.Name); err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	details := map[string]string{"scenario": req.Name}
+	err = registerWasmScenario(s.engine, s.wasmLimits, RegisterScenarioRequest{Name: req.Name, Description: req.Description, Wasm: req.Wasm})
+	switch {
+	case errors.Is(err, errWasmDisabled):
+		return nil, newError(codes.FailedPrecondition, details, "%v", err)
+	case errors.Is(err, errScenarioExists):
+		return nil, newError(codes.AlreadyExists, details, "%v", err)
+	case err != nil:
+		return nil, newError(codes.InvalidArgument, details, "%v", err)
+	}
+	s.telemetry.log().Info("WASM scenario registered", "scenario", req.Name, "client", grpcClient(ctx), "bytes", len(req.Wasm))
+	return &pb.RegisterScenarioResponse{}, nil
+}
+
+// checkAdmin 校验请求元数据中的管理令牌
+func (s *GrpcServer) checkAdmin(ctx context.Context) error {
+	if err := checkAdminToken(s.adminToken, metadataValue(ctx, AdminTokenMetadataKey)); err != nil {
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+	return nil
+}
+
+// tenant 按请求元数据中的API密钥返回请求所属的租户，未配置租户时为nil
+func (s *GrpcServer) tenant(ctx context.Context) (*Tenant, error) {
+	tenant, err := s.tenants.resolve(metadataValue(ctx, APIKeyMetadataKey))
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	return tenant, nil
+}
+
+// scope 返回请求中env_id在注册表中的键（按租户与会话区分）及其所属会话
+func (s *GrpcServer) scope(ctx context.Context, envID string) (string, *Session, error) {
+	tenant, err := s.tenant(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	return s.sessions.scope(tenant.namespace(), sessionIDFromContext(ctx), envID)
+}
+
+// grpcClient 返回请求的来源主机，用于按客户端计数
+func grpcClient(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return clientHost(p.Addr.String())
+	}
+	return ""
+}
+
+// OpenSession 打开会话，之后在请求元数据中携带session-id的请求使用会话内的env_id命名空间
+func (s *GrpcServer) OpenSession(ctx context.Context, req *pb.OpenSessionRequest) (*pb.OpenSessionResponse, error) {
+	tenant, err := s.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var conn uint64
+	if req.BindConnection {
+		conn = connID(ctx)
+	}
+	sess := s.sessions.Open(tenant.namespace(), req.Client, time.Duration(req.TtlSeconds)*time.Second, conn)
+	return &pb.OpenSessionResponse{
+		SessionId:  sess.ID,
+		TtlSeconds: int32(sess.TTL / time.Second),
+	}, nil
+}
+
+// CloseSession 关闭会话并关闭其所有环境
+func (s *GrpcServer) CloseSession(ctx context.Context, req *pb.CloseSessionRequest) (*pb.CloseSessionResponse, error) {
+	tenant, err := s.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if sess, ok := s.sessions.lookup(req.SessionId); !ok || sess.Tenant != tenant.namespace() {
+		return nil, errSessionNotFound(req.SessionId)
+	}
+	closed, ok := s.sessions.Close(req.SessionId)
+	if !ok {
+		return nil, errSessionNotFound(req.SessionId)
+	}
+	return &pb.CloseSessionResponse{ClosedEnvironments: int32(closed)}, nil
+}
+
 // convertProtoAction converts protobuf Action to core.Action
 func (s *GrpcServer) convertProtoAction(protoAction *pb.Action) ([]core.Action, error) {
+	action, err := protoToAction(protoAction)
+	if err != nil {
+		return nil, err
+	}
+	return []core.Action{action}, nil
+}
+
+// protoToAction 将单个protobuf动作转换为core.Action，复合动作的各分量递归转换为DictAction，参数化动作转换为HybridAction
+func protoToAction(protoAction *pb.Action) (core.Action, error) {
 	if protoAction == nil {
 		return nil, fmt.Errorf("action is nil")
 	}
@@ -328,6 +1142,28 @@ func (s *GrpcServer) convertProtoAction(protoAction *pb.Action) ([]core.Action,
 		}
 	case *pb.Action_RawData:
 		actionData = data.RawData
+	case *pb.Action_Dict:
+		if data.Dict == nil {
+			return nil, fmt.Errorf("action dict is nil")
+		}
+		components := make(map[string]core.Action, len(data.Dict.Actions))
+		for name, component := range data.Dict.Actions {
+			action, err := protoToAction(component)
+			if err != nil {
+				return nil, fmt.Errorf("component %q: %w", name, err)
+			}
+			components[name] = action
+		}
+		action := core.NewDictAction(components)
+		if err := action.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid action: %w", err)
+		}
+		return action, nil
+	case *pb.Action_Hybrid:
+		if data.Hybrid == nil {
+			return nil, fmt.Errorf("hybrid action is nil")
+		}
+		return core.NewHybridAction(int(data.Hybrid.Choice), data.Hybrid.Parameters), nil
 	case nil:
 		return nil, fmt.Errorf("action data is nil")
 	default:
@@ -340,5 +1176,5 @@ func (s *GrpcServer) convertProtoAction(protoAction *pb.Action) ([]core.Action,
 		return nil, fmt.Errorf("invalid action: %w", err)
 	}
 
-	return []core.Action{action}, nil
+	return action, nil
 }
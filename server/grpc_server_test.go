@@ -0,0 +1,107 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/jelech/rl_env_engine/core"
+	pb "github.com/jelech/rl_env_engine/proto"
+	"github.com/jelech/rl_env_engine/scenarios"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// BenchmarkGrpcServerStep 测量每个内置场景经回环gRPC单步的耗时与内存分配，分配数包括同一进程中的服务端；
+// 回合结束时调用ResetEnvironment（计入耗时）
+func BenchmarkGrpcServerStep(b *testing.B) {
+	discardLogs(b)
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		b.Fatal(err)
+	}
+	srv := grpc.NewServer(grpc.MaxRecvMsgSize(MaxMessageSize), grpc.MaxSendMsgSize(MaxMessageSize))
+	pb.RegisterSimulationServiceServer(srv, NewGrpcServer())
+	go srv.Serve(lis)
+	defer srv.Stop()
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer conn.Close()
+	client := pb.NewSimulationServiceClient(conn)
+	ctx := context.Background()
+
+	for _, s := range scenarios.All() {
+		scenario := s.GetName()
+		b.Run(scenario, func(b *testing.B) {
+			actions := sampleBenchActions(b, scenario)
+			requests := make([]*pb.StepEnvironmentRequest, len(actions))
+			for i, set := range actions {
+				requests[i] = &pb.StepEnvironmentRequest{EnvId: scenario}
+				for _, action := range set {
+					protoAction, err := benchProtoAction(action.GetData())
+					if err != nil {
+						b.Skip(err)
+					}
+					requests[i].Actions = append(requests[i].Actions, protoAction)
+				}
+			}
+
+			created, err := client.CreateEnvironment(ctx, &pb.CreateEnvironmentRequest{EnvId: scenario, Scenario: scenario})
+			if err != nil || !created.Success {
+				b.Fatalf("create: %v %s", err, created.GetMessage())
+			}
+			defer client.CloseEnvironment(ctx, &pb.CloseEnvironmentRequest{EnvId: scenario})
+			reset := &pb.ResetEnvironmentRequest{EnvId: scenario}
+			if _, err := client.ResetEnvironment(ctx, reset); err != nil {
+				b.Fatal(err)
+			}
+			step := func(req *pb.StepEnvironmentRequest) error {
+				resp, err := client.StepEnvironment(ctx, req)
+				if err != nil {
+					return err
+				}
+				if core.AllDone(resp.Done) {
+					_, err = client.ResetEnvironment(ctx, reset)
+				}
+				return err
+			}
+			for _, req := range requests {
+				if err := step(req); err != nil {
+					b.Skipf("random actions: %v", err)
+				}
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := step(requests[i%len(requests)]); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// benchProtoAction 将采样的动作转换为gRPC动作
+func benchProtoAction(data interface{}) (*pb.Action, error) {
+	switch v := data.(type) {
+	case int:
+		return &pb.Action{Data: &pb.Action_IntValue{IntValue: int64(v)}}, nil
+	case float64:
+		return &pb.Action{Data: &pb.Action_FloatValue{FloatValue: v}}, nil
+	case []float64:
+		return &pb.Action{Data: &pb.Action_FloatArray{FloatArray: &pb.FloatArray{Values: v}}}, nil
+	case []int:
+		values := make([]int64, len(v))
+		for i, x := range v {
+			values[i] = int64(x)
+		}
+		return &pb.Action{Data: &pb.Action_IntArray{IntArray: &pb.IntArray{Values: values}}}, nil
+	case []bool:
+		return &pb.Action{Data: &pb.Action_BoolArray{BoolArray: &pb.BoolArray{Values: v}}}, nil
+	}
+	return nil, fmt.Errorf("action %T cannot be sent over gRPC", data)
+}
@@ -1,22 +1,53 @@
 package server
 
+// 请求与响应结构修改后运行 make python-schema 同步Python客户端的类型定义
+//go:generate go run ../cmd/gen_pyschema -out ../python_client/rl_env_engine_client/http_schema.py
+
 import (
 	"context"
+	"crypto/tls"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"math"
+	"net"
 	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/jelech/rl_env_engine/core"
+	"github.com/jelech/rl_env_engine/core/curriculum"
+	"github.com/jelech/rl_env_engine/core/metrics"
+	"github.com/jelech/rl_env_engine/core/record"
+	"github.com/jelech/rl_env_engine/core/runstore"
+	"github.com/jelech/rl_env_engine/core/wasm"
 	"github.com/jelech/rl_env_engine/scenarios/simple"
+	"google.golang.org/grpc/codes"
 )
 
 // GymAPI 定义Gym兼容的API结构
 type GymAPI struct {
 	engine       *core.SimulationEngine
-	environments map[string]core.Environment
-	configs      map[string]core.Config
+	environments *EnvRegistry
+	sessions     *SessionManager
+	limits       Limits
+	telemetry    telemetry
+	gymnasium    bool
+	adminToken   string
+	draining     atomic.Bool
+	tenants      *tenantSet
+	scenarios    []string // 启用的场景与预设，为空时不限制
+	wasmLimits   *wasm.Limits
+	tlsConfig    *tls.Config
+	latency      *metrics.StepLatency
+
+	serveMu  sync.Mutex
+	servers  []*http.Server // Serve启动的HTTP服务，由Shutdown停止
+	stopped  bool           // 已调用Shutdown，之后的Serve直接返回
+	stopping sync.WaitGroup // 进行中的Shutdown，Serve等待其结束后返回
 }
 
 // ResetRequest 重置请求
@@ -24,24 +55,91 @@ type ResetRequest struct {
 	EnvID string `json:"env_id"`
 }
 
-// ResetResponse 重置响应
+// ResetResponse 重置响应，开启agent_dict的环境以Agents代替Observation
 type ResetResponse struct {
 	Observation [][]float64            `json:"observation"`
+	Text        []string               `json:"text,omitempty"`      // Text观察空间的环境中各智能体的文本观察，此时Observation的元素为空
+	Image       []*ImageData           `json:"image,omitempty"`     // Image观察空间的环境中各智能体的图像观察，此时Observation的元素为空
+	AgentIDs    []string               `json:"agent_ids,omitempty"` // Observation中各观察所属智能体的名称，开启agent_dict时为空
 	Info        map[string]interface{} `json:"info"`
+	Agents      map[string]AgentStep   `json:"agents,omitempty"`
+}
+
+// ImageData 图像观察：行优先HWC排列的uint8像素，JSON中以base64字符串编码
+type ImageData struct {
+	Pixels   []byte `json:"pixels"`
+	Height   int    `json:"height"`
+	Width    int    `json:"width"`
+	Channels int    `json:"channels"`
 }
 
-// StepRequest 步进请求
+// AgentStep 开启agent_dict的环境中一个智能体的结果，Info为其观察的元数据
+type AgentStep struct {
+	Observation []float64              `json:"observation"`
+	Text        string                 `json:"text,omitempty"`  // 文本观察
+	Image       *ImageData             `json:"image,omitempty"` // 图像观察
+	Reward      float64                `json:"reward"`
+	Terminated  bool                   `json:"terminated"`
+	Truncated   bool                   `json:"truncated"`
+	Info        map[string]interface{} `json:"info,omitempty"`
+}
+
+// StepRequest 步进请求。Values非空时按环境的动作空间切分给各智能体（与/step_raw相同），
+// 适用于任意场景；Text非空时为Text动作空间的环境中各智能体的文本动作；否则Action为simple场景的{"value": x}
 type StepRequest struct {
 	EnvID  string                 `json:"env_id"`
-	Action map[string]interface{} `json:"action"`
+	Action map[string]interface{} `json:"action,omitempty"`
+	Values ActionValues           `json:"values,omitempty"`
+	Text   []string               `json:"text,omitempty"`
+}
+
+// ActionValues 平铺的动作值。JSON中除数值外也接受布尔值（true为1、false为0），
+// MultiBinary动作可以直接以[true, false, ...]发送
+type ActionValues []float64
+
+// UnmarshalJSON 解码数值与布尔值混合的数组
+func (v *ActionValues) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, (*[]float64)(v)); err == nil {
+		return nil
+	}
+	var raw []interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	values := make([]float64, len(raw))
+	for i, item := range raw {
+		switch x := item.(type) {
+		case float64:
+			values[i] = x
+		case bool:
+			if x {
+				values[i] = 1
+			}
+		default:
+			return fmt.Errorf("action value %d must be a number or a boolean, got %T", i, item)
+		}
+	}
+	*v = values
+	return nil
 }
 
-// StepResponse 步进响应
+// StepResponse 步进响应，开启gymnasium_api的环境额外返回Terminated与Truncated，Done为两者之或；
+// 开启dm_env的环境额外返回StepType（"MID"或"LAST"）与Discount；开启auto_reset的环境回合结束时
+// Observation为新回合的初始观察，结束时的观察在Info["terminal_observation"]中；开启agent_dict的环境
+// 以Agents代替Observation、Reward、Done、Terminated与Truncated
 type StepResponse struct {
 	Observation [][]float64            `json:"observation"`
+	Text        []string               `json:"text,omitempty"`      // Text观察空间的环境中各智能体的文本观察，此时Observation的元素为空
+	Image       []*ImageData           `json:"image,omitempty"`     // Image观察空间的环境中各智能体的图像观察，此时Observation的元素为空
+	AgentIDs    []string               `json:"agent_ids,omitempty"` // Observation、Reward、Done等下标i对应的智能体名称，开启agent_dict时为空
 	Reward      []float64              `json:"reward"`
 	Done        []bool                 `json:"done"`
+	Terminated  []bool                 `json:"terminated,omitempty"`
+	Truncated   []bool                 `json:"truncated,omitempty"`
+	StepType    []string               `json:"step_type,omitempty"`
+	Discount    []float64              `json:"discount,omitempty"`
 	Info        map[string]interface{} `json:"info"`
+	Agents      map[string]AgentStep   `json:"agents,omitempty"`
 }
 
 // CreateEnvRequest 创建环境请求
@@ -53,63 +151,342 @@ type CreateEnvRequest struct {
 
 // CreateEnvResponse 创建环境响应
 type CreateEnvResponse struct {
-	Success bool   `json:"success"`
-	Message string `json:"message"`
+	Success bool      `json:"success"`
+	Message string    `json:"message"`
+	Error   *APIError `json:"error,omitempty"` // Success为false时的错误
+}
+
+// RecordRequest 录制请求，Path为空时停止录制
+type RecordRequest struct {
+	EnvID string `json:"env_id"`
+	Path  string `json:"path"`
+}
+
+// CurriculumStageRequest 切换课程阶段请求，Frozen为true时停止自动推进
+type CurriculumStageRequest struct {
+	EnvID  string `json:"env_id"`
+	Stage  int    `json:"stage"`
+	Frozen bool   `json:"frozen"`
+}
+
+// OpenSessionRequest 打开会话请求，TTLSeconds为0时使用服务端默认值
+type OpenSessionRequest struct {
+	Client     string `json:"client"`
+	TTLSeconds int    `json:"ttl_seconds"`
+}
+
+// OpenSessionResponse 打开会话响应，之后的请求在X-Session-Id头中携带SessionID
+type OpenSessionResponse struct {
+	SessionID  string `json:"session_id"`
+	TTLSeconds int    `json:"ttl_seconds"`
+}
+
+// CloseSessionRequest 关闭会话请求
+type CloseSessionRequest struct {
+	SessionID string `json:"session_id"`
+}
+
+// CloseSessionResponse 关闭会话响应
+type CloseSessionResponse struct {
+	ClosedEnvironments int `json:"closed_environments"`
+}
+
+// ErrorResponse 错误响应：Code为HTTP状态码，Status、Message与Details对应protobuf Error的code、message与details
+type ErrorResponse struct {
+	Error   bool              `json:"error"`
+	Message string            `json:"message"`
+	Code    int               `json:"code"`
+	Status  string            `json:"status"`
+	Details map[string]string `json:"details,omitempty"`
 }
 
 // InfoResponse 环境信息响应
 type InfoResponse struct {
 	Scenarios []string               `json:"scenarios"`
+	Presets   []core.Preset          `json:"presets"`
 	EnvIDs    []string               `json:"env_ids"`
 	Info      map[string]interface{} `json:"info"`
+	// 服务端实现的协议版本与仍支持的最低版本，见APIVersion
+	APIVersion    int `json:"api_version"`
+	MinAPIVersion int `json:"min_api_version"`
 }
 
-func NewGymAPI() *GymAPI {
-	engine := core.NewSimulationEngine()
+// MetricsResponse /metrics响应：以场景为键的步进延迟与吞吐，只包含调用方可创建且已步进过的场景
+type MetricsResponse struct {
+	Transport   string                             `json:"transport"`
+	StepLatency map[string]metrics.ScenarioLatency `json:"step_latency"`
+}
 
-	// 注册简单测试场景
-	simpleScenario := simple.NewSimpleScenario()
-	engine.RegisterScenario(simpleScenario)
+// StatsResponse /stats响应：回合累计奖励与长度的滚动统计，Environments以env_id为键，只包含未关闭的环境；
+// Scenarios以场景名为键，包含已关闭的环境
+type StatsResponse struct {
+	Window       int                               `json:"window"` // Recent保留的最近回合数
+	Scenarios    map[string]metrics.EpisodeSummary `json:"scenarios"`
+	Environments map[string]metrics.EpisodeSummary `json:"environments"`
+}
 
+func NewGymAPI() *GymAPI {
 	return &GymAPI{
-		engine:       engine,
-		environments: make(map[string]core.Environment),
-		configs:      make(map[string]core.Config),
+		engine:       newBuiltinEngine(),
+		environments: NewEnvRegistry(),
+		sessions:     NewSessionManager(),
+		telemetry:    telemetry{stats: metrics.NewEpisodeStats(0)},
+		latency:      metrics.NewStepLatency(),
 	}
 }
 
-func (api *GymAPI) StartServer(port int) error {
+// Engine 返回注册了场景和预设的仿真引擎
+func (api *GymAPI) Engine() *core.SimulationEngine {
+	return api.engine
+}
+
+// SetMetricsSink 设置指标输出，之后创建的环境会发布回合指标及创建/关闭事件；为nil时不发布
+func (api *GymAPI) SetMetricsSink(sink core.MetricsSink) {
+	api.telemetry.metrics = sink
+}
+
+// SetRunStore 设置运行存储，之后创建的环境及其回合会被记录，并可通过/runs查询；为nil时不记录
+func (api *GymAPI) SetRunStore(store *runstore.Store) {
+	api.telemetry.runs = store
+}
+
+// SetOutputDir 设置服务端输出目录：客户端给出的record_path、video_dir、tensorboard_dir与/record路径均解析到该目录之下，
+// 为空时拒绝这些路径
+func (api *GymAPI) SetOutputDir(dir string) {
+	api.telemetry.outputDir = dir
+}
+
+// SetLogger 设置运行日志，为nil时使用core.DefaultLogger()
+func (api *GymAPI) SetLogger(logger core.Logger) {
+	api.telemetry.logger = logger
+}
+
+// StepLatency 返回/metrics报告的按场景的步进延迟与吞吐
+func (api *GymAPI) StepLatency() *metrics.StepLatency {
+	return api.latency
+}
+
+// EpisodeStats 返回/stats报告的回合统计
+func (api *GymAPI) EpisodeStats() *metrics.EpisodeStats {
+	return api.telemetry.stats
+}
+
+// SetGymnasiumAPI 设置之后创建的环境（配置中未设置gymnasium_api时）的步进响应是否返回terminated与truncated
+func (api *GymAPI) SetGymnasiumAPI(enabled bool) {
+	api.gymnasium = enabled
+}
+
+// SetLimits 设置创建环境时检查的资源上限，超出时/create返回429
+func (api *GymAPI) SetLimits(limits Limits) {
+	api.limits = limits
+}
+
+// SetTenants 按租户划分服务端：之后每个请求需在X-API-Key头中携带所属租户的API密钥，只能看到本租户的环境与会话，
+// 且只能在租户的上限内创建允许的场景；为空时不区分租户
+func (api *GymAPI) SetTenants(tenants []Tenant) error {
+	set, err := newTenantSet(tenants)
+	if err != nil {
+		return err
+	}
+	api.tenants = set
+	return nil
+}
+
+// SetScenarios 只允许创建（并在/info中列出）names中的场景与预设，租户可以进一步限制；为空时不限制
+func (api *GymAPI) SetScenarios(names []string) {
+	api.scenarios = names
+}
+
+// SetSessionTTL 设置未指定TTL的会话使用的空闲超时（def<=0时为DefaultSessionTTL）与客户端可以请求的上限（max<=0时不限制）
+func (api *GymAPI) SetSessionTTL(def, max time.Duration) {
+	api.sessions.SetTTL(def, max)
+}
+
+// SetTLS 以config（见LoadTLSConfig）通过HTTPS提供服务，为nil时使用HTTP；须在Serve之前调用
+func (api *GymAPI) SetTLS(config *tls.Config) {
+	api.tlsConfig = config
+}
+
+// EnableWasmScenarios 允许客户端通过/scenario/register上传实现wasmenv环境ABI的WebAssembly模块注册场景，
+// 其每个环境在独立的沙箱实例中运行，资源受限于limits（为0的字段取wasm.DefaultLimits）。上传需要管理令牌，未设置令牌时一律拒绝
+func (api *GymAPI) EnableWasmScenarios(limits wasm.Limits) {
+	api.wasmLimits = &limits
+}
+
+// SetAdminToken 设置管理令牌，/admin/下的请求需在X-Admin-Token头中携带；为空时不校验
+func (api *GymAPI) SetAdminToken(token string) {
+	api.adminToken = token
+}
+
+// DrainEnvironments 停止创建新环境，最多等待timeout让客户端关闭已有环境，force时随后强制关闭剩余环境；
+// 返回剩余与强制关闭的环境数
+func (api *GymAPI) DrainEnvironments(timeout time.Duration, force bool) (remaining, closed int) {
+	api.draining.Store(true)
+	return drain(api.environments, api.sessions, api.telemetry, timeout, force)
+}
+
+// Shutdown 停止接受新连接，等待进行中的请求完成（ctx结束时强制断开剩余连接并返回其错误），之后Serve返回nil。
+// 不关闭环境，见CloseEnvironments
+func (api *GymAPI) Shutdown(ctx context.Context) error {
+	api.serveMu.Lock()
+	api.stopped = true
+	servers := api.servers
+	api.stopping.Add(1)
+	api.serveMu.Unlock()
+	defer api.stopping.Done()
+
+	var first error
+	for _, srv := range servers {
+		if err := srv.Shutdown(ctx); err != nil {
+			srv.Close()
+			if first == nil {
+				first = err
+			}
+		}
+	}
+	return first
+}
+
+// CloseEnvironments 停止创建新环境并立即关闭全部活跃环境，返回关闭的环境数
+func (api *GymAPI) CloseEnvironments() int {
+	_, closed := api.DrainEnvironments(0, true)
+	return closed
+}
+
+// SaveSnapshot 将实现了core.Checkpointer的环境及其所属会话写入path，返回保存的环境数
+func (api *GymAPI) SaveSnapshot(path string) (int, error) {
+	snap := takeSnapshot(api.engine, api.environments, api.sessions, api.telemetry.log())
+	return len(snap.Environments), writeSnapshot(path, snap)
+}
+
+// RestoreSnapshot 按path中的快照重新创建环境并恢复其状态（通常在启动时），文件不存在时不做任何事；
+// 恢复失败的环境记录日志后跳过，返回恢复的环境数
+func (api *GymAPI) RestoreSnapshot(path string) (int, error) {
+	snap, err := readSnapshot(path)
+	if err != nil || snap == nil {
+		return 0, err
+	}
+	return restoreSnapshot(api.engine, api.wasmLimits, api.environments, api.sessions, api.telemetry, api.gymnasium, snap), nil
+}
+
+// StartSnapshots 每隔interval（为0时DefaultSnapshotInterval）将快照保存到path，返回的函数停止定期保存并最后保存一次
+func (api *GymAPI) StartSnapshots(path string, interval time.Duration) (stop func()) {
+	return watchSnapshots(func() error {
+		_, err := api.SaveSnapshot(path)
+		return err
+	}, interval, api.telemetry.log())
+}
+
+// Registry 返回保存活跃环境的注册表
+func (api *GymAPI) Registry() *EnvRegistry {
+	return api.environments
+}
+
+// SetRegistry 替换保存活跃环境的注册表，与GrpcServer共用同一个时两种协议可以操作同一批环境。
+// 应在创建环境之前调用
+func (api *GymAPI) SetRegistry(registry *EnvRegistry) {
+	api.environments = registry
+}
+
+// Sessions 返回打开的客户端会话表
+func (api *GymAPI) Sessions() *SessionManager {
+	return api.sessions
+}
+
+// SetSessions 替换客户端会话表，与注册表一起和GrpcServer共用时，一种协议打开的会话也作用于另一种协议的请求
+func (api *GymAPI) SetSessions(sessions *SessionManager) {
+	api.sessions = sessions
+}
+
+// Handler 返回注册了全部路由（含CORS）的HTTP处理器，便于挂载到自定义的http.Server或监听地址上
+func (api *GymAPI) Handler() http.Handler {
 	mux := http.NewServeMux()
 
 	// 注册路由
 	mux.HandleFunc("/", api.handleIndex)
 	mux.HandleFunc("/info", api.handleInfo)
+	mux.HandleFunc("/metrics", api.handleMetrics)
 	mux.HandleFunc("/create", api.handleCreateEnv)
 	mux.HandleFunc("/reset", api.handleReset)
 	mux.HandleFunc("/step", api.handleStep)
+	mux.HandleFunc("/step_raw", api.handleStepRaw)
 	mux.HandleFunc("/close", api.handleClose)
+	mux.HandleFunc("/spaces", api.handleSpaces)
+	mux.HandleFunc("/metadata", api.handleMetadata)
+	mux.HandleFunc("/debug/env/", api.handleDebugEnv)
+	mux.HandleFunc("/record", api.handleRecord)
+	mux.HandleFunc("/curriculum", api.handleCurriculum)
+	mux.HandleFunc("/curriculum/stage", api.handleCurriculumStage)
+	mux.HandleFunc("/runs", api.handleRuns)
+	mux.HandleFunc("/stats", api.handleStats)
+	mux.HandleFunc("/session/open", api.handleOpenSession)
+	mux.HandleFunc("/session/close", api.handleCloseSession)
+	mux.HandleFunc("/scenario/register", api.handleRegisterScenario)
+	mux.HandleFunc("/admin/envs", api.handleAdminEnvs)
+	mux.HandleFunc("/admin/close", api.handleAdminClose)
+	mux.HandleFunc("/admin/state", api.handleAdminState)
+	mux.HandleFunc("/admin/drain", api.handleAdminDrain)
 
-	// 添加CORS中间件
-	handler := api.corsMiddleware(mux)
+	// 添加CORS中间件，开启追踪时为每个请求创建span
+	return traceMiddleware(api.recoverMiddleware(api.corsMiddleware(mux)))
+}
 
+func (api *GymAPI) StartServer(port int) error {
 	addr := fmt.Sprintf(":%d", port)
-	log.Printf("Starting Gym API server on http://localhost%s", addr)
-	log.Printf("Available endpoints:")
-	log.Printf("  GET  /         - API information")
-	log.Printf("  GET  /info     - Environment information")
-	log.Printf("  POST /create   - Create environment")
-	log.Printf("  POST /reset    - Reset environment")
-	log.Printf("  POST /step     - Step environment")
-	log.Printf("  POST /close    - Close environment")
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen: %v", err)
+	}
+	api.telemetry.log().Info("Starting Gym API server", "url", "http://localhost"+addr)
+	return api.Serve(lis)
+}
+
+// Serve 在lis上提供HTTP API，如Listen返回的Unix套接字监听器
+func (api *GymAPI) Serve(lis net.Listener) error {
+	log := api.telemetry.log()
+	log.Info("Available endpoints", "addr", lis.Addr().String())
+	log.Info("endpoint", "route", "GET /", "description", "API information")
+	log.Info("endpoint", "route", "GET /info", "description", "Environment information")
+	log.Info("endpoint", "route", "GET /metrics", "description", "Per-scenario step latency and throughput")
+	log.Info("endpoint", "route", "POST /create", "description", "Create environment")
+	log.Info("endpoint", "route", "POST /reset", "description", "Reset environment")
+	log.Info("endpoint", "route", "POST /step", "description", "Step environment")
+	log.Info("endpoint", "route", "POST /close", "description", "Close environment")
+	log.Info("endpoint", "route", "POST /metadata", "description", "Environment metadata")
+	log.Info("endpoint", "route", "GET /debug/env/{id}", "description", "Internal state of an environment")
+	log.Info("endpoint", "route", "POST /record", "description", "Start or stop trajectory recording")
+	log.Info("endpoint", "route", "GET /runs", "description", "Recorded runs and episodes")
+	log.Info("endpoint", "route", "GET /stats", "description", "Rolling episode return and length statistics")
+	log.Info("endpoint", "route", "POST /curriculum, /curriculum/stage", "description", "Curriculum progress and stage control")
+	log.Info("endpoint", "route", "POST /session/open", "description", "Open a session scoping the environments of a client")
+	log.Info("endpoint", "route", "POST /session/close", "description", "Close a session and all of its environments")
+	log.Info("endpoint", "route", "GET /admin/envs, POST /admin/close, /admin/state, /admin/drain", "description", "Admin operations")
+
+	if api.tlsConfig != nil {
+		lis = tls.NewListener(lis, api.tlsConfig)
+	}
+	srv := &http.Server{Handler: api.Handler()}
+	api.serveMu.Lock()
+	if api.stopped {
+		api.serveMu.Unlock()
+		return lis.Close()
+	}
+	api.servers = append(api.servers, srv)
+	api.serveMu.Unlock()
 
-	return http.ListenAndServe(addr, handler)
+	if err := srv.Serve(lis); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	// Serve在Shutdown开始时即返回，等待进行中的请求结束后再返回
+	api.stopping.Wait()
+	return nil
 }
 
 func (api *GymAPI) corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, "+SessionHeader+", "+AdminTokenHeader+", "+APIKeyHeader)
 
 		if r.Method == "OPTIONS" {
 			w.WriteHeader(http.StatusOK)
@@ -126,12 +503,28 @@ func (api *GymAPI) handleIndex(w http.ResponseWriter, r *http.Request) {
 		"version":     "1.0.0",
 		"description": "OpenAI Gym compatible API for simulation environments",
 		"endpoints": map[string]string{
-			"GET /":        "This information",
-			"GET /info":    "Get environment information",
-			"POST /create": "Create a new environment",
-			"POST /reset":  "Reset an environment",
-			"POST /step":   "Step an environment",
-			"POST /close":  "Close an environment",
+			"GET /":                  "This information",
+			"GET /info":              "Get environment information",
+			"GET /metrics":           "Per-scenario step latency (p50/p95/p99) and throughput",
+			"POST /create":           "Create a new environment",
+			"POST /reset":            "Reset an environment",
+			"POST /step":             "Step an environment",
+			"POST /step_raw":         "Step an environment with a binary body (uint16 env_id length, env_id, little-endian float actions; ?dtype=float32)",
+			"POST /close":            "Close an environment",
+			"POST /spaces":           "Get the action and observation spaces of an environment",
+			"POST /metadata":         "Get reward range, max steps and render modes of an environment",
+			"GET /debug/env/{id}":    "Dump the checkpoint, internal state and wrappers of an environment as JSON",
+			"POST /record":           "Record transitions of an environment to a JSONL file (empty path stops)",
+			"POST /curriculum":       "Get the curriculum progress of an environment created with a curriculum config",
+			"POST /curriculum/stage": "Switch the curriculum stage of an environment (applied on the next reset); frozen stops automatic progression",
+			"GET /runs":              "Recorded runs with episode statistics (?scenario=&env_id=&active=&limit=, or ?id= for episodes)",
+			"GET /stats":             "Rolling episode return/length statistics per environment and per scenario (?scenario=&env_id=)",
+			"POST /session/open":     "Open a session; requests carrying its id in the " + SessionHeader + " header use a private env_id namespace",
+			"POST /session/close":    "Close a session and all of its environments",
+			"GET /admin/envs":        "List all environments with owner, age and step count (admin)",
+			"POST /admin/close":      "Force-close an environment by its full registry key (admin)",
+			"POST /admin/state":      "Dump the internal state of an environment as JSON (admin)",
+			"POST /admin/drain":      "Stop creating environments and wait for or force-close the existing ones (admin)",
 		},
 	}
 
@@ -139,15 +532,29 @@ func (api *GymAPI) handleIndex(w http.ResponseWriter, r *http.Request) {
 }
 
 func (api *GymAPI) handleInfo(w http.ResponseWriter, r *http.Request) {
-	scenarios := api.engine.ListScenarios()
-	envIDs := make([]string, 0, len(api.environments))
-	for envID := range api.environments {
-		envIDs = append(envIDs, envID)
+	tenant, ok := api.tenant(w, r)
+	if !ok {
+		return
+	}
+	_, sess, ok := api.envKey(w, r, "")
+	if !ok {
+		return
+	}
+	scenarios := creatable(api.scenarios, tenant, api.engine.ListScenarios())
+	envIDs := api.sessions.visibleIDs(api.environments, sess, tenant.namespace())
+	presets := make([]core.Preset, 0)
+	for _, preset := range api.engine.Presets() {
+		if canCreate(api.scenarios, tenant, preset.Name) {
+			presets = append(presets, preset)
+		}
 	}
 
 	response := InfoResponse{
-		Scenarios: scenarios,
-		EnvIDs:    envIDs,
+		Scenarios:     scenarios,
+		Presets:       presets,
+		EnvIDs:        envIDs,
+		APIVersion:    APIVersion,
+		MinAPIVersion: MinAPIVersion,
 		Info: map[string]interface{}{
 			"total_scenarios":     len(scenarios),
 			"active_environments": len(envIDs),
@@ -157,6 +564,19 @@ func (api *GymAPI) handleInfo(w http.ResponseWriter, r *http.Request) {
 	api.writeJSON(w, response)
 }
 
+func (api *GymAPI) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	tenant, ok := api.tenant(w, r)
+	if !ok {
+		return
+	}
+	api.writeJSON(w, MetricsResponse{
+		Transport: "http",
+		StepLatency: api.latency.Summaries(func(scenario string) bool {
+			return canCreate(api.scenarios, tenant, scenario)
+		}),
+	})
+}
+
 func (api *GymAPI) handleCreateEnv(w http.ResponseWriter, r *http.Request) {
 	if r.Method != "POST" {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -168,34 +588,100 @@ func (api *GymAPI) handleCreateEnv(w http.ResponseWriter, r *http.Request) {
 		api.writeError(w, "Invalid JSON", http.StatusBadRequest)
 		return
 	}
+	tenant, ok := api.tenant(w, r)
+	if !ok {
+		return
+	}
+	if err := checkCreate(api.scenarios, tenant, req.Scenario); err != nil {
+		api.writeError(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	key, sess, ok := api.envKey(w, r, req.EnvID)
+	if !ok {
+		return
+	}
 
 	// 检查环境是否已存在
-	if _, exists := api.environments[req.EnvID]; exists {
-		response := CreateEnvResponse{
-			Success: false,
-			Message: fmt.Sprintf("Environment %s already exists", req.EnvID),
-		}
-		api.writeJSON(w, response)
+	if _, exists := api.environments.Get(key); exists {
+		api.writeJSON(w, createEnvFailure(codes.AlreadyExists, req.EnvID, fmt.Sprintf("Environment %s already exists", req.EnvID)))
 		return
 	}
 
-	// 创建配置
-	config := core.NewBaseConfig(req.Config)
+	if api.draining.Load() {
+		api.writeError(w, "Server is draining", http.StatusServiceUnavailable)
+		return
+	}
+
+	// 检查资源上限
+	client := clientHost(r.RemoteAddr)
+	if err := api.limits.checkCounts(api.environments, sess, client, 1); err != nil {
+		api.writeError(w, err.Error(), http.StatusTooManyRequests)
+		return
+	}
+	if err := tenant.checkCounts(api.environments, sess, client, 1); err != nil {
+		api.writeError(w, err.Error(), http.StatusTooManyRequests)
+		return
+	}
 
-	// 创建环境
-	env, err := api.engine.CreateEnvironment(req.Scenario, config)
+	// 创建配置，按客户端期望的协议版本（APIVersionHeader）调整步进语义
+	version, err := headerAPIVersion(r.Header.Get(APIVersionHeader))
+	if err != nil {
+		api.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	values, err := negotiateAPIVersion(version, req.Config)
 	if err != nil {
-		response := CreateEnvResponse{
-			Success: false,
-			Message: fmt.Sprintf("Failed to create environment: %v", err),
+		api.writeError(w, err.Error(), http.StatusPreconditionFailed)
+		return
+	}
+	config := core.NewBaseConfig(values)
+	if err := validateServerOptions(config); err != nil {
+		api.writeJSON(w, createEnvFailure(codes.InvalidArgument, req.EnvID, fmt.Sprintf("Failed to create environment: %v", err)))
+		return
+	}
+
+	// 创建环境，并按配置开启轨迹录制、录像或指标发布
+	env, err := api.engine.CreateEnvironmentContext(r.Context(), req.Scenario, config)
+	if err == nil {
+		if err := api.limits.checkObservation(env); err != nil {
+			env.Close()
+			api.writeError(w, err.Error(), http.StatusTooManyRequests)
+			return
 		}
-		api.writeJSON(w, response)
+		if err := tenant.checkObservation(env); err != nil {
+			env.Close()
+			api.writeError(w, err.Error(), http.StatusTooManyRequests)
+			return
+		}
+		env, err = api.telemetry.wrapEnvironment(api.engine.Hooks(), env, config, req.Scenario, key, values)
+	}
+	if err != nil {
+		api.writeJSON(w, createEnvFailure(codes.InvalidArgument, req.EnvID, fmt.Sprintf("Failed to create environment: %v", err)))
 		return
 	}
 
-	// 保存环境和配置
-	api.environments[req.EnvID] = env
-	api.configs[req.EnvID] = config
+	// 保存环境和配置；并发创建同名环境时只保留先注册的一个
+	entry := newEnvEntry(env, config, api.gymnasium)
+	entry.client, entry.scenario, entry.tenant = client, req.Scenario, tenant.namespace()
+	if !api.environments.add(key, entry) {
+		env.Close()
+		api.writeJSON(w, createEnvFailure(codes.AlreadyExists, req.EnvID, fmt.Sprintf("Environment %s already exists", req.EnvID)))
+		return
+	}
+	if sess != nil && !sess.own(api.environments, key, api.telemetry) {
+		api.writeError(w, fmt.Sprintf("Session %s closed", sess.ID), http.StatusNotFound)
+		return
+	}
+	// 并发创建时复核数量上限
+	err = api.limits.checkCounts(api.environments, sess, client, 0)
+	if err == nil {
+		err = tenant.checkCounts(api.environments, sess, client, 0)
+	}
+	if err != nil {
+		discard(api.environments, sess, key, api.telemetry)
+		api.writeError(w, err.Error(), http.StatusTooManyRequests)
+		return
+	}
 
 	response := CreateEnvResponse{
 		Success: true,
@@ -216,30 +702,46 @@ func (api *GymAPI) handleReset(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	env, exists := api.environments[req.EnvID]
+	key, _, ok := api.envKey(w, r, req.EnvID)
+	if !ok {
+		return
+	}
+	entry, exists := api.environments.entry(key)
 	if !exists {
-		api.writeError(w, fmt.Sprintf("Environment %s not found", req.EnvID), http.StatusNotFound)
+		api.writeEnvNotFound(w, req.EnvID)
 		return
 	}
+	env := entry.env
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	// 客户端断开不中断仿真，但步进仍归属于请求的span
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), 30*time.Second)
 	defer cancel()
 
+	entry.mu.Lock()
 	observations, err := env.Reset(ctx)
+	if err == nil && entry.truncation != nil {
+		entry.truncation.Reset()
+	}
+	entry.mu.Unlock()
 	if err != nil {
-		api.writeError(w, fmt.Sprintf("Failed to reset environment: %v", err), http.StatusInternalServerError)
+		api.writeEnvFailed(w, req.EnvID, "reset", err)
 		return
 	}
+	entry.stats.reset()
 
-	// 转换观察为JSON格式
-	obsData := make([][]float64, len(observations))
-	for i, obs := range observations {
-		obsData[i] = obs.GetData()
-	}
+	// 响应编码完成后归还对象池中的观察
+	defer core.ReleaseObservations(observations)
 
-	response := ResetResponse{
-		Observation: obsData,
-		Info:        env.GetInfo(),
+	response := ResetResponse{Info: env.GetInfo(), AgentIDs: entry.agentNames(len(observations))}
+	response.Observation, response.Text, response.Image = jsonObservations(observations)
+	result, err := entry.stepResult(observations, nil, nil, nil)
+	if err != nil {
+		api.writeError(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if result != nil {
+		response.Agents, response.Observation, response.Text, response.Image = jsonAgents(result), nil, nil, nil
+		response.AgentIDs = nil
 	}
 
 	api.writeJSON(w, response)
@@ -250,6 +752,7 @@ func (api *GymAPI) handleStep(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
+	start := time.Now()
 
 	var req StepRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -257,42 +760,154 @@ func (api *GymAPI) handleStep(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	env, exists := api.environments[req.EnvID]
+	key, _, ok := api.envKey(w, r, req.EnvID)
+	if !ok {
+		return
+	}
+	entry, exists := api.environments.entry(key)
 	if !exists {
-		api.writeError(w, fmt.Sprintf("Environment %s not found", req.EnvID), http.StatusNotFound)
+		api.writeEnvNotFound(w, req.EnvID)
 		return
 	}
+	env := entry.env
 
 	// 转换action为对应场景的Action类型
-	actions, err := api.convertActions(req.Action)
+	var actions []core.Action
+	var err error
+	switch {
+	case len(req.Values) > 0:
+		actions, err = rawActions(env.GetSpaces().ActionSpace, req.Values)
+	case len(req.Text) > 0:
+		actions = textActions(req.Text)
+		err = checkActions(env.GetSpaces().ActionSpace, actions)
+	default:
+		if actions, err = api.convertActions(env.GetSpaces().ActionSpace, req.Action); err == nil {
+			err = checkActions(env.GetSpaces().ActionSpace, actions)
+		}
+	}
 	if err != nil {
-		api.writeError(w, fmt.Sprintf("Failed to convert actions: %v", err), http.StatusBadRequest)
+		api.writeErrorDetails(w, fmt.Sprintf("Invalid action: %v", err), http.StatusBadRequest, map[string]string{"env_id": req.EnvID})
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	// 客户端断开不中断仿真，但步进仍归属于请求的span
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), 30*time.Second)
 	defer cancel()
 
+	entry.mu.Lock()
+	stepStart := time.Now()
 	observations, rewards, done, err := env.Step(ctx, actions)
 	if err != nil {
-		api.writeError(w, fmt.Sprintf("Failed to step environment: %v", err), http.StatusInternalServerError)
+		entry.mu.Unlock()
+		api.writeEnvFailed(w, req.EnvID, "step", err)
 		return
 	}
+	api.latency.Env.Observe(entry.scenario, time.Since(stepStart))
+	entry.stats.step()
 
-	// 转换观察为JSON格式
-	obsData := make([][]float64, len(observations))
-	for i, obs := range observations {
-		obsData[i] = obs.GetData()
+	response := StepResponse{
+		Reward: rewards,
+		Done:   done,
+		Info:   env.GetInfo(),
+	}
+	var terminated, truncated []bool
+	if entry.truncation != nil {
+		terminated, truncated = entry.truncation.Step(done, response.Info)
+	}
+	observations, err = entry.resetIfDone(ctx, observations, done, response.Info)
+	if err != nil {
+		entry.mu.Unlock()
+		api.writeEnvFailed(w, req.EnvID, "auto-reset", err)
+		return
 	}
 
-	response := StepResponse{
-		Observation: obsData,
-		Reward:      rewards,
-		Done:        done,
-		Info:        env.GetInfo(),
+	response.Observation, response.Text, response.Image = jsonObservations(observations)
+	response.AgentIDs = entry.agentNames(len(observations))
+	// 响应编码完成后归还对象池中的观察
+	defer core.ReleaseObservations(observations)
+
+	if entry.gymnasium {
+		response.Terminated, response.Truncated = terminated, truncated
+	}
+	if entry.dmEnv {
+		var types []core.StepType
+		types, response.Discount = core.StepTransitions(terminated, truncated)
+		response.StepType = make([]string, len(types))
+		for i, t := range types {
+			response.StepType[i] = t.String()
+		}
+	}
+	result, err := entry.stepResult(observations, rewards, terminated, truncated)
+	entry.mu.Unlock()
+	if err != nil {
+		api.writeError(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if result != nil {
+		response.Agents = jsonAgents(result)
+		response.Observation, response.Text, response.Image = nil, nil, nil
+		response.Reward, response.Done, response.AgentIDs = nil, nil, nil
+		response.Terminated, response.Truncated = nil, nil
 	}
 
 	api.writeJSON(w, response)
+	api.latency.Request.Observe(entry.scenario, time.Since(start))
+}
+
+// jsonAgents 将core.StepResult转换为以智能体名称为键的AgentStep
+func jsonAgents(result *core.StepResult) map[string]AgentStep {
+	agents := make(map[string]AgentStep, len(result.Agents))
+	for _, name := range result.Agents {
+		data, texts, images := jsonObservations([]core.Observation{result.Observations[name]})
+		step := AgentStep{
+			Observation: data[0],
+			Reward:      result.Rewards[name],
+			Terminated:  result.Terminated[name],
+			Truncated:   result.Truncated[name],
+			Info:        result.Infos[name],
+		}
+		if texts != nil {
+			step.Text = texts[0]
+		}
+		if images != nil {
+			step.Image = images[0]
+		}
+		agents[name] = step
+	}
+	return agents
+}
+
+// textActions 将各智能体的文本转换为Text动作
+func textActions(texts []string) []core.Action {
+	actions := make([]core.Action, len(texts))
+	for i, text := range texts {
+		actions[i] = core.NewGenericAction(text)
+	}
+	return actions
+}
+
+// jsonObservations 将观察转换为JSON格式：各智能体的数值数据，以及文本观察与图像观察（没有时为nil）。
+// 图像观察的数值数据为空，像素只在图像中以base64返回
+func jsonObservations(observations []core.Observation) (data [][]float64, texts []string, images []*ImageData) {
+	data = make([][]float64, len(observations))
+	for i, obs := range observations {
+		if text, ok := core.ObservationText(obs); ok {
+			if texts == nil {
+				texts = make([]string, len(observations))
+			}
+			texts[i] = text
+		}
+		if pixels, height, width, channels, ok := core.ObservationImage(obs); ok {
+			if images == nil {
+				images = make([]*ImageData, len(observations))
+			}
+			images[i] = &ImageData{Pixels: pixels, Height: height, Width: width, Channels: channels}
+			data[i] = []float64{}
+			continue
+		}
+		data[i] = obs.GetData()
+	}
+	return data, texts, images
 }
 
 func (api *GymAPI) handleClose(w http.ResponseWriter, r *http.Request) {
@@ -309,9 +924,13 @@ func (api *GymAPI) handleClose(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	env, exists := api.environments[req.EnvID]
+	key, sess, ok := api.envKey(w, r, req.EnvID)
+	if !ok {
+		return
+	}
+	env, exists := api.environments.Get(key)
 	if !exists {
-		api.writeError(w, fmt.Sprintf("Environment %s not found", req.EnvID), http.StatusNotFound)
+		api.writeEnvNotFound(w, req.EnvID)
 		return
 	}
 
@@ -320,8 +939,11 @@ func (api *GymAPI) handleClose(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	delete(api.environments, req.EnvID)
-	delete(api.configs, req.EnvID)
+	api.environments.Remove(key)
+	api.telemetry.reportClosed(key)
+	if sess != nil {
+		sess.untrack(key)
+	}
 
 	response := map[string]interface{}{
 		"success": true,
@@ -331,37 +953,677 @@ func (api *GymAPI) handleClose(w http.ResponseWriter, r *http.Request) {
 	api.writeJSON(w, response)
 }
 
-func (api *GymAPI) convertActions(actionData map[string]interface{}) ([]core.Action, error) {
-	// 支持多种场景的action转换
+func (api *GymAPI) handleMetadata(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
 
-	// 尝试解析为简单场景的action
-	if value, ok := actionData["value"]; ok {
-		if val, ok := value.(float64); ok {
-			action := simple.NewSimpleAction(val)
-			return []core.Action{action}, nil
-		} else {
-			return nil, fmt.Errorf("invalid value type for simple action")
-		}
+	var req struct {
+		EnvID string `json:"env_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		api.writeError(w, "Invalid JSON", http.StatusBadRequest)
+		return
 	}
 
-	return nil, fmt.Errorf("unsupported action format, expected 'sku_actions' or 'value' field")
+	key, _, ok := api.envKey(w, r, req.EnvID)
+	if !ok {
+		return
+	}
+	env, exists := api.environments.Get(key)
+	if !exists {
+		api.writeEnvNotFound(w, req.EnvID)
+		return
+	}
+
+	api.writeJSON(w, core.GetEnvMetadata(env))
 }
 
-func (api *GymAPI) writeJSON(w http.ResponseWriter, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(data); err != nil {
-		log.Printf("Failed to encode JSON: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+func (api *GymAPI) handleSpaces(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		EnvID string `json:"env_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		api.writeError(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	key, _, ok := api.envKey(w, r, req.EnvID)
+	if !ok {
+		return
+	}
+	env, exists := api.environments.Get(key)
+	if !exists {
+		api.writeEnvNotFound(w, req.EnvID)
+		return
 	}
+
+	api.writeJSON(w, env.GetSpaces())
 }
 
-func (api *GymAPI) writeError(w http.ResponseWriter, message string, code int) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(code)
-	response := map[string]interface{}{
-		"error":   true,
-		"message": message,
-		"code":    code,
+func (api *GymAPI) handleRecord(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	// 录制会在服务端写文件，需要管理令牌
+	if !api.requireAdmin(w, r) {
+		return
+	}
+
+	var req RecordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		api.writeError(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	var path string
+	if req.Path != "" {
+		var err error
+		if path, err = outputPath(api.telemetry.outputDir, req.Path); err != nil {
+			status := http.StatusBadRequest
+			if errors.Is(err, errNoOutputDir) {
+				status = http.StatusPreconditionFailed
+			}
+			api.writeError(w, err.Error(), status)
+			return
+		}
+	}
+
+	key, _, ok := api.envKey(w, r, req.EnvID)
+	if !ok {
+		return
 	}
-	json.NewEncoder(w).Encode(response)
+	entry, exists := api.environments.entry(key)
+	if !exists {
+		api.writeEnvNotFound(w, req.EnvID)
+		return
+	}
+
+	// 持有环境锁停止与替换录制器，避免并发的Step写入已关闭的录制器
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	// 加锁后重新读取环境，等待期间它可能已被其他/record请求替换
+	env, exists := api.environments.Get(key)
+	if !exists {
+		api.writeEnvNotFound(w, req.EnvID)
+		return
+	}
+
+	// 先结束已有的录制，再按需录制到新文件
+	if recorder, ok := env.(*record.Recorder); ok {
+		if err := recorder.Stop(); err != nil {
+			api.telemetry.log().Warn("Failed to finish recording", "env_id", req.EnvID, "error", err)
+		}
+		env = recorder.Unwrap()
+		api.environments.Replace(key, env)
+	}
+
+	message := fmt.Sprintf("Recording of %s stopped", req.EnvID)
+	if path != "" {
+		recorder, err := record.NewFile(env, path)
+		if err != nil {
+			api.writeError(w, fmt.Sprintf("Failed to start recording: %v", err), http.StatusInternalServerError)
+			return
+		}
+		api.environments.Replace(key, recorder)
+		message = fmt.Sprintf("Recording %s to %s", req.EnvID, req.Path)
+	}
+
+	api.writeJSON(w, map[string]interface{}{
+		"success": true,
+		"message": message,
+	})
+}
+
+// handleCurriculum 返回环境的课程进度
+func (api *GymAPI) handleCurriculum(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		EnvID string `json:"env_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		api.writeError(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	c, ok := api.curriculum(w, r, req.EnvID)
+	if !ok {
+		return
+	}
+	api.writeJSON(w, c.Progress())
+}
+
+// handleCurriculumStage 切换环境的课程阶段，新阶段的参数在下次Reset时生效
+func (api *GymAPI) handleCurriculumStage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req CurriculumStageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		api.writeError(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	c, ok := api.curriculum(w, r, req.EnvID)
+	if !ok {
+		return
+	}
+	if err := c.SetStage(req.Stage, req.Frozen); err != nil {
+		api.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	api.writeJSON(w, c.Progress())
+}
+
+// curriculum 返回环境的课程学习包装器，环境不存在或未以curriculum配置创建时写入错误响应
+func (api *GymAPI) curriculum(w http.ResponseWriter, r *http.Request, envID string) (*curriculum.Curriculum, bool) {
+	key, _, ok := api.envKey(w, r, envID)
+	if !ok {
+		return nil, false
+	}
+	env, exists := api.environments.Get(key)
+	if !exists {
+		api.writeEnvNotFound(w, envID)
+		return nil, false
+	}
+	c, ok := curriculum.Find(env)
+	if !ok {
+		api.writeError(w, fmt.Sprintf("Environment %s has no curriculum", envID), http.StatusBadRequest)
+		return nil, false
+	}
+	return c, true
+}
+
+// handleRuns 查询运行存储：不带id时按scenario、env_id、active、limit筛选运行及汇总统计，
+// 带id时返回该运行及其回合
+func (api *GymAPI) handleRuns(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	// 运行记录包含所有租户的环境，配置租户时只对管理员开放
+	if api.tenants != nil && !api.checkAdmin(w, r) {
+		return
+	}
+	store := api.telemetry.runs
+	if store == nil {
+		api.writeError(w, "Run store is not enabled", http.StatusNotFound)
+		return
+	}
+
+	query := r.URL.Query()
+	if id := query.Get("id"); id != "" {
+		runID, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			api.writeError(w, fmt.Sprintf("Invalid run id %q", id), http.StatusBadRequest)
+			return
+		}
+		run, err := store.Run(runID)
+		if errors.Is(err, sql.ErrNoRows) {
+			api.writeError(w, fmt.Sprintf("Run %d not found", runID), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			api.writeError(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		episodes, err := store.Episodes(runID, 0)
+		if err != nil {
+			api.writeError(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		api.writeJSON(w, map[string]interface{}{"run": run, "episodes": episodes})
+		return
+	}
+
+	filter := runstore.RunFilter{
+		Scenario: query.Get("scenario"),
+		EnvID:    query.Get("env_id"),
+		Active:   query.Get("active") == "true",
+	}
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil {
+			api.writeError(w, fmt.Sprintf("Invalid limit %q", limit), http.StatusBadRequest)
+			return
+		}
+		filter.Limit = n
+	}
+	runs, err := store.Runs(filter)
+	if err != nil {
+		api.writeError(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	api.writeJSON(w, map[string]interface{}{"runs": runs})
+}
+
+// handleStats 返回回合统计，可按scenario与env_id（完整的注册表键）过滤
+func (api *GymAPI) handleStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	// 统计包含所有租户的环境，配置租户时只对管理员开放
+	if api.tenants != nil && !api.checkAdmin(w, r) {
+		return
+	}
+	stats := api.telemetry.stats
+	query := r.URL.Query()
+	scenario, envID := query.Get("scenario"), query.Get("env_id")
+	response := StatsResponse{
+		Window:       stats.Window(),
+		Scenarios:    stats.Scenarios(),
+		Environments: stats.Environments(),
+	}
+	if scenario != "" {
+		for name := range response.Scenarios {
+			if name != scenario {
+				delete(response.Scenarios, name)
+			}
+		}
+	}
+	for key, summary := range response.Environments {
+		if (scenario != "" && summary.Scenario != scenario) || (envID != "" && key != envID) {
+			delete(response.Environments, key)
+		}
+	}
+	api.writeJSON(w, response)
+}
+
+// handleOpenSession 打开会话，HTTP会话在空闲超过TTL后过期并关闭其环境
+func (api *GymAPI) handleOpenSession(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req OpenSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		api.writeError(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	tenant, ok := api.tenant(w, r)
+	if !ok {
+		return
+	}
+	sess := api.sessions.Open(tenant.namespace(), req.Client, time.Duration(req.TTLSeconds)*time.Second, 0)
+	api.writeJSON(w, OpenSessionResponse{
+		SessionID:  sess.ID,
+		TTLSeconds: int(sess.TTL / time.Second),
+	})
+}
+
+// handleRegisterScenario 编译上传的WASM模块（JSON中base64编码）并注册为场景；需要管理令牌，场景名不能与已有的场景重名
+func (api *GymAPI) handleRegisterScenario(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	// 上传的模块会在服务端执行，服务端未设置管理令牌时同样拒绝
+	if !api.requireAdmin(w, r) {
+		return
+	}
+
+	var req RegisterScenarioRequest
+	r.Body = http.MaxBytesReader(w, r.Body, 2*MaxWasmModuleSize)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		api.writeError(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	tenant, ok := api.tenant(w, r)
+	if !ok {
+		return
+	}
+	if err := checkCreate(api.scenarios, tenant, req.Name); err != nil {
+		api.writeError(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	details := map[string]string{"scenario": req.Name}
+	err := registerWasmScenario(api.engine, api.wasmLimits, req)
+	switch {
+	case errors.Is(err, errWasmDisabled):
+		api.writeErrorDetails(w, err.Error(), http.StatusForbidden, details)
+		return
+	case errors.Is(err, errScenarioExists):
+		api.writeErrorDetails(w, err.Error(), http.StatusConflict, details)
+		return
+	case err != nil:
+		api.writeErrorDetails(w, err.Error(), http.StatusBadRequest, details)
+		return
+	}
+	api.telemetry.log().Info("WASM scenario registered", "scenario", req.Name, "client", clientHost(r.RemoteAddr), "bytes", len(req.Wasm))
+	api.writeJSON(w, RegisterScenarioResponse{Scenario: req.Name})
+}
+
+// handleCloseSession 关闭会话并关闭其所有环境
+func (api *GymAPI) handleCloseSession(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req CloseSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		api.writeError(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	tenant, ok := api.tenant(w, r)
+	if !ok {
+		return
+	}
+	if sess, ok := api.sessions.lookup(req.SessionID); !ok || sess.Tenant != tenant.namespace() {
+		api.writeErrorDetails(w, fmt.Sprintf("Session %s not found", req.SessionID), http.StatusNotFound, map[string]string{"session_id": req.SessionID})
+		return
+	}
+	closed, ok := api.sessions.Close(req.SessionID)
+	if !ok {
+		api.writeErrorDetails(w, fmt.Sprintf("Session %s not found", req.SessionID), http.StatusNotFound, map[string]string{"session_id": req.SessionID})
+		return
+	}
+	api.writeJSON(w, CloseSessionResponse{ClosedEnvironments: closed})
+}
+
+// handleAdminEnvs 列出全部环境（含各会话的环境）的所属会话、客户端、存活时间与步数
+func (api *GymAPI) handleAdminEnvs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !api.checkAdmin(w, r) {
+		return
+	}
+	api.writeJSON(w, AdminEnvsResponse{
+		Environments: envStatuses(api.environments, api.sessions),
+		Draining:     api.draining.Load(),
+	})
+}
+
+// handleAdminClose 强制关闭环境，env_id为注册表中的完整键，不受会话限制
+func (api *GymAPI) handleAdminClose(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !api.checkAdmin(w, r) {
+		return
+	}
+
+	var req struct {
+		EnvID string `json:"env_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		api.writeError(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if !forceClose(api.environments, api.sessions, req.EnvID, api.telemetry) {
+		api.writeEnvNotFound(w, req.EnvID)
+		return
+	}
+	api.telemetry.log().Info("Environment force-closed by admin", "env_id", req.EnvID)
+	api.writeJSON(w, map[string]interface{}{
+		"success": true,
+		"message": fmt.Sprintf("Environment %s closed successfully", req.EnvID),
+	})
+}
+
+// handleAdminState 以JSON导出环境的内部状态（core.DumpState）
+func (api *GymAPI) handleAdminState(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !api.checkAdmin(w, r) {
+		return
+	}
+
+	var req struct {
+		EnvID string `json:"env_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		api.writeError(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	state, exists := dumpState(api.environments, req.EnvID)
+	if !exists {
+		api.writeEnvNotFound(w, req.EnvID)
+		return
+	}
+	api.writeJSON(w, state)
+}
+
+// handleAdminDrain 停止创建新环境，等待已有环境关闭，force时随后强制关闭剩余环境
+func (api *GymAPI) handleAdminDrain(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !api.checkAdmin(w, r) {
+		return
+	}
+
+	var req DrainRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		api.writeError(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	remaining, closed := api.DrainEnvironments(time.Duration(req.TimeoutSeconds*float64(time.Second)), req.Force)
+	api.telemetry.log().Info("Draining", "remaining", remaining, "force_closed", closed)
+	api.writeJSON(w, DrainResponse{RemainingEnvironments: remaining, ClosedEnvironments: closed})
+}
+
+// checkAdmin 校验X-Admin-Token头中的管理令牌，失败时写入401响应并返回false
+func (api *GymAPI) checkAdmin(w http.ResponseWriter, r *http.Request) bool {
+	if err := checkAdminToken(api.adminToken, r.Header.Get(AdminTokenHeader)); err != nil {
+		api.writeError(w, err.Error(), http.StatusUnauthorized)
+		return false
+	}
+	return true
+}
+
+// requireAdmin 与checkAdmin相同，但服务端未设置管理令牌时写入412响应，用于在服务端写文件或执行上传代码的请求
+func (api *GymAPI) requireAdmin(w http.ResponseWriter, r *http.Request) bool {
+	if err := requireAdminToken(api.adminToken, r.Header.Get(AdminTokenHeader)); err != nil {
+		status := http.StatusUnauthorized
+		if errors.Is(err, errNoAdminToken) {
+			status = http.StatusPreconditionFailed
+		}
+		api.writeError(w, err.Error(), status)
+		return false
+	}
+	return true
+}
+
+// tenant 按X-API-Key头返回请求所属的租户（未配置租户时为nil）；密钥无效时写入401响应并返回false
+func (api *GymAPI) tenant(w http.ResponseWriter, r *http.Request) (*Tenant, bool) {
+	tenant, err := api.tenants.resolve(r.Header.Get(APIKeyHeader))
+	if err != nil {
+		api.writeError(w, err.Error(), http.StatusUnauthorized)
+		return nil, false
+	}
+	return tenant, true
+}
+
+// envKey 按X-API-Key与X-Session-Id头返回env_id在注册表中的键及其所属会话；密钥或会话无效时写入错误响应并返回false
+func (api *GymAPI) envKey(w http.ResponseWriter, r *http.Request, envID string) (string, *Session, bool) {
+	tenant, ok := api.tenant(w, r)
+	if !ok {
+		return "", nil, false
+	}
+	key, sess, err := api.sessions.scope(tenant.namespace(), r.Header.Get(SessionHeader), envID)
+	if err != nil {
+		api.writeError(w, err.Error(), http.StatusNotFound)
+		return "", nil, false
+	}
+	traceEnvID(r.Context(), envID)
+	return key, sess, true
+}
+
+func (api *GymAPI) convertActions(space core.ActionSpace, actionData map[string]interface{}) ([]core.Action, error) {
+	// 支持多种场景的action转换
+
+	// Dict动作空间：action的各键为命名分量
+	if space.Type == core.SpaceTypeDict {
+		action, err := jsonDictAction(actionData)
+		if err != nil {
+			return nil, err
+		}
+		return []core.Action{action}, nil
+	}
+
+	// Hybrid动作空间：action为{"choice": 下标, "parameters": [所选动作的参数...]}
+	if space.Type == core.SpaceTypeHybrid {
+		action, err := jsonHybridAction(actionData)
+		if err != nil {
+			return nil, err
+		}
+		return []core.Action{action}, nil
+	}
+
+	// 尝试解析为简单场景的action
+	if value, ok := actionData["value"]; ok {
+		if val, ok := value.(float64); ok {
+			action := simple.NewSimpleAction(val)
+			return []core.Action{action}, nil
+		} else {
+			return nil, fmt.Errorf("invalid value type for simple action")
+		}
+	}
+
+	return nil, fmt.Errorf("unsupported action format, expected 'sku_actions' or 'value' field")
+}
+
+// jsonDictAction 将JSON对象转换为DictAction：数值为float64，字符串为string，布尔值为bool，
+// 数组为[]float64，对象递归转换为嵌套的DictAction
+func jsonDictAction(data map[string]interface{}) (*core.DictAction, error) {
+	components := make(map[string]core.Action, len(data))
+	for name, v := range data {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			component, err := jsonDictAction(v)
+			if err != nil {
+				return nil, fmt.Errorf("component %q: %w", name, err)
+			}
+			components[name] = component
+		case []interface{}:
+			values, err := jsonArray(v)
+			if err != nil {
+				return nil, fmt.Errorf("component %q: %w", name, err)
+			}
+			components[name] = core.NewGenericAction(values)
+		case float64, string, bool:
+			components[name] = core.NewGenericAction(v)
+		default:
+			return nil, fmt.Errorf("component %q has unsupported value %v", name, v)
+		}
+	}
+	action := core.NewDictAction(components)
+	if err := action.Validate(); err != nil {
+		return nil, err
+	}
+	return action, nil
+}
+
+// jsonHybridAction 将{"choice": 下标, "parameters": [...]}转换为HybridAction，无参数的动作可以省略parameters
+func jsonHybridAction(data map[string]interface{}) (*core.HybridAction, error) {
+	for key := range data {
+		if key != "choice" && key != "parameters" {
+			return nil, fmt.Errorf("hybrid action has unknown field %q, expected choice and parameters", key)
+		}
+	}
+	choice, ok := data["choice"].(float64)
+	if !ok || choice != math.Trunc(choice) {
+		return nil, fmt.Errorf("hybrid action choice must be an integer, got %v", data["choice"])
+	}
+	var parameters []float64
+	switch v := data["parameters"].(type) {
+	case nil:
+	case float64:
+		parameters = []float64{v}
+	case []interface{}:
+		var err error
+		if parameters, err = jsonArray(v); err != nil {
+			return nil, fmt.Errorf("hybrid action parameters: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("hybrid action parameters must be an array of numbers, got %v", v)
+	}
+	return core.NewHybridAction(int(choice), parameters), nil
+}
+
+// jsonArray 将JSON数组转换为[]float64，与ActionValues相同，布尔元素转换为1/0
+func jsonArray(data []interface{}) ([]float64, error) {
+	values := make([]float64, len(data))
+	for i, v := range data {
+		switch v := v.(type) {
+		case float64:
+			values[i] = v
+		case bool:
+			if v {
+				values[i] = 1
+			}
+		default:
+			return nil, fmt.Errorf("array element %d must be a number or a boolean, got %v", i, v)
+		}
+	}
+	return values, nil
+}
+
+// writeJSON 以规范JSON（见encodeJSON）写入响应
+func (api *GymAPI) writeJSON(w http.ResponseWriter, data interface{}) {
+	body, err := encodeJSON(data)
+	if err != nil {
+		api.telemetry.log().Warn("Failed to encode JSON", "error", err)
+		api.writeError(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(append(body, '\n'))
+}
+
+// internalErrorBody 响应无法编码时的500错误响应体，与writeError的编码结果相同
+var internalErrorBody = []byte(`{"error":true,"message":"Internal server error","code":500,"status":"INTERNAL"}`)
+
+func (api *GymAPI) writeError(w http.ResponseWriter, message string, code int) {
+	api.writeErrorDetails(w, message, code, nil)
+}
+
+// writeErrorDetails 写入错误响应，Status由HTTP状态码对应的gRPC状态码得出，details为附加的键值
+func (api *GymAPI) writeErrorDetails(w http.ResponseWriter, message string, code int, details map[string]string) {
+	body, err := encodeJSON(ErrorResponse{Error: true, Message: message, Code: code, Status: errorCode(httpErrorCode(code)), Details: details})
+	if err != nil {
+		api.telemetry.log().Warn("Failed to encode JSON", "error", err)
+		body, code = internalErrorBody, http.StatusInternalServerError
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	w.Write(append(body, '\n'))
+}
+
+// writeEnvFailed 写入环境Reset/Step失败的错误响应，op为reset或step，状态码由envErrorStatus得出
+func (api *GymAPI) writeEnvFailed(w http.ResponseWriter, envID, op string, err error) {
+	_, code := envErrorStatus(err)
+	api.writeErrorDetails(w, fmt.Sprintf("Failed to %s environment: %v", op, err), code, map[string]string{"env_id": envID})
+}
+
+// writeEnvNotFound 写入环境不存在的错误响应
+func (api *GymAPI) writeEnvNotFound(w http.ResponseWriter, envID string) {
+	api.writeErrorDetails(w, fmt.Sprintf("Environment %s not found", envID), http.StatusNotFound, map[string]string{"env_id": envID})
 }
@@ -0,0 +1,151 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jelech/rl_env_engine/core"
+	"github.com/jelech/rl_env_engine/scenarios"
+)
+
+// BenchmarkGymAPIStep 测量每个内置场景经Gym风格JSON接口（回环HTTP）单步的耗时与内存分配，
+// 分配数包括同一进程中的服务端；动作以平铺的values发送，回合结束时调用/reset（计入耗时）
+func BenchmarkGymAPIStep(b *testing.B) {
+	discardLogs(b)
+	srv := httptest.NewServer(NewGymAPI().Handler())
+	defer srv.Close()
+	client := srv.Client()
+	var buf bytes.Buffer
+	post := func(path string, request, response interface{}) error {
+		buf.Reset()
+		if err := json.NewEncoder(&buf).Encode(request); err != nil {
+			return err
+		}
+		resp, err := client.Post(srv.URL+path, "application/json", &buf)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			return fmt.Errorf("%s: %d %s", path, resp.StatusCode, body)
+		}
+		return json.NewDecoder(resp.Body).Decode(response)
+	}
+
+	for _, s := range scenarios.All() {
+		scenario := s.GetName()
+		b.Run(scenario, func(b *testing.B) {
+			actions := sampleBenchActions(b, scenario)
+			values := make([]ActionValues, len(actions))
+			for i, set := range actions {
+				for _, action := range set {
+					v, err := benchActionValues(action.GetData())
+					if err != nil {
+						b.Skip(err)
+					}
+					values[i] = append(values[i], v...)
+				}
+			}
+
+			var created CreateEnvResponse
+			if err := post("/create", CreateEnvRequest{EnvID: scenario, Scenario: scenario}, &created); err != nil || !created.Success {
+				b.Fatalf("create: %v %s", err, created.Message)
+			}
+			defer post("/close", map[string]string{"env_id": scenario}, &map[string]interface{}{})
+			var reset ResetResponse
+			if err := post("/reset", ResetRequest{EnvID: scenario}, &reset); err != nil {
+				b.Fatal(err)
+			}
+			step := func(values ActionValues) error {
+				var resp StepResponse
+				if err := post("/step", StepRequest{EnvID: scenario, Values: values}, &resp); err != nil {
+					return err
+				}
+				if core.AllDone(resp.Done) {
+					return post("/reset", ResetRequest{EnvID: scenario}, &reset)
+				}
+				return nil
+			}
+			for _, v := range values {
+				if err := step(v); err != nil {
+					b.Skipf("random actions: %v", err)
+				}
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := step(values[i%len(values)]); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// discardLogs 在基准期间丢弃服务端按请求打印的日志
+func discardLogs(b *testing.B) {
+	out := log.Writer()
+	log.SetOutput(io.Discard)
+	b.Cleanup(func() { log.SetOutput(out) })
+}
+
+// sampleBenchActions 用场景的进程内环境以默认配置采样256组随机动作，计时期间循环使用；
+// 需要配置才能创建的场景跳过
+func sampleBenchActions(b *testing.B, scenario string) [][]core.Action {
+	b.Helper()
+	env, err := newBuiltinEngine().CreateEnvironment(scenario, core.NewBaseConfig(map[string]interface{}{}))
+	if err != nil {
+		b.Skipf("default config: %v", err)
+	}
+	defer env.Close()
+	observations, err := env.Reset(context.Background())
+	if err != nil {
+		b.Fatalf("reset: %v", err)
+	}
+	defer core.ReleaseObservations(observations)
+	rng := rand.New(rand.NewSource(1))
+	actions := make([][]core.Action, 256)
+	for i := range actions {
+		if actions[i], err = core.SampleActions(env, env.GetSpaces().ActionSpace, len(observations), rng); err != nil {
+			b.Skipf("sample actions: %v", err)
+		}
+	}
+	return actions
+}
+
+// benchActionValues 将采样的动作平铺为/step与/step_raw接受的values
+func benchActionValues(data interface{}) ([]float64, error) {
+	switch v := data.(type) {
+	case int:
+		return []float64{float64(v)}, nil
+	case float64:
+		return []float64{v}, nil
+	case []float64:
+		return v, nil
+	case []int:
+		values := make([]float64, len(v))
+		for i, x := range v {
+			values[i] = float64(x)
+		}
+		return values, nil
+	case []bool:
+		values := make([]float64, len(v))
+		for i, x := range v {
+			if x {
+				values[i] = 1
+			}
+		}
+		return values, nil
+	}
+	return nil, fmt.Errorf("action %T cannot be sent as values", data)
+}
@@ -0,0 +1,296 @@
+package server
+
+import (
+	"encoding"
+	"encoding/json"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// encodeJSON 按HTTP API的规范JSON编码v，GymAPI的所有响应都经由它输出：
+//   - 键为字段json标签中的名称，统一为snake_case（由cmd/gen_pyschema -check检查）；
+//   - 带omitempty的字段为空时省略，其余字段总是出现：nil的切片与映射编码为[]与{}，
+//     只有指针与接口字段以null表示没有值，Python客户端因此不必区分缺失、null与空集合；
+//   - 浮点数按其位宽编码为能还原原值的最短表示（float32不会展开为float64的尾数），-0编码为0，
+//     NaN与±Inf编码为null，与core/record.Floats一致，而不是让整个响应编码失败
+//
+// 实现了json.Marshaler或encoding.TextMarshaler的类型仍由其自身编码
+func encodeJSON(v interface{}) ([]byte, error) {
+	e := &jsonEncoder{}
+	if err := e.value(reflect.ValueOf(v)); err != nil {
+		return nil, err
+	}
+	return e.buf, nil
+}
+
+type jsonEncoder struct {
+	buf []byte
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+func (e *jsonEncoder) value(v reflect.Value) error {
+	if !v.IsValid() {
+		e.buf = append(e.buf, "null"...)
+		return nil
+	}
+	if v.Kind() != reflect.Pointer && v.CanAddr() && marshals(reflect.PointerTo(v.Type())) {
+		v = v.Addr()
+	}
+	if marshals(v.Type()) {
+		if (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && v.IsNil() {
+			e.buf = append(e.buf, "null"...)
+			return nil
+		}
+		return e.marshal(v.Interface())
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		e.buf = strconv.AppendBool(e.buf, v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		e.buf = strconv.AppendInt(e.buf, v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		e.buf = strconv.AppendUint(e.buf, v.Uint(), 10)
+	case reflect.Float32:
+		e.buf = appendJSONFloat(e.buf, v.Float(), 32)
+	case reflect.Float64:
+		e.buf = appendJSONFloat(e.buf, v.Float(), 64)
+	case reflect.String:
+		return e.marshal(v.String())
+	case reflect.Interface, reflect.Pointer:
+		if v.IsNil() {
+			e.buf = append(e.buf, "null"...)
+			return nil
+		}
+		return e.value(v.Elem())
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			if v.IsNil() {
+				e.buf = append(e.buf, `""`...)
+				return nil
+			}
+			// []byte与encoding/json一样编码为base64字符串
+			return e.marshal(v.Bytes())
+		}
+		return e.array(v)
+	case reflect.Array:
+		return e.array(v)
+	case reflect.Map:
+		return e.object(v)
+	case reflect.Struct:
+		return e.structFields(v)
+	default:
+		return &json.UnsupportedTypeError{Type: v.Type()}
+	}
+	return nil
+}
+
+// marshals 报告t是否自行编码为JSON
+func marshals(t reflect.Type) bool {
+	return t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType)
+}
+
+// marshal 用encoding/json编码v，用于字符串与自行编码的类型
+func (e *jsonEncoder) marshal(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	e.buf = append(e.buf, data...)
+	return nil
+}
+
+func (e *jsonEncoder) array(v reflect.Value) error {
+	e.buf = append(e.buf, '[')
+	for i := 0; i < v.Len(); i++ {
+		if i > 0 {
+			e.buf = append(e.buf, ',')
+		}
+		if err := e.value(v.Index(i)); err != nil {
+			return err
+		}
+	}
+	e.buf = append(e.buf, ']')
+	return nil
+}
+
+// object 按键排序编码映射，与encoding/json一致；键不是字符串或整数时交给encoding/json
+func (e *jsonEncoder) object(v reflect.Value) error {
+	keyKind := v.Type().Key().Kind()
+	if keyKind != reflect.String && (keyKind < reflect.Int || keyKind > reflect.Uint64) || marshals(v.Type().Key()) {
+		return e.marshal(v.Interface())
+	}
+	keys := make([]string, 0, v.Len())
+	values := make(map[string]reflect.Value, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		var key string
+		switch k := iter.Key(); {
+		case keyKind == reflect.String:
+			key = k.String()
+		case keyKind <= reflect.Int64:
+			key = strconv.FormatInt(k.Int(), 10)
+		default:
+			key = strconv.FormatUint(k.Uint(), 10)
+		}
+		keys = append(keys, key)
+		values[key] = iter.Value()
+	}
+	sort.Strings(keys)
+
+	e.buf = append(e.buf, '{')
+	for i, key := range keys {
+		if i > 0 {
+			e.buf = append(e.buf, ',')
+		}
+		if err := e.marshal(key); err != nil {
+			return err
+		}
+		e.buf = append(e.buf, ':')
+		if err := e.value(values[key]); err != nil {
+			return err
+		}
+	}
+	e.buf = append(e.buf, '}')
+	return nil
+}
+
+func (e *jsonEncoder) structFields(v reflect.Value) error {
+	e.buf = append(e.buf, '{')
+	first := true
+	for _, f := range jsonFieldsOf(v.Type()) {
+		fv, ok := fieldByIndex(v, f.index)
+		if !ok || f.omitEmpty && isEmptyJSONValue(fv) {
+			continue
+		}
+		if !first {
+			e.buf = append(e.buf, ',')
+		}
+		first = false
+		e.buf = append(e.buf, f.key...)
+		if err := e.value(fv); err != nil {
+			return err
+		}
+	}
+	e.buf = append(e.buf, '}')
+	return nil
+}
+
+// jsonField 结构体中参与编码的字段，key为已编码的"name":
+type jsonField struct {
+	index     []int
+	key       string
+	omitEmpty bool
+}
+
+var jsonFieldCache sync.Map // reflect.Type -> []jsonField
+
+// jsonFieldsOf 按encoding/json的规则列出t的字段：跳过未导出字段与json:"-"，展开没有标签的匿名结构体
+func jsonFieldsOf(t reflect.Type) []jsonField {
+	if cached, ok := jsonFieldCache.Load(t); ok {
+		return cached.([]jsonField)
+	}
+	var fields []jsonField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				for _, embedded := range jsonFieldsOf(ft) {
+					embedded.index = append([]int{i}, embedded.index...)
+					fields = append(fields, embedded)
+				}
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		key, _ := json.Marshal(name)
+		fields = append(fields, jsonField{
+			index:     []int{i},
+			key:       string(key) + ":",
+			omitEmpty: strings.Contains(opts, "omitempty"),
+		})
+	}
+	jsonFieldCache.Store(t, fields)
+	return fields
+}
+
+// fieldByIndex 与reflect.Value.FieldByIndex相同，但经过nil的嵌入指针时返回false
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// isEmptyJSONValue 报告omitempty是否省略v
+func isEmptyJSONValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Pointer:
+		return v.IsNil()
+	}
+	return false
+}
+
+// appendJSONFloat 以encoding/json的格式追加f的最短表示，非有限值追加null
+func appendJSONFloat(b []byte, f float64, bits int) []byte {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return append(b, "null"...)
+	}
+	if f == 0 {
+		// encoding/json将-0编码为-0，对Python客户端没有意义，统一为0
+		f = 0
+	}
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 {
+		if bits == 64 && (abs < 1e-6 || abs >= 1e21) || bits == 32 && (float32(abs) < 1e-6 || float32(abs) >= 1e21) {
+			format = 'e'
+		}
+	}
+	b = strconv.AppendFloat(b, f, format, -1, bits)
+	if format == 'e' {
+		// 与encoding/json一样把e-09写作e-9
+		if n := len(b); n >= 4 && b[n-4] == 'e' && b[n-3] == '-' && b[n-2] == '0' {
+			b[n-2] = b[n-1]
+			b = b[:n-1]
+		}
+	}
+	return b
+}
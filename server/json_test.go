@@ -0,0 +1,212 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/jelech/rl_env_engine/core"
+	"github.com/jelech/rl_env_engine/core/curriculum"
+	"github.com/jelech/rl_env_engine/core/metrics"
+	"google.golang.org/grpc/codes"
+)
+
+var update = flag.Bool("update", false, "rewrite testdata/responses.golden with the current encoding")
+
+// goldenResponses GymAPI的各种响应，编码结果与testdata/responses.golden逐个比较
+var goldenResponses = []struct {
+	name string
+	v    interface{}
+}{
+	{"create_env", CreateEnvResponse{Success: true, Message: "Environment e1 created successfully"}},
+	{"create_env_failure", createEnvFailure(codes.AlreadyExists, "e1", "Environment e1 already exists")},
+	{"reset", ResetResponse{
+		Observation: [][]float64{{0.5, -0.25, 1e-7, 1e21}},
+		AgentIDs:    []string{"agent_0"},
+		Info:        map[string]interface{}{"step": 0, "seed": int64(42)},
+	}},
+	{"reset_text", ResetResponse{Observation: [][]float64{{}}, Text: []string{"hello \"world\""}, Info: nil}},
+	{"reset_image", ResetResponse{
+		Observation: [][]float64{{}},
+		Image:       []*ImageData{{Pixels: []byte{0, 127, 255}, Height: 1, Width: 1, Channels: 3}},
+	}},
+	{"reset_agents", ResetResponse{Info: map[string]interface{}{}, Agents: map[string]AgentStep{
+		"prey":     {Observation: []float64{1, 2}},
+		"predator": {Observation: []float64{3, 4}, Info: map[string]interface{}{"action_mask": []bool{true, false}}},
+	}}},
+	{"step", StepResponse{
+		Observation: [][]float64{{math.Copysign(0, -1), float64(float32(0.1)), math.NaN()}},
+		Reward:      []float64{math.Inf(1)},
+		Done:        []bool{false},
+		Info:        map[string]interface{}{"float32": float32(0.1), "neg_zero": math.Copysign(0, -1), "nested": map[string]interface{}{"b": 2, "a": nil}},
+	}},
+	{"step_gymnasium_dm_env", StepResponse{
+		Observation: [][]float64{{1}, {2}},
+		AgentIDs:    []string{"a", "b"},
+		Reward:      []float64{1, -1},
+		Done:        []bool{true, true},
+		Terminated:  []bool{true, false},
+		Truncated:   []bool{false, true},
+		StepType:    []string{"LAST", "LAST"},
+		Discount:    []float64{0, 1},
+		Info:        map[string]interface{}{"terminal_observation": []interface{}{[]float64{0.5}, "text"}},
+	}},
+	{"step_agents", StepResponse{Agents: map[string]AgentStep{
+		"player_x": {Text: "X..", Reward: 1, Terminated: true},
+		"player_o": {Image: &ImageData{Pixels: []byte{}, Height: 0, Width: 0, Channels: 1}, Reward: -1, Truncated: true},
+	}}},
+	{"spaces", core.SpaceDefinition{
+		ActionSpace:      core.ActionSpace{Type: core.SpaceTypeDiscrete, Low: []float64{0}, High: []float64{1}, Shape: []int32{1}, Dtype: "int64"},
+		ObservationSpace: core.ObservationSpace{Type: core.SpaceTypeBox, Low: []float64{-1, -2}, High: []float64{1, 2}, Shape: []int32{2}, Dtype: "float32"},
+	}},
+	{"metadata", core.EnvMetadata{RewardRange: [2]float64{math.Inf(-1), 1}, MaxEpisodeSteps: 500, RenderModes: []string{"ansi", "rgb_array"}}},
+	{"curriculum", curriculum.Progress{Stage: 1, Stages: 3, Episodes: 10, StageEpisodes: 4, SuccessRate: 0.75, Window: 4,
+		Parameters: map[string]float64{"length": 2.5}}},
+	{"info", InfoResponse{
+		Scenarios:     []string{"cartpole", "simple"},
+		Presets:       []core.Preset{{Name: "cartpole-long", Scenario: "cartpole", Config: map[string]interface{}{"max_steps": 1000}}},
+		Info:          map[string]interface{}{"total_scenarios": 2, "active_environments": 0},
+		APIVersion:    APIVersion,
+		MinAPIVersion: MinAPIVersion,
+	}},
+	{"metrics", MetricsResponse{Transport: "http", StepLatency: map[string]metrics.ScenarioLatency{
+		"cartpole": {Step: metrics.LatencySummary{Count: 3, MeanMs: 0.012, P50Ms: 0.01, P95Ms: 0.02, P99Ms: 0.02, MaxMs: 0.02, PerSecond: 0.05}},
+	}}},
+	{"stats", StatsResponse{Window: 2,
+		Scenarios: map[string]metrics.EpisodeSummary{"cartpole": {Episodes: 2,
+			Returns: metrics.SeriesSummary{Mean: 15, Min: 10, Max: 20, RecentMean: 15, Recent: []float64{10, 20}},
+			Lengths: metrics.SeriesSummary{Mean: 15, Min: 10, Max: 20, RecentMean: 15, Recent: []float64{10, 20}}}},
+		Environments: map[string]metrics.EpisodeSummary{"e1": {Scenario: "cartpole"}},
+	}},
+	{"debug_env", EnvDebugDump{EnvID: "e1", Scenario: "cartpole", Steps: 5, Episodes: 1,
+		Wrappers:   []string{"*wrappers.TimeLimit", "*cartpole.CartPoleEnvironment"},
+		Checkpoint: json.RawMessage(`{"x":1}`), State: map[string]interface{}{"x": 1.5}}},
+	{"debug_env_no_checkpoint", EnvDebugDump{EnvID: "e2", Scenario: "simple", CheckpointError: "not a core.Checkpointer", Fault: "panic: boom"}},
+	{"open_session", OpenSessionResponse{SessionID: "s1", TTLSeconds: 300}},
+	{"close_session", CloseSessionResponse{ClosedEnvironments: 2}},
+	{"admin_envs", AdminEnvsResponse{Environments: []EnvStatus{{EnvID: "s1/e1", Scenario: "cartpole", SessionID: "s1",
+		Client: "127.0.0.1", AgeSeconds: 1.5, IdleSeconds: 0.25, Steps: 10, Episodes: 1, Tenant: "team-a"}}}},
+	{"admin_envs_empty", AdminEnvsResponse{Draining: true}},
+	{"admin_state", Snapshot{Version: snapshotVersion, Created: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Sessions: []SessionSnapshot{{ID: "s1", TTLSeconds: 300}},
+		Environments: []EnvSnapshot{{EnvID: "s1/e1", Scenario: "cartpole", Config: map[string]interface{}{"seed": 1},
+			State: []byte(`{}`), EpisodeSteps: 3, Steps: 3, Episodes: 1}}}},
+	{"drain", DrainResponse{RemainingEnvironments: 1, ClosedEnvironments: 2}},
+	{"register_scenario", RegisterScenarioResponse{Scenario: "my_env"}},
+	{"error", ErrorResponse{Error: true, Message: "Environment e1 not found", Code: 404, Status: "NOT_FOUND",
+		Details: map[string]string{"env_id": "e1"}}},
+	{"error_no_details", ErrorResponse{Error: true, Message: "Method not allowed", Code: 405, Status: "UNIMPLEMENTED"}},
+}
+
+func TestEncodeJSONGolden(t *testing.T) {
+	var got bytes.Buffer
+	for _, tt := range goldenResponses {
+		body, err := encodeJSON(tt.v)
+		if err != nil {
+			t.Fatalf("%s: encodeJSON: %v", tt.name, err)
+		}
+		if !json.Valid(body) {
+			t.Fatalf("%s: invalid JSON %s", tt.name, body)
+		}
+		fmt.Fprintf(&got, "=== %s\n%s\n", tt.name, body)
+	}
+
+	path := filepath.Join("testdata", "responses.golden")
+	if *update {
+		if err := os.WriteFile(path, got.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run go test -run TestEncodeJSONGolden -update to create it)", err)
+	}
+	if !bytes.Equal(got.Bytes(), want) {
+		gotCases, wantCases := splitGolden(got.Bytes()), splitGolden(want)
+		for _, tt := range goldenResponses {
+			if gotCases[tt.name] != wantCases[tt.name] {
+				t.Errorf("%s:\n got %s\nwant %s", tt.name, gotCases[tt.name], wantCases[tt.name])
+			}
+		}
+		if len(gotCases) != len(wantCases) {
+			t.Errorf("golden file has %d cases, want %d", len(wantCases), len(gotCases))
+		}
+	}
+}
+
+// splitGolden 按"=== name"行把golden文件拆为各用例的编码结果
+func splitGolden(data []byte) map[string]string {
+	cases := make(map[string]string)
+	var name string
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if rest, ok := bytes.CutPrefix(line, []byte("=== ")); ok {
+			name = string(rest)
+			continue
+		}
+		if name != "" && len(line) > 0 {
+			cases[name] += string(line)
+		}
+	}
+	return cases
+}
+
+func TestAppendJSONFloat(t *testing.T) {
+	tests := []struct {
+		f    float64
+		bits int
+		want string
+	}{
+		{0, 64, "0"},
+		{math.Copysign(0, -1), 64, "0"},
+		{math.Copysign(0, -1), 32, "0"},
+		{-1.5, 64, "-1.5"},
+		{float64(float32(0.1)), 32, "0.1"},
+		{float64(float32(0.1)), 64, "0.10000000149011612"},
+		{1e-7, 64, "1e-7"},
+		{1e21, 64, "1e+21"},
+		{123456789, 64, "123456789"},
+		{math.NaN(), 64, "null"},
+		{math.Inf(-1), 32, "null"},
+	}
+	for _, tt := range tests {
+		if got := string(appendJSONFloat(nil, tt.f, tt.bits)); got != tt.want {
+			t.Errorf("appendJSONFloat(%v, %d) = %s, want %s", tt.f, tt.bits, got, tt.want)
+		}
+	}
+}
+
+func TestInternalErrorBody(t *testing.T) {
+	body, err := encodeJSON(ErrorResponse{Error: true, Message: "Internal server error", Code: http.StatusInternalServerError,
+		Status: errorCode(httpErrorCode(http.StatusInternalServerError))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(body, internalErrorBody) {
+		t.Fatalf("internalErrorBody = %s, want %s", internalErrorBody, body)
+	}
+}
+
+func TestInfoScenariosSorted(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NewGymAPI().Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/info", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /info: %d %s", rec.Code, rec.Body)
+	}
+	var info InfoResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &info); err != nil {
+		t.Fatal(err)
+	}
+	if len(info.Scenarios) < 2 || !sort.StringsAreSorted(info.Scenarios) {
+		t.Fatalf("scenarios are not sorted: %v", info.Scenarios)
+	}
+}
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// Limits 服务端创建环境时检查的资源上限，为0的字段不限制。
+// 超出上限时gRPC返回ResourceExhausted，HTTP返回429
+type Limits struct {
+	// MaxEnvs 注册表中的活跃环境总数
+	MaxEnvs int `json:"max_envs,omitempty" yaml:"max_envs,omitempty"`
+	// MaxEnvsPerClient 同一客户端（按连接的来源地址区分，与是否使用会话无关）的活跃环境数
+	MaxEnvsPerClient int `json:"max_envs_per_client,omitempty" yaml:"max_envs_per_client,omitempty"`
+	// MaxEnvsPerSession 每个会话的活跃环境数
+	MaxEnvsPerSession int `json:"max_envs_per_session,omitempty" yaml:"max_envs_per_session,omitempty"`
+	// MaxObservationSize 每个智能体观察的数值个数（按观察空间的形状计算）
+	MaxObservationSize int `json:"max_observation_size,omitempty" yaml:"max_observation_size,omitempty"`
+}
+
+// checkCounts 检查创建pending个环境后各项数量是否超出上限：创建前pending为1，
+// 环境加入注册表后pending为0，用于在并发创建时复核
+func (l Limits) checkCounts(registry *EnvRegistry, sess *Session, client string, pending int) error {
+	if l.MaxEnvs > 0 && registry.Len()+pending > l.MaxEnvs {
+		return fmt.Errorf("server has reached the limit of %d environments", l.MaxEnvs)
+	}
+	if l.MaxEnvsPerSession > 0 && sess != nil && sess.Len()+pending > l.MaxEnvsPerSession {
+		return fmt.Errorf("session has reached the limit of %d environments", l.MaxEnvsPerSession)
+	}
+	if l.MaxEnvsPerClient > 0 && registry.clientLen(client)+pending > l.MaxEnvsPerClient {
+		return fmt.Errorf("client %s has reached the limit of %d environments", client, l.MaxEnvsPerClient)
+	}
+	return nil
+}
+
+// checkObservation 检查环境每个智能体观察的数值个数
+func (l Limits) checkObservation(env core.Environment) error {
+	if l.MaxObservationSize <= 0 {
+		return nil
+	}
+	size := 1
+	for _, dim := range env.GetSpaces().ObservationSpace.Shape {
+		size *= int(dim)
+	}
+	if size > l.MaxObservationSize {
+		return fmt.Errorf("observation of %d values exceeds the limit of %d", size, l.MaxObservationSize)
+	}
+	return nil
+}
+
+// clientHost 返回来源地址的主机部分，作为按客户端计数的键；Unix套接字上的客户端共用同一个键
+func clientHost(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+// discard 撤销刚注册的环境（复核上限失败时）：从会话与注册表中移除并关闭
+func discard(registry *EnvRegistry, sess *Session, key string, t telemetry) {
+	if sess != nil {
+		sess.untrack(key)
+	}
+	if env, ok := registry.Remove(key); ok {
+		env.Close()
+		t.reportClosed(key)
+	}
+}
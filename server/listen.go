@@ -0,0 +1,59 @@
+package server
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+)
+
+// UnixScheme Unix套接字地址的前缀，如unix:///run/rlenv/grpc.sock
+const UnixScheme = "unix://"
+
+// IsUnixAddress 判断address是否为unix://地址
+func IsUnixAddress(address string) bool {
+	return strings.HasPrefix(address, UnixScheme)
+}
+
+// Listen 监听address：unix://地址在对应路径上监听Unix套接字，其余按TCP地址host:port监听
+func Listen(address string) (net.Listener, error) {
+	if IsUnixAddress(address) {
+		return listenUnix(strings.TrimPrefix(address, UnixScheme))
+	}
+	lis, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen: %v", err)
+	}
+	return lis, nil
+}
+
+// listenUnix 在path上监听Unix套接字。path处残留的套接字文件（上次未正常退出）会被替换，其他类型的文件不会被删除；
+// 套接字权限为0600，只有同一用户的进程可以连接，需要共享时可放宽所在目录与套接字的权限。
+// 监听器关闭时删除套接字文件
+func listenUnix(path string) (net.Listener, error) {
+	if path == "" {
+		return nil, fmt.Errorf("empty unix socket path")
+	}
+	if info, err := os.Lstat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+		os.Remove(path)
+	}
+	lis, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen: %v", err)
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		lis.Close()
+		return nil, err
+	}
+	return lis, nil
+}
+
+// LoadTLSConfig 读取PEM格式的证书与私钥，返回服务端的TLS配置（最低TLS 1.2）
+func LoadTLSConfig(certFile, keyFile string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+	return &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}, nil
+}
@@ -0,0 +1,278 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	pb "github.com/jelech/rl_env_engine/proto"
+	"google.golang.org/grpc/metadata"
+)
+
+// placed 返回不属于会话的env_id所在的节点：迁移过的环境为记录的节点，否则按哈希环
+func (r *Router) placed(ctx context.Context, envID string) (*routerWorker, error) {
+	r.mu.Lock()
+	n := len(r.placements)
+	r.mu.Unlock()
+	if n > 0 {
+		tenant, err := r.tenantName(ctx)
+		if err != nil {
+			return nil, err
+		}
+		r.mu.Lock()
+		w, ok := r.placements[tenantKey(tenant, envID)]
+		r.mu.Unlock()
+		if ok {
+			return w, nil
+		}
+	}
+	return r.ring.Load().get(envID), nil
+}
+
+// tenantName 返回请求的API密钥所属的租户名，由工作节点的GetInfo解析后缓存；未携带密钥时为空
+func (r *Router) tenantName(ctx context.Context) (string, error) {
+	apiKey := metadataValue(ctx, APIKeyMetadataKey)
+	if apiKey == "" {
+		return "", nil
+	}
+	r.mu.Lock()
+	tenant, ok := r.tenants[apiKey]
+	r.mu.Unlock()
+	if ok {
+		return tenant, nil
+	}
+
+	md, _ := metadata.FromOutgoingContext(forwardContext(ctx))
+	md.Delete(SessionMetadataKey)
+	info, err := r.pick().client.GetInfo(metadata.NewOutgoingContext(ctx, md), &pb.GetInfoRequest{})
+	if err != nil {
+		return "", err
+	}
+	tenant, _ = info.Info.AsMap()["tenant"].(string)
+	r.mu.Lock()
+	r.tenants[apiKey] = tenant
+	r.mu.Unlock()
+	return tenant, nil
+}
+
+// unplace 客户端关闭环境后删除其迁移记录
+func (r *Router) unplace(ctx context.Context, envID string) {
+	r.mu.Lock()
+	n := len(r.placements)
+	r.mu.Unlock()
+	if n == 0 {
+		return
+	}
+	if tenant, err := r.tenantName(ctx); err == nil {
+		r.unplaceKey(tenantKey(tenant, envID))
+	}
+}
+
+// unplaceKey 删除注册表中的完整键key的迁移记录
+func (r *Router) unplaceKey(key string) {
+	r.mu.Lock()
+	delete(r.placements, key)
+	r.mu.Unlock()
+}
+
+// worker 返回地址为addr的节点
+func (r *Router) worker(addr string) (*routerWorker, error) {
+	for _, w := range r.workers {
+		if w.addr == addr {
+			return w, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown worker %s", addr)
+}
+
+// locate 返回注册表中的完整键为key的环境及其所在的节点
+func (r *Router) locate(ctx context.Context, key string) (*pb.EnvironmentStatus, *routerWorker, error) {
+	workers := r.live()
+	resps := make([]*pb.ListEnvironmentsResponse, len(workers))
+	err := broadcast(workers, func(i int, w *routerWorker) error {
+		resp, err := w.client.ListEnvironments(ctx, &pb.ListEnvironmentsRequest{})
+		resps[i] = resp
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	for i, resp := range resps {
+		for _, env := range resp.Environments {
+			if env.EnvId == key {
+				return env, workers[i], nil
+			}
+		}
+	}
+	return nil, nil, fmt.Errorf("environment %s not found", key)
+}
+
+// migrate 将环境env（属于会话时连同会话的全部环境）从from迁移到to，返回迁移的环境数。
+// 迁移期间持有其路由分段的写锁，客户端对这些环境的请求等待迁移完成后转发到to；
+// 导入to失败时在from上恢复
+func (r *Router) migrate(ctx context.Context, env *pb.EnvironmentStatus, from, to *routerWorker) (int, error) {
+	if from == to {
+		return 0, nil
+	}
+	envID := env.EnvId
+	if env.Tenant != "" {
+		envID = strings.TrimPrefix(envID, env.Tenant+":")
+	}
+	lockKey := envID
+	if env.SessionId != "" {
+		lockKey = env.SessionId
+	}
+	lock := r.stripe(lockKey)
+	lock.Lock()
+	defer lock.Unlock()
+
+	exported, err := from.client.ExportEnvironment(ctx, &pb.ExportEnvironmentRequest{EnvId: env.EnvId, Detach: true})
+	if err != nil {
+		return 0, err
+	}
+	if _, err := to.client.ImportEnvironment(ctx, &pb.ImportEnvironmentRequest{Snapshot: exported.Snapshot}); err != nil {
+		if _, restoreErr := from.client.ImportEnvironment(ctx, &pb.ImportEnvironmentRequest{Snapshot: exported.Snapshot}); restoreErr != nil {
+			return 0, fmt.Errorf("failed to import into %s: %v; restoring on %s also failed: %v", to.addr, err, from.addr, restoreErr)
+		}
+		return 0, fmt.Errorf("failed to import into %s: %w", to.addr, err)
+	}
+
+	r.mu.Lock()
+	if env.SessionId != "" {
+		if sess, ok := r.sessions[env.SessionId]; ok {
+			sess.worker = to
+		} else {
+			r.sessions[env.SessionId] = &routedSession{worker: to, ttl: DefaultSessionTTL, lastSeen: time.Now()}
+		}
+	} else if r.ring.Load().get(envID) == to {
+		delete(r.placements, env.EnvId)
+	} else {
+		r.placements[env.EnvId] = to
+	}
+	r.mu.Unlock()
+	r.log().Info("router: migrated environment", "env_id", env.EnvId, "environments", exported.Environments, "from", from.addr, "to", to.addr)
+	return int(exported.Environments), nil
+}
+
+// MigrateEnvironment 管理接口：把环境（属于会话时连同会话的全部环境）迁移到另一个节点，用于重新均衡负载。
+// 环境需实现core.Checkpointer；迁移后持有env_id的客户端无需改动，请求转发到新的节点
+func (r *Router) MigrateEnvironment(ctx context.Context, req *pb.MigrateEnvironmentRequest) (*pb.MigrateEnvironmentResponse, error) {
+	to, err := r.worker(req.Worker)
+	if err != nil {
+		return nil, err
+	}
+	if to.drained.Load() {
+		return nil, fmt.Errorf("worker %s is drained", to.addr)
+	}
+	fctx := forwardContext(ctx)
+	env, from, err := r.locate(fctx, req.EnvId)
+	if err != nil {
+		return nil, err
+	}
+	n, err := r.migrate(fctx, env, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.MigrateEnvironmentResponse{MigratedEnvironments: int32(n)}, nil
+}
+
+// DrainWorker 管理接口：把节点移出哈希环，不再在其上创建环境或打开会话，
+// 并把其上的环境迁移到其余节点（不属于会话的环境迁往新的哈希环所指节点）。
+// 未实现core.Checkpointer的环境留在原节点，客户端关闭前仍可继续使用；全部迁出后该节点可以下线
+func (r *Router) DrainWorker(ctx context.Context, req *pb.DrainWorkerRequest) (*pb.DrainWorkerResponse, error) {
+	w, err := r.worker(req.Worker)
+	if err != nil {
+		return nil, err
+	}
+	fctx := forwardContext(ctx)
+
+	// 暂停全部路由，记录节点上现有环境的位置后再替换哈希环，排空期间这些环境仍转发到原节点
+	for i := range r.stripes {
+		r.stripes[i].Lock()
+	}
+	list, err := w.client.ListEnvironments(fctx, &pb.ListEnvironmentsRequest{})
+	if err == nil {
+		err = r.removeFromRing(w, list.Environments)
+	}
+	for i := range r.stripes {
+		r.stripes[i].Unlock()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.DrainWorkerResponse{}
+	migrated := make(map[string]bool) // 已随会话迁移的会话
+	for _, env := range list.Environments {
+		if env.SessionId != "" && migrated[env.SessionId] {
+			continue
+		}
+		to := r.pick()
+		if env.SessionId == "" {
+			envID := env.EnvId
+			if env.Tenant != "" {
+				envID = strings.TrimPrefix(envID, env.Tenant+":")
+			}
+			to = r.ring.Load().get(envID)
+		}
+		n, err := r.migrate(fctx, env, w, to)
+		if err != nil {
+			r.log().Warn("router: failed to migrate environment", "env_id", env.EnvId, "worker", w.addr, "error", err)
+			continue
+		}
+		resp.MigratedEnvironments += int32(n)
+		if env.SessionId != "" {
+			migrated[env.SessionId] = true
+		}
+	}
+
+	list, err = w.client.ListEnvironments(fctx, &pb.ListEnvironmentsRequest{})
+	if err != nil {
+		return nil, err
+	}
+	resp.RemainingEnvironments = int32(len(list.Environments))
+	if resp.RemainingEnvironments == 0 && !r.hasSessions(w) {
+		w.drained.Store(true)
+	}
+	r.log().Info("router: drained worker", "worker", w.addr, "migrated", resp.MigratedEnvironments, "remaining", resp.RemainingEnvironments)
+	return resp, nil
+}
+
+// removeFromRing 固定envs中不属于会话的环境到节点w，并将w移出哈希环
+func (r *Router) removeFromRing(w *routerWorker, envs []*pb.EnvironmentStatus) error {
+	ring := r.ring.Load()
+	workers := make([]*routerWorker, 0, len(ring.workers))
+	for _, other := range ring.workers {
+		if other != w {
+			workers = append(workers, other)
+		}
+	}
+	if len(workers) == len(ring.workers) {
+		return nil
+	}
+	if len(workers) == 0 {
+		return fmt.Errorf("cannot drain the last worker %s", w.addr)
+	}
+	r.mu.Lock()
+	for _, env := range envs {
+		if _, ok := r.placements[env.EnvId]; env.SessionId == "" && !ok {
+			r.placements[env.EnvId] = w
+		}
+	}
+	r.mu.Unlock()
+	r.ring.Store(newHashRing(workers))
+	return nil
+}
+
+// hasSessions 判断是否有路由器记录的会话位于节点w（如尚未创建环境的会话）
+func (r *Router) hasSessions(w *routerWorker) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, sess := range r.sessions {
+		if sess.worker == w {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/jelech/rl_env_engine/core"
+	"github.com/jelech/rl_env_engine/core/record"
+	"github.com/jelech/rl_env_engine/core/tensorboard"
+	"github.com/jelech/rl_env_engine/core/video"
+)
+
+// errNoOutputDir 服务端未设置输出目录，客户端不能让服务端写文件
+var errNoOutputDir = errors.New("server has no output directory configured")
+
+// outputConfigKeys 创建配置中由客户端给出、服务端写入的输出路径
+var outputConfigKeys = []string{record.ConfigKey, video.ConfigKeyDir, tensorboard.ConfigKey}
+
+// outputPath 将客户端给出的相对路径解析到服务端输出目录root之下，
+// 未设置root时返回errNoOutputDir，绝对路径与跳出root的路径（包含..）被拒绝
+func outputPath(root, path string) (string, error) {
+	if root == "" {
+		return "", errNoOutputDir
+	}
+	if !filepath.IsLocal(path) {
+		return "", fmt.Errorf("output path %q must be relative to the server output directory and must not contain ..", path)
+	}
+	return filepath.Join(root, path), nil
+}
+
+// outputConfig 将配置中的输出路径（outputConfigKeys）解析到服务端输出目录之下，返回替换后的配置；
+// 未设置输出路径时原样返回config
+func (t telemetry) outputConfig(config core.Config) (core.Config, error) {
+	if config == nil {
+		return nil, nil
+	}
+	resolved := make(map[string]interface{})
+	for _, key := range outputConfigKeys {
+		path, ok, err := config.GetString(key)
+		if err != nil {
+			return nil, err
+		}
+		if !ok || path == "" {
+			continue
+		}
+		full, err := outputPath(t.outputDir, path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		resolved[key] = full
+	}
+	if len(resolved) == 0 {
+		return config, nil
+	}
+	source, ok := config.(interface{ Values() map[string]interface{} })
+	if !ok {
+		return nil, fmt.Errorf("cannot resolve output paths of %T", config)
+	}
+	values := source.Values()
+	for k, v := range resolved {
+		values[k] = v
+	}
+	return core.NewBaseConfig(values), nil
+}
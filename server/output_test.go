@@ -0,0 +1,72 @@
+package server
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/jelech/rl_env_engine/core"
+	"github.com/jelech/rl_env_engine/core/tensorboard"
+	"github.com/jelech/rl_env_engine/core/video"
+)
+
+func TestOutputPath(t *testing.T) {
+	root := t.TempDir()
+	tests := []struct {
+		path string
+		want string // 为空表示应被拒绝
+	}{
+		{"run.jsonl", filepath.Join(root, "run.jsonl")},
+		{"a/b/run.jsonl", filepath.Join(root, "a", "b", "run.jsonl")},
+		{"a/../run.jsonl", filepath.Join(root, "run.jsonl")},
+		{"../run.jsonl", ""},
+		{"a/../../run.jsonl", ""},
+		{"/etc/passwd", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		got, err := outputPath(root, tt.path)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("outputPath(%q) = %s, want an error", tt.path, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("outputPath(%q) = %s, %v, want %s", tt.path, got, err, tt.want)
+		}
+	}
+
+	if _, err := outputPath("", "run.jsonl"); !errors.Is(err, errNoOutputDir) {
+		t.Errorf("outputPath without a root: %v, want errNoOutputDir", err)
+	}
+}
+
+func TestOutputConfig(t *testing.T) {
+	root := t.TempDir()
+	telemetry := telemetry{outputDir: root}
+	config, err := telemetry.outputConfig(core.NewBaseConfig(map[string]interface{}{
+		video.ConfigKeyDir:    "videos",
+		tensorboard.ConfigKey: "runs/e1",
+		"max_steps":           10,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dir, _, _ := config.GetString(video.ConfigKeyDir); dir != filepath.Join(root, "videos") {
+		t.Errorf("%s = %s, want it under %s", video.ConfigKeyDir, dir, root)
+	}
+	if dir, _, _ := config.GetString(tensorboard.ConfigKey); dir != filepath.Join(root, "runs", "e1") {
+		t.Errorf("%s = %s, want it under %s", tensorboard.ConfigKey, dir, root)
+	}
+	if steps, _, _ := config.GetInt("max_steps"); steps != 10 {
+		t.Errorf("max_steps = %d, want 10", steps)
+	}
+
+	if _, err := telemetry.outputConfig(core.NewBaseConfig(map[string]interface{}{video.ConfigKeyDir: "/tmp"})); err == nil {
+		t.Errorf("absolute %s was accepted", video.ConfigKeyDir)
+	}
+	if _, err := telemetry.outputConfig(core.NewBaseConfig(map[string]interface{}{video.ConfigKeyDir: "../videos"})); err == nil {
+		t.Errorf("%s outside the output directory was accepted", video.ConfigKeyDir)
+	}
+}
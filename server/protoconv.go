@@ -0,0 +1,441 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/jelech/rl_env_engine/core"
+	"github.com/jelech/rl_env_engine/core/metrics"
+	pb "github.com/jelech/rl_env_engine/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// TypedValuesKey 创建环境时的服务端选项：为true时gRPC响应中元数据与信息的标量（数值、布尔、字符串）
+// 改由typed_metadata/typed_info以带类型的Value返回，整数不再变为double；Struct只保留列表等复合值。
+// HTTP JSON接口本身保留数值类型，不受此选项影响
+const TypedValuesKey = "typed_values"
+
+// TypedValuesEnabled 读取创建配置中的TypedValuesKey，未设置时为false
+func TypedValuesEnabled(config core.Config) (bool, error) {
+	if config == nil {
+		return false, nil
+	}
+	enabled, _, err := config.GetBool(TypedValuesKey)
+	return enabled, err
+}
+
+// stepEncoder 将仿真结果转换为protobuf消息，并复用转换所需的消息与切片：
+// 所有观察消息分配在一块连续内存中，观察数据共用一个底层数组，元数据与信息的Struct原地更新。
+// 同一个stepEncoder只能在上一次编码的消息发送完毕后再次使用，不能被多个goroutine并发使用
+type stepEncoder struct {
+	messages []pb.Observation
+	pointers []*pb.Observation
+	data     []float64
+	data32   []float32
+	pixels   []uint8
+	images   []pb.Image
+	actions  []core.Action
+	info     *structpb.Struct
+
+	typed     bool // 标量以带类型的Value返回，见TypedValuesKey
+	typedInfo map[string]*pb.Value
+
+	final *stepEncoder // 编码自动重置前的最后观察，首次自动重置时创建
+}
+
+// observations 将观察转换为protobuf格式，float32存储的观察填充data_f32，文本观察填充text，图像观察只填充image，
+// agent_id为agentIDs中对应的名称。返回的消息引用stepEncoder的缓冲区，下一次调用会覆盖它们
+func (e *stepEncoder) observations(observations []core.Observation, agentIDs []string) ([]*pb.Observation, error) {
+	n := len(observations)
+	if cap(e.messages) < n {
+		e.messages = make([]pb.Observation, n)
+		e.pointers = make([]*pb.Observation, n)
+		e.images = make([]pb.Image, n)
+	}
+	e.messages, e.pointers = e.messages[:n], e.pointers[:n]
+
+	// 先统计数据总量，使底层数组在本次编码中不会扩容，从而各消息引用的切片保持有效
+	size, size32, sizePixels := 0, 0, 0
+	for _, obs := range observations {
+		if pixels, _, _, _, ok := core.ObservationImage(obs); ok {
+			sizePixels += len(pixels)
+		} else if data32 := float32Data(obs); data32 != nil {
+			size32 += len(data32)
+		} else {
+			size += len(obs.GetData())
+		}
+	}
+	if cap(e.data) < size {
+		e.data = make([]float64, 0, size)
+	}
+	if cap(e.data32) < size32 {
+		e.data32 = make([]float32, 0, size32)
+	}
+	if cap(e.pixels) < sizePixels {
+		e.pixels = make([]uint8, 0, sizePixels)
+	}
+	e.data, e.data32, e.pixels = e.data[:0], e.data32[:0], e.pixels[:0]
+
+	for i, obs := range observations {
+		message := &e.messages[i]
+		message.Data, message.DataF32, message.Image = nil, nil, nil
+		message.Text, _ = core.ObservationText(obs)
+		message.AgentId = ""
+		if i < len(agentIDs) {
+			message.AgentId = agentIDs[i]
+		}
+		if metadata := obs.GetMetadata(); len(metadata) > 0 {
+			if message.Metadata == nil {
+				message.Metadata = &structpb.Struct{}
+			}
+			var err error
+			if e.typed {
+				if message.TypedMetadata == nil {
+					message.TypedMetadata = make(map[string]*pb.Value, len(metadata))
+				}
+				err = fillTyped(message.Metadata, message.TypedMetadata, metadata)
+			} else {
+				message.TypedMetadata = nil
+				err = fillStruct(message.Metadata, metadata)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to create metadata struct for observation %d: %v", i, err)
+			}
+		} else {
+			message.Metadata, message.TypedMetadata = nil, nil
+		}
+		if pixels, height, width, channels, ok := core.ObservationImage(obs); ok {
+			start := len(e.pixels)
+			e.pixels = append(e.pixels, pixels...)
+			image := &e.images[i]
+			image.Pixels = e.pixels[start:len(e.pixels):len(e.pixels)]
+			image.Height, image.Width, image.Channels = int32(height), int32(width), int32(channels)
+			message.Image = image
+		} else if data32 := float32Data(obs); data32 != nil {
+			start := len(e.data32)
+			e.data32 = append(e.data32, data32...)
+			message.DataF32 = e.data32[start:len(e.data32):len(e.data32)]
+		} else {
+			start := len(e.data)
+			e.data = append(e.data, obs.GetData()...)
+			message.Data = e.data[start:len(e.data):len(e.data)]
+		}
+		e.pointers[i] = message
+	}
+	return e.pointers, nil
+}
+
+// infoStruct 将环境信息转换为protobuf Struct与带类型的标量（仅typed为true时非nil），原地更新上一次的结果
+func (e *stepEncoder) infoStruct(info map[string]interface{}) (*structpb.Struct, map[string]*pb.Value, error) {
+	if e.info == nil {
+		e.info = &structpb.Struct{}
+	}
+	if !e.typed {
+		if err := fillStruct(e.info, info); err != nil {
+			return nil, nil, err
+		}
+		return e.info, nil, nil
+	}
+	if e.typedInfo == nil {
+		e.typedInfo = make(map[string]*pb.Value, len(info))
+	}
+	if err := fillTyped(e.info, e.typedInfo, info); err != nil {
+		return nil, nil, err
+	}
+	return e.info, e.typedInfo, nil
+}
+
+// protoStepTypes 返回各智能体的protobuf时间步类型与折扣
+func protoStepTypes(terminated, truncated []bool) ([]pb.StepType, []float64) {
+	types, discounts := core.StepTransitions(terminated, truncated)
+	protoTypes := make([]pb.StepType, len(types))
+	for i, t := range types {
+		protoTypes[i] = pb.StepType(t)
+	}
+	return protoTypes, discounts
+}
+
+// float32Data 返回float32存储的观察数据，观察以float64存储时返回nil
+func float32Data(obs core.Observation) []float32 {
+	if o, ok := obs.(core.Float32Observation); ok {
+		return o.GetData32()
+	}
+	return nil
+}
+
+// protoStruct 将map转换为新的protobuf Struct
+func protoStruct(m map[string]interface{}) (*structpb.Struct, error) {
+	s := &structpb.Struct{}
+	if err := fillStruct(s, m); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// fillStruct 用m的内容原地更新s：删除m中没有的字段，已有字段的Value在类型相同时直接改写
+func fillStruct(s *structpb.Struct, m map[string]interface{}) error {
+	if s.Fields == nil {
+		s.Fields = make(map[string]*structpb.Value, len(m))
+	}
+	for k := range s.Fields {
+		if _, ok := m[k]; !ok {
+			delete(s.Fields, k)
+		}
+	}
+	for k, v := range m {
+		value := s.Fields[k]
+		if value == nil {
+			value = &structpb.Value{}
+			s.Fields[k] = value
+		}
+		if err := setProtoValue(value, v); err != nil {
+			return fmt.Errorf("field %q: %w", k, err)
+		}
+	}
+	return nil
+}
+
+// fillTyped 用m的内容原地更新typed与s：标量写入typed，其余值写入s
+func fillTyped(s *structpb.Struct, typed map[string]*pb.Value, m map[string]interface{}) error {
+	if s.Fields == nil {
+		s.Fields = make(map[string]*structpb.Value)
+	}
+	for k := range typed {
+		if _, ok := m[k]; !ok {
+			delete(typed, k)
+		}
+	}
+	for k := range s.Fields {
+		if _, ok := m[k]; !ok {
+			delete(s.Fields, k)
+		}
+	}
+	for k, v := range m {
+		value := typed[k]
+		if value == nil && isTypedScalar(v) {
+			value = &pb.Value{}
+		}
+		if value != nil && setTypedValue(value, v) {
+			typed[k] = value
+			delete(s.Fields, k)
+			continue
+		}
+		delete(typed, k)
+		field := s.Fields[k]
+		if field == nil {
+			field = &structpb.Value{}
+			s.Fields[k] = field
+		}
+		if err := setProtoValue(field, v); err != nil {
+			return fmt.Errorf("field %q: %w", k, err)
+		}
+	}
+	return nil
+}
+
+// setTypedValue 将标量v写入dst并返回true，复用dst中类型相同的字段；v不是标量时返回false
+func setTypedValue(dst *pb.Value, v interface{}) bool {
+	switch v := v.(type) {
+	case bool:
+		if kind, ok := dst.Kind.(*pb.Value_BoolValue); ok {
+			kind.BoolValue = v
+		} else {
+			dst.Kind = &pb.Value_BoolValue{BoolValue: v}
+		}
+	case int:
+		setInt(dst, int64(v))
+	case int32:
+		setInt(dst, int64(v))
+	case int64:
+		setInt(dst, v)
+	case float32:
+		setDouble(dst, float64(v))
+	case float64:
+		setDouble(dst, v)
+	case string:
+		if kind, ok := dst.Kind.(*pb.Value_StringValue); ok {
+			kind.StringValue = v
+		} else {
+			dst.Kind = &pb.Value_StringValue{StringValue: v}
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// isTypedScalar 判断v能否以带类型的Value表示
+func isTypedScalar(v interface{}) bool {
+	switch v.(type) {
+	case bool, int, int32, int64, float32, float64, string:
+		return true
+	}
+	return false
+}
+
+// typedScalars 将m中的标量字段转换为带类型的Value，其余字段跳过
+func typedScalars(m map[string]interface{}) map[string]*pb.Value {
+	typed := make(map[string]*pb.Value, len(m))
+	for k, v := range m {
+		if !isTypedScalar(v) {
+			continue
+		}
+		value := &pb.Value{}
+		setTypedValue(value, v)
+		typed[k] = value
+	}
+	return typed
+}
+
+func setInt(dst *pb.Value, x int64) {
+	if kind, ok := dst.Kind.(*pb.Value_IntValue); ok {
+		kind.IntValue = x
+		return
+	}
+	dst.Kind = &pb.Value_IntValue{IntValue: x}
+}
+
+func setDouble(dst *pb.Value, x float64) {
+	if kind, ok := dst.Kind.(*pb.Value_DoubleValue); ok {
+		kind.DoubleValue = x
+		return
+	}
+	dst.Kind = &pb.Value_DoubleValue{DoubleValue: x}
+}
+
+// setProtoValue 将v写入dst。场景元数据中常见的数值、字符串及其切片按类型直接转换，
+// 不经过反射或字符串格式化，且复用dst中类型相同的字段；其余类型交给structpb.NewValue
+func setProtoValue(dst *structpb.Value, v interface{}) error {
+	switch v := v.(type) {
+	case nil:
+		if _, ok := dst.Kind.(*structpb.Value_NullValue); !ok {
+			dst.Kind = &structpb.Value_NullValue{}
+		}
+	case bool:
+		setBool(dst, v)
+	case int:
+		setNumber(dst, float64(v))
+	case int32:
+		setNumber(dst, float64(v))
+	case int64:
+		setNumber(dst, float64(v))
+	case float32:
+		setNumber(dst, float64(v))
+	case float64:
+		setNumber(dst, v)
+	case string:
+		if kind, ok := dst.Kind.(*structpb.Value_StringValue); ok {
+			kind.StringValue = v
+		} else {
+			dst.Kind = &structpb.Value_StringValue{StringValue: v}
+		}
+	case []float64:
+		for i, x := range listValues(dst, len(v)) {
+			setNumber(x, v[i])
+		}
+	case []int:
+		for i, x := range listValues(dst, len(v)) {
+			setNumber(x, float64(v[i]))
+		}
+	case []bool:
+		for i, x := range listValues(dst, len(v)) {
+			setBool(x, v[i])
+		}
+	case []interface{}:
+		for i, x := range listValues(dst, len(v)) {
+			if err := setProtoValue(x, v[i]); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		return fillStruct(structValue(dst), v)
+	default:
+		value, err := structpb.NewValue(v)
+		if err != nil {
+			return err
+		}
+		dst.Kind = value.Kind
+	}
+	return nil
+}
+
+func setNumber(dst *structpb.Value, x float64) {
+	if kind, ok := dst.Kind.(*structpb.Value_NumberValue); ok {
+		kind.NumberValue = x
+		return
+	}
+	dst.Kind = &structpb.Value_NumberValue{NumberValue: x}
+}
+
+func setBool(dst *structpb.Value, b bool) {
+	if kind, ok := dst.Kind.(*structpb.Value_BoolValue); ok {
+		kind.BoolValue = b
+		return
+	}
+	dst.Kind = &structpb.Value_BoolValue{BoolValue: b}
+}
+
+// listValues 将dst设为长度为n的列表并返回其元素，复用已有的列表与元素
+func listValues(dst *structpb.Value, n int) []*structpb.Value {
+	kind, ok := dst.Kind.(*structpb.Value_ListValue)
+	if !ok || kind.ListValue == nil {
+		kind = &structpb.Value_ListValue{ListValue: &structpb.ListValue{}}
+		dst.Kind = kind
+	}
+	values := kind.ListValue.Values
+	if cap(values) < n {
+		values = append(values[:cap(values)], make([]*structpb.Value, n-cap(values))...)
+	}
+	values = values[:n]
+	for i, value := range values {
+		if value == nil {
+			values[i] = &structpb.Value{}
+		}
+	}
+	kind.ListValue.Values = values
+	return values
+}
+
+// structValue 将dst设为Struct并返回它，复用已有的Struct
+func structValue(dst *structpb.Value) *structpb.Struct {
+	if kind, ok := dst.Kind.(*structpb.Value_StructValue); ok && kind.StructValue != nil {
+		return kind.StructValue
+	}
+	s := &structpb.Struct{}
+	dst.Kind = &structpb.Value_StructValue{StructValue: s}
+	return s
+}
+
+// protoAgents 将core.StepResult转换为以智能体名称为键的AgentStep，observations为已转换的观察，与result.Agents一一对应
+func protoAgents(result *core.StepResult, observations []*pb.Observation) map[string]*pb.AgentStep {
+	agents := make(map[string]*pb.AgentStep, len(result.Agents))
+	for i, name := range result.Agents {
+		agents[name] = &pb.AgentStep{
+			Observation: observations[i],
+			Reward:      result.Rewards[name],
+			Terminated:  result.Terminated[name],
+			Truncated:   result.Truncated[name],
+		}
+	}
+	return agents
+}
+
+// protoStepLatency 将各场景的步进延迟摘要转换为protobuf格式
+func protoStepLatency(summaries map[string]metrics.ScenarioLatency) map[string]*pb.ScenarioLatency {
+	latency := make(map[string]*pb.ScenarioLatency, len(summaries))
+	for scenario, summary := range summaries {
+		latency[scenario] = &pb.ScenarioLatency{Step: protoLatencySummary(summary.Step), Request: protoLatencySummary(summary.Request)}
+	}
+	return latency
+}
+
+func protoLatencySummary(summary metrics.LatencySummary) *pb.LatencySummary {
+	return &pb.LatencySummary{
+		Count:     summary.Count,
+		MeanMs:    summary.MeanMs,
+		P50Ms:     summary.P50Ms,
+		P95Ms:     summary.P95Ms,
+		P99Ms:     summary.P99Ms,
+		MaxMs:     summary.MaxMs,
+		PerSecond: summary.PerSecond,
+	}
+}
@@ -0,0 +1,48 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// 环境的panic由core.Guard转换为错误；以下拦截器与中间件兜底处理其余代码路径（如包装层之外的处理器逻辑）中的panic，
+// 将其转换为错误响应并记录调用栈，使单个请求的panic不影响其他请求与服务端进程
+
+// recoverUnary 将gRPC调用中的panic转换为Internal错误
+func (s *GrpcServer) recoverUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer s.recoverRPC(info.FullMethod, &err)
+	return handler(ctx, req)
+}
+
+// recoverStream 将gRPC流中的panic转换为Internal错误并结束该流
+func (s *GrpcServer) recoverStream(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer s.recoverRPC(info.FullMethod, &err)
+	return handler(srv, stream)
+}
+
+// recoverRPC 在defer中调用，恢复panic并将err设为Internal错误
+func (s *GrpcServer) recoverRPC(method string, err *error) {
+	if r := recover(); r != nil {
+		s.telemetry.log().Error("Panic serving gRPC request", "method", method, "panic", fmt.Sprint(r), "stack", string(debug.Stack()))
+		*err = status.Errorf(codes.Internal, "internal error: %v", r)
+	}
+}
+
+// recoverMiddleware 将HTTP处理器中的panic转换为500错误响应
+func (api *GymAPI) recoverMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if v := recover(); v != nil {
+				api.telemetry.log().Error("Panic serving HTTP request", "route", r.Method+" "+r.URL.Path, "panic", fmt.Sprint(v), "stack", string(debug.Stack()))
+				api.writeError(w, fmt.Sprintf("Internal error: %v", v), http.StatusInternalServerError)
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
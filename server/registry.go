@@ -0,0 +1,310 @@
+package server
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// EnvRegistry 按env_id索引的活跃环境表，可被多个goroutine并发使用。
+// 基于sync.Map：Reset/Step等路径上的查找无锁，创建与关闭互不阻塞，适合同时运行数百个环境的服务端。
+// GrpcServer与GymAPI各自默认持有一个，也可通过SetRegistry共用同一个
+type EnvRegistry struct {
+	envs  sync.Map // env_id -> *envEntry
+	count atomic.Int64
+}
+
+// GymnasiumAPIKey 创建环境时的服务端选项：为true时步进响应额外返回terminated与truncated（Gymnasium ≥0.26语义）。
+// 未设置时使用服务端的默认值（SetGymnasiumAPI）
+const GymnasiumAPIKey = "gymnasium_api"
+
+// GymnasiumEnabled 读取创建配置中的GymnasiumAPIKey，未设置时返回def
+func GymnasiumEnabled(config core.Config, def bool) (bool, error) {
+	if config == nil {
+		return def, nil
+	}
+	enabled, ok, err := config.GetBool(GymnasiumAPIKey)
+	if !ok || err != nil {
+		return def, err
+	}
+	return enabled, nil
+}
+
+// DmEnvKey 创建环境时的服务端选项：为true时步进响应额外返回dm_env的时间步类型与折扣
+const DmEnvKey = "dm_env"
+
+// DmEnvEnabled 读取创建配置中的DmEnvKey，未设置时为false
+func DmEnvEnabled(config core.Config) (bool, error) {
+	if config == nil {
+		return false, nil
+	}
+	enabled, _, err := config.GetBool(DmEnvKey)
+	return enabled, err
+}
+
+// AutoResetKey 创建环境时的服务端选项：为true时所有智能体都结束后，步进请求随即重置环境，
+// 响应返回新回合的初始观察，结束时的观察放在info的TerminalObservationKey中，省去一次reset往返
+const AutoResetKey = "auto_reset"
+
+// TerminalObservationKey 自动重置时步进响应info中回合结束时各智能体的观察
+const TerminalObservationKey = "terminal_observation"
+
+// AutoResetEnabled 读取创建配置中的AutoResetKey，未设置时为false
+func AutoResetEnabled(config core.Config) (bool, error) {
+	if config == nil {
+		return false, nil
+	}
+	enabled, _, err := config.GetBool(AutoResetKey)
+	return enabled, err
+}
+
+// AgentDictKey 创建环境时的服务端选项：为true时重置与步进响应以智能体名称为键返回各智能体的观察、奖励、
+// terminated与truncated（core.StepResult），代替按下标对齐的切片
+const AgentDictKey = "agent_dict"
+
+// AgentDictEnabled 读取创建配置中的AgentDictKey，未设置时为false
+func AgentDictEnabled(config core.Config) (bool, error) {
+	if config == nil {
+		return false, nil
+	}
+	enabled, _, err := config.GetBool(AgentDictKey)
+	return enabled, err
+}
+
+// validateServerOptions 校验创建配置中由服务端处理的选项
+func validateServerOptions(config core.Config) error {
+	if _, err := TypedValuesEnabled(config); err != nil {
+		return err
+	}
+	if _, err := AutoResetEnabled(config); err != nil {
+		return err
+	}
+	if _, err := DmEnvEnabled(config); err != nil {
+		return err
+	}
+	if _, err := AgentDictEnabled(config); err != nil {
+		return err
+	}
+	_, err := GymnasiumEnabled(config, false)
+	return err
+}
+
+// envEntry 注册表中的一个环境及其创建配置，替换环境时整体替换条目
+type envEntry struct {
+	env         core.Environment
+	config      core.Config
+	typedValues bool                      // 创建配置开启了typed_values
+	gymnasium   bool                      // 步进响应返回terminated与truncated
+	dmEnv       bool                      // 步进响应返回时间步类型与折扣
+	agentDict   bool                      // 响应以智能体名称为键返回各智能体的结果
+	autoReset   bool                      // 回合结束时在步进请求中自动重置
+	truncation  *core.TruncationTracker   // 开启gymnasium_api、dm_env或agent_dict时拆分结束标志，否则为nil
+	client      string                    // 创建该环境的客户端，用于按客户端计数（Limits.MaxEnvsPerClient）
+	tenant      string                    // 创建该环境的租户，用于按租户计数（Tenant.Limits.MaxEnvs）
+	scenario    string                    // 创建该环境的场景
+	stats       *envStats                 // 运行统计，替换条目时沿用
+	agentIDs    *atomic.Pointer[[]string] // 各智能体名称的缓存，替换条目时沿用
+	mu          *sync.Mutex               // 串行化重置、步进与保存检查点，替换条目时沿用
+}
+
+// envStats 环境的运行统计，供管理接口列出
+type envStats struct {
+	created  time.Time
+	steps    atomic.Int64
+	episodes atomic.Int64
+	lastUsed atomic.Int64 // 最近一次reset或step的时间（UnixNano）
+}
+
+func newEnvStats() *envStats {
+	stats := &envStats{created: time.Now()}
+	stats.lastUsed.Store(stats.created.UnixNano())
+	return stats
+}
+
+// reset 记录一次重置
+func (s *envStats) reset() {
+	s.episodes.Add(1)
+	s.lastUsed.Store(time.Now().UnixNano())
+}
+
+// step 记录一步
+func (s *envStats) step() {
+	s.steps.Add(1)
+	s.lastUsed.Store(time.Now().UnixNano())
+}
+
+// newEnvEntry 按创建配置构造条目，gymnasium为服务端的默认值
+func newEnvEntry(env core.Environment, config core.Config, gymnasium bool) *envEntry {
+	entry := &envEntry{env: env, config: config, stats: newEnvStats(), mu: &sync.Mutex{}, agentIDs: &atomic.Pointer[[]string]{}}
+	entry.typedValues, _ = TypedValuesEnabled(config)
+	entry.gymnasium, _ = GymnasiumEnabled(config, gymnasium)
+	entry.dmEnv, _ = DmEnvEnabled(config)
+	entry.autoReset, _ = AutoResetEnabled(config)
+	entry.agentDict, _ = AgentDictEnabled(config)
+	if entry.gymnasium || entry.dmEnv || entry.agentDict {
+		entry.truncation = core.NewTruncationTracker(env)
+	}
+	return entry
+}
+
+// resetIfDone 开启auto_reset且所有智能体都已结束时重置环境：结束时的观察（文本观察为其文本）复制到info[TerminalObservationKey]，
+// 归还observations并返回新回合的初始观察，否则原样返回observations；重置的错误原样返回。调用方须持有entry.mu
+func (entry *envEntry) resetIfDone(ctx context.Context, observations []core.Observation, done []bool, info map[string]interface{}) ([]core.Observation, error) {
+	if !entry.autoReset || !core.AllDone(done) {
+		return observations, nil
+	}
+	terminal := make([]interface{}, len(observations))
+	for i, obs := range observations {
+		if text, ok := core.ObservationText(obs); ok {
+			terminal[i] = text
+			continue
+		}
+		terminal[i] = append([]float64(nil), obs.GetData()...)
+	}
+	next, err := entry.env.Reset(ctx)
+	if err != nil {
+		return nil, err
+	}
+	core.ReleaseObservations(observations)
+	info[TerminalObservationKey] = terminal
+	if entry.truncation != nil {
+		entry.truncation.Reset()
+	}
+	entry.stats.reset()
+	return next, nil
+}
+
+// stepResult 开启agent_dict时按智能体名称组装结果，否则返回nil；rewards、terminated与truncated为nil表示重置的结果
+func (entry *envEntry) stepResult(observations []core.Observation, rewards []float64, terminated, truncated []bool) (*core.StepResult, error) {
+	if !entry.agentDict {
+		return nil, nil
+	}
+	return core.NewStepResult(entry.agentNames(len(observations)), observations, rewards, terminated, truncated)
+}
+
+// agentNames 返回前n个智能体的名称（见core.AgentNames），名称不随步进变化，因此缓存起来避免每步重新格式化
+func (entry *envEntry) agentNames(n int) []string {
+	if names := entry.agentIDs.Load(); names != nil && len(*names) >= n {
+		return (*names)[:n:n]
+	}
+	names := core.AgentNames(entry.env, n)
+	entry.agentIDs.Store(&names)
+	return names
+}
+
+// NewEnvRegistry 创建空的环境表
+func NewEnvRegistry() *EnvRegistry {
+	return &EnvRegistry{}
+}
+
+// Get 返回env_id对应的环境
+func (r *EnvRegistry) Get(envID string) (core.Environment, bool) {
+	entry, ok := r.entry(envID)
+	if !ok {
+		return nil, false
+	}
+	return entry.env, true
+}
+
+// entry 返回env_id对应的条目
+func (r *EnvRegistry) entry(envID string) (*envEntry, bool) {
+	entry, ok := r.envs.Load(envID)
+	if !ok {
+		return nil, false
+	}
+	return entry.(*envEntry), true
+}
+
+// Config 返回env_id对应环境的创建配置
+func (r *EnvRegistry) Config(envID string) (core.Config, bool) {
+	entry, ok := r.envs.Load(envID)
+	if !ok {
+		return nil, false
+	}
+	return entry.(*envEntry).config, true
+}
+
+// Add 注册新环境，env_id已存在时不做修改并返回false
+func (r *EnvRegistry) Add(envID string, env core.Environment, config core.Config) bool {
+	return r.add(envID, newEnvEntry(env, config, false))
+}
+
+func (r *EnvRegistry) add(envID string, entry *envEntry) bool {
+	if _, loaded := r.envs.LoadOrStore(envID, entry); loaded {
+		return false
+	}
+	r.count.Add(1)
+	return true
+}
+
+// Replace 将已注册的环境替换为env（如开始或停止录制时），env_id不存在时返回false
+func (r *EnvRegistry) Replace(envID string, env core.Environment) bool {
+	entry, ok := r.envs.Load(envID)
+	if !ok {
+		return false
+	}
+	old := entry.(*envEntry)
+	replaced := *old
+	replaced.env = env
+	return r.envs.CompareAndSwap(envID, old, &replaced)
+}
+
+// Remove 移除并返回env_id对应的环境，不会关闭环境
+func (r *EnvRegistry) Remove(envID string) (core.Environment, bool) {
+	entry, ok := r.envs.LoadAndDelete(envID)
+	if !ok {
+		return nil, false
+	}
+	r.count.Add(-1)
+	return entry.(*envEntry).env, true
+}
+
+// Len 返回活跃环境数
+func (r *EnvRegistry) Len() int {
+	return int(r.count.Load())
+}
+
+// clientLen 返回client创建的活跃环境数，需要遍历注册表，只在创建环境时调用
+func (r *EnvRegistry) clientLen(client string) int {
+	n := 0
+	r.each(func(_ string, entry *envEntry) {
+		if entry.client == client {
+			n++
+		}
+	})
+	return n
+}
+
+// tenantLen 返回租户tenant的活跃环境数，需要遍历注册表，只在创建环境时调用
+func (r *EnvRegistry) tenantLen(tenant string) int {
+	n := 0
+	r.each(func(_ string, entry *envEntry) {
+		if entry.tenant == tenant {
+			n++
+		}
+	})
+	return n
+}
+
+// each 按任意顺序遍历所有条目
+func (r *EnvRegistry) each(f func(key string, entry *envEntry)) {
+	r.envs.Range(func(key, entry interface{}) bool {
+		f(key.(string), entry.(*envEntry))
+		return true
+	})
+}
+
+// IDs 按字母顺序返回所有活跃环境的env_id
+func (r *EnvRegistry) IDs() []string {
+	ids := make([]string, 0, r.Len())
+	r.envs.Range(func(key, _ interface{}) bool {
+		ids = append(ids, key.(string))
+		return true
+	})
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,730 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+	"net"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jelech/rl_env_engine/core"
+	pb "github.com/jelech/rl_env_engine/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/stats"
+)
+
+// hashReplicas 每个工作节点在哈希环上的虚拟节点数，越多环境在节点间分布越均匀
+const hashReplicas = 256
+
+// routeStripes 路由锁的分段数，迁移环境时只暂停同一分段内的请求
+const routeStripes = 64
+
+// hashRing 一致性哈希环：每个节点占hashReplicas个虚拟节点，键归属顺时针方向的第一个虚拟节点。
+// 增删节点时只有相邻区间的键改变归属，其余环境仍路由到原来的节点。创建后不再修改，节点变化时整体替换
+type hashRing struct {
+	workers []*routerWorker // 参与分配新环境的节点
+	hashes  []uint32
+	nodes   map[uint32]*routerWorker // 虚拟节点的哈希 -> 节点
+}
+
+func newHashRing(workers []*routerWorker) *hashRing {
+	ring := &hashRing{workers: workers, nodes: make(map[uint32]*routerWorker, len(workers)*hashReplicas)}
+	for _, w := range workers {
+		for j := 0; j < hashReplicas; j++ {
+			h := ringHash(w.addr + "#" + strconv.Itoa(j))
+			if _, ok := ring.nodes[h]; ok {
+				continue
+			}
+			ring.nodes[h] = w
+			ring.hashes = append(ring.hashes, h)
+		}
+	}
+	sort.Slice(ring.hashes, func(i, j int) bool { return ring.hashes[i] < ring.hashes[j] })
+	return ring
+}
+
+// ringHash 返回key在哈希环上的位置。相近的env_id（如env-1、env-2）经CRC32等哈希后分布不均，这里取SHA-256的前4字节
+func ringHash(key string) uint32 {
+	sum := sha256.Sum256([]byte(key))
+	return binary.BigEndian.Uint32(sum[:4])
+}
+
+// get 返回key所属的节点
+func (r *hashRing) get(key string) *routerWorker {
+	h := ringHash(key)
+	i := sort.Search(len(r.hashes), func(i int) bool { return r.hashes[i] >= h })
+	if i == len(r.hashes) {
+		i = 0
+	}
+	return r.nodes[r.hashes[i]]
+}
+
+// routerWorker 一个工作节点（运行GrpcServer的进程）及其连接
+type routerWorker struct {
+	addr    string
+	conn    *grpc.ClientConn
+	client  pb.SimulationServiceClient
+	drained atomic.Bool // 已通过DrainWorker迁出全部环境，不再参与广播与查找会话
+}
+
+// routedSession 路由器记录的会话所在节点。会话内的环境全部位于打开会话的节点
+type routedSession struct {
+	worker   *routerWorker
+	apiKey   string // 打开会话时的API密钥，连接断开时以此关闭会话
+	conn     uint64 // 绑定的客户端连接，0表示未绑定
+	ttl      time.Duration
+	lastSeen time.Time
+}
+
+// Router 路由前端：实现与GrpcServer相同的gRPC服务，自身不创建环境，
+// 而是按env_id的一致性哈希将请求转发到一组工作节点，环境吞吐量随节点数扩展到多台机器。
+// 携带会话ID的请求转发到打开该会话的节点，迁移过的环境转发到其当前所在的节点；
+// 请求元数据中的会话ID、API密钥与管理令牌原样转发，租户、资源上限与管理令牌在各工作节点上配置
+type Router struct {
+	pb.UnimplementedSimulationServiceServer
+	workers []*routerWorker
+	ring    atomic.Pointer[hashRing]
+	next    atomic.Uint64 // 轮流选择打开会话与评估策略的节点
+	stripes [routeStripes]sync.RWMutex
+
+	mu         sync.Mutex
+	sessions   map[string]*routedSession
+	placements map[string]*routerWorker // 迁移后不在哈希环所指节点上的环境：注册表中的完整键 -> 所在节点
+	tenants    map[string]string        // API密钥 -> 租户名，用于计算环境在注册表中的完整键
+	conns      atomic.Uint64
+	logger     core.Logger
+}
+
+// NewRouter 创建转发到workers（host:port或unix://地址）的路由器，连接在第一次请求时建立
+func NewRouter(workers []string) (*Router, error) {
+	if len(workers) == 0 {
+		return nil, fmt.Errorf("router needs at least one worker")
+	}
+	r := &Router{
+		sessions:   make(map[string]*routedSession),
+		placements: make(map[string]*routerWorker),
+		tenants:    make(map[string]string),
+	}
+	seen := make(map[string]bool, len(workers))
+	for _, addr := range workers {
+		if addr == "" || seen[addr] {
+			r.Close()
+			return nil, fmt.Errorf("empty or duplicate worker address %q", addr)
+		}
+		seen[addr] = true
+		conn, err := grpc.NewClient(addr,
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithDefaultCallOptions(
+				grpc.MaxCallRecvMsgSize(MaxMessageSize),
+				grpc.MaxCallSendMsgSize(MaxMessageSize),
+			),
+		)
+		if err != nil {
+			r.Close()
+			return nil, fmt.Errorf("failed to connect to worker %s: %w", addr, err)
+		}
+		r.workers = append(r.workers, &routerWorker{addr: addr, conn: conn, client: pb.NewSimulationServiceClient(conn)})
+	}
+	r.ring.Store(newHashRing(r.workers))
+	return r, nil
+}
+
+// Workers 按NewRouter的参数顺序返回工作节点的地址
+func (r *Router) Workers() []string {
+	addrs := make([]string, len(r.workers))
+	for i, w := range r.workers {
+		addrs[i] = w.addr
+	}
+	return addrs
+}
+
+// SetLogger 设置运行日志，为nil时使用core.DefaultLogger()
+func (r *Router) SetLogger(logger core.Logger) {
+	r.logger = logger
+}
+
+// log 返回路由器的运行日志
+func (r *Router) log() core.Logger {
+	if r.logger != nil {
+		return r.logger
+	}
+	return core.DefaultLogger()
+}
+
+// WorkerFor 返回未迁移过的、不属于会话的env_id所在节点的地址
+func (r *Router) WorkerFor(envID string) string {
+	return r.ring.Load().get(envID).addr
+}
+
+// Close 关闭与工作节点的连接，节点上的环境不受影响
+func (r *Router) Close() error {
+	for _, w := range r.workers {
+		w.conn.Close()
+	}
+	return nil
+}
+
+// Serve 在lis上提供路由服务，如Listen返回的Unix套接字监听器
+func (r *Router) Serve(lis net.Listener) error {
+	grpcServer := grpc.NewServer(
+		grpc.MaxRecvMsgSize(MaxMessageSize),
+		grpc.MaxSendMsgSize(MaxMessageSize),
+		grpc.StatsHandler(routerConnHandler{router: r}),
+		grpc.ChainUnaryInterceptor(errorUnary),
+		grpc.ChainStreamInterceptor(errorStream),
+	)
+	pb.RegisterSimulationServiceServer(grpcServer, r)
+	reflection.Register(grpcServer)
+
+	r.log().Info("gRPC router forwarding to workers", "addr", lis.Addr().String(), "workers", r.Workers())
+	return grpcServer.Serve(lis)
+}
+
+// forwardContext 将请求元数据中的会话ID、API密钥与管理令牌转发给工作节点
+func forwardContext(ctx context.Context) context.Context {
+	md := metadata.MD{}
+	for _, key := range []string{SessionMetadataKey, APIKeyMetadataKey, AdminTokenMetadataKey} {
+		if value := metadataValue(ctx, key); value != "" {
+			md.Set(key, value)
+		}
+	}
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// route 返回处理env_id请求的节点：携带会话ID时为会话所在节点，迁移过的环境为其当前所在节点，
+// 否则按env_id哈希。请求转发完成后调用release；迁移环境时持有对应分段的写锁，新请求等待迁移完成
+func (r *Router) route(ctx context.Context, envID string) (w *routerWorker, release func(), err error) {
+	key := envID
+	sessionID := sessionIDFromContext(ctx)
+	if sessionID != "" {
+		key = sessionID
+	}
+	lock := r.stripe(key)
+	lock.RLock()
+	if sessionID != "" {
+		w, err = r.sessionWorker(ctx, sessionID)
+	} else {
+		w, err = r.placed(ctx, envID)
+	}
+	if err != nil {
+		lock.RUnlock()
+		return nil, nil, err
+	}
+	return w, lock.RUnlock, nil
+}
+
+// stripe 返回保护key（env_id或会话ID）路由的锁
+func (r *Router) stripe(key string) *sync.RWMutex {
+	return &r.stripes[crc32.ChecksumIEEE([]byte(key))%routeStripes]
+}
+
+// pick 轮流返回一个参与分配新环境的节点，用于不涉及已有环境的请求
+func (r *Router) pick() *routerWorker {
+	workers := r.ring.Load().workers
+	return workers[(r.next.Add(1)-1)%uint64(len(workers))]
+}
+
+// live 返回未排空的节点
+func (r *Router) live() []*routerWorker {
+	workers := make([]*routerWorker, 0, len(r.workers))
+	for _, w := range r.workers {
+		if !w.drained.Load() {
+			workers = append(workers, w)
+		}
+	}
+	return workers
+}
+
+// sessionWorker 返回会话id所在的节点。路由器未记录该会话时（如路由器重启或会话由其他路由器打开），
+// 依次询问各节点并记录接受该会话的节点
+func (r *Router) sessionWorker(ctx context.Context, id string) (*routerWorker, error) {
+	r.mu.Lock()
+	sess, ok := r.sessions[id]
+	if ok {
+		sess.lastSeen = time.Now()
+	}
+	r.mu.Unlock()
+	if ok {
+		return sess.worker, nil
+	}
+
+	md, _ := metadata.FromOutgoingContext(forwardContext(ctx))
+	md.Set(SessionMetadataKey, id)
+	probe := metadata.NewOutgoingContext(ctx, md)
+	for _, w := range r.live() {
+		if _, err := w.client.GetInfo(probe, &pb.GetInfoRequest{}); err == nil {
+			r.track(id, &routedSession{worker: w, apiKey: metadataValue(ctx, APIKeyMetadataKey), ttl: DefaultSessionTTL})
+			return w, nil
+		}
+	}
+	return nil, fmt.Errorf("session %s not found or expired", id)
+}
+
+// track 记录会话所在的节点，并清理空闲超过TTL（在节点上已过期）且未绑定连接的记录
+func (r *Router) track(id string, sess *routedSession) {
+	now := time.Now()
+	sess.lastSeen = now
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for other, s := range r.sessions {
+		if s.conn == 0 && now.Sub(s.lastSeen) > s.ttl {
+			delete(r.sessions, other)
+		}
+	}
+	r.sessions[id] = sess
+}
+
+// callAll 并发地对workers中的每个节点调用call，返回各节点的错误
+func callAll(workers []*routerWorker, call func(i int, w *routerWorker) error) []error {
+	errs := make([]error, len(workers))
+	var wg sync.WaitGroup
+	for i, w := range workers {
+		wg.Add(1)
+		go func(i int, w *routerWorker) {
+			defer wg.Done()
+			errs[i] = call(i, w)
+		}(i, w)
+	}
+	wg.Wait()
+	return errs
+}
+
+// broadcast 对workers中的节点调用call，返回按节点顺序的第一个错误
+func broadcast(workers []*routerWorker, call func(i int, w *routerWorker) error) error {
+	for _, err := range callAll(workers, call) {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// first 对未排空的节点调用call，有节点成功时返回nil，否则返回第一个节点的错误。
+// 用于按注册表中的完整键定位环境的管理请求
+func (r *Router) first(call func(w *routerWorker) error) error {
+	errs := callAll(r.live(), func(_ int, w *routerWorker) error { return call(w) })
+	for _, err := range errs {
+		if err == nil {
+			return nil
+		}
+	}
+	return errs[0]
+}
+
+// GetInfo 携带会话ID时返回会话所在节点的信息，否则合并各节点的环境列表
+func (r *Router) GetInfo(ctx context.Context, req *pb.GetInfoRequest) (*pb.GetInfoResponse, error) {
+	fctx := forwardContext(ctx)
+	if id := sessionIDFromContext(ctx); id != "" {
+		w, err := r.sessionWorker(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		return w.client.GetInfo(fctx, req)
+	}
+
+	workers := r.live()
+	resps := make([]*pb.GetInfoResponse, len(workers))
+	err := broadcast(workers, func(i int, w *routerWorker) error {
+		resp, err := w.client.GetInfo(fctx, req)
+		resps[i] = resp
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp := resps[0]
+	for _, other := range resps[1:] {
+		resp.EnvIds = append(resp.EnvIds, other.EnvIds...)
+		resp.StepLatency = mergeStepLatency(resp.StepLatency, other.StepLatency)
+	}
+	sort.Strings(resp.EnvIds)
+	// 节点返回的其他字段经Struct后整数已变成double，因此typed_info只覆盖路由改写的字段
+	overrides := map[string]interface{}{
+		"active_environments": len(resp.EnvIds),
+		"server_type":         "gRPC router",
+		"workers":             len(workers),
+	}
+	info := resp.Info.AsMap()
+	for k, v := range overrides {
+		info[k] = v
+	}
+	if resp.Info, err = protoStruct(info); err != nil {
+		return nil, fmt.Errorf("failed to create info struct: %v", err)
+	}
+	if resp.TypedInfo == nil {
+		resp.TypedInfo = make(map[string]*pb.Value)
+	}
+	for k, v := range typedScalars(overrides) {
+		resp.TypedInfo[k] = v
+	}
+	resp.Name = "Simulation gRPC Router"
+	return resp, nil
+}
+
+// mergeStepLatency 将other中各节点的步进延迟合并到into：次数与吞吐相加，均值按次数加权，
+// 分位数与最大值取各节点中较大者（合并后的分位数是上界）
+func mergeStepLatency(into, other map[string]*pb.ScenarioLatency) map[string]*pb.ScenarioLatency {
+	if into == nil {
+		into = make(map[string]*pb.ScenarioLatency, len(other))
+	}
+	for scenario, latency := range other {
+		merged, ok := into[scenario]
+		if !ok {
+			into[scenario] = latency
+			continue
+		}
+		merged.Step = mergeLatencySummary(merged.Step, latency.Step)
+		merged.Request = mergeLatencySummary(merged.Request, latency.Request)
+	}
+	return into
+}
+
+func mergeLatencySummary(a, b *pb.LatencySummary) *pb.LatencySummary {
+	if a.GetCount() == 0 {
+		return b
+	}
+	if b.GetCount() == 0 {
+		return a
+	}
+	count := a.Count + b.Count
+	return &pb.LatencySummary{
+		Count:     count,
+		MeanMs:    (a.MeanMs*float64(a.Count) + b.MeanMs*float64(b.Count)) / float64(count),
+		P50Ms:     math.Max(a.P50Ms, b.P50Ms),
+		P95Ms:     math.Max(a.P95Ms, b.P95Ms),
+		P99Ms:     math.Max(a.P99Ms, b.P99Ms),
+		MaxMs:     math.Max(a.MaxMs, b.MaxMs),
+		PerSecond: a.PerSecond + b.PerSecond,
+	}
+}
+
+// CreateEnvironment 在env_id所属的节点上创建环境
+func (r *Router) CreateEnvironment(ctx context.Context, req *pb.CreateEnvironmentRequest) (*pb.CreateEnvironmentResponse, error) {
+	w, release, err := r.route(ctx, req.EnvId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return w.client.CreateEnvironment(forwardContext(ctx), req)
+}
+
+// ResetEnvironment 转发到环境所在的节点
+func (r *Router) ResetEnvironment(ctx context.Context, req *pb.ResetEnvironmentRequest) (*pb.ResetEnvironmentResponse, error) {
+	w, release, err := r.route(ctx, req.EnvId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return w.client.ResetEnvironment(forwardContext(ctx), req)
+}
+
+// StepEnvironment 转发到环境所在的节点
+func (r *Router) StepEnvironment(ctx context.Context, req *pb.StepEnvironmentRequest) (*pb.StepEnvironmentResponse, error) {
+	w, release, err := r.route(ctx, req.EnvId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return w.client.StepEnvironment(forwardContext(ctx), req)
+}
+
+// CloseEnvironment 转发到环境所在的节点
+func (r *Router) CloseEnvironment(ctx context.Context, req *pb.CloseEnvironmentRequest) (*pb.CloseEnvironmentResponse, error) {
+	w, release, err := r.route(ctx, req.EnvId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	resp, err := w.client.CloseEnvironment(forwardContext(ctx), req)
+	if err == nil && resp.Success && sessionIDFromContext(ctx) == "" {
+		r.unplace(ctx, req.EnvId)
+	}
+	return resp, err
+}
+
+// GetSpaces 转发到环境所在的节点
+func (r *Router) GetSpaces(ctx context.Context, req *pb.GetSpacesRequest) (*pb.GetSpacesResponse, error) {
+	w, release, err := r.route(ctx, req.EnvId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return w.client.GetSpaces(forwardContext(ctx), req)
+}
+
+// GetMetadata 转发到环境所在的节点
+func (r *Router) GetMetadata(ctx context.Context, req *pb.GetMetadataRequest) (*pb.GetMetadataResponse, error) {
+	w, release, err := r.route(ctx, req.EnvId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return w.client.GetMetadata(forwardContext(ctx), req)
+}
+
+// DebugEnvironment 转发到环境所在的节点
+func (r *Router) DebugEnvironment(ctx context.Context, req *pb.DebugEnvironmentRequest) (*pb.DebugEnvironmentResponse, error) {
+	w, release, err := r.route(ctx, req.EnvId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return w.client.DebugEnvironment(forwardContext(ctx), req)
+}
+
+// GetCurriculum 转发到环境所在的节点
+func (r *Router) GetCurriculum(ctx context.Context, req *pb.GetCurriculumRequest) (*pb.CurriculumProgress, error) {
+	w, release, err := r.route(ctx, req.EnvId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return w.client.GetCurriculum(forwardContext(ctx), req)
+}
+
+// SetCurriculumStage 转发到环境所在的节点
+func (r *Router) SetCurriculumStage(ctx context.Context, req *pb.SetCurriculumStageRequest) (*pb.CurriculumProgress, error) {
+	w, release, err := r.route(ctx, req.EnvId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return w.client.SetCurriculumStage(forwardContext(ctx), req)
+}
+
+// EvaluatePolicy 轮流在各节点上评估策略
+func (r *Router) EvaluatePolicy(ctx context.Context, req *pb.EvaluatePolicyRequest) (*pb.EvaluatePolicyResponse, error) {
+	return r.pick().client.EvaluatePolicy(forwardContext(ctx), req)
+}
+
+// StreamStep 将流中的每一步转发到环境所在节点的流上，按请求顺序返回响应
+func (r *Router) StreamStep(stream pb.SimulationService_StreamStepServer) error {
+	ctx := stream.Context()
+	streams := make(map[*routerWorker]pb.SimulationService_StreamStepClient)
+	defer func() {
+		for _, s := range streams {
+			s.CloseSend()
+		}
+	}()
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		resp, err := r.streamStep(ctx, streams, req)
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
+
+// streamStep 在环境所在节点的流上执行一步，第一次用到该节点时建立流
+func (r *Router) streamStep(ctx context.Context, streams map[*routerWorker]pb.SimulationService_StreamStepClient, req *pb.StepEnvironmentRequest) (*pb.StepEnvironmentResponse, error) {
+	w, release, err := r.route(ctx, req.EnvId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	upstream, ok := streams[w]
+	if !ok {
+		if upstream, err = w.client.StreamStep(forwardContext(ctx)); err != nil {
+			return nil, err
+		}
+		streams[w] = upstream
+	}
+	if err := upstream.Send(req); err != nil {
+		return nil, err
+	}
+	return upstream.Recv()
+}
+
+// OpenSession 轮流在一个节点上打开会话，会话内的环境全部创建在该节点上。
+// bind_connection由路由器处理：客户端与路由器的连接断开时关闭节点上的会话
+func (r *Router) OpenSession(ctx context.Context, req *pb.OpenSessionRequest) (*pb.OpenSessionResponse, error) {
+	w := r.pick()
+	resp, err := w.client.OpenSession(forwardContext(ctx), &pb.OpenSessionRequest{Client: req.Client, TtlSeconds: req.TtlSeconds})
+	if err != nil {
+		return nil, err
+	}
+	sess := &routedSession{
+		worker: w,
+		apiKey: metadataValue(ctx, APIKeyMetadataKey),
+		ttl:    time.Duration(resp.TtlSeconds) * time.Second,
+	}
+	if req.BindConnection {
+		sess.conn = connID(ctx)
+	}
+	r.track(resp.SessionId, sess)
+	return resp, nil
+}
+
+// CloseSession 关闭会话所在节点上的会话
+func (r *Router) CloseSession(ctx context.Context, req *pb.CloseSessionRequest) (*pb.CloseSessionResponse, error) {
+	w, err := r.sessionWorker(ctx, req.SessionId)
+	if err != nil {
+		return nil, errSessionNotFound(req.SessionId)
+	}
+	resp, err := w.client.CloseSession(forwardContext(ctx), req)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	delete(r.sessions, req.SessionId)
+	r.mu.Unlock()
+	return resp, nil
+}
+
+// closeConnSessions 关闭绑定到已断开的客户端连接conn的会话
+func (r *Router) closeConnSessions(conn uint64) {
+	matched := make(map[string]*routedSession)
+	r.mu.Lock()
+	for id, sess := range r.sessions {
+		if sess.conn == conn {
+			matched[id] = sess
+			delete(r.sessions, id)
+		}
+	}
+	r.mu.Unlock()
+	for id, sess := range matched {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		if sess.apiKey != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, APIKeyMetadataKey, sess.apiKey)
+		}
+		if _, err := sess.worker.client.CloseSession(ctx, &pb.CloseSessionRequest{SessionId: id}); err != nil {
+			r.log().Warn("router: failed to close session", "session_id", id, "worker", sess.worker.addr, "error", err)
+		}
+		cancel()
+	}
+}
+
+// ListEnvironments 管理接口：合并各节点的环境列表，任一节点正在排空时draining为true
+func (r *Router) ListEnvironments(ctx context.Context, req *pb.ListEnvironmentsRequest) (*pb.ListEnvironmentsResponse, error) {
+	fctx := forwardContext(ctx)
+	workers := r.live()
+	resps := make([]*pb.ListEnvironmentsResponse, len(workers))
+	err := broadcast(workers, func(i int, w *routerWorker) error {
+		resp, err := w.client.ListEnvironments(fctx, req)
+		resps[i] = resp
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb.ListEnvironmentsResponse{}
+	for _, other := range resps {
+		resp.Environments = append(resp.Environments, other.Environments...)
+		resp.Draining = resp.Draining || other.Draining
+	}
+	sort.Slice(resp.Environments, func(i, j int) bool { return resp.Environments[i].EnvId < resp.Environments[j].EnvId })
+	return resp, nil
+}
+
+// ForceCloseEnvironment 管理接口：在持有该键的节点上强制关闭环境
+func (r *Router) ForceCloseEnvironment(ctx context.Context, req *pb.ForceCloseEnvironmentRequest) (*pb.ForceCloseEnvironmentResponse, error) {
+	fctx := forwardContext(ctx)
+	var found atomic.Pointer[pb.ForceCloseEnvironmentResponse]
+	err := r.first(func(w *routerWorker) error {
+		resp, err := w.client.ForceCloseEnvironment(fctx, req)
+		if err == nil {
+			found.Store(resp)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.unplaceKey(req.EnvId)
+	return found.Load(), nil
+}
+
+// DumpEnvironmentState 管理接口：导出持有该键的节点上的环境状态
+func (r *Router) DumpEnvironmentState(ctx context.Context, req *pb.DumpEnvironmentStateRequest) (*pb.DumpEnvironmentStateResponse, error) {
+	fctx := forwardContext(ctx)
+	var found atomic.Pointer[pb.DumpEnvironmentStateResponse]
+	err := r.first(func(w *routerWorker) error {
+		resp, err := w.client.DumpEnvironmentState(fctx, req)
+		if err == nil {
+			found.Store(resp)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return found.Load(), nil
+}
+
+// Drain 管理接口：同时排空所有节点，返回各节点剩余与强制关闭的环境数之和
+func (r *Router) Drain(ctx context.Context, req *pb.DrainRequest) (*pb.DrainResponse, error) {
+	fctx := forwardContext(ctx)
+	workers := r.live()
+	resps := make([]*pb.DrainResponse, len(workers))
+	err := broadcast(workers, func(i int, w *routerWorker) error {
+		resp, err := w.client.Drain(fctx, req)
+		resps[i] = resp
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb.DrainResponse{}
+	for _, other := range resps {
+		resp.RemainingEnvironments += other.RemainingEnvironments
+		resp.ClosedEnvironments += other.ClosedEnvironments
+	}
+	return resp, nil
+}
+
+// RegisterScenario 在全部工作节点（含已排空的节点，迁移时仍可能用到）上注册上传的场景，任一节点失败时返回错误
+func (r *Router) RegisterScenario(ctx context.Context, req *pb.RegisterScenarioRequest) (*pb.RegisterScenarioResponse, error) {
+	fctx := forwardContext(ctx)
+	err := broadcast(r.workers, func(i int, w *routerWorker) error {
+		_, err := w.client.RegisterScenario(fctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.RegisterScenarioResponse{}, nil
+}
+
+// routerConnHandler 为每个客户端连接编号，连接断开时关闭绑定到该连接的会话
+type routerConnHandler struct {
+	router *Router
+}
+
+func (h routerConnHandler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return context.WithValue(ctx, connIDKey{}, h.router.conns.Add(1))
+}
+
+func (h routerConnHandler) HandleConn(ctx context.Context, s stats.ConnStats) {
+	if _, ok := s.(*stats.ConnEnd); !ok {
+		return
+	}
+	if id := connID(ctx); id != 0 {
+		go h.router.closeConnSessions(id)
+	}
+}
+
+func (h routerConnHandler) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return ctx
+}
+
+func (h routerConnHandler) HandleRPC(context.Context, stats.RPCStats) {}
@@ -0,0 +1,352 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+)
+
+// SessionMetadataKey gRPC请求元数据中携带会话ID的键
+const SessionMetadataKey = "session-id"
+
+// SessionHeader HTTP请求中携带会话ID的头
+const SessionHeader = "X-Session-Id"
+
+// DefaultSessionTTL 会话的默认空闲超时
+const DefaultSessionTTL = 10 * time.Minute
+
+// Session 一个客户端的会话。携带会话ID的请求中的env_id只在会话内可见，
+// 不同会话可以使用相同的env_id；会话关闭或过期时其创建的环境全部关闭
+type Session struct {
+	ID      string
+	Tenant  string // 打开会话的租户，未配置租户时为空
+	Client  string
+	TTL     time.Duration
+	Created time.Time
+
+	lastSeen atomic.Int64 // 最近一次请求的时间（UnixNano）
+	conn     uint64       // 绑定的gRPC连接，0表示未绑定
+
+	mu     sync.Mutex
+	envs   map[string]func() // 注册表中的键 -> 关闭该环境的函数
+	closed bool
+}
+
+// key 返回会话内的env_id在注册表中的键
+func (sess *Session) key(envID string) string {
+	return sess.ID + "/" + envID
+}
+
+// touch 以当前时间续期
+func (sess *Session) touch() {
+	sess.lastSeen.Store(time.Now().UnixNano())
+}
+
+// expired 判断会话在now时是否已空闲超过TTL
+func (sess *Session) expired(now time.Time) bool {
+	return now.Sub(time.Unix(0, sess.lastSeen.Load())) > sess.TTL
+}
+
+// own 将会话内新建并已注册到registry的环境记入会话，会话关闭或过期时从registry移除并关闭该环境。
+// 会话已关闭时立即移除并关闭环境，返回false
+func (sess *Session) own(registry *EnvRegistry, key string, t telemetry) bool {
+	closeEnv := func() {
+		if env, ok := registry.Remove(key); ok {
+			env.Close()
+			t.reportClosed(key)
+		}
+	}
+	sess.mu.Lock()
+	if !sess.closed {
+		sess.envs[key] = closeEnv
+	}
+	closed := sess.closed
+	sess.mu.Unlock()
+	if closed {
+		closeEnv()
+	}
+	return !closed
+}
+
+// untrack 移除已由客户端关闭的环境
+func (sess *Session) untrack(key string) {
+	sess.mu.Lock()
+	delete(sess.envs, key)
+	sess.mu.Unlock()
+}
+
+// Len 返回会话内的活跃环境数
+func (sess *Session) Len() int {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	return len(sess.envs)
+}
+
+// EnvIDs 按字母顺序返回会话内的env_id
+func (sess *Session) EnvIDs() []string {
+	sess.mu.Lock()
+	ids := make([]string, 0, len(sess.envs))
+	for key := range sess.envs {
+		ids = append(ids, strings.TrimPrefix(key, sess.ID+"/"))
+	}
+	sess.mu.Unlock()
+	sort.Strings(ids)
+	return ids
+}
+
+// close 关闭会话的所有环境，返回关闭的环境数
+func (sess *Session) close() int {
+	sess.mu.Lock()
+	sess.closed = true
+	envs := sess.envs
+	sess.envs = nil
+	sess.mu.Unlock()
+	for _, closeEnv := range envs {
+		closeEnv()
+	}
+	return len(envs)
+}
+
+// SessionManager 按ID索引的会话表，可被多个goroutine并发使用。
+// 第一次打开会话时启动后台清理，定期关闭空闲超时的会话
+type SessionManager struct {
+	mu         sync.Mutex
+	sessions   map[string]*Session
+	reaper     sync.Once
+	conns      atomic.Uint64
+	defaultTTL time.Duration // 未指定TTL的会话使用的TTL，0表示DefaultSessionTTL
+	maxTTL     time.Duration // 客户端可以请求的TTL上限，0表示不限制
+}
+
+// NewSessionManager 创建空的会话表
+func NewSessionManager() *SessionManager {
+	return &SessionManager{sessions: make(map[string]*Session)}
+}
+
+// SetTTL 设置之后打开的会话未指定TTL时使用的TTL（<=0时为DefaultSessionTTL）与可以请求的TTL上限（<=0时不限制），
+// 超过上限的TTL按上限处理
+func (m *SessionManager) SetTTL(def, max time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.defaultTTL, m.maxTTL = def, max
+}
+
+// ttl 返回请求ttl的会话实际使用的TTL
+func (m *SessionManager) ttl(ttl time.Duration) time.Duration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if ttl <= 0 {
+		ttl = m.defaultTTL
+	}
+	if ttl <= 0 {
+		ttl = DefaultSessionTTL
+	}
+	if m.maxTTL > 0 && ttl > m.maxTTL {
+		ttl = m.maxTTL
+	}
+	return ttl
+}
+
+// Open 打开会话，ttl<=0时使用默认TTL（见SetTTL）；conn非0时会话随该gRPC连接断开而关闭
+func (m *SessionManager) Open(tenant, client string, ttl time.Duration, conn uint64) *Session {
+	return m.open(newSessionID(), tenant, client, ttl, conn)
+}
+
+// restore 以快照中的ID重新打开会话（服务端重启后），会话已存在时直接返回。
+// 恢复的会话不再绑定连接，客户端未重新使用时按TTL过期
+func (m *SessionManager) restore(id, tenant, client string, ttl time.Duration) *Session {
+	if sess, ok := m.lookup(id); ok {
+		return sess
+	}
+	return m.open(id, tenant, client, ttl, 0)
+}
+
+func (m *SessionManager) open(id, tenant, client string, ttl time.Duration, conn uint64) *Session {
+	sess := &Session{
+		ID:      id,
+		Tenant:  tenant,
+		Client:  client,
+		TTL:     m.ttl(ttl),
+		Created: time.Now(),
+		conn:    conn,
+		envs:    make(map[string]func()),
+	}
+	sess.touch()
+
+	m.mu.Lock()
+	m.sessions[sess.ID] = sess
+	m.mu.Unlock()
+	m.reaper.Do(func() { go m.reap() })
+	return sess
+}
+
+// Get 返回id对应的未过期会话并为其续期
+func (m *SessionManager) Get(id string) (*Session, bool) {
+	m.mu.Lock()
+	sess, ok := m.sessions[id]
+	m.mu.Unlock()
+	if !ok || sess.expired(time.Now()) {
+		return nil, false
+	}
+	sess.touch()
+	return sess, true
+}
+
+// lookup 返回id对应的会话，不续期
+func (m *SessionManager) lookup(id string) (*Session, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sess, ok := m.sessions[id]
+	return sess, ok
+}
+
+// Close 关闭会话及其所有环境，返回关闭的环境数；会话不存在时ok为false
+func (m *SessionManager) Close(id string) (closed int, ok bool) {
+	m.mu.Lock()
+	sess, ok := m.sessions[id]
+	delete(m.sessions, id)
+	m.mu.Unlock()
+	if !ok {
+		return 0, false
+	}
+	return sess.close(), true
+}
+
+// scope 返回租户namespace的请求中env_id在注册表中的键及其所属会话：sessionID为空时键为租户内的env_id，
+// 否则为会话内的键；会话不存在、已过期或属于其他租户时返回错误
+func (m *SessionManager) scope(namespace, sessionID, envID string) (string, *Session, error) {
+	if sessionID == "" {
+		return tenantKey(namespace, envID), nil, nil
+	}
+	sess, ok := m.Get(sessionID)
+	if !ok || sess.Tenant != namespace {
+		return "", nil, fmt.Errorf("session %s not found or expired", sessionID)
+	}
+	return sess.key(envID), sess, nil
+}
+
+// visibleIDs 返回请求可见的env_id：会话内只列出该会话的环境，不带会话时只列出本租户不属于会话的环境
+func (m *SessionManager) visibleIDs(registry *EnvRegistry, sess *Session, namespace string) []string {
+	if sess != nil {
+		return sess.EnvIDs()
+	}
+	prefix := tenantKey(namespace, "")
+	all := registry.IDs()
+	ids := all[:0]
+	for _, id := range all {
+		if !m.owned(id) && strings.HasPrefix(id, prefix) {
+			ids = append(ids, strings.TrimPrefix(id, prefix))
+		}
+	}
+	return ids
+}
+
+// Len 返回打开的会话数
+func (m *SessionManager) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.sessions)
+}
+
+// owned 判断注册表中的键是否属于某个打开的会话
+func (m *SessionManager) owned(key string) bool {
+	i := strings.IndexByte(key, '/')
+	if i <= 0 {
+		return false
+	}
+	m.mu.Lock()
+	_, ok := m.sessions[key[:i]]
+	m.mu.Unlock()
+	return ok
+}
+
+// reap 定期关闭空闲超时的会话
+func (m *SessionManager) reap() {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for now := range ticker.C {
+		m.closeWhere(func(sess *Session) bool { return sess.expired(now) })
+	}
+}
+
+// closeWhere 关闭满足条件的会话
+func (m *SessionManager) closeWhere(match func(*Session) bool) {
+	var matched []*Session
+	m.mu.Lock()
+	for id, sess := range m.sessions {
+		if match(sess) {
+			matched = append(matched, sess)
+			delete(m.sessions, id)
+		}
+	}
+	m.mu.Unlock()
+	for _, sess := range matched {
+		sess.close()
+	}
+}
+
+func newSessionID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// sessionIDFromContext 读取gRPC请求元数据中的会话ID
+func sessionIDFromContext(ctx context.Context) string {
+	return metadataValue(ctx, SessionMetadataKey)
+}
+
+// metadataValue 读取gRPC请求元数据中key的第一个值
+func metadataValue(ctx context.Context, key string) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// connIDKey gRPC连接上下文中保存连接编号的键
+type connIDKey struct{}
+
+// connID 返回请求所在gRPC连接的编号，不经过sessionConnHandler的请求为0
+func connID(ctx context.Context) uint64 {
+	id, _ := ctx.Value(connIDKey{}).(uint64)
+	return id
+}
+
+// sessionConnHandler 为每个gRPC连接编号，连接断开时关闭绑定到该连接的会话。
+// 只处理连接事件，不读取RPC的消息
+type sessionConnHandler struct {
+	sessions *SessionManager
+}
+
+func (h sessionConnHandler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return context.WithValue(ctx, connIDKey{}, h.sessions.conns.Add(1))
+}
+
+func (h sessionConnHandler) HandleConn(ctx context.Context, s stats.ConnStats) {
+	if _, ok := s.(*stats.ConnEnd); !ok {
+		return
+	}
+	if id := connID(ctx); id != 0 {
+		h.sessions.closeWhere(func(sess *Session) bool { return sess.conn == id })
+	}
+}
+
+func (h sessionConnHandler) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return ctx
+}
+
+func (h sessionConnHandler) HandleRPC(context.Context, stats.RPCStats) {}
@@ -0,0 +1,457 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/jelech/rl_env_engine/core"
+	"github.com/jelech/rl_env_engine/core/runstore"
+)
+
+// 共享内存传输：同机的Python进程通过Unix套接字发送控制消息，观察与动作经由内存映射文件交换，
+// 每步只在套接字上往返几十字节，省去网络序列化，也不需要cgo。每个连接拥有一个环境和一个映射文件。
+//
+// 控制消息（整数均为小端序）：
+//
+//	请求: uint8 操作码 | uint32 负载长度 | 负载
+//	响应: uint8 状态(0成功, 1失败) | uint32 负载长度 | 负载（失败时为错误信息）
+//
+// 操作码：
+//
+//	1 create  负载为ShmCreateRequest的JSON，响应ShmCreateResponse的JSON
+//	2 reset   无负载
+//	3 step    无负载，动作值预先写入映射文件的动作区
+//	4 close   无负载，关闭环境并删除映射文件
+//
+// reset与step的响应负载为：uint64 结果在映射文件中的偏移 | uint64 映射文件大小 | info的JSON（仅在create时开启info）。
+// 映射文件大小变化时客户端需重新映射并重新读取文件头。
+//
+// 映射文件布局（浮点数宽度由环境的dtype决定，float32为4字节，否则为8字节）：
+//
+//	文件头(64字节): "RLENVSHM" | uint32 版本 | uint32 浮点数宽度 | uint32 槽数 | uint32 保留 |
+//	                uint64 槽大小 | uint64 首个槽的偏移 | uint64 动作区偏移 | uint64 动作区容量（值的个数） | 8字节保留
+//	动作区: uint32 动作值个数 | 4字节保留 | 动作值...（按/step_raw的规则切分给各智能体）
+//	槽: 环形使用，每次reset或step写入下一个槽；内容为/step_raw的响应格式，其后追加n个截断标志(1或0)
+//
+// 结果在之后的槽数-1步内保持不变，客户端可以直接引用映射内存而不复制
+
+// 共享内存控制消息的操作码
+const (
+	shmOpCreate byte = 1
+	shmOpReset  byte = 2
+	shmOpStep   byte = 3
+	shmOpClose  byte = 4
+)
+
+// 共享内存控制消息的响应状态
+const (
+	shmStatusOK    byte = 0
+	shmStatusError byte = 1
+)
+
+const (
+	shmMagic      = "RLENVSHM"
+	shmVersion    = 1
+	shmHeaderSize = 64
+	shmPageSize   = 4096
+)
+
+const (
+	// DefaultShmSlots 映射文件中结果槽的默认个数
+	DefaultShmSlots = 4
+	// DefaultShmMaxAgents 动作区默认可容纳的智能体数
+	DefaultShmMaxAgents = 64
+)
+
+// ShmCreateRequest 共享内存传输的create消息
+type ShmCreateRequest struct {
+	Scenario  string                 `json:"scenario"`
+	Config    map[string]interface{} `json:"config,omitempty"`
+	Slots     int                    `json:"slots,omitempty"`      // 结果槽数，0时为DefaultShmSlots
+	MaxAgents int                    `json:"max_agents,omitempty"` // 动作区可容纳的智能体数，0时为DefaultShmMaxAgents
+	Info      bool                   `json:"info,omitempty"`       // reset与step的响应携带info
+}
+
+// ShmCreateResponse 共享内存传输create消息的响应
+type ShmCreateResponse struct {
+	Path     string               `json:"path"`   // 映射文件路径，客户端以读写方式映射
+	Size     int                  `json:"size"`   // 映射文件大小
+	Spaces   core.SpaceDefinition `json:"spaces"` // 与/spaces相同的Gym兼容形式
+	Metadata core.EnvMetadata     `json:"metadata"`
+}
+
+// ShmServer 共享内存传输的服务端
+type ShmServer struct {
+	engine    *core.SimulationEngine
+	telemetry telemetry
+	dir       string
+	sessions  atomic.Int64
+}
+
+// NewShmServer 创建注册了全部内置场景的共享内存服务端，映射文件默认放在/dev/shm（不存在时为临时目录）
+func NewShmServer() *ShmServer {
+	dir := os.TempDir()
+	if info, err := os.Stat("/dev/shm"); err == nil && info.IsDir() {
+		dir = "/dev/shm"
+	}
+	return &ShmServer{engine: newBuiltinEngine(), dir: dir}
+}
+
+// Engine 返回注册场景的仿真引擎
+func (s *ShmServer) Engine() *core.SimulationEngine {
+	return s.engine
+}
+
+// SetDir 设置之后创建的映射文件所在的目录
+func (s *ShmServer) SetDir(dir string) {
+	s.dir = dir
+}
+
+// SetMetricsSink 将之后创建的环境的回合指标与生命周期事件发布到sink，nil表示不发布
+func (s *ShmServer) SetMetricsSink(sink core.MetricsSink) {
+	s.telemetry.metrics = sink
+}
+
+// SetRunStore 将之后创建的环境的运行与回合记录到store，nil表示不记录
+func (s *ShmServer) SetRunStore(store *runstore.Store) {
+	s.telemetry.runs = store
+}
+
+// SetOutputDir 设置服务端输出目录，客户端给出的record_path、video_dir与tensorboard_dir解析到该目录之下，为空时拒绝
+func (s *ShmServer) SetOutputDir(dir string) {
+	s.telemetry.outputDir = dir
+}
+
+// SetLogger 设置运行日志，为nil时使用core.DefaultLogger()
+func (s *ShmServer) SetLogger(logger core.Logger) {
+	s.telemetry.logger = logger
+}
+
+// ListenAndServe 在Unix套接字path上监听并处理连接。path处残留的套接字文件会被替换，
+// 套接字权限为0600，只有同一用户的进程可以连接
+func (s *ShmServer) ListenAndServe(path string) error {
+	lis, err := listenUnix(path)
+	if err != nil {
+		return err
+	}
+	defer lis.Close()
+
+	s.telemetry.log().Info("Starting shared-memory Simulation server", "path", path, "dir", s.dir)
+	return s.Serve(lis)
+}
+
+// Serve 接受lis上的连接，每个连接由单独的goroutine处理
+func (s *ShmServer) Serve(lis net.Listener) error {
+	for {
+		conn, err := lis.Accept()
+		if err != nil {
+			return err
+		}
+		go s.serveConn(conn)
+	}
+}
+
+// serveConn 依次处理一个连接的控制消息，连接断开时关闭环境并删除映射文件
+func (s *ShmServer) serveConn(conn net.Conn) {
+	defer conn.Close()
+	var session *shmSession
+	defer func() {
+		if session != nil {
+			session.close()
+			s.telemetry.reportClosed(session.envID)
+		}
+	}()
+
+	r := bufio.NewReader(conn)
+	var header [5]byte
+	var payload, frame []byte
+	for {
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			return
+		}
+		op, n := header[0], binary.LittleEndian.Uint32(header[1:])
+		if n > MaxMessageSize {
+			conn.Write(shmFrame(nil, shmStatusError, []byte(fmt.Sprintf("message of %d bytes exceeds the limit", n))))
+			return
+		}
+		if cap(payload) < int(n) {
+			payload = make([]byte, n)
+		}
+		payload = payload[:n]
+		if _, err := io.ReadFull(r, payload); err != nil {
+			return
+		}
+
+		reply, err := s.handle(&session, op, payload)
+		if err != nil {
+			frame = shmFrame(frame[:0], shmStatusError, []byte(err.Error()))
+		} else {
+			frame = shmFrame(frame[:0], shmStatusOK, reply)
+		}
+		if _, err := conn.Write(frame); err != nil {
+			return
+		}
+		if op == shmOpClose {
+			return
+		}
+	}
+}
+
+// shmFrame 将响应编码为控制消息帧追加到dst
+func shmFrame(dst []byte, status byte, payload []byte) []byte {
+	dst = append(dst, status)
+	dst = binary.LittleEndian.AppendUint32(dst, uint32(len(payload)))
+	return append(dst, payload...)
+}
+
+// handle 执行一条控制消息，返回响应负载
+func (s *ShmServer) handle(session **shmSession, op byte, payload []byte) ([]byte, error) {
+	if op == shmOpCreate {
+		if *session != nil {
+			return nil, errors.New("an environment was already created on this connection")
+		}
+		var req ShmCreateRequest
+		if err := json.Unmarshal(payload, &req); err != nil {
+			return nil, fmt.Errorf("invalid create request: %v", err)
+		}
+		created, err := s.create(req)
+		if err != nil {
+			return nil, err
+		}
+		*session = created
+		return json.Marshal(created.createResponse())
+	}
+
+	sess := *session
+	if sess == nil {
+		return nil, errors.New("no environment has been created on this connection")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	switch op {
+	case shmOpReset:
+		return sess.reset(ctx)
+	case shmOpStep:
+		return sess.step(ctx)
+	case shmOpClose:
+		*session = nil
+		s.telemetry.reportClosed(sess.envID)
+		return nil, sess.close()
+	}
+	return nil, fmt.Errorf("unknown operation %d", op)
+}
+
+// create 创建环境及其映射文件
+func (s *ShmServer) create(req ShmCreateRequest) (*shmSession, error) {
+	if req.Slots < 0 || req.MaxAgents < 0 {
+		return nil, fmt.Errorf("slots and max_agents must not be negative")
+	}
+	config := core.NewBaseConfig(req.Config)
+	dtype, err := core.ParseDtype(config)
+	if err != nil {
+		return nil, err
+	}
+
+	envID := fmt.Sprintf("shm-%d", s.sessions.Add(1))
+	env, err := s.engine.CreateEnvironment(req.Scenario, config)
+	if err == nil {
+		env, err = s.telemetry.wrapEnvironment(s.engine.Hooks(), env, config, req.Scenario, envID, req.Config)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create environment: %v", err)
+	}
+
+	sess := &shmSession{
+		envID:      envID,
+		env:        env,
+		truncation: core.NewTruncationTracker(env),
+		info:       req.Info,
+		width:      8,
+		slots:      req.Slots,
+	}
+	if dtype == core.DtypeFloat32 {
+		sess.width = 4
+	}
+	if sess.slots == 0 {
+		sess.slots = DefaultShmSlots
+	}
+	maxAgents := req.MaxAgents
+	if maxAgents == 0 {
+		maxAgents = DefaultShmMaxAgents
+	}
+	spaces := env.GetSpaces()
+	sess.actionCap = max(core.ActionValueCount(spaces.ActionSpace), 1) * maxAgents
+	sess.slotsOffset = alignUp(shmHeaderSize+8+sess.actionCap*sess.width, shmHeaderSize)
+	// 先按单个智能体估计槽大小，结果放不下时再扩大
+	sess.slotSize = alignUp(8+spaces.ObservationSpace.Size()*sess.width+4*sess.width, shmPageSize)
+
+	if sess.file, err = os.CreateTemp(s.dir, "rlenv-*.shm"); err != nil {
+		env.Close()
+		return nil, err
+	}
+	if err := sess.remap(); err != nil {
+		sess.close()
+		return nil, err
+	}
+	return sess, nil
+}
+
+// shmSession 一个连接的环境与映射文件，只被该连接的goroutine使用
+type shmSession struct {
+	envID      string
+	env        core.Environment
+	truncation *core.TruncationTracker
+	info       bool
+
+	file        *os.File
+	region      []byte
+	width       int
+	slots       int
+	slotSize    int
+	slotsOffset int
+	actionCap   int
+	next        int // 下一个写入的槽
+}
+
+func (sess *shmSession) createResponse() ShmCreateResponse {
+	return ShmCreateResponse{
+		Path:     sess.file.Name(),
+		Size:     len(sess.region),
+		Spaces:   sess.env.GetSpaces(),
+		Metadata: core.GetEnvMetadata(sess.env),
+	}
+}
+
+// remap 按当前布局调整映射文件大小，重新映射并写入文件头
+func (sess *shmSession) remap() error {
+	size := sess.slotsOffset + sess.slots*sess.slotSize
+	if sess.region != nil {
+		if err := unmapFile(sess.region); err != nil {
+			return err
+		}
+		sess.region = nil
+	}
+	if err := sess.file.Truncate(int64(size)); err != nil {
+		return err
+	}
+	region, err := mapFile(sess.file, size)
+	if err != nil {
+		return err
+	}
+	sess.region = region
+
+	h := region[:shmHeaderSize]
+	copy(h, shmMagic)
+	binary.LittleEndian.PutUint32(h[8:], shmVersion)
+	binary.LittleEndian.PutUint32(h[12:], uint32(sess.width))
+	binary.LittleEndian.PutUint32(h[16:], uint32(sess.slots))
+	binary.LittleEndian.PutUint64(h[24:], uint64(sess.slotSize))
+	binary.LittleEndian.PutUint64(h[32:], uint64(sess.slotsOffset))
+	binary.LittleEndian.PutUint64(h[40:], shmHeaderSize)
+	binary.LittleEndian.PutUint64(h[48:], uint64(sess.actionCap))
+	return nil
+}
+
+func (sess *shmSession) reset(ctx context.Context) ([]byte, error) {
+	observations, err := sess.env.Reset(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reset environment: %w", err)
+	}
+	sess.truncation.Reset()
+	return sess.result(observations, nil, nil, nil)
+}
+
+func (sess *shmSession) step(ctx context.Context) ([]byte, error) {
+	area := sess.region[shmHeaderSize:]
+	count := int(binary.LittleEndian.Uint32(area))
+	if count > sess.actionCap {
+		return nil, fmt.Errorf("%d action values exceed the capacity of %d", count, sess.actionCap)
+	}
+	values := make([]float64, count)
+	for i := range values {
+		if sess.width == 4 {
+			values[i] = float64(math.Float32frombits(binary.LittleEndian.Uint32(area[8+i*4:])))
+		} else {
+			values[i] = math.Float64frombits(binary.LittleEndian.Uint64(area[8+i*8:]))
+		}
+	}
+	actions, err := rawActions(sess.env.GetSpaces().ActionSpace, values)
+	if err != nil {
+		return nil, fmt.Errorf("invalid action: %v", err)
+	}
+
+	observations, rewards, done, err := sess.env.Step(ctx, actions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to step environment: %w", err)
+	}
+	_, truncated := sess.truncation.Step(done, sess.env.GetInfo())
+	return sess.result(observations, rewards, done, truncated)
+}
+
+// result 将一步的结果写入下一个槽，返回reset与step的响应负载
+func (sess *shmSession) result(observations []core.Observation, rewards []float64, done, truncated []bool) ([]byte, error) {
+	defer core.ReleaseObservations(observations)
+
+	slot := sess.next
+	sess.next = (sess.next + 1) % sess.slots
+	offset := sess.slotsOffset + slot*sess.slotSize
+	// 直接编码到映射内存；超出槽大小时append会改写到新分配的数组，扩大槽后再复制回来
+	out := encodeRawStep(sess.region[offset:offset:offset+sess.slotSize], observations, rewards, done, sess.width)
+	for i := range observations {
+		var t float64
+		if i < len(truncated) && truncated[i] {
+			t = 1
+		}
+		out = appendRawFloat(out, t, sess.width)
+	}
+	if len(out) > sess.slotSize {
+		for sess.slotSize < len(out) {
+			sess.slotSize *= 2
+		}
+		if err := sess.remap(); err != nil {
+			return nil, err
+		}
+		offset = sess.slotsOffset + slot*sess.slotSize
+		copy(sess.region[offset:], out)
+	}
+
+	reply := binary.LittleEndian.AppendUint64(nil, uint64(offset))
+	reply = binary.LittleEndian.AppendUint64(reply, uint64(len(sess.region)))
+	if sess.info {
+		info, err := json.Marshal(sess.env.GetInfo())
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode info: %v", err)
+		}
+		reply = append(reply, info...)
+	}
+	return reply, nil
+}
+
+// close 关闭环境，解除映射并删除映射文件
+func (sess *shmSession) close() error {
+	err := sess.env.Close()
+	if sess.region != nil {
+		unmapFile(sess.region)
+		sess.region = nil
+	}
+	if sess.file != nil {
+		sess.file.Close()
+		os.Remove(sess.file.Name())
+		sess.file = nil
+	}
+	return err
+}
+
+func alignUp(n, align int) int {
+	return (n + align - 1) / align * align
+}
@@ -0,0 +1,20 @@
+//go:build !unix
+
+package server
+
+import (
+	"errors"
+	"os"
+)
+
+var errShmUnsupported = errors.New("shared-memory transport is not supported on this platform")
+
+// mapFile 在不支持mmap的平台上总是返回错误
+func mapFile(f *os.File, size int) ([]byte, error) {
+	return nil, errShmUnsupported
+}
+
+// unmapFile 在不支持mmap的平台上总是返回错误
+func unmapFile(region []byte) error {
+	return errShmUnsupported
+}
@@ -0,0 +1,18 @@
+//go:build unix
+
+package server
+
+import (
+	"os"
+	"syscall"
+)
+
+// mapFile 以共享读写方式映射文件的前size字节
+func mapFile(f *os.File, size int) ([]byte, error) {
+	return syscall.Mmap(int(f.Fd()), 0, size, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+}
+
+// unmapFile 解除mapFile建立的映射
+func unmapFile(region []byte) error {
+	return syscall.Munmap(region)
+}
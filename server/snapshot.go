@@ -0,0 +1,322 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jelech/rl_env_engine/core"
+	"github.com/jelech/rl_env_engine/core/wasm"
+)
+
+// DefaultSnapshotInterval 定期保存快照的默认间隔
+const DefaultSnapshotInterval = 30 * time.Second
+
+// snapshotVersion 快照文件格式的版本，格式不兼容时递增
+const snapshotVersion = 1
+
+// Snapshot 服务端状态快照：实现了core.Checkpointer的活跃环境及其所属会话，以及上传的WASM场景。
+// 定期写入磁盘，服务端重启（如升级）后据此恢复长时间运行的仿真
+type Snapshot struct {
+	Version      int                `json:"version"`
+	Created      time.Time          `json:"created"`
+	Scenarios    []ScenarioSnapshot `json:"scenarios,omitempty"`
+	Sessions     []SessionSnapshot  `json:"sessions,omitempty"`
+	Environments []EnvSnapshot      `json:"environments"`
+}
+
+// SessionSnapshot 快照中的会话
+type SessionSnapshot struct {
+	ID         string  `json:"id"`
+	Tenant     string  `json:"tenant,omitempty"`
+	Client     string  `json:"client,omitempty"`
+	TTLSeconds float64 `json:"ttl_seconds"`
+}
+
+// EnvSnapshot 快照中的环境
+type EnvSnapshot struct {
+	EnvID        string                 `json:"env_id"` // 注册表中的完整键，会话内的环境为session_id/env_id
+	Scenario     string                 `json:"scenario"`
+	Config       map[string]interface{} `json:"config"`
+	Client       string                 `json:"client,omitempty"`
+	Tenant       string                 `json:"tenant,omitempty"`
+	State        []byte                 `json:"state"`         // core.Checkpoint的结果（含随机数生成器状态）
+	EpisodeSteps int                    `json:"episode_steps"` // 截断判断已计数的回合步数
+	Steps        int64                  `json:"steps"`
+	Episodes     int64                  `json:"episodes"`
+}
+
+// takeSnapshot 在各环境的两步之间保存检查点，跳过未实现core.Checkpointer的环境并写入log
+func takeSnapshot(engine *core.SimulationEngine, registry *EnvRegistry, sessions *SessionManager, log core.Logger) *Snapshot {
+	snap := &Snapshot{Version: snapshotVersion, Created: time.Now(), Scenarios: uploadedScenarios(engine, nil)}
+	sessionIDs := make(map[string]bool)
+	registry.each(func(key string, entry *envEntry) {
+		env, err := snapshotEnv(key, entry)
+		if err != nil {
+			if !errors.Is(err, core.ErrCheckpointUnsupported) {
+				log.Warn("snapshot: skipping environment", "env_id", key, "error", err)
+			}
+			return
+		}
+		snap.Environments = append(snap.Environments, env)
+		if sessions.owned(key) {
+			sessionIDs[key[:strings.IndexByte(key, '/')]] = true
+		}
+	})
+	sort.Slice(snap.Environments, func(i, j int) bool { return snap.Environments[i].EnvID < snap.Environments[j].EnvID })
+
+	for id := range sessionIDs {
+		if sess, ok := sessions.lookup(id); ok {
+			snap.Sessions = append(snap.Sessions, SessionSnapshot{ID: sess.ID, Tenant: sess.Tenant, Client: sess.Client, TTLSeconds: sess.TTL.Seconds()})
+		}
+	}
+	sort.Slice(snap.Sessions, func(i, j int) bool { return snap.Sessions[i].ID < snap.Sessions[j].ID })
+	return snap
+}
+
+// snapshotEnv 保存一个环境的检查点
+func snapshotEnv(key string, entry *envEntry) (EnvSnapshot, error) {
+	values, ok := entry.config.(interface{ Values() map[string]interface{} })
+	if !ok {
+		return EnvSnapshot{}, fmt.Errorf("config of type %T cannot be saved", entry.config)
+	}
+	env := EnvSnapshot{
+		EnvID:    key,
+		Scenario: entry.scenario,
+		Config:   values.Values(),
+		Client:   entry.client,
+		Tenant:   entry.tenant,
+		Steps:    entry.stats.steps.Load(),
+		Episodes: entry.stats.episodes.Load(),
+	}
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	state, err := core.Checkpoint(entry.env)
+	if err != nil {
+		return EnvSnapshot{}, err
+	}
+	env.State = state
+	if entry.truncation != nil {
+		env.EpisodeSteps = entry.truncation.Steps()
+	}
+	return env, nil
+}
+
+// writeSnapshot 将快照写入path：先写临时文件再重命名，写入中途退出不会损坏上一次的快照
+func writeSnapshot(path string, snap *Snapshot) error {
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// readSnapshot 读取path中的快照，文件不存在时返回nil
+func readSnapshot(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	snap, err := decodeSnapshot(data)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot %s: %w", path, err)
+	}
+	return snap, nil
+}
+
+// decodeSnapshot 解码JSON编码的快照并检查版本
+func decodeSnapshot(data []byte) (*Snapshot, error) {
+	var snap Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("invalid snapshot: %w", err)
+	}
+	if snap.Version != snapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d", snap.Version)
+	}
+	return &snap, nil
+}
+
+// exportEnv 保存注册表中键为key的环境的快照，环境属于会话时包含该会话及其全部环境（以及它们使用的上传场景）；
+// 任一环境不支持检查点时返回错误且不做修改。detach时随后从本服务端移除并关闭这些环境及会话，
+// 路由器据此将环境迁移到其他工作节点
+func exportEnv(engine *core.SimulationEngine, registry *EnvRegistry, sessions *SessionManager, t telemetry, key string, detach bool) (*Snapshot, error) {
+	if _, ok := registry.entry(key); !ok {
+		return nil, fmt.Errorf("environment %s not found", key)
+	}
+	snap := &Snapshot{Version: snapshotVersion, Created: time.Now()}
+	keys := []string{key}
+	var sess *Session
+	if sessions.owned(key) {
+		sess, _ = sessions.lookup(key[:strings.IndexByte(key, '/')])
+	}
+	if sess != nil {
+		snap.Sessions = []SessionSnapshot{{ID: sess.ID, Tenant: sess.Tenant, Client: sess.Client, TTLSeconds: sess.TTL.Seconds()}}
+		keys = keys[:0]
+		for _, envID := range sess.EnvIDs() {
+			keys = append(keys, sess.key(envID))
+		}
+	}
+	for _, k := range keys {
+		entry, ok := registry.entry(k)
+		if !ok {
+			continue
+		}
+		env, err := snapshotEnv(k, entry)
+		if err != nil {
+			return nil, fmt.Errorf("environment %s: %w", k, err)
+		}
+		snap.Environments = append(snap.Environments, env)
+	}
+	used := make(map[string]bool, len(snap.Environments))
+	for _, env := range snap.Environments {
+		used[env.Scenario] = true
+	}
+	snap.Scenarios = uploadedScenarios(engine, used)
+
+	if detach {
+		for _, env := range snap.Environments {
+			forceClose(registry, sessions, env.EnvID, t)
+		}
+		if sess != nil {
+			sessions.Close(sess.ID)
+		}
+	}
+	return snap, nil
+}
+
+// importSnapshot 按exportEnv的结果注册场景并重新创建环境与会话，任一环境恢复失败时关闭已恢复的环境并返回错误
+func importSnapshot(engine *core.SimulationEngine, wasmLimits *wasm.Limits, registry *EnvRegistry, sessions *SessionManager, t telemetry, gymnasium bool, snap *Snapshot) (int, error) {
+	for _, s := range snap.Sessions {
+		if _, ok := sessions.lookup(s.ID); ok {
+			return 0, fmt.Errorf("session %s already exists", s.ID)
+		}
+	}
+	if err := restoreScenarios(engine, wasmLimits, snap.Scenarios, t.log()); err != nil {
+		return 0, err
+	}
+	for _, s := range snap.Sessions {
+		sessions.restore(s.ID, s.Tenant, s.Client, time.Duration(s.TTLSeconds*float64(time.Second)))
+	}
+	for i, item := range snap.Environments {
+		if err := restoreEnv(engine, registry, sessions, t, gymnasium, item); err != nil {
+			for _, done := range snap.Environments[:i] {
+				forceClose(registry, sessions, done.EnvID, t)
+			}
+			for _, s := range snap.Sessions {
+				sessions.Close(s.ID)
+			}
+			return 0, fmt.Errorf("environment %s: %w", item.EnvID, err)
+		}
+	}
+	return len(snap.Environments), nil
+}
+
+// restoreSnapshot 按快照注册场景、重新创建环境并恢复检查点，单个场景或环境恢复失败时记录日志并跳过，返回恢复的环境数
+func restoreSnapshot(engine *core.SimulationEngine, wasmLimits *wasm.Limits, registry *EnvRegistry, sessions *SessionManager, t telemetry, gymnasium bool, snap *Snapshot) int {
+	for _, s := range snap.Scenarios {
+		if err := restoreScenarios(engine, wasmLimits, []ScenarioSnapshot{s}, t.log()); err != nil {
+			t.log().Warn("snapshot: failed to restore scenario", "error", err)
+		}
+	}
+	for _, s := range snap.Sessions {
+		sessions.restore(s.ID, s.Tenant, s.Client, time.Duration(s.TTLSeconds*float64(time.Second)))
+	}
+
+	restored := 0
+	for _, item := range snap.Environments {
+		if err := restoreEnv(engine, registry, sessions, t, gymnasium, item); err != nil {
+			t.log().Warn("snapshot: failed to restore environment", "env_id", item.EnvID, "error", err)
+			continue
+		}
+		restored++
+	}
+	return restored
+}
+
+// restoreEnv 创建并注册快照中的一个环境；环境按创建配置重新包装，轨迹录制等从恢复时重新开始
+func restoreEnv(engine *core.SimulationEngine, registry *EnvRegistry, sessions *SessionManager, t telemetry, gymnasium bool, item EnvSnapshot) error {
+	var sess *Session
+	if i := strings.IndexByte(item.EnvID, '/'); i > 0 {
+		var ok bool
+		if sess, ok = sessions.lookup(item.EnvID[:i]); !ok {
+			return fmt.Errorf("session %s not in snapshot", item.EnvID[:i])
+		}
+	}
+
+	config := core.NewBaseConfig(item.Config)
+	env, err := engine.CreateEnvironment(item.Scenario, config)
+	if err != nil {
+		return err
+	}
+	if err := core.RestoreCheckpoint(env, item.State); err != nil {
+		env.Close()
+		return err
+	}
+	env, err = t.wrapEnvironment(engine.Hooks(), env, config, item.Scenario, item.EnvID, item.Config)
+	if err != nil {
+		return err
+	}
+
+	entry := newEnvEntry(env, config, gymnasium)
+	entry.client, entry.scenario, entry.tenant = item.Client, item.Scenario, item.Tenant
+	entry.stats.steps.Store(item.Steps)
+	entry.stats.episodes.Store(item.Episodes)
+	if entry.truncation != nil {
+		entry.truncation.Resume(item.EpisodeSteps)
+	}
+	if !registry.add(item.EnvID, entry) {
+		env.Close()
+		return fmt.Errorf("environment already exists")
+	}
+	if sess != nil && !sess.own(registry, item.EnvID, t) {
+		return fmt.Errorf("session %s closed", sess.ID)
+	}
+	return nil
+}
+
+// watchSnapshots 每隔interval调用一次save，保存失败时写入log，返回的函数停止定期保存并最后保存一次
+func watchSnapshots(save func() error, interval time.Duration, log core.Logger) (stop func()) {
+	if interval <= 0 {
+		interval = DefaultSnapshotInterval
+	}
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := save(); err != nil {
+					log.Warn("snapshot: failed to save", "error", err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-finished
+		if err := save(); err != nil {
+			log.Warn("snapshot: failed to save", "error", err)
+		}
+	}
+}
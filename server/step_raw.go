@@ -0,0 +1,248 @@
+package server
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// RawContentType 是/step_raw请求与响应的Content-Type
+const RawContentType = "application/octet-stream"
+
+// /step_raw的二进制格式，所有整数与浮点数均为小端序，浮点数默认为float64，
+// 查询参数dtype=float32时请求与响应中的浮点数均为float32：
+//
+//	请求: uint16 env_id长度 | env_id | 动作值...
+//	响应: uint32 智能体数n | n个uint32观察长度 | 观察值... | n个奖励 | n个结束标志(1或0)
+//
+// 动作值按环境的动作空间切分给各智能体：离散空间每个智能体一个值（取整），
+// 单维连续空间每个智能体一个值，其余空间每个智能体Size()个值。
+// 响应无法携带回合结束时的观察，因此以auto_reset创建的环境不能使用/step_raw（返回400）
+
+// rawBufferPool 复用请求体与响应体的缓冲区
+var rawBufferPool = sync.Pool{New: func() interface{} { return new([]byte) }}
+
+func (api *GymAPI) handleStepRaw(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	start := time.Now()
+	dtype, err := core.ParseDtype(core.NewBaseConfig(map[string]interface{}{core.DtypeKey: r.URL.Query().Get(core.DtypeKey)}))
+	if err != nil {
+		api.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	width := 8
+	if dtype == core.DtypeFloat32 {
+		width = 4
+	}
+
+	buf := rawBufferPool.Get().(*[]byte)
+	defer rawBufferPool.Put(buf)
+	body, err := readBody(http.MaxBytesReader(w, r.Body, MaxMessageSize), (*buf)[:0])
+	*buf = body
+	if err != nil {
+		api.writeError(w, fmt.Sprintf("Failed to read body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	envID, values, err := decodeRawStep(body, width)
+	if err != nil {
+		api.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	key, _, ok := api.envKey(w, r, envID)
+	if !ok {
+		return
+	}
+	entry, exists := api.environments.entry(key)
+	if !exists {
+		api.writeEnvNotFound(w, envID)
+		return
+	}
+	if entry.autoReset {
+		api.writeErrorDetails(w, fmt.Sprintf("Environment %s was created with %s, which /step_raw does not support: the binary response cannot carry the terminal observation; use /step instead", envID, AutoResetKey),
+			http.StatusBadRequest, map[string]string{"env_id": envID})
+		return
+	}
+	env := entry.env
+	actions, err := rawActions(env.GetSpaces().ActionSpace, values)
+	if err != nil {
+		api.writeErrorDetails(w, fmt.Sprintf("Invalid action: %v", err), http.StatusBadRequest, map[string]string{"env_id": envID})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), 30*time.Second)
+	defer cancel()
+
+	entry.mu.Lock()
+	stepStart := time.Now()
+	observations, rewards, done, err := env.Step(ctx, actions)
+	if err != nil {
+		entry.mu.Unlock()
+		api.writeEnvFailed(w, envID, "step", err)
+		return
+	}
+	api.latency.Env.Observe(entry.scenario, time.Since(stepStart))
+	entry.stats.step()
+
+	// 二进制格式只返回done，但仍需计数回合步数，使混用/step时的截断判断保持正确
+	if entry.truncation != nil {
+		entry.truncation.Step(done, env.GetInfo())
+	}
+	entry.mu.Unlock()
+
+	// 请求体已解码完毕，复用同一缓冲区编码响应
+	*buf = encodeRawStep((*buf)[:0], observations, rewards, done, width)
+	core.ReleaseObservations(observations)
+
+	w.Header().Set("Content-Type", RawContentType)
+	w.Write(*buf)
+	api.latency.Request.Observe(entry.scenario, time.Since(start))
+}
+
+// readBody 将请求体读入dst并返回
+func readBody(body io.Reader, dst []byte) ([]byte, error) {
+	for {
+		if len(dst) == cap(dst) {
+			dst = append(dst, 0)[:len(dst)]
+		}
+		n, err := body.Read(dst[len(dst):cap(dst)])
+		dst = dst[:len(dst)+n]
+		if err == io.EOF {
+			return dst, nil
+		}
+		if err != nil {
+			return dst, err
+		}
+	}
+}
+
+// decodeRawStep 解析/step_raw请求体，返回环境ID与动作值
+func decodeRawStep(body []byte, width int) (string, []float64, error) {
+	if len(body) < 2 {
+		return "", nil, errors.New("body too short for the env_id length")
+	}
+	n := int(binary.LittleEndian.Uint16(body))
+	if len(body) < 2+n {
+		return "", nil, fmt.Errorf("body too short for an env_id of %d bytes", n)
+	}
+	envID, payload := string(body[2:2+n]), body[2+n:]
+	if len(payload)%width != 0 {
+		return "", nil, fmt.Errorf("action payload of %d bytes is not a multiple of %d", len(payload), width)
+	}
+	values := make([]float64, len(payload)/width)
+	for i := range values {
+		if width == 4 {
+			values[i] = float64(math.Float32frombits(binary.LittleEndian.Uint32(payload[i*4:])))
+		} else {
+			values[i] = math.Float64frombits(binary.LittleEndian.Uint64(payload[i*8:]))
+		}
+	}
+	return envID, values, nil
+}
+
+// rawActions 按动作空间将动作值切分为各智能体的动作，Text动作（包括Dict中的Text分量）不能以数值表示。
+// 动作值在取整之前按动作空间检查，离散动作的非整数值同样被拒绝
+func rawActions(space core.ActionSpace, values []float64) ([]core.Action, error) {
+	if space.Type == core.SpaceTypeText {
+		return nil, fmt.Errorf("text actions cannot be sent as numeric values")
+	}
+	size := core.ActionValueCount(space)
+	if size <= 0 || len(values) == 0 || len(values)%size != 0 {
+		return nil, fmt.Errorf("got %d action values, expected a positive multiple of %d", len(values), size)
+	}
+	actions := make([]core.Action, len(values)/size)
+	for i := range actions {
+		agent := values[i*size : (i+1)*size]
+		if err := checkRawValues(space, agent); err != nil {
+			return nil, fmt.Errorf("action %d does not match the action space: %w", i, err)
+		}
+		actions[i] = core.NewActionFromValues(space, agent)
+	}
+	return actions, nil
+}
+
+// checkRawValues 在取整之前检查一个智能体的平铺动作值，Dict空间按SubspaceNames的顺序切分后逐个检查，
+// Hybrid空间的离散动作下标须为整数
+func checkRawValues(space core.ActionSpace, values []float64) error {
+	if space.Type == core.SpaceTypeDict {
+		for _, name := range space.SubspaceNames() {
+			sub := space.Subspaces[name]
+			n := core.ActionValueCount(sub)
+			if err := checkRawValues(sub, values[:n]); err != nil {
+				return fmt.Errorf("dict action component %q: %w", name, err)
+			}
+			values = values[n:]
+		}
+		return nil
+	}
+	if space.Type == core.SpaceTypeHybrid {
+		if values[0] != math.Trunc(values[0]) {
+			return fmt.Errorf("hybrid action choice must be an integer, got %v", values[0])
+		}
+		return space.Check(core.NewActionFromValues(space, values))
+	}
+	var raw interface{} = values
+	if len(values) == 1 {
+		raw = values[0]
+	}
+	return space.Check(core.NewGenericAction(raw))
+}
+
+// encodeRawStep 将一步的结果按/step_raw的响应格式追加到dst
+func encodeRawStep(dst []byte, observations []core.Observation, rewards []float64, done []bool, width int) []byte {
+	dst = binary.LittleEndian.AppendUint32(dst, uint32(len(observations)))
+	for _, obs := range observations {
+		dst = binary.LittleEndian.AppendUint32(dst, uint32(observationLen(obs)))
+	}
+	for _, obs := range observations {
+		if data32 := float32Data(obs); data32 != nil && width == 4 {
+			for _, v := range data32 {
+				dst = binary.LittleEndian.AppendUint32(dst, math.Float32bits(v))
+			}
+			continue
+		}
+		for _, v := range obs.GetData() {
+			dst = appendRawFloat(dst, v, width)
+		}
+	}
+	for i := range observations {
+		var reward float64
+		if i < len(rewards) {
+			reward = rewards[i]
+		}
+		dst = appendRawFloat(dst, reward, width)
+	}
+	for i := range observations {
+		var d float64
+		if i < len(done) && done[i] {
+			d = 1
+		}
+		dst = appendRawFloat(dst, d, width)
+	}
+	return dst
+}
+
+func observationLen(obs core.Observation) int {
+	if data32 := float32Data(obs); data32 != nil {
+		return len(data32)
+	}
+	return len(obs.GetData())
+}
+
+func appendRawFloat(dst []byte, v float64, width int) []byte {
+	if width == 4 {
+		return binary.LittleEndian.AppendUint32(dst, math.Float32bits(float32(v)))
+	}
+	return binary.LittleEndian.AppendUint64(dst, math.Float64bits(v))
+}
@@ -0,0 +1,51 @@
+package server
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// rawStepBody 编码float64格式的/step_raw请求体
+func rawStepBody(envID string, actions ...float64) []byte {
+	body := binary.LittleEndian.AppendUint16(nil, uint16(len(envID)))
+	body = append(body, envID...)
+	for _, action := range actions {
+		body = binary.LittleEndian.AppendUint64(body, math.Float64bits(action))
+	}
+	return body
+}
+
+func TestStepRawAutoReset(t *testing.T) {
+	handler := NewGymAPI().Handler()
+	post := func(path string, body []byte) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body)))
+		return rec
+	}
+	for _, create := range []string{
+		`{"env_id":"plain","scenario":"cartpole"}`,
+		`{"env_id":"auto","scenario":"cartpole","config":{"auto_reset":true}}`,
+	} {
+		if rec := post("/create", []byte(create)); !strings.Contains(rec.Body.String(), `"success":true`) {
+			t.Fatalf("create %s: %s", create, rec.Body)
+		}
+	}
+	for _, envID := range []string{"plain", "auto"} {
+		if rec := post("/reset", []byte(`{"env_id":"`+envID+`"}`)); rec.Code != http.StatusOK {
+			t.Fatalf("reset %s: %d %s", envID, rec.Code, rec.Body)
+		}
+	}
+
+	if rec := post("/step_raw", rawStepBody("plain", 1)); rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != RawContentType {
+		t.Fatalf("step_raw without auto_reset: %d %s", rec.Code, rec.Body)
+	}
+	rec := post("/step_raw", rawStepBody("auto", 1))
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), AutoResetKey) {
+		t.Fatalf("step_raw with auto_reset: %d %s, want 400 naming %s", rec.Code, rec.Body, AutoResetKey)
+	}
+}
@@ -0,0 +1,176 @@
+package server
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// APIKeyMetadataKey gRPC请求元数据中携带租户API密钥的键
+const APIKeyMetadataKey = "api-key"
+
+// APIKeyHeader HTTP请求中携带租户API密钥的头
+const APIKeyHeader = "X-API-Key"
+
+// errAPIKey API密钥缺失或不属于任何租户
+var errAPIKey = errors.New("invalid API key")
+
+// Tenant 一个租户（团队）：持有任一APIKeys的请求属于该租户，只能看到本租户的环境与会话。
+// 配置了租户的服务端拒绝不带有效API密钥的请求（管理接口除外，仍由管理令牌控制）
+type Tenant struct {
+	Name    string   `json:"name" yaml:"name"`
+	APIKeys []string `json:"api_keys" yaml:"api_keys"`
+	// Scenarios 允许创建的场景与预设，为空时不限制
+	Scenarios []string `json:"scenarios,omitempty" yaml:"scenarios,omitempty"`
+	// Limits 租户的资源上限，MaxEnvs按租户的全部环境计数，其余字段与服务端的Limits含义相同，两者同时生效
+	Limits Limits `json:"limits,omitempty" yaml:"limits,omitempty"`
+}
+
+// namespace 返回租户在注册表中的命名空间，未配置租户时（t为nil）为空
+func (t *Tenant) namespace() string {
+	if t == nil {
+		return ""
+	}
+	return t.Name
+}
+
+// allows 判断租户是否可以创建场景scenario
+func (t *Tenant) allows(scenario string) bool {
+	return t == nil || listed(t.Scenarios, scenario)
+}
+
+// checkScenario 检查租户是否可以创建场景scenario
+func (t *Tenant) checkScenario(scenario string) error {
+	if !t.allows(scenario) {
+		return fmt.Errorf("scenario %s is not allowed for tenant %s", scenario, t.Name)
+	}
+	return nil
+}
+
+// listed 判断name是否在names中，names为空时不限制
+func listed(names []string, name string) bool {
+	if len(names) == 0 {
+		return true
+	}
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// checkCreate 检查场景是否在服务端启用的场景enabled之内（为空时不限制）且租户t可以创建
+func checkCreate(enabled []string, t *Tenant, scenario string) error {
+	if !listed(enabled, scenario) {
+		return fmt.Errorf("scenario %s is not enabled on this server", scenario)
+	}
+	return t.checkScenario(scenario)
+}
+
+// canCreate 判断场景是否在服务端启用的场景enabled之内且租户t可以创建
+func canCreate(enabled []string, t *Tenant, scenario string) bool {
+	return listed(enabled, scenario) && t.allows(scenario)
+}
+
+// creatable 按名称排序返回names中在服务端启用且租户t可以创建的场景，names会被排序
+func creatable(enabled []string, t *Tenant, names []string) []string {
+	sort.Strings(names)
+	if len(enabled) == 0 && (t == nil || len(t.Scenarios) == 0) {
+		return names
+	}
+	allowed := make([]string, 0, len(names))
+	for _, name := range names {
+		if canCreate(enabled, t, name) {
+			allowed = append(allowed, name)
+		}
+	}
+	return allowed
+}
+
+// checkCounts 检查租户的数量上限，pending的含义与Limits.checkCounts相同
+func (t *Tenant) checkCounts(registry *EnvRegistry, sess *Session, client string, pending int) error {
+	if t == nil {
+		return nil
+	}
+	limits := t.Limits
+	if limits.MaxEnvs > 0 && registry.tenantLen(t.Name)+pending > limits.MaxEnvs {
+		return fmt.Errorf("tenant %s has reached the limit of %d environments", t.Name, limits.MaxEnvs)
+	}
+	limits.MaxEnvs = 0
+	if err := limits.checkCounts(registry, sess, client, pending); err != nil {
+		return fmt.Errorf("tenant %s: %w", t.Name, err)
+	}
+	return nil
+}
+
+// checkObservation 检查租户的观察大小上限
+func (t *Tenant) checkObservation(env core.Environment) error {
+	if t == nil {
+		return nil
+	}
+	if err := t.Limits.checkObservation(env); err != nil {
+		return fmt.Errorf("tenant %s: %w", t.Name, err)
+	}
+	return nil
+}
+
+// tenantSet 按API密钥索引的租户表，以密钥的SHA-256为键，查找耗时与密钥内容无关
+type tenantSet struct {
+	byKey map[[sha256.Size]byte]*Tenant
+}
+
+// newTenantSet 校验并索引租户：名称非空、不重复且不含':'与'/'，每个租户至少一个API密钥，密钥不重复。
+// tenants为空时返回nil，即不区分租户
+func newTenantSet(tenants []Tenant) (*tenantSet, error) {
+	if len(tenants) == 0 {
+		return nil, nil
+	}
+	set := &tenantSet{byKey: make(map[[sha256.Size]byte]*Tenant)}
+	names := make(map[string]bool, len(tenants))
+	for i := range tenants {
+		tenant := tenants[i]
+		if tenant.Name == "" || strings.ContainsAny(tenant.Name, ":/") {
+			return nil, fmt.Errorf("invalid tenant name %q", tenant.Name)
+		}
+		if names[tenant.Name] {
+			return nil, fmt.Errorf("duplicate tenant %s", tenant.Name)
+		}
+		names[tenant.Name] = true
+		if len(tenant.APIKeys) == 0 {
+			return nil, fmt.Errorf("tenant %s has no API keys", tenant.Name)
+		}
+		for _, key := range tenant.APIKeys {
+			sum := sha256.Sum256([]byte(key))
+			if key == "" || set.byKey[sum] != nil {
+				return nil, fmt.Errorf("tenant %s has an empty or duplicate API key", tenant.Name)
+			}
+			set.byKey[sum] = &tenant
+		}
+	}
+	return set, nil
+}
+
+// resolve 返回API密钥所属的租户；未配置租户时返回nil
+func (s *tenantSet) resolve(apiKey string) (*Tenant, error) {
+	if s == nil {
+		return nil, nil
+	}
+	tenant, ok := s.byKey[sha256.Sum256([]byte(apiKey))]
+	if !ok || apiKey == "" {
+		return nil, errAPIKey
+	}
+	return tenant, nil
+}
+
+// tenantKey 返回租户内不属于会话的env_id在注册表中的键
+func tenantKey(namespace, envID string) string {
+	if namespace == "" {
+		return envID
+	}
+	return namespace + ":" + envID
+}
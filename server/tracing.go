@@ -0,0 +1,135 @@
+package server
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/jelech/rl_env_engine/core"
+	"github.com/jelech/rl_env_engine/core/tracing"
+)
+
+// metadataCarrier 让OpenTelemetry的传播器读取gRPC请求元数据中的traceparent等字段
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// startRPCSpan 以请求元数据中上游的追踪上下文为父，为gRPC方法创建服务端span
+func startRPCSpan(ctx context.Context, method string) (context.Context, trace.Span) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md))
+	}
+	return tracing.Tracer().Start(ctx, method,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(attribute.String("rpc.system", "grpc"), attribute.String("rpc.method", method)))
+}
+
+// endRPCSpan 记录gRPC调用的状态码并结束span
+func endRPCSpan(span trace.Span, err error) {
+	if err != nil {
+		st := status.Convert(err)
+		span.SetAttributes(attribute.Int("rpc.grpc.status_code", int(st.Code())))
+		span.SetStatus(codes.Error, st.Message())
+	}
+	span.End()
+}
+
+// traceUnary 开启追踪时为每个gRPC调用创建span，请求带有env_id时记录为属性
+func traceUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if !tracing.Enabled() {
+		return handler(ctx, req)
+	}
+	ctx, span := startRPCSpan(ctx, info.FullMethod)
+	if r, ok := req.(interface{ GetEnvId() string }); ok && r.GetEnvId() != "" {
+		span.SetAttributes(core.TraceAttrEnvID.String(r.GetEnvId()))
+	}
+	resp, err := handler(ctx, req)
+	endRPCSpan(span, err)
+	return resp, err
+}
+
+// tracedStream 以带有span的上下文代替流的上下文
+type tracedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s tracedStream) Context() context.Context {
+	return s.ctx
+}
+
+// traceStream 开启追踪时为每个流创建span，流中每一步的env.Step span是它的子span
+func traceStream(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if !tracing.Enabled() {
+		return handler(srv, stream)
+	}
+	ctx, span := startRPCSpan(stream.Context(), info.FullMethod)
+	err := handler(srv, tracedStream{ServerStream: stream, ctx: ctx})
+	endRPCSpan(span, err)
+	return err
+}
+
+// statusRecorder 记录HTTP处理器写出的状态码
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusRecorder) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// traceMiddleware 开启追踪时以请求头中上游的追踪上下文为父，为每个HTTP请求创建服务端span
+func traceMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !tracing.Enabled() {
+			next.ServeHTTP(w, r)
+			return
+		}
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := tracing.Tracer().Start(ctx, r.Method+" "+r.URL.Path,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(attribute.String("http.request.method", r.Method), attribute.String("url.path", r.URL.Path)))
+		defer span.End()
+
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r.WithContext(ctx))
+		span.SetAttributes(attribute.Int("http.response.status_code", recorder.status))
+		if recorder.status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(recorder.status))
+		}
+	})
+}
+
+// traceEnvID 将env_id记录到请求的span上，未开启追踪时不做任何事
+func traceEnvID(ctx context.Context, envID string) {
+	if tracing.Enabled() && envID != "" {
+		trace.SpanFromContext(ctx).SetAttributes(core.TraceAttrEnvID.String(envID))
+	}
+}
@@ -0,0 +1,22 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// checkActions 在交给环境之前按动作空间检查各智能体的动作（维数、取值范围与数据类型），
+// 使不合法的动作得到说明原因的错误，而不是在场景中以类型断言失败等形式出错；
+// raw_data的字节动作由场景自行解析，不做检查
+func checkActions(space core.ActionSpace, actions []core.Action) error {
+	for i, action := range actions {
+		if _, custom := action.GetData().([]byte); custom {
+			continue
+		}
+		if err := space.Check(action); err != nil {
+			return fmt.Errorf("action %d does not match the action space: %w", i, err)
+		}
+	}
+	return nil
+}
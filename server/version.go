@@ -0,0 +1,53 @@
+package server
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// APIVersion 服务端实现的协议版本，GetInfo与HTTP /info返回。
+// 版本1：步进响应只有done（Gym四元组语义）；版本2：步进响应还返回terminated与truncated（Gymnasium五元组语义）
+const APIVersion = 2
+
+// MinAPIVersion 服务端仍支持的最低协议版本
+const MinAPIVersion = 1
+
+// APIVersionHeader HTTP创建环境请求中客户端期望的协议版本，gRPC使用CreateEnvironmentRequest.api_version
+const APIVersionHeader = "X-API-Version"
+
+// negotiateAPIVersion 按客户端期望的协议版本调整创建配置：版本1关闭gymnasium_api，版本2开启，
+// 0表示未指定，配置保持不变。版本不受支持，或配置显式设置的gymnasium_api与该版本的步进语义冲突时返回错误，
+// 避免旧客户端把新语义的响应当作旧语义解析
+func negotiateAPIVersion(version int, config map[string]interface{}) (map[string]interface{}, error) {
+	if version == 0 {
+		return config, nil
+	}
+	if version < MinAPIVersion || version > APIVersion {
+		return nil, fmt.Errorf("unsupported API version %d, server supports %d to %d", version, MinAPIVersion, APIVersion)
+	}
+	gymnasium := version >= 2
+	if enabled, ok, err := core.NewBaseConfig(config).GetBool(GymnasiumAPIKey); err != nil {
+		return nil, err
+	} else if ok && enabled != gymnasium {
+		return nil, fmt.Errorf("%s=%t conflicts with API version %d", GymnasiumAPIKey, enabled, version)
+	}
+	if config == nil {
+		config = make(map[string]interface{}, 1)
+	}
+	config[GymnasiumAPIKey] = gymnasium
+	return config, nil
+}
+
+// headerAPIVersion 解析APIVersionHeader，未设置时为0
+func headerAPIVersion(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	version, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s header %q", APIVersionHeader, value)
+	}
+	return version, nil
+}
@@ -0,0 +1,101 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/jelech/rl_env_engine/core"
+	"github.com/jelech/rl_env_engine/core/wasm"
+	"github.com/jelech/rl_env_engine/scenarios/wasmenv"
+)
+
+// MaxWasmModuleSize 上传的WASM模块的大小上限
+const MaxWasmModuleSize = 16 << 20
+
+// errWasmDisabled 服务端未开启WASM场景上传
+var errWasmDisabled = errors.New("uploading WASM scenarios is disabled on this server")
+
+// errScenarioExists 上传的场景与已有的场景或预设重名
+var errScenarioExists = errors.New("scenario name is already in use")
+
+// RegisterScenarioRequest 上传WASM模块注册场景的请求，模块需实现wasmenv.ABIVersion的环境ABI
+type RegisterScenarioRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Wasm        []byte `json:"wasm"` // JSON中为base64编码
+}
+
+// RegisterScenarioResponse 注册场景的响应
+type RegisterScenarioResponse struct {
+	Scenario string `json:"scenario"`
+}
+
+// ScenarioSnapshot 快照中上传的WASM场景，恢复其环境之前重新注册
+type ScenarioSnapshot struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Wasm        []byte `json:"wasm"`
+}
+
+// registerWasmScenario 编译、校验并注册上传的模块。场景名在服务端全局可见，因此不能与任何已有的场景或预设重名
+// （包括其他客户端此前上传的场景），否则返回errScenarioExists；重新上传完全相同的模块视为成功，
+// 使路由广播与快照恢复可以重试
+func registerWasmScenario(engine *core.SimulationEngine, limits *wasm.Limits, req RegisterScenarioRequest) error {
+	if limits == nil {
+		return errWasmDisabled
+	}
+	if len(req.Wasm) > MaxWasmModuleSize {
+		return fmt.Errorf("WASM module of %d bytes exceeds the limit of %d bytes", len(req.Wasm), MaxWasmModuleSize)
+	}
+	scenario, err := wasmenv.NewWasmScenario(req.Name, req.Description, req.Wasm, *limits)
+	if err != nil {
+		return fmt.Errorf("invalid WASM scenario: %w", err)
+	}
+	err = engine.ReplaceScenario(scenario, func(old core.Scenario) bool {
+		uploaded, ok := old.(*wasmenv.WasmScenario)
+		return ok && bytes.Equal(uploaded.Source(), req.Wasm)
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %v", errScenarioExists, err)
+	}
+	return nil
+}
+
+// uploadedScenarios 返回引擎中上传的WASM场景；names非nil时只返回其中的场景
+func uploadedScenarios(engine *core.SimulationEngine, names map[string]bool) []ScenarioSnapshot {
+	var list []ScenarioSnapshot
+	for _, name := range engine.ListScenarios() {
+		if names != nil && !names[name] {
+			continue
+		}
+		scenario, err := engine.GetScenario(name)
+		if err != nil {
+			continue
+		}
+		if s, ok := scenario.(*wasmenv.WasmScenario); ok {
+			list = append(list, ScenarioSnapshot{Name: name, Description: s.GetDescription(), Wasm: s.Source()})
+		}
+	}
+	return list
+}
+
+// restoreScenarios 重新注册快照中的WASM场景，服务端未开启上传时跳过并写入log（这些场景的环境将无法恢复）
+func restoreScenarios(engine *core.SimulationEngine, limits *wasm.Limits, scenarios []ScenarioSnapshot, log core.Logger) error {
+	for _, s := range scenarios {
+		if existing, err := engine.GetScenario(s.Name); err == nil {
+			if uploaded, ok := existing.(*wasmenv.WasmScenario); ok && bytes.Equal(uploaded.Source(), s.Wasm) {
+				continue
+			}
+		}
+		if limits == nil {
+			log.Warn("snapshot: skipping WASM scenario", "scenario", s.Name, "error", errWasmDisabled)
+			continue
+		}
+		req := RegisterScenarioRequest{Name: s.Name, Description: s.Description, Wasm: s.Wasm}
+		if err := registerWasmScenario(engine, limits, req); err != nil {
+			return fmt.Errorf("scenario %s: %w", s.Name, err)
+		}
+	}
+	return nil
+}
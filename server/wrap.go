@@ -0,0 +1,127 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/jelech/rl_env_engine/core"
+	"github.com/jelech/rl_env_engine/core/curriculum"
+	"github.com/jelech/rl_env_engine/core/metrics"
+	"github.com/jelech/rl_env_engine/core/record"
+	"github.com/jelech/rl_env_engine/core/runstore"
+	"github.com/jelech/rl_env_engine/core/tensorboard"
+	"github.com/jelech/rl_env_engine/core/tracing"
+	"github.com/jelech/rl_env_engine/core/video"
+	"github.com/jelech/rl_env_engine/core/wrappers"
+)
+
+// telemetry 服务端发布指标与持久化运行记录的目标及运行日志，由GrpcServer与GymAPI共用，metrics、stats与runs均为nil时不包装
+type telemetry struct {
+	metrics core.MetricsSink
+	stats   *metrics.EpisodeStats // 汇总回合统计，供/stats查询
+	runs    *runstore.Store
+	logger  core.Logger // 为nil时使用core.DefaultLogger()
+	// outputDir 客户端给出的输出路径（record_path、video_dir、tensorboard_dir与/record）所在的服务端目录，为空时拒绝这些路径
+	outputDir string
+}
+
+// episodeSink 返回回合指标的发布目标，未设置指标输出与回合统计时为nil
+func (t telemetry) episodeSink() core.MetricsSink {
+	switch {
+	case t.stats == nil:
+		return t.metrics
+	case t.metrics == nil:
+		return t.stats
+	default:
+		return core.MultiMetricsSink{t.metrics, t.stats}
+	}
+}
+
+// log 返回服务端的运行日志
+func (t telemetry) log() core.Logger {
+	if t.logger != nil {
+		return t.logger
+	}
+	return core.DefaultLogger()
+}
+
+// wrapEnvironment 按创建配置与服务端设置包装环境：video_dir开启录像，tensorboard_dir开启回合统计（两者的目录均位于服务端输出目录之下），
+// 设置了指标输出或回合统计时发布回合指标，注册了钩子时触发hooks中的生命周期钩子，设置了运行存储时记录回合，curriculum按课程调整参数，randomize注入噪声与随机化参数，rescale_action缩放动作，reward_scale/reward_clip/reward_sign变换奖励，
+// record_path开启轨迹录制（路径位于服务端输出目录之下），开启追踪时为每次Reset与Step创建span，并以core.Guard隔离环境的panic。包装失败时关闭环境并返回错误
+func (t telemetry) wrapEnvironment(hooks *core.Hooks, env core.Environment, config core.Config, scenario, envID string, rawConfig map[string]interface{}) (core.Environment, error) {
+	config, err := t.outputConfig(config)
+	if err != nil {
+		env.Close()
+		return nil, err
+	}
+	// 录像需要直接访问环境的RenderFrame，因此放在最内层；回合统计、指标与钩子记录原始奖励，
+	// 课程学习按原始奖励判断回合是否成功，奖励变换放在它们之外；追踪放在其余包装层之外，span覆盖它们的耗时；
+	// panic隔离放在录制之内，覆盖其余包装层；轨迹录制放在最外层，记录客户端收到的奖励，并便于/record替换或停止录制
+	layers := []func(core.Environment, core.Config) (core.Environment, error){
+		video.FromConfig,
+		tensorboard.FromConfig,
+		func(env core.Environment, config core.Config) (core.Environment, error) {
+			sink := t.episodeSink()
+			if sink == nil {
+				return env, nil
+			}
+			return metrics.NewEpisodeReporter(env, sink, metricTags(scenario, envID)), nil
+		},
+		func(env core.Environment, config core.Config) (core.Environment, error) {
+			return hooks.Wrap(env, scenario, envID), nil
+		},
+		func(env core.Environment, config core.Config) (core.Environment, error) {
+			if t.runs == nil {
+				return env, nil
+			}
+			runID, err := t.runs.CreateRun(envID, scenario, rawConfig)
+			if err != nil {
+				// 持久化失败不影响环境创建
+				t.log().Warn("run store: failed to create run", "env_id", envID, "error", err)
+				return env, nil
+			}
+			return runstore.NewTracker(env, t.runs, runID), nil
+		},
+		curriculum.FromConfig,
+		wrappers.FromConfig,
+		func(env core.Environment, config core.Config) (core.Environment, error) {
+			if !tracing.Enabled() {
+				return env, nil
+			}
+			return tracing.Wrap(env, scenario, envID), nil
+		},
+		func(env core.Environment, config core.Config) (core.Environment, error) {
+			return core.NewGuard(env, func(err error, stack []byte) {
+				t.log().Error("Environment panicked", "env_id", envID, "scenario", scenario, "error", err, "stack", string(stack))
+			}), nil
+		},
+		record.FromConfig,
+	}
+	for _, wrap := range layers {
+		wrapped, err := wrap(env, config)
+		if err != nil {
+			env.Close()
+			return nil, err
+		}
+		env = wrapped
+	}
+	if t.metrics != nil {
+		t.metrics.Event(metrics.EventEnvCreated, fmt.Sprintf("environment %s created from scenario %s", envID, scenario), metricTags(scenario, envID))
+	}
+	return env, nil
+}
+
+// reportClosed 发布环境关闭事件，并移除该环境的回合统计
+func (t telemetry) reportClosed(envID string) {
+	if t.stats != nil {
+		t.stats.Forget(envID)
+	}
+	if t.metrics == nil {
+		return
+	}
+	t.metrics.Event(metrics.EventEnvClosed, fmt.Sprintf("environment %s closed", envID), map[string]string{"env_id": envID})
+}
+
+// metricTags 返回环境指标的标签
+func metricTags(scenario, envID string) map[string]string {
+	return map[string]string{"scenario": scenario, "env_id": envID}
+}
@@ -0,0 +1,120 @@
+package rl_env_engine
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/jelech/rl_env_engine/server"
+)
+
+// ServerHandle controls a server started by StartHTTPServerAsync or StartGrpcServerAsync:
+//
+//	h, err := simulations.StartHTTPServerAsync(simulations.NewHTTPServerConfig(0))
+//	if err != nil { ... }
+//	defer h.Stop()
+//	url := "http://" + h.Addr()
+type ServerHandle struct {
+	addr     string
+	shutdown func(ctx context.Context) error
+	errCh    chan error
+	done     chan struct{}
+}
+
+// newServerHandle serves srv in a separate goroutine
+func newServerHandle(srv *startedServer) *ServerHandle {
+	h := &ServerHandle{
+		addr:     srv.addr,
+		shutdown: srv.shutdown,
+		errCh:    make(chan error, 1),
+		done:     make(chan struct{}),
+	}
+	go func() {
+		defer close(h.done)
+		defer close(h.errCh)
+		err := srv.serve()
+		srv.close()
+		if err != nil {
+			h.errCh <- err
+		}
+	}()
+	return h
+}
+
+// Addr returns the address the server is bound to, host:port with the actual port (useful
+// when the config's Port is 0), or the unix:// address of a Unix socket
+func (h *ServerHandle) Addr() string {
+	return h.addr
+}
+
+// Err returns a channel receiving the error the server stopped with, if any; it is closed
+// once the server stopped and its environments are closed
+func (h *ServerHandle) Err() <-chan error {
+	return h.errCh
+}
+
+// Done returns a channel closed once the server stopped and its environments are closed
+func (h *ServerHandle) Done() <-chan struct{} {
+	return h.done
+}
+
+// Shutdown stops the server gracefully: it stops accepting connections, waits for pending
+// requests until ctx ends, then saves a final snapshot when snapshots are enabled and closes
+// every active environment. It returns once the server stopped, or ctx's error when ctx
+// ended first. Calling it again is safe
+func (h *ServerHandle) Shutdown(ctx context.Context) error {
+	err := h.shutdown(ctx)
+	select {
+	case <-h.done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Stop is Shutdown with DefaultShutdownTimeout
+func (h *ServerHandle) Stop() error {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultShutdownTimeout)
+	defer cancel()
+	return h.Shutdown(ctx)
+}
+
+// startedServer is a server that is set up and bound to its address but not serving yet
+type startedServer struct {
+	addr     string
+	serve    func() error
+	shutdown func(ctx context.Context) error
+	closers  []func()
+}
+
+// onClose registers f to run by close, after the functions registered later
+func (s *startedServer) onClose(f func()) {
+	s.closers = append(s.closers, f)
+}
+
+// close releases what the server set up once it stopped serving, in reverse order of
+// registration: e.g. a final snapshot is saved before the environments are closed
+func (s *startedServer) close() {
+	for i := len(s.closers) - 1; i >= 0; i-- {
+		s.closers[i]()
+	}
+	s.closers = nil
+}
+
+// listenAddress binds a unix:// host, or port on every interface otherwise, and returns the
+// listener with the address to reach it: host with the bound port, or the unix:// address
+func listenAddress(host string, port int) (net.Listener, string, error) {
+	if server.IsUnixAddress(host) {
+		lis, err := server.Listen(host)
+		return lis, host, err
+	}
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to listen: %v", err)
+	}
+	if tcp, ok := lis.Addr().(*net.TCPAddr); ok {
+		port = tcp.Port
+	}
+	return lis, net.JoinHostPort(host, strconv.Itoa(port)), nil
+}
@@ -0,0 +1,164 @@
+package rl_env_engine
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jelech/rl_env_engine/core"
+	pb "github.com/jelech/rl_env_engine/proto"
+	"github.com/jelech/rl_env_engine/scenarios/cartpole"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// closeCountingScenario is cartpole under another name, counting how many of its
+// environments were closed
+type closeCountingScenario struct {
+	core.Scenario
+	name   string
+	closed atomic.Int32
+}
+
+func (s *closeCountingScenario) GetName() string { return s.name }
+
+func (s *closeCountingScenario) CreateEnvironment(config core.Config) (core.Environment, error) {
+	env, err := s.Scenario.CreateEnvironment(config)
+	if err != nil {
+		return nil, err
+	}
+	return &closeCountingEnvironment{Environment: env, closed: &s.closed}, nil
+}
+
+type closeCountingEnvironment struct {
+	core.Environment
+	closed *atomic.Int32
+}
+
+func (e *closeCountingEnvironment) Close() error {
+	e.closed.Add(1)
+	return e.Environment.Close()
+}
+
+// registerCloseCounting registers a closeCountingScenario named name
+func registerCloseCounting(t *testing.T, name string) *closeCountingScenario {
+	t.Helper()
+	scenario := &closeCountingScenario{Scenario: cartpole.NewCartPoleScenario(), name: name}
+	if err := RegisterScenario(scenario); err != nil {
+		t.Fatal(err)
+	}
+	return scenario
+}
+
+// checkStopped shuts h down and checks it reports a clean stop: Shutdown returns nil, Done
+// and Err are closed without an error, and calling Shutdown again is safe
+func checkStopped(t *testing.T, h *ServerHandle) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := h.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	select {
+	case <-h.Done():
+	default:
+		t.Fatal("Done is not closed after Shutdown returned")
+	}
+	if err, ok := <-h.Err(); ok {
+		t.Fatalf("Err received %v, want a closed channel", err)
+	}
+	if err := h.Shutdown(ctx); err != nil {
+		t.Fatalf("second Shutdown: %v", err)
+	}
+}
+
+// boundPort returns the port of a host:port address, failing when it is still 0
+func boundPort(t *testing.T, addr string) string {
+	t.Helper()
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		t.Fatalf("Addr %q: %v", addr, err)
+	}
+	if port == "0" {
+		t.Fatalf("Addr %q reports port 0 instead of the bound port", addr)
+	}
+	return port
+}
+
+func TestHTTPServerHandle(t *testing.T) {
+	scenario := registerCloseCounting(t, "handle_test_http")
+	h, err := StartHTTPServerAsync(NewHTTPServerConfig(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	url := "http://" + h.Addr()
+	boundPort(t, h.Addr())
+
+	for _, envID := range []string{"e1", "e2"} {
+		resp, err := http.Post(url+"/create", "application/json",
+			strings.NewReader(`{"env_id":"`+envID+`","scenario":"handle_test_http"}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("create %s: %d", envID, resp.StatusCode)
+		}
+	}
+
+	checkStopped(t, h)
+	if n := scenario.closed.Load(); n != 2 {
+		t.Errorf("%d environments closed on shutdown, want 2", n)
+	}
+	if resp, err := http.Get(url + "/info"); err == nil {
+		resp.Body.Close()
+		t.Error("the server still answers after Shutdown")
+	}
+}
+
+func TestGrpcServerHandle(t *testing.T) {
+	scenario := registerCloseCounting(t, "handle_test_grpc")
+	h, err := StartGrpcServerAsync(NewGrpcServerConfig(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	boundPort(t, h.Addr())
+
+	conn, err := grpc.NewClient(h.Addr(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := pb.NewSimulationServiceClient(conn)
+	resp, err := client.CreateEnvironment(context.Background(), &pb.CreateEnvironmentRequest{EnvId: "e1", Scenario: "handle_test_grpc"})
+	if err != nil || !resp.Success {
+		t.Fatalf("create: %v %s", err, resp.GetMessage())
+	}
+
+	checkStopped(t, h)
+	if n := scenario.closed.Load(); n != 1 {
+		t.Errorf("%d environments closed on shutdown, want 1", n)
+	}
+}
+
+func TestServerHandleListenError(t *testing.T) {
+	lis, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+	port := lis.Addr().(*net.TCPAddr).Port
+
+	if h, err := StartHTTPServerAsync(NewHTTPServerConfig(port)); err == nil {
+		h.Stop()
+		t.Error("StartHTTPServerAsync on a busy port returned no error")
+	}
+	if h, err := StartGrpcServerAsync(NewGrpcServerConfig(port)); err == nil {
+		h.Stop()
+		t.Error("StartGrpcServerAsync on a busy port returned no error")
+	}
+}
@@ -0,0 +1,148 @@
+package rl_env_engine
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/jelech/rl_env_engine/core"
+	"github.com/jelech/rl_env_engine/core/runstore"
+	"github.com/jelech/rl_env_engine/server"
+)
+
+// ShmServerConfig represents shared-memory transport configuration
+type ShmServerConfig struct {
+	// SocketPath is the Unix socket carrying the control messages
+	SocketPath string
+	// Dir, when set, is where the memory-mapped files are created instead of /dev/shm
+	Dir string
+	// PresetDir, when set, is a directory of YAML/JSON simulation files served as named
+	// presets and reloaded while the server runs
+	PresetDir string
+	// MetricsSink, when set, receives episode metrics and environment lifecycle events
+	MetricsSink core.MetricsSink
+	// Hooks, when set, are the lifecycle hooks fired for every environment the server creates
+	// (share one value between servers to observe all of them)
+	Hooks *core.Hooks
+	// Logger, when set, receives the server's log output instead of the default logger
+	Logger Logger
+	// RunStore, when set, records environment creations and episode results
+	RunStore *runstore.Store
+	// OutputDir, when set, is the server directory that output paths given by clients
+	// (record_path, video_dir and tensorboard_dir) are resolved under; absolute paths and
+	// paths containing .. are rejected. Without it the server writes no files requested by
+	// clients
+	OutputDir string
+}
+
+// DefaultShmServerConfig returns default shared-memory transport configuration
+func DefaultShmServerConfig() *ShmServerConfig {
+	return &ShmServerConfig{
+		SocketPath: filepath.Join(os.TempDir(), "rl_env_engine.sock"),
+	}
+}
+
+// StartShmServer starts the shared-memory transport for Python processes on the same machine.
+// Observations and actions are exchanged through memory-mapped files, so stepping avoids
+// network serialization without requiring cgo
+func StartShmServer(config *ShmServerConfig) error {
+	if config == nil {
+		config = DefaultShmServerConfig()
+	}
+
+	shmServer := server.NewShmServer()
+	InstallScenarios(shmServer.Engine())
+	if err := InstallPresets(shmServer.Engine()); err != nil {
+		return err
+	}
+	if config.Hooks != nil {
+		shmServer.Engine().SetHooks(config.Hooks)
+	}
+	if config.Dir != "" {
+		shmServer.SetDir(config.Dir)
+	}
+	shmServer.SetMetricsSink(config.MetricsSink)
+	shmServer.SetLogger(config.Logger)
+	logger := loggerOr(config.Logger)
+	if config.RunStore != nil {
+		shmServer.SetRunStore(config.RunStore)
+	}
+	shmServer.SetOutputDir(config.OutputDir)
+	if config.PresetDir != "" {
+		stop, err := watchPresetDir(shmServer.Engine(), config.PresetDir, DefaultPresetReloadInterval, logger)
+		if err != nil {
+			return err
+		}
+		defer stop()
+	}
+
+	logger.Info("Starting Simulation shared-memory server", "socket", config.SocketPath)
+
+	return shmServer.ListenAndServe(config.SocketPath)
+}
+
+// StartShmServerAsync starts the shared-memory server in a separate goroutine
+// Returns a channel that will receive any error from the server
+func StartShmServerAsync(config *ShmServerConfig) <-chan error {
+	errCh := make(chan error, 1)
+
+	go func() {
+		defer close(errCh)
+		if err := StartShmServer(config); err != nil {
+			errCh <- err
+		}
+	}()
+
+	return errCh
+}
+
+// NewShmServerConfig creates a new shared-memory transport configuration
+func NewShmServerConfig(socketPath string) *ShmServerConfig {
+	return &ShmServerConfig{SocketPath: socketPath}
+}
+
+// WithDir sets the directory of the memory-mapped files
+func (c *ShmServerConfig) WithDir(dir string) *ShmServerConfig {
+	c.Dir = dir
+	return c
+}
+
+// WithPresetDir sets the directory of hot-reloaded environment presets
+func (c *ShmServerConfig) WithPresetDir(dir string) *ShmServerConfig {
+	c.PresetDir = dir
+	return c
+}
+
+// WithMetricsSink sets where episode metrics and environment events are published
+func (c *ShmServerConfig) WithMetricsSink(sink core.MetricsSink) *ShmServerConfig {
+	c.MetricsSink = sink
+	return c
+}
+
+// WithHooks sets the lifecycle hooks fired for the server's environments
+func (c *ShmServerConfig) WithHooks(hooks *core.Hooks) *ShmServerConfig {
+	c.Hooks = hooks
+	return c
+}
+
+// WithLogger sets the logger receiving the server's log output
+func (c *ShmServerConfig) WithLogger(logger Logger) *ShmServerConfig {
+	c.Logger = logger
+	return c
+}
+
+// WithRunStore sets the store that records runs and episodes
+func (c *ShmServerConfig) WithRunStore(store *runstore.Store) *ShmServerConfig {
+	c.RunStore = store
+	return c
+}
+
+// WithOutputDir sets the server directory that client output paths are resolved under
+func (c *ShmServerConfig) WithOutputDir(dir string) *ShmServerConfig {
+	c.OutputDir = dir
+	return c
+}
+
+// Address returns the control socket path
+func (c *ShmServerConfig) Address() string {
+	return c.SocketPath
+}
@@ -5,10 +5,14 @@ package rl_env_engine
 import (
 	"context"
 	"fmt"
-	"log"
-	"sync"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
 
 	"github.com/jelech/rl_env_engine/core"
+	"github.com/jelech/rl_env_engine/scenarios"
 	"github.com/jelech/rl_env_engine/scenarios/simple"
 )
 
@@ -24,18 +28,44 @@ type Observation = core.Observation
 // Action represents agent action
 type Action = core.Action
 
-// NewSimulation creates a new simulation environment for the specified scenario
-func NewSimulation(scenario string, config map[string]interface{}) (Simulation, error) {
-	engine := core.NewSimulationEngine()
+// TimeStep represents a dm_env-style time step (step type, reward, discount, observation)
+type TimeStep = core.TimeStep
 
-	// Register built-in scenarios
-	registerBuiltinScenarios(engine)
+// TimeStepEnv adapts a simulation to the dm_env Reset/Step protocol
+type TimeStepEnv = core.TimeStepEnv
+
+// StepResult holds one step's observations, rewards and done flags keyed by agent name
+type StepResult = core.StepResult
+
+// MultiAgentEnv adapts a simulation to a Step that takes and returns per-agent maps
+type MultiAgentEnv = core.MultiAgentEnv
+
+// NewSimulation creates a new simulation environment for the specified scenario or preset name
+func NewSimulation(scenario string, config map[string]interface{}) (Simulation, error) {
+	engine, err := newBuiltinEngine()
+	if err != nil {
+		return nil, err
+	}
 
 	// Convert config map to Config interface
 	cfg := core.NewBaseConfig(config)
 	return engine.CreateEnvironment(scenario, cfg)
 }
 
+// newBuiltinEngine creates an engine with the built-in scenarios, those added with
+// RegisterScenario and the default presets
+func newBuiltinEngine() (*core.SimulationEngine, error) {
+	engine := core.NewSimulationEngine()
+
+	// Register built-in scenarios
+	scenarios.RegisterAll(engine)
+	InstallScenarios(engine)
+	if err := InstallPresets(engine); err != nil {
+		return nil, err
+	}
+	return engine, nil
+}
+
 // NewSimpleSimulation creates a simple simulation with simplified configuration
 func NewSimpleSimulation(opts ...SimpleOption) (Simulation, error) {
 	config := &SimpleConfig{
@@ -84,6 +114,16 @@ func NewSimpleAction(value float64) Action {
 	return simple.NewSimpleAction(value)
 }
 
+// NewTimeStepEnv wraps a simulation so Reset and Step return dm_env-style TimeSteps
+func NewTimeStepEnv(sim Simulation) *TimeStepEnv {
+	return core.NewTimeStepEnv(sim)
+}
+
+// NewMultiAgentEnv wraps a simulation so Reset and Step work with StepResults keyed by agent name
+func NewMultiAgentEnv(sim Simulation) *MultiAgentEnv {
+	return core.NewMultiAgentEnv(sim)
+}
+
 // GetObservationData extracts float64 data from observation
 func GetObservationData(obs Observation) []float64 {
 	return obs.GetData()
@@ -94,62 +134,39 @@ func GetObservationMetadata(obs Observation) map[string]interface{} {
 	return obs.GetMetadata()
 }
 
-// RunSimulation is a convenience function to run a complete simulation
-func RunSimulation(scenario string, config map[string]interface{}, episodes int, actionFunc func([]Observation) []Action) error {
+// RunSimulation is a convenience function to run a complete simulation: it creates the
+// scenario, runs episodes with actionFunc (see RunEpisodes for when an episode ends),
+// closes the simulation and returns the statistics of every episode
+func RunSimulation(scenario string, config map[string]interface{}, episodes int, actionFunc func([]Observation) []Action) (*RunResult, error) {
 	sim, err := NewSimulation(scenario, config)
 	if err != nil {
-		return fmt.Errorf("failed to create simulation: %w", err)
+		return nil, fmt.Errorf("failed to create simulation: %w", err)
 	}
 	defer sim.Close()
 
-	ctx := context.Background()
-
-	for episode := 0; episode < episodes; episode++ {
-		// Reset environment
-		observations, err := sim.Reset(ctx)
-		if err != nil {
-			return fmt.Errorf("failed to reset simulation at episode %d: %w", episode, err)
-		}
-
-		for step := 0; step < 100; step++ { // Max 100 steps per episode
-			// Get actions from user function
-			actions := actionFunc(observations)
-
-			// Execute step
-			obs, rewards, done, err := sim.Step(ctx, actions)
-			if err != nil {
-				return fmt.Errorf("failed to step simulation at episode %d, step %d: %w", episode, step, err)
-			}
-
-			observations = obs
-
-			// Check if done
-			if len(done) > 0 && done[0] {
-				break
-			}
-
-			// Optional: use rewards for logging or early termination
-			_ = rewards
-		}
-	}
-
-	return nil
-}
-
-// registerBuiltinScenarios registers all built-in scenarios
-func registerBuiltinScenarios(engine *core.SimulationEngine) {
-
-	// Register simple test scenario
-	simpleScenario := simple.NewSimpleScenario()
-	engine.RegisterScenario(simpleScenario)
+	return RunEpisodes(context.Background(), sim, actionFunc, RunOptions{Episodes: episodes})
 }
 
 // ServerConfig represents configuration for both HTTP and gRPC servers
 type ServerConfig struct {
 	HTTPConfig *HTTPServerConfig
 	GrpcConfig *GrpcServerConfig
+	// LogLevel and LogFormat, when set, configure the process-wide logger (see ConfigureLogging)
+	LogLevel  string
+	LogFormat string
+	// Logger, when set, receives the log output of both servers unless their own configs set one
+	Logger Logger
+	// Hooks, when set, are fired for the environments of both servers unless their own configs set some
+	Hooks *core.Hooks
+	// HandleSignals makes StartServersAndWait shut both servers down on SIGINT or SIGTERM,
+	// like canceling its context
+	HandleSignals bool
 }
 
+// DefaultShutdownTimeout is how long a server whose context is canceled waits for pending
+// requests, RPCs and streams before cutting them off
+const DefaultShutdownTimeout = 10 * time.Second
+
 // DefaultServerConfig returns default configuration for both servers
 func DefaultServerConfig() *ServerConfig {
 	return &ServerConfig{
@@ -158,35 +175,74 @@ func DefaultServerConfig() *ServerConfig {
 	}
 }
 
+// WithSnapshotDir saves the environments of each server to its own file in dir
+// (http.snapshot.json and grpc.snapshot.json) every interval and restores them on startup
+func (c *ServerConfig) WithSnapshotDir(dir string, interval time.Duration) *ServerConfig {
+	if c.HTTPConfig != nil {
+		c.HTTPConfig.WithSnapshot(filepath.Join(dir, "http.snapshot.json"), interval)
+	}
+	if c.GrpcConfig != nil {
+		c.GrpcConfig.WithSnapshot(filepath.Join(dir, "grpc.snapshot.json"), interval)
+	}
+	return c
+}
+
 // StartServers starts both HTTP and gRPC servers concurrently
 // Returns error channels for each server
 func StartServers(config *ServerConfig) (<-chan error, <-chan error) {
+	return startServers(context.Background(), config)
+}
+
+// startServers starts both servers until ctx is canceled; each channel receives the error
+// of its server, if any, and is closed once the server stopped
+func startServers(ctx context.Context, config *ServerConfig) (<-chan error, <-chan error) {
 	if config == nil {
 		config = DefaultServerConfig()
 	}
 
-	var wg sync.WaitGroup
 	httpErrCh := make(chan error, 1)
 	grpcErrCh := make(chan error, 1)
+	if config.LogLevel != "" || config.LogFormat != "" {
+		if err := ConfigureLogging(config.LogLevel, config.LogFormat); err != nil {
+			httpErrCh <- err
+			close(httpErrCh)
+			close(grpcErrCh)
+			return httpErrCh, grpcErrCh
+		}
+	}
+
+	if config.Logger != nil {
+		if config.HTTPConfig != nil && config.HTTPConfig.Logger == nil {
+			config.HTTPConfig.Logger = config.Logger
+		}
+		if config.GrpcConfig != nil && config.GrpcConfig.Logger == nil {
+			config.GrpcConfig.Logger = config.Logger
+		}
+	}
+	if config.Hooks != nil {
+		if config.HTTPConfig != nil && config.HTTPConfig.Hooks == nil {
+			config.HTTPConfig.Hooks = config.Hooks
+		}
+		if config.GrpcConfig != nil && config.GrpcConfig.Hooks == nil {
+			config.GrpcConfig.Hooks = config.Hooks
+		}
+	}
+	logger := loggerOr(config.Logger)
 
 	// Start HTTP server
-	wg.Add(1)
 	go func() {
-		defer wg.Done()
 		defer close(httpErrCh)
-		log.Printf("Starting HTTP server on %s", config.HTTPConfig.Address())
-		if err := StartHTTPServer(config.HTTPConfig); err != nil {
+		logger.Info("Starting HTTP server", "addr", config.HTTPConfig.Address())
+		if err := StartHTTPServerContext(ctx, config.HTTPConfig); err != nil {
 			httpErrCh <- err
 		}
 	}()
 
 	// Start gRPC server
-	wg.Add(1)
 	go func() {
-		defer wg.Done()
 		defer close(grpcErrCh)
-		log.Printf("Starting gRPC server on %s", config.GrpcConfig.Address())
-		if err := StartGrpcServer(config.GrpcConfig); err != nil {
+		logger.Info("Starting gRPC server", "addr", config.GrpcConfig.Address())
+		if err := StartGrpcServerContext(ctx, config.GrpcConfig); err != nil {
 			grpcErrCh <- err
 		}
 	}()
@@ -194,22 +250,59 @@ func StartServers(config *ServerConfig) (<-chan error, <-chan error) {
 	return httpErrCh, grpcErrCh
 }
 
-// StartServersAndWait starts both servers and waits for them to finish
-// Returns the first error encountered from either server
-func StartServersAndWait(config *ServerConfig) error {
-	httpErrCh, grpcErrCh := StartServers(config)
+// StartServersAndWait starts both servers and serves until ctx is canceled, config.HandleSignals
+// catches SIGINT or SIGTERM, or either server fails. Both servers are then shut down
+// gracefully and their environments closed before it returns the first server error, or
+// nil after a clean shutdown
+func StartServersAndWait(ctx context.Context, config *ServerConfig) error {
+	if config == nil {
+		config = DefaultServerConfig()
+	}
+	if config.HandleSignals {
+		var stop context.CancelFunc
+		ctx, stop = signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+		defer stop()
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	httpErrCh, grpcErrCh := startServers(ctx, config)
+
+	// The first server to stop stops the other one; wait for both so nothing leaks
+	var first error
+	for httpErrCh != nil || grpcErrCh != nil {
+		select {
+		case err := <-httpErrCh:
+			httpErrCh = nil
+			if err != nil && first == nil {
+				first = fmt.Errorf("HTTP server error: %w", err)
+			}
+		case err := <-grpcErrCh:
+			grpcErrCh = nil
+			if err != nil && first == nil {
+				first = fmt.Errorf("gRPC server error: %w", err)
+			}
+		}
+		cancel()
+	}
+	return first
+}
 
-	// Wait for the first error or completion
+// serveContext runs serve until it returns or ctx is canceled; shutdown then stops it,
+// waiting up to DefaultShutdownTimeout for pending requests
+func serveContext(ctx context.Context, serve func() error, shutdown func(ctx context.Context) error) error {
+	errCh := make(chan error, 1)
+	go func() { errCh <- serve() }()
 	select {
-	case err := <-httpErrCh:
-		if err != nil {
-			return fmt.Errorf("HTTP server error: %w", err)
-		}
-	case err := <-grpcErrCh:
-		if err != nil {
-			return fmt.Errorf("gRPC server error: %w", err)
-		}
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
 	}
 
-	return nil
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), DefaultShutdownTimeout)
+	defer cancel()
+	shutdownErr := shutdown(shutdownCtx)
+	if err := <-errCh; err != nil {
+		return err
+	}
+	return shutdownErr
 }
@@ -0,0 +1,18 @@
+package rl_env_engine
+
+import (
+	"context"
+
+	"github.com/jelech/rl_env_engine/core/tracing"
+)
+
+// TracingOptions configures the export of OpenTelemetry spans to an OTLP/gRPC endpoint
+type TracingOptions = tracing.Options
+
+// SetupTracing exports spans for HTTP requests, gRPC calls, environment creation and every
+// Reset and Step (with env_id, scenario, episode and step attributes) to the OTLP endpoint in
+// opts. Call the returned function before exiting to flush pending spans and stop tracing.
+// Applications with their own OpenTelemetry SDK can use tracing.SetTracerProvider instead
+func SetupTracing(opts TracingOptions) (shutdown func(context.Context) error, err error) {
+	return tracing.Setup(context.Background(), opts)
+}
@@ -0,0 +1,161 @@
+package rl_env_engine
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jelech/rl_env_engine/core"
+)
+
+// VectorSimulation runs n copies of a scenario with the same config side by side.
+// Reset and Step operate on all copies at once, in parallel through a core.StepPool,
+// and return one StepResult per copy in copy order.
+//
+// Like EnvPool and Gymnasium's vector environments, a copy whose episode ended
+// (every agent terminated or truncated) is reset by the next Step instead of stepped:
+// its actions are ignored and its result is the initial observation with zero rewards.
+type VectorSimulation struct {
+	sims []Simulation
+	pool *core.StepPool
+	done []bool
+}
+
+// NewVectorSimulation creates n copies of the scenario or preset with the same config.
+// When the config sets a non-zero seed, copy i uses seed+i so the copies differ but the
+// whole vector stays reproducible; otherwise each copy seeds itself as usual
+func NewVectorSimulation(scenario string, n int, config map[string]interface{}) (*VectorSimulation, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("vector simulation needs at least one copy, got %d", n)
+	}
+	engine, err := newBuiltinEngine()
+	if err != nil {
+		return nil, err
+	}
+
+	v := &VectorSimulation{pool: core.NewStepPool(0), done: make([]bool, n)}
+	for i := 0; i < n; i++ {
+		sim, err := engine.CreateEnvironment(scenario, core.NewBaseConfig(instanceConfig(config, i)))
+		if err != nil {
+			v.Close()
+			return nil, fmt.Errorf("failed to create simulation %d: %w", i, err)
+		}
+		v.sims = append(v.sims, sim)
+	}
+	return v, nil
+}
+
+// instanceConfig copies config for copy i, offsetting a non-zero seed by i
+func instanceConfig(config map[string]interface{}, i int) map[string]interface{} {
+	copied := make(map[string]interface{}, len(config))
+	for k, v := range config {
+		copied[k] = v
+	}
+	switch seed := copied["seed"].(type) {
+	case int:
+		if seed != 0 {
+			copied["seed"] = seed + i
+		}
+	case int64:
+		if seed != 0 {
+			copied["seed"] = seed + int64(i)
+		}
+	case float64:
+		if seed != 0 {
+			copied["seed"] = seed + float64(i)
+		}
+	}
+	return copied
+}
+
+// Len returns the number of copies
+func (v *VectorSimulation) Len() int {
+	return len(v.sims)
+}
+
+// Simulation returns copy i, e.g. to render it or read its info
+func (v *VectorSimulation) Simulation(i int) Simulation {
+	return v.sims[i]
+}
+
+// GetSpaces returns the spaces of the copies, which share a config
+func (v *VectorSimulation) GetSpaces() core.SpaceDefinition {
+	return v.sims[0].GetSpaces()
+}
+
+// Reset resets every copy. The first failure is returned with the index of its copy
+func (v *VectorSimulation) Reset(ctx context.Context) ([]*StepResult, error) {
+	results := make([]*StepResult, len(v.sims))
+	for i, r := range v.pool.Reset(ctx, v.sims) {
+		if r.Err != nil {
+			return nil, fmt.Errorf("failed to reset simulation %d: %w", i, r.Err)
+		}
+		results[i] = r.Result
+		v.done[i] = false
+	}
+	return results, nil
+}
+
+// Step applies actions[i] to copy i and resets the copies whose episode ended on the
+// previous step. The first failure is returned with the index of its copy
+func (v *VectorSimulation) Step(ctx context.Context, actions [][]Action) ([]*StepResult, error) {
+	if len(actions) != len(v.sims) {
+		return nil, fmt.Errorf("got actions for %d simulations, expected %d", len(actions), len(v.sims))
+	}
+
+	var stepping, resetting []int
+	for i, done := range v.done {
+		if done {
+			resetting = append(resetting, i)
+		} else {
+			stepping = append(stepping, i)
+		}
+	}
+
+	results := make([]*StepResult, len(v.sims))
+	if len(resetting) > 0 {
+		envs := make([]core.Environment, len(resetting))
+		for j, i := range resetting {
+			envs[j] = v.sims[i]
+		}
+		for j, r := range v.pool.Reset(ctx, envs) {
+			i := resetting[j]
+			if r.Err != nil {
+				return nil, fmt.Errorf("failed to reset simulation %d: %w", i, r.Err)
+			}
+			results[i] = r.Result
+			v.done[i] = false
+		}
+	}
+	if len(stepping) > 0 {
+		envs := make([]core.Environment, len(stepping))
+		stepActions := make([][]Action, len(stepping))
+		for j, i := range stepping {
+			envs[j] = v.sims[i]
+			stepActions[j] = actions[i]
+		}
+		poolResults, err := v.pool.Step(ctx, envs, stepActions)
+		if err != nil {
+			return nil, err
+		}
+		for j, r := range poolResults {
+			i := stepping[j]
+			if r.Err != nil {
+				return nil, fmt.Errorf("failed to step simulation %d: %w", i, r.Err)
+			}
+			results[i] = r.Result
+			v.done[i] = r.Result.Done()
+		}
+	}
+	return results, nil
+}
+
+// Close closes every copy and returns the first error
+func (v *VectorSimulation) Close() error {
+	var first error
+	for i, sim := range v.sims {
+		if err := sim.Close(); err != nil && first == nil {
+			first = fmt.Errorf("failed to close simulation %d: %w", i, err)
+		}
+	}
+	return first
+}